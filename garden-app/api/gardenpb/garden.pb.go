@@ -0,0 +1,2129 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.24.4
+// source: garden.proto
+
+package gardenpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LightState int32
+
+const (
+	// LIGHT_STATE_TOGGLE switches the light to the opposite of its current state
+	LightState_LIGHT_STATE_TOGGLE LightState = 0
+	LightState_LIGHT_STATE_ON     LightState = 1
+	LightState_LIGHT_STATE_OFF    LightState = 2
+)
+
+// Enum value maps for LightState.
+var (
+	LightState_name = map[int32]string{
+		0: "LIGHT_STATE_TOGGLE",
+		1: "LIGHT_STATE_ON",
+		2: "LIGHT_STATE_OFF",
+	}
+	LightState_value = map[string]int32{
+		"LIGHT_STATE_TOGGLE": 0,
+		"LIGHT_STATE_ON":     1,
+		"LIGHT_STATE_OFF":    2,
+	}
+)
+
+func (x LightState) Enum() *LightState {
+	p := new(LightState)
+	*p = x
+	return p
+}
+
+func (x LightState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LightState) Descriptor() protoreflect.EnumDescriptor {
+	return file_garden_proto_enumTypes[0].Descriptor()
+}
+
+func (LightState) Type() protoreflect.EnumType {
+	return &file_garden_proto_enumTypes[0]
+}
+
+func (x LightState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LightState.Descriptor instead.
+func (LightState) EnumDescriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{0}
+}
+
+type Garden struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	TopicPrefix   string                 `protobuf:"bytes,3,opt,name=topic_prefix,json=topicPrefix,proto3" json:"topic_prefix,omitempty"`
+	MaxZones      uint32                 `protobuf:"varint,4,opt,name=max_zones,json=maxZones,proto3" json:"max_zones,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	LightSchedule *LightSchedule         `protobuf:"bytes,7,opt,name=light_schedule,json=lightSchedule,proto3" json:"light_schedule,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,8,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+}
+
+func (x *Garden) Reset() {
+	*x = Garden{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Garden) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Garden) ProtoMessage() {}
+
+func (x *Garden) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Garden.ProtoReflect.Descriptor instead.
+func (*Garden) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Garden) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Garden) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Garden) GetTopicPrefix() string {
+	if x != nil {
+		return x.TopicPrefix
+	}
+	return ""
+}
+
+func (x *Garden) GetMaxZones() uint32 {
+	if x != nil {
+		return x.MaxZones
+	}
+	return 0
+}
+
+func (x *Garden) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Garden) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Garden) GetLightSchedule() *LightSchedule {
+	if x != nil {
+		return x.LightSchedule
+	}
+	return nil
+}
+
+func (x *Garden) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type LightSchedule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Duration  string `protobuf:"bytes,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	StartTime string `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+}
+
+func (x *LightSchedule) Reset() {
+	*x = LightSchedule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightSchedule) ProtoMessage() {}
+
+func (x *LightSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightSchedule.ProtoReflect.Descriptor instead.
+func (*LightSchedule) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LightSchedule) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *LightSchedule) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+type Zone struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	GardenId         string                 `protobuf:"bytes,2,opt,name=garden_id,json=gardenId,proto3" json:"garden_id,omitempty"`
+	Name             string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Position         uint32                 `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	EndDate          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	WaterScheduleIds []string               `protobuf:"bytes,7,rep,name=water_schedule_ids,json=waterScheduleIds,proto3" json:"water_schedule_ids,omitempty"`
+	SkipCount        uint32                 `protobuf:"varint,8,opt,name=skip_count,json=skipCount,proto3" json:"skip_count,omitempty"`
+	Details          *ZoneDetails           `protobuf:"bytes,9,opt,name=details,proto3" json:"details,omitempty"`
+}
+
+func (x *Zone) Reset() {
+	*x = Zone{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Zone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Zone) ProtoMessage() {}
+
+func (x *Zone) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Zone.ProtoReflect.Descriptor instead.
+func (*Zone) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Zone) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Zone) GetGardenId() string {
+	if x != nil {
+		return x.GardenId
+	}
+	return ""
+}
+
+func (x *Zone) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Zone) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Zone) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Zone) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Zone) GetWaterScheduleIds() []string {
+	if x != nil {
+		return x.WaterScheduleIds
+	}
+	return nil
+}
+
+func (x *Zone) GetSkipCount() uint32 {
+	if x != nil {
+		return x.SkipCount
+	}
+	return 0
+}
+
+func (x *Zone) GetDetails() *ZoneDetails {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type ZoneDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Description string `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Notes       string `protobuf:"bytes,2,opt,name=notes,proto3" json:"notes,omitempty"`
+}
+
+func (x *ZoneDetails) Reset() {
+	*x = ZoneDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ZoneDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZoneDetails) ProtoMessage() {}
+
+func (x *ZoneDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZoneDetails.ProtoReflect.Descriptor instead.
+func (*ZoneDetails) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ZoneDetails) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ZoneDetails) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+type WaterSchedule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description  string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Duration     string                 `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Interval     string                 `protobuf:"bytes,5,opt,name=interval,proto3" json:"interval,omitempty"`
+	StartDate    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	StartTime    string                 `protobuf:"bytes,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndDate      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	ActivePeriod *ActivePeriod          `protobuf:"bytes,9,opt,name=active_period,json=activePeriod,proto3" json:"active_period,omitempty"`
+}
+
+func (x *WaterSchedule) Reset() {
+	*x = WaterSchedule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WaterSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaterSchedule) ProtoMessage() {}
+
+func (x *WaterSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaterSchedule.ProtoReflect.Descriptor instead.
+func (*WaterSchedule) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WaterSchedule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WaterSchedule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WaterSchedule) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *WaterSchedule) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *WaterSchedule) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *WaterSchedule) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *WaterSchedule) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *WaterSchedule) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *WaterSchedule) GetActivePeriod() *ActivePeriod {
+	if x != nil {
+		return x.ActivePeriod
+	}
+	return nil
+}
+
+type ActivePeriod struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartMonth string `protobuf:"bytes,1,opt,name=start_month,json=startMonth,proto3" json:"start_month,omitempty"`
+	EndMonth   string `protobuf:"bytes,2,opt,name=end_month,json=endMonth,proto3" json:"end_month,omitempty"`
+}
+
+func (x *ActivePeriod) Reset() {
+	*x = ActivePeriod{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActivePeriod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivePeriod) ProtoMessage() {}
+
+func (x *ActivePeriod) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivePeriod.ProtoReflect.Descriptor instead.
+func (*ActivePeriod) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ActivePeriod) GetStartMonth() string {
+	if x != nil {
+		return x.StartMonth
+	}
+	return ""
+}
+
+func (x *ActivePeriod) GetEndMonth() string {
+	if x != nil {
+		return x.EndMonth
+	}
+	return ""
+}
+
+type ListGardensRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IncludeEndDated bool `protobuf:"varint,1,opt,name=include_end_dated,json=includeEndDated,proto3" json:"include_end_dated,omitempty"`
+}
+
+func (x *ListGardensRequest) Reset() {
+	*x = ListGardensRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListGardensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGardensRequest) ProtoMessage() {}
+
+func (x *ListGardensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGardensRequest.ProtoReflect.Descriptor instead.
+func (*ListGardensRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListGardensRequest) GetIncludeEndDated() bool {
+	if x != nil {
+		return x.IncludeEndDated
+	}
+	return false
+}
+
+type ListGardensResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Gardens []*Garden `protobuf:"bytes,1,rep,name=gardens,proto3" json:"gardens,omitempty"`
+}
+
+func (x *ListGardensResponse) Reset() {
+	*x = ListGardensResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListGardensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGardensResponse) ProtoMessage() {}
+
+func (x *ListGardensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGardensResponse.ProtoReflect.Descriptor instead.
+func (*ListGardensResponse) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListGardensResponse) GetGardens() []*Garden {
+	if x != nil {
+		return x.Gardens
+	}
+	return nil
+}
+
+type GetGardenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetGardenRequest) Reset() {
+	*x = GetGardenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetGardenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGardenRequest) ProtoMessage() {}
+
+func (x *GetGardenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGardenRequest.ProtoReflect.Descriptor instead.
+func (*GetGardenRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetGardenRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListZonesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GardenId        string `protobuf:"bytes,1,opt,name=garden_id,json=gardenId,proto3" json:"garden_id,omitempty"`
+	IncludeEndDated bool   `protobuf:"varint,2,opt,name=include_end_dated,json=includeEndDated,proto3" json:"include_end_dated,omitempty"`
+}
+
+func (x *ListZonesRequest) Reset() {
+	*x = ListZonesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListZonesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListZonesRequest) ProtoMessage() {}
+
+func (x *ListZonesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListZonesRequest.ProtoReflect.Descriptor instead.
+func (*ListZonesRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListZonesRequest) GetGardenId() string {
+	if x != nil {
+		return x.GardenId
+	}
+	return ""
+}
+
+func (x *ListZonesRequest) GetIncludeEndDated() bool {
+	if x != nil {
+		return x.IncludeEndDated
+	}
+	return false
+}
+
+type ListZonesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Zones []*Zone `protobuf:"bytes,1,rep,name=zones,proto3" json:"zones,omitempty"`
+}
+
+func (x *ListZonesResponse) Reset() {
+	*x = ListZonesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListZonesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListZonesResponse) ProtoMessage() {}
+
+func (x *ListZonesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListZonesResponse.ProtoReflect.Descriptor instead.
+func (*ListZonesResponse) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListZonesResponse) GetZones() []*Zone {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+type GetZoneRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GardenId string `protobuf:"bytes,1,opt,name=garden_id,json=gardenId,proto3" json:"garden_id,omitempty"`
+	Id       string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetZoneRequest) Reset() {
+	*x = GetZoneRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetZoneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetZoneRequest) ProtoMessage() {}
+
+func (x *GetZoneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetZoneRequest.ProtoReflect.Descriptor instead.
+func (*GetZoneRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetZoneRequest) GetGardenId() string {
+	if x != nil {
+		return x.GardenId
+	}
+	return ""
+}
+
+func (x *GetZoneRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListWaterSchedulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IncludeEndDated bool `protobuf:"varint,1,opt,name=include_end_dated,json=includeEndDated,proto3" json:"include_end_dated,omitempty"`
+}
+
+func (x *ListWaterSchedulesRequest) Reset() {
+	*x = ListWaterSchedulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListWaterSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWaterSchedulesRequest) ProtoMessage() {}
+
+func (x *ListWaterSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWaterSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListWaterSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListWaterSchedulesRequest) GetIncludeEndDated() bool {
+	if x != nil {
+		return x.IncludeEndDated
+	}
+	return false
+}
+
+type ListWaterSchedulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WaterSchedules []*WaterSchedule `protobuf:"bytes,1,rep,name=water_schedules,json=waterSchedules,proto3" json:"water_schedules,omitempty"`
+}
+
+func (x *ListWaterSchedulesResponse) Reset() {
+	*x = ListWaterSchedulesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListWaterSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWaterSchedulesResponse) ProtoMessage() {}
+
+func (x *ListWaterSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWaterSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListWaterSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListWaterSchedulesResponse) GetWaterSchedules() []*WaterSchedule {
+	if x != nil {
+		return x.WaterSchedules
+	}
+	return nil
+}
+
+type GetWaterScheduleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetWaterScheduleRequest) Reset() {
+	*x = GetWaterScheduleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWaterScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWaterScheduleRequest) ProtoMessage() {}
+
+func (x *GetWaterScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWaterScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetWaterScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetWaterScheduleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type LightAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State       LightState `protobuf:"varint,1,opt,name=state,proto3,enum=garden.v1.LightState" json:"state,omitempty"`
+	ForDuration string     `protobuf:"bytes,2,opt,name=for_duration,json=forDuration,proto3" json:"for_duration,omitempty"`
+}
+
+func (x *LightAction) Reset() {
+	*x = LightAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightAction) ProtoMessage() {}
+
+func (x *LightAction) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightAction.ProtoReflect.Descriptor instead.
+func (*LightAction) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LightAction) GetState() LightState {
+	if x != nil {
+		return x.State
+	}
+	return LightState_LIGHT_STATE_TOGGLE
+}
+
+func (x *LightAction) GetForDuration() string {
+	if x != nil {
+		return x.ForDuration
+	}
+	return ""
+}
+
+type StopAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	All bool `protobuf:"varint,1,opt,name=all,proto3" json:"all,omitempty"`
+}
+
+func (x *StopAction) Reset() {
+	*x = StopAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopAction) ProtoMessage() {}
+
+func (x *StopAction) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopAction.ProtoReflect.Descriptor instead.
+func (*StopAction) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StopAction) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type ExecuteGardenActionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GardenId string       `protobuf:"bytes,1,opt,name=garden_id,json=gardenId,proto3" json:"garden_id,omitempty"`
+	Light    *LightAction `protobuf:"bytes,2,opt,name=light,proto3" json:"light,omitempty"`
+	Stop     *StopAction  `protobuf:"bytes,3,opt,name=stop,proto3" json:"stop,omitempty"`
+}
+
+func (x *ExecuteGardenActionRequest) Reset() {
+	*x = ExecuteGardenActionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteGardenActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteGardenActionRequest) ProtoMessage() {}
+
+func (x *ExecuteGardenActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteGardenActionRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteGardenActionRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExecuteGardenActionRequest) GetGardenId() string {
+	if x != nil {
+		return x.GardenId
+	}
+	return ""
+}
+
+func (x *ExecuteGardenActionRequest) GetLight() *LightAction {
+	if x != nil {
+		return x.Light
+	}
+	return nil
+}
+
+func (x *ExecuteGardenActionRequest) GetStop() *StopAction {
+	if x != nil {
+		return x.Stop
+	}
+	return nil
+}
+
+type ExecuteGardenActionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ExecuteGardenActionResponse) Reset() {
+	*x = ExecuteGardenActionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteGardenActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteGardenActionResponse) ProtoMessage() {}
+
+func (x *ExecuteGardenActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteGardenActionResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteGardenActionResponse) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{18}
+}
+
+type WaterAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// duration is optional. The Zone's next WaterSchedule duration is used if it is not set
+	Duration       string `protobuf:"bytes,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	IgnoreMoisture bool   `protobuf:"varint,2,opt,name=ignore_moisture,json=ignoreMoisture,proto3" json:"ignore_moisture,omitempty"`
+	IgnoreWeather  bool   `protobuf:"varint,3,opt,name=ignore_weather,json=ignoreWeather,proto3" json:"ignore_weather,omitempty"`
+}
+
+func (x *WaterAction) Reset() {
+	*x = WaterAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WaterAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaterAction) ProtoMessage() {}
+
+func (x *WaterAction) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaterAction.ProtoReflect.Descriptor instead.
+func (*WaterAction) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WaterAction) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *WaterAction) GetIgnoreMoisture() bool {
+	if x != nil {
+		return x.IgnoreMoisture
+	}
+	return false
+}
+
+func (x *WaterAction) GetIgnoreWeather() bool {
+	if x != nil {
+		return x.IgnoreWeather
+	}
+	return false
+}
+
+type ExecuteZoneActionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GardenId string       `protobuf:"bytes,1,opt,name=garden_id,json=gardenId,proto3" json:"garden_id,omitempty"`
+	ZoneId   string       `protobuf:"bytes,2,opt,name=zone_id,json=zoneId,proto3" json:"zone_id,omitempty"`
+	Water    *WaterAction `protobuf:"bytes,3,opt,name=water,proto3" json:"water,omitempty"`
+}
+
+func (x *ExecuteZoneActionRequest) Reset() {
+	*x = ExecuteZoneActionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteZoneActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteZoneActionRequest) ProtoMessage() {}
+
+func (x *ExecuteZoneActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteZoneActionRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteZoneActionRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ExecuteZoneActionRequest) GetGardenId() string {
+	if x != nil {
+		return x.GardenId
+	}
+	return ""
+}
+
+func (x *ExecuteZoneActionRequest) GetZoneId() string {
+	if x != nil {
+		return x.ZoneId
+	}
+	return ""
+}
+
+func (x *ExecuteZoneActionRequest) GetWater() *WaterAction {
+	if x != nil {
+		return x.Water
+	}
+	return nil
+}
+
+type ExecuteZoneActionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ExecuteZoneActionResponse) Reset() {
+	*x = ExecuteZoneActionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteZoneActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteZoneActionResponse) ProtoMessage() {}
+
+func (x *ExecuteZoneActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteZoneActionResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteZoneActionResponse) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{21}
+}
+
+// WatchWaterEventsRequest can optionally filter events to a single Garden or Zone
+type WatchWaterEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GardenId string `protobuf:"bytes,1,opt,name=garden_id,json=gardenId,proto3" json:"garden_id,omitempty"`
+	ZoneId   string `protobuf:"bytes,2,opt,name=zone_id,json=zoneId,proto3" json:"zone_id,omitempty"`
+}
+
+func (x *WatchWaterEventsRequest) Reset() {
+	*x = WatchWaterEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchWaterEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchWaterEventsRequest) ProtoMessage() {}
+
+func (x *WatchWaterEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchWaterEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchWaterEventsRequest) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WatchWaterEventsRequest) GetGardenId() string {
+	if x != nil {
+		return x.GardenId
+	}
+	return ""
+}
+
+func (x *WatchWaterEventsRequest) GetZoneId() string {
+	if x != nil {
+		return x.ZoneId
+	}
+	return ""
+}
+
+type WaterEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GardenId  string                 `protobuf:"bytes,1,opt,name=garden_id,json=gardenId,proto3" json:"garden_id,omitempty"`
+	ZoneId    string                 `protobuf:"bytes,2,opt,name=zone_id,json=zoneId,proto3" json:"zone_id,omitempty"`
+	Duration  string                 `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *WaterEvent) Reset() {
+	*x = WaterEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_garden_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WaterEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaterEvent) ProtoMessage() {}
+
+func (x *WaterEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_garden_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaterEvent.ProtoReflect.Descriptor instead.
+func (*WaterEvent) Descriptor() ([]byte, []int) {
+	return file_garden_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WaterEvent) GetGardenId() string {
+	if x != nil {
+		return x.GardenId
+	}
+	return ""
+}
+
+func (x *WaterEvent) GetZoneId() string {
+	if x != nil {
+		return x.ZoneId
+	}
+	return ""
+}
+
+func (x *WaterEvent) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *WaterEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_garden_proto protoreflect.FileDescriptor
+
+var file_garden_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
+	0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbc, 0x02, 0x0a, 0x06, 0x47,
+	0x61, 0x72, 0x64, 0x65, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x70,
+	0x69, 0x63, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1b, 0x0a, 0x09,
+	0x6d, 0x61, 0x78, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x6d, 0x61, 0x78, 0x5a, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x3f, 0x0a, 0x0e, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x67, 0x68, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x0d, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x74, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x22, 0x4a, 0x0a, 0x0d, 0x4c, 0x69, 0x67,
+	0x68, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xd4, 0x02, 0x0a, 0x04, 0x5a, 0x6f, 0x6e, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b,
+	0x0a, 0x09, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x2c, 0x0a,
+	0x12, 0x77, 0x61, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x5f,
+	0x69, 0x64, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x77, 0x61, 0x74, 0x65, 0x72,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x49, 0x64, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x6b, 0x69, 0x70, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x09, 0x73, 0x6b, 0x69, 0x70, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x64, 0x65,
+	0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x61,
+	0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x6f, 0x6e, 0x65, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x73, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x22, 0x45, 0x0a, 0x0b,
+	0x5a, 0x6f, 0x6e, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a,
+	0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f,
+	0x74, 0x65, 0x73, 0x22, 0xdc, 0x02, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a,
+	0x08, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64,
+	0x44, 0x61, 0x74, 0x65, 0x12, 0x3c, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x70,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x61,
+	0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x52, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x22, 0x4c, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x6d, 0x6f, 0x6e, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x4d, 0x6f,
+	0x6e, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x6e, 0x64, 0x5f, 0x6d, 0x6f, 0x6e, 0x74, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x4d, 0x6f, 0x6e, 0x74, 0x68,
+	0x22, 0x40, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x45, 0x6e, 0x64, 0x44, 0x61, 0x74,
+	0x65, 0x64, 0x22, 0x42, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x67, 0x61, 0x72,
+	0x64, 0x65, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x67, 0x61, 0x72,
+	0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x52, 0x07, 0x67,
+	0x61, 0x72, 0x64, 0x65, 0x6e, 0x73, 0x22, 0x22, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x47, 0x61, 0x72,
+	0x64, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x5b, 0x0a, 0x10, 0x4c, 0x69,
+	0x73, 0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x45,
+	0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x64, 0x22, 0x3a, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x5a,
+	0x6f, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x05,
+	0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x67, 0x61,
+	0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x6f, 0x6e, 0x65, 0x52, 0x05, 0x7a, 0x6f,
+	0x6e, 0x65, 0x73, 0x22, 0x3d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e,
+	0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x47, 0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2a, 0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x45, 0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x64, 0x22, 0x5f, 0x0a, 0x1a, 0x4c,
+	0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0f, 0x77, 0x61, 0x74,
+	0x65, 0x72, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57,
+	0x61, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x0e, 0x77, 0x61,
+	0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x29, 0x0a, 0x17,
+	0x47, 0x65, 0x74, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x5d, 0x0a, 0x0b, 0x4c, 0x69, 0x67, 0x68, 0x74,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x6f, 0x72, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x66, 0x6f, 0x72, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x1e, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x70, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x03, 0x61, 0x6c, 0x6c, 0x22, 0x92, 0x01, 0x0a, 0x1a, 0x45, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x65, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e,
+	0x49, 0x64, 0x12, 0x2c, 0x0a, 0x05, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x67, 0x68, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x6c, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x29, 0x0a, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x22, 0x1d, 0x0a, 0x1b, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x79, 0x0a, 0x0b, 0x57, 0x61,
+	0x74, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x5f,
+	0x6d, 0x6f, 0x69, 0x73, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e,
+	0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x4d, 0x6f, 0x69, 0x73, 0x74, 0x75, 0x72, 0x65, 0x12, 0x25,
+	0x0a, 0x0e, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x5f, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x57, 0x65,
+	0x61, 0x74, 0x68, 0x65, 0x72, 0x22, 0x7e, 0x0a, 0x18, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
+	0x5a, 0x6f, 0x6e, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x49, 0x64, 0x12, 0x17,
+	0x0a, 0x07, 0x7a, 0x6f, 0x6e, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x7a, 0x6f, 0x6e, 0x65, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x05, 0x77, 0x61, 0x74, 0x65, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05,
+	0x77, 0x61, 0x74, 0x65, 0x72, 0x22, 0x1b, 0x0a, 0x19, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
+	0x5a, 0x6f, 0x6e, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x4f, 0x0a, 0x17, 0x57, 0x61, 0x74, 0x63, 0x68, 0x57, 0x61, 0x74, 0x65, 0x72,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x7a, 0x6f,
+	0x6e, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x7a, 0x6f, 0x6e,
+	0x65, 0x49, 0x64, 0x22, 0x98, 0x01, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x65, 0x72, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x49, 0x64, 0x12,
+	0x17, 0x0a, 0x07, 0x7a, 0x6f, 0x6e, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x7a, 0x6f, 0x6e, 0x65, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2a, 0x4d,
+	0x0a, 0x0a, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x12,
+	0x4c, 0x49, 0x47, 0x48, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x54, 0x4f, 0x47, 0x47,
+	0x4c, 0x45, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x49, 0x47, 0x48, 0x54, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x5f, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x4c, 0x49, 0x47, 0x48,
+	0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x4f, 0x46, 0x46, 0x10, 0x02, 0x32, 0xe5, 0x05,
+	0x0a, 0x0d, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x4c, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x73, 0x12, 0x1d,
+	0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47,
+	0x61, 0x72, 0x64, 0x65, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61,
+	0x72, 0x64, 0x65, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a,
+	0x09, 0x47, 0x65, 0x74, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x12, 0x1b, 0x2e, 0x67, 0x61, 0x72,
+	0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x12, 0x64, 0x0a, 0x13, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x25, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x47, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x47, 0x61, 0x72, 0x64,
+	0x65, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x46, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x1b, 0x2e,
+	0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x5a, 0x6f,
+	0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x67, 0x61, 0x72,
+	0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x5a,
+	0x6f, 0x6e, 0x65, 0x12, 0x19, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f,
+	0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x6f, 0x6e, 0x65, 0x12,
+	0x5e, 0x0a, 0x11, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x67, 0x61, 0x72, 0x64,
+	0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5a, 0x6f, 0x6e,
+	0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x61, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x24, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x67, 0x61,
+	0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x65,
+	0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x50, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x22, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x67, 0x61, 0x72,
+	0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x12, 0x4f, 0x0a, 0x10, 0x57, 0x61, 0x74, 0x63, 0x68, 0x57, 0x61, 0x74,
+	0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x22, 0x2e, 0x67, 0x61, 0x72, 0x64, 0x65,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x57, 0x61, 0x74, 0x65, 0x72, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x67,
+	0x61, 0x72, 0x64, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x65, 0x72, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x42, 0x5a, 0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x61, 0x6c, 0x76, 0x69, 0x6e, 0x6d, 0x63, 0x6c, 0x65, 0x61, 0x6e,
+	0x2f, 0x61, 0x75, 0x74, 0x6f, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x2d, 0x67, 0x61, 0x72, 0x64, 0x65,
+	0x6e, 0x2f, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x2d, 0x61, 0x70, 0x70, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x67, 0x61, 0x72, 0x64, 0x65, 0x6e, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_garden_proto_rawDescOnce sync.Once
+	file_garden_proto_rawDescData = file_garden_proto_rawDesc
+)
+
+func file_garden_proto_rawDescGZIP() []byte {
+	file_garden_proto_rawDescOnce.Do(func() {
+		file_garden_proto_rawDescData = protoimpl.X.CompressGZIP(file_garden_proto_rawDescData)
+	})
+	return file_garden_proto_rawDescData
+}
+
+var file_garden_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_garden_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_garden_proto_goTypes = []interface{}{
+	(LightState)(0),                     // 0: garden.v1.LightState
+	(*Garden)(nil),                      // 1: garden.v1.Garden
+	(*LightSchedule)(nil),               // 2: garden.v1.LightSchedule
+	(*Zone)(nil),                        // 3: garden.v1.Zone
+	(*ZoneDetails)(nil),                 // 4: garden.v1.ZoneDetails
+	(*WaterSchedule)(nil),               // 5: garden.v1.WaterSchedule
+	(*ActivePeriod)(nil),                // 6: garden.v1.ActivePeriod
+	(*ListGardensRequest)(nil),          // 7: garden.v1.ListGardensRequest
+	(*ListGardensResponse)(nil),         // 8: garden.v1.ListGardensResponse
+	(*GetGardenRequest)(nil),            // 9: garden.v1.GetGardenRequest
+	(*ListZonesRequest)(nil),            // 10: garden.v1.ListZonesRequest
+	(*ListZonesResponse)(nil),           // 11: garden.v1.ListZonesResponse
+	(*GetZoneRequest)(nil),              // 12: garden.v1.GetZoneRequest
+	(*ListWaterSchedulesRequest)(nil),   // 13: garden.v1.ListWaterSchedulesRequest
+	(*ListWaterSchedulesResponse)(nil),  // 14: garden.v1.ListWaterSchedulesResponse
+	(*GetWaterScheduleRequest)(nil),     // 15: garden.v1.GetWaterScheduleRequest
+	(*LightAction)(nil),                 // 16: garden.v1.LightAction
+	(*StopAction)(nil),                  // 17: garden.v1.StopAction
+	(*ExecuteGardenActionRequest)(nil),  // 18: garden.v1.ExecuteGardenActionRequest
+	(*ExecuteGardenActionResponse)(nil), // 19: garden.v1.ExecuteGardenActionResponse
+	(*WaterAction)(nil),                 // 20: garden.v1.WaterAction
+	(*ExecuteZoneActionRequest)(nil),    // 21: garden.v1.ExecuteZoneActionRequest
+	(*ExecuteZoneActionResponse)(nil),   // 22: garden.v1.ExecuteZoneActionResponse
+	(*WatchWaterEventsRequest)(nil),     // 23: garden.v1.WatchWaterEventsRequest
+	(*WaterEvent)(nil),                  // 24: garden.v1.WaterEvent
+	(*timestamppb.Timestamp)(nil),       // 25: google.protobuf.Timestamp
+}
+var file_garden_proto_depIdxs = []int32{
+	25, // 0: garden.v1.Garden.created_at:type_name -> google.protobuf.Timestamp
+	25, // 1: garden.v1.Garden.end_date:type_name -> google.protobuf.Timestamp
+	2,  // 2: garden.v1.Garden.light_schedule:type_name -> garden.v1.LightSchedule
+	25, // 3: garden.v1.Zone.created_at:type_name -> google.protobuf.Timestamp
+	25, // 4: garden.v1.Zone.end_date:type_name -> google.protobuf.Timestamp
+	4,  // 5: garden.v1.Zone.details:type_name -> garden.v1.ZoneDetails
+	25, // 6: garden.v1.WaterSchedule.start_date:type_name -> google.protobuf.Timestamp
+	25, // 7: garden.v1.WaterSchedule.end_date:type_name -> google.protobuf.Timestamp
+	6,  // 8: garden.v1.WaterSchedule.active_period:type_name -> garden.v1.ActivePeriod
+	1,  // 9: garden.v1.ListGardensResponse.gardens:type_name -> garden.v1.Garden
+	3,  // 10: garden.v1.ListZonesResponse.zones:type_name -> garden.v1.Zone
+	5,  // 11: garden.v1.ListWaterSchedulesResponse.water_schedules:type_name -> garden.v1.WaterSchedule
+	0,  // 12: garden.v1.LightAction.state:type_name -> garden.v1.LightState
+	16, // 13: garden.v1.ExecuteGardenActionRequest.light:type_name -> garden.v1.LightAction
+	17, // 14: garden.v1.ExecuteGardenActionRequest.stop:type_name -> garden.v1.StopAction
+	20, // 15: garden.v1.ExecuteZoneActionRequest.water:type_name -> garden.v1.WaterAction
+	25, // 16: garden.v1.WaterEvent.timestamp:type_name -> google.protobuf.Timestamp
+	7,  // 17: garden.v1.GardenService.ListGardens:input_type -> garden.v1.ListGardensRequest
+	9,  // 18: garden.v1.GardenService.GetGarden:input_type -> garden.v1.GetGardenRequest
+	18, // 19: garden.v1.GardenService.ExecuteGardenAction:input_type -> garden.v1.ExecuteGardenActionRequest
+	10, // 20: garden.v1.GardenService.ListZones:input_type -> garden.v1.ListZonesRequest
+	12, // 21: garden.v1.GardenService.GetZone:input_type -> garden.v1.GetZoneRequest
+	21, // 22: garden.v1.GardenService.ExecuteZoneAction:input_type -> garden.v1.ExecuteZoneActionRequest
+	13, // 23: garden.v1.GardenService.ListWaterSchedules:input_type -> garden.v1.ListWaterSchedulesRequest
+	15, // 24: garden.v1.GardenService.GetWaterSchedule:input_type -> garden.v1.GetWaterScheduleRequest
+	23, // 25: garden.v1.GardenService.WatchWaterEvents:input_type -> garden.v1.WatchWaterEventsRequest
+	8,  // 26: garden.v1.GardenService.ListGardens:output_type -> garden.v1.ListGardensResponse
+	1,  // 27: garden.v1.GardenService.GetGarden:output_type -> garden.v1.Garden
+	19, // 28: garden.v1.GardenService.ExecuteGardenAction:output_type -> garden.v1.ExecuteGardenActionResponse
+	11, // 29: garden.v1.GardenService.ListZones:output_type -> garden.v1.ListZonesResponse
+	3,  // 30: garden.v1.GardenService.GetZone:output_type -> garden.v1.Zone
+	22, // 31: garden.v1.GardenService.ExecuteZoneAction:output_type -> garden.v1.ExecuteZoneActionResponse
+	14, // 32: garden.v1.GardenService.ListWaterSchedules:output_type -> garden.v1.ListWaterSchedulesResponse
+	5,  // 33: garden.v1.GardenService.GetWaterSchedule:output_type -> garden.v1.WaterSchedule
+	24, // 34: garden.v1.GardenService.WatchWaterEvents:output_type -> garden.v1.WaterEvent
+	26, // [26:35] is the sub-list for method output_type
+	17, // [17:26] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_garden_proto_init() }
+func file_garden_proto_init() {
+	if File_garden_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_garden_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Garden); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Zone); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ZoneDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WaterSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActivePeriod); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListGardensRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListGardensResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetGardenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListZonesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListZonesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetZoneRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListWaterSchedulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListWaterSchedulesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetWaterScheduleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteGardenActionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteGardenActionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WaterAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteZoneActionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteZoneActionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchWaterEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_garden_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WaterEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_garden_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_garden_proto_goTypes,
+		DependencyIndexes: file_garden_proto_depIdxs,
+		EnumInfos:         file_garden_proto_enumTypes,
+		MessageInfos:      file_garden_proto_msgTypes,
+	}.Build()
+	File_garden_proto = out.File
+	file_garden_proto_rawDesc = nil
+	file_garden_proto_goTypes = nil
+	file_garden_proto_depIdxs = nil
+}
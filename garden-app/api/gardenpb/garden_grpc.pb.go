@@ -0,0 +1,435 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.4
+// source: garden.proto
+
+package gardenpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	GardenService_ListGardens_FullMethodName         = "/garden.v1.GardenService/ListGardens"
+	GardenService_GetGarden_FullMethodName           = "/garden.v1.GardenService/GetGarden"
+	GardenService_ExecuteGardenAction_FullMethodName = "/garden.v1.GardenService/ExecuteGardenAction"
+	GardenService_ListZones_FullMethodName           = "/garden.v1.GardenService/ListZones"
+	GardenService_GetZone_FullMethodName             = "/garden.v1.GardenService/GetZone"
+	GardenService_ExecuteZoneAction_FullMethodName   = "/garden.v1.GardenService/ExecuteZoneAction"
+	GardenService_ListWaterSchedules_FullMethodName  = "/garden.v1.GardenService/ListWaterSchedules"
+	GardenService_GetWaterSchedule_FullMethodName    = "/garden.v1.GardenService/GetWaterSchedule"
+	GardenService_WatchWaterEvents_FullMethodName    = "/garden.v1.GardenService/WatchWaterEvents"
+)
+
+// GardenServiceClient is the client API for GardenService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GardenServiceClient interface {
+	ListGardens(ctx context.Context, in *ListGardensRequest, opts ...grpc.CallOption) (*ListGardensResponse, error)
+	GetGarden(ctx context.Context, in *GetGardenRequest, opts ...grpc.CallOption) (*Garden, error)
+	ExecuteGardenAction(ctx context.Context, in *ExecuteGardenActionRequest, opts ...grpc.CallOption) (*ExecuteGardenActionResponse, error)
+	ListZones(ctx context.Context, in *ListZonesRequest, opts ...grpc.CallOption) (*ListZonesResponse, error)
+	GetZone(ctx context.Context, in *GetZoneRequest, opts ...grpc.CallOption) (*Zone, error)
+	ExecuteZoneAction(ctx context.Context, in *ExecuteZoneActionRequest, opts ...grpc.CallOption) (*ExecuteZoneActionResponse, error)
+	ListWaterSchedules(ctx context.Context, in *ListWaterSchedulesRequest, opts ...grpc.CallOption) (*ListWaterSchedulesResponse, error)
+	GetWaterSchedule(ctx context.Context, in *GetWaterScheduleRequest, opts ...grpc.CallOption) (*WaterSchedule, error)
+	// WatchWaterEvents streams an event every time a Zone is watered until the client cancels
+	WatchWaterEvents(ctx context.Context, in *WatchWaterEventsRequest, opts ...grpc.CallOption) (GardenService_WatchWaterEventsClient, error)
+}
+
+type gardenServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGardenServiceClient(cc grpc.ClientConnInterface) GardenServiceClient {
+	return &gardenServiceClient{cc}
+}
+
+func (c *gardenServiceClient) ListGardens(ctx context.Context, in *ListGardensRequest, opts ...grpc.CallOption) (*ListGardensResponse, error) {
+	out := new(ListGardensResponse)
+	err := c.cc.Invoke(ctx, GardenService_ListGardens_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gardenServiceClient) GetGarden(ctx context.Context, in *GetGardenRequest, opts ...grpc.CallOption) (*Garden, error) {
+	out := new(Garden)
+	err := c.cc.Invoke(ctx, GardenService_GetGarden_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gardenServiceClient) ExecuteGardenAction(ctx context.Context, in *ExecuteGardenActionRequest, opts ...grpc.CallOption) (*ExecuteGardenActionResponse, error) {
+	out := new(ExecuteGardenActionResponse)
+	err := c.cc.Invoke(ctx, GardenService_ExecuteGardenAction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gardenServiceClient) ListZones(ctx context.Context, in *ListZonesRequest, opts ...grpc.CallOption) (*ListZonesResponse, error) {
+	out := new(ListZonesResponse)
+	err := c.cc.Invoke(ctx, GardenService_ListZones_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gardenServiceClient) GetZone(ctx context.Context, in *GetZoneRequest, opts ...grpc.CallOption) (*Zone, error) {
+	out := new(Zone)
+	err := c.cc.Invoke(ctx, GardenService_GetZone_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gardenServiceClient) ExecuteZoneAction(ctx context.Context, in *ExecuteZoneActionRequest, opts ...grpc.CallOption) (*ExecuteZoneActionResponse, error) {
+	out := new(ExecuteZoneActionResponse)
+	err := c.cc.Invoke(ctx, GardenService_ExecuteZoneAction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gardenServiceClient) ListWaterSchedules(ctx context.Context, in *ListWaterSchedulesRequest, opts ...grpc.CallOption) (*ListWaterSchedulesResponse, error) {
+	out := new(ListWaterSchedulesResponse)
+	err := c.cc.Invoke(ctx, GardenService_ListWaterSchedules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gardenServiceClient) GetWaterSchedule(ctx context.Context, in *GetWaterScheduleRequest, opts ...grpc.CallOption) (*WaterSchedule, error) {
+	out := new(WaterSchedule)
+	err := c.cc.Invoke(ctx, GardenService_GetWaterSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gardenServiceClient) WatchWaterEvents(ctx context.Context, in *WatchWaterEventsRequest, opts ...grpc.CallOption) (GardenService_WatchWaterEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GardenService_ServiceDesc.Streams[0], GardenService_WatchWaterEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gardenServiceWatchWaterEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GardenService_WatchWaterEventsClient interface {
+	Recv() (*WaterEvent, error)
+	grpc.ClientStream
+}
+
+type gardenServiceWatchWaterEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *gardenServiceWatchWaterEventsClient) Recv() (*WaterEvent, error) {
+	m := new(WaterEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GardenServiceServer is the server API for GardenService service.
+// All implementations must embed UnimplementedGardenServiceServer
+// for forward compatibility
+type GardenServiceServer interface {
+	ListGardens(context.Context, *ListGardensRequest) (*ListGardensResponse, error)
+	GetGarden(context.Context, *GetGardenRequest) (*Garden, error)
+	ExecuteGardenAction(context.Context, *ExecuteGardenActionRequest) (*ExecuteGardenActionResponse, error)
+	ListZones(context.Context, *ListZonesRequest) (*ListZonesResponse, error)
+	GetZone(context.Context, *GetZoneRequest) (*Zone, error)
+	ExecuteZoneAction(context.Context, *ExecuteZoneActionRequest) (*ExecuteZoneActionResponse, error)
+	ListWaterSchedules(context.Context, *ListWaterSchedulesRequest) (*ListWaterSchedulesResponse, error)
+	GetWaterSchedule(context.Context, *GetWaterScheduleRequest) (*WaterSchedule, error)
+	// WatchWaterEvents streams an event every time a Zone is watered until the client cancels
+	WatchWaterEvents(*WatchWaterEventsRequest, GardenService_WatchWaterEventsServer) error
+	mustEmbedUnimplementedGardenServiceServer()
+}
+
+// UnimplementedGardenServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedGardenServiceServer struct {
+}
+
+func (UnimplementedGardenServiceServer) ListGardens(context.Context, *ListGardensRequest) (*ListGardensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGardens not implemented")
+}
+func (UnimplementedGardenServiceServer) GetGarden(context.Context, *GetGardenRequest) (*Garden, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGarden not implemented")
+}
+func (UnimplementedGardenServiceServer) ExecuteGardenAction(context.Context, *ExecuteGardenActionRequest) (*ExecuteGardenActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteGardenAction not implemented")
+}
+func (UnimplementedGardenServiceServer) ListZones(context.Context, *ListZonesRequest) (*ListZonesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListZones not implemented")
+}
+func (UnimplementedGardenServiceServer) GetZone(context.Context, *GetZoneRequest) (*Zone, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetZone not implemented")
+}
+func (UnimplementedGardenServiceServer) ExecuteZoneAction(context.Context, *ExecuteZoneActionRequest) (*ExecuteZoneActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteZoneAction not implemented")
+}
+func (UnimplementedGardenServiceServer) ListWaterSchedules(context.Context, *ListWaterSchedulesRequest) (*ListWaterSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWaterSchedules not implemented")
+}
+func (UnimplementedGardenServiceServer) GetWaterSchedule(context.Context, *GetWaterScheduleRequest) (*WaterSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWaterSchedule not implemented")
+}
+func (UnimplementedGardenServiceServer) WatchWaterEvents(*WatchWaterEventsRequest, GardenService_WatchWaterEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchWaterEvents not implemented")
+}
+func (UnimplementedGardenServiceServer) mustEmbedUnimplementedGardenServiceServer() {}
+
+// UnsafeGardenServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GardenServiceServer will
+// result in compilation errors.
+type UnsafeGardenServiceServer interface {
+	mustEmbedUnimplementedGardenServiceServer()
+}
+
+func RegisterGardenServiceServer(s grpc.ServiceRegistrar, srv GardenServiceServer) {
+	s.RegisterService(&GardenService_ServiceDesc, srv)
+}
+
+func _GardenService_ListGardens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGardensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GardenServiceServer).ListGardens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GardenService_ListGardens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GardenServiceServer).ListGardens(ctx, req.(*ListGardensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GardenService_GetGarden_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGardenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GardenServiceServer).GetGarden(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GardenService_GetGarden_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GardenServiceServer).GetGarden(ctx, req.(*GetGardenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GardenService_ExecuteGardenAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteGardenActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GardenServiceServer).ExecuteGardenAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GardenService_ExecuteGardenAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GardenServiceServer).ExecuteGardenAction(ctx, req.(*ExecuteGardenActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GardenService_ListZones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListZonesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GardenServiceServer).ListZones(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GardenService_ListZones_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GardenServiceServer).ListZones(ctx, req.(*ListZonesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GardenService_GetZone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GardenServiceServer).GetZone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GardenService_GetZone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GardenServiceServer).GetZone(ctx, req.(*GetZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GardenService_ExecuteZoneAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteZoneActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GardenServiceServer).ExecuteZoneAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GardenService_ExecuteZoneAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GardenServiceServer).ExecuteZoneAction(ctx, req.(*ExecuteZoneActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GardenService_ListWaterSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWaterSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GardenServiceServer).ListWaterSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GardenService_ListWaterSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GardenServiceServer).ListWaterSchedules(ctx, req.(*ListWaterSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GardenService_GetWaterSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWaterScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GardenServiceServer).GetWaterSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GardenService_GetWaterSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GardenServiceServer).GetWaterSchedule(ctx, req.(*GetWaterScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GardenService_WatchWaterEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchWaterEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GardenServiceServer).WatchWaterEvents(m, &gardenServiceWatchWaterEventsServer{stream})
+}
+
+type GardenService_WatchWaterEventsServer interface {
+	Send(*WaterEvent) error
+	grpc.ServerStream
+}
+
+type gardenServiceWatchWaterEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *gardenServiceWatchWaterEventsServer) Send(m *WaterEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// GardenService_ServiceDesc is the grpc.ServiceDesc for GardenService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GardenService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "garden.v1.GardenService",
+	HandlerType: (*GardenServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListGardens",
+			Handler:    _GardenService_ListGardens_Handler,
+		},
+		{
+			MethodName: "GetGarden",
+			Handler:    _GardenService_GetGarden_Handler,
+		},
+		{
+			MethodName: "ExecuteGardenAction",
+			Handler:    _GardenService_ExecuteGardenAction_Handler,
+		},
+		{
+			MethodName: "ListZones",
+			Handler:    _GardenService_ListZones_Handler,
+		},
+		{
+			MethodName: "GetZone",
+			Handler:    _GardenService_GetZone_Handler,
+		},
+		{
+			MethodName: "ExecuteZoneAction",
+			Handler:    _GardenService_ExecuteZoneAction_Handler,
+		},
+		{
+			MethodName: "ListWaterSchedules",
+			Handler:    _GardenService_ListWaterSchedules_Handler,
+		},
+		{
+			MethodName: "GetWaterSchedule",
+			Handler:    _GardenService_GetWaterSchedule_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchWaterEvents",
+			Handler:       _GardenService_WatchWaterEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "garden.proto",
+}
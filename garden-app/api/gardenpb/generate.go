@@ -0,0 +1,4 @@
+// Package gardenpb contains the protobuf messages and gRPC service generated from garden.proto
+package gardenpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative garden.proto
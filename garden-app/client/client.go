@@ -0,0 +1,153 @@
+// Package client is a Go client for the garden-app API. It wraps the babyapi Clients for each resource with typed
+// methods, so other programs don't need to create requests or decode responses themselves
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+)
+
+const includeEndDatedQuery = "include_end_dated=true"
+
+// Client is used to manage resources and execute actions with the API. The babyapi Clients are available for
+// requests that don't have a typed method
+type Client struct {
+	Gardens        *babyapi.Client[*pkg.Garden]
+	Zones          *babyapi.Client[*pkg.Zone]
+	WaterSchedules *babyapi.Client[*pkg.WaterSchedule]
+	WeatherClients *babyapi.Client[*weather.Config]
+
+	httpClient    *http.Client
+	requestEditor babyapi.RequestEditor
+}
+
+// New creates a Client for the API at the address, like "http://localhost:8080"
+func New(address string) *Client {
+	address = strings.TrimSuffix(address, "/")
+
+	c := &Client{
+		Gardens:        babyapi.NewClient[*pkg.Garden](address, "/gardens"),
+		WaterSchedules: babyapi.NewClient[*pkg.WaterSchedule](address, "/water_schedules"),
+		WeatherClients: babyapi.NewClient[*weather.Config](address, "/weather_clients"),
+		httpClient:     http.DefaultClient,
+		requestEditor:  babyapi.DefaultRequestEditor,
+	}
+	c.Zones = babyapi.NewSubClient[*pkg.Garden, *pkg.Zone](c.Gardens, "/zones")
+
+	return c
+}
+
+// SetHTTPClient sets the HTTP client used for all requests
+func (c *Client) SetHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	c.Gardens.SetHTTPClient(httpClient)
+	c.Zones.SetHTTPClient(httpClient)
+	c.WaterSchedules.SetHTTPClient(httpClient)
+	c.WeatherClients.SetHTTPClient(httpClient)
+	return c
+}
+
+// SetRequestEditor sets a function that is used to modify all requests before they are sent
+func (c *Client) SetRequestEditor(requestEditor babyapi.RequestEditor) *Client {
+	c.requestEditor = requestEditor
+	c.Gardens.SetRequestEditor(requestEditor)
+	c.Zones.SetRequestEditor(requestEditor)
+	c.WaterSchedules.SetRequestEditor(requestEditor)
+	c.WeatherClients.SetRequestEditor(requestEditor)
+	return c
+}
+
+// SetToken uses an API token for all requests. This is required when authentication is enabled
+func (c *Client) SetToken(token string) *Client {
+	return c.SetRequestEditor(func(r *http.Request) error {
+		r.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// get gets a resource by ID
+func get[T babyapi.Resource](ctx context.Context, c *babyapi.Client[T], id string, parentIDs ...string) (T, error) {
+	resp, err := c.Get(ctx, id, parentIDs...)
+	if err != nil {
+		return *new(T), err
+	}
+	return resp.Data, nil
+}
+
+// list gets all resources. End-dated resources are only included if includeEndDated is true
+func list[T babyapi.Resource](ctx context.Context, c *babyapi.Client[T], includeEndDated bool, parentIDs ...string) ([]T, error) {
+	query := ""
+	if includeEndDated {
+		query = includeEndDatedQuery
+	}
+
+	resp, err := c.GetAll(ctx, query, parentIDs...)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+	return resp.Data.Items, nil
+}
+
+// create creates a new resource and returns it with the ID and defaults from the API
+func create[T babyapi.Resource](ctx context.Context, c *babyapi.Client[T], resource T, parentIDs ...string) (T, error) {
+	resp, err := c.Post(ctx, resource, parentIDs...)
+	if err != nil {
+		return *new(T), err
+	}
+	return resp.Data, nil
+}
+
+// update patches an existing resource. Only fields that are set in the resource are changed, and the ID must not be
+// set since it can't be updated
+func update[T babyapi.Resource](ctx context.Context, c *babyapi.Client[T], id string, resource T, parentIDs ...string) (T, error) {
+	resp, err := c.Patch(ctx, id, resource, parentIDs...)
+	if err != nil {
+		return *new(T), err
+	}
+	return resp.Data, nil
+}
+
+// remove deletes a resource. End-dateable resources are end-dated the first time they are deleted
+func remove[T babyapi.Resource](ctx context.Context, c *babyapi.Client[T], id string, parentIDs ...string) error {
+	_, err := c.Delete(ctx, id, parentIDs...)
+	return err
+}
+
+// do sends a JSON request to a custom route and decodes the response into T. A nil body sends an empty request
+func do[T any](ctx context.Context, c *Client, method, url string, body any, expectedStatusCode int) (T, error) {
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return *new(T), fmt.Errorf("error encoding request body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return *new(T), fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := babyapi.MakeRequest[T](req, c.httpClient, expectedStatusCode, c.requestEditor)
+	if err != nil {
+		return *new(T), err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gardenID = "c5cvhpcbcv45e8bp16dg"
+	zoneID   = "chkodpg3lcj13q82mq40"
+)
+
+type request struct {
+	method string
+	path   string
+	query  string
+	body   string
+	auth   string
+}
+
+// setupServer creates a server that records the request and responds with the status and body
+func setupServer(t *testing.T, status int, body string) (*Client, *request) {
+	t.Helper()
+
+	received := &request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		*received = request{r.Method, r.URL.Path, r.URL.RawQuery, string(data), r.Header.Get("Authorization")}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return New(server.URL + "/").SetToken("token"), received
+}
+
+func TestCreateGarden(t *testing.T) {
+	c, received := setupServer(t, http.StatusCreated, `{"id":"c5cvhpcbcv45e8bp16dg","name":"test-garden","topic_prefix":"test"}`)
+
+	g, err := c.CreateGarden(context.Background(), &pkg.Garden{Name: "test-garden", TopicPrefix: "test"})
+	require.NoError(t, err)
+
+	assert.Equal(t, gardenID, g.GetID())
+	assert.Equal(t, "test-garden", g.Name)
+
+	assert.Equal(t, http.MethodPost, received.method)
+	assert.Equal(t, "/gardens", received.path)
+	assert.Equal(t, "Bearer token", received.auth)
+
+	var sent map[string]any
+	require.NoError(t, json.Unmarshal([]byte(received.body), &sent))
+	assert.Equal(t, "test-garden", sent["name"])
+}
+
+func TestListZones(t *testing.T) {
+	c, received := setupServer(t, http.StatusOK, `{"items":[{"id":"chkodpg3lcj13q82mq40","name":"test-zone"}]}`)
+
+	zones, err := c.ListZones(context.Background(), gardenID, true)
+	require.NoError(t, err)
+
+	require.Len(t, zones, 1)
+	assert.Equal(t, "test-zone", zones[0].Name)
+
+	assert.Equal(t, http.MethodGet, received.method)
+	assert.Equal(t, "/gardens/"+gardenID+"/zones", received.path)
+	assert.Equal(t, "include_end_dated=true", received.query)
+}
+
+func TestWaterZone(t *testing.T) {
+	c, received := setupServer(t, http.StatusAccepted, `{}`)
+
+	err := c.WaterZone(context.Background(), gardenID, zoneID, 15*time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, received.method)
+	assert.Equal(t, "/gardens/"+gardenID+"/zones/"+zoneID+"/action", received.path)
+	assert.JSONEq(t, `{"water":{"duration":"15m0s","ignore_moisture":false,"ignore_weather":false,"ignore_budget":false,"dry_run":false},"stop":null}`, received.body)
+}
+
+func TestSetLight(t *testing.T) {
+	c, received := setupServer(t, http.StatusAccepted, `{}`)
+
+	err := c.SetLight(context.Background(), gardenID, pkg.LightStateOn, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/gardens/"+gardenID+"/action", received.path)
+	assert.JSONEq(t, `{"light":{"state":"ON","for_duration":"1h0m0s"},"stop":null,"stop_all":null}`, received.body)
+}
+
+func TestGetMoisture(t *testing.T) {
+	c, received := setupServer(t, http.StatusOK, `{"history":[{"record_time":"2023-08-24T00:00:00Z","percent":40}],"count":1,"range":"24h0m0s","resolution":"1h0m0s","average":40}`)
+
+	moisture, err := c.GetMoisture(context.Background(), gardenID, zoneID, 24*time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, moisture.Count)
+	assert.Equal(t, 40.0, moisture.History[0].Percent)
+	require.NotNil(t, moisture.Average)
+	assert.Equal(t, 40.0, *moisture.Average)
+
+	assert.Equal(t, http.MethodGet, received.method)
+	assert.Equal(t, "/gardens/"+gardenID+"/zones/"+zoneID+"/moisture", received.path)
+	assert.Equal(t, "range=24h0m0s&resolution=1h0m0s", received.query)
+	assert.Empty(t, received.body)
+}
+
+func TestPauseWaterSchedule(t *testing.T) {
+	c, received := setupServer(t, http.StatusOK, `{"id":"c5cvhpcbcv45e8bp16dg","paused":true}`)
+
+	_, err := c.PauseWaterSchedule(context.Background(), gardenID)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, received.method)
+	assert.Equal(t, "/water_schedules/"+gardenID+"/pause", received.path)
+}
+
+func TestErrorResponse(t *testing.T) {
+	c, _ := setupServer(t, http.StatusBadRequest, `{"status":"Invalid request.","error":"missing required name field"}`)
+
+	t.Run("Resource", func(t *testing.T) {
+		_, err := c.CreateZone(context.Background(), gardenID, &pkg.Zone{})
+
+		var errResp *babyapi.ErrResponse
+		require.True(t, errors.As(err, &errResp))
+		assert.Equal(t, http.StatusBadRequest, errResp.HTTPStatusCode)
+		assert.Equal(t, "missing required name field", errResp.ErrorText)
+	})
+
+	t.Run("Action", func(t *testing.T) {
+		err := c.StopZone(context.Background(), gardenID, zoneID)
+
+		var errResp *babyapi.ErrResponse
+		require.True(t, errors.As(err, &errResp))
+		assert.Equal(t, http.StatusBadRequest, errResp.HTTPStatusCode)
+	})
+}
+
+func TestUpdateZone(t *testing.T) {
+	c, received := setupServer(t, http.StatusOK, `{"id":"chkodpg3lcj13q82mq40","name":"new-name"}`)
+
+	zone, err := c.UpdateZone(context.Background(), gardenID, zoneID, &pkg.Zone{Name: "new-name"})
+	require.NoError(t, err)
+	assert.Equal(t, "new-name", zone.Name)
+
+	assert.Equal(t, http.MethodPatch, received.method)
+	assert.Equal(t, "/gardens/"+gardenID+"/zones/"+zoneID, received.path)
+
+	var sent map[string]any
+	require.NoError(t, json.Unmarshal([]byte(received.body), &sent))
+	assert.Equal(t, "new-name", sent["name"])
+	assert.Nil(t, sent["id"])
+}
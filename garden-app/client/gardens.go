@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+)
+
+// GetGarden gets a Garden by ID
+func (c *Client) GetGarden(ctx context.Context, id string) (*pkg.Garden, error) {
+	return get(ctx, c.Gardens, id)
+}
+
+// ListGardens gets all Gardens
+func (c *Client) ListGardens(ctx context.Context, includeEndDated bool) ([]*pkg.Garden, error) {
+	return list(ctx, c.Gardens, includeEndDated)
+}
+
+// CreateGarden creates a new Garden
+func (c *Client) CreateGarden(ctx context.Context, garden *pkg.Garden) (*pkg.Garden, error) {
+	return create(ctx, c.Gardens, garden)
+}
+
+// UpdateGarden changes the fields that are set in the Garden
+func (c *Client) UpdateGarden(ctx context.Context, id string, garden *pkg.Garden) (*pkg.Garden, error) {
+	return update(ctx, c.Gardens, id, garden)
+}
+
+// DeleteGarden end-dates a Garden, or removes it if it is already end-dated
+func (c *Client) DeleteGarden(ctx context.Context, id string) error {
+	return remove(ctx, c.Gardens, id)
+}
+
+// GardenAction executes a GardenAction, like controlling the light or stopping watering
+func (c *Client) GardenAction(ctx context.Context, gardenID string, gardenAction *action.GardenAction) error {
+	gardenURL, err := c.Gardens.URL(gardenID)
+	if err != nil {
+		return err
+	}
+
+	_, err = do[map[string]any](ctx, c, http.MethodPost, gardenURL+"/action", gardenAction, http.StatusAccepted)
+	if err != nil {
+		return fmt.Errorf("error executing GardenAction: %w", err)
+	}
+	return nil
+}
+
+// SetLight turns the Garden's light on or off. If forDuration is more than zero, it is changed back after the duration
+func (c *Client) SetLight(ctx context.Context, gardenID string, state pkg.LightState, forDuration time.Duration) error {
+	light := &action.LightAction{State: state}
+	if forDuration > 0 {
+		light.ForDuration = &pkg.Duration{Duration: forDuration}
+	}
+	return c.GardenAction(ctx, gardenID, &action.GardenAction{Light: light})
+}
+
+// StopWatering stops the Garden's current watering. If all is true, the queue of Zones to water is also cleared
+func (c *Client) StopWatering(ctx context.Context, gardenID string, all bool) error {
+	return c.GardenAction(ctx, gardenID, &action.GardenAction{Stop: &action.StopAction{All: all}})
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+)
+
+// GetWaterSchedule gets a WaterSchedule by ID
+func (c *Client) GetWaterSchedule(ctx context.Context, id string) (*pkg.WaterSchedule, error) {
+	return get(ctx, c.WaterSchedules, id)
+}
+
+// ListWaterSchedules gets all WaterSchedules
+func (c *Client) ListWaterSchedules(ctx context.Context, includeEndDated bool) ([]*pkg.WaterSchedule, error) {
+	return list(ctx, c.WaterSchedules, includeEndDated)
+}
+
+// CreateWaterSchedule creates a new WaterSchedule
+func (c *Client) CreateWaterSchedule(ctx context.Context, ws *pkg.WaterSchedule) (*pkg.WaterSchedule, error) {
+	return create(ctx, c.WaterSchedules, ws)
+}
+
+// UpdateWaterSchedule changes the fields that are set in the WaterSchedule
+func (c *Client) UpdateWaterSchedule(ctx context.Context, id string, ws *pkg.WaterSchedule) (*pkg.WaterSchedule, error) {
+	return update(ctx, c.WaterSchedules, id, ws)
+}
+
+// DeleteWaterSchedule end-dates a WaterSchedule, or removes it if it is already end-dated
+func (c *Client) DeleteWaterSchedule(ctx context.Context, id string) error {
+	return remove(ctx, c.WaterSchedules, id)
+}
+
+// PauseWaterSchedule stops the WaterSchedule from watering until it is resumed
+func (c *Client) PauseWaterSchedule(ctx context.Context, id string) (*pkg.WaterSchedule, error) {
+	return c.waterScheduleAction(ctx, id, "/pause")
+}
+
+// ResumeWaterSchedule continues watering with a paused WaterSchedule
+func (c *Client) ResumeWaterSchedule(ctx context.Context, id string) (*pkg.WaterSchedule, error) {
+	return c.waterScheduleAction(ctx, id, "/resume")
+}
+
+func (c *Client) waterScheduleAction(ctx context.Context, id, path string) (*pkg.WaterSchedule, error) {
+	wsURL, err := c.WaterSchedules.URL(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := do[*pkg.WaterSchedule](ctx, c, http.MethodPost, wsURL+path, nil, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("error updating WaterSchedule: %w", err)
+	}
+	return ws, nil
+}
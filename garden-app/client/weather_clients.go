@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+)
+
+// GetWeatherClient gets a WeatherClient's config by ID
+func (c *Client) GetWeatherClient(ctx context.Context, id string) (*weather.Config, error) {
+	return get(ctx, c.WeatherClients, id)
+}
+
+// ListWeatherClients gets all WeatherClient configs
+func (c *Client) ListWeatherClients(ctx context.Context) ([]*weather.Config, error) {
+	return list(ctx, c.WeatherClients, false)
+}
+
+// CreateWeatherClient creates a new WeatherClient
+func (c *Client) CreateWeatherClient(ctx context.Context, config *weather.Config) (*weather.Config, error) {
+	return create(ctx, c.WeatherClients, config)
+}
+
+// UpdateWeatherClient changes the fields that are set in the WeatherClient's config
+func (c *Client) UpdateWeatherClient(ctx context.Context, id string, config *weather.Config) (*weather.Config, error) {
+	return update(ctx, c.WeatherClients, id, config)
+}
+
+// DeleteWeatherClient removes a WeatherClient
+func (c *Client) DeleteWeatherClient(ctx context.Context, id string) error {
+	return remove(ctx, c.WeatherClients, id)
+}
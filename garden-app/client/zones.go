@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+)
+
+// WaterHistory is a Zone's recent waterings with some statistics about them
+type WaterHistory struct {
+	History        []pkg.WaterHistory `json:"history"`
+	Count          int                `json:"count"`
+	Average        string             `json:"average"`
+	Total          string             `json:"total"`
+	MeasuredLiters *float64           `json:"measured_liters,omitempty"`
+	ExpectedLiters *float64           `json:"expected_liters,omitempty"`
+}
+
+// MoistureHistory is a Zone's soil moisture series with the minimum, maximum, and average. The statistics are not
+// set when there is no data
+type MoistureHistory struct {
+	History    []pkg.MoistureHistory `json:"history"`
+	Count      int                   `json:"count"`
+	Range      string                `json:"range"`
+	Resolution string                `json:"resolution"`
+	Average    *float64              `json:"average,omitempty"`
+	Min        *float64              `json:"min,omitempty"`
+	Max        *float64              `json:"max,omitempty"`
+}
+
+// GetZone gets a Zone by ID
+func (c *Client) GetZone(ctx context.Context, gardenID, id string) (*pkg.Zone, error) {
+	return get(ctx, c.Zones, id, gardenID)
+}
+
+// ListZones gets all of a Garden's Zones
+func (c *Client) ListZones(ctx context.Context, gardenID string, includeEndDated bool) ([]*pkg.Zone, error) {
+	return list(ctx, c.Zones, includeEndDated, gardenID)
+}
+
+// CreateZone creates a new Zone in the Garden
+func (c *Client) CreateZone(ctx context.Context, gardenID string, zone *pkg.Zone) (*pkg.Zone, error) {
+	return create(ctx, c.Zones, zone, gardenID)
+}
+
+// UpdateZone changes the fields that are set in the Zone
+func (c *Client) UpdateZone(ctx context.Context, gardenID, id string, zone *pkg.Zone) (*pkg.Zone, error) {
+	return update(ctx, c.Zones, id, zone, gardenID)
+}
+
+// DeleteZone end-dates a Zone, or removes it if it is already end-dated
+func (c *Client) DeleteZone(ctx context.Context, gardenID, id string) error {
+	return remove(ctx, c.Zones, id, gardenID)
+}
+
+// ZoneAction executes a ZoneAction, like watering or stopping
+func (c *Client) ZoneAction(ctx context.Context, gardenID, zoneID string, zoneAction *action.ZoneAction) error {
+	zoneURL, err := c.Zones.URL(zoneID, gardenID)
+	if err != nil {
+		return err
+	}
+
+	_, err = do[map[string]any](ctx, c, http.MethodPost, zoneURL+"/action", zoneAction, http.StatusAccepted)
+	if err != nil {
+		return fmt.Errorf("error executing ZoneAction: %w", err)
+	}
+	return nil
+}
+
+// WaterZone waters the Zone for the duration. The duration is still scaled by the Zone's weather and moisture
+// controls unless they are ignored with ZoneAction
+func (c *Client) WaterZone(ctx context.Context, gardenID, zoneID string, duration time.Duration) error {
+	return c.ZoneAction(ctx, gardenID, zoneID, &action.ZoneAction{
+		Water: &action.WaterAction{Duration: &pkg.Duration{Duration: duration}},
+	})
+}
+
+// StopZone stops watering the Zone and removes it from the queue
+func (c *Client) StopZone(ctx context.Context, gardenID, zoneID string) error {
+	return c.ZoneAction(ctx, gardenID, zoneID, &action.ZoneAction{Stop: &action.ZoneStopAction{}})
+}
+
+// GetWaterHistory gets the Zone's waterings from the recent time range. A limit of zero gets all of them
+func (c *Client) GetWaterHistory(ctx context.Context, gardenID, zoneID string, timeRange time.Duration, limit uint64) (*WaterHistory, error) {
+	query := url.Values{}
+	query.Set("range", timeRange.String())
+	query.Set("limit", strconv.FormatUint(limit, 10))
+
+	return zoneHistory[*WaterHistory](ctx, c, gardenID, zoneID, "/history", query)
+}
+
+// GetMoisture gets the Zone's soil moisture from the recent time range, averaged at the resolution
+func (c *Client) GetMoisture(ctx context.Context, gardenID, zoneID string, timeRange, resolution time.Duration) (*MoistureHistory, error) {
+	query := url.Values{}
+	query.Set("range", timeRange.String())
+	query.Set("resolution", resolution.String())
+
+	return zoneHistory[*MoistureHistory](ctx, c, gardenID, zoneID, "/moisture", query)
+}
+
+// zoneHistory gets one of the Zone's history routes
+func zoneHistory[T any](ctx context.Context, c *Client, gardenID, zoneID, path string, query url.Values) (T, error) {
+	zoneURL, err := c.Zones.URL(zoneID, gardenID)
+	if err != nil {
+		return *new(T), err
+	}
+	return do[T](ctx, c, http.MethodGet, zoneURL+path+"?"+query.Encode(), nil, http.StatusOK)
+}
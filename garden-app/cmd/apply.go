@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFilename string
+	applyFormat   string
+	applyPrune    bool
+	applyDryRun   bool
+
+	applyCommand = &cobra.Command{
+		Use:   "apply",
+		Short: "Make storage match a declarative file",
+		Long:  `Compares the resources in a file with the same format as export to the configured storage and prints a diff. Then, it creates and updates resources so storage matches the file. Restart the server afterwards so it schedules the changed resources, or use the server's declarative config option instead`,
+		Run:   runApply,
+	}
+)
+
+func init() {
+	applyCommand.Flags().StringVarP(&applyFilename, "file", "f", "", "file with the desired resources")
+	applyCommand.Flags().StringVar(&applyFormat, "format", "", "format of the file (json or yaml). Defaults to the file extension")
+	applyCommand.Flags().BoolVar(&applyPrune, "prune", false, "delete resources that are not in the file")
+	applyCommand.Flags().BoolVar(&applyDryRun, "dry-run", false, "only print the diff without changing anything")
+	applyCommand.MarkFlagRequired("file")
+}
+
+// fileFormat uses the format flag if it is set, otherwise it uses the file extension
+func fileFormat(filename, format string) string {
+	if format != "" {
+		return format
+	}
+	if filepath.Ext(filename) == ".json" {
+		return "json"
+	}
+	return "yaml"
+}
+
+// runApply will print the changes needed to make storage match the file and then apply them
+func runApply(cmd *cobra.Command, _ []string) {
+	f, err := os.Open(applyFilename)
+	if err != nil {
+		cmd.PrintErrln("error opening file:", err)
+		return
+	}
+	defer f.Close()
+
+	desired, err := storage.ReadExport(f, fileFormat(applyFilename, applyFormat))
+	if err != nil {
+		cmd.PrintErrln("error reading file:", err)
+		return
+	}
+
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("unable to initialize storage client:", err)
+		return
+	}
+
+	plan, err := storageClient.PlanApply(context.Background(), desired, applyPrune)
+	if err != nil {
+		cmd.PrintErrln("error comparing resources:", err)
+		return
+	}
+
+	err = plan.WriteDiff(cmd.OutOrStdout())
+	if err != nil {
+		cmd.PrintErrln("error writing diff:", err)
+		return
+	}
+
+	if applyDryRun || len(plan.Changes) == 0 {
+		return
+	}
+
+	err = storageClient.Apply(context.Background(), plan)
+	if err != nil {
+		cmd.PrintErrln("error applying changes:", err)
+		return
+	}
+
+	cmd.Printf("applied %d changes\n", len(plan.Changes))
+}
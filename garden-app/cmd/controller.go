@@ -15,6 +15,7 @@ var (
 	moistureValue               int
 	moistureInterval            time.Duration
 	publishWaterEvent           bool
+	simulateWatering            bool
 	publishHealth               bool
 	healthInterval              time.Duration
 	enableUI                    bool
@@ -22,13 +23,18 @@ var (
 	temperatureHumidityInterval time.Duration
 	temperatureValue            float64
 	humidityValue               float64
+	flowRate                    float64
+	httpAddress                 string
 
 	controllerCommand = &cobra.Command{
 		Use:     "controller",
 		Aliases: []string{"controller run"},
 		Short:   "Run a mock garden-controller",
-		Long:    `Subscribes on a MQTT topic to act as a mock garden-controller for testing purposes`,
-		Run:     runController,
+		Long: `Subscribes on a MQTT topic to act as a mock garden-controller for testing purposes.
+
+When the config file has a list of controller.controllers, a virtual controller is started for each one in this
+process. Options that are not set for a virtual controller use the flags or config file values`,
+		Run: runController,
 	}
 )
 
@@ -58,6 +64,9 @@ func init() {
 	controllerCommand.PersistentFlags().BoolVar(&publishWaterEvent, "publish-water-event", true, "Whether or not watering events should be published for logging")
 	viper.BindPFlag("controller.publish_water_event", controllerCommand.PersistentFlags().Lookup("publish-water-event"))
 
+	controllerCommand.PersistentFlags().BoolVar(&simulateWatering, "simulate-watering", true, "Wait for each watering's duration before publishing the water event so stop commands can interrupt it")
+	viper.BindPFlag("controller.simulate_watering", controllerCommand.PersistentFlags().Lookup("simulate-watering"))
+
 	controllerCommand.PersistentFlags().BoolVar(&publishHealth, "publish-health", true, "Whether or not to publish health data every minute")
 	viper.BindPFlag("controller.publish_health", controllerCommand.PersistentFlags().Lookup("publish-health"))
 
@@ -78,6 +87,12 @@ func init() {
 
 	controllerCommand.PersistentFlags().Float64Var(&humidityValue, "humidity-value", 100, "The value to use for humidity data publishing")
 	viper.BindPFlag("controller.humidity_value", controllerCommand.PersistentFlags().Lookup("humidity-value"))
+
+	controllerCommand.PersistentFlags().Float64Var(&flowRate, "flow-rate", 0, "Liters per minute to emulate with flow meter pulses for water events (0 to disable)")
+	viper.BindPFlag("controller.flow_rate", controllerCommand.PersistentFlags().Lookup("flow-rate"))
+
+	controllerCommand.PersistentFlags().StringVar(&httpAddress, "http-address", "", "Address for the HTTP API used to change the virtual hardware, like :8081 (empty to disable)")
+	viper.BindPFlag("controller.http_address", controllerCommand.PersistentFlags().Lookup("http-address"))
 }
 
 // runController will start up the mock garden-controller
@@ -88,6 +103,13 @@ func runController(cmd *cobra.Command, _ []string) {
 		return
 	}
 
+	if len(config.Controllers) > 0 {
+		if err := controller.RunVirtualControllers(config); err != nil {
+			cmd.PrintErrln("error running virtual Controllers:", err)
+		}
+		return
+	}
+
 	controller, err := controller.NewController(config)
 	if err != nil {
 		cmd.PrintErrln("error creating Controller:", err)
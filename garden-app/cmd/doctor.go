@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Statuses of a doctorCheck
+const (
+	checkOK   = "OK"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+	checkSkip = "SKIP"
+)
+
+var (
+	doctorTimeout time.Duration
+
+	doctorCommand = &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that the server's config and dependencies are ready",
+		Long:  `Validates the config file and checks the connection to storage, the MQTT broker, and InfluxDB. Then it gets data from each WeatherClient and makes sure each Garden's controller published health data recently. It exits with an error if any check fails`,
+		Args:  cobra.NoArgs,
+		Run:   runDoctor,
+	}
+)
+
+func init() {
+	doctorCommand.Flags().DurationVar(&doctorTimeout, "timeout", 10*time.Second, "timeout for each connection check")
+}
+
+// doctorCheck is one line of the readiness report
+type doctorCheck struct {
+	Name    string
+	Status  string
+	Details string
+}
+
+// doctor runs checks for the server's dependencies. Later checks use the clients created by earlier ones and are
+// skipped if those clients are not available
+type doctor struct {
+	config  server.Config
+	timeout time.Duration
+	checks  []doctorCheck
+
+	storageClient *storage.Client
+	metricsClient metrics.Client
+}
+
+// runDoctor prints the readiness report and exits with an error if any check failed
+func runDoctor(cmd *cobra.Command, _ []string) {
+	d := &doctor{timeout: doctorTimeout}
+	d.run(context.Background())
+
+	err := d.writeReport(cmd.OutOrStdout())
+	if err != nil {
+		cmd.PrintErrln("error writing report:", err)
+	}
+
+	if failed := d.failed(); failed > 0 {
+		cmd.PrintErrf("%d check(s) failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+// run reads the config and then runs all checks in order
+func (d *doctor) run(ctx context.Context) {
+	if !d.checkConfig() {
+		return
+	}
+
+	d.checkStorage(ctx)
+	d.checkMQTT()
+	d.checkMetrics(ctx)
+	d.checkWeatherClients(ctx)
+	d.checkGardens(ctx)
+}
+
+func (d *doctor) add(name, status, details string) {
+	d.checks = append(d.checks, doctorCheck{name, status, details})
+}
+
+// failed counts the failed checks
+func (d *doctor) failed() int {
+	count := 0
+	for _, c := range d.checks {
+		if c.Status == checkFail {
+			count++
+		}
+	}
+	return count
+}
+
+// writeReport writes a table with each check's status
+func (d *doctor) writeReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAILS")
+	for _, c := range d.checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Name, c.Status, c.Details)
+	}
+	return tw.Flush()
+}
+
+// checkConfig reads the config file and validates the values that are not checked by connecting to something.
+// Keys that are not used by the server are only a warning since they are often typos
+func (d *doctor) checkConfig() bool {
+	err := viper.ReadInConfig()
+	if err != nil {
+		d.add("config", checkFail, fmt.Sprintf("unable to read config file: %v", err))
+		return false
+	}
+
+	err = viper.Unmarshal(&d.config)
+	if err != nil {
+		d.add("config", checkFail, fmt.Sprintf("unable to read config from file: %v", err))
+		return false
+	}
+
+	problems := []string{}
+	err = d.config.LogConfig.Validate()
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	for i, bw := range d.config.BlackoutWindows {
+		err = bw.Validate()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid blackout_windows[%d]: %v", i, err))
+		}
+	}
+	err = d.config.CatchUp.Validate()
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	if d.config.Health.DownThreshold < 0 {
+		problems = append(problems, "health.down_threshold must not be negative")
+	}
+	if d.config.WebConfig.Port < 0 || d.config.WebConfig.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("invalid web_server.port %d", d.config.WebConfig.Port))
+	}
+	if len(problems) > 0 {
+		d.add("config", checkFail, strings.Join(problems, "; "))
+		return false
+	}
+
+	// Only the keys from the file are checked since the global config also has keys for flags, like the controller
+	// command's flags, that are not part of server.Config
+	fileConfig := viper.New()
+	fileConfig.SetConfigFile(viper.ConfigFileUsed())
+	err = fileConfig.ReadInConfig()
+	if err != nil {
+		d.add("config", checkFail, fmt.Sprintf("unable to read config file: %v", err))
+		return false
+	}
+
+	var exact server.Config
+	err = fileConfig.UnmarshalExact(&exact)
+	if err != nil {
+		d.add("config", checkWarn, fmt.Sprintf("%s has unused keys: %v", viper.ConfigFileUsed(), err))
+		return true
+	}
+
+	d.add("config", checkOK, viper.ConfigFileUsed())
+	return true
+}
+
+// checkStorage creates the storage client and reads the Gardens to make sure it is reachable
+func (d *doctor) checkStorage(ctx context.Context) {
+	storageClient, err := storage.NewClient(d.config.StorageConfig)
+	if err != nil {
+		d.add("storage", checkFail, fmt.Sprintf("unable to initialize storage client: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	_, err = storageClient.Gardens.GetAll(ctx, nil)
+	if err != nil {
+		d.add("storage", checkFail, fmt.Sprintf("unable to read Gardens: %v", err))
+		return
+	}
+
+	d.storageClient = storageClient
+	d.add("storage", checkOK, fmt.Sprintf("connected to %s storage", d.config.StorageConfig.Driver))
+}
+
+// checkMQTT connects to the broker. It is skipped in simulation mode since an in-memory broker is used
+func (d *doctor) checkMQTT() {
+	if d.config.Simulation.Enabled {
+		d.add("mqtt", checkSkip, "simulation mode uses an in-memory broker")
+		return
+	}
+
+	mqttClient, err := mqtt.NewClient(d.config.MQTTConfig, nil)
+	if err != nil {
+		d.add("mqtt", checkFail, fmt.Sprintf("unable to initialize MQTT client: %v", err))
+		return
+	}
+
+	// Connecting is not cancellable, so the result is ignored after the timeout
+	result := make(chan error, 1)
+	go func() {
+		result <- mqttClient.Connect()
+	}()
+
+	broker := fmt.Sprintf("%s:%d", d.config.MQTTConfig.Broker, d.config.MQTTConfig.Port)
+	select {
+	case err = <-result:
+	case <-time.After(d.timeout):
+		err = errors.New("timed out connecting")
+	}
+	if err != nil {
+		d.add("mqtt", checkFail, fmt.Sprintf("unable to connect to %s: %v", broker, err))
+		return
+	}
+	mqttClient.Disconnect(250)
+
+	d.add("mqtt", checkOK, "connected to "+broker)
+}
+
+// checkMetrics creates the metrics client and pings it
+func (d *doctor) checkMetrics(ctx context.Context) {
+	name := "influxdb"
+	if d.config.MetricsConfig.Driver == metrics.DriverPrometheus {
+		name = "prometheus"
+	}
+
+	metricsClient, err := metrics.NewClient(d.config.MetricsConfig, d.config.InfluxDBConfig)
+	if err != nil {
+		d.add(name, checkFail, fmt.Sprintf("unable to initialize metrics client: %v", err))
+		return
+	}
+
+	pinger, ok := metricsClient.(metrics.Pinger)
+	if !ok {
+		d.metricsClient = metricsClient
+		d.add(name, checkSkip, "client does not support ping")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	up, err := pinger.Ping(ctx)
+	if err == nil && !up {
+		err = errors.New("server is not running")
+	}
+	if err != nil {
+		metricsClient.Close()
+		d.add(name, checkFail, err.Error())
+		return
+	}
+
+	d.metricsClient = metricsClient
+	d.add(name, checkOK, "server is running")
+}
+
+// checkWeatherClients gets the total rain from each WeatherClient since it is used by every type of client
+func (d *doctor) checkWeatherClients(ctx context.Context) {
+	if d.storageClient == nil {
+		d.add("weather clients", checkSkip, "storage is not available")
+		return
+	}
+
+	configs, err := d.storageClient.WeatherClientConfigs.GetAll(ctx, nil)
+	if err != nil {
+		d.add("weather clients", checkFail, fmt.Sprintf("unable to get WeatherClients: %v", err))
+		return
+	}
+	if len(configs) == 0 {
+		d.add("weather clients", checkSkip, "no WeatherClients are configured")
+		return
+	}
+
+	for _, wc := range configs {
+		name := fmt.Sprintf("weather client %s (%s)", wc.GetID(), wc.Type)
+
+		weatherClient, err := d.storageClient.GetWeatherClient(wc.ID.ID)
+		if err != nil {
+			d.add(name, checkFail, fmt.Sprintf("unable to create client: %v", err))
+			continue
+		}
+
+		rain, err := weatherClient.GetTotalRain(24 * time.Hour)
+		if err != nil {
+			d.add(name, checkFail, fmt.Sprintf("unable to get total rain: %v", err))
+			continue
+		}
+		d.add(name, checkOK, fmt.Sprintf("%.1fmm of rain in the last 24h", rain))
+	}
+}
+
+// checkGardens makes sure each active Garden's controller published health data within the down threshold
+func (d *doctor) checkGardens(ctx context.Context) {
+	switch {
+	case d.storageClient == nil:
+		d.add("gardens", checkSkip, "storage is not available")
+		return
+	case d.metricsClient == nil:
+		d.add("gardens", checkSkip, "metrics are not available")
+		return
+	case d.config.Simulation.Enabled:
+		d.add("gardens", checkSkip, "simulation mode uses simulated controllers")
+		return
+	}
+
+	all, err := d.storageClient.Gardens.GetAll(ctx, nil)
+	if err != nil {
+		d.add("gardens", checkFail, fmt.Sprintf("unable to get Gardens: %v", err))
+		return
+	}
+
+	gardens := []*pkg.Garden{}
+	for _, g := range all {
+		if !g.EndDated() {
+			gardens = append(gardens, g)
+		}
+	}
+	if len(gardens) == 0 {
+		d.add("gardens", checkSkip, "no Gardens are configured")
+		return
+	}
+
+	threshold := d.config.Health.DownThreshold
+	if threshold <= 0 {
+		threshold = pkg.DefaultHealthThreshold
+	}
+
+	for _, g := range gardens {
+		name := fmt.Sprintf("garden %s (%s)", g.GetID(), g.Name)
+
+		queryCtx, cancel := context.WithTimeout(ctx, d.timeout)
+		lastContact, err := d.metricsClient.GetLastContact(queryCtx, g.TopicPrefix)
+		cancel()
+		if err != nil {
+			d.add(name, checkFail, fmt.Sprintf("unable to get last contact: %v", err))
+			continue
+		}
+
+		health := pkg.NewGardenHealth(lastContact, time.Now(), threshold)
+		status := checkOK
+		if health.Status != "UP" {
+			status = checkFail
+		}
+		d.add(name, status, health.Details)
+	}
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/promql"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/automated-garden/garden-app/server"
+	"github.com/calvinmclean/babyapi"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoctorCheckConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         string
+		expectedOK     bool
+		expectedStatus string
+		expectedDetail string
+	}{
+		{
+			"Valid",
+			"storage:\n  driver: hashmap\n",
+			true,
+			checkOK,
+			"config.yaml",
+		},
+		{
+			"UnusedKey",
+			"storage:\n  driver: hashmap\nstorag:\n  driver: hashmap\n",
+			true,
+			checkWarn,
+			"has unused keys",
+		},
+		{
+			"InvalidBlackoutWindow",
+			"blackout_windows:\n  - start_time: \"25:00\"\n    end_time: \"06:00\"\n",
+			false,
+			checkFail,
+			"invalid blackout_windows[0]",
+		},
+		{
+			"InvalidYAML",
+			"storage: [",
+			false,
+			checkFail,
+			"unable to read config file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(filename, []byte(tt.config), 0o600))
+
+			viper.SetConfigFile(filename)
+
+			d := &doctor{timeout: time.Second}
+			assert.Equal(t, tt.expectedOK, d.checkConfig())
+
+			require.Len(t, d.checks, 1)
+			assert.Equal(t, tt.expectedStatus, d.checks[0].Status)
+			assert.Contains(t, d.checks[0].Details, tt.expectedDetail)
+		})
+	}
+}
+
+// newDoctorMetricsServer creates a Prometheus-compatible server that responds to pings and reports the last contact
+func newDoctorMetricsServer(t *testing.T, lastContact time.Time) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/-/healthy":
+			_, _ = w.Write([]byte("OK"))
+		case "/api/v1/query":
+			fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[0,"%d"]}]}}`, lastContact.Unix())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestDoctorChecks(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, storageClient.Gardens.Set(ctx, &pkg.Garden{ID: babyapi.NewID(), Name: "Garden", TopicPrefix: "garden"}))
+	require.NoError(t, storageClient.WeatherClientConfigs.Set(ctx, &weather.Config{
+		ID:      babyapi.NewID(),
+		Type:    "fake",
+		Options: map[string]interface{}{"rain_mm": 12.5, "rain_interval": "24h"},
+	}))
+
+	tests := []struct {
+		name        string
+		lastContact time.Time
+		expected    []string
+	}{
+		{
+			"ControllerUp",
+			time.Now(),
+			[]string{checkOK, checkOK, checkOK},
+		},
+		{
+			"ControllerDown",
+			time.Now().Add(-time.Hour),
+			[]string{checkOK, checkOK, checkFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &doctor{
+				timeout:       time.Second,
+				storageClient: storageClient,
+				config: server.Config{
+					MetricsConfig: metrics.Config{
+						Driver:     metrics.DriverPrometheus,
+						Prometheus: promql.Config{Address: newDoctorMetricsServer(t, tt.lastContact)},
+					},
+				},
+			}
+
+			d.checkMetrics(ctx)
+			d.checkWeatherClients(ctx)
+			d.checkGardens(ctx)
+
+			statuses := []string{}
+			for _, c := range d.checks {
+				statuses = append(statuses, c.Status)
+			}
+			assert.Equal(t, tt.expected, statuses)
+			assert.Equal(t, strings.Count(strings.Join(tt.expected, ","), checkFail), d.failed())
+
+			var out bytes.Buffer
+			require.NoError(t, d.writeReport(&out))
+			assert.Contains(t, out.String(), "prometheus")
+			assert.Contains(t, out.String(), "12.5mm of rain in the last 24h")
+		})
+	}
+}
+
+func TestDoctorSkipsChecksWithoutClients(t *testing.T) {
+	d := &doctor{timeout: time.Second}
+	d.checkWeatherClients(context.Background())
+	d.checkGardens(context.Background())
+
+	assert.Equal(t, []doctorCheck{
+		{"weather clients", checkSkip, "storage is not available"},
+		{"gardens", checkSkip, "storage is not available"},
+	}, d.checks)
+	assert.Equal(t, 0, d.failed())
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	exportOutput string
+	exportFormat string
+	importFormat string
+
+	exportCommand = &cobra.Command{
+		Use:   "export",
+		Short: "Export all resources from storage",
+		Long:  `Writes all Gardens, Zones, WaterSchedules, and WeatherClients from the configured storage to a single JSON or YAML document`,
+		Run:   runExport,
+	}
+
+	importCommand = &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import resources into storage",
+		Long:  `Validates and saves all resources from a document created by export. Restart the server afterwards so it schedules the imported resources`,
+		Args:  cobra.ExactArgs(1),
+		Run:   runImport,
+	}
+)
+
+func init() {
+	exportCommand.Flags().StringVarP(&exportOutput, "output", "o", "", "file to write to instead of stdout")
+	exportCommand.Flags().StringVar(&exportFormat, "format", "yaml", "format of the export (json or yaml)")
+
+	importCommand.Flags().StringVar(&importFormat, "format", "", "format of the file (json or yaml). Defaults to the file extension")
+}
+
+// storageClientFromConfig creates a storage client from the config file used by the server
+func storageClientFromConfig() (*storage.Client, error) {
+	var config server.Config
+	if err := viper.Unmarshal(&config); err != nil {
+		return nil, err
+	}
+	return storage.NewClient(config.StorageConfig)
+}
+
+// runExport will write all resources from storage to stdout or the output file
+func runExport(cmd *cobra.Command, _ []string) {
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("unable to initialize storage client:", err)
+		return
+	}
+
+	export, err := storageClient.Export(context.Background())
+	if err != nil {
+		cmd.PrintErrln("error exporting resources:", err)
+		return
+	}
+
+	out := cmd.OutOrStdout()
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			cmd.PrintErrln("error creating output file:", err)
+			return
+		}
+		defer f.Close()
+		out = f
+	}
+
+	err = storage.WriteExport(out, export, exportFormat)
+	if err != nil {
+		cmd.PrintErrln("error writing export:", err)
+	}
+}
+
+// runImport will read resources from the file and save them to storage
+func runImport(cmd *cobra.Command, args []string) {
+	f, err := os.Open(args[0])
+	if err != nil {
+		cmd.PrintErrln("error opening file:", err)
+		return
+	}
+	defer f.Close()
+
+	export, err := storage.ReadExport(f, fileFormat(args[0], importFormat))
+	if err != nil {
+		cmd.PrintErrln("error reading file:", err)
+		return
+	}
+
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("unable to initialize storage client:", err)
+		return
+	}
+
+	err = storageClient.Import(context.Background(), export)
+	if err != nil {
+		cmd.PrintErrln("error importing resources:", err)
+		return
+	}
+
+	cmd.Printf(
+		"imported %d Gardens, %d Zones, %d ZoneGroups, %d Plants, %d Reminders, %d WaterSchedules, and %d WeatherClients\n",
+		len(export.Gardens), len(export.Zones), len(export.ZoneGroups), len(export.Plants), len(export.Reminders),
+		len(export.WaterSchedules), len(export.WeatherClients),
+	)
+}
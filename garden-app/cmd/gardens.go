@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/calvinmclean/automated-garden/garden-app/client"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gardensCommand = &cobra.Command{
+		Use:   "gardens",
+		Short: "Manage Gardens with the API",
+	}
+
+	gardenTable = table[*pkg.Garden]{
+		columns: []string{"ID", "NAME", "TOPIC PREFIX", "MAX ZONES", "END DATE"},
+		row: func(g *pkg.Garden) []string {
+			return []string{g.GetID(), g.Name, g.TopicPrefix, valueOrEmpty(g.MaxZones), formatTime(g.EndDate)}
+		},
+	}
+)
+
+func init() {
+	addClientFlags(gardensCommand)
+
+	create := createCommand(gardenTable, map[string]string{
+		"name":         "name",
+		"topic-prefix": "topic_prefix",
+		"max-zones":    "max_zones",
+	}, (*client.Client).CreateGarden)
+	create.Flags().String("name", "", "name of the Garden")
+	create.Flags().String("topic-prefix", "", "prefix for the MQTT topics used by the Garden's controller")
+	create.Flags().Uint("max-zones", 0, "maximum number of Zones in the Garden")
+
+	gardensCommand.AddCommand(
+		listCommand(gardenTable, (*client.Client).ListGardens),
+		getCommand(gardenTable, (*client.Client).GetGarden),
+		create,
+		deleteCommand("Garden", (*client.Client).DeleteGarden),
+	)
+}
@@ -2,17 +2,24 @@ package cmd
 
 import (
 	"github.com/calvinmclean/automated-garden/garden-app/controller"
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	wifiSSID    string
-	writeFile   bool
-	mainConfig  bool
-	wifiConfig  bool
-	overwrite   bool
-	interactive bool
+	wifiSSID         string
+	board            string
+	firmwareDir      string
+	gardenAPIAddress string
+	gardenID         string
+	flash            bool
+	flashPort        string
+	writeFile        bool
+	mainConfig       bool
+	wifiConfig       bool
+	overwrite        bool
+	interactive      bool
 
 	generateConfigCommand = &cobra.Command{
 		Use:   "generate-config",
@@ -26,6 +33,26 @@ func init() {
 	generateConfigCommand.Flags().StringVar(&wifiSSID, "ssid", "", "SSID for your WiFi network")
 	viper.BindPFlag("controller.wifi.ssid", generateConfigCommand.Flags().Lookup("ssid"))
 
+	generateConfigCommand.Flags().StringVar(&board, "board", firmware.BoardESP32, "board profile used for pin names, moisture sensor calibration, and pin validation")
+	err := generateConfigCommand.RegisterFlagCompletionFunc("board", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return firmware.Boards, cobra.ShellCompDirectiveDefault
+	})
+	if err != nil {
+		panic(err)
+	}
+	viper.BindPFlag("controller.board", generateConfigCommand.Flags().Lookup("board"))
+
+	generateConfigCommand.Flags().StringVar(&firmwareDir, "firmware-dir", "", "garden-controller PlatformIO project to write configs into, under its 'include' directory")
+	viper.BindPFlag("controller.firmware_dir", generateConfigCommand.Flags().Lookup("firmware-dir"))
+
+	generateConfigCommand.Flags().StringVar(&gardenAPIAddress, "garden-api", "", "garden-app API address used to load the pins from the Garden's Zones, like http://localhost:8080")
+	viper.BindPFlag("controller.garden_api_address", generateConfigCommand.Flags().Lookup("garden-api"))
+	generateConfigCommand.Flags().StringVar(&gardenID, "garden-id", "", "ID of the Garden to load Zones from with --garden-api")
+	viper.BindPFlag("controller.garden_id", generateConfigCommand.Flags().Lookup("garden-id"))
+
+	generateConfigCommand.Flags().BoolVar(&flash, "flash", false, "build and upload the firmware using PlatformIO after writing configs to --firmware-dir")
+	generateConfigCommand.Flags().StringVar(&flashPort, "port", "", "serial port used to upload the firmware (detected by PlatformIO if empty)")
+
 	generateConfigCommand.Flags().BoolVarP(&writeFile, "write", "w", false, "write results to file instead of stdout")
 	generateConfigCommand.Flags().BoolVar(&wifiConfig, "wifi-config", true, "enable generating 'wifi_config.h'")
 	generateConfigCommand.Flags().BoolVar(&mainConfig, "main-config", true, "enable generating 'config.h'")
@@ -43,5 +70,27 @@ func GenerateConfig(cmd *cobra.Command, _ []string) {
 		return
 	}
 
-	controller.GenerateConfig(config, writeFile, wifiConfig, mainConfig, overwrite, interactive)
+	if config.GardenAPIAddress != "" || config.GardenID != "" {
+		if config.GardenAPIAddress == "" || config.GardenID == "" {
+			cmd.PrintErrln("--garden-api and --garden-id must be used together")
+			return
+		}
+		if err := config.LoadZonesFromAPI(cmd.Context()); err != nil {
+			cmd.PrintErrln("unable to load Zones from API:", err)
+			return
+		}
+	}
+
+	err := controller.GenerateConfig(config, writeFile, wifiConfig, mainConfig, overwrite, interactive)
+	if err != nil {
+		cmd.PrintErrln("error generating config:", err)
+		return
+	}
+
+	if flash {
+		err = controller.FlashFirmware(cmd.Context(), config, flashPort)
+		if err != nil {
+			cmd.PrintErrln("error flashing firmware:", err)
+		}
+	}
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	migrateFrom string
+	migrateTo   string
+
+	migrateStorageCommand = &cobra.Command{
+		Use:   "migrate-storage",
+		Short: "Copy all resources to a different storage backend",
+		Long:  `Reads every resource from the storage configured in one config file, validates it, and writes it to the storage configured in another. The destination should be empty and the server should be stopped`,
+		Run:   runMigrateStorage,
+	}
+)
+
+func init() {
+	migrateStorageCommand.Flags().StringVar(&migrateFrom, "from", "", "config file with the storage to read from")
+	migrateStorageCommand.Flags().StringVar(&migrateTo, "to", "", "config file with the storage to write to")
+	migrateStorageCommand.MarkFlagRequired("from")
+	migrateStorageCommand.MarkFlagRequired("to")
+}
+
+// storageClientFromFile creates a storage client from the storage section of a config file. Environment variables
+// are not used since they would apply to both files
+func storageClientFromFile(filename string) (*storage.Client, error) {
+	v := viper.New()
+	v.SetConfigFile(filename)
+	err := v.ReadInConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file: %w", err)
+	}
+
+	var config server.Config
+	err = v.Unmarshal(&config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config from file: %w", err)
+	}
+
+	return storage.NewClient(config.StorageConfig)
+}
+
+// runMigrateStorage will copy all resources from the "from" storage to the "to" storage and print a summary
+func runMigrateStorage(cmd *cobra.Command, _ []string) {
+	from, err := storageClientFromFile(migrateFrom)
+	if err != nil {
+		cmd.PrintErrf("error creating storage client from %q: %v\n", migrateFrom, err)
+		return
+	}
+
+	to, err := storageClientFromFile(migrateTo)
+	if err != nil {
+		cmd.PrintErrf("error creating storage client from %q: %v\n", migrateTo, err)
+		return
+	}
+
+	summary, err := storage.Migrate(context.Background(), from, to)
+	if err != nil {
+		cmd.PrintErrln("error migrating storage:", err)
+		return
+	}
+
+	cmd.Println("migrated resources:")
+	cmd.Printf("  Gardens: %d\n", summary.Gardens)
+	cmd.Printf("  Zones: %d\n", summary.Zones)
+	cmd.Printf("  ZoneGroups: %d\n", summary.ZoneGroups)
+	cmd.Printf("  Plants: %d\n", summary.Plants)
+	cmd.Printf("  Reminders: %d\n", summary.Reminders)
+	cmd.Printf("  WaterSchedules: %d\n", summary.WaterSchedules)
+	cmd.Printf("  WeatherClients: %d\n", summary.WeatherClients)
+	cmd.Printf("  NotificationClients: %d\n", summary.NotificationClients)
+	cmd.Printf("  APITokens: %d\n", summary.APITokens)
+	cmd.Printf("  Users: %d\n", summary.Users)
+	cmd.Printf("  Webhooks: %d\n", summary.Webhooks)
+	cmd.Printf("  WaterHistory events: %d\n", summary.WaterHistory)
+	cmd.Printf("  Audit entries: %d\n", summary.AuditEntries)
+	cmd.Printf("  Weather readings: %d\n", summary.WeatherReadings)
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultClientAddress = "http://localhost:8080"
+	clientTokenEnv       = "GARDEN_APP_TOKEN"
+
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var (
+	clientToken  string
+	clientOutput string
+)
+
+// addClientFlags adds the flags used by commands that manage resources with the API. The address uses the root
+// command's "--address" flag
+func addClientFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&clientToken, "token", "", "API token to use when authentication is enabled. Defaults to "+clientTokenEnv)
+	cmd.PersistentFlags().StringVarP(&clientOutput, "output", "o", outputTable, "output format (table or json)")
+}
+
+// newClient creates a client for the server at the "--address" flag
+func newClient(cmd *cobra.Command) *client.Client {
+	address, _ := cmd.Flags().GetString("address")
+	if address == "" {
+		address = defaultClientAddress
+	}
+
+	c := client.New(address)
+
+	token := clientToken
+	if token == "" {
+		token = os.Getenv(clientTokenEnv)
+	}
+	if token != "" {
+		c.SetToken(token)
+	}
+
+	return c
+}
+
+// table describes how resources are printed with the table output
+type table[T any] struct {
+	columns []string
+	row     func(T) []string
+}
+
+// print writes the resources as a table, or writes the data as JSON
+func (t table[T]) print(w io.Writer, data any, resources ...T) error {
+	switch clientOutput {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case outputTable:
+	default:
+		return fmt.Errorf("invalid output format %q", clientOutput)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.columns, "\t"))
+	for _, r := range resources {
+		fmt.Fprintln(tw, strings.Join(t.row(r), "\t"))
+	}
+	return tw.Flush()
+}
+
+// listCommand creates a command to print all resources
+func listCommand[T any](t table[T], list func(*client.Client, context.Context, bool) ([]T, error)) *cobra.Command {
+	var includeEndDated bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all resources",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			resources, err := list(newClient(cmd), context.Background(), includeEndDated)
+			if err != nil {
+				cmd.PrintErrln("error listing resources:", err)
+				return
+			}
+
+			err = t.print(cmd.OutOrStdout(), resources, resources...)
+			if err != nil {
+				cmd.PrintErrln("error printing resources:", err)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&includeEndDated, "include-end-dated", false, "include end-dated resources")
+	return cmd
+}
+
+// getCommand creates a command to print one resource
+func getCommand[T any](t table[T], get func(*client.Client, context.Context, string) (T, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Get a resource by ID",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			resource, err := get(newClient(cmd), context.Background(), args[0])
+			if err != nil {
+				cmd.PrintErrln("error getting resource:", err)
+				return
+			}
+
+			err = t.print(cmd.OutOrStdout(), resource, resource)
+			if err != nil {
+				cmd.PrintErrln("error printing resource:", err)
+			}
+		},
+	}
+}
+
+// createCommand creates a command to create a resource from a JSON or YAML file and the field flags. The fields map
+// flag names to JSON field names. Flags that are set replace the fields from the file
+func createCommand[T any](t table[T], fields map[string]string, create func(*client.Client, context.Context, T) (T, error)) *cobra.Command {
+	var filename string
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a resource",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			resource, err := resourceFromFlags[T](cmd, filename, fields)
+			if err != nil {
+				cmd.PrintErrln("error reading resource:", err)
+				return
+			}
+
+			created, err := create(newClient(cmd), context.Background(), resource)
+			if err != nil {
+				cmd.PrintErrln("error creating resource:", err)
+				return
+			}
+
+			err = t.print(cmd.OutOrStdout(), created, created)
+			if err != nil {
+				cmd.PrintErrln("error printing resource:", err)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&filename, "file", "f", "", "JSON or YAML file with the resource")
+	return cmd
+}
+
+// deleteCommand creates a command to delete a resource
+func deleteCommand(name string, remove func(*client.Client, context.Context, string) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete ID",
+		Short: "Delete a resource by ID. End-dateable resources are end-dated first and removed if they are deleted again",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := remove(newClient(cmd), context.Background(), args[0])
+			if err != nil {
+				cmd.PrintErrln("error deleting resource:", err)
+				return
+			}
+			cmd.Printf("deleted %s %s\n", name, args[0])
+		},
+	}
+}
+
+// resourceFromFlags reads the resource from the file, if it is set, and then sets the fields from flags that are
+// changed. YAML is converted to JSON so resources are decoded the same way as they are by the API
+func resourceFromFlags[T any](cmd *cobra.Command, filename string, fields map[string]string) (T, error) {
+	flags := cmd.Flags()
+	var result T
+	values := map[string]any{}
+
+	if filename != "" {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return result, fmt.Errorf("error reading file: %w", err)
+		}
+		if fileFormat(filename, "") == "json" {
+			err = json.Unmarshal(data, &values)
+		} else {
+			err = yaml.Unmarshal(data, &values)
+		}
+		if err != nil {
+			return result, fmt.Errorf("error decoding file: %w", err)
+		}
+	}
+
+	for flagName, field := range fields {
+		if !flags.Changed(flagName) {
+			continue
+		}
+
+		var value any
+		var err error
+		switch flags.Lookup(flagName).Value.Type() {
+		case "uint":
+			value, err = flags.GetUint(flagName)
+		case "stringSlice":
+			value, err = flags.GetStringSlice(flagName)
+		case "stringToString":
+			value, err = flags.GetStringToString(flagName)
+		default:
+			value, err = flags.GetString(flagName)
+		}
+		if err != nil {
+			return result, err
+		}
+		values[field] = value
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return result, fmt.Errorf("error encoding resource: %w", err)
+	}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return result, fmt.Errorf("error decoding resource: %w", err)
+	}
+	return result, nil
+}
+
+// formatTime formats a time as RFC3339 or returns an empty string if it is nil
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// valueOrEmpty formats a pointer or returns an empty string if it is nil
+func valueOrEmpty[T any](v *T) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := any(v).(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(*v)
+}
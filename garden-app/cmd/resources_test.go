@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceFromFlags(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "garden.yaml")
+	err := os.WriteFile(filename, []byte("name: From File\ntopic_prefix: file\nmax_zones: 1\n"), 0o600)
+	require.NoError(t, err)
+
+	fields := map[string]string{
+		"name":      "name",
+		"max-zones": "max_zones",
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		args     []string
+		expected *pkg.Garden
+	}{
+		{
+			"FlagsOnly",
+			"",
+			[]string{"--name", "Garden", "--max-zones", "3"},
+			&pkg.Garden{Name: "Garden", MaxZones: uintPointer(3)},
+		},
+		{
+			"FileOnly",
+			filename,
+			nil,
+			&pkg.Garden{Name: "From File", TopicPrefix: "file", MaxZones: uintPointer(1)},
+		},
+		{
+			"FlagsReplaceFile",
+			filename,
+			[]string{"--max-zones", "2"},
+			&pkg.Garden{Name: "From File", TopicPrefix: "file", MaxZones: uintPointer(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := createCommand(gardenTable, fields, nil)
+			cmd.Flags().String("name", "", "")
+			cmd.Flags().Uint("max-zones", 0, "")
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			garden, err := resourceFromFlags[*pkg.Garden](cmd, tt.filename, fields)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, garden)
+		})
+	}
+}
+
+func TestTablePrint(t *testing.T) {
+	gardens := []*pkg.Garden{
+		{Name: "Garden", TopicPrefix: "garden", MaxZones: uintPointer(2)},
+		{Name: "Other", TopicPrefix: "other"},
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		clientOutput = outputTable
+		var out bytes.Buffer
+		err := gardenTable.print(&out, gardens, gardens...)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, []string{"ID", "NAME", "TOPIC", "PREFIX", "MAX", "ZONES", "END", "DATE"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{gardens[0].GetID(), "Garden", "garden", "2"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{gardens[1].GetID(), "Other", "other"}, strings.Fields(lines[2]))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		clientOutput = outputJSON
+		defer func() { clientOutput = outputTable }()
+
+		var out bytes.Buffer
+		err := gardenTable.print(&out, gardens[1], gardens[1])
+		require.NoError(t, err)
+
+		var result pkg.Garden
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		assert.Equal(t, "Other", result.Name)
+		assert.Equal(t, "other", result.TopicPrefix)
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		clientOutput = "xml"
+		defer func() { clientOutput = outputTable }()
+
+		err := gardenTable.print(&bytes.Buffer{}, gardens)
+		assert.EqualError(t, err, `invalid output format "xml"`)
+	})
+}
+
+func uintPointer(n uint) *uint {
+	return &n
+}
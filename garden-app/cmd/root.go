@@ -19,7 +19,9 @@ func Execute() {
 	api := server.NewAPI()
 	command := api.Command()
 
-	command.AddCommand(controllerCommand)
+	command.AddCommand(controllerCommand, exportCommand, importCommand, applyCommand, migrateStorageCommand, doctorCommand)
+	command.AddCommand(gardensCommand, zonesCommand, waterSchedulesCommand, weatherClientsCommand)
+	command.AddCommand(usersCommand, tokensCommand)
 
 	viper.SetEnvPrefix("GARDEN_APP")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
@@ -52,6 +54,16 @@ func Execute() {
 			return fmt.Errorf("unable to read config from file: %w", err)
 		}
 
+		api.SetConfigLoader(func() (server.Config, error) {
+			var config server.Config
+			err := viper.ReadInConfig()
+			if err != nil {
+				return config, fmt.Errorf("unable to read config file: %w", err)
+			}
+			err = viper.Unmarshal(&config)
+			return config, err
+		})
+
 		err = api.Setup(config, true)
 		if err != nil {
 			return fmt.Errorf("error setting up API: %w", err)
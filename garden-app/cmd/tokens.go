@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tokenName   string
+	tokenScopes []string
+	tokenUserID string
+
+	tokensCommand = &cobra.Command{
+		Use:   "tokens",
+		Short: "Manage APITokens directly in storage",
+		Long:  `Manages APITokens using the storage from the config file instead of the API, so it can be used to set up or recover access when the API requires authentication`,
+	}
+
+	tokenTable = table[*pkg.APIToken]{
+		columns: []string{"ID", "NAME", "SCOPES", "USER ID"},
+		row: func(t *pkg.APIToken) []string {
+			return []string{t.GetID(), t.Name, joinScopes(t.Scopes), t.UserID}
+		},
+	}
+
+	// createdTokenTable includes the token since it is only available when it is created
+	createdTokenTable = table[*pkg.APIToken]{
+		columns: append(slices.Clone(tokenTable.columns), "TOKEN"),
+		row: func(t *pkg.APIToken) []string {
+			return append(tokenTable.row(t), t.Token())
+		},
+	}
+)
+
+func init() {
+	addStorageCommandFlags(tokensCommand)
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create an APIToken. The token is only shown once",
+		Args:  cobra.NoArgs,
+		Run:   runCreateToken,
+	}
+	create.Flags().StringVar(&tokenName, "name", "", "name of the APIToken")
+	create.Flags().StringSliceVar(&tokenScopes, "scope", nil, "scopes for the APIToken (read, write, actions, or admin). Can be repeated")
+	create.Flags().StringVar(&tokenUserID, "user", "", "ID of the User that requests using this APIToken are made as")
+
+	tokensCommand.AddCommand(
+		create,
+		&cobra.Command{
+			Use:   "remove ID",
+			Short: "Remove an APIToken so it can no longer be used",
+			Args:  cobra.ExactArgs(1),
+			Run:   runRemoveToken,
+		},
+		&cobra.Command{
+			Use:   "list",
+			Short: "List all APITokens. Tokens are not included",
+			Args:  cobra.NoArgs,
+			Run:   runListTokens,
+		},
+	)
+}
+
+// runCreateToken validates the APIToken like the API does when it is created and then stores its hash
+func runCreateToken(cmd *cobra.Command, _ []string) {
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("error creating storage client:", err)
+		return
+	}
+
+	ctx := context.Background()
+	token := &pkg.APIToken{Name: tokenName, UserID: tokenUserID}
+	for _, s := range tokenScopes {
+		token.Scopes = append(token.Scopes, pkg.APITokenScope(s))
+	}
+
+	err = token.Bind(&http.Request{Method: http.MethodPost})
+	if err != nil {
+		cmd.PrintErrln("invalid APIToken:", err)
+		return
+	}
+	if token.UserID != "" {
+		_, err = storageClient.Users.Get(ctx, token.UserID)
+		if err != nil {
+			cmd.PrintErrf("error getting User %q: %v\n", token.UserID, err)
+			return
+		}
+	}
+
+	err = token.GenerateToken()
+	if err != nil {
+		cmd.PrintErrln("error generating token:", err)
+		return
+	}
+
+	err = storageClient.APITokens.Set(ctx, token)
+	if err != nil {
+		cmd.PrintErrln("error storing APIToken:", err)
+		return
+	}
+
+	resp := &server.APITokenResponse{
+		ID:     token.ID,
+		Name:   token.Name,
+		Scopes: token.Scopes,
+		UserID: token.UserID,
+		Token:  token.Token(),
+	}
+	err = createdTokenTable.print(cmd.OutOrStdout(), resp, token)
+	if err != nil {
+		cmd.PrintErrln("error printing APIToken:", err)
+	}
+}
+
+func runRemoveToken(cmd *cobra.Command, args []string) {
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("error creating storage client:", err)
+		return
+	}
+
+	ctx := context.Background()
+	_, err = storageClient.APITokens.Get(ctx, args[0])
+	if err != nil {
+		cmd.PrintErrf("error getting APIToken %q: %v\n", args[0], err)
+		return
+	}
+
+	err = storageClient.APITokens.Delete(ctx, args[0])
+	if err != nil {
+		cmd.PrintErrln("error deleting APIToken:", err)
+		return
+	}
+
+	cmd.Printf("removed APIToken %s\n", args[0])
+}
+
+// runListTokens prints the APITokens without their hashes
+func runListTokens(cmd *cobra.Command, _ []string) {
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("error creating storage client:", err)
+		return
+	}
+
+	tokens, err := storageClient.APITokens.GetAll(context.Background(), nil)
+	if err != nil {
+		cmd.PrintErrln("error listing APITokens:", err)
+		return
+	}
+	slices.SortFunc(tokens, func(a, b *pkg.APIToken) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	resp := []*server.APITokenResponse{}
+	for _, t := range tokens {
+		resp = append(resp, &server.APITokenResponse{ID: t.ID, Name: t.Name, Scopes: t.Scopes, UserID: t.UserID})
+	}
+	err = tokenTable.print(cmd.OutOrStdout(), resp, tokens...)
+	if err != nil {
+		cmd.PrintErrln("error printing APITokens:", err)
+	}
+}
+
+// joinScopes formats scopes as a comma-separated list
+func joinScopes(scopes []pkg.APITokenScope) string {
+	result := make([]string, len(scopes))
+	for i, s := range scopes {
+		result[i] = string(s)
+	}
+	return strings.Join(result, ",")
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	userName  string
+	userEmail string
+	userRole  string
+
+	usersCommand = &cobra.Command{
+		Use:   "users",
+		Short: "Manage Users directly in storage",
+		Long:  `Manages Users using the storage from the config file instead of the API, so it can be used to set up or recover access when the API requires authentication`,
+	}
+
+	userTable = table[*pkg.User]{
+		columns: []string{"ID", "NAME", "EMAIL", "ROLE"},
+		row: func(u *pkg.User) []string {
+			return []string{u.GetID(), u.Name, u.Email, string(u.Role)}
+		},
+	}
+)
+
+func init() {
+	addStorageCommandFlags(usersCommand)
+
+	add := &cobra.Command{
+		Use:   "add",
+		Short: "Add a User",
+		Args:  cobra.NoArgs,
+		Run:   runAddUser,
+	}
+	add.Flags().StringVar(&userName, "name", "", "name of the User")
+	add.Flags().StringVar(&userEmail, "email", "", "email used to match the User when logging in with OIDC")
+	add.Flags().StringVar(&userRole, "role", string(pkg.UserRoleViewer), "role for resources that don't belong to a Garden (owner, operator, or viewer)")
+
+	usersCommand.AddCommand(
+		add,
+		&cobra.Command{
+			Use:   "remove ID",
+			Short: "Remove a User and its roles for all Gardens",
+			Args:  cobra.ExactArgs(1),
+			Run:   runRemoveUser,
+		},
+		&cobra.Command{
+			Use:   "list",
+			Short: "List all Users",
+			Args:  cobra.NoArgs,
+			Run:   runListUsers,
+		},
+	)
+}
+
+// addStorageCommandFlags adds the flags used by commands that manage resources directly in storage
+func addStorageCommandFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&clientOutput, "output", "o", outputTable, "output format (table or json)")
+}
+
+// runAddUser validates the User like the API does when it is created and then stores it
+func runAddUser(cmd *cobra.Command, _ []string) {
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("error creating storage client:", err)
+		return
+	}
+
+	user := &pkg.User{Name: userName, Email: userEmail, Role: pkg.UserRole(userRole)}
+	err = user.Bind(&http.Request{Method: http.MethodPost})
+	if err != nil {
+		cmd.PrintErrln("invalid User:", err)
+		return
+	}
+
+	err = storageClient.Users.Set(context.Background(), user)
+	if err != nil {
+		cmd.PrintErrln("error storing User:", err)
+		return
+	}
+
+	err = userTable.print(cmd.OutOrStdout(), user, user)
+	if err != nil {
+		cmd.PrintErrln("error printing User:", err)
+	}
+}
+
+// runRemoveUser deletes the User and removes it from all Gardens. The User's APITokens can no longer be used
+func runRemoveUser(cmd *cobra.Command, args []string) {
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("error creating storage client:", err)
+		return
+	}
+
+	ctx := context.Background()
+	_, err = storageClient.Users.Get(ctx, args[0])
+	if err != nil {
+		cmd.PrintErrf("error getting User %q: %v\n", args[0], err)
+		return
+	}
+
+	err = storageClient.Users.Delete(ctx, args[0])
+	if err != nil {
+		cmd.PrintErrln("error deleting User:", err)
+		return
+	}
+
+	err = storageClient.RemoveUserFromGardens(ctx, args[0])
+	if err != nil {
+		cmd.PrintErrln("error removing User from Gardens:", err)
+		return
+	}
+
+	cmd.Printf("removed User %s\n", args[0])
+}
+
+func runListUsers(cmd *cobra.Command, _ []string) {
+	storageClient, err := storageClientFromConfig()
+	if err != nil {
+		cmd.PrintErrln("error creating storage client:", err)
+		return
+	}
+
+	users, err := storageClient.Users.GetAll(context.Background(), nil)
+	if err != nil {
+		cmd.PrintErrln("error listing Users:", err)
+		return
+	}
+	slices.SortFunc(users, func(a, b *pkg.User) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	err = userTable.print(cmd.OutOrStdout(), users, users...)
+	if err != nil {
+		cmd.PrintErrln("error printing Users:", err)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runStorageCommand runs the command with the args and returns its output
+func runStorageCommand(t *testing.T, cmd *cobra.Command, args ...string) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	require.NoError(t, cmd.Execute())
+	return out.String()
+}
+
+func TestUsersAndTokensCommands(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "config.yaml")
+	config := "storage:\n  driver: hashmap\n  options:\n    filename: " + filepath.Join(dir, "gardens.yaml") + "\n"
+	require.NoError(t, os.WriteFile(filename, []byte(config), 0o600))
+	viper.SetConfigFile(filename)
+
+	out := runStorageCommand(t, usersCommand, "add", "--name", "Sam", "--email", "sam@example.com", "--role", "owner")
+	assert.Contains(t, out, "sam@example.com   owner")
+
+	storageClient, err := storageClientFromConfig()
+	require.NoError(t, err)
+	users, err := storageClient.Users.GetAll(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	user := users[0]
+
+	garden := &pkg.Garden{ID: babyapi.NewID(), Users: map[string]pkg.UserRole{user.GetID(): pkg.UserRoleOwner}}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		out := runStorageCommand(t, usersCommand, "add", "--name", "Alex", "--role", "admin")
+		assert.Contains(t, out, `invalid User: invalid role "admin"`)
+	})
+
+	t.Run("ListUsers", func(t *testing.T) {
+		out := runStorageCommand(t, usersCommand, "list")
+		assert.Contains(t, out, user.GetID())
+	})
+
+	t.Run("CreateToken", func(t *testing.T) {
+		out := runStorageCommand(t, tokensCommand, "create", "--name", "recovery", "--scope", "read", "--scope", "write", "--user", user.GetID())
+		assert.Regexp(t, `recovery\s+read,write\s+`+user.GetID()+`\s+[0-9a-f]{64}`, out)
+
+		out = runStorageCommand(t, tokensCommand, "list")
+		assert.Regexp(t, `recovery\s+read,write\s+`+user.GetID()+`\n`, out)
+	})
+
+	t.Run("CreateTokenInvalidScope", func(t *testing.T) {
+		out := runStorageCommand(t, tokensCommand, "create", "--name", "recovery", "--scope", "everything", "--user", "")
+		assert.Contains(t, out, `invalid APIToken: invalid scope "everything"`)
+	})
+
+	t.Run("RemoveToken", func(t *testing.T) {
+		// The storage file is read again to get the APIToken created by the command
+		storageClient, err := storageClientFromConfig()
+		require.NoError(t, err)
+		tokens, err := storageClient.APITokens.GetAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+
+		out := runStorageCommand(t, tokensCommand, "remove", tokens[0].GetID())
+		assert.Equal(t, "removed APIToken "+tokens[0].GetID()+"\n", out)
+	})
+
+	t.Run("RemoveUser", func(t *testing.T) {
+		out := runStorageCommand(t, usersCommand, "remove", user.GetID())
+		assert.Equal(t, "removed User "+user.GetID()+"\n", out)
+
+		storageClient, err := storageClientFromConfig()
+		require.NoError(t, err)
+		updated, err := storageClient.Gardens.Get(context.Background(), garden.GetID())
+		require.NoError(t, err)
+		assert.Empty(t, updated.Users)
+	})
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/calvinmclean/automated-garden/garden-app/client"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waterSchedulesCommand = &cobra.Command{
+		Use:   "water-schedules",
+		Short: "Manage WaterSchedules with the API",
+	}
+
+	waterScheduleTable = table[*pkg.WaterSchedule]{
+		columns: []string{"ID", "NAME", "INTERVAL", "DURATION", "START TIME", "PAUSED", "END DATE"},
+		row: func(ws *pkg.WaterSchedule) []string {
+			paused := ""
+			if ws.Paused {
+				paused = "true"
+			}
+			return []string{
+				ws.GetID(),
+				ws.Name,
+				valueOrEmpty(ws.Interval),
+				valueOrEmpty(ws.Duration),
+				valueOrEmpty(ws.StartTime),
+				paused,
+				formatTime(ws.EndDate),
+			}
+		},
+	}
+)
+
+func init() {
+	addClientFlags(waterSchedulesCommand)
+
+	create := createCommand(waterScheduleTable, map[string]string{
+		"name":        "name",
+		"description": "description",
+		"interval":    "interval",
+		"duration":    "duration",
+		"start-time":  "start_time",
+	}, (*client.Client).CreateWaterSchedule)
+	create.Flags().String("name", "", "name of the WaterSchedule")
+	create.Flags().String("description", "", "description of the WaterSchedule")
+	create.Flags().String("interval", "", `time between waterings, like "72h", or "cron:" followed by a cron expression`)
+	create.Flags().String("duration", "", `how long to water for, like "15m"`)
+	create.Flags().String("start-time", "", `time of day to water, like "22:00:00-07:00"`)
+
+	waterSchedulesCommand.AddCommand(
+		listCommand(waterScheduleTable, (*client.Client).ListWaterSchedules),
+		getCommand(waterScheduleTable, (*client.Client).GetWaterSchedule),
+		create,
+		deleteCommand("WaterSchedule", (*client.Client).DeleteWaterSchedule),
+	)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/calvinmclean/automated-garden/garden-app/client"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/spf13/cobra"
+)
+
+var (
+	weatherClientsCommand = &cobra.Command{
+		Use:   "weather-clients",
+		Short: "Manage WeatherClients with the API",
+	}
+
+	weatherClientTable = table[*weather.Config]{
+		columns: []string{"ID", "TYPE"},
+		row: func(wc *weather.Config) []string {
+			return []string{wc.GetID(), wc.Type}
+		},
+	}
+)
+
+func init() {
+	addClientFlags(weatherClientsCommand)
+
+	create := createCommand(weatherClientTable, map[string]string{
+		"type":   "type",
+		"option": "options",
+	}, (*client.Client).CreateWeatherClient)
+	create.Flags().String("type", "", "type of WeatherClient, like netatmo, openweathermap, openmeteo, ecowitt, or ambient_weather")
+	create.Flags().StringToString("option", nil, "option for the WeatherClient as key=value. Use a file for options that are not strings")
+
+	list := listCommand(weatherClientTable, func(c *client.Client, ctx context.Context, _ bool) ([]*weather.Config, error) {
+		return c.ListWeatherClients(ctx)
+	})
+	// WeatherClients are not end-dated
+	list.Flags().MarkHidden("include-end-dated")
+
+	weatherClientsCommand.AddCommand(
+		list,
+		getCommand(weatherClientTable, (*client.Client).GetWeatherClient),
+		create,
+		deleteCommand("WeatherClient", (*client.Client).DeleteWeatherClient),
+	)
+}
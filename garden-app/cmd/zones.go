@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/client"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	zoneGardenID      string
+	zoneWaterDuration time.Duration
+
+	zonesCommand = &cobra.Command{
+		Use:   "zones",
+		Short: "Manage a Garden's Zones with the API",
+	}
+
+	zoneWaterCommand = &cobra.Command{
+		Use:   "water ID",
+		Short: "Water a Zone",
+		Args:  cobra.ExactArgs(1),
+		Run:   runZoneWater,
+	}
+
+	zoneTable = table[*pkg.Zone]{
+		columns: []string{"ID", "NAME", "POSITION", "WATER SCHEDULES", "END DATE"},
+		row: func(z *pkg.Zone) []string {
+			waterScheduleIDs := []string{}
+			for _, id := range z.WaterScheduleIDs {
+				waterScheduleIDs = append(waterScheduleIDs, id.String())
+			}
+			return []string{z.GetID(), z.Name, valueOrEmpty(z.Position), strings.Join(waterScheduleIDs, ","), formatTime(z.EndDate)}
+		},
+	}
+)
+
+func init() {
+	addClientFlags(zonesCommand)
+	zonesCommand.PersistentFlags().StringVar(&zoneGardenID, "garden", "", "ID of the Zones' Garden")
+	zonesCommand.MarkPersistentFlagRequired("garden")
+
+	zoneWaterCommand.Flags().DurationVar(&zoneWaterDuration, "duration", 0, "duration to water the Zone for")
+	zoneWaterCommand.MarkFlagRequired("duration")
+
+	create := createCommand(zoneTable, map[string]string{
+		"name":            "name",
+		"position":        "position",
+		"water-schedules": "water_schedule_ids",
+	}, func(c *client.Client, ctx context.Context, z *pkg.Zone) (*pkg.Zone, error) {
+		return c.CreateZone(ctx, zoneGardenID, z)
+	})
+	create.Flags().String("name", "", "name of the Zone")
+	create.Flags().Uint("position", 0, "position of the Zone's valve on the controller")
+	create.Flags().StringSlice("water-schedules", nil, "IDs of WaterSchedules used by the Zone")
+
+	zonesCommand.AddCommand(
+		listCommand(zoneTable, func(c *client.Client, ctx context.Context, includeEndDated bool) ([]*pkg.Zone, error) {
+			return c.ListZones(ctx, zoneGardenID, includeEndDated)
+		}),
+		getCommand(zoneTable, func(c *client.Client, ctx context.Context, id string) (*pkg.Zone, error) {
+			return c.GetZone(ctx, zoneGardenID, id)
+		}),
+		create,
+		deleteCommand("Zone", func(c *client.Client, ctx context.Context, id string) error {
+			return c.DeleteZone(ctx, zoneGardenID, id)
+		}),
+		zoneWaterCommand,
+	)
+}
+
+// runZoneWater waters the Zone for the duration from the flag
+func runZoneWater(cmd *cobra.Command, args []string) {
+	err := newClient(cmd).WaterZone(context.Background(), zoneGardenID, args[0], zoneWaterDuration)
+	if err != nil {
+		cmd.PrintErrln("error watering Zone:", err)
+		return
+	}
+	cmd.Printf("watering Zone %s for %s\n", args[0], zoneWaterDuration)
+}
@@ -15,6 +15,8 @@ type assertionData struct {
 	stopActions    int
 	stopAllActions int
 	lightActions   []action.LightAction
+
+	recirculationActions []action.RecirculationAction
 }
 
 // AssertWaterActions is used to check that all expected WaterMessages were received, then reset recorded info
@@ -56,3 +58,13 @@ func (c *Controller) AssertLightActions(t *testing.T, expected ...action.LightAc
 	c.assertionData.lightActions = []action.LightAction{}
 	c.assertionData.Unlock()
 }
+
+// AssertRecirculationActions is used to check that all expected RecirculationActions were received, then reset recorded info
+func (c *Controller) AssertRecirculationActions(t *testing.T, expected ...action.RecirculationAction) {
+	t.Helper()
+
+	c.assertionData.Lock()
+	assert.Equal(t, expected, c.assertionData.recirculationActions)
+	c.assertionData.recirculationActions = nil
+	c.assertionData.Unlock()
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"math/rand"
@@ -11,19 +12,26 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
 	"github.com/calvinmclean/automated-garden/garden-app/server"
 	paho "github.com/eclipse/paho.mqtt.golang"
 	"github.com/go-co-op/gocron"
 	"github.com/rivo/tview"
 )
 
+// defaultFlowMeterPulsesPerLiter is used to emulate flow meter pulses when FlowMeterPulsesPerLiter is not configured
+const defaultFlowMeterPulsesPerLiter = firmware.DefaultFlowMeterPulsesPerLiter
+
 // Config holds all the options and sub-configs for the mock controller
 type Config struct {
 	MQTTConfig   mqtt.Config `mapstructure:"mqtt"`
 	NestedConfig `mapstructure:"controller"`
 	LogConfig    server.LogConfig `mapstructure:"log"`
+	Tracing      tracing.Config   `mapstructure:"tracing"`
 }
 
 // NestedConfig is an unfortunate struct that I had to create to have this nested under the 'controller' key
@@ -37,6 +45,14 @@ type NestedConfig struct {
 	TemperatureValue                float64 `mapstructure:"temperature_value"`
 	HumidityValue                   float64 `mapstructure:"humidity_value"`
 	TemperatureHumidityDisableNoise bool    `mapstructure:"temperature_humidity_disable_noise"`
+	FlowRate                        float64 `mapstructure:"flow_rate"`
+	// SimulateWatering waits for each WaterAction's duration, one Zone at a time, before publishing the water event
+	// so StopActions and StopAllActions can interrupt it
+	SimulateWatering bool `mapstructure:"simulate_watering"`
+	// HTTPAddress enables the HTTP API used to inspect and change the virtual hardware, like ":8081"
+	HTTPAddress string `mapstructure:"http_address"`
+	// Controllers runs multiple virtual controllers in one process instead of the single controller
+	Controllers []VirtualControllerConfig `mapstructure:"controllers"`
 
 	// Configs used for both
 	TopicPrefix                 string        `mapstructure:"topic_prefix" survey:"topic_prefix"`
@@ -46,13 +62,21 @@ type NestedConfig struct {
 	HealthInterval              time.Duration `mapstructure:"health_interval" survey:"health_interval"`
 	PublishTemperatureHumidity  bool          `mapstructure:"publish_temperature_humidity" survey:"publish_temperature_humidity"`
 	TemperatureHumidityInterval time.Duration `mapstructure:"temperature_humidity_interval" survey:"temperature_humidity_interval"`
+	FlowMeterPulsesPerLiter     float64       `mapstructure:"flow_meter_pulses_per_liter" survey:"flow_meter_pulses_per_liter"`
 
 	// Configs only used for generate-config
-	WifiConfig             `mapstructure:"wifi" survey:"wifi"`
+	WifiConfig  `mapstructure:"wifi" survey:"wifi"`
+	Board       string `mapstructure:"board" survey:"board"`
+	FirmwareDir string `mapstructure:"firmware_dir"`
+	// GardenAPIAddress and GardenID load the Zones' pins from the garden-app API instead of the configured Zones
+	GardenAPIAddress       string        `mapstructure:"garden_api_address"`
+	GardenID               string        `mapstructure:"garden_id"`
 	Zones                  []ZoneConfig  `mapstructure:"zones" survey:"zones"`
 	DefaultWaterTime       time.Duration `mapstructure:"default_water_time" survey:"default_water_time"`
 	EnableButtons          bool          `mapstructure:"enable_buttons" survey:"enable_buttons"`
 	EnableMoistureSensor   bool          `mapstructure:"enable_moisture_sensor" survey:"enable_moisture_sensor"`
+	EnableFlowMeter        bool          `mapstructure:"enable_flow_meter" survey:"enable_flow_meter"`
+	EnableDosing           bool          `mapstructure:"enable_dosing" survey:"enable_dosing"`
 	LightPin               string        `mapstructure:"light_pin" survey:"light_pin"`
 	StopButtonPin          string        `mapstructure:"stop_water_button" survey:"stop_water_button"`
 	DisableWatering        bool          `mapstructure:"disable_watering" survey:"disable_watering"`
@@ -72,7 +96,21 @@ type Controller struct {
 	pubLogger *slog.Logger
 	subLogger *slog.Logger
 
-	quit chan os.Signal
+	quit            chan os.Signal
+	shutdownTracing func(context.Context) error
+
+	// commandIDs keeps the CommandIDs of received WaterMessages so retried commands are not watered again
+	commandIDs    map[string]struct{}
+	commandIDsMtx sync.Mutex
+
+	// stateMtx protects the virtual hardware state that can be changed by the HTTP API
+	stateMtx        sync.Mutex
+	lightState      pkg.LightState
+	disconnected    bool
+	firmwareVersion string
+
+	waterQueue chan queuedWater
+	stopWater  chan struct{}
 
 	assertionData
 }
@@ -83,10 +121,17 @@ func NewController(cfg Config) (*Controller, error) {
 		Config: cfg,
 		quit:   make(chan os.Signal, 1),
 	}
+	controller.initWaterQueue()
 
-	controller.logger = cfg.LogConfig.NewLogger()
-	controller.subLogger = cfg.LogConfig.NewLogger()
-	controller.pubLogger = cfg.LogConfig.NewLogger()
+	controller.logger = cfg.LogConfig.NewLogger().With("topic_prefix", cfg.TopicPrefix)
+	controller.subLogger = cfg.LogConfig.NewLogger().With("topic_prefix", cfg.TopicPrefix)
+	controller.pubLogger = cfg.LogConfig.NewLogger().With("topic_prefix", cfg.TopicPrefix)
+
+	var err error
+	controller.shutdownTracing, err = tracing.Setup(cfg.Tracing, "garden-controller")
+	if err != nil {
+		return nil, fmt.Errorf("unable to setup tracing: %w", err)
+	}
 
 	if controller.EnableUI {
 		controller.app = controller.setupUI()
@@ -133,16 +178,15 @@ func (c *Controller) Start() {
 	c.logger.Debug("initializing scheduler")
 	scheduler := gocron.NewScheduler(time.Local)
 	if c.MoistureInterval != 0 {
-		for p := 0; p < c.NumZones; p++ {
-			c.logger.With(
-				"interval", c.MoistureInterval.String(),
-				"strategy", c.MoistureStrategy,
-			).Debug("create scheduled job to publish moisture data")
-			_, err := scheduler.Every(c.MoistureInterval).Do(c.publishMoistureData, p)
-			if err != nil {
-				c.logger.Error("error scheduling moisture publishing", "error", err)
-				return
-			}
+		c.logger.With(
+			"interval", c.MoistureInterval.String(),
+			"strategy", c.MoistureStrategy,
+		).Debug("create scheduled job to publish moisture data")
+		// A single Job publishes for all Zones since NumZones can be changed by a config command
+		_, err := scheduler.Every(c.MoistureInterval).Do(c.publishAllMoistureData)
+		if err != nil {
+			c.logger.Error("error scheduling moisture publishing", "error", err)
+			return
 		}
 	}
 	if c.PublishHealth {
@@ -163,6 +207,16 @@ func (c *Controller) Start() {
 	}
 	scheduler.StartAsync()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	if c.SimulateWatering {
+		go c.runWaterQueue(ctx)
+	}
+
+	shutdownHTTP := func() {}
+	if c.HTTPAddress != "" {
+		shutdownHTTP = startHTTPServer(c.HTTPAddress, c.HTTPHandler(), c.logger)
+	}
+
 	// Shutdown gracefully on Ctrl+C
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
@@ -174,10 +228,17 @@ func (c *Controller) Start() {
 		c.logger.Info("gracefully shutting down controller")
 
 		scheduler.Stop()
+		cancel()
+		shutdownHTTP()
 
 		// Disconnect mqttClient
 		c.logger.Info("disconnecting MQTT Client")
 		c.mqttClient.Disconnect(1000)
+
+		err := c.shutdownTracing(context.Background())
+		if err != nil {
+			c.logger.Error("error flushing traces", "error", err)
+		}
 		wg.Done()
 	}()
 
@@ -237,6 +298,17 @@ func (c *Controller) setupUI() *tview.Application {
 	return app.SetRoot(grid, true)
 }
 
+// publishAllMoistureData publishes moisture data for each of the Controller's current Zones
+func (c *Controller) publishAllMoistureData() {
+	c.stateMtx.Lock()
+	numZones := c.NumZones
+	c.stateMtx.Unlock()
+
+	for p := 0; p < numZones; p++ {
+		c.publishMoistureData(p)
+	}
+}
+
 // publishMoistureData publishes an InfluxDB line containing moisture data for a Zone
 func (c *Controller) publishMoistureData(zone int) {
 	moisture := c.createMoistureData()
@@ -267,6 +339,28 @@ func (c *Controller) publishHealthData() {
 	}
 }
 
+// setLightState changes the virtual light's state, toggling it for pkg.LightStateToggle, and publishes the new state
+// the same way as the garden-controller
+func (c *Controller) setLightState(state pkg.LightState) {
+	c.stateMtx.Lock()
+	if state == pkg.LightStateToggle {
+		state = pkg.LightStateOn
+		if c.lightState == pkg.LightStateOn {
+			state = pkg.LightStateOff
+		}
+	}
+	c.lightState = state
+	c.stateMtx.Unlock()
+
+	topic := fmt.Sprintf("%s/data/light", c.TopicPrefix)
+	lightLogger := c.pubLogger.With("topic", topic, "state", state.String())
+	lightLogger.Info("publishing light data")
+	err := c.mqttClient.Publish(topic, []byte(fmt.Sprintf("light,garden=\"%s\" state=%d", c.TopicPrefix, state)))
+	if err != nil {
+		lightLogger.Error("unable to publish light data", "error", err)
+	}
+}
+
 func (c *Controller) publishTemperatureHumidityData() {
 	temperatureTopic := fmt.Sprintf("%s/data/temperature", c.TopicPrefix)
 	humidityTopic := fmt.Sprintf("%s/data/humidity", c.TopicPrefix)
@@ -303,8 +397,27 @@ func addNoise(baseValue float64, percentRange float64) float64 {
 	return baseValue + diff
 }
 
+// emulateFlowMeter counts the pulses a flow meter would send while watering at the configured FlowRate (liters per
+// minute) and converts them to milliliters the same way the garden-controller does. It returns 0 when flow meter
+// emulation is disabled
+func (c *Controller) emulateFlowMeter(duration time.Duration) int64 {
+	if c.FlowRate <= 0 {
+		return 0
+	}
+	pulsesPerLiter := c.FlowMeterPulsesPerLiter
+	if pulsesPerLiter <= 0 {
+		pulsesPerLiter = defaultFlowMeterPulsesPerLiter
+	}
+
+	pulses := int64(addNoise(c.FlowRate, c.FlowRate*0.05) * duration.Minutes() * pulsesPerLiter)
+	return int64(float64(pulses) * 1000 / pulsesPerLiter)
+}
+
 // createMoistureData uses the MoistureStrategy config to create a moisture data point
 func (c *Controller) createMoistureData() int {
+	c.stateMtx.Lock()
+	defer c.stateMtx.Unlock()
+
 	switch c.MoistureStrategy {
 	case "random":
 		// nolint:gosec
@@ -341,16 +454,66 @@ func (c *Controller) publishWaterEvent(waterMsg action.WaterMessage, cmdTopic st
 		"zone_position", waterMsg.Position,
 		"duration", waterMsg.Duration,
 	)
+	if waterMsg.Fertilizer > 0 {
+		waterEventLogger = waterEventLogger.With("fertilizer", waterMsg.Fertilizer)
+	}
+	msg := fmt.Sprintf("water,zone=%d millis=%d", waterMsg.Position, waterMsg.Duration)
+	if ml := c.emulateFlowMeter(time.Duration(waterMsg.Duration) * time.Millisecond); ml > 0 {
+		waterEventLogger = waterEventLogger.With("milliliters", ml)
+		msg += fmt.Sprintf(",ml=%d", ml)
+	}
+
 	waterEventLogger.Info("publishing watering event for Zone")
-	err := c.mqttClient.Publish(
-		dataTopic,
-		[]byte(fmt.Sprintf("water,zone=%d millis=%d", waterMsg.Position, waterMsg.Duration)),
-	)
+	err := c.mqttClient.Publish(dataTopic, []byte(msg))
 	if err != nil {
 		waterEventLogger.Error("unable to publish watering event", "error", err)
 	}
 }
 
+// publishWaterAck acknowledges a water command by publishing its CommandID on "{{.TopicPrefix}}/ack/water"
+func (c *Controller) publishWaterAck(commandID, cmdTopic string) {
+	ackTopic := strings.Replace(cmdTopic, "/command/", "/ack/", 1)
+	c.pubLogger.Info("acknowledging water command", "topic", ackTopic, "command_id", commandID)
+	err := c.mqttClient.Publish(ackTopic, []byte(commandID))
+	if err != nil {
+		c.pubLogger.Error("unable to publish water command acknowledgment", "command_id", commandID, "error", err)
+	}
+}
+
+// isDuplicateCommand returns true if the command was already received. Otherwise, it is remembered
+func (c *Controller) isDuplicateCommand(commandID string) bool {
+	c.commandIDsMtx.Lock()
+	defer c.commandIDsMtx.Unlock()
+
+	if c.commandIDs == nil {
+		c.commandIDs = map[string]struct{}{}
+	}
+	if _, ok := c.commandIDs[commandID]; ok {
+		return true
+	}
+	c.commandIDs[commandID] = struct{}{}
+	return false
+}
+
+// applyConfig changes the Controller's Zones and DefaultWaterTime at runtime like the firmware does when it
+// receives a config command. DefaultWaterTime is only changed when it is set so the configured value is kept
+func (c *Controller) applyConfig(configMsg action.ConfigMessage) {
+	c.stateMtx.Lock()
+	defer c.stateMtx.Unlock()
+
+	c.NumZones = int(configMsg.NumZones)
+	if configMsg.DefaultWaterTime > 0 {
+		c.DefaultWaterTime = time.Duration(configMsg.DefaultWaterTime) * time.Millisecond
+	}
+}
+
+// defaultWaterTime returns the DefaultWaterTime, which can be changed by a config command
+func (c *Controller) defaultWaterTime() time.Duration {
+	c.stateMtx.Lock()
+	defer c.stateMtx.Unlock()
+	return c.DefaultWaterTime
+}
+
 // getHandlerForTopic provides a different MessageHandler function for each of the expected
 // topics to be able to handle them in different ways
 func (c *Controller) getHandlerForTopic(topic string) paho.MessageHandler {
@@ -363,6 +526,12 @@ func (c *Controller) getHandlerForTopic(topic string) paho.MessageHandler {
 		return c.stopAllHandler(topic)
 	case "light":
 		return c.lightHandler(topic)
+	case "recirculation":
+		return c.recirculationHandler(topic)
+	case "config":
+		return c.configHandler(topic)
+	case "firmware_update":
+		return c.firmwareUpdateHandler(topic)
 	default:
 		return paho.MessageHandler(func(_ paho.Client, msg paho.Message) {
 			c.subLogger.With(
@@ -381,6 +550,9 @@ func (c *Controller) topics() ([]string, error) {
 		c.MQTTConfig.StopTopic,
 		c.MQTTConfig.StopAllTopic,
 		c.MQTTConfig.LightTopic,
+		c.MQTTConfig.RecirculationTopic,
+		c.MQTTConfig.ConfigTopic,
+		c.MQTTConfig.FirmwareUpdateTopic,
 	}
 	for _, templateFunc := range templateFuncs {
 		topic, err := templateFunc(c.TopicPrefix)
@@ -2,7 +2,9 @@ package controller
 
 import (
 	"testing"
+	"time"
 
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,3 +17,58 @@ func TestAddNoise(t *testing.T) {
 		assert.GreaterOrEqual(t, r, base-float64(percentRange))
 	}
 }
+
+func TestEmulateFlowMeter(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		c := &Controller{}
+		assert.Equal(t, int64(0), c.emulateFlowMeter(time.Minute))
+	})
+
+	t.Run("WithinNoise", func(t *testing.T) {
+		c := &Controller{Config: Config{NestedConfig: NestedConfig{FlowRate: 2, FlowMeterPulsesPerLiter: 450}}}
+		for i := 0; i < 100; i++ {
+			ml := c.emulateFlowMeter(time.Minute)
+			assert.GreaterOrEqual(t, ml, int64(1890))
+			assert.LessOrEqual(t, ml, int64(2100))
+		}
+	})
+}
+
+func TestIsDuplicateCommand(t *testing.T) {
+	c := &Controller{}
+	assert.False(t, c.isDuplicateCommand("command1"))
+	assert.True(t, c.isDuplicateCommand("command1"))
+	assert.False(t, c.isDuplicateCommand("command2"))
+}
+
+func TestApplyConfig(t *testing.T) {
+	tests := []struct {
+		name                     string
+		configMsg                action.ConfigMessage
+		expectedNumZones         int
+		expectedDefaultWaterTime time.Duration
+	}{
+		{
+			"ChangeNumZones",
+			action.ConfigMessage{NumZones: 3},
+			3,
+			5 * time.Second,
+		},
+		{
+			"ChangeDefaultWaterTime",
+			action.ConfigMessage{NumZones: 1, DefaultWaterTime: 15000},
+			1,
+			15 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Controller{Config: Config{NestedConfig: NestedConfig{NumZones: 1, DefaultWaterTime: 5 * time.Second}}}
+			c.applyConfig(tt.configMsg)
+
+			assert.Equal(t, tt.expectedNumZones, c.NumZones)
+			assert.Equal(t, tt.expectedDefaultWaterTime, c.defaultWaterTime())
+		})
+	}
+}
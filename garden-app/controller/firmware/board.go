@@ -0,0 +1,190 @@
+package firmware
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// BoardESP32 is the default board used by the garden-controller
+	BoardESP32 = "esp32"
+	// BoardESP8266 is a smaller board with fewer pins and a single analog input
+	BoardESP8266 = "esp8266"
+)
+
+// Boards is the list of supported board names
+var Boards = []string{BoardESP32, BoardESP8266}
+
+// pinCapabilities describes what a pin can be used for
+type pinCapabilities struct {
+	output    bool
+	interrupt bool
+	analog    bool
+}
+
+// BoardProfile describes the pins, ADC range, and features of a board that runs the garden-controller
+type BoardProfile struct {
+	Name string
+	// NoPin is the pin identifier used in the generated config for pins that are not used
+	NoPin string
+	// MoistureSensorAirValue and MoistureSensorWaterValue calibrate moisture sensor readings for the ADC range
+	MoistureSensorAirValue   int
+	MoistureSensorWaterValue int
+	// PlatformIOEnv is the PlatformIO environment used to build and upload the firmware
+	PlatformIOEnv string
+
+	// maxMoistureSensors limits the number of Zones with a moisture sensor since each needs an analog input
+	maxMoistureSensors int
+	pins               map[string]pinCapabilities
+}
+
+var (
+	esp32Profile = BoardProfile{
+		Name:                     BoardESP32,
+		NoPin:                    "GPIO_NUM_MAX",
+		MoistureSensorAirValue:   3415,
+		MoistureSensorWaterValue: 1362,
+		PlatformIOEnv:            "esp32dev",
+		maxMoistureSensors:       8,
+		pins:                     esp32Pins(),
+	}
+	esp8266Profile = BoardProfile{
+		Name:  BoardESP8266,
+		NoPin: "-1",
+		// The ESP8266 has a 10-bit ADC, so the ESP32's 12-bit calibration values are scaled down
+		MoistureSensorAirValue:   853,
+		MoistureSensorWaterValue: 340,
+		PlatformIOEnv:            "nodemcuv2",
+		maxMoistureSensors:       1,
+		pins: map[string]pinCapabilities{
+			"D0": {output: true},
+			"D1": {output: true, interrupt: true},
+			"D2": {output: true, interrupt: true},
+			"D3": {output: true, interrupt: true},
+			"D4": {output: true, interrupt: true},
+			"D5": {output: true, interrupt: true},
+			"D6": {output: true, interrupt: true},
+			"D7": {output: true, interrupt: true},
+			"D8": {output: true, interrupt: true},
+			"A0": {analog: true},
+		},
+	}
+)
+
+// esp32Pins creates the usable ESP32 pins. Pins 6-11 are used for flash, pins 34-39 are input-only, and only ADC1
+// pins (32-39) can read analog values while WiFi is enabled
+func esp32Pins() map[string]pinCapabilities {
+	pins := map[string]pinCapabilities{}
+	for _, n := range []int{0, 1, 2, 3, 4, 5, 12, 13, 14, 15, 16, 17, 18, 19, 21, 22, 23, 25, 26, 27, 32, 33, 34, 35, 36, 37, 38, 39} {
+		pins[fmt.Sprintf("GPIO_NUM_%d", n)] = pinCapabilities{
+			output:    n < 34,
+			interrupt: true,
+			analog:    n >= 32,
+		}
+	}
+	return pins
+}
+
+// GetBoardProfile returns the BoardProfile for the board name. An empty name uses the ESP32
+func GetBoardProfile(board string) (BoardProfile, error) {
+	switch strings.ToLower(board) {
+	case "", BoardESP32:
+		return esp32Profile, nil
+	case BoardESP8266:
+		return esp8266Profile, nil
+	default:
+		return BoardProfile{}, fmt.Errorf("invalid board %q, must be one of %v", board, Boards)
+	}
+}
+
+// pinRequirement is a configured pin and what it needs to be capable of
+type pinRequirement struct {
+	name      string
+	pin       string
+	output    bool
+	interrupt bool
+	analog    bool
+}
+
+// ValidatePins checks that each configured pin exists on the board and supports how it is used
+func (b BoardProfile) ValidatePins(config Config) error {
+	requirements := []pinRequirement{
+		{name: "light_pin", pin: config.LightPin, output: true},
+		{name: "temperature_humidity_pin", pin: config.TemperatureHumidityPin, output: true},
+	}
+	if config.EnableButtons {
+		requirements = append(requirements, pinRequirement{name: "stop_water_button", pin: config.StopButtonPin, interrupt: true})
+	}
+
+	moistureSensors := 0
+	for i, z := range config.Zones {
+		requirements = append(requirements,
+			pinRequirement{name: fmt.Sprintf("zones[%d].pump_pin", i), pin: z.PumpPin, output: true},
+			pinRequirement{name: fmt.Sprintf("zones[%d].valve_pin", i), pin: z.ValvePin, output: true},
+			pinRequirement{name: fmt.Sprintf("zones[%d].button_pin", i), pin: z.ButtonPin, interrupt: true},
+			pinRequirement{name: fmt.Sprintf("zones[%d].moisture_sensor_pin", i), pin: z.MoistureSensorPin, analog: true},
+			pinRequirement{name: fmt.Sprintf("zones[%d].flow_meter_pin", i), pin: z.FlowMeterPin, interrupt: true},
+			pinRequirement{name: fmt.Sprintf("zones[%d].dosing_pin", i), pin: z.DosingPin, output: true},
+		)
+		if b.isPinSet(z.MoistureSensorPin) {
+			moistureSensors++
+		}
+	}
+
+	var errs []error
+	for _, r := range requirements {
+		if err := b.validatePin(r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if config.EnableMoistureSensor && moistureSensors > b.maxMoistureSensors {
+		errs = append(errs, fmt.Errorf("%s supports at most %d moisture sensors, but %d are configured", b.Name, b.maxMoistureSensors, moistureSensors))
+	}
+
+	return errors.Join(errs...)
+}
+
+// isPinSet returns false for empty pins and the board's NoPin
+func (b BoardProfile) isPinSet(pin string) bool {
+	return pin != "" && pin != b.NoPin
+}
+
+func (b BoardProfile) validatePin(r pinRequirement) error {
+	if !b.isPinSet(r.pin) {
+		return nil
+	}
+
+	capabilities, ok := b.pins[r.pin]
+	if !ok {
+		return fmt.Errorf("%s: pin %q does not exist on %s, must be one of %v", r.name, r.pin, b.Name, b.pinNames())
+	}
+
+	switch {
+	case r.output && !capabilities.output:
+		return fmt.Errorf("%s: pin %q on %s cannot be used as an output", r.name, r.pin, b.Name)
+	case r.interrupt && !capabilities.interrupt:
+		return fmt.Errorf("%s: pin %q on %s does not support interrupts", r.name, r.pin, b.Name)
+	case r.analog && !capabilities.analog:
+		return fmt.Errorf("%s: pin %q on %s cannot read analog values", r.name, r.pin, b.Name)
+	}
+
+	return nil
+}
+
+// pinNames returns the sorted names of the board's pins
+func (b BoardProfile) pinNames() []string {
+	names := make([]string, 0, len(b.pins))
+	for name := range b.pins {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(a, b)
+	})
+	return names
+}
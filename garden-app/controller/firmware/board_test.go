@@ -0,0 +1,135 @@
+package firmware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBoardProfile(t *testing.T) {
+	board, err := GetBoardProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, BoardESP32, board.Name)
+
+	board, err = GetBoardProfile("ESP8266")
+	require.NoError(t, err)
+	assert.Equal(t, BoardESP8266, board.Name)
+
+	_, err = GetBoardProfile("arduino")
+	assert.EqualError(t, err, `invalid board "arduino", must be one of [esp32 esp8266]`)
+}
+
+func TestValidatePins(t *testing.T) {
+	tests := []struct {
+		name        string
+		board       string
+		config      Config
+		expectedErr string
+	}{
+		{
+			"ESP32Valid",
+			BoardESP32,
+			Config{
+				Zones: []ZoneConfig{{
+					PumpPin:           "GPIO_NUM_18",
+					ValvePin:          "GPIO_NUM_16",
+					ButtonPin:         "GPIO_NUM_39",
+					MoistureSensorPin: "GPIO_NUM_36",
+					FlowMeterPin:      "GPIO_NUM_MAX",
+				}},
+				LightPin:             "GPIO_NUM_32",
+				EnableMoistureSensor: true,
+			},
+			"",
+		},
+		{
+			"ESP32PinDoesNotExist",
+			BoardESP32,
+			Config{Zones: []ZoneConfig{{PumpPin: "GPIO_NUM_6", ValvePin: "GPIO_NUM_16"}}},
+			`zones[0].pump_pin: pin "GPIO_NUM_6" does not exist on esp32`,
+		},
+		{
+			"ESP32InputOnlyPin",
+			BoardESP32,
+			Config{Zones: []ZoneConfig{{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_34"}}},
+			`zones[0].valve_pin: pin "GPIO_NUM_34" on esp32 cannot be used as an output`,
+		},
+		{
+			"ESP32MoistureSensorNotADC1",
+			BoardESP32,
+			Config{Zones: []ZoneConfig{{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16", MoistureSensorPin: "GPIO_NUM_25"}}},
+			`zones[0].moisture_sensor_pin: pin "GPIO_NUM_25" on esp32 cannot read analog values`,
+		},
+		{
+			"ESP8266Valid",
+			BoardESP8266,
+			Config{
+				Zones: []ZoneConfig{
+					{PumpPin: "D1", ValvePin: "D2", MoistureSensorPin: "A0"},
+					{PumpPin: "D1", ValvePin: "D5", ButtonPin: "-1"},
+				},
+				EnableMoistureSensor: true,
+			},
+			"",
+		},
+		{
+			"ESP8266GPIONames",
+			BoardESP8266,
+			Config{Zones: []ZoneConfig{{PumpPin: "GPIO_NUM_18", ValvePin: "D2"}}},
+			`zones[0].pump_pin: pin "GPIO_NUM_18" does not exist on esp8266, must be one of [A0 D0 D1 D2 D3 D4 D5 D6 D7 D8]`,
+		},
+		{
+			"ESP8266NoInterrupt",
+			BoardESP8266,
+			Config{EnableButtons: true, StopButtonPin: "D0"},
+			`stop_water_button: pin "D0" on esp8266 does not support interrupts`,
+		},
+		{
+			"ESP8266TooManyMoistureSensors",
+			BoardESP8266,
+			Config{
+				Zones: []ZoneConfig{
+					{PumpPin: "D1", ValvePin: "D2", MoistureSensorPin: "A0"},
+					{PumpPin: "D1", ValvePin: "D5", MoistureSensorPin: "A0"},
+				},
+				EnableMoistureSensor: true,
+			},
+			"esp8266 supports at most 1 moisture sensors, but 2 are configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := GetBoardProfile(tt.board)
+			require.NoError(t, err)
+
+			err = board.ValidatePins(tt.config)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestRenderESP8266(t *testing.T) {
+	config, err := Render(Config{
+		Board:                BoardESP8266,
+		TopicPrefix:          "garden",
+		Zones:                []ZoneConfig{{PumpPin: "D1", ValvePin: "D2", MoistureSensorPin: "A0"}},
+		EnableMoistureSensor: true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, config, "#define ZONES { { D1, D2, -1, A0 } }")
+	assert.Contains(t, config, "#define MOISTURE_SENSOR_AIR_VALUE 853")
+	assert.Contains(t, config, "#define MOISTURE_SENSOR_WATER_VALUE 340")
+
+	_, err = Render(Config{
+		Board: BoardESP8266,
+		Zones: []ZoneConfig{{PumpPin: "D1", ValvePin: "A0"}},
+	})
+	assert.EqualError(t, err, `invalid pins: zones[0].valve_pin: pin "A0" on esp8266 cannot be used as an output`)
+}
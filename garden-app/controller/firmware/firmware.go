@@ -0,0 +1,175 @@
+// Package firmware renders the garden-controller's config.h and describes the boards that can run it
+package firmware
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"time"
+)
+
+const (
+	configTemplate = `#ifndef config_h
+#define config_h
+
+#define TOPIC_PREFIX "{{ .TopicPrefix }}"
+
+#define QUEUE_SIZE 10
+
+#define ENABLE_WIFI
+#ifdef ENABLE_WIFI
+#define MQTT_ADDRESS "{{ .MQTTBroker }}"
+#define MQTT_PORT {{ .MQTTPort }}
+#define MQTT_CLIENT_NAME TOPIC_PREFIX
+#define MQTT_WATER_TOPIC TOPIC_PREFIX"/command/water"
+#define MQTT_STOP_TOPIC TOPIC_PREFIX"/command/stop"
+#define MQTT_STOP_ALL_TOPIC TOPIC_PREFIX"/command/stop_all"
+#define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
+#define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
+#define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
+#define MQTT_WATER_ACK_TOPIC TOPIC_PREFIX"/ack/water"
+
+{{ if .PublishHealth }}
+#define ENABLE_MQTT_HEALTH
+#ifdef ENABLE_MQTT_HEALTH
+#define MQTT_HEALTH_DATA_TOPIC TOPIC_PREFIX"/data/health"
+#define HEALTH_PUBLISH_INTERVAL {{ milliseconds .HealthInterval }}
+#endif
+{{ end }}
+
+#define ENABLE_MQTT_LOGGING
+#ifdef ENABLE_MQTT_LOGGING
+#define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
+#endif
+
+#define JSON_CAPACITY {{ if .EnableDosing }}80{{ else }}64{{ end }}
+#endif
+
+{{ if .DisableWatering }}
+#define DISABLE_WATERING
+{{ end -}}
+#define NUM_ZONES {{ len .Zones }}
+#define ZONES { {{ range $index, $z := .Zones }}{{if $index}}, {{end}}{ {{ $z.PumpPin }}, {{ $z.ValvePin }}, {{ or $z.ButtonPin $.BoardProfile.NoPin }}, {{ or $z.MoistureSensorPin $.BoardProfile.NoPin }} }{{ end }} }
+#define DEFAULT_WATER_TIME {{ milliseconds  .DefaultWaterTime }}
+
+{{ if .LightPin }}
+#define LIGHT_PIN {{ .LightPin }}
+{{ end }}
+
+{{ if .EnableButtons }}
+#define ENABLE_BUTTONS
+#ifdef ENABLE_BUTTONS
+#define STOP_BUTTON_PIN {{ .StopButtonPin }}
+#endif
+{{ end }}
+
+{{ if .EnableMoistureSensor }}
+#ifdef ENABLE_MOISTURE_SENSORS AND ENABLE_WIFI
+#define MQTT_MOISTURE_DATA_TOPIC TOPIC_PREFIX"/data/moisture"
+#define MOISTURE_SENSOR_AIR_VALUE {{ .BoardProfile.MoistureSensorAirValue }}
+#define MOISTURE_SENSOR_WATER_VALUE {{ .BoardProfile.MoistureSensorWaterValue }}
+#define MOISTURE_SENSOR_INTERVAL {{ milliseconds .MoistureInterval }}
+#endif
+{{ end -}}
+
+{{ if .EnableFlowMeter }}
+#define ENABLE_FLOW_METERS
+#ifdef ENABLE_FLOW_METERS
+#define FLOW_METER_PINS { {{ range $index, $z := .Zones }}{{if $index}}, {{end}}{{ or $z.FlowMeterPin $.BoardProfile.NoPin }}{{ end }} }
+#define FLOW_METER_PULSES_PER_LITER {{ .FlowMeterPulsesPerLiter }}
+#endif
+{{ end -}}
+
+{{ if .EnableDosing }}
+#define ENABLE_DOSING
+#ifdef ENABLE_DOSING
+#define DOSING_PINS { {{ range $index, $z := .Zones }}{{if $index}}, {{end}}{{ or $z.DosingPin $.BoardProfile.NoPin }}{{ end }} }
+#endif
+{{ end -}}
+
+{{ if .PublishTemperatureHumidity }}
+#define ENABLE_DHT22
+#ifdef ENABLE_DHT22
+#define MQTT_TEMPERATURE_DATA_TOPIC TOPIC_PREFIX"/data/temperature"
+#define MQTT_HUMIDITY_DATA_TOPIC TOPIC_PREFIX"/data/humidity"
+#define DHT22_PIN {{ .TemperatureHumidityPin }}
+#define DHT22_INTERVAL {{ milliseconds .TemperatureHumidityInterval }}
+#endif
+{{ end -}}
+#endif
+`
+)
+
+// DefaultFlowMeterPulsesPerLiter is the pulses per liter of common hall effect flow meters
+const DefaultFlowMeterPulsesPerLiter = 450
+
+// Config has the hardware, MQTT, and feature configurations used to render config.h
+type Config struct {
+	TopicPrefix string
+	MQTTBroker  string
+	MQTTPort    int
+	Board       string
+
+	Zones                       []ZoneConfig
+	DefaultWaterTime            time.Duration
+	DisableWatering             bool
+	LightPin                    string
+	EnableButtons               bool
+	StopButtonPin               string
+	EnableMoistureSensor        bool
+	MoistureInterval            time.Duration
+	EnableFlowMeter             bool
+	FlowMeterPulsesPerLiter     float64
+	EnableDosing                bool
+	PublishHealth               bool
+	HealthInterval              time.Duration
+	PublishTemperatureHumidity  bool
+	TemperatureHumidityPin      string
+	TemperatureHumidityInterval time.Duration
+}
+
+// ZoneConfig has the configuration details for controlling hardware pins
+type ZoneConfig struct {
+	PumpPin           string `mapstructure:"pump_pin" survey:"pump_pin"`
+	ValvePin          string `mapstructure:"valve_pin" survey:"valve_pin"`
+	ButtonPin         string `mapstructure:"button_pin" survey:"button_pin"`
+	MoistureSensorPin string `mapstructure:"moisture_sensor_pin" survey:"moisture_sensor_pin"`
+	FlowMeterPin      string `mapstructure:"flow_meter_pin" survey:"flow_meter_pin"`
+	DosingPin         string `mapstructure:"dosing_pin" survey:"dosing_pin"`
+}
+
+// Render validates the pins for the Config's board and renders config.h
+func Render(config Config) (string, error) {
+	milliseconds := func(interval time.Duration) string {
+		return fmt.Sprintf("%d", interval.Milliseconds())
+	}
+	t := template.Must(template.
+		New("config.h").
+		Funcs(template.FuncMap{"milliseconds": milliseconds}).
+		Parse(configTemplate))
+
+	board, err := GetBoardProfile(config.Board)
+	if err != nil {
+		return "", err
+	}
+	err = board.ValidatePins(config)
+	if err != nil {
+		return "", fmt.Errorf("invalid pins: %w", err)
+	}
+
+	var result bytes.Buffer
+	data := struct {
+		Config
+		BoardProfile BoardProfile
+	}{config, board}
+	err = t.Execute(&result, data)
+	if err != nil {
+		return "", err
+	}
+	return removeExtraNewlines(result.String()), nil
+}
+
+func removeExtraNewlines(input string) string {
+	return regexp.MustCompile(`(?m)^\n{2,}`).ReplaceAllLiteralString(input, "\n")
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// firmwareDownloadTimeout limits how long the mock controller waits to download firmware
+const firmwareDownloadTimeout = time.Minute
+
+// firmwareUpdateHandler downloads the firmware and verifies its checksum like the real controller does before
+// installing it. The mock controller only records the new version, then publishes the result
+func (c *Controller) firmwareUpdateHandler(topic string) paho.MessageHandler {
+	return paho.MessageHandler(func(_ paho.Client, msg paho.Message) {
+		firmwareLogger := c.subLogger.With("topic", topic)
+		var updateMsg action.FirmwareUpdateMessage
+		err := json.Unmarshal(msg.Payload(), &updateMsg)
+		if err != nil {
+			firmwareLogger.Error("unable to unmarshal FirmwareUpdateMessage JSON", "error", err)
+			return
+		}
+		firmwareLogger.Info("received FirmwareUpdateMessage", "firmware_id", updateMsg.FirmwareID, "version", updateMsg.Version)
+
+		statusMsg := action.FirmwareStatusMessage{
+			FirmwareID: updateMsg.FirmwareID,
+			Status:     pkg.FirmwareUpdateStatusUpdated,
+		}
+		err = verifyFirmware(updateMsg)
+		if err != nil {
+			firmwareLogger.Error("unable to update firmware", "error", err)
+			statusMsg.Status = pkg.FirmwareUpdateStatusFailed
+			statusMsg.Error = err.Error()
+		} else {
+			c.stateMtx.Lock()
+			c.firmwareVersion = updateMsg.Version
+			c.stateMtx.Unlock()
+		}
+
+		c.publishFirmwareStatus(statusMsg)
+	})
+}
+
+// verifyFirmware downloads the firmware and checks that it matches the SHA-256 checksum
+func verifyFirmware(updateMsg action.FirmwareUpdateMessage) error {
+	client := &http.Client{Timeout: firmwareDownloadTimeout}
+	resp, err := client.Get(updateMsg.URL)
+	if err != nil {
+		return fmt.Errorf("unable to download firmware: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to download firmware: unexpected status %d", resp.StatusCode)
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to download firmware: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if !strings.EqualFold(checksum, updateMsg.Checksum) {
+		return fmt.Errorf("checksum mismatch: expected %q but got %q", updateMsg.Checksum, checksum)
+	}
+	return nil
+}
+
+// publishFirmwareStatus reports the result of a firmware update on "{prefix}/ack/firmware_update"
+func (c *Controller) publishFirmwareStatus(statusMsg action.FirmwareStatusMessage) {
+	ackTopic := c.TopicPrefix + "/ack/firmware_update"
+	c.pubLogger.Info("publishing firmware update status", "topic", ackTopic, "status", statusMsg.Status)
+
+	data, err := json.Marshal(statusMsg)
+	if err != nil {
+		c.pubLogger.Error("unable to marshal FirmwareStatusMessage to JSON", "error", err)
+		return
+	}
+
+	err = c.mqttClient.Publish(ackTopic, data)
+	if err != nil {
+		c.pubLogger.Error("unable to publish firmware update status", "error", err)
+	}
+}
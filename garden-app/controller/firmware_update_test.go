@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirmwareUpdateHandler(t *testing.T) {
+	binary := []byte("firmware binary")
+	sum := sha256.Sum256(binary)
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/firmware/abc/content" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(binary)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		msg             action.FirmwareUpdateMessage
+		expected        action.FirmwareStatusMessage
+		expectedVersion string
+	}{
+		{
+			"Successful",
+			action.FirmwareUpdateMessage{FirmwareID: "abc", Version: "v1.2.0", URL: server.URL + "/firmware/abc/content", Checksum: checksum},
+			action.FirmwareStatusMessage{FirmwareID: "abc", Status: pkg.FirmwareUpdateStatusUpdated},
+			"v1.2.0",
+		},
+		{
+			"ErrorChecksumMismatch",
+			action.FirmwareUpdateMessage{FirmwareID: "abc", Version: "v1.2.0", URL: server.URL + "/firmware/abc/content", Checksum: "123"},
+			action.FirmwareStatusMessage{
+				FirmwareID: "abc",
+				Status:     pkg.FirmwareUpdateStatusFailed,
+				Error:      `checksum mismatch: expected "123" but got "` + checksum + `"`,
+			},
+			"",
+		},
+		{
+			"ErrorNotFound",
+			action.FirmwareUpdateMessage{FirmwareID: "abc", Version: "v1.2.0", URL: server.URL + "/firmware/def/content", Checksum: checksum},
+			action.FirmwareStatusMessage{
+				FirmwareID: "abc",
+				Status:     pkg.FirmwareUpdateStatusFailed,
+				Error:      "unable to download firmware: unexpected status 404",
+			},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status action.FirmwareStatusMessage
+			mqttClient := mqtt.NewInMemoryClient(mqtt.Config{}, nil, mqtt.TopicHandler{
+				Topic: "test-garden/ack/firmware_update",
+				Handler: func(_ paho.Client, msg paho.Message) {
+					require.NoError(t, json.Unmarshal(msg.Payload(), &status))
+				},
+			})
+
+			c := &Controller{
+				Config:     Config{NestedConfig: NestedConfig{TopicPrefix: "test-garden"}},
+				mqttClient: mqttClient,
+				logger:     slog.Default(),
+				pubLogger:  slog.Default(),
+				subLogger:  slog.Default(),
+			}
+			require.NoError(t, mqttClient.Subscribe("test-garden/command/firmware_update", c.firmwareUpdateHandler("test-garden/command/firmware_update")))
+
+			data, err := json.Marshal(tt.msg)
+			require.NoError(t, err)
+			require.NoError(t, mqttClient.Publish("test-garden/command/firmware_update", data))
+
+			assert.Equal(t, tt.expected, status)
+			assert.Equal(t, tt.expectedVersion, c.state().FirmwareVersion)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
+)
+
+// execCommand is used to run external commands and can be replaced in tests
+var execCommand = exec.CommandContext
+
+// FlashFirmware uses PlatformIO to build the firmware in FirmwareDir and upload it to the board. PlatformIO uploads to
+// ESP32 and ESP8266 boards with esptool. If port is empty, PlatformIO detects it
+func FlashFirmware(ctx context.Context, config Config, port string) error {
+	if config.FirmwareDir == "" {
+		return errors.New("firmware_dir is required for flashing")
+	}
+
+	board, err := firmware.GetBoardProfile(config.Board)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"run", "--project-dir", config.FirmwareDir, "--environment", board.PlatformIOEnv, "--target", "upload"}
+	if port != "" {
+		args = append(args, "--upload-port", port)
+	}
+
+	config.LogConfig.NewLogger().Info("flashing firmware", "board", board.Name, "firmware_dir", config.FirmwareDir, "port", port)
+
+	cmd := execCommand(ctx, "pio", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running PlatformIO: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlashFirmware(t *testing.T) {
+	var args []string
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		args = append([]string{name}, arg...)
+		return exec.CommandContext(ctx, "true")
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	t.Run("ESP32WithPort", func(t *testing.T) {
+		err := FlashFirmware(context.Background(), Config{NestedConfig: NestedConfig{FirmwareDir: "garden-controller"}}, "/dev/ttyUSB0")
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"pio", "run", "--project-dir", "garden-controller", "--environment", "esp32dev", "--target", "upload", "--upload-port", "/dev/ttyUSB0",
+		}, args)
+	})
+
+	t.Run("ESP8266", func(t *testing.T) {
+		err := FlashFirmware(context.Background(), Config{NestedConfig: NestedConfig{FirmwareDir: "garden-controller", Board: firmware.BoardESP8266}}, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"pio", "run", "--project-dir", "garden-controller", "--environment", "nodemcuv2", "--target", "upload",
+		}, args)
+	})
+
+	t.Run("ErrorMissingFirmwareDir", func(t *testing.T) {
+		err := FlashFirmware(context.Background(), Config{}, "")
+		assert.EqualError(t, err, "firmware_dir is required for flashing")
+	})
+}
@@ -2,90 +2,21 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"regexp"
-	"time"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/calvinmclean/automated-garden/garden-app/client"
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 )
 
 const (
-	configTemplate = `#ifndef config_h
-#define config_h
-
-#define TOPIC_PREFIX "{{ .TopicPrefix }}"
-
-#define QUEUE_SIZE 10
-
-#define ENABLE_WIFI
-#ifdef ENABLE_WIFI
-#define MQTT_ADDRESS "{{ .MQTTConfig.Broker }}"
-#define MQTT_PORT {{ .MQTTConfig.Port }}
-#define MQTT_CLIENT_NAME TOPIC_PREFIX
-#define MQTT_WATER_TOPIC TOPIC_PREFIX"/command/water"
-#define MQTT_STOP_TOPIC TOPIC_PREFIX"/command/stop"
-#define MQTT_STOP_ALL_TOPIC TOPIC_PREFIX"/command/stop_all"
-#define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
-#define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
-#define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
-
-{{ if .PublishHealth }}
-#define ENABLE_MQTT_HEALTH
-#ifdef ENABLE_MQTT_HEALTH
-#define MQTT_HEALTH_DATA_TOPIC TOPIC_PREFIX"/data/health"
-#define HEALTH_PUBLISH_INTERVAL {{ milliseconds .HealthInterval }}
-#endif
-{{ end }}
-
-#define ENABLE_MQTT_LOGGING
-#ifdef ENABLE_MQTT_LOGGING
-#define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
-#endif
-
-#define JSON_CAPACITY 48
-#endif
-
-{{ if .DisableWatering }}
-#define DISABLE_WATERING
-{{ end -}}
-#define NUM_ZONES {{ len .Zones }}
-#define ZONES { {{ range $index, $z := .Zones }}{{if $index}}, {{end}}{ {{ $z.PumpPin }}, {{ $z.ValvePin }}, {{ or $z.ButtonPin "GPIO_NUM_MAX" }}, {{ or $z.MoistureSensorPin "GPIO_NUM_MAX" }} }{{ end }} }
-#define DEFAULT_WATER_TIME {{ milliseconds  .DefaultWaterTime }}
-
-{{ if .LightPin }}
-#define LIGHT_PIN {{ .LightPin }}
-{{ end }}
-
-{{ if .EnableButtons }}
-#define ENABLE_BUTTONS
-#ifdef ENABLE_BUTTONS
-#define STOP_BUTTON_PIN {{ .StopButtonPin }}
-#endif
-{{ end }}
-
-{{ if .EnableMoistureSensor }}
-#ifdef ENABLE_MOISTURE_SENSORS AND ENABLE_WIFI
-#define MQTT_MOISTURE_DATA_TOPIC TOPIC_PREFIX"/data/moisture"
-#define MOISTURE_SENSOR_AIR_VALUE 3415
-#define MOISTURE_SENSOR_WATER_VALUE 1362
-#define MOISTURE_SENSOR_INTERVAL {{ milliseconds .MoistureInterval }}
-#endif
-{{ end -}}
-
-{{ if .PublishTemperatureHumidity }}
-#define ENABLE_DHT22
-#ifdef ENABLE_DHT22
-#define MQTT_TEMPERATURE_DATA_TOPIC TOPIC_PREFIX"/data/temperature"
-#define MQTT_HUMIDITY_DATA_TOPIC TOPIC_PREFIX"/data/humidity"
-#define DHT22_PIN {{ .TemperatureHumidityPin }}
-#define DHT22_INTERVAL {{ milliseconds .TemperatureHumidityInterval }}
-#endif
-{{ end -}}
-#endif
-`
 	wifiConfigTemplate = `#ifndef wifi_config_h
 #define wifi_config_h
 
@@ -103,16 +34,66 @@ type WifiConfig struct {
 }
 
 // ZoneConfig has the configuration details for controlling hardware pins
-type ZoneConfig struct {
-	PumpPin           string `mapstructure:"pump_pin" survey:"pump_pin"`
-	ValvePin          string `mapstructure:"valve_pin" survey:"valve_pin"`
-	ButtonPin         string `mapstructure:"button_pin" survey:"button_pin"`
-	MoistureSensorPin string `mapstructure:"moisture_sensor_pin" survey:"moisture_sensor_pin"`
+type ZoneConfig = firmware.ZoneConfig
+
+// firmwareConfig creates the firmware.Config used to render config.h
+func (cfg Config) firmwareConfig() firmware.Config {
+	return firmware.Config{
+		TopicPrefix:                 cfg.TopicPrefix,
+		MQTTBroker:                  cfg.MQTTConfig.Broker,
+		MQTTPort:                    cfg.MQTTConfig.Port,
+		Board:                       cfg.Board,
+		Zones:                       cfg.Zones,
+		DefaultWaterTime:            cfg.DefaultWaterTime,
+		DisableWatering:             cfg.DisableWatering,
+		LightPin:                    cfg.LightPin,
+		EnableButtons:               cfg.EnableButtons,
+		StopButtonPin:               cfg.StopButtonPin,
+		EnableMoistureSensor:        cfg.EnableMoistureSensor,
+		MoistureInterval:            cfg.MoistureInterval,
+		EnableFlowMeter:             cfg.EnableFlowMeter,
+		FlowMeterPulsesPerLiter:     cfg.FlowMeterPulsesPerLiter,
+		EnableDosing:                cfg.EnableDosing,
+		PublishHealth:               cfg.PublishHealth,
+		HealthInterval:              cfg.HealthInterval,
+		PublishTemperatureHumidity:  cfg.PublishTemperatureHumidity,
+		TemperatureHumidityPin:      cfg.TemperatureHumidityPin,
+		TemperatureHumidityInterval: cfg.TemperatureHumidityInterval,
+	}
+}
+
+// LoadZonesFromAPI replaces the configured Zones and TopicPrefix with the Garden's from the garden-app API, so the
+// generated config uses the pins stored in each Zone's HardwareConfig
+func (cfg *Config) LoadZonesFromAPI(ctx context.Context) error {
+	c := client.New(cfg.GardenAPIAddress)
+
+	garden, err := c.GetGarden(ctx, cfg.GardenID)
+	if err != nil {
+		return fmt.Errorf("error getting Garden: %w", err)
+	}
+
+	zones, err := c.ListZones(ctx, cfg.GardenID, false)
+	if err != nil {
+		return fmt.Errorf("error getting Zones: %w", err)
+	}
+
+	err = garden.ValidatePinConflicts(zones)
+	if err != nil {
+		return fmt.Errorf("invalid Zone pins: %w", err)
+	}
+
+	cfg.Zones, err = pkg.ControllerZones(zones)
+	if err != nil {
+		return fmt.Errorf("invalid Zone pins: %w", err)
+	}
+	cfg.TopicPrefix = garden.TopicPrefix
+
+	return nil
 }
 
 // GenerateConfig will create config.h and wifi_config.h based on the provided configurations. It can optionally write to files
-// instead of stdout
-func GenerateConfig(config Config, writeFile, genWifiConfig, genMainConfig, overwrite, interactive bool) {
+// instead of stdout. When FirmwareDir is configured, the files are always written to its include directory
+func GenerateConfig(config Config, writeFile, genWifiConfig, genMainConfig, overwrite, interactive bool) error {
 	logger := config.LogConfig.NewLogger()
 
 	if interactive {
@@ -121,22 +102,25 @@ func GenerateConfig(config Config, writeFile, genWifiConfig, genMainConfig, over
 			Default: genMainConfig,
 		}, &genMainConfig)
 		if err != nil {
-			logger.Error("survey error", "error", err)
-			return
+			return fmt.Errorf("survey error: %w", err)
 		}
 	}
 
+	includeDir := ""
+	if config.FirmwareDir != "" {
+		writeFile = true
+		includeDir = filepath.Join(config.FirmwareDir, "include")
+	}
+
 	if genMainConfig {
 		logger.Debug("generating 'config.h'")
 		mainConfig, err := generateMainConfig(config, interactive)
 		if err != nil {
-			logger.Error("error generating 'config.h'", "error", err)
-			return
+			return fmt.Errorf("error generating 'config.h': %w", err)
 		}
-		err = writeOutput(logger, mainConfig, "config.h", writeFile, overwrite, interactive)
+		err = writeOutput(logger, mainConfig, filepath.Join(includeDir, "config.h"), writeFile, overwrite, interactive)
 		if err != nil {
-			logger.Error("error generating 'config.h'", "error", err)
-			return
+			return fmt.Errorf("error generating 'config.h': %w", err)
 		}
 	}
 
@@ -146,8 +130,7 @@ func GenerateConfig(config Config, writeFile, genWifiConfig, genMainConfig, over
 			Default: genWifiConfig,
 		}, &genWifiConfig)
 		if err != nil {
-			logger.Error("survey error", "error", err)
-			return
+			return fmt.Errorf("survey error: %w", err)
 		}
 	}
 
@@ -155,15 +138,15 @@ func GenerateConfig(config Config, writeFile, genWifiConfig, genMainConfig, over
 		logger.Debug("generating 'wifi_config.h'")
 		wifiConfig, err := generateWiFiConfig(config.WifiConfig, interactive)
 		if err != nil {
-			logger.Error("error generating 'wifi_config.h'", "error", err)
-			return
+			return fmt.Errorf("error generating 'wifi_config.h': %w", err)
 		}
-		err = writeOutput(logger, wifiConfig, "wifi_config.h", writeFile, overwrite, interactive)
+		err = writeOutput(logger, wifiConfig, filepath.Join(includeDir, "wifi_config.h"), writeFile, overwrite, interactive)
 		if err != nil {
-			logger.Error("error generating 'wifi_config.h'", "error", err)
-			return
+			return fmt.Errorf("error generating 'wifi_config.h': %w", err)
 		}
 	}
+
+	return nil
 }
 
 func writeOutput(logger *slog.Logger, content, filename string, writeFile, overwrite, interactive bool) error {
@@ -227,21 +210,7 @@ func generateMainConfig(config Config, interactive bool) (string, error) {
 		}
 	}
 
-	milliseconds := func(interval time.Duration) string {
-		return fmt.Sprintf("%d", interval.Milliseconds())
-	}
-	t := template.Must(template.
-		New("config.h").
-		Funcs(template.FuncMap{"milliseconds": milliseconds}).
-		Parse(configTemplate))
-
-	var result bytes.Buffer
-	data := config
-	err := t.Execute(&result, data)
-	if err != nil {
-		return "", err
-	}
-	return removeExtraNewlines(result.String()), nil
+	return firmware.Render(config.firmwareConfig())
 }
 
 func generateWiFiConfig(config WifiConfig, interactive bool) (string, error) {
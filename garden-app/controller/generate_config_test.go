@@ -2,19 +2,41 @@ package controller
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGenerateConfig(_ *testing.T) {
-	GenerateConfig(Config{}, true, true, true, false, false)
-	GenerateConfig(Config{}, true, true, true, false, false)
+	_ = GenerateConfig(Config{}, true, true, true, false, false)
+	_ = GenerateConfig(Config{}, true, true, true, false, false)
 	os.RemoveAll("config.h")
 }
 
+func TestGenerateConfigFirmwareDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "include"), 0o755))
+
+	config := Config{NestedConfig: NestedConfig{
+		FirmwareDir: dir,
+		TopicPrefix: "garden",
+		WifiConfig:  WifiConfig{SSID: "ssid", Password: "password"},
+	}}
+	require.NoError(t, GenerateConfig(config, false, true, true, false, false))
+
+	assert.FileExists(t, filepath.Join(dir, "include", "config.h"))
+	wifiConfig, err := os.ReadFile(filepath.Join(dir, "include", "wifi_config.h"))
+	require.NoError(t, err)
+	assert.Contains(t, string(wifiConfig), `#define SSID "ssid"`)
+
+	err = GenerateConfig(config, false, true, true, false, false)
+	assert.ErrorContains(t, err, "use --force to overwrite")
+}
+
 func TestGenerateMainConfig(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -57,13 +79,14 @@ func TestGenerateMainConfig(t *testing.T) {
 #define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
 #define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
 #define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
+#define MQTT_WATER_ACK_TOPIC TOPIC_PREFIX"/ack/water"
 
 #define ENABLE_MQTT_LOGGING
 #ifdef ENABLE_MQTT_LOGGING
 #define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
 #endif
 
-#define JSON_CAPACITY 48
+#define JSON_CAPACITY 64
 #endif
 
 #define NUM_ZONES 1
@@ -83,6 +106,7 @@ func TestGenerateMainConfig(t *testing.T) {
 							ValvePin:          "GPIO_NUM_16",
 							ButtonPin:         "GPIO_NUM_19",
 							MoistureSensorPin: "GPIO_NUM_36",
+							FlowMeterPin:      "GPIO_NUM_25",
 						},
 					},
 					TopicPrefix:                 "garden",
@@ -97,6 +121,8 @@ func TestGenerateMainConfig(t *testing.T) {
 					PublishTemperatureHumidity:  true,
 					TemperatureHumidityInterval: 5 * time.Minute,
 					TemperatureHumidityPin:      "GPIO_NUM_27",
+					EnableFlowMeter:             true,
+					FlowMeterPulsesPerLiter:     450,
 				},
 				MQTTConfig: mqtt.Config{
 					Broker: "localhost",
@@ -121,6 +147,7 @@ func TestGenerateMainConfig(t *testing.T) {
 #define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
 #define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
 #define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
+#define MQTT_WATER_ACK_TOPIC TOPIC_PREFIX"/ack/water"
 
 #define ENABLE_MQTT_HEALTH
 #ifdef ENABLE_MQTT_HEALTH
@@ -133,7 +160,7 @@ func TestGenerateMainConfig(t *testing.T) {
 #define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
 #endif
 
-#define JSON_CAPACITY 48
+#define JSON_CAPACITY 64
 #endif
 
 #define NUM_ZONES 1
@@ -154,6 +181,12 @@ func TestGenerateMainConfig(t *testing.T) {
 #define MOISTURE_SENSOR_INTERVAL 5000
 #endif
 
+#define ENABLE_FLOW_METERS
+#ifdef ENABLE_FLOW_METERS
+#define FLOW_METER_PINS { GPIO_NUM_25 }
+#define FLOW_METER_PULSES_PER_LITER 450
+#endif
+
 #define ENABLE_DHT22
 #ifdef ENABLE_DHT22
 #define MQTT_TEMPERATURE_DATA_TOPIC TOPIC_PREFIX"/data/temperature"
@@ -201,13 +234,14 @@ func TestGenerateMainConfig(t *testing.T) {
 #define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
 #define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
 #define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
+#define MQTT_WATER_ACK_TOPIC TOPIC_PREFIX"/ack/water"
 
 #define ENABLE_MQTT_LOGGING
 #ifdef ENABLE_MQTT_LOGGING
 #define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
 #endif
 
-#define JSON_CAPACITY 48
+#define JSON_CAPACITY 64
 #endif
 
 #define DISABLE_WATERING
@@ -266,19 +300,148 @@ func TestGenerateMainConfig(t *testing.T) {
 #define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
 #define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
 #define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
+#define MQTT_WATER_ACK_TOPIC TOPIC_PREFIX"/ack/water"
 
 #define ENABLE_MQTT_LOGGING
 #ifdef ENABLE_MQTT_LOGGING
 #define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
 #endif
 
-#define JSON_CAPACITY 48
+#define JSON_CAPACITY 64
 #endif
 
 #define NUM_ZONES 4
 #define ZONES { { GPIO_NUM_18, GPIO_NUM_16, GPIO_NUM_MAX, GPIO_NUM_MAX }, { GPIO_NUM_18, GPIO_NUM_16, GPIO_NUM_MAX, GPIO_NUM_MAX }, { GPIO_NUM_18, GPIO_NUM_16, GPIO_NUM_MAX, GPIO_NUM_MAX }, { GPIO_NUM_18, GPIO_NUM_16, GPIO_NUM_MAX, GPIO_NUM_MAX } }
 #define DEFAULT_WATER_TIME 5000
 
+#endif
+`,
+		},
+		{
+			"MultipleZonesWithFlowMeters",
+			Config{
+				NestedConfig: NestedConfig{
+					Zones: []ZoneConfig{
+						{
+							PumpPin:      "GPIO_NUM_18",
+							ValvePin:     "GPIO_NUM_16",
+							FlowMeterPin: "GPIO_NUM_25",
+						},
+						{
+							PumpPin:  "GPIO_NUM_18",
+							ValvePin: "GPIO_NUM_17",
+						},
+					},
+					TopicPrefix:             "garden",
+					DefaultWaterTime:        5 * time.Second,
+					EnableFlowMeter:         true,
+					FlowMeterPulsesPerLiter: 450,
+				},
+				MQTTConfig: mqtt.Config{
+					Broker: "localhost",
+					Port:   1883,
+				},
+			},
+			`#ifndef config_h
+#define config_h
+
+#define TOPIC_PREFIX "garden"
+
+#define QUEUE_SIZE 10
+
+#define ENABLE_WIFI
+#ifdef ENABLE_WIFI
+#define MQTT_ADDRESS "localhost"
+#define MQTT_PORT 1883
+#define MQTT_CLIENT_NAME TOPIC_PREFIX
+#define MQTT_WATER_TOPIC TOPIC_PREFIX"/command/water"
+#define MQTT_STOP_TOPIC TOPIC_PREFIX"/command/stop"
+#define MQTT_STOP_ALL_TOPIC TOPIC_PREFIX"/command/stop_all"
+#define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
+#define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
+#define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
+#define MQTT_WATER_ACK_TOPIC TOPIC_PREFIX"/ack/water"
+
+#define ENABLE_MQTT_LOGGING
+#ifdef ENABLE_MQTT_LOGGING
+#define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
+#endif
+
+#define JSON_CAPACITY 64
+#endif
+
+#define NUM_ZONES 2
+#define ZONES { { GPIO_NUM_18, GPIO_NUM_16, GPIO_NUM_MAX, GPIO_NUM_MAX }, { GPIO_NUM_18, GPIO_NUM_17, GPIO_NUM_MAX, GPIO_NUM_MAX } }
+#define DEFAULT_WATER_TIME 5000
+
+#define ENABLE_FLOW_METERS
+#ifdef ENABLE_FLOW_METERS
+#define FLOW_METER_PINS { GPIO_NUM_25, GPIO_NUM_MAX }
+#define FLOW_METER_PULSES_PER_LITER 450
+#endif
+#endif
+`,
+		},
+		{
+			"MultipleZonesWithDosingPumps",
+			Config{
+				NestedConfig: NestedConfig{
+					Zones: []ZoneConfig{
+						{
+							PumpPin:  "GPIO_NUM_18",
+							ValvePin: "GPIO_NUM_16",
+						},
+						{
+							PumpPin:   "GPIO_NUM_18",
+							ValvePin:  "GPIO_NUM_17",
+							DosingPin: "GPIO_NUM_26",
+						},
+					},
+					TopicPrefix:      "garden",
+					DefaultWaterTime: 5 * time.Second,
+					EnableDosing:     true,
+				},
+				MQTTConfig: mqtt.Config{
+					Broker: "localhost",
+					Port:   1883,
+				},
+			},
+			`#ifndef config_h
+#define config_h
+
+#define TOPIC_PREFIX "garden"
+
+#define QUEUE_SIZE 10
+
+#define ENABLE_WIFI
+#ifdef ENABLE_WIFI
+#define MQTT_ADDRESS "localhost"
+#define MQTT_PORT 1883
+#define MQTT_CLIENT_NAME TOPIC_PREFIX
+#define MQTT_WATER_TOPIC TOPIC_PREFIX"/command/water"
+#define MQTT_STOP_TOPIC TOPIC_PREFIX"/command/stop"
+#define MQTT_STOP_ALL_TOPIC TOPIC_PREFIX"/command/stop_all"
+#define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
+#define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
+#define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
+#define MQTT_WATER_ACK_TOPIC TOPIC_PREFIX"/ack/water"
+
+#define ENABLE_MQTT_LOGGING
+#ifdef ENABLE_MQTT_LOGGING
+#define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
+#endif
+
+#define JSON_CAPACITY 80
+#endif
+
+#define NUM_ZONES 2
+#define ZONES { { GPIO_NUM_18, GPIO_NUM_16, GPIO_NUM_MAX, GPIO_NUM_MAX }, { GPIO_NUM_18, GPIO_NUM_17, GPIO_NUM_MAX, GPIO_NUM_MAX } }
+#define DEFAULT_WATER_TIME 5000
+
+#define ENABLE_DOSING
+#ifdef ENABLE_DOSING
+#define DOSING_PINS { GPIO_NUM_MAX, GPIO_NUM_26 }
+#endif
 #endif
 `,
 		},
@@ -1,10 +1,14 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
 	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func (c *Controller) waterHandler(topic string) paho.MessageHandler {
@@ -17,15 +21,43 @@ func (c *Controller) waterHandler(topic string) paho.MessageHandler {
 			return
 		}
 
+		// Continue the garden-app's trace so the time between publishing and receiving the message is included
+		ctx := tracing.Extract(context.Background(), waterMsg.TraceContext)
+		_, span := tracing.Start(ctx, "controller.Water", trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(
+			attribute.String("messaging.system", "mqtt"),
+			attribute.String("messaging.destination.name", msg.Topic()),
+			attribute.String("zone_id", waterMsg.ZoneID),
+		))
+		defer span.End()
+
+		if waterMsg.CommandID != "" {
+			// Retried commands are acknowledged again, but only watered once
+			defer c.publishWaterAck(waterMsg.CommandID, topic)
+			if c.isDuplicateCommand(waterMsg.CommandID) {
+				waterLogger.Info("ignoring WaterAction that was already received", "command_id", waterMsg.CommandID)
+				return
+			}
+		}
+
 		c.assertionData.Lock()
 		c.assertionData.waterActions = append(c.assertionData.waterActions, waterMsg)
 		c.assertionData.Unlock()
 
+		// The firmware waters for its DefaultWaterTime when a WaterAction has no duration
+		if waterMsg.Duration == 0 {
+			waterMsg.Duration = c.defaultWaterTime().Milliseconds()
+		}
+
 		waterLogger.With(
 			"zone_id", waterMsg.ZoneID,
 			"position", waterMsg.Position,
 			"duration", waterMsg.Duration,
 		).Info("received WaterAction")
+
+		if c.SimulateWatering {
+			c.queueWater(waterMsg, topic)
+			return
+		}
 		c.publishWaterEvent(waterMsg, topic)
 	}
 }
@@ -37,6 +69,9 @@ func (c *Controller) stopHandler(_ string) paho.MessageHandler {
 		c.assertionData.Unlock()
 
 		c.subLogger.Info("received StopAction", "topic", msg.Topic())
+		if c.SimulateWatering {
+			c.stopWatering()
+		}
 	}
 }
 
@@ -47,6 +82,9 @@ func (c *Controller) stopAllHandler(_ string) paho.MessageHandler {
 		c.assertionData.Unlock()
 
 		c.subLogger.Info("received StopAllAction", "topic", msg.Topic())
+		if c.SimulateWatering {
+			c.stopAllWatering()
+		}
 	})
 }
 
@@ -65,5 +103,43 @@ func (c *Controller) lightHandler(topic string) paho.MessageHandler {
 		c.assertionData.Unlock()
 
 		lightLogger.Info("received LightAction", "state", action.State)
+		c.setLightState(action.State)
+	})
+}
+
+func (c *Controller) recirculationHandler(topic string) paho.MessageHandler {
+	return paho.MessageHandler(func(_ paho.Client, msg paho.Message) {
+		recirculationLogger := c.subLogger.With("topic", topic)
+		var action action.RecirculationAction
+		err := json.Unmarshal(msg.Payload(), &action)
+		if err != nil {
+			recirculationLogger.Error("unable to unmarshal RecirculationAction JSON", "error", err)
+			return
+		}
+
+		c.assertionData.Lock()
+		c.assertionData.recirculationActions = append(c.assertionData.recirculationActions, action)
+		c.assertionData.Unlock()
+
+		recirculationLogger.Info("received RecirculationAction", "state", action.State)
+	})
+}
+
+func (c *Controller) configHandler(topic string) paho.MessageHandler {
+	return paho.MessageHandler(func(_ paho.Client, msg paho.Message) {
+		configLogger := c.subLogger.With("topic", topic)
+		var configMsg action.ConfigMessage
+		err := json.Unmarshal(msg.Payload(), &configMsg)
+		if err != nil {
+			configLogger.Error("unable to unmarshal ConfigMessage JSON", "error", err)
+			return
+		}
+
+		configLogger.Info(
+			"received ConfigMessage",
+			"num_zones", configMsg.NumZones,
+			"default_water_time", configMsg.DefaultWaterTime,
+		)
+		c.applyConfig(configMsg)
 	})
 }
@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+)
+
+// StateResponse is the virtual hardware state of the Controller returned by the HTTP API
+type StateResponse struct {
+	TopicPrefix      string         `json:"topic_prefix"`
+	Connected        bool           `json:"connected"`
+	NumZones         int            `json:"num_zones"`
+	MoistureStrategy string         `json:"moisture_strategy"`
+	MoistureValue    int            `json:"moisture_value"`
+	LightState       pkg.LightState `json:"light_state"`
+	DefaultWaterTime string         `json:"default_water_time,omitempty"`
+	FirmwareVersion  string         `json:"firmware_version,omitempty"`
+}
+
+// CommandsResponse has the commands received by the Controller since they were last cleared
+type CommandsResponse struct {
+	Water         []action.WaterMessage        `json:"water"`
+	Stop          int                          `json:"stop"`
+	StopAll       int                          `json:"stop_all"`
+	Light         []action.LightAction         `json:"light"`
+	Recirculation []action.RecirculationAction `json:"recirculation"`
+}
+
+// MoistureRequest changes the value and optionally the strategy used to create moisture data
+type MoistureRequest struct {
+	Value    *int   `json:"value"`
+	Strategy string `json:"strategy"`
+}
+
+// ConnectionRequest connects or disconnects the Controller's MQTT client
+type ConnectionRequest struct {
+	Connected bool `json:"connected"`
+}
+
+// LightRequest sets the light state. An empty state toggles the light
+type LightRequest struct {
+	State pkg.LightState `json:"state"`
+}
+
+// HTTPHandler returns the HTTP API used to inspect and change the Controller's virtual hardware:
+//   - GET /state: get the current state
+//   - PUT /moisture: set the moisture value and strategy
+//   - PUT /connection: connect or disconnect from the MQTT broker
+//   - PUT /light: turn the light on, off, or toggle it and publish the new state
+//   - GET /commands: get the commands received from the garden-app
+//   - DELETE /commands: clear the received commands
+func (c *Controller) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", c.allowMethods(c.getState, http.MethodGet))
+	mux.HandleFunc("/moisture", c.allowMethods(c.setMoisture, http.MethodPut))
+	mux.HandleFunc("/connection", c.allowMethods(c.setConnection, http.MethodPut))
+	mux.HandleFunc("/light", c.allowMethods(c.setLight, http.MethodPut))
+	mux.HandleFunc("/commands", c.allowMethods(c.commands, http.MethodGet, http.MethodDelete))
+	return mux
+}
+
+// allowMethods responds with 405 Method Not Allowed for requests that do not use one of the methods
+func (c *Controller) allowMethods(handler http.HandlerFunc, methods ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(methods, r.Method) {
+			c.httpError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (c *Controller) getState(w http.ResponseWriter, _ *http.Request) {
+	c.writeJSON(w, http.StatusOK, c.state())
+}
+
+func (c *Controller) setMoisture(w http.ResponseWriter, r *http.Request) {
+	var req MoistureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.httpError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if req.Value == nil {
+		c.httpError(w, http.StatusBadRequest, errors.New("missing required value field"))
+		return
+	}
+	if req.Strategy != "" && !slices.Contains(moistureStrategies, req.Strategy) {
+		c.httpError(w, http.StatusBadRequest, fmt.Errorf("invalid strategy %q", req.Strategy))
+		return
+	}
+
+	c.stateMtx.Lock()
+	c.MoistureValue = *req.Value
+	if req.Strategy != "" {
+		c.MoistureStrategy = req.Strategy
+	}
+	c.stateMtx.Unlock()
+
+	c.logger.Info("moisture changed from HTTP API", "moisture_value", *req.Value, "moisture_strategy", req.Strategy)
+	c.writeJSON(w, http.StatusOK, c.state())
+}
+
+func (c *Controller) setConnection(w http.ResponseWriter, r *http.Request) {
+	var req ConnectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.httpError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+
+	if req.Connected {
+		c.logger.Info("connecting to MQTT broker from HTTP API")
+		if err := c.mqttClient.Connect(); err != nil {
+			c.httpError(w, http.StatusInternalServerError, fmt.Errorf("unable to connect to MQTT broker: %w", err))
+			return
+		}
+	} else {
+		c.logger.Info("disconnecting from MQTT broker from HTTP API")
+		c.mqttClient.Disconnect(250)
+	}
+
+	c.stateMtx.Lock()
+	c.disconnected = !req.Connected
+	c.stateMtx.Unlock()
+
+	c.writeJSON(w, http.StatusOK, c.state())
+}
+
+func (c *Controller) setLight(w http.ResponseWriter, r *http.Request) {
+	var req LightRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.httpError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+
+	c.setLightState(req.State)
+	c.writeJSON(w, http.StatusOK, c.state())
+}
+
+func (c *Controller) commands(w http.ResponseWriter, r *http.Request) {
+	c.assertionData.Lock()
+	resp := CommandsResponse{
+		Water:         c.assertionData.waterActions,
+		Stop:          c.assertionData.stopActions,
+		StopAll:       c.assertionData.stopAllActions,
+		Light:         c.assertionData.lightActions,
+		Recirculation: c.assertionData.recirculationActions,
+	}
+	if r.Method == http.MethodDelete {
+		c.assertionData.waterActions = nil
+		c.assertionData.stopActions = 0
+		c.assertionData.stopAllActions = 0
+		c.assertionData.lightActions = nil
+		c.assertionData.recirculationActions = nil
+	}
+	c.assertionData.Unlock()
+
+	if r.Method == http.MethodDelete {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	c.writeJSON(w, http.StatusOK, resp)
+}
+
+// state returns the current StateResponse
+func (c *Controller) state() StateResponse {
+	c.stateMtx.Lock()
+	defer c.stateMtx.Unlock()
+
+	connected := !c.disconnected
+	if checker, ok := c.mqttClient.(mqtt.ConnectionChecker); ok {
+		connected = checker.IsConnected()
+	}
+
+	state := StateResponse{
+		TopicPrefix:      c.TopicPrefix,
+		Connected:        connected,
+		NumZones:         c.NumZones,
+		MoistureStrategy: c.MoistureStrategy,
+		MoistureValue:    c.MoistureValue,
+		LightState:       c.lightState,
+		FirmwareVersion:  c.firmwareVersion,
+	}
+	if c.DefaultWaterTime > 0 {
+		state.DefaultWaterTime = c.DefaultWaterTime.String()
+	}
+	return state
+}
+
+func (c *Controller) writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		c.logger.Error("unable to write HTTP response", "error", err)
+	}
+}
+
+func (c *Controller) httpError(w http.ResponseWriter, status int, err error) {
+	c.writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// startHTTPServer serves the handler on addr until the returned function is used to shut it down
+func startHTTPServer(addr string, handler http.Handler, logger *slog.Logger) func() {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		logger.Info("starting HTTP API", "address", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("error running HTTP API", "error", err)
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Error("error shutting down HTTP API", "error", err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	newController := func() (*Controller, *mqtt.MockClient) {
+		mqttClient := new(mqtt.MockClient)
+		return &Controller{
+			Config: Config{NestedConfig: NestedConfig{
+				TopicPrefix:      "test-garden",
+				NumZones:         2,
+				MoistureStrategy: "random",
+				MoistureValue:    100,
+			}},
+			mqttClient: mqttClient,
+			logger:     slog.Default(),
+			pubLogger:  slog.Default(),
+			subLogger:  slog.Default(),
+		}, mqttClient
+	}
+
+	request := func(c *Controller, method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		c.HTTPHandler().ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("GetState", func(t *testing.T) {
+		c, _ := newController()
+
+		w := request(c, http.MethodGet, "/state", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"topic_prefix":"test-garden","connected":true,"num_zones":2,"moisture_strategy":"random","moisture_value":100,"light_state":"OFF"}`, w.Body.String())
+	})
+
+	t.Run("SetMoisture", func(t *testing.T) {
+		c, _ := newController()
+
+		w := request(c, http.MethodPut, "/moisture", `{"value":42,"strategy":"constant"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 42, c.createMoistureData())
+		assert.Equal(t, "constant", c.MoistureStrategy)
+	})
+
+	t.Run("SetMoistureErrors", func(t *testing.T) {
+		c, _ := newController()
+
+		w := request(c, http.MethodPut, "/moisture", `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"missing required value field"}`, w.Body.String())
+
+		w = request(c, http.MethodPut, "/moisture", `{"value":1,"strategy":"sideways"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"invalid strategy \"sideways\""}`, w.Body.String())
+
+		w = request(c, http.MethodGet, "/moisture", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("SetConnection", func(t *testing.T) {
+		c, mqttClient := newController()
+		mqttClient.On("Disconnect", uint(250)).Return()
+		mqttClient.On("Connect").Return(nil)
+
+		w := request(c, http.MethodPut, "/connection", `{"connected":false}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, c.state().Connected)
+
+		w = request(c, http.MethodPut, "/connection", `{"connected":true}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, c.state().Connected)
+
+		mqttClient.AssertExpectations(t)
+	})
+
+	t.Run("SetLight", func(t *testing.T) {
+		c, mqttClient := newController()
+		mqttClient.On("Publish", "test-garden/data/light", []byte(`light,garden="test-garden" state=1`)).Return(nil).Once()
+		mqttClient.On("Publish", "test-garden/data/light", []byte(`light,garden="test-garden" state=0`)).Return(nil).Once()
+
+		w := request(c, http.MethodPut, "/light", `{"state":""}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, pkg.LightStateOn, c.state().LightState)
+
+		w = request(c, http.MethodPut, "/light", `{"state":"OFF"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, pkg.LightStateOff, c.state().LightState)
+
+		mqttClient.AssertExpectations(t)
+	})
+
+	t.Run("Commands", func(t *testing.T) {
+		c, _ := newController()
+		c.assertionData.waterActions = []action.WaterMessage{{Duration: 1000, ZoneID: "zone", Position: 0}}
+		c.assertionData.stopActions = 1
+
+		w := request(c, http.MethodGet, "/commands", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"stop":1`)
+		assert.Contains(t, w.Body.String(), `"id":"zone"`)
+
+		w = request(c, http.MethodDelete, "/commands", "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		c.AssertWaterActions(t)
+		c.AssertStopActions(t, 0)
+	})
+
+	t.Run("ToggleLight", func(t *testing.T) {
+		c, mqttClient := newController()
+		mqttClient.On("Publish", "test-garden/data/light", mock.Anything).Return(nil)
+
+		c.setLightState(pkg.LightStateToggle)
+		c.setLightState(pkg.LightStateToggle)
+		assert.Equal(t, pkg.LightStateOff, c.state().LightState)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
 )
 
 func configPrompts(config *Config) error {
@@ -12,6 +13,20 @@ func configPrompts(config *Config) error {
 		return fmt.Errorf("error completing MQTT prompts: %w", err)
 	}
 
+	board := config.Board
+	if board == "" {
+		board = firmware.BoardESP32
+	}
+	err = survey.AskOne(&survey.Select{
+		Message: "Board",
+		Options: firmware.Boards,
+		Default: board,
+		Help:    "the board determines pin names, moisture sensor calibration, and which pins can be used for each feature",
+	}, &config.Board)
+	if err != nil {
+		return fmt.Errorf("error completing board prompt: %w", err)
+	}
+
 	err = wateringPrompts(config)
 	if err != nil {
 		return fmt.Errorf("error completing watering prompts: %w", err)
@@ -41,6 +56,20 @@ func configPrompts(config *Config) error {
 		return fmt.Errorf("error completing moisture prompts: %w", err)
 	}
 
+	err = flowMeterPrompts(config)
+	if err != nil {
+		return fmt.Errorf("error completing flow meter prompts: %w", err)
+	}
+
+	err = survey.AskOne(&survey.Input{
+		Message: "Enable dosing pumps",
+		Default: fmt.Sprintf("%t", config.EnableDosing),
+		Help:    "enable injecting fertilizer while watering zones with a dosing pin",
+	}, &config.EnableDosing)
+	if err != nil {
+		return fmt.Errorf("error completing dosing prompt: %w", err)
+	}
+
 	err = temperatureHumidityPrompts(config)
 	if err != nil {
 		return fmt.Errorf("error completing temperature and humidity prompts: %w", err)
@@ -159,6 +188,33 @@ func moisturePrompts(config *Config) error {
 	return survey.Ask(qs, config)
 }
 
+func flowMeterPrompts(config *Config) error {
+	err := survey.AskOne(&survey.Input{
+		Message: "Enable flow meters",
+		Default: fmt.Sprintf("%t", config.EnableFlowMeter),
+		Help:    "enable measuring the volume of water delivered to zones with a flow meter pin",
+	}, &config.EnableFlowMeter)
+	if err != nil {
+		return err
+	}
+
+	if !config.EnableFlowMeter {
+		return nil
+	}
+
+	qs := []*survey.Question{
+		{
+			Name: "flow_meter_pulses_per_liter",
+			Prompt: &survey.Input{
+				Message: "Flow meter pulses per liter",
+				Default: fmt.Sprintf("%g", config.FlowMeterPulsesPerLiter),
+				Help:    "number of pulses the flow meter sends for each liter of water, found in the sensor's datasheet",
+			},
+		},
+	}
+	return survey.Ask(qs, config)
+}
+
 func temperatureHumidityPrompts(config *Config) error {
 	err := survey.AskOne(&survey.Input{
 		Message: "Enable temperature and humidity (DHT22) sensor",
@@ -219,9 +275,14 @@ func wateringPrompts(config *Config) error {
 }
 
 func zonePrompts(config *Config) error {
+	board, err := firmware.GetBoardProfile(config.Board)
+	if err != nil {
+		return err
+	}
+
 	addAnotherZone := true
 	for addAnotherZone {
-		err := survey.AskOne(&survey.Confirm{
+		err = survey.AskOne(&survey.Confirm{
 			Message: fmt.Sprintf("You currently have %d Zones configured. Would you like to add another?", len(config.Zones)),
 		}, &addAnotherZone)
 		if err != nil {
@@ -253,16 +314,32 @@ func zonePrompts(config *Config) error {
 				Name: "button_pin",
 				Prompt: &survey.Input{
 					Message: "\tButton pin",
-					Default: "GPIO_NUM_MAX",
-					Help:    "pin identifier for a button that controls this zone (GPIO_NUM_MAX to disable)",
+					Default: board.NoPin,
+					Help:    "pin identifier for a button that controls this zone (" + board.NoPin + " to disable)",
 				},
 			},
 			{
 				Name: "moisture_sensor_pin",
 				Prompt: &survey.Input{
 					Message: "\tMoisture sensor pin",
-					Default: "GPIO_NUM_MAX",
-					Help:    "pin identifier for a moisture sensor that corresponds to this zone (GPIO_NUM_MAX to disable)",
+					Default: board.NoPin,
+					Help:    "pin identifier for a moisture sensor that corresponds to this zone (" + board.NoPin + " to disable)",
+				},
+			},
+			{
+				Name: "flow_meter_pin",
+				Prompt: &survey.Input{
+					Message: "\tFlow meter pin",
+					Default: board.NoPin,
+					Help:    "pin identifier for a flow meter that measures water delivered to this zone (" + board.NoPin + " to disable)",
+				},
+			},
+			{
+				Name: "dosing_pin",
+				Prompt: &survey.Input{
+					Message: "\tDosing pin",
+					Default: board.NoPin,
+					Help:    "pin identifier for the relay controlling a fertilizer dosing pump for this zone (" + board.NoPin + " to disable)",
 				},
 			},
 		}
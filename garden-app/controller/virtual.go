@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// VirtualControllerConfig configures one or more virtual controllers that run in the same process. Any options
+// that are not set use the values from the main controller config
+type VirtualControllerConfig struct {
+	TopicPrefix      string `mapstructure:"topic_prefix"`
+	NumZones         *int   `mapstructure:"num_zones"`
+	MoistureStrategy string `mapstructure:"moisture_strategy"`
+	MoistureValue    *int   `mapstructure:"moisture_value"`
+	// Count creates this many controllers using TopicPrefix with a numbered suffix, like "garden-1" and "garden-2"
+	Count int `mapstructure:"count"`
+}
+
+var moistureStrategies = []string{"random", "constant", "increasing", "decreasing"}
+
+// VirtualConfigs creates a Config for each of the configured virtual controllers. The UI is disabled since multiple
+// controllers cannot share it, and the HTTP API is served by RunVirtualControllers instead of each controller
+func (cfg Config) VirtualConfigs() ([]Config, error) {
+	if len(cfg.Controllers) == 0 {
+		return nil, errors.New("no virtual controllers are configured")
+	}
+
+	var result []Config
+	topicPrefixes := map[string]struct{}{}
+	for i, vc := range cfg.Controllers {
+		if vc.TopicPrefix == "" {
+			return nil, fmt.Errorf("controllers[%d]: missing required topic_prefix", i)
+		}
+		if vc.Count < 0 {
+			return nil, fmt.Errorf("controllers[%d]: count must not be negative", i)
+		}
+		if vc.MoistureStrategy != "" && !slices.Contains(moistureStrategies, vc.MoistureStrategy) {
+			return nil, fmt.Errorf("controllers[%d]: invalid moisture_strategy %q", i, vc.MoistureStrategy)
+		}
+
+		for _, topicPrefix := range vc.topicPrefixes() {
+			if _, ok := topicPrefixes[topicPrefix]; ok {
+				return nil, fmt.Errorf("controllers[%d]: duplicate topic_prefix %q", i, topicPrefix)
+			}
+			topicPrefixes[topicPrefix] = struct{}{}
+
+			result = append(result, vc.apply(cfg, topicPrefix))
+		}
+	}
+
+	return result, nil
+}
+
+// topicPrefixes returns the TopicPrefix for each controller created by the config
+func (vc VirtualControllerConfig) topicPrefixes() []string {
+	if vc.Count == 0 {
+		return []string{vc.TopicPrefix}
+	}
+
+	result := make([]string, vc.Count)
+	for i := range result {
+		result[i] = fmt.Sprintf("%s-%d", vc.TopicPrefix, i+1)
+	}
+	return result
+}
+
+// apply overrides the base Config with the virtual controller's options
+func (vc VirtualControllerConfig) apply(base Config, topicPrefix string) Config {
+	result := base
+	result.Controllers = nil
+	result.EnableUI = false
+	result.HTTPAddress = ""
+	result.TopicPrefix = topicPrefix
+
+	if vc.NumZones != nil {
+		result.NumZones = *vc.NumZones
+	}
+	if vc.MoistureStrategy != "" {
+		result.MoistureStrategy = vc.MoistureStrategy
+	}
+	if vc.MoistureValue != nil {
+		result.MoistureValue = *vc.MoistureValue
+	}
+
+	return result
+}
+
+// RunVirtualControllers creates and starts a Controller for each of the configured virtual controllers and blocks
+// until they are all stopped
+func RunVirtualControllers(cfg Config) error {
+	configs, err := cfg.VirtualConfigs()
+	if err != nil {
+		return err
+	}
+
+	controllers := make([]*Controller, 0, len(configs))
+	for _, c := range configs {
+		controller, err := NewController(c)
+		if err != nil {
+			for _, started := range controllers {
+				started.mqttClient.Disconnect(1000)
+			}
+			return fmt.Errorf("error creating Controller %q: %w", c.TopicPrefix, err)
+		}
+		controllers = append(controllers, controller)
+	}
+
+	// Serve the HTTP API for all controllers using their TopicPrefix as a path prefix
+	if cfg.HTTPAddress != "" {
+		mux := http.NewServeMux()
+		for _, controller := range controllers {
+			prefix := "/" + controller.TopicPrefix
+			mux.Handle(prefix+"/", http.StripPrefix(prefix, controller.HTTPHandler()))
+		}
+		shutdownHTTP := startHTTPServer(cfg.HTTPAddress, mux, cfg.LogConfig.NewLogger())
+		defer shutdownHTTP()
+	}
+
+	wg := &sync.WaitGroup{}
+	for _, controller := range controllers {
+		wg.Add(1)
+		go func(c *Controller) {
+			defer wg.Done()
+			c.Start()
+		}(controller)
+	}
+	wg.Wait()
+
+	return nil
+}
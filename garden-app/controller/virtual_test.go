@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualConfigs(t *testing.T) {
+	five := 5
+	zero := 0
+	base := Config{NestedConfig: NestedConfig{
+		EnableUI:         true,
+		TopicPrefix:      "test-garden",
+		NumZones:         1,
+		MoistureStrategy: "random",
+		MoistureValue:    100,
+	}}
+
+	tests := []struct {
+		name        string
+		controllers []VirtualControllerConfig
+		expected    []NestedConfig
+		expectedErr string
+	}{
+		{
+			"Overrides",
+			[]VirtualControllerConfig{
+				{TopicPrefix: "garden-a", NumZones: &five, MoistureStrategy: "decreasing", MoistureValue: &zero},
+				{TopicPrefix: "garden-b"},
+			},
+			[]NestedConfig{
+				{TopicPrefix: "garden-a", NumZones: 5, MoistureStrategy: "decreasing", MoistureValue: 0},
+				{TopicPrefix: "garden-b", NumZones: 1, MoistureStrategy: "random", MoistureValue: 100},
+			},
+			"",
+		},
+		{
+			"Count",
+			[]VirtualControllerConfig{{TopicPrefix: "garden", Count: 2}},
+			[]NestedConfig{
+				{TopicPrefix: "garden-1", NumZones: 1, MoistureStrategy: "random", MoistureValue: 100},
+				{TopicPrefix: "garden-2", NumZones: 1, MoistureStrategy: "random", MoistureValue: 100},
+			},
+			"",
+		},
+		{
+			"ErrorNoControllers",
+			nil,
+			nil,
+			"no virtual controllers are configured",
+		},
+		{
+			"ErrorMissingTopicPrefix",
+			[]VirtualControllerConfig{{NumZones: &five}},
+			nil,
+			"controllers[0]: missing required topic_prefix",
+		},
+		{
+			"ErrorInvalidMoistureStrategy",
+			[]VirtualControllerConfig{{TopicPrefix: "garden", MoistureStrategy: "sideways"}},
+			nil,
+			`controllers[0]: invalid moisture_strategy "sideways"`,
+		},
+		{
+			"ErrorDuplicateTopicPrefix",
+			[]VirtualControllerConfig{{TopicPrefix: "garden", Count: 2}, {TopicPrefix: "garden-2"}},
+			nil,
+			`controllers[1]: duplicate topic_prefix "garden-2"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			cfg.Controllers = tt.controllers
+
+			configs, err := cfg.VirtualConfigs()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			actual := []NestedConfig{}
+			for _, c := range configs {
+				assert.False(t, c.EnableUI)
+				assert.Nil(t, c.Controllers)
+				actual = append(actual, c.NestedConfig)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+)
+
+// waterQueueSize is the number of WaterMessages that can wait while another Zone is watering
+const waterQueueSize = 100
+
+// queuedWater is a WaterMessage waiting to be watered and the topic it was received on
+type queuedWater struct {
+	msg   action.WaterMessage
+	topic string
+}
+
+// initWaterQueue creates the channels used to emulate watering
+func (c *Controller) initWaterQueue() {
+	c.waterQueue = make(chan queuedWater, waterQueueSize)
+	c.stopWater = make(chan struct{}, 1)
+}
+
+// runWaterQueue waters each queued WaterMessage one at a time, the same as the garden-controller, until the context
+// is done
+func (c *Controller) runWaterQueue(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case qw := <-c.waterQueue:
+			// Ignore stops that were received before this watering started
+			select {
+			case <-c.stopWater:
+			default:
+			}
+			c.water(ctx, qw)
+		}
+	}
+}
+
+// water waits for the WaterMessage's duration, or until it is stopped, and then publishes the water event with the
+// time that it actually watered
+func (c *Controller) water(ctx context.Context, qw queuedWater) {
+	logger := c.subLogger.With("zone_position", qw.msg.Position, "duration", qw.msg.Duration)
+	logger.Info("watering Zone")
+
+	start := time.Now()
+	timer := time.NewTimer(time.Duration(qw.msg.Duration) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-c.stopWater:
+		logger.Info("watering stopped early")
+	case <-ctx.Done():
+		logger.Info("watering stopped by shutdown")
+	}
+
+	qw.msg.Duration = time.Since(start).Milliseconds()
+	c.publishWaterEvent(qw.msg, qw.topic)
+}
+
+// queueWater adds the WaterMessage to the queue of Zones to water
+func (c *Controller) queueWater(msg action.WaterMessage, topic string) {
+	select {
+	case c.waterQueue <- queuedWater{msg, topic}:
+	default:
+		c.subLogger.Error("unable to queue WaterAction because the queue is full", "zone_position", msg.Position)
+	}
+}
+
+// stopWatering interrupts the current watering. The next Zone in the queue will start watering
+func (c *Controller) stopWatering() {
+	select {
+	case c.stopWater <- struct{}{}:
+	default:
+	}
+}
+
+// stopAllWatering clears the queue and interrupts the current watering
+func (c *Controller) stopAllWatering() {
+	for cleared := false; !cleared; {
+		select {
+		case <-c.waterQueue:
+		default:
+			cleared = true
+		}
+	}
+	c.stopWatering()
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var waterEventMillis = regexp.MustCompile(`^water,zone=(\d+) millis=(\d+)$`)
+
+func TestSimulateWatering(t *testing.T) {
+	setup := func(t *testing.T) (*Controller, chan string) {
+		t.Helper()
+
+		published := make(chan string, 10)
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("Publish", "test-garden/data/water", mock.Anything).
+			Run(func(args mock.Arguments) { published <- string(args.Get(1).([]byte)) }).
+			Return(nil)
+
+		c := &Controller{
+			Config: Config{NestedConfig: NestedConfig{
+				TopicPrefix:       "test-garden",
+				PublishWaterEvent: true,
+				SimulateWatering:  true,
+			}},
+			mqttClient: mqttClient,
+			logger:     slog.Default(),
+			pubLogger:  slog.Default(),
+			subLogger:  slog.Default(),
+		}
+		c.initWaterQueue()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go c.runWaterQueue(ctx)
+
+		return c, published
+	}
+
+	// waitForWaterEvent returns the zone and millis from the next published water event
+	waitForWaterEvent := func(t *testing.T, published chan string) (int, int64) {
+		t.Helper()
+
+		select {
+		case msg := <-published:
+			matches := waterEventMillis.FindStringSubmatch(msg)
+			require.Len(t, matches, 3, msg)
+			zone, _ := strconv.Atoi(matches[1])
+			millis, _ := strconv.ParseInt(matches[2], 10, 64)
+			return zone, millis
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for water event")
+			return 0, 0
+		}
+	}
+
+	t.Run("CompletesWatering", func(t *testing.T) {
+		c, published := setup(t)
+
+		c.queueWater(action.WaterMessage{Position: 0, Duration: 10}, "test-garden/command/water")
+		zone, millis := waitForWaterEvent(t, published)
+		assert.Equal(t, 0, zone)
+		assert.GreaterOrEqual(t, millis, int64(10))
+	})
+
+	t.Run("StopStartsNextZone", func(t *testing.T) {
+		c, published := setup(t)
+
+		c.queueWater(action.WaterMessage{Position: 0, Duration: time.Hour.Milliseconds()}, "test-garden/command/water")
+		c.queueWater(action.WaterMessage{Position: 1, Duration: 10}, "test-garden/command/water")
+		time.Sleep(10 * time.Millisecond)
+		c.stopWatering()
+
+		zone, millis := waitForWaterEvent(t, published)
+		assert.Equal(t, 0, zone)
+		assert.Less(t, millis, time.Hour.Milliseconds())
+
+		zone, _ = waitForWaterEvent(t, published)
+		assert.Equal(t, 1, zone)
+	})
+
+	t.Run("StopAllClearsQueue", func(t *testing.T) {
+		c, published := setup(t)
+
+		c.queueWater(action.WaterMessage{Position: 0, Duration: time.Hour.Milliseconds()}, "test-garden/command/water")
+		c.queueWater(action.WaterMessage{Position: 1, Duration: 10}, "test-garden/command/water")
+		time.Sleep(10 * time.Millisecond)
+		c.stopAllWatering()
+
+		zone, millis := waitForWaterEvent(t, published)
+		assert.Equal(t, 0, zone)
+		assert.Less(t, millis, time.Hour.Milliseconds())
+
+		select {
+		case msg := <-published:
+			t.Fatalf("unexpected water event after StopAllAction: %s", msg)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	// Embed the time zone database since Garden time zones cannot rely on it being installed, like in the container
+	_ "time/tzdata"
+
 	"github.com/calvinmclean/automated-garden/garden-app/cmd"
 )
 
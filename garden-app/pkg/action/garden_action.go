@@ -11,19 +11,20 @@ import (
 // GardenAction collects all the possible actions for a Garden into a single struct so these can easily be
 // received as one request
 type GardenAction struct {
-	Light *LightAction `json:"light" form:"light"`
-	Stop  *StopAction  `json:"stop" form:"stop"`
+	Light   *LightAction   `json:"light" form:"light"`
+	Stop    *StopAction    `json:"stop" form:"stop"`
+	StopAll *StopAllAction `json:"stop_all" form:"stop_all"`
 }
 
 // String...
 func (action *GardenAction) String() string {
-	return fmt.Sprintf("{LightAction: %+v, StopAction: %+v}", action.Light, action.Stop)
+	return fmt.Sprintf("{LightAction: %+v, StopAction: %+v, StopAllAction: %+v}", action.Light, action.Stop, action.StopAll)
 }
 
 // Bind is used to make this struct compatible with our REST API implemented with go-chi.
 // It will verify that the request is valid
 func (action *GardenAction) Bind(_ *http.Request) error {
-	if action == nil || (action.Light == nil && action.Stop == nil) {
+	if action == nil || (action.Light == nil && action.Stop == nil && action.StopAll == nil) {
 		return errors.New("missing required action fields")
 	}
 
@@ -42,8 +43,43 @@ type LightAction struct {
 	ForDuration *pkg.Duration  `json:"for_duration" form:"for_duration"`
 }
 
+// RecirculationAction turns a hydroponic Garden's recirculation pump on or off. It is sent by the Jobs for the
+// Garden's RecirculationSchedule
+type RecirculationAction struct {
+	State pkg.LightState `json:"state"`
+}
+
 // StopAction is an action for stopping watering of a Zone. It doesn't stop watering a specific Zone, only what is
 // currently watering and optionally clearing the queue of Zones to water.
 type StopAction struct {
 	All bool `json:"all" form:"all"`
 }
+
+// StopAllAction stops the current watering and clears the controller's queue. Queued and deferred WaterActions for
+// the Garden's Zones are also cancelled. It is the same as a StopAction with All set
+type StopAllAction struct{}
+
+// ConfigMessage is published to a Garden's controller when its Zones or pins change so the new config is applied
+// without reflashing the firmware. DefaultWaterTime is milliseconds and the controller keeps its current value when it
+// is 0
+type ConfigMessage struct {
+	NumZones         uint  `json:"num_zones"`
+	DefaultWaterTime int64 `json:"default_water_time,omitempty"`
+}
+
+// FirmwareUpdateMessage is published to a Garden's controller to have it download firmware from URL and install it
+// if the SHA-256 Checksum matches. The controller reports the result by publishing a FirmwareStatusMessage
+type FirmwareUpdateMessage struct {
+	FirmwareID string `json:"firmware_id"`
+	Version    string `json:"version"`
+	URL        string `json:"url"`
+	Checksum   string `json:"checksum"`
+}
+
+// FirmwareStatusMessage is published by a controller on "{prefix}/ack/firmware_update" after it receives a
+// FirmwareUpdateMessage. Status is "updated" or "failed" and Error explains why the update failed
+type FirmwareStatusMessage struct {
+	FirmwareID string                   `json:"firmware_id"`
+	Status     pkg.FirmwareUpdateStatus `json:"status"`
+	Error      string                   `json:"error,omitempty"`
+}
@@ -11,36 +11,74 @@ import (
 // ZoneAction collects all the possible actions for a Zone into a single struct so these can easily be
 // received as one request
 type ZoneAction struct {
-	Water *WaterAction `json:"water" form:"water"`
+	Water *WaterAction    `json:"water" form:"water"`
+	Stop  *ZoneStopAction `json:"stop" form:"stop"`
 }
 
 // String...
 func (action *ZoneAction) String() string {
-	return fmt.Sprintf("%+v", *action.Water)
+	return fmt.Sprintf("{WaterAction: %+v, StopAction: %+v}", action.Water, action.Stop)
 }
 
 // Bind is used to make this struct compatible with our REST API implemented with go-chi.
 // It will verify that the request is valid
 func (action *ZoneAction) Bind(*http.Request) error {
-	if action == nil || action.Water == nil {
+	if action == nil || (action.Water == nil && action.Stop == nil) {
 		return errors.New("missing required action fields")
 	}
+	if action.Water != nil && action.Stop != nil {
+		return errors.New("only one of water and stop can be used")
+	}
+	if action.Water != nil && action.Water.Duration != nil && action.Water.Duration.Duration < 0 {
+		return errors.New("duration must not be negative")
+	}
+	if action.Water != nil && action.Water.Fertilizer != nil && action.Water.Fertilizer.Duration < 0 {
+		return errors.New("fertilizer must not be negative")
+	}
+	if action.Water != nil && action.Water.Cycles != nil {
+		if action.Water.Duration != nil {
+			return errors.New("only one of duration and cycles can be used")
+		}
+		err := action.Water.Cycles.Validate()
+		if err != nil {
+			return fmt.Errorf("error validating cycles: %w", err)
+		}
+	}
 
 	return nil
 }
 
-// WaterAction is an action for watering a Zone for the specified amount of time
+// WaterAction is an action for watering a Zone for the specified amount of time. If Duration is not set, the
+// Zone's next WaterSchedule's duration is used. Cycles can be used instead of Duration to water in pulses.
+// Fertilizer is how long the Zone's dosing pump runs while watering. DryRun will calculate the watering without
+// sending it. IgnoreBudget allows watering even if it exceeds the Garden's WaterBudget. ID identifies the
+// WaterAction's ActionRecord and is set by the server instead of the request
 type WaterAction struct {
-	Duration       *pkg.Duration `json:"duration" form:"duration"`
-	IgnoreMoisture bool          `json:"ignore_moisture"`
-	IgnoreWeather  bool          `json:"ignore_weather"`
+	ID             string           `json:"-"`
+	Duration       *pkg.Duration    `json:"duration" form:"duration"`
+	Cycles         *pkg.WaterCycles `json:"cycles,omitempty"`
+	Fertilizer     *pkg.Duration    `json:"fertilizer,omitempty"`
+	IgnoreMoisture bool             `json:"ignore_moisture"`
+	IgnoreWeather  bool             `json:"ignore_weather"`
+	IgnoreBudget   bool             `json:"ignore_budget"`
+	DryRun         bool             `json:"dry_run"`
 }
 
-// WaterMessage is the message being sent over MQTT to the embedded garden controller
+// ZoneStopAction stops watering a Zone and cancels its queued and deferred WaterActions. The controller is only
+// told to stop if the Zone is currently watering
+type ZoneStopAction struct{}
+
+// WaterMessage is the message being sent over MQTT to the embedded garden controller. Fertilizer is the number of
+// milliseconds to run the Zone's dosing pump at the start of watering. TraceContext has the W3C trace context headers
+// when tracing is enabled so the controller can continue the trace. CommandID is only set when acknowledgments are
+// enabled and the controller publishes it back to confirm that it received the message
 type WaterMessage struct {
-	Duration int64  `json:"duration"`
-	ZoneID   string `json:"id"`
-	Position uint   `json:"position"`
+	Duration     int64             `json:"duration"`
+	ZoneID       string            `json:"id"`
+	Position     uint              `json:"position"`
+	Fertilizer   int64             `json:"fertilizer,omitempty"`
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+	CommandID    string            `json:"command_id,omitempty"`
 }
 
 // String...
@@ -3,6 +3,9 @@ package action
 import (
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 )
 
 func TestZoneAction(t *testing.T) {
@@ -21,6 +24,26 @@ func TestZoneAction(t *testing.T) {
 			&ZoneAction{},
 			"missing required action fields",
 		},
+		{
+			"WaterAndStopError",
+			&ZoneAction{Water: &WaterAction{}, Stop: &ZoneStopAction{}},
+			"only one of water and stop can be used",
+		},
+		{
+			"DurationAndCyclesError",
+			&ZoneAction{Water: &WaterAction{
+				Duration: &pkg.Duration{Duration: time.Minute},
+				Cycles:   &pkg.WaterCycles{Count: 3, Water: &pkg.Duration{Duration: time.Minute}, Soak: &pkg.Duration{}},
+			}},
+			"only one of duration and cycles can be used",
+		},
+		{
+			"InvalidCyclesError",
+			&ZoneAction{Water: &WaterAction{
+				Cycles: &pkg.WaterCycles{Count: 1, Water: &pkg.Duration{Duration: time.Minute}, Soak: &pkg.Duration{}},
+			}},
+			"error validating cycles: count must be at least 2",
+		},
 	}
 
 	t.Run("Successful", func(t *testing.T) {
@@ -33,6 +56,16 @@ func TestZoneAction(t *testing.T) {
 			t.Errorf("Unexpected error reading ZoneActionRequest JSON: %v", err)
 		}
 	})
+	t.Run("SuccessfulStop", func(t *testing.T) {
+		ar := &ZoneAction{
+			Stop: &ZoneStopAction{},
+		}
+		r := httptest.NewRequest("", "/", nil)
+		err := ar.Bind(r)
+		if err != nil {
+			t.Errorf("Unexpected error reading ZoneActionRequest JSON: %v", err)
+		}
+	})
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := httptest.NewRequest("", "/", nil)
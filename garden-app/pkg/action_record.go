@@ -0,0 +1,57 @@
+package pkg
+
+import "time"
+
+// ActionStatus is a step in the lifecycle of an ActionRecord
+type ActionStatus string
+
+const (
+	// ActionStatusQueued is used when a WaterAction is waiting for another Zone to finish because of the Garden's
+	// MaxConcurrentZones
+	ActionStatusQueued ActionStatus = "queued"
+	// ActionStatusPublished is used when the command was sent to the controller
+	ActionStatusPublished ActionStatus = "published"
+	// ActionStatusAcknowledged is used when the controller confirmed that it received the command. This is only
+	// used when water command acknowledgments are enabled
+	ActionStatusAcknowledged ActionStatus = "acknowledged"
+	// ActionStatusCompleted is used when the controller published that the Zone finished watering
+	ActionStatusCompleted ActionStatus = "completed"
+	// ActionStatusSkipped is used when the WaterAction was not executed, like when weather control skips it
+	ActionStatusSkipped ActionStatus = "skipped"
+	// ActionStatusCancelled is used when a queued WaterAction was cancelled by a StopAction
+	ActionStatusCancelled ActionStatus = "cancelled"
+	// ActionStatusFailed is used when the command could not be published or was never acknowledged
+	ActionStatusFailed ActionStatus = "failed"
+)
+
+// ActionRecord keeps track of a WaterAction from when it is requested until the controller publishes that it is
+// complete. Status is the latest step and History has every step in order. Message explains why an action was
+// skipped or failed
+type ActionRecord struct {
+	ID        string                `json:"id"`
+	Type      string                `json:"type"`
+	GardenID  string                `json:"garden_id"`
+	ZoneID    string                `json:"zone_id"`
+	Duration  *Duration             `json:"duration,omitempty"`
+	Status    ActionStatus          `json:"status"`
+	Message   string                `json:"message,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	History   []ActionStatusHistory `json:"history"`
+}
+
+// ActionStatusHistory is when an ActionRecord changed to the Status
+type ActionStatusHistory struct {
+	Status ActionStatus `json:"status"`
+	Time   time.Time    `json:"time"`
+}
+
+// SetStatus changes the Status and adds it to the History
+func (r *ActionRecord) SetStatus(status ActionStatus, message string, now time.Time) {
+	r.Status = status
+	if message != "" {
+		r.Message = message
+	}
+	r.UpdatedAt = now
+	r.History = append(r.History, ActionStatusHistory{Status: status, Time: now})
+}
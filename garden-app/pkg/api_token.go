@@ -0,0 +1,154 @@
+package pkg
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/babyapi"
+)
+
+// APITokenScope limits which requests can be made using an APIToken
+type APITokenScope string
+
+const (
+	// APITokenScopeRead allows GET requests
+	APITokenScopeRead APITokenScope = "read"
+	// APITokenScopeWrite allows creating, updating, and deleting resources
+	APITokenScopeWrite APITokenScope = "write"
+	// APITokenScopeActions allows sending actions to Gardens and Zones and testing NotificationClients
+	APITokenScopeActions APITokenScope = "actions"
+	// APITokenScopeAdmin allows managing APITokens and includes all other scopes
+	APITokenScopeAdmin APITokenScope = "admin"
+)
+
+// ValidateAPITokenScopes makes sure there is at least one scope and all scopes are valid
+func ValidateAPITokenScopes(scopes []APITokenScope) error {
+	if len(scopes) == 0 {
+		return validation.Missing("scopes")
+	}
+	for _, s := range scopes {
+		switch s {
+		case APITokenScopeRead, APITokenScopeWrite, APITokenScopeActions, APITokenScopeAdmin:
+		default:
+			return fmt.Errorf("invalid scope %q", s)
+		}
+	}
+	return nil
+}
+
+// HasAPITokenScope returns true if the scopes include the scope or the admin scope
+func HasAPITokenScope(scopes []APITokenScope, scope APITokenScope) bool {
+	for _, s := range scopes {
+		if s == scope || s == APITokenScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// HashAPIToken returns the hex-encoded SHA-256 of the token. Only the hash is stored so tokens cannot be read
+// back from storage
+func HashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// APIToken allows authenticating requests to the API. The token is generated when the APIToken is created and is
+// only shown in that response
+type APIToken struct {
+	ID        babyapi.ID      `json:"id" yaml:"id"`
+	Name      string          `json:"name" yaml:"name"`
+	Scopes    []APITokenScope `json:"scopes" yaml:"scopes"`
+	TokenHash string          `json:"token_hash,omitempty" yaml:"token_hash,omitempty"`
+	// UserID makes requests using this APIToken act as the User, so they are limited by the User's roles
+	UserID string `json:"user_id,omitempty" yaml:"user_id,omitempty"`
+
+	token string
+}
+
+// GenerateToken creates a new random token and stores its hash
+func (t *APIToken) GenerateToken() error {
+	data := make([]byte, 32)
+	_, err := rand.Read(data)
+	if err != nil {
+		return fmt.Errorf("error generating token: %w", err)
+	}
+
+	t.token = hex.EncodeToString(data)
+	t.TokenHash = HashAPIToken(t.token)
+	return nil
+}
+
+// Token returns the token if it was just generated. Otherwise, it is empty
+func (t *APIToken) Token() string {
+	return t.token
+}
+
+// HasScope returns true if the APIToken has the scope or the admin scope
+func (t *APIToken) HasScope(scope APITokenScope) bool {
+	return HasAPITokenScope(t.Scopes, scope)
+}
+
+func (t *APIToken) GetID() string {
+	return t.ID.String()
+}
+
+func (t *APIToken) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// Bind is used to make this struct compatible with the go-chi webserver for reading incoming
+// JSON requests
+func (t *APIToken) Bind(r *http.Request) error {
+	if t == nil {
+		return errors.New("missing required APIToken fields")
+	}
+
+	err := t.ID.Bind(r)
+	if err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		return errors.New("APITokens cannot be replaced, use PATCH to update the name or scopes")
+	case http.MethodPost:
+		if t.Name == "" {
+			return validation.Missing("name")
+		}
+		return ValidateAPITokenScopes(t.Scopes)
+	case http.MethodPatch:
+		if t.Scopes != nil {
+			return ValidateAPITokenScopes(t.Scopes)
+		}
+	}
+
+	return nil
+}
+
+// Patch allows modifying the name, scopes, and User. The token cannot be changed, so a new APIToken must be created instead
+func (t *APIToken) Patch(newToken *APIToken) *babyapi.ErrResponse {
+	if newToken.Name != "" {
+		t.Name = newToken.Name
+	}
+	if newToken.Scopes != nil {
+		t.Scopes = newToken.Scopes
+	}
+	if newToken.UserID != "" {
+		t.UserID = newToken.UserID
+	}
+	return nil
+}
+
+// EndDated allows this to satisfy an interface even though the resources does not have end-dates
+func (*APIToken) EndDated() bool {
+	return false
+}
+
+func (*APIToken) SetEndDate(_ time.Time) {}
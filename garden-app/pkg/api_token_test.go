@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokenGenerateToken(t *testing.T) {
+	token := &APIToken{}
+	require.NoError(t, token.GenerateToken())
+
+	assert.Len(t, token.Token(), 64)
+	assert.Equal(t, HashAPIToken(token.Token()), token.TokenHash)
+	assert.NotEqual(t, token.Token(), token.TokenHash)
+
+	other := &APIToken{}
+	require.NoError(t, other.GenerateToken())
+	assert.NotEqual(t, token.Token(), other.Token())
+}
+
+func TestHasAPITokenScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []APITokenScope
+		scope    APITokenScope
+		expected bool
+	}{
+		{"HasScope", []APITokenScope{APITokenScopeRead}, APITokenScopeRead, true},
+		{"MissingScope", []APITokenScope{APITokenScopeRead}, APITokenScopeWrite, false},
+		{"AdminHasAllScopes", []APITokenScope{APITokenScopeAdmin}, APITokenScopeActions, true},
+		{"NoScopes", nil, APITokenScopeRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, HasAPITokenScope(tt.scopes, tt.scope))
+		})
+	}
+}
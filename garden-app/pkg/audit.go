@@ -0,0 +1,28 @@
+package pkg
+
+import "time"
+
+const (
+	// AuditSourceAPI is used for changes and actions requested with the HTTP API
+	AuditSourceAPI = "api"
+	// AuditSourceGRPC is used for actions requested with the gRPC API
+	AuditSourceGRPC = "grpc"
+	// AuditSourceScheduler is used for actions executed by scheduled jobs
+	AuditSourceScheduler = "scheduler"
+)
+
+// AuditEntry records an action that was executed or a resource that was changed, and what caused it. Actions
+// are "water_action", "light_action", and "stop_action". Changes are "created", "updated", "deleted", and "restored"
+type AuditEntry struct {
+	Timestamp    time.Time `json:"timestamp"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	Action       string    `json:"action"`
+	// Source is "api", "grpc", or "scheduler"
+	Source string `json:"source"`
+	// Actor is the name of the API token or user that made the request, if authentication is enabled
+	Actor      string `json:"actor,omitempty"`
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	// Details has additional information like the duration of a WaterAction or the WaterSchedule that scheduled it
+	Details map[string]string `json:"details,omitempty"`
+}
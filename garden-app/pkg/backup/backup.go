@@ -0,0 +1,173 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/photos/disk"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/photos/s3"
+)
+
+const (
+	// DefaultInterval is used when the Config does not set an Interval
+	DefaultInterval = 24 * time.Hour
+
+	keyPrefix     = "backup-"
+	keySuffix     = ".json"
+	keyTimeFormat = "20060102T150405Z"
+)
+
+// ErrNotFound is returned by a Store when there is no backup with the requested key
+var ErrNotFound = errors.New("backup not found")
+
+// Store is where backups are saved. It uses the same disk and S3 drivers as photos
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context) ([]string, error)
+}
+
+// Config is used to configure scheduled backups of all storage and where they are saved
+type Config struct {
+	Driver  string                 `mapstructure:"driver"`
+	Options map[string]interface{} `mapstructure:"options"`
+	// Interval is how often a backup is created. It defaults to 24 hours
+	Interval time.Duration `mapstructure:"interval"`
+	// KeepLast is the number of the most recent backups to keep. All backups are kept if it is 0
+	KeepLast int `mapstructure:"keep_last"`
+	// MaxAge removes backups that are older than this. Backups are not removed by age if it is 0
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// Enabled returns true if a driver is configured
+func (c Config) Enabled() bool {
+	return c.Driver != ""
+}
+
+// Validate checks that the durations and retention are not negative
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if c.KeepLast < 0 {
+		return errors.New("keep_last must not be negative")
+	}
+	if c.MaxAge < 0 {
+		return errors.New("max_age must not be negative")
+	}
+	return nil
+}
+
+// IntervalOrDefault returns the Interval or DefaultInterval if it is not set
+func (c Config) IntervalOrDefault() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return DefaultInterval
+}
+
+// NewStore will use the config to create and return the correct type of Store
+func NewStore(c Config) (Store, error) {
+	var (
+		store Store
+		err   error
+	)
+	switch c.Driver {
+	case "disk":
+		store, err = disk.NewStore(c.Options)
+	case "s3":
+		store, err = s3.NewStore(c.Options)
+	default:
+		err = fmt.Errorf("invalid driver '%s'", c.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &notFoundWrapper{store}, nil
+}
+
+// notFoundWrapper converts each driver's not found error into ErrNotFound
+type notFoundWrapper struct {
+	Store
+}
+
+func (s *notFoundWrapper) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := s.Store.Get(ctx, key)
+	if errors.Is(err, disk.ErrNotFound) || errors.Is(err, s3.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return body, err
+}
+
+// Key returns the name of a backup created at the time, like "backup-20230101T080000Z.json"
+func Key(t time.Time) string {
+	return keyPrefix + t.UTC().Format(keyTimeFormat) + keySuffix
+}
+
+// ParseKey returns the time that a backup was created from its key. It returns false if the key is not a backup, so
+// other files in the same directory or bucket are ignored
+func ParseKey(key string) (time.Time, bool) {
+	timestamp, ok := strings.CutPrefix(key, keyPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	timestamp, ok = strings.CutSuffix(timestamp, keySuffix)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(keyTimeFormat, timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Info describes a saved backup
+type Info struct {
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// List returns the backups in the Store, starting with the most recent
+func List(ctx context.Context, store Store) ([]Info, error) {
+	keys, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing backups: %w", err)
+	}
+
+	result := []Info{}
+	for _, key := range keys {
+		createdAt, ok := ParseKey(key)
+		if !ok {
+			continue
+		}
+		result = append(result, Info{Key: key, CreatedAt: createdAt})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// Expired returns the backups that should be removed because they are past the KeepLast most recent backups or
+// older than MaxAge. The backups must be sorted with the most recent first, like the result of List
+func (c Config) Expired(backups []Info, now time.Time) []Info {
+	result := []Info{}
+	for i, b := range backups {
+		tooMany := c.KeepLast > 0 && i >= c.KeepLast
+		tooOld := c.MaxAge > 0 && now.Sub(b.CreatedAt) > c.MaxAge
+		if tooMany || tooOld {
+			result = append(result, b)
+		}
+	}
+	return result
+}
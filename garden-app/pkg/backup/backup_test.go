@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	createdAt := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.FixedZone("MST", -7*60*60))
+
+	key := Key(createdAt)
+	assert.Equal(t, "backup-20230101T150000Z.json", key)
+
+	parsed, ok := ParseKey(key)
+	assert.True(t, ok)
+	assert.True(t, createdAt.Equal(parsed))
+
+	for _, key := range []string{"photo.png", "backup-latest.json", "backup-20230101T150000Z.yaml"} {
+		t.Run("Invalid_"+key, func(t *testing.T) {
+			_, ok := ParseKey(key)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewStore(Config{
+		Driver:  "disk",
+		Options: map[string]interface{}{"directory": t.TempDir()},
+	})
+	require.NoError(t, err)
+
+	first := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	for _, key := range []string{Key(first), Key(second), "notes.txt"} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("{}"), 2, "application/json"))
+	}
+
+	backups, err := List(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, []Info{
+		{Key: Key(second), CreatedAt: second},
+		{Key: Key(first), CreatedAt: first},
+	}, backups)
+
+	_, err = store.Get(ctx, Key(first.Add(time.Hour)))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2023, time.January, 10, 8, 0, 0, 0, time.UTC)
+	backups := []Info{}
+	for i := 0; i < 5; i++ {
+		createdAt := now.Add(-time.Duration(i) * 24 * time.Hour)
+		backups = append(backups, Info{Key: Key(createdAt), CreatedAt: createdAt})
+	}
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected []Info
+	}{
+		{"NoRetention", Config{}, []Info{}},
+		{"KeepLast", Config{KeepLast: 3}, backups[3:]},
+		{"MaxAge", Config{MaxAge: 36 * time.Hour}, backups[2:]},
+		{"KeepLastAndMaxAge", Config{KeepLast: 4, MaxAge: 60 * time.Hour}, backups[3:]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.cfg.Expired(backups, now))
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{Driver: "disk", KeepLast: 7}.Validate())
+	assert.EqualError(t, Config{Driver: "disk", KeepLast: -1}.Validate(), "keep_last must not be negative")
+	assert.EqualError(t, Config{Driver: "disk", MaxAge: -time.Hour}.Validate(), "max_age must not be negative")
+}
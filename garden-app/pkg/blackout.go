@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+)
+
+const blackoutTimeFormat = "15:04"
+
+// maxBlackoutDeferral limits how far watering can be deferred when BlackoutWindows cover all times
+const maxBlackoutDeferral = 7 * 24 * time.Hour
+
+// BlackoutWindow is a recurring range of time when no WaterActions are published, like "10:00" to "16:00" during
+// the hottest part of the day. An EndTime before the StartTime ends on the next day, and equal times cover the
+// whole day. Days limits the window to days of the week that it starts on, and it is every day if empty
+type BlackoutWindow struct {
+	Name      string   `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	StartTime string   `json:"start_time" yaml:"start_time" mapstructure:"start_time"`
+	EndTime   string   `json:"end_time" yaml:"end_time" mapstructure:"end_time"`
+	Days      []string `json:"days,omitempty" yaml:"days,omitempty" mapstructure:"days"`
+}
+
+// Validate makes sure the times use the "15:04" format and Days are names of weekdays
+func (bw BlackoutWindow) Validate() error {
+	if bw.StartTime == "" {
+		return validation.Missing("start_time")
+	}
+	if bw.EndTime == "" {
+		return validation.Missing("end_time")
+	}
+	for _, t := range []string{bw.StartTime, bw.EndTime} {
+		_, err := time.Parse(blackoutTimeFormat, t)
+		if err != nil {
+			return fmt.Errorf("invalid time %q: must use HH:MM format", t)
+		}
+	}
+	for _, day := range bw.Days {
+		_, err := parseWeekday(day)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// occurrence returns the start and end of the window that starts on the same day as t, in t's location. It
+// returns false if the window is not used on that day
+func (bw BlackoutWindow) occurrence(t time.Time) (time.Time, time.Time, bool) {
+	if len(bw.Days) > 0 {
+		used := false
+		for _, day := range bw.Days {
+			weekday, err := parseWeekday(day)
+			if err == nil && weekday == t.Weekday() {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	startTime, err := time.Parse(blackoutTimeFormat, bw.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	endTime, err := time.Parse(blackoutTimeFormat, bw.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start := time.Date(t.Year(), t.Month(), t.Day(), startTime.Hour(), startTime.Minute(), 0, 0, t.Location())
+	end := time.Date(t.Year(), t.Month(), t.Day(), endTime.Hour(), endTime.Minute(), 0, 0, t.Location())
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, true
+}
+
+// end returns the end of the window if it contains t. Both the occurrence starting on t's day and the one from the
+// day before are checked since windows can continue past midnight
+func (bw BlackoutWindow) end(t time.Time) (time.Time, bool) {
+	for _, day := range []time.Time{t, t.AddDate(0, 0, -1)} {
+		start, end, ok := bw.occurrence(day)
+		if ok && !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// BlackoutEnd returns the time that watering can resume if t is in one of the BlackoutWindows. Windows that overlap
+// or follow each other are combined, so the result is never in a blackout unless they cover more than a week.
+// Windows use t's location
+func BlackoutEnd(windows []BlackoutWindow, t time.Time) (time.Time, bool) {
+	result := t
+	for result.Sub(t) < maxBlackoutDeferral {
+		inBlackout := false
+		for _, bw := range windows {
+			end, ok := bw.end(result)
+			if ok && end.After(result) {
+				result = end
+				inBlackout = true
+			}
+		}
+		if !inBlackout {
+			break
+		}
+	}
+	return result, !result.Equal(t)
+}
+
+// parseWeekday returns the time.Weekday with the case-insensitive name
+func parseWeekday(day string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(day, weekday.String()) {
+			return weekday, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q: must be a day of the week", day)
+}
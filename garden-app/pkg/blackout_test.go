@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlackoutWindowValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		window      BlackoutWindow
+		expectedErr string
+	}{
+		{
+			"Successful",
+			BlackoutWindow{StartTime: "10:00", EndTime: "16:00", Days: []string{"Saturday", "sunday"}},
+			"",
+		},
+		{
+			"ErrorMissingStartTime",
+			BlackoutWindow{EndTime: "16:00"},
+			"missing required start_time field",
+		},
+		{
+			"ErrorMissingEndTime",
+			BlackoutWindow{StartTime: "10:00"},
+			"missing required end_time field",
+		},
+		{
+			"ErrorInvalidTime",
+			BlackoutWindow{StartTime: "10am", EndTime: "16:00"},
+			`invalid time "10am": must use HH:MM format`,
+		},
+		{
+			"ErrorInvalidDay",
+			BlackoutWindow{StartTime: "10:00", EndTime: "16:00", Days: []string{"funday"}},
+			`invalid day "funday": must be a day of the week`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestBlackoutEnd(t *testing.T) {
+	// Saturday
+	date := func(day, hour, minute int) time.Time {
+		return time.Date(2023, time.August, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		windows    []BlackoutWindow
+		t          time.Time
+		expected   time.Time
+		inBlackout bool
+	}{
+		{
+			"NoWindows",
+			nil,
+			date(26, 12, 0),
+			date(26, 12, 0),
+			false,
+		},
+		{
+			"InWindow",
+			[]BlackoutWindow{{StartTime: "10:00", EndTime: "16:00"}},
+			date(26, 12, 0),
+			date(26, 16, 0),
+			true,
+		},
+		{
+			"StartIsInclusive",
+			[]BlackoutWindow{{StartTime: "10:00", EndTime: "16:00"}},
+			date(26, 10, 0),
+			date(26, 16, 0),
+			true,
+		},
+		{
+			"EndIsExclusive",
+			[]BlackoutWindow{{StartTime: "10:00", EndTime: "16:00"}},
+			date(26, 16, 0),
+			date(26, 16, 0),
+			false,
+		},
+		{
+			"OvernightWindowAfterMidnight",
+			[]BlackoutWindow{{StartTime: "22:00", EndTime: "02:00"}},
+			date(27, 1, 0),
+			date(27, 2, 0),
+			true,
+		},
+		{
+			"OvernightWindowBeforeMidnight",
+			[]BlackoutWindow{{StartTime: "22:00", EndTime: "02:00"}},
+			date(26, 23, 0),
+			date(27, 2, 0),
+			true,
+		},
+		{
+			"WholeDay",
+			[]BlackoutWindow{{StartTime: "00:00", EndTime: "00:00", Days: []string{"saturday"}}},
+			date(26, 8, 0),
+			date(27, 0, 0),
+			true,
+		},
+		{
+			"OtherDay",
+			[]BlackoutWindow{{StartTime: "00:00", EndTime: "00:00", Days: []string{"monday"}}},
+			date(26, 8, 0),
+			date(26, 8, 0),
+			false,
+		},
+		{
+			"OverlappingWindowsAreCombined",
+			[]BlackoutWindow{
+				{StartTime: "12:00", EndTime: "18:00"},
+				{StartTime: "10:00", EndTime: "14:00"},
+			},
+			date(26, 11, 0),
+			date(26, 18, 0),
+			true,
+		},
+		{
+			"LimitedWhenAlwaysInBlackout",
+			[]BlackoutWindow{{StartTime: "00:00", EndTime: "00:00"}},
+			date(26, 8, 0),
+			date(3, 0, 0).AddDate(0, 1, 0),
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, inBlackout := BlackoutEnd(tt.windows, tt.t)
+			assert.Equal(t, tt.expected, end)
+			assert.Equal(t, tt.inBlackout, inBlackout)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// pendingTimerDuration is used for the real timers that back virtual timers. They are never expected to fire,
+// but they are still created so callers get a *time.Timer that can be stopped
+const pendingTimerDuration = 100 * 365 * 24 * time.Hour
+
+// Clock provides the current time to the worker and scheduler. A real Clock just uses the system time, but
+// a virtual Clock is frozen until it is explicitly advanced. This allows simulating a long period of time
+// in just a few seconds. Clock implements gocron's TimeWrapper interface
+type Clock struct {
+	mu      sync.Mutex
+	virtual bool
+	now     time.Time
+	timers  []*virtualTimer
+}
+
+// virtualTimer keeps track of a function that should be executed when the virtual Clock reaches fireAt
+type virtualTimer struct {
+	fireAt time.Time
+	f      func()
+	timer  *time.Timer
+	direct bool
+}
+
+// New creates a real Clock that uses the system time
+func New() *Clock {
+	return &Clock{}
+}
+
+// NewVirtual creates a virtual Clock that starts at the specified time and only moves forward using Advance
+func NewVirtual(start time.Time) *Clock {
+	return &Clock{virtual: true, now: start}
+}
+
+// IsVirtual returns true if the Clock is a virtual Clock
+func (c *Clock) IsVirtual() bool {
+	return c != nil && c.virtual
+}
+
+// Now returns the current time in the specified location
+func (c *Clock) Now(loc *time.Location) time.Time {
+	if !c.IsVirtual() {
+		return time.Now().In(loc)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now.In(loc)
+}
+
+// Unix is used by gocron to create times
+func (c *Clock) Unix(sec int64, nsec int64) time.Time {
+	return time.Unix(sec, nsec)
+}
+
+// Sleep will sleep for the duration when using a real Clock. A virtual Clock returns immediately since
+// time only moves when advanced
+func (c *Clock) Sleep(d time.Duration) {
+	if !c.IsVirtual() {
+		time.Sleep(d)
+	}
+}
+
+// AfterFunc waits for the duration to elapse and then calls f. When the Clock is virtual, f is only called
+// once the Clock is advanced past the duration. The returned Timer can be used to cancel the call
+func (c *Clock) AfterFunc(d time.Duration, f func()) *time.Timer {
+	return c.afterFunc(d, f, false)
+}
+
+// AfterFuncDirect is like AfterFunc, but Advance calls f itself instead of passing it to the run function. This is
+// used for timers that finish all of their work in f, so there is nothing for the caller of Advance to wait for
+func (c *Clock) AfterFuncDirect(d time.Duration, f func()) *time.Timer {
+	return c.afterFunc(d, f, true)
+}
+
+func (c *Clock) afterFunc(d time.Duration, f func(), direct bool) *time.Timer {
+	if !c.IsVirtual() {
+		return time.AfterFunc(d, f)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &virtualTimer{
+		fireAt: c.now.Add(d),
+		f:      f,
+		timer:  time.AfterFunc(pendingTimerDuration, func() {}),
+		direct: direct,
+	}
+	c.timers = append(c.timers, t)
+	return t.timer
+}
+
+// Advance moves a virtual Clock forward by the duration. Any timers that are due within the period are
+// executed in order with the Clock set to their scheduled time. Each timer's function, except for ones from
+// AfterFuncDirect, is passed to run, which allows the caller to block until any triggered work is completed. Advance
+// returns the number of timers that fired, not including ones from AfterFuncDirect
+func (c *Clock) Advance(d time.Duration, run func(fire func())) int {
+	if !c.IsVirtual() {
+		return 0
+	}
+
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+
+	fired := 0
+	for {
+		t := c.nextTimerBefore(target)
+		if t == nil {
+			break
+		}
+
+		// A timer that is already stopped was cancelled by its owner and should not run
+		if !t.timer.Stop() {
+			continue
+		}
+
+		switch {
+		case t.direct:
+			t.f()
+		case run != nil:
+			run(t.f)
+			fired++
+		default:
+			t.f()
+			fired++
+		}
+	}
+
+	c.mu.Lock()
+	c.now = target
+	c.mu.Unlock()
+
+	return fired
+}
+
+// nextTimerBefore removes and returns the earliest timer scheduled at or before the target time. The Clock
+// is moved forward to the timer's scheduled time
+func (c *Clock) nextTimerBefore(target time.Time) *virtualTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].fireAt.Before(c.timers[j].fireAt)
+	})
+
+	if len(c.timers) == 0 || c.timers[0].fireAt.After(target) {
+		return nil
+	}
+
+	t := c.timers[0]
+	c.timers = c.timers[1:]
+	if t.fireAt.After(c.now) {
+		c.now = t.fireAt
+	}
+	return t
+}
@@ -0,0 +1,73 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualClockAdvance(t *testing.T) {
+	start := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	c := NewVirtual(start)
+
+	fireTimes := []time.Time{}
+	record := func() { fireTimes = append(fireTimes, c.Now(time.UTC)) }
+
+	c.AfterFunc(2*time.Hour, record)
+	c.AfterFunc(1*time.Hour, record)
+	stopped := c.AfterFunc(90*time.Minute, record)
+	c.AfterFunc(5*time.Hour, record)
+
+	assert.True(t, stopped.Stop())
+
+	fired := c.Advance(3*time.Hour, nil)
+	assert.Equal(t, 2, fired)
+	assert.Equal(t, []time.Time{start.Add(time.Hour), start.Add(2 * time.Hour)}, fireTimes)
+	assert.Equal(t, start.Add(3*time.Hour), c.Now(time.UTC))
+
+	fired = c.Advance(3*time.Hour, nil)
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, start.Add(5*time.Hour), fireTimes[2])
+}
+
+func TestVirtualClockTimerScheduledDuringAdvance(t *testing.T) {
+	start := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	c := NewVirtual(start)
+
+	count := 0
+	var repeat func()
+	repeat = func() {
+		count++
+		c.AfterFunc(time.Hour, repeat)
+	}
+	c.AfterFunc(time.Hour, repeat)
+
+	c.Advance(24*time.Hour, func(fire func()) { fire() })
+	assert.Equal(t, 24, count)
+}
+
+func TestRealClock(t *testing.T) {
+	c := New()
+	assert.False(t, c.IsVirtual())
+	assert.WithinDuration(t, time.Now(), c.Now(time.UTC), time.Second)
+	assert.Equal(t, 0, c.Advance(time.Hour, nil))
+}
+
+func TestVirtualClockAfterFuncDirect(t *testing.T) {
+	start := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	c := NewVirtual(start)
+
+	directCalls := 0
+	c.AfterFuncDirect(time.Hour, func() { directCalls++ })
+	c.AfterFunc(2*time.Hour, func() {})
+
+	runCalls := 0
+	fired := c.Advance(3*time.Hour, func(fire func()) {
+		runCalls++
+		fire()
+	})
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, 1, directCalls)
+	assert.Equal(t, 1, runCalls)
+}
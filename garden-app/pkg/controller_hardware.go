@@ -0,0 +1,191 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
+)
+
+// ControllerHardware describes a Garden's garden-controller board and the pins that are not used by a Zone. It is
+// used with each Zone's HardwareConfig to render the controller's config
+type ControllerHardware struct {
+	Board                   string    `json:"board,omitempty" yaml:"board,omitempty"`
+	LightPin                string    `json:"light_pin,omitempty" yaml:"light_pin,omitempty"`
+	StopButtonPin           string    `json:"stop_button_pin,omitempty" yaml:"stop_button_pin,omitempty"`
+	TemperatureHumidityPin  string    `json:"temperature_humidity_pin,omitempty" yaml:"temperature_humidity_pin,omitempty"`
+	DefaultWaterTime        *Duration `json:"default_water_time,omitempty" yaml:"default_water_time,omitempty"`
+	FlowMeterPulsesPerLiter *float64  `json:"flow_meter_pulses_per_liter,omitempty" yaml:"flow_meter_pulses_per_liter,omitempty"`
+}
+
+// Validate makes sure the board is supported. Pins are validated for the board when the config is rendered since
+// they depend on the Garden's Zones
+func (h *ControllerHardware) Validate() error {
+	if h.Board != "" {
+		_, err := firmware.GetBoardProfile(h.Board)
+		if err != nil {
+			return err
+		}
+	}
+	if h.DefaultWaterTime != nil {
+		err := h.DefaultWaterTime.ValidateNotNegative("default_water_time")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Patch updates the fields that are set in the new ControllerHardware
+func (h *ControllerHardware) Patch(new *ControllerHardware) {
+	if new.Board != "" {
+		h.Board = new.Board
+	}
+	if new.LightPin != "" {
+		h.LightPin = new.LightPin
+	}
+	if new.StopButtonPin != "" {
+		h.StopButtonPin = new.StopButtonPin
+	}
+	if new.TemperatureHumidityPin != "" {
+		h.TemperatureHumidityPin = new.TemperatureHumidityPin
+	}
+	if new.DefaultWaterTime != nil {
+		h.DefaultWaterTime = new.DefaultWaterTime
+	}
+	if new.FlowMeterPulsesPerLiter != nil {
+		h.FlowMeterPulsesPerLiter = new.FlowMeterPulsesPerLiter
+	}
+}
+
+// isEmpty is used to remove ControllerHardware with a PATCH request
+func (h *ControllerHardware) isEmpty() bool {
+	return *h == ControllerHardware{}
+}
+
+// HardwareConfig has the garden-controller pins used by a Zone. The pump and valve pins are required to render the
+// controller's config. Zones can share a pump pin
+type HardwareConfig struct {
+	PumpPin           string `json:"pump_pin,omitempty" yaml:"pump_pin,omitempty"`
+	ValvePin          string `json:"valve_pin,omitempty" yaml:"valve_pin,omitempty"`
+	ButtonPin         string `json:"button_pin,omitempty" yaml:"button_pin,omitempty"`
+	MoistureSensorPin string `json:"moisture_sensor_pin,omitempty" yaml:"moisture_sensor_pin,omitempty"`
+	FlowMeterPin      string `json:"flow_meter_pin,omitempty" yaml:"flow_meter_pin,omitempty"`
+	DosingPin         string `json:"dosing_pin,omitempty" yaml:"dosing_pin,omitempty"`
+}
+
+// Patch updates the pins that are set in the new HardwareConfig
+func (hc *HardwareConfig) Patch(new *HardwareConfig) {
+	if new.PumpPin != "" {
+		hc.PumpPin = new.PumpPin
+	}
+	if new.ValvePin != "" {
+		hc.ValvePin = new.ValvePin
+	}
+	if new.ButtonPin != "" {
+		hc.ButtonPin = new.ButtonPin
+	}
+	if new.MoistureSensorPin != "" {
+		hc.MoistureSensorPin = new.MoistureSensorPin
+	}
+	if new.FlowMeterPin != "" {
+		hc.FlowMeterPin = new.FlowMeterPin
+	}
+	if new.DosingPin != "" {
+		hc.DosingPin = new.DosingPin
+	}
+}
+
+// isEmpty is used to remove a HardwareConfig with a PATCH request
+func (hc *HardwareConfig) isEmpty() bool {
+	return *hc == HardwareConfig{}
+}
+
+// ControllerZones returns the firmware.ZoneConfigs for the Zones ordered by Position. Positions must start at 0
+// without gaps since the controller uses the index of each Zone's pins, and each Zone needs pump and valve pins
+func ControllerZones(zones []*Zone) ([]firmware.ZoneConfig, error) {
+	zones = slices.DeleteFunc(slices.Clone(zones), func(z *Zone) bool { return z.Position == nil })
+	slices.SortFunc(zones, func(a, b *Zone) int { return int(*a.Position) - int(*b.Position) })
+
+	if len(zones) == 0 {
+		return nil, errors.New("garden has no zones")
+	}
+
+	result := make([]firmware.ZoneConfig, 0, len(zones))
+	for i, z := range zones {
+		if *z.Position != uint(i) {
+			return nil, fmt.Errorf("missing zone with position %d: positions must start at 0 without gaps", i)
+		}
+		hc := z.HardwareConfig
+		if hc == nil || hc.PumpPin == "" || hc.ValvePin == "" {
+			return nil, fmt.Errorf("zone %q is missing required pump_pin and valve_pin", z.Name)
+		}
+
+		result = append(result, firmware.ZoneConfig{
+			PumpPin:           hc.PumpPin,
+			ValvePin:          hc.ValvePin,
+			ButtonPin:         hc.ButtonPin,
+			MoistureSensorPin: hc.MoistureSensorPin,
+			FlowMeterPin:      hc.FlowMeterPin,
+			DosingPin:         hc.DosingPin,
+		})
+	}
+
+	return result, nil
+}
+
+// ValidatePinConflicts makes sure each pin is only used for one purpose by the Garden's ControllerHardware and the
+// Zones' HardwareConfigs. Zones can share a pump pin, but it cannot be used for anything else
+func (g *Garden) ValidatePinConflicts(zones []*Zone) error {
+	type pinUse struct {
+		name string
+		pump bool
+	}
+	uses := map[string]pinUse{}
+
+	use := func(pin string, name string, pump bool) error {
+		if pin == "" {
+			return nil
+		}
+		existing, ok := uses[pin]
+		if !ok {
+			uses[pin] = pinUse{name, pump}
+			return nil
+		}
+		if existing.pump && pump {
+			return nil
+		}
+		return fmt.Errorf("pin %q is used by %s and %s", pin, existing.name, name)
+	}
+
+	var errs []error
+	if g.ControllerHardware != nil {
+		errs = append(errs,
+			use(g.ControllerHardware.LightPin, "light_pin", false),
+			use(g.ControllerHardware.StopButtonPin, "stop_button_pin", false),
+			use(g.ControllerHardware.TemperatureHumidityPin, "temperature_humidity_pin", false),
+		)
+	}
+
+	// Zones are sorted so errors are the same for each request
+	zones = slices.Clone(zones)
+	slices.SortFunc(zones, func(a, b *Zone) int { return strings.Compare(a.Name, b.Name) })
+	for _, z := range zones {
+		hc := z.HardwareConfig
+		if hc == nil {
+			continue
+		}
+		errs = append(errs,
+			use(hc.PumpPin, fmt.Sprintf("zone %q pump_pin", z.Name), true),
+			use(hc.ValvePin, fmt.Sprintf("zone %q valve_pin", z.Name), false),
+			use(hc.ButtonPin, fmt.Sprintf("zone %q button_pin", z.Name), false),
+			use(hc.MoistureSensorPin, fmt.Sprintf("zone %q moisture_sensor_pin", z.Name), false),
+			use(hc.FlowMeterPin, fmt.Sprintf("zone %q flow_meter_pin", z.Name), false),
+			use(hc.DosingPin, fmt.Sprintf("zone %q dosing_pin", z.Name), false),
+		)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControllerZones(t *testing.T) {
+	position := func(p uint) *uint { return &p }
+
+	t.Run("OrderedByPosition", func(t *testing.T) {
+		zones, err := ControllerZones([]*Zone{
+			{Name: "b", Position: position(1), HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_17"}},
+			{Name: "a", Position: position(0), HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16", ButtonPin: "GPIO_NUM_19"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []firmware.ZoneConfig{
+			{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16", ButtonPin: "GPIO_NUM_19"},
+			{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_17"},
+		}, zones)
+	})
+
+	tests := []struct {
+		name  string
+		zones []*Zone
+		err   string
+	}{
+		{
+			"ErrorNoZones",
+			nil,
+			"garden has no zones",
+		},
+		{
+			"ErrorPositionGap",
+			[]*Zone{{Name: "a", Position: position(1), HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}}},
+			"missing zone with position 0: positions must start at 0 without gaps",
+		},
+		{
+			"ErrorMissingValvePin",
+			[]*Zone{{Name: "a", Position: position(0), HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18"}}},
+			`zone "a" is missing required pump_pin and valve_pin`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ControllerZones(tt.zones)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestValidatePinConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		garden *Garden
+		zones  []*Zone
+		err    string
+	}{
+		{
+			"SharedPump",
+			&Garden{},
+			[]*Zone{
+				{Name: "a", HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}},
+				{Name: "b", HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_17"}},
+			},
+			"",
+		},
+		{
+			"ZonesWithoutHardwareConfig",
+			&Garden{ControllerHardware: &ControllerHardware{LightPin: "GPIO_NUM_32"}},
+			[]*Zone{{Name: "a"}},
+			"",
+		},
+		{
+			"ErrorSameValve",
+			&Garden{},
+			[]*Zone{
+				{Name: "b", HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}},
+				{Name: "a", HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}},
+			},
+			`pin "GPIO_NUM_16" is used by zone "a" valve_pin and zone "b" valve_pin`,
+		},
+		{
+			"ErrorPumpUsedAsValve",
+			&Garden{},
+			[]*Zone{
+				{Name: "a", HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}},
+				{Name: "b", HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_19", ValvePin: "GPIO_NUM_18"}},
+			},
+			`pin "GPIO_NUM_18" is used by zone "a" pump_pin and zone "b" valve_pin`,
+		},
+		{
+			"ErrorSamePinInZone",
+			&Garden{},
+			[]*Zone{{Name: "a", HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16", ButtonPin: "GPIO_NUM_16"}}},
+			`pin "GPIO_NUM_16" is used by zone "a" valve_pin and zone "a" button_pin`,
+		},
+		{
+			"ErrorLightPin",
+			&Garden{ControllerHardware: &ControllerHardware{LightPin: "GPIO_NUM_32"}},
+			[]*Zone{{Name: "a", HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_32"}}},
+			`pin "GPIO_NUM_32" is used by light_pin and zone "a" valve_pin`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.garden.ValidatePinConflicts(tt.zones)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
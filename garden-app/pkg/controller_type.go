@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+)
+
+// ControllerType is the kind of device that controls a Garden. MQTT is used by garden-controllers, OpenSprinkler
+// controllers are used with their HTTP API instead, and Tasmota is used for smart relays that accept ON/OFF commands
+type ControllerType string
+
+const (
+	ControllerTypeMQTT          ControllerType = "mqtt"
+	ControllerTypeOpenSprinkler ControllerType = "opensprinkler"
+	ControllerTypeTasmota       ControllerType = "tasmota"
+)
+
+// Validate returns an error if the ControllerType is not known. An empty ControllerType is the same as MQTT
+func (ct ControllerType) Validate() error {
+	switch ct {
+	case "", ControllerTypeMQTT, ControllerTypeOpenSprinkler, ControllerTypeTasmota:
+		return nil
+	default:
+		return fmt.Errorf("invalid controller_type %q: must be one of %q, %q, or %q", ct, ControllerTypeMQTT, ControllerTypeOpenSprinkler, ControllerTypeTasmota)
+	}
+}
+
+// UsesOpenSprinkler returns true if the Garden's actions are sent to an OpenSprinkler controller
+func (g *Garden) UsesOpenSprinkler() bool {
+	return g.ControllerType == ControllerTypeOpenSprinkler
+}
+
+// UsesTasmota returns true if the Garden's Zones are smart relays that are turned on and off with MQTT commands
+func (g *Garden) UsesTasmota() bool {
+	return g.ControllerType == ControllerTypeTasmota
+}
+
+// ValidateController makes sure an OpenSprinkler or Tasmota Garden has the config that it needs and does not use
+// features that it doesn't support. PATCH requests can change these fields separately, so this is also checked after
+// merging
+func (g *Garden) ValidateController() error {
+	if g.OpenSprinkler != nil && !g.UsesOpenSprinkler() {
+		return fmt.Errorf("opensprinkler is only used when controller_type is %q", ControllerTypeOpenSprinkler)
+	}
+	if g.Tasmota != nil && !g.UsesTasmota() {
+		return fmt.Errorf("tasmota is only used when controller_type is %q", ControllerTypeTasmota)
+	}
+	if g.ControllerHardware != nil && (g.UsesOpenSprinkler() || g.UsesTasmota()) {
+		return fmt.Errorf("controller_hardware is not supported by %s controllers", g.ControllerType)
+	}
+
+	switch g.ControllerType {
+	case ControllerTypeOpenSprinkler:
+		if g.OpenSprinkler == nil {
+			return validation.Missing("opensprinkler")
+		}
+		err := g.OpenSprinkler.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid opensprinkler: %w", err)
+		}
+	case ControllerTypeTasmota:
+		// the config is optional since the default topic works for most Tasmota devices
+		if g.Tasmota != nil {
+			err := g.Tasmota.Validate()
+			if err != nil {
+				return fmt.Errorf("invalid tasmota: %w", err)
+			}
+		}
+	default:
+		return nil
+	}
+
+	if g.LightSchedule != nil {
+		return fmt.Errorf("light_schedule is not supported by %s controllers", g.ControllerType)
+	}
+	if g.RecirculationSchedule != nil {
+		return fmt.Errorf("recirculation_schedule is not supported by %s controllers", g.ControllerType)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/opensprinkler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerTypeValidate(t *testing.T) {
+	for _, ct := range []ControllerType{"", ControllerTypeMQTT, ControllerTypeOpenSprinkler, ControllerTypeTasmota} {
+		t.Run("Valid_"+string(ct), func(t *testing.T) {
+			assert.NoError(t, ct.Validate())
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.EqualError(t, ControllerType("rachio").Validate(), `invalid controller_type "rachio": must be one of "mqtt", "opensprinkler", or "tasmota"`)
+	})
+}
+
+func TestGardenValidateController(t *testing.T) {
+	config := &opensprinkler.Config{Address: "http://192.168.1.50:8080", Password: "opendoor"}
+
+	tests := []struct {
+		name   string
+		garden *Garden
+		err    string
+	}{
+		{
+			"ValidMQTT",
+			&Garden{},
+			"",
+		},
+		{
+			"ValidOpenSprinkler",
+			&Garden{ControllerType: ControllerTypeOpenSprinkler, OpenSprinkler: config},
+			"",
+		},
+		{
+			"ErrorMissingOpenSprinkler",
+			&Garden{ControllerType: ControllerTypeOpenSprinkler},
+			"missing required opensprinkler field",
+		},
+		{
+			"ErrorInvalidOpenSprinkler",
+			&Garden{ControllerType: ControllerTypeOpenSprinkler, OpenSprinkler: &opensprinkler.Config{}},
+			"invalid opensprinkler: missing required address field",
+		},
+		{
+			"ErrorOpenSprinklerWithMQTT",
+			&Garden{ControllerType: ControllerTypeMQTT, OpenSprinkler: config},
+			`opensprinkler is only used when controller_type is "opensprinkler"`,
+		},
+		{
+			"ErrorLightSchedule",
+			&Garden{
+				ControllerType: ControllerTypeOpenSprinkler,
+				OpenSprinkler:  config,
+				LightSchedule:  &LightSchedule{Duration: &Duration{Duration: time.Hour}},
+			},
+			"light_schedule is not supported by opensprinkler controllers",
+		},
+		{
+			"ValidTasmotaWithoutConfig",
+			&Garden{ControllerType: ControllerTypeTasmota},
+			"",
+		},
+		{
+			"ErrorInvalidTasmota",
+			&Garden{ControllerType: ControllerTypeTasmota, Tasmota: &TasmotaConfig{PowerTopic: "cmnd/{{.Garden}}/#"}},
+			"invalid tasmota: invalid power_topic template: topic must not contain wildcards",
+		},
+		{
+			"ErrorTasmotaWithMQTT",
+			&Garden{Tasmota: &TasmotaConfig{}},
+			`tasmota is only used when controller_type is "tasmota"`,
+		},
+		{
+			"ErrorTasmotaControllerHardware",
+			&Garden{ControllerType: ControllerTypeTasmota, ControllerHardware: &ControllerHardware{Board: "esp32"}},
+			"controller_hardware is not supported by tasmota controllers",
+		},
+		{
+			"ErrorTasmotaRecirculationSchedule",
+			&Garden{ControllerType: ControllerTypeTasmota, RecirculationSchedule: &RecirculationSchedule{}},
+			"recirculation_schedule is not supported by tasmota controllers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.garden.ValidateController()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestTasmotaConfigTopic(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *TasmotaConfig
+		expected string
+	}{
+		{"Default", nil, "cmnd/garden/POWER3"},
+		{"EmptyPowerTopic", &TasmotaConfig{}, "cmnd/garden/POWER3"},
+		{"ESPHome", &TasmotaConfig{PowerTopic: "{{.Garden}}/switch/valve_{{.Position}}/command"}, "garden/switch/valve_2/command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topic, err := tt.config.Topic("garden", 2)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, topic)
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/jsonschema"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
 	"github.com/go-co-op/gocron"
 	"github.com/robfig/cron/v3"
 	"gopkg.in/yaml.v3"
@@ -21,14 +23,56 @@ type Duration struct {
 	Cron string
 }
 
-// SchedulerFunc is a wrapper around gocron's fluent style to easily choose the cron or duration-based scheduling
-func (d *Duration) SchedulerFunc(s *gocron.Scheduler) *gocron.Scheduler {
+// SchedulerFunc is a wrapper around gocron's fluent style to easily choose the cron or duration-based scheduling.
+// If loc is set, a cron schedule uses that time zone instead of the scheduler's
+func (d *Duration) SchedulerFunc(s *gocron.Scheduler, loc *time.Location) *gocron.Scheduler {
 	if d.Cron != "" {
-		return s.Cron(d.Cron)
+		return s.Cron(d.CronInLocation(loc))
 	}
 	return s.Every(d.Duration)
 }
 
+// CronInLocation returns the Cron expression with a time zone prefix so it is evaluated in the location. The
+// expression is not changed if loc is nil or it already has a time zone
+func (d *Duration) CronInLocation(loc *time.Location) string {
+	if loc == nil || strings.HasPrefix(d.Cron, "TZ=") || strings.HasPrefix(d.Cron, "CRON_TZ=") {
+		return d.Cron
+	}
+	return fmt.Sprintf("CRON_TZ=%s %s", loc.String(), d.Cron)
+}
+
+// ValidatePositive returns an error if the Duration is missing, uses a cron expression, or is not greater than 0.
+// The name is the field used in error messages
+func (d *Duration) ValidatePositive(name string) error {
+	if d == nil {
+		return validation.Missing(name)
+	}
+	if d.Cron != "" || d.Duration <= 0 {
+		return validation.Invalid(name, "%s must be a positive duration", name)
+	}
+	return nil
+}
+
+// ValidateNotNegative returns an error if the Duration is missing, uses a cron expression, or is less than 0.
+// The name is the field used in error messages
+func (d *Duration) ValidateNotNegative(name string) error {
+	if d == nil {
+		return validation.Missing(name)
+	}
+	if d.Cron != "" || d.Duration < 0 {
+		return validation.Invalid(name, "%s must not be a negative duration", name)
+	}
+	return nil
+}
+
+// JSONSchema describes a Duration as a string. The "duration" format is not used because it is for ISO 8601 durations
+func (d *Duration) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: `Go duration like "1h30m", or "cron:" followed by a cron expression when used as an interval`,
+	}
+}
+
 // MarshalJSON will convert Duration into the string representation
 func (d *Duration) MarshalJSON() ([]byte, error) {
 	if d.Cron != "" {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ajg/form"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
 
@@ -86,6 +87,29 @@ func TestDurationJSONMarshal(t *testing.T) {
 	})
 }
 
+func TestDurationCronInLocation(t *testing.T) {
+	phoenix, err := time.LoadLocation("America/Phoenix")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		cron     string
+		loc      *time.Location
+		expected string
+	}{
+		{"NilLocation", "0 8 * * *", nil, "0 8 * * *"},
+		{"AddTimeZone", "0 8 * * *", phoenix, "CRON_TZ=America/Phoenix 0 8 * * *"},
+		{"ExistingTimeZone", "CRON_TZ=UTC 0 8 * * *", phoenix, "CRON_TZ=UTC 0 8 * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Duration{Cron: tt.cron}
+			assert.Equal(t, tt.expected, d.CronInLocation(tt.loc))
+		})
+	}
+}
+
 func TestDurationUnmarshalYAML(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -194,3 +218,36 @@ func TestDurationUnmarshalText(t *testing.T) {
 		})
 	}
 }
+
+func TestDurationValidate(t *testing.T) {
+	tests := []struct {
+		name                string
+		d                   *Duration
+		expectedPositive    string
+		expectedNotNegative string
+	}{
+		{"Positive", &Duration{Duration: time.Minute}, "", ""},
+		{"Zero", &Duration{}, "water must be a positive duration", ""},
+		{"Negative", &Duration{Duration: -time.Minute}, "water must be a positive duration", "water must not be a negative duration"},
+		{"Cron", &Duration{Cron: "0 6 * * *"}, "water must be a positive duration", "water must not be a negative duration"},
+		{"Missing", nil, "missing required water field", "missing required water field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.d.ValidatePositive("water")
+			if tt.expectedPositive == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedPositive)
+			}
+
+			err = tt.d.ValidateNotNegative("water")
+			if tt.expectedNotNegative == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedNotNegative)
+			}
+		})
+	}
+}
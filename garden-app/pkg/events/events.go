@@ -0,0 +1,69 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// subscriberBufferSize is the number of Events that can be queued for a subscriber before new Events are dropped
+const subscriberBufferSize = 100
+
+// Event is a change in the state of the application, like a resource being updated or an action being executed.
+// Type is formatted as "<resource>.<action>", for example "garden.updated" or "water_action.executed"
+type Event struct {
+	Type      string      `json:"type"`
+	ID        string      `json:"id,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Bus is used to publish Events to any number of subscribers. Publishing never blocks, so slow subscribers
+// will miss Events instead of delaying the publisher. A nil Bus is valid and will ignore all Events
+type Bus struct {
+	subscribers map[chan Event]struct{}
+	mu          sync.RWMutex
+}
+
+// NewBus creates a new Bus without any subscribers
+func NewBus() *Bus {
+	return &Bus{subscribers: map[chan Event]struct{}{}}
+}
+
+// Publish sends the Event to all current subscribers. If the Timestamp is not set, it is set to the current time
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel that receives all Events published after subscribing. The returned function must
+// be used to unsubscribe when Events are no longer needed
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	subscriber := make(chan Event, subscriberBufferSize)
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return subscriber, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, subscriber)
+			b.mu.Unlock()
+		})
+	}
+}
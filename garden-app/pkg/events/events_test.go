@@ -0,0 +1,54 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+
+	first, unsubscribeFirst := bus.Subscribe()
+	second, unsubscribeSecond := bus.Subscribe()
+	defer unsubscribeSecond()
+
+	bus.Publish(Event{Type: "garden.created", ID: "id"})
+
+	for _, subscriber := range []<-chan Event{first, second} {
+		e := <-subscriber
+		assert.Equal(t, "garden.created", e.Type)
+		assert.Equal(t, "id", e.ID)
+		assert.False(t, e.Timestamp.IsZero())
+	}
+
+	unsubscribeFirst()
+	// Unsubscribing more than once is safe
+	unsubscribeFirst()
+
+	bus.Publish(Event{Type: "garden.deleted", Timestamp: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)})
+
+	e := <-second
+	assert.Equal(t, "garden.deleted", e.Type)
+	assert.Equal(t, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), e.Timestamp)
+	assert.Len(t, first, 0)
+}
+
+func TestBusSlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := NewBus()
+
+	subscriber, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBufferSize+10; i++ {
+		bus.Publish(Event{Type: "water_action.executed"})
+	}
+
+	assert.Len(t, subscriber, subscriberBufferSize)
+}
+
+func TestNilBus(t *testing.T) {
+	var bus *Bus
+	bus.Publish(Event{Type: "garden.created"})
+}
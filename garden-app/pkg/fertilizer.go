@@ -0,0 +1,39 @@
+package pkg
+
+import (
+	"errors"
+	"time"
+)
+
+// FertilizerSchedule injects fertilizer with a Zone's dosing pump while a WaterSchedule waters it. The injection
+// time is either a fixed Duration or a Ratio of the watering duration. It is never longer than the watering, and
+// the controller ignores it for Zones that do not have a dosing pin
+type FertilizerSchedule struct {
+	Duration *Duration `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Ratio    *float32  `json:"ratio,omitempty" yaml:"ratio,omitempty"`
+}
+
+// Validate makes sure exactly one of Duration and Ratio is used and that it is in range
+func (fs *FertilizerSchedule) Validate() error {
+	if (fs.Duration == nil) == (fs.Ratio == nil) {
+		return errors.New("exactly one of duration and ratio is required")
+	}
+	if fs.Duration != nil {
+		err := fs.Duration.ValidatePositive("duration")
+		if err != nil {
+			return err
+		}
+	}
+	if fs.Ratio != nil && (*fs.Ratio <= 0 || *fs.Ratio > 1) {
+		return errors.New("ratio must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+// DoseDuration returns how long the dosing pump runs while watering for the specified duration
+func (fs *FertilizerSchedule) DoseDuration(water time.Duration) time.Duration {
+	if fs.Ratio != nil {
+		return ScaleDuration(water, *fs.Ratio)
+	}
+	return min(fs.Duration.Duration, water)
+}
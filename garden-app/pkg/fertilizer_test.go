@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFertilizerScheduleValidate(t *testing.T) {
+	half := float32(0.5)
+	zero := float32(0)
+	tooHigh := float32(1.5)
+
+	tests := []struct {
+		name       string
+		fertilizer FertilizerSchedule
+		err        string
+	}{
+		{"ValidDuration", FertilizerSchedule{Duration: &Duration{Duration: 30 * time.Second}}, ""},
+		{"ValidRatio", FertilizerSchedule{Ratio: &half}, ""},
+		{"ErrorEmpty", FertilizerSchedule{}, "exactly one of duration and ratio is required"},
+		{"ErrorBoth", FertilizerSchedule{Duration: &Duration{Duration: 30 * time.Second}, Ratio: &half}, "exactly one of duration and ratio is required"},
+		{"ErrorZeroDuration", FertilizerSchedule{Duration: &Duration{}}, "duration must be a positive duration"},
+		{"ErrorZeroRatio", FertilizerSchedule{Ratio: &zero}, "ratio must be greater than 0 and at most 1"},
+		{"ErrorRatioTooHigh", FertilizerSchedule{Ratio: &tooHigh}, "ratio must be greater than 0 and at most 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fertilizer.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestFertilizerScheduleDoseDuration(t *testing.T) {
+	half := float32(0.5)
+
+	assert.Equal(t, 30*time.Second, (&FertilizerSchedule{Ratio: &half}).DoseDuration(time.Minute))
+	assert.Equal(t, 10*time.Second, (&FertilizerSchedule{Duration: &Duration{Duration: 10 * time.Second}}).DoseDuration(time.Minute))
+	assert.Equal(t, time.Minute, (&FertilizerSchedule{Duration: &Duration{Duration: time.Hour}}).DoseDuration(time.Minute))
+}
@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+)
+
+// FirmwareUpdateStatus is a step in updating a Garden's controller to new Firmware
+type FirmwareUpdateStatus string
+
+const (
+	// FirmwareUpdateStatusPending is used when the update was sent to the controller and it has not responded yet
+	FirmwareUpdateStatusPending FirmwareUpdateStatus = "pending"
+	// FirmwareUpdateStatusUpdated is used when the controller installed the Firmware
+	FirmwareUpdateStatusUpdated FirmwareUpdateStatus = "updated"
+	// FirmwareUpdateStatusFailed is used when the update could not be sent or the controller was unable to install it
+	FirmwareUpdateStatusFailed FirmwareUpdateStatus = "failed"
+)
+
+// Validate returns an error if a controller reported an unexpected status
+func (s FirmwareUpdateStatus) Validate() error {
+	switch s {
+	case FirmwareUpdateStatusUpdated, FirmwareUpdateStatusFailed:
+		return nil
+	default:
+		return fmt.Errorf("invalid firmware update status %q: must be %q or %q", s, FirmwareUpdateStatusUpdated, FirmwareUpdateStatusFailed)
+	}
+}
+
+// Firmware is a garden-controller binary that is installed over-the-air. It only holds the details about the
+// binary and the contents are saved by the configured firmware storage. Updates tracks which Gardens' controllers
+// were sent the Firmware and whether they installed it
+type Firmware struct {
+	ID          babyapi.ID                 `json:"id" yaml:"id,omitempty"`
+	Version     string                     `json:"version" yaml:"version,omitempty"`
+	Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
+	Checksum    string                     `json:"checksum" yaml:"checksum,omitempty"`
+	Size        int64                      `json:"size" yaml:"size,omitempty"`
+	CreatedAt   *time.Time                 `json:"created_at" yaml:"created_at,omitempty"`
+	EndDate     *time.Time                 `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+	Updates     map[string]*FirmwareUpdate `json:"updates,omitempty" yaml:"updates,omitempty"`
+}
+
+// FirmwareUpdate is the status of installing Firmware on a Garden's controller
+type FirmwareUpdate struct {
+	Status      FirmwareUpdateStatus `json:"status" yaml:"status"`
+	Error       string               `json:"error,omitempty" yaml:"error,omitempty"`
+	StartedAt   time.Time            `json:"started_at" yaml:"started_at"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+}
+
+func (f *Firmware) GetID() string {
+	return f.ID.String()
+}
+
+// String...
+func (f *Firmware) String() string {
+	return fmt.Sprintf("%+v", *f)
+}
+
+// EndDated returns true if the Firmware is end-dated
+func (f *Firmware) EndDated() bool {
+	return f.EndDate != nil && f.EndDate.Before(time.Now())
+}
+
+func (f *Firmware) SetEndDate(now time.Time) {
+	f.EndDate = &now
+}
+
+// StartUpdate records that the Firmware was sent to the Garden's controller. An earlier status for the Garden is
+// replaced since the update is started again
+func (f *Firmware) StartUpdate(gardenID string, now time.Time) {
+	if f.Updates == nil {
+		f.Updates = map[string]*FirmwareUpdate{}
+	}
+	f.Updates[gardenID] = &FirmwareUpdate{
+		Status:    FirmwareUpdateStatusPending,
+		StartedAt: now,
+	}
+}
+
+// CompleteUpdate records the result of updating the Garden's controller. Failed updates keep the error message
+func (f *Firmware) CompleteUpdate(gardenID string, status FirmwareUpdateStatus, errMsg string, now time.Time) error {
+	update, ok := f.Updates[gardenID]
+	if !ok {
+		return fmt.Errorf("firmware %q was not sent to garden %q", f.GetID(), gardenID)
+	}
+
+	update.Status = status
+	update.Error = errMsg
+	update.CompletedAt = &now
+	return nil
+}
+
+// Patch allows for updating the Firmware's Version and Description. Everything else is set when it is uploaded
+func (f *Firmware) Patch(newFirmware *Firmware) *babyapi.ErrResponse {
+	if newFirmware.Version != "" {
+		f.Version = newFirmware.Version
+	}
+	if newFirmware.Description != "" {
+		f.Description = newFirmware.Description
+	}
+
+	return nil
+}
+
+// Bind is only used for PATCH requests since Firmware is created by uploading it
+func (f *Firmware) Bind(r *http.Request) error {
+	if f == nil {
+		return errors.New("missing required Firmware fields")
+	}
+
+	err := f.ID.Bind(r)
+	if err != nil {
+		return err
+	}
+
+	if r.Method == http.MethodPatch {
+		if f.EndDate != nil {
+			return errors.New("to end-date Firmware, please use the DELETE endpoint")
+		}
+		if f.Checksum != "" || f.Size != 0 {
+			return errors.New("unable to change checksum or size")
+		}
+		if f.Updates != nil {
+			return errors.New("unable to change updates")
+		}
+	}
+
+	return nil
+}
+
+func (f *Firmware) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirmwareBind(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		firmware *Firmware
+		err      string
+	}{
+		{"PatchVersion", http.MethodPatch, &Firmware{Version: "v1.2.0"}, ""},
+		{"ErrorPatchChecksum", http.MethodPatch, &Firmware{Checksum: "abc"}, "unable to change checksum or size"},
+		{"ErrorPatchUpdates", http.MethodPatch, &Firmware{Updates: map[string]*FirmwareUpdate{}}, "unable to change updates"},
+		{"ErrorPatchEndDate", http.MethodPatch, &Firmware{EndDate: &time.Time{}}, "to end-date Firmware, please use the DELETE endpoint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.firmware.Bind(&http.Request{Method: tt.method})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestFirmwareUpdates(t *testing.T) {
+	started := time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC)
+	completed := started.Add(time.Minute)
+
+	t.Run("ErrorNotStarted", func(t *testing.T) {
+		f := &Firmware{ID: babyapi.NewID()}
+		err := f.CompleteUpdate("garden", FirmwareUpdateStatusUpdated, "", completed)
+		assert.EqualError(t, err, fmt.Sprintf("firmware %q was not sent to garden \"garden\"", f.GetID()))
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		f := &Firmware{}
+		f.StartUpdate("garden", started)
+		assert.Equal(t, &FirmwareUpdate{Status: FirmwareUpdateStatusPending, StartedAt: started}, f.Updates["garden"])
+
+		err := f.CompleteUpdate("garden", FirmwareUpdateStatusFailed, "checksum mismatch", completed)
+		require.NoError(t, err)
+		assert.Equal(t, &FirmwareUpdate{
+			Status:      FirmwareUpdateStatusFailed,
+			Error:       "checksum mismatch",
+			StartedAt:   started,
+			CompletedAt: &completed,
+		}, f.Updates["garden"])
+	})
+
+	t.Run("StartAgainResetsStatus", func(t *testing.T) {
+		f := &Firmware{}
+		f.StartUpdate("garden", started)
+		require.NoError(t, f.CompleteUpdate("garden", FirmwareUpdateStatusFailed, "checksum mismatch", completed))
+
+		f.StartUpdate("garden", completed)
+		assert.Equal(t, &FirmwareUpdate{Status: FirmwareUpdateStatusPending, StartedAt: completed}, f.Updates["garden"])
+	})
+}
+
+func TestFirmwareUpdateStatusValidate(t *testing.T) {
+	assert.NoError(t, FirmwareUpdateStatusUpdated.Validate())
+	assert.NoError(t, FirmwareUpdateStatusFailed.Validate())
+	assert.EqualError(t, FirmwareUpdateStatusPending.Validate(), `invalid firmware update status "pending": must be "updated" or "failed"`)
+}
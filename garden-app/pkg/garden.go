@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/opensprinkler"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
 	"github.com/calvinmclean/babyapi"
 )
 
@@ -21,7 +25,33 @@ type Garden struct {
 	CreatedAt                 *time.Time     `json:"created_at" yaml:"created_at,omitempty"`
 	EndDate                   *time.Time     `json:"end_date,omitempty" yaml:"end_date,omitempty"`
 	LightSchedule             *LightSchedule `json:"light_schedule,omitempty" yaml:"light_schedule,omitempty"`
+	Location                  *Location      `json:"location,omitempty" yaml:"location,omitempty"`
+	TimeZone                  string         `json:"time_zone,omitempty" yaml:"time_zone,omitempty"`
 	TemperatureHumiditySensor *bool          `json:"temperature_humidity_sensor,omitempty" yaml:"temperature_humidity_sensor,omitempty"`
+	Pricing                   *WaterPricing  `json:"pricing,omitempty" yaml:"pricing,omitempty"`
+	WaterBudget               *WaterBudget   `json:"water_budget,omitempty" yaml:"water_budget,omitempty"`
+	// SeasonalAdjustment scales durations from WaterSchedules for all of the Garden's Zones by month
+	SeasonalAdjustment SeasonalAdjustment `json:"seasonal_adjustment,omitempty" yaml:"seasonal_adjustment,omitempty"`
+	// BlackoutWindows are times when the Garden's Zones are not watered. Scheduled watering is deferred until the
+	// window ends
+	BlackoutWindows []BlackoutWindow `json:"blackout_windows,omitempty" yaml:"blackout_windows,omitempty"`
+	// MaxConcurrentZones limits how many Zones are watered at the same time. Other WaterActions are queued until
+	// the controller publishes that a Zone finished watering
+	MaxConcurrentZones *uint `json:"max_concurrent_zones,omitempty" yaml:"max_concurrent_zones,omitempty"`
+	// Type is soil when it is empty. Only hydroponic Gardens use a RecirculationSchedule
+	Type                  GardenType             `json:"type,omitempty" yaml:"type,omitempty"`
+	RecirculationSchedule *RecirculationSchedule `json:"recirculation_schedule,omitempty" yaml:"recirculation_schedule,omitempty"`
+	// TopicTemplates override the server's MQTT topic templates for controllers that use different topics
+	TopicTemplates *TopicTemplates `json:"topic_templates,omitempty" yaml:"topic_templates,omitempty"`
+	// ControllerType is mqtt when it is empty. OpenSprinkler controllers use the OpenSprinkler config instead of MQTT
+	ControllerType ControllerType        `json:"controller_type,omitempty" yaml:"controller_type,omitempty"`
+	OpenSprinkler  *opensprinkler.Config `json:"opensprinkler,omitempty" yaml:"opensprinkler,omitempty"`
+	Tasmota        *TasmotaConfig        `json:"tasmota,omitempty" yaml:"tasmota,omitempty"`
+	// ControllerHardware is used with the Zones' HardwareConfigs to render the garden-controller's config
+	ControllerHardware *ControllerHardware `json:"controller_hardware,omitempty" yaml:"controller_hardware,omitempty"`
+	// Users maps User IDs to their role for this Garden and its nested resources. Requests made as a User can only
+	// access Gardens that give them a role
+	Users map[string]UserRole `json:"users,omitempty" yaml:"users,omitempty"`
 }
 
 func (g *Garden) GetID() string {
@@ -40,8 +70,12 @@ type GardenHealth struct {
 	LastContact *time.Time `json:"last_contact,omitempty"`
 }
 
-// Health returns a GardenHealth struct after querying InfluxDB for the Garden controller's last contact time
-func (g *Garden) Health(ctx context.Context, influxdbClient influxdb.Client) *GardenHealth {
+// DefaultHealthThreshold is how long a Garden's controller can go without contact before it is considered "DOWN"
+const DefaultHealthThreshold = 5 * time.Minute
+
+// Health returns a GardenHealth struct after querying InfluxDB for the Garden controller's last contact time.
+// The current time is passed in so it can come from the Worker's Clock
+func (g *Garden) Health(ctx context.Context, influxdbClient metrics.Client, now time.Time) *GardenHealth {
 	lastContact, err := influxdbClient.GetLastContact(ctx, g.TopicPrefix)
 	if err != nil {
 		return &GardenHealth{
@@ -50,6 +84,12 @@ func (g *Garden) Health(ctx context.Context, influxdbClient influxdb.Client) *Ga
 		}
 	}
 
+	return NewGardenHealth(lastContact, now, DefaultHealthThreshold)
+}
+
+// NewGardenHealth creates a GardenHealth from the controller's last contact time. The Garden is considered "UP" if
+// its last contact was less than threshold ago
+func NewGardenHealth(lastContact time.Time, now time.Time, threshold time.Duration) *GardenHealth {
 	if lastContact.IsZero() {
 		return &GardenHealth{
 			Status:  "DOWN",
@@ -57,9 +97,8 @@ func (g *Garden) Health(ctx context.Context, influxdbClient influxdb.Client) *Ga
 		}
 	}
 
-	// Garden is considered "UP" if it's last contact was less than 5 minutes ago
-	between := time.Since(lastContact)
-	up := between < 5*time.Minute
+	between := now.Sub(lastContact)
+	up := between < threshold
 
 	status := "UP"
 	if !up {
@@ -82,6 +121,11 @@ func (g *Garden) SetEndDate(now time.Time) {
 	g.EndDate = &now
 }
 
+// Restore removes the EndDate so the Garden is active again
+func (g *Garden) Restore() {
+	g.EndDate = nil
+}
+
 // Patch allows for easily updating individual fields of a Garden by passing in a new Garden containing
 // the desired values
 func (g *Garden) Patch(newGarden *Garden) *babyapi.ErrResponse {
@@ -107,18 +151,149 @@ func (g *Garden) Patch(newGarden *Garden) *babyapi.ErrResponse {
 		}
 		g.LightSchedule.Patch(newGarden.LightSchedule)
 
-		// If both Duration and StartTime are empty, remove the schedule
-		if newGarden.LightSchedule.Duration == nil && newGarden.LightSchedule.StartTime == nil {
+		// If the new LightSchedule is empty, remove the schedule. AmbientLight can be changed by itself, but it is
+		// not a schedule without the other fields
+		if (newGarden.LightSchedule.isEmpty() && newGarden.LightSchedule.AmbientLight == nil) || g.LightSchedule.isEmpty() {
 			g.LightSchedule = nil
 		}
 	}
+	if newGarden.Location != nil {
+		g.Location = newGarden.Location
+	}
+	if newGarden.TimeZone != "" {
+		g.TimeZone = newGarden.TimeZone
+	}
 	if newGarden.TemperatureHumiditySensor != nil {
 		g.TemperatureHumiditySensor = newGarden.TemperatureHumiditySensor
 	}
+	if newGarden.Pricing != nil {
+		if g.Pricing == nil {
+			g.Pricing = &WaterPricing{}
+		}
+		g.Pricing.Patch(newGarden.Pricing)
+	}
+	// an empty list is used to remove all BlackoutWindows
+	if newGarden.BlackoutWindows != nil {
+		g.BlackoutWindows = newGarden.BlackoutWindows
+	}
+	if newGarden.MaxConcurrentZones != nil {
+		g.MaxConcurrentZones = newGarden.MaxConcurrentZones
+	}
+	if newGarden.Type != "" {
+		g.Type = newGarden.Type
+	}
+	if newGarden.RecirculationSchedule != nil {
+		if g.RecirculationSchedule == nil {
+			g.RecirculationSchedule = &RecirculationSchedule{}
+		}
+		g.RecirculationSchedule.Patch(newGarden.RecirculationSchedule)
+
+		// If the new RecirculationSchedule is empty, remove the schedule
+		if newGarden.RecirculationSchedule.isEmpty() {
+			g.RecirculationSchedule = nil
+		}
+	}
+	if newGarden.WaterBudget != nil {
+		if g.WaterBudget == nil {
+			g.WaterBudget = &WaterBudget{}
+		}
+		g.WaterBudget.Patch(newGarden.WaterBudget)
+
+		// If the new WaterBudget is empty, remove the budget
+		if newGarden.WaterBudget.isEmpty() {
+			g.WaterBudget = nil
+		}
+	}
+	// an empty map is used to remove the SeasonalAdjustment
+	if newGarden.SeasonalAdjustment != nil {
+		g.SeasonalAdjustment = newGarden.SeasonalAdjustment
+	}
+	if newGarden.TopicTemplates != nil {
+		if g.TopicTemplates == nil {
+			g.TopicTemplates = &TopicTemplates{}
+		}
+		g.TopicTemplates.Patch(newGarden.TopicTemplates)
+
+		// If the new TopicTemplates are empty, use the server's config again
+		if newGarden.TopicTemplates.isEmpty() {
+			g.TopicTemplates = nil
+		}
+	}
+	if newGarden.ControllerHardware != nil {
+		if g.ControllerHardware == nil {
+			g.ControllerHardware = &ControllerHardware{}
+		}
+		g.ControllerHardware.Patch(newGarden.ControllerHardware)
+
+		// If the new ControllerHardware is empty, remove it
+		if newGarden.ControllerHardware.isEmpty() {
+			g.ControllerHardware = nil
+		}
+	}
+	if newGarden.ControllerType != "" {
+		g.ControllerType = newGarden.ControllerType
+	}
+	if newGarden.OpenSprinkler != nil {
+		if g.OpenSprinkler == nil {
+			g.OpenSprinkler = &opensprinkler.Config{}
+		}
+		if newGarden.OpenSprinkler.Address != "" {
+			g.OpenSprinkler.Address = newGarden.OpenSprinkler.Address
+		}
+		if newGarden.OpenSprinkler.Password != "" {
+			g.OpenSprinkler.Password = newGarden.OpenSprinkler.Password
+		}
+	}
+	if newGarden.Tasmota != nil {
+		if g.Tasmota == nil {
+			g.Tasmota = &TasmotaConfig{}
+		}
+		if newGarden.Tasmota.PowerTopic != "" {
+			g.Tasmota.PowerTopic = newGarden.Tasmota.PowerTopic
+		}
+	}
+	// an empty map is used to remove all Users
+	if newGarden.Users != nil {
+		g.Users = newGarden.Users
+	}
 
 	return nil
 }
 
+// TimeLocation returns the Garden's TimeZone as a time.Location, or nil if it is not set
+func (g *Garden) TimeLocation() (*time.Location, error) {
+	if g.TimeZone == "" {
+		return nil, nil
+	}
+	// "Local" is not allowed since the Garden's TimeZone should not depend on the server
+	if strings.EqualFold(g.TimeZone, "local") {
+		return nil, fmt.Errorf("invalid time_zone: %q", g.TimeZone)
+	}
+
+	loc, err := time.LoadLocation(g.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time_zone: %w", err)
+	}
+	return loc, nil
+}
+
+// BlackoutEnd returns the time that watering can resume if t is in one of the Garden's BlackoutWindows or the
+// global windows. Windows use the Garden's TimeZone, or the server's local time if it is not set
+func (g *Garden) BlackoutEnd(global []BlackoutWindow, t time.Time) (time.Time, bool) {
+	loc, err := g.TimeLocation()
+	if err != nil || loc == nil {
+		loc = time.Local
+	}
+
+	windows := append(slices.Clone(global), g.BlackoutWindows...)
+	return BlackoutEnd(windows, t.In(loc))
+}
+
+// UserRole returns the User's role for this Garden. It is empty if the User can't access the Garden
+func (g *Garden) UserRole(userID string) UserRole {
+	return g.Users[userID]
+}
+
 // HasTemperatureHumiditySensor determines if the Garden has a sensor configured
 func (g *Garden) HasTemperatureHumiditySensor() bool {
 	return g.TemperatureHumiditySensor != nil && *g.TemperatureHumiditySensor
@@ -144,35 +319,56 @@ func (g *Garden) Bind(r *http.Request) error {
 			g.CreatedAt = &now
 		}
 		if g.Name == "" {
-			return errors.New("missing required name field")
+			return validation.Missing("name")
 		}
 		if g.TopicPrefix == "" {
-			return errors.New("missing required topic_prefix field")
+			return validation.Missing("topic_prefix")
 		}
 		illegalRegexp := regexp.MustCompile(`[\$\#\*\>\+\/]`)
 		if illegalRegexp.MatchString(g.TopicPrefix) {
 			return errors.New("one or more invalid characters in Garden topic_prefix")
 		}
 		if g.MaxZones == nil {
-			return errors.New("missing required max_zones field")
+			return validation.Missing("max_zones")
 		} else if *g.MaxZones == 0 {
 			return errors.New("max_zones must not be 0")
 		}
 		// consider empty LightSchedule as nil for removing from HTML form
-		if g.LightSchedule != nil && (g.LightSchedule.Duration == nil || g.LightSchedule.Duration.Duration == 0) {
+		if g.LightSchedule != nil && !g.LightSchedule.UsesSunTimes() && (g.LightSchedule.Duration == nil || g.LightSchedule.Duration.Duration == 0) {
 			startTimeEmpty := g.LightSchedule.StartTime == nil || g.LightSchedule.StartTime.Time.IsZero()
 			if startTimeEmpty {
 				g.LightSchedule = nil
 			}
 		}
 		if g.LightSchedule != nil {
-			if g.LightSchedule.Duration == nil {
+			if g.LightSchedule.Duration == nil && g.LightSchedule.Until == nil {
 				return errors.New("missing required light_schedule.duration field")
 			}
 
-			if g.LightSchedule.StartTime == nil {
+			if g.LightSchedule.StartTime == nil && g.LightSchedule.Start == nil {
 				return errors.New("missing required light_schedule.start_time field")
 			}
+
+			if g.LightSchedule.UsesSunTimes() && g.Location == nil {
+				return errors.New("missing required location field for light_schedule using sunrise or sunset")
+			}
+		}
+		err = g.ValidateRecirculation()
+		if err != nil {
+			return err
+		}
+		if g.TopicTemplates != nil && g.TopicTemplates.isEmpty() {
+			g.TopicTemplates = nil
+		}
+		if g.WaterBudget != nil {
+			err = g.WaterBudget.Validate()
+			if err != nil {
+				return fmt.Errorf("invalid water_budget: %w", err)
+			}
+		}
+		err = g.ValidateController()
+		if err != nil {
+			return err
 		}
 	case http.MethodPatch:
 		illegalRegexp := regexp.MustCompile(`[\$\#\*\>\+\/]`)
@@ -188,20 +384,123 @@ func (g *Garden) Bind(r *http.Request) error {
 	}
 
 	if g.LightSchedule != nil {
+		if g.LightSchedule.StartTime != nil && g.LightSchedule.Start != nil {
+			return errors.New("only one of light_schedule.start_time and light_schedule.start can be used")
+		}
+		if g.LightSchedule.Duration != nil && g.LightSchedule.Until != nil {
+			return errors.New("only one of light_schedule.duration and light_schedule.until can be used")
+		}
 		if g.LightSchedule.StartTime != nil {
 			err = g.LightSchedule.StartTime.Validate()
 			if err != nil {
 				return err
 			}
 		}
+		for _, st := range []*SunTime{g.LightSchedule.Start, g.LightSchedule.Until} {
+			if st == nil {
+				continue
+			}
+			err = st.Validate()
+			if err != nil {
+				return fmt.Errorf("invalid light_schedule: %w", err)
+			}
+		}
 		// Check that Duration is valid Duration
 		if g.LightSchedule.Duration != nil {
 			if g.LightSchedule.Duration.Duration >= 24*time.Hour {
 				return fmt.Errorf("invalid light_schedule.duration >= 24 hours: %s", g.LightSchedule.Duration)
 			}
 		}
+		if g.LightSchedule.AmbientLight != nil {
+			err = g.LightSchedule.AmbientLight.Validate()
+			if err != nil {
+				return fmt.Errorf("invalid light_schedule.ambient_light: %w", err)
+			}
+		}
+	}
+
+	if g.Location != nil {
+		err = g.Location.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid location: %w", err)
+		}
+	}
+
+	_, err = g.TimeLocation()
+	if err != nil {
+		return err
+	}
+
+	if g.Pricing != nil {
+		err = g.Pricing.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid pricing: %w", err)
+		}
+	}
+
+	if g.MaxConcurrentZones != nil && *g.MaxConcurrentZones == 0 {
+		return errors.New("max_concurrent_zones must not be 0")
 	}
 
+	err = g.Type.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = g.ControllerType.Validate()
+	if err != nil {
+		return err
+	}
+
+	for i, bw := range g.BlackoutWindows {
+		err = bw.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid blackout_windows[%d]: %w", i, err)
+		}
+	}
+
+	if g.TopicTemplates != nil {
+		err = g.TopicTemplates.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid topic_templates: %w", err)
+		}
+	}
+
+	err = g.SeasonalAdjustment.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid seasonal_adjustment: %w", err)
+	}
+
+	if g.ControllerHardware != nil {
+		err = g.ControllerHardware.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid controller_hardware: %w", err)
+		}
+	}
+
+	for userID, role := range g.Users {
+		err = role.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid users[%q]: %w", userID, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRecirculation makes sure a RecirculationSchedule is only used by a hydroponic Garden. PATCH requests can
+// change the Type and RecirculationSchedule separately, so this is also checked after merging
+func (g *Garden) ValidateRecirculation() error {
+	if g.RecirculationSchedule == nil {
+		return nil
+	}
+	if g.Type != GardenTypeHydroponic {
+		return errors.New("recirculation_schedule is only used for hydroponic Gardens")
+	}
+	err := g.RecirculationSchedule.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid recirculation_schedule: %w", err)
+	}
 	return nil
 }
 
@@ -52,7 +52,7 @@ func TestHealth(t *testing.T) {
 
 			g := Garden{TopicPrefix: "garden"}
 
-			gardenHealth := g.Health(context.Background(), influxdbClient)
+			gardenHealth := g.Health(context.Background(), influxdbClient, time.Now())
 			if gardenHealth.Status != tt.expectedStatus {
 				t.Errorf("Unexpected GardenHealth.Status: expected = %s, actual = %s", tt.expectedStatus, gardenHealth.Status)
 			}
@@ -109,6 +109,10 @@ func TestGardenPatch(t *testing.T) {
 			"PatchCreatedAt",
 			&Garden{CreatedAt: &now},
 		},
+		{
+			"PatchMaxConcurrentZones",
+			&Garden{MaxConcurrentZones: &ten},
+		},
 		{
 			"PatchLightSchedule.Duration",
 			&Garden{LightSchedule: &LightSchedule{
@@ -121,6 +125,18 @@ func TestGardenPatch(t *testing.T) {
 				StartTime: NewStartTime(time.Date(0, 1, 1, 15, 4, 0, 0, time.FixedZone("", 0))),
 			}},
 		},
+		{
+			"PatchLightSchedule.Start",
+			&Garden{LightSchedule: &LightSchedule{
+				Start: &SunTime{Event: SunEventSunrise, Offset: 30 * time.Minute},
+			}},
+		},
+		{
+			"PatchLightSchedule.Until",
+			&Garden{LightSchedule: &LightSchedule{
+				Until: &SunTime{Event: SunEventSunset, Offset: -1 * time.Hour},
+			}},
+		},
 		{
 			"PatchLightSchedule.AdhocOnTime",
 			&Garden{LightSchedule: &LightSchedule{
@@ -171,6 +187,171 @@ func TestGardenPatch(t *testing.T) {
 		}
 	})
 
+	t.Run("PatchAmbientLight", func(t *testing.T) {
+		g := &Garden{
+			LightSchedule: &LightSchedule{
+				StartTime: NewStartTime(time.Date(0, 1, 1, 15, 4, 0, 0, time.FixedZone("", 0))),
+				Duration:  &Duration{2 * time.Hour, ""},
+			},
+		}
+		err := g.Patch(&Garden{LightSchedule: &LightSchedule{AmbientLight: &AmbientLightControl{LuxThreshold: 10000}}})
+		require.Nil(t, err)
+		require.NotNil(t, g.LightSchedule)
+		require.True(t, g.LightSchedule.HasAmbientLightControl())
+		require.Equal(t, 2*time.Hour, g.LightSchedule.Duration.Duration)
+
+		err = g.Patch(&Garden{LightSchedule: &LightSchedule{AmbientLight: &AmbientLightControl{}}})
+		require.Nil(t, err)
+		require.NotNil(t, g.LightSchedule)
+		require.Nil(t, g.LightSchedule.AmbientLight)
+	})
+
+	t.Run("PatchAmbientLightWithoutLightSchedule", func(t *testing.T) {
+		g := &Garden{}
+		err := g.Patch(&Garden{LightSchedule: &LightSchedule{AmbientLight: &AmbientLightControl{LuxThreshold: 10000}}})
+		require.Nil(t, err)
+		require.Nil(t, g.LightSchedule)
+	})
+
+	t.Run("PatchSunTimesReplaceStartTimeAndDuration", func(t *testing.T) {
+		g := &Garden{
+			LightSchedule: &LightSchedule{
+				StartTime: NewStartTime(time.Date(0, 1, 1, 15, 4, 0, 0, time.FixedZone("", 0))),
+				Duration:  &Duration{2 * time.Hour, ""},
+			},
+		}
+		start := &SunTime{Event: SunEventSunrise}
+		until := &SunTime{Event: SunEventSunset}
+		location := &Location{Latitude: 33.45, Longitude: -112.07}
+
+		err := g.Patch(&Garden{LightSchedule: &LightSchedule{Start: start, Until: until}, Location: location})
+		require.Nil(t, err)
+
+		require.Equal(t, &LightSchedule{Start: start, Until: until}, g.LightSchedule)
+		require.Equal(t, location, g.Location)
+	})
+
+	t.Run("PatchTimeZone", func(t *testing.T) {
+		g := &Garden{TimeZone: "America/Phoenix"}
+
+		err := g.Patch(&Garden{})
+		require.Nil(t, err)
+		require.Equal(t, "America/Phoenix", g.TimeZone)
+
+		err = g.Patch(&Garden{TimeZone: "Europe/London"})
+		require.Nil(t, err)
+		require.Equal(t, "Europe/London", g.TimeZone)
+	})
+
+	t.Run("PatchBlackoutWindows", func(t *testing.T) {
+		windows := []BlackoutWindow{{StartTime: "10:00", EndTime: "16:00"}}
+		g := &Garden{BlackoutWindows: windows}
+
+		err := g.Patch(&Garden{})
+		require.Nil(t, err)
+		require.Equal(t, windows, g.BlackoutWindows)
+
+		err = g.Patch(&Garden{BlackoutWindows: []BlackoutWindow{}})
+		require.Nil(t, err)
+		require.Empty(t, g.BlackoutWindows)
+	})
+
+	t.Run("PatchSeasonalAdjustment", func(t *testing.T) {
+		adjustment := SeasonalAdjustment{"july": 130}
+		g := &Garden{SeasonalAdjustment: adjustment}
+
+		err := g.Patch(&Garden{})
+		require.Nil(t, err)
+		require.Equal(t, adjustment, g.SeasonalAdjustment)
+
+		err = g.Patch(&Garden{SeasonalAdjustment: SeasonalAdjustment{}})
+		require.Nil(t, err)
+		require.Empty(t, g.SeasonalAdjustment)
+	})
+
+	t.Run("PatchRecirculationSchedule", func(t *testing.T) {
+		g := &Garden{
+			Type: GardenTypeHydroponic,
+			RecirculationSchedule: &RecirculationSchedule{
+				OnDuration:  &Duration{Duration: 15 * time.Minute},
+				OffDuration: &Duration{Duration: 45 * time.Minute},
+			},
+		}
+
+		err := g.Patch(&Garden{RecirculationSchedule: &RecirculationSchedule{OffDuration: &Duration{Duration: time.Hour}}})
+		require.Nil(t, err)
+		require.Equal(t, GardenTypeHydroponic, g.Type)
+		require.Equal(t, &RecirculationSchedule{
+			OnDuration:  &Duration{Duration: 15 * time.Minute},
+			OffDuration: &Duration{Duration: time.Hour},
+		}, g.RecirculationSchedule)
+
+		err = g.Patch(&Garden{RecirculationSchedule: &RecirculationSchedule{}})
+		require.Nil(t, err)
+		require.Nil(t, g.RecirculationSchedule)
+	})
+
+	t.Run("PatchTopicTemplates", func(t *testing.T) {
+		g := &Garden{TopicTemplates: &TopicTemplates{Water: "cmnd/{{.Garden}}/water"}}
+
+		err := g.Patch(&Garden{TopicTemplates: &TopicTemplates{Light: "cmnd/{{.Garden}}/POWER"}})
+		require.Nil(t, err)
+		require.Equal(t, &TopicTemplates{
+			Water: "cmnd/{{.Garden}}/water",
+			Light: "cmnd/{{.Garden}}/POWER",
+		}, g.TopicTemplates)
+
+		err = g.Patch(&Garden{TopicTemplates: &TopicTemplates{}})
+		require.Nil(t, err)
+		require.Nil(t, g.TopicTemplates)
+	})
+
+	t.Run("PatchControllerHardware", func(t *testing.T) {
+		g := &Garden{ControllerHardware: &ControllerHardware{Board: "esp32"}}
+
+		err := g.Patch(&Garden{ControllerHardware: &ControllerHardware{LightPin: "GPIO_NUM_32"}})
+		require.Nil(t, err)
+		require.Equal(t, &ControllerHardware{Board: "esp32", LightPin: "GPIO_NUM_32"}, g.ControllerHardware)
+
+		err = g.Patch(&Garden{ControllerHardware: &ControllerHardware{}})
+		require.Nil(t, err)
+		require.Nil(t, g.ControllerHardware)
+	})
+
+	t.Run("PatchWaterBudget", func(t *testing.T) {
+		scaleDown := true
+		g := &Garden{WaterBudget: &WaterBudget{MaxLiters: 500, Period: &Duration{Duration: 168 * time.Hour}}}
+
+		err := g.Patch(&Garden{WaterBudget: &WaterBudget{ScaleDown: &scaleDown}})
+		require.Nil(t, err)
+		require.Equal(t, &WaterBudget{
+			MaxLiters: 500,
+			Period:    &Duration{Duration: 168 * time.Hour},
+			ScaleDown: &scaleDown,
+		}, g.WaterBudget)
+
+		err = g.Patch(&Garden{WaterBudget: &WaterBudget{}})
+		require.Nil(t, err)
+		require.Nil(t, g.WaterBudget)
+	})
+
+	t.Run("PatchUsers", func(t *testing.T) {
+		g := &Garden{Users: map[string]UserRole{"c5cvhpcbcv45e8bp16dg": UserRoleOwner}}
+
+		err := g.Patch(&Garden{})
+		require.Nil(t, err)
+		require.Equal(t, map[string]UserRole{"c5cvhpcbcv45e8bp16dg": UserRoleOwner}, g.Users)
+
+		err = g.Patch(&Garden{Users: map[string]UserRole{"c5cvhpcbcv45e8bp16dg": UserRoleViewer}})
+		require.Nil(t, err)
+		require.Equal(t, UserRoleViewer, g.UserRole("c5cvhpcbcv45e8bp16dg"))
+
+		err = g.Patch(&Garden{Users: map[string]UserRole{}})
+		require.Nil(t, err)
+		require.Empty(t, g.Users)
+		require.Equal(t, UserRole(""), g.UserRole("c5cvhpcbcv45e8bp16dg"))
+	})
+
 	t.Run("PatchDoesNotAddEndDate", func(t *testing.T) {
 		now := time.Now()
 		g := &Garden{}
@@ -215,3 +396,34 @@ func TestHasTemperatureHumiditySensor(t *testing.T) {
 		assert.Equal(t, tt.expected, g.HasTemperatureHumiditySensor())
 	}
 }
+
+func TestGardenTimeLocation(t *testing.T) {
+	tests := []struct {
+		timeZone    string
+		expected    string
+		expectedErr string
+	}{
+		{"", "", ""},
+		{"America/Phoenix", "America/Phoenix", ""},
+		{"UTC", "UTC", ""},
+		{"Local", "", `invalid time_zone: "Local"`},
+		{"Mars/Olympus_Mons", "", "invalid time_zone: unknown time zone Mars/Olympus_Mons"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timeZone, func(t *testing.T) {
+			g := &Garden{TimeZone: tt.timeZone}
+			loc, err := g.TimeLocation()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, loc)
+				return
+			}
+			assert.Equal(t, tt.expected, loc.String())
+		})
+	}
+}
@@ -20,6 +20,14 @@ const (
 |> filter(fn: (r) => r["zone"] == "{{.ZonePosition}}")
 |> filter(fn: (r) => r["topic"] == "{{.TopicPrefix}}/data/moisture")
 |> mean()`
+	moistureHistoryQueryTemplate = `from(bucket: "{{.Bucket}}")
+|> range(start: -{{.Start}})
+|> filter(fn: (r) => r["_measurement"] == "moisture")
+|> filter(fn: (r) => r["_field"] == "value")
+|> filter(fn: (r) => r["zone"] == "{{.ZonePosition}}")
+|> filter(fn: (r) => r["topic"] == "{{.TopicPrefix}}/data/moisture")
+|> aggregateWindow(every: {{.Resolution}}, fn: mean, timeSrc: "_start", createEmpty: false)
+|> yield(name: "mean")`
 	healthQueryTemplate = `from(bucket: "{{.Bucket}}")
 |> range(start: -{{.Start}})
 |> filter(fn: (r) => r["_measurement"] == "health")
@@ -31,16 +39,18 @@ const (
 |> filter(fn: (r) => r["_measurement"] == "water")
 |> filter(fn: (r) => r["topic"] == "{{.TopicPrefix}}/data/water")
 |> filter(fn: (r) => r["zone"] == "{{.ZonePosition}}")
+|> filter(fn: (r) => r["_field"] == "millis" or r["_field"] == "ml")
+|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
 |> sort(columns: ["_time"], desc: true)
 {{- if .Limit }}
 |> limit(n: {{.Limit}})
 {{- end }}
 |> yield(name: "last")`
-	temperatureAndHumidityQueryTemplate = `from(bucket: "{{.Bucket}}")
+	sensorQueryTemplate = `from(bucket: "{{.Bucket}}")
 |> range(start: -{{.Start}})
-|> filter(fn: (r) => r["_measurement"] == "temperature" or r["_measurement"] == "humidity")
+|> filter(fn: (r) => r["_measurement"] == "{{.Measurement}}")
 |> filter(fn: (r) => r["_field"] == "value")
-|> filter(fn: (r) => r["topic"] == "{{.TopicPrefix}}/data/temperature" or r["topic"] == "{{.TopicPrefix}}/data/humidity")
+|> filter(fn: (r) => r["topic"] == "{{.TopicPrefix}}/data/{{.Measurement}}")
 |> mean()`
 )
 
@@ -53,18 +63,26 @@ var influxDBClientSummary = prometheus.NewSummaryVec(prometheus.SummaryOpts{
 // Client is an interface that allows querying InfluxDB for data
 type Client interface {
 	GetMoisture(context.Context, uint, string) (float64, error)
+	GetMoistureHistory(context.Context, uint, string, time.Duration, time.Duration) ([]map[string]interface{}, error)
 	GetLastContact(context.Context, string) (time.Time, error)
 	GetWaterHistory(context.Context, uint, string, time.Duration, uint64) ([]map[string]interface{}, error)
-	GetTemperatureAndHumidity(context.Context, string) (float64, float64, error)
+	GetTemperature(context.Context, string) (float64, error)
+	GetHumidity(context.Context, string) (float64, error)
+	GetLightLevel(context.Context, string) (float64, error)
+	Write(context.Context, string, []byte) error
 	influxdb2.Client
 }
 
-// Config holds configuration values for connecting the the InfluxDB server
+// Config holds configuration values for connecting the the InfluxDB server. Version selects the query backend:
+// 2 (default) uses Flux and 3 uses SQL. InfluxDB 3 does not use the Org and the Bucket is the database name
 type Config struct {
 	Address string `mapstructure:"address"`
 	Token   string `mapstructure:"token"`
 	Org     string `mapstructure:"org"`
 	Bucket  string `mapstructure:"bucket"`
+	Version int    `mapstructure:"version"`
+	// Ingest enables writing data from controllers to InfluxDB with the garden-app instead of Telegraf
+	Ingest bool `mapstructure:"ingest"`
 }
 
 // queryData is used to fill out any of the query templates
@@ -74,6 +92,8 @@ type queryData struct {
 	ZonePosition uint
 	TopicPrefix  string
 	Limit        uint64
+	Measurement  string
+	Resolution   time.Duration
 }
 
 // Render executes the specified template with the queryData to create a string
@@ -134,6 +154,43 @@ func (client *client) GetMoisture(ctx context.Context, zonePosition uint, topicP
 	return result, queryResult.Err()
 }
 
+// GetMoistureHistory gets a Zone's average soil moisture for each window of the resolution in the time range
+func (client *client) GetMoistureHistory(ctx context.Context, zonePosition uint, topicPrefix string, timeRange, resolution time.Duration) ([]map[string]interface{}, error) {
+	timer := prometheus.NewTimer(influxDBClientSummary.WithLabelValues("GetMoistureHistory"))
+	defer timer.ObserveDuration()
+
+	// Prepare query
+	queryString, err := queryData{
+		Bucket:       client.config.Bucket,
+		Start:        timeRange,
+		TopicPrefix:  topicPrefix,
+		ZonePosition: zonePosition,
+		Resolution:   resolution,
+	}.Render(moistureHistoryQueryTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query InfluxDB
+	queryAPI := client.QueryAPI(client.config.Org)
+	queryResult, err := queryAPI.Query(ctx, queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read and return the result as slice of maps. Windows without any data are not included
+	result := []map[string]interface{}{}
+	for queryResult.Next() {
+		record := queryResult.Record()
+		value, _ := record.Value().(float64)
+		result = append(result, map[string]interface{}{
+			"Value":      value,
+			"RecordTime": record.Time(),
+		})
+	}
+	return result, queryResult.Err()
+}
+
 func (client *client) GetLastContact(ctx context.Context, topicPrefix string) (time.Time, error) {
 	timer := prometheus.NewTimer(influxDBClientSummary.WithLabelValues("GetLastContact"))
 	defer timer.ObserveDuration()
@@ -189,47 +246,83 @@ func (client *client) GetWaterHistory(ctx context.Context, zonePosition uint, to
 		return nil, err
 	}
 
-	// Read and return the result as slice of maps
+	// Read and return the result as slice of maps. Milliliters is only included when the controller has a flow meter
 	result := []map[string]interface{}{}
 	for queryResult.Next() {
-		result = append(result, map[string]interface{}{
-			"Duration":   int(queryResult.Record().Value().(float64)),
-			"RecordTime": queryResult.Record().Time(),
-		})
+		record := queryResult.Record()
+		millis, _ := record.ValueByKey("millis").(float64)
+		h := map[string]interface{}{
+			"Duration":   int(millis),
+			"RecordTime": record.Time(),
+		}
+		if ml, ok := record.ValueByKey("ml").(float64); ok {
+			h["Milliliters"] = ml
+		}
+		result = append(result, h)
 	}
 	return result, queryResult.Err()
 }
 
-// GetTemperatureAndHumidity gets the recent temperature and humidity data for a Garden
-func (client *client) GetTemperatureAndHumidity(ctx context.Context, topicPrefix string) (float64, float64, error) {
-	timer := prometheus.NewTimer(influxDBClientSummary.WithLabelValues("GetMoisture"))
+// GetTemperature returns the Garden's average temperature, in Celsius, in the last 15 minutes
+func (client *client) GetTemperature(ctx context.Context, topicPrefix string) (float64, error) {
+	timer := prometheus.NewTimer(influxDBClientSummary.WithLabelValues("GetTemperature"))
+	defer timer.ObserveDuration()
+
+	return client.getSensorMean(ctx, "temperature", topicPrefix)
+}
+
+// GetHumidity returns the Garden's average relative humidity percentage in the last 15 minutes
+func (client *client) GetHumidity(ctx context.Context, topicPrefix string) (float64, error) {
+	timer := prometheus.NewTimer(influxDBClientSummary.WithLabelValues("GetHumidity"))
+	defer timer.ObserveDuration()
+
+	return client.getSensorMean(ctx, "humidity", topicPrefix)
+}
+
+// GetLightLevel returns the Garden's average ambient light level, in lux, in the last 15 minutes
+func (client *client) GetLightLevel(ctx context.Context, topicPrefix string) (float64, error) {
+	timer := prometheus.NewTimer(influxDBClientSummary.WithLabelValues("GetLightLevel"))
 	defer timer.ObserveDuration()
 
+	return client.getSensorMean(ctx, "light_level", topicPrefix)
+}
+
+// Write adds the topic tag to a message published by a controller and writes it to InfluxDB
+func (client *client) Write(ctx context.Context, topic string, payload []byte) error {
+	timer := prometheus.NewTimer(influxDBClientSummary.WithLabelValues("Write"))
+	defer timer.ObserveDuration()
+
+	line, err := AddTopicTag(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	writeAPI := client.WriteAPIBlocking(client.config.Org, client.config.Bucket)
+	return writeAPI.WriteRecord(ctx, line)
+}
+
+// getSensorMean queries the average value of a Garden's sensor measurement, which the controller publishes on
+// "{topicPrefix}/data/{measurement}". It returns 0 if there is no recent data
+func (client *client) getSensorMean(ctx context.Context, measurement, topicPrefix string) (float64, error) {
 	queryString, err := queryData{
 		Bucket:      client.config.Bucket,
 		Start:       time.Minute * 15,
 		TopicPrefix: topicPrefix,
-	}.Render(temperatureAndHumidityQueryTemplate)
+		Measurement: measurement,
+	}.Render(sensorQueryTemplate)
 	if err != nil {
-		return 0, 0, err
+		return 0, err
 	}
 
 	queryAPI := client.QueryAPI(client.config.Org)
 	queryResult, err := queryAPI.Query(ctx, queryString)
 	if err != nil {
-		return 0, 0, err
+		return 0, err
 	}
 
-	var temperature float64
-	var humidity float64
-	for queryResult.Next() {
-		switch queryResult.Record().Measurement() {
-		case "temperature":
-			temperature = queryResult.Record().Value().(float64)
-		case "humidity":
-			humidity = queryResult.Record().Value().(float64)
-		}
+	var result float64
+	if queryResult.Next() {
+		result = queryResult.Record().Value().(float64)
 	}
-
-	return temperature, humidity, queryResult.Err()
+	return result, queryResult.Err()
 }
@@ -0,0 +1,26 @@
+package influxdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+var tagReplacer = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// AddTopicTag parses a message published by a controller, which is a line of InfluxDB line protocol, and adds the
+// MQTT topic as the "topic" tag. This is the same as Telegraf's mqtt_consumer, so data can be found for each Garden
+func AddTopicTag(topic string, payload []byte) (string, error) {
+	line := strings.TrimSpace(string(payload))
+
+	// The measurement ends at the first comma, which starts the tags, or space, which starts the fields
+	i := strings.IndexAny(line, ", ")
+	if i <= 0 || !strings.Contains(line[i:], " ") {
+		return "", fmt.Errorf("invalid line protocol %q", line)
+	}
+	return fmt.Sprintf("%s,topic=%s%s", line[:i], EscapeTag(topic), line[i:]), nil
+}
+
+// EscapeTag escapes characters that are not allowed in line protocol tag values
+func EscapeTag(value string) string {
+	return tagReplacer.Replace(value)
+}
@@ -0,0 +1,66 @@
+package influxdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddTopicTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		topic    string
+		payload  string
+		expected string
+	}{
+		{
+			"Moisture",
+			"test-garden/data/moisture",
+			"moisture,zone=1 value=40",
+			"moisture,topic=test-garden/data/moisture,zone=1 value=40",
+		},
+		{
+			"WaterWithFlowMeter",
+			"test-garden/data/water",
+			"water,zone=0 millis=6000,ml=1500\n",
+			"water,topic=test-garden/data/water,zone=0 millis=6000,ml=1500",
+		},
+		{
+			"Light",
+			"test-garden/data/light",
+			`light,garden="test-garden" state=1`,
+			`light,topic=test-garden/data/light,garden="test-garden" state=1`,
+		},
+		{
+			"HealthWithoutTags",
+			"test-garden/data/health",
+			`health garden="test-garden"`,
+			`health,topic=test-garden/data/health garden="test-garden"`,
+		},
+		{
+			"TopicWithSpace",
+			"test garden/data/humidity",
+			"humidity value=40",
+			`humidity,topic=test\ garden/data/humidity value=40`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, err := AddTopicTag(tt.topic, []byte(tt.payload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, line)
+		})
+	}
+
+	t.Run("ErrorMissingFields", func(t *testing.T) {
+		_, err := AddTopicTag("test-garden/data/moisture", []byte("moisture,zone=1"))
+		assert.EqualError(t, err, `invalid line protocol "moisture,zone=1"`)
+	})
+
+	t.Run("ErrorEmpty", func(t *testing.T) {
+		_, err := AddTopicTag("test-garden/data/moisture", []byte(""))
+		assert.EqualError(t, err, `invalid line protocol ""`)
+	})
+}
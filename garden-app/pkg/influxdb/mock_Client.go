@@ -92,6 +92,30 @@ func (_m *MockClient) DeleteAPI() api.DeleteAPI {
 	return r0
 }
 
+// GetHumidity provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) GetHumidity(_a0 context.Context, _a1 string) (float64, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) float64); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastContact provides a mock function with given fields: _a0, _a1
 func (_m *MockClient) GetLastContact(_a0 context.Context, _a1 string) (time.Time, error) {
 	ret := _m.Called(_a0, _a1)
@@ -116,6 +140,30 @@ func (_m *MockClient) GetLastContact(_a0 context.Context, _a1 string) (time.Time
 	return r0, r1
 }
 
+// GetLightLevel provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) GetLightLevel(_a0 context.Context, _a1 string) (float64, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) float64); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMoisture provides a mock function with given fields: _a0, _a1, _a2
 func (_m *MockClient) GetMoisture(_a0 context.Context, _a1 uint, _a2 string) (float64, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -140,14 +188,39 @@ func (_m *MockClient) GetMoisture(_a0 context.Context, _a1 uint, _a2 string) (fl
 	return r0, r1
 }
 
-// GetTemperatureAndHumidity provides a mock function with given fields: _a0, _a1
-func (_m *MockClient) GetTemperatureAndHumidity(_a0 context.Context, _a1 string) (float64, float64, error) {
+// GetMoistureHistory provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *MockClient) GetMoistureHistory(_a0 context.Context, _a1 uint, _a2 string, _a3 time.Duration, _a4 time.Duration) ([]map[string]interface{}, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 []map[string]interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, time.Duration, time.Duration) ([]map[string]interface{}, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, time.Duration, time.Duration) []map[string]interface{}); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]map[string]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, time.Duration, time.Duration) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTemperature provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) GetTemperature(_a0 context.Context, _a1 string) (float64, error) {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 float64
-	var r1 float64
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, float64, error)); ok {
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, error)); ok {
 		return rf(_a0, _a1)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, string) float64); ok {
@@ -156,19 +229,13 @@ func (_m *MockClient) GetTemperatureAndHumidity(_a0 context.Context, _a1 string)
 		r0 = ret.Get(0).(float64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) float64); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
-		r1 = ret.Get(1).(float64)
-	}
-
-	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
-		r2 = rf(_a0, _a1)
-	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
 // GetWaterHistory provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
@@ -451,6 +518,20 @@ func (_m *MockClient) UsersAPI() api.UsersAPI {
 	return r0
 }
 
+// Write provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockClient) Write(_a0 context.Context, _a1 string, _a2 []byte) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WriteAPI provides a mock function with given fields: org, bucket
 func (_m *MockClient) WriteAPI(org string, bucket string) api.WriteAPI {
 	ret := _m.Called(org, bucket)
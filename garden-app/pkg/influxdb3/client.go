@@ -0,0 +1,291 @@
+package influxdb3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+)
+
+const (
+	queryPath = "/api/v3/query_sql"
+	writePath = "/api/v3/write_lp"
+	pingPath  = "/health"
+	// recentRange is how far back to look for the most recent data, which is the same as the InfluxDB 2.x client
+	recentRange = 15 * time.Minute
+)
+
+// timeLayouts are used to parse times from query results since InfluxDB 3 does not include the time zone
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"}
+
+// Client queries InfluxDB 3.x with SQL using its HTTP query API. This is used instead of Flux, which is not supported
+// by InfluxDB 3. Measurements are tables and the Config's Bucket is the database
+type Client struct {
+	config     influxdb.Config
+	httpClient *http.Client
+}
+
+// NewClient creates an InfluxDB 3 Client from the config
+func NewClient(config influxdb.Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{},
+	}
+}
+
+// queryRequest is the body of a request to the SQL query API. Params are used for any values from user input
+type queryRequest struct {
+	Database string         `json:"db"`
+	Query    string         `json:"q"`
+	Format   string         `json:"format"`
+	Params   map[string]any `json:"params,omitempty"`
+}
+
+// GetMoisture returns the Zone's average soil moisture in the last 15 minutes
+func (c *Client) GetMoisture(ctx context.Context, zonePosition uint, topicPrefix string) (float64, error) {
+	rows, err := c.query(ctx, fmt.Sprintf(`SELECT AVG(value) AS value FROM moisture
+WHERE time >= now() - %s AND zone = $zone AND topic = $topic`, interval(recentRange)), zoneParams(zonePosition, topicPrefix, "moisture"))
+	if err != nil {
+		return 0, err
+	}
+	return firstFloat(rows, "value"), nil
+}
+
+// GetMoistureHistory gets a Zone's average soil moisture for each window of the resolution in the time range
+func (c *Client) GetMoistureHistory(ctx context.Context, zonePosition uint, topicPrefix string, timeRange, resolution time.Duration) ([]map[string]interface{}, error) {
+	rows, err := c.query(ctx, fmt.Sprintf(`SELECT date_bin(%s, time) AS time, AVG(value) AS value FROM moisture
+WHERE time >= now() - %s AND zone = $zone AND topic = $topic
+GROUP BY 1 ORDER BY 1`, interval(resolution), interval(timeRange)), zoneParams(zonePosition, topicPrefix, "moisture"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Windows without any data are not included, which is the same as the InfluxDB 2.x client
+	result := []map[string]interface{}{}
+	for _, row := range rows {
+		value, ok := row["value"].(float64)
+		if !ok {
+			continue
+		}
+		recordTime, err := parseTime(row["time"])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, map[string]interface{}{
+			"Value":      value,
+			"RecordTime": recordTime,
+		})
+	}
+	return result, nil
+}
+
+// GetLastContact returns the time of the most recent health data published by the controller
+func (c *Client) GetLastContact(ctx context.Context, topicPrefix string) (time.Time, error) {
+	rows, err := c.query(ctx, fmt.Sprintf(`SELECT MAX(time) AS time FROM health
+WHERE time >= now() - %s AND garden = $garden`, interval(recentRange)), map[string]any{"garden": topicPrefix})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(rows) == 0 || rows[0]["time"] == nil {
+		return time.Time{}, nil
+	}
+	return parseTime(rows[0]["time"])
+}
+
+// GetWaterHistory gets recent water events for a specific Zone. The result uses the same keys as the InfluxDB 2.x
+// client and Milliliters is only included when the controller has a flow meter
+func (c *Client) GetWaterHistory(ctx context.Context, zonePosition uint, topicPrefix string, timeRange time.Duration, limit uint64) ([]map[string]interface{}, error) {
+	// All columns are selected since the ml column only exists if a controller has a flow meter
+	query := fmt.Sprintf(`SELECT * FROM water
+WHERE time >= now() - %s AND zone = $zone AND topic = $topic
+ORDER BY time DESC`, interval(timeRange))
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := c.query(ctx, query, zoneParams(zonePosition, topicPrefix, "water"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, row := range rows {
+		recordTime, err := parseTime(row["time"])
+		if err != nil {
+			return nil, err
+		}
+		millis, _ := row["millis"].(float64)
+		h := map[string]interface{}{
+			"Duration":   int(millis),
+			"RecordTime": recordTime,
+		}
+		if ml, ok := row["ml"].(float64); ok {
+			h["Milliliters"] = ml
+		}
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+// GetTemperature returns the Garden's average temperature, in Celsius, in the last 15 minutes
+func (c *Client) GetTemperature(ctx context.Context, topicPrefix string) (float64, error) {
+	return c.getSensorMean(ctx, "temperature", topicPrefix)
+}
+
+// GetHumidity returns the Garden's average relative humidity percentage in the last 15 minutes
+func (c *Client) GetHumidity(ctx context.Context, topicPrefix string) (float64, error) {
+	return c.getSensorMean(ctx, "humidity", topicPrefix)
+}
+
+// GetLightLevel returns the Garden's average ambient light level, in lux, in the last 15 minutes
+func (c *Client) GetLightLevel(ctx context.Context, topicPrefix string) (float64, error) {
+	return c.getSensorMean(ctx, "light_level", topicPrefix)
+}
+
+// Close closes idle connections to the server
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// Ping checks if the server is running and the token is allowed to use it
+func (c *Client) Ping(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.config.Address, "/")+pingPath, http.NoBody)
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return false, fmt.Errorf("error pinging InfluxDB: %w", err)
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// Write adds the topic tag to a message published by a controller and writes it to the database
+func (c *Client) Write(ctx context.Context, topic string, payload []byte) error {
+	line, err := influxdb.AddTopicTag(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	writeURL := fmt.Sprintf("%s%s?db=%s", strings.TrimSuffix(c.config.Address, "/"), writePath, url.QueryEscape(c.config.Bucket))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, writeURL, strings.NewReader(line))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("error writing to InfluxDB: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// getSensorMean queries the average value of a Garden's sensor measurement. The measurement is never user input
+func (c *Client) getSensorMean(ctx context.Context, measurement, topicPrefix string) (float64, error) {
+	rows, err := c.query(ctx, fmt.Sprintf(`SELECT AVG(value) AS value FROM %s
+WHERE time >= now() - %s AND topic = $topic`, measurement, interval(recentRange)), map[string]any{"topic": fmt.Sprintf("%s/data/%s", topicPrefix, measurement)})
+	if err != nil {
+		return 0, err
+	}
+	return firstFloat(rows, "value"), nil
+}
+
+// query executes the SQL query and returns each row as a map of column name to value
+func (c *Client) query(ctx context.Context, query string, params map[string]any) ([]map[string]any, error) {
+	body, err := json.Marshal(queryRequest{
+		Database: c.config.Bucket,
+		Query:    query,
+		Format:   "json",
+		Params:   params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.config.Address, "/")+queryPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error querying InfluxDB: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var rows []map[string]any
+	err = json.NewDecoder(resp.Body).Decode(&rows)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding query result: %w", err)
+	}
+	return rows, nil
+}
+
+// do executes the request with authorization. An error is returned, and the body is closed, for non-2xx responses
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return resp, nil
+}
+
+// zoneParams creates the query parameters for a Zone's data on the controller's topic
+func zoneParams(zonePosition uint, topicPrefix, measurement string) map[string]any {
+	return map[string]any{
+		"zone":  strconv.FormatUint(uint64(zonePosition), 10),
+		"topic": fmt.Sprintf("%s/data/%s", topicPrefix, measurement),
+	}
+}
+
+// interval creates a SQL interval from the duration. Whole milliseconds are used since that is the precision of
+// the data from controllers
+func interval(d time.Duration) string {
+	return fmt.Sprintf("INTERVAL '%d milliseconds'", d.Milliseconds())
+}
+
+// firstFloat returns the column from the first row, or 0 if there is no data
+func firstFloat(rows []map[string]any, column string) float64 {
+	if len(rows) == 0 {
+		return 0
+	}
+	result, _ := rows[0][column].(float64)
+	return result
+}
+
+// parseTime parses a time from a query result. Times without a time zone are UTC
+func parseTime(value any) (time.Time, error) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected time value: %v", value)
+	}
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, s, time.UTC)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse time %q", s)
+}
@@ -0,0 +1,189 @@
+package influxdb3
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient creates a Client for a server that responds with the result and records each request
+func newTestClient(t *testing.T, status int, result string) (*Client, *[]queryRequest) {
+	t.Helper()
+
+	requests := []queryRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, queryPath, r.URL.Path)
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+
+		var req queryRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(result))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(influxdb.Config{Address: server.URL, Token: "my-token", Bucket: "garden", Version: 3})
+	t.Cleanup(client.Close)
+	return client, &requests
+}
+
+func TestGetMoisture(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, `[{"value":42.5}]`)
+
+	moisture, err := client.GetMoisture(context.Background(), 1, "test-garden")
+	require.NoError(t, err)
+	assert.Equal(t, 42.5, moisture)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "garden", req.Database)
+	assert.Equal(t, "json", req.Format)
+	assert.Contains(t, req.Query, "FROM moisture")
+	assert.Contains(t, req.Query, "INTERVAL '900000 milliseconds'")
+	assert.Equal(t, map[string]any{"zone": "1", "topic": "test-garden/data/moisture"}, req.Params)
+}
+
+func TestGetMoistureNoData(t *testing.T) {
+	client, _ := newTestClient(t, http.StatusOK, `[{}]`)
+
+	moisture, err := client.GetMoisture(context.Background(), 0, "test-garden")
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, moisture)
+}
+
+func TestGetMoistureHistory(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, `[{"time":"2023-08-01T10:00:00","value":40},{"time":"2023-08-01T11:00:00"}]`)
+
+	history, err := client.GetMoistureHistory(context.Background(), 0, "test-garden", 72*time.Hour, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"Value": 40.0, "RecordTime": time.Date(2023, time.August, 1, 10, 0, 0, 0, time.UTC)},
+	}, history)
+
+	require.Len(t, *requests, 1)
+	assert.Contains(t, (*requests)[0].Query, "date_bin(INTERVAL '3600000 milliseconds', time)")
+}
+
+func TestGetLastContact(t *testing.T) {
+	t.Run("Successful", func(t *testing.T) {
+		client, requests := newTestClient(t, http.StatusOK, `[{"time":"2023-08-01T10:00:00.5"}]`)
+
+		lastContact, err := client.GetLastContact(context.Background(), "test-garden")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, time.August, 1, 10, 0, 0, 500000000, time.UTC), lastContact)
+		assert.Equal(t, map[string]any{"garden": "test-garden"}, (*requests)[0].Params)
+	})
+
+	t.Run("NoData", func(t *testing.T) {
+		client, _ := newTestClient(t, http.StatusOK, `[{"time":null}]`)
+
+		lastContact, err := client.GetLastContact(context.Background(), "test-garden")
+		require.NoError(t, err)
+		assert.True(t, lastContact.IsZero())
+	})
+}
+
+func TestGetWaterHistory(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, `[
+		{"time":"2023-08-01T11:00:00Z","millis":3000,"ml":250,"topic":"test-garden/data/water","zone":"0"},
+		{"time":"2023-08-01T10:00:00Z","millis":5000,"topic":"test-garden/data/water","zone":"0"}
+	]`)
+
+	history, err := client.GetWaterHistory(context.Background(), 0, "test-garden", 72*time.Hour, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"Duration": 3000, "RecordTime": time.Date(2023, time.August, 1, 11, 0, 0, 0, time.UTC), "Milliliters": 250.0},
+		{"Duration": 5000, "RecordTime": time.Date(2023, time.August, 1, 10, 0, 0, 0, time.UTC)},
+	}, history)
+
+	require.Len(t, *requests, 1)
+	assert.Contains(t, (*requests)[0].Query, "ORDER BY time DESC LIMIT 2")
+}
+
+func TestGetTemperatureAndHumidity(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, `[{"value":21.5}]`)
+
+	temperature, err := client.GetTemperature(context.Background(), "test-garden")
+	require.NoError(t, err)
+	assert.Equal(t, 21.5, temperature)
+
+	_, err = client.GetHumidity(context.Background(), "test-garden")
+	require.NoError(t, err)
+
+	_, err = client.GetLightLevel(context.Background(), "test-garden")
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 3)
+	assert.Contains(t, (*requests)[0].Query, "FROM temperature")
+	assert.Equal(t, map[string]any{"topic": "test-garden/data/temperature"}, (*requests)[0].Params)
+	assert.Contains(t, (*requests)[1].Query, "FROM humidity")
+	assert.Equal(t, map[string]any{"topic": "test-garden/data/humidity"}, (*requests)[1].Params)
+	assert.Contains(t, (*requests)[2].Query, "FROM light_level")
+	assert.Equal(t, map[string]any{"topic": "test-garden/data/light_level"}, (*requests)[2].Params)
+}
+
+func TestQueryError(t *testing.T) {
+	client, _ := newTestClient(t, http.StatusBadRequest, "table 'moisture' not found\n")
+
+	_, err := client.GetMoisture(context.Background(), 0, "test-garden")
+	assert.EqualError(t, err, "error querying InfluxDB: 400 Bad Request: table 'moisture' not found")
+}
+
+func TestWrite(t *testing.T) {
+	var path, db, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		path = r.URL.Path
+		db = r.URL.Query().Get("db")
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(influxdb.Config{Address: server.URL, Token: "my-token", Bucket: "garden", Version: 3})
+	defer client.Close()
+
+	err := client.Write(context.Background(), "test-garden/data/moisture", []byte("moisture,zone=1 value=40"))
+	require.NoError(t, err)
+
+	assert.Equal(t, writePath, path)
+	assert.Equal(t, "garden", db)
+	assert.Equal(t, "moisture,topic=test-garden/data/moisture,zone=1 value=40", body)
+}
+
+func TestPing(t *testing.T) {
+	var path string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		path = r.URL.Path
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	client := NewClient(influxdb.Config{Address: server.URL, Token: "my-token", Bucket: "garden", Version: 3})
+	defer client.Close()
+
+	ok, err := client.Ping(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, pingPath, path)
+
+	status = http.StatusUnauthorized
+	ok, err = client.Ping(context.Background())
+	assert.EqualError(t, err, "error pinging InfluxDB: 401 Unauthorized: OK")
+	assert.False(t, ok)
+}
@@ -0,0 +1,129 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+)
+
+// Draft is the JSON Schema version used by For
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// xidPattern matches the string representation of an xid.ID
+const xidPattern = "^[0-9a-v]{20}$"
+
+// Schema is the subset of JSON Schema that is needed to describe the resources
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Description          string             `json:"description,omitempty"`
+}
+
+// Provider is implemented by types that have custom JSON encoding, so their schema can't be created from the Go type
+type Provider interface {
+	JSONSchema() *Schema
+}
+
+var (
+	providerType  = reflect.TypeOf((*Provider)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+	xidType       = reflect.TypeOf(xid.ID{})
+	babyapiIDType = reflect.TypeOf(babyapi.ID{})
+)
+
+// For creates a Schema for the JSON representation of the value's type. Field names come from the json struct tags
+// and fields with "-" are skipped
+func For(v any) *Schema {
+	return forType(reflect.TypeOf(v), map[reflect.Type]bool{})
+}
+
+func forType(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if reflect.PointerTo(t).Implements(providerType) {
+		return reflect.New(t).Interface().(Provider).JSONSchema()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case xidType, babyapiIDType:
+		return &Schema{Type: "string", Pattern: xidPattern}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		minimum := float64(0)
+		return &Schema{Type: "integer", Minimum: &minimum}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: forType(t.Elem(), seen)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: forType(t.Elem(), seen)}
+	case reflect.Struct:
+		// Recursive types are allowed to be any object instead of being expanded forever
+		if seen[t] {
+			return &Schema{Type: "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		result := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		addProperties(result, t, seen)
+		return result
+	default:
+		// Interfaces can be any value
+		return &Schema{}
+	}
+}
+
+// addProperties adds a property for each exported field. Embedded structs without a json name are flattened like
+// they are by encoding/json
+func addProperties(s *Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			addProperties(s, fieldType, seen)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = forType(field.Type, seen)
+	}
+}
@@ -0,0 +1,72 @@
+package jsonschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+)
+
+type customType struct{}
+
+func (*customType) JSONSchema() *Schema {
+	return &Schema{Type: "string", Format: "custom"}
+}
+
+type embedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type recursive struct {
+	Children []recursive `json:"children"`
+}
+
+type testStruct struct {
+	embedded
+
+	Name      string            `json:"name"`
+	Count     *uint             `json:"count,omitempty"`
+	Enabled   bool              `json:"enabled"`
+	Ratio     float64           `json:"ratio"`
+	Tags      []string          `json:"tags"`
+	Options   map[string]any    `json:"options"`
+	ID        xid.ID            `json:"id"`
+	CreatedAt *time.Time        `json:"created_at"`
+	Custom    *customType       `json:"custom"`
+	Nested    recursive         `json:"nested"`
+	Skipped   string            `json:"-"`
+	NoTag     int               `json:",omitempty"`
+	Labels    map[string]string `json:"labels"`
+	private   string
+}
+
+func TestFor(t *testing.T) {
+	zero := float64(0)
+	expected := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"embedded":   {Type: "string"},
+			"name":       {Type: "string"},
+			"count":      {Type: "integer", Minimum: &zero},
+			"enabled":    {Type: "boolean"},
+			"ratio":      {Type: "number"},
+			"tags":       {Type: "array", Items: &Schema{Type: "string"}},
+			"options":    {Type: "object", AdditionalProperties: &Schema{}},
+			"id":         {Type: "string", Pattern: xidPattern},
+			"created_at": {Type: "string", Format: "date-time"},
+			"custom":     {Type: "string", Format: "custom"},
+			"nested": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"children": {Type: "array", Items: &Schema{Type: "object"}},
+				},
+			},
+			"NoTag":  {Type: "integer"},
+			"labels": {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		},
+	}
+
+	assert.Equal(t, expected, For(testStruct{private: "unused"}))
+	assert.Equal(t, expected, For(&testStruct{}))
+}
@@ -2,9 +2,12 @@ package pkg
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/jsonschema"
 )
 
 const (
@@ -28,6 +31,12 @@ var (
 	}
 )
 
+// GardenLightState is the last light state published by a Garden's controller
+type GardenLightState struct {
+	State     LightState `json:"state"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
 // LightState is an enum representing the state of a Light (ON or OFF)
 type LightState int
 
@@ -36,6 +45,11 @@ func (l LightState) String() string {
 	return stateToString[l]
 }
 
+// JSONSchema describes a LightState as one of its string representations. Empty toggles the light
+func (l *LightState) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "string", Enum: stateToString}
+}
+
 // MarshalJSON will convert LightState into it's JSON string representation
 func (l LightState) MarshalJSON() ([]byte, error) {
 	if int(l) >= len(stateToString) {
@@ -63,12 +77,98 @@ func (l *LightState) unmarshal(data []byte) error {
 	return nil
 }
 
+// untilPrefix is used in a LightSchedule's JSON duration to turn the light off at a SunTime instead of after a Duration
+const untilPrefix = "until "
+
 // LightSchedule allows the user to control when the Garden light is turned on and off
 // "Time" should be in the format of LightTimeFormat constant ("15:04:05-07:00")
+//
+// Start and Until can be used instead of StartTime and Duration to turn the light on and off relative to sunrise
+// and sunset at the Garden's Location. In JSON, Until is set using the duration field: "until sunset-1h"
 type LightSchedule struct {
-	Duration    *Duration  `json:"duration" yaml:"duration"`
-	StartTime   *StartTime `json:"start_time" yaml:"start_time"`
-	AdhocOnTime *time.Time `json:"adhoc_on_time,omitempty" yaml:"adhoc_on_time,omitempty"`
+	Duration     *Duration            `json:"duration" yaml:"duration"`
+	StartTime    *StartTime           `json:"start_time" yaml:"start_time"`
+	Start        *SunTime             `json:"start,omitempty" yaml:"start,omitempty"`
+	Until        *SunTime             `json:"-" yaml:"until,omitempty"`
+	AdhocOnTime  *time.Time           `json:"adhoc_on_time,omitempty" yaml:"adhoc_on_time,omitempty"`
+	AmbientLight *AmbientLightControl `json:"ambient_light,omitempty" yaml:"ambient_light,omitempty"`
+}
+
+// DefaultAmbientLightCheckInterval is how often the light level is checked again after turning the light on is
+// delayed when AmbientLightControl.CheckInterval is not set
+const DefaultAmbientLightCheckInterval = 15 * time.Minute
+
+// AmbientLightControl delays turning the light on while a light sensor measures at least LuxThreshold. The light
+// level is checked again every CheckInterval until it is below the threshold or the light is scheduled to turn off
+type AmbientLightControl struct {
+	LuxThreshold  float64   `json:"lux_threshold" yaml:"lux_threshold"`
+	CheckInterval *Duration `json:"check_interval,omitempty" yaml:"check_interval,omitempty"`
+}
+
+// Interval returns the CheckInterval or the default if it is not set
+func (alc *AmbientLightControl) Interval() time.Duration {
+	if alc.CheckInterval == nil || alc.CheckInterval.Duration == 0 {
+		return DefaultAmbientLightCheckInterval
+	}
+	return alc.CheckInterval.Duration
+}
+
+// Validate checks that the threshold and interval are not negative
+func (alc *AmbientLightControl) Validate() error {
+	if alc.LuxThreshold < 0 {
+		return errors.New("lux_threshold must not be negative")
+	}
+	if alc.CheckInterval != nil && alc.CheckInterval.Duration < 0 {
+		return errors.New("check_interval must not be negative")
+	}
+	return nil
+}
+
+// HasAmbientLightControl is used to determine if the light level should be checked before turning the light on
+func (ls *LightSchedule) HasAmbientLightControl() bool {
+	return ls != nil && ls.AmbientLight != nil && ls.AmbientLight.LuxThreshold > 0
+}
+
+// lightScheduleJSON has the same fields as LightSchedule without the custom JSON methods
+type lightScheduleJSON LightSchedule
+
+// MarshalJSON writes Until into the duration field when it is used
+func (ls *LightSchedule) MarshalJSON() ([]byte, error) {
+	var duration any = ls.Duration
+	if ls.Until != nil {
+		duration = untilPrefix + ls.Until.String()
+	}
+
+	return json.Marshal(struct {
+		Duration any `json:"duration"`
+		*lightScheduleJSON
+	}{duration, (*lightScheduleJSON)(ls)})
+}
+
+// UnmarshalJSON reads the duration field as either a Duration or "until" followed by a SunTime
+func (ls *LightSchedule) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*lightScheduleJSON
+		Duration json.RawMessage `json:"duration"`
+	}{lightScheduleJSON: (*lightScheduleJSON)(ls)}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	if len(aux.Duration) == 0 || string(aux.Duration) == "null" {
+		return nil
+	}
+
+	var durationString string
+	if json.Unmarshal(aux.Duration, &durationString) == nil && strings.HasPrefix(durationString, untilPrefix) {
+		ls.Until, err = SunTimeFromString(strings.TrimPrefix(durationString, untilPrefix))
+		return err
+	}
+
+	ls.Duration = &Duration{}
+	return json.Unmarshal(aux.Duration, ls.Duration)
 }
 
 // String...
@@ -76,15 +176,120 @@ func (ls *LightSchedule) String() string {
 	return fmt.Sprintf("%+v", *ls)
 }
 
-// Patch allows modifying the struct in-place with values from a different instance
+// UsesSunTimes returns true if the LightSchedule is relative to sunrise or sunset, so it requires a Location
+func (ls *LightSchedule) UsesSunTimes() bool {
+	return ls.Start != nil || ls.Until != nil
+}
+
+// NextTime returns the first time after the provided time that the light will change to the specified state.
+// The Location is only used when the LightSchedule uses sunrise or sunset. If tz is set, StartTime is used as the
+// local time in that time zone instead of using its offset
+func (ls *LightSchedule) NextTime(state LightState, loc *Location, tz *time.Location, after time.Time) (time.Time, error) {
+	if ls.UsesSunTimes() && loc == nil {
+		return time.Time{}, errors.New("unable to use sunrise or sunset without a location")
+	}
+
+	// Start from the previous day since the light might turn off a day after it turns on. The upper limit allows
+	// searching past a polar night or midnight sun
+	date := after.UTC().AddDate(0, 0, -1)
+	for i := 0; i < 368; i++ {
+		onTime, offTime, err := ls.timesOn(loc, tz, date.AddDate(0, 0, i))
+		if errors.Is(err, ErrNoSunEvent) {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		next := offTime
+		if state == LightStateOn {
+			next = onTime
+		}
+		if next.After(after) {
+			return next, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to find next %s time", state)
+}
+
+// timesOn returns the times that the light turns on and off for the light cycle starting on the date
+func (ls *LightSchedule) timesOn(loc *Location, tz *time.Location, date time.Time) (time.Time, time.Time, error) {
+	var onTime time.Time
+	switch {
+	case ls.Start != nil:
+		var err error
+		onTime, err = ls.Start.On(loc, date)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	case ls.StartTime != nil:
+		startTime := ls.StartTime.Time
+		if tz == nil {
+			tz = startTime.Location()
+		}
+		onTime = time.Date(
+			date.Year(), date.Month(), date.Day(),
+			startTime.Hour(), startTime.Minute(), startTime.Second(), 0,
+			tz,
+		)
+	default:
+		return time.Time{}, time.Time{}, errors.New("missing start time")
+	}
+
+	switch {
+	case ls.Until != nil:
+		offTime, err := ls.Until.On(loc, date)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		// If the light turns off before it turns on, it is on overnight and turns off the next day
+		if !offTime.After(onTime) {
+			offTime, err = ls.Until.On(loc, date.AddDate(0, 0, 1))
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+		}
+		return onTime, offTime, nil
+	case ls.Duration != nil:
+		return onTime, onTime.Add(ls.Duration.Duration), nil
+	default:
+		return time.Time{}, time.Time{}, errors.New("missing duration")
+	}
+}
+
+// Patch allows modifying the struct in-place with values from a different instance. Setting StartTime or Duration
+// replaces Start or Until, and the other way around
 func (ls *LightSchedule) Patch(new *LightSchedule) {
 	if new.Duration != nil {
 		ls.Duration = new.Duration
+		ls.Until = nil
 	}
 	if new.StartTime != nil {
 		ls.StartTime = new.StartTime
+		ls.Start = nil
+	}
+	if new.Start != nil {
+		ls.Start = new.Start
+		ls.StartTime = nil
+	}
+	if new.Until != nil {
+		ls.Until = new.Until
+		ls.Duration = nil
 	}
 	if new.AdhocOnTime == nil {
 		ls.AdhocOnTime = nil
 	}
+	// A LuxThreshold of 0 removes the AmbientLightControl
+	if new.AmbientLight != nil {
+		ls.AmbientLight = new.AmbientLight
+		if new.AmbientLight.LuxThreshold == 0 {
+			ls.AmbientLight = nil
+		}
+	}
+}
+
+// isEmpty returns true if none of the fields that define a schedule are set
+func (ls *LightSchedule) isEmpty() bool {
+	return ls.Duration == nil && ls.StartTime == nil && ls.Start == nil && ls.Until == nil
 }
@@ -3,6 +3,10 @@ package pkg
 import (
 	"encoding/json"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLightStateString(t *testing.T) {
@@ -138,3 +142,108 @@ func TestLightStateMarshal(t *testing.T) {
 		}
 	})
 }
+
+func TestLightScheduleJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"StartTimeAndDuration",
+			`{"duration":"15h","start_time":"22:00:01-07:00"}`,
+			`{"duration":"15h0m0s","start_time":"22:00:01-07:00"}`,
+		},
+		{
+			"StartAndUntil",
+			`{"start":"sunrise+30m","duration":"until sunset-1h"}`,
+			`{"duration":"until sunset-1h0m0s","start_time":null,"start":"sunrise+30m0s"}`,
+		},
+		{
+			"StartAndDuration",
+			`{"start":"sunset","duration":"4h"}`,
+			`{"duration":"4h0m0s","start_time":null,"start":"sunset"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ls LightSchedule
+			require.NoError(t, json.Unmarshal([]byte(tt.input), &ls))
+
+			result, err := json.Marshal(&ls)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result))
+		})
+	}
+
+	t.Run("ErrorInvalidUntil", func(t *testing.T) {
+		var ls LightSchedule
+		err := json.Unmarshal([]byte(`{"duration":"until noon"}`), &ls)
+		assert.EqualError(t, err, `invalid sun time "noon": must start with "sunrise" or "sunset"`)
+	})
+}
+
+func TestLightScheduleNextTime(t *testing.T) {
+	phoenix := &Location{Latitude: 33.45, Longitude: -112.07}
+	// 2023-06-01 08:00 in Phoenix (UTC-7)
+	now := time.Date(2023, time.June, 1, 15, 0, 0, 0, time.UTC)
+	sunrise, sunset, err := phoenix.SunTimes(now)
+	require.NoError(t, err)
+
+	startTime, err := StartTimeFromString("22:00:00-07:00")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		lightSchedule *LightSchedule
+		expectedOn    time.Time
+		expectedOff   time.Time
+	}{
+		{
+			"StartAndUntil",
+			&LightSchedule{
+				Start: &SunTime{Event: SunEventSunrise, Offset: 30 * time.Minute},
+				Until: &SunTime{Event: SunEventSunset, Offset: -1 * time.Hour},
+			},
+			sunrise.AddDate(0, 0, 1).Add(30 * time.Minute).Truncate(time.Minute),
+			sunset.Add(-1 * time.Hour),
+		},
+		{
+			"StartAndDuration",
+			&LightSchedule{
+				Start:    &SunTime{Event: SunEventSunset},
+				Duration: &Duration{Duration: 2 * time.Hour},
+			},
+			sunset,
+			sunset.Add(2 * time.Hour),
+		},
+		{
+			"StartTimeAndUntilOvernight",
+			&LightSchedule{
+				StartTime: startTime,
+				Until:     &SunTime{Event: SunEventSunrise},
+			},
+			time.Date(2023, time.June, 2, 5, 0, 0, 0, time.UTC),
+			sunrise.AddDate(0, 0, 1).Truncate(time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			on, err := tt.lightSchedule.NextTime(LightStateOn, phoenix, nil, now)
+			require.NoError(t, err)
+			assert.WithinDuration(t, tt.expectedOn, on, time.Minute)
+
+			off, err := tt.lightSchedule.NextTime(LightStateOff, phoenix, nil, now)
+			require.NoError(t, err)
+			assert.WithinDuration(t, tt.expectedOff, off, time.Minute)
+		})
+	}
+
+	t.Run("ErrorMissingLocation", func(t *testing.T) {
+		ls := &LightSchedule{Start: &SunTime{Event: SunEventSunrise}, Duration: &Duration{Duration: time.Hour}}
+		_, err := ls.NextTime(LightStateOn, nil, nil, now)
+		assert.EqualError(t, err, "unable to use sunrise or sunset without a location")
+	})
+}
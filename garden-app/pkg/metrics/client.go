@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb3"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/promql"
+)
+
+const (
+	DriverInfluxDB   = "influxdb"
+	DriverPrometheus = "prometheus"
+)
+
+// Config selects the metrics backend with Driver. InfluxDB is used by default and is configured by the top-level
+// influxdb config. Prometheus uses a Prometheus-compatible store, like VictoriaMetrics
+type Config struct {
+	Driver     string        `mapstructure:"driver"`
+	Prometheus promql.Config `mapstructure:"prometheus"`
+}
+
+// Client is an interface that allows querying the time-series data that garden-controllers publish. It is
+// implemented by the Flux-based client for InfluxDB 2.x, the SQL-based client for InfluxDB 3.x, and the PromQL-based
+// client for Prometheus-compatible stores
+type Client interface {
+	GetMoisture(context.Context, uint, string) (float64, error)
+	GetMoistureHistory(context.Context, uint, string, time.Duration, time.Duration) ([]map[string]interface{}, error)
+	GetLastContact(context.Context, string) (time.Time, error)
+	GetWaterHistory(context.Context, uint, string, time.Duration, uint64) ([]map[string]interface{}, error)
+	GetTemperature(context.Context, string) (float64, error)
+	GetHumidity(context.Context, string) (float64, error)
+	GetLightLevel(context.Context, string) (float64, error)
+	// Write stores a message that a controller published on the topic
+	Write(context.Context, string, []byte) error
+	Close()
+}
+
+// Pinger is implemented by Clients that can check if the backend is running. The InfluxDB 2.x client gets this from
+// the underlying InfluxDB client
+type Pinger interface {
+	Ping(context.Context) (bool, error)
+}
+
+var (
+	_ Client = (influxdb.Client)(nil)
+	_ Client = (*influxdb3.Client)(nil)
+	_ Client = (*promql.Client)(nil)
+
+	_ Pinger = (*influxdb3.Client)(nil)
+	_ Pinger = (*promql.Client)(nil)
+)
+
+// NewClient creates the Client for the configured driver. When using InfluxDB, the client depends on the configured
+// version and InfluxDB 2.x is used by default
+func NewClient(config Config, influxdbConfig influxdb.Config) (Client, error) {
+	switch config.Driver {
+	case "", DriverInfluxDB:
+		return newInfluxDBClient(influxdbConfig)
+	case DriverPrometheus:
+		if config.Prometheus.Address == "" {
+			return nil, errors.New("missing required prometheus address")
+		}
+		return promql.NewClient(config.Prometheus), nil
+	default:
+		return nil, fmt.Errorf("invalid metrics driver %q: must be %q or %q", config.Driver, DriverInfluxDB, DriverPrometheus)
+	}
+}
+
+func newInfluxDBClient(config influxdb.Config) (Client, error) {
+	switch config.Version {
+	case 0, 2:
+		return influxdb.NewClient(config), nil
+	case 3:
+		return influxdb3.NewClient(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported InfluxDB version %d: must be 2 or 3", config.Version)
+	}
+}
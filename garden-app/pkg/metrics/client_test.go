@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb3"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Run("InfluxDB3", func(t *testing.T) {
+		client, err := NewClient(Config{}, influxdb.Config{Address: "http://localhost:8181", Bucket: "garden", Version: 3})
+		require.NoError(t, err)
+		assert.IsType(t, &influxdb3.Client{}, client)
+	})
+
+	t.Run("ErrorUnsupportedVersion", func(t *testing.T) {
+		_, err := NewClient(Config{}, influxdb.Config{Version: 1})
+		assert.EqualError(t, err, "unsupported InfluxDB version 1: must be 2 or 3")
+	})
+
+	t.Run("Prometheus", func(t *testing.T) {
+		client, err := NewClient(Config{
+			Driver:     DriverPrometheus,
+			Prometheus: promql.Config{Address: "http://localhost:8428"},
+		}, influxdb.Config{})
+		require.NoError(t, err)
+		assert.IsType(t, &promql.Client{}, client)
+	})
+
+	t.Run("ErrorMissingPrometheusAddress", func(t *testing.T) {
+		_, err := NewClient(Config{Driver: DriverPrometheus}, influxdb.Config{})
+		assert.EqualError(t, err, "missing required prometheus address")
+	})
+
+	t.Run("ErrorInvalidDriver", func(t *testing.T) {
+		_, err := NewClient(Config{Driver: "graphite"}, influxdb.Config{})
+		assert.EqualError(t, err, `invalid metrics driver "graphite": must be "influxdb" or "prometheus"`)
+	})
+}
@@ -0,0 +1,155 @@
+package mqtt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// InMemoryClient is a Client that delivers published messages directly to subscribed handlers in the same
+// process instead of using a broker. Messages are delivered synchronously, which makes it useful for simulations
+type InMemoryClient struct {
+	mu sync.RWMutex
+	Config
+	defaultHandler mqtt.MessageHandler
+	handlers       []TopicHandler
+}
+
+// NewInMemoryClient creates an InMemoryClient with the same arguments as NewClient
+func NewInMemoryClient(config Config, defaultHandler mqtt.MessageHandler, handlers ...TopicHandler) *InMemoryClient {
+	return &InMemoryClient{
+		Config:         config,
+		defaultHandler: defaultHandler,
+		handlers:       handlers,
+	}
+}
+
+// Subscribe adds a new handler for the topic. The topic can use the "+" and "#" wildcards
+func (c *InMemoryClient) Subscribe(topic string, handler mqtt.MessageHandler) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, TopicHandler{Topic: topic, Handler: handler})
+	return nil
+}
+
+// Unsubscribe removes all handlers for the topic
+func (c *InMemoryClient) Unsubscribe(topic string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = slices.DeleteFunc(c.handlers, func(h TopicHandler) bool {
+		return h.Topic == topic
+	})
+	return nil
+}
+
+// Publish delivers the message to all handlers with a matching topic, or the default handler if none match
+func (c *InMemoryClient) Publish(topic string, message []byte) error {
+	if len(topic) == 0 {
+		return fmt.Errorf("unable to publish with an empty topic")
+	}
+
+	// Copy matching handlers so they can publish their own messages without deadlocking
+	c.mu.RLock()
+	matches := []mqtt.MessageHandler{}
+	for _, h := range c.handlers {
+		if topicMatches(h.Topic, topic) {
+			matches = append(matches, h.Handler)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(matches) == 0 && c.defaultHandler != nil {
+		matches = append(matches, c.defaultHandler)
+	}
+
+	msg := &inMemoryMessage{topic: topic, payload: message}
+	for _, handler := range matches {
+		handler(nil, msg)
+	}
+	return nil
+}
+
+// WaterTopic returns the topic string for watering a zone
+func (c *InMemoryClient) WaterTopic(topicPrefix string) (string, error) {
+	return c.Config.WaterTopic(topicPrefix)
+}
+
+// StopTopic returns the topic string for stopping watering a single zone
+func (c *InMemoryClient) StopTopic(topicPrefix string) (string, error) {
+	return c.Config.StopTopic(topicPrefix)
+}
+
+// StopAllTopic returns the topic string for stopping watering all zones in a garden
+func (c *InMemoryClient) StopAllTopic(topicPrefix string) (string, error) {
+	return c.Config.StopAllTopic(topicPrefix)
+}
+
+// LightTopic returns the topic string for changing the light state in a Garden
+func (c *InMemoryClient) LightTopic(topicPrefix string) (string, error) {
+	return c.Config.LightTopic(topicPrefix)
+}
+
+// RecirculationTopic returns the topic string for changing the state of a hydroponic Garden's recirculation pump
+func (c *InMemoryClient) RecirculationTopic(topicPrefix string) (string, error) {
+	return c.Config.RecirculationTopic(topicPrefix)
+}
+
+// ConfigTopic returns the topic string for sending config changes to a Garden's controller
+func (c *InMemoryClient) ConfigTopic(topicPrefix string) (string, error) {
+	return c.Config.ConfigTopic(topicPrefix)
+}
+
+// FirmwareUpdateTopic returns the topic string for sending firmware updates to a Garden's controller
+func (c *InMemoryClient) FirmwareUpdateTopic(topicPrefix string) (string, error) {
+	return c.Config.FirmwareUpdateTopic(topicPrefix)
+}
+
+// Connect does nothing since there is no broker
+func (c *InMemoryClient) Connect() error {
+	return nil
+}
+
+// IsConnected is always true since there is no broker
+func (c *InMemoryClient) IsConnected() bool {
+	return true
+}
+
+// Disconnect does nothing since there is no broker
+func (c *InMemoryClient) Disconnect(uint) {}
+
+// topicMatches checks if the topic matches the subscription filter, which can use "+" to match a single
+// level or "#" to match all remaining levels
+func topicMatches(filter, topic string) bool {
+	filterParts := strings.Split(filter, "/")
+	topicParts := strings.Split(topic, "/")
+
+	for i, part := range filterParts {
+		if part == "#" {
+			return true
+		}
+		if i >= len(topicParts) {
+			return false
+		}
+		if part != "+" && part != topicParts[i] {
+			return false
+		}
+	}
+	return len(filterParts) == len(topicParts)
+}
+
+// inMemoryMessage implements the paho Message interface for messages delivered by the InMemoryClient
+type inMemoryMessage struct {
+	topic   string
+	payload []byte
+}
+
+func (m *inMemoryMessage) Duplicate() bool   { return false }
+func (m *inMemoryMessage) Qos() byte         { return 1 }
+func (m *inMemoryMessage) Retained() bool    { return false }
+func (m *inMemoryMessage) Topic() string     { return m.topic }
+func (m *inMemoryMessage) MessageID() uint16 { return 0 }
+func (m *inMemoryMessage) Payload() []byte   { return m.payload }
+func (m *inMemoryMessage) Ack()              {}
@@ -0,0 +1,59 @@
+package mqtt
+
+import (
+	"testing"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		filter   string
+		topic    string
+		expected bool
+	}{
+		{"garden/data/water", "garden/data/water", true},
+		{"+/data/water", "garden/data/water", true},
+		{"+/data/water", "garden/data/light", false},
+		{"garden/#", "garden/data/water", true},
+		{"garden/+", "garden/data/water", false},
+		{"garden/data/water/extra", "garden/data/water", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter+"_"+tt.topic, func(t *testing.T) {
+			assert.Equal(t, tt.expected, topicMatches(tt.filter, tt.topic))
+		})
+	}
+}
+
+func TestInMemoryClientPublish(t *testing.T) {
+	var defaultMessages, waterMessages []string
+	client := NewInMemoryClient(
+		Config{WaterTopicTemplate: "{{.Garden}}/command/water"},
+		func(_ mqtt.Client, msg mqtt.Message) { defaultMessages = append(defaultMessages, msg.Topic()) },
+	)
+	err := client.Subscribe("+/command/water", func(_ mqtt.Client, msg mqtt.Message) {
+		waterMessages = append(waterMessages, string(msg.Payload()))
+	})
+	assert.NoError(t, err)
+
+	topic, err := client.WaterTopic("garden")
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Publish(topic, []byte("water")))
+	assert.NoError(t, client.Publish("garden/command/light", []byte("light")))
+	assert.Error(t, client.Publish("", nil))
+
+	assert.Equal(t, []string{"water"}, waterMessages)
+	assert.Equal(t, []string{"garden/command/light"}, defaultMessages)
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		assert.NoError(t, client.Unsubscribe("+/command/water"))
+		assert.NoError(t, client.Publish(topic, []byte("water")))
+
+		assert.Equal(t, []string{"water"}, waterMessages)
+		assert.Equal(t, []string{"garden/command/light", "garden/command/water"}, defaultMessages)
+	})
+}
@@ -9,6 +9,30 @@ type MockClient struct {
 	mock.Mock
 }
 
+// ConfigTopic provides a mock function with given fields: _a0
+func (_m *MockClient) ConfigTopic(_a0 string) (string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Connect provides a mock function with given fields:
 func (_m *MockClient) Connect() error {
 	ret := _m.Called()
@@ -28,6 +52,30 @@ func (_m *MockClient) Disconnect(_a0 uint) {
 	_m.Called(_a0)
 }
 
+// FirmwareUpdateTopic provides a mock function with given fields: _a0
+func (_m *MockClient) FirmwareUpdateTopic(_a0 string) (string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LightTopic provides a mock function with given fields: _a0
 func (_m *MockClient) LightTopic(_a0 string) (string, error) {
 	ret := _m.Called(_a0)
@@ -66,6 +114,30 @@ func (_m *MockClient) Publish(_a0 string, _a1 []byte) error {
 	return r0
 }
 
+// RecirculationTopic provides a mock function with given fields: _a0
+func (_m *MockClient) RecirculationTopic(_a0 string) (string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StopAllTopic provides a mock function with given fields: _a0
 func (_m *MockClient) StopAllTopic(_a0 string) (string, error) {
 	ret := _m.Called(_a0)
@@ -2,11 +2,16 @@ package mqtt
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
-	"html/template"
 	"log/slog"
+	"os"
+	"slices"
 	"sync"
+	"text/template"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,16 +23,87 @@ var mqttClientSummary = prometheus.NewSummaryVec(prometheus.SummaryOpts{
 	Help:      "summary of MQTT client calls",
 }, []string{"function", "topic"})
 
+var mqttPublishFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "garden_app",
+	Name:      "mqtt_publish_failures",
+	Help:      "count of failed attempts to publish MQTT messages, including retries of queued messages",
+}, []string{"topic"})
+
+const (
+	defaultRecirculationTopicTemplate  = "{{.Garden}}/command/recirculation"
+	defaultConfigTopicTemplate         = "{{.Garden}}/command/config"
+	defaultFirmwareUpdateTopicTemplate = "{{.Garden}}/command/firmware_update"
+)
+
 // Config is used to read the necessary configuration values from a YAML file
 type Config struct {
-	ClientID string `mapstructure:"client_id"`
-	Broker   string `mapstructure:"broker"`
-	Port     int    `mapstructure:"port"`
+	ClientID string     `mapstructure:"client_id"`
+	Broker   string     `mapstructure:"broker"`
+	Port     int        `mapstructure:"port"`
+	TLS      *TLSConfig `mapstructure:"tls"`
+
+	// PublishQueueSize is the maximum number of messages to queue while the broker is unavailable. The default is
+	// 100 and a negative value disables the queue so publishing returns an error instead
+	PublishQueueSize int `mapstructure:"publish_queue_size"`
+	// PublishQueueTTL is how long a queued message can wait to be published before it is dropped. The default is 10m
+	PublishQueueTTL time.Duration `mapstructure:"publish_queue_ttl"`
 
 	WaterTopicTemplate   string `mapstructure:"water_topic"`
 	StopTopicTemplate    string `mapstructure:"stop_topic"`
 	StopAllTopicTemplate string `mapstructure:"stop_all_topic"`
 	LightTopicTemplate   string `mapstructure:"light_topic"`
+	// RecirculationTopicTemplate defaults to "{{.Garden}}/command/recirculation" since it is only used by
+	// hydroponic Gardens
+	RecirculationTopicTemplate string `mapstructure:"recirculation_topic"`
+	// ConfigTopicTemplate defaults to "{{.Garden}}/command/config" and is used to send config changes to
+	// controllers without reflashing their firmware
+	ConfigTopicTemplate string `mapstructure:"config_topic"`
+	// FirmwareUpdateTopicTemplate defaults to "{{.Garden}}/command/firmware_update" and is used to tell controllers
+	// to download and install new firmware
+	FirmwareUpdateTopicTemplate string `mapstructure:"firmware_update_topic"`
+}
+
+// TLSConfig enables connecting to the broker with TLS. The certificate and key fields are paths to PEM files.
+// CACert is only needed if the broker's certificate is not signed by a CA trusted by the system, and ClientCert
+// and ClientKey are used for brokers that require client certificates, like AWS IoT
+type TLSConfig struct {
+	CACert             string `mapstructure:"ca_cert"`
+	ClientCert         string `mapstructure:"client_cert"`
+	ClientKey          string `mapstructure:"client_key"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// Config creates a *tls.Config by reading the configured certificate files
+func (c *TLSConfig) Config() (*tls.Config, error) {
+	//nolint:gosec // InsecureSkipVerify is only used if explicitly configured
+	result := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CACert != "" {
+		caCert, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA certificate: %w", err)
+		}
+		result.RootCAs = x509.NewCertPool()
+		if !result.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no valid certificates found in CA certificate %q", c.CACert)
+		}
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, errors.New("client_cert and client_key must be used together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		result.Certificates = []tls.Certificate{cert}
+	}
+
+	return result, nil
 }
 
 // Client is an interface that allows access to MQTT functionality within the garden-app
@@ -37,15 +113,39 @@ type Client interface {
 	StopTopic(string) (string, error)
 	StopAllTopic(string) (string, error)
 	LightTopic(string) (string, error)
+	RecirculationTopic(string) (string, error)
+	ConfigTopic(string) (string, error)
+	FirmwareUpdateTopic(string) (string, error)
 	Connect() error
 	Disconnect(uint)
 }
 
+// Subscriber is implemented by Clients that can subscribe to topics after they are created, like when a new
+// WeatherClient uses a topic
+type Subscriber interface {
+	Subscribe(topic string, handler mqtt.MessageHandler) error
+	Unsubscribe(topic string) error
+}
+
+// ConnectionChecker is implemented by Clients that can report if they are currently connected to the broker
+type ConnectionChecker interface {
+	IsConnected() bool
+}
+
+var (
+	_ ConnectionChecker = (*client)(nil)
+	_ ConnectionChecker = (*InMemoryClient)(nil)
+)
+
 // client is a wrapper struct for connecting our config and MQTT Client. It implements the Client interface
 type client struct {
-	mu sync.Mutex
 	mqtt.Client
 	Config
+	queue *publishQueue
+
+	// handlers are subscribed each time the client connects
+	handlers   []TopicHandler
+	handlersMu sync.Mutex
 }
 
 // TopicHandler is a struct that contains a topic string and MessageHandler for instructing the client how to handle topics
@@ -58,28 +158,35 @@ type TopicHandler struct {
 // using the supplied functions to handle incoming messages. It really should be used with only one function,
 // but I wanted to make it an optional argument, which required using the variadic function argument
 func NewClient(config Config, defaultHandler mqtt.MessageHandler, handlers ...TopicHandler) (Client, error) {
-	opts := mqtt.NewClientOptions().AddBroker(fmt.Sprintf("tcp://%s:%d", config.Broker, config.Port))
+	opts := mqtt.NewClientOptions()
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.Config()
+		if err != nil {
+			return nil, fmt.Errorf("invalid MQTT TLS config: %w", err)
+		}
+		opts.AddBroker(fmt.Sprintf("ssl://%s:%d", config.Broker, config.Port))
+		opts.SetTLSConfig(tlsConfig)
+	} else {
+		opts.AddBroker(fmt.Sprintf("tcp://%s:%d", config.Broker, config.Port))
+	}
+	c := &client{Config: config, handlers: handlers}
+
 	opts.ClientID = config.ClientID
 	opts.AutoReconnect = true
 	opts.CleanSession = false
-	if len(handlers) > 0 {
-		opts.OnConnect = func(c mqtt.Client) {
-			for _, handler := range handlers {
-				if token := c.Subscribe(handler.Topic, byte(1), handler.Handler); token.Wait() && token.Error() != nil {
-					// TODO: can I return an error instead of panicking (recover maybe?)
-					panic(token.Error())
-				}
-			}
-		}
-	}
+	opts.OnConnect = c.subscribeAll
 	opts.DefaultPublishHandler = defaultHandler
 
-	err := prometheus.Register(mqttClientSummary)
-	if err != nil && errors.Is(err, prometheus.AlreadyRegisteredError{}) {
-		return nil, err
+	for _, collector := range []prometheus.Collector{mqttClientSummary, mqttPublishQueueGauge, mqttPublishQueueDropped, mqttPublishFailures} {
+		err := prometheus.Register(collector)
+		if err != nil && errors.Is(err, prometheus.AlreadyRegisteredError{}) {
+			return nil, err
+		}
 	}
 
-	return &client{Client: mqtt.NewClient(opts), Config: config}, nil
+	c.Client = mqtt.NewClient(opts)
+	c.queue = newPublishQueue(config.PublishQueueSize, config.PublishQueueTTL, c.publish)
+	return c, nil
 }
 
 // Connect uses the MQTT Client's Connect function but returns the error instead of Token
@@ -95,20 +202,80 @@ func (c *client) Connect() error {
 	return token.Error()
 }
 
-// Publish will send the message to the specified MQTT topic
+// Publish will send the message to the specified MQTT topic. If the broker is unavailable, the message is queued
+// and retried in the background, and the returned error wraps ErrPublishQueued
 func (c *client) Publish(topic string, message []byte) error {
 	timer := prometheus.NewTimer(mqttClientSummary.WithLabelValues("Publish", topic))
 	defer timer.ObserveDuration()
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
 	if len(topic) == 0 {
 		return fmt.Errorf("unable to publish with an empty topic")
 	}
+	return c.queue.Publish(topic, message)
+}
+
+// Subscribe adds a handler for the topic. It is subscribed immediately if the client is connected and is
+// subscribed again after reconnecting
+func (c *client) Subscribe(topic string, handler mqtt.MessageHandler) error {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	c.handlers = append(c.handlers, TopicHandler{Topic: topic, Handler: handler})
+
+	if !c.Client.IsConnected() {
+		return nil
+	}
+	if token := c.Client.Subscribe(topic, byte(1), handler); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("unable to subscribe to topic %q: %w", topic, token.Error())
+	}
+	return nil
+}
+
+// Unsubscribe removes all handlers for the topic
+func (c *client) Unsubscribe(topic string) error {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	c.handlers = slices.DeleteFunc(c.handlers, func(h TopicHandler) bool {
+		return h.Topic == topic
+	})
+
+	if !c.Client.IsConnected() {
+		return nil
+	}
+	if token := c.Client.Unsubscribe(topic); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("unable to unsubscribe from topic %q: %w", topic, token.Error())
+	}
+	return nil
+}
+
+// subscribeAll is used when the client connects to subscribe to all handlers' topics
+func (c *client) subscribeAll(mc mqtt.Client) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	for _, handler := range c.handlers {
+		if token := mc.Subscribe(handler.Topic, byte(1), handler.Handler); token.Wait() && token.Error() != nil {
+			// TODO: can I return an error instead of panicking (recover maybe?)
+			panic(token.Error())
+		}
+	}
+}
+
+// Disconnect stops retrying queued messages and disconnects from the broker
+func (c *client) Disconnect(quiesce uint) {
+	c.queue.Close()
+	c.Client.Disconnect(quiesce)
+}
+
+// publish connects to the broker if necessary and publishes the message
+func (c *client) publish(topic string, message []byte) error {
 	if err := c.Connect(); err != nil {
+		mqttPublishFailures.WithLabelValues(topic).Inc()
 		return fmt.Errorf("unable to connect to MQTT broker: %v", err)
 	}
 	if token := c.Client.Publish(topic, byte(1), false, message); token.Wait() && token.Error() != nil {
+		mqttPublishFailures.WithLabelValues(topic).Inc()
 		return fmt.Errorf("unable to publish MQTT message: %v", token.Error())
 	}
 	return nil
@@ -134,12 +301,48 @@ func (c *Config) LightTopic(topicPrefix string) (string, error) {
 	return c.executeTopicTemplate(c.LightTopicTemplate, topicPrefix)
 }
 
+// RecirculationTopic returns the topic string for changing the state of a hydroponic Garden's recirculation pump
+func (c *Config) RecirculationTopic(topicPrefix string) (string, error) {
+	templateString := c.RecirculationTopicTemplate
+	if templateString == "" {
+		templateString = defaultRecirculationTopicTemplate
+	}
+	return c.executeTopicTemplate(templateString, topicPrefix)
+}
+
+// ConfigTopic returns the topic string for sending config changes to a Garden's controller
+func (c *Config) ConfigTopic(topicPrefix string) (string, error) {
+	templateString := c.ConfigTopicTemplate
+	if templateString == "" {
+		templateString = defaultConfigTopicTemplate
+	}
+	return c.executeTopicTemplate(templateString, topicPrefix)
+}
+
+// FirmwareUpdateTopic returns the topic string for sending firmware updates to a Garden's controller
+func (c *Config) FirmwareUpdateTopic(topicPrefix string) (string, error) {
+	templateString := c.FirmwareUpdateTopicTemplate
+	if templateString == "" {
+		templateString = defaultFirmwareUpdateTopicTemplate
+	}
+	return c.executeTopicTemplate(templateString, topicPrefix)
+}
+
 // executeTopicTemplate is a helper function used by all the exported topic evaluation functions
 func (c *Config) executeTopicTemplate(templateString string, topicPrefix string) (string, error) {
-	t := template.Must(template.New("topic").Parse(templateString))
+	return ExecuteTopicTemplate(templateString, topicPrefix)
+}
+
+// ExecuteTopicTemplate fills the topic template with the Garden's topic prefix as {{.Garden}}. This is also used
+// for a Garden's own TopicTemplates, so parsing errors are returned instead of panicking
+func ExecuteTopicTemplate(templateString string, topicPrefix string) (string, error) {
+	t, err := template.New("topic").Parse(templateString)
+	if err != nil {
+		return "", err
+	}
 	var result bytes.Buffer
 	data := map[string]string{"Garden": topicPrefix}
-	err := t.Execute(&result, data)
+	err = t.Execute(&result, data)
 	return result.String(), err
 }
 
@@ -0,0 +1,130 @@
+package mqtt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCert creates a self-signed certificate and key in the directory and returns their paths
+func writeTestCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "garden-app"},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir)
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name        string
+		config      TLSConfig
+		expectedErr string
+	}{
+		{"Empty", TLSConfig{}, ""},
+		{"InsecureSkipVerify", TLSConfig{InsecureSkipVerify: true}, ""},
+		{"CACert", TLSConfig{CACert: certFile}, ""},
+		{"ClientCert", TLSConfig{ClientCert: certFile, ClientKey: keyFile}, ""},
+		{
+			"ErrorMissingCACert",
+			TLSConfig{CACert: filepath.Join(dir, "missing.pem")},
+			"error reading CA certificate: open " + filepath.Join(dir, "missing.pem") + ": no such file or directory",
+		},
+		{
+			"ErrorInvalidCACert",
+			TLSConfig{CACert: invalidFile},
+			`no valid certificates found in CA certificate "` + invalidFile + `"`,
+		},
+		{
+			"ErrorMissingClientKey",
+			TLSConfig{ClientCert: certFile},
+			"client_cert and client_key must be used together",
+		},
+		{
+			"ErrorInvalidClientCert",
+			TLSConfig{ClientCert: invalidFile, ClientKey: keyFile},
+			"error loading client certificate: tls: failed to find any PEM data in certificate input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.config.Config()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.config.InsecureSkipVerify, result.InsecureSkipVerify)
+			assert.Equal(t, tt.config.CACert != "", result.RootCAs != nil)
+			assert.Equal(t, tt.config.ClientCert != "", len(result.Certificates) == 1)
+		})
+	}
+}
+
+func TestNewClientInvalidTLSConfig(t *testing.T) {
+	_, err := NewClient(Config{TLS: &TLSConfig{ClientKey: "key.pem"}}, nil)
+	assert.EqualError(t, err, "invalid MQTT TLS config: client_cert and client_key must be used together")
+}
+
+func TestPublishWhileDisconnected(t *testing.T) {
+	// Nothing is listening on port 1, so connecting to the broker fails
+	c, err := NewClient(Config{Broker: "127.0.0.1", Port: 1}, nil)
+	require.NoError(t, err)
+	defer c.Disconnect(0)
+
+	err = c.Publish("garden/action/water", []byte("message"))
+	assert.ErrorIs(t, err, ErrPublishQueued)
+	assert.ErrorContains(t, err, "unable to connect to MQTT broker")
+}
+
+func TestExecuteTopicTemplate(t *testing.T) {
+	t.Run("Successful", func(t *testing.T) {
+		topic, err := ExecuteTopicTemplate("cmnd/{{.Garden}}/POWER1", "tasmota_garden")
+		require.NoError(t, err)
+		assert.Equal(t, "cmnd/tasmota_garden/POWER1", topic)
+	})
+
+	t.Run("ErrorInvalidTemplate", func(t *testing.T) {
+		_, err := ExecuteTopicTemplate("{{.Garden}/command/water", "garden")
+		assert.Error(t, err)
+	})
+}
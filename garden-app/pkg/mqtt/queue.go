@@ -0,0 +1,215 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultPublishQueueSize = 100
+	defaultPublishQueueTTL  = 10 * time.Minute
+
+	minPublishRetryBackoff = time.Second
+	maxPublishRetryBackoff = time.Minute
+)
+
+// ErrPublishQueued is returned by Publish when a message could not be sent yet, so it is queued and retried in the
+// background. It wraps the error from sending the message
+var ErrPublishQueued = errors.New("MQTT message is queued until the broker is available")
+
+var mqttPublishQueueGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "garden_app",
+	Name:      "mqtt_publish_queue_messages",
+	Help:      "number of MQTT messages that are queued to be retried",
+})
+
+var mqttPublishQueueDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "garden_app",
+	Name:      "mqtt_publish_queue_dropped",
+	Help:      "count of queued MQTT messages that were dropped because the queue was full or they expired",
+}, []string{"reason"})
+
+// queuedMessage is a message that could not be published yet
+type queuedMessage struct {
+	topic    string
+	message  []byte
+	queuedAt time.Time
+	sent     bool
+}
+
+// publishQueue stores messages that could not be published, like when the broker is restarting, and retries them
+// in order with exponential backoff. When the queue is full, the oldest message is dropped. Messages older than the
+// TTL are also dropped since actions like watering should not run much later than they were scheduled
+type publishQueue struct {
+	mu       sync.Mutex
+	messages []*queuedMessage
+	send     func(topic string, message []byte) error
+
+	// sendMu makes sure messages are sent one at a time so they stay in order. The queue's lock is not held while
+	// sending, so a slow broker does not block adding messages
+	sendMu sync.Mutex
+
+	maxSize    int
+	ttl        time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	retrying  bool
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// newPublishQueue creates a publishQueue using send to publish messages. A negative maxSize disables the queue so
+// errors from send are returned
+func newPublishQueue(maxSize int, ttl time.Duration, send func(topic string, message []byte) error) *publishQueue {
+	if maxSize == 0 {
+		maxSize = defaultPublishQueueSize
+	}
+	if ttl == 0 {
+		ttl = defaultPublishQueueTTL
+	}
+	return &publishQueue{
+		send:       send,
+		maxSize:    maxSize,
+		ttl:        ttl,
+		minBackoff: minPublishRetryBackoff,
+		maxBackoff: maxPublishRetryBackoff,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Publish sends the message after any queued messages. If it cannot be sent, it is queued and retried in the
+// background, and the returned error wraps ErrPublishQueued and the error from sending
+func (q *publishQueue) Publish(topic string, message []byte) error {
+	if q.maxSize < 0 {
+		return q.send(topic, message)
+	}
+
+	msg := &queuedMessage{topic: topic, message: message, queuedAt: time.Now()}
+	q.mu.Lock()
+	q.push(msg)
+	q.mu.Unlock()
+
+	q.sendMu.Lock()
+	err := q.flush()
+	q.sendMu.Unlock()
+	if err == nil {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.retrying {
+		q.retrying = true
+		go q.retry()
+	}
+
+	switch {
+	// Another message failed after this one was sent
+	case msg.sent:
+		return nil
+	// The queue is full, so this message was dropped when it failed
+	case !slices.Contains(q.messages, msg):
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrPublishQueued, err)
+}
+
+// Len returns the number of queued messages
+func (q *publishQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.messages)
+}
+
+// Close stops retrying queued messages
+func (q *publishQueue) Close() {
+	q.closeOnce.Do(func() {
+		close(q.stop)
+	})
+}
+
+// push adds the message to the end of the queue, dropping the oldest message if it is full. It must be called while
+// holding the lock
+func (q *publishQueue) push(msg *queuedMessage) {
+	if len(q.messages) >= q.maxSize {
+		q.messages = q.messages[1:]
+		mqttPublishQueueDropped.WithLabelValues("full").Inc()
+	}
+	q.messages = append(q.messages, msg)
+	mqttPublishQueueGauge.Set(float64(len(q.messages)))
+}
+
+// flush sends queued messages in order until one fails. It must be called while holding sendMu. Each message is
+// removed from the queue while it is sent, so a full queue can't drop it, and it is put back at the front if it fails
+func (q *publishQueue) flush() error {
+	q.mu.Lock()
+	defer func() {
+		mqttPublishQueueGauge.Set(float64(len(q.messages)))
+		q.mu.Unlock()
+	}()
+
+	for len(q.messages) > 0 {
+		next := q.messages[0]
+		q.messages = q.messages[1:]
+		if time.Since(next.queuedAt) > q.ttl {
+			mqttPublishQueueDropped.WithLabelValues("expired").Inc()
+			continue
+		}
+
+		q.mu.Unlock()
+		err := q.send(next.topic, next.message)
+		q.mu.Lock()
+
+		if err != nil {
+			q.messages = append([]*queuedMessage{next}, q.messages...)
+			if len(q.messages) > q.maxSize {
+				q.messages = q.messages[1:]
+				mqttPublishQueueDropped.WithLabelValues("full").Inc()
+			}
+			return err
+		}
+		next.sent = true
+	}
+
+	return nil
+}
+
+// retry flushes the queue with exponential backoff until it is empty or the queue is closed
+func (q *publishQueue) retry() {
+	backoff := q.minBackoff
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-timer.C:
+		}
+
+		q.sendMu.Lock()
+		err := q.flush()
+		q.sendMu.Unlock()
+
+		// Publish could have queued another message after flushing, but it does not start retrying while this is
+		q.mu.Lock()
+		done := err == nil && len(q.messages) == 0
+		if done {
+			q.retrying = false
+		}
+		q.mu.Unlock()
+		if done {
+			return
+		}
+
+		backoff = min(2*backoff, q.maxBackoff)
+		timer.Reset(backoff)
+	}
+}
@@ -0,0 +1,190 @@
+package mqtt
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBroker records published messages and fails while it is unavailable
+type fakeBroker struct {
+	mu          sync.Mutex
+	unavailable bool
+	published   []string
+}
+
+func (b *fakeBroker) send(_ string, message []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.unavailable {
+		return errors.New("broker unavailable")
+	}
+	b.published = append(b.published, string(message))
+	return nil
+}
+
+func (b *fakeBroker) setUnavailable(unavailable bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unavailable = unavailable
+}
+
+func (b *fakeBroker) getPublished() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string{}, b.published...)
+}
+
+func newTestQueue(maxSize int, ttl time.Duration, broker *fakeBroker) *publishQueue {
+	q := newPublishQueue(maxSize, ttl, broker.send)
+	q.minBackoff = time.Millisecond
+	q.maxBackoff = 5 * time.Millisecond
+	return q
+}
+
+func TestPublishQueue(t *testing.T) {
+	t.Run("PublishedImmediately", func(t *testing.T) {
+		broker := &fakeBroker{}
+		q := newTestQueue(0, 0, broker)
+		defer q.Close()
+
+		assert.NoError(t, q.Publish("topic", []byte("1")))
+		assert.Equal(t, []string{"1"}, broker.getPublished())
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("QueuedAndRetriedInOrder", func(t *testing.T) {
+		broker := &fakeBroker{unavailable: true}
+		q := newTestQueue(0, 0, broker)
+		defer q.Close()
+
+		assert.ErrorIs(t, q.Publish("topic", []byte("1")), ErrPublishQueued)
+		assert.ErrorIs(t, q.Publish("topic", []byte("2")), ErrPublishQueued)
+		assert.Equal(t, 2, q.Len())
+		assert.Empty(t, broker.getPublished())
+
+		broker.setUnavailable(false)
+		assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"1", "2"}, broker.getPublished())
+	})
+
+	t.Run("OldestDroppedWhenFull", func(t *testing.T) {
+		broker := &fakeBroker{unavailable: true}
+		q := newTestQueue(2, 0, broker)
+		defer q.Close()
+
+		for _, msg := range []string{"1", "2", "3"} {
+			assert.ErrorIs(t, q.Publish("topic", []byte(msg)), ErrPublishQueued)
+		}
+		assert.Equal(t, 2, q.Len())
+
+		broker.setUnavailable(false)
+		assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"2", "3"}, broker.getPublished())
+	})
+
+	t.Run("ExpiredMessagesDropped", func(t *testing.T) {
+		broker := &fakeBroker{unavailable: true}
+		q := newTestQueue(0, 10*time.Millisecond, broker)
+		q.minBackoff = time.Hour
+		defer q.Close()
+
+		assert.ErrorIs(t, q.Publish("topic", []byte("1")), ErrPublishQueued)
+		time.Sleep(20 * time.Millisecond)
+
+		broker.setUnavailable(false)
+		assert.NoError(t, q.Publish("topic", []byte("2")))
+		assert.Equal(t, []string{"2"}, broker.getPublished())
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("QueuedErrorWrapsSendError", func(t *testing.T) {
+		broker := &fakeBroker{unavailable: true}
+		q := newTestQueue(0, 0, broker)
+		defer q.Close()
+
+		err := q.Publish("topic", []byte("1"))
+		assert.ErrorIs(t, err, ErrPublishQueued)
+		assert.EqualError(t, err, "MQTT message is queued until the broker is available: broker unavailable")
+	})
+
+	t.Run("NotLockedWhileSending", func(t *testing.T) {
+		sending := make(chan struct{})
+		release := make(chan struct{})
+		q := newPublishQueue(0, 0, func(string, []byte) error {
+			sending <- struct{}{}
+			<-release
+			return nil
+		})
+		defer q.Close()
+
+		done := make(chan error)
+		go func() {
+			done <- q.Publish("topic", []byte("1"))
+		}()
+		<-sending
+
+		// The message is removed from the queue while it is sent
+		assert.Equal(t, 0, q.Len())
+
+		close(release)
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("DroppedWhileSendingReturnsError", func(t *testing.T) {
+		sending := make(chan struct{}, 1)
+		release := make(chan struct{})
+		q := newPublishQueue(1, 0, func(_ string, message []byte) error {
+			if string(message) == "1" {
+				sending <- struct{}{}
+				<-release
+			}
+			return errors.New("broker unavailable")
+		})
+		defer q.Close()
+
+		first := make(chan error)
+		go func() {
+			first <- q.Publish("topic", []byte("1"))
+		}()
+		<-sending
+
+		second := make(chan error)
+		go func() {
+			second <- q.Publish("topic", []byte("2"))
+		}()
+		assert.Eventually(t, func() bool { return q.Len() == 1 }, time.Second, time.Millisecond)
+
+		// The first message is the oldest when it is put back, so it is dropped from the full queue
+		close(release)
+		err := <-first
+		assert.EqualError(t, err, "broker unavailable")
+		assert.NotErrorIs(t, err, ErrPublishQueued)
+		assert.ErrorIs(t, <-second, ErrPublishQueued)
+	})
+
+	t.Run("DisabledReturnsError", func(t *testing.T) {
+		broker := &fakeBroker{unavailable: true}
+		q := newTestQueue(-1, 0, broker)
+		defer q.Close()
+
+		assert.EqualError(t, q.Publish("topic", []byte("1")), "broker unavailable")
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("CloseStopsRetrying", func(t *testing.T) {
+		broker := &fakeBroker{unavailable: true}
+		q := newTestQueue(0, 0, broker)
+
+		assert.ErrorIs(t, q.Publish("topic", []byte("1")), ErrPublishQueued)
+		q.Close()
+
+		broker.setUnavailable(false)
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, 1, q.Len())
+		assert.Empty(t, broker.getPublished())
+	})
+}
@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications/email"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications/fake"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications/pushover"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications/telegram"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
 	"github.com/calvinmclean/babyapi"
 )
 
@@ -46,13 +49,13 @@ func (nc *Client) Bind(r *http.Request) error {
 	switch r.Method {
 	case http.MethodPut, http.MethodPost:
 		if nc.Name == "" {
-			return errors.New("missing required name field")
+			return validation.Missing("name")
 		}
 		if nc.Type == "" {
-			return errors.New("missing required type field")
+			return validation.Missing("type")
 		}
 		if nc.Options == nil {
-			return errors.New("missing required options field")
+			return validation.Missing("options")
 		}
 	}
 
@@ -107,6 +110,10 @@ func newClient(c *Client) (client, error) {
 	switch c.Type {
 	case "pushover":
 		client, err = pushover.NewClient(c.Options)
+	case "email":
+		client, err = email.NewClient(c.Options)
+	case "telegram":
+		client, err = telegram.NewClient(c.Options)
 	case "fake":
 		client, err = fake.NewClient(c.Options)
 	default:
@@ -0,0 +1,86 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const defaultPort = 587
+
+// sendMail is used to send the message and can be overridden in tests
+var sendMail = smtp.SendMail
+
+type Config struct {
+	Host     string   `json:"host,omitempty" yaml:"host,omitempty" mapstructure:"host,omitempty"`
+	Port     int      `json:"port,omitempty" yaml:"port,omitempty" mapstructure:"port,omitempty"`
+	Username string   `json:"username,omitempty" yaml:"username,omitempty" mapstructure:"username,omitempty"`
+	Password string   `json:"password,omitempty" yaml:"password,omitempty" mapstructure:"password,omitempty"`
+	From     string   `json:"from,omitempty" yaml:"from,omitempty" mapstructure:"from,omitempty"`
+	To       []string `json:"to,omitempty" yaml:"to,omitempty" mapstructure:"to,omitempty"`
+}
+
+type Client struct {
+	*Config
+	auth smtp.Auth
+}
+
+func NewClient(options map[string]interface{}) (*Client, error) {
+	client := &Client{}
+
+	err := mapstructure.Decode(options, &client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Host == "" {
+		return nil, errors.New("missing required host")
+	}
+	if client.From == "" {
+		return nil, errors.New("missing required from")
+	}
+	if len(client.To) == 0 {
+		return nil, errors.New("missing required to")
+	}
+	if client.Port == 0 {
+		client.Port = defaultPort
+	}
+
+	if client.Username != "" {
+		client.auth = smtp.PlainAuth("", client.Username, client.Password, client.Host)
+	}
+
+	return client, nil
+}
+
+func (c *Client) SendMessage(title, message string) error {
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	return sendMail(addr, c.auth, c.From, c.To, c.buildMessage(title, message))
+}
+
+func (c *Client) buildMessage(title, message string) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", c.From)
+	fmt.Fprintf(&sb, "To: %s\r\n", strings.Join(c.To, ", "))
+	fmt.Fprintf(&sb, "Subject: %s\r\n", encodeSubject(title))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(message)
+	sb.WriteString("\r\n")
+	return []byte(sb.String())
+}
+
+// encodeSubject removes line breaks so the title cannot add extra headers and encodes any non-ASCII characters
+func encodeSubject(title string) string {
+	lines := strings.FieldsFunc(title, func(r rune) bool {
+		return r == '\r' || r == '\n'
+	})
+	return mime.QEncoding.Encode("utf-8", strings.Join(lines, " "))
+}
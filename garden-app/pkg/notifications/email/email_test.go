@@ -0,0 +1,109 @@
+package email
+
+import (
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       map[string]interface{}
+		expectedError string
+	}{
+		{
+			"MissingHost",
+			map[string]interface{}{"from": "garden@example.com", "to": []interface{}{"me@example.com"}},
+			"missing required host",
+		},
+		{
+			"MissingFrom",
+			map[string]interface{}{"host": "smtp.example.com", "to": []interface{}{"me@example.com"}},
+			"missing required from",
+		},
+		{
+			"MissingTo",
+			map[string]interface{}{"host": "smtp.example.com", "from": "garden@example.com"},
+			"missing required to",
+		},
+		{
+			"Successful",
+			map[string]interface{}{"host": "smtp.example.com", "from": "garden@example.com", "to": []interface{}{"me@example.com"}},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.options)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, defaultPort, client.Port)
+			assert.Nil(t, client.auth)
+		})
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	client, err := NewClient(map[string]interface{}{
+		"host":     "smtp.example.com",
+		"port":     float64(2525),
+		"username": "user",
+		"password": "password",
+		"from":     "garden@example.com",
+		"to":       []interface{}{"me@example.com", "you@example.com"},
+	})
+	require.NoError(t, err)
+
+	defer func(original func(string, smtp.Auth, string, []string, []byte) error) {
+		sendMail = original
+	}(sendMail)
+
+	var sentAddr, sentFrom string
+	var sentTo []string
+	var sentMsg []byte
+	sendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		assert.NotNil(t, auth)
+		sentAddr, sentFrom, sentTo, sentMsg = addr, from, to, msg
+		return nil
+	}
+
+	err = client.SendMessage("Garden: Water Action Error", "something went wrong")
+	require.NoError(t, err)
+
+	assert.Equal(t, "smtp.example.com:2525", sentAddr)
+	assert.Equal(t, "garden@example.com", sentFrom)
+	assert.Equal(t, []string{"me@example.com", "you@example.com"}, sentTo)
+	assert.Equal(t, "From: garden@example.com\r\n"+
+		"To: me@example.com, you@example.com\r\n"+
+		"Subject: Garden: Water Action Error\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n"+
+		"\r\n"+
+		"something went wrong\r\n", string(sentMsg))
+}
+
+func TestEncodeSubject(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{"ASCII", "Garden: Water Action Error", "Garden: Water Action Error"},
+		{"HeaderInjection", "Garden\r\nBcc: attacker@example.com", "Garden Bcc: attacker@example.com"},
+		{"NonASCII", "Jardín: Water Action", "=?utf-8?q?Jard=C3=ADn:_Water_Action?="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, encodeSubject(tt.title))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const baseURI = "https://api.telegram.org"
+
+// Config holds the token for a Telegram bot created with @BotFather and the ID of the chat that it sends messages to
+type Config struct {
+	BotToken string `json:"bot_token,omitempty" yaml:"bot_token,omitempty" mapstructure:"bot_token,omitempty"`
+	ChatID   string `json:"chat_id,omitempty" yaml:"chat_id,omitempty" mapstructure:"chat_id,omitempty"`
+}
+
+type Client struct {
+	*Config
+	*http.Client
+	baseURL *url.URL
+}
+
+func NewClient(options map[string]interface{}) (*Client, error) {
+	client := &Client{Client: http.DefaultClient}
+
+	// WeakDecode allows chat_id to be a number since that is how Telegram shows it
+	err := mapstructure.WeakDecode(options, &client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.BotToken == "" {
+		return nil, errors.New("missing required bot_token")
+	}
+	if client.ChatID == "" {
+		return nil, errors.New("missing required chat_id")
+	}
+
+	client.baseURL, err = url.Parse(baseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+type sendMessageRequest struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type sendMessageResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (c *Client) SendMessage(title, message string) error {
+	reqBody, err := json.Marshal(sendMessageRequest{
+		ChatID: c.ChatID,
+		Text:   fmt.Sprintf("%s\n%s", title, message),
+	})
+	if err != nil {
+		return err
+	}
+
+	reqURL := *c.baseURL
+	reqURL.Path = fmt.Sprintf("/bot%s/sendMessage", c.BotToken)
+
+	req, err := http.NewRequest(http.MethodPost, reqURL.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		// the error includes the URL, which contains the bot token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body with status %d: %v", resp.StatusCode, err)
+	}
+
+	var result sendMessageResponse
+	err = json.Unmarshal(respBody, &result)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal response body with status %d: %w", resp.StatusCode, err)
+	}
+
+	if !result.OK {
+		return fmt.Errorf("error sending message with status %d: %s", resp.StatusCode, result.Description)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        map[string]interface{}
+		expectedErr    string
+		expectedChatID string
+	}{
+		{
+			"Successful",
+			map[string]interface{}{"bot_token": "token", "chat_id": "12345"},
+			"",
+			"12345",
+		},
+		{
+			"SuccessfulNumericChatID",
+			map[string]interface{}{"bot_token": "token", "chat_id": float64(-1001234567890)},
+			"",
+			"-1001234567890",
+		},
+		{
+			"ErrorMissingBotToken",
+			map[string]interface{}{"chat_id": "12345"},
+			"missing required bot_token",
+			"",
+		},
+		{
+			"ErrorMissingChatID",
+			map[string]interface{}{"bot_token": "token"},
+			"missing required chat_id",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.options)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedChatID, client.ChatID)
+		})
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		response    string
+		expectedErr string
+	}{
+		{
+			"Successful",
+			http.StatusOK,
+			`{"ok":true,"result":{}}`,
+			"",
+		},
+		{
+			"ErrorChatNotFound",
+			http.StatusBadRequest,
+			`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`,
+			"error sending message with status 400: Bad Request: chat not found",
+		},
+		{
+			"ErrorInvalidResponse",
+			http.StatusBadGateway,
+			`bad gateway`,
+			"unable to unmarshal response body with status 502: invalid character 'b' looking for beginning of value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/bottoken/sendMessage", r.URL.Path)
+
+				var req sendMessageRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, sendMessageRequest{ChatID: "12345", Text: "Garden: Controller Down\nlast contact was 10m ago"}, req)
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(map[string]interface{}{"bot_token": "token", "chat_id": "12345"})
+			require.NoError(t, err)
+			client.baseURL, err = url.Parse(server.URL)
+			require.NoError(t, err)
+
+			err = client.SendMessage("Garden: Controller Down", "last contact was 10m ago")
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package opensprinkler
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // the OpenSprinkler API requires an MD5 hash of the password
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+)
+
+// maxRunTime is the longest time that the API allows a station to be run manually
+const maxRunTime = 18 * time.Hour
+
+// resultMessages describe the result codes returned by the API. 1 is success
+var resultMessages = map[int]string{
+	2:  "unauthorized",
+	3:  "mismatch",
+	16: "data missing",
+	17: "out of range",
+	18: "data format error",
+	19: "RF code error",
+	32: "page not found",
+	48: "not permitted",
+}
+
+// Config is used to connect to an OpenSprinkler controller's HTTP API
+type Config struct {
+	Address string `json:"address" yaml:"address"`
+	// Password is the device password. The API requires an MD5 hash of it, which is created for each request
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// Validate makes sure the Address is an HTTP URL
+func (c *Config) Validate() error {
+	if c.Address == "" {
+		return validation.Missing("address")
+	}
+	u, err := url.Parse(c.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid address %q: must use http or https", c.Address)
+	}
+	return nil
+}
+
+// Client sends commands to an OpenSprinkler controller with its HTTP API
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewClient creates a Client from the config
+func NewClient(config Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// RunStation manually runs a station for the duration, which is rounded up to whole seconds. Stations are numbered
+// from 0, which is the same as Zone positions
+func (c *Client) RunStation(ctx context.Context, station uint, duration time.Duration) error {
+	if duration > maxRunTime {
+		return fmt.Errorf("duration %s is longer than the maximum of %s", duration, maxRunTime)
+	}
+	seconds := int(math.Ceil(duration.Seconds()))
+
+	return c.get(ctx, "/cm", url.Values{
+		"sid": {strconv.FormatUint(uint64(station), 10)},
+		"en":  {"1"},
+		"t":   {strconv.Itoa(seconds)},
+	})
+}
+
+// StopAll stops all running stations and clears the ones that are waiting to run
+func (c *Client) StopAll(ctx context.Context) error {
+	return c.get(ctx, "/cv", url.Values{"rsn": {"1"}})
+}
+
+func (c *Client) get(ctx context.Context, path string, params url.Values) error {
+	hash := md5.Sum([]byte(c.config.Password)) //nolint:gosec
+	params.Set("pw", hex.EncodeToString(hash[:]))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.config.Address, "/")+path+"?"+params.Encode(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to OpenSprinkler: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status from OpenSprinkler: %s", resp.Status)
+	}
+
+	var result struct {
+		Result int `json:"result"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return fmt.Errorf("error decoding OpenSprinkler response: %w", err)
+	}
+
+	if result.Result != 1 {
+		msg, ok := resultMessages[result.Result]
+		if !ok {
+			msg = "unknown error"
+		}
+		return fmt.Errorf("OpenSprinkler error %d: %s", result.Result, msg)
+	}
+	return nil
+}
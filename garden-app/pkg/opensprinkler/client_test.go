@@ -0,0 +1,111 @@
+package opensprinkler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// md5 of "opendoor", which is the default OpenSprinkler password
+const defaultPasswordHash = "a6d82bced638de3def1e9bbb4983225c"
+
+// newTestClient creates a Client for a server that responds with the result and records each request
+func newTestClient(t *testing.T, result string) (*Client, *[]*url.URL) {
+	t.Helper()
+
+	requests := []*url.URL{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL)
+		_, _ = w.Write([]byte(result))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{Address: server.URL, Password: "opendoor"}), &requests
+}
+
+func TestRunStation(t *testing.T) {
+	client, requests := newTestClient(t, `{"result":1}`)
+
+	err := client.RunStation(context.Background(), 2, 90500*time.Millisecond)
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "/cm", req.Path)
+	assert.Equal(t, url.Values{
+		"pw":  {defaultPasswordHash},
+		"sid": {"2"},
+		"en":  {"1"},
+		"t":   {"91"},
+	}, req.Query())
+}
+
+func TestRunStationErrorTooLong(t *testing.T) {
+	client, requests := newTestClient(t, `{"result":1}`)
+
+	err := client.RunStation(context.Background(), 0, 19*time.Hour)
+	assert.EqualError(t, err, "duration 19h0m0s is longer than the maximum of 18h0m0s")
+	assert.Empty(t, *requests)
+}
+
+func TestStopAll(t *testing.T) {
+	client, requests := newTestClient(t, `{"result":1}`)
+
+	err := client.StopAll(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "/cv", req.Path)
+	assert.Equal(t, url.Values{"pw": {defaultPasswordHash}, "rsn": {"1"}}, req.Query())
+}
+
+func TestResultError(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+		err    string
+	}{
+		{"Unauthorized", `{"result":2}`, "OpenSprinkler error 2: unauthorized"},
+		{"OutOfRange", `{"result":17}`, "OpenSprinkler error 17: out of range"},
+		{"Unknown", `{"result":99}`, "OpenSprinkler error 99: unknown error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, tt.result)
+
+			err := client.StopAll(context.Background())
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		err    string
+	}{
+		{"Valid", Config{Address: "http://192.168.1.50:8080"}, ""},
+		{"ErrorMissingAddress", Config{}, "missing required address field"},
+		{"ErrorInvalidScheme", Config{Address: "192.168.1.50"}, `invalid address "192.168.1.50": must use http or https`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package pkg
+
+import "time"
+
+// PendingJobType is the kind of one-time Job that a PendingJob schedules
+type PendingJobType string
+
+const (
+	// PendingJobDeferredWatering executes a WaterSchedule's watering after a BlackoutWindow ends
+	PendingJobDeferredWatering PendingJobType = "deferred_watering"
+	// PendingJobWaterCycle executes one of the remaining pulses of a WaterAction with Cycles
+	PendingJobWaterCycle PendingJobType = "water_cycle"
+	// PendingJobLightRevert changes a Garden's light back after a LightAction with ForDuration
+	PendingJobLightRevert PendingJobType = "light_revert"
+)
+
+// PendingJob is a one-time Job scheduled by the Worker. It is saved in storage until it runs so it can be scheduled
+// again if the server restarts. WaterScheduleID is only used by deferred waterings, Duration and Fertilizer are
+// only used by water cycles, and LightState is only used by light reverts, which do not have a ZoneID
+type PendingJob struct {
+	ID              string         `json:"id"`
+	Type            PendingJobType `json:"type"`
+	GardenID        string         `json:"garden_id"`
+	ZoneID          string         `json:"zone_id"`
+	WaterScheduleID string         `json:"water_schedule_id,omitempty"`
+	ScheduledTime   time.Time      `json:"scheduled_time"`
+	RunAt           time.Time      `json:"run_at"`
+	Duration        *Duration      `json:"duration,omitempty"`
+	Fertilizer      *Duration      `json:"fertilizer,omitempty"`
+	LightState      *LightState    `json:"light_state,omitempty"`
+}
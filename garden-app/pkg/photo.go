@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+)
+
+// Photo is a picture of a Zone or Plant. It only holds the details about the photo and the contents are saved by
+// the configured photo storage. A Zone or Plant's Photos, sorted by TakenAt, make a timeline of how it has grown
+type Photo struct {
+	ID          babyapi.ID `json:"id" yaml:"id,omitempty"`
+	GardenID    xid.ID     `json:"garden_id" yaml:"garden_id,omitempty"`
+	ZoneID      xid.ID     `json:"zone_id" yaml:"zone_id,omitempty"`
+	PlantID     *xid.ID    `json:"plant_id,omitempty" yaml:"plant_id,omitempty"`
+	Caption     string     `json:"caption,omitempty" yaml:"caption,omitempty"`
+	ContentType string     `json:"content_type" yaml:"content_type,omitempty"`
+	Size        int64      `json:"size" yaml:"size,omitempty"`
+	TakenAt     *time.Time `json:"taken_at" yaml:"taken_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at" yaml:"created_at,omitempty"`
+	EndDate     *time.Time `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+}
+
+func (p *Photo) GetID() string {
+	return p.ID.String()
+}
+
+// String...
+func (p *Photo) String() string {
+	return fmt.Sprintf("%+v", *p)
+}
+
+// EndDated returns true if the Photo is end-dated
+func (p *Photo) EndDated() bool {
+	return p.EndDate != nil && p.EndDate.Before(time.Now())
+}
+
+func (p *Photo) SetEndDate(now time.Time) {
+	p.EndDate = &now
+}
+
+// Patch allows for updating the Photo's Caption and TakenAt. Everything else is set when it is uploaded
+func (p *Photo) Patch(newPhoto *Photo) *babyapi.ErrResponse {
+	if newPhoto.Caption != "" {
+		p.Caption = newPhoto.Caption
+	}
+	if newPhoto.TakenAt != nil {
+		p.TakenAt = newPhoto.TakenAt
+	}
+
+	return nil
+}
+
+// Bind is only used for PATCH requests since Photos are created by uploading them to a Zone or Plant
+func (p *Photo) Bind(r *http.Request) error {
+	if p == nil {
+		return errors.New("missing required Photo fields")
+	}
+
+	err := p.ID.Bind(r)
+	if err != nil {
+		return err
+	}
+
+	if r.Method == http.MethodPatch {
+		if p.EndDate != nil {
+			return errors.New("to end-date a Photo, please use the DELETE endpoint")
+		}
+		if !p.GardenID.IsNil() || !p.ZoneID.IsNil() || p.PlantID != nil {
+			return errors.New("unable to change garden_id, zone_id, or plant_id")
+		}
+		if p.ContentType != "" || p.Size != 0 {
+			return errors.New("unable to change content_type or size")
+		}
+	}
+
+	return nil
+}
+
+func (p *Photo) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
@@ -0,0 +1,46 @@
+package pkg
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhotoBind(t *testing.T) {
+	plantID := xid.New()
+
+	tests := []struct {
+		name   string
+		method string
+		photo  *Photo
+		err    string
+	}{
+		{"PatchCaption", http.MethodPatch, &Photo{Caption: "first flowers"}, ""},
+		{"ErrorPatchPlantID", http.MethodPatch, &Photo{PlantID: &plantID}, "unable to change garden_id, zone_id, or plant_id"},
+		{"ErrorPatchSize", http.MethodPatch, &Photo{Size: 10}, "unable to change content_type or size"},
+		{"ErrorPatchEndDate", http.MethodPatch, &Photo{EndDate: &time.Time{}}, "to end-date a Photo, please use the DELETE endpoint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.photo.Bind(&http.Request{Method: tt.method})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestPhotoPatch(t *testing.T) {
+	takenAt := time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC)
+	photo := &Photo{Caption: "seedling", ContentType: "image/jpeg"}
+
+	err := photo.Patch(&Photo{Caption: "first true leaves", TakenAt: &takenAt})
+	assert.Nil(t, err)
+	assert.Equal(t, &Photo{Caption: "first true leaves", ContentType: "image/jpeg", TakenAt: &takenAt}, photo)
+}
@@ -0,0 +1,124 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// ErrNotFound is returned when there is no file for the key
+var ErrNotFound = errors.New("file not found")
+
+type Config struct {
+	Directory string `mapstructure:"directory"`
+}
+
+// Store saves photos as files in a local directory
+type Store struct {
+	*Config
+}
+
+func NewStore(options map[string]interface{}) (*Store, error) {
+	store := &Store{}
+
+	err := mapstructure.Decode(options, &store.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if store.Directory == "" {
+		return nil, errors.New("missing required directory")
+	}
+
+	err = os.MkdirAll(store.Directory, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("error creating directory: %w", err)
+	}
+
+	return store, nil
+}
+
+func (s *Store) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a failed upload does not leave a partial photo
+	tmp, err := os.CreateTemp(s.Directory, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, body)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("error closing file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *Store) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *Store) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List returns the keys of all files in the directory, sorted by name
+func (s *Store) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory: %w", err)
+	}
+
+	keys := []string{}
+	for _, e := range entries {
+		// Temporary files from uploads that are in progress start with "."
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		keys = append(keys, e.Name())
+	}
+	return keys, nil
+}
+
+// path makes sure the key is only a file name so it can't be used to read or write outside of the directory
+func (s *Store) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || key[0] == '.' {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	return filepath.Join(s.Directory, key), nil
+}
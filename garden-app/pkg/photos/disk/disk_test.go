@@ -0,0 +1,59 @@
+package disk
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStoreMissingDirectory(t *testing.T) {
+	_, err := NewStore(map[string]interface{}{})
+	assert.EqualError(t, err, "missing required directory")
+}
+
+func TestStore(t *testing.T) {
+	store, err := NewStore(map[string]interface{}{"directory": t.TempDir()})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	err = store.Put(ctx, "abc", strings.NewReader("image data"), 10, "image/jpeg")
+	require.NoError(t, err)
+
+	body, err := store.Get(ctx, "abc")
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	body.Close()
+	assert.Equal(t, "image data", string(data))
+
+	keys, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, keys)
+
+	err = store.Delete(ctx, "abc")
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, "abc")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Deleting a missing photo is not an error
+	err = store.Delete(ctx, "abc")
+	assert.NoError(t, err)
+}
+
+func TestStoreInvalidKey(t *testing.T) {
+	store, err := NewStore(map[string]interface{}{"directory": t.TempDir()})
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../abc", "a/b", ".hidden"} {
+		t.Run(key, func(t *testing.T) {
+			err := store.Put(context.Background(), key, strings.NewReader("data"), 4, "image/jpeg")
+			assert.Error(t, err)
+		})
+	}
+}
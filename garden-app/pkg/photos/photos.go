@@ -0,0 +1,68 @@
+package photos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/photos/disk"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/photos/s3"
+)
+
+// ErrNotFound is returned by a Store when there is no photo with the requested key
+var ErrNotFound = errors.New("photo not found")
+
+// Store is an interface for saving and reading the contents of uploaded photos
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config is used to identify and configure where photos are stored
+type Config struct {
+	Driver  string                 `mapstructure:"driver"`
+	Options map[string]interface{} `mapstructure:"options"`
+	// MaxSizeBytes limits the size of uploaded photos. It defaults to 10MB
+	MaxSizeBytes int64 `mapstructure:"max_size_bytes"`
+}
+
+// Enabled returns true if a driver is configured
+func (c Config) Enabled() bool {
+	return c.Driver != ""
+}
+
+// NewStore will use the config to create and return the correct type of Store
+func NewStore(c Config) (Store, error) {
+	var (
+		store Store
+		err   error
+	)
+	switch c.Driver {
+	case "disk":
+		store, err = disk.NewStore(c.Options)
+	case "s3":
+		store, err = s3.NewStore(c.Options)
+	default:
+		err = fmt.Errorf("invalid driver '%s'", c.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &notFoundWrapper{store}, nil
+}
+
+// notFoundWrapper converts each driver's not found error into ErrNotFound
+type notFoundWrapper struct {
+	Store
+}
+
+func (s *notFoundWrapper) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := s.Store.Get(ctx, key)
+	if errors.Is(err, disk.ErrNotFound) || errors.Is(err, s3.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return body, err
+}
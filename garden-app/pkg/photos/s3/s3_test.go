@@ -0,0 +1,188 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal S3 server that keeps objects in memory
+type fakeS3 struct {
+	sync.Mutex
+	objects map[string]string
+	auth    []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = string(body)
+	case http.MethodGet:
+		if r.URL.Query().Get("list-type") == "2" {
+			f.list(w, r)
+			return
+		}
+		obj, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(obj))
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// list responds with the objects in the bucket that start with the prefix, one page at a time
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	keys := []string{}
+	for objectPath := range f.objects {
+		key := strings.TrimPrefix(objectPath, r.URL.Path+"/")
+		if strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("continuation-token"))
+	keys = keys[start:]
+	truncated := len(keys) > 1
+	if truncated {
+		keys = keys[:1]
+	}
+
+	_, _ = fmt.Fprint(w, "<ListBucketResult>")
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+	}
+	_, _ = fmt.Fprintf(w, "<IsTruncated>%t</IsTruncated><NextContinuationToken>%d</NextContinuationToken></ListBucketResult>", truncated, start+1)
+}
+
+func TestNewStore(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     map[string]interface{}
+		expectedErr string
+	}{
+		{
+			"MissingBucket",
+			map[string]interface{}{"region": "us-east-1"},
+			"missing required bucket",
+		},
+		{
+			"MissingRegion",
+			map[string]interface{}{"bucket": "photos"},
+			"missing required region",
+		},
+		{
+			"MissingCredentials",
+			map[string]interface{}{"bucket": "photos", "region": "us-east-1"},
+			"missing required access_key_id and secret_access_key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStore(tt.options)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+
+	t.Run("DefaultEndpoint", func(t *testing.T) {
+		store, err := NewStore(map[string]interface{}{
+			"bucket":            "photos",
+			"region":            "us-west-2",
+			"access_key_id":     "key",
+			"secret_access_key": "secret",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "https://s3.us-west-2.amazonaws.com", store.Endpoint)
+	})
+}
+
+func TestStore(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewStore(map[string]interface{}{
+		"endpoint":          server.URL,
+		"bucket":            "photos",
+		"prefix":            "garden",
+		"region":            "us-east-1",
+		"access_key_id":     "key",
+		"secret_access_key": "secret",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	err = store.Put(ctx, "abc", strings.NewReader("image data"), 10, "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/photos/garden/abc": "image data"}, fake.objects)
+
+	body, err := store.Get(ctx, "abc")
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	body.Close()
+	assert.Equal(t, "image data", string(data))
+
+	fake.objects["/photos/garden/def"] = "other"
+	fake.objects["/photos/other/ghi"] = "other prefix"
+	keys, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc", "def"}, keys)
+
+	err = store.Delete(ctx, "abc")
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, "abc")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, auth := range fake.auth {
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/"), auth)
+	}
+}
+
+func TestSign(t *testing.T) {
+	store, err := NewStore(map[string]interface{}{
+		"bucket":            "photos",
+		"region":            "us-east-1",
+		"access_key_id":     "AKIDEXAMPLE",
+		"secret_access_key": "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	})
+	require.NoError(t, err)
+	store.now = func() time.Time {
+		return time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	req, err := store.newRequest(context.Background(), http.MethodGet, "abc", http.NoBody)
+	require.NoError(t, err)
+	store.sign(req)
+
+	assert.Equal(t, "https://s3.us-east-1.amazonaws.com/photos/abc", req.URL.String())
+	assert.Equal(t, "20240501T120000Z", req.Header.Get("X-Amz-Date"))
+	assert.Regexp(t,
+		`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240501/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`,
+		req.Header.Get("Authorization"),
+	)
+}
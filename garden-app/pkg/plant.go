@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+)
+
+// Plant represents an actual Plant growing in one of a Garden's Zones. It does not control watering and is only used
+// to keep track of care details, like when it was planted and when it should be ready to harvest
+type Plant struct {
+	ID                  babyapi.ID `json:"id" yaml:"id,omitempty"`
+	Name                string     `json:"name" yaml:"name,omitempty"`
+	GardenID            xid.ID     `json:"garden_id" yaml:"garden_id,omitempty"`
+	ZoneID              xid.ID     `json:"zone_id" yaml:"zone_id,omitempty"`
+	Species             string     `json:"species,omitempty" yaml:"species,omitempty"`
+	PlantingDate        *time.Time `json:"planting_date,omitempty" yaml:"planting_date,omitempty"`
+	ExpectedHarvestDate *time.Time `json:"expected_harvest_date,omitempty" yaml:"expected_harvest_date,omitempty"`
+	Notes               string     `json:"notes,omitempty" yaml:"notes,omitempty"`
+	CreatedAt           *time.Time `json:"created_at" yaml:"created_at,omitempty"`
+	EndDate             *time.Time `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+}
+
+func (p *Plant) GetID() string {
+	return p.ID.String()
+}
+
+// String...
+func (p *Plant) String() string {
+	return fmt.Sprintf("%+v", *p)
+}
+
+// EndDated returns true if the Plant is end-dated
+func (p *Plant) EndDated() bool {
+	return p.EndDate != nil && p.EndDate.Before(time.Now())
+}
+
+func (p *Plant) SetEndDate(now time.Time) {
+	p.EndDate = &now
+}
+
+// HarvestDue returns true if the Plant has an ExpectedHarvestDate that is before the provided time
+func (p *Plant) HarvestDue(now time.Time) bool {
+	return p.ExpectedHarvestDate != nil && !p.ExpectedHarvestDate.After(now)
+}
+
+// Patch allows for easily updating individual fields of a Plant by passing in a new Plant containing the desired
+// values
+func (p *Plant) Patch(newPlant *Plant) *babyapi.ErrResponse {
+	if newPlant.Name != "" {
+		p.Name = newPlant.Name
+	}
+	if newPlant.Species != "" {
+		p.Species = newPlant.Species
+	}
+	if newPlant.PlantingDate != nil {
+		p.PlantingDate = newPlant.PlantingDate
+	}
+	if newPlant.ExpectedHarvestDate != nil {
+		p.ExpectedHarvestDate = newPlant.ExpectedHarvestDate
+	}
+	if newPlant.Notes != "" {
+		p.Notes = newPlant.Notes
+	}
+	if newPlant.CreatedAt != nil {
+		p.CreatedAt = newPlant.CreatedAt
+	}
+	if p.EndDate != nil && newPlant.EndDate == nil {
+		p.EndDate = newPlant.EndDate
+	}
+
+	return nil
+}
+
+func (p *Plant) Bind(r *http.Request) error {
+	if p == nil {
+		return errors.New("missing required Plant fields")
+	}
+
+	err := p.ID.Bind(r)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch r.Method {
+	case http.MethodPost:
+		p.CreatedAt = &now
+		fallthrough
+	case http.MethodPut:
+		if p.CreatedAt == nil || p.CreatedAt.IsZero() {
+			p.CreatedAt = &now
+		}
+		if p.Name == "" {
+			return validation.Missing("name")
+		}
+	case http.MethodPatch:
+		if p.EndDate != nil {
+			return errors.New("to end-date a Plant, please use the DELETE endpoint")
+		}
+		if !p.GardenID.IsNil() {
+			return errors.New("unable to change GardenID")
+		}
+		if !p.ZoneID.IsNil() {
+			return errors.New("unable to change ZoneID")
+		}
+	}
+
+	if p.PlantingDate != nil && p.ExpectedHarvestDate != nil && p.ExpectedHarvestDate.Before(*p.PlantingDate) {
+		return errors.New("expected_harvest_date must be after planting_date")
+	}
+
+	return nil
+}
+
+func (p *Plant) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
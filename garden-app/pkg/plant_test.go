@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlantBind(t *testing.T) {
+	plantingDate := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	harvestDate := plantingDate.Add(70 * 24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		method string
+		plant  *Plant
+		err    string
+	}{
+		{"Valid", http.MethodPost, &Plant{Name: "tomato", PlantingDate: &plantingDate, ExpectedHarvestDate: &harvestDate}, ""},
+		{"ErrorMissingName", http.MethodPost, &Plant{Species: "Solanum lycopersicum"}, "missing required name field"},
+		{"ErrorHarvestBeforePlanting", http.MethodPost, &Plant{Name: "tomato", PlantingDate: &harvestDate, ExpectedHarvestDate: &plantingDate}, "expected_harvest_date must be after planting_date"},
+		{"PatchNotes", http.MethodPatch, &Plant{Notes: "pruned suckers"}, ""},
+		{"ErrorPatchEndDate", http.MethodPatch, &Plant{EndDate: &time.Time{}}, "to end-date a Plant, please use the DELETE endpoint"},
+		{"ErrorPatchGardenID", http.MethodPatch, &Plant{GardenID: xid.New()}, "unable to change GardenID"},
+		{"ErrorPatchZoneID", http.MethodPatch, &Plant{ZoneID: xid.New()}, "unable to change ZoneID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.plant.Bind(&http.Request{Method: tt.method})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestPlantPatch(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		newPlant *Plant
+	}{
+		{"PatchName", &Plant{Name: "name"}},
+		{"PatchSpecies", &Plant{Species: "Capsicum annuum"}},
+		{"PatchPlantingDate", &Plant{PlantingDate: &now}},
+		{"PatchExpectedHarvestDate", &Plant{ExpectedHarvestDate: &now}},
+		{"PatchNotes", &Plant{Notes: "notes"}},
+		{"PatchCreatedAt", &Plant{CreatedAt: &now}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plant{}
+			err := p.Patch(tt.newPlant)
+			require.Nil(t, err)
+			assert.Equal(t, tt.newPlant, p)
+		})
+	}
+
+	t.Run("PatchRemoveEndDate", func(t *testing.T) {
+		p := &Plant{EndDate: &now}
+
+		err := p.Patch(&Plant{})
+		require.Nil(t, err)
+		assert.Nil(t, p.EndDate)
+	})
+}
+
+func TestPlantHarvestDue(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+
+	assert.False(t, (&Plant{}).HarvestDue(now))
+	assert.True(t, (&Plant{ExpectedHarvestDate: &yesterday}).HarvestDue(now))
+	assert.False(t, (&Plant{ExpectedHarvestDate: &tomorrow}).HarvestDue(now))
+}
@@ -0,0 +1,353 @@
+package promql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+)
+
+const (
+	queryPath      = "/api/v1/query"
+	queryRangePath = "/api/v1/query_range"
+	writePath      = "/write"
+	// pingPath is supported by Prometheus and VictoriaMetrics
+	pingPath = "/-/healthy"
+	// recentRange is how far back to look for the most recent data, which is the same as the InfluxDB clients
+	recentRange = 15 * time.Minute
+)
+
+// Config holds configuration values for a Prometheus-compatible store. Address is used for the Prometheus HTTP
+// API. WriteAddress accepts InfluxDB line protocol, like VictoriaMetrics, and defaults to Address + "/write"
+type Config struct {
+	Address      string `mapstructure:"address"`
+	WriteAddress string `mapstructure:"write_address"`
+	Token        string `mapstructure:"token"`
+}
+
+// Client writes the data that controllers publish to a Prometheus-compatible store and queries it with PromQL.
+// Each line protocol field becomes a metric named "{measurement}_{field}", like "moisture_value", with the
+// controller's topic as a label
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient creates a Client from the config
+func NewClient(config Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{},
+		now:        time.Now,
+	}
+}
+
+// Write stores a message published by a controller. The topic is added as a label so data can be found for each
+// Garden. Health messages are stored as the time that they were received since they do not have a numeric value
+func (c *Client) Write(ctx context.Context, topic string, payload []byte) error {
+	line, err := c.line(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	writeAddress := c.config.WriteAddress
+	if writeAddress == "" {
+		writeAddress = strings.TrimSuffix(c.config.Address, "/") + writePath
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, writeAddress, strings.NewReader(line))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	_, err = c.do(req)
+	if err != nil {
+		return fmt.Errorf("error writing data: %w", err)
+	}
+	return nil
+}
+
+// line converts the message to a line of InfluxDB line protocol with the topic label
+func (c *Client) line(topic string, payload []byte) (string, error) {
+	if prefix, ok := strings.CutSuffix(topic, "/data/health"); ok {
+		return fmt.Sprintf("health,garden=%s last_contact=%d", influxdb.EscapeTag(prefix), c.now().Unix()), nil
+	}
+	return influxdb.AddTopicTag(topic, payload)
+}
+
+// GetMoisture returns the Zone's average soil moisture in the last 15 minutes
+func (c *Client) GetMoisture(ctx context.Context, zonePosition uint, topicPrefix string) (float64, error) {
+	result, err := c.query(ctx, fmt.Sprintf("avg(avg_over_time(moisture_value%s[%s]))", zoneSelector(zonePosition, topicPrefix, "moisture"), duration(recentRange)))
+	if err != nil {
+		return 0, err
+	}
+	return firstValue(result), nil
+}
+
+// GetMoistureHistory gets a Zone's average soil moisture for each window of the resolution in the time range. The
+// RecordTime is the start of each window, which is the same as the InfluxDB clients
+func (c *Client) GetMoistureHistory(ctx context.Context, zonePosition uint, topicPrefix string, timeRange, resolution time.Duration) ([]map[string]interface{}, error) {
+	end := c.now()
+	result, err := c.queryRange(
+		ctx,
+		fmt.Sprintf("avg(avg_over_time(moisture_value%s[%s]))", zoneSelector(zonePosition, topicPrefix, "moisture"), duration(resolution)),
+		end.Add(-timeRange).Add(resolution), end, resolution,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []map[string]interface{}{}
+	for _, s := range result {
+		for _, v := range s.Values {
+			history = append(history, map[string]interface{}{
+				"Value":      v.Value,
+				"RecordTime": v.Time.Add(-resolution),
+			})
+		}
+	}
+	return history, nil
+}
+
+// GetLastContact returns the time of the most recent health data published by the controller
+func (c *Client) GetLastContact(ctx context.Context, topicPrefix string) (time.Time, error) {
+	result, err := c.query(ctx, fmt.Sprintf("max(last_over_time(health_last_contact{garden=%s}[%s]))", strconv.Quote(topicPrefix), duration(recentRange)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(result) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(int64(firstValue(result)), 0).UTC(), nil
+}
+
+// GetWaterHistory gets recent water events for a specific Zone. Raw samples are used since each one is a separate
+// event. The result uses the same keys as the InfluxDB clients and Milliliters is only included when the controller
+// has a flow meter
+func (c *Client) GetWaterHistory(ctx context.Context, zonePosition uint, topicPrefix string, timeRange time.Duration, limit uint64) ([]map[string]interface{}, error) {
+	selector := fmt.Sprintf(
+		`{__name__=~"water_millis|water_ml",topic=%s,zone="%d"}`,
+		strconv.Quote(topicPrefix+"/data/water"), zonePosition,
+	)
+	result, err := c.query(ctx, fmt.Sprintf("%s[%s]", selector, duration(timeRange)))
+	if err != nil {
+		return nil, err
+	}
+
+	// millis and ml are separate series, so they are combined using the time of each sample
+	events := map[time.Time]map[string]interface{}{}
+	for _, s := range result {
+		for _, v := range s.Values {
+			event, ok := events[v.Time]
+			if !ok {
+				event = map[string]interface{}{"RecordTime": v.Time, "Duration": 0}
+				events[v.Time] = event
+			}
+			switch s.Metric["__name__"] {
+			case "water_millis":
+				event["Duration"] = int(v.Value)
+			case "water_ml":
+				event["Milliliters"] = v.Value
+			}
+		}
+	}
+
+	history := []map[string]interface{}{}
+	for _, event := range events {
+		history = append(history, event)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i]["RecordTime"].(time.Time).After(history[j]["RecordTime"].(time.Time))
+	})
+	if limit > 0 && uint64(len(history)) > limit {
+		history = history[:limit]
+	}
+	return history, nil
+}
+
+// GetTemperature returns the Garden's average temperature, in Celsius, in the last 15 minutes
+func (c *Client) GetTemperature(ctx context.Context, topicPrefix string) (float64, error) {
+	return c.getSensorMean(ctx, "temperature", topicPrefix)
+}
+
+// GetHumidity returns the Garden's average relative humidity percentage in the last 15 minutes
+func (c *Client) GetHumidity(ctx context.Context, topicPrefix string) (float64, error) {
+	return c.getSensorMean(ctx, "humidity", topicPrefix)
+}
+
+// GetLightLevel returns the Garden's average ambient light level, in lux, in the last 15 minutes
+func (c *Client) GetLightLevel(ctx context.Context, topicPrefix string) (float64, error) {
+	return c.getSensorMean(ctx, "light_level", topicPrefix)
+}
+
+// Ping checks if the server is running
+func (c *Client) Ping(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.config.Address, "/")+pingPath, http.NoBody)
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %w", err)
+	}
+
+	_, err = c.do(req)
+	if err != nil {
+		return false, fmt.Errorf("error pinging server: %w", err)
+	}
+	return true, nil
+}
+
+// Close closes idle connections to the server
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// getSensorMean queries the average value of a Garden's sensor measurement
+func (c *Client) getSensorMean(ctx context.Context, measurement, topicPrefix string) (float64, error) {
+	result, err := c.query(ctx, fmt.Sprintf(
+		"avg(avg_over_time(%s_value{topic=%s}[%s]))",
+		measurement, strconv.Quote(fmt.Sprintf("%s/data/%s", topicPrefix, measurement)), duration(recentRange),
+	))
+	if err != nil {
+		return 0, err
+	}
+	return firstValue(result), nil
+}
+
+// query executes an instant query
+func (c *Client) query(ctx context.Context, query string) ([]series, error) {
+	return c.get(ctx, queryPath, url.Values{"query": {query}})
+}
+
+// queryRange executes a range query with a step between each point
+func (c *Client) queryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]series, error) {
+	return c.get(ctx, queryRangePath, url.Values{
+		"query": {query},
+		"start": {strconv.FormatInt(start.Unix(), 10)},
+		"end":   {strconv.FormatInt(end.Unix(), 10)},
+		"step":  {duration(step)},
+	})
+}
+
+func (c *Client) get(ctx context.Context, path string, params url.Values) ([]series, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.config.Address, "/")+path+"?"+params.Encode(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		var apiErr apiResponse
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+			return nil, fmt.Errorf("error querying metrics: %s", apiErr.Error)
+		}
+		return nil, fmt.Errorf("error querying metrics: %w", err)
+	}
+
+	var resp apiResponse
+	err = json.Unmarshal(body, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding query result: %w", err)
+	}
+	return resp.Data.Result, nil
+}
+
+// do executes the request with authorization and returns the body. An error is returned for non-2xx responses,
+// but the body is still returned so errors from the API can be read
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return body, fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return body, nil
+}
+
+// apiResponse is the response from the Prometheus HTTP API
+type apiResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []series `json:"result"`
+	} `json:"data"`
+}
+
+// series is one result of a query. Value is used for instant queries of a vector and Values for matrices
+type series struct {
+	Metric map[string]string `json:"metric"`
+	Value  *sample           `json:"value"`
+	Values []sample          `json:"values"`
+}
+
+// sample is a value and its time. The API uses an array with the time in seconds and the value as a string
+type sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// UnmarshalJSON parses a sample from an array like [1435781451.781, "1"]
+func (s *sample) UnmarshalJSON(data []byte) error {
+	var raw [2]json.RawMessage
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	var seconds float64
+	err = json.Unmarshal(raw[0], &seconds)
+	if err != nil {
+		return fmt.Errorf("invalid sample time: %w", err)
+	}
+
+	var value string
+	err = json.Unmarshal(raw[1], &value)
+	if err != nil {
+		return fmt.Errorf("invalid sample value: %w", err)
+	}
+	s.Value, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid sample value: %w", err)
+	}
+
+	s.Time = time.UnixMilli(int64(math.Round(seconds * 1000))).UTC()
+	return nil
+}
+
+// zoneSelector creates a label selector for a Zone's data on the controller's topic
+func zoneSelector(zonePosition uint, topicPrefix, measurement string) string {
+	return fmt.Sprintf(`{topic=%s,zone="%d"}`, strconv.Quote(fmt.Sprintf("%s/data/%s", topicPrefix, measurement)), zonePosition)
+}
+
+// duration formats a PromQL duration. Milliseconds are used so any Go duration can be represented
+func duration(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// firstValue returns the value of the first result of an instant query, or 0 if there is no data
+func firstValue(result []series) float64 {
+	if len(result) == 0 || result[0].Value == nil {
+		return 0
+	}
+	return result[0].Value.Value
+}
@@ -0,0 +1,242 @@
+package promql
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2023, time.August, 1, 12, 0, 0, 0, time.UTC)
+
+type testRequest struct {
+	method string
+	path   string
+	params url.Values
+	body   string
+}
+
+// newTestClient creates a Client for a server that responds with the result and records each request
+func newTestClient(t *testing.T, status int, result string) (*Client, *[]testRequest) {
+	t.Helper()
+
+	requests := []testRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests = append(requests, testRequest{r.Method, r.URL.Path, r.URL.Query(), string(body)})
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(result))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(Config{Address: server.URL, Token: "my-token"})
+	client.now = func() time.Time { return testNow }
+	t.Cleanup(client.Close)
+	return client, &requests
+}
+
+func TestGetMoisture(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1690891200,"42.5"]}]}}`)
+
+	moisture, err := client.GetMoisture(context.Background(), 1, "test-garden")
+	require.NoError(t, err)
+	assert.Equal(t, 42.5, moisture)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, http.MethodGet, req.method)
+	assert.Equal(t, queryPath, req.path)
+	assert.Equal(t, `avg(avg_over_time(moisture_value{topic="test-garden/data/moisture",zone="1"}[900000ms]))`, req.params.Get("query"))
+}
+
+func TestGetMoistureNoData(t *testing.T) {
+	client, _ := newTestClient(t, http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+
+	moisture, err := client.GetMoisture(context.Background(), 0, "test-garden")
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, moisture)
+}
+
+func TestGetMoistureHistory(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1690884000,"40"],[1690887600,"38.5"]]}]}}`)
+
+	history, err := client.GetMoistureHistory(context.Background(), 0, "test-garden", 3*time.Hour, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"Value": 40.0, "RecordTime": time.Date(2023, time.August, 1, 9, 0, 0, 0, time.UTC)},
+		{"Value": 38.5, "RecordTime": time.Date(2023, time.August, 1, 10, 0, 0, 0, time.UTC)},
+	}, history)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, queryRangePath, req.path)
+	assert.Equal(t, `avg(avg_over_time(moisture_value{topic="test-garden/data/moisture",zone="0"}[3600000ms]))`, req.params.Get("query"))
+	assert.Equal(t, "1690884000", req.params.Get("start"))
+	assert.Equal(t, "1690891200", req.params.Get("end"))
+	assert.Equal(t, "3600000ms", req.params.Get("step"))
+}
+
+func TestGetLastContact(t *testing.T) {
+	t.Run("Successful", func(t *testing.T) {
+		client, requests := newTestClient(t, http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1690891200,"1690884000"]}]}}`)
+
+		lastContact, err := client.GetLastContact(context.Background(), "test-garden")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, time.August, 1, 10, 0, 0, 0, time.UTC), lastContact)
+
+		require.Len(t, *requests, 1)
+		assert.Equal(t, `max(last_over_time(health_last_contact{garden="test-garden"}[900000ms]))`, (*requests)[0].params.Get("query"))
+	})
+
+	t.Run("NoData", func(t *testing.T) {
+		client, _ := newTestClient(t, http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+
+		lastContact, err := client.GetLastContact(context.Background(), "test-garden")
+		require.NoError(t, err)
+		assert.True(t, lastContact.IsZero())
+	})
+}
+
+func TestGetWaterHistory(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, `{"status":"success","data":{"resultType":"matrix","result":[
+{"metric":{"__name__":"water_millis","topic":"test-garden/data/water","zone":"0"},"values":[[1690884000,"6000"],[1690887600,"3000"],[1690891200,"1000"]]},
+{"metric":{"__name__":"water_ml","topic":"test-garden/data/water","zone":"0"},"values":[[1690887600,"750"]]}
+]}}`)
+
+	history, err := client.GetWaterHistory(context.Background(), 0, "test-garden", 72*time.Hour, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"Duration": 1000, "RecordTime": time.Date(2023, time.August, 1, 12, 0, 0, 0, time.UTC)},
+		{"Duration": 3000, "Milliliters": 750.0, "RecordTime": time.Date(2023, time.August, 1, 11, 0, 0, 0, time.UTC)},
+	}, history)
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, `{__name__=~"water_millis|water_ml",topic="test-garden/data/water",zone="0"}[259200000ms]`, (*requests)[0].params.Get("query"))
+}
+
+func TestGetTemperatureAndHumidity(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1690891200,"22.5"]}]}}`)
+
+	temperature, err := client.GetTemperature(context.Background(), "test-garden")
+	require.NoError(t, err)
+	assert.Equal(t, 22.5, temperature)
+
+	_, err = client.GetHumidity(context.Background(), "test-garden")
+	require.NoError(t, err)
+
+	_, err = client.GetLightLevel(context.Background(), "test-garden")
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 3)
+	assert.Equal(t, `avg(avg_over_time(temperature_value{topic="test-garden/data/temperature"}[900000ms]))`, (*requests)[0].params.Get("query"))
+	assert.Equal(t, `avg(avg_over_time(humidity_value{topic="test-garden/data/humidity"}[900000ms]))`, (*requests)[1].params.Get("query"))
+	assert.Equal(t, `avg(avg_over_time(light_level_value{topic="test-garden/data/light_level"}[900000ms]))`, (*requests)[2].params.Get("query"))
+}
+
+func TestQueryError(t *testing.T) {
+	client, _ := newTestClient(t, http.StatusBadRequest, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+
+	_, err := client.GetMoisture(context.Background(), 0, "test-garden")
+	assert.EqualError(t, err, "error querying metrics: parse error")
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		topic    string
+		payload  string
+		expected string
+	}{
+		{
+			"Moisture",
+			"test-garden/data/moisture",
+			"moisture,zone=1 value=40",
+			"moisture,topic=test-garden/data/moisture,zone=1 value=40",
+		},
+		{
+			"WaterWithFlowMeter",
+			"test-garden/data/water",
+			"water,zone=0 millis=6000,ml=1500\n",
+			"water,topic=test-garden/data/water,zone=0 millis=6000,ml=1500",
+		},
+		{
+			"TemperatureWithoutTags",
+			"test-garden/data/temperature",
+			"temperature value=21.5",
+			"temperature,topic=test-garden/data/temperature value=21.5",
+		},
+		{
+			"TopicWithSpace",
+			"test garden/data/humidity",
+			"humidity value=40",
+			`humidity,topic=test\ garden/data/humidity value=40`,
+		},
+		{
+			"Health",
+			"test-garden/data/health",
+			`health garden="test-garden"`,
+			"health,garden=test-garden last_contact=1690891200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newTestClient(t, http.StatusNoContent, "")
+
+			err := client.Write(context.Background(), tt.topic, []byte(tt.payload))
+			require.NoError(t, err)
+
+			require.Len(t, *requests, 1)
+			req := (*requests)[0]
+			assert.Equal(t, http.MethodPost, req.method)
+			assert.Equal(t, writePath, req.path)
+			assert.Equal(t, tt.expected, req.body)
+		})
+	}
+
+	t.Run("ErrorInvalidMessage", func(t *testing.T) {
+		client, requests := newTestClient(t, http.StatusNoContent, "")
+
+		err := client.Write(context.Background(), "test-garden/data/moisture", []byte("moisture"))
+		assert.EqualError(t, err, `invalid line protocol "moisture"`)
+		assert.Empty(t, *requests)
+	})
+
+	t.Run("ErrorResponse", func(t *testing.T) {
+		client, _ := newTestClient(t, http.StatusBadRequest, "cannot parse line")
+
+		err := client.Write(context.Background(), "test-garden/data/moisture", []byte("moisture,zone=1 value=40"))
+		assert.EqualError(t, err, "error writing data: unexpected status 400 Bad Request: cannot parse line")
+	})
+}
+
+func TestPing(t *testing.T) {
+	t.Run("Successful", func(t *testing.T) {
+		client, requests := newTestClient(t, http.StatusOK, "OK")
+
+		ok, err := client.Ping(context.Background())
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		require.Len(t, *requests, 1)
+		assert.Equal(t, pingPath, (*requests)[0].path)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		client, _ := newTestClient(t, http.StatusServiceUnavailable, "not ready")
+
+		ok, err := client.Ping(context.Background())
+		assert.EqualError(t, err, "error pinging server: unexpected status 503 Service Unavailable: not ready")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,60 @@
+package pkg
+
+import (
+	"fmt"
+)
+
+// GardenType describes how a Garden's plants are grown. Soil Gardens are watered by Zones, and hydroponic Gardens
+// can also use a RecirculationSchedule to keep nutrient solution moving
+type GardenType string
+
+const (
+	GardenTypeSoil       GardenType = "soil"
+	GardenTypeHydroponic GardenType = "hydroponic"
+)
+
+// Validate returns an error if the GardenType is not known. An empty GardenType is the same as soil
+func (gt GardenType) Validate() error {
+	switch gt {
+	case "", GardenTypeSoil, GardenTypeHydroponic:
+		return nil
+	default:
+		return fmt.Errorf("invalid type %q: must be one of %q or %q", gt, GardenTypeSoil, GardenTypeHydroponic)
+	}
+}
+
+// RecirculationSchedule runs a hydroponic Garden's recirculation pump with a repeating duty cycle. The pump is on
+// for OnDuration and then off for OffDuration. This is separate from watering Zones
+type RecirculationSchedule struct {
+	OnDuration  *Duration `json:"on_duration" yaml:"on_duration"`
+	OffDuration *Duration `json:"off_duration" yaml:"off_duration"`
+}
+
+// Validate makes sure both durations are positive
+func (rs *RecirculationSchedule) Validate() error {
+	err := rs.OnDuration.ValidatePositive("on_duration")
+	if err != nil {
+		return err
+	}
+	return rs.OffDuration.ValidatePositive("off_duration")
+}
+
+// Patch updates the durations that are set in the new RecirculationSchedule
+func (rs *RecirculationSchedule) Patch(new *RecirculationSchedule) {
+	if new.OnDuration != nil {
+		rs.OnDuration = new.OnDuration
+	}
+	if new.OffDuration != nil {
+		rs.OffDuration = new.OffDuration
+	}
+}
+
+// isEmpty is used to remove a RecirculationSchedule with a PATCH request
+func (rs *RecirculationSchedule) isEmpty() bool {
+	return rs.OnDuration == nil && rs.OffDuration == nil
+}
+
+// HasRecirculation returns true if the Garden is hydroponic and has a RecirculationSchedule
+func (g *Garden) HasRecirculation() bool {
+	return g.Type == GardenTypeHydroponic && g.RecirculationSchedule != nil
+}
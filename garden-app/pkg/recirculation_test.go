@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGardenTypeValidate(t *testing.T) {
+	for _, gt := range []GardenType{"", GardenTypeSoil, GardenTypeHydroponic} {
+		t.Run("Valid_"+string(gt), func(t *testing.T) {
+			assert.NoError(t, gt.Validate())
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.EqualError(t, GardenType("aeroponic").Validate(), `invalid type "aeroponic": must be one of "soil" or "hydroponic"`)
+	})
+}
+
+func TestGardenValidateRecirculation(t *testing.T) {
+	schedule := &RecirculationSchedule{
+		OnDuration:  &Duration{Duration: 15 * time.Minute},
+		OffDuration: &Duration{Duration: 45 * time.Minute},
+	}
+
+	tests := []struct {
+		name   string
+		garden *Garden
+		err    string
+	}{
+		{
+			"ValidHydroponic",
+			&Garden{Type: GardenTypeHydroponic, RecirculationSchedule: schedule},
+			"",
+		},
+		{
+			"ValidHydroponicWithoutSchedule",
+			&Garden{Type: GardenTypeHydroponic},
+			"",
+		},
+		{
+			"ErrorSoilGarden",
+			&Garden{RecirculationSchedule: schedule},
+			"recirculation_schedule is only used for hydroponic Gardens",
+		},
+		{
+			"ErrorMissingOnDuration",
+			&Garden{Type: GardenTypeHydroponic, RecirculationSchedule: &RecirculationSchedule{
+				OffDuration: &Duration{Duration: 45 * time.Minute},
+			}},
+			"invalid recirculation_schedule: missing required on_duration field",
+		},
+		{
+			"ErrorMissingOffDuration",
+			&Garden{Type: GardenTypeHydroponic, RecirculationSchedule: &RecirculationSchedule{
+				OnDuration: &Duration{Duration: 15 * time.Minute},
+			}},
+			"invalid recirculation_schedule: missing required off_duration field",
+		},
+		{
+			"ErrorZeroOffDuration",
+			&Garden{Type: GardenTypeHydroponic, RecirculationSchedule: &RecirculationSchedule{
+				OnDuration:  &Duration{Duration: 15 * time.Minute},
+				OffDuration: &Duration{},
+			}},
+			"invalid recirculation_schedule: off_duration must be a positive duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.garden.ValidateRecirculation()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+)
+
+// ReminderType is the kind of care that a Reminder is for
+type ReminderType string
+
+const (
+	ReminderTypePrune     ReminderType = "prune"
+	ReminderTypeFertilize ReminderType = "fertilize"
+	ReminderTypeHarvest   ReminderType = "harvest"
+	ReminderTypeRepot     ReminderType = "repot"
+)
+
+// Valid returns an error if the ReminderType is not one of the supported types
+func (rt ReminderType) Valid() error {
+	switch rt {
+	case ReminderTypePrune, ReminderTypeFertilize, ReminderTypeHarvest, ReminderTypeRepot:
+		return nil
+	case "":
+		return validation.Missing("type")
+	default:
+		return fmt.Errorf("invalid type %q: must be one of prune, fertilize, harvest, or repot", rt)
+	}
+}
+
+// Reminder is used to send a notification when a Zone or Plant needs care that is not handled automatically, like
+// pruning or repotting. It is sent at the StartDate and then repeated every Interval, if it is set
+type Reminder struct {
+	ID        babyapi.ID   `json:"id" yaml:"id,omitempty"`
+	Type      ReminderType `json:"type" yaml:"type,omitempty"`
+	GardenID  xid.ID       `json:"garden_id" yaml:"garden_id,omitempty"`
+	ZoneID    *xid.ID      `json:"zone_id,omitempty" yaml:"zone_id,omitempty"`
+	PlantID   *xid.ID      `json:"plant_id,omitempty" yaml:"plant_id,omitempty"`
+	Message   string       `json:"message,omitempty" yaml:"message,omitempty"`
+	StartDate *time.Time   `json:"start_date" yaml:"start_date,omitempty"`
+	Interval  *Duration    `json:"interval,omitempty" yaml:"interval,omitempty"`
+	CreatedAt *time.Time   `json:"created_at" yaml:"created_at,omitempty"`
+	EndDate   *time.Time   `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+}
+
+func (r *Reminder) GetID() string {
+	return r.ID.String()
+}
+
+// String...
+func (r *Reminder) String() string {
+	return fmt.Sprintf("%+v", *r)
+}
+
+// EndDated returns true if the Reminder is end-dated
+func (r *Reminder) EndDated() bool {
+	return r.EndDate != nil && r.EndDate.Before(time.Now())
+}
+
+func (r *Reminder) SetEndDate(now time.Time) {
+	r.EndDate = &now
+}
+
+// Repeats returns true if the Reminder has an Interval
+func (r *Reminder) Repeats() bool {
+	return r.Interval != nil && r.Interval.Duration > 0
+}
+
+// NextTime returns the first time the Reminder is due at or after the provided time. It returns nil if the Reminder
+// does not repeat and its StartDate has already passed
+func (r *Reminder) NextTime(now time.Time) *time.Time {
+	if r.StartDate == nil {
+		return nil
+	}
+	if !r.StartDate.Before(now) {
+		next := *r.StartDate
+		return &next
+	}
+	if !r.Repeats() {
+		return nil
+	}
+
+	intervals := now.Sub(*r.StartDate) / r.Interval.Duration
+	next := r.StartDate.Add(intervals * r.Interval.Duration)
+	if next.Before(now) {
+		next = next.Add(r.Interval.Duration)
+	}
+	return &next
+}
+
+// Patch allows for easily updating individual fields of a Reminder by passing in a new Reminder containing the
+// desired values
+func (r *Reminder) Patch(newReminder *Reminder) *babyapi.ErrResponse {
+	if newReminder.Type != "" {
+		r.Type = newReminder.Type
+	}
+	if newReminder.Message != "" {
+		r.Message = newReminder.Message
+	}
+	if newReminder.StartDate != nil {
+		r.StartDate = newReminder.StartDate
+	}
+	if newReminder.Interval != nil {
+		r.Interval = newReminder.Interval
+	}
+	if newReminder.CreatedAt != nil {
+		r.CreatedAt = newReminder.CreatedAt
+	}
+	if r.EndDate != nil && newReminder.EndDate == nil {
+		r.EndDate = newReminder.EndDate
+	}
+
+	return nil
+}
+
+func (r *Reminder) Bind(req *http.Request) error {
+	if r == nil {
+		return errors.New("missing required Reminder fields")
+	}
+
+	err := r.ID.Bind(req)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch req.Method {
+	case http.MethodPost:
+		r.CreatedAt = &now
+		fallthrough
+	case http.MethodPut:
+		if r.CreatedAt == nil || r.CreatedAt.IsZero() {
+			r.CreatedAt = &now
+		}
+		if err := r.Type.Valid(); err != nil {
+			return err
+		}
+		if r.GardenID.IsNil() {
+			return validation.Missing("garden_id")
+		}
+		if r.StartDate == nil {
+			return validation.Missing("start_date")
+		}
+	case http.MethodPatch:
+		if r.EndDate != nil {
+			return errors.New("to end-date a Reminder, please use the DELETE endpoint")
+		}
+		if !r.GardenID.IsNil() || r.ZoneID != nil || r.PlantID != nil {
+			return errors.New("unable to change garden_id, zone_id, or plant_id")
+		}
+		if r.Type != "" {
+			if err := r.Type.Valid(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.Interval != nil {
+		if r.Interval.Cron != "" {
+			return errors.New("interval must be a duration instead of a cron expression")
+		}
+		if r.Interval.Duration < time.Hour {
+			return errors.New("interval must be at least 1h")
+		}
+	}
+
+	return nil
+}
+
+func (r *Reminder) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderBind(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	gardenID := xid.New()
+	zoneID := xid.New()
+
+	tests := []struct {
+		name     string
+		method   string
+		reminder *Reminder
+		err      string
+	}{
+		{"Valid", http.MethodPost, &Reminder{Type: ReminderTypePrune, GardenID: gardenID, StartDate: &start, Interval: &Duration{Duration: 14 * 24 * time.Hour}}, ""},
+		{"ErrorMissingType", http.MethodPost, &Reminder{GardenID: gardenID, StartDate: &start}, "missing required type field"},
+		{"ErrorInvalidType", http.MethodPost, &Reminder{Type: "water", GardenID: gardenID, StartDate: &start}, `invalid type "water": must be one of prune, fertilize, harvest, or repot`},
+		{"ErrorMissingGardenID", http.MethodPost, &Reminder{Type: ReminderTypeRepot, StartDate: &start}, "missing required garden_id field"},
+		{"ErrorMissingStartDate", http.MethodPost, &Reminder{Type: ReminderTypeRepot, GardenID: gardenID}, "missing required start_date field"},
+		{"ErrorCronInterval", http.MethodPost, &Reminder{Type: ReminderTypeFertilize, GardenID: gardenID, StartDate: &start, Interval: &Duration{Cron: "0 9 * * 1"}}, "interval must be a duration instead of a cron expression"},
+		{"ErrorShortInterval", http.MethodPost, &Reminder{Type: ReminderTypeFertilize, GardenID: gardenID, StartDate: &start, Interval: &Duration{Duration: time.Minute}}, "interval must be at least 1h"},
+		{"PatchMessage", http.MethodPatch, &Reminder{Message: "use half strength"}, ""},
+		{"ErrorPatchInvalidType", http.MethodPatch, &Reminder{Type: "water"}, `invalid type "water": must be one of prune, fertilize, harvest, or repot`},
+		{"ErrorPatchZoneID", http.MethodPatch, &Reminder{ZoneID: &zoneID}, "unable to change garden_id, zone_id, or plant_id"},
+		{"ErrorPatchEndDate", http.MethodPatch, &Reminder{EndDate: &time.Time{}}, "to end-date a Reminder, please use the DELETE endpoint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.reminder.Bind(&http.Request{Method: tt.method})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestReminderNextTime(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	week := &Duration{Duration: 7 * 24 * time.Hour}
+
+	tests := []struct {
+		name     string
+		reminder *Reminder
+		now      time.Time
+		expected *time.Time
+	}{
+		{"MissingStartDate", &Reminder{}, start, nil},
+		{"BeforeStartDate", &Reminder{StartDate: &start}, start.Add(-time.Hour), &start},
+		{"AtStartDate", &Reminder{StartDate: &start, Interval: week}, start, &start},
+		{"AfterOneTimeReminder", &Reminder{StartDate: &start}, start.Add(time.Hour), nil},
+		{"RepeatsAfterStartDate", &Reminder{StartDate: &start, Interval: week}, start.Add(time.Hour), timePointer(start.Add(7 * 24 * time.Hour))},
+		{"RepeatsExactlyOnInterval", &Reminder{StartDate: &start, Interval: week}, start.Add(14 * 24 * time.Hour), timePointer(start.Add(14 * 24 * time.Hour))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.reminder.NextTime(tt.now))
+		})
+	}
+}
+
+func TestReminderPatch(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		newReminder *Reminder
+	}{
+		{"PatchType", &Reminder{Type: ReminderTypeHarvest}},
+		{"PatchMessage", &Reminder{Message: "message"}},
+		{"PatchStartDate", &Reminder{StartDate: &now}},
+		{"PatchInterval", &Reminder{Interval: &Duration{Duration: time.Hour}}},
+		{"PatchCreatedAt", &Reminder{CreatedAt: &now}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reminder{}
+			err := r.Patch(tt.newReminder)
+			require.Nil(t, err)
+			assert.Equal(t, tt.newReminder, r)
+		})
+	}
+
+	t.Run("PatchRemoveEndDate", func(t *testing.T) {
+		r := &Reminder{EndDate: &now}
+
+		err := r.Patch(&Reminder{})
+		require.Nil(t, err)
+		assert.Nil(t, r.EndDate)
+	})
+}
+
+func timePointer(t time.Time) *time.Time {
+	return &t
+}
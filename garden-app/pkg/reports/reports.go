@@ -0,0 +1,167 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
+)
+
+const monthFormat = "2006-01"
+
+// Report contains the water usage and costs for a Garden, organized by month
+type Report struct {
+	GardenID string           `json:"garden_id"`
+	Currency string           `json:"currency,omitempty"`
+	Months   []*MonthlyReport `json:"months"`
+}
+
+// MonthlyReport contains the total usage and cost for a single month and the breakdown for each Zone
+type MonthlyReport struct {
+	Month string `json:"month"`
+	Usage
+	Zones []*ZoneUsage `json:"zones"`
+}
+
+// ZoneUsage is the usage and cost for a single Zone
+type ZoneUsage struct {
+	ZoneID   string `json:"zone_id"`
+	ZoneName string `json:"zone_name"`
+	Usage
+}
+
+// Usage holds the watering totals and calculated costs
+type Usage struct {
+	WaterEvents   int     `json:"water_events"`
+	WaterDuration string  `json:"water_duration"`
+	Liters        float64 `json:"liters"`
+	KWh           float64 `json:"kwh"`
+	WaterCost     float64 `json:"water_cost"`
+	EnergyCost    float64 `json:"energy_cost"`
+	TotalCost     float64 `json:"total_cost"`
+
+	duration time.Duration
+}
+
+// add includes the watering duration and liters in the Usage and calculates new totals using the pricing. Costs
+// are not calculated if pricing is nil
+func (u *Usage) add(d time.Duration, liters float64, pricing *pkg.WaterPricing) {
+	u.WaterEvents++
+	u.duration += d
+	u.WaterDuration = u.duration.String()
+	u.Liters += liters
+
+	if pricing == nil {
+		return
+	}
+	_, kWh := pricing.EstimateUsage(d)
+	u.KWh += kWh
+	u.WaterCost += pricing.WaterCost(liters)
+	u.EnergyCost += pricing.EnergyCost(kWh)
+	u.TotalCost = u.WaterCost + u.EnergyCost
+}
+
+// eventLiters returns the liters recorded with a water event, like the ones measured by a flow meter, and
+// otherwise estimates them with the Zone's flow rate
+func eventLiters(g *pkg.Garden, z *pkg.Zone, d time.Duration, recorded *float64) float64 {
+	if recorded != nil {
+		return *recorded
+	}
+	liters, _ := z.EstimateLiters(g, d)
+	return liters
+}
+
+// Generate creates a Report for the Garden with the specified number of months, ending with the month that
+// contains now. Water history for each Zone is read from InfluxDB
+func Generate(ctx context.Context, influxdbClient metrics.Client, g *pkg.Garden, zones []*pkg.Zone, now time.Time, months int) (*Report, error) {
+	if g.Pricing == nil {
+		return nil, errors.New("garden does not have pricing configured")
+	}
+	if months < 1 {
+		return nil, errors.New("months must be at least 1")
+	}
+
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+
+	report := &Report{
+		GardenID: g.GetID(),
+		Currency: g.Pricing.Currency,
+	}
+
+	monthlyReports := map[string]*MonthlyReport{}
+	for m := start; !m.After(now); m = m.AddDate(0, 1, 0) {
+		monthly := &MonthlyReport{Month: m.Format(monthFormat), Zones: []*ZoneUsage{}}
+		monthlyReports[monthly.Month] = monthly
+		report.Months = append(report.Months, monthly)
+	}
+
+	for _, z := range zones {
+		if z.Position == nil {
+			continue
+		}
+
+		history, err := influxdbClient.GetWaterHistory(ctx, *z.Position, g.TopicPrefix, now.Sub(start), 0)
+		if err != nil {
+			return nil, fmt.Errorf("error getting water history for Zone %q: %w", z.GetID(), err)
+		}
+
+		zoneUsages := map[string]*ZoneUsage{}
+		for _, h := range history {
+			recordTime, ok := h["RecordTime"].(time.Time)
+			if !ok || recordTime.Before(start) {
+				continue
+			}
+			durationMillis, ok := h["Duration"].(int)
+			if !ok {
+				continue
+			}
+
+			monthly, ok := monthlyReports[recordTime.UTC().Format(monthFormat)]
+			if !ok {
+				continue
+			}
+
+			zoneUsage, ok := zoneUsages[monthly.Month]
+			if !ok {
+				zoneUsage = &ZoneUsage{ZoneID: z.GetID(), ZoneName: z.Name}
+				zoneUsages[monthly.Month] = zoneUsage
+				monthly.Zones = append(monthly.Zones, zoneUsage)
+			}
+
+			var measured *float64
+			if ml, ok := h["Milliliters"].(float64); ok {
+				liters := ml / 1000
+				measured = &liters
+			}
+
+			d := time.Duration(durationMillis) * time.Millisecond
+			liters := eventLiters(g, z, d, measured)
+			zoneUsage.add(d, liters, g.Pricing)
+			monthly.add(d, liters, g.Pricing)
+		}
+	}
+
+	for _, monthly := range report.Months {
+		if monthly.WaterDuration == "" {
+			monthly.WaterDuration = time.Duration(0).String()
+		}
+	}
+
+	return report, nil
+}
+
+// Summary creates a short human-readable summary of the MonthlyReport that can be used in notifications
+func (m *MonthlyReport) Summary(currency string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: watered %d times for %s using %.1f L and %.2f kWh. Total cost: %.2f %s",
+		m.Month, m.WaterEvents, m.WaterDuration, m.Liters, m.KWh, m.TotalCost, currency)
+	for _, z := range m.Zones {
+		fmt.Fprintf(&sb, "\n- %s: %.1f L, %.2f %s", z.ZoneName, z.Liters, z.TotalCost, currency)
+	}
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,66 @@
+package reports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGenerate(t *testing.T) {
+	id, _ := xid.FromString("c5cvhpcbcv45e8bp16dg")
+	zero := uint(0)
+
+	garden := &pkg.Garden{
+		ID:          babyapi.ID{ID: id},
+		TopicPrefix: "garden",
+		Pricing: &pkg.WaterPricing{
+			Currency:     "USD",
+			CostPerLiter: 0.01,
+			FlowRate:     10,
+		},
+	}
+	zones := []*pkg.Zone{
+		{ID: babyapi.ID{ID: id}, Name: "zone", Position: &zero},
+		{ID: babyapi.NewID(), Name: "no position"},
+	}
+
+	now := time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	influxdbClient := new(influxdb.MockClient)
+	influxdbClient.On("GetWaterHistory", mock.Anything, uint(0), "garden", now.Sub(time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)), uint64(0)).
+		Return([]map[string]interface{}{
+			{"Duration": 60000, "RecordTime": time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC)},
+			{"Duration": 120000, "RecordTime": time.Date(2023, time.February, 2, 0, 0, 0, 0, time.UTC)},
+			{"Duration": 120000, "RecordTime": time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		}, nil)
+
+	report, err := Generate(context.Background(), influxdbClient, garden, zones, now, 2)
+	assert.NoError(t, err)
+	influxdbClient.AssertExpectations(t)
+
+	assert.Len(t, report.Months, 2)
+	assert.Equal(t, "2023-02", report.Months[0].Month)
+	assert.Equal(t, 1, report.Months[0].WaterEvents)
+	assert.Equal(t, 20.0, report.Months[0].Liters)
+	assert.Equal(t, "2023-03", report.Months[1].Month)
+	assert.Equal(t, 10.0, report.Months[1].Liters)
+	assert.Equal(t, 0.1, report.Months[1].TotalCost)
+	assert.Len(t, report.Months[1].Zones, 1)
+
+	assert.Equal(t, "2023-02: watered 1 times for 2m0s using 20.0 L and 0.00 kWh. Total cost: 0.20 USD\n- zone: 20.0 L, 0.20 USD", report.Months[0].Summary(report.Currency))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate(context.Background(), nil, &pkg.Garden{}, nil, time.Now(), 1)
+	assert.EqualError(t, err, "garden does not have pricing configured")
+
+	_, err = Generate(context.Background(), nil, &pkg.Garden{Pricing: &pkg.WaterPricing{}}, nil, time.Now(), 0)
+	assert.EqualError(t, err, "months must be at least 1")
+}
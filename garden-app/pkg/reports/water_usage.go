@@ -0,0 +1,162 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+)
+
+// Period is the length of time that water usage is grouped by
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	PeriodYear  Period = "year"
+)
+
+// Validate returns an error if the Period is not known
+func (p Period) Validate() error {
+	switch p {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+		return nil
+	default:
+		return fmt.Errorf("invalid period %q: must be one of %q, %q, %q, or %q", p, PeriodDay, PeriodWeek, PeriodMonth, PeriodYear)
+	}
+}
+
+// start returns the beginning of the Period that contains t. Weeks start on Monday
+func (p Period) start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch p {
+	case PeriodWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case PeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case PeriodYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// add moves the time by n Periods
+func (p Period) add(t time.Time, n int) time.Time {
+	switch p {
+	case PeriodWeek:
+		return t.AddDate(0, 0, 7*n)
+	case PeriodMonth:
+		return t.AddDate(0, n, 0)
+	case PeriodYear:
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// UsageStart returns the beginning of the oldest of the count Periods that end with the one containing now
+func UsageStart(period Period, count int, now time.Time) time.Time {
+	return period.add(period.start(now), -(count - 1))
+}
+
+// WaterUsageReport contains the water usage for a Garden's Zones, organized by Period
+type WaterUsageReport struct {
+	GardenID string         `json:"garden_id"`
+	Period   Period         `json:"period"`
+	Currency string         `json:"currency,omitempty"`
+	Periods  []*PeriodUsage `json:"periods"`
+}
+
+// PeriodUsage contains the total usage for a single Period and the breakdown for each Zone
+type PeriodUsage struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Usage
+	Zones []*ZoneUsage `json:"zones"`
+}
+
+// WaterUsage groups the water history of each Zone by Period for the count Periods ending with the one that
+// contains now. History is a map of Zone IDs to their water history. Each event uses the liters measured by a flow
+// meter or estimated when it was recorded, and otherwise they are estimated using the Zone's flow rate. Costs are only
+// calculated for Gardens with pricing
+func WaterUsage(g *pkg.Garden, zones []*pkg.Zone, history map[string][]pkg.WaterHistory, period Period, count int, now time.Time) (*WaterUsageReport, error) {
+	err := period.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 {
+		return nil, errors.New("count must be at least 1")
+	}
+
+	loc, err := g.TimeLocation()
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	report := &WaterUsageReport{
+		GardenID: g.GetID(),
+		Period:   period,
+	}
+	if g.Pricing != nil {
+		report.Currency = g.Pricing.Currency
+	}
+
+	for start := UsageStart(period, count, now.In(loc)); !start.After(now); start = period.add(start, 1) {
+		report.Periods = append(report.Periods, &PeriodUsage{
+			Start: start,
+			End:   period.add(start, 1),
+			Zones: []*ZoneUsage{},
+		})
+	}
+
+	for _, z := range zones {
+		zoneUsages := map[*PeriodUsage]*ZoneUsage{}
+		for _, h := range history[z.GetID()] {
+			periodUsage := report.find(h.RecordTime)
+			if periodUsage == nil || h.Duration == nil {
+				continue
+			}
+			d := h.Duration.Duration
+
+			zoneUsage, ok := zoneUsages[periodUsage]
+			if !ok {
+				zoneUsage = &ZoneUsage{ZoneID: z.GetID(), ZoneName: z.Name}
+				zoneUsages[periodUsage] = zoneUsage
+				periodUsage.Zones = append(periodUsage.Zones, zoneUsage)
+			}
+
+			// Liters measured by a flow meter are more accurate than the ones estimated when watering
+			recorded := h.MeasuredLiters
+			if recorded == nil {
+				recorded = h.ExpectedLiters
+			}
+			liters := eventLiters(g, z, d, recorded)
+			zoneUsage.add(d, liters, g.Pricing)
+			periodUsage.add(d, liters, g.Pricing)
+		}
+	}
+
+	for _, periodUsage := range report.Periods {
+		if periodUsage.WaterDuration == "" {
+			periodUsage.WaterDuration = time.Duration(0).String()
+		}
+	}
+
+	return report, nil
+}
+
+// find returns the PeriodUsage that contains the time, or nil if it is outside of the report
+func (r *WaterUsageReport) find(t time.Time) *PeriodUsage {
+	for _, p := range r.Periods {
+		if !t.Before(p.Start) && t.Before(p.End) {
+			return p
+		}
+	}
+	return nil
+}
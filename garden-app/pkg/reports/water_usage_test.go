@@ -0,0 +1,105 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaterUsage(t *testing.T) {
+	zoneFlowRate := 2.0
+	measured := 3.5
+
+	garden := &pkg.Garden{
+		ID:      babyapi.NewID(),
+		Pricing: &pkg.WaterPricing{Currency: "USD", CostPerLiter: 0.01, FlowRate: 10},
+	}
+	zone1 := &pkg.Zone{ID: babyapi.NewID(), Name: "zone1"}
+	zone2 := &pkg.Zone{ID: babyapi.NewID(), Name: "zone2", FlowRate: &zoneFlowRate}
+
+	history := map[string][]pkg.WaterHistory{
+		zone1.GetID(): {
+			{Duration: &pkg.Duration{Duration: time.Minute}, RecordTime: time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC)},
+			{Duration: &pkg.Duration{Duration: 2 * time.Minute}, RecordTime: time.Date(2023, time.February, 2, 0, 0, 0, 0, time.UTC), MeasuredLiters: &measured},
+			{Duration: &pkg.Duration{Duration: 2 * time.Minute}, RecordTime: time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		zone2.GetID(): {
+			{Duration: &pkg.Duration{Duration: 5 * time.Minute}, RecordTime: time.Date(2023, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	now := time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	report, err := WaterUsage(garden, []*pkg.Zone{zone1, zone2}, history, PeriodMonth, 2, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, PeriodMonth, report.Period)
+	assert.Equal(t, "USD", report.Currency)
+	require.Len(t, report.Periods, 2)
+
+	feb := report.Periods[0]
+	assert.Equal(t, time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC), feb.Start)
+	assert.Equal(t, time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), feb.End)
+	assert.Equal(t, 1, feb.WaterEvents)
+	assert.Equal(t, 3.5, feb.Liters)
+
+	mar := report.Periods[1]
+	assert.Equal(t, 2, mar.WaterEvents)
+	assert.Equal(t, "6m0s", mar.WaterDuration)
+	assert.Equal(t, 20.0, mar.Liters)
+	assert.InDelta(t, 0.2, mar.TotalCost, 0.0001)
+	require.Len(t, mar.Zones, 2)
+	assert.Equal(t, 10.0, mar.Zones[0].Liters)
+	assert.Equal(t, 10.0, mar.Zones[1].Liters)
+}
+
+func TestWaterUsageWithoutPricing(t *testing.T) {
+	zone := &pkg.Zone{ID: babyapi.NewID(), Name: "zone"}
+	history := map[string][]pkg.WaterHistory{
+		zone.GetID(): {{Duration: &pkg.Duration{Duration: time.Hour}, RecordTime: time.Date(2023, time.March, 14, 12, 0, 0, 0, time.UTC)}},
+	}
+
+	report, err := WaterUsage(&pkg.Garden{ID: babyapi.NewID()}, []*pkg.Zone{zone}, history, PeriodDay, 1, time.Date(2023, time.March, 14, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	require.Len(t, report.Periods, 1)
+	assert.Equal(t, 1, report.Periods[0].WaterEvents)
+	assert.Equal(t, 0.0, report.Periods[0].Liters)
+	assert.Equal(t, 0.0, report.Periods[0].TotalCost)
+}
+
+func TestWaterUsageErrors(t *testing.T) {
+	_, err := WaterUsage(&pkg.Garden{}, nil, nil, "decade", 1, time.Now())
+	assert.EqualError(t, err, `invalid period "decade": must be one of "day", "week", "month", or "year"`)
+
+	_, err = WaterUsage(&pkg.Garden{}, nil, nil, PeriodMonth, 0, time.Now())
+	assert.EqualError(t, err, "count must be at least 1")
+}
+
+func TestUsageStart(t *testing.T) {
+	// Wednesday
+	now := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		period   Period
+		count    int
+		expected time.Time
+	}{
+		{PeriodDay, 1, time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{PeriodDay, 3, time.Date(2023, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		{PeriodWeek, 1, time.Date(2023, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		{PeriodWeek, 2, time.Date(2023, time.March, 6, 0, 0, 0, 0, time.UTC)},
+		{PeriodMonth, 3, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{PeriodYear, 2, time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			assert.Equal(t, tt.expected, UsageStart(tt.period, tt.count, now))
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package pkg
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Revision is a previous version of a resource that was saved before it was updated, so the update can be rolled
+// back. Numbers increase for each Revision of a resource and are not reused when old Revisions are removed
+type Revision struct {
+	Number     uint            `json:"number"`
+	RecordTime time.Time       `json:"record_time"`
+	Data       json.RawMessage `json:"data"`
+}
@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const maxSeasonalAdjustmentPercent = 300
+
+// SeasonalAdjustment scales durations from WaterSchedules for all of a Garden's Zones by month. It maps lowercase
+// month names to a percentage, like {"july": 130, "december": 50}, and months that are not set are not scaled. This
+// is a simple alternative to WeatherControl for Gardens without a WeatherClient
+type SeasonalAdjustment map[string]uint
+
+// Validate makes sure each key is a month name and each percentage is not more than 300. A percentage of 0 is
+// allowed to stop watering for the month
+func (sa SeasonalAdjustment) Validate() error {
+	months := make([]string, 0, len(sa))
+	for month := range sa {
+		months = append(months, month)
+	}
+	slices.Sort(months)
+
+	for _, month := range months {
+		_, err := parseMonth(month)
+		if err != nil {
+			return err
+		}
+		if sa[month] > maxSeasonalAdjustmentPercent {
+			return fmt.Errorf("invalid percentage %d for %s: must be between 0 and %d", sa[month], month, maxSeasonalAdjustmentPercent)
+		}
+	}
+	return nil
+}
+
+// Scale returns the factor used to scale durations in the month. It is 1 if the month is not set
+func (sa SeasonalAdjustment) Scale(month time.Month) float32 {
+	percent, ok := sa[strings.ToLower(month.String())]
+	if !ok {
+		return 1
+	}
+	return float32(percent) / 100
+}
+
+// parseMonth returns the time.Month for a lowercase month name like "january"
+func parseMonth(name string) (time.Month, error) {
+	for m := time.January; m <= time.December; m++ {
+		if strings.ToLower(m.String()) == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid month %q: must be a lowercase month name like \"january\"", name)
+}
+
+// SeasonalScale returns the factor from the Garden's SeasonalAdjustment for the month of t in the Garden's TimeZone,
+// or the server's local time if it is not set
+func (g *Garden) SeasonalScale(t time.Time) float32 {
+	if g == nil || len(g.SeasonalAdjustment) == 0 {
+		return 1
+	}
+
+	loc, err := g.TimeLocation()
+	if err != nil || loc == nil {
+		loc = time.Local
+	}
+	return g.SeasonalAdjustment.Scale(t.In(loc).Month())
+}
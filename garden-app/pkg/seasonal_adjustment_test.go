@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeasonalAdjustmentValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		adjustment  SeasonalAdjustment
+		expectedErr string
+	}{
+		{
+			"Successful",
+			SeasonalAdjustment{"july": 130, "december": 0},
+			"",
+		},
+		{
+			"SuccessfulNil",
+			nil,
+			"",
+		},
+		{
+			"ErrorInvalidMonth",
+			SeasonalAdjustment{"jul": 130},
+			`invalid month "jul": must be a lowercase month name like "january"`,
+		},
+		{
+			"ErrorUppercaseMonth",
+			SeasonalAdjustment{"July": 130},
+			`invalid month "July": must be a lowercase month name like "january"`,
+		},
+		{
+			"ErrorPercentTooHigh",
+			SeasonalAdjustment{"july": 400},
+			"invalid percentage 400 for july: must be between 0 and 300",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.adjustment.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestGardenSeasonalScale(t *testing.T) {
+	adjustment := SeasonalAdjustment{"july": 130, "august": 50, "december": 0}
+
+	tests := []struct {
+		name     string
+		garden   *Garden
+		t        time.Time
+		expected float32
+	}{
+		{"NilGarden", nil, time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), 1},
+		{"NoAdjustment", &Garden{TimeZone: "UTC"}, time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), 1},
+		{"MonthNotSet", &Garden{SeasonalAdjustment: adjustment, TimeZone: "UTC"}, time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC), 1},
+		{"Increase", &Garden{SeasonalAdjustment: adjustment, TimeZone: "UTC"}, time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), 1.3},
+		{"Zero", &Garden{SeasonalAdjustment: adjustment, TimeZone: "UTC"}, time.Date(2023, time.December, 15, 0, 0, 0, 0, time.UTC), 0},
+		{
+			"UsesTimeZone",
+			&Garden{SeasonalAdjustment: adjustment, TimeZone: "America/Phoenix"},
+			// this is still July in Phoenix
+			time.Date(2023, time.August, 1, 3, 0, 0, 0, time.UTC),
+			1.3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.garden.SeasonalScale(tt.t))
+		})
+	}
+}
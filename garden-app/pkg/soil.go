@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"fmt"
+	"time"
+)
+
+// SoilType describes how well a Zone's soil holds water. Sandy soil drains quickly, so each watering is shorter
+// since extra water is lost. Clay holds water much longer, so each watering is longer and soaks in slowly
+type SoilType string
+
+const (
+	SoilTypeSand SoilType = "sand"
+	SoilTypeLoam SoilType = "loam"
+	SoilTypeClay SoilType = "clay"
+)
+
+// soilTypeDurationScales are the built-in presets used to scale watering durations for each SoilType. Loam is
+// the baseline
+var soilTypeDurationScales = map[SoilType]float32{
+	SoilTypeSand: 0.75,
+	SoilTypeLoam: 1,
+	SoilTypeClay: 1.25,
+}
+
+const (
+	minCropCoefficient = 0.1
+	maxCropCoefficient = 2
+
+	minAdjustmentPercent = 10
+	maxAdjustmentPercent = 300
+)
+
+// Validate returns an error if the SoilType is not one of the presets. An empty SoilType is valid
+func (st SoilType) Validate() error {
+	if st == "" {
+		return nil
+	}
+	if _, ok := soilTypeDurationScales[st]; !ok {
+		return fmt.Errorf("invalid soil_type %q: must be one of %q, %q, or %q", st, SoilTypeSand, SoilTypeLoam, SoilTypeClay)
+	}
+	return nil
+}
+
+// DurationScale returns the factor used to scale watering durations for the SoilType. An empty or unknown
+// SoilType does not scale
+func (st SoilType) DurationScale() float32 {
+	scale, ok := soilTypeDurationScales[st]
+	if !ok {
+		return 1
+	}
+	return scale
+}
+
+// validateCropCoefficient makes sure the coefficient is in a reasonable range. Most plants are between 0.3 and 1.2
+func validateCropCoefficient(kc float32) error {
+	if kc < minCropCoefficient || kc > maxCropCoefficient {
+		return fmt.Errorf("invalid crop_coefficient %v: must be between %v and %v", kc, minCropCoefficient, maxCropCoefficient)
+	}
+	return nil
+}
+
+// validateAdjustmentPercent makes sure a manual adjustment does not accidentally stop or flood the Zone
+func validateAdjustmentPercent(percent uint) error {
+	if percent < minAdjustmentPercent || percent > maxAdjustmentPercent {
+		return fmt.Errorf("invalid adjustment_percent %d: must be between %d and %d", percent, minAdjustmentPercent, maxAdjustmentPercent)
+	}
+	return nil
+}
+
+// WaterDurationScale returns the factor used to scale the Zone's watering durations based on its SoilType,
+// CropCoefficient, and AdjustmentPercent. It is 1 if none are set
+func (z *Zone) WaterDurationScale() float32 {
+	scale := z.SoilType.DurationScale()
+	if z.CropCoefficient != nil {
+		scale *= *z.CropCoefficient
+	}
+	if z.AdjustmentPercent != nil {
+		scale *= float32(*z.AdjustmentPercent) / 100
+	}
+	return scale
+}
+
+// ScaleWaterDuration applies the Zone's WaterDurationScale to a duration from one of its WaterSchedules
+func (z *Zone) ScaleWaterDuration(d time.Duration) time.Duration {
+	return ScaleDuration(d, z.WaterDurationScale())
+}
+
+// ScaleDuration multiplies the duration by scale. The result is rounded to milliseconds since that is the precision
+// used for water commands and it hides float32 rounding errors, like 1.2 scaling 1 hour to 1h12m0.000171661s.
+// The duration is not changed when scale is 1
+func ScaleDuration(d time.Duration, scale float32) time.Duration {
+	if scale == 1 {
+		return d
+	}
+	return time.Duration(float64(d) * float64(scale)).Round(time.Millisecond)
+}
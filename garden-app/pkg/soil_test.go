@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZoneWaterDurationScale(t *testing.T) {
+	half := float32(0.5)
+	adjustment := uint(120)
+	tests := []struct {
+		name     string
+		zone     *Zone
+		expected time.Duration
+	}{
+		{"Default", &Zone{}, time.Hour},
+		{"Sand", &Zone{SoilType: SoilTypeSand}, 45 * time.Minute},
+		{"Loam", &Zone{SoilType: SoilTypeLoam}, time.Hour},
+		{"Clay", &Zone{SoilType: SoilTypeClay}, 75 * time.Minute},
+		{"CropCoefficient", &Zone{CropCoefficient: &half}, 30 * time.Minute},
+		{"ClayAndCropCoefficient", &Zone{SoilType: SoilTypeClay, CropCoefficient: &half}, 37*time.Minute + 30*time.Second},
+		{"AdjustmentPercent", &Zone{AdjustmentPercent: &adjustment}, 72 * time.Minute},
+		{"CropCoefficientAndAdjustmentPercent", &Zone{CropCoefficient: &half, AdjustmentPercent: &adjustment}, 36 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.zone.ScaleWaterDuration(time.Hour))
+		})
+	}
+}
+
+func TestScaleDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		scale    float32
+		expected time.Duration
+	}{
+		{"NoScale", time.Hour, 1, time.Hour},
+		{"NoScaleKeepsSubMillisecond", time.Microsecond, 1, time.Microsecond},
+		{"BelowOne", time.Hour, 0.5, 30 * time.Minute},
+		{"BelowOneFloatError", time.Hour, 0.9, 54 * time.Minute},
+		{"AboveOne", time.Hour, 1.5, 90 * time.Minute},
+		{"AboveOneFloatError", time.Hour, 1.2, 72 * time.Minute},
+		{"SubMillisecondRoundsDown", time.Microsecond, 0.5, 0},
+		{"SubMillisecondRemainderRoundsDown", 1400 * time.Microsecond, 0.5, time.Millisecond},
+		{"SubMillisecondRemainderRoundsUp", 3 * time.Millisecond, 0.5, 2 * time.Millisecond},
+		{"SubMillisecondRemainderAboveOne", 1100 * time.Microsecond, 1.5, 2 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ScaleDuration(tt.duration, tt.scale))
+		})
+	}
+}
+
+func TestZoneBindSoilAndCrop(t *testing.T) {
+	position := uint(0)
+	zero := float32(0)
+	tooHigh := float32(2.5)
+	valid := float32(1.1)
+	lowAdjustment := uint(5)
+	highAdjustment := uint(400)
+
+	tests := []struct {
+		name        string
+		zone        *Zone
+		expectedErr string
+	}{
+		{
+			"Valid",
+			&Zone{SoilType: SoilTypeSand, CropCoefficient: &valid},
+			"",
+		},
+		{
+			"ErrorInvalidSoilType",
+			&Zone{SoilType: "gravel"},
+			`invalid soil_type "gravel": must be one of "sand", "loam", or "clay"`,
+		},
+		{
+			"ErrorZeroCropCoefficient",
+			&Zone{CropCoefficient: &zero},
+			"invalid crop_coefficient 0: must be between 0.1 and 2",
+		},
+		{
+			"ErrorCropCoefficientTooHigh",
+			&Zone{CropCoefficient: &tooHigh},
+			"invalid crop_coefficient 2.5: must be between 0.1 and 2",
+		},
+		{
+			"ErrorAdjustmentPercentTooLow",
+			&Zone{AdjustmentPercent: &lowAdjustment},
+			"invalid adjustment_percent 5: must be between 10 and 300",
+		},
+		{
+			"ErrorAdjustmentPercentTooHigh",
+			&Zone{AdjustmentPercent: &highAdjustment},
+			"invalid adjustment_percent 400: must be between 10 and 300",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.zone.ID = babyapi.NewID()
+			tt.zone.Name = "zone"
+			tt.zone.Position = &position
+
+			err := tt.zone.Bind(&http.Request{Method: http.MethodPut})
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
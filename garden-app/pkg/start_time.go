@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/jsonschema"
 )
 
 const (
@@ -54,6 +56,11 @@ func (st *StartTime) Validate() error {
 	return nil
 }
 
+// JSONSchema describes a StartTime as a string time of day with a time zone offset like "23:00:00-07:00"
+func (st *StartTime) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "string", Format: "time"}
+}
+
 func (st *StartTime) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf(`"%s"`, st.String())), nil
 }
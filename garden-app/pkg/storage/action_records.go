@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/madflojo/hord"
+)
+
+// maxActionRecords is the number of ActionRecords kept for each Zone by the KV storage. Older records are removed
+// when new ones are added
+const maxActionRecords = 100
+
+// ActionRecordStorage keeps track of the status of actions sent to controllers so a specific request can be
+// followed until it is complete
+type ActionRecordStorage interface {
+	// SetActionRecord creates or replaces the ActionRecord with the same ID
+	SetActionRecord(ctx context.Context, record pkg.ActionRecord) error
+	// GetActionRecord returns the ActionRecord with the ID, or babyapi.ErrNotFound if it does not exist
+	GetActionRecord(ctx context.Context, id string) (*pkg.ActionRecord, error)
+	// GetZoneActionRecords returns the Zone's ActionRecords, starting with the most recent. A limit of 0 returns all
+	// records
+	GetZoneActionRecords(ctx context.Context, zoneID string, limit uint64) ([]pkg.ActionRecord, error)
+}
+
+// kvActionRecordStorage stores each ActionRecord as JSON in a hord.Database and keeps a list of each Zone's
+// ActionRecord IDs so they can be listed
+type kvActionRecordStorage struct {
+	db hord.Database
+	mu sync.Mutex
+}
+
+func newKVActionRecordStorage(db hord.Database) *kvActionRecordStorage {
+	return &kvActionRecordStorage{db: db}
+}
+
+func actionRecordKey(id string) string {
+	return "ActionRecord_" + id
+}
+
+func zoneActionRecordsKey(zoneID string) string {
+	return "ActionRecords_" + zoneID
+}
+
+// SetActionRecord stores the ActionRecord. New records are added to the beginning of the Zone's list and the
+// oldest records are removed when there are more than maxActionRecords
+func (s *kvActionRecordStorage) SetActionRecord(_ context.Context, record pkg.ActionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error marshalling action record: %w", err)
+	}
+
+	err = s.db.Set(actionRecordKey(record.ID), data)
+	if err != nil {
+		return fmt.Errorf("error writing action record: %w", err)
+	}
+
+	ids, err := s.getZoneIDs(record.ZoneID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(ids, record.ID) {
+		return nil
+	}
+
+	ids = append([]string{record.ID}, ids...)
+	if len(ids) > maxActionRecords {
+		for _, id := range ids[maxActionRecords:] {
+			err = s.db.Delete(actionRecordKey(id))
+			if err != nil {
+				return fmt.Errorf("error deleting old action record: %w", err)
+			}
+		}
+		ids = ids[:maxActionRecords]
+	}
+
+	data, err = json.Marshal(ids)
+	if err != nil {
+		return fmt.Errorf("error marshalling action record IDs: %w", err)
+	}
+
+	err = s.db.Set(zoneActionRecordsKey(record.ZoneID), data)
+	if err != nil {
+		return fmt.Errorf("error writing action record IDs: %w", err)
+	}
+
+	return nil
+}
+
+// GetActionRecord reads the ActionRecord
+func (s *kvActionRecordStorage) GetActionRecord(_ context.Context, id string) (*pkg.ActionRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(id)
+}
+
+// GetZoneActionRecords reads the Zone's list and then each ActionRecord in it
+func (s *kvActionRecordStorage) GetZoneActionRecords(_ context.Context, zoneID string, limit uint64) ([]pkg.ActionRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.getZoneIDs(zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []pkg.ActionRecord{}
+	for _, id := range ids {
+		if limit > 0 && uint64(len(result)) >= limit {
+			break
+		}
+
+		record, err := s.get(id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *record)
+	}
+
+	return result, nil
+}
+
+func (s *kvActionRecordStorage) get(id string) (*pkg.ActionRecord, error) {
+	data, err := s.db.Get(actionRecordKey(id))
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return nil, babyapi.ErrNotFound
+		}
+		return nil, fmt.Errorf("error getting action record: %w", err)
+	}
+
+	var result pkg.ActionRecord
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing action record: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (s *kvActionRecordStorage) getZoneIDs(zoneID string) ([]string, error) {
+	data, err := s.db.Get(zoneActionRecordsKey(zoneID))
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting action record IDs: %w", err)
+	}
+
+	var result []string
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing action record IDs: %w", err)
+	}
+
+	return result, nil
+}
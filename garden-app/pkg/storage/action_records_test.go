@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVActionRecordStorage(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	records, err := client.ActionRecords.GetZoneActionRecords(ctx, "zone", 0)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	_, err = client.ActionRecords.GetActionRecord(ctx, "missing")
+	assert.ErrorIs(t, err, babyapi.ErrNotFound)
+
+	record := pkg.ActionRecord{ID: "first", Type: "water", ZoneID: "zone", CreatedAt: now}
+	record.SetStatus(pkg.ActionStatusPublished, "", now)
+	require.NoError(t, client.ActionRecords.SetActionRecord(ctx, record))
+	require.NoError(t, client.ActionRecords.SetActionRecord(ctx, pkg.ActionRecord{ID: "second", Type: "water", ZoneID: "zone", CreatedAt: now}))
+
+	t.Run("UpdateExisting", func(t *testing.T) {
+		record.SetStatus(pkg.ActionStatusCompleted, "", now.Add(time.Minute))
+		require.NoError(t, client.ActionRecords.SetActionRecord(ctx, record))
+
+		result, err := client.ActionRecords.GetActionRecord(ctx, "first")
+		require.NoError(t, err)
+		assert.Equal(t, pkg.ActionStatusCompleted, result.Status)
+		assert.Len(t, result.History, 2)
+
+		records, err := client.ActionRecords.GetZoneActionRecords(ctx, "zone", 0)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "second", records[0].ID)
+		assert.Equal(t, "first", records[1].ID)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		records, err := client.ActionRecords.GetZoneActionRecords(ctx, "zone", 1)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "second", records[0].ID)
+	})
+
+	t.Run("OldestRemoved", func(t *testing.T) {
+		for i := 0; i < maxActionRecords; i++ {
+			require.NoError(t, client.ActionRecords.SetActionRecord(ctx, pkg.ActionRecord{
+				ID:        fmt.Sprintf("record%d", i),
+				ZoneID:    "zone",
+				CreatedAt: now,
+			}))
+		}
+
+		records, err := client.ActionRecords.GetZoneActionRecords(ctx, "zone", 0)
+		require.NoError(t, err)
+		require.Len(t, records, maxActionRecords)
+		assert.Equal(t, fmt.Sprintf("record%d", maxActionRecords-1), records[0].ID)
+
+		_, err = client.ActionRecords.GetActionRecord(ctx, "first")
+		assert.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+}
@@ -0,0 +1,370 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/calvinmclean/babyapi"
+)
+
+// Actions used in a ResourceChange
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// ResourceChange describes how one resource is changed by applying a declarative Export. Fields has the names of
+// changed fields for updates
+type ResourceChange struct {
+	ResourceType string   `json:"resource_type"`
+	ResourceID   string   `json:"resource_id"`
+	Name         string   `json:"name,omitempty"`
+	Action       string   `json:"action"`
+	Fields       []string `json:"fields,omitempty"`
+}
+
+// String formats the change as a line of a diff
+func (c ResourceChange) String() string {
+	prefix := map[string]string{
+		ChangeCreated: "+",
+		ChangeUpdated: "~",
+		ChangeDeleted: "-",
+	}[c.Action]
+
+	result := fmt.Sprintf("%s %s %s", prefix, c.ResourceType, c.ResourceID)
+	if c.Name != "" {
+		result += fmt.Sprintf(" (%s)", c.Name)
+	}
+	if len(c.Fields) > 0 {
+		result += ": " + strings.Join(c.Fields, ", ")
+	}
+	return result
+}
+
+// ApplyPlan has the changes needed to make storage match a declarative Export
+type ApplyPlan struct {
+	Changes []ResourceChange
+	// Saved has the resources that are created or updated
+	Saved *Export
+	// Pruned has the resources that are not in the Export. They are deleted like they are with the API, so
+	// end-dateable resources are end-dated instead of removed
+	Pruned *Export
+}
+
+// WriteDiff writes one line for each change
+func (p *ApplyPlan) WriteDiff(w io.Writer) error {
+	if len(p.Changes) == 0 {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+
+	for _, c := range p.Changes {
+		_, err := fmt.Fprintln(w, c.String())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PlanApply compares the desired Export to storage. Resources that are unchanged are not saved again and, if prune is
+// true, resources that are not in the Export are deleted. Existing resources keep their created_at and start_date if
+// the Export does not set them. The Export is validated the same way as Import
+func (c *Client) PlanApply(ctx context.Context, desired *Export, prune bool) (*ApplyPlan, error) {
+	if desired == nil {
+		return nil, errors.New("missing desired resources")
+	}
+
+	err := keepDefaultedFields(ctx, c.WeatherClientConfigs, desired.WeatherClients)
+	if err == nil {
+		err = keepDefaultedFields(ctx, c.WaterSchedules, desired.WaterSchedules)
+	}
+	if err == nil {
+		err = keepDefaultedFields(ctx, c.Gardens, desired.Gardens)
+	}
+	if err == nil {
+		err = keepDefaultedFields(ctx, c.Zones, desired.Zones)
+	}
+	if err == nil {
+		err = keepDefaultedFields(ctx, c.ZoneGroups, desired.ZoneGroups)
+	}
+	if err == nil {
+		err = keepDefaultedFields(ctx, c.Plants, desired.Plants)
+	}
+	if err == nil {
+		err = keepDefaultedFields(ctx, c.Reminders, desired.Reminders)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateImport(ctx, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ApplyPlan{
+		Changes: []ResourceChange{},
+		Saved:   &Export{},
+		Pruned:  &Export{},
+	}
+
+	// Resources are planned in the same order that Import saves them
+	plan.Saved.WeatherClients, plan.Pruned.WeatherClients, err = planResources(ctx, plan, "weather_client", c.WeatherClientConfigs, desired.WeatherClients, prune)
+	if err != nil {
+		return nil, err
+	}
+	plan.Saved.WaterSchedules, plan.Pruned.WaterSchedules, err = planResources(ctx, plan, "water_schedule", c.WaterSchedules, desired.WaterSchedules, prune)
+	if err != nil {
+		return nil, err
+	}
+	plan.Saved.Gardens, plan.Pruned.Gardens, err = planResources(ctx, plan, "garden", c.Gardens, desired.Gardens, prune)
+	if err != nil {
+		return nil, err
+	}
+	plan.Saved.Zones, plan.Pruned.Zones, err = planResources(ctx, plan, "zone", c.Zones, desired.Zones, prune)
+	if err != nil {
+		return nil, err
+	}
+	plan.Saved.ZoneGroups, plan.Pruned.ZoneGroups, err = planResources(ctx, plan, "zone_group", c.ZoneGroups, desired.ZoneGroups, prune)
+	if err != nil {
+		return nil, err
+	}
+	plan.Saved.Plants, plan.Pruned.Plants, err = planResources(ctx, plan, "plant", c.Plants, desired.Plants, prune)
+	if err != nil {
+		return nil, err
+	}
+	plan.Saved.Reminders, plan.Pruned.Reminders, err = planResources(ctx, plan, "reminder", c.Reminders, desired.Reminders, prune)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// Apply saves and deletes the resources from the ApplyPlan. Pruned resources are deleted before the resources that
+// they reference
+func (c *Client) Apply(ctx context.Context, plan *ApplyPlan) error {
+	err := c.Import(ctx, plan.Saved)
+	if err != nil {
+		return err
+	}
+
+	err = deleteResources(ctx, "Reminder", c.Reminders, plan.Pruned.Reminders)
+	if err == nil {
+		err = deleteResources(ctx, "Plant", c.Plants, plan.Pruned.Plants)
+	}
+	if err == nil {
+		err = deleteResources(ctx, "ZoneGroup", c.ZoneGroups, plan.Pruned.ZoneGroups)
+	}
+	if err == nil {
+		err = deleteResources(ctx, "Zone", c.Zones, plan.Pruned.Zones)
+	}
+	if err == nil {
+		err = deleteResources(ctx, "Garden", c.Gardens, plan.Pruned.Gardens)
+	}
+	if err == nil {
+		err = deleteResources(ctx, "WaterSchedule", c.WaterSchedules, plan.Pruned.WaterSchedules)
+	}
+	if err == nil {
+		err = deleteResources(ctx, "WeatherClient", c.WeatherClientConfigs, plan.Pruned.WeatherClients)
+	}
+	return err
+}
+
+// defaultedFields are set to the current time by validation if they are not set
+var defaultedFields = []string{"created_at", "start_date"}
+
+// keepDefaultedFields copies defaultedFields from existing resources to desired resources that do not set them.
+// Otherwise, validation would set them to the current time and every apply would update them
+func keepDefaultedFields[T babyapi.Resource](ctx context.Context, s babyapi.Storage[T], desired []T) error {
+	for _, d := range desired {
+		if reflect.ValueOf(d).IsNil() {
+			continue
+		}
+
+		existing, err := s.Get(ctx, d.GetID())
+		if err != nil {
+			continue
+		}
+
+		desiredFields, err := resourceFields(d)
+		if err != nil {
+			return err
+		}
+		existingFields, err := resourceFields(existing)
+		if err != nil {
+			return err
+		}
+
+		missing := map[string]json.RawMessage{}
+		for _, field := range defaultedFields {
+			isSet := desiredFields[field] != nil && string(desiredFields[field]) != "null"
+			value, isExisting := existingFields[field]
+			if !isSet && isExisting && string(value) != "null" {
+				missing[field] = value
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		data, err := json.Marshal(missing)
+		if err != nil {
+			return fmt.Errorf("error encoding existing fields for %q: %w", d.GetID(), err)
+		}
+		err = json.Unmarshal(data, d)
+		if err != nil {
+			return fmt.Errorf("error setting existing fields for %q: %w", d.GetID(), err)
+		}
+	}
+	return nil
+}
+
+// planResources adds changes to the plan for one type of resource and returns the resources that are saved and
+// pruned. Resources that are already end-dated are not pruned again
+func planResources[T babyapi.Resource](ctx context.Context, plan *ApplyPlan, resourceType string, s babyapi.Storage[T], desired []T, prune bool) ([]T, []T, error) {
+	all, err := s.GetAll(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get all resources for %s: %w", resourceType, err)
+	}
+
+	existing := map[string]T{}
+	for _, e := range all {
+		existing[e.GetID()] = e
+	}
+
+	saved := []T{}
+	for _, d := range desired {
+		desiredFields, err := resourceFields(d)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		change := ResourceChange{
+			ResourceType: resourceType,
+			ResourceID:   d.GetID(),
+			Name:         resourceName(desiredFields),
+			Action:       ChangeCreated,
+		}
+
+		e, ok := existing[d.GetID()]
+		delete(existing, d.GetID())
+		if ok {
+			existingFields, err := resourceFields(e)
+			if err != nil {
+				return nil, nil, err
+			}
+			change.Action = ChangeUpdated
+			change.Fields = changedFields(desiredFields, existingFields)
+			if len(change.Fields) == 0 {
+				continue
+			}
+		}
+
+		plan.Changes = append(plan.Changes, change)
+		saved = append(saved, d)
+	}
+
+	pruned := []T{}
+	if !prune {
+		return saved, pruned, nil
+	}
+
+	// Remaining resources are sorted so the plan is the same each time
+	ids := []string{}
+	for id := range existing {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		e := existing[id]
+		if endDateable, ok := any(e).(babyapi.EndDateable); ok && endDateable.EndDated() {
+			continue
+		}
+
+		fields, err := resourceFields(e)
+		if err != nil {
+			return nil, nil, err
+		}
+		plan.Changes = append(plan.Changes, ResourceChange{
+			ResourceType: resourceType,
+			ResourceID:   id,
+			Name:         resourceName(fields),
+			Action:       ChangeDeleted,
+		})
+		pruned = append(pruned, e)
+	}
+
+	return saved, pruned, nil
+}
+
+func deleteResources[T babyapi.Resource](ctx context.Context, name string, s babyapi.Storage[T], resources []T) error {
+	for _, r := range resources {
+		err := s.Delete(ctx, r.GetID())
+		if err != nil {
+			return fmt.Errorf("error deleting %s %q: %w", name, r.GetID(), err)
+		}
+	}
+	return nil
+}
+
+// resourceFields gets the top-level JSON fields of a resource so they can be compared
+func resourceFields(resource any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding resource: %w", err)
+	}
+
+	var result map[string]json.RawMessage
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding resource: %w", err)
+	}
+	return result, nil
+}
+
+// resourceName gets the name field of a resource, if it has one
+func resourceName(fields map[string]json.RawMessage) string {
+	var name string
+	_ = json.Unmarshal(fields["name"], &name)
+	return name
+}
+
+// changedFields returns the sorted names of fields that are different or only set in one of the resources
+func changedFields(desired, existing map[string]json.RawMessage) []string {
+	result := []string{}
+	for key, value := range desired {
+		if !jsonEqual(value, existing[key]) {
+			result = append(result, key)
+		}
+	}
+	for key := range existing {
+		if _, ok := desired[key]; !ok {
+			result = append(result, key)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// jsonEqual compares the decoded values so formatting differences are ignored
+func jsonEqual(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	var aValue, bValue any
+	if json.Unmarshal(a, &aValue) != nil || json.Unmarshal(b, &bValue) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
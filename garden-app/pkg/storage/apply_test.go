@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*Client, *Export) {
+		t.Helper()
+		client, err := NewClient(Config{Driver: "hashmap"})
+		require.NoError(t, err)
+		createExportResources(t, client)
+
+		export, err := client.Export(ctx)
+		require.NoError(t, err)
+
+		// Apply once so defaults from validation are saved
+		plan, err := client.PlanApply(ctx, export, false)
+		require.NoError(t, err)
+		require.NoError(t, client.Apply(ctx, plan))
+
+		export, err = client.Export(ctx)
+		require.NoError(t, err)
+		return client, export
+	}
+
+	t.Run("NoChanges", func(t *testing.T) {
+		client, export := setup(t)
+
+		plan, err := client.PlanApply(ctx, export, true)
+		require.NoError(t, err)
+		assert.Empty(t, plan.Changes)
+
+		var buf bytes.Buffer
+		require.NoError(t, plan.WriteDiff(&buf))
+		assert.Equal(t, "no changes\n", buf.String())
+	})
+
+	t.Run("CreateAndUpdate", func(t *testing.T) {
+		client, export := setup(t)
+
+		export.Gardens[0].Name = "renamed"
+		// Leaving out created_at keeps the existing value
+		createdAt := export.Gardens[0].CreatedAt
+		export.Gardens[0].CreatedAt = nil
+
+		maxZones := uint(1)
+		newGarden := &pkg.Garden{
+			ID:          babyapi.NewID(),
+			Name:        "new garden",
+			TopicPrefix: "new-garden",
+			MaxZones:    &maxZones,
+		}
+		export.Gardens = append(export.Gardens, newGarden)
+
+		plan, err := client.PlanApply(ctx, export, false)
+		require.NoError(t, err)
+		assert.Equal(t, []ResourceChange{
+			{ResourceType: "garden", ResourceID: export.Gardens[0].GetID(), Name: "renamed", Action: ChangeUpdated, Fields: []string{"name"}},
+			{ResourceType: "garden", ResourceID: newGarden.GetID(), Name: "new garden", Action: ChangeCreated},
+		}, plan.Changes)
+
+		var buf bytes.Buffer
+		require.NoError(t, plan.WriteDiff(&buf))
+		assert.Equal(t, "~ garden "+export.Gardens[0].GetID()+" (renamed): name\n+ garden "+newGarden.GetID()+" (new garden)\n", buf.String())
+
+		require.NoError(t, client.Apply(ctx, plan))
+
+		g, err := client.Gardens.Get(ctx, export.Gardens[0].GetID())
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", g.Name)
+		assert.Equal(t, createdAt.Unix(), g.CreatedAt.Unix())
+
+		_, err = client.Gardens.Get(ctx, newGarden.GetID())
+		require.NoError(t, err)
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		client, export := setup(t)
+
+		reminder := export.Reminders[0]
+		export.Reminders = nil
+
+		plan, err := client.PlanApply(ctx, export, false)
+		require.NoError(t, err)
+		assert.Empty(t, plan.Changes)
+
+		plan, err = client.PlanApply(ctx, export, true)
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, 1)
+		assert.Equal(t, ChangeDeleted, plan.Changes[0].Action)
+		assert.Equal(t, reminder.GetID(), plan.Changes[0].ResourceID)
+
+		require.NoError(t, client.Apply(ctx, plan))
+
+		// End-dated resources are not pruned again
+		r, err := client.Reminders.Get(ctx, reminder.GetID())
+		require.NoError(t, err)
+		assert.True(t, r.EndDated())
+
+		plan, err = client.PlanApply(ctx, export, true)
+		require.NoError(t, err)
+		assert.Empty(t, plan.Changes)
+	})
+
+	t.Run("ErrorInvalid", func(t *testing.T) {
+		client, export := setup(t)
+		export.Zones[0].Position = nil
+
+		_, err := client.PlanApply(ctx, export, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing required position field")
+	})
+}
+
+func TestResourceChangeString(t *testing.T) {
+	change := ResourceChange{
+		ResourceType: "zone",
+		ResourceID:   "c5cvhpcbcv45e8bp16dg",
+		Action:       ChangeDeleted,
+	}
+	assert.Equal(t, "- zone c5cvhpcbcv45e8bp16dg", change.String())
+
+	change.Action = ChangeUpdated
+	change.Name = "Zone 1"
+	change.Fields = []string{"name", "position"}
+	assert.Equal(t, "~ zone c5cvhpcbcv45e8bp16dg (Zone 1): name, position", change.String())
+}
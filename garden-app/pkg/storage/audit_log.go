@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/madflojo/hord"
+	"github.com/rs/xid"
+)
+
+// maxAuditEntries is the number of audit entries kept by the KV storage. Older entries are removed when new ones
+// are added
+const maxAuditEntries = 10000
+
+const (
+	auditLogPrefix = "AuditLog_"
+
+	// oldAuditLogKey was used to store all audit entries as a single JSON list
+	oldAuditLogKey = "AuditLog"
+)
+
+// AuditLogStorage is an append-only record of executed actions and resource changes
+type AuditLogStorage interface {
+	// AddAuditEntry records a new entry
+	AddAuditEntry(ctx context.Context, entry pkg.AuditEntry) error
+	// GetAuditEntries returns entries recorded after since, starting with the most recent. Empty resourceType or
+	// resourceID will not filter by that field and a limit of 0 returns all entries
+	GetAuditEntries(ctx context.Context, resourceType, resourceID string, since time.Time, limit uint64) ([]pkg.AuditEntry, error)
+}
+
+// kvAuditLogStorage stores each audit entry with its own key in a hord.Database. The keys sort by the entry's
+// Timestamp
+type kvAuditLogStorage struct {
+	db hord.Database
+}
+
+func newKVAuditLogStorage(db hord.Database) *kvAuditLogStorage {
+	return &kvAuditLogStorage{db: db}
+}
+
+// migrateAuditLogKeys moves each entry from the list saved with oldAuditLogKey to its own key. The keys use the
+// entry's position in the list so migrating again does not add duplicates
+func migrateAuditLogKeys(db hord.Database) error {
+	data, err := db.Get(oldAuditLogKey)
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return nil
+		}
+		return fmt.Errorf("error getting audit log: %w", err)
+	}
+
+	var all []pkg.AuditEntry
+	err = json.Unmarshal(data, &all)
+	if err != nil {
+		return fmt.Errorf("error parsing audit log: %w", err)
+	}
+
+	for i, entry := range all {
+		err = setAuditEntry(db, entryKey(auditLogPrefix, entry.Timestamp, fmt.Sprintf("migrated%06d", i)), entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = db.Delete(oldAuditLogKey)
+	if err != nil {
+		return fmt.Errorf("error deleting audit log list: %w", err)
+	}
+
+	return nil
+}
+
+// AddAuditEntry stores the entry and then removes the oldest entries when there are more than maxAuditEntries
+func (s *kvAuditLogStorage) AddAuditEntry(_ context.Context, entry pkg.AuditEntry) error {
+	err := setAuditEntry(s.db, entryKey(auditLogPrefix, entry.Timestamp, xid.New().String()), entry)
+	if err != nil {
+		return err
+	}
+
+	err = trimEntries(s.db, auditLogPrefix, maxAuditEntries)
+	if err != nil {
+		return fmt.Errorf("error removing old audit entries: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditEntries reads the entries, starting with the most recent, and filters them
+func (s *kvAuditLogStorage) GetAuditEntries(_ context.Context, resourceType, resourceID string, since time.Time, limit uint64) ([]pkg.AuditEntry, error) {
+	keys, err := entryKeys(s.db, auditLogPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("error getting audit log: %w", err)
+	}
+
+	result := []pkg.AuditEntry{}
+	for _, key := range keys {
+		if limit > 0 && uint64(len(result)) >= limit {
+			break
+		}
+
+		entry, err := getAuditEntry(s.db, key)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			// removed after the keys were read
+			continue
+		}
+		// entries are sorted, so everything after this is also too old
+		if entry.Timestamp.Before(since) {
+			break
+		}
+		if resourceType != "" && resourceType != entry.ResourceType {
+			continue
+		}
+		if resourceID != "" && resourceID != entry.ResourceID {
+			continue
+		}
+		result = append(result, *entry)
+	}
+
+	return result, nil
+}
+
+// getAuditEntry reads the entry with the key. It returns nil if the entry does not exist
+func getAuditEntry(db hord.Database, key string) (*pkg.AuditEntry, error) {
+	data, err := db.Get(key)
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting audit entry: %w", err)
+	}
+
+	var result pkg.AuditEntry
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing audit entry: %w", err)
+	}
+
+	return &result, nil
+}
+
+func setAuditEntry(db hord.Database, key string, entry pkg.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error marshalling audit entry: %w", err)
+	}
+
+	err = db.Set(key, data)
+	if err != nil {
+		return fmt.Errorf("error writing audit entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	"github.com/madflojo/hord"
+	"github.com/madflojo/hord/drivers/hashmap"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVAuditLogStorage(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	entries, err := client.AuditLog.GetAuditEntries(ctx, "", "", time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	for i, entry := range []pkg.AuditEntry{
+		{ResourceType: "garden", ResourceID: "garden1", Action: "created"},
+		{ResourceType: "zone", ResourceID: "zone1", Action: "created"},
+		{ResourceType: "zone", ResourceID: "zone1", Action: "water_action"},
+		{ResourceType: "zone", ResourceID: "zone2", Action: "water_action"},
+	} {
+		entry.Timestamp = now.Add(time.Duration(i) * time.Hour)
+		entry.Source = pkg.AuditSourceAPI
+		require.NoError(t, client.AuditLog.AddAuditEntry(ctx, entry))
+	}
+
+	t.Run("MostRecentFirst", func(t *testing.T) {
+		entries, err := client.AuditLog.GetAuditEntries(ctx, "", "", time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, entries, 4)
+		assert.Equal(t, "zone2", entries[0].ResourceID)
+		assert.Equal(t, "garden1", entries[3].ResourceID)
+	})
+
+	t.Run("ResourceAndID", func(t *testing.T) {
+		entries, err := client.AuditLog.GetAuditEntries(ctx, "zone", "zone1", time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "water_action", entries[0].Action)
+		assert.Equal(t, "created", entries[1].Action)
+	})
+
+	t.Run("ResourceOnly", func(t *testing.T) {
+		entries, err := client.AuditLog.GetAuditEntries(ctx, "zone", "", time.Time{}, 0)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3)
+	})
+
+	t.Run("SinceAndLimit", func(t *testing.T) {
+		entries, err := client.AuditLog.GetAuditEntries(ctx, "", "", now.Add(90*time.Minute), 0)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+
+		entries, err = client.AuditLog.GetAuditEntries(ctx, "zone", "", time.Time{}, 1)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "zone2", entries[0].ResourceID)
+	})
+}
+
+func TestKVAuditLogStorageMultipleInstances(t *testing.T) {
+	db, err := kv.NewFileDB(hashmap.Config{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	// Each instance has its own storage using the same database
+	instances := []*kvAuditLogStorage{newKVAuditLogStorage(db), newKVAuditLogStorage(db)}
+
+	var wg sync.WaitGroup
+	for _, s := range instances {
+		wg.Add(1)
+		go func(s *kvAuditLogStorage) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				assert.NoError(t, s.AddAuditEntry(ctx, pkg.AuditEntry{
+					Timestamp:    now.Add(time.Duration(i) * time.Minute),
+					ResourceType: "zone",
+					ResourceID:   "zone1",
+					Action:       "water_action",
+					Source:       pkg.AuditSourceScheduler,
+				}))
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	entries, err := instances[0].GetAuditEntries(ctx, "", "", time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Len(t, entries, 100)
+}
+
+func TestKVAuditLogStorageMax(t *testing.T) {
+	db, err := kv.NewFileDB(hashmap.Config{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+	s := newKVAuditLogStorage(db)
+
+	for i := 0; i < maxAuditEntries+5; i++ {
+		// Entries are added directly so the test does not list all keys for each one
+		require.NoError(t, setAuditEntry(db, entryKey(auditLogPrefix, now.Add(time.Duration(i)*time.Minute), xid.New().String()), pkg.AuditEntry{
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+			Action:    "created",
+		}))
+	}
+	require.NoError(t, s.AddAuditEntry(ctx, pkg.AuditEntry{Timestamp: now.Add(24 * 365 * time.Hour), Action: "updated"}))
+
+	entries, err := s.GetAuditEntries(ctx, "", "", time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, maxAuditEntries)
+	assert.Equal(t, "updated", entries[0].Action)
+	assert.Equal(t, now.Add(6*time.Minute), entries[maxAuditEntries-1].Timestamp)
+}
+
+func TestMigrateAuditLogKeys(t *testing.T) {
+	db, err := kv.NewFileDB(hashmap.Config{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	data, err := json.Marshal([]pkg.AuditEntry{
+		{Timestamp: now.Add(time.Hour), ResourceType: "zone", ResourceID: "zone1", Action: "updated"},
+		{Timestamp: now, ResourceType: "zone", ResourceID: "zone1", Action: "created"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Set("AuditLog", data))
+
+	require.NoError(t, migrateAuditLogKeys(db))
+	// Migrating again does not change anything
+	require.NoError(t, migrateAuditLogKeys(db))
+
+	_, err = db.Get("AuditLog")
+	assert.ErrorIs(t, err, hord.ErrNil)
+
+	entries, err := newKVAuditLogStorage(db).GetAuditEntries(ctx, "", "", time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "updated", entries[0].Action)
+	assert.Equal(t, "created", entries[1].Action)
+}
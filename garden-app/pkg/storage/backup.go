@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/mqttsensor"
+)
+
+// Backup has every resource in an Export plus the NotificationClients, APITokens, Users, Webhooks, and history, so
+// storage can be restored after it is lost. History is in the order it was added, starting with the oldest
+type Backup struct {
+	Export
+
+	CreatedAt           time.Time               `json:"created_at"`
+	NotificationClients []*notifications.Client `json:"notification_clients,omitempty"`
+	APITokens           []*pkg.APIToken         `json:"api_tokens,omitempty"`
+	Users               []*pkg.User             `json:"users,omitempty"`
+	Webhooks            []*pkg.Webhook          `json:"webhooks,omitempty"`
+	// WaterHistory is keyed by Zone ID
+	WaterHistory map[string][]pkg.WaterHistory `json:"water_history,omitempty"`
+	// WeatherReadings is keyed by WeatherClient ID
+	WeatherReadings map[string][]mqttsensor.Reading `json:"weather_readings,omitempty"`
+	AuditEntries    []pkg.AuditEntry                `json:"audit_entries,omitempty"`
+}
+
+// Backup reads all resources and history from storage
+func (c *Client) Backup(ctx context.Context) (*Backup, error) {
+	export, err := c.Export(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Backup{
+		Export:          *export,
+		CreatedAt:       time.Now(),
+		WaterHistory:    map[string][]pkg.WaterHistory{},
+		WeatherReadings: map[string][]mqttsensor.Reading{},
+	}
+	if c.now != nil {
+		b.CreatedAt = c.now()
+	}
+
+	b.NotificationClients, err = c.NotificationClientConfigs.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all NotificationClients: %w", err)
+	}
+
+	b.APITokens, err = c.APITokens.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all APITokens: %w", err)
+	}
+
+	b.Users, err = c.Users.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all Users: %w", err)
+	}
+
+	b.Webhooks, err = c.Webhooks.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all Webhooks: %w", err)
+	}
+
+	for _, z := range export.Zones {
+		history, err := c.WaterHistory.GetWaterHistory(ctx, z.GetID(), time.Time{}, 0)
+		if err != nil {
+			return nil, fmt.Errorf("error getting water history for Zone %q: %w", z.ID, err)
+		}
+		if len(history) == 0 {
+			continue
+		}
+		// History is returned with the most recent first, so it is reversed to be added in the same order
+		slices.Reverse(history)
+		b.WaterHistory[z.GetID()] = history
+	}
+
+	for _, wc := range export.WeatherClients {
+		readings, err := c.WeatherReadings.GetWeatherReadings(ctx, wc.GetID(), time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting weather readings for WeatherClient %q: %w", wc.ID, err)
+		}
+		if len(readings) == 0 {
+			continue
+		}
+		slices.Reverse(readings)
+		b.WeatherReadings[wc.GetID()] = readings
+	}
+
+	b.AuditEntries, err = c.AuditLog.GetAuditEntries(ctx, "", "", time.Time{}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error getting audit entries: %w", err)
+	}
+	slices.Reverse(b.AuditEntries)
+
+	return b, nil
+}
+
+// Restore validates all resources in the Backup and then saves them. Like Import, existing resources with the same
+// ID are replaced and nothing is saved if any resource is invalid. History that is already in storage is not added
+// again, so restoring the same Backup more than once does not create duplicates
+func (c *Client) Restore(ctx context.Context, b *Backup) (*MigrationSummary, error) {
+	if b == nil {
+		return nil, errors.New("missing backup data")
+	}
+
+	err := b.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Import(ctx, &b.Export)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nc := range b.NotificationClients {
+		err = c.NotificationClientConfigs.Set(ctx, nc)
+		if err != nil {
+			return nil, fmt.Errorf("error saving NotificationClient %q: %w", nc.ID, err)
+		}
+	}
+
+	for _, t := range b.APITokens {
+		err = c.APITokens.Set(ctx, t)
+		if err != nil {
+			return nil, fmt.Errorf("error saving APIToken %q: %w", t.ID, err)
+		}
+	}
+
+	for _, u := range b.Users {
+		err = c.Users.Set(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("error saving User %q: %w", u.ID, err)
+		}
+	}
+
+	for _, w := range b.Webhooks {
+		err = c.Webhooks.Set(ctx, w)
+		if err != nil {
+			return nil, fmt.Errorf("error saving Webhook %q: %w", w.ID, err)
+		}
+	}
+
+	summary := &MigrationSummary{
+		Gardens:             len(b.Gardens),
+		Zones:               len(b.Zones),
+		ZoneGroups:          len(b.ZoneGroups),
+		Plants:              len(b.Plants),
+		Reminders:           len(b.Reminders),
+		WaterSchedules:      len(b.WaterSchedules),
+		WeatherClients:      len(b.WeatherClients),
+		NotificationClients: len(b.NotificationClients),
+		APITokens:           len(b.APITokens),
+		Users:               len(b.Users),
+		Webhooks:            len(b.Webhooks),
+	}
+
+	for zoneID, history := range b.WaterHistory {
+		existing, err := c.WaterHistory.GetWaterHistory(ctx, zoneID, time.Time{}, 0)
+		if err != nil {
+			return nil, fmt.Errorf("error getting water history for Zone %q: %w", zoneID, err)
+		}
+		for _, h := range history {
+			if slices.ContainsFunc(existing, func(e pkg.WaterHistory) bool { return e.RecordTime.Equal(h.RecordTime) }) {
+				continue
+			}
+			err = c.WaterHistory.AddWaterHistory(ctx, zoneID, h)
+			if err != nil {
+				return nil, fmt.Errorf("error saving water history for Zone %q: %w", zoneID, err)
+			}
+			summary.WaterHistory++
+		}
+	}
+
+	for clientID, readings := range b.WeatherReadings {
+		existing, err := c.WeatherReadings.GetWeatherReadings(ctx, clientID, time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting weather readings for WeatherClient %q: %w", clientID, err)
+		}
+		for _, r := range readings {
+			if slices.ContainsFunc(existing, func(e mqttsensor.Reading) bool { return e.Time.Equal(r.Time) }) {
+				continue
+			}
+			err = c.WeatherReadings.AddWeatherReading(ctx, clientID, r)
+			if err != nil {
+				return nil, fmt.Errorf("error saving weather readings for WeatherClient %q: %w", clientID, err)
+			}
+			summary.WeatherReadings++
+		}
+	}
+
+	existingEntries, err := c.AuditLog.GetAuditEntries(ctx, "", "", time.Time{}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error getting audit entries: %w", err)
+	}
+	for _, entry := range b.AuditEntries {
+		if slices.ContainsFunc(existingEntries, func(e pkg.AuditEntry) bool { return sameAuditEntry(e, entry) }) {
+			continue
+		}
+		err = c.AuditLog.AddAuditEntry(ctx, entry)
+		if err != nil {
+			return nil, fmt.Errorf("error saving audit entry: %w", err)
+		}
+		summary.AuditEntries++
+	}
+
+	return summary, nil
+}
+
+// validate checks the resources that are not validated by Import. NotificationClients, Users, and Webhooks use their
+// Bind methods. APITokens cannot be replaced with PUT, so they are validated like they are when created
+func (b *Backup) validate() error {
+	r := &http.Request{Method: http.MethodPut}
+
+	for _, nc := range b.NotificationClients {
+		if nc == nil || nc.ID.IsNil() {
+			return errors.New("invalid NotificationClient: missing required field 'id'")
+		}
+		err := nc.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid NotificationClient %q: %w", nc.ID, err)
+		}
+	}
+
+	for _, t := range b.APITokens {
+		if t == nil || t.ID.IsNil() {
+			return errors.New("invalid APIToken: missing required field 'id'")
+		}
+		if t.Name == "" || t.TokenHash == "" {
+			return fmt.Errorf("invalid APIToken %q: missing required name or token hash", t.ID)
+		}
+		err := pkg.ValidateAPITokenScopes(t.Scopes)
+		if err != nil {
+			return fmt.Errorf("invalid APIToken %q: %w", t.ID, err)
+		}
+	}
+
+	for _, u := range b.Users {
+		if u == nil || u.ID.IsNil() {
+			return errors.New("invalid User: missing required field 'id'")
+		}
+		err := u.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid User %q: %w", u.ID, err)
+		}
+	}
+
+	for _, w := range b.Webhooks {
+		if w == nil || w.ID.IsNil() {
+			return errors.New("invalid Webhook: missing required field 'id'")
+		}
+		err := w.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid Webhook %q: %w", w.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// sameAuditEntry compares the fields that identify an audit entry since entries do not have IDs
+func sameAuditEntry(a, b pkg.AuditEntry) bool {
+	return a.Timestamp.Equal(b.Timestamp) &&
+		a.ResourceType == b.ResourceType &&
+		a.ResourceID == b.ResourceID &&
+		a.Action == b.Action &&
+		a.Source == b.Source
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+
+	from, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+	createExportResources(t, from)
+
+	zones, err := from.Zones.GetAll(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	zoneID := zones[0].GetID()
+
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, from.WaterHistory.AddWaterHistory(ctx, zoneID, pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Second},
+			RecordTime: now.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	b, err := from.Backup(ctx)
+	require.NoError(t, err)
+
+	// Backups are saved as JSON, so restore from the decoded data
+	data, err := json.Marshal(b)
+	require.NoError(t, err)
+	var decoded Backup
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	to, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	summary, err := to.Restore(ctx, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Gardens)
+	assert.Equal(t, 2, summary.WaterHistory)
+
+	t.Run("RestoreAgainDoesNotDuplicateHistory", func(t *testing.T) {
+		summary, err := to.Restore(ctx, &decoded)
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.Gardens)
+		assert.Equal(t, 0, summary.WaterHistory)
+
+		expectedHistory, err := from.WaterHistory.GetWaterHistory(ctx, zoneID, time.Time{}, 0)
+		require.NoError(t, err)
+		history, err := to.WaterHistory.GetWaterHistory(ctx, zoneID, time.Time{}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, expectedHistory, history)
+	})
+
+	t.Run("ErrorMissingBackup", func(t *testing.T) {
+		_, err := to.Restore(ctx, nil)
+		assert.EqualError(t, err, "missing backup data")
+	})
+}
@@ -1,10 +1,14 @@
 package storage
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage/postgres"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage/s3"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
 
 	"github.com/calvinmclean/babyapi"
@@ -15,6 +19,9 @@ import (
 	"github.com/mitchellh/mapstructure"
 )
 
+// postgresSetupTimeout limits how long connecting and running migrations can take
+const postgresSetupTimeout = time.Minute
+
 // Config is used to identify and configure a storage client
 type Config struct {
 	Driver  string                 `mapstructure:"driver"`
@@ -24,29 +31,122 @@ type Config struct {
 type Client struct {
 	Gardens                   babyapi.Storage[*pkg.Garden]
 	Zones                     babyapi.Storage[*pkg.Zone]
+	ZoneGroups                babyapi.Storage[*pkg.ZoneGroup]
+	Plants                    babyapi.Storage[*pkg.Plant]
+	Reminders                 babyapi.Storage[*pkg.Reminder]
+	Photos                    babyapi.Storage[*pkg.Photo]
+	Firmware                  babyapi.Storage[*pkg.Firmware]
 	WaterSchedules            babyapi.Storage[*pkg.WaterSchedule]
 	WeatherClientConfigs      babyapi.Storage[*weather.Config]
 	NotificationClientConfigs babyapi.Storage[*notifications.Client]
+	APITokens                 babyapi.Storage[*pkg.APIToken]
+	Users                     babyapi.Storage[*pkg.User]
+	Webhooks                  babyapi.Storage[*pkg.Webhook]
+	WaterHistory              WaterHistoryStorage
+	AuditLog                  AuditLogStorage
+	WeatherReadings           WeatherReadingStorage
+	Revisions                 RevisionStorage
+	PendingJobs               PendingJobStorage
+	ActionRecords             ActionRecordStorage
+	WebhookDeliveries         WebhookDeliveryStorage
+
+	now func() time.Time
+}
+
+// SetNow sets the function used to get the current time for WeatherClients created by this Client. By default,
+// they use the system time
+func (c *Client) SetNow(now func() time.Time) {
+	c.now = now
 }
 
 func NewClient(config Config) (*Client, error) {
+	if config.Driver == "postgres" {
+		return newPostgresClient(config)
+	}
+
 	db, err := newHordDB(config)
 	if err != nil {
 		return nil, fmt.Errorf("error creating base client: %w", err)
 	}
 
+	err = migrateWaterHistoryKeys(db)
+	if err != nil {
+		return nil, fmt.Errorf("error migrating water history: %w", err)
+	}
+
+	err = migrateAuditLogKeys(db)
+	if err != nil {
+		return nil, fmt.Errorf("error migrating audit log: %w", err)
+	}
+
 	return &Client{
 		Gardens:                   babyapi.NewKVStorage[*pkg.Garden](db, "Garden"),
 		Zones:                     babyapi.NewKVStorage[*pkg.Zone](db, "Zone"),
+		ZoneGroups:                babyapi.NewKVStorage[*pkg.ZoneGroup](db, zoneGroupKVPrefix),
+		Plants:                    babyapi.NewKVStorage[*pkg.Plant](db, "Plant"),
+		Reminders:                 babyapi.NewKVStorage[*pkg.Reminder](db, "Reminder"),
+		Photos:                    babyapi.NewKVStorage[*pkg.Photo](db, "Photo"),
+		Firmware:                  babyapi.NewKVStorage[*pkg.Firmware](db, "Firmware"),
 		WaterSchedules:            babyapi.NewKVStorage[*pkg.WaterSchedule](db, "WaterSchedule"),
 		WeatherClientConfigs:      babyapi.NewKVStorage[*weather.Config](db, "WeatherClient"),
 		NotificationClientConfigs: babyapi.NewKVStorage[*notifications.Client](db, "NotificationClient"),
+		APITokens:                 babyapi.NewKVStorage[*pkg.APIToken](db, "APIToken"),
+		Users:                     babyapi.NewKVStorage[*pkg.User](db, "User"),
+		Webhooks:                  babyapi.NewKVStorage[*pkg.Webhook](db, "Webhook"),
+		WaterHistory:              newKVWaterHistoryStorage(db),
+		AuditLog:                  newKVAuditLogStorage(db),
+		WeatherReadings:           newKVWeatherReadingStorage(db),
+		Revisions:                 newKVRevisionStorage(db),
+		PendingJobs:               newKVPendingJobStorage(db),
+		ActionRecords:             newKVActionRecordStorage(db),
+		WebhookDeliveries:         newKVWebhookDeliveryStorage(db),
+	}, nil
+}
+
+// newPostgresClient connects to PostgreSQL and runs migrations to make sure all tables exist
+func newPostgresClient(config Config) (*Client, error) {
+	var cfg postgres.Config
+	err := mapstructure.Decode(config.Options, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding config: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresSetupTimeout)
+	defer cancel()
+
+	db, err := postgres.Open(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating base client: %w", err)
+	}
+
+	return &Client{
+		Gardens:                   postgres.NewStorage[*pkg.Garden](db, "gardens"),
+		Zones:                     postgres.NewStorage[*pkg.Zone](db, "zones"),
+		ZoneGroups:                postgres.NewStorage[*pkg.ZoneGroup](db, "zone_groups"),
+		Plants:                    postgres.NewStorage[*pkg.Plant](db, "plants"),
+		Reminders:                 postgres.NewStorage[*pkg.Reminder](db, "reminders"),
+		Photos:                    postgres.NewStorage[*pkg.Photo](db, "photos"),
+		Firmware:                  postgres.NewStorage[*pkg.Firmware](db, "firmware"),
+		WaterSchedules:            postgres.NewStorage[*pkg.WaterSchedule](db, "water_schedules"),
+		WeatherClientConfigs:      postgres.NewStorage[*weather.Config](db, "weather_clients"),
+		NotificationClientConfigs: postgres.NewStorage[*notifications.Client](db, "notification_clients"),
+		APITokens:                 postgres.NewStorage[*pkg.APIToken](db, "api_tokens"),
+		Users:                     postgres.NewStorage[*pkg.User](db, "users"),
+		Webhooks:                  postgres.NewStorage[*pkg.Webhook](db, "webhooks"),
+		WaterHistory:              postgres.NewWaterHistoryStorage(db),
+		AuditLog:                  postgres.NewAuditLogStorage(db),
+		WeatherReadings:           postgres.NewWeatherReadingStorage(db),
+		Revisions:                 postgres.NewRevisionStorage(db, maxRevisions),
+		PendingJobs:               postgres.NewPendingJobStorage(db),
+		ActionRecords:             postgres.NewActionRecordStorage(db),
+		WebhookDeliveries:         postgres.NewWebhookDeliveryStorage(db),
 	}, nil
 }
 
 // newHordDB will create a new DB connection for one of the supported hord backends:
 //   - hashmap
 //   - redis
+//   - s3
 func newHordDB(config Config) (hord.Database, error) {
 	switch config.Driver {
 	case "hashmap":
@@ -63,6 +163,16 @@ func newHordDB(config Config) (hord.Database, error) {
 			return nil, fmt.Errorf("error decoding config: %w", err)
 		}
 		return kv.NewRedisDB(cfg)
+	case "s3":
+		db, err := s3.Dial(config.Options)
+		if err != nil {
+			return nil, fmt.Errorf("error creating database connection: %w", err)
+		}
+		err = db.Setup()
+		if err != nil {
+			return nil, fmt.Errorf("error setting up database: %w", err)
+		}
+		return db, nil
 	default:
 		return nil, fmt.Errorf("invalid KV driver: %q", config.Driver)
 	}
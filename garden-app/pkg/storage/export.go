@@ -0,0 +1,369 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/rs/xid"
+	"gopkg.in/yaml.v3"
+)
+
+// Export is a single document containing all Gardens, Zones, ZoneGroups, Plants, Reminders, WaterSchedules, and
+// WeatherClients. IDs are kept so the relationships between resources are the same after importing it
+type Export struct {
+	Gardens        []*pkg.Garden        `json:"gardens"`
+	Zones          []*pkg.Zone          `json:"zones"`
+	ZoneGroups     []*pkg.ZoneGroup     `json:"zone_groups,omitempty"`
+	Plants         []*pkg.Plant         `json:"plants,omitempty"`
+	Reminders      []*pkg.Reminder      `json:"reminders,omitempty"`
+	WaterSchedules []*pkg.WaterSchedule `json:"water_schedules"`
+	WeatherClients []*weather.Config    `json:"weather_clients"`
+}
+
+// Export reads all resources, including end-dated ones, from storage
+func (c *Client) Export(ctx context.Context) (*Export, error) {
+	gardens, err := c.Gardens.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all Gardens: %w", err)
+	}
+
+	zones, err := c.Zones.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all Zones: %w", err)
+	}
+
+	zoneGroups, err := c.ZoneGroups.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all ZoneGroups: %w", err)
+	}
+
+	plants, err := c.Plants.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all Plants: %w", err)
+	}
+
+	reminders, err := c.Reminders.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all Reminders: %w", err)
+	}
+
+	waterSchedules, err := c.WaterSchedules.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all WaterSchedules: %w", err)
+	}
+
+	weatherClients, err := c.WeatherClientConfigs.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all WeatherClients: %w", err)
+	}
+
+	return &Export{
+		Gardens:        gardens,
+		Zones:          zones,
+		ZoneGroups:     zoneGroups,
+		Plants:         plants,
+		Reminders:      reminders,
+		WaterSchedules: waterSchedules,
+		WeatherClients: weatherClients,
+	}, nil
+}
+
+// Import validates all resources in the Export and then saves them. Existing resources with the same ID are replaced.
+// Nothing is saved if any resource is invalid or references a resource that is not in the Export or storage
+func (c *Client) Import(ctx context.Context, e *Export) error {
+	err := c.validateImport(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	// Resources are saved before the ones that reference them
+	for _, wc := range e.WeatherClients {
+		err = c.WeatherClientConfigs.Set(ctx, wc)
+		if err != nil {
+			return fmt.Errorf("error saving WeatherClient %q: %w", wc.ID, err)
+		}
+	}
+	for _, ws := range e.WaterSchedules {
+		err = c.WaterSchedules.Set(ctx, ws)
+		if err != nil {
+			return fmt.Errorf("error saving WaterSchedule %q: %w", ws.ID, err)
+		}
+	}
+	for _, g := range e.Gardens {
+		err = c.Gardens.Set(ctx, g)
+		if err != nil {
+			return fmt.Errorf("error saving Garden %q: %w", g.ID, err)
+		}
+	}
+	for _, z := range e.Zones {
+		err = c.Zones.Set(ctx, z)
+		if err != nil {
+			return fmt.Errorf("error saving Zone %q: %w", z.ID, err)
+		}
+	}
+	for _, zg := range e.ZoneGroups {
+		err = c.ZoneGroups.Set(ctx, zg)
+		if err != nil {
+			return fmt.Errorf("error saving ZoneGroup %q: %w", zg.ID, err)
+		}
+	}
+	for _, p := range e.Plants {
+		err = c.Plants.Set(ctx, p)
+		if err != nil {
+			return fmt.Errorf("error saving Plant %q: %w", p.ID, err)
+		}
+	}
+	for _, rem := range e.Reminders {
+		err = c.Reminders.Set(ctx, rem)
+		if err != nil {
+			return fmt.Errorf("error saving Reminder %q: %w", rem.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// validateImport uses each resource's Bind method to validate it and makes sure that referenced resources exist
+func (c *Client) validateImport(ctx context.Context, e *Export) error {
+	if e == nil {
+		return errors.New("missing import data")
+	}
+
+	r := &http.Request{Method: http.MethodPut}
+
+	weatherClientIDs := map[xid.ID]bool{}
+	for _, wc := range e.WeatherClients {
+		if wc == nil || wc.ID.IsNil() {
+			return errors.New("invalid WeatherClient: missing required field 'id'")
+		}
+		err := wc.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid WeatherClient %q: %w", wc.ID, err)
+		}
+		weatherClientIDs[wc.ID.ID] = true
+	}
+
+	waterScheduleIDs := map[xid.ID]bool{}
+	for _, ws := range e.WaterSchedules {
+		if ws == nil || ws.ID.IsNil() {
+			return errors.New("invalid WaterSchedule: missing required field 'id'")
+		}
+		err := ws.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid WaterSchedule %q: %w", ws.ID, err)
+		}
+		for _, clientID := range weatherClientIDsForWaterSchedule(ws) {
+			err = checkExists(ctx, weatherClientIDs, clientID, c.WeatherClientConfigs.Get)
+			if err != nil {
+				return fmt.Errorf("invalid WaterSchedule %q: error checking WeatherClient %q: %w", ws.ID, clientID, err)
+			}
+		}
+		waterScheduleIDs[ws.ID.ID] = true
+	}
+
+	gardenIDs := map[xid.ID]bool{}
+	for _, g := range e.Gardens {
+		if g == nil || g.ID.IsNil() {
+			return errors.New("invalid Garden: missing required field 'id'")
+		}
+		err := g.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid Garden %q: %w", g.ID, err)
+		}
+		gardenIDs[g.ID.ID] = true
+	}
+
+	zoneIDs := map[xid.ID]bool{}
+	for _, z := range e.Zones {
+		if z == nil || z.ID.IsNil() {
+			return errors.New("invalid Zone: missing required field 'id'")
+		}
+		err := z.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid Zone %q: %w", z.ID, err)
+		}
+		err = checkExists(ctx, gardenIDs, z.GardenID, c.Gardens.Get)
+		if err != nil {
+			return fmt.Errorf("invalid Zone %q: error checking Garden %q: %w", z.ID, z.GardenID, err)
+		}
+		for _, wsID := range z.WaterScheduleIDs {
+			err = checkExists(ctx, waterScheduleIDs, wsID, c.WaterSchedules.Get)
+			if err != nil {
+				return fmt.Errorf("invalid Zone %q: error checking WaterSchedule %q: %w", z.ID, wsID, err)
+			}
+		}
+		zoneIDs[z.ID.ID] = true
+	}
+
+	for _, zg := range e.ZoneGroups {
+		if zg == nil || zg.ID.IsNil() {
+			return errors.New("invalid ZoneGroup: missing required field 'id'")
+		}
+		err := zg.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid ZoneGroup %q: %w", zg.ID, err)
+		}
+		err = checkExists(ctx, gardenIDs, zg.GardenID, c.Gardens.Get)
+		if err != nil {
+			return fmt.Errorf("invalid ZoneGroup %q: error checking Garden %q: %w", zg.ID, zg.GardenID, err)
+		}
+		for _, zoneID := range zg.ZoneIDs {
+			err = checkExists(ctx, zoneIDs, zoneID, c.Zones.Get)
+			if err != nil {
+				return fmt.Errorf("invalid ZoneGroup %q: error checking Zone %q: %w", zg.ID, zoneID, err)
+			}
+		}
+		for _, wsID := range zg.WaterScheduleIDs {
+			err = checkExists(ctx, waterScheduleIDs, wsID, c.WaterSchedules.Get)
+			if err != nil {
+				return fmt.Errorf("invalid ZoneGroup %q: error checking WaterSchedule %q: %w", zg.ID, wsID, err)
+			}
+		}
+	}
+
+	plantIDs := map[xid.ID]bool{}
+	for _, p := range e.Plants {
+		if p == nil || p.ID.IsNil() {
+			return errors.New("invalid Plant: missing required field 'id'")
+		}
+		err := p.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid Plant %q: %w", p.ID, err)
+		}
+		err = checkExists(ctx, gardenIDs, p.GardenID, c.Gardens.Get)
+		if err != nil {
+			return fmt.Errorf("invalid Plant %q: error checking Garden %q: %w", p.ID, p.GardenID, err)
+		}
+		err = checkExists(ctx, zoneIDs, p.ZoneID, c.Zones.Get)
+		if err != nil {
+			return fmt.Errorf("invalid Plant %q: error checking Zone %q: %w", p.ID, p.ZoneID, err)
+		}
+		plantIDs[p.ID.ID] = true
+	}
+
+	for _, rem := range e.Reminders {
+		if rem == nil || rem.ID.IsNil() {
+			return errors.New("invalid Reminder: missing required field 'id'")
+		}
+		err := rem.Bind(r)
+		if err != nil {
+			return fmt.Errorf("invalid Reminder %q: %w", rem.ID, err)
+		}
+		err = checkExists(ctx, gardenIDs, rem.GardenID, c.Gardens.Get)
+		if err != nil {
+			return fmt.Errorf("invalid Reminder %q: error checking Garden %q: %w", rem.ID, rem.GardenID, err)
+		}
+		if rem.ZoneID != nil {
+			err = checkExists(ctx, zoneIDs, *rem.ZoneID, c.Zones.Get)
+			if err != nil {
+				return fmt.Errorf("invalid Reminder %q: error checking Zone %q: %w", rem.ID, *rem.ZoneID, err)
+			}
+		}
+		if rem.PlantID != nil {
+			err = checkExists(ctx, plantIDs, *rem.PlantID, c.Plants.Get)
+			if err != nil {
+				return fmt.Errorf("invalid Reminder %q: error checking Plant %q: %w", rem.ID, *rem.PlantID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkExists returns an error if the ID is not in the imported IDs or in storage
+func checkExists[T any](ctx context.Context, imported map[xid.ID]bool, id xid.ID, get func(context.Context, string) (T, error)) error {
+	if imported[id] {
+		return nil
+	}
+	_, err := get(ctx, id.String())
+	return err
+}
+
+func weatherClientIDsForWaterSchedule(ws *pkg.WaterSchedule) []xid.ID {
+	ids := []xid.ID{}
+	if ws.HasRainControl() {
+		ids = append(ids, ws.WeatherControl.Rain.ClientID)
+	}
+	if ws.HasTemperatureControl() {
+		ids = append(ids, ws.WeatherControl.Temperature.ClientID)
+	}
+	if ws.HasRainForecastControl() {
+		ids = append(ids, ws.WeatherControl.RainForecast.ClientID)
+	}
+	if ws.HasFrostControl() {
+		ids = append(ids, ws.WeatherControl.Frost.ClientID)
+	}
+	return ids
+}
+
+// WriteExport encodes the Export as "json" or "yaml". YAML is converted from JSON so resources have the same
+// fields and formats in both
+func WriteExport(w io.Writer, e *Export, format string) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("error encoding JSON: %w", err)
+	}
+
+	switch format {
+	case "json":
+		var out bytes.Buffer
+		err = json.Indent(&out, data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error formatting JSON: %w", err)
+		}
+		out.WriteByte('\n')
+		_, err = out.WriteTo(w)
+		return err
+	case "yaml":
+		var generic any
+		err = json.Unmarshal(data, &generic)
+		if err != nil {
+			return fmt.Errorf("error decoding JSON: %w", err)
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		err = encoder.Encode(generic)
+		if err != nil {
+			return fmt.Errorf("error encoding YAML: %w", err)
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("invalid format %q", format)
+	}
+}
+
+// ReadExport decodes an Export from "json" or "yaml"
+func ReadExport(r io.Reader, format string) (*Export, error) {
+	var e Export
+	switch format {
+	case "json":
+		err := json.NewDecoder(r).Decode(&e)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding JSON: %w", err)
+		}
+	case "yaml":
+		var generic any
+		err := yaml.NewDecoder(r).Decode(&generic)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding YAML: %w", err)
+		}
+		data, err := json.Marshal(generic)
+		if err != nil {
+			return nil, fmt.Errorf("error converting YAML to JSON: %w", err)
+		}
+		err = json.Unmarshal(data, &e)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid format %q", format)
+	}
+	return &e, nil
+}
@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createExportResources(t *testing.T, client *Client) {
+	t.Helper()
+	ctx := context.Background()
+	createdAt := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	weatherClient := &weather.Config{
+		ID:      babyapi.NewID(),
+		Type:    "fake",
+		Options: map[string]interface{}{"rain_mm": 25.4},
+	}
+	require.NoError(t, client.WeatherClientConfigs.Set(ctx, weatherClient))
+
+	waterSchedule := &pkg.WaterSchedule{
+		ID:        babyapi.NewID(),
+		Duration:  &pkg.Duration{Duration: time.Minute},
+		Interval:  &pkg.Duration{Duration: 24 * time.Hour},
+		StartTime: pkg.NewStartTime(createdAt),
+		StartDate: &createdAt,
+		WeatherControl: &weather.Control{
+			Rain: &weather.ScaleControl{
+				BaselineValue: float32Pointer(0),
+				Factor:        float32Pointer(0),
+				Range:         float32Pointer(25.4),
+				ClientID:      weatherClient.ID.ID,
+			},
+		},
+	}
+	require.NoError(t, client.WaterSchedules.Set(ctx, waterSchedule))
+
+	maxZones := uint(2)
+	garden := &pkg.Garden{
+		ID:          babyapi.NewID(),
+		Name:        "garden",
+		TopicPrefix: "garden",
+		MaxZones:    &maxZones,
+		CreatedAt:   &createdAt,
+	}
+	require.NoError(t, client.Gardens.Set(ctx, garden))
+
+	position := uint(0)
+	zone := &pkg.Zone{
+		ID:               babyapi.NewID(),
+		Name:             "zone",
+		GardenID:         garden.ID.ID,
+		Position:         &position,
+		CreatedAt:        &createdAt,
+		WaterScheduleIDs: []xid.ID{waterSchedule.ID.ID},
+	}
+	require.NoError(t, client.Zones.Set(ctx, zone))
+
+	require.NoError(t, client.ZoneGroups.Set(ctx, &pkg.ZoneGroup{
+		ID:               babyapi.NewID(),
+		Name:             "zone group",
+		GardenID:         garden.ID.ID,
+		ZoneIDs:          []xid.ID{zone.ID.ID},
+		WaterScheduleIDs: []xid.ID{waterSchedule.ID.ID},
+		CreatedAt:        &createdAt,
+	}))
+
+	plant := &pkg.Plant{
+		ID:        babyapi.NewID(),
+		Name:      "plant",
+		GardenID:  garden.ID.ID,
+		ZoneID:    zone.ID.ID,
+		Species:   "Solanum lycopersicum",
+		CreatedAt: &createdAt,
+	}
+	require.NoError(t, client.Plants.Set(ctx, plant))
+
+	require.NoError(t, client.Reminders.Set(ctx, &pkg.Reminder{
+		ID:        babyapi.NewID(),
+		Type:      pkg.ReminderTypePrune,
+		GardenID:  garden.ID.ID,
+		PlantID:   &plant.ID.ID,
+		StartDate: &createdAt,
+		Interval:  &pkg.Duration{Duration: 14 * 24 * time.Hour},
+		CreatedAt: &createdAt,
+	}))
+}
+
+func float32Pointer(n float64) *float32 {
+	f := float32(n)
+	return &f
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+
+			source, err := NewClient(Config{Driver: "hashmap"})
+			require.NoError(t, err)
+			createExportResources(t, source)
+
+			export, err := source.Export(ctx)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, WriteExport(&buf, export, format))
+
+			imported, err := ReadExport(&buf, format)
+			require.NoError(t, err)
+
+			destination, err := NewClient(Config{Driver: "hashmap"})
+			require.NoError(t, err)
+			require.NoError(t, destination.Import(ctx, imported))
+
+			result, err := destination.Export(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, export, result)
+		})
+	}
+}
+
+func TestImportInvalidReferences(t *testing.T) {
+	ctx := context.Background()
+
+	source, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+	createExportResources(t, source)
+
+	t.Run("MissingGarden", func(t *testing.T) {
+		export, err := source.Export(ctx)
+		require.NoError(t, err)
+		export.Gardens = nil
+
+		destination, err := NewClient(Config{Driver: "hashmap"})
+		require.NoError(t, err)
+
+		err = destination.Import(ctx, export)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error checking Garden")
+
+		// Nothing is saved when validation fails
+		zones, err := destination.Zones.GetAll(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, zones)
+	})
+
+	t.Run("MissingZone", func(t *testing.T) {
+		export, err := source.Export(ctx)
+		require.NoError(t, err)
+		export.Zones = nil
+
+		destination, err := NewClient(Config{Driver: "hashmap"})
+		require.NoError(t, err)
+
+		err = destination.Import(ctx, export)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error checking Zone")
+	})
+
+	t.Run("MissingWeatherClient", func(t *testing.T) {
+		export, err := source.Export(ctx)
+		require.NoError(t, err)
+		export.WeatherClients = nil
+
+		destination, err := NewClient(Config{Driver: "hashmap"})
+		require.NoError(t, err)
+
+		err = destination.Import(ctx, export)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error checking WeatherClient")
+	})
+
+	t.Run("ReferenceAlreadyInStorage", func(t *testing.T) {
+		export, err := source.Export(ctx)
+		require.NoError(t, err)
+		export.Gardens = nil
+
+		// The source already has the Garden, so the Zone is valid
+		require.NoError(t, source.Import(ctx, export))
+	})
+
+	t.Run("InvalidGarden", func(t *testing.T) {
+		export, err := source.Export(ctx)
+		require.NoError(t, err)
+		export.Gardens[0].TopicPrefix = ""
+
+		err = source.Import(ctx, export)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing required topic_prefix field")
+	})
+}
+
+func TestReadExportInvalidFormat(t *testing.T) {
+	_, err := ReadExport(bytes.NewBufferString("{}"), "xml")
+	assert.EqualError(t, err, `invalid format "xml"`)
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/madflojo/hord"
+)
+
+// entryKeyTimeFormat is used in entry keys so they sort by time
+const entryKeyTimeFormat = "20060102150405.000000000"
+
+// entryKey is the key for one entry of a list that is stored with a key per entry, so multiple instances can add
+// entries without replacing each other's. Keys sort by time and the ID makes them unique
+func entryKey(prefix string, t time.Time, id string) string {
+	return fmt.Sprintf("%s%s_%s", prefix, t.UTC().Format(entryKeyTimeFormat), id)
+}
+
+// entryKeys returns the keys of the entries with the prefix, starting with the most recent
+func entryKeys(db hord.Database, prefix string) ([]string, error) {
+	keys, err := db.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("error getting keys: %w", err)
+	}
+
+	result := []string{}
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, key)
+		}
+	}
+
+	slices.Sort(result)
+	slices.Reverse(result)
+	return result, nil
+}
+
+// trimEntries deletes the oldest entries with the prefix when there are more than maxEntries
+func trimEntries(db hord.Database, prefix string, maxEntries int) error {
+	keys, err := entryKeys(db, prefix)
+	if err != nil {
+		return err
+	}
+	if len(keys) <= maxEntries {
+		return nil
+	}
+
+	for _, key := range keys[maxEntries:] {
+		err = db.Delete(key)
+		if err != nil {
+			return fmt.Errorf("error deleting old entry: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+)
+
+// MigrationSummary has the number of each type of resource copied by Migrate or saved by Restore
+type MigrationSummary struct {
+	Gardens             int `json:"gardens"`
+	Zones               int `json:"zones"`
+	ZoneGroups          int `json:"zone_groups"`
+	Plants              int `json:"plants"`
+	Reminders           int `json:"reminders"`
+	WaterSchedules      int `json:"water_schedules"`
+	WeatherClients      int `json:"weather_clients"`
+	NotificationClients int `json:"notification_clients"`
+	APITokens           int `json:"api_tokens"`
+	Users               int `json:"users"`
+	Webhooks            int `json:"webhooks"`
+	WaterHistory        int `json:"water_history"`
+	AuditEntries        int `json:"audit_entries"`
+	WeatherReadings     int `json:"weather_readings"`
+}
+
+// Migrate copies every resource from one Client to another, like when changing storage drivers. Each resource is
+// validated with its Bind method before anything is written
+func Migrate(ctx context.Context, from, to *Client) (*MigrationSummary, error) {
+	backup, err := from.Backup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return to.Restore(ctx, backup)
+}
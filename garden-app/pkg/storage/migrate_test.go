@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/mqttsensor"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	from, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+	createExportResources(t, from)
+
+	require.NoError(t, from.NotificationClientConfigs.Set(ctx, &notifications.Client{
+		ID:      babyapi.NewID(),
+		Name:    "fake",
+		Type:    "fake",
+		Options: map[string]any{},
+	}))
+
+	token := &pkg.APIToken{
+		ID:     babyapi.NewID(),
+		Name:   "dashboard",
+		Scopes: []pkg.APITokenScope{pkg.APITokenScopeRead},
+	}
+	require.NoError(t, token.GenerateToken())
+	require.NoError(t, from.APITokens.Set(ctx, token))
+
+	user := &pkg.User{
+		ID:   babyapi.NewID(),
+		Name: "Sam",
+		Role: pkg.UserRoleViewer,
+	}
+	require.NoError(t, from.Users.Set(ctx, user))
+
+	webhook := &pkg.Webhook{
+		ID:     babyapi.NewID(),
+		Name:   "alerts",
+		URL:    "https://example.com/hook",
+		Secret: "secret",
+	}
+	require.NoError(t, from.Webhooks.Set(ctx, webhook))
+
+	zones, err := from.Zones.GetAll(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	zoneID := zones[0].GetID()
+
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, from.WaterHistory.AddWaterHistory(ctx, zoneID, pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Second},
+			RecordTime: now.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	require.NoError(t, from.AuditLog.AddAuditEntry(ctx, pkg.AuditEntry{
+		Timestamp:    now,
+		ResourceType: "zone",
+		ResourceID:   zoneID,
+		Action:       "water_action",
+		Source:       pkg.AuditSourceScheduler,
+	}))
+
+	weatherClients, err := from.WeatherClientConfigs.GetAll(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, weatherClients, 1)
+	weatherClientID := weatherClients[0].GetID()
+
+	rain := float32(1.5)
+	require.NoError(t, from.WeatherReadings.AddWeatherReading(ctx, weatherClientID, mqttsensor.Reading{
+		Time:   now,
+		RainMM: &rain,
+	}))
+
+	to, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	summary, err := Migrate(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, &MigrationSummary{
+		Gardens:             1,
+		Zones:               1,
+		ZoneGroups:          1,
+		Plants:              1,
+		Reminders:           1,
+		WaterSchedules:      1,
+		WeatherClients:      1,
+		NotificationClients: 1,
+		APITokens:           1,
+		Users:               1,
+		Webhooks:            1,
+		WaterHistory:        3,
+		AuditEntries:        1,
+		WeatherReadings:     1,
+	}, summary)
+
+	migratedToken, err := to.APITokens.Get(ctx, token.GetID())
+	require.NoError(t, err)
+	assert.Equal(t, token.TokenHash, migratedToken.TokenHash)
+
+	migratedUser, err := to.Users.Get(ctx, user.GetID())
+	require.NoError(t, err)
+	assert.Equal(t, user, migratedUser)
+
+	migratedWebhook, err := to.Webhooks.Get(ctx, webhook.GetID())
+	require.NoError(t, err)
+	assert.Equal(t, webhook, migratedWebhook)
+
+	expectedHistory, err := from.WaterHistory.GetWaterHistory(ctx, zoneID, time.Time{}, 0)
+	require.NoError(t, err)
+	history, err := to.WaterHistory.GetWaterHistory(ctx, zoneID, time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, expectedHistory, history)
+
+	auditEntries, err := to.AuditLog.GetAuditEntries(ctx, "zone", zoneID, time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Len(t, auditEntries, 1)
+
+	readings, err := to.WeatherReadings.GetWeatherReadings(ctx, weatherClientID, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+	assert.Equal(t, rain, *readings[0].RainMM)
+}
+
+func TestMigrateInvalidResource(t *testing.T) {
+	ctx := context.Background()
+
+	from, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+	createExportResources(t, from)
+
+	require.NoError(t, from.NotificationClientConfigs.Set(ctx, &notifications.Client{
+		ID:   babyapi.NewID(),
+		Type: "fake",
+	}))
+
+	to, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	_, err = Migrate(ctx, from, to)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required name field")
+
+	// Nothing is written when a resource is invalid
+	gardens, err := to.Gardens.GetAll(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, gardens)
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/madflojo/hord"
+)
+
+const pendingJobsKey = "PendingJobs"
+
+// PendingJobStorage keeps the Worker's one-time Jobs until they run so they are not lost when the server restarts
+type PendingJobStorage interface {
+	// SetPendingJob creates or replaces the PendingJob with the same ID
+	SetPendingJob(ctx context.Context, job pkg.PendingJob) error
+	// DeletePendingJob removes the PendingJob. It does not return an error if it doesn't exist
+	DeletePendingJob(ctx context.Context, id string) error
+	// GetPendingJobs returns all PendingJobs, starting with the one that runs first
+	GetPendingJobs(ctx context.Context) ([]pkg.PendingJob, error)
+}
+
+// kvPendingJobStorage stores all PendingJobs as a single JSON object in a hord.Database, by ID
+type kvPendingJobStorage struct {
+	db hord.Database
+	mu sync.Mutex
+}
+
+func newKVPendingJobStorage(db hord.Database) *kvPendingJobStorage {
+	return &kvPendingJobStorage{db: db}
+}
+
+// SetPendingJob adds the PendingJob to the stored object
+func (s *kvPendingJobStorage) SetPendingJob(_ context.Context, job pkg.PendingJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.get()
+	if err != nil {
+		return err
+	}
+
+	all[job.ID] = job
+	return s.set(all)
+}
+
+// DeletePendingJob removes the PendingJob from the stored object
+func (s *kvPendingJobStorage) DeletePendingJob(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.get()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := all[id]; !ok {
+		return nil
+	}
+
+	delete(all, id)
+	return s.set(all)
+}
+
+// GetPendingJobs reads the stored object and sorts the PendingJobs by RunAt
+func (s *kvPendingJobStorage) GetPendingJobs(_ context.Context) ([]pkg.PendingJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]pkg.PendingJob, 0, len(all))
+	for _, job := range all {
+		result = append(result, job)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].RunAt.Equal(result[j].RunAt) {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].RunAt.Before(result[j].RunAt)
+	})
+
+	return result, nil
+}
+
+func (s *kvPendingJobStorage) get() (map[string]pkg.PendingJob, error) {
+	data, err := s.db.Get(pendingJobsKey)
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return map[string]pkg.PendingJob{}, nil
+		}
+		return nil, fmt.Errorf("error getting pending jobs: %w", err)
+	}
+
+	result := map[string]pkg.PendingJob{}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing pending jobs: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *kvPendingJobStorage) set(all map[string]pkg.PendingJob) error {
+	data, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("error marshalling pending jobs: %w", err)
+	}
+
+	err = s.db.Set(pendingJobsKey, data)
+	if err != nil {
+		return fmt.Errorf("error writing pending jobs: %w", err)
+	}
+
+	return nil
+}
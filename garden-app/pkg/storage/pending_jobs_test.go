@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVPendingJobStorage(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	jobs, err := client.PendingJobs.GetPendingJobs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	require.NoError(t, client.PendingJobs.SetPendingJob(ctx, pkg.PendingJob{
+		ID:    "cycle",
+		Type:  pkg.PendingJobWaterCycle,
+		RunAt: now.Add(2 * time.Hour),
+		Duration: &pkg.Duration{
+			Duration: time.Minute,
+		},
+	}))
+	require.NoError(t, client.PendingJobs.SetPendingJob(ctx, pkg.PendingJob{
+		ID:              "deferred",
+		Type:            pkg.PendingJobDeferredWatering,
+		WaterScheduleID: "ws",
+		RunAt:           now.Add(3 * time.Hour),
+	}))
+
+	t.Run("SortedByRunAt", func(t *testing.T) {
+		jobs, err := client.PendingJobs.GetPendingJobs(ctx)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		assert.Equal(t, "cycle", jobs[0].ID)
+		assert.Equal(t, time.Minute, jobs[0].Duration.Duration)
+		assert.Equal(t, "deferred", jobs[1].ID)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		require.NoError(t, client.PendingJobs.SetPendingJob(ctx, pkg.PendingJob{
+			ID:              "deferred",
+			Type:            pkg.PendingJobDeferredWatering,
+			WaterScheduleID: "ws",
+			RunAt:           now.Add(time.Hour),
+		}))
+
+		jobs, err := client.PendingJobs.GetPendingJobs(ctx)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		assert.Equal(t, "deferred", jobs[0].ID)
+		assert.True(t, now.Add(time.Hour).Equal(jobs[0].RunAt))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, client.PendingJobs.DeletePendingJob(ctx, "deferred"))
+		require.NoError(t, client.PendingJobs.DeletePendingJob(ctx, "missing"))
+
+		jobs, err := client.PendingJobs.GetPendingJobs(ctx)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "cycle", jobs[0].ID)
+	})
+}
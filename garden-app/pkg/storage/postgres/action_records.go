@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+)
+
+// ActionRecordStorage stores each ActionRecord as a row in the action_records table
+type ActionRecordStorage struct {
+	db *sql.DB
+}
+
+// NewActionRecordStorage creates an ActionRecordStorage using a database that has been migrated
+func NewActionRecordStorage(db *sql.DB) *ActionRecordStorage {
+	return &ActionRecordStorage{db}
+}
+
+// SetActionRecord creates or replaces the ActionRecord with the same ID
+func (s *ActionRecordStorage) SetActionRecord(ctx context.Context, record pkg.ActionRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error marshalling action record: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO action_records (id, zone_id, created_at, data) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data`,
+		record.ID, record.ZoneID, record.CreatedAt, data,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing action record: %w", err)
+	}
+
+	return nil
+}
+
+// GetActionRecord returns the ActionRecord with the ID, or babyapi.ErrNotFound if it does not exist
+func (s *ActionRecordStorage) GetActionRecord(ctx context.Context, id string) (*pkg.ActionRecord, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, "SELECT data FROM action_records WHERE id = $1", id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, babyapi.ErrNotFound
+		}
+		return nil, fmt.Errorf("error getting action record: %w", err)
+	}
+
+	var result pkg.ActionRecord
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing action record: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetZoneActionRecords returns the Zone's ActionRecords, starting with the most recent. A limit of 0 returns all
+// records
+func (s *ActionRecordStorage) GetZoneActionRecords(ctx context.Context, zoneID string, limit uint64) ([]pkg.ActionRecord, error) {
+	q := "SELECT data FROM action_records WHERE zone_id = $1 ORDER BY created_at DESC, id DESC"
+	args := []any{zoneID}
+	if limit > 0 {
+		q += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting action records: %w", err)
+	}
+	defer rows.Close()
+
+	result := []pkg.ActionRecord{}
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning action record: %w", err)
+		}
+
+		var record pkg.ActionRecord
+		err = json.Unmarshal(data, &record)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing action record: %w", err)
+		}
+		result = append(result, record)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error getting action records: %w", rows.Err())
+	}
+
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+)
+
+// AuditLogStorage stores each audit entry as a row in the audit_log table
+type AuditLogStorage struct {
+	db *sql.DB
+}
+
+// NewAuditLogStorage creates an AuditLogStorage using a database that has been migrated
+func NewAuditLogStorage(db *sql.DB) *AuditLogStorage {
+	return &AuditLogStorage{db}
+}
+
+// AddAuditEntry inserts a new row for the entry
+func (s *AuditLogStorage) AddAuditEntry(ctx context.Context, entry pkg.AuditEntry) error {
+	var details []byte
+	if len(entry.Details) > 0 {
+		var err error
+		details, err = json.Marshal(entry.Details)
+		if err != nil {
+			return fmt.Errorf("error marshalling details: %w", err)
+		}
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO audit_log (record_time, resource_type, resource_id, action, source, actor, remote_addr, details)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		entry.Timestamp, entry.ResourceType, entry.ResourceID, entry.Action, entry.Source, entry.Actor, entry.RemoteAddr, details,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditEntries returns entries recorded after since, starting with the most recent. Empty resourceType or
+// resourceID will not filter by that field and a limit of 0 returns all entries
+func (s *AuditLogStorage) GetAuditEntries(ctx context.Context, resourceType, resourceID string, since time.Time, limit uint64) ([]pkg.AuditEntry, error) {
+	q := `SELECT record_time, resource_type, resource_id, action, source, actor, remote_addr, details FROM audit_log
+		WHERE ($1 = '' OR resource_type = $1) AND ($2 = '' OR resource_id = $2) AND record_time >= $3
+		ORDER BY record_time DESC, id DESC`
+	args := []any{resourceType, resourceID, since}
+	if limit > 0 {
+		q += " LIMIT $4"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	result := []pkg.AuditEntry{}
+	for rows.Next() {
+		var entry pkg.AuditEntry
+		var details []byte
+		err = rows.Scan(
+			&entry.Timestamp, &entry.ResourceType, &entry.ResourceID, &entry.Action,
+			&entry.Source, &entry.Actor, &entry.RemoteAddr, &details,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning audit entry: %w", err)
+		}
+
+		if len(details) > 0 {
+			err = json.Unmarshal(details, &entry.Details)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing audit entry details: %w", err)
+			}
+		}
+		result = append(result, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error getting audit entries: %w", rows.Err())
+	}
+
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationsLockID is used with pg_advisory_lock so multiple garden-app instances starting at the same time
+// do not run migrations concurrently
+const migrationsLockID = 7_355_608
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// loadMigrations reads all embedded migrations and sorts them by version. Files must be named like
+// "0001_description.sql"
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	migrations := []migration{}
+	seen := map[int]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+
+		versionStr, _, found := strings.Cut(name, "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration name %q: expected format NNNN_description.sql", name)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration name %q: %w", name, err)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %q and %q have the same version", other, name)
+		}
+		seen[version] = name
+
+		data, err := migrationsFS.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %q: %w", name, err)
+		}
+
+		migrations = append(migrations, migration{version, name, string(data)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// Migrate applies all embedded migrations that are not recorded in the schema_migrations table. Each migration
+// runs in its own transaction
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	// advisory locks are held by a session, so the same connection has to be used for everything
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID)
+	if err != nil {
+		return fmt.Errorf("error acquiring migrations lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockID) //nolint:errcheck
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %w", err)
+	}
+
+	applied := map[int]bool{}
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return fmt.Errorf("error getting applied migrations: %w", err)
+	}
+	for rows.Next() {
+		var version int
+		err = rows.Scan(&version)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return fmt.Errorf("error getting applied migrations: %w", rows.Err())
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		err = applyMigration(ctx, conn, m)
+		if err != nil {
+			return fmt.Errorf("error applying migration %q: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, m migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = tx.ExecContext(ctx, m.sql)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.version, m.name)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,44 @@
+-- Each resource is stored as JSON in the data column. end_date is copied from the resource so end-dated
+-- resources can be filtered without decoding everything
+
+CREATE TABLE gardens (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE zones (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX zones_garden_id_idx ON zones ((data->>'garden_id'));
+
+CREATE TABLE water_schedules (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE weather_clients (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE notification_clients (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
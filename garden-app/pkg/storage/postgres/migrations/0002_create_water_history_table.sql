@@ -0,0 +1,10 @@
+-- Water events are recorded for each Zone so history is available without InfluxDB
+
+CREATE TABLE water_history (
+	id BIGSERIAL PRIMARY KEY,
+	zone_id TEXT NOT NULL,
+	duration_ms BIGINT NOT NULL,
+	record_time TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX water_history_zone_id_record_time_idx ON water_history (zone_id, record_time DESC);
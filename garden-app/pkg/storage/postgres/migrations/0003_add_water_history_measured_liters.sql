@@ -0,0 +1,3 @@
+-- Zones with a flow meter report the volume of water delivered during each water event
+
+ALTER TABLE water_history ADD COLUMN measured_liters DOUBLE PRECISION;
@@ -0,0 +1,9 @@
+-- APITokens created using the /tokens API. Only a hash of each token is stored
+
+CREATE TABLE api_tokens (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
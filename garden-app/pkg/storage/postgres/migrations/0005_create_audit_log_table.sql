@@ -0,0 +1,16 @@
+-- Executed actions and resource changes are recorded in an append-only audit log
+
+CREATE TABLE audit_log (
+	id BIGSERIAL PRIMARY KEY,
+	record_time TIMESTAMPTZ NOT NULL,
+	resource_type TEXT NOT NULL,
+	resource_id TEXT NOT NULL,
+	action TEXT NOT NULL,
+	source TEXT NOT NULL,
+	actor TEXT NOT NULL DEFAULT '',
+	remote_addr TEXT NOT NULL DEFAULT '',
+	details JSONB
+);
+
+CREATE INDEX audit_log_resource_record_time_idx ON audit_log (resource_type, resource_id, record_time DESC);
+CREATE INDEX audit_log_record_time_idx ON audit_log (record_time DESC);
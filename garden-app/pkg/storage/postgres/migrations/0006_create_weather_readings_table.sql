@@ -0,0 +1,11 @@
+-- Readings published by local sensors for mqtt_sensor WeatherClients
+
+CREATE TABLE weather_readings (
+	id BIGSERIAL PRIMARY KEY,
+	client_id TEXT NOT NULL,
+	record_time TIMESTAMPTZ NOT NULL,
+	rain_mm REAL,
+	temperature REAL
+);
+
+CREATE INDEX weather_readings_client_id_record_time_idx ON weather_readings (client_id, record_time DESC);
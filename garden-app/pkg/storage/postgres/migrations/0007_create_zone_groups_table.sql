@@ -0,0 +1,9 @@
+-- ZoneGroups combine a Garden's Zones so they share WaterSchedules and actions
+
+CREATE TABLE zone_groups (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,9 @@
+-- Plants track care details for the Plants growing in a Zone
+
+CREATE TABLE plants (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
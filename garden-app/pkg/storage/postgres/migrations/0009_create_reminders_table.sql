@@ -0,0 +1,9 @@
+-- Reminders send notifications for Zone and Plant care like pruning and fertilizing
+
+CREATE TABLE reminders (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,9 @@
+-- Photos hold the details of pictures uploaded for Zones and Plants. The contents are saved by the photo storage
+
+CREATE TABLE photos (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,10 @@
+-- Previous versions of resources are saved before each update so the update can be rolled back
+
+CREATE TABLE revisions (
+	resource_type TEXT NOT NULL,
+	resource_id TEXT NOT NULL,
+	number INTEGER NOT NULL,
+	record_time TIMESTAMPTZ NOT NULL,
+	data JSONB NOT NULL,
+	PRIMARY KEY (resource_type, resource_id, number)
+);
@@ -0,0 +1,7 @@
+-- One-time Jobs scheduled by the Worker are saved until they run so they can be scheduled again after a restart
+
+CREATE TABLE pending_jobs (
+	id TEXT PRIMARY KEY,
+	run_at TIMESTAMPTZ NOT NULL,
+	data JSONB NOT NULL
+);
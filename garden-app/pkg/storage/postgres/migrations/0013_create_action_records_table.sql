@@ -0,0 +1,10 @@
+-- The status of actions sent to controllers is recorded so each request can be followed until it is complete
+
+CREATE TABLE action_records (
+	id TEXT PRIMARY KEY,
+	zone_id TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	data JSONB NOT NULL
+);
+
+CREATE INDEX action_records_zone_id_created_at_idx ON action_records (zone_id, created_at DESC);
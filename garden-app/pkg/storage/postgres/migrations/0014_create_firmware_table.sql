@@ -0,0 +1,10 @@
+-- Firmware holds the details of garden-controller binaries uploaded for OTA updates. The contents are saved by the
+-- firmware storage
+
+CREATE TABLE firmware (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
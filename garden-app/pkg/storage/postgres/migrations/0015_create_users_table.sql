@@ -0,0 +1,9 @@
+-- Users created using the /users API. Gardens give Users roles by ID
+
+CREATE TABLE users (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
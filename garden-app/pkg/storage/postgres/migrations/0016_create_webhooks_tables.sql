@@ -0,0 +1,18 @@
+-- Webhooks created using the /webhooks API and the result of sending each Event to them
+
+CREATE TABLE webhooks (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL,
+	end_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhook_deliveries (
+	id TEXT PRIMARY KEY,
+	webhook_id TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	data JSONB NOT NULL
+);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries (webhook_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- Waterings started at the controller, like with a button, are recorded with the measured volume when they finish
+
+ALTER TABLE water_history ADD COLUMN manual BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+)
+
+// PendingJobStorage stores each PendingJob as a row in the pending_jobs table
+type PendingJobStorage struct {
+	db *sql.DB
+}
+
+// NewPendingJobStorage creates a PendingJobStorage using a database that has been migrated
+func NewPendingJobStorage(db *sql.DB) *PendingJobStorage {
+	return &PendingJobStorage{db}
+}
+
+// SetPendingJob creates or replaces the PendingJob with the same ID
+func (s *PendingJobStorage) SetPendingJob(ctx context.Context, job pkg.PendingJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("error marshalling pending job: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO pending_jobs (id, run_at, data) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET run_at = EXCLUDED.run_at, data = EXCLUDED.data`,
+		job.ID, job.RunAt, data,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing pending job: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePendingJob removes the PendingJob's row
+func (s *PendingJobStorage) DeletePendingJob(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM pending_jobs WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("error deleting pending job: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingJobs returns all PendingJobs, starting with the one that runs first
+func (s *PendingJobStorage) GetPendingJobs(ctx context.Context) ([]pkg.PendingJob, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT data FROM pending_jobs ORDER BY run_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("error getting pending jobs: %w", err)
+	}
+	defer rows.Close()
+
+	result := []pkg.PendingJob{}
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning pending job: %w", err)
+		}
+
+		var job pkg.PendingJob
+		err = json.Unmarshal(data, &job)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing pending job: %w", err)
+		}
+		result = append(result, job)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error getting pending jobs: %w", rows.Err())
+	}
+
+	return result, nil
+}
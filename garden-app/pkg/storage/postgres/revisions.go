@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+)
+
+// RevisionStorage stores each Revision as a row in the revisions table
+type RevisionStorage struct {
+	db           *sql.DB
+	maxRevisions int
+}
+
+// NewRevisionStorage creates a RevisionStorage using a database that has been migrated. Only the newest
+// maxRevisions are kept for each resource
+func NewRevisionStorage(db *sql.DB, maxRevisions int) *RevisionStorage {
+	return &RevisionStorage{db, maxRevisions}
+}
+
+// AddRevision inserts a row using the next number for the resource and removes the oldest rows when there are more
+// than maxRevisions
+func (s *RevisionStorage) AddRevision(ctx context.Context, resourceType, resourceID string, recordTime time.Time, data []byte) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO revisions (resource_type, resource_id, number, record_time, data)
+		SELECT $1, $2, COALESCE(MAX(number), 0) + 1, $3, $4 FROM revisions WHERE resource_type = $1 AND resource_id = $2`,
+		resourceType, resourceID, recordTime, data,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing revision: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM revisions WHERE resource_type = $1 AND resource_id = $2 AND number NOT IN (
+			SELECT number FROM revisions WHERE resource_type = $1 AND resource_id = $2 ORDER BY number DESC LIMIT $3
+		)`,
+		resourceType, resourceID, s.maxRevisions,
+	)
+	if err != nil {
+		return fmt.Errorf("error removing old revisions: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisions returns the resource's Revisions, starting with the most recent
+func (s *RevisionStorage) GetRevisions(ctx context.Context, resourceType, resourceID string) ([]pkg.Revision, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT number, record_time, data FROM revisions WHERE resource_type = $1 AND resource_id = $2 ORDER BY number DESC",
+		resourceType, resourceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting revisions: %w", err)
+	}
+	defer rows.Close()
+
+	result := []pkg.Revision{}
+	for rows.Next() {
+		var revision pkg.Revision
+		err = rows.Scan(&revision.Number, &revision.RecordTime, &revision.Data)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning revision: %w", err)
+		}
+		result = append(result, revision)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error getting revisions: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// GetRevision returns the resource's Revision with the number, or babyapi.ErrNotFound if it does not exist
+func (s *RevisionStorage) GetRevision(ctx context.Context, resourceType, resourceID string, number uint) (*pkg.Revision, error) {
+	var revision pkg.Revision
+	err := s.db.QueryRowContext(ctx,
+		"SELECT number, record_time, data FROM revisions WHERE resource_type = $1 AND resource_id = $2 AND number = $3",
+		resourceType, resourceID, number,
+	).Scan(&revision.Number, &revision.RecordTime, &revision.Data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, babyapi.ErrNotFound
+		}
+		return nil, fmt.Errorf("error getting revision: %w", err)
+	}
+
+	return &revision, nil
+}
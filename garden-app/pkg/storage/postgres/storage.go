@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+)
+
+// Storage implements babyapi.Storage using a table with the same columns as the ones created by migrations.
+// It behaves like babyapi's KVStorage: Delete will end-date resources before actually deleting them and GetAll
+// excludes end-dated resources unless the "end_dated=true" query parameter is used
+type Storage[T babyapi.Resource] struct {
+	db    *sql.DB
+	table string
+}
+
+// NewStorage creates a Storage for the table. The table name must be trusted since it is used directly in queries
+func NewStorage[T babyapi.Resource](db *sql.DB, table string) babyapi.Storage[T] {
+	return &Storage[T]{db, table}
+}
+
+// Get reads a single resource by ID
+func (s *Storage[T]) Get(ctx context.Context, id string) (T, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf("SELECT data FROM %s WHERE id = $1", s.table), id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return *new(T), babyapi.ErrNotFound
+		}
+		return *new(T), fmt.Errorf("error getting data: %w", err)
+	}
+
+	return s.parse(data)
+}
+
+// GetAll reads all resources from the table
+func (s *Storage[T]) GetAll(ctx context.Context, query url.Values) ([]T, error) {
+	q := fmt.Sprintf("SELECT data FROM %s", s.table)
+	if query.Get("end_dated") != "true" {
+		q += " WHERE end_date IS NULL OR end_date > now()"
+	}
+	q += " ORDER BY created_at"
+
+	rows, err := s.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error getting data: %w", err)
+	}
+	defer rows.Close()
+
+	results := []T{}
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning data: %w", err)
+		}
+
+		result, err := s.parse(data)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error getting data: %w", rows.Err())
+	}
+
+	return results, nil
+}
+
+// Set creates or replaces the resource
+func (s *Storage[T]) Set(ctx context.Context, item T) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("error marshalling data: %w", err)
+	}
+
+	// end_date is read from the JSON so this works for any resource with an "end_date" field
+	var endDate struct {
+		EndDate *time.Time `json:"end_date"`
+	}
+	err = json.Unmarshal(data, &endDate)
+	if err != nil {
+		return fmt.Errorf("error parsing end_date: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (id, data, end_date) VALUES ($1, $2, $3)
+ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, end_date = EXCLUDED.end_date, updated_at = now()`, s.table),
+		item.GetID(), data, endDate.EndDate,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing data to database: %w", err)
+	}
+
+	return nil
+}
+
+// Delete will end-date the resource if it implements babyapi.EndDateable and is not already end-dated. Otherwise,
+// it is deleted from the table
+func (s *Storage[T]) Delete(ctx context.Context, id string) error {
+	result, err := s.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error getting resource before deleting: %w", err)
+	}
+
+	endDateable, ok := any(result).(babyapi.EndDateable)
+	if ok && !endDateable.EndDated() {
+		endDateable.SetEndDate(time.Now())
+		return s.Set(ctx, result)
+	}
+
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", s.table), id)
+	if err != nil {
+		return fmt.Errorf("error deleting data: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage[T]) parse(data []byte) (T, error) {
+	var result T
+	err := json.Unmarshal(data, &result)
+	if err != nil {
+		return *new(T), fmt.Errorf("error parsing data: %w", err)
+	}
+	return result, nil
+}
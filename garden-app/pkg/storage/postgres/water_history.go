@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+)
+
+// WaterHistoryStorage stores each water event as a row in the water_history table
+type WaterHistoryStorage struct {
+	db *sql.DB
+}
+
+// NewWaterHistoryStorage creates a WaterHistoryStorage using a database that has been migrated
+func NewWaterHistoryStorage(db *sql.DB) *WaterHistoryStorage {
+	return &WaterHistoryStorage{db}
+}
+
+// AddWaterHistory records a water event for the Zone. Manual waterings already include the measured volume
+func (s *WaterHistoryStorage) AddWaterHistory(ctx context.Context, zoneID string, history pkg.WaterHistory) error {
+	if history.Duration == nil {
+		return errors.New("missing required duration")
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO water_history (zone_id, duration_ms, record_time, measured_liters, manual) VALUES ($1, $2, $3, $4, $5)",
+		zoneID, history.Duration.Milliseconds(), history.RecordTime, history.MeasuredLiters, history.Manual,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing water history: %w", err)
+	}
+
+	return nil
+}
+
+// GetWaterHistory returns the Zone's water events recorded after since, starting with the most recent. A limit
+// of 0 returns all events
+func (s *WaterHistoryStorage) GetWaterHistory(ctx context.Context, zoneID string, since time.Time, limit uint64) ([]pkg.WaterHistory, error) {
+	q := "SELECT duration_ms, record_time, measured_liters, manual FROM water_history WHERE zone_id = $1 AND record_time >= $2 ORDER BY record_time DESC"
+	args := []any{zoneID, since}
+	if limit > 0 {
+		q += " LIMIT $3"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting water history: %w", err)
+	}
+	defer rows.Close()
+
+	result := []pkg.WaterHistory{}
+	for rows.Next() {
+		var durationMS int64
+		var recordTime time.Time
+		var measuredLiters sql.NullFloat64
+		var manual bool
+		err = rows.Scan(&durationMS, &recordTime, &measuredLiters, &manual)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning water history: %w", err)
+		}
+
+		history := pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Duration(durationMS) * time.Millisecond},
+			RecordTime: recordTime,
+			Manual:     manual,
+		}
+		if measuredLiters.Valid {
+			history.MeasuredLiters = &measuredLiters.Float64
+		}
+		result = append(result, history)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error getting water history: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// SetMeasuredLiters records the volume measured by a flow meter on the Zone's most recent water event that does
+// not have a measurement yet and is not manual
+func (s *WaterHistoryStorage) SetMeasuredLiters(ctx context.Context, zoneID string, liters float64) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE water_history SET measured_liters = $2 WHERE id = (
+			SELECT id FROM water_history WHERE zone_id = $1 AND measured_liters IS NULL AND NOT manual ORDER BY record_time DESC LIMIT 1
+		)`,
+		zoneID, liters,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing measured liters: %w", err)
+	}
+
+	return nil
+}
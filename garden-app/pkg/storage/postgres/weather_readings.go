@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/mqttsensor"
+)
+
+// WeatherReadingStorage stores each sensor reading as a row in the weather_readings table
+type WeatherReadingStorage struct {
+	db *sql.DB
+}
+
+// NewWeatherReadingStorage creates a WeatherReadingStorage using a database that has been migrated
+func NewWeatherReadingStorage(db *sql.DB) *WeatherReadingStorage {
+	return &WeatherReadingStorage{db}
+}
+
+// AddWeatherReading records a reading for the WeatherClient
+func (s *WeatherReadingStorage) AddWeatherReading(ctx context.Context, clientID string, reading mqttsensor.Reading) error {
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO weather_readings (client_id, record_time, rain_mm, temperature) VALUES ($1, $2, $3, $4)",
+		clientID, reading.Time, reading.RainMM, reading.Temperature,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing weather reading: %w", err)
+	}
+
+	return nil
+}
+
+// GetWeatherReadings returns the WeatherClient's readings recorded after since, starting with the most recent
+func (s *WeatherReadingStorage) GetWeatherReadings(ctx context.Context, clientID string, since time.Time) ([]mqttsensor.Reading, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT record_time, rain_mm, temperature FROM weather_readings WHERE client_id = $1 AND record_time >= $2 ORDER BY record_time DESC",
+		clientID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting weather readings: %w", err)
+	}
+	defer rows.Close()
+
+	result := []mqttsensor.Reading{}
+	for rows.Next() {
+		var reading mqttsensor.Reading
+		var rainMM, temperature sql.NullFloat64
+		err = rows.Scan(&reading.Time, &rainMM, &temperature)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning weather reading: %w", err)
+		}
+
+		if rainMM.Valid {
+			f := float32(rainMM.Float64)
+			reading.RainMM = &f
+		}
+		if temperature.Valid {
+			f := float32(temperature.Float64)
+			reading.Temperature = &f
+		}
+		result = append(result, reading)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error getting weather readings: %w", rows.Err())
+	}
+
+	return result, nil
+}
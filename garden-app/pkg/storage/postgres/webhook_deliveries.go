@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+)
+
+// WebhookDeliveryStorage stores each WebhookDelivery as a row in the webhook_deliveries table
+type WebhookDeliveryStorage struct {
+	db *sql.DB
+}
+
+// NewWebhookDeliveryStorage creates a WebhookDeliveryStorage using a database that has been migrated
+func NewWebhookDeliveryStorage(db *sql.DB) *WebhookDeliveryStorage {
+	return &WebhookDeliveryStorage{db}
+}
+
+// SetWebhookDelivery creates or replaces the WebhookDelivery with the same ID
+func (s *WebhookDeliveryStorage) SetWebhookDelivery(ctx context.Context, delivery pkg.WebhookDelivery) error {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("error marshalling webhook delivery: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (id, webhook_id, created_at, data) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data`,
+		delivery.ID, delivery.WebhookID, delivery.CreatedAt, data,
+	)
+	if err != nil {
+		return fmt.Errorf("error writing webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries returns the Webhook's deliveries, starting with the most recent. A limit of 0 returns all
+// deliveries
+func (s *WebhookDeliveryStorage) GetWebhookDeliveries(ctx context.Context, webhookID string, limit uint64) ([]pkg.WebhookDelivery, error) {
+	q := "SELECT data FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC, id DESC"
+	args := []any{webhookID}
+	if limit > 0 {
+		q += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	result := []pkg.WebhookDelivery{}
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", err)
+		}
+
+		var delivery pkg.WebhookDelivery
+		err = json.Unmarshal(data, &delivery)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing webhook delivery: %w", err)
+		}
+		result = append(result, delivery)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error getting webhook deliveries: %w", rows.Err())
+	}
+
+	return result, nil
+}
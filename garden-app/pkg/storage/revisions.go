@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/madflojo/hord"
+)
+
+// maxRevisions is the number of Revisions kept for each resource. Older Revisions are removed when new ones are
+// added
+const maxRevisions = 20
+
+// RevisionStorage keeps previous versions of resources so updates can be rolled back
+type RevisionStorage interface {
+	// AddRevision saves the resource's JSON data as its newest Revision
+	AddRevision(ctx context.Context, resourceType, resourceID string, recordTime time.Time, data []byte) error
+	// GetRevisions returns the resource's Revisions, starting with the most recent
+	GetRevisions(ctx context.Context, resourceType, resourceID string) ([]pkg.Revision, error)
+	// GetRevision returns the resource's Revision with the number, or babyapi.ErrNotFound if it does not exist
+	GetRevision(ctx context.Context, resourceType, resourceID string, number uint) (*pkg.Revision, error)
+}
+
+// kvRevisionStorage stores each resource's Revisions as a single JSON list in a hord.Database
+type kvRevisionStorage struct {
+	db hord.Database
+	mu sync.Mutex
+}
+
+func newKVRevisionStorage(db hord.Database) *kvRevisionStorage {
+	return &kvRevisionStorage{db: db}
+}
+
+func revisionsKey(resourceType, resourceID string) string {
+	return "Revisions_" + resourceType + "_" + resourceID
+}
+
+// AddRevision adds the Revision to the beginning of the resource's list and removes the oldest Revisions when
+// there are more than maxRevisions
+func (s *kvRevisionStorage) AddRevision(_ context.Context, resourceType, resourceID string, recordTime time.Time, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.get(resourceType, resourceID)
+	if err != nil {
+		return err
+	}
+
+	number := uint(1)
+	if len(all) > 0 {
+		number = all[0].Number + 1
+	}
+
+	all = append([]pkg.Revision{{
+		Number:     number,
+		RecordTime: recordTime,
+		Data:       data,
+	}}, all...)
+	if len(all) > maxRevisions {
+		all = all[:maxRevisions]
+	}
+
+	result, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("error marshalling revisions: %w", err)
+	}
+
+	err = s.db.Set(revisionsKey(resourceType, resourceID), result)
+	if err != nil {
+		return fmt.Errorf("error writing revisions: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisions reads the resource's list
+func (s *kvRevisionStorage) GetRevisions(_ context.Context, resourceType, resourceID string) ([]pkg.Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.get(resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	if all == nil {
+		return []pkg.Revision{}, nil
+	}
+
+	return all, nil
+}
+
+// GetRevision finds the Revision in the resource's list
+func (s *kvRevisionStorage) GetRevision(_ context.Context, resourceType, resourceID string, number uint) (*pkg.Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.get(resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range all {
+		if revision.Number == number {
+			return &revision, nil
+		}
+	}
+
+	return nil, babyapi.ErrNotFound
+}
+
+func (s *kvRevisionStorage) get(resourceType, resourceID string) ([]pkg.Revision, error) {
+	data, err := s.db.Get(revisionsKey(resourceType, resourceID))
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting revisions: %w", err)
+	}
+
+	var result []pkg.Revision
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing revisions: %w", err)
+	}
+
+	return result, nil
+}
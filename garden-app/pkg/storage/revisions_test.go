@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVRevisionStorage(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	revisions, err := client.Revisions.GetRevisions(ctx, "zone", "zone1")
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	for i := 0; i < maxRevisions+5; i++ {
+		data := []byte(fmt.Sprintf(`{"name":"version %d"}`, i+1))
+		require.NoError(t, client.Revisions.AddRevision(ctx, "zone", "zone1", now.Add(time.Duration(i)*time.Hour), data))
+	}
+	require.NoError(t, client.Revisions.AddRevision(ctx, "garden", "zone1", now, []byte(`{}`)))
+
+	t.Run("MostRecentFirstAndBounded", func(t *testing.T) {
+		revisions, err := client.Revisions.GetRevisions(ctx, "zone", "zone1")
+		require.NoError(t, err)
+		require.Len(t, revisions, maxRevisions)
+		assert.Equal(t, uint(maxRevisions+5), revisions[0].Number)
+		assert.Equal(t, uint(6), revisions[maxRevisions-1].Number)
+	})
+
+	t.Run("GetRevision", func(t *testing.T) {
+		revision, err := client.Revisions.GetRevision(ctx, "zone", "zone1", 10)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"version 10"}`, string(revision.Data))
+		assert.Equal(t, now.Add(9*time.Hour), revision.RecordTime)
+	})
+
+	t.Run("ErrorRemovedRevision", func(t *testing.T) {
+		_, err := client.Revisions.GetRevision(ctx, "zone", "zone1", 5)
+		assert.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("SeparateResourceTypes", func(t *testing.T) {
+		revisions, err := client.Revisions.GetRevisions(ctx, "garden", "zone1")
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, uint(1), revisions[0].Number)
+	})
+}
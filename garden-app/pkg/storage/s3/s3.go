@@ -0,0 +1,179 @@
+// Package s3 implements a hord.Database that saves each key as an object in an S3-compatible bucket. All objects are
+// read into a local cache during Setup and writes go to the bucket before the cache, so reads do not need any
+// requests. Since the cache is only loaded once, a bucket and prefix must only be used by one garden-app at a time
+package s3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	objects "github.com/calvinmclean/automated-garden/garden-app/pkg/photos/s3"
+	"github.com/madflojo/hord"
+)
+
+// requestTimeout limits how long each request to the bucket can take
+const requestTimeout = 30 * time.Second
+
+// DB is a hord.Database backed by an S3 bucket with a write-through cache
+type DB struct {
+	store *objects.Store
+
+	cache    map[string][]byte
+	cacheMtx sync.RWMutex
+
+	// writeMtx makes sure concurrent writes to the same key are saved in the bucket and cache in the same order
+	writeMtx sync.Mutex
+}
+
+var _ hord.Database = &DB{}
+
+// Dial creates a DB using the same options as the s3 photos driver: endpoint, region, bucket, prefix,
+// access_key_id, and secret_access_key
+func Dial(options map[string]interface{}) (*DB, error) {
+	store, err := objects.NewStore(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DB{
+		store: store,
+		cache: map[string][]byte{},
+	}, nil
+}
+
+// Setup reads every object in the bucket into the cache
+func (db *DB) Setup() error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	keys, err := db.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing objects: %w", err)
+	}
+
+	cache := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		data, err := db.getObject(key)
+		if err != nil {
+			return fmt.Errorf("error reading object %q: %w", key, err)
+		}
+		cache[key] = data
+	}
+
+	db.cacheMtx.Lock()
+	db.cache = cache
+	db.cacheMtx.Unlock()
+
+	return nil
+}
+
+// HealthCheck makes sure the bucket can be listed
+func (db *DB) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	_, err := db.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing objects: %w", err)
+	}
+	return nil
+}
+
+// Get returns the data from the cache. hord.ErrNil is returned when the key does not exist
+func (db *DB) Get(key string) ([]byte, error) {
+	if key == "" {
+		return nil, hord.ErrInvalidKey
+	}
+
+	db.cacheMtx.RLock()
+	defer db.cacheMtx.RUnlock()
+
+	data, ok := db.cache[key]
+	if !ok {
+		return nil, hord.ErrNil
+	}
+	return bytes.Clone(data), nil
+}
+
+// Set saves the object in the bucket and then updates the cache
+func (db *DB) Set(key string, data []byte) error {
+	if key == "" {
+		return hord.ErrInvalidKey
+	}
+	if len(data) == 0 {
+		return hord.ErrInvalidData
+	}
+
+	db.writeMtx.Lock()
+	defer db.writeMtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	err := db.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/json")
+	if err != nil {
+		return fmt.Errorf("error saving object %q: %w", key, err)
+	}
+
+	db.cacheMtx.Lock()
+	db.cache[key] = bytes.Clone(data)
+	db.cacheMtx.Unlock()
+
+	return nil
+}
+
+// Delete removes the object from the bucket and then the cache
+func (db *DB) Delete(key string) error {
+	if key == "" {
+		return hord.ErrInvalidKey
+	}
+
+	db.writeMtx.Lock()
+	defer db.writeMtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	err := db.store.Delete(ctx, key)
+	if err != nil {
+		return fmt.Errorf("error deleting object %q: %w", key, err)
+	}
+
+	db.cacheMtx.Lock()
+	delete(db.cache, key)
+	db.cacheMtx.Unlock()
+
+	return nil
+}
+
+// Keys returns all keys in the cache
+func (db *DB) Keys() ([]string, error) {
+	db.cacheMtx.RLock()
+	defer db.cacheMtx.RUnlock()
+
+	keys := make([]string, 0, len(db.cache))
+	for key := range db.cache {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Close does nothing since there is no connection to close
+func (db *DB) Close() {}
+
+func (db *DB) getObject(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	body, err := db.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
@@ -0,0 +1,143 @@
+package s3
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/madflojo/hord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal S3 server that keeps objects in memory and lists them in one page
+type fakeS3 struct {
+	sync.Mutex
+	objects map[string]string
+	gets    int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = string(body)
+	case http.MethodGet:
+		if r.URL.Query().Get("list-type") == "2" {
+			f.list(w, r)
+			return
+		}
+		f.gets++
+		obj, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(obj))
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	keys := []string{}
+	for objectPath := range f.objects {
+		key := strings.TrimPrefix(objectPath, r.URL.Path+"/")
+		if strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	_, _ = fmt.Fprint(w, "<ListBucketResult>")
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+	}
+	_, _ = fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+}
+
+func TestDB(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{
+		"/garden/storage/Garden_abc": `{"id":"abc"}`,
+		"/garden/photos/photo":       "image data",
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	options := map[string]interface{}{
+		"endpoint":          server.URL,
+		"bucket":            "garden",
+		"prefix":            "storage",
+		"region":            "us-east-1",
+		"access_key_id":     "key",
+		"secret_access_key": "secret",
+	}
+
+	db, err := Dial(options)
+	require.NoError(t, err)
+	require.NoError(t, db.Setup())
+	require.NoError(t, db.HealthCheck())
+
+	t.Run("ExistingObjectsAreCached", func(t *testing.T) {
+		keys, err := db.Keys()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Garden_abc"}, keys)
+
+		gets := fake.gets
+		data, err := db.Get("Garden_abc")
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":"abc"}`, string(data))
+		assert.Equal(t, gets, fake.gets)
+	})
+
+	t.Run("SetWritesThrough", func(t *testing.T) {
+		require.NoError(t, db.Set("Zone_def", []byte(`{"id":"def"}`)))
+		assert.Equal(t, `{"id":"def"}`, fake.objects["/garden/storage/Zone_def"])
+
+		data, err := db.Get("Zone_def")
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":"def"}`, string(data))
+
+		// A new DB loads the changes from the bucket
+		reloaded, err := Dial(options)
+		require.NoError(t, err)
+		require.NoError(t, reloaded.Setup())
+		keys, err := reloaded.Keys()
+		require.NoError(t, err)
+		sort.Strings(keys)
+		assert.Equal(t, []string{"Garden_abc", "Zone_def"}, keys)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, db.Delete("Zone_def"))
+		assert.NotContains(t, fake.objects, "/garden/storage/Zone_def")
+
+		_, err := db.Get("Zone_def")
+		assert.ErrorIs(t, err, hord.ErrNil)
+	})
+
+	t.Run("ErrorInvalidKeyAndData", func(t *testing.T) {
+		_, err := db.Get("")
+		assert.ErrorIs(t, err, hord.ErrInvalidKey)
+		assert.ErrorIs(t, db.Set("Zone_def", nil), hord.ErrInvalidData)
+	})
+
+	t.Run("ErrorSetDoesNotUpdateCache", func(t *testing.T) {
+		server.Close()
+
+		err := db.Set("Zone_ghi", []byte(`{"id":"ghi"}`))
+		assert.Error(t, err)
+
+		_, err = db.Get("Zone_ghi")
+		assert.ErrorIs(t, err, hord.ErrNil)
+	})
+}
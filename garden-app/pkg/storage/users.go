@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/calvinmclean/babyapi"
+)
+
+// RemoveUserFromGardens removes the User's role from every Garden, including end-dated ones, so a deleted User's ID
+// does not keep access
+func (c *Client) RemoveUserFromGardens(ctx context.Context, userID string) error {
+	gardens, err := c.Gardens.GetAll(ctx, babyapi.EndDatedQueryParam(true))
+	if err != nil {
+		return fmt.Errorf("unable to get all Gardens: %w", err)
+	}
+
+	for _, g := range gardens {
+		if g.UserRole(userID) == "" {
+			continue
+		}
+
+		delete(g.Users, userID)
+		err = c.Gardens.Set(ctx, g)
+		if err != nil {
+			return fmt.Errorf("error storing Garden %q: %w", g.GetID(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveUserFromGardens(t *testing.T) {
+	c, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	userID := babyapi.NewID().String()
+	otherUserID := babyapi.NewID().String()
+	endDate := time.Now().Add(-time.Hour)
+
+	gardens := []*pkg.Garden{
+		{ID: babyapi.NewID(), Users: map[string]pkg.UserRole{userID: pkg.UserRoleOwner, otherUserID: pkg.UserRoleViewer}},
+		{ID: babyapi.NewID(), Users: map[string]pkg.UserRole{userID: pkg.UserRoleViewer}, EndDate: &endDate},
+		{ID: babyapi.NewID()},
+	}
+	for _, g := range gardens {
+		require.NoError(t, c.Gardens.Set(context.Background(), g))
+	}
+
+	require.NoError(t, c.RemoveUserFromGardens(context.Background(), userID))
+
+	for _, g := range gardens {
+		updated, err := c.Gardens.Get(context.Background(), g.GetID())
+		require.NoError(t, err)
+		assert.Equal(t, pkg.UserRole(""), updated.UserRole(userID))
+	}
+
+	first, err := c.Gardens.Get(context.Background(), gardens[0].GetID())
+	require.NoError(t, err)
+	assert.Equal(t, pkg.UserRoleViewer, first.UserRole(otherUserID))
+}
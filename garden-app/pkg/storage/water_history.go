@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/madflojo/hord"
+	"github.com/rs/xid"
+)
+
+// maxWaterHistory is the number of water events kept for each Zone by the KV storage. Older events are removed
+// when new ones are added
+const maxWaterHistory = 1000
+
+// WaterHistoryStorage keeps a record of the WaterActions executed for each Zone so history is available without
+// InfluxDB
+type WaterHistoryStorage interface {
+	// AddWaterHistory records a water event for the Zone
+	AddWaterHistory(ctx context.Context, zoneID string, history pkg.WaterHistory) error
+	// GetWaterHistory returns the Zone's water events recorded after since, starting with the most recent. A limit
+	// of 0 returns all events
+	GetWaterHistory(ctx context.Context, zoneID string, since time.Time, limit uint64) ([]pkg.WaterHistory, error)
+	// SetMeasuredLiters records the volume measured by a flow meter on the Zone's most recent water event that
+	// does not have a measurement yet. Manual water events are skipped since they are added with their measurement
+	SetMeasuredLiters(ctx context.Context, zoneID string, liters float64) error
+}
+
+// kvWaterHistoryStorage stores each water event with its own key in a hord.Database. The keys start with the
+// Zone's ID and sort by the event's RecordTime
+type kvWaterHistoryStorage struct {
+	db hord.Database
+}
+
+func newKVWaterHistoryStorage(db hord.Database) *kvWaterHistoryStorage {
+	return &kvWaterHistoryStorage{db: db}
+}
+
+// oldWaterHistoryKey was used to store each Zone's water history as a single JSON list
+func oldWaterHistoryKey(zoneID string) string {
+	return "WaterHistory_" + zoneID
+}
+
+func waterHistoryPrefix(zoneID string) string {
+	return "WaterHistory_" + zoneID + "_"
+}
+
+// migrateWaterHistoryKeys moves each event from the lists saved with oldWaterHistoryKey to its own key. The keys
+// use the event's position in the list so migrating again does not add duplicates
+func migrateWaterHistoryKeys(db hord.Database) error {
+	keys, err := db.Keys()
+	if err != nil {
+		return fmt.Errorf("error getting keys: %w", err)
+	}
+
+	for _, key := range keys {
+		zoneID, found := strings.CutPrefix(key, "WaterHistory_")
+		// Keys for a single event have the Zone's ID, time, and ID separated by "_"
+		if !found || strings.Contains(zoneID, "_") {
+			continue
+		}
+
+		data, err := db.Get(key)
+		if err != nil {
+			return fmt.Errorf("error getting water history for Zone %q: %w", zoneID, err)
+		}
+
+		var all []pkg.WaterHistory
+		err = json.Unmarshal(data, &all)
+		if err != nil {
+			return fmt.Errorf("error parsing water history for Zone %q: %w", zoneID, err)
+		}
+
+		for i, history := range all {
+			err = setWaterHistory(db, entryKey(waterHistoryPrefix(zoneID), history.RecordTime, fmt.Sprintf("migrated%06d", i)), history)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = db.Delete(key)
+		if err != nil {
+			return fmt.Errorf("error deleting water history list for Zone %q: %w", zoneID, err)
+		}
+	}
+
+	return nil
+}
+
+// AddWaterHistory stores the event and then removes the Zone's oldest events when there are more than
+// maxWaterHistory
+func (s *kvWaterHistoryStorage) AddWaterHistory(_ context.Context, zoneID string, history pkg.WaterHistory) error {
+	err := setWaterHistory(s.db, entryKey(waterHistoryPrefix(zoneID), history.RecordTime, xid.New().String()), history)
+	if err != nil {
+		return err
+	}
+
+	err = trimEntries(s.db, waterHistoryPrefix(zoneID), maxWaterHistory)
+	if err != nil {
+		return fmt.Errorf("error removing old water history: %w", err)
+	}
+
+	return nil
+}
+
+// GetWaterHistory reads the Zone's events, starting with the most recent, until they are too old or the limit is
+// reached
+func (s *kvWaterHistoryStorage) GetWaterHistory(_ context.Context, zoneID string, since time.Time, limit uint64) ([]pkg.WaterHistory, error) {
+	keys, err := entryKeys(s.db, waterHistoryPrefix(zoneID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting water history: %w", err)
+	}
+
+	result := []pkg.WaterHistory{}
+	for _, key := range keys {
+		if limit > 0 && uint64(len(result)) >= limit {
+			break
+		}
+
+		h, err := getWaterHistory(s.db, key)
+		if err != nil {
+			return nil, err
+		}
+		if h == nil {
+			// removed after the keys were read
+			continue
+		}
+		// events are sorted, so everything after this is also too old
+		if h.RecordTime.Before(since) {
+			break
+		}
+		result = append(result, *h)
+	}
+
+	return result, nil
+}
+
+// SetMeasuredLiters updates the most recent event without a measurement that is not Manual. Nothing is changed if
+// all events already have one
+func (s *kvWaterHistoryStorage) SetMeasuredLiters(_ context.Context, zoneID string, liters float64) error {
+	keys, err := entryKeys(s.db, waterHistoryPrefix(zoneID))
+	if err != nil {
+		return fmt.Errorf("error getting water history: %w", err)
+	}
+
+	for _, key := range keys {
+		h, err := getWaterHistory(s.db, key)
+		if err != nil {
+			return err
+		}
+		if h == nil || h.MeasuredLiters != nil || h.Manual {
+			continue
+		}
+
+		h.MeasuredLiters = &liters
+		return setWaterHistory(s.db, key, *h)
+	}
+
+	return nil
+}
+
+// getWaterHistory reads the event with the key. It returns nil if the event does not exist
+func getWaterHistory(db hord.Database, key string) (*pkg.WaterHistory, error) {
+	data, err := db.Get(key)
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting water history: %w", err)
+	}
+
+	var result pkg.WaterHistory
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing water history: %w", err)
+	}
+
+	return &result, nil
+}
+
+func setWaterHistory(db hord.Database, key string, history pkg.WaterHistory) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("error marshalling water history: %w", err)
+	}
+
+	err = db.Set(key, data)
+	if err != nil {
+		return fmt.Errorf("error writing water history: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	"github.com/madflojo/hord"
+	"github.com/madflojo/hord/drivers/hashmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVWaterHistoryStorage(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	history, err := client.WaterHistory.GetWaterHistory(ctx, "zone", time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	for i := 0; i < 3; i++ {
+		err = client.WaterHistory.AddWaterHistory(ctx, "zone", pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Duration(i+1) * time.Second},
+			RecordTime: now.Add(time.Duration(i) * time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("MostRecentFirst", func(t *testing.T) {
+		history, err := client.WaterHistory.GetWaterHistory(ctx, "zone", time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		assert.Equal(t, "3s", history[0].Duration.String())
+		assert.Equal(t, "1s", history[2].Duration.String())
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		history, err := client.WaterHistory.GetWaterHistory(ctx, "zone", now.Add(30*time.Minute), 0)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		history, err := client.WaterHistory.GetWaterHistory(ctx, "zone", time.Time{}, 1)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "3s", history[0].Duration.String())
+	})
+
+	t.Run("OtherZone", func(t *testing.T) {
+		history, err := client.WaterHistory.GetWaterHistory(ctx, "other", time.Time{}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+}
+
+func TestKVWaterHistoryStorageSetMeasuredLiters(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	t.Run("NoHistory", func(t *testing.T) {
+		require.NoError(t, client.WaterHistory.SetMeasuredLiters(ctx, "zone", 1))
+
+		history, err := client.WaterHistory.GetWaterHistory(ctx, "zone", time.Time{}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	for i := 0; i < 2; i++ {
+		err = client.WaterHistory.AddWaterHistory(ctx, "zone", pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Minute},
+			RecordTime: now.Add(time.Duration(i) * time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("MostRecentEvent", func(t *testing.T) {
+		require.NoError(t, client.WaterHistory.SetMeasuredLiters(ctx, "zone", 1.5))
+
+		history, err := client.WaterHistory.GetWaterHistory(ctx, "zone", time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.NotNil(t, history[0].MeasuredLiters)
+		assert.Equal(t, 1.5, *history[0].MeasuredLiters)
+		assert.Nil(t, history[1].MeasuredLiters)
+	})
+
+	t.Run("AlreadyMeasured", func(t *testing.T) {
+		require.NoError(t, client.WaterHistory.SetMeasuredLiters(ctx, "zone", 2))
+		require.NoError(t, client.WaterHistory.SetMeasuredLiters(ctx, "zone", 3))
+
+		history, err := client.WaterHistory.GetWaterHistory(ctx, "zone", time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, 1.5, *history[0].MeasuredLiters)
+		assert.Equal(t, 2.0, *history[1].MeasuredLiters)
+	})
+
+	t.Run("SkipManual", func(t *testing.T) {
+		require.NoError(t, client.WaterHistory.AddWaterHistory(ctx, "manual", pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Minute},
+			RecordTime: now,
+		}))
+		require.NoError(t, client.WaterHistory.AddWaterHistory(ctx, "manual", pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Minute},
+			RecordTime: now.Add(time.Hour),
+			Manual:     true,
+		}))
+
+		require.NoError(t, client.WaterHistory.SetMeasuredLiters(ctx, "manual", 1))
+
+		history, err := client.WaterHistory.GetWaterHistory(ctx, "manual", time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Nil(t, history[0].MeasuredLiters)
+		require.NotNil(t, history[1].MeasuredLiters)
+		assert.Equal(t, 1.0, *history[1].MeasuredLiters)
+	})
+}
+
+func TestKVWaterHistoryStorageMax(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	for i := 0; i < maxWaterHistory+5; i++ {
+		err = client.WaterHistory.AddWaterHistory(ctx, "zone", pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Second},
+			RecordTime: now.Add(time.Duration(i) * time.Minute),
+		})
+		require.NoError(t, err)
+	}
+
+	history, err := client.WaterHistory.GetWaterHistory(ctx, "zone", time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, history, maxWaterHistory)
+	assert.Equal(t, now.Add(time.Duration(maxWaterHistory+4)*time.Minute), history[0].RecordTime)
+}
+
+func TestKVWaterHistoryStorageMultipleInstances(t *testing.T) {
+	db, err := kv.NewFileDB(hashmap.Config{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	// Each instance has its own storage using the same database
+	instances := []*kvWaterHistoryStorage{newKVWaterHistoryStorage(db), newKVWaterHistoryStorage(db)}
+
+	var wg sync.WaitGroup
+	for _, s := range instances {
+		wg.Add(1)
+		go func(s *kvWaterHistoryStorage) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				assert.NoError(t, s.AddWaterHistory(ctx, "zone", pkg.WaterHistory{
+					Duration:   &pkg.Duration{Duration: time.Second},
+					RecordTime: now.Add(time.Duration(i) * time.Minute),
+				}))
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	history, err := instances[0].GetWaterHistory(ctx, "zone", time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Len(t, history, 100)
+}
+
+func TestMigrateWaterHistoryKeys(t *testing.T) {
+	db, err := kv.NewFileDB(hashmap.Config{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	data, err := json.Marshal([]pkg.WaterHistory{
+		{Duration: &pkg.Duration{Duration: 2 * time.Second}, RecordTime: now.Add(time.Hour)},
+		{Duration: &pkg.Duration{Duration: time.Second}, RecordTime: now},
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Set("WaterHistory_zone", data))
+
+	require.NoError(t, migrateWaterHistoryKeys(db))
+	// Migrating again does not change anything
+	require.NoError(t, migrateWaterHistoryKeys(db))
+
+	_, err = db.Get("WaterHistory_zone")
+	assert.ErrorIs(t, err, hord.ErrNil)
+
+	history, err := newKVWaterHistoryStorage(db).GetWaterHistory(ctx, "zone", time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "2s", history[0].Duration.String())
+	assert.Equal(t, "1s", history[1].Duration.String())
+}
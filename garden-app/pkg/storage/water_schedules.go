@@ -6,15 +6,35 @@ import (
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
 )
 
-// GetZonesUsingWaterSchedule will find all Zones that use this WaterSchedule and return the Zones along with the Gardens they belong to
+// GetZonesUsingWaterSchedule will find all Zones that use this WaterSchedule, directly or through a ZoneGroup, and
+// return the Zones along with the Gardens they belong to
 func (c *Client) GetZonesUsingWaterSchedule(id string) ([]*pkg.ZoneAndGarden, error) {
 	gardens, err := c.Gardens.GetAll(context.Background(), babyapi.EndDatedQueryParam(false))
 	if err != nil {
 		return nil, fmt.Errorf("unable to get all Gardens: %w", err)
 	}
 
+	zoneGroups, err := c.ZoneGroups.GetAll(context.Background(), babyapi.EndDatedQueryParam(false))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all ZoneGroups: %w", err)
+	}
+
+	// Zones in a ZoneGroup using the WaterSchedule are included, but are still only watered once
+	groupZoneIDs := map[xid.ID]bool{}
+	for _, zg := range zoneGroups {
+		for _, wsID := range zg.WaterScheduleIDs {
+			if wsID.String() != id {
+				continue
+			}
+			for _, zoneID := range zg.ZoneIDs {
+				groupZoneIDs[zoneID] = true
+			}
+		}
+	}
+
 	results := []*pkg.ZoneAndGarden{}
 	for _, g := range gardens {
 		zones, err := c.Zones.GetAll(context.Background(), nil)
@@ -25,6 +45,9 @@ func (c *Client) GetZonesUsingWaterSchedule(id string) ([]*pkg.ZoneAndGarden, er
 			if z.GardenID != g.ID.ID || z.EndDated() {
 				return false
 			}
+			if groupZoneIDs[z.ID.ID] {
+				return true
+			}
 			for _, wsID := range z.WaterScheduleIDs {
 				if wsID.String() == id {
 					return true
@@ -2,17 +2,36 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/mqttsensor"
 	"github.com/calvinmclean/babyapi"
 	"github.com/rs/xid"
 )
 
 // GetWeatherClient ...
 func (c *Client) GetWeatherClient(id xid.ID) (weather.Client, error) {
-	clientConfig, err := c.WeatherClientConfigs.Get(context.Background(), id.String())
+	clientConfig, err := c.getWeatherClientConfig(id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newWeatherClient(clientConfig, nil)
+}
+
+// NewWeatherClient creates a weather.Client for the Config, which does not need to be stored yet. This is used to
+// validate Configs, like making sure all of a composite client's clients exist and none of them use this Config
+func (c *Client) NewWeatherClient(clientConfig *weather.Config) (weather.Client, error) {
+	return c.newWeatherClient(clientConfig, nil)
+}
+
+func (c *Client) getWeatherClientConfig(id string) (*weather.Config, error) {
+	clientConfig, err := c.WeatherClientConfigs.Get(context.Background(), id)
 	if err != nil {
 		return nil, fmt.Errorf("error getting weather client config: %w", err)
 	}
@@ -21,10 +40,60 @@ func (c *Client) GetWeatherClient(id xid.ID) (weather.Client, error) {
 		return nil, fmt.Errorf("weather client config not found")
 	}
 
-	return weather.NewClient(clientConfig, func(weatherClientOptions map[string]interface{}) error {
-		clientConfig.Options = weatherClientOptions
-		return c.WeatherClientConfigs.Set(context.Background(), clientConfig)
-	})
+	return clientConfig, nil
+}
+
+// newWeatherClient creates the weather.Client for the Config. Composite clients recursively create their clients.
+// parents has the IDs of composite clients that led to this one so circular references are detected
+func (c *Client) newWeatherClient(clientConfig *weather.Config, parents []string) (weather.Client, error) {
+	var client weather.Client
+	var err error
+	switch clientConfig.Type {
+	case "composite":
+		parents = append(slices.Clone(parents), clientConfig.GetID())
+		client, err = weather.NewCompositeClient(clientConfig, func(id string) (weather.Client, error) {
+			if slices.Contains(parents, id) {
+				return nil, errors.New("circular reference to composite client")
+			}
+
+			sourceConfig, err := c.getWeatherClientConfig(id)
+			if err != nil {
+				return nil, err
+			}
+			return c.newWeatherClient(sourceConfig, parents)
+		})
+	case "mqtt_sensor":
+		client, err = weather.NewMQTTSensorClient(clientConfig, func(since time.Time) ([]mqttsensor.Reading, error) {
+			return c.WeatherReadings.GetWeatherReadings(context.Background(), clientConfig.GetID(), since)
+		})
+	default:
+		client, err = weather.NewClient(clientConfig, func(weatherClientOptions map[string]interface{}) error {
+			clientConfig.Options = weatherClientOptions
+			return c.WeatherClientConfigs.Set(context.Background(), clientConfig)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.now != nil {
+		weather.SetNow(client, c.now)
+	}
+
+	return client, nil
+}
+
+// GetWeatherClientsUsingWeatherClient will return all composite WeatherClients that combine data from this WeatherClient
+func (c *Client) GetWeatherClientsUsingWeatherClient(id string) ([]*weather.Config, error) {
+	weatherClients, err := c.WeatherClientConfigs.GetAll(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all WeatherClients: %w", err)
+	}
+	weatherClients = babyapi.FilterFunc[*weather.Config](func(wc *weather.Config) bool {
+		return slices.Contains(wc.CompositeClientIDs(), id)
+	}).Filter(weatherClients)
+
+	return weatherClients, nil
 }
 
 // GetWaterSchedulesUsingWeatherClient will return all WaterSchedules that rely on this WeatherClient
@@ -43,6 +112,12 @@ func (c *Client) GetWaterSchedulesUsingWeatherClient(id string) ([]*pkg.WaterSch
 		if ws.HasTemperatureControl() && ws.WeatherControl.Temperature.ClientID.String() == id {
 			return true
 		}
+		if ws.HasRainForecastControl() && ws.WeatherControl.RainForecast.ClientID.String() == id {
+			return true
+		}
+		if ws.HasFrostControl() && ws.WeatherControl.Frost.ClientID.String() == id {
+			return true
+		}
 		return false
 	}).Filter(waterSchedules)
 
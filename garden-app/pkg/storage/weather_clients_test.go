@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCompositeWeatherClient(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	weather.ResetCache()
+
+	var ids []interface{}
+	for _, rain := range []float64{10, 30} {
+		wc := &weather.Config{
+			ID:   babyapi.NewID(),
+			Type: "fake",
+			Options: map[string]interface{}{
+				"rain_mm":       rain,
+				"rain_interval": "24h",
+			},
+		}
+		require.NoError(t, client.WeatherClientConfigs.Set(ctx, wc))
+		ids = append(ids, wc.GetID())
+	}
+
+	composite := &weather.Config{
+		ID:      babyapi.NewID(),
+		Type:    "composite",
+		Options: map[string]interface{}{"client_ids": ids},
+	}
+	require.NoError(t, client.WeatherClientConfigs.Set(ctx, composite))
+
+	t.Run("Average", func(t *testing.T) {
+		wc, err := client.GetWeatherClient(composite.ID.ID)
+		require.NoError(t, err)
+
+		rain, err := wc.GetTotalRain(24 * time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, float32(20), rain)
+	})
+
+	t.Run("UsedBy", func(t *testing.T) {
+		weatherClients, err := client.GetWeatherClientsUsingWeatherClient(ids[0].(string))
+		require.NoError(t, err)
+		require.Len(t, weatherClients, 1)
+		assert.Equal(t, composite.GetID(), weatherClients[0].GetID())
+	})
+
+	t.Run("ErrorCircularReference", func(t *testing.T) {
+		other := &weather.Config{
+			ID:      babyapi.NewID(),
+			Type:    "composite",
+			Options: map[string]interface{}{"client_ids": []interface{}{composite.GetID()}},
+		}
+		require.NoError(t, client.WeatherClientConfigs.Set(ctx, other))
+
+		// Update the first composite client to use the second one
+		circular := &weather.Config{
+			ID:      composite.ID,
+			Type:    "composite",
+			Options: map[string]interface{}{"client_ids": []interface{}{other.GetID()}},
+		}
+		require.NoError(t, client.WeatherClientConfigs.Set(ctx, circular))
+
+		_, err := client.GetWeatherClient(composite.ID.ID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "circular reference to composite client")
+	})
+}
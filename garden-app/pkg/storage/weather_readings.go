@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/mqttsensor"
+	"github.com/madflojo/hord"
+)
+
+// maxWeatherReadings is the number of readings kept for each WeatherClient by the KV storage. Older readings are
+// removed when new ones are added
+const maxWeatherReadings = 10000
+
+// WeatherReadingStorage keeps the readings published by local sensors for "mqtt_sensor" WeatherClients
+type WeatherReadingStorage interface {
+	// AddWeatherReading records a reading for the WeatherClient
+	AddWeatherReading(ctx context.Context, clientID string, reading mqttsensor.Reading) error
+	// GetWeatherReadings returns the WeatherClient's readings recorded after since, starting with the most recent
+	GetWeatherReadings(ctx context.Context, clientID string, since time.Time) ([]mqttsensor.Reading, error)
+}
+
+// kvWeatherReadingStorage stores each WeatherClient's readings as a single JSON list in a hord.Database
+type kvWeatherReadingStorage struct {
+	db hord.Database
+	mu sync.Mutex
+}
+
+func newKVWeatherReadingStorage(db hord.Database) *kvWeatherReadingStorage {
+	return &kvWeatherReadingStorage{db: db}
+}
+
+func weatherReadingsKey(clientID string) string {
+	return "WeatherReadings_" + clientID
+}
+
+// AddWeatherReading adds the reading to the beginning of the WeatherClient's list and removes the oldest readings
+// when there are more than maxWeatherReadings
+func (s *kvWeatherReadingStorage) AddWeatherReading(_ context.Context, clientID string, reading mqttsensor.Reading) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.get(clientID)
+	if err != nil {
+		return err
+	}
+
+	all = append([]mqttsensor.Reading{reading}, all...)
+	if len(all) > maxWeatherReadings {
+		all = all[:maxWeatherReadings]
+	}
+
+	data, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("error marshalling weather readings: %w", err)
+	}
+
+	err = s.db.Set(weatherReadingsKey(clientID), data)
+	if err != nil {
+		return fmt.Errorf("error writing weather readings: %w", err)
+	}
+
+	return nil
+}
+
+// GetWeatherReadings reads the WeatherClient's list and filters it by time
+func (s *kvWeatherReadingStorage) GetWeatherReadings(_ context.Context, clientID string, since time.Time) ([]mqttsensor.Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.get(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []mqttsensor.Reading{}
+	for _, r := range all {
+		// readings are sorted, so everything after this is also too old
+		if r.Time.Before(since) {
+			break
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+func (s *kvWeatherReadingStorage) get(clientID string) ([]mqttsensor.Reading, error) {
+	data, err := s.db.Get(weatherReadingsKey(clientID))
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting weather readings: %w", err)
+	}
+
+	var result []mqttsensor.Reading
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing weather readings: %w", err)
+	}
+
+	return result, nil
+}
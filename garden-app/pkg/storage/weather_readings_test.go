@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/mqttsensor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVWeatherReadingStorage(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	readings, err := client.WeatherReadings.GetWeatherReadings(ctx, "client1", time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, readings)
+
+	for i := 0; i < 3; i++ {
+		rain := float32(i)
+		err = client.WeatherReadings.AddWeatherReading(ctx, "client1", mqttsensor.Reading{
+			Time:   now.Add(time.Duration(i) * time.Hour),
+			RainMM: &rain,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("MostRecentFirst", func(t *testing.T) {
+		readings, err := client.WeatherReadings.GetWeatherReadings(ctx, "client1", time.Time{})
+		require.NoError(t, err)
+		require.Len(t, readings, 3)
+		assert.Equal(t, float32(2), *readings[0].RainMM)
+		assert.Equal(t, float32(0), *readings[2].RainMM)
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		readings, err := client.WeatherReadings.GetWeatherReadings(ctx, "client1", now.Add(30*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, readings, 2)
+		assert.Equal(t, now.Add(2*time.Hour), readings[0].Time)
+	})
+
+	t.Run("OtherClient", func(t *testing.T) {
+		readings, err := client.WeatherReadings.GetWeatherReadings(ctx, "client2", time.Time{})
+		require.NoError(t, err)
+		assert.Empty(t, readings)
+	})
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/madflojo/hord"
+)
+
+// maxWebhookDeliveries is the number of WebhookDeliveries kept for each Webhook by the KV storage. Older deliveries
+// are removed when new ones are added
+const maxWebhookDeliveries = 100
+
+// WebhookDeliveryStorage keeps track of the Events sent to each Webhook so failed deliveries can be found
+type WebhookDeliveryStorage interface {
+	// SetWebhookDelivery creates or replaces the WebhookDelivery with the same ID
+	SetWebhookDelivery(ctx context.Context, delivery pkg.WebhookDelivery) error
+	// GetWebhookDeliveries returns the Webhook's deliveries, starting with the most recent. A limit of 0 returns all
+	// deliveries
+	GetWebhookDeliveries(ctx context.Context, webhookID string, limit uint64) ([]pkg.WebhookDelivery, error)
+}
+
+// kvWebhookDeliveryStorage stores each Webhook's deliveries as a JSON list in a hord.Database
+type kvWebhookDeliveryStorage struct {
+	db hord.Database
+	mu sync.Mutex
+}
+
+func newKVWebhookDeliveryStorage(db hord.Database) *kvWebhookDeliveryStorage {
+	return &kvWebhookDeliveryStorage{db: db}
+}
+
+// webhookDeliveriesKey does not start with "Webhook" so the keys are not read as Webhooks by the KV storage
+func webhookDeliveriesKey(webhookID string) string {
+	return "DeliveriesForWebhook_" + webhookID
+}
+
+// SetWebhookDelivery replaces the delivery in the Webhook's list or adds it to the beginning. The oldest deliveries
+// are removed when there are more than maxWebhookDeliveries
+func (s *kvWebhookDeliveryStorage) SetWebhookDelivery(_ context.Context, delivery pkg.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries, err := s.get(delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(deliveries, func(d pkg.WebhookDelivery) bool {
+		return d.ID == delivery.ID
+	})
+	if i >= 0 {
+		deliveries[i] = delivery
+	} else {
+		deliveries = append([]pkg.WebhookDelivery{delivery}, deliveries...)
+		if len(deliveries) > maxWebhookDeliveries {
+			deliveries = deliveries[:maxWebhookDeliveries]
+		}
+	}
+
+	data, err := json.Marshal(deliveries)
+	if err != nil {
+		return fmt.Errorf("error marshalling webhook deliveries: %w", err)
+	}
+
+	err = s.db.Set(webhookDeliveriesKey(delivery.WebhookID), data)
+	if err != nil {
+		return fmt.Errorf("error writing webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries reads the Webhook's list of deliveries
+func (s *kvWebhookDeliveryStorage) GetWebhookDeliveries(_ context.Context, webhookID string, limit uint64) ([]pkg.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries, err := s.get(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && uint64(len(deliveries)) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (s *kvWebhookDeliveryStorage) get(webhookID string) ([]pkg.WebhookDelivery, error) {
+	data, err := s.db.Get(webhookDeliveriesKey(webhookID))
+	if err != nil {
+		if errors.Is(err, hord.ErrNil) {
+			return []pkg.WebhookDelivery{}, nil
+		}
+		return nil, fmt.Errorf("error getting webhook deliveries: %w", err)
+	}
+
+	var result []pkg.WebhookDelivery
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing webhook deliveries: %w", err)
+	}
+
+	return result, nil
+}
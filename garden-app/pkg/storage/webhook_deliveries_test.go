@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVWebhookDeliveryStorage(t *testing.T) {
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	deliveries, err := client.WebhookDeliveries.GetWebhookDeliveries(ctx, "webhook", 0)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	delivery := pkg.WebhookDelivery{ID: "first", WebhookID: "webhook", EventType: "garden.updated", Status: pkg.WebhookDeliveryStatusPending, CreatedAt: now}
+	require.NoError(t, client.WebhookDeliveries.SetWebhookDelivery(ctx, delivery))
+	require.NoError(t, client.WebhookDeliveries.SetWebhookDelivery(ctx, pkg.WebhookDelivery{ID: "second", WebhookID: "webhook", CreatedAt: now}))
+	require.NoError(t, client.WebhookDeliveries.SetWebhookDelivery(ctx, pkg.WebhookDelivery{ID: "other", WebhookID: "other", CreatedAt: now}))
+
+	t.Run("UpdateExisting", func(t *testing.T) {
+		delivery.Status = pkg.WebhookDeliveryStatusSucceeded
+		delivery.Attempts = 2
+		require.NoError(t, client.WebhookDeliveries.SetWebhookDelivery(ctx, delivery))
+
+		deliveries, err := client.WebhookDeliveries.GetWebhookDeliveries(ctx, "webhook", 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, "second", deliveries[0].ID)
+		assert.Equal(t, "first", deliveries[1].ID)
+		assert.Equal(t, pkg.WebhookDeliveryStatusSucceeded, deliveries[1].Status)
+		assert.Equal(t, 2, deliveries[1].Attempts)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		deliveries, err := client.WebhookDeliveries.GetWebhookDeliveries(ctx, "webhook", 1)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, "second", deliveries[0].ID)
+	})
+
+	t.Run("OldDeliveriesRemoved", func(t *testing.T) {
+		for i := 0; i < maxWebhookDeliveries; i++ {
+			require.NoError(t, client.WebhookDeliveries.SetWebhookDelivery(ctx, pkg.WebhookDelivery{
+				ID:        fmt.Sprintf("delivery%d", i),
+				WebhookID: "webhook",
+				CreatedAt: now,
+			}))
+		}
+
+		deliveries, err := client.WebhookDeliveries.GetWebhookDeliveries(ctx, "webhook", 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, maxWebhookDeliveries)
+		assert.Equal(t, fmt.Sprintf("delivery%d", maxWebhookDeliveries-1), deliveries[0].ID)
+
+		deliveries, err = client.WebhookDeliveries.GetWebhookDeliveries(ctx, "other", 0)
+		require.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+	})
+
+	t.Run("NotReadAsWebhooks", func(t *testing.T) {
+		require.NoError(t, client.Webhooks.Set(ctx, &pkg.Webhook{ID: babyapi.NewID(), Name: "Alerts"}))
+
+		webhooks, err := client.Webhooks.GetAll(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, "Alerts", webhooks[0].Name)
+	})
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+)
+
+// zoneGroupKVPrefix does not start with "Zone" so ZoneGroups are not read as Zones by the KV storage
+const zoneGroupKVPrefix = "GroupOfZones"
+
+// GetZoneGroupsForZone returns the active ZoneGroups that the Zone is a member of
+func (c *Client) GetZoneGroupsForZone(ctx context.Context, z *pkg.Zone) ([]*pkg.ZoneGroup, error) {
+	zoneGroups, err := c.ZoneGroups.GetAll(ctx, babyapi.EndDatedQueryParam(false))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all ZoneGroups: %w", err)
+	}
+
+	return babyapi.FilterFunc[*pkg.ZoneGroup](func(zg *pkg.ZoneGroup) bool {
+		return zg.GardenID == z.GardenID && zg.HasZone(z.ID.ID)
+	}).Filter(zoneGroups), nil
+}
+
+// GetWaterScheduleIDsForZone returns the IDs of the WaterSchedules that the Zone uses directly or through its
+// ZoneGroups, without duplicates
+func (c *Client) GetWaterScheduleIDsForZone(ctx context.Context, z *pkg.Zone) ([]xid.ID, error) {
+	zoneGroups, err := c.GetZoneGroupsForZone(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := slices.Clone(z.WaterScheduleIDs)
+	for _, zg := range zoneGroups {
+		for _, id := range zg.WaterScheduleIDs {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
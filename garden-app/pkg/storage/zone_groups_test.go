@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneGroupWaterSchedules(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	maxZones := uint(3)
+	garden := &pkg.Garden{ID: babyapi.NewID(), Name: "garden", TopicPrefix: "garden", MaxZones: &maxZones}
+	require.NoError(t, client.Gardens.Set(ctx, garden))
+
+	directWS := xid.New()
+	groupWS := xid.New()
+
+	zones := []*pkg.Zone{}
+	for i, wsIDs := range [][]xid.ID{{directWS}, {groupWS}, nil} {
+		position := uint(i)
+		z := &pkg.Zone{ID: babyapi.NewID(), GardenID: garden.ID.ID, Position: &position, WaterScheduleIDs: wsIDs}
+		require.NoError(t, client.Zones.Set(ctx, z))
+		zones = append(zones, z)
+	}
+
+	endDate := time.Now().Add(-time.Hour)
+	require.NoError(t, client.ZoneGroups.Set(ctx, &pkg.ZoneGroup{
+		ID:               babyapi.NewID(),
+		GardenID:         garden.ID.ID,
+		ZoneIDs:          []xid.ID{zones[1].ID.ID, zones[2].ID.ID},
+		WaterScheduleIDs: []xid.ID{groupWS},
+	}))
+	// End-dated ZoneGroups are ignored
+	require.NoError(t, client.ZoneGroups.Set(ctx, &pkg.ZoneGroup{
+		ID:               babyapi.NewID(),
+		GardenID:         garden.ID.ID,
+		ZoneIDs:          []xid.ID{zones[0].ID.ID},
+		WaterScheduleIDs: []xid.ID{groupWS},
+		EndDate:          &endDate,
+	}))
+
+	t.Run("GetZonesUsingWaterSchedule", func(t *testing.T) {
+		zonesAndGardens, err := client.GetZonesUsingWaterSchedule(groupWS.String())
+		require.NoError(t, err)
+
+		// zones[1] uses the WaterSchedule directly and through the ZoneGroup, but is only included once
+		zoneIDs := []string{}
+		for _, zg := range zonesAndGardens {
+			zoneIDs = append(zoneIDs, zg.Zone.GetID())
+			assert.Equal(t, garden.GetID(), zg.Garden.GetID())
+		}
+		assert.ElementsMatch(t, []string{zones[1].GetID(), zones[2].GetID()}, zoneIDs)
+	})
+
+	t.Run("GetWaterScheduleIDsForZone", func(t *testing.T) {
+		ids, err := client.GetWaterScheduleIDsForZone(ctx, zones[0])
+		require.NoError(t, err)
+		assert.Equal(t, []xid.ID{directWS}, ids)
+
+		ids, err = client.GetWaterScheduleIDsForZone(ctx, zones[1])
+		require.NoError(t, err)
+		assert.Equal(t, []xid.ID{groupWS}, ids)
+
+		ids, err = client.GetWaterScheduleIDsForZone(ctx, zones[2])
+		require.NoError(t, err)
+		assert.Equal(t, []xid.ID{groupWS}, ids)
+	})
+}
+
+func TestZoneGroupsAreNotZones(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := NewClient(Config{Driver: "hashmap"})
+	require.NoError(t, err)
+
+	err = client.ZoneGroups.Set(ctx, &pkg.ZoneGroup{ID: babyapi.NewID(), GardenID: xid.New(), Name: "group"})
+	require.NoError(t, err)
+
+	zones, err := client.Zones.GetAll(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, zones)
+}
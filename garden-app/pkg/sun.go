@@ -0,0 +1,190 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SunEventSunrise is used to schedule relative to sunrise
+	SunEventSunrise SunEvent = "sunrise"
+	// SunEventSunset is used to schedule relative to sunset
+	SunEventSunset SunEvent = "sunset"
+
+	// julianUnixEpoch is the Julian day of 1970-01-01T00:00:00Z
+	julianUnixEpoch = 2440587.5
+	// julian2000 is the Julian day of 2000-01-01T12:00:00Z
+	julian2000 = 2451545.0
+)
+
+// ErrNoSunEvent is returned when the sun does not rise or set on a day, which happens near the poles
+var ErrNoSunEvent = errors.New("sun does not rise or set on this day")
+
+// SunEvent is either sunrise or sunset
+type SunEvent string
+
+// Location is the coordinates of a Garden, used to calculate sunrise and sunset
+type Location struct {
+	Latitude  float64 `json:"latitude" yaml:"latitude"`
+	Longitude float64 `json:"longitude" yaml:"longitude"`
+}
+
+// Validate checks that the coordinates are in range
+func (l *Location) Validate() error {
+	if l.Latitude < -90 || l.Latitude > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90: %v", l.Latitude)
+	}
+	if l.Longitude < -180 || l.Longitude > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180: %v", l.Longitude)
+	}
+	return nil
+}
+
+// SunTimes calculates sunrise and sunset for the date's year, month, and day at the Location. It uses the
+// sunrise equation, which is accurate within a couple of minutes
+func (l *Location) SunTimes(date time.Time) (sunrise time.Time, sunset time.Time, err error) {
+	noon := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+	n := math.Round(float64(noon.Unix())/86400 + julianUnixEpoch - julian2000)
+
+	meanSolarTime := n - l.Longitude/360
+	meanAnomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360)
+	m := radians(meanAnomaly)
+	center := 1.9148*math.Sin(m) + 0.02*math.Sin(2*m) + 0.0003*math.Sin(3*m)
+	eclipticLongitude := radians(math.Mod(meanAnomaly+center+180+102.9372, 360))
+	transit := julian2000 + meanSolarTime + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*eclipticLongitude)
+
+	declination := math.Asin(math.Sin(eclipticLongitude) * math.Sin(radians(23.4397)))
+	lat := radians(l.Latitude)
+	cosHourAngle := (math.Sin(radians(-0.833)) - math.Sin(lat)*math.Sin(declination)) / (math.Cos(lat) * math.Cos(declination))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, time.Time{}, ErrNoSunEvent
+	}
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+
+	return julianToTime(transit - hourAngle/360), julianToTime(transit + hourAngle/360), nil
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func julianToTime(julian float64) time.Time {
+	seconds := (julian - julianUnixEpoch) * 86400
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC().Truncate(time.Second)
+}
+
+// SunTime is a time of day relative to sunrise or sunset, like "sunrise+30m" or "sunset-1h"
+type SunTime struct {
+	Event  SunEvent
+	Offset time.Duration
+}
+
+// SunTimeFromString parses a SunTime from a string like "sunrise+30m"
+func SunTimeFromString(s string) (*SunTime, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	var event SunEvent
+	switch {
+	case strings.HasPrefix(s, string(SunEventSunrise)):
+		event = SunEventSunrise
+	case strings.HasPrefix(s, string(SunEventSunset)):
+		event = SunEventSunset
+	default:
+		return nil, fmt.Errorf("invalid sun time %q: must start with %q or %q", s, SunEventSunrise, SunEventSunset)
+	}
+
+	result := &SunTime{Event: event}
+
+	offset := strings.ReplaceAll(strings.TrimPrefix(s, string(event)), " ", "")
+	if offset == "" {
+		return result, nil
+	}
+	if offset[0] != '+' && offset[0] != '-' {
+		return nil, fmt.Errorf("invalid sun time %q: offset must start with + or -", s)
+	}
+
+	var err error
+	result.Offset, err = time.ParseDuration(offset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sun time %q: %w", s, err)
+	}
+
+	return result, nil
+}
+
+// String returns the SunTime in the same format that it is parsed from
+func (st *SunTime) String() string {
+	switch {
+	case st.Offset > 0:
+		return fmt.Sprintf("%s+%s", st.Event, st.Offset)
+	case st.Offset < 0:
+		return fmt.Sprintf("%s%s", st.Event, st.Offset)
+	default:
+		return string(st.Event)
+	}
+}
+
+// Validate checks that the offset stays within a day
+func (st *SunTime) Validate() error {
+	if st.Offset >= 12*time.Hour || st.Offset <= -12*time.Hour {
+		return fmt.Errorf("invalid sun time offset must be less than 12 hours: %s", st)
+	}
+	return nil
+}
+
+// On returns the time of this SunTime at the Location on the date's year, month, and day
+func (st *SunTime) On(loc *Location, date time.Time) (time.Time, error) {
+	sunrise, sunset, err := loc.SunTimes(date)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if st.Event == SunEventSunset {
+		return sunset.Add(st.Offset), nil
+	}
+	return sunrise.Add(st.Offset), nil
+}
+
+// JSONSchema describes a SunTime as a string like "sunrise+30m"
+func (st *SunTime) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "string", Pattern: "^(sunrise|sunset)([+-].+)?$"}
+}
+
+func (st *SunTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(st.String())
+}
+
+func (st *SunTime) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	return st.UnmarshalText([]byte(s))
+}
+
+func (st *SunTime) UnmarshalText(data []byte) error {
+	result, err := SunTimeFromString(string(data))
+	if err != nil {
+		return err
+	}
+	*st = *result
+	return nil
+}
+
+// UnmarshalYAML reads a SunTime from a string
+func (st *SunTime) UnmarshalYAML(value *yaml.Node) error {
+	return st.UnmarshalText([]byte(value.Value))
+}
+
+// MarshalYAML will convert SunTime into the string representation
+func (st *SunTime) MarshalYAML() (interface{}, error) {
+	return st.String(), nil
+}
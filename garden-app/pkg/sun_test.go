@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSunTimes(t *testing.T) {
+	tests := []struct {
+		name            string
+		location        Location
+		date            time.Time
+		expectedSunrise time.Time
+		expectedSunset  time.Time
+	}{
+		{
+			"PhoenixSummer",
+			Location{Latitude: 33.45, Longitude: -112.07},
+			time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, time.June, 1, 12, 19, 0, 0, time.UTC),
+			time.Date(2023, time.June, 2, 2, 32, 0, 0, time.UTC),
+		},
+		{
+			"LondonWinter",
+			Location{Latitude: 51.51, Longitude: -0.13},
+			time.Date(2023, time.December, 21, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, time.December, 21, 8, 4, 0, 0, time.UTC),
+			time.Date(2023, time.December, 21, 15, 53, 0, 0, time.UTC),
+		},
+		{
+			"Sydney",
+			Location{Latitude: -33.87, Longitude: 151.21},
+			time.Date(2023, time.December, 21, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, time.December, 20, 18, 41, 0, 0, time.UTC),
+			time.Date(2023, time.December, 21, 9, 5, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sunrise, sunset, err := tt.location.SunTimes(tt.date)
+			require.NoError(t, err)
+			assert.WithinDuration(t, tt.expectedSunrise, sunrise, 2*time.Minute)
+			assert.WithinDuration(t, tt.expectedSunset, sunset, 2*time.Minute)
+		})
+	}
+
+	t.Run("ErrorPolarNight", func(t *testing.T) {
+		loc := Location{Latitude: 78.22, Longitude: 15.65}
+		_, _, err := loc.SunTimes(time.Date(2023, time.December, 21, 0, 0, 0, 0, time.UTC))
+		assert.ErrorIs(t, err, ErrNoSunEvent)
+	})
+}
+
+func TestLocationValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		location    Location
+		expectedErr string
+	}{
+		{"Valid", Location{Latitude: 33.45, Longitude: -112.07}, ""},
+		{"InvalidLatitude", Location{Latitude: 91}, "latitude must be between -90 and 90: 91"},
+		{"InvalidLongitude", Location{Longitude: -181}, "longitude must be between -180 and 180: -181"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.location.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestSunTimeFromString(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    *SunTime
+		expectedErr string
+	}{
+		{"sunrise", &SunTime{Event: SunEventSunrise}, ""},
+		{"sunrise+30m", &SunTime{Event: SunEventSunrise, Offset: 30 * time.Minute}, ""},
+		{"Sunset-1h", &SunTime{Event: SunEventSunset, Offset: -1 * time.Hour}, ""},
+		{"sunset - 1h30m", &SunTime{Event: SunEventSunset, Offset: -90 * time.Minute}, ""},
+		{"noon", nil, `invalid sun time "noon": must start with "sunrise" or "sunset"`},
+		{"sunrise30m", nil, `invalid sun time "sunrise30m": offset must start with + or -`},
+		{"sunrise+abc", nil, `invalid sun time "sunrise+abc": time: invalid duration "+abc"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			st, err := SunTimeFromString(tt.input)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, st)
+		})
+	}
+}
+
+func TestSunTimeJSON(t *testing.T) {
+	for _, input := range []string{`"sunrise"`, `"sunrise+30m0s"`, `"sunset-1h0m0s"`} {
+		t.Run(input, func(t *testing.T) {
+			var st SunTime
+			require.NoError(t, json.Unmarshal([]byte(input), &st))
+
+			result, err := json.Marshal(&st)
+			require.NoError(t, err)
+			assert.Equal(t, input, string(result))
+		})
+	}
+}
+
+func TestSunTimeValidate(t *testing.T) {
+	assert.NoError(t, (&SunTime{Event: SunEventSunset, Offset: -11 * time.Hour}).Validate())
+	assert.EqualError(t, (&SunTime{Event: SunEventSunrise, Offset: 12 * time.Hour}).Validate(), "invalid sun time offset must be less than 12 hours: sunrise+12h0m0s")
+}
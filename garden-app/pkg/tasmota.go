@@ -0,0 +1,67 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DefaultTasmotaPowerTopic is the command topic for each relay of a Tasmota device. The Garden's TopicPrefix is
+	// the device's topic
+	DefaultTasmotaPowerTopic = "cmnd/{{.Garden}}/POWER{{.Relay}}"
+
+	TasmotaPowerOn  = "ON"
+	TasmotaPowerOff = "OFF"
+)
+
+// TasmotaConfig is used by Gardens that use smart relays as zone valves. These accept ON and OFF commands, so the
+// garden-app turns the relay off after the water duration instead of the device
+type TasmotaConfig struct {
+	// PowerTopic is a template for the topic that controls a Zone's relay. It can use {{.Garden}} (the TopicPrefix),
+	// {{.Position}} (the Zone's position), and {{.Relay}} (the position plus one, which is how Tasmota numbers relays).
+	// The default is DefaultTasmotaPowerTopic. ESPHome devices can use something like {{.Garden}}/switch/zone_{{.Relay}}/command
+	PowerTopic string `json:"power_topic,omitempty" yaml:"power_topic,omitempty"`
+}
+
+// Validate makes sure the PowerTopic can be used to create a topic
+func (c *TasmotaConfig) Validate() error {
+	topic, err := c.Topic("garden", 0)
+	if err != nil {
+		return fmt.Errorf("invalid power_topic template: %w", err)
+	}
+	if topic == "" {
+		return errors.New("invalid power_topic template: topic must not be empty")
+	}
+	if strings.ContainsAny(topic, "+#") {
+		return errors.New("invalid power_topic template: topic must not contain wildcards")
+	}
+	return nil
+}
+
+// Topic returns the topic for the relay at the Zone's position. A nil config uses the default template
+func (c *TasmotaConfig) Topic(topicPrefix string, position uint) (string, error) {
+	templateString := DefaultTasmotaPowerTopic
+	if c != nil && c.PowerTopic != "" {
+		templateString = c.PowerTopic
+	}
+
+	t, err := template.New("tasmota").Parse(templateString)
+	if err != nil {
+		return "", err
+	}
+
+	var result bytes.Buffer
+	data := map[string]any{
+		"Garden":   topicPrefix,
+		"Position": position,
+		"Relay":    position + 1,
+	}
+	err = t.Execute(&result, data)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
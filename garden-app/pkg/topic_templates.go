@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+)
+
+// TopicTemplates override the server's configured MQTT command topics for a single Garden. This allows using
+// controllers with a different topic hierarchy, like Tasmota or OpenSprinkler. Each one is a template that uses
+// {{.Garden}} for the TopicPrefix, and empty templates use the server's config
+type TopicTemplates struct {
+	Water         string `json:"water,omitempty" yaml:"water,omitempty"`
+	Stop          string `json:"stop,omitempty" yaml:"stop,omitempty"`
+	StopAll       string `json:"stop_all,omitempty" yaml:"stop_all,omitempty"`
+	Light         string `json:"light,omitempty" yaml:"light,omitempty"`
+	Recirculation string `json:"recirculation,omitempty" yaml:"recirculation,omitempty"`
+}
+
+// Validate makes sure each template can be executed and creates a topic that can be published to
+func (tt *TopicTemplates) Validate() error {
+	for _, t := range tt.templates() {
+		if t.template == "" {
+			continue
+		}
+		topic, err := mqtt.ExecuteTopicTemplate(t.template, "garden")
+		if err != nil {
+			return fmt.Errorf("invalid %s template: %w", t.name, err)
+		}
+		if topic == "" {
+			return fmt.Errorf("invalid %s template: topic must not be empty", t.name)
+		}
+		if strings.ContainsAny(topic, "+#") {
+			return fmt.Errorf("invalid %s template: topic must not contain wildcards", t.name)
+		}
+	}
+	return nil
+}
+
+// Patch updates the templates that are set in the new TopicTemplates
+func (tt *TopicTemplates) Patch(new *TopicTemplates) {
+	if new.Water != "" {
+		tt.Water = new.Water
+	}
+	if new.Stop != "" {
+		tt.Stop = new.Stop
+	}
+	if new.StopAll != "" {
+		tt.StopAll = new.StopAll
+	}
+	if new.Light != "" {
+		tt.Light = new.Light
+	}
+	if new.Recirculation != "" {
+		tt.Recirculation = new.Recirculation
+	}
+}
+
+// isEmpty is used to remove TopicTemplates with a PATCH request
+func (tt *TopicTemplates) isEmpty() bool {
+	for _, t := range tt.templates() {
+		if t.template != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (tt *TopicTemplates) templates() []struct{ name, template string } {
+	return []struct{ name, template string }{
+		{"water", tt.Water},
+		{"stop", tt.Stop},
+		{"stop_all", tt.StopAll},
+		{"light", tt.Light},
+		{"recirculation", tt.Recirculation},
+	}
+}
+
+// Topic executes the Garden's override template, if it has one, or returns the topic from the server's config
+func (g *Garden) Topic(override func(*TopicTemplates) string, configured func(string) (string, error)) (string, error) {
+	if g.TopicTemplates != nil {
+		if t := override(g.TopicTemplates); t != "" {
+			return mqtt.ExecuteTopicTemplate(t, g.TopicPrefix)
+		}
+	}
+	return configured(g.TopicPrefix)
+}
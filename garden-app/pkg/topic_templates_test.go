@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicTemplatesValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates *TopicTemplates
+		err       string
+	}{
+		{
+			"ValidTasmota",
+			&TopicTemplates{Light: "cmnd/{{.Garden}}/POWER1", Water: "cmnd/{{.Garden}}/Backlog"},
+			"",
+		},
+		{
+			"ValidStatic",
+			&TopicTemplates{StopAll: "opensprinkler/stop"},
+			"",
+		},
+		{
+			"ErrorInvalidTemplate",
+			&TopicTemplates{Water: "{{.Garden}/water"},
+			`invalid water template: template: topic:1: bad character U+007D '}'`,
+		},
+		{
+			"ErrorWildcard",
+			&TopicTemplates{Stop: "{{.Garden}}/+/stop"},
+			"invalid stop template: topic must not contain wildcards",
+		},
+		{
+			"ErrorEmptyTopic",
+			&TopicTemplates{Recirculation: "{{if false}}topic{{end}}"},
+			"invalid recirculation template: topic must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.templates.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestGardenTopic(t *testing.T) {
+	configured := func(topicPrefix string) (string, error) {
+		return topicPrefix + "/command/light", nil
+	}
+	light := func(tt *TopicTemplates) string { return tt.Light }
+
+	t.Run("Configured", func(t *testing.T) {
+		g := &Garden{TopicPrefix: "garden", TopicTemplates: &TopicTemplates{Water: "cmnd/{{.Garden}}/water"}}
+
+		topic, err := g.Topic(light, configured)
+		require.NoError(t, err)
+		assert.Equal(t, "garden/command/light", topic)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		g := &Garden{TopicPrefix: "garden", TopicTemplates: &TopicTemplates{Light: "cmnd/{{.Garden}}/POWER1"}}
+
+		topic, err := g.Topic(light, configured)
+		require.NoError(t, err)
+		assert.Equal(t, "cmnd/garden/POWER1", topic)
+	})
+}
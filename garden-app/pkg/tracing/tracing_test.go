@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setupRecorder uses a TracerProvider that records spans in memory for the duration of the test
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	_, err := Setup(Config{}, "")
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	original := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(original) })
+
+	return recorder
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(Config{}, "garden-app")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	t.Run("InjectIsEmptyWithoutProvider", func(t *testing.T) {
+		ctx, span := Start(context.Background(), "span")
+		defer span.End()
+
+		assert.Nil(t, Inject(ctx))
+	})
+}
+
+func TestInjectExtract(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	ctx, parent := Start(context.Background(), "parent")
+	traceContext := Inject(ctx)
+	parent.End()
+
+	require.Contains(t, traceContext, "traceparent")
+
+	ctx = Extract(context.Background(), traceContext)
+	_, child := Start(ctx, "child")
+	child.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Parent().SpanID())
+
+	t.Run("ExtractEmpty", func(t *testing.T) {
+		ctx := Extract(context.Background(), nil)
+		assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+	})
+}
+
+func TestEnd(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	_, span := Start(context.Background(), "success")
+	End(span, nil)
+
+	_, span = Start(context.Background(), "failure")
+	End(span, errors.New("oops"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "oops", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, "exception", spans[1].Events()[0].Name)
+}
@@ -0,0 +1,127 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/babyapi"
+)
+
+// UserRole is a User's permission level. Each Garden gives Users a role for itself and its Zones, Plants, and other
+// nested resources. The User's own role is used for everything else, like WaterSchedules
+type UserRole string
+
+const (
+	// UserRoleOwner can create, change, and delete resources
+	UserRoleOwner UserRole = "owner"
+	// UserRoleOperator can view resources and send actions
+	UserRoleOperator UserRole = "operator"
+	// UserRoleViewer can only view resources
+	UserRoleViewer UserRole = "viewer"
+)
+
+// Validate makes sure the UserRole is one of the valid roles
+func (r UserRole) Validate() error {
+	switch r {
+	case UserRoleOwner, UserRoleOperator, UserRoleViewer:
+		return nil
+	default:
+		return fmt.Errorf("invalid role %q: must be one of %q, %q, or %q", r, UserRoleOwner, UserRoleOperator, UserRoleViewer)
+	}
+}
+
+// Allows returns true if the UserRole permits requests that need the scope. The admin scope is never allowed by a
+// role, so only admin APITokens can manage APITokens and Users
+func (r UserRole) Allows(scope APITokenScope) bool {
+	switch scope {
+	case APITokenScopeRead:
+		return r == UserRoleViewer || r == UserRoleOperator || r == UserRoleOwner
+	case APITokenScopeActions:
+		return r == UserRoleOperator || r == UserRoleOwner
+	case APITokenScopeWrite:
+		return r == UserRoleOwner
+	default:
+		return false
+	}
+}
+
+// User is a person using this installation. Requests are made as a User when they use an APIToken with the User's
+// ID or log in with OIDC using the User's Email
+type User struct {
+	ID    babyapi.ID `json:"id" yaml:"id"`
+	Name  string     `json:"name" yaml:"name"`
+	Email string     `json:"email,omitempty" yaml:"email,omitempty"`
+	// Role is used for resources that don't belong to a Garden. Gardens give the User a role with their Users field
+	Role      UserRole   `json:"role" yaml:"role"`
+	CreatedAt *time.Time `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+}
+
+func (u *User) GetID() string {
+	return u.ID.String()
+}
+
+// String...
+func (u *User) String() string {
+	return fmt.Sprintf("%+v", *u)
+}
+
+func (u *User) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// Bind is used to make this struct compatible with the go-chi webserver for reading incoming
+// JSON requests
+func (u *User) Bind(r *http.Request) error {
+	if u == nil {
+		return errors.New("missing required User fields")
+	}
+
+	err := u.ID.Bind(r)
+	if err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		now := time.Now()
+		u.CreatedAt = &now
+		fallthrough
+	case http.MethodPut:
+		if u.Name == "" {
+			return validation.Missing("name")
+		}
+		if u.Role == "" {
+			return validation.Missing("role")
+		}
+	}
+
+	if u.Role != "" {
+		return u.Role.Validate()
+	}
+
+	return nil
+}
+
+// Patch allows modifying the name, email, and role
+func (u *User) Patch(newUser *User) *babyapi.ErrResponse {
+	if newUser.Name != "" {
+		u.Name = newUser.Name
+	}
+	if newUser.Email != "" {
+		u.Email = newUser.Email
+	}
+	if newUser.Role != "" {
+		u.Role = newUser.Role
+	}
+	return nil
+}
+
+// EndDated allows this to satisfy an interface even though the resources does not have end-dates
+func (*User) EndDated() bool {
+	return false
+}
+
+func (*User) SetEndDate(_ time.Time) {}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRoleAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     UserRole
+		scope    APITokenScope
+		expected bool
+	}{
+		{"ViewerCanRead", UserRoleViewer, APITokenScopeRead, true},
+		{"ViewerCannotSendActions", UserRoleViewer, APITokenScopeActions, false},
+		{"ViewerCannotWrite", UserRoleViewer, APITokenScopeWrite, false},
+		{"OperatorCanRead", UserRoleOperator, APITokenScopeRead, true},
+		{"OperatorCanSendActions", UserRoleOperator, APITokenScopeActions, true},
+		{"OperatorCannotWrite", UserRoleOperator, APITokenScopeWrite, false},
+		{"OwnerCanWrite", UserRoleOwner, APITokenScopeWrite, true},
+		{"OwnerIsNotAdmin", UserRoleOwner, APITokenScopeAdmin, false},
+		{"NoRole", "", APITokenScopeRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.role.Allows(tt.scope))
+		})
+	}
+}
+
+func TestUserBind(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		user        *User
+		expectedErr string
+	}{
+		{
+			"Valid",
+			http.MethodPost,
+			&User{Name: "Sam", Role: UserRoleOperator},
+			"",
+		},
+		{
+			"MissingName",
+			http.MethodPost,
+			&User{Role: UserRoleOperator},
+			"missing required name field",
+		},
+		{
+			"MissingRole",
+			http.MethodPut,
+			&User{ID: babyapi.NewID(), Name: "Sam"},
+			"missing required role field",
+		},
+		{
+			"InvalidRole",
+			http.MethodPatch,
+			&User{Role: "gardener"},
+			`invalid role "gardener": must be one of "owner", "operator", or "viewer"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(tt.method, "/users/c5cvhpcbcv45e8bp16dg", nil)
+			err := tt.user.Bind(r)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
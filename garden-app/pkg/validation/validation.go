@@ -0,0 +1,38 @@
+package validation
+
+import "fmt"
+
+// Error codes are stable so API clients can handle validation errors without parsing the message
+const (
+	CodeRequired = "required"
+	CodeInvalid  = "invalid"
+)
+
+// Error is returned when a resource field is missing or invalid. Field is the field's JSON name
+type Error struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Missing creates an Error for a required field that is not set
+func Missing(field string) error {
+	return &Error{
+		Field:   field,
+		Code:    CodeRequired,
+		Message: fmt.Sprintf("missing required %s field", field),
+	}
+}
+
+// Invalid creates an Error for a field with an invalid value
+func Invalid(field, format string, args ...any) error {
+	return &Error{
+		Field:   field,
+		Code:    CodeInvalid,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,46 @@
+package pkg
+
+import (
+	"errors"
+)
+
+// WaterBudget limits the liters that a Garden's Zones can use in a rolling Period, like 500 liters each week.
+// Usage comes from flow meters or is estimated with flow rates, so Zones without either are not limited until the
+// budget is used up by other Zones. When a watering would exceed the budget, it is skipped, or ScaleDown reduces
+// it to the remaining liters instead
+type WaterBudget struct {
+	MaxLiters float64   `json:"max_liters" yaml:"max_liters"`
+	Period    *Duration `json:"period" yaml:"period"`
+	ScaleDown *bool     `json:"scale_down,omitempty" yaml:"scale_down,omitempty"`
+}
+
+// Validate makes sure the WaterBudget has a positive MaxLiters and Period
+func (wb *WaterBudget) Validate() error {
+	if wb.MaxLiters <= 0 {
+		return errors.New("max_liters must be greater than 0")
+	}
+	return wb.Period.ValidatePositive("period")
+}
+
+// Patch updates the fields that are set in the new WaterBudget
+func (wb *WaterBudget) Patch(new *WaterBudget) {
+	if new.MaxLiters != 0 {
+		wb.MaxLiters = new.MaxLiters
+	}
+	if new.Period != nil {
+		wb.Period = new.Period
+	}
+	if new.ScaleDown != nil {
+		wb.ScaleDown = new.ScaleDown
+	}
+}
+
+// isEmpty is used to remove a WaterBudget with a PATCH request
+func (wb *WaterBudget) isEmpty() bool {
+	return wb.MaxLiters == 0 && wb.Period == nil && wb.ScaleDown == nil
+}
+
+// ScalesDown returns true if waterings that would exceed the budget are reduced instead of skipped
+func (wb *WaterBudget) ScalesDown() bool {
+	return wb.ScaleDown != nil && *wb.ScaleDown
+}
@@ -0,0 +1,32 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaterBudgetValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget *WaterBudget
+		err    string
+	}{
+		{"Valid", &WaterBudget{MaxLiters: 500, Period: &Duration{Duration: 168 * time.Hour}}, ""},
+		{"ErrorMissingMaxLiters", &WaterBudget{Period: &Duration{Duration: time.Hour}}, "max_liters must be greater than 0"},
+		{"ErrorMissingPeriod", &WaterBudget{MaxLiters: 500}, "missing required period field"},
+		{"ErrorCronPeriod", &WaterBudget{MaxLiters: 500, Period: &Duration{Cron: "0 0 * * 1"}}, "period must be a positive duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.budget.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
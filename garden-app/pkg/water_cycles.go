@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"errors"
+	"time"
+)
+
+// WaterCycles splits a watering into Count pulses of the Water duration with Soak time between them. This gives
+// the water time to soak into soil, like clay, that would otherwise cause runoff
+type WaterCycles struct {
+	Count uint      `json:"count" yaml:"count"`
+	Water *Duration `json:"water" yaml:"water"`
+	Soak  *Duration `json:"soak" yaml:"soak"`
+}
+
+// Validate makes sure there is more than one cycle, Water is positive, and Soak is not negative
+func (wc *WaterCycles) Validate() error {
+	if wc.Count < 2 {
+		return errors.New("count must be at least 2")
+	}
+	err := wc.Water.ValidatePositive("water")
+	if err != nil {
+		return err
+	}
+	return wc.Soak.ValidateNotNegative("soak")
+}
+
+// TotalWater returns the total time spent watering for all of the cycles
+func (wc *WaterCycles) TotalWater() time.Duration {
+	return time.Duration(wc.Count) * wc.Water.Duration
+}
+
+// TotalDuration returns the time from the start of the first pulse to the end of the last one, including soaking
+func (wc *WaterCycles) TotalDuration() time.Duration {
+	return wc.TotalWater() + time.Duration(wc.Count-1)*wc.Soak.Duration
+}
+
+// Pulse splits the total watering duration evenly into each cycle. This is used after a duration is scaled so each
+// pulse is scaled equally
+func (wc *WaterCycles) Pulse(total time.Duration) time.Duration {
+	return total / time.Duration(wc.Count)
+}
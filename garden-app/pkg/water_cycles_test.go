@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaterCyclesValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cycles WaterCycles
+		err    string
+	}{
+		{
+			"Valid",
+			WaterCycles{Count: 3, Water: &Duration{Duration: 2 * time.Minute}, Soak: &Duration{Duration: 10 * time.Minute}},
+			"",
+		},
+		{
+			"ValidWithoutSoaking",
+			WaterCycles{Count: 2, Water: &Duration{Duration: 2 * time.Minute}, Soak: &Duration{}},
+			"",
+		},
+		{
+			"ErrorSingleCycle",
+			WaterCycles{Count: 1, Water: &Duration{Duration: 2 * time.Minute}, Soak: &Duration{Duration: 10 * time.Minute}},
+			"count must be at least 2",
+		},
+		{
+			"ErrorMissingWater",
+			WaterCycles{Count: 3, Soak: &Duration{Duration: 10 * time.Minute}},
+			"missing required water field",
+		},
+		{
+			"ErrorZeroWater",
+			WaterCycles{Count: 3, Water: &Duration{}, Soak: &Duration{Duration: 10 * time.Minute}},
+			"water must be a positive duration",
+		},
+		{
+			"ErrorMissingSoak",
+			WaterCycles{Count: 3, Water: &Duration{Duration: 2 * time.Minute}},
+			"missing required soak field",
+		},
+		{
+			"ErrorNegativeSoak",
+			WaterCycles{Count: 3, Water: &Duration{Duration: 2 * time.Minute}, Soak: &Duration{Duration: -time.Minute}},
+			"soak must not be a negative duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cycles.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestWaterCyclesDurations(t *testing.T) {
+	cycles := WaterCycles{Count: 3, Water: &Duration{Duration: 2 * time.Minute}, Soak: &Duration{Duration: 10 * time.Minute}}
+
+	assert.Equal(t, 6*time.Minute, cycles.TotalWater())
+	assert.Equal(t, 26*time.Minute, cycles.TotalDuration())
+	assert.Equal(t, time.Minute, cycles.Pulse(3*time.Minute))
+}
@@ -0,0 +1,72 @@
+package pkg
+
+import (
+	"errors"
+	"time"
+)
+
+// WaterPricing configures the costs of water and electricity for a Garden. Since most Gardens do not have a
+// flow meter or power monitoring, the FlowRate and PumpPower are used to estimate usage from watering durations
+type WaterPricing struct {
+	Currency     string  `json:"currency,omitempty" yaml:"currency,omitempty"`
+	CostPerLiter float64 `json:"cost_per_liter,omitempty" yaml:"cost_per_liter,omitempty"`
+	CostPerKWh   float64 `json:"cost_per_kwh,omitempty" yaml:"cost_per_kwh,omitempty"`
+	// FlowRate is the liters per minute delivered while a Zone is watering
+	FlowRate float64 `json:"flow_rate_lpm,omitempty" yaml:"flow_rate_lpm,omitempty"`
+	// PumpPower is the power used by the pump, in watts, while a Zone is watering
+	PumpPower float64 `json:"pump_power_watts,omitempty" yaml:"pump_power_watts,omitempty"`
+}
+
+// Validate makes sure none of the values are negative
+func (p *WaterPricing) Validate() error {
+	if p.CostPerLiter < 0 {
+		return errors.New("cost_per_liter must not be negative")
+	}
+	if p.CostPerKWh < 0 {
+		return errors.New("cost_per_kwh must not be negative")
+	}
+	if p.FlowRate < 0 {
+		return errors.New("flow_rate_lpm must not be negative")
+	}
+	if p.PumpPower < 0 {
+		return errors.New("pump_power_watts must not be negative")
+	}
+	return nil
+}
+
+// Patch allows modifying the struct in-place with values from a different instance
+func (p *WaterPricing) Patch(newPricing *WaterPricing) {
+	if newPricing.Currency != "" {
+		p.Currency = newPricing.Currency
+	}
+	if newPricing.CostPerLiter != 0 {
+		p.CostPerLiter = newPricing.CostPerLiter
+	}
+	if newPricing.CostPerKWh != 0 {
+		p.CostPerKWh = newPricing.CostPerKWh
+	}
+	if newPricing.FlowRate != 0 {
+		p.FlowRate = newPricing.FlowRate
+	}
+	if newPricing.PumpPower != 0 {
+		p.PumpPower = newPricing.PumpPower
+	}
+}
+
+// EstimateUsage uses the FlowRate and PumpPower to estimate the liters of water and kWh of energy used
+// when watering for the duration
+func (p *WaterPricing) EstimateUsage(d time.Duration) (float64, float64) {
+	liters := d.Minutes() * p.FlowRate
+	kWh := d.Hours() * p.PumpPower / 1000
+	return liters, kWh
+}
+
+// WaterCost returns the cost of the liters of water
+func (p *WaterPricing) WaterCost(liters float64) float64 {
+	return liters * p.CostPerLiter
+}
+
+// EnergyCost returns the cost of the kWh of energy
+func (p *WaterPricing) EnergyCost(kWh float64) float64 {
+	return kWh * p.CostPerKWh
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
 	"github.com/calvinmclean/babyapi"
 	"github.com/rs/xid"
@@ -14,6 +16,8 @@ import (
 // WaterSchedule allows the user to have more control over how the Zone is watered using an Interval
 // and optional MinimumMoisture which acts as the threshold the Zone's soil should be above.
 // StartTime specifies when the watering interval should originate from. It can be used to increase/decrease delays in watering.
+// The Interval can also be a cron expression, like "cron:0 6 * * 1,3,5", and then StartTime is not required since the
+// expression controls the time
 type WaterSchedule struct {
 	ID             babyapi.ID       `json:"id" yaml:"id"`
 	Duration       *Duration        `json:"duration" yaml:"duration"`
@@ -25,6 +29,17 @@ type WaterSchedule struct {
 	Name           string           `json:"name,omitempty" yaml:"name,omitempty"`
 	Description    string           `json:"description,omitempty" yaml:"description,omitempty"`
 	ActivePeriod   *ActivePeriod    `json:"active_period,omitempty" yaml:"active_period,omitempty"`
+	// MinDuration and MaxDuration limit the duration after it is scaled by WeatherControl or the Zone
+	MinDuration *Duration `json:"min_duration,omitempty" yaml:"min_duration,omitempty"`
+	MaxDuration *Duration `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`
+	// Paused WaterSchedules are not scheduled, but they keep their Zones so they can be resumed later
+	Paused bool `json:"paused,omitempty" yaml:"paused,omitempty"`
+	// SkipCount is the number of upcoming scheduled waterings that will be skipped
+	SkipCount uint `json:"skip_count,omitempty" yaml:"skip_count,omitempty"`
+	// Cycles waters in pulses with soaking time between them. The Duration is set to the total time watering
+	Cycles *WaterCycles `json:"cycles,omitempty" yaml:"cycles,omitempty"`
+	// Fertilizer is injected by the Zone's dosing pump during scheduled waterings
+	Fertilizer *FertilizerSchedule `json:"fertilizer,omitempty" yaml:"fertilizer,omitempty"`
 }
 
 func (ws *WaterSchedule) GetID() string {
@@ -38,18 +53,45 @@ func (ws *WaterSchedule) String() string {
 
 // EndDated returns true if the WaterSchedule is end-dated
 func (ws *WaterSchedule) EndDated() bool {
-	return ws.EndDate != nil && ws.EndDate.Before(time.Now())
+	return ws.EndDatedAt(time.Now())
+}
+
+// EndDatedAt returns true if the WaterSchedule is end-dated at the specified time
+func (ws *WaterSchedule) EndDatedAt(t time.Time) bool {
+	return ws.EndDate != nil && ws.EndDate.Before(t)
 }
 
 func (ws *WaterSchedule) SetEndDate(now time.Time) {
 	ws.EndDate = &now
 }
 
+// Restore removes the EndDate so the WaterSchedule is active again
+func (ws *WaterSchedule) Restore() {
+	ws.EndDate = nil
+}
+
+// Scheduled returns true if the WaterSchedule should have a scheduled Job because it is not end-dated or paused
+func (ws *WaterSchedule) Scheduled() bool {
+	return !ws.EndDated() && !ws.Paused
+}
+
+// HasCronInterval returns true if the Interval is a cron expression instead of a duration
+func (ws *WaterSchedule) HasCronInterval() bool {
+	return ws.Interval != nil && ws.Interval.Cron != ""
+}
+
+// NotStartedAt returns true if t is before the StartDate of a WaterSchedule with a cron Interval. This is not needed
+// for other Intervals since their scheduled Job starts on the StartDate
+func (ws *WaterSchedule) NotStartedAt(t time.Time) bool {
+	return ws.HasCronInterval() && ws.StartDate != nil && t.Before(*ws.StartDate)
+}
+
 // HasWeatherControl is used to determine if weather conditions should be checked before watering the Zone
 // This checks that WeatherControl is defined and has at least one type of control configured
 func (ws *WaterSchedule) HasWeatherControl() bool {
 	return ws != nil &&
-		(ws.HasRainControl() || ws.HasSoilMoistureControl() || ws.HasTemperatureControl())
+		(ws.HasRainControl() || ws.HasSoilMoistureControl() || ws.HasTemperatureControl() || ws.HasRainForecastControl() ||
+			ws.HasFrostControl())
 }
 
 // Patch allows modifying the struct in-place with values from a different instance
@@ -87,16 +129,98 @@ func (ws *WaterSchedule) Patch(new *WaterSchedule) *babyapi.ErrResponse {
 		}
 		ws.ActivePeriod.Patch(new.ActivePeriod)
 	}
+	if new.MinDuration != nil {
+		ws.MinDuration = new.MinDuration
+	}
+	if new.MaxDuration != nil {
+		ws.MaxDuration = new.MaxDuration
+	}
+	if new.Cycles != nil {
+		ws.Cycles = new.Cycles
+	}
+	if new.Fertilizer != nil {
+		ws.Fertilizer = new.Fertilizer
+	}
+	if ws.Cycles != nil {
+		ws.Duration = &Duration{Duration: ws.Cycles.TotalWater()}
+	}
+
+	return nil
+}
+
+// ValidateDurationLimits makes sure MinDuration and MaxDuration are positive and MinDuration is not greater
+// than MaxDuration
+func (ws *WaterSchedule) ValidateDurationLimits() error {
+	limits := []struct {
+		name string
+		d    *Duration
+	}{
+		{"min_duration", ws.MinDuration},
+		{"max_duration", ws.MaxDuration},
+	}
+	for _, limit := range limits {
+		if limit.d == nil {
+			continue
+		}
+		if limit.d.Cron != "" {
+			return fmt.Errorf("%s cannot use a cron expression", limit.name)
+		}
+		if limit.d.Duration <= 0 {
+			return fmt.Errorf("%s must be a positive duration", limit.name)
+		}
+	}
+
+	if ws.MinDuration != nil && ws.MaxDuration != nil && ws.MinDuration.Duration > ws.MaxDuration.Duration {
+		return errors.New("min_duration must not be greater than max_duration")
+	}
 
 	return nil
 }
 
+// ClampDuration limits a scaled duration to the MinDuration and MaxDuration. A duration of 0 means that watering
+// is skipped, so it is not changed
+func (ws *WaterSchedule) ClampDuration(d time.Duration) time.Duration {
+	if d == 0 {
+		return 0
+	}
+	if ws.MinDuration != nil && d < ws.MinDuration.Duration {
+		d = ws.MinDuration.Duration
+	}
+	if ws.MaxDuration != nil && d > ws.MaxDuration.Duration {
+		d = ws.MaxDuration.Duration
+	}
+	return d
+}
+
 // HasRainControl is used to determine if rain conditions should be checked before watering the Zone
 func (ws *WaterSchedule) HasRainControl() bool {
 	return ws.WeatherControl != nil &&
 		ws.WeatherControl.Rain != nil
 }
 
+// WithoutRainControl returns a copy of the WaterSchedule without its RainControl and RainForecastControl. The
+// WaterSchedule is returned if it does not have either
+func (ws *WaterSchedule) WithoutRainControl() *WaterSchedule {
+	if ws == nil || (!ws.HasRainControl() && !ws.HasRainForecastControl()) {
+		return ws
+	}
+
+	result := *ws
+	wc := *ws.WeatherControl
+	wc.Rain = nil
+	wc.RainForecast = nil
+	result.WeatherControl = &wc
+	return &result
+}
+
+// ForZone returns the WaterSchedule used to water the Zone, which does not use rain controls if the Zone skips them
+func (ws *WaterSchedule) ForZone(z *Zone) *WaterSchedule {
+	if z.SkipsRainControl() {
+		return ws.WithoutRainControl()
+	}
+	return ws
+}
+
 // HasSoilMoistureControl is used to determine if soil moisture conditions should be checked before watering the Zone
 func (ws *WaterSchedule) HasSoilMoistureControl() bool {
 	return ws.WeatherControl != nil &&
@@ -110,6 +234,18 @@ func (ws *WaterSchedule) HasTemperatureControl() bool {
 		ws.WeatherControl.Temperature != nil
 }
 
+// HasRainForecastControl is used to determine if forecasted rain should be checked before watering the Zone
+func (ws *WaterSchedule) HasRainForecastControl() bool {
+	return ws.WeatherControl != nil &&
+		ws.WeatherControl.RainForecast != nil
+}
+
+// HasFrostControl is used to determine if the forecasted low temperature should be checked before watering the Zone
+func (ws *WaterSchedule) HasFrostControl() bool {
+	return ws.WeatherControl != nil &&
+		ws.WeatherControl.Frost != nil
+}
+
 // IsActive determines if the WaterSchedule is currently in it's ActivePeriod. Always true if no ActivePeriod is configured
 func (ws *WaterSchedule) IsActive(now time.Time) bool {
 	if ws.ActivePeriod == nil {
@@ -119,72 +255,143 @@ func (ws *WaterSchedule) IsActive(now time.Time) bool {
 	// Run validate to make sure start/end values are set. No chance of error since validation has already happened
 	_ = ws.ActivePeriod.Validate()
 
-	// Set current year to this year for easy comparison
-	ws.ActivePeriod.start = ws.ActivePeriod.start.AddDate(now.Year(), 0, 0)
-	ws.ActivePeriod.end = ws.ActivePeriod.end.AddDate(now.Year(), 0, 0)
+	current := newMonthDay(now)
 
 	// Handle wraparound dates like December -> February (Winter)
-	// If the period starts before now, we need to bump the end time by a year, otherwise
-	// the start period needs to be last year
-	if ws.ActivePeriod.start.After(ws.ActivePeriod.end) {
-		if ws.ActivePeriod.start.Before(now) {
-			ws.ActivePeriod.end = ws.ActivePeriod.end.AddDate(1, 0, 0)
-		} else {
-			ws.ActivePeriod.start = ws.ActivePeriod.start.AddDate(-1, 0, 0)
-		}
+	if ws.ActivePeriod.start > ws.ActivePeriod.end {
+		return current >= ws.ActivePeriod.start || current <= ws.ActivePeriod.end
 	}
 
-	return now.Month() == ws.ActivePeriod.start.Month() || // currently start month
-		now.Month() == ws.ActivePeriod.end.Month() || // currently end month
-		(now.After(ws.ActivePeriod.start) && now.Before(ws.ActivePeriod.end)) // somewhere in-between
+	return current >= ws.ActivePeriod.start && current <= ws.ActivePeriod.end
 }
 
-// ActivePeriod contains the start and end months for when a WaterSchedule should be considered active. Both of these constraints are inclusive
+// ActivePeriod contains the start and end of when a WaterSchedule should be considered active. Both of these constraints
+// are inclusive. It is configured with either months (StartMonth and EndMonth) or month-day values in "MM-DD" format
+// (StartDate and EndDate) for more precise control, like a growing season from "04-15" to "10-31"
 type ActivePeriod struct {
-	StartMonth string `json:"start_month" yaml:"start_month"`
-	EndMonth   string `json:"end_month" yaml:"end_month"`
+	StartMonth string `json:"start_month,omitempty" yaml:"start_month,omitempty"`
+	EndMonth   string `json:"end_month,omitempty" yaml:"end_month,omitempty"`
+
+	StartDate string `json:"start_date,omitempty" yaml:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty" yaml:"end_date,omitempty"`
 
-	start time.Time
-	end   time.Time
+	start monthDay
+	end   monthDay
 }
 
-// Validate parses the Month strings to make sure they are valid
+const monthDayFormat = "01-02"
+
+// monthDay is a comparable representation of a day in the year, ignoring the year itself
+type monthDay int
+
+func newMonthDay(t time.Time) monthDay {
+	return monthDay(int(t.Month())*100 + t.Day())
+}
+
+// Validate parses the Month or Date strings to make sure they are valid
 func (ap *ActivePeriod) Validate() error {
 	if ap == nil {
 		return nil
 	}
 
-	var err error
-	ap.start, err = time.Parse("January", ap.StartMonth)
+	if ap.StartDate != "" || ap.EndDate != "" {
+		return ap.validateDates()
+	}
+
+	start, err := time.Parse("January", ap.StartMonth)
 	if err != nil {
 		return fmt.Errorf("invalid StartMonth: %w", err)
 	}
-	ap.end, err = time.Parse("January", ap.EndMonth)
+	end, err := time.Parse("January", ap.EndMonth)
 	if err != nil {
 		return fmt.Errorf("invalid EndMonth: %w", err)
 	}
 
-	if ap.start.Month() == ap.end.Month() {
+	if start.Month() == end.Month() {
 		return fmt.Errorf("StartMonth and EndMonth must be different")
 	}
 
+	// Months include every day, so the end is the last possible day of the month
+	ap.start = newMonthDay(start)
+	ap.end = monthDay(int(end.Month())*100 + 31)
+
 	return nil
 }
 
-// Patch allows for easily updating/editing an ActivePeriod
+func (ap *ActivePeriod) validateDates() error {
+	if ap.StartMonth != "" || ap.EndMonth != "" {
+		return errors.New("StartMonth and EndMonth cannot be used with StartDate and EndDate")
+	}
+
+	// Use a leap year so February 29 is allowed
+	start, err := time.Parse("2006-"+monthDayFormat, "2000-"+ap.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid StartDate %q: expected format MM-DD", ap.StartDate)
+	}
+	end, err := time.Parse("2006-"+monthDayFormat, "2000-"+ap.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid EndDate %q: expected format MM-DD", ap.EndDate)
+	}
+
+	ap.start = newMonthDay(start)
+	ap.end = newMonthDay(end)
+
+	if ap.start == ap.end {
+		return errors.New("StartDate and EndDate must be different")
+	}
+
+	return nil
+}
+
+// IsEmpty returns true if none of the start or end values are set
+func (ap *ActivePeriod) IsEmpty() bool {
+	return ap.StartMonth == "" && ap.EndMonth == "" && ap.StartDate == "" && ap.EndDate == ""
+}
+
+// String returns a short representation of the ActivePeriod, like "Apr - Oct" or "04-15 - 10-31"
+func (ap *ActivePeriod) String() string {
+	if ap.StartDate != "" || ap.EndDate != "" {
+		return fmt.Sprintf("%s - %s", ap.StartDate, ap.EndDate)
+	}
+	return fmt.Sprintf("%s - %s", shortMonth(ap.StartMonth), shortMonth(ap.EndMonth))
+}
+
+func shortMonth(month string) string {
+	if len(month) < 3 {
+		return month
+	}
+	return month[0:3]
+}
+
+// Patch allows for easily updating/editing an ActivePeriod. Since months and dates cannot be used together,
+// setting one of them will clear the other
 func (ap *ActivePeriod) Patch(new *ActivePeriod) {
+	if new.StartMonth != "" || new.EndMonth != "" {
+		ap.StartDate = ""
+		ap.EndDate = ""
+	}
+	if new.StartDate != "" || new.EndDate != "" {
+		ap.StartMonth = ""
+		ap.EndMonth = ""
+	}
 	if new.StartMonth != "" {
 		ap.StartMonth = new.StartMonth
 	}
 	if new.EndMonth != "" {
 		ap.EndMonth = new.EndMonth
 	}
+	if new.StartDate != "" {
+		ap.StartDate = new.StartDate
+	}
+	if new.EndDate != "" {
+		ap.EndDate = new.EndDate
+	}
 }
 
 // NextWaterDetails has information about the next time this WaterSchedule will be used
 type NextWaterDetails struct {
 	Time            *time.Time `json:"time,omitempty"`
-	Duration        string     `json:"duration,omitempty"`
+	Duration        *Duration  `json:"duration,omitempty"`
 	WaterScheduleID *xid.ID    `json:"water_schedule_id,omitempty"`
 	Message         string     `json:"message,omitempty"`
 }
@@ -224,13 +431,20 @@ func (ws *WaterSchedule) Bind(r *http.Request) error {
 	switch r.Method {
 	case http.MethodPut, http.MethodPost:
 		if ws.Interval == nil {
-			return errors.New("missing required interval field")
+			return validation.Missing("interval")
+		}
+		if ws.Cycles != nil {
+			err := ws.Cycles.Validate()
+			if err != nil {
+				return fmt.Errorf("error validating cycles: %w", err)
+			}
+			ws.Duration = &Duration{Duration: ws.Cycles.TotalWater()}
 		}
 		if ws.Duration == nil {
-			return errors.New("missing required duration field")
+			return validation.Missing("duration")
 		}
-		if ws.StartTime == nil {
-			return errors.New("missing required start_time field")
+		if ws.StartTime == nil && !ws.HasCronInterval() {
+			return validation.Missing("start_time")
 		}
 		// If StartDate is not included, default to today
 		if ws.StartDate == nil {
@@ -245,7 +459,7 @@ func (ws *WaterSchedule) Bind(r *http.Request) error {
 		}
 		if ws.ActivePeriod != nil {
 			// Allow removing active period by setting empty for each. This is useful for HTML form
-			if ws.ActivePeriod.StartMonth == "" && ws.ActivePeriod.EndMonth == "" {
+			if ws.ActivePeriod.IsEmpty() {
 				ws.ActivePeriod = nil
 			}
 		}
@@ -253,6 +467,12 @@ func (ws *WaterSchedule) Bind(r *http.Request) error {
 		if ws.EndDate != nil {
 			return errors.New("to end-date a WaterSchedule, please use the DELETE endpoint")
 		}
+		if ws.Cycles != nil {
+			err := ws.Cycles.Validate()
+			if err != nil {
+				return fmt.Errorf("error validating cycles: %w", err)
+			}
+		}
 	}
 
 	if ws.ActivePeriod != nil {
@@ -262,6 +482,13 @@ func (ws *WaterSchedule) Bind(r *http.Request) error {
 		}
 	}
 
+	if ws.Fertilizer != nil {
+		err := ws.Fertilizer.Validate()
+		if err != nil {
+			return fmt.Errorf("error validating fertilizer: %w", err)
+		}
+	}
+
 	if ws.StartTime != nil {
 		err = ws.StartTime.Validate()
 		if err != nil {
@@ -269,6 +496,11 @@ func (ws *WaterSchedule) Bind(r *http.Request) error {
 		}
 	}
 
+	err = ws.ValidateDurationLimits()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -286,6 +518,18 @@ func ValidateWeatherControl(wc *weather.Control) error {
 			return fmt.Errorf("error validating rain_control: %w", err)
 		}
 	}
+	if wc.RainForecast != nil {
+		err := ValidateRainForecastControl(wc.RainForecast)
+		if err != nil {
+			return fmt.Errorf("error validating rain_forecast_control: %w", err)
+		}
+	}
+	if wc.Frost != nil {
+		err := ValidateFrostControl(wc.Frost)
+		if err != nil {
+			return fmt.Errorf("error validating frost_control: %w", err)
+		}
+	}
 	if wc.SoilMoisture != nil {
 		if wc.SoilMoisture.MinimumMoisture == nil {
 			return errors.New("error validating moisture_control: missing required field: minimum_moisture")
@@ -294,6 +538,59 @@ func ValidateWeatherControl(wc *weather.Control) error {
 	return nil
 }
 
+// ValidateRainForecastControl validates input for RainForecastControl
+func ValidateRainForecastControl(rc *weather.RainForecastControl) error {
+	errStringFormat := "missing required field: %s"
+	if rc.Threshold == nil {
+		return fmt.Errorf(errStringFormat, "threshold")
+	}
+	if *rc.Threshold < float32(0) {
+		return errors.New("threshold must be a positive number")
+	}
+	if rc.HoursAhead == nil {
+		return fmt.Errorf(errStringFormat, "hours_ahead")
+	}
+	if *rc.HoursAhead <= 0 {
+		return errors.New("hours_ahead must be a positive number")
+	}
+	if rc.ClientID.IsNil() {
+		return fmt.Errorf(errStringFormat, "client_id")
+	}
+	return validateOnError(rc.OnError)
+}
+
+// ValidateFrostControl validates input for FrostControl. ProtectMinutes is only required in protect mode
+func ValidateFrostControl(fc *weather.FrostControl) error {
+	errStringFormat := "missing required field: %s"
+	switch fc.Mode {
+	case "":
+		return fmt.Errorf(errStringFormat, "mode")
+	case weather.FrostModeProtect:
+		if fc.ProtectMinutes == nil {
+			return fmt.Errorf(errStringFormat, "protect_minutes")
+		}
+		if *fc.ProtectMinutes <= 0 {
+			return errors.New("protect_minutes must be a positive number")
+		}
+	case weather.FrostModeInhibit:
+	default:
+		return fmt.Errorf("invalid mode %q: must be one of %q or %q", fc.Mode, weather.FrostModeProtect, weather.FrostModeInhibit)
+	}
+	if fc.Threshold == nil {
+		return fmt.Errorf(errStringFormat, "threshold")
+	}
+	if fc.HoursAhead == nil {
+		return fmt.Errorf(errStringFormat, "hours_ahead")
+	}
+	if *fc.HoursAhead <= 0 {
+		return errors.New("hours_ahead must be a positive number")
+	}
+	if fc.ClientID.IsNil() {
+		return fmt.Errorf(errStringFormat, "client_id")
+	}
+	return validateOnError(fc.OnError)
+}
+
 // ValidateScaleControl validates input for ScaleControl
 func ValidateScaleControl(sc *weather.ScaleControl) error {
 	errStringFormat := "missing required field: %s"
@@ -315,5 +612,128 @@ func ValidateScaleControl(sc *weather.ScaleControl) error {
 	if sc.ClientID.IsNil() {
 		return fmt.Errorf(errStringFormat, "client_id")
 	}
+	return validateOnError(sc.OnError)
+}
+
+// validateOnError makes sure a control's OnError policy is valid. It is optional and defaults to
+// weather.OnErrorWaterUnscaled
+func validateOnError(onError string) error {
+	if onError != "" && !slices.Contains(weather.OnErrorPolicies, onError) {
+		return fmt.Errorf(
+			"invalid on_error %q: must be one of %q, %q, or %q",
+			onError, weather.OnErrorWaterUnscaled, weather.OnErrorSkip, weather.OnErrorFail,
+		)
+	}
+	return nil
+}
+
+// conflictHorizon is how far ahead WaterSchedules are compared when checking for conflicts. A full year is used so
+// ActivePeriods are always considered
+const conflictHorizon = 366 * 24 * time.Hour
+
+// firstWaterTime returns the first time that this WaterSchedule will water, based on the StartDate and StartTime
+func (ws *WaterSchedule) firstWaterTime() time.Time {
+	date := time.Now()
+	if ws.StartDate != nil {
+		date = *ws.StartDate
+	}
+	startTime := ws.StartTime.Time
+	date = date.In(startTime.Location())
+	return time.Date(date.Year(), date.Month(), date.Day(), startTime.Hour(), startTime.Minute(), startTime.Second(), 0, startTime.Location())
+}
+
+// canCheckConflicts returns true if the WaterSchedule has all of the fields needed to calculate watering times.
+// Cron intervals are not supported
+func (ws *WaterSchedule) canCheckConflicts() bool {
+	return ws.Duration != nil && ws.Interval != nil && ws.StartTime != nil &&
+		ws.Interval.Cron == "" && ws.Interval.Duration > 0
+}
+
+// wateringSpan returns how long each watering lasts, including the soaking time between Cycles
+func (ws *WaterSchedule) wateringSpan() time.Duration {
+	if ws.Cycles != nil {
+		return ws.Cycles.TotalDuration()
+	}
+	return ws.Duration.Duration
+}
+
+// Conflict returns the first time that watering from this WaterSchedule overlaps with watering from the other
+// WaterSchedule while both are active. It returns nil if they do not conflict or if either uses a cron interval
+func (ws *WaterSchedule) Conflict(other *WaterSchedule) *time.Time {
+	if !ws.canCheckConflicts() || !other.canCheckConflicts() {
+		return nil
+	}
+
+	a, b := ws.firstWaterTime(), other.firstWaterTime()
+
+	// Skip ahead to the last watering before the other one starts so old StartDates don't require many iterations
+	if a.Before(b) {
+		a = a.Add(b.Sub(a) / ws.Interval.Duration * ws.Interval.Duration).Add(-ws.Interval.Duration)
+	} else {
+		b = b.Add(a.Sub(b) / other.Interval.Duration * other.Interval.Duration).Add(-other.Interval.Duration)
+	}
+
+	end := a.Add(conflictHorizon)
+	if b.After(a) {
+		end = b.Add(conflictHorizon)
+	}
+
+	for a.Before(end) && b.Before(end) {
+		aEnd := a.Add(ws.wateringSpan())
+		bEnd := b.Add(other.wateringSpan())
+
+		if a.Before(bEnd) && b.Before(aEnd) && ws.IsActive(a) && other.IsActive(b) {
+			result := a
+			if b.After(a) {
+				result = b
+			}
+			return &result
+		}
+
+		if aEnd.Before(bEnd) {
+			a = a.Add(ws.Interval.Duration)
+		} else {
+			b = b.Add(other.Interval.Duration)
+		}
+	}
+
+	return nil
+}
+
+// ValidateNoConflicts checks each pair of WaterSchedules and returns an error for the first one that overlaps.
+// End-dated and paused WaterSchedules are ignored since they do not water
+func ValidateNoConflicts(waterSchedules []*WaterSchedule) error {
+	for i, ws := range waterSchedules {
+		if !ws.Scheduled() {
+			continue
+		}
+		for _, other := range waterSchedules[i+1:] {
+			if !other.Scheduled() || ws.ID == other.ID {
+				continue
+			}
+			conflict := ws.Conflict(other)
+			if conflict != nil {
+				return fmt.Errorf("WaterSchedules %q and %q overlap at %s", ws.GetID(), other.GetID(), conflict.Format(time.RFC3339))
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateNoConflictsWith returns an error for the first of the other WaterSchedules that overlaps with this one.
+// Unlike ValidateNoConflicts, existing overlaps between the other WaterSchedules are not checked
+func (ws *WaterSchedule) ValidateNoConflictsWith(others []*WaterSchedule) error {
+	if !ws.Scheduled() {
+		return nil
+	}
+	for _, other := range others {
+		if !other.Scheduled() || ws.ID == other.ID {
+			continue
+		}
+		conflict := ws.Conflict(other)
+		if conflict != nil {
+			return fmt.Errorf("WaterSchedules %q and %q overlap at %s", ws.GetID(), other.GetID(), conflict.Format(time.RFC3339))
+		}
+	}
 	return nil
 }
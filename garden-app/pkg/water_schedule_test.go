@@ -33,6 +33,46 @@ func TestWaterScheduleEndDated(t *testing.T) {
 	}
 }
 
+func TestWaterScheduleScheduled(t *testing.T) {
+	pastDate := time.Now().Add(-1 * time.Minute)
+	tests := []struct {
+		name     string
+		ws       *WaterSchedule
+		expected bool
+	}{
+		{"Active", &WaterSchedule{}, true},
+		{"EndDated", &WaterSchedule{EndDate: &pastDate}, false},
+		{"Paused", &WaterSchedule{Paused: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.ws.Scheduled())
+		})
+	}
+}
+
+func TestWaterScheduleNotStartedAt(t *testing.T) {
+	startDate := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		interval *Duration
+		t        time.Time
+		expected bool
+	}{
+		{"CronBeforeStartDate", &Duration{Cron: "0 6 * * 1"}, startDate.Add(-time.Hour), true},
+		{"CronAfterStartDate", &Duration{Cron: "0 6 * * 1"}, startDate.Add(time.Hour), false},
+		{"DurationBeforeStartDate", &Duration{Duration: 24 * time.Hour}, startDate.Add(-time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &WaterSchedule{Interval: tt.interval, StartDate: &startDate}
+			assert.Equal(t, tt.expected, ws.NotStartedAt(tt.t))
+		})
+	}
+}
+
 func TestWaterSchedulePatch(t *testing.T) {
 	one := 1
 	float := float32(1)
@@ -127,6 +167,18 @@ func TestWaterSchedulePatch(t *testing.T) {
 				},
 			},
 		},
+		{
+			"PatchMinDuration",
+			&WaterSchedule{
+				MinDuration: &Duration{time.Minute, ""},
+			},
+		},
+		{
+			"PatchMaxDuration",
+			&WaterSchedule{
+				MaxDuration: &Duration{time.Hour, ""},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -165,6 +217,123 @@ func TestWaterSchedulePatch(t *testing.T) {
 	})
 }
 
+func TestWaterScheduleValidateDurationLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		ws          *WaterSchedule
+		expectedErr string
+	}{
+		{
+			"NoLimits",
+			&WaterSchedule{},
+			"",
+		},
+		{
+			"MinAndMax",
+			&WaterSchedule{
+				MinDuration: &Duration{Duration: time.Minute},
+				MaxDuration: &Duration{Duration: time.Hour},
+			},
+			"",
+		},
+		{
+			"ErrorCron",
+			&WaterSchedule{
+				MaxDuration: &Duration{Cron: "0 * * * *"},
+			},
+			"max_duration cannot use a cron expression",
+		},
+		{
+			"ErrorNotPositive",
+			&WaterSchedule{
+				MinDuration: &Duration{Duration: -1 * time.Minute},
+			},
+			"min_duration must be a positive duration",
+		},
+		{
+			"ErrorMinGreaterThanMax",
+			&WaterSchedule{
+				MinDuration: &Duration{Duration: time.Hour},
+				MaxDuration: &Duration{Duration: time.Minute},
+			},
+			"min_duration must not be greater than max_duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ws.ValidateDurationLimits()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestWaterScheduleClampDuration(t *testing.T) {
+	ws := &WaterSchedule{
+		MinDuration: &Duration{Duration: time.Minute},
+		MaxDuration: &Duration{Duration: time.Hour},
+	}
+
+	tests := []struct {
+		name     string
+		input    time.Duration
+		expected time.Duration
+	}{
+		{"BelowMin", 30 * time.Second, time.Minute},
+		{"AboveMax", 2 * time.Hour, time.Hour},
+		{"InRange", 15 * time.Minute, 15 * time.Minute},
+		{"ZeroIsNotChanged", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ws.ClampDuration(tt.input))
+		})
+	}
+
+	t.Run("NoLimits", func(t *testing.T) {
+		assert.Equal(t, 2*time.Hour, (&WaterSchedule{}).ClampDuration(2*time.Hour))
+	})
+}
+
+func TestWaterScheduleForZone(t *testing.T) {
+	skip := true
+	ws := &WaterSchedule{
+		Duration: &Duration{Duration: time.Hour},
+		WeatherControl: &weather.Control{
+			Rain:         &weather.ScaleControl{},
+			Temperature:  &weather.ScaleControl{},
+			RainForecast: &weather.RainForecastControl{},
+		},
+	}
+
+	t.Run("ZoneUsesRainControl", func(t *testing.T) {
+		assert.Same(t, ws, ws.ForZone(&Zone{}))
+		assert.Same(t, ws, ws.ForZone(nil))
+	})
+
+	t.Run("ZoneSkipsRainControl", func(t *testing.T) {
+		result := ws.ForZone(&Zone{SkipRainControl: &skip})
+		assert.False(t, result.HasRainControl())
+		assert.False(t, result.HasRainForecastControl())
+		assert.True(t, result.HasTemperatureControl())
+		assert.Equal(t, ws.Duration, result.Duration)
+
+		// the original WaterSchedule is not changed
+		assert.True(t, ws.HasRainControl())
+		assert.True(t, ws.HasRainForecastControl())
+	})
+
+	t.Run("NoRainControl", func(t *testing.T) {
+		noRain := &WaterSchedule{Duration: &Duration{Duration: time.Hour}}
+		assert.Same(t, noRain, noRain.ForZone(&Zone{SkipRainControl: &skip}))
+	})
+}
+
 func TestActivePeriodValidate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -203,6 +372,62 @@ func TestActivePeriodValidate(t *testing.T) {
 			},
 			`StartMonth and EndMonth must be different`,
 		},
+		{
+			"ValidDates",
+			&ActivePeriod{
+				StartDate: "04-01",
+				EndDate:   "10-31",
+			},
+			"",
+		},
+		{
+			"ValidLeapDay",
+			&ActivePeriod{
+				StartDate: "02-29",
+				EndDate:   "10-31",
+			},
+			"",
+		},
+		{
+			"InvalidStartDate",
+			&ActivePeriod{
+				StartDate: "April 1",
+				EndDate:   "10-31",
+			},
+			`invalid StartDate "April 1": expected format MM-DD`,
+		},
+		{
+			"InvalidEndDate",
+			&ActivePeriod{
+				StartDate: "04-01",
+				EndDate:   "11-31",
+			},
+			`invalid EndDate "11-31": expected format MM-DD`,
+		},
+		{
+			"InvalidMissingEndDate",
+			&ActivePeriod{
+				StartDate: "04-01",
+			},
+			`invalid EndDate "": expected format MM-DD`,
+		},
+		{
+			"InvalidSameStartEndDate",
+			&ActivePeriod{
+				StartDate: "04-01",
+				EndDate:   "04-01",
+			},
+			`StartDate and EndDate must be different`,
+		},
+		{
+			"InvalidMonthsAndDates",
+			&ActivePeriod{
+				StartMonth: "April",
+				StartDate:  "04-01",
+				EndDate:    "10-31",
+			},
+			`StartMonth and EndMonth cannot be used with StartDate and EndDate`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -393,3 +618,142 @@ func TestWaterScheduleIsActive(t *testing.T) {
 		assert.Equal(t, true, (&WaterSchedule{}).IsActive(time.Now()))
 	})
 }
+
+func TestWaterScheduleIsActiveDates(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentDate string
+		ap          *ActivePeriod
+		expected    bool
+	}{
+		{"StartDate", "04-15", &ActivePeriod{StartDate: "04-15", EndDate: "10-31"}, true},
+		{"EndDate", "10-31", &ActivePeriod{StartDate: "04-15", EndDate: "10-31"}, true},
+		{"DayBeforeStart", "04-14", &ActivePeriod{StartDate: "04-15", EndDate: "10-31"}, false},
+		{"DayAfterEnd", "11-01", &ActivePeriod{StartDate: "04-15", EndDate: "10-31"}, false},
+		{"InBetween", "07-04", &ActivePeriod{StartDate: "04-15", EndDate: "10-31"}, true},
+		{"WraparoundAfterStart", "12-25", &ActivePeriod{StartDate: "11-15", EndDate: "02-15"}, true},
+		{"WraparoundBeforeEnd", "01-01", &ActivePeriod{StartDate: "11-15", EndDate: "02-15"}, true},
+		{"WraparoundDayAfterEnd", "02-16", &ActivePeriod{StartDate: "11-15", EndDate: "02-15"}, false},
+		{"WraparoundDayBeforeStart", "11-14", &ActivePeriod{StartDate: "11-15", EndDate: "02-15"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentTime, err := time.Parse("2006-01-02", fmt.Sprintf("%d-%s", time.Now().Year(), tt.currentDate))
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expected, (&WaterSchedule{ActivePeriod: tt.ap}).IsActive(currentTime))
+		})
+	}
+}
+
+func TestActivePeriodPatch(t *testing.T) {
+	t.Run("DatesReplaceMonths", func(t *testing.T) {
+		ap := &ActivePeriod{StartMonth: "April", EndMonth: "October"}
+		ap.Patch(&ActivePeriod{StartDate: "04-15", EndDate: "10-31"})
+		assert.Equal(t, &ActivePeriod{StartDate: "04-15", EndDate: "10-31"}, ap)
+	})
+	t.Run("MonthsReplaceDates", func(t *testing.T) {
+		ap := &ActivePeriod{StartDate: "04-15", EndDate: "10-31"}
+		ap.Patch(&ActivePeriod{StartMonth: "April"})
+		assert.Equal(t, &ActivePeriod{StartMonth: "April"}, ap)
+	})
+}
+
+func TestActivePeriodString(t *testing.T) {
+	assert.Equal(t, "Apr - Oct", (&ActivePeriod{StartMonth: "April", EndMonth: "October"}).String())
+	assert.Equal(t, "04-15 - 10-31", (&ActivePeriod{StartDate: "04-15", EndDate: "10-31"}).String())
+}
+
+func TestWaterScheduleConflict(t *testing.T) {
+	startDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	newWaterSchedule := func(startTime string, duration, interval time.Duration) *WaterSchedule {
+		st, err := StartTimeFromString(startTime)
+		require.NoError(t, err)
+		return &WaterSchedule{
+			Duration:  &Duration{Duration: duration},
+			Interval:  &Duration{Duration: interval},
+			StartDate: &startDate,
+			StartTime: st,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		ws               *WaterSchedule
+		other            *WaterSchedule
+		expectedConflict string
+	}{
+		{
+			"SameTime",
+			newWaterSchedule("08:00:00Z", time.Hour, 24*time.Hour),
+			newWaterSchedule("08:00:00Z", time.Hour, 24*time.Hour),
+			"2023-01-01T08:00:00Z",
+		},
+		{
+			"NoOverlapSameDay",
+			newWaterSchedule("08:00:00Z", time.Hour, 24*time.Hour),
+			newWaterSchedule("09:00:00Z", time.Hour, 24*time.Hour),
+			"",
+		},
+		{
+			"PartialOverlap",
+			newWaterSchedule("08:00:00Z", time.Hour, 24*time.Hour),
+			newWaterSchedule("08:30:00Z", time.Hour, 24*time.Hour),
+			"2023-01-01T08:30:00Z",
+		},
+		{
+			"DifferentIntervalsEventuallyOverlap",
+			newWaterSchedule("08:00:00Z", time.Hour, 48*time.Hour),
+			newWaterSchedule("08:00:00Z", time.Hour, 72*time.Hour),
+			"2023-01-01T08:00:00Z",
+		},
+		{
+			"OffsetIntervalsNeverOverlap",
+			newWaterSchedule("08:00:00Z", time.Hour, 48*time.Hour),
+			func() *WaterSchedule {
+				ws := newWaterSchedule("08:00:00Z", time.Hour, 48*time.Hour)
+				nextDay := startDate.AddDate(0, 0, 1)
+				ws.StartDate = &nextDay
+				return ws
+			}(),
+			"",
+		},
+		{
+			"DifferentActivePeriods",
+			func() *WaterSchedule {
+				ws := newWaterSchedule("08:00:00Z", time.Hour, 24*time.Hour)
+				ws.ActivePeriod = &ActivePeriod{StartDate: "04-01", EndDate: "09-30"}
+				return ws
+			}(),
+			func() *WaterSchedule {
+				ws := newWaterSchedule("08:00:00Z", time.Hour, 24*time.Hour)
+				ws.ActivePeriod = &ActivePeriod{StartDate: "10-01", EndDate: "03-31"}
+				return ws
+			}(),
+			"",
+		},
+		{
+			"CronIsNotChecked",
+			newWaterSchedule("08:00:00Z", time.Hour, 24*time.Hour),
+			&WaterSchedule{
+				Duration:  &Duration{Duration: time.Hour},
+				Interval:  &Duration{Cron: "0 8 * * *"},
+				StartTime: NewStartTime(startDate),
+			},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflict := tt.ws.Conflict(tt.other)
+			if tt.expectedConflict == "" {
+				assert.Nil(t, conflict)
+				return
+			}
+			require.NotNil(t, conflict)
+			assert.Equal(t, tt.expectedConflict, conflict.UTC().Format(time.RFC3339))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package ambientweather
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	baseURI      = "https://rt.ambientweather.net"
+	defaultUnits = "metric"
+
+	// pageLimit is the most records that the API returns for one request, which is one day of 5 minute records
+	pageLimit = 288
+	// requestInterval is the time between requests for more pages since the API allows one request per second
+	requestInterval    = time.Second
+	millimetersPerInch = 25.4
+)
+
+// Config holds the keys from an Ambient Weather account and the MAC address of the weather station. Units can be
+// "metric" or "imperial" and only affect temperature since rain is always converted to millimeters
+type Config struct {
+	APIKey         string `json:"api_key,omitempty" yaml:"api_key,omitempty" mapstructure:"api_key,omitempty"`
+	ApplicationKey string `json:"application_key,omitempty" yaml:"application_key,omitempty" mapstructure:"application_key,omitempty"`
+	MAC            string `json:"mac,omitempty" yaml:"mac,omitempty" mapstructure:"mac,omitempty"`
+	Units          string `json:"units,omitempty" yaml:"units,omitempty" mapstructure:"units,omitempty"`
+}
+
+// Client is used to get the history of a personal weather station from the Ambient Weather API
+type Client struct {
+	*Config
+	*http.Client
+	baseURL         *url.URL
+	now             func() time.Time
+	requestInterval time.Duration
+}
+
+// NewClient creates a new Ambient Weather API client from configuration
+func NewClient(options map[string]interface{}) (*Client, error) {
+	client := &Client{Client: http.DefaultClient, now: time.Now, requestInterval: requestInterval}
+
+	err := mapstructure.Decode(options, &client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.APIKey == "" || client.ApplicationKey == "" {
+		return nil, errors.New("missing required api_key and application_key")
+	}
+	if client.MAC == "" {
+		return nil, errors.New("missing required mac")
+	}
+	switch client.Units {
+	case "":
+		client.Units = defaultUnits
+	case "metric", "imperial":
+	default:
+		return nil, fmt.Errorf("invalid units %q", client.Units)
+	}
+
+	client.baseURL, err = url.Parse(baseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// SetNow sets the function used to get the current time when calculating the period for weather data
+func (c *Client) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// record is a single reading from the station. The API always uses imperial units
+type record struct {
+	// DateUTC is the time of the reading in milliseconds
+	DateUTC int64 `json:"dateutc"`
+	// TempF is the outdoor temperature
+	TempF *float32 `json:"tempf"`
+	// DailyRainIn is the rain since midnight
+	DailyRainIn *float32 `json:"dailyrainin"`
+}
+
+func (r record) time() time.Time {
+	return time.UnixMilli(r.DateUTC)
+}
+
+// getRecords gets the station's records from start until end. The API returns the newest records first, one page
+// at a time, so it requests older pages until reaching the start
+func (c *Client) getRecords(start, end time.Time) ([]record, error) {
+	result := []record{}
+	for {
+		values := url.Values{}
+		values.Add("endDate", strconv.FormatInt(end.UnixMilli(), 10))
+		values.Add("limit", strconv.Itoa(pageLimit))
+
+		var page []record
+		err := c.get("/v1/devices/"+c.MAC, values, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range page {
+			if r.time().Before(start) {
+				return result, nil
+			}
+			result = append(result, r)
+		}
+
+		if len(page) < pageLimit {
+			return result, nil
+		}
+		// the next page ends before the oldest record from this page
+		end = page[len(page)-1].time().Add(-time.Millisecond)
+		time.Sleep(c.requestInterval)
+	}
+}
+
+// get executes a GET request against the API with common parameters and decodes the JSON response
+func (c *Client) get(path string, values url.Values, result interface{}) error {
+	reqURL := *c.baseURL
+	reqURL.Path = path
+
+	values.Add("apiKey", c.APIKey)
+	values.Add("applicationKey", c.ApplicationKey)
+	reqURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body with status %d: %v", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unexpected status %d with body: %s", resp.StatusCode, string(respBody))
+	}
+
+	err = json.Unmarshal(respBody, result)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal response body: %w", err)
+	}
+
+	return nil
+}
+
+// dailyMax returns the highest value for each day in the server's local time. Records without a value are skipped
+func dailyMax(records []record, value func(record) *float32) map[string]float32 {
+	result := map[string]float32{}
+	for _, r := range records {
+		v := value(r)
+		if v == nil {
+			continue
+		}
+
+		day := r.time().In(time.Local).Format(time.DateOnly)
+		high, ok := result[day]
+		if !ok || *v > high {
+			result[day] = *v
+		}
+	}
+	return result
+}
+
+// startOfDay returns midnight at the start of t's day in the server's local time
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,204 @@
+package ambientweather
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       map[string]interface{}
+		expectedErr   string
+		expectedUnits string
+	}{
+		{
+			"Successful",
+			map[string]interface{}{"api_key": "key", "application_key": "app", "mac": "AA:BB:CC:DD:EE:FF"},
+			"",
+			"metric",
+		},
+		{
+			"SuccessfulImperial",
+			map[string]interface{}{"api_key": "key", "application_key": "app", "mac": "AA:BB:CC:DD:EE:FF", "units": "imperial"},
+			"",
+			"imperial",
+		},
+		{
+			"ErrorMissingKeys",
+			map[string]interface{}{"api_key": "key", "mac": "AA:BB:CC:DD:EE:FF"},
+			"missing required api_key and application_key",
+			"",
+		},
+		{
+			"ErrorMissingMAC",
+			map[string]interface{}{"api_key": "key", "application_key": "app"},
+			"missing required mac",
+			"",
+		},
+		{
+			"ErrorInvalidUnits",
+			map[string]interface{}{"api_key": "key", "application_key": "app", "mac": "AA:BB:CC:DD:EE:FF", "units": "kelvin"},
+			`invalid units "kelvin"`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.options)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedUnits, client.Units)
+		})
+	}
+}
+
+var now = time.Date(2023, time.April, 4, 12, 0, 0, 0, time.Local)
+
+func float32Pointer(n float32) *float32 {
+	return &n
+}
+
+// newTestClient creates a Client using a server that returns the newest records ending at the request's endDate
+func newTestClient(t *testing.T, units string, records []record) (*Client, *[]string) {
+	t.Helper()
+
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/devices/AA:BB:CC:DD:EE:FF", r.URL.Path)
+		assert.Equal(t, "key", r.URL.Query().Get("apiKey"))
+		assert.Equal(t, "app", r.URL.Query().Get("applicationKey"))
+		requests = append(requests, r.URL.Query().Get("endDate"))
+
+		endDate, err := strconv.ParseInt(r.URL.Query().Get("endDate"), 10, 64)
+		require.NoError(t, err)
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		require.NoError(t, err)
+
+		page := []record{}
+		for _, rec := range records {
+			if rec.DateUTC <= endDate && len(page) < limit {
+				page = append(page, rec)
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(page))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(map[string]interface{}{"api_key": "key", "application_key": "app", "mac": "AA:BB:CC:DD:EE:FF", "units": units})
+	require.NoError(t, err)
+
+	client.baseURL, err = url.Parse(server.URL)
+	require.NoError(t, err)
+	client.SetNow(func() time.Time { return now })
+	client.requestInterval = 0
+
+	return client, &requests
+}
+
+// fiveMinuteRecords creates records every 5 minutes from newest to oldest, like the API
+func fiveMinuteRecords(start, end time.Time, tempF, dailyRainIn func(time.Time) *float32) []record {
+	result := []record{}
+	for t := end; !t.Before(start); t = t.Add(-5 * time.Minute) {
+		result = append(result, record{DateUTC: t.UnixMilli(), TempF: tempF(t), DailyRainIn: dailyRainIn(t)})
+	}
+	return result
+}
+
+func TestGetTotalRain(t *testing.T) {
+	// 1 inch on April 3 and 0.5 inches on April 4
+	records := fiveMinuteRecords(
+		time.Date(2023, time.April, 1, 0, 0, 0, 0, time.Local),
+		now,
+		func(time.Time) *float32 { return nil },
+		func(t time.Time) *float32 {
+			switch {
+			case t.Day() == 3 && t.Hour() >= 12:
+				return float32Pointer(1)
+			case t.Day() == 4 && t.Hour() >= 6:
+				return float32Pointer(0.5)
+			default:
+				return float32Pointer(0)
+			}
+		},
+	)
+	client, requests := newTestClient(t, "", records)
+
+	totalRain, err := client.GetTotalRain(24 * time.Hour)
+	assert.NoError(t, err)
+	assert.InDelta(t, 38.1, totalRain, 0.001)
+	// one and a half days of records need 2 pages
+	assert.Len(t, *requests, 2)
+	assert.Equal(t, strconv.FormatInt(now.UnixMilli(), 10), (*requests)[0])
+}
+
+func TestGetAverageHighTemperature(t *testing.T) {
+	records := fiveMinuteRecords(
+		time.Date(2023, time.March, 30, 0, 0, 0, 0, time.Local),
+		now,
+		func(t time.Time) *float32 {
+			highs := map[int]float32{30: 50, 31: 50, 1: 59, 2: 68, 3: 77, 4: 100}
+			if t.Hour() == 14 {
+				return float32Pointer(highs[t.Day()])
+			}
+			return float32Pointer(40)
+		},
+		func(time.Time) *float32 { return nil },
+	)
+
+	t.Run("Metric", func(t *testing.T) {
+		client, _ := newTestClient(t, "", records)
+
+		// Less than 72h is increased to the minimum
+		avgHighTemp, err := client.GetAverageHighTemperature(24 * time.Hour)
+		assert.NoError(t, err)
+		assert.InDelta(t, 20, avgHighTemp, 0.001)
+	})
+
+	t.Run("Imperial", func(t *testing.T) {
+		client, _ := newTestClient(t, "imperial", records)
+
+		avgHighTemp, err := client.GetAverageHighTemperature(72 * time.Hour)
+		assert.NoError(t, err)
+		assert.InDelta(t, 68, avgHighTemp, 0.001)
+	})
+}
+
+func TestGetTotalRainErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"apiKey-missing"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(map[string]interface{}{"api_key": "key", "application_key": "app", "mac": "AA:BB:CC:DD:EE:FF"})
+	require.NoError(t, err)
+	client.baseURL, err = url.Parse(server.URL)
+	require.NoError(t, err)
+
+	_, err = client.GetTotalRain(24 * time.Hour)
+	assert.EqualError(t, err, `received unexpected status 401 with body: {"error":"apiKey-missing"}`)
+}
+
+func TestForecastsNotSupported(t *testing.T) {
+	client, err := NewClient(map[string]interface{}{"api_key": "key", "application_key": "app", "mac": "AA:BB:CC:DD:EE:FF"})
+	require.NoError(t, err)
+
+	_, err = client.GetForecastedRain(time.Hour)
+	assert.EqualError(t, err, "ambient_weather does not support rain forecasts")
+
+	_, err = client.GetForecastedLowTemperature(time.Hour)
+	assert.EqualError(t, err, "ambient_weather does not support temperature forecasts")
+}
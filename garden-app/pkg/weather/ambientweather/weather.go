@@ -0,0 +1,69 @@
+package ambientweather
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const minTemperatureInterval = 72 * time.Hour
+
+// GetTotalRain returns the sum of all rainfall in millimeters in the given period. Since the station's rain resets
+// each day, this uses the highest daily rain of each day in the period up to and including today
+func (c *Client) GetTotalRain(since time.Duration) (float32, error) {
+	now := c.now()
+
+	records, err := c.getRecords(startOfDay(now.Add(-since)), now)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float32
+	for _, rain := range dailyMax(records, func(r record) *float32 { return r.DailyRainIn }) {
+		total += rain * millimetersPerInch
+	}
+	return total, nil
+}
+
+// GetAverageHighTemperature returns the average daily high temperature between the given time and the end of
+// yesterday (since daily high can be misleading if queried mid-day)
+func (c *Client) GetAverageHighTemperature(since time.Duration) (float32, error) {
+	// Time to check since must always be at least 3 days
+	if since < minTemperatureInterval {
+		since = minTemperatureInterval
+	}
+
+	now := c.now()
+	today := startOfDay(now)
+
+	records, err := c.getRecords(startOfDay(now.Add(-since)), today.Add(-time.Millisecond))
+	if err != nil {
+		return 0, err
+	}
+
+	dailyHighs := dailyMax(records, func(r record) *float32 { return r.TempF })
+	if len(dailyHighs) == 0 {
+		return 0, fmt.Errorf("no temperature history in the last %s", since)
+	}
+
+	var total float32
+	for _, high := range dailyHighs {
+		total += high
+	}
+	average := total / float32(len(dailyHighs))
+
+	if c.Units == "imperial" {
+		return average, nil
+	}
+	return (average - 32) * 5 / 9, nil
+}
+
+// GetForecastedRain is not supported because Ambient Weather stations only provide measured data
+func (c *Client) GetForecastedRain(_ time.Duration) (float32, error) {
+	return 0, errors.New("ambient_weather does not support rain forecasts")
+}
+
+// GetForecastedLowTemperature is not supported because Ambient Weather stations only provide measured data
+func (c *Client) GetForecastedLowTemperature(_ time.Duration) (float32, error) {
+	return 0, errors.New("ambient_weather does not support temperature forecasts")
+}
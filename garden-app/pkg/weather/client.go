@@ -6,9 +6,17 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/ambientweather"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/composite"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/ecowitt"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/fake"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/mqttsensor"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/netatmo"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/openmeteo"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/openweathermap"
 	"github.com/calvinmclean/babyapi"
+	"github.com/mitchellh/mapstructure"
 	"github.com/patrickmn/go-cache"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -21,16 +29,24 @@ var (
 		Name:      "weather_client_duration_seconds",
 		Help:      "summary of weather client calls",
 	}, []string{"function", "cached"})
+
+	weatherClientErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "garden_app",
+		Name:      "weather_client_errors",
+		Help:      "count of errors from weather client calls",
+	}, []string{"function", "type"})
 )
 
 func init() {
-	prometheus.MustRegister(weatherClientSummary)
+	prometheus.MustRegister(weatherClientSummary, weatherClientErrors)
 }
 
 // Client is an interface defining the possible methods used to interact with the weather client APIs
 type Client interface {
 	GetTotalRain(since time.Duration) (float32, error)
 	GetAverageHighTemperature(since time.Duration) (float32, error)
+	GetForecastedRain(ahead time.Duration) (float32, error)
+	GetForecastedLowTemperature(ahead time.Duration) (float32, error)
 }
 
 // Config is used to identify and configure a client type
@@ -61,10 +77,10 @@ func (wc *Config) Bind(r *http.Request) error {
 	switch r.Method {
 	case http.MethodPut, http.MethodPost:
 		if wc.Type == "" {
-			return errors.New("missing required type field")
+			return validation.Missing("type")
 		}
 		if wc.Options == nil {
-			return errors.New("missing required options field")
+			return validation.Missing("options")
 		}
 	}
 
@@ -77,8 +93,20 @@ func NewClient(c *Config, storageCallback func(map[string]interface{}) error) (c
 	switch c.Type {
 	case "netatmo":
 		client, err = netatmo.NewClient(c.Options, storageCallback)
+	case "openweathermap":
+		client, err = openweathermap.NewClient(c.Options)
+	case "openmeteo":
+		client, err = openmeteo.NewClient(c.Options)
+	case "ecowitt":
+		client, err = ecowitt.NewClient(c.Options)
+	case "ambient_weather":
+		client, err = ambientweather.NewClient(c.Options)
 	case "fake":
 		client, err = fake.NewClient(c.Options)
+	case "composite":
+		err = errors.New("composite clients must be created with NewCompositeClient")
+	case "mqtt_sensor":
+		err = errors.New("mqtt_sensor clients must be created with NewMQTTSensorClient")
 	default:
 		err = fmt.Errorf("invalid type '%s'", c.Type)
 	}
@@ -89,6 +117,66 @@ func NewClient(c *Config, storageCallback func(map[string]interface{}) error) (c
 	return newMetricsWrapperClient(client, c), nil
 }
 
+// NewCompositeClient creates a "composite" client that combines data from other clients. getClient is used to create
+// the client for each of the IDs in the Config's client_ids option
+func NewCompositeClient(c *Config, getClient func(id string) (Client, error)) (Client, error) {
+	if c.Type != "composite" {
+		return nil, fmt.Errorf("invalid type '%s' for composite client", c.Type)
+	}
+
+	client, err := composite.NewClient(c.Options, func(id string) (composite.Source, error) {
+		return getClient(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newMetricsWrapperClient(client, c), nil
+}
+
+// NewMQTTSensorClient creates an "mqtt_sensor" client that uses readings published by a local sensor. getReadings
+// is used to read the sensor's readings that were recorded after since
+func NewMQTTSensorClient(c *Config, getReadings func(since time.Time) ([]mqttsensor.Reading, error)) (Client, error) {
+	if c.Type != "mqtt_sensor" {
+		return nil, fmt.Errorf("invalid type '%s' for mqtt_sensor client", c.Type)
+	}
+
+	client, err := mqttsensor.NewClient(c.Options, getReadings)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMetricsWrapperClient(client, c), nil
+}
+
+// MQTTSensorTopic returns the topic used by an "mqtt_sensor" client. It returns an empty string for other types
+func (wc *Config) MQTTSensorTopic() string {
+	if wc.Type != "mqtt_sensor" {
+		return ""
+	}
+
+	var cfg mqttsensor.Config
+	err := mapstructure.Decode(wc.Options, &cfg)
+	if err != nil {
+		return ""
+	}
+	return cfg.Topic
+}
+
+// CompositeClientIDs returns the IDs of the clients used by a "composite" client. It returns nil for other types
+func (wc *Config) CompositeClientIDs() []string {
+	if wc.Type != "composite" {
+		return nil
+	}
+
+	var cfg composite.Config
+	err := mapstructure.Decode(wc.Options, &cfg)
+	if err != nil {
+		return nil
+	}
+	return cfg.ClientIDs
+}
+
 // Patch allows modifying an existing Config with fields from a new one
 func (wc *Config) Patch(newConfig *Config) *babyapi.ErrResponse {
 	if newConfig.Type != "" {
@@ -105,6 +193,16 @@ func (wc *Config) Patch(newConfig *Config) *babyapi.ErrResponse {
 	return nil
 }
 
+// SupportsForecast returns false for Client types that only provide measured data
+func (wc *Config) SupportsForecast() bool {
+	switch wc.Type {
+	case "netatmo", "mqtt_sensor", "ecowitt", "ambient_weather":
+		return false
+	default:
+		return true
+	}
+}
+
 // EndDated allows this to satisfy an interface even though the resources does not have end-dates
 func (*Config) EndDated() bool {
 	return false
@@ -117,12 +215,13 @@ func (*Config) SetEndDate(_ time.Time) {}
 type clientWrapper struct {
 	Client
 	*Config
+	now func() time.Time
 }
 
 // newMetricsWrapperClient returns the input client wrapped with a Prometheus metrics collector. It is intended to
 // directly wrap functions to create other clients
 func newMetricsWrapperClient(client Client, config *Config) Client {
-	return &clientWrapper{client, config}
+	return &clientWrapper{Client: client, Config: config}
 }
 
 // GetTotalRain ...
@@ -133,7 +232,7 @@ func (c *clientWrapper) GetTotalRain(since time.Duration) (float32, error) {
 		weatherClientSummary.WithLabelValues("GetTotalRain", fmt.Sprintf("%t", cached)).Observe(time.Since(now).Seconds())
 	}()
 
-	cacheKey := fmt.Sprintf("total_rain_%d_%s", since, c.Config.ID)
+	cacheKey := fmt.Sprintf("total_rain_%d_%s%s", since, c.Config.ID, c.cacheKeySuffix())
 	cachedData, found := responseCache.Get(cacheKey)
 	if found {
 		cached = true
@@ -142,6 +241,7 @@ func (c *clientWrapper) GetTotalRain(since time.Duration) (float32, error) {
 
 	totalRain, err := c.Client.GetTotalRain(since)
 	if err != nil {
+		weatherClientErrors.WithLabelValues("GetTotalRain", c.Config.Type).Inc()
 		return 0, err
 	}
 	responseCache.Set(cacheKey, totalRain, cache.DefaultExpiration)
@@ -157,7 +257,7 @@ func (c *clientWrapper) GetAverageHighTemperature(since time.Duration) (float32,
 		weatherClientSummary.WithLabelValues("GetAverageHighTemperature", fmt.Sprintf("%t", cached)).Observe(time.Since(now).Seconds())
 	}()
 
-	cacheKey := fmt.Sprintf("avg_temp_%d_%s", since, c.Config.ID)
+	cacheKey := fmt.Sprintf("avg_temp_%d_%s%s", since, c.Config.ID, c.cacheKeySuffix())
 	cachedData, found := responseCache.Get(cacheKey)
 	if found {
 		cached = true
@@ -166,6 +266,7 @@ func (c *clientWrapper) GetAverageHighTemperature(since time.Duration) (float32,
 
 	avgTemp, err := c.Client.GetAverageHighTemperature(since)
 	if err != nil {
+		weatherClientErrors.WithLabelValues("GetAverageHighTemperature", c.Config.Type).Inc()
 		return 0, err
 	}
 	responseCache.Set(cacheKey, avgTemp, cache.DefaultExpiration)
@@ -173,6 +274,80 @@ func (c *clientWrapper) GetAverageHighTemperature(since time.Duration) (float32,
 	return avgTemp, nil
 }
 
+// GetForecastedRain ...
+func (c *clientWrapper) GetForecastedRain(ahead time.Duration) (float32, error) {
+	now := time.Now()
+	cached := false
+	defer func() {
+		weatherClientSummary.WithLabelValues("GetForecastedRain", fmt.Sprintf("%t", cached)).Observe(time.Since(now).Seconds())
+	}()
+
+	cacheKey := fmt.Sprintf("forecast_rain_%d_%s%s", ahead, c.Config.ID, c.cacheKeySuffix())
+	cachedData, found := responseCache.Get(cacheKey)
+	if found {
+		cached = true
+		return cachedData.(float32), nil
+	}
+
+	forecastedRain, err := c.Client.GetForecastedRain(ahead)
+	if err != nil {
+		weatherClientErrors.WithLabelValues("GetForecastedRain", c.Config.Type).Inc()
+		return 0, err
+	}
+	responseCache.Set(cacheKey, forecastedRain, cache.DefaultExpiration)
+
+	return forecastedRain, nil
+}
+
+// GetForecastedLowTemperature ...
+func (c *clientWrapper) GetForecastedLowTemperature(ahead time.Duration) (float32, error) {
+	now := time.Now()
+	cached := false
+	defer func() {
+		weatherClientSummary.WithLabelValues("GetForecastedLowTemperature", fmt.Sprintf("%t", cached)).Observe(time.Since(now).Seconds())
+	}()
+
+	cacheKey := fmt.Sprintf("forecast_low_temp_%d_%s%s", ahead, c.Config.ID, c.cacheKeySuffix())
+	cachedData, found := responseCache.Get(cacheKey)
+	if found {
+		cached = true
+		return cachedData.(float32), nil
+	}
+
+	forecastedLow, err := c.Client.GetForecastedLowTemperature(ahead)
+	if err != nil {
+		weatherClientErrors.WithLabelValues("GetForecastedLowTemperature", c.Config.Type).Inc()
+		return 0, err
+	}
+	responseCache.Set(cacheKey, forecastedLow, cache.DefaultExpiration)
+
+	return forecastedLow, nil
+}
+
+// SetNow configures the Client to use now instead of the system time when calculating the period for weather
+// data. This is used in simulation mode so weather data is read for the virtual time
+func SetNow(client Client, now func() time.Time) {
+	wrapper, ok := client.(*clientWrapper)
+	if !ok {
+		return
+	}
+	wrapper.now = now
+
+	setter, ok := wrapper.Client.(interface{ SetNow(func() time.Time) })
+	if ok {
+		setter.SetNow(now)
+	}
+}
+
+// cacheKeySuffix separates cached data by hour when a custom time is used since it can move much faster than
+// the cache expiration
+func (c *clientWrapper) cacheKeySuffix() string {
+	if c.now == nil {
+		return ""
+	}
+	return fmt.Sprintf("_%d", c.now().Truncate(time.Hour).Unix())
+}
+
 func ResetCache() {
 	responseCache = cache.New(5*time.Minute, 1*time.Minute)
 }
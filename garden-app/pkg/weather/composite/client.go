@@ -0,0 +1,133 @@
+package composite
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	StrategyAverage = "average"
+	StrategyMax     = "max"
+	StrategyMin     = "min"
+)
+
+// Source is a weather client that provides data to combine. It has the same methods as weather.Client
+type Source interface {
+	GetTotalRain(since time.Duration) (float32, error)
+	GetAverageHighTemperature(since time.Duration) (float32, error)
+	GetForecastedRain(ahead time.Duration) (float32, error)
+	GetForecastedLowTemperature(ahead time.Duration) (float32, error)
+}
+
+// Config lists the IDs of other WeatherClients to combine and the strategy used to combine their data. Strategy
+// can be "average", "max", or "min" and defaults to "average"
+type Config struct {
+	ClientIDs []string `json:"client_ids" yaml:"client_ids" mapstructure:"client_ids"`
+	Strategy  string   `json:"strategy,omitempty" yaml:"strategy,omitempty" mapstructure:"strategy,omitempty"`
+}
+
+// Client combines data from multiple Sources. Sources that return an error are left out so one unavailable API
+// does not prevent getting data from the others
+type Client struct {
+	*Config
+	sources []Source
+}
+
+// NewClient creates a new composite client. getSource is used to create the Source for each of the client_ids
+func NewClient(options map[string]interface{}, getSource func(id string) (Source, error)) (*Client, error) {
+	client := &Client{}
+
+	err := mapstructure.Decode(options, &client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(client.ClientIDs) == 0 {
+		return nil, errors.New("missing required client_ids")
+	}
+	switch client.Strategy {
+	case "":
+		client.Strategy = StrategyAverage
+	case StrategyAverage, StrategyMax, StrategyMin:
+	default:
+		return nil, fmt.Errorf("invalid strategy %q", client.Strategy)
+	}
+
+	for _, id := range client.ClientIDs {
+		source, err := getSource(id)
+		if err != nil {
+			return nil, fmt.Errorf("error getting client %q: %w", id, err)
+		}
+		client.sources = append(client.sources, source)
+	}
+
+	return client, nil
+}
+
+// GetTotalRain combines the total rain from each Source
+func (c *Client) GetTotalRain(since time.Duration) (float32, error) {
+	return c.combine(func(s Source) (float32, error) {
+		return s.GetTotalRain(since)
+	})
+}
+
+// GetAverageHighTemperature combines the average high temperature from each Source
+func (c *Client) GetAverageHighTemperature(since time.Duration) (float32, error) {
+	return c.combine(func(s Source) (float32, error) {
+		return s.GetAverageHighTemperature(since)
+	})
+}
+
+// GetForecastedRain combines the forecasted rain from each Source
+func (c *Client) GetForecastedRain(ahead time.Duration) (float32, error) {
+	return c.combine(func(s Source) (float32, error) {
+		return s.GetForecastedRain(ahead)
+	})
+}
+
+// GetForecastedLowTemperature combines the forecasted low temperature from each Source
+func (c *Client) GetForecastedLowTemperature(ahead time.Duration) (float32, error) {
+	return c.combine(func(s Source) (float32, error) {
+		return s.GetForecastedLowTemperature(ahead)
+	})
+}
+
+// combine gets a value from each Source and combines them using the configured strategy. An error is only
+// returned if all Sources fail
+func (c *Client) combine(get func(Source) (float32, error)) (float32, error) {
+	var values []float32
+	var errs []error
+	for i, source := range c.sources {
+		value, err := get(source)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("client %q: %w", c.ClientIDs[i], err))
+			continue
+		}
+		values = append(values, value)
+	}
+
+	if len(values) == 0 {
+		return 0, fmt.Errorf("error getting data from all clients: %w", errors.Join(errs...))
+	}
+
+	result := values[0]
+	for _, value := range values[1:] {
+		switch c.Strategy {
+		case StrategyMax:
+			result = max(result, value)
+		case StrategyMin:
+			result = min(result, value)
+		default:
+			result += value
+		}
+	}
+
+	if c.Strategy == StrategyAverage {
+		result /= float32(len(values))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package composite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticSource struct {
+	value float32
+	err   error
+}
+
+func (s staticSource) GetTotalRain(time.Duration) (float32, error) {
+	return s.value, s.err
+}
+
+func (s staticSource) GetAverageHighTemperature(time.Duration) (float32, error) {
+	return s.value, s.err
+}
+
+func (s staticSource) GetForecastedRain(time.Duration) (float32, error) {
+	return s.value, s.err
+}
+
+func (s staticSource) GetForecastedLowTemperature(time.Duration) (float32, error) {
+	return s.value, s.err
+}
+
+func newTestClient(t *testing.T, strategy string, sources map[string]staticSource) *Client {
+	t.Helper()
+
+	ids := []string{}
+	for id := range sources {
+		ids = append(ids, id)
+	}
+
+	client, err := NewClient(map[string]interface{}{
+		"client_ids": ids,
+		"strategy":   strategy,
+	}, func(id string) (Source, error) {
+		return sources[id], nil
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestNewClientErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+		err     string
+	}{
+		{
+			"MissingClientIDs",
+			map[string]interface{}{},
+			"missing required client_ids",
+		},
+		{
+			"InvalidStrategy",
+			map[string]interface{}{"client_ids": []string{"a"}, "strategy": "median"},
+			`invalid strategy "median"`,
+		},
+		{
+			"ErrorGettingSource",
+			map[string]interface{}{"client_ids": []string{"a"}},
+			`error getting client "a": not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(tt.options, func(string) (Source, error) {
+				return nil, errors.New("not found")
+			})
+			require.Error(t, err)
+			assert.Equal(t, tt.err, err.Error())
+		})
+	}
+}
+
+func TestNewClientDefaultStrategy(t *testing.T) {
+	client := newTestClient(t, "", map[string]staticSource{"a": {value: 1}})
+	assert.Equal(t, StrategyAverage, client.Strategy)
+}
+
+func TestCombine(t *testing.T) {
+	sources := map[string]staticSource{
+		"a": {value: 10},
+		"b": {value: 20},
+		"c": {value: 60},
+	}
+
+	tests := []struct {
+		strategy string
+		expected float32
+	}{
+		{StrategyAverage, 30},
+		{StrategyMax, 60},
+		{StrategyMin, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			client := newTestClient(t, tt.strategy, sources)
+
+			rain, err := client.GetTotalRain(24 * time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rain)
+
+			temp, err := client.GetAverageHighTemperature(24 * time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, temp)
+
+			forecast, err := client.GetForecastedRain(24 * time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, forecast)
+		})
+	}
+}
+
+func TestCombineSkipsErrors(t *testing.T) {
+	client := newTestClient(t, StrategyAverage, map[string]staticSource{
+		"a": {value: 10},
+		"b": {err: errors.New("unavailable")},
+	})
+
+	rain, err := client.GetTotalRain(24 * time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, float32(10), rain)
+}
+
+func TestCombineAllErrors(t *testing.T) {
+	client := newTestClient(t, StrategyAverage, map[string]staticSource{
+		"a": {err: errors.New("unavailable")},
+	})
+
+	_, err := client.GetTotalRain(24 * time.Hour)
+	require.Error(t, err)
+	assert.Equal(t, "error getting data from all clients: client \"a\": unavailable", err.Error())
+}
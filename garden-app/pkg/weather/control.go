@@ -1,12 +1,18 @@
 package weather
 
-import "github.com/rs/xid"
+import (
+	"time"
+
+	"github.com/rs/xid"
+)
 
 // Control defines certain parameters and behaviors to influence watering patterns based off weather data
 type Control struct {
 	Rain         *ScaleControl        `json:"rain_control,omitempty"`
 	SoilMoisture *SoilMoistureControl `json:"moisture_control,omitempty"`
 	Temperature  *ScaleControl        `json:"temperature_control,omitempty"`
+	RainForecast *RainForecastControl `json:"rain_forecast_control,omitempty"`
+	Frost        *FrostControl        `json:"frost_control,omitempty"`
 }
 
 // Patch allows modifying the struct in-place with values from a different instance
@@ -31,8 +37,33 @@ func (wc *Control) Patch(new *Control) {
 		}
 		wc.Temperature.Patch(new.Temperature)
 	}
+	if new.RainForecast != nil {
+		if wc.RainForecast == nil {
+			wc.RainForecast = &RainForecastControl{}
+		}
+		wc.RainForecast.Patch(new.RainForecast)
+	}
+	if new.Frost != nil {
+		if wc.Frost == nil {
+			wc.Frost = &FrostControl{}
+		}
+		wc.Frost.Patch(new.Frost)
+	}
 }
 
+const (
+	// OnErrorWaterUnscaled continues watering without a control when its WeatherClient returns an error. This is
+	// the default
+	OnErrorWaterUnscaled = "water_unscaled"
+	// OnErrorSkip skips watering when the control's WeatherClient returns an error
+	OnErrorSkip = "skip"
+	// OnErrorFail fails the WaterAction with the WeatherClient's error so it is reported instead of watering
+	OnErrorFail = "fail"
+)
+
+// OnErrorPolicies are the valid values for a control's OnError
+var OnErrorPolicies = []string{OnErrorWaterUnscaled, OnErrorSkip, OnErrorFail}
+
 // SoilMoistureControl defines parameters for delaying watering based on soil moisture data. This will skip watering if the
 // soil moisture is below the minimum
 // soil moisture value is currently hard-coded as the average value over the last 15 minutes
@@ -40,6 +71,103 @@ type SoilMoistureControl struct {
 	MinimumMoisture *int `json:"minimum_moisture,omitempty"`
 }
 
+// RainForecastControl defines parameters for skipping watering when rain is expected soon. If the forecasted rain
+// for the next HoursAhead is at least the Threshold (in millimeters), watering is skipped. OnError decides what
+// happens when the forecast is not available
+type RainForecastControl struct {
+	Threshold  *float32 `json:"threshold"`
+	HoursAhead *int     `json:"hours_ahead"`
+	ClientID   xid.ID   `json:"client_id"`
+	OnError    string   `json:"on_error,omitempty"`
+}
+
+// Patch allows modifying the struct in-place with values from a different instance
+func (rc *RainForecastControl) Patch(new *RainForecastControl) {
+	if new.Threshold != nil {
+		rc.Threshold = new.Threshold
+	}
+	if new.HoursAhead != nil {
+		rc.HoursAhead = new.HoursAhead
+	}
+	if !new.ClientID.IsNil() {
+		rc.ClientID = new.ClientID
+	}
+	if new.OnError != "" {
+		rc.OnError = new.OnError
+	}
+}
+
+// Ahead returns the forecast period as a Duration
+func (rc *RainForecastControl) Ahead() time.Duration {
+	return time.Duration(*rc.HoursAhead) * time.Hour
+}
+
+// ShouldSkip returns true if the forecasted rain reaches the Threshold
+func (rc *RainForecastControl) ShouldSkip(forecastedRain float32) bool {
+	return forecastedRain >= *rc.Threshold
+}
+
+const (
+	// FrostModeProtect waters briefly before a forecasted freeze, which protects plants like citrus since wet soil
+	// holds and releases more heat overnight
+	FrostModeProtect = "protect"
+	// FrostModeInhibit skips watering before a forecasted freeze so water does not freeze on plants or in pipes
+	FrostModeInhibit = "inhibit"
+)
+
+// FrostControl defines parameters for changing watering when the forecasted low temperature for the next HoursAhead
+// is at or below the Threshold (in the WeatherClient's temperature units). The Mode determines if the watering is
+// replaced by a short frost protection watering of ProtectMinutes or skipped. OnError decides what happens when the
+// forecast is not available
+type FrostControl struct {
+	Mode           string   `json:"mode"`
+	Threshold      *float32 `json:"threshold"`
+	HoursAhead     *int     `json:"hours_ahead"`
+	ProtectMinutes *int     `json:"protect_minutes,omitempty"`
+	ClientID       xid.ID   `json:"client_id"`
+	OnError        string   `json:"on_error,omitempty"`
+}
+
+// Patch allows modifying the struct in-place with values from a different instance
+func (fc *FrostControl) Patch(new *FrostControl) {
+	if new.Mode != "" {
+		fc.Mode = new.Mode
+	}
+	if new.Threshold != nil {
+		fc.Threshold = new.Threshold
+	}
+	if new.HoursAhead != nil {
+		fc.HoursAhead = new.HoursAhead
+	}
+	if new.ProtectMinutes != nil {
+		fc.ProtectMinutes = new.ProtectMinutes
+	}
+	if !new.ClientID.IsNil() {
+		fc.ClientID = new.ClientID
+	}
+	if new.OnError != "" {
+		fc.OnError = new.OnError
+	}
+}
+
+// Ahead returns the forecast period as a Duration
+func (fc *FrostControl) Ahead() time.Duration {
+	return time.Duration(*fc.HoursAhead) * time.Hour
+}
+
+// IsFreezing returns true if the forecasted low temperature reaches the Threshold
+func (fc *FrostControl) IsFreezing(forecastedLow float32) bool {
+	return forecastedLow <= *fc.Threshold
+}
+
+// ProtectDuration returns the duration of frost protection watering
+func (fc *FrostControl) ProtectDuration() time.Duration {
+	if fc.ProtectMinutes == nil {
+		return 0
+	}
+	return time.Duration(*fc.ProtectMinutes) * time.Minute
+}
+
 // ScaleControl is a generic struct that enables scaling
 // BaselineValue is the value that scaling starts at
 // Range is the most extreme value that scaling will go to (used as max/min)
@@ -59,11 +187,14 @@ type SoilMoistureControl struct {
 // Basically, a Factor of 0.5 means that if watering is set at 30m, I want to water at most 45 min and at least 15 min
 // This way, the control doesn't need to know anything about the durations and can just return a multiplier that
 // makes this happen
+//
+// OnError decides what happens when the weather data is not available
 type ScaleControl struct {
 	BaselineValue *float32 `json:"baseline_value"`
 	Factor        *float32 `json:"factor"`
 	Range         *float32 `json:"range"`
 	ClientID      xid.ID   `json:"client_id"`
+	OnError       string   `json:"on_error,omitempty"`
 }
 
 // Patch allows modifying the struct in-place with values from a different instance
@@ -80,6 +211,9 @@ func (sc *ScaleControl) Patch(new *ScaleControl) {
 	if !new.ClientID.IsNil() {
 		sc.ClientID = new.ClientID
 	}
+	if new.OnError != "" {
+		sc.OnError = new.OnError
+	}
 }
 
 // Scale calculates and returns the multiplier based on the input value
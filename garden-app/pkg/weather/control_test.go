@@ -54,6 +54,22 @@ func TestPatch(t *testing.T) {
 				},
 			},
 		},
+		{
+			"PatchRainForecast.HoursAhead",
+			&Control{
+				RainForecast: &RainForecastControl{
+					HoursAhead: &fifty,
+				},
+			},
+		},
+		{
+			"PatchFrost.Mode",
+			&Control{
+				Frost: &FrostControl{
+					Mode: FrostModeInhibit,
+				},
+			},
+		},
 		{
 			"PatchSoilMoisture.MinimumMoisture",
 			&Control{
@@ -80,10 +96,18 @@ func TestPatch(t *testing.T) {
 			if tt.newControl.SoilMoisture == nil {
 				tt.newControl.SoilMoisture = &SoilMoistureControl{}
 			}
+			if tt.newControl.RainForecast == nil {
+				tt.newControl.RainForecast = &RainForecastControl{}
+			}
+			if tt.newControl.Frost == nil {
+				tt.newControl.Frost = &FrostControl{}
+			}
 			c := &Control{
 				Rain:         &ScaleControl{},
 				Temperature:  &ScaleControl{},
 				SoilMoisture: &SoilMoistureControl{},
+				RainForecast: &RainForecastControl{},
+				Frost:        &FrostControl{},
 			}
 			c.Patch(tt.newControl)
 			assert.Equal(t, tt.newControl, c)
@@ -0,0 +1,208 @@
+package ecowitt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	baseURI      = "https://api.ecowitt.net"
+	historyPath  = "/api/v3/device/history"
+	dateFormat   = "2006-01-02 15:04:05"
+	defaultUnits = "metric"
+
+	// unit IDs used by the API
+	celsiusUnitID     = "1"
+	fahrenheitUnitID  = "2"
+	millimetersUnitID = "12"
+)
+
+// Config holds the keys from an Ecowitt account and the MAC address of the weather station. Units can be "metric"
+// or "imperial" and only affect temperature since rain is always in millimeters
+type Config struct {
+	ApplicationKey string `json:"application_key,omitempty" yaml:"application_key,omitempty" mapstructure:"application_key,omitempty"`
+	APIKey         string `json:"api_key,omitempty" yaml:"api_key,omitempty" mapstructure:"api_key,omitempty"`
+	MAC            string `json:"mac,omitempty" yaml:"mac,omitempty" mapstructure:"mac,omitempty"`
+	Units          string `json:"units,omitempty" yaml:"units,omitempty" mapstructure:"units,omitempty"`
+}
+
+// Client is used to get the history of a personal weather station from the Ecowitt API
+type Client struct {
+	*Config
+	*http.Client
+	baseURL *url.URL
+	now     func() time.Time
+}
+
+// NewClient creates a new Ecowitt API client from configuration
+func NewClient(options map[string]interface{}) (*Client, error) {
+	client := &Client{Client: http.DefaultClient, now: time.Now}
+
+	err := mapstructure.Decode(options, &client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.ApplicationKey == "" || client.APIKey == "" {
+		return nil, errors.New("missing required application_key and api_key")
+	}
+	if client.MAC == "" {
+		return nil, errors.New("missing required mac")
+	}
+	switch client.Units {
+	case "":
+		client.Units = defaultUnits
+	case "metric", "imperial":
+	default:
+		return nil, fmt.Errorf("invalid units %q", client.Units)
+	}
+
+	client.baseURL, err = url.Parse(baseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// SetNow sets the function used to get the current time when calculating the period for weather data
+func (c *Client) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// series is a list of values by unix timestamp. The API uses strings for both
+type series struct {
+	Unit string            `json:"unit"`
+	List map[string]string `json:"list"`
+}
+
+// historyData has the series that are requested by this client
+type historyData struct {
+	Outdoor struct {
+		Temperature series `json:"temperature"`
+	} `json:"outdoor"`
+	Rainfall struct {
+		// Daily is the rain since midnight when each value was recorded
+		Daily series `json:"daily"`
+	} `json:"rainfall"`
+}
+
+// historyResponse is the response from the history endpoint. Errors use a non-zero Code with a 200 status
+type historyResponse struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// getHistory gets a series from the station's history between start and end. The API chooses the time between
+// values based on the length of the period
+func (c *Client) getHistory(callback string, start, end time.Time) (historyData, error) {
+	values := url.Values{}
+	values.Add("start_date", start.Format(dateFormat))
+	values.Add("end_date", end.Format(dateFormat))
+	values.Add("cycle_type", "auto")
+	values.Add("call_back", callback)
+
+	var resp historyResponse
+	err := c.get(historyPath, values, &resp)
+	if err != nil {
+		return historyData{}, err
+	}
+	if resp.Code != 0 {
+		return historyData{}, fmt.Errorf("received error code %d: %s", resp.Code, resp.Msg)
+	}
+
+	var data historyData
+	// data is an empty list instead of an object when the station has no history for the period
+	if len(resp.Data) == 0 || bytes.Equal(bytes.TrimSpace(resp.Data), []byte("[]")) {
+		return data, nil
+	}
+	err = json.Unmarshal(resp.Data, &data)
+	if err != nil {
+		return historyData{}, fmt.Errorf("unable to unmarshal history data: %w", err)
+	}
+	return data, nil
+}
+
+// get executes a GET request against the API with common parameters and decodes the JSON response
+func (c *Client) get(path string, values url.Values, result interface{}) error {
+	reqURL := *c.baseURL
+	reqURL.Path = path
+
+	values.Add("application_key", c.ApplicationKey)
+	values.Add("api_key", c.APIKey)
+	values.Add("mac", c.MAC)
+	values.Add("rainfall_unitid", millimetersUnitID)
+	if c.Units == "imperial" {
+		values.Add("temp_unitid", fahrenheitUnitID)
+	} else {
+		values.Add("temp_unitid", celsiusUnitID)
+	}
+	reqURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body with status %d: %v", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unexpected status %d with body: %s", resp.StatusCode, string(respBody))
+	}
+
+	err = json.Unmarshal(respBody, result)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal response body: %w", err)
+	}
+
+	return nil
+}
+
+// dailyMax returns the highest value of the series for each day in the server's local time
+func (s series) dailyMax() (map[string]float32, error) {
+	result := map[string]float32{}
+	for ts, value := range s.List {
+		seconds, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q: %w", ts, err)
+		}
+		v, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			// missing values are empty or "-"
+			continue
+		}
+
+		day := time.Unix(seconds, 0).In(time.Local).Format(time.DateOnly)
+		high, ok := result[day]
+		if !ok || float32(v) > high {
+			result[day] = float32(v)
+		}
+	}
+	return result, nil
+}
+
+// startOfDay returns midnight at the start of t's day in the server's local time
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
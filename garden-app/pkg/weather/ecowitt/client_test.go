@@ -0,0 +1,153 @@
+package ecowitt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       map[string]interface{}
+		expectedErr   string
+		expectedUnits string
+	}{
+		{
+			"Successful",
+			map[string]interface{}{"application_key": "app", "api_key": "key", "mac": "AA:BB:CC:DD:EE:FF"},
+			"",
+			"metric",
+		},
+		{
+			"SuccessfulImperial",
+			map[string]interface{}{"application_key": "app", "api_key": "key", "mac": "AA:BB:CC:DD:EE:FF", "units": "imperial"},
+			"",
+			"imperial",
+		},
+		{
+			"ErrorMissingKeys",
+			map[string]interface{}{"api_key": "key", "mac": "AA:BB:CC:DD:EE:FF"},
+			"missing required application_key and api_key",
+			"",
+		},
+		{
+			"ErrorMissingMAC",
+			map[string]interface{}{"application_key": "app", "api_key": "key"},
+			"missing required mac",
+			"",
+		},
+		{
+			"ErrorInvalidUnits",
+			map[string]interface{}{"application_key": "app", "api_key": "key", "mac": "AA:BB:CC:DD:EE:FF", "units": "kelvin"},
+			`invalid units "kelvin"`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.options)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedUnits, client.Units)
+		})
+	}
+}
+
+var now = time.Date(2023, time.April, 4, 12, 0, 0, 0, time.Local)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(map[string]interface{}{"application_key": "app", "api_key": "key", "mac": "AA:BB:CC:DD:EE:FF"})
+	assert.NoError(t, err)
+
+	client.baseURL, err = url.Parse(server.URL)
+	assert.NoError(t, err)
+	client.SetNow(func() time.Time { return now })
+
+	return client
+}
+
+// at returns the unix timestamp for the hour on the day of April 2023
+func at(day, hour int) int64 {
+	return time.Date(2023, time.April, day, hour, 0, 0, 0, time.Local).Unix()
+}
+
+func TestGetTotalRain(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/device/history", r.URL.Path)
+		assert.Equal(t, "rainfall.daily", r.URL.Query().Get("call_back"))
+		assert.Equal(t, "AA:BB:CC:DD:EE:FF", r.URL.Query().Get("mac"))
+		assert.Equal(t, "12", r.URL.Query().Get("rainfall_unitid"))
+		assert.Equal(t, "2023-04-03 00:00:00", r.URL.Query().Get("start_date"))
+		assert.Equal(t, "2023-04-04 12:00:00", r.URL.Query().Get("end_date"))
+
+		fmt.Fprintf(w, `{"code":0,"msg":"success","data":{"rainfall":{"daily":{"unit":"mm","list":{"%d":"1.5","%d":"2.5","%d":"0","%d":"1","%d":"-"}}}}}`,
+			at(3, 8), at(3, 20), at(4, 0), at(4, 8), at(4, 10),
+		)
+	})
+
+	totalRain, err := client.GetTotalRain(24 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3.5), totalRain)
+}
+
+func TestGetTotalRainNoData(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"code":0,"msg":"success","data":[]}`)
+	})
+
+	totalRain, err := client.GetTotalRain(24 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0), totalRain)
+}
+
+func TestGetTotalRainErrorCode(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"code":40010,"msg":"Illegal Application_Key Parameter","data":[]}`)
+	})
+
+	_, err := client.GetTotalRain(24 * time.Hour)
+	assert.EqualError(t, err, "received error code 40010: Illegal Application_Key Parameter")
+}
+
+func TestGetAverageHighTemperature(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "outdoor.temperature", r.URL.Query().Get("call_back"))
+		assert.Equal(t, "1", r.URL.Query().Get("temp_unitid"))
+		// Less than 72h is increased to the minimum
+		assert.Equal(t, "2023-04-01 00:00:00", r.URL.Query().Get("start_date"))
+		assert.Equal(t, "2023-04-03 23:59:59", r.URL.Query().Get("end_date"))
+
+		fmt.Fprintf(w, `{"code":0,"msg":"success","data":{"outdoor":{"temperature":{"unit":"℃","list":{"%d":"10","%d":"20","%d":"25","%d":"30","%d":"28","%d":"40"}}}}}`,
+			at(1, 8), at(1, 14), at(2, 14), at(3, 14), at(3, 16), at(4, 0),
+		)
+	})
+
+	avgHighTemp, err := client.GetAverageHighTemperature(24 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(25), avgHighTemp)
+}
+
+func TestForecastsNotSupported(t *testing.T) {
+	client := newTestClient(t, func(http.ResponseWriter, *http.Request) {})
+
+	_, err := client.GetForecastedRain(time.Hour)
+	assert.EqualError(t, err, "ecowitt does not support rain forecasts")
+
+	_, err = client.GetForecastedLowTemperature(time.Hour)
+	assert.EqualError(t, err, "ecowitt does not support temperature forecasts")
+}
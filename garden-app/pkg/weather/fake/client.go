@@ -13,7 +13,10 @@ type Config struct {
 	RainInterval string  `mapstructure:"rain_interval"`
 	rainInterval time.Duration
 
+	ForecastRainMM float32 `mapstructure:"forecast_rain_mm"`
+
 	AverageHighTemperature float32 `mapstructure:"avg_high_temperature"`
+	ForecastLowTemperature float32 `mapstructure:"forecast_low_temperature"`
 
 	Error string `mapstructure:"error"`
 }
@@ -60,3 +63,23 @@ func (c *Client) GetAverageHighTemperature(_ time.Duration) (float32, error) {
 
 	return c.AverageHighTemperature, nil
 }
+
+// GetForecastedRain calculates and returns the configured amount of forecasted rain for the given period. It uses
+// the same rain_interval as GetTotalRain
+func (c *Client) GetForecastedRain(ahead time.Duration) (float32, error) {
+	if c.Error != "" {
+		return 0, errors.New(c.Error)
+	}
+
+	numIntervals := float32(ahead.Hours() / c.rainInterval.Hours())
+	return numIntervals * c.ForecastRainMM, nil
+}
+
+// GetForecastedLowTemperature returns the configured value
+func (c *Client) GetForecastedLowTemperature(_ time.Duration) (float32, error) {
+	if c.Error != "" {
+		return 0, errors.New(c.Error)
+	}
+
+	return c.ForecastLowTemperature, nil
+}
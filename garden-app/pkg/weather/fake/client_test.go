@@ -92,3 +92,27 @@ func TestGetTotalRain(t *testing.T) {
 		})
 	}
 }
+
+func TestGetForecastedRain(t *testing.T) {
+	client, err := NewClient(map[string]interface{}{
+		"rain_interval":    "24h",
+		"forecast_rain_mm": 10,
+	})
+	assert.NoError(t, err)
+
+	forecastedRain, err := client.GetForecastedRain(12 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(5), forecastedRain)
+}
+
+func TestGetForecastedLowTemperature(t *testing.T) {
+	client, err := NewClient(map[string]interface{}{
+		"rain_interval":            "24h",
+		"forecast_low_temperature": -2,
+	})
+	assert.NoError(t, err)
+
+	forecastedLow, err := client.GetForecastedLowTemperature(12 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(-2), forecastedLow)
+}
@@ -37,6 +37,54 @@ func (_m *MockClient) GetAverageHighTemperature(since time.Duration) (float32, e
 	return r0, r1
 }
 
+// GetForecastedLowTemperature provides a mock function with given fields: ahead
+func (_m *MockClient) GetForecastedLowTemperature(ahead time.Duration) (float32, error) {
+	ret := _m.Called(ahead)
+
+	var r0 float32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Duration) (float32, error)); ok {
+		return rf(ahead)
+	}
+	if rf, ok := ret.Get(0).(func(time.Duration) float32); ok {
+		r0 = rf(ahead)
+	} else {
+		r0 = ret.Get(0).(float32)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Duration) error); ok {
+		r1 = rf(ahead)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForecastedRain provides a mock function with given fields: ahead
+func (_m *MockClient) GetForecastedRain(ahead time.Duration) (float32, error) {
+	ret := _m.Called(ahead)
+
+	var r0 float32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Duration) (float32, error)); ok {
+		return rf(ahead)
+	}
+	if rf, ok := ret.Get(0).(func(time.Duration) float32); ok {
+		r0 = rf(ahead)
+	} else {
+		r0 = ret.Get(0).(float32)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Duration) error); ok {
+		r1 = rf(ahead)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTotalRain provides a mock function with given fields: since
 func (_m *MockClient) GetTotalRain(since time.Duration) (float32, error) {
 	ret := _m.Called(since)
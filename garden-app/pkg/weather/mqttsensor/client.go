@@ -0,0 +1,154 @@
+package mqttsensor
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Config has the MQTT topic that the sensor publishes readings to
+type Config struct {
+	Topic string `json:"topic" yaml:"topic" mapstructure:"topic"`
+}
+
+// Reading is a single message from the sensor. Fields that were not included in the message are nil
+type Reading struct {
+	Time        time.Time `json:"time"`
+	RainMM      *float32  `json:"rain_mm,omitempty"`
+	Temperature *float32  `json:"temperature,omitempty"`
+}
+
+// Client calculates weather data from readings that were published by a local sensor and recorded in storage
+type Client struct {
+	*Config
+	getReadings func(since time.Time) ([]Reading, error)
+	now         func() time.Time
+}
+
+// NewClient creates a new client. getReadings is used to read the sensor's readings recorded after since
+func NewClient(options map[string]interface{}, getReadings func(since time.Time) ([]Reading, error)) (*Client, error) {
+	client := &Client{getReadings: getReadings, now: time.Now}
+
+	err := mapstructure.Decode(options, &client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Topic == "" {
+		return nil, errors.New("missing required topic")
+	}
+	if strings.ContainsAny(client.Topic, "+#") {
+		return nil, errors.New("topic cannot use wildcards")
+	}
+
+	return client, nil
+}
+
+// SetNow sets the function used to get the current time when calculating the period for weather data
+func (c *Client) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// GetTotalRain adds up the rain from all readings in the period. Rain gauges usually only publish when they
+// measure rain, so no readings means there was no rain
+func (c *Client) GetTotalRain(since time.Duration) (float32, error) {
+	readings, err := c.getReadings(c.now().Add(-since))
+	if err != nil {
+		return 0, fmt.Errorf("error getting readings: %w", err)
+	}
+
+	var total float32
+	for _, r := range readings {
+		if r.RainMM != nil {
+			total += *r.RainMM
+		}
+	}
+
+	return total, nil
+}
+
+// GetAverageHighTemperature finds the highest temperature of each day in the period and returns the average
+func (c *Client) GetAverageHighTemperature(since time.Duration) (float32, error) {
+	readings, err := c.getReadings(c.now().Add(-since))
+	if err != nil {
+		return 0, fmt.Errorf("error getting readings: %w", err)
+	}
+
+	dailyHighs := map[time.Time]float32{}
+	for _, r := range readings {
+		if r.Temperature == nil {
+			continue
+		}
+
+		day := r.Time.Truncate(24 * time.Hour)
+		high, ok := dailyHighs[day]
+		if !ok || *r.Temperature > high {
+			dailyHighs[day] = *r.Temperature
+		}
+	}
+
+	if len(dailyHighs) == 0 {
+		return 0, fmt.Errorf("no temperature readings in the last %s", since)
+	}
+
+	var total float32
+	for _, high := range dailyHighs {
+		total += high
+	}
+
+	return total / float32(len(dailyHighs)), nil
+}
+
+// GetForecastedRain is not supported since the sensor only has measured data
+func (c *Client) GetForecastedRain(_ time.Duration) (float32, error) {
+	return 0, errors.New("rain forecast is not supported by mqtt_sensor clients")
+}
+
+// GetForecastedLowTemperature is not supported since the sensor only has measured data
+func (c *Client) GetForecastedLowTemperature(_ time.Duration) (float32, error) {
+	return 0, errors.New("temperature forecast is not supported by mqtt_sensor clients")
+}
+
+// ParseReading reads the fields from a message like "weather rain_mm=0.2,temperature=21.5". The measurement name
+// is optional and at least one of the rain_mm or temperature fields is required
+func ParseReading(payload []byte, t time.Time) (Reading, error) {
+	fields := strings.TrimSpace(string(payload))
+	if _, after, found := strings.Cut(fields, " "); found {
+		fields = strings.TrimSpace(after)
+	}
+
+	reading := Reading{Time: t}
+	for _, field := range strings.Split(fields, ",") {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			return Reading{}, fmt.Errorf("invalid field %q", field)
+		}
+
+		var target **float32
+		switch key {
+		case "rain_mm":
+			target = &reading.RainMM
+		case "temperature":
+			target = &reading.Temperature
+		default:
+			continue
+		}
+
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return Reading{}, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		f32 := float32(f)
+		*target = &f32
+	}
+
+	if reading.RainMM == nil && reading.Temperature == nil {
+		return Reading{}, errors.New("missing rain_mm and temperature fields")
+	}
+
+	return reading, nil
+}
@@ -0,0 +1,170 @@
+package mqttsensor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func float32Pointer(f float32) *float32 {
+	return &f
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     map[string]interface{}
+		expectedErr string
+	}{
+		{
+			"Successful",
+			map[string]interface{}{"topic": "garden/data/rain"},
+			"",
+		},
+		{
+			"ErrorMissingTopic",
+			map[string]interface{}{},
+			"missing required topic",
+		},
+		{
+			"ErrorWildcard",
+			map[string]interface{}{"topic": "+/data/rain"},
+			"topic cannot use wildcards",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(tt.options, nil)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	now := time.Date(2023, time.August, 23, 12, 0, 0, 0, time.UTC)
+	readings := []Reading{
+		{Time: now.Add(-1 * time.Hour), RainMM: float32Pointer(1.5), Temperature: float32Pointer(30)},
+		{Time: now.Add(-2 * time.Hour), Temperature: float32Pointer(32)},
+		{Time: now.Add(-24 * time.Hour), RainMM: float32Pointer(2)},
+		{Time: now.Add(-26 * time.Hour), Temperature: float32Pointer(20)},
+	}
+
+	var requestedSince time.Time
+	client, err := NewClient(map[string]interface{}{"topic": "garden/data/rain"}, func(since time.Time) ([]Reading, error) {
+		requestedSince = since
+		result := []Reading{}
+		for _, r := range readings {
+			if !r.Time.Before(since) {
+				result = append(result, r)
+			}
+		}
+		return result, nil
+	})
+	require.NoError(t, err)
+	client.SetNow(func() time.Time { return now })
+
+	t.Run("GetTotalRain", func(t *testing.T) {
+		rain, err := client.GetTotalRain(48 * time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, float32(3.5), rain)
+		assert.Equal(t, now.Add(-48*time.Hour), requestedSince)
+
+		rain, err = client.GetTotalRain(12 * time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, float32(1.5), rain)
+	})
+
+	t.Run("GetAverageHighTemperature", func(t *testing.T) {
+		temp, err := client.GetAverageHighTemperature(48 * time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, float32(26), temp)
+	})
+
+	t.Run("GetAverageHighTemperatureNoReadings", func(t *testing.T) {
+		_, err := client.GetAverageHighTemperature(30 * time.Minute)
+		assert.EqualError(t, err, "no temperature readings in the last 30m0s")
+	})
+
+	t.Run("GetForecastedRain", func(t *testing.T) {
+		_, err := client.GetForecastedRain(24 * time.Hour)
+		assert.Error(t, err)
+	})
+}
+
+func TestClientReadingsError(t *testing.T) {
+	client, err := NewClient(map[string]interface{}{"topic": "garden/data/rain"}, func(time.Time) ([]Reading, error) {
+		return nil, errors.New("storage error")
+	})
+	require.NoError(t, err)
+
+	_, err = client.GetTotalRain(time.Hour)
+	assert.EqualError(t, err, "error getting readings: storage error")
+}
+
+func TestParseReading(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		payload     string
+		expected    Reading
+		expectedErr string
+	}{
+		{
+			"RainAndTemperature",
+			"weather rain_mm=0.2,temperature=21.5",
+			Reading{Time: now, RainMM: float32Pointer(0.2), Temperature: float32Pointer(21.5)},
+			"",
+		},
+		{
+			"NoMeasurementName",
+			"rain_mm=0.2",
+			Reading{Time: now, RainMM: float32Pointer(0.2)},
+			"",
+		},
+		{
+			"UnknownFieldsIgnored",
+			"weather temperature=-3,humidity=40",
+			Reading{Time: now, Temperature: float32Pointer(-3)},
+			"",
+		},
+		{
+			"ErrorInvalidValue",
+			"weather rain_mm=abc",
+			Reading{},
+			`invalid value for rain_mm: strconv.ParseFloat: parsing "abc": invalid syntax`,
+		},
+		{
+			"ErrorInvalidField",
+			"weather rain_mm",
+			Reading{},
+			`invalid field "rain_mm"`,
+		},
+		{
+			"ErrorMissingFields",
+			"weather humidity=40",
+			Reading{},
+			"missing rain_mm and temperature fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reading, err := ParseReading([]byte(tt.payload), now)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, reading)
+		})
+	}
+}
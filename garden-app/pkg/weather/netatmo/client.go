@@ -14,9 +14,14 @@ import (
 )
 
 const (
-	baseURI = "https://api.netatmo.com"
+	// tokenRefreshMargin refreshes the access token a little before it expires so requests do not fail if it
+	// expires while they are in progress
+	tokenRefreshMargin = 5 * time.Minute
 )
 
+// baseURI is a variable so tests can use a local server
+var baseURI = "https://api.netatmo.com"
+
 // Config is specific to the Netatmo API and holds all of the necessary fields for interacting with the API.
 // If StationID is not provided, StationName is used to get it from the API
 // If RainModuleID is not provided, RainModuleName is used to get it from the API
@@ -50,14 +55,16 @@ type Client struct {
 	*http.Client
 	baseURL         *url.URL
 	storageCallback func(map[string]interface{}) error
+	now             func() time.Time
 }
 
 // NewClient creates a new Netatmo API client from configuration
 // If StationID is not provided, StationName is used to get it from the API
 // If RainModuleID is not provided, RainModuleName is used to get it from the API
-// For Authentication, AccessToken, RefreshToken, ClientID and ClientSecret are required
+// For Authentication, AccessToken, RefreshToken, ClientID and ClientSecret are required. Authentication can be
+// left out until tokens are created with the OAuth flow, but the client will return errors until then
 func NewClient(options map[string]interface{}, storageCallback func(map[string]interface{}) error) (*Client, error) {
-	client := &Client{Client: http.DefaultClient, storageCallback: storageCallback}
+	client := &Client{Client: http.DefaultClient, storageCallback: storageCallback, now: time.Now}
 
 	err := mapstructure.Decode(options, &client.Config)
 	if err != nil {
@@ -69,6 +76,20 @@ func NewClient(options map[string]interface{}, storageCallback func(map[string]i
 		return nil, err
 	}
 
+	if client.StationID == "" && client.StationName == "" {
+		return nil, errors.New("station_id or station_name must be provided")
+	}
+	if client.RainModuleID == "" && client.RainModuleName == "" {
+		return nil, errors.New("rain_module_id or rain_module_name must be provided")
+	}
+	if client.OutdoorModuleID == "" && client.OutdoorModuleName == "" {
+		return nil, errors.New("outdoor_module_id or outdoor_module_name must be provided")
+	}
+
+	if client.Authentication == nil {
+		return client, nil
+	}
+
 	if client.StationID == "" || client.RainModuleID == "" || client.OutdoorModuleID == "" {
 		err = client.setDeviceIDs()
 		if err != nil {
@@ -79,6 +100,11 @@ func NewClient(options map[string]interface{}, storageCallback func(map[string]i
 	return client, nil
 }
 
+// SetNow sets the function used to get the current time when calculating the period for weather data
+func (c *Client) SetNow(now func() time.Time) {
+	c.now = now
+}
+
 type stationDataResponse struct {
 	Body struct {
 		Devices []station `json:"devices"`
@@ -139,16 +165,6 @@ func (c *Client) getStationData() (stationDataResponse, error) {
 }
 
 func (c *Client) setDeviceIDs() error {
-	if c.StationID == "" && c.StationName == "" {
-		return errors.New("station_id or station_name must be provided")
-	}
-	if c.RainModuleID == "" && c.RainModuleName == "" {
-		return errors.New("rain_module_id or rain_module_name must be provided")
-	}
-	if c.OutdoorModuleID == "" && c.OutdoorModuleName == "" {
-		return errors.New("outdoor_module_id or outdoor_module_name must be provided")
-	}
-
 	stationData, err := c.getStationData()
 	if err != nil {
 		return err
@@ -189,12 +205,17 @@ func (c *Client) setDeviceIDs() error {
 	return nil
 }
 
+// refreshToken gets a new access token when the current one is expired or about to expire
 func (c *Client) refreshToken() error {
+	if c.Authentication == nil {
+		return errors.New("missing authentication: use the OAuth flow to authorize this client")
+	}
+
 	// It's safe to ignore the time.Parse error because knowing the expiration is an optional early exit
 	expiry, _ := time.Parse(time.RFC3339Nano, c.Config.Authentication.ExpirationDate)
 
 	// Exit early if token is not expired
-	if time.Now().Before(expiry) {
+	if time.Now().Add(tokenRefreshMargin).Before(expiry) {
 		return nil
 	}
 
@@ -205,7 +226,10 @@ func (c *Client) refreshToken() error {
 		"client_secret": {c.ClientSecret},
 	}
 
-	req, err := http.NewRequest("POST", "https://api.netatmo.com/oauth2/token", strings.NewReader(formData.Encode()))
+	tokenURL := *c.baseURL
+	tokenURL.Path = "/oauth2/token"
+
+	req, err := http.NewRequest(http.MethodPost, tokenURL.String(), strings.NewReader(formData.Encode()))
 	if err != nil {
 		return err
 	}
@@ -232,25 +256,33 @@ func (c *Client) refreshToken() error {
 	c.Authentication.ExpirationDate = time.Now().Add(time.Duration(c.Authentication.ExpiresIn) * time.Second).Format(time.RFC3339Nano)
 
 	// Use storage callback to save new authentication details
-	err = c.storageCallback(map[string]interface{}{
-		"station_id":          c.Config.StationID,
-		"station_name":        c.Config.StationName,
-		"rain_module_id":      c.Config.RainModuleID,
-		"rain_module_name":    c.Config.RainModuleName,
-		"outdoor_module_id":   c.Config.OutdoorModuleID,
-		"outdoor_module_name": c.Config.OutdoorModuleName,
-		"authentication": map[string]interface{}{
-			"access_token":    c.Config.Authentication.AccessToken,
-			"refresh_token":   c.Config.Authentication.RefreshToken,
-			"expires_in":      c.Config.Authentication.ExpiresIn,
-			"expiration_date": c.Config.Authentication.ExpirationDate,
-		},
-		"client_id":     c.Config.ClientID,
-		"client_secret": c.Config.ClientSecret,
-	})
+	err = c.storageCallback(c.Config.options())
 	if err != nil {
 		return fmt.Errorf("error executing storage callback to store new tokens: %w", err)
 	}
 
 	return nil
 }
+
+// options converts the Config back to the options map used to store it
+func (c *Config) options() map[string]interface{} {
+	result := map[string]interface{}{
+		"station_id":          c.StationID,
+		"station_name":        c.StationName,
+		"rain_module_id":      c.RainModuleID,
+		"rain_module_name":    c.RainModuleName,
+		"outdoor_module_id":   c.OutdoorModuleID,
+		"outdoor_module_name": c.OutdoorModuleName,
+		"client_id":           c.ClientID,
+		"client_secret":       c.ClientSecret,
+	}
+	if c.Authentication != nil {
+		result["authentication"] = map[string]interface{}{
+			"access_token":    c.Authentication.AccessToken,
+			"refresh_token":   c.Authentication.RefreshToken,
+			"expires_in":      c.Authentication.ExpiresIn,
+			"expiration_date": c.Authentication.ExpirationDate,
+		}
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/oauth2"
+)
+
+// authScope allows reading data from the user's weather stations
+const authScope = "read_station"
+
+// newOAuth2Config creates the config for Netatmo's authorization code flow using the client_id and client_secret
+// from the options
+func newOAuth2Config(options map[string]interface{}, redirectURL string) (*Config, *oauth2.Config, error) {
+	var cfg Config
+	err := mapstructure.Decode(options, &cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, nil, errors.New("client_id and client_secret are required for OAuth")
+	}
+
+	return &cfg, &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{authScope},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   baseURI + "/oauth2/authorize",
+			TokenURL:  baseURI + "/oauth2/token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}, nil
+}
+
+// AuthCodeURL returns the URL that users are redirected to in order to authorize access to their weather station.
+// After authorizing, Netatmo redirects to redirectURL with the code and state
+func AuthCodeURL(options map[string]interface{}, redirectURL, state string) (string, error) {
+	_, oauth2Config, err := newOAuth2Config(options, redirectURL)
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2Config.AuthCodeURL(state), nil
+}
+
+// ExchangeCode gets new tokens using the code from the OAuth callback. It returns the options with the new
+// authentication details so they can be stored
+func ExchangeCode(ctx context.Context, options map[string]interface{}, redirectURL, code string) (map[string]interface{}, error) {
+	cfg, oauth2Config, err := newOAuth2Config(options, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauth2Config.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging code: %w", err)
+	}
+
+	cfg.Authentication = &TokenData{
+		AccessToken:    token.AccessToken,
+		RefreshToken:   token.RefreshToken,
+		ExpiresIn:      int(time.Until(token.Expiry).Seconds()),
+		ExpirationDate: token.Expiry.Format(time.RFC3339Nano),
+	}
+
+	return cfg.options(), nil
+}
@@ -0,0 +1,147 @@
+package netatmo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTokenServer starts a server that responds to token requests and uses it as the Netatmo API
+func setupTokenServer(t *testing.T, check func(url.Values)) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/oauth2/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		check(r.PostForm)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "new-access-token",
+			"refresh_token": "new-refresh-token",
+			"expires_in":    10800,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	original := baseURI
+	baseURI = server.URL
+	t.Cleanup(func() { baseURI = original })
+}
+
+func testOptions() map[string]interface{} {
+	return map[string]interface{}{
+		"station_name":        "Station",
+		"rain_module_name":    "Rain",
+		"outdoor_module_name": "Outdoor",
+		"client_id":           "client-id",
+		"client_secret":       "client-secret",
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	authURL, err := AuthCodeURL(testOptions(), "http://localhost/callback", "state")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "api.netatmo.com", u.Host)
+	assert.Equal(t, "/oauth2/authorize", u.Path)
+	assert.Equal(t, "client-id", u.Query().Get("client_id"))
+	assert.Equal(t, "http://localhost/callback", u.Query().Get("redirect_uri"))
+	assert.Equal(t, "read_station", u.Query().Get("scope"))
+	assert.Equal(t, "state", u.Query().Get("state"))
+}
+
+func TestAuthCodeURLMissingClientSecret(t *testing.T) {
+	options := testOptions()
+	delete(options, "client_secret")
+
+	_, err := AuthCodeURL(options, "http://localhost/callback", "state")
+	require.Error(t, err)
+	assert.Equal(t, "client_id and client_secret are required for OAuth", err.Error())
+}
+
+func TestExchangeCode(t *testing.T) {
+	setupTokenServer(t, func(form url.Values) {
+		assert.Equal(t, "authorization_code", form.Get("grant_type"))
+		assert.Equal(t, "code", form.Get("code"))
+		assert.Equal(t, "client-secret", form.Get("client_secret"))
+	})
+
+	options, err := ExchangeCode(context.Background(), testOptions(), "http://localhost/callback", "code")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Station", options["station_name"])
+	auth, ok := options["authentication"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "new-access-token", auth["access_token"])
+	assert.Equal(t, "new-refresh-token", auth["refresh_token"])
+
+	expiry, err := time.Parse(time.RFC3339Nano, auth["expiration_date"].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(3*time.Hour), expiry, time.Minute)
+}
+
+func TestRefreshTokenBeforeExpiry(t *testing.T) {
+	tests := []struct {
+		name            string
+		expiresIn       time.Duration
+		expectedRefresh bool
+	}{
+		{"NotExpiringSoon", time.Hour, false},
+		{"ExpiringSoon", time.Minute, true},
+		{"Expired", -time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshed := false
+			setupTokenServer(t, func(form url.Values) {
+				refreshed = true
+				assert.Equal(t, "refresh_token", form.Get("grant_type"))
+				assert.Equal(t, "refresh-token", form.Get("refresh_token"))
+			})
+
+			options := testOptions()
+			options["station_id"] = "station"
+			options["rain_module_id"] = "rain"
+			options["outdoor_module_id"] = "outdoor"
+			options["authentication"] = map[string]interface{}{
+				"access_token":    "access-token",
+				"refresh_token":   "refresh-token",
+				"expiration_date": time.Now().Add(tt.expiresIn).Format(time.RFC3339Nano),
+			}
+
+			var stored map[string]interface{}
+			client, err := NewClient(options, func(m map[string]interface{}) error {
+				stored = m
+				return nil
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, client.refreshToken())
+			assert.Equal(t, tt.expectedRefresh, refreshed)
+			if tt.expectedRefresh {
+				assert.Equal(t, "new-access-token", client.Authentication.AccessToken)
+				assert.Equal(t, "new-access-token", stored["authentication"].(map[string]interface{})["access_token"])
+			}
+		})
+	}
+}
+
+func TestRefreshTokenMissingAuthentication(t *testing.T) {
+	client, err := NewClient(testOptions(), nil)
+	require.NoError(t, err)
+
+	err = client.refreshToken()
+	require.Error(t, err)
+	assert.Equal(t, "missing authentication: use the OAuth flow to authorize this client", err.Error())
+}
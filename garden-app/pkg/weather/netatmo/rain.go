@@ -1,6 +1,7 @@
 package netatmo
 
 import (
+	"errors"
 	"time"
 )
 
@@ -13,7 +14,7 @@ func (c *Client) GetTotalRain(since time.Duration) (float32, error) {
 		since = minRainInterval
 	}
 
-	beginDate := time.Now().Add(-since)
+	beginDate := c.now().Add(-since)
 	rainData, err := c.getMeasure("sum_rain", "1day", beginDate, nil)
 	if err != nil {
 		return 0, err
@@ -21,3 +22,8 @@ func (c *Client) GetTotalRain(since time.Duration) (float32, error) {
 
 	return rainData.Total(), nil
 }
+
+// GetForecastedRain is not supported because Netatmo weather stations only provide measured data
+func (c *Client) GetForecastedRain(_ time.Duration) (float32, error) {
+	return 0, errors.New("netatmo does not support rain forecasts")
+}
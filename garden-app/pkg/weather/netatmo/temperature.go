@@ -1,6 +1,7 @@
 package netatmo
 
 import (
+	"errors"
 	"time"
 )
 
@@ -14,7 +15,7 @@ func (c *Client) GetAverageHighTemperature(since time.Duration) (float32, error)
 		since = minTemperatureInterval
 	}
 
-	now := time.Now()
+	now := c.now()
 	beginDate := now.Add(-since).Truncate(time.Hour)
 	beginDate = time.Date(beginDate.Year(), beginDate.Month(), beginDate.Day()-1, 23, 59, 59, 0, time.Local)
 	// Since we are looking at daily max temp, get time all the way to very end of yesterday
@@ -27,3 +28,8 @@ func (c *Client) GetAverageHighTemperature(since time.Duration) (float32, error)
 
 	return temperatureData.Average(), nil
 }
+
+// GetForecastedLowTemperature is not supported because Netatmo weather stations only provide measured data
+func (c *Client) GetForecastedLowTemperature(_ time.Duration) (float32, error) {
+	return 0, errors.New("netatmo does not support temperature forecasts")
+}
@@ -0,0 +1,167 @@
+package openmeteo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	baseURI      = "https://api.open-meteo.com"
+	forecastPath = "/v1/forecast"
+	defaultUnits = "metric"
+
+	// maxPastDays and maxForecastDays are the limits of the forecast API
+	maxPastDays     = 92
+	maxForecastDays = 16
+)
+
+// Config holds the location used for the free Open-Meteo API, which does not require an API key. Units can be "metric"
+// or "imperial" and only affect temperature since rain is always in millimeters
+type Config struct {
+	Latitude  *float64 `json:"lat,omitempty" yaml:"lat,omitempty" mapstructure:"lat,omitempty"`
+	Longitude *float64 `json:"lon,omitempty" yaml:"lon,omitempty" mapstructure:"lon,omitempty"`
+	Units     string   `json:"units,omitempty" yaml:"units,omitempty" mapstructure:"units,omitempty"`
+}
+
+// Client is used to interact with the Open-Meteo API
+type Client struct {
+	*Config
+	*http.Client
+	baseURL *url.URL
+	now     func() time.Time
+}
+
+// NewClient creates a new Open-Meteo API client from configuration
+func NewClient(options map[string]interface{}) (*Client, error) {
+	client := &Client{Client: http.DefaultClient, now: time.Now}
+
+	err := mapstructure.WeakDecode(options, &client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Latitude == nil || client.Longitude == nil {
+		return nil, errors.New("missing required lat and lon")
+	}
+	switch client.Units {
+	case "":
+		client.Units = defaultUnits
+	case "metric", "imperial":
+	default:
+		return nil, fmt.Errorf("invalid units %q", client.Units)
+	}
+
+	client.baseURL, err = url.Parse(baseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// SetNow sets the function used to get the current time when calculating the period for weather data
+func (c *Client) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// forecastResponse is the response from the forecast endpoint. Times are unix timestamps and values are nil when
+// data is not available
+type forecastResponse struct {
+	Hourly struct {
+		Time          []int64    `json:"time"`
+		Precipitation []*float32 `json:"precipitation"`
+		Temperature   []*float32 `json:"temperature_2m"`
+	} `json:"hourly"`
+	Daily struct {
+		Time           []int64    `json:"time"`
+		TemperatureMax []*float32 `json:"temperature_2m_max"`
+	} `json:"daily"`
+}
+
+// getForecast gets hourly or daily data from the forecast endpoint. It includes pastDays before today and
+// forecastDays starting with today
+func (c *Client) getForecast(values url.Values, pastDays, forecastDays int) (forecastResponse, error) {
+	values.Add("past_days", strconv.Itoa(min(pastDays, maxPastDays)))
+	values.Add("forecast_days", strconv.Itoa(min(forecastDays, maxForecastDays)))
+
+	var result forecastResponse
+	err := c.get(forecastPath, values, &result)
+	return result, err
+}
+
+// get executes a GET request against the API with common parameters and decodes the JSON response
+func (c *Client) get(path string, values url.Values, result interface{}) error {
+	reqURL := *c.baseURL
+	reqURL.Path = path
+
+	values.Add("latitude", fmt.Sprintf("%f", *c.Latitude))
+	values.Add("longitude", fmt.Sprintf("%f", *c.Longitude))
+	values.Add("timeformat", "unixtime")
+	if c.Units == "imperial" {
+		values.Add("temperature_unit", "fahrenheit")
+	}
+	reqURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body with status %d: %v", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unexpected status %d with body: %s", resp.StatusCode, string(respBody))
+	}
+
+	err = json.Unmarshal(respBody, result)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal response body: %w", err)
+	}
+
+	return nil
+}
+
+// valuesInPeriod returns the values with a time after start and not after end. Missing values are skipped
+func valuesInPeriod(times []int64, values []*float32, start, end time.Time) []float32 {
+	result := []float32{}
+	for i, ts := range times {
+		if i >= len(values) || values[i] == nil {
+			continue
+		}
+		t := time.Unix(ts, 0)
+		if t.After(start) && !t.After(end) {
+			result = append(result, *values[i])
+		}
+	}
+	return result
+}
+
+// daysInPeriod returns the number of full days needed to cover the period, with a minimum of one
+func daysInPeriod(d time.Duration) int {
+	days := int(d / (24 * time.Hour))
+	if d%(24*time.Hour) != 0 {
+		days++
+	}
+	if days < 1 {
+		days = 1
+	}
+	return days
+}
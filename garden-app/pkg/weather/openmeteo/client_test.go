@@ -0,0 +1,177 @@
+package openmeteo
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       map[string]interface{}
+		expectedErr   string
+		expectedUnits string
+	}{
+		{
+			"Successful",
+			map[string]interface{}{"lat": 32.2, "lon": -110.9},
+			"",
+			"metric",
+		},
+		{
+			"SuccessfulStringCoordinatesAndUnits",
+			map[string]interface{}{"lat": "32.2", "lon": "-110.9", "units": "imperial"},
+			"",
+			"imperial",
+		},
+		{
+			"ErrorMissingLatLon",
+			map[string]interface{}{"lat": 32.2},
+			"missing required lat and lon",
+			"",
+		},
+		{
+			"ErrorInvalidUnits",
+			map[string]interface{}{"lat": 32.2, "lon": -110.9, "units": "standard"},
+			`invalid units "standard"`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.options)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedUnits, client.Units)
+		})
+	}
+}
+
+var now = time.Date(2023, time.April, 2, 12, 0, 0, 0, time.UTC)
+
+func newTestClient(t *testing.T, options map[string]interface{}, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(options)
+	assert.NoError(t, err)
+
+	client.baseURL, err = url.Parse(server.URL)
+	assert.NoError(t, err)
+	client.SetNow(func() time.Time { return now })
+
+	return client
+}
+
+func TestGetTotalRain(t *testing.T) {
+	client := newTestClient(t, map[string]interface{}{"lat": 32.2, "lon": -110.9}, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/forecast", r.URL.Path)
+		assert.Equal(t, "precipitation", r.URL.Query().Get("hourly"))
+		assert.Equal(t, "3", r.URL.Query().Get("past_days"))
+		assert.Equal(t, "1", r.URL.Query().Get("forecast_days"))
+		assert.Equal(t, "unixtime", r.URL.Query().Get("timeformat"))
+		assert.Equal(t, "32.200000", r.URL.Query().Get("latitude"))
+
+		fmt.Fprintf(w, `{"hourly":{"time":[%d,%d,%d,%d,%d],"precipitation":[10,1.5,null,2,10]}}`,
+			now.Add(-49*time.Hour).Unix(),
+			now.Add(-47*time.Hour).Unix(),
+			now.Add(-24*time.Hour).Unix(),
+			now.Unix(),
+			now.Add(time.Hour).Unix(),
+		)
+	})
+
+	totalRain, err := client.GetTotalRain(48 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3.5), totalRain)
+}
+
+func TestGetTotalRainErrorStatus(t *testing.T) {
+	client := newTestClient(t, map[string]interface{}{"lat": 32.2, "lon": -110.9}, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":true,"reason":"Latitude must be in range of -90 to 90°"}`)
+	})
+
+	_, err := client.GetTotalRain(24 * time.Hour)
+	assert.EqualError(t, err, `received unexpected status 400 with body: {"error":true,"reason":"Latitude must be in range of -90 to 90°"}`)
+}
+
+func TestGetAverageHighTemperature(t *testing.T) {
+	today := time.Date(2023, time.April, 2, 0, 0, 0, 0, time.UTC)
+
+	client := newTestClient(t, map[string]interface{}{"lat": 32.2, "lon": -110.9, "units": "imperial"}, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "temperature_2m_max", r.URL.Query().Get("daily"))
+		assert.Equal(t, "fahrenheit", r.URL.Query().Get("temperature_unit"))
+		// Less than 72h is increased to the minimum
+		assert.Equal(t, "3", r.URL.Query().Get("past_days"))
+
+		fmt.Fprintf(w, `{"daily":{"time":[%d,%d,%d,%d],"temperature_2m_max":[20,25,30,100]}}`,
+			today.AddDate(0, 0, -3).Unix(),
+			today.AddDate(0, 0, -2).Unix(),
+			today.AddDate(0, 0, -1).Unix(),
+			today.Unix(),
+		)
+	})
+
+	avgHighTemp, err := client.GetAverageHighTemperature(24 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(25), avgHighTemp)
+}
+
+func TestGetForecastedRain(t *testing.T) {
+	client := newTestClient(t, map[string]interface{}{"lat": 32.2, "lon": -110.9}, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "precipitation,temperature_2m", r.URL.Query().Get("hourly"))
+		assert.Equal(t, "0", r.URL.Query().Get("past_days"))
+		assert.Equal(t, "2", r.URL.Query().Get("forecast_days"))
+
+		fmt.Fprintf(w, `{"hourly":{"time":[%d,%d,%d,%d,%d],"precipitation":[10,1.5,0,2,10]}}`,
+			now.Unix(),
+			now.Add(1*time.Hour).Unix(),
+			now.Add(2*time.Hour).Unix(),
+			now.Add(3*time.Hour).Unix(),
+			now.Add(5*time.Hour).Unix(),
+		)
+	})
+
+	forecastedRain, err := client.GetForecastedRain(3 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3.5), forecastedRain)
+}
+
+func TestGetForecastedLowTemperature(t *testing.T) {
+	client := newTestClient(t, map[string]interface{}{"lat": 32.2, "lon": -110.9}, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"hourly":{"time":[%d,%d,%d,%d],"temperature_2m":[4.5,1.5,null,-3]}}`,
+			now.Unix(),
+			now.Add(1*time.Hour).Unix(),
+			now.Add(2*time.Hour).Unix(),
+			now.Add(5*time.Hour).Unix(),
+		)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		forecastedLow, err := client.GetForecastedLowTemperature(3 * time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, float32(1.5), forecastedLow)
+	})
+
+	t.Run("ErrorNoForecast", func(t *testing.T) {
+		client.SetNow(func() time.Time {
+			return now.Add(-24 * time.Hour)
+		})
+
+		_, err := client.GetForecastedLowTemperature(3 * time.Hour)
+		assert.EqualError(t, err, "no hourly forecast available for the period")
+	})
+}
@@ -0,0 +1,54 @@
+package openmeteo
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// GetForecastedRain returns the sum of all forecasted rainfall in millimeters for the given period. The API only
+// provides a 16 day forecast, so longer periods are limited to that
+func (c *Client) GetForecastedRain(ahead time.Duration) (float32, error) {
+	forecast, err := c.getHourlyForecast(ahead)
+	if err != nil {
+		return 0, err
+	}
+
+	now := c.now()
+
+	var total float32
+	for _, rain := range valuesInPeriod(forecast.Hourly.Time, forecast.Hourly.Precipitation, now, now.Add(ahead)) {
+		total += rain
+	}
+	return total, nil
+}
+
+// GetForecastedLowTemperature returns the lowest forecasted temperature for the given period in the configured units.
+// The current hour is included since it can already be the coldest
+func (c *Client) GetForecastedLowTemperature(ahead time.Duration) (float32, error) {
+	forecast, err := c.getHourlyForecast(ahead)
+	if err != nil {
+		return 0, err
+	}
+
+	now := c.now()
+	temperatures := valuesInPeriod(forecast.Hourly.Time, forecast.Hourly.Temperature, now.Add(-time.Hour), now.Add(ahead))
+	if len(temperatures) == 0 {
+		return 0, errors.New("no hourly forecast available for the period")
+	}
+
+	low := temperatures[0]
+	for _, temperature := range temperatures[1:] {
+		low = min(low, temperature)
+	}
+	return low, nil
+}
+
+// getHourlyForecast gets the hourly rain and temperature forecast starting today and covering the period
+func (c *Client) getHourlyForecast(ahead time.Duration) (forecastResponse, error) {
+	values := url.Values{}
+	values.Add("hourly", "precipitation,temperature_2m")
+
+	// an extra day is needed since the period can end after midnight on the last day
+	return c.getForecast(values, 0, daysInPeriod(ahead)+1)
+}
@@ -0,0 +1,28 @@
+package openmeteo
+
+import (
+	"net/url"
+	"time"
+)
+
+// GetTotalRain returns the sum of all rainfall in millimeters in the given period. Each hourly value is the rain in
+// the hour before it, so this includes the hours that ended in the period. The API only provides 92 days of past
+// data, so longer periods are limited to that
+func (c *Client) GetTotalRain(since time.Duration) (float32, error) {
+	values := url.Values{}
+	values.Add("hourly", "precipitation")
+
+	// an extra past day is needed since the period can start before midnight on the first day
+	forecast, err := c.getForecast(values, daysInPeriod(since)+1, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	now := c.now()
+
+	var total float32
+	for _, rain := range valuesInPeriod(forecast.Hourly.Time, forecast.Hourly.Precipitation, now.Add(-since), now) {
+		total += rain
+	}
+	return total, nil
+}
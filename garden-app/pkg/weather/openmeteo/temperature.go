@@ -0,0 +1,43 @@
+package openmeteo
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const minTemperatureInterval = 72 * time.Hour
+
+// GetAverageHighTemperature returns the average daily high temperature between the given time and the end of
+// yesterday (since daily high can be misleading if queried mid-day). Days use UTC since that is the API's default
+// time zone
+func (c *Client) GetAverageHighTemperature(since time.Duration) (float32, error) {
+	// Time to check since must always be at least 3 days
+	if since < minTemperatureInterval {
+		since = minTemperatureInterval
+	}
+
+	days := daysInPeriod(since)
+
+	values := url.Values{}
+	values.Add("daily", "temperature_2m_max")
+
+	forecast, err := c.getForecast(values, days, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	// daily times are at midnight UTC, so this includes each day in the period ending with yesterday
+	today := c.now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	highs := valuesInPeriod(forecast.Daily.Time, forecast.Daily.TemperatureMax, yesterday.AddDate(0, 0, -days), yesterday)
+	if len(highs) == 0 {
+		return 0, errors.New("no daily temperatures available for the period")
+	}
+
+	var total float32
+	for _, high := range highs {
+		total += high
+	}
+	return total / float32(len(highs)), nil
+}
@@ -0,0 +1,149 @@
+package openweathermap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	baseURI      = "https://api.openweathermap.org"
+	dateFormat   = "2006-01-02"
+	defaultUnits = "metric"
+)
+
+// Config is specific to the OpenWeatherMap One Call API and holds all of the necessary fields for interacting with it.
+// Units can be "standard", "metric", or "imperial" and only affect temperature since rain is always in millimeters
+type Config struct {
+	APIKey    string   `json:"api_key,omitempty" yaml:"api_key,omitempty" mapstructure:"api_key,omitempty"`
+	Latitude  *float64 `json:"lat,omitempty" yaml:"lat,omitempty" mapstructure:"lat,omitempty"`
+	Longitude *float64 `json:"lon,omitempty" yaml:"lon,omitempty" mapstructure:"lon,omitempty"`
+	Units     string   `json:"units,omitempty" yaml:"units,omitempty" mapstructure:"units,omitempty"`
+}
+
+// Client is used to interact with the OpenWeatherMap API
+type Client struct {
+	*Config
+	*http.Client
+	baseURL *url.URL
+	now     func() time.Time
+}
+
+// NewClient creates a new OpenWeatherMap API client from configuration
+func NewClient(options map[string]interface{}) (*Client, error) {
+	client := &Client{Client: http.DefaultClient, now: time.Now}
+
+	err := mapstructure.WeakDecode(options, &client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.APIKey == "" {
+		return nil, errors.New("missing required api_key")
+	}
+	if client.Latitude == nil || client.Longitude == nil {
+		return nil, errors.New("missing required lat and lon")
+	}
+	switch client.Units {
+	case "":
+		client.Units = defaultUnits
+	case "standard", "metric", "imperial":
+	default:
+		return nil, fmt.Errorf("invalid units %q", client.Units)
+	}
+
+	client.baseURL, err = url.Parse(baseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// SetNow sets the function used to get the current time when calculating the period for weather data
+func (c *Client) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// daySummary is the response from the One Call day_summary endpoint
+type daySummary struct {
+	Date          string `json:"date"`
+	Precipitation struct {
+		Total float32 `json:"total"`
+	} `json:"precipitation"`
+	Temperature struct {
+		Min float32 `json:"min"`
+		Max float32 `json:"max"`
+	} `json:"temperature"`
+}
+
+// getDaySummary gets the aggregated weather data for a single day
+func (c *Client) getDaySummary(date time.Time) (daySummary, error) {
+	values := url.Values{}
+	values.Add("date", date.Format(dateFormat))
+
+	var result daySummary
+	err := c.get("/data/3.0/onecall/day_summary", values, &result)
+	if err != nil {
+		return daySummary{}, err
+	}
+	return result, nil
+}
+
+// get executes a GET request against the API with common parameters and decodes the JSON response
+func (c *Client) get(path string, values url.Values, result interface{}) error {
+	reqURL := *c.baseURL
+	reqURL.Path = path
+
+	values.Add("lat", fmt.Sprintf("%f", *c.Latitude))
+	values.Add("lon", fmt.Sprintf("%f", *c.Longitude))
+	values.Add("units", c.Units)
+	values.Add("appid", c.APIKey)
+	reqURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body with status %d: %v", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unexpected status %d with body: %s", resp.StatusCode, string(respBody))
+	}
+
+	err = json.Unmarshal(respBody, result)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal response body: %w", err)
+	}
+
+	return nil
+}
+
+// daysInPeriod returns the number of full days needed to cover the period, with a minimum of one
+func daysInPeriod(since time.Duration) int {
+	days := int(since / (24 * time.Hour))
+	if since%(24*time.Hour) != 0 {
+		days++
+	}
+	if days < 1 {
+		days = 1
+	}
+	return days
+}
@@ -0,0 +1,190 @@
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       map[string]interface{}
+		expectedErr   string
+		expectedUnits string
+	}{
+		{
+			"Successful",
+			map[string]interface{}{"api_key": "key", "lat": 32.2, "lon": -110.9},
+			"",
+			"metric",
+		},
+		{
+			"SuccessfulStringCoordinatesAndUnits",
+			map[string]interface{}{"api_key": "key", "lat": "32.2", "lon": "-110.9", "units": "imperial"},
+			"",
+			"imperial",
+		},
+		{
+			"ErrorMissingAPIKey",
+			map[string]interface{}{"lat": 32.2, "lon": -110.9},
+			"missing required api_key",
+			"",
+		},
+		{
+			"ErrorMissingLatLon",
+			map[string]interface{}{"api_key": "key", "lat": 32.2},
+			"missing required lat and lon",
+			"",
+		},
+		{
+			"ErrorInvalidUnits",
+			map[string]interface{}{"api_key": "key", "lat": 32.2, "lon": -110.9, "units": "kelvin"},
+			`invalid units "kelvin"`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.options)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedUnits, client.Units)
+		})
+	}
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(map[string]interface{}{"api_key": "key", "lat": 32.2, "lon": -110.9})
+	assert.NoError(t, err)
+
+	client.baseURL, err = url.Parse(server.URL)
+	assert.NoError(t, err)
+
+	return client
+}
+
+func TestGetTotalRain(t *testing.T) {
+	requestedDates := []string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/data/3.0/onecall/day_summary", r.URL.Path)
+		assert.Equal(t, "key", r.URL.Query().Get("appid"))
+		assert.Equal(t, "metric", r.URL.Query().Get("units"))
+
+		requestedDates = append(requestedDates, r.URL.Query().Get("date"))
+		fmt.Fprint(w, `{"precipitation":{"total":2.5},"temperature":{"min":10,"max":30}}`)
+	})
+
+	totalRain, err := client.GetTotalRain(48 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(5), totalRain)
+	assert.Equal(t, []string{
+		time.Now().AddDate(0, 0, -1).Format(dateFormat),
+		time.Now().Format(dateFormat),
+	}, requestedDates)
+}
+
+func TestGetAverageHighTemperature(t *testing.T) {
+	temperatures := []float32{20, 25, 30}
+	requestedDates := []string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		temp := temperatures[len(requestedDates)]
+		requestedDates = append(requestedDates, r.URL.Query().Get("date"))
+		fmt.Fprintf(w, `{"precipitation":{"total":0},"temperature":{"min":10,"max":%f}}`, temp)
+	})
+
+	// Less than 72h is increased to the minimum
+	avgHighTemp, err := client.GetAverageHighTemperature(24 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(25), avgHighTemp)
+	assert.Len(t, requestedDates, 3)
+	assert.Equal(t, time.Now().AddDate(0, 0, -1).Format(dateFormat), requestedDates[2])
+}
+
+func TestGetTotalRainWithSetNow(t *testing.T) {
+	requestedDates := []string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requestedDates = append(requestedDates, r.URL.Query().Get("date"))
+		fmt.Fprint(w, `{"precipitation":{"total":2.5},"temperature":{"min":10,"max":30}}`)
+	})
+	client.SetNow(func() time.Time {
+		return time.Date(2023, time.April, 2, 12, 0, 0, 0, time.UTC)
+	})
+
+	_, err := client.GetTotalRain(48 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2023-04-01", "2023-04-02"}, requestedDates)
+}
+
+func TestGetTotalRainErrorStatus(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"cod":401,"message":"Invalid API key"}`)
+	})
+
+	_, err := client.GetTotalRain(24 * time.Hour)
+	assert.EqualError(t, err, `received unexpected status 401 with body: {"cod":401,"message":"Invalid API key"}`)
+}
+
+func TestGetForecastedRain(t *testing.T) {
+	now := time.Now()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/data/3.0/onecall", r.URL.Path)
+		assert.Equal(t, "current,minutely,daily,alerts", r.URL.Query().Get("exclude"))
+
+		fmt.Fprintf(w, `{"hourly":[{"dt":%d,"rain":{"1h":1.5}},{"dt":%d},{"dt":%d,"rain":{"1h":2}},{"dt":%d,"rain":{"1h":10}}]}`,
+			now.Unix(),
+			now.Add(1*time.Hour).Unix(),
+			now.Add(2*time.Hour).Unix(),
+			now.Add(5*time.Hour).Unix(),
+		)
+	})
+
+	forecastedRain, err := client.GetForecastedRain(3 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3.5), forecastedRain)
+}
+
+func TestGetForecastedLowTemperature(t *testing.T) {
+	now := time.Now()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/data/3.0/onecall", r.URL.Path)
+		assert.Equal(t, "metric", r.URL.Query().Get("units"))
+
+		fmt.Fprintf(w, `{"hourly":[{"dt":%d,"temp":4.5},{"dt":%d,"temp":1.5},{"dt":%d,"temp":2},{"dt":%d,"temp":-3}]}`,
+			now.Unix(),
+			now.Add(1*time.Hour).Unix(),
+			now.Add(2*time.Hour).Unix(),
+			now.Add(5*time.Hour).Unix(),
+		)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		forecastedLow, err := client.GetForecastedLowTemperature(3 * time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, float32(1.5), forecastedLow)
+	})
+
+	t.Run("ErrorNoForecast", func(t *testing.T) {
+		client.SetNow(func() time.Time {
+			return now.Add(-24 * time.Hour)
+		})
+
+		_, err := client.GetForecastedLowTemperature(3 * time.Hour)
+		assert.EqualError(t, err, "no hourly forecast available for the period")
+	})
+}
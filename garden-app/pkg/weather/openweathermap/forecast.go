@@ -0,0 +1,77 @@
+package openweathermap
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// hourlyForecast is the response from the One Call endpoint when only hourly data is requested
+type hourlyForecast struct {
+	Hourly []struct {
+		Timestamp   int64   `json:"dt"`
+		Temperature float32 `json:"temp"`
+		Rain        struct {
+			OneHour float32 `json:"1h"`
+		} `json:"rain"`
+	} `json:"hourly"`
+}
+
+// GetForecastedRain returns the sum of all forecasted rainfall in millimeters for the given period. The API only
+// provides an hourly forecast for the next 48 hours, so longer periods are limited to that
+func (c *Client) GetForecastedRain(ahead time.Duration) (float32, error) {
+	forecast, err := c.getHourlyForecast()
+	if err != nil {
+		return 0, err
+	}
+
+	end := c.now().Add(ahead)
+
+	var total float32
+	for _, hour := range forecast.Hourly {
+		if time.Unix(hour.Timestamp, 0).After(end) {
+			break
+		}
+		total += hour.Rain.OneHour
+	}
+
+	return total, nil
+}
+
+// GetForecastedLowTemperature returns the lowest forecasted temperature for the given period in the configured units.
+// Like GetForecastedRain, this is limited to the next 48 hours
+func (c *Client) GetForecastedLowTemperature(ahead time.Duration) (float32, error) {
+	forecast, err := c.getHourlyForecast()
+	if err != nil {
+		return 0, err
+	}
+
+	end := c.now().Add(ahead)
+
+	var low float32
+	found := false
+	for _, hour := range forecast.Hourly {
+		if time.Unix(hour.Timestamp, 0).After(end) {
+			break
+		}
+		if !found || hour.Temperature < low {
+			low = hour.Temperature
+			found = true
+		}
+	}
+
+	if !found {
+		return 0, errors.New("no hourly forecast available for the period")
+	}
+	return low, nil
+}
+
+// getHourlyForecast gets the hourly forecast for the next 48 hours
+func (c *Client) getHourlyForecast() (hourlyForecast, error) {
+	values := url.Values{}
+	values.Add("exclude", "current,minutely,daily,alerts")
+
+	var forecast hourlyForecast
+	err := c.get("/data/3.0/onecall", values, &forecast)
+	return forecast, err
+}
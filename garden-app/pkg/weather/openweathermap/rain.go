@@ -0,0 +1,22 @@
+package openweathermap
+
+import (
+	"time"
+)
+
+// GetTotalRain returns the sum of all rainfall in millimeters in the given period. Since data is aggregated by day,
+// this includes each day in the period up to and including today
+func (c *Client) GetTotalRain(since time.Duration) (float32, error) {
+	now := c.now()
+
+	var total float32
+	for i := daysInPeriod(since) - 1; i >= 0; i-- {
+		summary, err := c.getDaySummary(now.AddDate(0, 0, -i))
+		if err != nil {
+			return 0, err
+		}
+		total += summary.Precipitation.Total
+	}
+
+	return total, nil
+}
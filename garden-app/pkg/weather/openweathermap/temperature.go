@@ -0,0 +1,30 @@
+package openweathermap
+
+import (
+	"time"
+)
+
+const minTemperatureInterval = 72 * time.Hour
+
+// GetAverageHighTemperature returns the average daily high temperature between the given time and the end of
+// yesterday (since daily high can be misleading if queried mid-day)
+func (c *Client) GetAverageHighTemperature(since time.Duration) (float32, error) {
+	// Time to check since must always be at least 3 days
+	if since < minTemperatureInterval {
+		since = minTemperatureInterval
+	}
+
+	yesterday := c.now().AddDate(0, 0, -1)
+	days := daysInPeriod(since)
+
+	var total float32
+	for i := days - 1; i >= 0; i-- {
+		summary, err := c.getDaySummary(yesterday.AddDate(0, 0, -i))
+		if err != nil {
+			return 0, err
+		}
+		total += summary.Temperature.Max
+	}
+
+	return total / float32(days), nil
+}
@@ -0,0 +1,170 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/babyapi"
+)
+
+// webhookEventPattern matches Event types like "garden.updated" and filters for all of a resource's Events like
+// "garden.*"
+var webhookEventPattern = regexp.MustCompile(`^[a-z_]+\.([a-z_]+|\*)$`)
+
+// Webhook sends Events to a URL. Each request is signed with the Secret so the receiver can make sure it was sent
+// by this server. Events filters which Event types are sent. It is empty or "*" to send all Events and can use
+// filters like "garden.*" to send all of a resource's Events
+type Webhook struct {
+	ID        babyapi.ID `json:"id" yaml:"id"`
+	Name      string     `json:"name" yaml:"name"`
+	URL       string     `json:"url" yaml:"url"`
+	Secret    string     `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Events    []string   `json:"events,omitempty" yaml:"events,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+}
+
+func (w *Webhook) GetID() string {
+	return w.ID.String()
+}
+
+// String...
+func (w *Webhook) String() string {
+	return fmt.Sprintf("%+v", *w)
+}
+
+func (w *Webhook) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// Bind is used to make this struct compatible with the go-chi webserver for reading incoming
+// JSON requests
+func (w *Webhook) Bind(r *http.Request) error {
+	if w == nil {
+		return errors.New("missing required Webhook fields")
+	}
+
+	err := w.ID.Bind(r)
+	if err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		now := time.Now()
+		w.CreatedAt = &now
+		fallthrough
+	case http.MethodPut:
+		if w.Name == "" {
+			return validation.Missing("name")
+		}
+		if w.URL == "" {
+			return validation.Missing("url")
+		}
+		if w.Secret == "" {
+			return validation.Missing("secret")
+		}
+	}
+
+	if w.URL != "" {
+		err = validateWebhookURL(w.URL)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, e := range w.Events {
+		if e != "*" && !webhookEventPattern.MatchString(e) {
+			return fmt.Errorf("invalid events[%d]: %q must be \"*\" or an event type like \"garden.updated\" or \"garden.*\"", i, e)
+		}
+	}
+
+	return nil
+}
+
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid url %q: scheme must be http or https", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid url %q: missing host", rawURL)
+	}
+	return nil
+}
+
+// Patch allows modifying the name, url, secret, and events. Events are replaced when they are included
+func (w *Webhook) Patch(newWebhook *Webhook) *babyapi.ErrResponse {
+	if newWebhook.Name != "" {
+		w.Name = newWebhook.Name
+	}
+	if newWebhook.URL != "" {
+		w.URL = newWebhook.URL
+	}
+	if newWebhook.Secret != "" {
+		w.Secret = newWebhook.Secret
+	}
+	if newWebhook.Events != nil {
+		w.Events = newWebhook.Events
+	}
+	return nil
+}
+
+// EndDated allows this to satisfy an interface even though the resources does not have end-dates
+func (*Webhook) EndDated() bool {
+	return false
+}
+
+func (*Webhook) SetEndDate(_ time.Time) {}
+
+// Matches returns true if the Webhook sends Events with the type
+func (w *Webhook) Matches(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	for _, e := range w.Events {
+		switch {
+		case e == "*", e == eventType:
+			return true
+		case strings.HasSuffix(e, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(e, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is the result of sending an Event to a Webhook
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPending is used while the request is being sent or retried
+	WebhookDeliveryStatusPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryStatusSucceeded is used when the URL responded with a 2xx status
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryStatusFailed is used when every attempt failed
+	WebhookDeliveryStatusFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery keeps track of sending one Event to a Webhook. ResponseStatus and Error are from the latest
+// attempt
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	WebhookID      string                `json:"webhook_id"`
+	EventType      string                `json:"event_type"`
+	EventID        string                `json:"event_id,omitempty"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	ResponseStatus int                   `json:"response_status,omitempty"`
+	Error          string                `json:"error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookBind(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		webhook     *Webhook
+		expectedErr string
+	}{
+		{
+			"Valid",
+			http.MethodPost,
+			&Webhook{Name: "Alerts", URL: "https://example.com/hook", Secret: "secret", Events: []string{"garden.*", "water_action.executed"}},
+			"",
+		},
+		{
+			"MissingURL",
+			http.MethodPost,
+			&Webhook{Name: "Alerts", Secret: "secret"},
+			"missing required url field",
+		},
+		{
+			"MissingSecret",
+			http.MethodPut,
+			&Webhook{ID: babyapi.NewID(), Name: "Alerts", URL: "https://example.com/hook"},
+			"missing required secret field",
+		},
+		{
+			"InvalidScheme",
+			http.MethodPatch,
+			&Webhook{URL: "ftp://example.com/hook"},
+			`invalid url "ftp://example.com/hook": scheme must be http or https`,
+		},
+		{
+			"InvalidEvent",
+			http.MethodPatch,
+			&Webhook{Events: []string{"garden"}},
+			`invalid events[0]: "garden" must be "*" or an event type like "garden.updated" or "garden.*"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(tt.method, "/webhooks/c5cvhpcbcv45e8bp16dg", nil)
+			err := tt.webhook.Bind(r)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestWebhookMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    []string
+		eventType string
+		expected  bool
+	}{
+		{"NoFilters", nil, "garden.updated", true},
+		{"All", []string{"*"}, "zone.deleted", true},
+		{"Exact", []string{"water_action.executed"}, "water_action.executed", true},
+		{"ExactNoMatch", []string{"water_action.executed"}, "light_action.executed", false},
+		{"Wildcard", []string{"garden.*"}, "garden.created", true},
+		{"WildcardOtherResource", []string{"garden.*"}, "garden_health.changed", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Webhook{Events: tt.events}
+			assert.Equal(t, tt.expected, w.Matches(tt.eventType))
+		})
+	}
+}
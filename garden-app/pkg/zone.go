@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
 	"github.com/calvinmclean/babyapi"
 	"github.com/rs/xid"
 )
@@ -25,6 +26,21 @@ type Zone struct {
 	EndDate          *time.Time   `json:"end_date,omitempty" yaml:"end_date,omitempty"`
 	WaterScheduleIDs []xid.ID     `json:"water_schedule_ids" yaml:"water_schedule_ids"`
 	SkipCount        *uint        `json:"skip_count" yaml:"skip_count"`
+	// SoilType and CropCoefficient scale the durations from the Zone's WaterSchedules so Zones with different
+	// soil or plants can share a WaterSchedule
+	SoilType        SoilType `json:"soil_type,omitempty" yaml:"soil_type,omitempty"`
+	CropCoefficient *float32 `json:"crop_coefficient,omitempty" yaml:"crop_coefficient,omitempty"`
+	// AdjustmentPercent is a manual override applied after weather scaling, like 120 to give a struggling Zone
+	// more water for a while without changing a shared WaterSchedule
+	AdjustmentPercent *uint `json:"adjustment_percent,omitempty" yaml:"adjustment_percent,omitempty"`
+	// SkipRainControl is used for covered Zones, like in a greenhouse, so their WaterSchedules' RainControl and
+	// RainForecastControl do not reduce watering for rain that they do not get
+	SkipRainControl *bool `json:"skip_rain_control,omitempty" yaml:"skip_rain_control,omitempty"`
+	// FlowRate is the liters per minute delivered while the Zone is watering. It overrides the Garden's pricing
+	// flow rate for Zones with different emitters
+	FlowRate *float64 `json:"flow_rate_lpm,omitempty" yaml:"flow_rate_lpm,omitempty"`
+	// HardwareConfig has the garden-controller pins for the Zone, used to render the controller's config
+	HardwareConfig *HardwareConfig `json:"hardware_config,omitempty" yaml:"hardware_config,omitempty"`
 }
 
 func (z *Zone) GetID() string {
@@ -45,6 +61,32 @@ func (z *Zone) SetEndDate(now time.Time) {
 	z.EndDate = &now
 }
 
+// Restore removes the EndDate so the Zone is active again
+func (z *Zone) Restore() {
+	z.EndDate = nil
+}
+
+// SkipsRainControl returns true if the Zone is covered and does not use RainControl or RainForecastControl
+func (z *Zone) SkipsRainControl() bool {
+	return z != nil && z.SkipRainControl != nil && *z.SkipRainControl
+}
+
+// EstimateLiters uses the Zone's FlowRate, or the Garden's pricing flow rate if the Zone doesn't have one, to
+// estimate the liters used when watering for the duration. It returns false if neither has a flow rate
+func (z *Zone) EstimateLiters(g *Garden, d time.Duration) (float64, bool) {
+	flowRate := 0.0
+	if g != nil && g.Pricing != nil {
+		flowRate = g.Pricing.FlowRate
+	}
+	if z.FlowRate != nil {
+		flowRate = *z.FlowRate
+	}
+	if flowRate == 0 {
+		return 0, false
+	}
+	return d.Minutes() * flowRate, true
+}
+
 // Patch allows for easily updating individual fields of a Zone by passing in a new Zone containing
 // the desired values
 func (z *Zone) Patch(newZone *Zone) *babyapi.ErrResponse {
@@ -63,6 +105,33 @@ func (z *Zone) Patch(newZone *Zone) *babyapi.ErrResponse {
 	if newZone.SkipCount != nil {
 		z.SkipCount = newZone.SkipCount
 	}
+	if newZone.SoilType != "" {
+		z.SoilType = newZone.SoilType
+	}
+	if newZone.CropCoefficient != nil {
+		z.CropCoefficient = newZone.CropCoefficient
+	}
+	if newZone.AdjustmentPercent != nil {
+		z.AdjustmentPercent = newZone.AdjustmentPercent
+	}
+	if newZone.SkipRainControl != nil {
+		z.SkipRainControl = newZone.SkipRainControl
+	}
+	if newZone.FlowRate != nil {
+		z.FlowRate = newZone.FlowRate
+	}
+
+	if newZone.HardwareConfig != nil {
+		if z.HardwareConfig == nil {
+			z.HardwareConfig = &HardwareConfig{}
+		}
+		z.HardwareConfig.Patch(newZone.HardwareConfig)
+
+		// If the new HardwareConfig is empty, remove it
+		if newZone.HardwareConfig.isEmpty() {
+			z.HardwareConfig = nil
+		}
+	}
 
 	if len(newZone.WaterScheduleIDs) != 0 {
 		z.WaterScheduleIDs = newZone.WaterScheduleIDs
@@ -103,8 +172,20 @@ func (zd *ZoneDetails) Patch(new *ZoneDetails) {
 
 // WaterHistory holds information about a WaterEvent that occurred in the past
 type WaterHistory struct {
-	Duration   string    `json:"duration"`
+	Duration   *Duration `json:"duration"`
+	RecordTime time.Time `json:"record_time"`
+	// MeasuredLiters is the volume reported by the Zone's flow meter, if it has one
+	MeasuredLiters *float64 `json:"measured_liters,omitempty"`
+	// ExpectedLiters is estimated from the Duration and the Garden's configured flow rate
+	ExpectedLiters *float64 `json:"expected_liters,omitempty"`
+	// Manual is true when watering was started at the controller, like with a button, instead of by the garden-app
+	Manual bool `json:"manual,omitempty"`
+}
+
+// MoistureHistory is the Zone's average soil moisture percentage in a window of time starting at RecordTime
+type MoistureHistory struct {
 	RecordTime time.Time `json:"record_time"`
+	Percent    float64   `json:"percent"`
 }
 
 // ZoneAndGarden allows grouping the Zone and Garden it belongs too and is useful in some cases
@@ -144,10 +225,10 @@ func (z *Zone) Bind(r *http.Request) error {
 			z.CreatedAt = &now
 		}
 		if z.Position == nil {
-			return errors.New("missing required position field")
+			return validation.Missing("position")
 		}
 		if z.Name == "" {
-			return errors.New("missing required name field")
+			return validation.Missing("name")
 		}
 	case http.MethodPatch:
 		if z.EndDate != nil {
@@ -158,6 +239,26 @@ func (z *Zone) Bind(r *http.Request) error {
 		}
 	}
 
+	err = z.SoilType.Validate()
+	if err != nil {
+		return err
+	}
+	if z.CropCoefficient != nil {
+		err = validateCropCoefficient(*z.CropCoefficient)
+		if err != nil {
+			return err
+		}
+	}
+	if z.AdjustmentPercent != nil {
+		err = validateAdjustmentPercent(*z.AdjustmentPercent)
+		if err != nil {
+			return err
+		}
+	}
+	if z.FlowRate != nil && *z.FlowRate < 0 {
+		return errors.New("flow_rate_lpm must not be negative")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+)
+
+// ZoneGroup combines a Garden's Zones so they can share WaterSchedules and actions instead of configuring each
+// Zone separately. Each Zone is still watered individually, so the Garden's MaxConcurrentZones limits how many of
+// them water at the same time
+type ZoneGroup struct {
+	ID               babyapi.ID `json:"id" yaml:"id,omitempty"`
+	Name             string     `json:"name" yaml:"name,omitempty"`
+	GardenID         xid.ID     `json:"garden_id" yaml:"garden_id,omitempty"`
+	ZoneIDs          []xid.ID   `json:"zone_ids" yaml:"zone_ids"`
+	WaterScheduleIDs []xid.ID   `json:"water_schedule_ids" yaml:"water_schedule_ids"`
+	CreatedAt        *time.Time `json:"created_at" yaml:"created_at,omitempty"`
+	EndDate          *time.Time `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+}
+
+func (zg *ZoneGroup) GetID() string {
+	return zg.ID.String()
+}
+
+// String...
+func (zg *ZoneGroup) String() string {
+	return fmt.Sprintf("%+v", *zg)
+}
+
+// EndDated returns true if the ZoneGroup is end-dated
+func (zg *ZoneGroup) EndDated() bool {
+	return zg.EndDate != nil && zg.EndDate.Before(time.Now())
+}
+
+func (zg *ZoneGroup) SetEndDate(now time.Time) {
+	zg.EndDate = &now
+}
+
+// HasZone returns true if the Zone is a member of the ZoneGroup
+func (zg *ZoneGroup) HasZone(id xid.ID) bool {
+	return slices.Contains(zg.ZoneIDs, id)
+}
+
+// HasWaterSchedule returns true if the ZoneGroup uses the WaterSchedule
+func (zg *ZoneGroup) HasWaterSchedule(id xid.ID) bool {
+	return slices.Contains(zg.WaterScheduleIDs, id)
+}
+
+// Patch allows for easily updating individual fields of a ZoneGroup by passing in a new ZoneGroup containing
+// the desired values
+func (zg *ZoneGroup) Patch(newZoneGroup *ZoneGroup) *babyapi.ErrResponse {
+	if newZoneGroup.Name != "" {
+		zg.Name = newZoneGroup.Name
+	}
+	if newZoneGroup.CreatedAt != nil {
+		zg.CreatedAt = newZoneGroup.CreatedAt
+	}
+	if zg.EndDate != nil && newZoneGroup.EndDate == nil {
+		zg.EndDate = newZoneGroup.EndDate
+	}
+	if len(newZoneGroup.ZoneIDs) != 0 {
+		zg.ZoneIDs = newZoneGroup.ZoneIDs
+	}
+	if len(newZoneGroup.WaterScheduleIDs) != 0 {
+		zg.WaterScheduleIDs = newZoneGroup.WaterScheduleIDs
+	}
+
+	return nil
+}
+
+func (zg *ZoneGroup) Bind(r *http.Request) error {
+	if zg == nil {
+		return errors.New("missing required ZoneGroup fields")
+	}
+
+	err := zg.ID.Bind(r)
+	if err != nil {
+		return err
+	}
+
+	// Remove zero-valued IDs like the Zone does for HTML form input
+	zg.ZoneIDs = nonZeroIDs(zg.ZoneIDs)
+	zg.WaterScheduleIDs = nonZeroIDs(zg.WaterScheduleIDs)
+
+	now := time.Now()
+	switch r.Method {
+	case http.MethodPost:
+		zg.CreatedAt = &now
+		fallthrough
+	case http.MethodPut:
+		if zg.CreatedAt == nil || zg.CreatedAt.IsZero() {
+			zg.CreatedAt = &now
+		}
+		if zg.Name == "" {
+			return validation.Missing("name")
+		}
+		if len(zg.ZoneIDs) == 0 {
+			return validation.Missing("zone_ids")
+		}
+	case http.MethodPatch:
+		if zg.EndDate != nil {
+			return errors.New("to end-date a ZoneGroup, please use the DELETE endpoint")
+		}
+		if !zg.GardenID.IsNil() {
+			return errors.New("unable to change GardenID")
+		}
+	}
+
+	// Zones are watered in the order they are listed, so each one can only be included once
+	seen := map[xid.ID]bool{}
+	for _, id := range zg.ZoneIDs {
+		if seen[id] {
+			return fmt.Errorf("duplicate Zone %q in zone_ids", id)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+func (zg *ZoneGroup) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// nonZeroIDs returns the IDs without any zero values
+func nonZeroIDs(ids []xid.ID) []xid.ID {
+	result := []xid.ID{}
+	for _, id := range ids {
+		if !id.IsZero() {
+			result = append(result, id)
+		}
+	}
+	return result
+}
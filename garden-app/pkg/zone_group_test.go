@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneGroupBind(t *testing.T) {
+	zoneID := xid.New()
+	otherZoneID := xid.New()
+
+	tests := []struct {
+		name      string
+		method    string
+		zoneGroup *ZoneGroup
+		err       string
+	}{
+		{"Valid", http.MethodPost, &ZoneGroup{Name: "beds", ZoneIDs: []xid.ID{zoneID, otherZoneID}}, ""},
+		{"ErrorMissingName", http.MethodPost, &ZoneGroup{ZoneIDs: []xid.ID{zoneID}}, "missing required name field"},
+		{"ErrorMissingZoneIDs", http.MethodPost, &ZoneGroup{Name: "beds"}, "missing required zone_ids field"},
+		{"ErrorOnlyZeroZoneIDs", http.MethodPost, &ZoneGroup{Name: "beds", ZoneIDs: []xid.ID{{}}}, "missing required zone_ids field"},
+		{"ErrorDuplicateZoneIDs", http.MethodPost, &ZoneGroup{Name: "beds", ZoneIDs: []xid.ID{zoneID, zoneID}}, `duplicate Zone "` + zoneID.String() + `" in zone_ids`},
+		{"PatchName", http.MethodPatch, &ZoneGroup{Name: "beds"}, ""},
+		{"ErrorPatchEndDate", http.MethodPatch, &ZoneGroup{EndDate: &time.Time{}}, "to end-date a ZoneGroup, please use the DELETE endpoint"},
+		{"ErrorPatchGardenID", http.MethodPatch, &ZoneGroup{GardenID: xid.New()}, "unable to change GardenID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.zoneGroup.Bind(&http.Request{Method: tt.method})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestZoneGroupPatch(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		newZoneGroup *ZoneGroup
+	}{
+		{"PatchName", &ZoneGroup{Name: "name"}},
+		{"PatchCreatedAt", &ZoneGroup{CreatedAt: &now}},
+		{"PatchZoneIDs", &ZoneGroup{ZoneIDs: []xid.ID{xid.New()}}},
+		{"PatchWaterScheduleIDs", &ZoneGroup{WaterScheduleIDs: []xid.ID{xid.New()}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zg := &ZoneGroup{}
+			err := zg.Patch(tt.newZoneGroup)
+			require.Nil(t, err)
+			assert.Equal(t, tt.newZoneGroup, zg)
+		})
+	}
+
+	t.Run("PatchRemoveEndDate", func(t *testing.T) {
+		zg := &ZoneGroup{EndDate: &now}
+
+		err := zg.Patch(&ZoneGroup{})
+		require.Nil(t, err)
+		assert.Nil(t, zg.EndDate)
+	})
+}
@@ -35,6 +35,10 @@ func TestZoneEndDated(t *testing.T) {
 func TestZonePatch(t *testing.T) {
 	zero := uint(0)
 	three := uint(3)
+	kc := float32(0.8)
+	adjustment := uint(120)
+	skipRainControl := false
+	flowRate := 2.5
 	now := time.Now()
 	wsID := xid.New()
 	tests := []struct {
@@ -75,6 +79,30 @@ func TestZonePatch(t *testing.T) {
 				SkipCount: &three,
 			},
 		},
+		{
+			"PatchSoilType",
+			&Zone{SoilType: SoilTypeClay},
+		},
+		{
+			"PatchCropCoefficient",
+			&Zone{CropCoefficient: &kc},
+		},
+		{
+			"PatchAdjustmentPercent",
+			&Zone{AdjustmentPercent: &adjustment},
+		},
+		{
+			"PatchSkipRainControl",
+			&Zone{SkipRainControl: &skipRainControl},
+		},
+		{
+			"PatchFlowRate",
+			&Zone{FlowRate: &flowRate},
+		},
+		{
+			"PatchHardwareConfig",
+			&Zone{HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -98,6 +126,18 @@ func TestZonePatch(t *testing.T) {
 		}
 	})
 
+	t.Run("PatchRemoveHardwareConfig", func(t *testing.T) {
+		z := &Zone{HardwareConfig: &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}}
+
+		err := z.Patch(&Zone{HardwareConfig: &HardwareConfig{ButtonPin: "GPIO_NUM_19"}})
+		require.Nil(t, err)
+		assert.Equal(t, &HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16", ButtonPin: "GPIO_NUM_19"}, z.HardwareConfig)
+
+		err = z.Patch(&Zone{HardwareConfig: &HardwareConfig{}})
+		require.Nil(t, err)
+		assert.Nil(t, z.HardwareConfig)
+	})
+
 	t.Run("PatchRemoveEndDate", func(t *testing.T) {
 		now := time.Now()
 		p := &Zone{
@@ -112,3 +152,28 @@ func TestZonePatch(t *testing.T) {
 		}
 	})
 }
+
+func TestZoneEstimateLiters(t *testing.T) {
+	zoneFlowRate := 2.0
+
+	tests := []struct {
+		name     string
+		zone     *Zone
+		garden   *Garden
+		liters   float64
+		expected bool
+	}{
+		{"NoFlowRate", &Zone{}, &Garden{}, 0, false},
+		{"GardenFlowRate", &Zone{}, &Garden{Pricing: &WaterPricing{FlowRate: 10}}, 50, true},
+		{"ZoneFlowRate", &Zone{FlowRate: &zoneFlowRate}, &Garden{}, 10, true},
+		{"ZoneOverridesGarden", &Zone{FlowRate: &zoneFlowRate}, &Garden{Pricing: &WaterPricing{FlowRate: 10}}, 10, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			liters, ok := tt.zone.EstimateLiters(tt.garden, 5*time.Minute)
+			assert.Equal(t, tt.expected, ok)
+			assert.Equal(t, tt.liters, liters)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const actionsPath = "/actions"
+
+// actionRecords responds with the status of actions using the ID returned when they are requested. Like the
+// auditLog, it is created before the storage client is available
+type actionRecords struct {
+	storageClient *storage.Client
+	storage       storage.ActionRecordStorage
+}
+
+func (a *actionRecords) setup(storageClient *storage.Client) {
+	a.storageClient = storageClient
+	a.storage = storageClient.ActionRecords
+}
+
+// ActionRecordResponse is used to render an ActionRecord in the response body
+type ActionRecordResponse struct {
+	*pkg.ActionRecord
+}
+
+func (*ActionRecordResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// ActionRecordsResponse is the response for listing a Zone's recent actions
+type ActionRecordsResponse struct {
+	Items []pkg.ActionRecord `json:"items"`
+}
+
+func (*ActionRecordsResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getActionRecord responds with the ActionRecord for the ID in the path. Requests made as a User can only get
+// actions for Gardens that the User can read
+func (a *actionRecords) getActionRecord(_ http.ResponseWriter, r *http.Request) render.Renderer {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	id := babyapi.GetIDParam(r, "action")
+	logger.Info("received request to get action", "action_id", id)
+
+	record, err := a.storage.GetActionRecord(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, babyapi.ErrNotFound) {
+			return babyapi.ErrNotFoundResponse
+		}
+		logger.Error("unable to get action", "action_id", id, "error", err)
+		return babyapi.InternalServerError(err)
+	}
+	if !userCanReadGarden(r.Context(), a.storageClient, record.GardenID) {
+		return babyapi.ErrNotFoundResponse
+	}
+
+	return &ActionRecordResponse{record}
+}
+
+// actionRecords responds with the Zone's recent actions, starting with the most recent. The "limit" query parameter
+// sets the maximum number of actions
+func (api *ZonesAPI) actionRecords(r *http.Request, zone *pkg.Zone) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to get Zone actions")
+
+	limit, err := limitQueryParam(r)
+	if err != nil {
+		logger.Error("unable to parse limit", "error", err)
+		return nil, babyapi.ErrInvalidRequest(err)
+	}
+
+	records, err := api.storageClient.ActionRecords.GetZoneActionRecords(r.Context(), zone.GetID(), limit)
+	if err != nil {
+		logger.Error("unable to get Zone actions", "error", err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return &ActionRecordsResponse{Items: records}, nil
+}
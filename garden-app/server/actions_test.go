@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var actionIDRegexp = regexp.MustCompile(`"action_id":"[0-9a-v]{20}"`)
+
+// replaceActionIDs replaces generated action IDs in the response body so it can be compared
+func replaceActionIDs(body string) string {
+	return actionIDRegexp.ReplaceAllString(strings.TrimSpace(body), `"action_id":"ACTION_ID"`)
+}
+
+func TestActionRecords(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "test-garden").Return("garden/action/water", nil)
+	mqttClient.On("Publish", "garden/action/water", mock.Anything).Return(nil)
+	mqttClient.On("Disconnect", uint(100)).Return()
+
+	zr := NewZonesAPI()
+	zr.setup(storageClient, nil, worker.NewWorker(storageClient, nil, mqttClient, slog.Default()))
+	zr.worker.StartAsync()
+	defer zr.worker.Stop()
+
+	garden := createExampleGarden()
+	zone := createExampleZone()
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+	require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/gardens/%s/zones/%s/action", garden.ID, zone.ID), strings.NewReader(`{"water":{"duration":1000}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, zr.API, garden, "Gardens", "/gardens", r)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	var actionResp ZoneActionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actionResp))
+	require.NotEmpty(t, actionResp.ActionID)
+
+	actions := &actionRecords{}
+	actions.setup(storageClient)
+	api := babyapi.NewRootAPI("garden-app", "/")
+	api.AddCustomRoute(http.MethodGet, actionsPath+"/{actionID}", babyapi.Handler(actions.getActionRecord))
+
+	t.Run("GetActionRecord", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, actionsPath+"/"+actionResp.ActionID, http.NoBody)
+		w := babytest.TestRequest[*babyapi.NilResource](t, api, r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var record pkg.ActionRecord
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &record))
+		assert.Equal(t, actionResp.ActionID, record.ID)
+		assert.Equal(t, "water", record.Type)
+		assert.Equal(t, zone.GetID(), record.ZoneID)
+		assert.Equal(t, pkg.ActionStatusPublished, record.Status)
+		require.Len(t, record.History, 1)
+	})
+
+	t.Run("ErrorNotFound", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, actionsPath+"/missing", http.NoBody)
+		w := babytest.TestRequest[*babyapi.NilResource](t, api, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("ErrorUserCannotReadGarden", func(t *testing.T) {
+		user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+		otherGarden := createExampleGarden()
+		otherGarden.ID = babyapi.NewID()
+		otherGarden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleOwner}
+		require.NoError(t, storageClient.Gardens.Set(context.Background(), otherGarden))
+
+		r := httptest.NewRequest(http.MethodGet, actionsPath+"/"+actionResp.ActionID, http.NoBody)
+		r = r.WithContext(withUser(r.Context(), user))
+		w := babytest.TestRequest[*babyapi.NilResource](t, api, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("GetZoneActionRecords", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/gardens/%s/zones/%s/actions?limit=5", garden.ID, zone.ID), http.NoBody)
+		w := babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, zr.API, garden, "Gardens", "/gardens", r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp ActionRecordsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, actionResp.ActionID, resp.Items[0].ID)
+	})
+}
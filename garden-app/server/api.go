@@ -6,14 +6,19 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 
-	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/backup"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
 	"github.com/calvinmclean/automated-garden/garden-app/worker"
 	"github.com/calvinmclean/babyapi"
 	"github.com/calvinmclean/babyapi/html"
 	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/gorilla/websocket"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	prommetrics "github.com/slok/go-http-metrics/metrics/prometheus"
 	metrics_middleware "github.com/slok/go-http-metrics/middleware"
@@ -25,9 +30,27 @@ type API struct {
 	*babyapi.API[*babyapi.NilResource]
 	gardens             *GardensAPI
 	zones               *ZonesAPI
+	zoneGroups          *ZoneGroupsAPI
+	plants              *PlantsAPI
+	reminders           *RemindersAPI
+	photos              *PhotosAPI
+	firmware            *FirmwareAPI
 	weatherClients      *WeatherClientsAPI
 	notificationClients *NotificationClientsAPI
 	waterSchedules      *WaterSchedulesAPI
+	apiTokens           *APITokensAPI
+	users               *UsersAPI
+	webhooks            *WebhooksAPI
+	events              *events.Bus
+	storageClient       *storage.Client
+	audit               *auditLog
+	actions             *actionRecords
+	upgrader            websocket.Upgrader
+	auth                *authenticator
+	readiness           atomic.Pointer[readiness]
+	allowedOrigins      atomic.Pointer[[]string]
+	loadConfig          ConfigLoader
+	reloader            *configReloader
 }
 
 // NewAPI intializes an API without any integrations or clients. Use api.Setup(...) before running
@@ -36,36 +59,117 @@ func NewAPI() *API {
 		API:                 babyapi.NewRootAPI("garden-app", "/"),
 		gardens:             NewGardenAPI(),
 		zones:               NewZonesAPI(),
+		zoneGroups:          NewZoneGroupsAPI(),
+		plants:              NewPlantsAPI(),
+		reminders:           NewRemindersAPI(),
+		photos:              NewPhotosAPI(),
+		firmware:            NewFirmwareAPI(),
 		weatherClients:      NewWeatherClientsAPI(),
 		notificationClients: NewNotificationClientsAPI(),
 		waterSchedules:      NewWaterSchedulesAPI(),
+		apiTokens:           NewAPITokensAPI(),
+		users:               NewUsersAPI(),
+		webhooks:            NewWebhooksAPI(),
+		events:              events.NewBus(),
+		audit:               &auditLog{},
+		actions:             &actionRecords{},
 	}
+	api.upgrader = newUpgrader(api.getAllowedOrigins)
 	api.gardens.AddNestedAPI(api.zones)
+	api.gardens.AddNestedAPI(api.zoneGroups)
+	api.zones.AddNestedAPI(api.plants)
+	api.gardens.audit = api.audit
+	api.zones.audit = api.audit
+	api.zoneGroups.audit = api.audit
+	api.zones.photos = api.photos
+	api.plants.photos = api.photos
+	api.photos.events = api.events
+	api.photos.audit = api.audit
+	api.firmware.events = api.events
+	api.firmware.audit = api.audit
+
+	addResourceEvents(api.gardens.API, api.events, api.audit, "garden")
+	addResourceEvents(api.zones.API, api.events, api.audit, "zone")
+	addResourceEvents(api.zoneGroups.API, api.events, api.audit, "zone_group")
+	addResourceEvents(api.plants.API, api.events, api.audit, "plant")
+	addResourceEvents(api.reminders.API, api.events, api.audit, "reminder")
+	addResourceEvents(api.photos.API, api.events, api.audit, "photo")
+	addResourceEvents(api.firmware.API, api.events, api.audit, "firmware")
+	addResourceEvents(api.waterSchedules.API, api.events, api.audit, "water_schedule")
+	addResourceEvents(api.weatherClients.API, api.events, api.audit, "weather_client")
+	// These resources are only recorded in the audit log and do not publish Events
+	addResourceEvents(api.notificationClients.API, nil, api.audit, "notification_client")
+	addResourceEvents(api.apiTokens.API, nil, api.audit, "api_token")
+	addResourceEvents(api.users.API, nil, api.audit, "user")
+	addResourceEvents(api.webhooks.API, nil, api.audit, "webhook")
 
 	api.API.
+		AddMiddleware(tracingMiddleware).
 		AddMiddleware(std.HandlerProvider("", metrics_middleware.New(metrics_middleware.Config{
 			Recorder: prommetrics.NewRecorder(prommetrics.Config{Prefix: "garden_app"}),
 		}))).
+		AddMiddleware(includeEndDatedMiddleware).
+		AddMiddleware(validationErrorsMiddleware).
 		AddCustomRoute(http.MethodGet, "/metrics", promhttp.Handler()).
+		AddCustomRoute(http.MethodGet, healthzPath, babyapi.Handler(healthzHandler)).
+		AddCustomRoute(http.MethodGet, readyzPath, babyapi.Handler(api.readyzHandler)).
+		AddCustomRoute(http.MethodPost, reloadPath, babyapi.Handler(api.reloadHandler)).
+		AddCustomRoute(http.MethodGet, "/events", http.HandlerFunc(api.eventsHandler)).
+		AddCustomRoute(http.MethodGet, "/events/sse", http.HandlerFunc(api.sseEventsHandler)).
+		AddCustomRoute(http.MethodGet, auditPath, babyapi.Handler(api.audit.getAuditEntries)).
+		AddCustomRoute(http.MethodGet, actionsPath+"/{actionID}", babyapi.Handler(api.actions.getActionRecord)).
+		AddCustomRoute(http.MethodGet, schemaPath, babyapi.Handler(getSchema)).
+		AddCustomRoute(http.MethodGet, openAPIPath, http.HandlerFunc(openAPIHandler)).
+		AddCustomRoute(http.MethodGet, docsPath, http.HandlerFunc(swaggerUIHandler)).
 		AddCustomRoute(http.MethodGet, "/", http.RedirectHandler("/gardens", http.StatusFound)).
 		AddNestedAPI(api.gardens).
 		AddNestedAPI(api.weatherClients).
 		AddNestedAPI(api.notificationClients).
-		AddNestedAPI(api.waterSchedules)
+		AddNestedAPI(api.waterSchedules).
+		AddNestedAPI(api.reminders).
+		AddNestedAPI(api.photos).
+		AddNestedAPI(api.firmware).
+		AddNestedAPI(api.apiTokens).
+		AddNestedAPI(api.users).
+		AddNestedAPI(api.webhooks)
 
 	return api
 }
 
+// getAllowedOrigins returns the additional origins that can connect to /events
+func (api *API) getAllowedOrigins() []string {
+	origins := api.allowedOrigins.Load()
+	if origins == nil {
+		return nil
+	}
+	return *origins
+}
+
 // Setup will prepare to run by setting up clients and doing any final configurations for the API
 func (api *API) Setup(cfg Config, validateData bool) error {
 	html.SetFS(templates, "templates/*")
 	html.SetFuncs(templateFuncs)
 
-	logger := cfg.LogConfig.NewLogger().With("source", "server")
+	err := cfg.LogConfig.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid log config: %w", err)
+	}
+
+	logger := cfg.LogConfig.NewSubsystemLogger(LogSubsystemServer).With("source", "server")
 	slog.SetDefault(logger)
+	storageLogger := cfg.LogConfig.NewSubsystemLogger(LogSubsystemStorage).With("source", "storage")
+	mqttLogger := cfg.LogConfig.NewSubsystemLogger(LogSubsystemMQTT).With("source", "mqtt")
+
+	if cfg.Tracing.Enabled {
+		logger.Info("initializing tracing", "endpoint", cfg.Tracing.Endpoint)
+	}
+	shutdownTracing, err := tracing.Setup(cfg.Tracing, "garden-app")
+	if err != nil {
+		return fmt.Errorf("unable to setup tracing: %w", err)
+	}
 
 	// Initialize Storage Client
-	logger.Info("initializing storage client", "driver", cfg.StorageConfig.Driver)
+	storageLogger.Info("initializing storage client", "driver", cfg.StorageConfig.Driver)
 	storageClient, err := storage.NewClient(cfg.StorageConfig)
 	if err != nil {
 		return fmt.Errorf("unable to initialize storage client: %v", err)
@@ -78,52 +182,251 @@ func (api *API) Setup(cfg Config, validateData bool) error {
 		}
 	}
 
+	// Initialize metrics Client
+	if cfg.MetricsConfig.Driver == metrics.DriverPrometheus {
+		logger.With(
+			"address", cfg.MetricsConfig.Prometheus.Address,
+			"write_address", cfg.MetricsConfig.Prometheus.WriteAddress,
+		).Info("initializing Prometheus metrics client")
+	} else {
+		logger.With(
+			"address", cfg.InfluxDBConfig.Address,
+			"org", cfg.InfluxDBConfig.Org,
+			"bucket", cfg.InfluxDBConfig.Bucket,
+			"version", cfg.InfluxDBConfig.Version,
+		).Info("initializing InfluxDB client")
+	}
+	influxdbClient, err := metrics.NewClient(cfg.MetricsConfig, cfg.InfluxDBConfig)
+	if err != nil {
+		return fmt.Errorf("unable to initialize metrics client: %v", err)
+	}
+
 	// Initialize MQTT Client
-	logger.With(
+	mqttLogger.With(
 		"client_id", cfg.MQTTConfig.ClientID,
 		"broker", cfg.MQTTConfig.Broker,
 		"port", cfg.MQTTConfig.Port,
 	).Info("initializing MQTT client")
-	mqttClient, err := mqtt.NewClient(cfg.MQTTConfig, mqtt.DefaultHandler(logger), mqtt.TopicHandler{
+	mqttHandler := NewMQTTHandler(storageClient, mqttLogger)
+	mqttHandler.disableNotifications = cfg.Simulation.Enabled
+	waterDataHandler := mqtt.TopicHandler{
 		Topic:   "+/data/water",
-		Handler: paho.MessageHandler(NewMQTTHandler(storageClient, logger).Handle),
-	})
+		Handler: paho.MessageHandler(mqttHandler.Handle),
+	}
+	healthDataHandler := mqtt.TopicHandler{
+		Topic:   "+/data/health",
+		Handler: paho.MessageHandler(mqttHandler.HandleHealth),
+	}
+	lightDataHandler := mqtt.TopicHandler{
+		Topic:   "+/data/light",
+		Handler: paho.MessageHandler(mqttHandler.HandleLight),
+	}
+	lightLevelDataHandler := mqtt.TopicHandler{
+		Topic:   "+/data/light_level",
+		Handler: paho.MessageHandler(mqttHandler.HandleLightLevel),
+	}
+	waterAckHandler := mqtt.TopicHandler{
+		Topic:   "+/ack/water",
+		Handler: paho.MessageHandler(mqttHandler.HandleWaterAck),
+	}
+	firmwareStatusHandler := mqtt.TopicHandler{
+		Topic:   "+/ack/firmware_update",
+		Handler: paho.MessageHandler(mqttHandler.HandleFirmwareStatus),
+	}
+	var mqttClient mqtt.Client
+	if cfg.Simulation.Enabled {
+		logger.Info("using in-memory MQTT client and simulated controller")
+		mqttClient, err = newSimulatedMQTTClient(cfg.MQTTConfig, mqttLogger, waterDataHandler, healthDataHandler, lightDataHandler, lightLevelDataHandler, waterAckHandler, firmwareStatusHandler)
+	} else {
+		mqttClient, err = mqtt.NewClient(cfg.MQTTConfig, mqtt.DefaultHandler(mqttLogger), waterDataHandler, healthDataHandler, lightDataHandler, lightLevelDataHandler, waterAckHandler, firmwareStatusHandler)
+	}
 	if err != nil {
 		return fmt.Errorf("unable to initialize MQTT client: %v", err)
 	}
 
-	// Initialize InfluxDB Client
-	logger.With(
-		"address", cfg.InfluxDBConfig.Address,
-		"org", cfg.InfluxDBConfig.Org,
-		"bucket", cfg.InfluxDBConfig.Bucket,
-	).Info("initializing InfluxDB client")
-	influxdbClient := influxdb.NewClient(cfg.InfluxDBConfig)
-
 	// Initialize Scheduler
 	logger.Info("initializing scheduler")
-	worker := worker.NewWorker(storageClient, influxdbClient, mqttClient, cfg.LogConfig.NewLogger())
+	worker := worker.NewWorker(storageClient, influxdbClient, mqttClient, cfg.LogConfig.NewSubsystemLogger(LogSubsystemWorker))
+	worker.SetEventBus(api.events)
+	if cfg.Health.DownThreshold > 0 {
+		worker.SetHealthThreshold(cfg.Health.DownThreshold)
+	}
+	for i, bw := range cfg.BlackoutWindows {
+		err = bw.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid blackout_windows[%d]: %w", i, err)
+		}
+	}
+	worker.SetBlackoutWindows(cfg.BlackoutWindows)
+	worker.SetLeakDetection(cfg.LeakDetection)
+	err = cfg.CatchUp.Validate()
+	if err != nil {
+		return err
+	}
+	worker.SetCatchUp(cfg.CatchUp)
+	worker.SetWaterAck(cfg.WaterAck)
+	worker.SetWeatherRetry(cfg.WeatherRetry)
+	worker.SetManualWatering(cfg.ManualWatering)
+	if cfg.Backup.Enabled() {
+		err = cfg.Backup.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid backup config: %w", err)
+		}
+		var store backup.Store
+		store, err = backup.NewStore(cfg.Backup)
+		if err != nil {
+			return fmt.Errorf("error creating backup store: %w", err)
+		}
+		worker.SetBackups(cfg.Backup, store)
+	}
+	mqttHandler.worker = worker
+
+	if cfg.Simulation.Enabled {
+		logger.Info("enabling simulation mode with virtual clock")
+		err = api.setupSimulation(cfg.Simulation, storageClient, worker)
+		if err != nil {
+			return err
+		}
+	}
 
 	err = api.setup(cfg, storageClient, influxdbClient, worker)
 	if err != nil {
 		return err
 	}
 
+	// The declarative file is applied before the first WeatherClient sync so its WeatherClients are subscribed to
+	if cfg.Declarative.File != "" {
+		declarative := newDeclarativeWatcher(cfg.Declarative, storageClient, worker, api.events, logger)
+		err = declarative.reconcile(context.Background())
+		if err != nil {
+			return fmt.Errorf("unable to apply declarative file: %w", err)
+		}
+		go declarative.watch(api.Done())
+	}
+
+	// Readings for mqtt_sensor WeatherClients are subscribed to after the Worker's clock is setup so simulated
+	// readings use the virtual time
+	if subscriber, ok := mqttClient.(mqtt.Subscriber); ok {
+		sensors := newWeatherSensors(storageClient, subscriber, mqttLogger, worker.Now)
+		err = sensors.sync()
+		if err != nil {
+			return fmt.Errorf("unable to subscribe to weather sensors: %w", err)
+		}
+		go sensors.watch(api.events, api.Done())
+	}
+
+	webhooks := newWebhookDispatcher(storageClient, logger, worker.Now)
+	go webhooks.watch(api.events, api.Done())
+
+	// Telegraf writes controller data to InfluxDB unless ingestion is enabled. Prometheus-compatible stores always
+	// get the data from the garden-app
+	if cfg.InfluxDBConfig.Ingest || cfg.MetricsConfig.Driver == metrics.DriverPrometheus {
+		err = worker.StartIngestion()
+		if err != nil {
+			return fmt.Errorf("unable to start ingesting controller data: %w", err)
+		}
+	}
+
+	err = worker.ScheduleReportDigest()
+	if err != nil {
+		return fmt.Errorf("unable to schedule report digest: %w", err)
+	}
+
+	err = worker.ScheduleHealthChecks()
+	if err != nil {
+		return fmt.Errorf("unable to schedule health checks: %w", err)
+	}
+
+	err = worker.ScheduleLeakDetection()
+	if err != nil {
+		return fmt.Errorf("unable to schedule leak detection: %w", err)
+	}
+
+	err = worker.ScheduleBackups()
+	if err != nil {
+		return fmt.Errorf("unable to schedule backups: %w", err)
+	}
+
+	if cfg.GRPC.Port != 0 {
+		err = api.serveGRPC(cfg.GRPC, newGRPCServer(storageClient, worker, api.events, api.auth), logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	// One-time Jobs from before the server restarted are restored last so the missed ones that run immediately use
+	// the latest resources and config
+	err = worker.RestorePendingJobs()
+	if err != nil {
+		return fmt.Errorf("unable to restore pending jobs: %w", err)
+	}
+
 	worker.StartAsync()
 
+	api.readiness.Store(&readiness{
+		storageClient: storageClient,
+		mqttClient:    mqttClient,
+		worker:        worker,
+	})
+
+	if api.loadConfig != nil {
+		api.reloader = &configReloader{
+			load:    api.loadConfig,
+			current: cfg,
+			api:     api,
+			worker:  worker,
+			logger:  logger,
+		}
+		go api.reloader.watchSignals(api.Done())
+	}
+
 	go func() {
 		<-api.Done()
 		worker.Stop()
+
+		err := shutdownTracing(context.Background())
+		if err != nil {
+			logger.Error("error flushing traces", "error", err)
+		}
 	}()
 
 	return nil
 }
 
-func (api *API) setup(cfg Config, storageClient *storage.Client, influxdbClient influxdb.Client, worker *worker.Worker) error {
+func (api *API) setup(cfg Config, storageClient *storage.Client, influxdbClient metrics.Client, worker *worker.Worker) error {
+	if len(cfg.Auth.Tokens) > 0 || cfg.OIDC.IssuerURL != "" {
+		auth, err := newAuthenticator(cfg.Auth, storageClient.APITokens)
+		if err != nil {
+			return fmt.Errorf("error setting up authentication: %w", err)
+		}
+		auth.users = storageClient.Users
+		auth.gardens = storageClient.Gardens
+
+		if cfg.OIDC.IssuerURL != "" {
+			auth.oidc, err = newOIDCAuthenticator(context.Background(), cfg.OIDC)
+			if err != nil {
+				return fmt.Errorf("error setting up OIDC: %w", err)
+			}
+
+			api.API.
+				AddCustomRoute(http.MethodGet, oidcBasePath+"/login", http.HandlerFunc(auth.oidc.loginHandler)).
+				AddCustomRoute(http.MethodGet, oidcBasePath+"/callback", http.HandlerFunc(auth.oidc.callbackHandler)).
+				AddCustomRoute(http.MethodGet, oidcBasePath+"/logout", http.HandlerFunc(auth.oidc.logoutHandler))
+		}
+
+		api.API.AddMiddleware(auth.middleware)
+		api.auth = auth
+	}
+
 	if cfg.ReadOnly {
 		api.API.AddMiddleware(readOnlyMiddleware)
 	}
 
+	api.allowedOrigins.Store(&cfg.AllowedOrigins)
+	api.storageClient = storageClient
+	api.audit.setup(storageClient, worker.Now)
+	api.actions.setup(storageClient)
+
 	err := api.gardens.setup(cfg, storageClient, influxdbClient, worker)
 	if err != nil {
 		return fmt.Errorf("error setting up Gardens API: %w", err)
@@ -135,8 +438,35 @@ func (api *API) setup(cfg Config, storageClient *storage.Client, influxdbClient
 	}
 
 	api.zones.setup(storageClient, influxdbClient, worker)
+	api.zoneGroups.setup(storageClient, worker)
+	api.plants.setup(storageClient)
+
+	err = api.reminders.setup(storageClient, worker)
+	if err != nil {
+		return fmt.Errorf("error setting up Reminders API: %w", err)
+	}
+
+	err = api.photos.setup(cfg.Photos, storageClient)
+	if err != nil {
+		return fmt.Errorf("error setting up Photos API: %w", err)
+	}
+
+	err = api.firmware.setup(cfg.Firmware, storageClient, worker)
+	if err != nil {
+		return fmt.Errorf("error setting up Firmware API: %w", err)
+	}
+
+	// Without a metrics backend, water history is read from the events recorded in storage by the Worker
+	waterHistoryFromStorage := cfg.MetricsConfig.Driver != metrics.DriverPrometheus && cfg.InfluxDBConfig.Address == ""
+	api.zones.waterHistoryFromStorage = waterHistoryFromStorage
+	api.gardens.waterHistoryFromStorage = waterHistoryFromStorage
 	api.weatherClients.setup(storageClient)
 	api.notificationClients.setup(storageClient)
+	api.apiTokens.setup(storageClient)
+	api.users.setup(storageClient)
+	api.webhooks.setup(storageClient)
+	api.setupImportExport(storageClient, worker)
+	api.setupBackups(storageClient, worker)
 
 	return nil
 }
@@ -173,6 +503,51 @@ func validateAllStoredResources(storageClient *storage.Client) error {
 		}
 	}
 
+	zoneGroups, err := storageClient.ZoneGroups.GetAll(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("unable to get all ZoneGroups: %w", err)
+	}
+
+	for _, zg := range zoneGroups {
+		if zg.ID.IsNil() {
+			return errors.New("invalid ZoneGroup: missing required field 'id'")
+		}
+		err = zg.Bind(&http.Request{Method: http.MethodPut})
+		if err != nil {
+			return fmt.Errorf("invalid ZoneGroup %q: %w", zg.ID, err)
+		}
+	}
+
+	plants, err := storageClient.Plants.GetAll(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("unable to get all Plants: %w", err)
+	}
+
+	for _, p := range plants {
+		if p.ID.IsNil() {
+			return errors.New("invalid Plant: missing required field 'id'")
+		}
+		err = p.Bind(&http.Request{Method: http.MethodPut})
+		if err != nil {
+			return fmt.Errorf("invalid Plant %q: %w", p.ID, err)
+		}
+	}
+
+	reminders, err := storageClient.Reminders.GetAll(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("unable to get all Reminders: %w", err)
+	}
+
+	for _, rem := range reminders {
+		if rem.ID.IsNil() {
+			return errors.New("invalid Reminder: missing required field 'id'")
+		}
+		err = rem.Bind(&http.Request{Method: http.MethodPut})
+		if err != nil {
+			return fmt.Errorf("invalid Reminder %q: %w", rem.ID, err)
+		}
+	}
+
 	waterSchedules, err := storageClient.WaterSchedules.GetAll(context.Background(), nil)
 	if err != nil {
 		return fmt.Errorf("unable to get all WaterSchedules: %w", err)
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const apiTokensBasePath = "/tokens"
+
+// APITokensAPI encapsulates the structs and dependencies necessary for the APITokens API to function, including
+// storage and configuring
+type APITokensAPI struct {
+	*babyapi.API[*pkg.APIToken]
+
+	storageClient *storage.Client
+}
+
+// NewAPITokensAPI creates a new APITokensResource
+func NewAPITokensAPI() *APITokensAPI {
+	api := &APITokensAPI{}
+
+	api.API = babyapi.NewAPI[*pkg.APIToken]("APITokens", apiTokensBasePath, func() *pkg.APIToken { return &pkg.APIToken{} })
+
+	api.SetOnCreateOrUpdate(func(r *http.Request, t *pkg.APIToken) *babyapi.ErrResponse {
+		if t.UserID != "" {
+			_, err := api.storageClient.Users.Get(r.Context(), t.UserID)
+			if errors.Is(err, babyapi.ErrNotFound) {
+				return babyapi.ErrInvalidRequest(fmt.Errorf("User %q not found", t.UserID))
+			}
+			if err != nil {
+				return babyapi.InternalServerError(fmt.Errorf("error getting User %q: %w", t.UserID, err))
+			}
+		}
+
+		if r.Method != http.MethodPost {
+			return nil
+		}
+
+		err := t.GenerateToken()
+		if err != nil {
+			return babyapi.InternalServerError(err)
+		}
+		return nil
+	})
+
+	api.SetResponseWrapper(func(t *pkg.APIToken) render.Renderer {
+		return &APITokenResponse{
+			ID:     t.ID,
+			Name:   t.Name,
+			Scopes: t.Scopes,
+			UserID: t.UserID,
+			Token:  t.Token(),
+		}
+	})
+
+	api.ApplyExtension(conditionalRequests[*pkg.APIToken]{})
+
+	return api
+}
+
+func (api *APITokensAPI) setup(storageClient *storage.Client) {
+	api.storageClient = storageClient
+
+	api.SetStorage(api.storageClient.APITokens)
+}
+
+// APITokenResponse is used to respond without the token's hash. The token is only included when it is created
+type APITokenResponse struct {
+	ID     babyapi.ID          `json:"id"`
+	Name   string              `json:"name"`
+	Scopes []pkg.APITokenScope `json:"scopes"`
+	UserID string              `json:"user_id,omitempty"`
+	Token  string              `json:"token,omitempty"`
+
+	Links []Link `json:"links,omitempty"`
+}
+
+// Render ...
+func (resp *APITokenResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	if resp != nil {
+		resp.Links = append(resp.Links,
+			Link{
+				"self",
+				fmt.Sprintf("%s/%s", apiTokensBasePath, resp.ID),
+			},
+		)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokensAPI(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	api := NewAPITokensAPI()
+	api.setup(storageClient)
+
+	babytest.RunTableTest(t, api.API, []babytest.TestCase[*babyapi.AnyResource]{
+		{
+			Name: "CreateToken",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPost,
+				Body:   `{"name": "dashboard", "scopes": ["read"]}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status:     http.StatusCreated,
+				BodyRegexp: `{"id":"[0-9a-v]{20}","name":"dashboard","scopes":\["read"\],"token":"[0-9a-f]{64}","links":\[{"rel":"self","href":"/tokens/[0-9a-v]{20}"}\]}`,
+			},
+		},
+		{
+			Name: "GetTokenDoesNotIncludeToken",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodGet,
+				IDFunc: func(getResponse babytest.PreviousResponseGetter) string {
+					return getResponse("CreateToken").Data.GetID()
+				},
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status:     http.StatusOK,
+				BodyRegexp: `{"id":"[0-9a-v]{20}","name":"dashboard","scopes":\["read"\],"links":\[{"rel":"self","href":"/tokens/[0-9a-v]{20}"}\]}`,
+			},
+		},
+		{
+			Name: "PatchScopes",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPatch,
+				IDFunc: func(getResponse babytest.PreviousResponseGetter) string {
+					return getResponse("CreateToken").Data.GetID()
+				},
+				Body: `{"scopes": ["read", "actions"]}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status:     http.StatusOK,
+				BodyRegexp: `{"id":"[0-9a-v]{20}","name":"dashboard","scopes":\["read","actions"\],"links":\[{"rel":"self","href":"/tokens/[0-9a-v]{20}"}\]}`,
+			},
+		},
+		{
+			Name: "ErrorPut",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPut,
+				IDFunc: func(getResponse babytest.PreviousResponseGetter) string {
+					return getResponse("CreateToken").Data.GetID()
+				},
+				BodyFunc: func(getResponse babytest.PreviousResponseGetter) string {
+					return `{"id": "` + getResponse("CreateToken").Data.GetID() + `", "name": "dashboard", "scopes": ["admin"]}`
+				},
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status: http.StatusBadRequest,
+				Error:  `error putting resource: unexpected response with text: Invalid request.`,
+				Body:   `{"status":"Invalid request.","error":"APITokens cannot be replaced, use PATCH to update the name or scopes"}`,
+			},
+		},
+		{
+			Name: "ErrorCreateNoName",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPost,
+				Body:   `{"scopes": ["read"]}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status: http.StatusBadRequest,
+				Error:  `error posting resource: unexpected response with text: Invalid request.`,
+				Body:   `{"status":"Invalid request.","error":"missing required name field"}`,
+			},
+		},
+		{
+			Name: "ErrorCreateInvalidScope",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPost,
+				Body:   `{"name": "dashboard", "scopes": ["everything"]}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status: http.StatusBadRequest,
+				Error:  `error posting resource: unexpected response with text: Invalid request.`,
+				Body:   `{"status":"Invalid request.","error":"invalid scope \"everything\""}`,
+			},
+		},
+	})
+
+	t.Run("OnlyHashIsStored", func(t *testing.T) {
+		tokens, err := storageClient.APITokens.GetAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.Len(t, tokens[0].TokenHash, 64)
+		assert.Empty(t, tokens[0].Token())
+		assert.Equal(t, []pkg.APITokenScope{pkg.APITokenScopeRead, pkg.APITokenScopeActions}, tokens[0].Scopes)
+	})
+}
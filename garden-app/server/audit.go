@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const auditPath = "/audit"
+
+// auditLog records actions and resource changes requested with the API. It is created before the storage client
+// so it can be used when adding hooks to the APIs. Until storage is set, nothing is recorded
+type auditLog struct {
+	storage       storage.AuditLogStorage
+	storageClient *storage.Client
+	now           func() time.Time
+}
+
+func (a *auditLog) setup(storageClient *storage.Client, now func() time.Time) {
+	a.storage = storageClient.AuditLog
+	a.storageClient = storageClient
+	a.now = now
+}
+
+// record adds an entry for the API request using the authenticated actor and the client's address
+func (a *auditLog) record(r *http.Request, resourceType, resourceID, actionName string, details map[string]string) {
+	a.add(r.Context(), pkg.AuditEntry{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Action:       actionName,
+		Source:       pkg.AuditSourceAPI,
+		Actor:        actorFromContext(r.Context()),
+		RemoteAddr:   r.RemoteAddr,
+		Details:      details,
+	})
+}
+
+// add stores the entry. Errors are only logged since the action or change was already completed
+func (a *auditLog) add(ctx context.Context, entry pkg.AuditEntry) {
+	if a == nil || a.storage == nil {
+		return
+	}
+
+	entry.Timestamp = a.now()
+	err := a.storage.AddAuditEntry(ctx, entry)
+	if err != nil {
+		babyapi.GetLoggerFromContext(ctx).Error("unable to store audit entry", "error", err)
+	}
+}
+
+// gardenActionAuditDetails describes the GardenAction for the audit log. The returned action name is
+// "light_action" or "stop_action"
+func gardenActionAuditDetails(input *action.GardenAction) (string, map[string]string) {
+	if input.Stop != nil {
+		return "stop_action", map[string]string{"all": strconv.FormatBool(input.Stop.All)}
+	}
+	if input.StopAll != nil {
+		return "stop_action", map[string]string{"all": "true"}
+	}
+
+	details := map[string]string{"state": input.Light.State.String()}
+	if input.Light.ForDuration != nil {
+		details["for_duration"] = input.Light.ForDuration.Duration.String()
+	}
+	return "light_action", details
+}
+
+// zoneActionAuditDetails returns the action name and details used to describe the ZoneAction in the audit log
+func zoneActionAuditDetails(input *action.ZoneAction) (string, map[string]string) {
+	if input.Stop != nil {
+		return "stop_action", nil
+	}
+	return "water_action", waterActionAuditDetails(input.Water)
+}
+
+// waterActionAuditDetails describes the WaterAction for the audit log
+func waterActionAuditDetails(input *action.WaterAction) map[string]string {
+	details := map[string]string{}
+	if input.Duration != nil {
+		details["duration"] = input.Duration.Duration.String()
+	}
+	if input.IgnoreMoisture {
+		details["ignore_moisture"] = "true"
+	}
+	if input.IgnoreWeather {
+		details["ignore_weather"] = "true"
+	}
+	if input.IgnoreBudget {
+		details["ignore_budget"] = "true"
+	}
+	return details
+}
+
+// AuditLogResponse is the response for querying the audit log
+type AuditLogResponse struct {
+	Entries []pkg.AuditEntry `json:"entries"`
+	Count   int              `json:"count"`
+}
+
+func (resp *AuditLogResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getAuditEntries responds with audit entries starting with the most recent. They can be filtered using the
+// "resource" and "id" query parameters. The "range" parameter limits the entries to a recent time range and
+// "limit" sets the maximum number of entries. Requests made as a User only get entries for resources in their Gardens
+func (a *auditLog) getAuditEntries(_ http.ResponseWriter, r *http.Request) render.Renderer {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to get audit log")
+
+	var since time.Time
+	if rangeString := r.URL.Query().Get("range"); rangeString != "" {
+		timeRange, err := time.ParseDuration(rangeString)
+		if err != nil {
+			logger.Error("unable to parse time range", "error", err)
+			return babyapi.ErrInvalidRequest(fmt.Errorf("invalid range: %w", err))
+		}
+		since = a.now().Add(-timeRange)
+	}
+
+	limit, err := limitQueryParam(r)
+	if err != nil {
+		logger.Error("unable to parse limit", "error", err)
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid limit: %w", err))
+	}
+
+	// The limit is applied after filtering entries for the User
+	user := userFromContext(r.Context())
+	storageLimit := limit
+	if user != nil {
+		storageLimit = 0
+	}
+
+	entries, err := a.storage.GetAuditEntries(r.Context(), r.URL.Query().Get("resource"), r.URL.Query().Get("id"), since, storageLimit)
+	if err != nil {
+		logger.Error("unable to get audit entries", "error", err)
+		return babyapi.InternalServerError(err)
+	}
+
+	if user != nil {
+		entries = slices.DeleteFunc(entries, func(e pkg.AuditEntry) bool {
+			gardenID := resourceGardenID(r.Context(), a.storageClient, e.ResourceType, e.ResourceID)
+			return !userCanReadGarden(r.Context(), a.storageClient, gardenID)
+		})
+		if limit > 0 && uint64(len(entries)) > limit {
+			entries = entries[:limit]
+		}
+	}
+
+	return &AuditLogResponse{Entries: entries, Count: len(entries)}
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAuditLog(t *testing.T) (*storage.Client, *auditLog) {
+	t.Helper()
+
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	audit := &auditLog{}
+	audit.setup(storageClient, time.Now)
+	return storageClient, audit
+}
+
+func TestAuditResourceChanges(t *testing.T) {
+	storageClient, audit := setupAuditLog(t)
+
+	wcr := NewWeatherClientsAPI()
+	addResourceEvents(wcr.API, nil, audit, "weather_client")
+	wcr.setup(storageClient)
+
+	r := httptest.NewRequest(http.MethodPost, "/weather_clients", strings.NewReader(`{"type":"fake","options":{"rain_interval":"24h"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r = r.WithContext(withActor(r.Context(), "test-token"))
+	w := babytest.TestRequest[*weather.Config](t, wcr.API, r)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created weather.Config
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	r = httptest.NewRequest(http.MethodDelete, "/weather_clients/"+created.GetID(), http.NoBody)
+	w = babytest.TestRequest[*weather.Config](t, wcr.API, r)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	entries, err := storageClient.AuditLog.GetAuditEntries(context.Background(), "weather_client", created.GetID(), time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "deleted", entries[0].Action)
+	assert.Equal(t, pkg.AuditSourceAPI, entries[0].Source)
+	assert.Empty(t, entries[0].Actor)
+
+	assert.Equal(t, "created", entries[1].Action)
+	assert.Equal(t, "test-token", entries[1].Actor)
+	assert.NotEmpty(t, entries[1].RemoteAddr)
+}
+
+func TestAuditGardenAction(t *testing.T) {
+	storageClient, audit := setupAuditLog(t)
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("LightTopic", "test-garden").Return("garden/action/light", nil)
+	mqttClient.On("Publish", "garden/action/light", mock.Anything).Return(nil)
+
+	gr := NewGardenAPI()
+	gr.audit = audit
+	require.NoError(t, gr.setup(Config{}, storageClient, nil, worker.NewWorker(storageClient, nil, mqttClient, slog.Default())))
+
+	garden := createExampleGarden()
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/gardens/%s/action", garden.ID), strings.NewReader(`{"light":{"state":"on"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := babytest.TestRequest[*pkg.Garden](t, gr.API, r)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	entries, err := storageClient.AuditLog.GetAuditEntries(context.Background(), "garden", garden.GetID(), time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "light_action", entries[0].Action)
+	assert.Equal(t, map[string]string{"state": "ON"}, entries[0].Details)
+	mqttClient.AssertExpectations(t)
+}
+
+func TestGetAuditEntries(t *testing.T) {
+	storageClient, audit := setupAuditLog(t)
+
+	now := time.Now()
+	for i, entry := range []pkg.AuditEntry{
+		{ResourceType: "zone", ResourceID: "zone1", Action: "water_action", Source: pkg.AuditSourceScheduler},
+		{ResourceType: "zone", ResourceID: "zone2", Action: "water_action", Source: pkg.AuditSourceAPI},
+		{ResourceType: "garden", ResourceID: "garden1", Action: "updated", Source: pkg.AuditSourceAPI},
+	} {
+		entry.Timestamp = now.Add(time.Duration(i-2) * time.Hour)
+		require.NoError(t, storageClient.AuditLog.AddAuditEntry(context.Background(), entry))
+	}
+
+	api := babyapi.NewRootAPI("garden-app", "/")
+	api.AddCustomRoute(http.MethodGet, auditPath, babyapi.Handler(audit.getAuditEntries))
+
+	tests := []struct {
+		name        string
+		query       string
+		expectedIDs []string
+		status      int
+	}{
+		{"All", "", []string{"garden1", "zone2", "zone1"}, http.StatusOK},
+		{"Resource", "?resource=zone", []string{"zone2", "zone1"}, http.StatusOK},
+		{"ResourceAndID", "?resource=zone&id=zone1", []string{"zone1"}, http.StatusOK},
+		{"Range", "?range=90m", []string{"garden1", "zone2"}, http.StatusOK},
+		{"Limit", "?limit=1", []string{"garden1"}, http.StatusOK},
+		{"ErrorInvalidRange", "?range=abc", nil, http.StatusBadRequest},
+		{"ErrorInvalidLimit", "?limit=-1", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, auditPath+tt.query, http.NoBody)
+			w := babytest.TestRequest[*babyapi.NilResource](t, api, r)
+			require.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var resp AuditLogResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, len(tt.expectedIDs), resp.Count)
+
+			ids := []string{}
+			for _, entry := range resp.Entries {
+				ids = append(ids, entry.ResourceID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestGetAuditEntriesForUser(t *testing.T) {
+	storageClient, audit := setupAuditLog(t)
+
+	user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+	garden := createExampleGarden()
+	garden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleViewer}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+	zone := createExampleZone()
+	zone.GardenID = garden.ID.ID
+	require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+	otherGarden := createExampleGarden()
+	otherGarden.ID = babyapi.NewID()
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), otherGarden))
+
+	now := time.Now()
+	for i, entry := range []pkg.AuditEntry{
+		{ResourceType: "zone", ResourceID: zone.GetID(), Action: "water_action"},
+		{ResourceType: "garden", ResourceID: garden.GetID(), Action: "updated"},
+		{ResourceType: "garden", ResourceID: otherGarden.GetID(), Action: "updated"},
+		{ResourceType: "water_schedule", ResourceID: "ws", Action: "updated"},
+	} {
+		entry.Timestamp = now.Add(time.Duration(i-4) * time.Hour)
+		require.NoError(t, storageClient.AuditLog.AddAuditEntry(context.Background(), entry))
+	}
+
+	api := babyapi.NewRootAPI("garden-app", "/")
+	api.AddCustomRoute(http.MethodGet, auditPath, babyapi.Handler(audit.getAuditEntries))
+
+	tests := []struct {
+		name        string
+		query       string
+		expectedIDs []string
+	}{
+		{"OnlyUsersGardens", "", []string{garden.GetID(), zone.GetID()}},
+		{"LimitAfterFiltering", "?limit=1", []string{garden.GetID()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, auditPath+tt.query, http.NoBody)
+			r = r.WithContext(withUser(r.Context(), user))
+			w := babytest.TestRequest[*babyapi.NilResource](t, api, r)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var resp AuditLogResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			ids := []string{}
+			for _, entry := range resp.Entries {
+				ids = append(ids, entry.ResourceID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
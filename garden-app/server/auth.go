@@ -0,0 +1,397 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+	"github.com/rs/xid"
+)
+
+var errUnauthorized = &babyapi.ErrResponse{HTTPStatusCode: http.StatusUnauthorized, StatusText: "Unauthorized"}
+
+type actorContextKey struct{}
+
+// withActor adds the name of the authenticated token or user to the context so it can be included in the audit log
+func withActor(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, name)
+}
+
+// actorFromContext gets the name of the authenticated token or user. It is empty when authentication is disabled
+func actorFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(actorContextKey{}).(string)
+	return name
+}
+
+type userContextKey struct{}
+
+// withUser adds the User that the request is limited to. It is not added for admin requests since they can access
+// every Garden
+func withUser(ctx context.Context, user *pkg.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// userFromContext gets the User that the request is limited to. It is nil when the request is not made as a User
+func userFromContext(ctx context.Context) *pkg.User {
+	user, _ := ctx.Value(userContextKey{}).(*pkg.User)
+	return user
+}
+
+// gardenAllows returns true if the request's User has a role for the Garden that allows the scope. It is always true
+// when the request is not limited to a User
+func gardenAllows(ctx context.Context, garden *pkg.Garden, scope pkg.APITokenScope) bool {
+	user := userFromContext(ctx)
+	return user == nil || garden.UserRole(user.GetID()).Allows(scope)
+}
+
+// userCanReadGarden returns true if the request's User has a role for the Garden that allows reading it. It is false
+// when the Garden can't be found and always true when the request is not limited to a User
+func userCanReadGarden(ctx context.Context, storageClient *storage.Client, gardenID string) bool {
+	return userGardenAllows(ctx, storageClient, gardenID, pkg.APITokenScopeRead)
+}
+
+// userGardenAllows gets the Garden and returns true if the request's User has a role for it that allows the scope.
+// It is false when the Garden can't be found and always true when the request is not limited to a User
+func userGardenAllows(ctx context.Context, storageClient *storage.Client, gardenID string, scope pkg.APITokenScope) bool {
+	if userFromContext(ctx) == nil {
+		return true
+	}
+	if gardenID == "" {
+		return false
+	}
+
+	garden, err := storageClient.Gardens.Get(ctx, gardenID)
+	if err != nil {
+		return false
+	}
+	return gardenAllows(ctx, garden, scope)
+}
+
+// userGardenMiddleware is an ID middleware for resources that belong to a Garden, but are not nested under /gardens,
+// so the authenticator can't check the User's role for the Garden. Resources are not found when the User's role
+// does not allow reading their Garden, and requests other than GET are forbidden unless it allows writing
+func userGardenMiddleware[T babyapi.Resource](api *babyapi.API[T], allows func(*http.Request, T, pkg.APITokenScope) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resource, err := api.GetResourceFromContext(r.Context())
+			// PUT can create new resources, which are checked when they are created
+			if err != nil || userFromContext(r.Context()) == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allows(r, resource, pkg.APITokenScopeRead) {
+				_ = render.Render(w, r, babyapi.ErrNotFoundResponse)
+				return
+			}
+			if r.Method != http.MethodGet && !allows(r, resource, pkg.APITokenScopeWrite) {
+				_ = render.Render(w, r, babyapi.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// resourceGardenID finds the Garden that a resource belongs to using the resource types from Events and the audit
+// log. It is empty for resources that don't belong to a Garden or can't be found
+func resourceGardenID(ctx context.Context, storageClient *storage.Client, resourceType, id string) string {
+	switch resourceType {
+	case "garden":
+		return id
+	case "zone":
+		return storedGardenID(ctx, storageClient.Zones, id, func(z *pkg.Zone) xid.ID { return z.GardenID })
+	case "zone_group":
+		return storedGardenID(ctx, storageClient.ZoneGroups, id, func(zg *pkg.ZoneGroup) xid.ID { return zg.GardenID })
+	case "plant":
+		return storedGardenID(ctx, storageClient.Plants, id, func(p *pkg.Plant) xid.ID { return p.GardenID })
+	case "reminder":
+		return storedGardenID(ctx, storageClient.Reminders, id, func(r *pkg.Reminder) xid.ID { return r.GardenID })
+	case "photo":
+		return storedGardenID(ctx, storageClient.Photos, id, func(p *pkg.Photo) xid.ID { return p.GardenID })
+	default:
+		return ""
+	}
+}
+
+func storedGardenID[T babyapi.Resource](ctx context.Context, s babyapi.Storage[T], id string, gardenID func(T) xid.ID) string {
+	resource, err := s.Get(ctx, id)
+	if err != nil {
+		return ""
+	}
+	result := gardenID(resource)
+	if result.IsNil() {
+		return ""
+	}
+	return result.String()
+}
+
+// authenticator checks that requests use a token from the config, one created with the /tokens API, or an ID token
+// from the OIDC provider, and that the token has the scope required for the request. Requests made as a User are
+// also limited by the User's roles
+type authenticator struct {
+	tokens  []TokenConfig
+	storage babyapi.Storage[*pkg.APIToken]
+	oidc    *oidcAuthenticator
+	users   babyapi.Storage[*pkg.User]
+	gardens babyapi.Storage[*pkg.Garden]
+}
+
+func newAuthenticator(cfg AuthConfig, storage babyapi.Storage[*pkg.APIToken]) (*authenticator, error) {
+	for _, t := range cfg.Tokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("invalid auth token %q: missing required token field", t.Name)
+		}
+		err := pkg.ValidateAPITokenScopes(t.Scopes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth token %q: %w", t.Name, err)
+		}
+	}
+
+	return &authenticator{tokens: cfg.Tokens, storage: storage}, nil
+}
+
+func (a *authenticator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := babyapi.GetLoggerFromContext(r.Context())
+
+		// Probes do not have a token
+		if r.URL.Path == healthzPath || r.URL.Path == readyzPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The login endpoints must be reachable before logging in
+		if a.oidc != nil && strings.HasPrefix(r.URL.Path, oidcBasePath+"/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := tokenFromRequest(r)
+		if token == "" && a.oidc != nil {
+			if cookie, err := r.Cookie(sessionCookieName); err == nil {
+				token = cookie.Value
+			}
+		}
+		if token == "" {
+			logger.Info("received request without API token")
+			a.unauthorized(w, r)
+			return
+		}
+
+		name, scopes, user, err := a.lookup(r.Context(), token)
+		if err != nil {
+			logger.Error("error looking up API token", "error", err)
+			render.Render(w, r, babyapi.InternalServerError(err))
+			return
+		}
+		if scopes == nil {
+			logger.Info("received request with invalid API token")
+			a.unauthorized(w, r)
+			return
+		}
+
+		scope := requiredScope(r)
+		if !pkg.HasAPITokenScope(scopes, scope) {
+			logger.Info("API token is missing required scope", "token_name", name, "scope", scope)
+			render.Render(w, r, babyapi.ErrForbidden)
+			return
+		}
+
+		ctx := withActor(r.Context(), name)
+
+		// Admin requests can access every Garden, so they are not limited by the User's roles
+		if user != nil && !pkg.HasAPITokenScope(scopes, pkg.APITokenScopeAdmin) {
+			allowed, err := a.userAllowed(r.Context(), user, r.URL.Path, scope)
+			if err != nil {
+				logger.Error("error checking User's role", "error", err)
+				render.Render(w, r, babyapi.InternalServerError(err))
+				return
+			}
+			if !allowed {
+				logger.Info("User's role does not allow request", "user_id", user.GetID(), "scope", scope)
+				render.Render(w, r, babyapi.ErrForbidden)
+				return
+			}
+			ctx = withUser(ctx, user)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// lookup finds the name, scopes, and User for the token. Scopes are nil if the token is not found. The User is nil
+// if the token is not used by a User
+func (a *authenticator) lookup(ctx context.Context, token string) (string, []pkg.APITokenScope, *pkg.User, error) {
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			return t.Name, t.Scopes, nil, nil
+		}
+	}
+
+	var storedTokens []*pkg.APIToken
+	if a.storage != nil {
+		var err error
+		storedTokens, err = a.storage.GetAll(ctx, nil)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("error getting APITokens: %w", err)
+		}
+	}
+
+	hash := pkg.HashAPIToken(token)
+	for _, t := range storedTokens {
+		if subtle.ConstantTimeCompare([]byte(t.TokenHash), []byte(hash)) != 1 {
+			continue
+		}
+		if t.UserID == "" {
+			return t.Name, t.Scopes, nil, nil
+		}
+
+		// The token is invalid once its User is deleted
+		user, err := a.getUser(ctx, t.UserID)
+		if err != nil || user == nil {
+			return "", nil, nil, err
+		}
+		return t.Name, t.Scopes, user, nil
+	}
+
+	if a.oidc != nil {
+		if name := a.oidc.verify(ctx, token); name != "" {
+			// The name is the user's email when the provider includes it, so it is used to find the User
+			user, err := a.getUserByEmail(ctx, name)
+			if err != nil {
+				return "", nil, nil, err
+			}
+			return name, a.oidc.scopes, user, nil
+		}
+	}
+
+	return "", nil, nil, nil
+}
+
+// getUser gets the User by ID. It is nil if the User does not exist
+func (a *authenticator) getUser(ctx context.Context, id string) (*pkg.User, error) {
+	if a.users == nil {
+		return nil, nil
+	}
+
+	user, err := a.users.Get(ctx, id)
+	if errors.Is(err, babyapi.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting User %q: %w", id, err)
+	}
+	return user, nil
+}
+
+// getUserByEmail finds the User with the email. It is nil if there isn't one, so logging in with OIDC without a
+// User is only limited by the OIDC scopes
+func (a *authenticator) getUserByEmail(ctx context.Context, email string) (*pkg.User, error) {
+	if a.users == nil {
+		return nil, nil
+	}
+
+	users, err := a.users.GetAll(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting Users: %w", err)
+	}
+	for _, u := range users {
+		if u.Email != "" && strings.EqualFold(u.Email, email) {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+// userAllowed checks the User's role for the request. Requests for a Garden and its nested resources use the
+// Garden's role for the User. Everything else, including creating Gardens, uses the User's own role
+func (a *authenticator) userAllowed(ctx context.Context, user *pkg.User, path string, scope pkg.APITokenScope) (bool, error) {
+	gardenID := gardenIDFromPath(path)
+	if gardenID == "" || a.gardens == nil {
+		return user.Role.Allows(scope), nil
+	}
+
+	garden, err := a.gardens.Get(ctx, gardenID)
+	// Paths like /gardens/components are not Gardens, and the API responds with 404 for missing Gardens
+	if errors.Is(err, babyapi.ErrNotFound) {
+		return user.Role.Allows(scope), nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting Garden %q: %w", gardenID, err)
+	}
+
+	return garden.UserRole(user.GetID()).Allows(scope), nil
+}
+
+// gardenIDFromPath gets the Garden ID from paths for a Garden or its nested resources. It is empty for other paths
+func gardenIDFromPath(path string) string {
+	rest, found := strings.CutPrefix(path, gardenBasePath+"/")
+	if !found {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	return id
+}
+
+// tokenFromRequest reads the token from a Bearer or Basic Authorization header. Basic auth uses the token as the
+// password so browsers can prompt for it. Since browsers cannot set headers for WebSockets, GET requests can also
+// use the access_token query parameter
+func tokenFromRequest(r *http.Request) string {
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		return token
+	}
+	if _, password, ok := r.BasicAuth(); ok {
+		return password
+	}
+	if r.Method == http.MethodGet {
+		return r.URL.Query().Get("access_token")
+	}
+	return ""
+}
+
+// requiredScope determines which scope is needed for the request. Managing APITokens, Users, Webhooks, and backups
+// and the /admin endpoints require the admin scope.
+// The WeatherClient OAuth flow uses GET requests, but it requires the write scope since it stores new tokens
+func requiredScope(r *http.Request) pkg.APITokenScope {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == apiTokensBasePath || strings.HasPrefix(path, apiTokensBasePath+"/"):
+		return pkg.APITokenScopeAdmin
+	case path == usersBasePath || strings.HasPrefix(path, usersBasePath+"/"):
+		return pkg.APITokenScopeAdmin
+	case path == webhooksBasePath || strings.HasPrefix(path, webhooksBasePath+"/"):
+		return pkg.APITokenScopeAdmin
+	case path == backupsPath || path == restorePath || strings.HasPrefix(path, "/admin/"):
+		return pkg.APITokenScopeAdmin
+	case strings.HasPrefix(path, weatherClientsBasePath+"/") && strings.Contains(path, "/oauth/"):
+		return pkg.APITokenScopeWrite
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return pkg.APITokenScopeRead
+	case r.Method == http.MethodPost && (strings.HasSuffix(path, "/action") || strings.HasSuffix(path, "/test")):
+		return pkg.APITokenScopeActions
+	default:
+		return pkg.APITokenScopeWrite
+	}
+}
+
+// unauthorized redirects browsers to log in when OIDC is enabled. Otherwise, it responds with 401
+func (a *authenticator) unauthorized(w http.ResponseWriter, r *http.Request) {
+	if a.oidc != nil && r.Method == http.MethodGet && render.GetAcceptedContentType(r) == render.ContentTypeHTML {
+		http.Redirect(w, r, oidcBasePath+"/login?redirect="+url.QueryEscape(r.URL.RequestURI()), http.StatusFound)
+		return
+	}
+
+	w.Header().Add("WWW-Authenticate", `Bearer realm="garden-app"`)
+	w.Header().Add("WWW-Authenticate", `Basic realm="garden-app"`)
+	render.Render(w, r, errUnauthorized)
+}
@@ -0,0 +1,306 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	storedToken := &pkg.APIToken{
+		ID:     babyapi.NewID(),
+		Name:   "dashboard",
+		Scopes: []pkg.APITokenScope{pkg.APITokenScopeRead},
+	}
+	require.NoError(t, storedToken.GenerateToken())
+	require.NoError(t, storageClient.APITokens.Set(context.Background(), storedToken))
+
+	auth, err := newAuthenticator(AuthConfig{
+		Tokens: []TokenConfig{
+			{Name: "cli", Token: "admin-token", Scopes: []pkg.APITokenScope{pkg.APITokenScopeAdmin}},
+			{Name: "actions", Token: "actions-token", Scopes: []pkg.APITokenScope{pkg.APITokenScopeActions}},
+		},
+	}, storageClient.APITokens)
+	require.NoError(t, err)
+
+	// NewAPI can only be used once per test since it registers metrics, so this uses a simple API with the
+	// same middleware
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tokensAPI := NewAPITokensAPI()
+	tokensAPI.setup(storageClient)
+	api := babyapi.NewRootAPI("test", "/").
+		AddMiddleware(auth.middleware).
+		AddCustomRoute(http.MethodGet, "/gardens", okHandler).
+		AddCustomRoute(http.MethodPost, "/gardens", okHandler).
+		AddCustomRoute(http.MethodPost, "/gardens/{id}/action", okHandler).
+		AddCustomRoute(http.MethodGet, "/weather_clients/{id}/oauth/start", okHandler).
+		AddCustomRoute(http.MethodGet, readyzPath, okHandler).
+		AddCustomRoute(http.MethodPost, reloadPath, okHandler).
+		AddCustomRoute(http.MethodGet, webhooksBasePath, okHandler).
+		AddNestedAPI(tokensAPI)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		setAuth        func(*http.Request)
+		expectedStatus int
+	}{
+		{
+			"NoToken",
+			http.MethodGet, "/gardens",
+			func(*http.Request) {},
+			http.StatusUnauthorized,
+		},
+		{
+			"ProbeWithoutToken",
+			http.MethodGet, readyzPath,
+			func(*http.Request) {},
+			http.StatusOK,
+		},
+		{
+			"InvalidToken",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") },
+			http.StatusUnauthorized,
+		},
+		{
+			"ConfigTokenBearer",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer admin-token") },
+			http.StatusOK,
+		},
+		{
+			"ConfigTokenBasicAuth",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.SetBasicAuth("", "admin-token") },
+			http.StatusOK,
+		},
+		{
+			"StoredTokenRead",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+storedToken.Token()) },
+			http.StatusOK,
+		},
+		{
+			"StoredTokenQueryParam",
+			http.MethodGet, "/gardens?access_token=" + storedToken.Token(),
+			func(*http.Request) {},
+			http.StatusOK,
+		},
+		{
+			"StoredTokenCannotWrite",
+			http.MethodPost, "/gardens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+storedToken.Token()) },
+			http.StatusForbidden,
+		},
+		{
+			"StoredTokenCannotManageTokens",
+			http.MethodGet, "/tokens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+storedToken.Token()) },
+			http.StatusForbidden,
+		},
+		{
+			"StoredTokenCannotManageWebhooks",
+			http.MethodGet, webhooksBasePath,
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+storedToken.Token()) },
+			http.StatusForbidden,
+		},
+		{
+			"StoredTokenCannotStartWeatherClientOAuth",
+			http.MethodGet, "/weather_clients/c5cvhpcbcv45e8bp16dg/oauth/start",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+storedToken.Token()) },
+			http.StatusForbidden,
+		},
+		{
+			"ActionsTokenCannotRead",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer actions-token") },
+			http.StatusForbidden,
+		},
+		{
+			"ActionsTokenCanSendAction",
+			http.MethodPost, "/gardens/c5cvhpcbcv45e8bp16dg/action",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer actions-token") },
+			http.StatusOK,
+		},
+		{
+			"ActionsTokenCannotReloadConfig",
+			http.MethodPost, reloadPath,
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer actions-token") },
+			http.StatusForbidden,
+		},
+		{
+			"AdminTokenCanReloadConfig",
+			http.MethodPost, reloadPath,
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer admin-token") },
+			http.StatusOK,
+		},
+		{
+			"AdminTokenCanManageTokens",
+			http.MethodGet, "/tokens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer admin-token") },
+			http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(tt.method, tt.path, strings.NewReader(`{}`))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+			tt.setAuth(r)
+
+			w := babytest.TestRequest(t, api, r)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.Equal(t, []string{`Bearer realm="garden-app"`, `Basic realm="garden-app"`}, w.Header().Values("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareUsers(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	operator := &pkg.User{ID: babyapi.NewID(), Name: "operator", Role: pkg.UserRoleViewer}
+	require.NoError(t, storageClient.Users.Set(context.Background(), operator))
+	owner := &pkg.User{ID: babyapi.NewID(), Name: "owner", Role: pkg.UserRoleOwner}
+	require.NoError(t, storageClient.Users.Set(context.Background(), owner))
+
+	garden := createExampleGarden()
+	garden.Users = map[string]pkg.UserRole{operator.GetID(): pkg.UserRoleOperator}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+
+	createToken := func(userID string, scopes ...pkg.APITokenScope) string {
+		token := &pkg.APIToken{ID: babyapi.NewID(), Name: "token", Scopes: scopes, UserID: userID}
+		require.NoError(t, token.GenerateToken())
+		require.NoError(t, storageClient.APITokens.Set(context.Background(), token))
+		return token.Token()
+	}
+	allScopes := []pkg.APITokenScope{pkg.APITokenScopeRead, pkg.APITokenScopeWrite, pkg.APITokenScopeActions}
+	operatorToken := createToken(operator.GetID(), allScopes...)
+	ownerToken := createToken(owner.GetID(), allScopes...)
+	adminToken := createToken(operator.GetID(), pkg.APITokenScopeAdmin)
+	deletedUserToken := createToken(babyapi.NewID().String(), allScopes...)
+
+	auth, err := newAuthenticator(AuthConfig{}, storageClient.APITokens)
+	require.NoError(t, err)
+	auth.users = storageClient.Users
+	auth.gardens = storageClient.Gardens
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	api := babyapi.NewRootAPI("test", "/").
+		AddMiddleware(auth.middleware).
+		AddCustomRoute(http.MethodPost, "/gardens", okHandler).
+		AddCustomRoute(http.MethodGet, "/gardens/{id}", okHandler).
+		AddCustomRoute(http.MethodPatch, "/gardens/{id}", okHandler).
+		AddCustomRoute(http.MethodPost, "/gardens/{id}/action", okHandler).
+		AddCustomRoute(http.MethodGet, "/water_schedules", okHandler).
+		AddCustomRoute(http.MethodPost, "/water_schedules", okHandler).
+		AddCustomRoute(http.MethodGet, "/users", okHandler)
+
+	gardenPath := "/gardens/" + garden.GetID()
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		expectedStatus int
+	}{
+		{"OperatorCanGetGarden", http.MethodGet, gardenPath, operatorToken, http.StatusOK},
+		{"OperatorCanSendAction", http.MethodPost, gardenPath + "/action", operatorToken, http.StatusOK},
+		{"OperatorCannotChangeGarden", http.MethodPatch, gardenPath, operatorToken, http.StatusForbidden},
+		{"ViewerRoleCanGetWaterSchedules", http.MethodGet, "/water_schedules", operatorToken, http.StatusOK},
+		{"ViewerRoleCannotCreateWaterSchedule", http.MethodPost, "/water_schedules", operatorToken, http.StatusForbidden},
+		{"ViewerRoleCannotCreateGarden", http.MethodPost, "/gardens", operatorToken, http.StatusForbidden},
+		{"OwnerRoleCanCreateGarden", http.MethodPost, "/gardens", ownerToken, http.StatusOK},
+		{"NoGardenRole", http.MethodGet, gardenPath, ownerToken, http.StatusForbidden},
+		{"MissingGardenUsesUserRole", http.MethodGet, "/gardens/components", ownerToken, http.StatusOK},
+		{"OwnerRoleCannotManageUsers", http.MethodGet, "/users", ownerToken, http.StatusForbidden},
+		{"AdminIsNotLimitedByRole", http.MethodPatch, gardenPath, adminToken, http.StatusOK},
+		{"AdminCanManageUsers", http.MethodGet, "/users", adminToken, http.StatusOK},
+		{"DeletedUser", http.MethodGet, "/water_schedules", deletedUserToken, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(tt.method, tt.path, strings.NewReader(`{}`))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+
+			w := babytest.TestRequest(t, api, r)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestGardenIDFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/gardens", ""},
+		{"/gardens/c5cvhpcbcv45e8bp16dg", "c5cvhpcbcv45e8bp16dg"},
+		{"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/action", "c5cvhpcbcv45e8bp16dg"},
+		{"/water_schedules/c5cvhpcbcv45e8bp16dg", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, gardenIDFromPath(tt.path))
+		})
+	}
+}
+
+func TestNewAuthenticatorInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       TokenConfig
+		expectedErr string
+	}{
+		{
+			"MissingToken",
+			TokenConfig{Name: "cli", Scopes: []pkg.APITokenScope{pkg.APITokenScopeAdmin}},
+			`invalid auth token "cli": missing required token field`,
+		},
+		{
+			"MissingScopes",
+			TokenConfig{Name: "cli", Token: "token"},
+			`invalid auth token "cli": missing required scopes field`,
+		},
+		{
+			"InvalidScope",
+			TokenConfig{Name: "cli", Token: "token", Scopes: []pkg.APITokenScope{"everything"}},
+			`invalid auth token "cli": invalid scope "everything"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newAuthenticator(AuthConfig{Tokens: []TokenConfig{tt.token}}, nil)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/backup"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const (
+	backupsPath = "/backups"
+	backupParam = "backup"
+)
+
+var errBackupsNotConfigured = &babyapi.ErrResponse{
+	HTTPStatusCode: http.StatusNotImplemented,
+	StatusText:     "Not Implemented",
+	ErrorText:      "backups are not configured",
+}
+
+// setupBackups adds routes for listing and creating backups and restoring storage from a backup
+func (api *API) setupBackups(storageClient *storage.Client, w *worker.Worker) {
+	api.API.
+		AddCustomRoute(http.MethodGet, backupsPath, babyapi.Handler(func(_ http.ResponseWriter, r *http.Request) render.Renderer {
+			return listBackups(r, w)
+		})).
+		AddCustomRoute(http.MethodPost, backupsPath, babyapi.Handler(func(_ http.ResponseWriter, r *http.Request) render.Renderer {
+			return createBackup(r, w)
+		})).
+		AddCustomRoute(http.MethodPost, restorePath, babyapi.Handler(func(_ http.ResponseWriter, r *http.Request) render.Renderer {
+			return restoreBackup(r, storageClient, w)
+		}))
+}
+
+// BackupsResponse is used to list the saved backups
+type BackupsResponse struct {
+	Items []backup.Info `json:"items"`
+}
+
+func (resp *BackupsResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// BackupResponse describes a backup that was just created
+type BackupResponse struct {
+	backup.Info
+}
+
+func (resp *BackupResponse) Render(_ http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusCreated)
+	return nil
+}
+
+// RestoreResponse summarizes the resources and history that were restored
+type RestoreResponse struct {
+	*storage.MigrationSummary
+}
+
+func (resp *RestoreResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func listBackups(r *http.Request, w *worker.Worker) render.Renderer {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	backups, err := w.ListBackups(r.Context())
+	if errors.Is(err, worker.ErrBackupsNotConfigured) {
+		return errBackupsNotConfigured
+	}
+	if err != nil {
+		logger.Error("unable to list backups", "error", err)
+		return babyapi.InternalServerError(err)
+	}
+
+	return &BackupsResponse{Items: backups}
+}
+
+func createBackup(r *http.Request, w *worker.Worker) render.Renderer {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to create backup")
+
+	info, err := w.CreateBackup(r.Context())
+	if errors.Is(err, worker.ErrBackupsNotConfigured) {
+		return errBackupsNotConfigured
+	}
+	if err != nil {
+		logger.Error("unable to create backup", "error", err)
+		return babyapi.InternalServerError(err)
+	}
+
+	logger.Info("created backup", "key", info.Key)
+	return &BackupResponse{info}
+}
+
+// restoreBackup saves everything from a backup and then reschedules the restored Gardens, WaterSchedules, and
+// Reminders. The "backup" query parameter chooses a saved backup, otherwise the backup is read from the request body
+func restoreBackup(r *http.Request, storageClient *storage.Client, w *worker.Worker) render.Renderer {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	var (
+		b   *storage.Backup
+		err error
+	)
+	if key := r.URL.Query().Get(backupParam); key != "" {
+		logger = logger.With("key", key)
+		logger.Info("received request to restore saved backup")
+
+		b, err = w.GetBackup(r.Context(), key)
+		switch {
+		case errors.Is(err, worker.ErrBackupsNotConfigured):
+			return errBackupsNotConfigured
+		case errors.Is(err, backup.ErrNotFound):
+			return babyapi.ErrNotFoundResponse
+		case err != nil:
+			logger.Error("unable to read backup", "error", err)
+			return babyapi.ErrInvalidRequest(err)
+		}
+	} else {
+		logger.Info("received request to restore backup from request body")
+
+		b = &storage.Backup{}
+		err = json.NewDecoder(r.Body).Decode(b)
+		if err != nil {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("error decoding backup: %w", err))
+		}
+	}
+
+	summary, err := storageClient.Restore(r.Context(), b)
+	if err != nil {
+		logger.Error("unable to restore backup", "error", err)
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	err = resetSchedules(w, &b.Export)
+	if err != nil {
+		logger.Error("unable to reset schedules for restored resources", "error", err)
+		return babyapi.InternalServerError(err)
+	}
+
+	logger.Info("restored backup", "gardens", summary.Gardens, "zones", summary.Zones)
+	return &RestoreResponse{summary}
+}
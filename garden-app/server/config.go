@@ -1,22 +1,86 @@
 package server
 
 import (
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/backup"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/photos"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
 )
 
 // Config holds all the options and sub-configs for the server
 type Config struct {
 	WebConfig      `mapstructure:"web_server"`
-	InfluxDBConfig influxdb.Config `mapstructure:"influxdb"`
-	MQTTConfig     mqtt.Config     `mapstructure:"mqtt"`
-	StorageConfig  storage.Config  `mapstructure:"storage"`
-	LogConfig      LogConfig       `mapstructure:"log"`
+	InfluxDBConfig influxdb.Config             `mapstructure:"influxdb"`
+	MetricsConfig  metrics.Config              `mapstructure:"metrics"`
+	MQTTConfig     mqtt.Config                 `mapstructure:"mqtt"`
+	StorageConfig  storage.Config              `mapstructure:"storage"`
+	LogConfig      LogConfig                   `mapstructure:"log"`
+	Simulation     SimulationConfig            `mapstructure:"simulation"`
+	Health         HealthConfig                `mapstructure:"health"`
+	GRPC           GRPCConfig                  `mapstructure:"grpc"`
+	LeakDetection  worker.LeakDetectionConfig  `mapstructure:"leak_detection"`
+	CatchUp        worker.CatchUpConfig        `mapstructure:"catch_up"`
+	WaterAck       worker.WaterAckConfig       `mapstructure:"water_ack"`
+	WeatherRetry   worker.WeatherRetryConfig   `mapstructure:"weather_retry"`
+	ManualWatering worker.ManualWateringConfig `mapstructure:"manual_watering"`
+	Photos         photos.Config               `mapstructure:"photos"`
+	Backup         backup.Config               `mapstructure:"backup"`
+	Firmware       FirmwareConfig              `mapstructure:"firmware"`
+	Declarative    DeclarativeConfig           `mapstructure:"declarative"`
+	Tracing        tracing.Config              `mapstructure:"tracing"`
+	// BlackoutWindows are times when no Gardens are watered, in addition to each Garden's own BlackoutWindows
+	BlackoutWindows []pkg.BlackoutWindow `mapstructure:"blackout_windows"`
+}
+
+// GRPCConfig enables the gRPC API. It runs alongside the HTTP server when Port is set
+type GRPCConfig struct {
+	Port int `mapstructure:"port"`
+}
+
+// HealthConfig configures how the health of Garden controllers is tracked
+type HealthConfig struct {
+	// DownThreshold is how long a controller can go without publishing health data before it is considered "DOWN"
+	DownThreshold time.Duration `mapstructure:"down_threshold"`
 }
 
 // WebConfig is used to allow reading the "web_server" section into the main Config struct
 type WebConfig struct {
 	Port     int  `mapstructure:"port"`
 	ReadOnly bool `mapstructure:"readonly"`
+	// AllowedOrigins are additional origins, like a separately-hosted dashboard, that can connect to /events
+	AllowedOrigins []string   `mapstructure:"allowed_origins"`
+	Auth           AuthConfig `mapstructure:"auth"`
+	OIDC           OIDCConfig `mapstructure:"oidc"`
+}
+
+// AuthConfig configures API token authentication. It is only enabled when at least one token is configured. These
+// tokens are used to create more tokens with the /tokens API
+type AuthConfig struct {
+	Tokens []TokenConfig `mapstructure:"tokens"`
+}
+
+// OIDCConfig enables logging in to the web UI with an OpenID Connect provider. Browsers get a session cookie after
+// logging in, and API clients can use an ID token from the provider as a Bearer token
+type OIDCConfig struct {
+	IssuerURL    string `mapstructure:"issuer_url"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	// RedirectURL is the externally-reachable URL of the /auth/callback endpoint
+	RedirectURL string `mapstructure:"redirect_url"`
+	// Scopes are the API scopes given to logged-in users. It defaults to read, write, and actions
+	Scopes []pkg.APITokenScope `mapstructure:"scopes"`
+}
+
+// TokenConfig is a static API token and the scopes it is allowed to use
+type TokenConfig struct {
+	Name   string              `mapstructure:"name"`
+	Token  string              `mapstructure:"token"`
+	Scopes []pkg.APITokenScope `mapstructure:"scopes"`
 }
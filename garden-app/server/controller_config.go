@@ -0,0 +1,133 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/controller/firmware"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const (
+	controllerConfigPath = "/controller_config"
+
+	defaultControllerWaterTime      = 5 * time.Second
+	defaultControllerHealthInterval = time.Minute
+	defaultControllerSensorInterval = 5 * time.Minute
+)
+
+// controllerConfig responds with the garden-controller's config.h rendered from the Garden's ControllerHardware and
+// its Zones' HardwareConfigs so the firmware always matches the server's view of the Garden. The MQTT broker defaults
+// to the server's and can be changed with the "mqtt_broker" query parameter when the controller reaches it at a
+// different address
+func (api *GardensAPI) controllerConfig(w http.ResponseWriter, r *http.Request) render.Renderer {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	garden, httpErr := api.GetRequestedResource(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	logger.Info("received request to get Garden controller config")
+
+	if garden.UsesOpenSprinkler() || garden.UsesTasmota() {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("controller config is not available for %s controllers", garden.ControllerType))
+	}
+
+	zones, err := api.getAllZones(r.Context(), garden.ID.String(), false)
+	if err != nil {
+		return babyapi.InternalServerError(err)
+	}
+
+	config, err := api.firmwareConfig(r, garden, zones)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	result, err := firmware.Render(config)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, err = w.Write([]byte(result))
+	if err != nil {
+		logger.Error("unable to write controller config", "error", err)
+	}
+
+	return nil
+}
+
+// firmwareConfig creates the firmware.Config for the Garden. Features are enabled when their pins are set
+func (api *GardensAPI) firmwareConfig(r *http.Request, garden *pkg.Garden, zones []*pkg.Zone) (firmware.Config, error) {
+	hardware := pkg.ControllerHardware{}
+	if garden.ControllerHardware != nil {
+		hardware = *garden.ControllerHardware
+	}
+
+	board := hardware.Board
+	if board == "" {
+		board = firmware.BoardESP32
+	}
+	profile, err := firmware.GetBoardProfile(board)
+	if err != nil {
+		return firmware.Config{}, err
+	}
+
+	broker := api.config.MQTTConfig.Broker
+	if override := r.URL.Query().Get("mqtt_broker"); override != "" {
+		broker = override
+	}
+
+	config := firmware.Config{
+		TopicPrefix:                 garden.TopicPrefix,
+		MQTTBroker:                  broker,
+		MQTTPort:                    api.config.MQTTConfig.Port,
+		Board:                       board,
+		DefaultWaterTime:            defaultControllerWaterTime,
+		LightPin:                    hardware.LightPin,
+		StopButtonPin:               hardware.StopButtonPin,
+		MoistureInterval:            defaultControllerSensorInterval,
+		FlowMeterPulsesPerLiter:     firmware.DefaultFlowMeterPulsesPerLiter,
+		PublishHealth:               true,
+		HealthInterval:              defaultControllerHealthInterval,
+		PublishTemperatureHumidity:  garden.HasTemperatureHumiditySensor() && hardware.TemperatureHumidityPin != "",
+		TemperatureHumidityPin:      hardware.TemperatureHumidityPin,
+		TemperatureHumidityInterval: defaultControllerSensorInterval,
+	}
+	if hardware.DefaultWaterTime != nil {
+		config.DefaultWaterTime = hardware.DefaultWaterTime.Duration
+	}
+	if hardware.FlowMeterPulsesPerLiter != nil {
+		config.FlowMeterPulsesPerLiter = *hardware.FlowMeterPulsesPerLiter
+	}
+
+	err = garden.ValidatePinConflicts(zones)
+	if err != nil {
+		return firmware.Config{}, err
+	}
+
+	config.Zones, err = pkg.ControllerZones(zones)
+	if err != nil {
+		return firmware.Config{}, err
+	}
+
+	for _, z := range config.Zones {
+		config.EnableButtons = config.EnableButtons || z.ButtonPin != ""
+		config.EnableMoistureSensor = config.EnableMoistureSensor || z.MoistureSensorPin != ""
+		config.EnableFlowMeter = config.EnableFlowMeter || z.FlowMeterPin != ""
+		config.EnableDosing = config.EnableDosing || z.DosingPin != ""
+	}
+
+	// Zone buttons are only enabled with the stop button, so it uses the board's NoPin when it is not set
+	if hardware.StopButtonPin != "" {
+		config.EnableButtons = true
+	}
+	if config.EnableButtons && config.StopButtonPin == "" {
+		config.StopButtonPin = profile.NoPin
+	}
+
+	return config, nil
+}
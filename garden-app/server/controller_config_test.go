@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGardenControllerConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		updateGarden   func(*pkg.Garden)
+		updateZone     func(*pkg.Zone)
+		expectedLines  []string
+		expectedStatus int
+	}{
+		{
+			"Successful",
+			"",
+			func(g *pkg.Garden) {
+				g.ControllerHardware = &pkg.ControllerHardware{LightPin: "GPIO_NUM_32"}
+			},
+			func(z *pkg.Zone) {
+				z.HardwareConfig = &pkg.HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}
+			},
+			[]string{
+				`#define TOPIC_PREFIX "test-garden"`,
+				`#define MQTT_ADDRESS "mqtt.example.com"`,
+				`#define MQTT_PORT 1883`,
+				`#define NUM_ZONES 1`,
+				`#define ZONES { { GPIO_NUM_18, GPIO_NUM_16, GPIO_NUM_MAX, GPIO_NUM_MAX } }`,
+				`#define DEFAULT_WATER_TIME 5000`,
+				`#define LIGHT_PIN GPIO_NUM_32`,
+			},
+			http.StatusOK,
+		},
+		{
+			"SuccessfulWithFeaturesAndBrokerOverride",
+			"?mqtt_broker=192.168.0.10",
+			func(g *pkg.Garden) {
+				g.ControllerHardware = &pkg.ControllerHardware{
+					Board:            "esp8266",
+					StopButtonPin:    "D1",
+					DefaultWaterTime: &pkg.Duration{Duration: 15 * time.Second},
+				}
+			},
+			func(z *pkg.Zone) {
+				z.HardwareConfig = &pkg.HardwareConfig{PumpPin: "D5", ValvePin: "D6", ButtonPin: "D2", MoistureSensorPin: "A0"}
+			},
+			[]string{
+				`#define MQTT_ADDRESS "192.168.0.10"`,
+				`#define ZONES { { D5, D6, D2, A0 } }`,
+				`#define DEFAULT_WATER_TIME 15000`,
+				`#define STOP_BUTTON_PIN D1`,
+				`#define MOISTURE_SENSOR_AIR_VALUE 853`,
+			},
+			http.StatusOK,
+		},
+		{
+			"ErrorMissingPins",
+			"",
+			func(*pkg.Garden) {},
+			func(*pkg.Zone) {},
+			[]string{`{"status":"Invalid request.","error":"zone \"test-zone\" is missing required pump_pin and valve_pin"}`},
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorPositionGap",
+			"",
+			func(*pkg.Garden) {},
+			func(z *pkg.Zone) {
+				one := uint(1)
+				z.Position = &one
+				z.HardwareConfig = &pkg.HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}
+			},
+			[]string{`{"status":"Invalid request.","error":"missing zone with position 0: positions must start at 0 without gaps"}`},
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorPinConflict",
+			"",
+			func(g *pkg.Garden) {
+				g.ControllerHardware = &pkg.ControllerHardware{LightPin: "GPIO_NUM_16"}
+			},
+			func(z *pkg.Zone) {
+				z.HardwareConfig = &pkg.HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_16"}
+			},
+			[]string{`{"status":"Invalid request.","error":"pin \"GPIO_NUM_16\" is used by light_pin and zone \"test-zone\" valve_pin"}`},
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorInvalidPin",
+			"",
+			func(*pkg.Garden) {},
+			func(z *pkg.Zone) {
+				z.HardwareConfig = &pkg.HardwareConfig{PumpPin: "GPIO_NUM_18", ValvePin: "GPIO_NUM_34"}
+			},
+			[]string{`{"status":"Invalid request.","error":"invalid pins: zones[0].valve_pin: pin \"GPIO_NUM_34\" on esp32 cannot be used as an output"}`},
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorTasmota",
+			"",
+			func(g *pkg.Garden) {
+				g.ControllerType = pkg.ControllerTypeTasmota
+				g.LightSchedule = nil
+			},
+			func(*pkg.Zone) {},
+			[]string{`{"status":"Invalid request.","error":"controller config is not available for tasmota controllers"}`},
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+
+			garden := createExampleGarden()
+			tt.updateGarden(garden)
+			err := storageClient.Gardens.Set(context.Background(), garden)
+			require.NoError(t, err)
+
+			zone := createExampleZone()
+			tt.updateZone(zone)
+			err = storageClient.Zones.Set(context.Background(), zone)
+			require.NoError(t, err)
+
+			influxdbClient := new(influxdb.MockClient)
+			gr := NewGardenAPI()
+			err = gr.setup(Config{MQTTConfig: mqtt.Config{Broker: "mqtt.example.com", Port: 1883}}, storageClient, influxdbClient, worker.NewWorker(storageClient, influxdbClient, nil, slog.Default()))
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/gardens/%s/controller_config%s", garden.ID, tt.query), http.NoBody)
+			w := babytest.TestRequest[*pkg.Garden](t, gr.API, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			body := strings.TrimSpace(w.Body.String())
+			for _, line := range tt.expectedLines {
+				assert.Contains(t, body, line)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+)
+
+const defaultDeclarativeInterval = 30 * time.Second
+
+// DeclarativeConfig makes the server keep storage in sync with a file that has the same format as export. This
+// allows managing resources with a file in version control instead of the API
+type DeclarativeConfig struct {
+	File string `mapstructure:"file"`
+	// Prune deletes resources that are not in the file
+	Prune bool `mapstructure:"prune"`
+	// Interval is how often the file is checked for changes. It defaults to 30s
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// declarativeWatcher applies the file from a DeclarativeConfig each time its contents change
+type declarativeWatcher struct {
+	cfg           DeclarativeConfig
+	storageClient *storage.Client
+	worker        *worker.Worker
+	events        *events.Bus
+	logger        *slog.Logger
+
+	lastHash [sha256.Size]byte
+}
+
+func newDeclarativeWatcher(cfg DeclarativeConfig, storageClient *storage.Client, w *worker.Worker, bus *events.Bus, logger *slog.Logger) *declarativeWatcher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultDeclarativeInterval
+	}
+	return &declarativeWatcher{
+		cfg:           cfg,
+		storageClient: storageClient,
+		worker:        w,
+		events:        bus,
+		logger:        logger.With("declarative_file", cfg.File),
+	}
+}
+
+// reconcile makes storage match the file if it changed since the last time it was applied. Then, it resets the
+// schedules for changed resources and publishes an Event for each change
+func (d *declarativeWatcher) reconcile(ctx context.Context) error {
+	data, err := os.ReadFile(d.cfg.File)
+	if err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	hash := sha256.Sum256(data)
+	if hash == d.lastHash {
+		return nil
+	}
+
+	format := "yaml"
+	if filepath.Ext(d.cfg.File) == ".json" {
+		format = "json"
+	}
+
+	desired, err := storage.ReadExport(bytes.NewReader(data), format)
+	if err != nil {
+		return err
+	}
+
+	plan, err := d.storageClient.PlanApply(ctx, desired, d.cfg.Prune)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range plan.Changes {
+		d.logger.Info("applying declarative change", "change", c.String())
+	}
+
+	err = d.storageClient.Apply(ctx, plan)
+	if err != nil {
+		return err
+	}
+
+	// Pruned resources are read again so their schedules are removed now that they are end-dated. Resources that
+	// were removed instead of end-dated are skipped
+	pruned := &storage.Export{}
+	for _, g := range plan.Pruned.Gardens {
+		g, err = d.storageClient.Gardens.Get(ctx, g.GetID())
+		if err == nil {
+			pruned.Gardens = append(pruned.Gardens, g)
+		}
+	}
+	for _, ws := range plan.Pruned.WaterSchedules {
+		ws, err = d.storageClient.WaterSchedules.Get(ctx, ws.GetID())
+		if err == nil {
+			pruned.WaterSchedules = append(pruned.WaterSchedules, ws)
+		}
+	}
+	for _, rem := range plan.Pruned.Reminders {
+		rem, err = d.storageClient.Reminders.Get(ctx, rem.GetID())
+		if err == nil {
+			pruned.Reminders = append(pruned.Reminders, rem)
+		}
+	}
+
+	err = resetSchedules(d.worker, plan.Saved)
+	if err == nil {
+		err = resetSchedules(d.worker, pruned)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, c := range plan.Changes {
+		d.events.Publish(events.Event{
+			Type: fmt.Sprintf("%s.%s", c.ResourceType, c.Action),
+			ID:   c.ResourceID,
+			Data: c,
+		})
+	}
+
+	d.lastHash = hash
+	if len(plan.Changes) > 0 {
+		d.logger.Info("applied declarative file", "changes", len(plan.Changes))
+	}
+	return nil
+}
+
+// watch checks the file on the configured interval until done is closed. Errors are logged and the file is applied
+// again on the next check
+func (d *declarativeWatcher) watch(done <-chan struct{}) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			err := d.reconcile(context.Background())
+			if err != nil {
+				d.logger.Error("unable to apply declarative file", "error", err)
+			}
+		}
+	}
+}
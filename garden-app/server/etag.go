@@ -0,0 +1,190 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+var errPreconditionFailed = &babyapi.ErrResponse{
+	HTTPStatusCode: http.StatusPreconditionFailed,
+	StatusText:     "Precondition Failed",
+	ErrorText:      "resource does not match the If-Match or If-None-Match header",
+}
+
+// conditionalRequests is a babyapi Extension that adds an ETag header to JSON responses for a single resource.
+// Clients use it in the If-Match header with PUT, PATCH, or DELETE to make sure the resource was not changed since
+// they read it. PUT with "If-None-Match: *" only creates a new resource and will not replace an existing one. GET
+// with If-None-Match responds with 304 Not Modified if the resource did not change. The ETag is a hash of the
+// stored resource, so resources do not need a version field. Writes to the same resource are serialized from the
+// If-Match check until the resource is saved, so two requests with the same ETag can't both succeed
+type conditionalRequests[T babyapi.Resource] struct{}
+
+func (conditionalRequests[T]) Apply(api *babyapi.API[T]) error {
+	locks := &resourceLocks{locks: map[string]*resourceLock{}}
+
+	api.AddIDMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := api.GetIDParam(r)
+
+			// ID middlewares also run for custom routes and nested APIs, which are different resources. HTML
+			// responses are skipped so they are not cached with the same ETag as JSON
+			isResourcePath := strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), api.Base()+"/"+id)
+			if !isResourcePath || render.GetAcceptedContentType(r) == render.ContentTypeHTML {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			current := ""
+			resource, err := api.GetResourceFromContext(r.Context())
+			if err == nil {
+				current = resourceETag(resource)
+			}
+
+			// The resource in the context was read before the lock, so it is read again in case another request
+			// changed it
+			if r.Method == http.MethodPut || r.Method == http.MethodPatch || r.Method == http.MethodDelete {
+				unlock := locks.lock(id)
+				defer unlock()
+
+				current = ""
+				resource, err := api.Storage.Get(r.Context(), id)
+				if err == nil {
+					current = resourceETag(resource)
+				}
+			}
+
+			switch r.Method {
+			case http.MethodGet:
+				w.Header().Set("ETag", current)
+				if etagMatches(r.Header.Get("If-None-Match"), current) {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				ifMatch := r.Header.Get("If-Match")
+				if ifMatch != "" && !etagMatches(ifMatch, current) {
+					_ = render.Render(w, r, errPreconditionFailed)
+					return
+				}
+				if r.Method == http.MethodPut && etagMatches(r.Header.Get("If-None-Match"), current) {
+					_ = render.Render(w, r, errPreconditionFailed)
+					return
+				}
+			}
+
+			if r.Method == http.MethodDelete {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The resource is saved before the response is written, so it is read again to get the new ETag
+			next.ServeHTTP(&etagResponseWriter{ResponseWriter: w, setETag: func() {
+				updated, err := api.Storage.Get(r.Context(), id)
+				if err == nil {
+					w.Header().Set("ETag", resourceETag(updated))
+				}
+			}}, r)
+		})
+	})
+
+	return nil
+}
+
+// resourceETag returns a strong ETag using a hash of the resource's JSON representation
+func resourceETag(resource any) string {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches checks if the ETag is in the comma-separated list from an If-Match or If-None-Match header. The "*"
+// value matches any existing resource, so nothing matches an empty ETag
+func etagMatches(header, etag string) bool {
+	if header == "" || etag == "" {
+		return false
+	}
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceLocks has a lock for each resource ID that is being written. Locks are removed when nothing is waiting
+// for them
+type resourceLocks struct {
+	mu    sync.Mutex
+	locks map[string]*resourceLock
+}
+
+type resourceLock struct {
+	sync.Mutex
+	waiting int
+}
+
+// lock waits for the resource's lock and returns a function to unlock it
+func (l *resourceLocks) lock(id string) func() {
+	l.mu.Lock()
+	rl, ok := l.locks[id]
+	if !ok {
+		rl = &resourceLock{}
+		l.locks[id] = rl
+	}
+	rl.waiting++
+	l.mu.Unlock()
+
+	rl.Lock()
+	return func() {
+		rl.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		rl.waiting--
+		if rl.waiting == 0 {
+			delete(l.locks, id)
+		}
+	}
+}
+
+// etagResponseWriter sets the ETag header right before a successful response is written
+type etagResponseWriter struct {
+	http.ResponseWriter
+	setETag     func()
+	wroteHeader bool
+}
+
+func (w *etagResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if code >= 200 && code < 300 {
+			w.setETag()
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *etagResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Unwrap allows http.ResponseController to use the underlying ResponseWriter
+func (w *etagResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
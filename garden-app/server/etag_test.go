@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+	err := storageClient.Plants.Set(context.Background(), createExamplePlant())
+	require.NoError(t, err)
+
+	api := NewPlantsAPI()
+	api.setup(storageClient)
+
+	request := func(method, plantID, body string, headers map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/zones/"+id.String()+"/plants/"+plantID, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		return babytest.TestWithParentRoute[*pkg.Plant, *pkg.Zone](t, api.API, createExampleZone(), "Zones", "/zones", r)
+	}
+
+	w := request(http.MethodGet, id.String(), "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+
+	t.Run("GetNotModified", func(t *testing.T) {
+		w := request(http.MethodGet, id.String(), "", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("ErrorPutStaleETag", func(t *testing.T) {
+		w := request(http.MethodPut, id.String(), `{"id":"`+id.String()+`","name":"renamed"}`, map[string]string{"If-Match": `"stale"`})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.Equal(t, `{"status":"Precondition Failed","error":"resource does not match the If-Match or If-None-Match header"}`, strings.TrimSpace(w.Body.String()))
+	})
+
+	t.Run("ErrorPutCreateOnlyExists", func(t *testing.T) {
+		w := request(http.MethodPut, id.String(), `{"id":"`+id.String()+`","name":"renamed"}`, map[string]string{"If-None-Match": "*"})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("PutMatchingETag", func(t *testing.T) {
+		w := request(http.MethodPut, id.String(), `{"id":"`+id.String()+`","name":"renamed"}`, map[string]string{"If-Match": etag})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		newETag := w.Header().Get("ETag")
+		assert.NotEqual(t, etag, newETag)
+
+		w = request(http.MethodGet, id.String(), "", nil)
+		assert.Equal(t, newETag, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), `"name":"renamed"`)
+
+		w = request(http.MethodPatch, id.String(), `{"name":"patched"}`, map[string]string{"If-Match": etag})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("PutCreateWithClientID", func(t *testing.T) {
+		newID := xid.New().String()
+		w := request(http.MethodPut, newID, `{"id":"`+newID+`","name":"new plant"}`, map[string]string{"If-None-Match": "*"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+
+		w = request(http.MethodGet, newID, "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("ErrorIfMatchMissingResource", func(t *testing.T) {
+		newID := xid.New().String()
+		w := request(http.MethodPut, newID, `{"id":"`+newID+`","name":"new plant"}`, map[string]string{"If-Match": "*"})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+}
+
+func TestConditionalRequestsConcurrentPatch(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+	err := storageClient.Plants.Set(context.Background(), createExamplePlant())
+	require.NoError(t, err)
+
+	api := NewPlantsAPI()
+	api.setup(storageClient)
+	// Slow writes make it likely that both requests check the ETag before either one saves the Plant
+	api.SetStorage(slowSetStorage[*pkg.Plant]{api.Storage})
+
+	// The router is created once since the test helpers add the nested API for each request
+	parentAPI := babyapi.NewAPI("Zones", "/zones", func() *pkg.Zone { return createExampleZone() })
+	parentAPI.AddNestedAPI(api.API)
+	apiRouter, err := api.Router()
+	require.NoError(t, err)
+	router := chi.NewRouter()
+	api.DefaultMiddleware(router)
+	router.Route("/zones/{"+babyapi.IDParamKey("Zones")+"}", func(r chi.Router) {
+		r.Mount("/", apiRouter)
+	})
+
+	request := func(method, body string, headers map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/zones/"+id.String()+"/plants/"+id.String(), strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r.WithContext(context.WithValue(context.Background(), babyapi.ContextKey("Zones"), createExampleZone())))
+		return w
+	}
+
+	for i := 0; i < 20; i++ {
+		w := request(http.MethodGet, "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		etag := w.Header().Get("ETag")
+
+		codes := make(chan int, 2)
+		var wg sync.WaitGroup
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				w := request(http.MethodPatch, `{"name":"`+name+`"}`, map[string]string{"If-Match": etag})
+				codes <- w.Code
+			}(fmt.Sprintf("patched %d-%d", i, j))
+		}
+		wg.Wait()
+		close(codes)
+
+		var results []int
+		for code := range codes {
+			results = append(results, code)
+		}
+		assert.ElementsMatch(t, []int{http.StatusOK, http.StatusPreconditionFailed}, results)
+	}
+}
+
+// slowSetStorage waits before saving resources
+type slowSetStorage[T babyapi.Resource] struct {
+	babyapi.Storage[T]
+}
+
+func (s slowSetStorage[T]) Set(ctx context.Context, resource T) error {
+	time.Sleep(10 * time.Millisecond)
+	return s.Storage.Set(ctx, resource)
+}
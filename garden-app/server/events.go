@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	eventsWriteTimeout = 10 * time.Second
+	// sseHeartbeatInterval is how often a comment is sent to Server-Sent Events clients so idle connections are not
+	// closed by proxies
+	sseHeartbeatInterval = 30 * time.Second
+)
+
+// newUpgrader creates a WebSocket Upgrader that accepts same-origin requests and requests from any of the
+// allowedOrigins. An allowed origin of "*" accepts all origins. The origins are a function so they can be changed
+// when the config is reloaded
+func newUpgrader(getAllowedOrigins func() []string) websocket.Upgrader {
+	return websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+
+			u, err := url.Parse(origin)
+			if err == nil && strings.EqualFold(u.Host, r.Host) {
+				return true
+			}
+
+			return slices.ContainsFunc(getAllowedOrigins(), func(allowed string) bool {
+				return allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin)
+			})
+		},
+	}
+}
+
+// addResourceEvents publishes Events and records them in the audit log when resources are created, updated, deleted,
+// restored, paused, resumed, or skipped. Deletes and the others are detected with middleware since some APIs already
+// use the AfterDelete hook and the others use custom routes
+func addResourceEvents[T babyapi.Resource](api *babyapi.API[T], bus *events.Bus, audit *auditLog, resourceType string) {
+	api.SetAfterCreateOrUpdate(func(r *http.Request, resource T) *babyapi.ErrResponse {
+		action := "updated"
+		if r.Method == http.MethodPost {
+			action = "created"
+		}
+
+		bus.Publish(events.Event{
+			Type: fmt.Sprintf("%s.%s", resourceType, action),
+			ID:   resource.GetID(),
+			Data: eventData(resource),
+		})
+		audit.record(r, resourceType, resource.GetID(), action, nil)
+		return nil
+	})
+
+	api.AddIDMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := api.GetIDParam(r)
+			resourcePath := fmt.Sprintf("%s/%s", api.Base(), id)
+			path := strings.TrimSuffix(r.URL.Path, "/")
+
+			// ID middleware also runs for nested APIs, so make sure this request is for this API's resource
+			var action string
+			switch {
+			case r.Method == http.MethodDelete && strings.HasSuffix(path, resourcePath):
+				action = "deleted"
+			case r.Method == http.MethodPost && strings.HasSuffix(path, resourcePath+restorePath):
+				action = "restored"
+			case r.Method == http.MethodPost && strings.HasSuffix(path, resourcePath+pausePath):
+				action = "paused"
+			case r.Method == http.MethodPost && strings.HasSuffix(path, resourcePath+resumePath):
+				action = "resumed"
+			case r.Method == http.MethodPost && strings.HasSuffix(path, resourcePath+skipPath):
+				action = "skipped"
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status < 300 {
+				bus.Publish(events.Event{
+					Type: fmt.Sprintf("%s.%s", resourceType, action),
+					ID:   id,
+				})
+				audit.record(r, resourceType, id, action, nil)
+			}
+		})
+	})
+}
+
+// eventData returns the resource to include in an Event. Events are sent to every subscriber, so Weather client
+// options are removed since they can include credentials, like Webhook secrets are removed from responses
+func eventData(resource any) any {
+	if wc, ok := resource.(*weather.Config); ok {
+		withoutOptions := *wc
+		withoutOptions.Options = nil
+		return &withoutOptions
+	}
+	return resource
+}
+
+// statusRecorder saves the response status code so it can be checked after the handler is finished
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+// eventGardenID finds the Garden that the Event is for. Events from the Worker include the Garden ID and others use
+// the resource type from the Event's Type. It is empty for Events that are not for a Garden
+func eventGardenID(ctx context.Context, storageClient *storage.Client, e events.Event) string {
+	switch data := e.Data.(type) {
+	case worker.WaterActionEvent:
+		return data.GardenID
+	case worker.LightActionEvent:
+		return data.GardenID
+	case worker.GardenHealthEvent:
+		return data.GardenID
+	case worker.LeakEvent:
+		return data.GardenID
+	}
+
+	resourceType, _, _ := strings.Cut(e.Type, ".")
+	return resourceGardenID(ctx, storageClient, resourceType, e.ID)
+}
+
+// userCanReadEvent returns true if the request's User has a role for the Event's Garden. Events that are not for a
+// Garden are only sent when the request is not limited to a User
+func (api *API) userCanReadEvent(ctx context.Context, e events.Event) bool {
+	if userFromContext(ctx) == nil {
+		return true
+	}
+	return userCanReadGarden(ctx, api.storageClient, eventGardenID(ctx, api.storageClient, e))
+}
+
+// eventsHandler upgrades the request to a WebSocket connection and streams all Events as JSON until the
+// client disconnects or the server is stopped. Requests made as a User only receive Events for their Gardens
+func (api *API) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	// Subscribe before upgrading so no Events are missed after the client is connected
+	subscriber, unsubscribe := api.events.Subscribe()
+	defer unsubscribe()
+
+	conn, err := api.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already responded with an error
+		logger.Error("unable to upgrade to WebSocket connection", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	logger.Info("streaming events to WebSocket client")
+
+	// Reading is required to handle control messages and detect when the client disconnects
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			logger.Info("WebSocket client disconnected")
+			return
+		case <-api.Done():
+			return
+		case e := <-subscriber:
+			if !api.userCanReadEvent(r.Context(), e) {
+				continue
+			}
+			err = conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+			if err != nil {
+				logger.Error("unable to set WebSocket write deadline", "error", err)
+				return
+			}
+			err = conn.WriteJSON(e)
+			if err != nil {
+				logger.Error("unable to write event to WebSocket", "error", err)
+				return
+			}
+		}
+	}
+}
+
+// sseEventsHandler streams all Events as Server-Sent Events until the client disconnects or the server is stopped.
+// Each Event is sent as an unnamed message with the same JSON as the WebSocket so clients can use EventSource's
+// onmessage. This uses the same origin rules and User filtering as the WebSocket
+func (api *API) sseEventsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	if !api.upgrader.CheckOrigin(r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
+
+	rc := http.NewResponseController(w)
+
+	subscriber, unsubscribe := api.events.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// Flushing the headers right away lets the client know it is connected before any Events are published
+	err := rc.Flush()
+	if err != nil {
+		logger.Error("unable to flush Server-Sent Events response", "error", err)
+		return
+	}
+
+	logger.Info("streaming events to Server-Sent Events client")
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var data []byte
+		select {
+		case <-r.Context().Done():
+			logger.Info("Server-Sent Events client disconnected")
+			return
+		case <-api.Done():
+			return
+		case <-heartbeat.C:
+			data = []byte(": heartbeat\n\n")
+		case e := <-subscriber:
+			if !api.userCanReadEvent(r.Context(), e) {
+				continue
+			}
+			eventJSON, err := json.Marshal(e)
+			if err != nil {
+				logger.Error("unable to marshal event", "error", err, "type", e.Type)
+				continue
+			}
+			data = []byte(fmt.Sprintf("data: %s\n\n", eventJSON))
+		}
+
+		err = rc.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			logger.Error("unable to set Server-Sent Events write deadline", "error", err)
+			return
+		}
+		_, err = w.Write(data)
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			logger.Error("unable to write event to Server-Sent Events client", "error", err)
+			return
+		}
+	}
+}
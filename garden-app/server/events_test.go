@@ -0,0 +1,296 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsWebSocket(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	// NewAPI is not used here because it can only be called once per test due to metrics registration
+	api := &API{
+		API:            babyapi.NewRootAPI("garden-app", "/"),
+		weatherClients: NewWeatherClientsAPI(),
+		events:         events.NewBus(),
+		upgrader:       newUpgrader(func() []string { return nil }),
+	}
+	addResourceEvents(api.weatherClients.API, api.events, nil, "weather_client")
+	api.weatherClients.setup(storageClient)
+	api.API.
+		AddCustomRoute(http.MethodGet, "/events", http.HandlerFunc(api.eventsHandler)).
+		AddNestedAPI(api.weatherClients)
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/events", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	readEvent := func() events.Event {
+		t.Helper()
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+		var e events.Event
+		require.NoError(t, conn.ReadJSON(&e))
+		return e
+	}
+
+	resp, err := http.Post(server.URL+"/weather_clients", "application/json", strings.NewReader(`{"type":"fake","options":{"rain_interval":"24h","api_key":"credential"}}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := readEvent()
+	assert.Equal(t, "weather_client.created", created.Type)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "fake", created.Data.(map[string]interface{})["type"])
+	assert.Nil(t, created.Data.(map[string]interface{})["options"])
+
+	req, err := http.NewRequest(http.MethodPatch, server.URL+"/weather_clients/"+created.ID, strings.NewReader(`{"options":{"rain_mm":1,"api_key":"updated credential"}}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	updated := readEvent()
+	assert.Equal(t, "weather_client.updated", updated.Type)
+	assert.Equal(t, created.ID, updated.ID)
+	assert.Nil(t, updated.Data.(map[string]interface{})["options"])
+
+	// Options can include credentials, so they are not sent to subscribers
+	for _, e := range []events.Event{created, updated} {
+		data, err := json.Marshal(e)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "credential")
+	}
+
+	req, err = http.NewRequest(http.MethodDelete, server.URL+"/weather_clients/"+created.ID, nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	deleted := readEvent()
+	assert.Equal(t, "weather_client.deleted", deleted.Type)
+	assert.Equal(t, created.ID, deleted.ID)
+	assert.Nil(t, deleted.Data)
+
+	// Events published by the Worker are also streamed
+	api.events.Publish(events.Event{Type: "water_action.executed", ID: "zone"})
+	executed := readEvent()
+	assert.Equal(t, "water_action.executed", executed.Type)
+
+	data, err := json.Marshal(executed)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"id":"zone"`)
+}
+
+func TestEventsWebSocketAllowedOrigins(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		origin         string
+		expectedStatus int
+	}{
+		{"SameOrigin", nil, "", http.StatusSwitchingProtocols},
+		{"CrossOriginNotAllowed", nil, "http://dashboard.example.com", http.StatusForbidden},
+		{"CrossOriginAllowed", []string{"http://dashboard.example.com"}, "http://dashboard.example.com", http.StatusSwitchingProtocols},
+		{"CrossOriginWildcard", []string{"*"}, "http://dashboard.example.com", http.StatusSwitchingProtocols},
+		{"CrossOriginOtherAllowed", []string{"http://other.example.com"}, "http://dashboard.example.com", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &API{
+				API:      babyapi.NewRootAPI("garden-app", "/"),
+				events:   events.NewBus(),
+				upgrader: newUpgrader(func() []string { return tt.allowedOrigins }),
+			}
+			api.API.AddCustomRoute(http.MethodGet, "/events", http.HandlerFunc(api.eventsHandler))
+
+			router, err := api.Router()
+			require.NoError(t, err)
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+
+			conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/events", header)
+			if conn != nil {
+				defer conn.Close()
+			}
+			require.NotNil(t, resp)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == http.StatusSwitchingProtocols {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+			}
+		})
+	}
+}
+
+func TestEventsSSE(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		origin         string
+		expectedStatus int
+	}{
+		{"SameOrigin", nil, "", http.StatusOK},
+		{"CrossOriginNotAllowed", nil, "http://dashboard.example.com", http.StatusForbidden},
+		{"CrossOriginAllowed", []string{"http://dashboard.example.com"}, "http://dashboard.example.com", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &API{
+				API:      babyapi.NewRootAPI("garden-app", "/"),
+				events:   events.NewBus(),
+				upgrader: newUpgrader(func() []string { return tt.allowedOrigins }),
+			}
+			api.API.AddCustomRoute(http.MethodGet, "/events/sse", http.HandlerFunc(api.sseEventsHandler))
+
+			router, err := api.Router()
+			require.NoError(t, err)
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events/sse", http.NoBody)
+			require.NoError(t, err)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+			if tt.origin != "" {
+				assert.Equal(t, tt.origin, resp.Header.Get("Access-Control-Allow-Origin"))
+			}
+
+			api.events.Publish(events.Event{Type: "garden.updated", ID: "c5cvhpcbcv45e8bp16dg"})
+
+			reader := bufio.NewReader(resp.Body)
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(line, "data: "), line)
+
+			var e events.Event
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e))
+			assert.Equal(t, "garden.updated", e.Type)
+			assert.Equal(t, "c5cvhpcbcv45e8bp16dg", e.ID)
+
+			blank, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			assert.Equal(t, "\n", blank)
+		})
+	}
+}
+
+func TestEventsSSEForUser(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+	garden := createExampleGarden()
+	garden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleViewer}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+	otherGarden := createExampleGarden()
+	otherGarden.ID = babyapi.NewID()
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), otherGarden))
+
+	api := &API{
+		API:           babyapi.NewRootAPI("garden-app", "/"),
+		events:        events.NewBus(),
+		storageClient: storageClient,
+		upgrader:      newUpgrader(func() []string { return nil }),
+	}
+	api.API.
+		AddMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(withUser(r.Context(), user)))
+			})
+		}).
+		AddCustomRoute(http.MethodGet, "/events/sse", http.HandlerFunc(api.sseEventsHandler))
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events/sse", http.NoBody)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Only the last Event is for the User's Garden
+	api.events.Publish(events.Event{Type: "garden.updated", ID: otherGarden.GetID()})
+	api.events.Publish(events.Event{Type: "water_schedule.updated", ID: "ws"})
+	api.events.Publish(events.Event{
+		Type: "water_action.executed",
+		ID:   "zone",
+		Data: worker.WaterActionEvent{GardenID: otherGarden.GetID(), ZoneID: "zone", Duration: "1s"},
+	})
+	api.events.Publish(events.Event{
+		Type: "water_action.executed",
+		ID:   "zone",
+		Data: worker.WaterActionEvent{GardenID: garden.GetID(), ZoneID: "zone", Duration: "1s"},
+	})
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+
+	var e events.Event
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e))
+	require.Equal(t, "water_action.executed", e.Type)
+	assert.Equal(t, garden.GetID(), e.Data.(map[string]interface{})["garden_id"])
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+	"github.com/rs/xid"
+)
+
+const (
+	exportPath = "/export"
+	importPath = "/import"
+)
+
+// setupImportExport adds routes for exporting all resources as one document and importing it again
+func (api *API) setupImportExport(storageClient *storage.Client, w *worker.Worker) {
+	api.API.
+		AddCustomRoute(http.MethodGet, exportPath, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			exportResources(rw, r, storageClient)
+		})).
+		AddCustomRoute(http.MethodPost, importPath, babyapi.Handler(func(_ http.ResponseWriter, r *http.Request) render.Renderer {
+			return importResources(r, storageClient, w)
+		}))
+}
+
+// exportFormat uses the "format" query parameter or the Accept header to choose between JSON and YAML
+func exportFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+	if strings.Contains(r.Header.Get("Accept"), "yaml") {
+		return "yaml"
+	}
+	return "json"
+}
+
+func exportResources(w http.ResponseWriter, r *http.Request, storageClient *storage.Client) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to export all resources")
+
+	format := exportFormat(r)
+	if format != "json" && format != "yaml" {
+		render.Render(w, r, babyapi.ErrInvalidRequest(fmt.Errorf("invalid format %q", format)))
+		return
+	}
+
+	export, err := storageClient.Export(r.Context())
+	if err != nil {
+		logger.Error("unable to export resources", "error", err)
+		render.Render(w, r, babyapi.InternalServerError(err))
+		return
+	}
+	if userFromContext(r.Context()) != nil {
+		export = filterExportForUser(r.Context(), export)
+	}
+
+	w.Header().Set("Content-Type", "application/"+format)
+	err = storage.WriteExport(w, export, format)
+	if err != nil {
+		logger.Error("unable to write export", "error", err)
+	}
+}
+
+// filterExportForUser only keeps Gardens where the request's User has a role and the resources that belong to them.
+// WaterSchedules are kept if the remaining Zones or ZoneGroups use them. WeatherClients are removed since their
+// options have credentials
+func filterExportForUser(ctx context.Context, e *storage.Export) *storage.Export {
+	result := &storage.Export{}
+	gardenIDs := map[xid.ID]bool{}
+	for _, g := range e.Gardens {
+		if gardenAllows(ctx, g, pkg.APITokenScopeRead) {
+			result.Gardens = append(result.Gardens, g)
+			gardenIDs[g.ID.ID] = true
+		}
+	}
+
+	waterScheduleIDs := map[xid.ID]bool{}
+	for _, z := range e.Zones {
+		if gardenIDs[z.GardenID] {
+			result.Zones = append(result.Zones, z)
+			for _, id := range z.WaterScheduleIDs {
+				waterScheduleIDs[id] = true
+			}
+		}
+	}
+	for _, zg := range e.ZoneGroups {
+		if gardenIDs[zg.GardenID] {
+			result.ZoneGroups = append(result.ZoneGroups, zg)
+			for _, id := range zg.WaterScheduleIDs {
+				waterScheduleIDs[id] = true
+			}
+		}
+	}
+	for _, p := range e.Plants {
+		if gardenIDs[p.GardenID] {
+			result.Plants = append(result.Plants, p)
+		}
+	}
+	for _, rem := range e.Reminders {
+		if gardenIDs[rem.GardenID] {
+			result.Reminders = append(result.Reminders, rem)
+		}
+	}
+	for _, ws := range e.WaterSchedules {
+		if waterScheduleIDs[ws.ID.ID] {
+			result.WaterSchedules = append(result.WaterSchedules, ws)
+		}
+	}
+
+	return result
+}
+
+// checkImportForUser makes sure the request's User can change every stored Garden that the import replaces or adds
+// resources to. The User becomes the owner of new Gardens, like when they are created with the API
+func checkImportForUser(ctx context.Context, storageClient *storage.Client, e *storage.Export) *babyapi.ErrResponse {
+	user := userFromContext(ctx)
+	if user == nil {
+		return nil
+	}
+
+	// Invalid resources are skipped since they are rejected when the import is validated
+	gardenIDs := map[xid.ID]bool{}
+	for _, g := range e.Gardens {
+		if g != nil {
+			gardenIDs[g.ID.ID] = true
+		}
+	}
+	for _, z := range e.Zones {
+		if z != nil {
+			gardenIDs[z.GardenID] = true
+		}
+	}
+	for _, zg := range e.ZoneGroups {
+		if zg != nil {
+			gardenIDs[zg.GardenID] = true
+		}
+	}
+	for _, p := range e.Plants {
+		if p != nil {
+			gardenIDs[p.GardenID] = true
+		}
+	}
+	for _, rem := range e.Reminders {
+		if rem != nil && !rem.GardenID.IsNil() {
+			gardenIDs[rem.GardenID] = true
+		}
+	}
+
+	for id := range gardenIDs {
+		garden, err := storageClient.Gardens.Get(ctx, id.String())
+		if errors.Is(err, babyapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("error getting Garden %q: %w", id, err))
+		}
+		if !gardenAllows(ctx, garden, pkg.APITokenScopeWrite) {
+			return &babyapi.ErrResponse{
+				HTTPStatusCode: http.StatusForbidden,
+				StatusText:     "Forbidden",
+				ErrorText:      fmt.Sprintf("User's role for Garden %q does not allow changes", id),
+			}
+		}
+	}
+
+	for _, g := range e.Gardens {
+		if g == nil {
+			continue
+		}
+		_, err := storageClient.Gardens.Get(ctx, g.GetID())
+		if !errors.Is(err, babyapi.ErrNotFound) {
+			continue
+		}
+		if g.Users == nil {
+			g.Users = map[string]pkg.UserRole{}
+		}
+		g.Users[user.GetID()] = pkg.UserRoleOwner
+	}
+
+	return nil
+}
+
+// ImportResponse summarizes the resources that were imported
+type ImportResponse struct {
+	Gardens        int `json:"gardens"`
+	Zones          int `json:"zones"`
+	ZoneGroups     int `json:"zone_groups"`
+	Plants         int `json:"plants"`
+	Reminders      int `json:"reminders"`
+	WaterSchedules int `json:"water_schedules"`
+	WeatherClients int `json:"weather_clients"`
+}
+
+func (resp *ImportResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// importResources saves all resources from the request body and then reschedules the imported Gardens and
+// WaterSchedules. The body is read as YAML if the Content-Type includes "yaml", otherwise it is JSON
+func importResources(r *http.Request, storageClient *storage.Client, w *worker.Worker) render.Renderer {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to import resources")
+
+	format := "json"
+	if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+		format = "yaml"
+	}
+
+	export, err := storage.ReadExport(r.Body, format)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	if errResp := checkImportForUser(r.Context(), storageClient, export); errResp != nil {
+		logger.Info("User is not allowed to import resources", "error", errResp.ErrorText)
+		return errResp
+	}
+
+	err = storageClient.Import(r.Context(), export)
+	if err != nil {
+		logger.Error("unable to import resources", "error", err)
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	err = resetSchedules(w, export)
+	if err != nil {
+		logger.Error("unable to reset schedules for imported resources", "error", err)
+		return babyapi.InternalServerError(err)
+	}
+
+	logger.Info("imported resources", "gardens", len(export.Gardens), "zones", len(export.Zones))
+	return &ImportResponse{
+		Gardens:        len(export.Gardens),
+		Zones:          len(export.Zones),
+		ZoneGroups:     len(export.ZoneGroups),
+		Plants:         len(export.Plants),
+		Reminders:      len(export.Reminders),
+		WaterSchedules: len(export.WaterSchedules),
+		WeatherClients: len(export.WeatherClients),
+	}
+}
+
+// resetSchedules schedules the light, recirculation, water, and reminder jobs for resources after they are saved.
+// Schedules are reset after everything is saved since WaterSchedules use the time zone of their Zones' Gardens
+func resetSchedules(w *worker.Worker, e *storage.Export) error {
+	for _, g := range e.Gardens {
+		var err error
+		if g.EndDated() || g.LightSchedule == nil {
+			err = w.RemoveJobsByID(g.ID.String())
+		} else {
+			err = w.ResetLightSchedule(g)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to reset LightSchedule for Garden %q: %w", g.ID, err)
+		}
+		err = w.ResetRecirculationSchedule(g)
+		if err != nil {
+			return fmt.Errorf("unable to reset RecirculationSchedule for Garden %q: %w", g.ID, err)
+		}
+	}
+	for _, ws := range e.WaterSchedules {
+		var err error
+		if !ws.Scheduled() {
+			err = w.RemoveJobsByID(ws.ID.String())
+		} else {
+			err = w.ResetWaterSchedule(ws)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to reset WaterSchedule %q: %w", ws.ID, err)
+		}
+	}
+	for _, rem := range e.Reminders {
+		err := w.ResetReminder(rem)
+		if err != nil {
+			return fmt.Errorf("unable to reset Reminder %q: %w", rem.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportExportForUser(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+	require.NoError(t, storageClient.Users.Set(context.Background(), user))
+
+	garden := createExampleGarden()
+	garden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleOwner}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+	require.NoError(t, storageClient.Zones.Set(context.Background(), createExampleZone()))
+	require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), createExampleWaterSchedule()))
+	require.NoError(t, storageClient.WeatherClientConfigs.Set(context.Background(), createExampleWeatherClientConfig()))
+
+	operatorGarden := createExampleGarden()
+	operatorGarden.ID = babyapi.NewID()
+	operatorGarden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleOperator}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), operatorGarden))
+
+	otherGarden := createExampleGarden()
+	otherGarden.ID = babyapi.NewID()
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), otherGarden))
+
+	otherWaterSchedule := createExampleWaterSchedule()
+	otherWaterSchedule.ID = babyapi.NewID()
+	require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), otherWaterSchedule))
+
+	api := &API{API: babyapi.NewRootAPI("garden-app", "/")}
+	api.setupImportExport(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+
+	request := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r = r.WithContext(withUser(r.Context(), user))
+		return babytest.TestRequest[*babyapi.NilResource](t, api.API, r)
+	}
+
+	t.Run("ExportOnlyIncludesUsersGardens", func(t *testing.T) {
+		w := request(http.MethodGet, exportPath, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var export storage.Export
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &export))
+
+		gardenIDs := []string{}
+		for _, g := range export.Gardens {
+			gardenIDs = append(gardenIDs, g.GetID())
+		}
+		assert.ElementsMatch(t, []string{garden.GetID(), operatorGarden.GetID()}, gardenIDs)
+		require.Len(t, export.Zones, 1)
+		require.Len(t, export.WaterSchedules, 1)
+		assert.Equal(t, id.String(), export.WaterSchedules[0].GetID())
+		assert.Empty(t, export.WeatherClients)
+	})
+
+	tests := []struct {
+		name           string
+		export         *storage.Export
+		expectedStatus int
+	}{
+		{
+			"ErrorGardenWithoutRole",
+			&storage.Export{Gardens: []*pkg.Garden{otherGarden}},
+			http.StatusForbidden,
+		},
+		{
+			"ErrorOperatorCannotReplaceGarden",
+			&storage.Export{Gardens: []*pkg.Garden{operatorGarden}},
+			http.StatusForbidden,
+		},
+		{
+			"ErrorZoneInGardenWithoutRole",
+			&storage.Export{Zones: []*pkg.Zone{func() *pkg.Zone {
+				z := createExampleZone()
+				z.ID = babyapi.NewID()
+				z.GardenID = otherGarden.ID.ID
+				return z
+			}()}},
+			http.StatusForbidden,
+		},
+		{
+			"OwnerCanReplaceGarden",
+			&storage.Export{Gardens: []*pkg.Garden{garden}},
+			http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.export)
+			require.NoError(t, err)
+
+			w := request(http.MethodPost, importPath, body)
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+
+	t.Run("UserOwnsNewGardens", func(t *testing.T) {
+		newGarden := createExampleGarden()
+		newGarden.ID = babyapi.NewID()
+		newGarden.Users = nil
+
+		body, err := json.Marshal(&storage.Export{Gardens: []*pkg.Garden{newGarden}})
+		require.NoError(t, err)
+
+		w := request(http.MethodPost, importPath, body)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		stored, err := storageClient.Gardens.Get(context.Background(), newGarden.GetID())
+		require.NoError(t, err)
+		assert.Equal(t, pkg.UserRoleOwner, stored.UserRole(user.GetID()))
+	})
+}
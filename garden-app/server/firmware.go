@@ -0,0 +1,407 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/photos"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const (
+	firmwareBasePath = "/firmware"
+
+	defaultMaxFirmwareSizeBytes = 16 << 20
+	// firmwareFormOverheadBytes allows room for the other multipart form fields when limiting the request size
+	firmwareFormOverheadBytes = 1 << 20
+)
+
+var errFirmwareNotConfigured = &babyapi.ErrResponse{
+	HTTPStatusCode: http.StatusNotImplemented,
+	StatusText:     "Not Implemented",
+	ErrorText:      "firmware storage is not configured",
+}
+
+var errFirmwareBaseURLNotConfigured = &babyapi.ErrResponse{
+	HTTPStatusCode: http.StatusNotImplemented,
+	StatusText:     "Not Implemented",
+	ErrorText:      "firmware base_url must be configured to update controllers",
+}
+
+// FirmwareConfig enables uploading garden-controller firmware for OTA updates. Binaries are saved using the same
+// drivers as photos and MaxSizeBytes defaults to 16MB
+type FirmwareConfig struct {
+	Storage photos.Config `mapstructure:"storage"`
+	// BaseURL is the externally-reachable URL that controllers download firmware from. It is required to update
+	// controllers since the request's Host and X-Forwarded-Proto headers can't be trusted to build the URL
+	BaseURL string `mapstructure:"base_url"`
+	// DownloadToken is added to download URLs as the access_token query parameter so controllers can download
+	// firmware when authentication is enabled. It should only have the read scope
+	DownloadToken string `mapstructure:"download_token"`
+}
+
+// FirmwareAPI provides an API for uploading garden-controller firmware and updating controllers over-the-air.
+// Firmware is uploaded with the "/firmware/upload" route, so it can't be created directly with this API
+type FirmwareAPI struct {
+	*babyapi.API[*pkg.Firmware]
+
+	storageClient *storage.Client
+	worker        *worker.Worker
+	store         photos.Store
+	config        FirmwareConfig
+	maxSizeBytes  int64
+	events        *events.Bus
+	audit         *auditLog
+}
+
+// FirmwareUpdateRequest selects the Gardens to update. All active Gardens with garden-controllers are updated when
+// GardenIDs is empty
+type FirmwareUpdateRequest struct {
+	GardenIDs []string `json:"garden_ids"`
+}
+
+// Bind ...
+func (req *FirmwareUpdateRequest) Bind(_ *http.Request) error {
+	if req == nil {
+		return errors.New("missing required FirmwareUpdateRequest fields")
+	}
+	return nil
+}
+
+func NewFirmwareAPI() *FirmwareAPI {
+	api := &FirmwareAPI{}
+
+	api.API = babyapi.NewAPI("Firmware", firmwareBasePath, func() *pkg.Firmware { return &pkg.Firmware{} })
+	api.Post = nil
+	api.Put = nil
+
+	api.SetResponseWrapper(func(f *pkg.Firmware) render.Renderer {
+		return &FirmwareResponse{Firmware: f}
+	})
+
+	api.SetGetAllResponseWrapper(func(fs []*pkg.Firmware) render.Renderer {
+		slices.SortStableFunc(fs, func(f, g *pkg.Firmware) int {
+			return f.CreatedAt.Compare(*g.CreatedAt)
+		})
+
+		resp := AllFirmwareResponse{ResourceList: babyapi.ResourceList[*FirmwareResponse]{Items: []*FirmwareResponse{}}}
+		for _, f := range fs {
+			resp.Items = append(resp.Items, &FirmwareResponse{Firmware: f})
+		}
+		return resp
+	})
+
+	// The contents are removed when Firmware is deleted, but the details are kept like other end-dated resources
+	api.SetAfterDelete(func(r *http.Request) *babyapi.ErrResponse {
+		if api.store == nil {
+			return nil
+		}
+
+		err := api.store.Delete(r.Context(), api.GetIDParam(r))
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("unable to delete Firmware contents: %w", err))
+		}
+
+		return nil
+	})
+
+	api.AddCustomRoute(http.MethodPost, "/upload", babyapi.Handler(api.upload))
+	api.AddCustomIDRoute(http.MethodGet, "/content", babyapi.Handler(api.content))
+	api.AddCustomIDRoute(http.MethodPost, "/update", api.GetRequestedResourceAndDo(api.update))
+
+	api.ApplyExtension(conditionalRequests[*pkg.Firmware]{})
+
+	return api
+}
+
+func (api *FirmwareAPI) setup(cfg FirmwareConfig, storageClient *storage.Client, worker *worker.Worker) error {
+	api.storageClient = storageClient
+	api.worker = worker
+	api.config = cfg
+	api.SetStorage(api.storageClient.Firmware)
+
+	api.maxSizeBytes = cfg.Storage.MaxSizeBytes
+	if api.maxSizeBytes <= 0 {
+		api.maxSizeBytes = defaultMaxFirmwareSizeBytes
+	}
+
+	if cfg.BaseURL != "" {
+		u, err := url.Parse(cfg.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid firmware base_url %q: must be an http or https URL", cfg.BaseURL)
+		}
+	}
+
+	if !cfg.Storage.Enabled() {
+		return nil
+	}
+
+	var err error
+	api.store, err = photos.NewStore(cfg.Storage)
+	return err
+}
+
+// content responds with the Firmware binary. Controllers download it from here when they are updated
+func (api *FirmwareAPI) content(w http.ResponseWriter, r *http.Request) render.Renderer {
+	if api.store == nil {
+		return errFirmwareNotConfigured
+	}
+
+	firmware, httpErr := api.GetRequestedResource(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	if firmware.EndDated() {
+		return babyapi.ErrNotFoundResponse
+	}
+
+	body, err := api.store.Get(r.Context(), firmware.GetID())
+	if err != nil {
+		if errors.Is(err, photos.ErrNotFound) {
+			return babyapi.ErrNotFoundResponse
+		}
+		return babyapi.InternalServerError(fmt.Errorf("unable to get Firmware contents: %w", err))
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", fmt.Sprint(firmware.Size))
+	_, err = io.Copy(w, body)
+	if err != nil {
+		babyapi.GetLoggerFromContext(r.Context()).Error("unable to write Firmware contents", "error", err)
+	}
+
+	return nil
+}
+
+// upload saves Firmware from the "firmware" field of a multipart form. The "version" field is required and the
+// optional "description" field can explain what changed. The SHA-256 checksum is calculated so controllers can
+// verify the download
+func (api *FirmwareAPI) upload(w http.ResponseWriter, r *http.Request) render.Renderer {
+	if api.store == nil {
+		return errFirmwareNotConfigured
+	}
+
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, api.maxSizeBytes+firmwareFormOverheadBytes)
+	err := r.ParseMultipartForm(api.maxSizeBytes)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid multipart form: %w", err))
+	}
+	defer func() {
+		_ = r.MultipartForm.RemoveAll()
+	}()
+
+	version := r.FormValue("version")
+	if version == "" {
+		return babyapi.ErrInvalidRequest(errors.New("missing required version field"))
+	}
+
+	file, header, err := r.FormFile("firmware")
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("missing required firmware field: %w", err))
+	}
+	defer file.Close()
+
+	if header.Size > api.maxSizeBytes {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("firmware must be at most %d bytes", api.maxSizeBytes))
+	}
+	if header.Size == 0 {
+		return babyapi.ErrInvalidRequest(errors.New("firmware must not be empty"))
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return babyapi.InternalServerError(fmt.Errorf("unable to read firmware: %w", err))
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return babyapi.InternalServerError(fmt.Errorf("unable to read firmware: %w", err))
+	}
+
+	now := time.Now()
+	firmware := &pkg.Firmware{
+		ID:          babyapi.NewID(),
+		Version:     version,
+		Description: r.FormValue("description"),
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		Size:        header.Size,
+		CreatedAt:   &now,
+	}
+	logger = logger.With("firmware_id", firmware.GetID())
+
+	logger.Info("saving Firmware contents", "size", firmware.Size, "version", version)
+	err = api.store.Put(r.Context(), firmware.GetID(), file, firmware.Size, "application/octet-stream")
+	if err != nil {
+		return babyapi.InternalServerError(fmt.Errorf("unable to save Firmware contents: %w", err))
+	}
+
+	err = api.storageClient.Firmware.Set(r.Context(), firmware)
+	if err != nil {
+		// Remove the contents so they are not left behind without any details
+		deleteErr := api.store.Delete(r.Context(), firmware.GetID())
+		if deleteErr != nil {
+			logger.Error("unable to delete Firmware contents after error", "error", deleteErr)
+		}
+		return babyapi.InternalServerError(fmt.Errorf("unable to save Firmware: %w", err))
+	}
+
+	api.events.Publish(events.Event{
+		Type: "firmware.created",
+		ID:   firmware.GetID(),
+		Data: firmware,
+	})
+	api.audit.record(r, "firmware", firmware.GetID(), "created", nil)
+
+	render.Status(r, http.StatusCreated)
+	return &FirmwareResponse{Firmware: firmware}
+}
+
+// update publishes the Firmware's download URL and checksum to the requested Gardens' controllers. The response
+// has the Firmware with the status of each Garden's update
+func (api *FirmwareAPI) update(r *http.Request, firmware *pkg.Firmware) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to update controller Firmware")
+
+	if api.store == nil {
+		return nil, errFirmwareNotConfigured
+	}
+	if api.config.BaseURL == "" {
+		return nil, errFirmwareBaseURLNotConfigured
+	}
+	if firmware.EndDated() {
+		return nil, babyapi.ErrInvalidRequest(errors.New("unable to update controllers with end-dated firmware"))
+	}
+
+	req := &FirmwareUpdateRequest{}
+	if r.ContentLength != 0 {
+		if err := render.Bind(r, req); err != nil {
+			return nil, babyapi.ErrInvalidRequest(err)
+		}
+	}
+
+	gardens, httpErr := api.gardensToUpdate(r, req.GardenIDs)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	if len(gardens) == 0 {
+		return nil, babyapi.ErrInvalidRequest(errors.New("no gardens to update"))
+	}
+
+	result, err := api.worker.StartFirmwareUpdate(r.Context(), gardens, action.FirmwareUpdateMessage{
+		FirmwareID: firmware.GetID(),
+		Version:    firmware.Version,
+		URL:        api.downloadURL(firmware),
+		Checksum:   firmware.Checksum,
+	})
+	if err != nil {
+		logger.Error("unable to start Firmware update", "error", err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	gardenIDs := []string{}
+	for _, g := range gardens {
+		gardenIDs = append(gardenIDs, g.GetID())
+	}
+	api.audit.record(r, "firmware", firmware.GetID(), "update", map[string]string{"garden_ids": strings.Join(gardenIDs, ",")})
+
+	return &FirmwareResponse{Firmware: result}, nil
+}
+
+// gardensToUpdate gets the requested Gardens, or all active Gardens with garden-controllers if none are requested.
+// Requested Gardens must exist, be active, and use garden-controllers. Requests made as a User can only update
+// Gardens that the User can change
+func (api *FirmwareAPI) gardensToUpdate(r *http.Request, gardenIDs []string) ([]*pkg.Garden, *babyapi.ErrResponse) {
+	if len(gardenIDs) == 0 {
+		gardens, err := api.storageClient.Gardens.GetAll(r.Context(), babyapi.EndDatedQueryParam(false))
+		if err != nil {
+			return nil, babyapi.InternalServerError(fmt.Errorf("error getting all Gardens: %w", err))
+		}
+		return babyapi.FilterFunc[*pkg.Garden](func(g *pkg.Garden) bool {
+			return !g.UsesOpenSprinkler() && !g.UsesTasmota() && gardenAllows(r.Context(), g, pkg.APITokenScopeWrite)
+		}).Filter(gardens), nil
+	}
+
+	gardens := []*pkg.Garden{}
+	for _, id := range gardenIDs {
+		g, err := api.storageClient.Gardens.Get(r.Context(), id)
+		if err != nil {
+			return nil, babyapi.ErrInvalidRequest(fmt.Errorf("error getting Garden %q: %w", id, err))
+		}
+		if !gardenAllows(r.Context(), g, pkg.APITokenScopeWrite) {
+			return nil, babyapi.ErrForbidden
+		}
+		if g.EndDated() {
+			return nil, babyapi.ErrInvalidRequest(fmt.Errorf("unable to update end-dated Garden %q", id))
+		}
+		if g.UsesOpenSprinkler() || g.UsesTasmota() {
+			return nil, babyapi.ErrInvalidRequest(fmt.Errorf("firmware updates are not supported by %s controllers", g.ControllerType))
+		}
+		gardens = append(gardens, g)
+	}
+	return gardens, nil
+}
+
+// downloadURL is the URL of the Firmware's content using the configured BaseURL
+func (api *FirmwareAPI) downloadURL(firmware *pkg.Firmware) string {
+	baseURL := strings.TrimSuffix(api.config.BaseURL, "/")
+
+	result := fmt.Sprintf("%s%s/%s/content", baseURL, firmwareBasePath, firmware.GetID())
+	if api.config.DownloadToken != "" {
+		result += "?" + url.Values{"access_token": {api.config.DownloadToken}}.Encode()
+	}
+	return result
+}
+
+// FirmwareResponse is used to represent Firmware in the response body with hypermedia Links
+type FirmwareResponse struct {
+	*pkg.Firmware
+
+	Links []Link `json:"links,omitempty"`
+}
+
+// Render ...
+func (resp *FirmwareResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	if resp == nil {
+		return nil
+	}
+
+	firmwarePath := fmt.Sprintf("%s/%s", firmwareBasePath, resp.ID)
+	resp.Links = append(resp.Links,
+		Link{
+			"self",
+			firmwarePath,
+		},
+		Link{
+			"content",
+			firmwarePath + "/content",
+		},
+	)
+
+	return nil
+}
+
+// AllFirmwareResponse is a list of all Firmware sorted by when it was uploaded
+type AllFirmwareResponse struct {
+	babyapi.ResourceList[*FirmwareResponse]
+}
+
+func (resp AllFirmwareResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return resp.ResourceList.Render(w, r)
+}
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var firmwareBinary = []byte("\xe9\x06\x02\x20firmware")
+
+func firmwareConfig(t *testing.T) FirmwareConfig {
+	t.Helper()
+	return FirmwareConfig{Storage: photosConfig(t)}
+}
+
+func newFirmwareUploadRequest(t *testing.T, data []byte, fields map[string]string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if data != nil {
+		fw, err := mw.CreateFormFile("firmware", "firmware.bin")
+		require.NoError(t, err)
+		_, err = fw.Write(data)
+		require.NoError(t, err)
+	}
+	for k, v := range fields {
+		err := mw.WriteField(k, v)
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/firmware/upload", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestUploadFirmware(t *testing.T) {
+	sum := sha256.Sum256(firmwareBinary)
+
+	tests := []struct {
+		name           string
+		cfg            func(*testing.T) FirmwareConfig
+		data           []byte
+		fields         map[string]string
+		expectedRegexp string
+		code           int
+	}{
+		{
+			"Successful",
+			firmwareConfig,
+			firmwareBinary,
+			map[string]string{"version": "v1.2.0", "description": "adds dosing pump"},
+			`{"id":"[0-9a-v]{20}","version":"v1.2.0","description":"adds dosing pump","checksum":"` + hex.EncodeToString(sum[:]) + `","size":12,"created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","links":\[{"rel":"self","href":"/firmware/[0-9a-v]{20}"},{"rel":"content","href":"/firmware/[0-9a-v]{20}/content"}\]}`,
+			http.StatusCreated,
+		},
+		{
+			"ErrorMissingVersion",
+			firmwareConfig,
+			firmwareBinary,
+			nil,
+			`{"status":"Invalid request.","error":"missing required version field"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorMissingFirmware",
+			firmwareConfig,
+			nil,
+			map[string]string{"version": "v1.2.0"},
+			`{"status":"Invalid request.","error":"missing required firmware field: http: no such file"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorEmptyFirmware",
+			firmwareConfig,
+			[]byte{},
+			map[string]string{"version": "v1.2.0"},
+			`{"status":"Invalid request.","error":"firmware must not be empty"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorNotConfigured",
+			func(*testing.T) FirmwareConfig { return FirmwareConfig{} },
+			firmwareBinary,
+			map[string]string{"version": "v1.2.0"},
+			`{"status":"Not Implemented","error":"firmware storage is not configured"}`,
+			http.StatusNotImplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+
+			api := NewFirmwareAPI()
+			err := api.setup(tt.cfg(t), storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*pkg.Firmware](t, api.API, newFirmwareUploadRequest(t, tt.data, tt.fields))
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestFirmwareUpdate(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+
+	var published action.FirmwareUpdateMessage
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("FirmwareUpdateTopic", "test-garden").Return("test-garden/command/firmware_update", nil)
+	mqttClient.On("Publish", "test-garden/command/firmware_update", mock.Anything).
+		Run(func(args mock.Arguments) {
+			require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &published))
+		}).
+		Return(nil)
+	w := worker.NewWorker(storageClient, nil, mqttClient, slog.Default())
+
+	api := NewFirmwareAPI()
+	cfg := firmwareConfig(t)
+	cfg.BaseURL = "http://garden.local:8080/"
+	cfg.DownloadToken = "download-token"
+	err := api.setup(cfg, storageClient, w)
+	require.NoError(t, err)
+
+	resp := babytest.TestRequest[*pkg.Firmware](t, api.API, newFirmwareUploadRequest(t, firmwareBinary, map[string]string{"version": "v1.2.0"}))
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var firmware pkg.Firmware
+	err = json.Unmarshal(resp.Body.Bytes(), &firmware)
+	require.NoError(t, err)
+
+	t.Run("GetContent", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/firmware/%s/content", firmware.ID), http.NoBody)
+		resp := babytest.TestRequest[*pkg.Firmware](t, api.API, r)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "application/octet-stream", resp.Header().Get("Content-Type"))
+		assert.Equal(t, firmwareBinary, resp.Body.Bytes())
+	})
+
+	t.Run("UpdateAllGardens", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/firmware/%s/update", firmware.ID), http.NoBody)
+		// The download URL does not use the request's headers
+		r.Header.Set("X-Forwarded-Proto", "https")
+		r.Host = "attacker.example.com"
+		resp := babytest.TestRequest[*pkg.Firmware](t, api.API, r)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		assert.Equal(t, action.FirmwareUpdateMessage{
+			FirmwareID: firmware.GetID(),
+			Version:    "v1.2.0",
+			URL:        fmt.Sprintf("http://garden.local:8080/firmware/%s/content?access_token=download-token", firmware.ID),
+			Checksum:   firmware.Checksum,
+		}, published)
+
+		var result pkg.Firmware
+		err = json.Unmarshal(resp.Body.Bytes(), &result)
+		require.NoError(t, err)
+		require.Contains(t, result.Updates, id.String())
+		assert.Equal(t, pkg.FirmwareUpdateStatusPending, result.Updates[id.String()].Status)
+	})
+
+	t.Run("ControllerReportsStatus", func(t *testing.T) {
+		handler := NewMQTTHandler(storageClient, slog.Default())
+		handler.worker = w
+
+		err := handler.handleFirmwareStatus("test-garden/ack/firmware_update", []byte(`{"firmware_id":"`+firmware.GetID()+`","status":"updated"}`))
+		require.NoError(t, err)
+
+		stored, err := storageClient.Firmware.Get(context.Background(), firmware.GetID())
+		require.NoError(t, err)
+		assert.Equal(t, pkg.FirmwareUpdateStatusUpdated, stored.Updates[id.String()].Status)
+	})
+
+	t.Run("ErrorInvalidStatus", func(t *testing.T) {
+		handler := NewMQTTHandler(storageClient, slog.Default())
+		handler.worker = w
+
+		err := handler.handleFirmwareStatus("test-garden/ack/firmware_update", []byte(`{"firmware_id":"`+firmware.GetID()+`","status":"pending"}`))
+		assert.EqualError(t, err, `invalid firmware update status "pending": must be "updated" or "failed"`)
+	})
+
+	t.Run("UserOnlyUpdatesTheirGardens", func(t *testing.T) {
+		user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+		otherGarden := createExampleGarden()
+		otherGarden.ID = babyapi.ID{ID: id2}
+		otherGarden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleOwner}
+		require.NoError(t, storageClient.Gardens.Set(context.Background(), otherGarden))
+
+		t.Run("ErrorRequestedGardenForbidden", func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/firmware/%s/update", firmware.ID), strings.NewReader(`{"garden_ids":["`+id.String()+`"]}`))
+			r.Header.Set("Content-Type", "application/json")
+			r = r.WithContext(withUser(r.Context(), user))
+			resp := babytest.TestRequest[*pkg.Firmware](t, api.API, r)
+			assert.Equal(t, http.StatusForbidden, resp.Code)
+		})
+
+		t.Run("DefaultGardens", func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/firmware/%s/update", firmware.ID), http.NoBody)
+			r = r.WithContext(withUser(r.Context(), user))
+			resp := babytest.TestRequest[*pkg.Firmware](t, api.API, r)
+			require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+			var result pkg.Firmware
+			err = json.Unmarshal(resp.Body.Bytes(), &result)
+			require.NoError(t, err)
+			assert.Equal(t, pkg.FirmwareUpdateStatusPending, result.Updates[id2.String()].Status)
+			// The Garden that the User can't change keeps the status from the previous update
+			assert.Equal(t, pkg.FirmwareUpdateStatusUpdated, result.Updates[id.String()].Status)
+		})
+	})
+
+	t.Run("ErrorBaseURLNotConfigured", func(t *testing.T) {
+		api := NewFirmwareAPI()
+		err := api.setup(firmwareConfig(t), storageClient, w)
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/firmware/%s/update", firmware.ID), http.NoBody)
+		resp := babytest.TestRequest[*pkg.Firmware](t, api.API, r)
+		assert.Equal(t, http.StatusNotImplemented, resp.Code)
+		assert.Equal(t, `{"status":"Not Implemented","error":"firmware base_url must be configured to update controllers"}`, strings.TrimSpace(resp.Body.String()))
+	})
+
+	t.Run("ErrorGardenNotFound", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/firmware/%s/update", firmware.ID), strings.NewReader(`{"garden_ids":["missing"]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := babytest.TestRequest[*pkg.Firmware](t, api.API, r)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, `{"status":"Invalid request.","error":"error getting Garden \"missing\": resource not found"}`, strings.TrimSpace(resp.Body.String()))
+	})
+}
+
+func TestFirmwareSetupInvalidBaseURL(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+
+	for _, baseURL := range []string{"garden.local:8080", "ftp://garden.local", "http://"} {
+		t.Run(baseURL, func(t *testing.T) {
+			cfg := firmwareConfig(t)
+			cfg.BaseURL = baseURL
+			err := NewFirmwareAPI().setup(cfg, storageClient, nil)
+			assert.EqualError(t, err, fmt.Sprintf("invalid firmware base_url %q: must be an http or https URL", baseURL))
+		})
+	}
+}
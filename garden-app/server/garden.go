@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/reports"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
 	"github.com/calvinmclean/automated-garden/garden-app/worker"
 	"github.com/calvinmclean/babyapi"
@@ -26,9 +30,13 @@ type GardensAPI struct {
 	*babyapi.API[*pkg.Garden]
 
 	storageClient  *storage.Client
-	influxdbClient influxdb.Client
+	influxdbClient metrics.Client
 	worker         *worker.Worker
 	config         Config
+	audit          *auditLog
+
+	// waterHistoryFromStorage enables reading water history from storage instead of InfluxDB
+	waterHistoryFromStorage bool
 }
 
 func NewGardenAPI() *GardensAPI {
@@ -50,8 +58,22 @@ func NewGardenAPI() *GardensAPI {
 
 	api.SetOnCreateOrUpdate(api.onCreateOrUpdate)
 
+	// Requests made as a User only list Gardens that give the User a role
+	api.SetGetAllFilter(func(r *http.Request) babyapi.FilterFunc[*pkg.Garden] {
+		return func(g *pkg.Garden) bool {
+			return gardenAllows(r.Context(), g, pkg.APITokenScopeRead)
+		}
+	})
+
 	api.AddCustomIDRoute(http.MethodPost, "/action", api.GetRequestedResourceAndDo(api.gardenAction))
 
+	api.AddCustomIDRoute(http.MethodGet, "/reports", api.GetRequestedResourceAndDo(api.gardenReports))
+	api.AddCustomIDRoute(http.MethodGet, "/water_usage", api.GetRequestedResourceAndDo(api.waterUsage))
+
+	api.AddCustomIDRoute(http.MethodPost, restorePath, api.GetRequestedResourceAndDo(api.restore))
+
+	api.AddCustomIDRoute(http.MethodGet, controllerConfigPath, babyapi.Handler(api.controllerConfig))
+
 	api.AddCustomRoute(http.MethodGet, "/components", babyapi.Handler(func(_ http.ResponseWriter, r *http.Request) render.Renderer {
 		switch r.URL.Query().Get("type") {
 		case "create_modal":
@@ -104,11 +126,16 @@ func NewGardenAPI() *GardensAPI {
 	})
 
 	api.ApplyExtension(extensions.HTMX[*pkg.Garden]{})
+	api.ApplyExtension(conditionalRequests[*pkg.Garden]{})
+	api.ApplyExtension(revisionHistory[*pkg.Garden]{
+		resourceType: "garden",
+		storage:      func() storage.RevisionStorage { return api.storageClient.Revisions },
+	})
 
 	return api
 }
 
-func (api *GardensAPI) setup(config Config, storageClient *storage.Client, influxdbClient influxdb.Client, worker *worker.Worker) error {
+func (api *GardensAPI) setup(config Config, storageClient *storage.Client, influxdbClient metrics.Client, worker *worker.Worker) error {
 	api.storageClient = storageClient
 	api.influxdbClient = influxdbClient
 	api.worker = worker
@@ -131,6 +158,17 @@ func (api *GardensAPI) setup(config Config, storageClient *storage.Client, influ
 		}
 	}
 
+	// Initialize recirculation schedules for hydroponic Gardens
+	for _, g := range allGardens {
+		if g.EndDated() || !g.HasRecirculation() {
+			continue
+		}
+		err = api.worker.ScheduleRecirculation(g)
+		if err != nil {
+			return fmt.Errorf("unable to schedule recirculation for Garden %v: %v", g.ID, err)
+		}
+	}
+
 	return nil
 }
 
@@ -145,6 +183,75 @@ func (api *GardensAPI) onCreateOrUpdate(r *http.Request, garden *pkg.Garden) *ba
 		return babyapi.ErrInvalidRequest(fmt.Errorf("unable to set max_zones less than current num_zones=%d", numZones))
 	}
 
+	// PATCH requests can add a LightSchedule to a Garden without a Location, so this is checked after merging
+	if garden.LightSchedule != nil && garden.LightSchedule.UsesSunTimes() && garden.Location == nil {
+		return babyapi.ErrInvalidRequest(errors.New("missing required location field for light_schedule using sunrise or sunset"))
+	}
+	if err := garden.ValidateRecirculation(); err != nil {
+		return babyapi.ErrInvalidRequest(err)
+	}
+	if err := garden.ValidateController(); err != nil {
+		return babyapi.ErrInvalidRequest(err)
+	}
+	// The ControllerHardware's pins must not be used by the Garden's Zones
+	if garden.ControllerHardware != nil {
+		zones, err := api.getAllZones(r.Context(), garden.ID.String(), false)
+		if err != nil {
+			return babyapi.InternalServerError(err)
+		}
+		if err := garden.ValidatePinConflicts(zones); err != nil {
+			return babyapi.ErrInvalidRequest(err)
+		}
+	}
+	// PATCH requests can set part of a WaterBudget, so it is validated after merging
+	if garden.WaterBudget != nil {
+		if err := garden.WaterBudget.Validate(); err != nil {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("invalid water_budget: %w", err))
+		}
+	}
+
+	existing, err := api.storageClient.Gardens.Get(r.Context(), garden.ID.String())
+	if err != nil && !errors.Is(err, babyapi.ErrNotFound) {
+		return babyapi.InternalServerError(err)
+	}
+
+	// The User that creates a Garden is its owner so they can still access it
+	if user := userFromContext(r.Context()); existing == nil && user != nil {
+		if garden.Users == nil {
+			garden.Users = map[string]pkg.UserRole{}
+		}
+		garden.Users[user.GetID()] = pkg.UserRoleOwner
+	}
+	for userID := range garden.Users {
+		_, err := api.storageClient.Users.Get(r.Context(), userID)
+		if errors.Is(err, babyapi.ErrNotFound) {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("User %q not found", userID))
+		}
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("error getting User %q: %w", userID, err))
+		}
+	}
+
+	// WaterSchedules for this Garden's Zones use its TimeZone, so they are reset if it changes
+	if existing != nil && existing.TimeZone != garden.TimeZone {
+		logger.Info("resetting WaterSchedules for Garden's new time zone", "time_zone", garden.TimeZone)
+		if err := api.worker.ResetWaterSchedulesForGarden(garden); err != nil {
+			logger.Error("unable to reset WaterSchedules for Garden", "error", err)
+			return babyapi.InternalServerError(err)
+		}
+	}
+
+	// The controller's DefaultWaterTime can be changed without reflashing the firmware
+	if existing != nil && defaultWaterTime(existing) != defaultWaterTime(garden) {
+		zones, err := api.getAllZones(r.Context(), garden.ID.String(), false)
+		if err != nil {
+			return babyapi.InternalServerError(err)
+		}
+		if err := api.worker.PublishControllerConfig(garden, zones); err != nil {
+			logger.Error("unable to publish controller config", "error", err)
+		}
+	}
+
 	// If LightSchedule is empty, remove the scheduled Job
 	if garden.LightSchedule == nil {
 		logger.Info("removing LightSchedule")
@@ -163,9 +270,38 @@ func (api *GardensAPI) onCreateOrUpdate(r *http.Request, garden *pkg.Garden) *ba
 		}
 	}
 
+	// Recirculation is reset after the LightSchedule since removing those Jobs also removes the recirculation Jobs
+	if err := api.worker.ResetRecirculationSchedule(garden); err != nil {
+		logger.Error("unable to update/reset RecirculationSchedule", "recirculation_schedule", garden.RecirculationSchedule, "error", err)
+		return babyapi.InternalServerError(err)
+	}
+	// The pump is turned off so it is not left running when a Garden stops using recirculation
+	if existing != nil && existing.HasRecirculation() && !garden.HasRecirculation() {
+		logger.Info("turning off recirculation pump for Garden")
+		if err := api.worker.ExecuteRecirculationAction(garden, &action.RecirculationAction{State: pkg.LightStateOff}); err != nil {
+			logger.Error("unable to turn off recirculation pump", "error", err)
+			return babyapi.InternalServerError(err)
+		}
+	}
+
 	return nil
 }
 
+// defaultWaterTime returns the Garden's configured controller DefaultWaterTime, or 0 if it is not set
+func defaultWaterTime(g *pkg.Garden) time.Duration {
+	if g.ControllerHardware == nil || g.ControllerHardware.DefaultWaterTime == nil {
+		return 0
+	}
+	return g.ControllerHardware.DefaultWaterTime.Duration
+}
+
+// restore clears the Garden's EndDate and schedules its LightSchedule again
+func (api *GardensAPI) restore(r *http.Request, garden *pkg.Garden) (render.Renderer, *babyapi.ErrResponse) {
+	return restoreResource(r, garden, "Garden", api.storageClient.Gardens, api.onCreateOrUpdate, func(g *pkg.Garden) render.Renderer {
+		return api.NewGardenResponse(g)
+	})
+}
+
 // gardenAction reads a GardenAction request and uses it to execute one of the actions
 // that is available to run against a Zone. This one endpoint is used for all the different
 // kinds of actions so the action information is carried in the request body
@@ -189,6 +325,153 @@ func (api *GardensAPI) gardenAction(r *http.Request, garden *pkg.Garden) (render
 		return nil, babyapi.InternalServerError(err)
 	}
 
+	actionName, details := gardenActionAuditDetails(gardenAction)
+	api.audit.record(r, "garden", garden.GetID(), actionName, details)
+
 	render.Status(r, http.StatusAccepted)
 	return &GardenActionResponse{}, nil
 }
+
+// gardenReports generates monthly water usage and cost reports for the Garden
+func (api *GardensAPI) gardenReports(r *http.Request, garden *pkg.Garden) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to get Garden reports")
+
+	if garden.Pricing == nil {
+		return nil, babyapi.ErrInvalidRequest(errors.New("unable to create reports for Garden without pricing"))
+	}
+
+	months, err := monthsQueryParam(r)
+	if err != nil {
+		logger.Error("unable to parse months", "error", err)
+		return nil, babyapi.ErrInvalidRequest(err)
+	}
+
+	zones, err := api.getAllZones(r.Context(), garden.ID.String(), true)
+	if err != nil {
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), influxdb.QueryTimeout)
+	defer cancel()
+	defer api.influxdbClient.Close()
+
+	report, err := reports.Generate(ctx, api.influxdbClient, garden, zones, api.worker.Now(), months)
+	if err != nil {
+		logger.Error("unable to generate reports", "error", err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return &GardenReportsResponse{Report: report}, nil
+}
+
+// waterUsage responds with the liters used by each of the Garden's Zones for each period so they can be compared.
+// Costs are included if the Garden has pricing
+func (api *GardensAPI) waterUsage(r *http.Request, garden *pkg.Garden) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to get Garden water usage")
+
+	period := reports.Period(r.URL.Query().Get("period"))
+	if period == "" {
+		period = reports.PeriodMonth
+	}
+	err := period.Validate()
+	if err != nil {
+		return nil, babyapi.ErrInvalidRequest(err)
+	}
+
+	count, err := countQueryParam(r)
+	if err != nil {
+		logger.Error("unable to parse count", "error", err)
+		return nil, babyapi.ErrInvalidRequest(err)
+	}
+
+	zones, err := api.getAllZones(r.Context(), garden.ID.String(), true)
+	if err != nil {
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	// Periods start at midnight in the Garden's time zone
+	loc, err := garden.TimeLocation()
+	if err != nil {
+		return nil, babyapi.InternalServerError(err)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := api.worker.Now().In(loc)
+	since := reports.UsageStart(period, count, now)
+
+	history := map[string][]pkg.WaterHistory{}
+	for _, z := range zones {
+		if z.Position == nil {
+			continue
+		}
+
+		zoneHistory, err := api.getZoneWaterHistory(r.Context(), garden, z, since, now)
+		if err != nil {
+			logger.Error("unable to get water history", "zone_id", z.GetID(), "error", err)
+			return nil, babyapi.InternalServerError(err)
+		}
+		history[z.GetID()] = zoneHistory
+	}
+
+	usage, err := reports.WaterUsage(garden, zones, history, period, count, now)
+	if err != nil {
+		logger.Error("unable to calculate water usage", "error", err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return &GardenWaterUsageResponse{WaterUsageReport: usage}, nil
+}
+
+// getZoneWaterHistory gets the Zone's water history since the time from storage or InfluxDB
+func (api *GardensAPI) getZoneWaterHistory(ctx context.Context, g *pkg.Garden, z *pkg.Zone, since, now time.Time) ([]pkg.WaterHistory, error) {
+	if api.waterHistoryFromStorage {
+		return api.storageClient.WaterHistory.GetWaterHistory(ctx, z.GetID(), since, 0)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, influxdb.QueryTimeout)
+	defer cancel()
+	defer api.influxdbClient.Close()
+
+	history, err := api.influxdbClient.GetWaterHistory(ctx, *z.Position, g.TopicPrefix, now.Sub(since), 0)
+	if err != nil {
+		return nil, err
+	}
+	return waterHistoryFromMetrics(history), nil
+}
+
+func countQueryParam(r *http.Request) (int, error) {
+	countString := r.URL.Query().Get("count")
+	if len(countString) == 0 {
+		return 3, nil
+	}
+
+	count, err := strconv.Atoi(countString)
+	if err != nil {
+		return 0, err
+	}
+	if count < 1 || count > 366 {
+		return 0, errors.New("count must be between 1 and 366")
+	}
+
+	return count, nil
+}
+
+func monthsQueryParam(r *http.Request) (int, error) {
+	monthsString := r.URL.Query().Get("months")
+	if len(monthsString) == 0 {
+		return 3, nil
+	}
+
+	months, err := strconv.Atoi(monthsString)
+	if err != nil {
+		return 0, err
+	}
+	if months < 1 || months > 24 {
+		return 0, errors.New("months must be between 1 and 24")
+	}
+
+	return months, nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/reports"
 	"github.com/calvinmclean/babyapi"
 
 	"github.com/go-chi/render"
@@ -22,6 +23,8 @@ type GardenResponse struct {
 	Health                  *pkg.GardenHealth        `json:"health,omitempty"`
 	TemperatureHumidityData *TemperatureHumidityData `json:"temperature_humidity_data,omitempty"`
 	NumZones                uint                     `json:"num_zones"`
+	QueuedWaterActions      int                      `json:"queued_water_actions,omitempty"`
+	LightState              *pkg.GardenLightState    `json:"light_state,omitempty"`
 	Links                   []Link                   `json:"links,omitempty"`
 
 	api *GardensAPI
@@ -83,7 +86,9 @@ func (g *GardenResponse) Render(w http.ResponseWriter, r *http.Request) error {
 		},
 	)
 
-	g.Health = g.Garden.Health(ctx, g.api.influxdbClient)
+	g.Health = g.api.worker.GardenHealth(ctx, g.Garden, g.api.influxdbClient)
+	g.QueuedWaterActions = g.api.worker.QueuedWaterActions(g.Garden)
+	g.LightState = g.api.worker.LightState(g.Garden)
 
 	if g.Garden.LightSchedule != nil {
 		nextOnTime := g.api.worker.GetNextLightTime(g.Garden, pkg.LightStateOn)
@@ -112,7 +117,18 @@ func (g *GardenResponse) Render(w http.ResponseWriter, r *http.Request) error {
 				State: pkg.LightStateOff,
 			}
 		}
+	}
 
+	// A pending revert from a LightAction with ForDuration is the next LightAction if it is before the scheduled one
+	revertTime, revertState, ok := g.api.worker.LightRevert(g.Garden)
+	if ok && (g.NextLightAction == nil || revertTime.Before(*g.NextLightAction.Time)) {
+		g.NextLightAction = &NextLightAction{
+			Time:  &revertTime,
+			State: revertState,
+		}
+	}
+
+	if g.NextLightAction != nil {
 		var loc *time.Location
 		tzHeader := r.Header.Get("X-TZ-Offset")
 		if tzHeader != "" {
@@ -122,18 +138,29 @@ func (g *GardenResponse) Render(w http.ResponseWriter, r *http.Request) error {
 			}
 		}
 		if loc == nil {
+			// Error is ignored since TimeZone is validated when the Garden is created or updated
+			loc, _ = g.TimeLocation()
+		}
+		if loc == nil && g.LightSchedule != nil && g.LightSchedule.StartTime != nil {
 			loc = g.LightSchedule.StartTime.Time.Location()
 		}
 
-		offsetTime := g.NextLightAction.Time.In(loc)
-		g.NextLightAction.Time = &offsetTime
+		if loc != nil {
+			offsetTime := g.NextLightAction.Time.In(loc)
+			g.NextLightAction.Time = &offsetTime
+		}
 	}
 
 	if g.Garden.HasTemperatureHumiditySensor() {
-		t, h, err := g.api.influxdbClient.GetTemperatureAndHumidity(ctx, g.Garden.TopicPrefix)
+		logger := babyapi.GetLoggerFromContext(r.Context())
+		t, err := g.api.influxdbClient.GetTemperature(ctx, g.Garden.TopicPrefix)
 		if err != nil {
-			logger := babyapi.GetLoggerFromContext(r.Context())
-			logger.Error("error getting temperature and humidity data", "error", err)
+			logger.Error("error getting temperature data", "error", err)
+			return nil
+		}
+		h, err := g.api.influxdbClient.GetHumidity(ctx, g.Garden.TopicPrefix)
+		if err != nil {
+			logger.Error("error getting humidity data", "error", err)
 			return nil
 		}
 		g.TemperatureHumidityData = &TemperatureHumidityData{
@@ -190,6 +217,24 @@ func (api *GardensAPI) numZones(ctx context.Context, gardenID string) (uint, err
 	return uint(len(zones)), nil
 }
 
+// GardenReportsResponse is used to render the water usage and cost reports for a Garden
+type GardenReportsResponse struct {
+	*reports.Report
+}
+
+func (*GardenReportsResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// GardenWaterUsageResponse is used to render the water usage for each of a Garden's Zones
+type GardenWaterUsageResponse struct {
+	*reports.WaterUsageReport
+}
+
+func (*GardenWaterUsageResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
 type GardenActionResponse struct{}
 
 func (*GardenActionResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
@@ -106,7 +107,7 @@ func TestCreateGarden(t *testing.T) {
 			"SuccessfulWithTemperatureAndHumidity",
 			`{"name": "test-garden", "topic_prefix": "test-garden", "max_zones": 2, "temperature_humidity_sensor": true}`,
 			false,
-			`{"name":"test-garden","topic_prefix":"test-garden","id":"[0-9a-v]{20}","max_zones":2,"created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","temperature_humidity_sensor":true,"health":{"status":"UP","details":"last contact from Garden was \d+(s|ms) ago","last_contact":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)"},"temperature_humidity_data":{"temperature_celsius":50,"humidity_percentage":50},"num_zones":0,"links":\[{"rel":"self","href":"/gardens/[0-9a-v]{20}"},{"rel":"zones","href":"/gardens/[0-9a-v]{20}/zones"},{"rel":"action","href":"/gardens/[0-9a-v]{20}/action"}\]}`,
+			`{"name":"test-garden","topic_prefix":"test-garden","id":"[0-9a-v]{20}","max_zones":2,"created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","temperature_humidity_sensor":true,"health":{"status":"UP","details":"last contact from Garden was \d+(s|ms) ago","last_contact":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)"},"temperature_humidity_data":{"temperature_celsius":22.5,"humidity_percentage":40},"num_zones":0,"links":\[{"rel":"self","href":"/gardens/[0-9a-v]{20}"},{"rel":"zones","href":"/gardens/[0-9a-v]{20}/zones"},{"rel":"action","href":"/gardens/[0-9a-v]{20}/action"}\]}`,
 			http.StatusCreated,
 		},
 		{
@@ -163,9 +164,10 @@ func TestCreateGarden(t *testing.T) {
 			influxdbClient := new(influxdb.MockClient)
 			influxdbClient.On("GetLastContact", mock.Anything, "test-garden").Return(time.Now(), nil)
 			if tt.temperatureHumidityError {
-				influxdbClient.On("GetTemperatureAndHumidity", mock.Anything, "test-garden").Return(0.0, 0.0, errors.New("influxdb error"))
+				influxdbClient.On("GetTemperature", mock.Anything, "test-garden").Return(0.0, errors.New("influxdb error"))
 			} else {
-				influxdbClient.On("GetTemperatureAndHumidity", mock.Anything, "test-garden").Return(50.0, 50.0, nil)
+				influxdbClient.On("GetTemperature", mock.Anything, "test-garden").Return(22.5, nil)
+				influxdbClient.On("GetHumidity", mock.Anything, "test-garden").Return(40.0, nil)
 			}
 
 			gr := NewGardenAPI()
@@ -263,9 +265,10 @@ func TestUpdateGardenPUT(t *testing.T) {
 			influxdbClient := new(influxdb.MockClient)
 			influxdbClient.On("GetLastContact", mock.Anything, "test-garden").Return(time.Now(), nil)
 			if tt.temperatureHumidityError {
-				influxdbClient.On("GetTemperatureAndHumidity", mock.Anything, "test-garden").Return(0.0, 0.0, errors.New("influxdb error"))
+				influxdbClient.On("GetTemperature", mock.Anything, "test-garden").Return(0.0, errors.New("influxdb error"))
 			} else {
-				influxdbClient.On("GetTemperatureAndHumidity", mock.Anything, "test-garden").Return(50.0, 50.0, nil)
+				influxdbClient.On("GetTemperature", mock.Anything, "test-garden").Return(22.5, nil)
+				influxdbClient.On("GetHumidity", mock.Anything, "test-garden").Return(40.0, nil)
 			}
 
 			gr := NewGardenAPI()
@@ -335,6 +338,60 @@ func TestGetAllGardens(t *testing.T) {
 	}
 }
 
+func TestGardenUsers(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	assert.NoError(t, err)
+
+	user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+	assert.NoError(t, storageClient.Users.Set(context.Background(), user))
+
+	sharedGarden := createExampleGarden()
+	sharedGarden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleViewer}
+	assert.NoError(t, storageClient.Gardens.Set(context.Background(), sharedGarden))
+
+	otherGarden := createExampleGarden()
+	otherGarden.ID = babyapi.NewID()
+	assert.NoError(t, storageClient.Gardens.Set(context.Background(), otherGarden))
+
+	influxdbClient := new(influxdb.MockClient)
+	influxdbClient.On("GetLastContact", mock.Anything, mock.Anything).Return(time.Now(), nil)
+
+	gr := NewGardenAPI()
+	err = gr.setup(Config{}, storageClient, influxdbClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+	assert.NoError(t, err)
+
+	t.Run("GetAllOnlyIncludesUsersGardens", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/gardens", http.NoBody)
+		r = r.WithContext(withUser(r.Context(), user))
+		w := babytest.TestRequest[*pkg.Garden](t, gr.API, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), sharedGarden.GetID())
+		assert.NotContains(t, w.Body.String(), otherGarden.GetID())
+	})
+
+	t.Run("CreatorIsOwner", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/gardens", strings.NewReader(`{"name": "plot-2", "topic_prefix": "plot-2", "max_zones": 1}`))
+		r.Header.Set("Content-Type", "application/json")
+		r = r.WithContext(withUser(r.Context(), user))
+		w := babytest.TestRequest[*pkg.Garden](t, gr.API, r)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), fmt.Sprintf(`"users":{%q:"owner"}`, user.GetID()))
+	})
+
+	t.Run("ErrorUserNotFound", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, "/gardens/"+sharedGarden.GetID(), strings.NewReader(`{"users": {"cqsnecmiuvoqlhrmf2jg": "viewer"}}`))
+		r.Header.Set("Content-Type", "application/json")
+		w := babytest.TestRequest[*pkg.Garden](t, gr.API, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"status":"Invalid request.","error":"User \"cqsnecmiuvoqlhrmf2jg\" not found"}`, strings.TrimSpace(w.Body.String()))
+	})
+}
+
 func TestEndDateGarden(t *testing.T) {
 	now := time.Now()
 	endDatedGarden := createExampleGarden()
@@ -450,6 +507,22 @@ func TestUpdateGarden(t *testing.T) {
 			`{"status":"Invalid request.","error":"invalid character 'a' looking for beginning of value"}`,
 			http.StatusBadRequest,
 		},
+		{
+			"SuccessfullyAddSunLightSchedule",
+			createExampleGarden(),
+			nil,
+			`{"location": {"latitude": 33.45, "longitude": -112.07}, "light_schedule": {"start": "sunrise+30m", "duration": "until sunset-1h"}}`,
+			`{"name":"test-garden","topic_prefix":"test-garden","id":"[0-9a-v]{20}","max_zones":2,"created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","light_schedule":{"duration":"until sunset-1h0m0s","start_time":null,"start":"sunrise\+30m0s"},"location":{"latitude":33.45,"longitude":-112.07},"next_light_action":{"time":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d-07:00","state":"(ON|OFF)"},"health":{"status":"UP","details":"last contact from Garden was \d+(s|ms) ago","last_contact":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)"},"num_zones":1,"links":\[{"rel":"self","href":"/gardens/[0-9a-v]{20}"},{"rel":"zones","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones"},{"rel":"action","href":"/gardens/[0-9a-v]{20}/action"}\]}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorSunLightScheduleWithoutLocation",
+			createExampleGarden(),
+			nil,
+			`{"light_schedule": {"start": "sunrise"}}`,
+			`{"status":"Invalid request.","error":"missing required location field for light_schedule using sunrise or sunset"}`,
+			http.StatusBadRequest,
+		},
 		{
 			"ErrorReducingMaxZones",
 			gardenWithZone,
@@ -776,6 +849,54 @@ func TestGardenRequest(t *testing.T) {
 			},
 			"missing required light_schedule.start_time field",
 		},
+		{
+			"SunLightScheduleMissingLocationError",
+			&pkg.Garden{
+				Name:        "garden",
+				TopicPrefix: "garden",
+				MaxZones:    &one,
+				LightSchedule: &pkg.LightSchedule{
+					Start: &pkg.SunTime{Event: pkg.SunEventSunrise},
+					Until: &pkg.SunTime{Event: pkg.SunEventSunset},
+				},
+			},
+			"missing required location field for light_schedule using sunrise or sunset",
+		},
+		{
+			"StartTimeAndStartError",
+			&pkg.Garden{
+				Name:        "garden",
+				TopicPrefix: "garden",
+				MaxZones:    &one,
+				Location:    &pkg.Location{Latitude: 33.45, Longitude: -112.07},
+				LightSchedule: &pkg.LightSchedule{
+					StartTime: startTime,
+					Start:     &pkg.SunTime{Event: pkg.SunEventSunrise},
+					Duration:  &pkg.Duration{Duration: time.Hour},
+				},
+			},
+			"only one of light_schedule.start_time and light_schedule.start can be used",
+		},
+		{
+			"InvalidLocationError",
+			&pkg.Garden{
+				Name:        "garden",
+				TopicPrefix: "garden",
+				MaxZones:    &one,
+				Location:    &pkg.Location{Latitude: 100},
+			},
+			"invalid location: latitude must be between -90 and 90: 100",
+		},
+		{
+			"InvalidTimeZoneError",
+			&pkg.Garden{
+				Name:        "garden",
+				TopicPrefix: "garden",
+				MaxZones:    &one,
+				TimeZone:    "Mars/Olympus_Mons",
+			},
+			"invalid time_zone: unknown time zone Mars/Olympus_Mons",
+		},
 		{
 			"DurationGreaterThanOrEqualTo24HoursError",
 			&pkg.Garden{
@@ -914,3 +1035,158 @@ func TestUpdateGardenRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestGardenReports(t *testing.T) {
+	pricing := &pkg.WaterPricing{
+		Currency:     "USD",
+		CostPerLiter: 0.01,
+		CostPerKWh:   0.2,
+		FlowRate:     10,
+		PumpPower:    500,
+	}
+
+	tests := []struct {
+		name      string
+		pricing   *pkg.WaterPricing
+		query     string
+		setupMock func(*influxdb.MockClient)
+		expected  string
+		status    int
+	}{
+		{
+			"Successful",
+			pricing,
+			"?months=1",
+			func(influxdbClient *influxdb.MockClient) {
+				influxdbClient.On("GetWaterHistory", mock.Anything, uint(0), "test-garden", mock.Anything, uint64(0)).
+					Return([]map[string]interface{}{
+						{"Duration": 1800000, "RecordTime": time.Now()},
+						{"Duration": 1800000, "RecordTime": time.Now()},
+					}, nil)
+				influxdbClient.On("Close").Return()
+			},
+			`{"garden_id":"c5cvhpcbcv45e8bp16dg","currency":"USD","months":\[{"month":"\d{4}-\d{2}","water_events":2,"water_duration":"1h0m0s","liters":600,"kwh":0.5,"water_cost":6,"energy_cost":0.1,"total_cost":6.1,"zones":\[{"zone_id":"c5cvhpcbcv45e8bp16dg","zone_name":"test-zone","water_events":2,"water_duration":"1h0m0s","liters":600,"kwh":0.5,"water_cost":6,"energy_cost":0.1,"total_cost":6.1}\]}\]}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorNoPricing",
+			nil,
+			"",
+			func(*influxdb.MockClient) {},
+			`{"status":"Invalid request.","error":"unable to create reports for Garden without pricing"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorInvalidMonths",
+			pricing,
+			"?months=0",
+			func(*influxdb.MockClient) {},
+			`{"status":"Invalid request.","error":"months must be between 1 and 24"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorInfluxDB",
+			pricing,
+			"",
+			func(influxdbClient *influxdb.MockClient) {
+				influxdbClient.On("GetWaterHistory", mock.Anything, uint(0), "test-garden", mock.Anything, uint64(0)).
+					Return([]map[string]interface{}{}, errors.New("influxdb error"))
+				influxdbClient.On("Close").Return()
+			},
+			`{"status":"Server Error.","error":"error getting water history for Zone \\"c5cvhpcbcv45e8bp16dg\\": influxdb error"}`,
+			http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			influxdbClient := new(influxdb.MockClient)
+			tt.setupMock(influxdbClient)
+
+			storageClient := setupZoneAndGardenStorage(t)
+
+			garden := createExampleGarden()
+			garden.Pricing = tt.pricing
+			err := storageClient.Gardens.Set(context.Background(), garden)
+			assert.NoError(t, err)
+
+			gr := NewGardenAPI()
+			err = gr.setup(Config{}, storageClient, influxdbClient, worker.NewWorker(storageClient, influxdbClient, nil, slog.Default()))
+			assert.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/gardens/%s/reports%s", garden.ID, tt.query), http.NoBody)
+			w := babytest.TestRequest[*pkg.Garden](t, gr.API, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Regexp(t, tt.expected, strings.TrimSpace(w.Body.String()))
+			influxdbClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGardenWaterUsage(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 8, 0, 0, 0, time.UTC)
+	flowRate := 2.0
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+		status   int
+	}{
+		{
+			"SuccessfulWeek",
+			"?period=week&count=1",
+			`{"garden_id":"c5cvhpcbcv45e8bp16dg","period":"week","periods":[{"start":"2023-05-29T00:00:00Z","end":"2023-06-05T00:00:00Z","water_events":2,"water_duration":"1m30s","liters":2.5,"kwh":0,"water_cost":0,"energy_cost":0,"total_cost":0,"zones":[{"zone_id":"c5cvhpcbcv45e8bp16dg","zone_name":"test-zone","water_events":2,"water_duration":"1m30s","liters":2.5,"kwh":0,"water_cost":0,"energy_cost":0,"total_cost":0}]}]}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorInvalidPeriod",
+			"?period=decade",
+			`{"status":"Invalid request.","error":"invalid period \"decade\": must be one of \"day\", \"week\", \"month\", or \"year\""}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorInvalidCount",
+			"?count=0",
+			`{"status":"Invalid request.","error":"count must be between 1 and 366"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+
+			zone := createExampleZone()
+			zone.FlowRate = &flowRate
+			err := storageClient.Zones.Set(context.Background(), zone)
+			assert.NoError(t, err)
+
+			for _, h := range []pkg.WaterHistory{
+				{Duration: &pkg.Duration{Duration: time.Hour}, RecordTime: now.AddDate(0, 0, -7)},
+				{Duration: &pkg.Duration{Duration: time.Minute}, RecordTime: now.Add(-2 * time.Hour)},
+				{Duration: &pkg.Duration{Duration: 30 * time.Second}, RecordTime: now.Add(-1 * time.Hour)},
+			} {
+				err = storageClient.WaterHistory.AddWaterHistory(context.Background(), zone.GetID(), h)
+				assert.NoError(t, err)
+			}
+			err = storageClient.WaterHistory.SetMeasuredLiters(context.Background(), zone.GetID(), 0.5)
+			assert.NoError(t, err)
+
+			w := worker.NewWorker(storageClient, nil, nil, slog.Default())
+			w.SetClock(clock.NewVirtual(now))
+
+			gr := NewGardenAPI()
+			err = gr.setup(Config{}, storageClient, new(influxdb.MockClient), w)
+			assert.NoError(t, err)
+			gr.waterHistoryFromStorage = true
+
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/gardens/c5cvhpcbcv45e8bp16dg/water_usage%s", tt.query), http.NoBody)
+			rr := babytest.TestRequest[*pkg.Garden](t, gr.API, r)
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Equal(t, tt.expected, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
@@ -0,0 +1,529 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/api/gardenpb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcServer implements the GardenService using the same storage and Worker as the HTTP API
+type grpcServer struct {
+	gardenpb.UnimplementedGardenServiceServer
+
+	storageClient *storage.Client
+	worker        *worker.Worker
+	events        *events.Bus
+	audit         *auditLog
+}
+
+// newGRPCServer creates a gRPC server with the GardenService registered. When auth is not nil, every RPC requires a
+// token with the read scope, or the actions scope for executing actions
+func newGRPCServer(storageClient *storage.Client, worker *worker.Worker, bus *events.Bus, auth *authenticator) *grpc.Server {
+	var opts []grpc.ServerOption
+	if auth != nil {
+		opts = append(opts,
+			grpc.UnaryInterceptor(auth.unaryInterceptor),
+			grpc.StreamInterceptor(auth.streamInterceptor),
+		)
+	}
+
+	// Actions use the Worker's clock so audit entries match scheduled actions in simulation mode
+	audit := &auditLog{storage: storageClient.AuditLog, now: time.Now}
+	if worker != nil {
+		audit.now = worker.Now
+	}
+
+	server := grpc.NewServer(opts...)
+	gardenpb.RegisterGardenServiceServer(server, &grpcServer{
+		storageClient: storageClient,
+		worker:        worker,
+		events:        bus,
+		audit:         audit,
+	})
+
+	return server
+}
+
+// recordAction adds an entry to the audit log for an action executed with the gRPC API
+func (s *grpcServer) recordAction(ctx context.Context, resourceType, resourceID, actionName string, details map[string]string) {
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+
+	s.audit.add(ctx, pkg.AuditEntry{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Action:       actionName,
+		Source:       pkg.AuditSourceGRPC,
+		Actor:        actorFromContext(ctx),
+		RemoteAddr:   remoteAddr,
+		Details:      details,
+	})
+}
+
+// serveGRPC listens on the configured port and runs the gRPC server until the API is stopped
+func (api *API) serveGRPC(cfg GRPCConfig, server *grpc.Server, logger *slog.Logger) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Port))
+	if err != nil {
+		return fmt.Errorf("unable to listen for gRPC: %w", err)
+	}
+
+	go func() {
+		logger.Info("starting gRPC server", "port", cfg.Port)
+		err := server.Serve(listener)
+		if err != nil {
+			logger.Error("error running gRPC server", "error", err)
+		}
+	}()
+
+	go func() {
+		<-api.Done()
+		server.Stop()
+	}()
+
+	return nil
+}
+
+func (s *grpcServer) ListGardens(ctx context.Context, req *gardenpb.ListGardensRequest) (*gardenpb.ListGardensResponse, error) {
+	gardens, err := s.storageClient.Gardens.GetAll(ctx, babyapi.EndDatedQueryParam(req.GetIncludeEndDated()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting Gardens: %v", err)
+	}
+
+	resp := &gardenpb.ListGardensResponse{}
+	for _, g := range gardens {
+		if !gardenAllows(ctx, g, pkg.APITokenScopeRead) {
+			continue
+		}
+		resp.Gardens = append(resp.Gardens, gardenToProto(g))
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) GetGarden(ctx context.Context, req *gardenpb.GetGardenRequest) (*gardenpb.Garden, error) {
+	garden, err := s.getGarden(ctx, req.GetId(), pkg.APITokenScopeRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return gardenToProto(garden), nil
+}
+
+func (s *grpcServer) ExecuteGardenAction(ctx context.Context, req *gardenpb.ExecuteGardenActionRequest) (*gardenpb.ExecuteGardenActionResponse, error) {
+	garden, err := s.getGarden(ctx, req.GetGardenId(), pkg.APITokenScopeActions)
+	if err != nil {
+		return nil, err
+	}
+	if garden.EndDated() {
+		return nil, status.Error(codes.FailedPrecondition, "unable to execute action on end-dated garden")
+	}
+
+	gardenAction := &action.GardenAction{}
+	if req.GetLight() != nil {
+		gardenAction.Light = &action.LightAction{State: lightStateFromProto(req.GetLight().GetState())}
+		if req.GetLight().GetForDuration() != "" {
+			gardenAction.Light.ForDuration, err = parseDuration(req.GetLight().GetForDuration())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.GetStop() != nil {
+		gardenAction.Stop = &action.StopAction{All: req.GetStop().GetAll()}
+	}
+
+	err = gardenAction.Bind(nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.worker.ExecuteGardenAction(garden, gardenAction)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to execute GardenAction: %v", err)
+	}
+
+	actionName, details := gardenActionAuditDetails(gardenAction)
+	s.recordAction(ctx, "garden", garden.GetID(), actionName, details)
+
+	return &gardenpb.ExecuteGardenActionResponse{}, nil
+}
+
+func (s *grpcServer) ListZones(ctx context.Context, req *gardenpb.ListZonesRequest) (*gardenpb.ListZonesResponse, error) {
+	_, err := s.getGarden(ctx, req.GetGardenId(), pkg.APITokenScopeRead)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := s.storageClient.Zones.GetAll(ctx, babyapi.EndDatedQueryParam(req.GetIncludeEndDated()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting Zones: %v", err)
+	}
+
+	resp := &gardenpb.ListZonesResponse{}
+	for _, z := range filterZoneByGardenID(req.GetGardenId()).Filter(zones) {
+		resp.Zones = append(resp.Zones, zoneToProto(z))
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) GetZone(ctx context.Context, req *gardenpb.GetZoneRequest) (*gardenpb.Zone, error) {
+	_, zone, err := s.getGardenAndZone(ctx, req.GetGardenId(), req.GetId(), pkg.APITokenScopeRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return zoneToProto(zone), nil
+}
+
+func (s *grpcServer) ExecuteZoneAction(ctx context.Context, req *gardenpb.ExecuteZoneActionRequest) (*gardenpb.ExecuteZoneActionResponse, error) {
+	garden, zone, err := s.getGardenAndZone(ctx, req.GetGardenId(), req.GetZoneId(), pkg.APITokenScopeActions)
+	if err != nil {
+		return nil, err
+	}
+	if zone.EndDated() {
+		return nil, status.Error(codes.FailedPrecondition, "unable to execute action on end-dated zone")
+	}
+
+	zoneAction := &action.ZoneAction{}
+	if req.GetWater() != nil {
+		zoneAction.Water = &action.WaterAction{
+			IgnoreMoisture: req.GetWater().GetIgnoreMoisture(),
+			IgnoreWeather:  req.GetWater().GetIgnoreWeather(),
+		}
+		if req.GetWater().GetDuration() != "" {
+			zoneAction.Water.Duration, err = parseDuration(req.GetWater().GetDuration())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = zoneAction.Bind(nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.worker.ExecuteZoneAction(ctx, garden, zone, zoneAction)
+	if err != nil {
+		if errors.Is(err, worker.ErrMissingWaterDuration) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "unable to execute ZoneAction: %v", err)
+	}
+
+	s.recordAction(ctx, "zone", zone.GetID(), "water_action", waterActionAuditDetails(zoneAction.Water))
+
+	return &gardenpb.ExecuteZoneActionResponse{}, nil
+}
+
+func (s *grpcServer) ListWaterSchedules(ctx context.Context, req *gardenpb.ListWaterSchedulesRequest) (*gardenpb.ListWaterSchedulesResponse, error) {
+	waterSchedules, err := s.storageClient.WaterSchedules.GetAll(ctx, babyapi.EndDatedQueryParam(req.GetIncludeEndDated()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting WaterSchedules: %v", err)
+	}
+
+	resp := &gardenpb.ListWaterSchedulesResponse{}
+	for _, ws := range waterSchedules {
+		resp.WaterSchedules = append(resp.WaterSchedules, waterScheduleToProto(ws))
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) GetWaterSchedule(ctx context.Context, req *gardenpb.GetWaterScheduleRequest) (*gardenpb.WaterSchedule, error) {
+	ws, err := s.storageClient.WaterSchedules.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, storageErrorToStatus("WaterSchedule", err)
+	}
+
+	return waterScheduleToProto(ws), nil
+}
+
+// WatchWaterEvents sends water_action.executed Events from the event bus until the client cancels. Requests made as
+// a User only receive Events for Gardens where the User has a role
+func (s *grpcServer) WatchWaterEvents(req *gardenpb.WatchWaterEventsRequest, stream gardenpb.GardenService_WatchWaterEventsServer) error {
+	if req.GetGardenId() != "" {
+		_, err := s.getGarden(stream.Context(), req.GetGardenId(), pkg.APITokenScopeRead)
+		if err != nil {
+			return err
+		}
+	}
+
+	subscriber, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	// Headers are sent right away so clients know when they are subscribed
+	err := stream.SendHeader(metadata.MD{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-subscriber:
+			data, ok := e.Data.(worker.WaterActionEvent)
+			if !ok {
+				continue
+			}
+			if req.GetGardenId() != "" && req.GetGardenId() != data.GardenID {
+				continue
+			}
+			if req.GetZoneId() != "" && req.GetZoneId() != data.ZoneID {
+				continue
+			}
+			if !userCanReadGarden(stream.Context(), s.storageClient, data.GardenID) {
+				continue
+			}
+
+			err := stream.Send(&gardenpb.WaterEvent{
+				GardenId:  data.GardenID,
+				ZoneId:    data.ZoneID,
+				Duration:  data.Duration,
+				Timestamp: timestamppb.New(e.Timestamp),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// getGarden gets the Garden and makes sure the request's User has a role for it that allows the scope
+func (s *grpcServer) getGarden(ctx context.Context, id string, scope pkg.APITokenScope) (*pkg.Garden, error) {
+	garden, err := s.storageClient.Gardens.Get(ctx, id)
+	if err != nil {
+		return nil, storageErrorToStatus("Garden", err)
+	}
+	if !gardenAllows(ctx, garden, scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "User's role for Garden %q does not allow scope %q", id, scope)
+	}
+	return garden, nil
+}
+
+// getGardenAndZone gets the Garden and Zone and makes sure the Zone belongs to the Garden
+func (s *grpcServer) getGardenAndZone(ctx context.Context, gardenID, zoneID string, scope pkg.APITokenScope) (*pkg.Garden, *pkg.Zone, error) {
+	garden, err := s.getGarden(ctx, gardenID, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zone, err := s.storageClient.Zones.Get(ctx, zoneID)
+	if err != nil {
+		return nil, nil, storageErrorToStatus("Zone", err)
+	}
+	if zone.GardenID.String() != garden.GetID() {
+		return nil, nil, status.Error(codes.NotFound, "Zone not found")
+	}
+
+	return garden, zone, nil
+}
+
+func storageErrorToStatus(resourceName string, err error) error {
+	if errors.Is(err, babyapi.ErrNotFound) {
+		return status.Errorf(codes.NotFound, "%s not found", resourceName)
+	}
+	return status.Errorf(codes.Internal, "error getting %s: %v", resourceName, err)
+}
+
+func parseDuration(input string) (*pkg.Duration, error) {
+	d, err := time.ParseDuration(input)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid duration: %v", err)
+	}
+	return &pkg.Duration{Duration: d}, nil
+}
+
+// durationString formats the Duration the same way as the REST API
+func durationString(d *pkg.Duration) string {
+	switch {
+	case d == nil:
+		return ""
+	case d.Cron != "":
+		return "cron:" + d.Cron
+	default:
+		return d.Duration.String()
+	}
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func uintOrZero(v *uint) uint32 {
+	if v == nil {
+		return 0
+	}
+	return uint32(*v)
+}
+
+func lightStateFromProto(state gardenpb.LightState) pkg.LightState {
+	switch state {
+	case gardenpb.LightState_LIGHT_STATE_ON:
+		return pkg.LightStateOn
+	case gardenpb.LightState_LIGHT_STATE_OFF:
+		return pkg.LightStateOff
+	default:
+		return pkg.LightStateToggle
+	}
+}
+
+func gardenToProto(g *pkg.Garden) *gardenpb.Garden {
+	result := &gardenpb.Garden{
+		Id:          g.GetID(),
+		Name:        g.Name,
+		TopicPrefix: g.TopicPrefix,
+		MaxZones:    uintOrZero(g.MaxZones),
+		CreatedAt:   timestampOrNil(g.CreatedAt),
+		EndDate:     timestampOrNil(g.EndDate),
+		TimeZone:    g.TimeZone,
+	}
+	if g.LightSchedule != nil {
+		result.LightSchedule = &gardenpb.LightSchedule{
+			Duration: durationString(g.LightSchedule.Duration),
+		}
+		if g.LightSchedule.StartTime != nil {
+			result.LightSchedule.StartTime = g.LightSchedule.StartTime.String()
+		}
+	}
+	return result
+}
+
+func zoneToProto(z *pkg.Zone) *gardenpb.Zone {
+	result := &gardenpb.Zone{
+		Id:        z.GetID(),
+		GardenId:  z.GardenID.String(),
+		Name:      z.Name,
+		Position:  uintOrZero(z.Position),
+		CreatedAt: timestampOrNil(z.CreatedAt),
+		EndDate:   timestampOrNil(z.EndDate),
+		SkipCount: uintOrZero(z.SkipCount),
+	}
+	for _, id := range z.WaterScheduleIDs {
+		result.WaterScheduleIds = append(result.WaterScheduleIds, id.String())
+	}
+	if z.Details != nil {
+		result.Details = &gardenpb.ZoneDetails{
+			Description: z.Details.Description,
+			Notes:       z.Details.Notes,
+		}
+	}
+	return result
+}
+
+func waterScheduleToProto(ws *pkg.WaterSchedule) *gardenpb.WaterSchedule {
+	result := &gardenpb.WaterSchedule{
+		Id:          ws.GetID(),
+		Name:        ws.Name,
+		Description: ws.Description,
+		Duration:    durationString(ws.Duration),
+		Interval:    durationString(ws.Interval),
+		StartDate:   timestampOrNil(ws.StartDate),
+		EndDate:     timestampOrNil(ws.EndDate),
+	}
+	if ws.StartTime != nil {
+		result.StartTime = ws.StartTime.String()
+	}
+	if ws.ActivePeriod != nil {
+		result.ActivePeriod = &gardenpb.ActivePeriod{
+			StartMonth: ws.ActivePeriod.StartMonth,
+			EndMonth:   ws.ActivePeriod.EndMonth,
+		}
+	}
+	return result
+}
+
+// unaryInterceptor authenticates unary RPCs with the same tokens as the HTTP API
+func (a *authenticator) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authorizeGRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor authenticates streaming RPCs with the same tokens as the HTTP API
+func (a *authenticator) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authorizeGRPC(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ss, ctx})
+}
+
+// authorizedStream uses the context from authorizeGRPC so stream handlers can get the actor and User
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorizeGRPC reads a Bearer token from the "authorization" metadata and checks that it has the scope required
+// for the method. Executing actions requires the actions scope and everything else requires the read scope. The
+// returned context includes the token name for the audit log and the User that the request is limited to
+func (a *authenticator) authorizeGRPC(ctx context.Context, fullMethod string) (context.Context, error) {
+	var token string
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if t, found := strings.CutPrefix(value, "Bearer "); found {
+			token = t
+			break
+		}
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing API token")
+	}
+
+	name, scopes, user, err := a.lookup(ctx, token)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error looking up API token: %v", err)
+	}
+	if scopes == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid API token")
+	}
+
+	scope := pkg.APITokenScopeRead
+	if fullMethod == gardenpb.GardenService_ExecuteGardenAction_FullMethodName ||
+		fullMethod == gardenpb.GardenService_ExecuteZoneAction_FullMethodName {
+		scope = pkg.APITokenScopeActions
+	}
+	if !pkg.HasAPITokenScope(scopes, scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "API token %q is missing required scope %q", name, scope)
+	}
+
+	ctx = withActor(ctx, name)
+	// Every role can read resources that don't belong to a Garden, so the User's role for each Garden is checked
+	// when it is used
+	if user != nil && !pkg.HasAPITokenScope(scopes, pkg.APITokenScopeAdmin) {
+		ctx = withUser(ctx, user)
+	}
+	return ctx, nil
+}
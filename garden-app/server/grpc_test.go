@@ -0,0 +1,310 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/api/gardenpb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func setupGRPCClient(t *testing.T, server *grpc.Server) gardenpb.GardenServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return gardenpb.NewGardenServiceClient(conn)
+}
+
+func TestGRPCGetResources(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+	require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), createExampleWaterSchedule()))
+
+	client := setupGRPCClient(t, newGRPCServer(storageClient, nil, events.NewBus(), nil))
+	garden := createExampleGarden()
+	zone := createExampleZone()
+	ws := createExampleWaterSchedule()
+
+	t.Run("ListGardens", func(t *testing.T) {
+		resp, err := client.ListGardens(context.Background(), &gardenpb.ListGardensRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.GetGardens(), 1)
+		assert.Equal(t, garden.GetID(), resp.GetGardens()[0].GetId())
+		assert.Equal(t, "15h0m0s", resp.GetGardens()[0].GetLightSchedule().GetDuration())
+		assert.Equal(t, "22:00:01-07:00", resp.GetGardens()[0].GetLightSchedule().GetStartTime())
+	})
+
+	t.Run("GetGardenNotFound", func(t *testing.T) {
+		_, err := client.GetGarden(context.Background(), &gardenpb.GetGardenRequest{Id: "chkodpg3lcj13q82mq40"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("ListZones", func(t *testing.T) {
+		resp, err := client.ListZones(context.Background(), &gardenpb.ListZonesRequest{GardenId: garden.GetID()})
+		require.NoError(t, err)
+		require.Len(t, resp.GetZones(), 1)
+		assert.Equal(t, zone.GetID(), resp.GetZones()[0].GetId())
+		assert.Equal(t, []string{ws.GetID()}, resp.GetZones()[0].GetWaterScheduleIds())
+	})
+
+	t.Run("GetZone", func(t *testing.T) {
+		resp, err := client.GetZone(context.Background(), &gardenpb.GetZoneRequest{GardenId: garden.GetID(), Id: zone.GetID()})
+		require.NoError(t, err)
+		assert.Equal(t, "test-zone", resp.GetName())
+		assert.Equal(t, garden.GetID(), resp.GetGardenId())
+	})
+
+	t.Run("GetWaterSchedule", func(t *testing.T) {
+		resp, err := client.GetWaterSchedule(context.Background(), &gardenpb.GetWaterScheduleRequest{Id: ws.GetID()})
+		require.NoError(t, err)
+		assert.Equal(t, ws.Duration.Duration.String(), resp.GetDuration())
+		assert.Equal(t, ws.Interval.Duration.String(), resp.GetInterval())
+	})
+}
+
+func TestGRPCZoneActionAndWatchWaterEvents(t *testing.T) {
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "test-garden").Return("garden/action/water", nil)
+	mqttClient.On("Publish", "garden/action/water", mock.Anything).Return(nil)
+	mqttClient.On("Disconnect", uint(100)).Return()
+
+	storageClient := setupZoneAndGardenStorage(t)
+	bus := events.NewBus()
+	wkr := worker.NewWorker(storageClient, nil, mqttClient, slog.Default())
+	wkr.SetEventBus(bus)
+	wkr.StartAsync()
+
+	client := setupGRPCClient(t, newGRPCServer(storageClient, wkr, bus, nil))
+	garden := createExampleGarden()
+	zone := createExampleZone()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.WatchWaterEvents(ctx, &gardenpb.WatchWaterEventsRequest{ZoneId: zone.GetID()})
+	require.NoError(t, err)
+	// Wait for the stream to be established so it is subscribed before the action is executed
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	_, err = client.ExecuteZoneAction(context.Background(), &gardenpb.ExecuteZoneActionRequest{
+		GardenId: garden.GetID(),
+		ZoneId:   zone.GetID(),
+		Water:    &gardenpb.WaterAction{Duration: "1s"},
+	})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, garden.GetID(), event.GetGardenId())
+	assert.Equal(t, zone.GetID(), event.GetZoneId())
+	assert.Equal(t, "1s", event.GetDuration())
+
+	t.Run("ErrorMissingAction", func(t *testing.T) {
+		_, err := client.ExecuteZoneAction(context.Background(), &gardenpb.ExecuteZoneActionRequest{
+			GardenId: garden.GetID(),
+			ZoneId:   zone.GetID(),
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	wkr.Stop()
+	mqttClient.AssertExpectations(t)
+}
+
+func TestGRPCAuth(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+
+	auth, err := newAuthenticator(AuthConfig{Tokens: []TokenConfig{
+		{Name: "reader", Token: "read-token", Scopes: []pkg.APITokenScope{pkg.APITokenScopeRead}},
+	}}, nil)
+	require.NoError(t, err)
+
+	client := setupGRPCClient(t, newGRPCServer(storageClient, nil, events.NewBus(), auth))
+	garden := createExampleGarden()
+
+	tests := []struct {
+		name     string
+		token    string
+		call     func(context.Context) error
+		expected codes.Code
+	}{
+		{
+			"MissingToken",
+			"",
+			func(ctx context.Context) error {
+				_, err := client.ListGardens(ctx, &gardenpb.ListGardensRequest{})
+				return err
+			},
+			codes.Unauthenticated,
+		},
+		{
+			"InvalidToken",
+			"wrong",
+			func(ctx context.Context) error {
+				_, err := client.ListGardens(ctx, &gardenpb.ListGardensRequest{})
+				return err
+			},
+			codes.Unauthenticated,
+		},
+		{
+			"ReadAllowed",
+			"read-token",
+			func(ctx context.Context) error {
+				_, err := client.ListGardens(ctx, &gardenpb.ListGardensRequest{})
+				return err
+			},
+			codes.OK,
+		},
+		{
+			"ActionsForbidden",
+			"read-token",
+			func(ctx context.Context) error {
+				_, err := client.ExecuteGardenAction(ctx, &gardenpb.ExecuteGardenActionRequest{
+					GardenId: garden.GetID(),
+					Stop:     &gardenpb.StopAction{},
+				})
+				return err
+			},
+			codes.PermissionDenied,
+		},
+		{
+			"StreamMissingToken",
+			"",
+			func(ctx context.Context) error {
+				stream, err := client.WatchWaterEvents(ctx, &gardenpb.WatchWaterEventsRequest{})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+tt.token)
+			}
+
+			err := tt.call(ctx)
+			assert.Equal(t, tt.expected, status.Code(err))
+		})
+	}
+}
+
+func TestGRPCAuthUsers(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+	garden := createExampleGarden()
+
+	user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+	require.NoError(t, storageClient.Users.Set(context.Background(), user))
+	token := &pkg.APIToken{
+		ID:     babyapi.NewID(),
+		Name:   "sam",
+		Scopes: []pkg.APITokenScope{pkg.APITokenScopeRead, pkg.APITokenScopeActions},
+		UserID: user.GetID(),
+	}
+	require.NoError(t, token.GenerateToken())
+	require.NoError(t, storageClient.APITokens.Set(context.Background(), token))
+
+	auth, err := newAuthenticator(AuthConfig{}, storageClient.APITokens)
+	require.NoError(t, err)
+	auth.users = storageClient.Users
+	auth.gardens = storageClient.Gardens
+
+	bus := events.NewBus()
+	client := setupGRPCClient(t, newGRPCServer(storageClient, nil, bus, auth))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token.Token())
+
+	t.Run("NoGardenRole", func(t *testing.T) {
+		resp, err := client.ListGardens(ctx, &gardenpb.ListGardensRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, resp.GetGardens())
+
+		_, err = client.GetGarden(ctx, &gardenpb.GetGardenRequest{Id: garden.GetID()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		watchCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+
+		stream, err := client.WatchWaterEvents(watchCtx, &gardenpb.WatchWaterEventsRequest{GardenId: garden.GetID()})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	garden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleViewer}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+
+	t.Run("ViewerRole", func(t *testing.T) {
+		resp, err := client.ListGardens(ctx, &gardenpb.ListGardensRequest{})
+		require.NoError(t, err)
+		assert.Len(t, resp.GetGardens(), 1)
+
+		_, err = client.GetGarden(ctx, &gardenpb.GetGardenRequest{Id: garden.GetID()})
+		assert.NoError(t, err)
+
+		_, err = client.ExecuteGardenAction(ctx, &gardenpb.ExecuteGardenActionRequest{
+			GardenId: garden.GetID(),
+			Stop:     &gardenpb.StopAction{},
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("WatchWaterEventsOnlyIncludesUsersGardens", func(t *testing.T) {
+		other := &pkg.Garden{ID: babyapi.NewID(), Name: "other", TopicPrefix: "other"}
+		require.NoError(t, storageClient.Gardens.Set(context.Background(), other))
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := client.WatchWaterEvents(watchCtx, &gardenpb.WatchWaterEventsRequest{})
+		require.NoError(t, err)
+		_, err = stream.Header()
+		require.NoError(t, err)
+
+		for _, gardenID := range []string{other.GetID(), garden.GetID()} {
+			bus.Publish(events.Event{
+				Type: "water_action.executed",
+				Data: worker.WaterActionEvent{GardenID: gardenID, ZoneID: "zone", Duration: "1s"},
+			})
+		}
+
+		event, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, garden.GetID(), event.GetGardenId())
+	})
+}
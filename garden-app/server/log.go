@@ -1,21 +1,104 @@
 package server
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 )
 
+// Subsystems that can use a different log level than the rest of the server
+const (
+	LogSubsystemServer  = "server"
+	LogSubsystemWorker  = "worker"
+	LogSubsystemMQTT    = "mqtt"
+	LogSubsystemStorage = "storage"
+)
+
+var logSubsystems = []string{LogSubsystemServer, LogSubsystemWorker, LogSubsystemMQTT, LogSubsystemStorage}
+
+// logLevel and subsystemLogLevels are shared by all loggers so the levels can be changed when the config is reloaded
+var (
+	logLevel           = new(slog.LevelVar)
+	subsystemLogLevels = map[string]*slog.LevelVar{
+		LogSubsystemServer:  new(slog.LevelVar),
+		LogSubsystemWorker:  new(slog.LevelVar),
+		LogSubsystemMQTT:    new(slog.LevelVar),
+		LogSubsystemStorage: new(slog.LevelVar),
+	}
+)
+
 // LogConfig holds settings for logger
 type LogConfig struct {
-	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
+	Level  string            `mapstructure:"level"`
+	Format string            `mapstructure:"format"`
+	Levels map[string]string `mapstructure:"levels"`
+	File   LogFileConfig     `mapstructure:"file"`
+}
+
+// LogFileConfig is used to write logs to a file instead of stdout. The file is rotated once it reaches MaxSizeMB
+// and the newest MaxBackups rotated files are kept
+type LogFileConfig struct {
+	Path       string `mapstructure:"path"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`
+	MaxBackups int    `mapstructure:"max_backups"`
+}
+
+// Validate checks the format and levels and makes sure the log file can be opened
+func (c LogConfig) Validate() error {
+	switch c.Format {
+	case "", "json", "text":
+	default:
+		return fmt.Errorf("invalid log.format %q: must be one of [json text]", c.Format)
+	}
+
+	err := c.validateLevels()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.writer()
+	return err
+}
+
+// validateLevels makes sure the global and subsystem levels are valid
+func (c LogConfig) validateLevels() error {
+	if !validLogLevel(c.Level) {
+		return fmt.Errorf("invalid log.level %q", c.Level)
+	}
+	for subsystem, level := range c.Levels {
+		if !slices.Contains(logSubsystems, subsystem) {
+			return fmt.Errorf("invalid log.levels subsystem %q: must be one of %v", subsystem, logSubsystems)
+		}
+		if !validLogLevel(level) {
+			return fmt.Errorf("invalid log.levels.%s %q", subsystem, level)
+		}
+	}
+	return nil
+}
+
+func validLogLevel(level string) bool {
+	switch strings.ToLower(level) {
+	case "", "debug", "info", "warn", "error":
+		return true
+	default:
+		return false
+	}
+}
+
+// setLevels updates the shared levels. Subsystems without their own level use the global level
+func (c LogConfig) setLevels() {
+	logLevel.Set(c.GetLogLevel())
+	for subsystem, level := range subsystemLogLevels {
+		level.Set(c.GetSubsystemLogLevel(subsystem))
+	}
 }
 
 // GetHandler returns a slog handler based on the input. Valid values are "json", otherwise default text is used
-func (c LogConfig) getHandler(writer io.Writer) slog.Handler {
-	opts := &slog.HandlerOptions{Level: c.GetLogLevel()}
+func (c LogConfig) getHandler(writer io.Writer, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
 	switch c.Format {
 	case "json":
 		return slog.NewJSONHandler(writer, opts)
@@ -24,17 +107,63 @@ func (c LogConfig) getHandler(writer io.Writer) slog.Handler {
 	}
 }
 
+// writer returns the log file if it is configured, otherwise stdout
+func (c LogConfig) writer() (io.Writer, error) {
+	if c.File.Path == "" {
+		return os.Stdout, nil
+	}
+	return openLogFile(c.File)
+}
+
 func (c LogConfig) NewLogger() *slog.Logger {
-	return c.NewLoggerWithWriter(os.Stdout)
+	return c.newLogger(logLevel)
+}
+
+// NewSubsystemLogger creates a logger that uses the subsystem's level from the Levels config
+func (c LogConfig) NewSubsystemLogger(subsystem string) *slog.Logger {
+	level, ok := subsystemLogLevels[subsystem]
+	if !ok {
+		return c.NewLogger()
+	}
+	return c.newLogger(level)
+}
+
+// newLogger uses the configured writer. If the log file can't be opened, it logs to stdout instead of failing since
+// Validate is used to report the error
+func (c LogConfig) newLogger(level slog.Leveler) *slog.Logger {
+	c.setLevels()
+
+	writer, err := c.writer()
+	if err != nil {
+		logger := slog.New(c.getHandler(os.Stdout, level))
+		logger.Warn("unable to open log file, using stdout instead", "path", c.File.Path, "error", err)
+		return logger
+	}
+
+	return slog.New(c.getHandler(writer, level))
 }
 
 func (c LogConfig) NewLoggerWithWriter(writer io.Writer) *slog.Logger {
-	return slog.New(c.getHandler(writer))
+	c.setLevels()
+	return slog.New(c.getHandler(writer, logLevel))
 }
 
 // GetLogLevel returns the Level based on parsed string. Defaults to Info instead of error
 func (c LogConfig) GetLogLevel() slog.Level {
-	switch strings.ToLower(c.Level) {
+	return parseLogLevel(c.Level)
+}
+
+// GetSubsystemLogLevel returns the subsystem's Level if it is configured, otherwise the global Level
+func (c LogConfig) GetSubsystemLogLevel(subsystem string) slog.Level {
+	level, ok := c.Levels[subsystem]
+	if !ok || level == "" {
+		return c.GetLogLevel()
+	}
+	return parseLogLevel(level)
+}
+
+func parseLogLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
 	case "debug":
 		return slog.LevelDebug
 	case "warn":
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	defaultLogFileMaxSizeMB  = 100
+	defaultLogFileMaxBackups = 3
+)
+
+var (
+	logFilesMtx sync.Mutex
+	// logFiles are shared by path so all loggers write to the same file and rotate it together
+	logFiles = map[string]*rotatingFile{}
+)
+
+// rotatingFile writes to a log file and rotates it before it grows larger than maxSize. Rotated files are renamed
+// with a number suffix, where path.1 is the newest, and only maxBackups of them are kept
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+// openLogFile returns the shared rotatingFile for the path, opening it if it isn't already open
+func openLogFile(cfg LogFileConfig) (*rotatingFile, error) {
+	path, err := filepath.Abs(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log file path: %w", err)
+	}
+
+	logFilesMtx.Lock()
+	defer logFilesMtx.Unlock()
+
+	rf, ok := logFiles[path]
+	if ok {
+		return rf, nil
+	}
+
+	maxSizeMB := cfg.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultLogFileMaxSizeMB
+	}
+	maxBackups := cfg.MaxBackups
+	if maxBackups <= 0 {
+		maxBackups = defaultLogFileMaxBackups
+	}
+
+	rf = &rotatingFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	err = rf.open()
+	if err != nil {
+		return nil, err
+	}
+
+	logFiles[path] = rf
+	return rf, nil
+}
+
+// Write rotates the file first if the new data would make it too large. Data is never split between files
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		err := rf.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// open opens the log file for appending and creates its directory if needed
+func (rf *rotatingFile) open() error {
+	err := os.MkdirAll(filepath.Dir(rf.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("unable to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("unable to get log file size: %w", err)
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// rotate closes the current file, shifts the existing backups, and opens a new file
+func (rf *rotatingFile) rotate() error {
+	err := rf.file.Close()
+	if err != nil {
+		return fmt.Errorf("unable to close log file: %w", err)
+	}
+
+	err = os.Remove(rf.backupPath(rf.maxBackups))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to remove oldest log file: %w", err)
+	}
+
+	for i := rf.maxBackups - 1; i >= 0; i-- {
+		err = os.Rename(rf.backupPath(i), rf.backupPath(i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("unable to rotate log file: %w", err)
+		}
+	}
+
+	return rf.open()
+}
+
+// backupPath returns the path for the numbered backup. 0 is the current file
+func (rf *rotatingFile) backupPath(i int) string {
+	if i == 0 {
+		return rf.path
+	}
+	return fmt.Sprintf("%s.%d", rf.path, i)
+}
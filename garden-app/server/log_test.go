@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      LogConfig
+		expectedErr string
+	}{
+		{
+			"Empty",
+			LogConfig{},
+			"",
+		},
+		{
+			"Valid",
+			LogConfig{
+				Level:  "warn",
+				Format: "json",
+				Levels: map[string]string{"mqtt": "debug", "storage": "ERROR"},
+			},
+			"",
+		},
+		{
+			"InvalidFormat",
+			LogConfig{Format: "xml"},
+			`invalid log.format "xml": must be one of [json text]`,
+		},
+		{
+			"InvalidLevel",
+			LogConfig{Level: "verbose"},
+			`invalid log.level "verbose"`,
+		},
+		{
+			"InvalidSubsystem",
+			LogConfig{Levels: map[string]string{"weather": "debug"}},
+			`invalid log.levels subsystem "weather": must be one of [server worker mqtt storage]`,
+		},
+		{
+			"InvalidSubsystemLevel",
+			LogConfig{Levels: map[string]string{"worker": "verbose"}},
+			`invalid log.levels.worker "verbose"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestSubsystemLogLevels(t *testing.T) {
+	t.Cleanup(func() { LogConfig{}.setLevels() })
+
+	cfg := LogConfig{
+		Level:  "warn",
+		Levels: map[string]string{"mqtt": "debug"},
+	}
+
+	var buf bytes.Buffer
+	cfg.setLevels()
+	mqttLogger := slog.New(cfg.getHandler(&buf, subsystemLogLevels[LogSubsystemMQTT]))
+	workerLogger := slog.New(cfg.getHandler(&buf, subsystemLogLevels[LogSubsystemWorker]))
+
+	mqttLogger.Debug("mqtt debug")
+	workerLogger.Info("worker info")
+	workerLogger.Warn("worker warn")
+
+	assert.Contains(t, buf.String(), "mqtt debug")
+	assert.NotContains(t, buf.String(), "worker info")
+	assert.Contains(t, buf.String(), "worker warn")
+
+	t.Run("ChangingLevelsUpdatesExistingLoggers", func(t *testing.T) {
+		buf.Reset()
+		LogConfig{Level: "info"}.setLevels()
+
+		mqttLogger.Debug("mqtt debug")
+		workerLogger.Info("worker info")
+
+		assert.NotContains(t, buf.String(), "mqtt debug")
+		assert.Contains(t, buf.String(), "worker info")
+	})
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "garden-app.log")
+
+	rf, err := openLogFile(LogFileConfig{Path: path, MaxBackups: 2})
+	require.NoError(t, err)
+	rf.maxSize = 10
+
+	t.Run("SharedByPath", func(t *testing.T) {
+		other, err := openLogFile(LogFileConfig{Path: path})
+		require.NoError(t, err)
+		assert.Same(t, rf, other)
+	})
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = rf.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	readFile := func(name string) string {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	assert.Equal(t, "fourth\n", readFile(path))
+	assert.Equal(t, "third\n", readFile(path+".1"))
+	assert.Equal(t, "second\n", readFile(path+".2"))
+	assert.NoFileExists(t, path+".3")
+
+	t.Run("LargeWriteIsNotSplit", func(t *testing.T) {
+		line := strings.Repeat("x", 20) + "\n"
+		_, err = rf.Write([]byte(line))
+		require.NoError(t, err)
+
+		assert.Equal(t, line, readFile(path))
+		assert.Equal(t, "fourth\n", readFile(path+".1"))
+	})
+}
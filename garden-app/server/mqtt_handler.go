@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -10,17 +11,27 @@ import (
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
 
 type MQTTHandler struct {
 	storageClient *storage.Client
 	logger        *slog.Logger
+
+	// worker is used to record health data and start queued WaterActions. It is set after creating the Worker since the MQTT client is needed
+	// to create it, but the client does not connect to receive messages until the Worker starts
+	worker *worker.Worker
+
+	// disableNotifications is used in simulation mode so the simulated controller does not send notifications
+	// to real recipients
+	disableNotifications bool
 }
 
 func NewMQTTHandler(storageClient *storage.Client, logger *slog.Logger) *MQTTHandler {
-	return &MQTTHandler{storageClient, logger}
+	return &MQTTHandler{storageClient: storageClient, logger: logger}
 }
 
 func (h *MQTTHandler) getGarden(topicPrefix string) (*pkg.Garden, error) {
@@ -70,11 +81,112 @@ func (h *MQTTHandler) Handle(_ mqtt.Client, msg mqtt.Message) {
 	}
 }
 
+// HandleHealth records the time that a controller published health data
+func (h *MQTTHandler) HandleHealth(_ mqtt.Client, msg mqtt.Message) {
+	topicPrefix := strings.TrimSuffix(msg.Topic(), "/data/health")
+	if topicPrefix == "" || h.worker == nil {
+		return
+	}
+	h.logger.Debug("received health data", "topic_prefix", topicPrefix)
+	h.worker.RecordControllerContact(topicPrefix)
+}
+
+// HandleLight records the light state published by a controller after its light changes
+func (h *MQTTHandler) HandleLight(_ mqtt.Client, msg mqtt.Message) {
+	err := h.handleLight(msg.Topic(), msg.Payload())
+	if err != nil {
+		h.logger.With("topic", msg.Topic(), "error", err).Error("error handling light data")
+	}
+}
+
+func (h *MQTTHandler) handleLight(topic string, payload []byte) error {
+	topicPrefix := strings.TrimSuffix(topic, "/data/light")
+	if topicPrefix == "" || h.worker == nil {
+		return nil
+	}
+
+	state, err := parseLightMessage(payload)
+	if err != nil {
+		return fmt.Errorf("error parsing message: %w", err)
+	}
+
+	h.logger.Debug("received light data", "topic_prefix", topicPrefix, "state", state.String())
+	h.worker.RecordLightState(topicPrefix, state)
+	return nil
+}
+
+// HandleLightLevel records the ambient light level published by a controller's light sensor
+func (h *MQTTHandler) HandleLightLevel(_ mqtt.Client, msg mqtt.Message) {
+	err := h.handleLightLevel(msg.Topic(), msg.Payload())
+	if err != nil {
+		h.logger.With("topic", msg.Topic(), "error", err).Error("error handling light level data")
+	}
+}
+
+func (h *MQTTHandler) handleLightLevel(topic string, payload []byte) error {
+	topicPrefix := strings.TrimSuffix(topic, "/data/light_level")
+	if topicPrefix == "" || h.worker == nil {
+		return nil
+	}
+
+	lux, err := parseLightLevelMessage(payload)
+	if err != nil {
+		return fmt.Errorf("error parsing message: %w", err)
+	}
+
+	h.logger.Debug("received light level data", "topic_prefix", topicPrefix, "lux", lux)
+	h.worker.RecordLightLevel(topicPrefix, lux)
+	return nil
+}
+
+// HandleWaterAck is used when a controller acknowledges a water command by publishing its CommandID
+func (h *MQTTHandler) HandleWaterAck(_ mqtt.Client, msg mqtt.Message) {
+	topicPrefix := strings.TrimSuffix(msg.Topic(), "/ack/water")
+	commandID := strings.TrimSpace(string(msg.Payload()))
+	if topicPrefix == "" || commandID == "" || h.worker == nil {
+		return
+	}
+	h.logger.Debug("received water command acknowledgment", "topic_prefix", topicPrefix, "command_id", commandID)
+	h.worker.AcknowledgeWaterCommand(topicPrefix, commandID)
+}
+
+// HandleFirmwareStatus is used when a controller reports the result of a firmware update
+func (h *MQTTHandler) HandleFirmwareStatus(_ mqtt.Client, msg mqtt.Message) {
+	err := h.handleFirmwareStatus(msg.Topic(), msg.Payload())
+	if err != nil {
+		h.logger.With("topic", msg.Topic(), "error", err).Error("error handling firmware update status")
+	}
+}
+
+func (h *MQTTHandler) handleFirmwareStatus(topic string, payload []byte) error {
+	topicPrefix := strings.TrimSuffix(topic, "/ack/firmware_update")
+	if topicPrefix == "" || h.worker == nil {
+		return nil
+	}
+
+	var statusMsg action.FirmwareStatusMessage
+	err := json.Unmarshal(payload, &statusMsg)
+	if err != nil {
+		return fmt.Errorf("error parsing message: %w", err)
+	}
+	if err := statusMsg.Status.Validate(); err != nil {
+		return err
+	}
+
+	garden, err := h.getGarden(topicPrefix)
+	if err != nil {
+		return fmt.Errorf("error getting garden with topic-prefix %q: %w", topicPrefix, err)
+	}
+
+	h.logger.Info("received firmware update status", "garden_id", garden.GetID(), "firmware_id", statusMsg.FirmwareID, "status", statusMsg.Status)
+	return h.worker.CompleteFirmwareUpdate(context.Background(), statusMsg.FirmwareID, garden.GetID(), statusMsg.Status, statusMsg.Error)
+}
+
 func (h *MQTTHandler) handle(topic string, payload []byte) error {
 	logger := h.logger.With("topic", topic)
 	logger.Info("received message", "message", string(payload))
 
-	zonePosition, waterDuration, err := parseWaterMessage(payload)
+	waterMsg, err := parseWaterMessage(payload)
 	if err != nil {
 		return fmt.Errorf("error parsing message: %w", err)
 	}
@@ -90,11 +202,38 @@ func (h *MQTTHandler) handle(topic string, payload []byte) error {
 	}
 	logger.Info("found garden with topic-prefix", "topic_prefix", topicPrefix, "garden_id", garden.GetID())
 
-	zone, err := h.getZone(garden.GetID(), zonePosition)
+	zone, err := h.getZone(garden.GetID(), waterMsg.zonePosition)
 	if err != nil {
-		return fmt.Errorf("error getting zone with position %d: %w", zonePosition, err)
+		return fmt.Errorf("error getting zone with position %d: %w", waterMsg.zonePosition, err)
+	}
+	logger.Info("found zone with position", "zone_position", waterMsg.zonePosition, "zone_id", zone.GetID())
+
+	switch {
+	case waterMsg.manual:
+		// Manual waterings were not commanded by the garden-app, so they are added to the water history with their
+		// measurement instead of completing a WaterAction or checking for unexpected flow
+		if h.worker != nil {
+			h.worker.RecordManualWatering(garden, zone, waterMsg.duration, waterMsg.measuredLiters())
+		}
+	default:
+		if waterMsg.milliliters > 0 {
+			h.recordMeasuredLiters(logger, zone, waterMsg.milliliters)
+			// This is checked before starting queued WaterActions since their water history would make the flow expected
+			if h.worker != nil {
+				h.worker.CheckUnexpectedFlow(garden, zone, float64(waterMsg.milliliters)/1000)
+			}
+		}
+
+		// The controller publishes this message after watering, so queued WaterActions for the Garden can start
+		if h.worker != nil {
+			h.worker.CompleteWaterAction(garden, zone)
+		}
+	}
+
+	if h.disableNotifications {
+		logger.Debug("notifications are disabled")
+		return nil
 	}
-	logger.Info("found zone with position", "zone_position", zonePosition, "zone_id", zone.GetID())
 
 	// TODO: this might end up getting client from garden or zone config instead of using all
 	notificationClients, err := h.storageClient.NotificationClientConfigs.GetAll(context.Background(), nil)
@@ -103,7 +242,13 @@ func (h *MQTTHandler) handle(topic string, payload []byte) error {
 	}
 
 	title := fmt.Sprintf("%s finished watering", zone.Name)
-	message := fmt.Sprintf("watered for %s", waterDuration.String())
+	message := fmt.Sprintf("watered for %s", waterMsg.duration.String())
+	if waterMsg.manual {
+		message = "manually " + message
+	}
+	if waterMsg.milliliters > 0 {
+		message += fmt.Sprintf(" (%.1fL)", float64(waterMsg.milliliters)/1000)
+	}
 
 	for _, nc := range notificationClients {
 		ncLogger := logger.With(notificationClientIDLogField, nc.GetID())
@@ -120,47 +265,117 @@ func (h *MQTTHandler) handle(topic string, payload []byte) error {
 	return nil
 }
 
-func parseWaterMessage(msg []byte) (int, time.Duration, error) {
-	p := &parser{msg, 0}
-	zonePosition, err := p.readNextInt()
-	if err != nil {
-		return 0, 0, fmt.Errorf("error parsing zone position: %w", err)
+// recordMeasuredLiters stores the volume measured by the Zone's flow meter with its water history. Errors are only
+// logged so notifications are still sent
+func (h *MQTTHandler) recordMeasuredLiters(logger *slog.Logger, zone *pkg.Zone, milliliters int) {
+	if h.worker != nil {
+		h.worker.RecordMeasuredLiters(zone, float64(milliliters)/1000)
+	}
+
+	if h.storageClient.WaterHistory == nil {
+		return
 	}
 
-	waterMillis, err := p.readNextInt()
+	err := h.storageClient.WaterHistory.SetMeasuredLiters(context.Background(), zone.GetID(), float64(milliliters)/1000)
 	if err != nil {
-		return 0, 0, fmt.Errorf("error parsing watering time: %w", err)
+		logger.Error("unable to store measured liters", "zone_id", zone.GetID(), "error", err)
 	}
-	waterDuration := time.Duration(waterMillis) * time.Millisecond
+}
 
-	return zonePosition, waterDuration, nil
+// waterMessage is the data published by a controller after watering a Zone
+type waterMessage struct {
+	zonePosition int
+	duration     time.Duration
+	// milliliters is measured by a flow meter and is 0 when the Zone does not have one
+	milliliters int
+	// manual is true when watering was started at the controller, like with a button
+	manual bool
 }
 
-type parser struct {
-	data []byte
-	i    int
+// measuredLiters returns the liters measured by a flow meter or nil if there was no measurement
+func (m waterMessage) measuredLiters() *float64 {
+	if m.milliliters <= 0 {
+		return nil
+	}
+	liters := float64(m.milliliters) / 1000
+	return &liters
 }
 
-func (p *parser) readNextInt() (int, error) {
-	reading := false
-	var n []byte
-	for ; p.i < len(p.data); p.i++ {
-		c := p.data[p.i]
-		if c == ' ' {
-			p.i++
-			break
-		}
-		if reading {
-			n = append(n, c)
-			continue
+// parseWaterMessage reads an InfluxDB line protocol message like "water,zone=1 millis=6000,ml=1500". The
+// "ml" field is optional and waterings started with a button include a "source=button" tag
+func parseWaterMessage(msg []byte) (waterMessage, error) {
+	tags, fields, _ := strings.Cut(string(msg), " ")
+	tagValues := parseKeyValues(tags)
+	fieldValues := parseKeyValues(fields)
+
+	var result waterMessage
+	var err error
+	result.zonePosition, err = parseInt(tagValues["zone"])
+	if err != nil {
+		return waterMessage{}, fmt.Errorf("error parsing zone position: %w", err)
+	}
+
+	waterMillis, err := parseInt(fieldValues["millis"])
+	if err != nil {
+		return waterMessage{}, fmt.Errorf("error parsing watering time: %w", err)
+	}
+	result.duration = time.Duration(waterMillis) * time.Millisecond
+
+	if ml, ok := fieldValues["ml"]; ok {
+		result.milliliters, err = parseInt(ml)
+		if err != nil {
+			return waterMessage{}, fmt.Errorf("error parsing milliliters: %w", err)
 		}
-		if c == '=' {
-			reading = true
-			continue
+	}
+
+	result.manual = tagValues["source"] == "button"
+
+	return result, nil
+}
+
+// parseLightMessage reads the light state from a message like `light,garden="garden" state=1`
+func parseLightMessage(msg []byte) (pkg.LightState, error) {
+	_, fields, _ := strings.Cut(string(msg), " ")
+	state, err := parseInt(parseKeyValues(fields)["state"])
+	if err != nil {
+		return 0, fmt.Errorf("error parsing light state: %w", err)
+	}
+
+	switch pkg.LightState(state) {
+	case pkg.LightStateOff, pkg.LightStateOn:
+		return pkg.LightState(state), nil
+	default:
+		return 0, fmt.Errorf("invalid light state: %d", state)
+	}
+}
+
+// parseLightLevelMessage reads the light level, in lux, from a message like "light_level value=1200.5"
+func parseLightLevelMessage(msg []byte) (float64, error) {
+	_, fields, _ := strings.Cut(string(msg), " ")
+	lux, err := strconv.ParseFloat(parseKeyValues(fields)["value"], 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing light level: %w", err)
+	}
+	if lux < 0 {
+		return 0, fmt.Errorf("invalid light level: %f", lux)
+	}
+	return lux, nil
+}
+
+// parseKeyValues reads comma-separated "key=value" pairs. Parts without "=", like the measurement name, are skipped
+func parseKeyValues(s string) map[string]string {
+	result := map[string]string{}
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if ok {
+			result[key] = value
 		}
 	}
+	return result
+}
 
-	result, err := strconv.Atoi(string(n))
+func parseInt(s string) (int, error) {
+	result, err := strconv.Atoi(s)
 	if err != nil {
 		return 0, fmt.Errorf("invalid integer: %w", err)
 	}
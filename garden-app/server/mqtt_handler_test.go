@@ -2,13 +2,21 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"testing"
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications/fake"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
 	"github.com/calvinmclean/babyapi"
+	paho "github.com/eclipse/paho.mqtt.golang"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,29 +25,55 @@ func TestParseWaterMessage(t *testing.T) {
 		in            string
 		expectedPos   int
 		waterDuration time.Duration
+		milliliters   int
+		manual        bool
 	}{
 		{
 			"water,zone=1 millis=6000",
-			1, 6000 * time.Millisecond,
+			1, 6000 * time.Millisecond, 0, false,
 		},
 		{
 			"water,zone=100 millis=1",
-			100, 1 * time.Millisecond,
+			100, 1 * time.Millisecond, 0, false,
 		},
 		{
 			"water,zone=0 millis=0",
-			0, 0,
+			0, 0, 0, false,
+		},
+		{
+			"water,zone=2 millis=6000,ml=1500",
+			2, 6000 * time.Millisecond, 1500, false,
+		},
+		{
+			"water,zone=3,source=button millis=15000",
+			3, 15000 * time.Millisecond, 0, true,
+		},
+		{
+			"water,zone=3,source=button millis=15000,ml=500",
+			3, 15000 * time.Millisecond, 500, true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.in, func(t *testing.T) {
-			zonePosition, waterDuration, err := parseWaterMessage([]byte(tt.in))
+			waterMsg, err := parseWaterMessage([]byte(tt.in))
 			require.NoError(t, err)
-			require.Equal(t, tt.expectedPos, zonePosition)
-			require.Equal(t, tt.waterDuration, waterDuration)
+			require.Equal(t, tt.expectedPos, waterMsg.zonePosition)
+			require.Equal(t, tt.waterDuration, waterMsg.duration)
+			require.Equal(t, tt.milliliters, waterMsg.milliliters)
+			require.Equal(t, tt.manual, waterMsg.manual)
 		})
 	}
+
+	t.Run("ErrorInvalidMilliliters", func(t *testing.T) {
+		_, err := parseWaterMessage([]byte("water,zone=2 millis=6000,ml=abc"))
+		require.EqualError(t, err, `error parsing milliliters: invalid integer: strconv.Atoi: parsing "abc": invalid syntax`)
+	})
+
+	t.Run("ErrorMissingWateringTime", func(t *testing.T) {
+		_, err := parseWaterMessage([]byte("water,zone=2"))
+		require.EqualError(t, err, `error parsing watering time: invalid integer: strconv.Atoi: parsing "": invalid syntax`)
+	})
 }
 
 func TestHandle(t *testing.T) {
@@ -88,4 +122,217 @@ func TestHandle(t *testing.T) {
 		err = handler.handle("garden/data/water", []byte("water,zone=0 millis=6000"))
 		require.NoError(t, err)
 	})
+
+	err = storageClient.NotificationClientConfigs.Set(context.Background(), &notifications.Client{
+		ID:      babyapi.NewID(),
+		Name:    "TestClient",
+		Type:    "fake",
+		Options: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	t.Run("NotificationsDisabled", func(t *testing.T) {
+		fake.ResetLastMessage()
+		handler.disableNotifications = true
+		defer func() { handler.disableNotifications = false }()
+
+		err = handler.handle("garden/data/water", []byte("water,zone=0 millis=6000"))
+		require.NoError(t, err)
+		require.Equal(t, fake.Message{}, fake.LastMessage())
+	})
+
+	t.Run("SuccessfulWithNotificationClient", func(t *testing.T) {
+		fake.ResetLastMessage()
+
+		err = handler.handle("garden/data/water", []byte("water,zone=0 millis=6000"))
+		require.NoError(t, err)
+		require.Equal(t, fake.Message{Title: " finished watering", Message: "watered for 6s"}, fake.LastMessage())
+	})
+
+	t.Run("SuccessfulWithMeasuredLiters", func(t *testing.T) {
+		fake.ResetLastMessage()
+
+		err = storageClient.WaterHistory.AddWaterHistory(context.Background(), zone.GetID(), pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: 6 * time.Second},
+			RecordTime: time.Now().Add(-time.Hour),
+		})
+		require.NoError(t, err)
+
+		err = handler.handle("garden/data/water", []byte("water,zone=0 millis=6000,ml=1500"))
+		require.NoError(t, err)
+		require.Equal(t, fake.Message{Title: " finished watering", Message: "watered for 6s (1.5L)"}, fake.LastMessage())
+
+		history, err := storageClient.WaterHistory.GetWaterHistory(context.Background(), zone.GetID(), time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.NotNil(t, history[0].MeasuredLiters)
+		require.Equal(t, 1.5, *history[0].MeasuredLiters)
+	})
+
+	t.Run("SuccessfulManualWatering", func(t *testing.T) {
+		fake.ResetLastMessage()
+
+		bus := events.NewBus()
+		subscriber, unsubscribe := bus.Subscribe()
+		defer unsubscribe()
+
+		handler.worker = worker.NewWorker(storageClient, nil, nil, slog.Default())
+		handler.worker.SetEventBus(bus)
+		handler.worker.SetManualWatering(worker.ManualWateringConfig{PublishEvents: true})
+		defer func() { handler.worker = nil }()
+
+		err = handler.handle("garden/data/water", []byte("water,zone=0,source=button millis=15000,ml=500"))
+		require.NoError(t, err)
+		require.Equal(t, fake.Message{Title: " finished watering", Message: "manually watered for 15s (0.5L)"}, fake.LastMessage())
+
+		history, err := storageClient.WaterHistory.GetWaterHistory(context.Background(), zone.GetID(), time.Time{}, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.True(t, history[0].Manual)
+		require.Equal(t, 15*time.Second, history[0].Duration.Duration)
+		require.NotNil(t, history[0].MeasuredLiters)
+		require.Equal(t, 0.5, *history[0].MeasuredLiters)
+		// The measurement is not added to the previous watering
+		require.Equal(t, 1.5, *history[1].MeasuredLiters)
+
+		select {
+		case e := <-subscriber:
+			require.Equal(t, "water_action.manual", e.Type)
+			require.Equal(t, worker.WaterActionEvent{GardenID: garden.GetID(), ZoneID: zone.GetID(), Duration: "15s"}, e.Data)
+		default:
+			t.Error("expected water_action.manual Event")
+		}
+	})
+}
+
+func TestHandleHealth(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	handler := NewMQTTHandler(storageClient, slog.Default())
+	client := mqtt.NewInMemoryClient(mqtt.Config{}, nil, mqtt.TopicHandler{
+		Topic:   "+/data/health",
+		Handler: paho.MessageHandler(handler.HandleHealth),
+	})
+
+	handler.worker = worker.NewWorker(storageClient, nil, client, slog.Default())
+
+	g := &pkg.Garden{TopicPrefix: "garden"}
+	require.Equal(t, "DOWN", handler.worker.GardenHealth(context.Background(), g, nil).Status)
+
+	require.NoError(t, client.Publish("garden/data/health", []byte(`health garden="garden"`)))
+
+	health := handler.worker.GardenHealth(context.Background(), g, nil)
+	require.Equal(t, "UP", health.Status)
+	require.NotNil(t, health.LastContact)
+}
+
+func TestParseLightMessage(t *testing.T) {
+	tests := []struct {
+		in            string
+		expected      pkg.LightState
+		expectedError string
+	}{
+		{`light,garden="garden" state=1`, pkg.LightStateOn, ""},
+		{`light,garden="garden" state=0`, pkg.LightStateOff, ""},
+		{`light,garden="garden" state=2`, 0, "invalid light state: 2"},
+		{`light,garden="garden"`, 0, `error parsing light state: invalid integer: strconv.Atoi: parsing "": invalid syntax`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			state, err := parseLightMessage([]byte(tt.in))
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, state)
+		})
+	}
+}
+
+func TestParseLightLevelMessage(t *testing.T) {
+	tests := []struct {
+		in            string
+		expected      float64
+		expectedError string
+	}{
+		{`light_level value=1200.5`, 1200.5, ""},
+		{`light_level,garden="garden" value=0`, 0, ""},
+		{`light_level value=-1`, 0, "invalid light level: -1.000000"},
+		{`light_level`, 0, `error parsing light level: strconv.ParseFloat: parsing "": invalid syntax`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			lux, err := parseLightLevelMessage([]byte(tt.in))
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, lux)
+		})
+	}
+}
+
+func TestHandleLight(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	handler := NewMQTTHandler(storageClient, slog.Default())
+	client := mqtt.NewInMemoryClient(mqtt.Config{}, nil, mqtt.TopicHandler{
+		Topic:   "+/data/light",
+		Handler: paho.MessageHandler(handler.HandleLight),
+	})
+
+	handler.worker = worker.NewWorker(storageClient, nil, client, slog.Default())
+
+	g := &pkg.Garden{TopicPrefix: "garden"}
+	require.Nil(t, handler.worker.LightState(g))
+
+	require.NoError(t, client.Publish("garden/data/light", []byte(`light,garden="garden" state=1`)))
+
+	state := handler.worker.LightState(g)
+	require.NotNil(t, state)
+	require.Equal(t, pkg.LightStateOn, state.State)
+}
+
+func TestHandleWaterAck(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	handler := NewMQTTHandler(storageClient, slog.Default())
+	client := mqtt.NewInMemoryClient(mqtt.Config{WaterTopicTemplate: "{{.Garden}}/command/water"}, nil, mqtt.TopicHandler{
+		Topic:   "+/ack/water",
+		Handler: paho.MessageHandler(handler.HandleWaterAck),
+	})
+
+	handler.worker = worker.NewWorker(storageClient, nil, client, slog.Default())
+	handler.worker.SetWaterAck(worker.WaterAckConfig{Enabled: true})
+
+	var commandID string
+	require.NoError(t, client.Subscribe("garden/command/water", paho.MessageHandler(func(_ paho.Client, msg paho.Message) {
+		var waterMsg action.WaterMessage
+		require.NoError(t, json.Unmarshal(msg.Payload(), &waterMsg))
+		commandID = waterMsg.CommandID
+	})))
+
+	position := uint(0)
+	g := &pkg.Garden{ID: babyapi.NewID(), TopicPrefix: "garden"}
+	z := &pkg.Zone{ID: babyapi.NewID(), Position: &position}
+	err = handler.worker.ExecuteWaterAction(context.Background(), g, z, &action.WaterAction{Duration: &pkg.Duration{Duration: time.Second}})
+	require.NoError(t, err)
+	require.NotEmpty(t, commandID)
+	require.Equal(t, 1, handler.worker.PendingWaterAcks())
+
+	require.NoError(t, client.Publish("garden/ack/water", []byte(commandID)))
+	require.Equal(t, 0, handler.worker.PendingWaterAcks())
 }
@@ -46,6 +46,8 @@ func NewNotificationClientsAPI() *NotificationClientsAPI {
 
 	api.AddCustomIDRoute(http.MethodPost, "/test", babyapi.Handler(api.testNotificationClient))
 
+	api.ApplyExtension(conditionalRequests[*notifications.Client]{})
+
 	return api
 }
 
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-chi/render"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcBasePath        = "/auth"
+	sessionCookieName   = "garden_app_session"
+	oidcStateCookieName = "garden_app_oidc_state"
+)
+
+var defaultOIDCScopes = []pkg.APITokenScope{pkg.APITokenScopeRead, pkg.APITokenScopeWrite, pkg.APITokenScopeActions}
+
+// oidcAuthenticator logs in users with an OpenID Connect provider and validates the ID tokens it issues. The ID
+// token is used as the browser's session cookie so no sessions need to be stored
+type oidcAuthenticator struct {
+	verifier      *oidc.IDTokenVerifier
+	oauth2Config  oauth2.Config
+	scopes        []pkg.APITokenScope
+	secureCookies bool
+}
+
+func newOIDCAuthenticator(ctx context.Context, cfg OIDCConfig) (*oidcAuthenticator, error) {
+	if cfg.ClientID == "" {
+		return nil, errors.New("missing required client_id field")
+	}
+	if cfg.RedirectURL == "" {
+		return nil, errors.New("missing required redirect_url field")
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOIDCScopes
+	}
+	err := pkg.ValidateAPITokenScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("error discovering OIDC provider: %w", err)
+	}
+
+	return &oidcAuthenticator{
+		verifier: provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		oauth2Config: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+		},
+		scopes:        scopes,
+		secureCookies: strings.HasPrefix(cfg.RedirectURL, "https://"),
+	}, nil
+}
+
+// verify validates the raw ID token and returns a name for logging. The name is empty if the token is invalid
+func (o *oidcAuthenticator) verify(ctx context.Context, rawIDToken string) string {
+	idToken, err := o.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return ""
+	}
+
+	var claims struct {
+		Email string `json:"email"`
+	}
+	if idToken.Claims(&claims) == nil && claims.Email != "" {
+		return claims.Email
+	}
+	return idToken.Subject
+}
+
+// loginHandler redirects to the provider. The state includes the page to return to after logging in
+func (o *oidcAuthenticator) loginHandler(w http.ResponseWriter, r *http.Request) {
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	if err != nil {
+		render.Render(w, r, babyapi.InternalServerError(fmt.Errorf("error generating state: %w", err)))
+		return
+	}
+
+	redirect := r.URL.Query().Get("redirect")
+	if !isLocalPath(redirect) {
+		redirect = "/"
+	}
+	state := hex.EncodeToString(data) + ":" + url.QueryEscape(redirect)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     oidcBasePath,
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   o.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, o.oauth2Config.AuthCodeURL(state), http.StatusFound)
+}
+
+// callbackHandler exchanges the code from the provider for an ID token and stores it in the session cookie
+func (o *oidcAuthenticator) callbackHandler(w http.ResponseWriter, r *http.Request) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	if errParam := r.URL.Query().Get("error"); errParam != "" {
+		render.Render(w, r, babyapi.ErrInvalidRequest(fmt.Errorf("login failed: %s", errParam)))
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	stateCookie, err := r.Cookie(oidcStateCookieName)
+	if err != nil || state == "" || state != stateCookie.Value {
+		render.Render(w, r, babyapi.ErrInvalidRequest(errors.New("invalid state")))
+		return
+	}
+
+	token, err := o.oauth2Config.Exchange(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		logger.Error("error exchanging OIDC code", "error", err)
+		render.Render(w, r, babyapi.ErrInvalidRequest(fmt.Errorf("error exchanging code: %w", err)))
+		return
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		render.Render(w, r, babyapi.InternalServerError(errors.New("missing id_token in token response")))
+		return
+	}
+
+	idToken, err := o.verifier.Verify(r.Context(), rawIDToken)
+	if err != nil {
+		logger.Error("received invalid OIDC ID token", "error", err)
+		render.Render(w, r, babyapi.ErrInvalidRequest(fmt.Errorf("invalid ID token: %w", err)))
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Path:     oidcBasePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   o.secureCookies,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    rawIDToken,
+		Path:     "/",
+		Expires:  idToken.Expiry,
+		HttpOnly: true,
+		Secure:   o.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	redirect := "/"
+	if _, escaped, found := strings.Cut(state, ":"); found {
+		unescaped, err := url.QueryUnescape(escaped)
+		if err == nil && isLocalPath(unescaped) {
+			redirect = unescaped
+		}
+	}
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+// logoutHandler removes the session cookie. It does not end the session with the provider
+func (o *oidcAuthenticator) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   o.secureCookies,
+	})
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// isLocalPath makes sure a redirect stays on this server
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") && !strings.HasPrefix(path, "/\\")
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+const testIssuer = "https://accounts.example.com"
+
+func newTestOIDCAuthenticator(t *testing.T) (*oidcAuthenticator, func(jwt.Claims) string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, nil)
+	require.NoError(t, err)
+
+	sign := func(claims jwt.Claims) string {
+		token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+		require.NoError(t, err)
+		return token
+	}
+
+	keySet := &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{&key.PublicKey}}
+	return &oidcAuthenticator{
+		verifier: oidc.NewVerifier(testIssuer, keySet, &oidc.Config{ClientID: "garden-app"}),
+		oauth2Config: oauth2.Config{
+			ClientID:    "garden-app",
+			RedirectURL: "http://localhost:8080/auth/callback",
+			Endpoint:    oauth2.Endpoint{AuthURL: testIssuer + "/authorize", TokenURL: testIssuer + "/token"},
+			Scopes:      []string{oidc.ScopeOpenID},
+		},
+		scopes: []pkg.APITokenScope{pkg.APITokenScopeRead},
+	}, sign
+}
+
+func TestOIDCAuthMiddleware(t *testing.T) {
+	oidcAuth, sign := newTestOIDCAuthenticator(t)
+
+	auth, err := newAuthenticator(AuthConfig{}, nil)
+	require.NoError(t, err)
+	auth.oidc = oidcAuth
+
+	validToken := sign(jwt.Claims{
+		Issuer:   testIssuer,
+		Subject:  "user",
+		Audience: jwt.Audience{"garden-app"},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	expiredToken := sign(jwt.Claims{
+		Issuer:   testIssuer,
+		Subject:  "user",
+		Audience: jwt.Audience{"garden-app"},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+	})
+	wrongAudienceToken := sign(jwt.Claims{
+		Issuer:   testIssuer,
+		Subject:  "user",
+		Audience: jwt.Audience{"other-app"},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	api := babyapi.NewRootAPI("test", "/").
+		AddMiddleware(auth.middleware).
+		AddCustomRoute(http.MethodGet, "/gardens", okHandler).
+		AddCustomRoute(http.MethodPost, "/gardens", okHandler).
+		AddCustomRoute(http.MethodGet, oidcBasePath+"/login", http.HandlerFunc(oidcAuth.loginHandler))
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		setAuth          func(*http.Request)
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			"NoTokenJSON",
+			http.MethodGet, "/gardens",
+			func(*http.Request) {},
+			http.StatusUnauthorized,
+			"",
+		},
+		{
+			"NoTokenHTMLRedirectsToLogin",
+			http.MethodGet, "/gardens?limit=1",
+			func(r *http.Request) { r.Header.Set("Accept", "text/html") },
+			http.StatusFound,
+			"/auth/login?redirect=" + url.QueryEscape("/gardens?limit=1"),
+		},
+		{
+			"LoginDoesNotRequireToken",
+			http.MethodGet, "/auth/login",
+			func(*http.Request) {},
+			http.StatusFound,
+			"",
+		},
+		{
+			"BearerIDToken",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+validToken) },
+			http.StatusOK,
+			"",
+		},
+		{
+			"SessionCookie",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.AddCookie(&http.Cookie{Name: sessionCookieName, Value: validToken}) },
+			http.StatusOK,
+			"",
+		},
+		{
+			"SessionCookieMissingScope",
+			http.MethodPost, "/gardens",
+			func(r *http.Request) { r.AddCookie(&http.Cookie{Name: sessionCookieName, Value: validToken}) },
+			http.StatusForbidden,
+			"",
+		},
+		{
+			"ExpiredIDToken",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+expiredToken) },
+			http.StatusUnauthorized,
+			"",
+		},
+		{
+			"WrongAudience",
+			http.MethodGet, "/gardens",
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+wrongAudienceToken) },
+			http.StatusUnauthorized,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(tt.method, tt.path, strings.NewReader(`{}`))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+			tt.setAuth(r)
+
+			w := babytest.TestRequest(t, api, r)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedLocation != "" {
+				assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestOIDCLoginHandler(t *testing.T) {
+	oidcAuth, _ := newTestOIDCAuthenticator(t)
+
+	tests := []struct {
+		name             string
+		redirect         string
+		expectedRedirect string
+	}{
+		{"LocalPath", "/gardens/c5cvhpcbcv45e8bp16dg", "/gardens/c5cvhpcbcv45e8bp16dg"},
+		{"Empty", "", "/"},
+		{"OtherHost", "https://example.com", "/"},
+		{"SchemeRelative", "//example.com", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "/auth/login?redirect="+url.QueryEscape(tt.redirect), http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest(t, babyapi.NewRootAPI("test", "/").
+				AddCustomRoute(http.MethodGet, "/auth/login", http.HandlerFunc(oidcAuth.loginHandler)), r)
+			require.Equal(t, http.StatusFound, w.Code)
+
+			location, err := url.Parse(w.Header().Get("Location"))
+			require.NoError(t, err)
+			assert.Equal(t, testIssuer+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+
+			state := location.Query().Get("state")
+			_, escapedRedirect, found := strings.Cut(state, ":")
+			require.True(t, found)
+			redirect, err := url.QueryUnescape(escapedRedirect)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRedirect, redirect)
+
+			cookies := w.Result().Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, oidcStateCookieName, cookies[0].Name)
+			assert.Equal(t, state, cookies[0].Value)
+		})
+	}
+}
+
+func TestOIDCCallbackHandlerInvalidState(t *testing.T) {
+	oidcAuth, _ := newTestOIDCAuthenticator(t)
+
+	r, err := http.NewRequest(http.MethodGet, "/auth/callback?code=abc&state=abc:%2F", http.NoBody)
+	require.NoError(t, err)
+	r.AddCookie(&http.Cookie{Name: oidcStateCookieName, Value: "def:%2F"})
+
+	w := babytest.TestRequest(t, babyapi.NewRootAPI("test", "/").
+		AddCustomRoute(http.MethodGet, "/auth/callback", http.HandlerFunc(oidcAuth.callbackHandler)), r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestNewOIDCAuthenticatorInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         OIDCConfig
+		expectedErr string
+	}{
+		{
+			"MissingClientID",
+			OIDCConfig{IssuerURL: testIssuer, RedirectURL: "http://localhost:8080/auth/callback"},
+			"missing required client_id field",
+		},
+		{
+			"MissingRedirectURL",
+			OIDCConfig{IssuerURL: testIssuer, ClientID: "garden-app"},
+			"missing required redirect_url field",
+		},
+		{
+			"InvalidScope",
+			OIDCConfig{IssuerURL: testIssuer, ClientID: "garden-app", RedirectURL: "http://localhost:8080/auth/callback", Scopes: []pkg.APITokenScope{"everything"}},
+			`invalid scope "everything"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newOIDCAuthenticator(context.Background(), tt.cfg)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
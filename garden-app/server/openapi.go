@@ -0,0 +1,281 @@
+package server
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/jsonschema"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const (
+	openAPIPath = "/openapi.json"
+	docsPath    = "/docs"
+
+	errorSchemaName = "Error"
+)
+
+// swaggerUI is a page that shows the OpenAPI document with Swagger UI
+//
+//go:embed swagger_ui.html
+var swaggerUI []byte
+
+// pathParamRegexp matches chi URL parameters, which can have an optional regular expression like "{id:[0-9]+}"
+var pathParamRegexp = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// OpenAPIDocument is the subset of an OpenAPI 3.1 document that is generated from the routes. OpenAPI 3.1 uses
+// JSON Schema, so the resource schemas from the "/schema" route are used directly
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+// OpenAPIInfo has the title and version of the API
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIComponents has the schemas that are referenced by operations
+type OpenAPIComponents struct {
+	Schemas map[string]*jsonschema.Schema `json:"schemas"`
+}
+
+// OpenAPIOperation describes one method for a path
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *OpenAPIBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter is a URL path parameter
+type OpenAPIParameter struct {
+	Name     string             `json:"name"`
+	In       string             `json:"in"`
+	Required bool               `json:"required"`
+	Schema   *jsonschema.Schema `json:"schema"`
+}
+
+// OpenAPIBody is a JSON request body
+type OpenAPIBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response for one status code. Content is empty if the response does not have a known body
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType has the schema for a request or response body
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchemaRef `json:"schema"`
+}
+
+// OpenAPISchemaRef references a component schema, or is an array of them when Items is set
+type OpenAPISchemaRef struct {
+	Ref        string                       `json:"$ref,omitempty"`
+	Type       string                       `json:"type,omitempty"`
+	Properties map[string]*OpenAPISchemaRef `json:"properties,omitempty"`
+	Items      *OpenAPISchemaRef            `json:"items,omitempty"`
+}
+
+// openAPIHandler responds with an OpenAPI document for all routes of the router that handles the request. The routes
+// are read from the request so custom routes and nested APIs are always included
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		http.Error(w, "routes are not available", http.StatusInternalServerError)
+		return
+	}
+
+	doc, err := newOpenAPIDocument(rctx.Routes, newSchemaResponse())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error creating OpenAPI document: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, doc)
+}
+
+// swaggerUIHandler responds with the Swagger UI page for the OpenAPI document
+func swaggerUIHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(swaggerUI)
+}
+
+// newOpenAPIDocument creates an operation for each route. Routes for a resource from the SchemaResponse use its
+// schema for request and response bodies
+func newOpenAPIDocument(routes chi.Routes, schemas *SchemaResponse) (*OpenAPIDocument, error) {
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.1.0",
+		Info: OpenAPIInfo{
+			Title:   "Garden App",
+			Version: "1.0.0",
+		},
+		Paths: map[string]map[string]*OpenAPIOperation{},
+		Components: OpenAPIComponents{
+			Schemas: map[string]*jsonschema.Schema{
+				errorSchemaName: errorSchema(),
+			},
+		},
+	}
+
+	// Resources are found using their path without parameter names, since the names are different in the routes
+	resourcesByPath := map[string]string{}
+	for name, resource := range schemas.Resources {
+		schemaName := openAPISchemaName(name)
+		doc.Components.Schemas[schemaName] = resource.Schema
+		resourcesByPath[pathParamRegexp.ReplaceAllString(resource.Path, "{}")] = schemaName
+	}
+
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		path := openAPIRoutePath(route)
+
+		operations, ok := doc.Paths[path]
+		if !ok {
+			operations = map[string]*OpenAPIOperation{}
+			doc.Paths[path] = operations
+		}
+		operations[strings.ToLower(method)] = newOpenAPIOperation(method, path, resourcesByPath)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func newOpenAPIOperation(method, path string, resourcesByPath map[string]string) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		OperationID: openAPIOperationID(method, path),
+		Responses: map[string]*OpenAPIResponse{
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(&OpenAPISchemaRef{Ref: componentRef(errorSchemaName)}),
+			},
+		},
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if segments[0] != "" {
+		op.Tags = []string{segments[0]}
+	}
+
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &jsonschema.Schema{Type: "string"},
+		})
+	}
+
+	// Paths are either a resource collection or a single resource, which is the collection followed by an ID
+	normalizedPath := pathParamRegexp.ReplaceAllString(path, "{}")
+	collectionSchema, isCollection := resourcesByPath[normalizedPath]
+	resourceSchema, isResource := resourcesByPath[strings.TrimSuffix(normalizedPath, "/{}")]
+	isResource = isResource && strings.HasSuffix(normalizedPath, "/{}")
+
+	switch {
+	case isCollection && method == http.MethodGet:
+		op.Responses["200"] = &OpenAPIResponse{
+			Description: "OK",
+			Content: jsonContent(&OpenAPISchemaRef{
+				Type: "object",
+				Properties: map[string]*OpenAPISchemaRef{
+					"items": {Type: "array", Items: &OpenAPISchemaRef{Ref: componentRef(collectionSchema)}},
+				},
+			}),
+		}
+	case isCollection && method == http.MethodPost:
+		op.RequestBody = jsonBody(collectionSchema)
+		op.Responses["201"] = &OpenAPIResponse{Description: "Created", Content: jsonContent(&OpenAPISchemaRef{Ref: componentRef(collectionSchema)})}
+	case isResource && method == http.MethodGet:
+		op.Responses["200"] = &OpenAPIResponse{Description: "OK", Content: jsonContent(&OpenAPISchemaRef{Ref: componentRef(resourceSchema)})}
+	case isResource && (method == http.MethodPut || method == http.MethodPatch):
+		op.RequestBody = jsonBody(resourceSchema)
+		op.Responses["200"] = &OpenAPIResponse{Description: "OK", Content: jsonContent(&OpenAPISchemaRef{Ref: componentRef(resourceSchema)})}
+	case isResource && method == http.MethodDelete:
+		op.Responses["204"] = &OpenAPIResponse{Description: "No Content"}
+	default:
+		op.Responses["200"] = &OpenAPIResponse{Description: "OK"}
+	}
+
+	return op
+}
+
+// openAPIRoutePath converts a chi route to an OpenAPI path by removing wildcards from mounted routers, trailing
+// slashes, and regular expressions from parameters
+func openAPIRoutePath(route string) string {
+	segments := []string{}
+	for _, segment := range strings.Split(route, "/") {
+		if segment == "" || segment == "*" {
+			continue
+		}
+		segments = append(segments, pathParamRegexp.ReplaceAllString(segment, "{$1}"))
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// openAPIOperationID creates a unique ID from the method and path, like "getGardensGardensIDZones"
+func openAPIOperationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool {
+		return strings.ContainsRune("/{}_-.", r)
+	}) {
+		sb.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return sb.String()
+}
+
+// openAPISchemaName converts a resource name like "water_schedule" to a schema name like "WaterSchedule"
+func openAPISchemaName(resource string) string {
+	parts := strings.Split(resource, "_")
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func componentRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+func jsonContent(schema *OpenAPISchemaRef) map[string]OpenAPIMediaType {
+	return map[string]OpenAPIMediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+func jsonBody(schemaName string) *OpenAPIBody {
+	return &OpenAPIBody{
+		Required: true,
+		Content:  jsonContent(&OpenAPISchemaRef{Ref: componentRef(schemaName)}),
+	}
+}
+
+// errorSchema describes babyapi's error responses, including the fields from ValidationErrorResponse
+func errorSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"status": {Type: "string"},
+			"error":  {Type: "string"},
+			"field":  {Type: "string"},
+			"reason": {Type: "string", Enum: []string{validation.CodeRequired, validation.CodeInvalid}},
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	// NewAPI is not used here because it can only be called once per test due to metrics registration
+	gardens := NewGardenAPI()
+	err = gardens.setup(Config{}, storageClient, nil, nil)
+	require.NoError(t, err)
+	zones := NewZonesAPI()
+	zones.setup(storageClient, nil, nil)
+	gardens.AddNestedAPI(zones)
+
+	api := babyapi.NewRootAPI("garden-app", "/")
+	api.
+		AddCustomRoute(http.MethodGet, schemaPath, babyapi.Handler(getSchema)).
+		AddCustomRoute(http.MethodGet, openAPIPath, http.HandlerFunc(openAPIHandler)).
+		AddNestedAPI(gardens)
+
+	r := httptest.NewRequest(http.MethodGet, "/openapi.json", http.NoBody)
+	w := babytest.TestRequest(t, api, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var doc OpenAPIDocument
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Contains(t, doc.Components.Schemas, "Garden")
+	assert.Contains(t, doc.Components.Schemas, "WaterSchedule")
+	assert.Contains(t, doc.Components.Schemas, "Error")
+
+	t.Run("ResourceCollection", func(t *testing.T) {
+		require.Contains(t, doc.Paths, "/gardens")
+
+		post := doc.Paths["/gardens"]["post"]
+		require.NotNil(t, post)
+		assert.Equal(t, "postGardens", post.OperationID)
+		assert.Equal(t, []string{"gardens"}, post.Tags)
+		assert.Equal(t, "#/components/schemas/Garden", post.RequestBody.Content["application/json"].Schema.Ref)
+		assert.Contains(t, post.Responses, "201")
+
+		get := doc.Paths["/gardens"]["get"]
+		require.NotNil(t, get)
+		assert.Equal(t, "#/components/schemas/Garden", get.Responses["200"].Content["application/json"].Schema.Properties["items"].Items.Ref)
+	})
+
+	t.Run("NestedResource", func(t *testing.T) {
+		path := "/gardens/{GardensID}/zones/{ZonesID}"
+		require.Contains(t, doc.Paths, path)
+
+		put := doc.Paths[path]["put"]
+		require.NotNil(t, put)
+		assert.Equal(t, "#/components/schemas/Zone", put.RequestBody.Content["application/json"].Schema.Ref)
+		require.Len(t, put.Parameters, 2)
+		assert.Equal(t, "GardensID", put.Parameters[0].Name)
+		assert.Equal(t, "ZonesID", put.Parameters[1].Name)
+
+		assert.Contains(t, doc.Paths[path]["delete"].Responses, "204")
+	})
+
+	t.Run("CustomRoutes", func(t *testing.T) {
+		assert.Contains(t, doc.Paths, "/schema")
+		assert.Contains(t, doc.Paths, "/openapi.json")
+		assert.Contains(t, doc.Paths, "/gardens/{GardensID}/revisions")
+		assert.Contains(t, doc.Paths["/gardens/{GardensID}/action"], "post")
+	})
+}
+
+func TestSwaggerUI(t *testing.T) {
+	w := httptest.NewRecorder()
+	swaggerUIHandler(w, httptest.NewRequest(http.MethodGet, "/docs", http.NoBody))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+}
+
+func TestOpenAPIRoutePath(t *testing.T) {
+	tests := []struct {
+		route    string
+		expected string
+	}{
+		{"/", "/"},
+		{"/gardens/", "/gardens"},
+		{"/gardens/{GardensID}/", "/gardens/{GardensID}"},
+		{"/photos/{PhotosID}/content", "/photos/{PhotosID}/content"},
+		{"/revisions/{revisionID:[0-9]+}/rollback", "/revisions/{revisionID}/rollback"},
+		{"/*/*/gardens/{GardensID}/", "/gardens/{GardensID}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			assert.Equal(t, tt.expected, openAPIRoutePath(tt.route))
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/photos"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+	"github.com/rs/xid"
+)
+
+const (
+	photoBasePath = "/photos"
+
+	defaultMaxPhotoSizeBytes = 10 << 20
+	// photoFormOverheadBytes allows room for the other multipart form fields when limiting the request size
+	photoFormOverheadBytes = 1 << 20
+)
+
+var errPhotosNotConfigured = &babyapi.ErrResponse{
+	HTTPStatusCode: http.StatusNotImplemented,
+	StatusText:     "Not Implemented",
+	ErrorText:      "photo storage is not configured",
+}
+
+// PhotosAPI provides an API for reading and managing Photos. Photos are uploaded with the "/photos" route of a Zone
+// or Plant, so they can't be created directly with this API
+type PhotosAPI struct {
+	*babyapi.API[*pkg.Photo]
+
+	storageClient *storage.Client
+	store         photos.Store
+	maxSizeBytes  int64
+	events        *events.Bus
+	audit         *auditLog
+}
+
+func NewPhotosAPI() *PhotosAPI {
+	api := &PhotosAPI{}
+
+	api.API = babyapi.NewAPI("Photos", photoBasePath, func() *pkg.Photo { return &pkg.Photo{} })
+	api.Post = nil
+	api.Put = nil
+
+	api.SetResponseWrapper(func(p *pkg.Photo) render.Renderer {
+		return &PhotoResponse{Photo: p}
+	})
+
+	api.SetGetAllResponseWrapper(func(ps []*pkg.Photo) render.Renderer {
+		slices.SortStableFunc(ps, func(p, q *pkg.Photo) int {
+			return p.TakenAt.Compare(*q.TakenAt)
+		})
+
+		resp := AllPhotosResponse{ResourceList: babyapi.ResourceList[*PhotoResponse]{Items: []*PhotoResponse{}}}
+		for _, p := range ps {
+			resp.Items = append(resp.Items, &PhotoResponse{Photo: p})
+		}
+		return resp
+	})
+
+	api.SetGetAllFilter(api.photoFilter)
+
+	api.AddIDMiddleware(userGardenMiddleware(api.API, func(r *http.Request, p *pkg.Photo, scope pkg.APITokenScope) bool {
+		return userGardenAllows(r.Context(), api.storageClient, p.GardenID.String(), scope)
+	}))
+
+	// The contents are removed when a Photo is deleted, but the details are kept like other end-dated resources
+	api.SetAfterDelete(func(r *http.Request) *babyapi.ErrResponse {
+		if api.store == nil {
+			return nil
+		}
+
+		err := api.store.Delete(r.Context(), api.GetIDParam(r))
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("unable to delete Photo contents: %w", err))
+		}
+
+		return nil
+	})
+
+	api.AddCustomIDRoute(http.MethodGet, "/content", babyapi.Handler(api.content))
+
+	api.ApplyExtension(conditionalRequests[*pkg.Photo]{})
+
+	return api
+}
+
+func (api *PhotosAPI) setup(cfg photos.Config, storageClient *storage.Client) error {
+	api.storageClient = storageClient
+	api.SetStorage(api.storageClient.Photos)
+
+	api.maxSizeBytes = cfg.MaxSizeBytes
+	if api.maxSizeBytes <= 0 {
+		api.maxSizeBytes = defaultMaxPhotoSizeBytes
+	}
+
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	var err error
+	api.store, err = photos.NewStore(cfg)
+	return err
+}
+
+// content responds with the contents of the Photo using its ContentType
+func (api *PhotosAPI) content(w http.ResponseWriter, r *http.Request) render.Renderer {
+	if api.store == nil {
+		return errPhotosNotConfigured
+	}
+
+	photo, httpErr := api.GetRequestedResource(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	if photo.EndDated() {
+		return babyapi.ErrNotFoundResponse
+	}
+
+	body, err := api.store.Get(r.Context(), photo.GetID())
+	if err != nil {
+		if errors.Is(err, photos.ErrNotFound) {
+			return babyapi.ErrNotFoundResponse
+		}
+		return babyapi.InternalServerError(fmt.Errorf("unable to get Photo contents: %w", err))
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", photo.ContentType)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	_, err = io.Copy(w, body)
+	if err != nil {
+		babyapi.GetLoggerFromContext(r.Context()).Error("unable to write Photo contents", "error", err)
+	}
+
+	return nil
+}
+
+// upload saves a Photo from the "photo" field of a multipart form. The optional "taken_at" field is an RFC3339
+// timestamp and defaults to the current time. The optional "caption" field describes the Photo
+func (api *PhotosAPI) upload(w http.ResponseWriter, r *http.Request, gardenID, zoneID xid.ID, plantID *xid.ID) render.Renderer {
+	if api.store == nil {
+		return errPhotosNotConfigured
+	}
+
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, api.maxSizeBytes+photoFormOverheadBytes)
+	err := r.ParseMultipartForm(api.maxSizeBytes)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid multipart form: %w", err))
+	}
+	defer func() {
+		_ = r.MultipartForm.RemoveAll()
+	}()
+
+	file, header, err := r.FormFile("photo")
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("missing required photo field: %w", err))
+	}
+	defer file.Close()
+
+	if header.Size > api.maxSizeBytes {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("photo must be at most %d bytes", api.maxSizeBytes))
+	}
+
+	// The content type is detected from the file instead of trusting the form
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return babyapi.InternalServerError(fmt.Errorf("unable to read photo: %w", err))
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	if !isImageContentType(contentType) {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("unsupported content type %q: photo must be an image", contentType))
+	}
+
+	now := time.Now()
+	takenAt := now
+	if takenAtParam := r.FormValue("taken_at"); takenAtParam != "" {
+		takenAt, err = time.Parse(time.RFC3339, takenAtParam)
+		if err != nil {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("invalid taken_at: %w", err))
+		}
+	}
+
+	photo := &pkg.Photo{
+		ID:          babyapi.NewID(),
+		GardenID:    gardenID,
+		ZoneID:      zoneID,
+		PlantID:     plantID,
+		Caption:     r.FormValue("caption"),
+		ContentType: contentType,
+		Size:        header.Size,
+		TakenAt:     &takenAt,
+		CreatedAt:   &now,
+	}
+	logger = logger.With("photo_id", photo.GetID())
+
+	logger.Info("saving Photo contents", "size", photo.Size, "content_type", contentType)
+	err = api.store.Put(r.Context(), photo.GetID(), io.MultiReader(bytes.NewReader(head), file), photo.Size, contentType)
+	if err != nil {
+		return babyapi.InternalServerError(fmt.Errorf("unable to save Photo contents: %w", err))
+	}
+
+	err = api.storageClient.Photos.Set(r.Context(), photo)
+	if err != nil {
+		// Remove the contents so they are not left behind without any details
+		deleteErr := api.store.Delete(r.Context(), photo.GetID())
+		if deleteErr != nil {
+			logger.Error("unable to delete Photo contents after error", "error", deleteErr)
+		}
+		return babyapi.InternalServerError(fmt.Errorf("unable to save Photo: %w", err))
+	}
+
+	api.events.Publish(events.Event{
+		Type: "photo.created",
+		ID:   photo.GetID(),
+		Data: photo,
+	})
+	api.audit.record(r, "photo", photo.GetID(), "created", nil)
+
+	render.Status(r, http.StatusCreated)
+	return &PhotoResponse{Photo: photo}
+}
+
+func isImageContentType(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp", "image/bmp":
+		return true
+	}
+	return false
+}
+
+// photoFilter filters Photos using the "garden_id", "zone_id", and "plant_id" query parameters. Requests made as a
+// User only include Photos for Gardens that the User can read
+func (api *PhotosAPI) photoFilter(r *http.Request) babyapi.FilterFunc[*pkg.Photo] {
+	gardenID := r.URL.Query().Get("garden_id")
+	zoneID := r.URL.Query().Get("zone_id")
+	plantID := r.URL.Query().Get("plant_id")
+
+	return func(p *pkg.Photo) bool {
+		if gardenID != "" && p.GardenID.String() != gardenID {
+			return false
+		}
+		if zoneID != "" && p.ZoneID.String() != zoneID {
+			return false
+		}
+		if plantID != "" && (p.PlantID == nil || p.PlantID.String() != plantID) {
+			return false
+		}
+		return userCanReadGarden(r.Context(), api.storageClient, p.GardenID.String())
+	}
+}
+
+// PhotoResponse is used to represent a Photo in the response body with hypermedia Links
+type PhotoResponse struct {
+	*pkg.Photo
+
+	Links []Link `json:"links,omitempty"`
+}
+
+// Render ...
+func (resp *PhotoResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	if resp == nil {
+		return nil
+	}
+
+	photoPath := fmt.Sprintf("%s/%s", photoBasePath, resp.ID)
+	gardenPath := fmt.Sprintf("%s/%s", gardenBasePath, resp.GardenID)
+	zonePath := fmt.Sprintf("%s%s/%s", gardenPath, zoneBasePath, resp.ZoneID)
+	resp.Links = append(resp.Links,
+		Link{
+			"self",
+			photoPath,
+		},
+		Link{
+			"content",
+			photoPath + "/content",
+		},
+		Link{
+			"garden",
+			gardenPath,
+		},
+		Link{
+			"zone",
+			zonePath,
+		},
+	)
+	if resp.PlantID != nil {
+		resp.Links = append(resp.Links, Link{
+			"plant",
+			fmt.Sprintf("%s%s/%s", zonePath, plantBasePath, resp.PlantID),
+		})
+	}
+
+	return nil
+}
+
+// AllPhotosResponse is a list of Photos sorted by when they were taken, which is used as a growth timeline
+type AllPhotosResponse struct {
+	babyapi.ResourceList[*PhotoResponse]
+}
+
+func (resp AllPhotosResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return resp.ResourceList.Render(w, r)
+}
+
+func (resp AllPhotosResponse) HTML(r *http.Request) string {
+	return photoTimelineTemplate.Render(r, resp)
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/photos"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func photosConfig(t *testing.T) photos.Config {
+	t.Helper()
+	return photos.Config{
+		Driver:  "disk",
+		Options: map[string]interface{}{"directory": t.TempDir()},
+	}
+}
+
+func newPhotoUploadRequest(t *testing.T, target string, data []byte, fields map[string]string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if data != nil {
+		fw, err := mw.CreateFormFile("photo", "photo.png")
+		require.NoError(t, err)
+		_, err = fw.Write(data)
+		require.NoError(t, err)
+	}
+	for k, v := range fields {
+		err := mw.WriteField(k, v)
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+
+	r := httptest.NewRequest(http.MethodPost, target, &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestUploadPlantPhoto(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            func(*testing.T) photos.Config
+		data           []byte
+		fields         map[string]string
+		expectedRegexp string
+		code           int
+	}{
+		{
+			"Successful",
+			photosConfig,
+			pngHeader,
+			map[string]string{"caption": "first flowers", "taken_at": "2024-05-01T09:00:00Z"},
+			`{"id":"[0-9a-v]{20}","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"c5cvhpcbcv45e8bp16dg","plant_id":"c5cvhpcbcv45e8bp16dg","caption":"first flowers","content_type":"image/png","size":16,"taken_at":"2024-05-01T09:00:00Z","created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","links":\[{"rel":"self","href":"/photos/[0-9a-v]{20}"},{"rel":"content","href":"/photos/[0-9a-v]{20}/content"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"zone","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg"},{"rel":"plant","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/plants/c5cvhpcbcv45e8bp16dg"}\]}`,
+			http.StatusCreated,
+		},
+		{
+			"ErrorNotImage",
+			photosConfig,
+			[]byte("just some text"),
+			nil,
+			`{"status":"Invalid request.","error":"unsupported content type \\"text/plain; charset=utf-8\\": photo must be an image"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorMissingPhoto",
+			photosConfig,
+			nil,
+			map[string]string{"caption": "first flowers"},
+			`{"status":"Invalid request.","error":"missing required photo field: http: no such file"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorInvalidTakenAt",
+			photosConfig,
+			pngHeader,
+			map[string]string{"taken_at": "yesterday"},
+			`{"status":"Invalid request.","error":"invalid taken_at: .*"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorNotConfigured",
+			func(*testing.T) photos.Config { return photos.Config{} },
+			pngHeader,
+			nil,
+			`{"status":"Not Implemented","error":"photo storage is not configured"}`,
+			http.StatusNotImplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+			plant := createExamplePlant()
+			err := storageClient.Plants.Set(context.Background(), plant)
+			require.NoError(t, err)
+
+			photosAPI := NewPhotosAPI()
+			err = photosAPI.setup(tt.cfg(t), storageClient)
+			require.NoError(t, err)
+
+			api := NewPlantsAPI()
+			api.setup(storageClient)
+			api.photos = photosAPI
+
+			r := newPhotoUploadRequest(t, fmt.Sprintf("/zones/%s/plants/%s/photos", id, plant.ID), tt.data, tt.fields)
+			w := babytest.TestWithParentRoute[*pkg.Plant, *pkg.Zone](t, api.API, createExampleZone(), "Zones", "/zones", r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestUploadZonePhotoAndGetContent(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+
+	photosAPI := NewPhotosAPI()
+	err := photosAPI.setup(photosConfig(t), storageClient)
+	require.NoError(t, err)
+
+	zonesAPI := NewZonesAPI()
+	zonesAPI.setup(storageClient, nil, nil)
+	zonesAPI.photos = photosAPI
+
+	r := newPhotoUploadRequest(t, fmt.Sprintf("/gardens/%s/zones/%s/photos", id, id), pngHeader, nil)
+	w := babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, zonesAPI.API, createExampleGarden(), "Gardens", "/gardens", r)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var photo pkg.Photo
+	err = json.Unmarshal(w.Body.Bytes(), &photo)
+	require.NoError(t, err)
+	assert.Nil(t, photo.PlantID)
+
+	r = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/photos/%s/content", photo.ID), http.NoBody)
+	w = babytest.TestRequest[*pkg.Photo](t, photosAPI.API, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, pngHeader, w.Body.Bytes())
+
+	r = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/photos/%s", photo.ID), http.NoBody)
+	w = babytest.TestRequest[*pkg.Photo](t, photosAPI.API, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	r = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/photos/%s/content", photo.ID), http.NoBody)
+	w = babytest.TestRequest[*pkg.Photo](t, photosAPI.API, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetAllPhotos(t *testing.T) {
+	plantID := xid.New()
+	first := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(14 * 24 * time.Hour)
+	third := second.Add(14 * 24 * time.Hour)
+
+	photoIDs := []xid.ID{xid.New(), xid.New(), xid.New()}
+	ps := []*pkg.Photo{
+		{ID: babyapi.ID{ID: photoIDs[0]}, GardenID: id, ZoneID: id, PlantID: &plantID, TakenAt: &third},
+		{ID: babyapi.ID{ID: photoIDs[1]}, GardenID: id, ZoneID: id, TakenAt: &first},
+		{ID: babyapi.ID{ID: photoIDs[2]}, GardenID: id, ZoneID: id, PlantID: &plantID, TakenAt: &second},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []xid.ID
+	}{
+		{"AllSortedByTakenAt", "", []xid.ID{photoIDs[1], photoIDs[2], photoIDs[0]}},
+		{"FilterZone", "?zone_id=" + id.String(), []xid.ID{photoIDs[1], photoIDs[2], photoIDs[0]}},
+		{"FilterPlant", "?plant_id=" + plantID.String(), []xid.ID{photoIDs[2], photoIDs[0]}},
+		{"FilterOtherGarden", "?garden_id=" + id2.String(), []xid.ID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+			for _, p := range ps {
+				err := storageClient.Photos.Set(context.Background(), p)
+				require.NoError(t, err)
+			}
+
+			api := NewPhotosAPI()
+			err := api.setup(photos.Config{}, storageClient)
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodGet, "/photos"+tt.query, http.NoBody)
+			w := babytest.TestRequest[*pkg.Photo](t, api.API, r)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var resp AllPhotosResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+
+			ids := []xid.ID{}
+			for _, item := range resp.Items {
+				ids = append(ids, item.ID.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestPhotosUserGardens(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+
+	user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+	viewer := &pkg.User{ID: babyapi.NewID(), Name: "Alex", Role: pkg.UserRoleOwner}
+
+	garden, err := storageClient.Gardens.Get(context.Background(), id.String())
+	require.NoError(t, err)
+	garden.Users = map[string]pkg.UserRole{viewer.GetID(): pkg.UserRoleViewer}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+
+	otherGarden := createExampleGarden()
+	otherGarden.ID = babyapi.ID{ID: id2}
+	otherGarden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleOwner}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), otherGarden))
+
+	api := NewPhotosAPI()
+	err = api.setup(photosConfig(t), storageClient)
+	require.NoError(t, err)
+
+	takenAt := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	photo := &pkg.Photo{ID: babyapi.NewID(), GardenID: id, ZoneID: id, ContentType: "image/png", Size: int64(len(pngHeader)), TakenAt: &takenAt}
+	require.NoError(t, storageClient.Photos.Set(context.Background(), photo))
+	require.NoError(t, api.store.Put(context.Background(), photo.GetID(), bytes.NewReader(pngHeader), photo.Size, photo.ContentType))
+
+	photoPath := "/photos/" + photo.GetID()
+	tests := []struct {
+		name         string
+		user         *pkg.User
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{"GetAll", user, http.MethodGet, "/photos", http.StatusOK},
+		{"GetNotFound", user, http.MethodGet, photoPath, http.StatusNotFound},
+		{"ContentNotFound", user, http.MethodGet, photoPath + "/content", http.StatusNotFound},
+		{"DeleteNotFound", user, http.MethodDelete, photoPath, http.StatusNotFound},
+		{"ViewerCanGetContent", viewer, http.MethodGet, photoPath + "/content", http.StatusOK},
+		{"ViewerCannotDelete", viewer, http.MethodDelete, photoPath, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			r = r.WithContext(withUser(r.Context(), tt.user))
+			w := babytest.TestRequest[*pkg.Photo](t, api.API, r)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			// The Photo is never included for a User without a role for its Garden
+			if tt.user == user {
+				assert.NotContains(t, w.Body.String(), photo.GetID())
+			}
+		})
+	}
+
+	stored, err := storageClient.Photos.Get(context.Background(), photo.GetID())
+	require.NoError(t, err)
+	assert.False(t, stored.EndDated())
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+	"github.com/rs/xid"
+)
+
+const (
+	plantBasePath = "/plants"
+)
+
+// PlantsAPI encapsulates the structs and dependencies necessary for the "/plants" API to function
+type PlantsAPI struct {
+	*babyapi.API[*pkg.Plant]
+
+	storageClient *storage.Client
+	photos        *PhotosAPI
+}
+
+// NewPlantsAPI creates a new PlantsAPI. It is nested under the Zones API
+func NewPlantsAPI() *PlantsAPI {
+	api := &PlantsAPI{}
+
+	api.API = babyapi.NewAPI("Plants", plantBasePath, func() *pkg.Plant { return &pkg.Plant{} })
+
+	api.SetResponseWrapper(func(p *pkg.Plant) render.Renderer {
+		return &PlantResponse{Plant: p}
+	})
+
+	api.SetOnCreateOrUpdate(api.onCreateOrUpdate)
+
+	api.SetGetAllFilter(func(r *http.Request) babyapi.FilterFunc[*pkg.Plant] {
+		zoneID := api.GetParentIDParam(r)
+		return func(p *pkg.Plant) bool {
+			return p.ZoneID.String() == zoneID
+		}
+	})
+
+	api.AddCustomIDRoute(http.MethodPost, photoBasePath, babyapi.Handler(api.uploadPhoto))
+
+	api.ApplyExtension(conditionalRequests[*pkg.Plant]{})
+
+	return api
+}
+
+func (api *PlantsAPI) setup(storageClient *storage.Client) {
+	api.storageClient = storageClient
+
+	api.SetStorage(api.storageClient.Plants)
+}
+
+func (api *PlantsAPI) getZoneFromRequest(r *http.Request) (*pkg.Zone, *babyapi.ErrResponse) {
+	zone, err := babyapi.GetResourceFromContext[*pkg.Zone](r.Context(), api.ParentContextKey())
+	if err != nil {
+		if errors.Is(err, babyapi.ErrNotFound) {
+			return nil, babyapi.ErrNotFoundResponse
+		}
+		err = fmt.Errorf("error getting Zone %q for Plant: %w", api.GetParentIDParam(r), err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return zone, nil
+}
+
+// uploadPhoto saves a Photo for the Plant
+func (api *PlantsAPI) uploadPhoto(w http.ResponseWriter, r *http.Request) render.Renderer {
+	plant, httpErr := api.GetRequestedResource(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	if plant.EndDated() {
+		return babyapi.ErrInvalidRequest(errors.New("unable to add Photo to end-dated Plant"))
+	}
+
+	return api.photos.upload(w, r, plant.GardenID, plant.ZoneID, &plant.ID.ID)
+}
+
+// onCreateOrUpdate sets the Plant's ZoneID and GardenID from the Zone in the URL path
+func (api *PlantsAPI) onCreateOrUpdate(r *http.Request, p *pkg.Plant) *babyapi.ErrResponse {
+	zoneID := api.GetParentIDParam(r)
+	if !p.ZoneID.IsNil() && zoneID != p.ZoneID.String() {
+		return babyapi.ErrInvalidRequest(errors.New("zone_id for Plant must match URL path"))
+	}
+
+	zone, httpErr := api.getZoneFromRequest(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	if zone.EndDated() {
+		return babyapi.ErrInvalidRequest(errors.New("unable to add Plant to end-dated Zone"))
+	}
+	if !p.GardenID.IsNil() && p.GardenID != zone.GardenID {
+		return babyapi.ErrInvalidRequest(errors.New("garden_id for Plant must match URL path"))
+	}
+
+	var err error
+	p.ZoneID, err = xid.FromString(zoneID)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid ZoneID: %w", err))
+	}
+	p.GardenID = zone.GardenID
+
+	// Bind only sees the fields in the request, so the dates are checked again after a PATCH is applied
+	if p.PlantingDate != nil && p.ExpectedHarvestDate != nil && p.ExpectedHarvestDate.Before(*p.PlantingDate) {
+		return babyapi.ErrInvalidRequest(errors.New("expected_harvest_date must be after planting_date"))
+	}
+
+	return nil
+}
+
+// PlantResponse is used to represent a Plant in the response body with hypermedia Links. It also includes the number
+// of days until the Plant is expected to be ready to harvest, which is useful for reminders
+type PlantResponse struct {
+	*pkg.Plant
+
+	DaysUntilHarvest *int `json:"days_until_harvest,omitempty"`
+
+	Links []Link `json:"links,omitempty"`
+}
+
+// Render ...
+func (resp *PlantResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	if resp == nil {
+		return nil
+	}
+
+	if resp.ExpectedHarvestDate != nil && !resp.EndDated() {
+		days := int(math.Ceil(time.Until(*resp.ExpectedHarvestDate).Hours() / 24))
+		resp.DaysUntilHarvest = &days
+	}
+
+	gardenPath := fmt.Sprintf("%s/%s", gardenBasePath, resp.GardenID)
+	zonePath := fmt.Sprintf("%s%s/%s", gardenPath, zoneBasePath, resp.ZoneID)
+	resp.Links = append(resp.Links,
+		Link{
+			"self",
+			fmt.Sprintf("%s%s/%s", zonePath, plantBasePath, resp.ID),
+		},
+		Link{
+			"garden",
+			gardenPath,
+		},
+		Link{
+			"zone",
+			zonePath,
+		},
+	)
+
+	return nil
+}
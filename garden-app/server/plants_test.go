@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createExamplePlant() *pkg.Plant {
+	plantingDate := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	return &pkg.Plant{
+		ID:           babyapi.ID{ID: id},
+		Name:         "test-plant",
+		GardenID:     id,
+		ZoneID:       id,
+		Species:      "Solanum lycopersicum",
+		PlantingDate: &plantingDate,
+		CreatedAt:    &createdAt,
+	}
+}
+
+func TestCreatePlant(t *testing.T) {
+	endDatedZone := createExampleZone()
+	endDate := createdAt
+	endDatedZone.EndDate = &endDate
+
+	tests := []struct {
+		name           string
+		zone           *pkg.Zone
+		body           string
+		expectedRegexp string
+		code           int
+	}{
+		{
+			"Successful",
+			createExampleZone(),
+			`{"name":"tomato","species":"Solanum lycopersicum","planting_date":"2024-03-01T00:00:00Z","expected_harvest_date":"2099-05-10T00:00:00Z","notes":"planted from seed"}`,
+			`{"id":"[0-9a-v]{20}","name":"tomato","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"c5cvhpcbcv45e8bp16dg","species":"Solanum lycopersicum","planting_date":"2024-03-01T00:00:00Z","expected_harvest_date":"2099-05-10T00:00:00Z","notes":"planted from seed","created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","days_until_harvest":\d+,"links":\[{"rel":"self","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/plants/[0-9a-v]{20}"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"zone","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg"}\]}`,
+			http.StatusCreated,
+		},
+		{
+			"ErrorMissingName",
+			createExampleZone(),
+			`{"species":"Solanum lycopersicum"}`,
+			`{"status":"Invalid request.","error":"missing required name field"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorHarvestBeforePlanting",
+			createExampleZone(),
+			`{"name":"tomato","planting_date":"2024-03-01T00:00:00Z","expected_harvest_date":"2024-01-01T00:00:00Z"}`,
+			`{"status":"Invalid request.","error":"expected_harvest_date must be after planting_date"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorWrongZoneID",
+			createExampleZone(),
+			`{"name":"tomato","zone_id":"chkodpg3lcj13q82mq40"}`,
+			`{"status":"Invalid request.","error":"zone_id for Plant must match URL path"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorEndDatedZone",
+			endDatedZone,
+			`{"name":"tomato"}`,
+			`{"status":"Invalid request.","error":"unable to add Plant to end-dated Zone"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+
+			api := NewPlantsAPI()
+			api.setup(storageClient)
+
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/zones/%s/plants", tt.zone.ID), strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			w := babytest.TestWithParentRoute[*pkg.Plant, *pkg.Zone](t, api.API, tt.zone, "Zones", "/zones", r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestUpdatePlant(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+		code     int
+	}{
+		{
+			"SuccessfulNotes",
+			`{"notes":"pruned suckers"}`,
+			`{"id":"c5cvhpcbcv45e8bp16dg","name":"test-plant","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"c5cvhpcbcv45e8bp16dg","species":"Solanum lycopersicum","planting_date":"2024-03-01T00:00:00Z","notes":"pruned suckers","created_at":"2021-10-03T11:24:52.891386-07:00","links":[{"rel":"self","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/plants/c5cvhpcbcv45e8bp16dg"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"zone","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg"}]}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorHarvestBeforePlanting",
+			`{"expected_harvest_date":"2024-01-01T00:00:00Z"}`,
+			`{"status":"Invalid request.","error":"expected_harvest_date must be after planting_date"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorChangeZoneID",
+			`{"zone_id":"chkodpg3lcj13q82mq40"}`,
+			`{"status":"Invalid request.","error":"unable to change ZoneID"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+
+			plant := createExamplePlant()
+			err := storageClient.Plants.Set(context.Background(), plant)
+			require.NoError(t, err)
+
+			api := NewPlantsAPI()
+			api.setup(storageClient)
+
+			zone := createExampleZone()
+			r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/zones/%s/plants/%s", zone.ID, plant.ID), strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			w := babytest.TestWithParentRoute[*pkg.Plant, *pkg.Zone](t, api.API, zone, "Zones", "/zones", r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.expected, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestGetAllPlants(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+
+	plant := createExamplePlant()
+	err := storageClient.Plants.Set(context.Background(), plant)
+	require.NoError(t, err)
+
+	otherZonePlant := createExamplePlant()
+	otherZonePlant.ID = babyapi.ID{ID: id2}
+	otherZonePlant.ZoneID = id2
+	err = storageClient.Plants.Set(context.Background(), otherZonePlant)
+	require.NoError(t, err)
+
+	api := NewPlantsAPI()
+	api.setup(storageClient)
+
+	zone := createExampleZone()
+	r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/zones/%s/plants", zone.ID), http.NoBody)
+	w := babytest.TestWithParentRoute[*pkg.Plant, *pkg.Zone](t, api.API, zone, "Zones", "/zones", r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"items":[{"id":"c5cvhpcbcv45e8bp16dg","name":"test-plant","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"c5cvhpcbcv45e8bp16dg","species":"Solanum lycopersicum","planting_date":"2024-03-01T00:00:00Z","created_at":"2021-10-03T11:24:52.891386-07:00","links":[{"rel":"self","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/plants/c5cvhpcbcv45e8bp16dg"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"zone","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg"}]}]}`, strings.TrimSpace(w.Body.String()))
+}
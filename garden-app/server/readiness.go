@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+	"github.com/rs/xid"
+)
+
+const (
+	healthzPath = "/healthz"
+	readyzPath  = "/readyz"
+
+	// readinessTimeout limits how long the storage check can take so probes do not time out first
+	readinessTimeout = 2 * time.Second
+)
+
+// readiness checks the dependencies that are needed to handle requests. It is set by Setup once everything is
+// started, so the server is not ready before then
+type readiness struct {
+	storageClient *storage.Client
+	mqttClient    mqtt.Client
+	worker        *worker.Worker
+}
+
+// ReadinessCheck is the result of checking one dependency
+type ReadinessCheck struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Details string `json:"details,omitempty"`
+}
+
+// ReadinessResponse is used for the /healthz and /readyz endpoints. Checks are only included for /readyz
+type ReadinessResponse struct {
+	Status string           `json:"status"`
+	Checks []ReadinessCheck `json:"checks,omitempty"`
+}
+
+// Render ...
+func (resp *ReadinessResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// healthzHandler responds successfully as long as the process is able to handle requests
+func healthzHandler(_ http.ResponseWriter, _ *http.Request) render.Renderer {
+	return &ReadinessResponse{Status: "ok"}
+}
+
+// readyzHandler responds successfully if storage is reachable, the MQTT client is connected, and the scheduler is
+// running. Otherwise, it responds with 503 Service Unavailable so traffic is not routed to the server
+func (api *API) readyzHandler(_ http.ResponseWriter, r *http.Request) render.Renderer {
+	checks := []ReadinessCheck{{
+		Name:    "setup",
+		Ready:   false,
+		Details: "server is starting",
+	}}
+	if rd := api.readiness.Load(); rd != nil {
+		checks = rd.check(r.Context())
+	}
+
+	resp := &ReadinessResponse{Status: "ok", Checks: checks}
+	for _, c := range checks {
+		if !c.Ready {
+			resp.Status = "unavailable"
+			render.Status(r, http.StatusServiceUnavailable)
+			break
+		}
+	}
+
+	return resp
+}
+
+// check runs all readiness checks
+func (rd *readiness) check(ctx context.Context) []ReadinessCheck {
+	return []ReadinessCheck{
+		rd.checkStorage(ctx),
+		rd.checkMQTT(),
+		rd.checkScheduler(),
+	}
+}
+
+// checkStorage gets a Garden that does not exist, which only fails if storage can't be reached
+func (rd *readiness) checkStorage(ctx context.Context) ReadinessCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	_, err := rd.storageClient.Gardens.Get(ctx, xid.NilID().String())
+	if err != nil && !errors.Is(err, babyapi.ErrNotFound) {
+		return ReadinessCheck{"storage", false, fmt.Sprintf("unable to reach storage: %v", err)}
+	}
+	return ReadinessCheck{"storage", true, ""}
+}
+
+// checkMQTT uses the client's connection status. Clients that can't report it are assumed to be connected
+func (rd *readiness) checkMQTT() ReadinessCheck {
+	checker, ok := rd.mqttClient.(mqtt.ConnectionChecker)
+	if ok && !checker.IsConnected() {
+		return ReadinessCheck{"mqtt", false, "not connected to broker"}
+	}
+	return ReadinessCheck{"mqtt", true, ""}
+}
+
+func (rd *readiness) checkScheduler() ReadinessCheck {
+	if !rd.worker.IsRunning() {
+		return ReadinessCheck{"scheduler", false, "scheduler is not running"}
+	}
+	return ReadinessCheck{"scheduler", true, ""}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthz(t *testing.T) {
+	w := httptest.NewRecorder()
+	babyapi.Handler(healthzHandler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, healthzPath, http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, strings.TrimSpace(w.Body.String()))
+}
+
+func TestReadyz(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	mqttClient := mqtt.NewInMemoryClient(mqtt.Config{}, nil)
+
+	tests := []struct {
+		name           string
+		setup          func(*API, *worker.Worker)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			"NotSetup",
+			func(*API, *worker.Worker) {},
+			http.StatusServiceUnavailable,
+			`{"status":"unavailable","checks":[{"name":"setup","ready":false,"details":"server is starting"}]}`,
+		},
+		{
+			"SchedulerNotRunning",
+			func(api *API, w *worker.Worker) {
+				api.readiness.Store(&readiness{storageClient, mqttClient, w})
+			},
+			http.StatusServiceUnavailable,
+			`{"status":"unavailable","checks":[{"name":"storage","ready":true},{"name":"mqtt","ready":true},{"name":"scheduler","ready":false,"details":"scheduler is not running"}]}`,
+		},
+		{
+			"Ready",
+			func(api *API, w *worker.Worker) {
+				w.StartAsync()
+				api.readiness.Store(&readiness{storageClient, mqttClient, w})
+			},
+			http.StatusOK,
+			`{"status":"ok","checks":[{"name":"storage","ready":true},{"name":"mqtt","ready":true},{"name":"scheduler","ready":true}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wkr := worker.NewWorker(storageClient, nil, mqttClient, slog.Default())
+			defer wkr.Stop()
+
+			api := &API{}
+			tt.setup(api, wkr)
+
+			w := httptest.NewRecorder()
+			babyapi.Handler(api.readyzHandler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, readyzPath, http.NoBody))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
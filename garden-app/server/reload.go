@@ -0,0 +1,220 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sync"
+	"syscall"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const reloadPath = "/admin/reload"
+
+var errReloadNotConfigured = &babyapi.ErrResponse{
+	HTTPStatusCode: http.StatusNotImplemented,
+	StatusText:     "Not Implemented",
+	ErrorText:      "config reload is not configured",
+}
+
+// ConfigLoader reads the config again, like from the file that the server was started with
+type ConfigLoader func() (Config, error)
+
+// SetConfigLoader enables reloading the config with SIGHUP or the /admin/reload endpoint. This must be used before
+// Setup
+func (api *API) SetConfigLoader(load ConfigLoader) {
+	api.loadConfig = load
+}
+
+// configReloader applies config changes that do not require restarting the server. The scheduler and HTTP server
+// keep running, so scheduled Jobs and in-flight requests are not interrupted. Other changes are reported, but
+// they are not applied until the server is restarted
+type configReloader struct {
+	mu      sync.Mutex
+	load    ConfigLoader
+	current Config
+	api     *API
+	worker  *worker.Worker
+	logger  *slog.Logger
+}
+
+// ReloadResponse lists the config settings that were changed. RestartRequired has changed settings that are not
+// applied until the server is restarted
+type ReloadResponse struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required,omitempty"`
+}
+
+// Render ...
+func (resp *ReloadResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// reloadHandler reloads the config and responds with the settings that changed
+func (api *API) reloadHandler(_ http.ResponseWriter, r *http.Request) render.Renderer {
+	if api.reloader == nil {
+		return errReloadNotConfigured
+	}
+
+	resp, err := api.reloader.reload()
+	if err != nil {
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	api.audit.record(r, "config", "", "reloaded", nil)
+	return resp
+}
+
+// watchSignals reloads the config each time the process receives SIGHUP
+func (rl *configReloader) watchSignals(done <-chan struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-signals:
+			rl.logger.Info("received SIGHUP")
+			_, err := rl.reload()
+			if err != nil {
+				rl.logger.Error("unable to reload config", "error", err)
+			}
+		}
+	}
+}
+
+// reload reads the config and applies the settings that changed. Nothing is applied if the new config is invalid
+func (rl *configReloader) reload() (*ReloadResponse, error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	cfg, err := rl.load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+
+	err = cfg.LogConfig.validateLevels()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, bw := range cfg.BlackoutWindows {
+		err = bw.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid blackout_windows[%d]: %w", i, err)
+		}
+	}
+
+	resp := &ReloadResponse{Applied: []string{}}
+
+	levelChanged := cfg.LogConfig.Level != rl.current.LogConfig.Level
+	levelsChanged := !maps.Equal(cfg.LogConfig.Levels, rl.current.LogConfig.Levels)
+	if levelChanged || levelsChanged {
+		cfg.LogConfig.setLevels()
+		rl.current.LogConfig.Level = cfg.LogConfig.Level
+		rl.current.LogConfig.Levels = cfg.LogConfig.Levels
+	}
+	if levelChanged {
+		resp.Applied = append(resp.Applied, "log.level")
+	}
+	if levelsChanged {
+		resp.Applied = append(resp.Applied, "log.levels")
+	}
+
+	if !slices.Equal(cfg.AllowedOrigins, rl.current.AllowedOrigins) {
+		rl.api.allowedOrigins.Store(&cfg.AllowedOrigins)
+		rl.current.AllowedOrigins = cfg.AllowedOrigins
+		resp.Applied = append(resp.Applied, "web_server.allowed_origins")
+	}
+
+	if cfg.Health.DownThreshold != rl.current.Health.DownThreshold {
+		threshold := cfg.Health.DownThreshold
+		if threshold <= 0 {
+			threshold = pkg.DefaultHealthThreshold
+		}
+		rl.worker.SetHealthThreshold(threshold)
+		rl.current.Health = cfg.Health
+		resp.Applied = append(resp.Applied, "health.down_threshold")
+	}
+
+	if !reflect.DeepEqual(cfg.BlackoutWindows, rl.current.BlackoutWindows) {
+		rl.worker.SetBlackoutWindows(cfg.BlackoutWindows)
+		rl.current.BlackoutWindows = cfg.BlackoutWindows
+		resp.Applied = append(resp.Applied, "blackout_windows")
+	}
+
+	// Enabling or disabling leak detection changes the scheduled Jobs, so only the thresholds are applied
+	leakDetection := cfg.LeakDetection
+	leakDetection.Enabled = rl.current.LeakDetection.Enabled
+	if !reflect.DeepEqual(leakDetection, rl.current.LeakDetection) {
+		rl.worker.SetLeakDetection(leakDetection)
+		rl.current.LeakDetection = leakDetection
+		resp.Applied = append(resp.Applied, "leak_detection")
+	}
+
+	if cfg.WaterAck != rl.current.WaterAck {
+		rl.worker.SetWaterAck(cfg.WaterAck)
+		rl.current.WaterAck = cfg.WaterAck
+		resp.Applied = append(resp.Applied, "water_ack")
+	}
+
+	if cfg.WeatherRetry != rl.current.WeatherRetry {
+		rl.worker.SetWeatherRetry(cfg.WeatherRetry)
+		rl.current.WeatherRetry = cfg.WeatherRetry
+		resp.Applied = append(resp.Applied, "weather_retry")
+	}
+
+	if cfg.ManualWatering != rl.current.ManualWatering {
+		rl.worker.SetManualWatering(cfg.ManualWatering)
+		rl.current.ManualWatering = cfg.ManualWatering
+		resp.Applied = append(resp.Applied, "manual_watering")
+	}
+
+	restartRequired := []struct {
+		name     string
+		old, new any
+	}{
+		{"web_server.port", rl.current.Port, cfg.Port},
+		{"web_server.readonly", rl.current.ReadOnly, cfg.ReadOnly},
+		{"web_server.auth", rl.current.Auth, cfg.Auth},
+		{"web_server.oidc", rl.current.OIDC, cfg.OIDC},
+		{"log.format", rl.current.LogConfig.Format, cfg.LogConfig.Format},
+		{"log.file", rl.current.LogConfig.File, cfg.LogConfig.File},
+		{"leak_detection.enabled", rl.current.LeakDetection.Enabled, cfg.LeakDetection.Enabled},
+		{"influxdb", rl.current.InfluxDBConfig, cfg.InfluxDBConfig},
+		{"metrics", rl.current.MetricsConfig, cfg.MetricsConfig},
+		{"mqtt", rl.current.MQTTConfig, cfg.MQTTConfig},
+		{"storage", rl.current.StorageConfig, cfg.StorageConfig},
+		{"simulation", rl.current.Simulation, cfg.Simulation},
+		{"tracing", rl.current.Tracing, cfg.Tracing},
+		{"catch_up", rl.current.CatchUp, cfg.CatchUp},
+		{"grpc", rl.current.GRPC, cfg.GRPC},
+		{"photos", rl.current.Photos, cfg.Photos},
+		{"backup", rl.current.Backup, cfg.Backup},
+		{"firmware", rl.current.Firmware, cfg.Firmware},
+		{"declarative", rl.current.Declarative, cfg.Declarative},
+	}
+	for _, setting := range restartRequired {
+		if !reflect.DeepEqual(setting.old, setting.new) {
+			resp.RestartRequired = append(resp.RestartRequired, setting.name)
+		}
+	}
+
+	rl.logger.Info("reloaded config", "applied", resp.Applied)
+	if len(resp.RestartRequired) > 0 {
+		rl.logger.Warn("some config changes require a restart", "settings", resp.RestartRequired)
+	}
+
+	return resp, nil
+}
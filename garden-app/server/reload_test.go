@@ -0,0 +1,178 @@
+package server
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigReload(t *testing.T) {
+	t.Cleanup(func() { LogConfig{}.setLevels() })
+
+	current := Config{
+		LogConfig: LogConfig{Level: "info"},
+		MQTTConfig: mqtt.Config{
+			Broker: "localhost",
+			Port:   1883,
+		},
+		LeakDetection: worker.LeakDetectionConfig{Enabled: true},
+	}
+
+	tests := []struct {
+		name            string
+		update          func(*Config)
+		expected        *ReloadResponse
+		expectedErr     string
+		expectedBlocked bool
+	}{
+		{
+			"NoChanges",
+			func(*Config) {},
+			&ReloadResponse{Applied: []string{}},
+			"",
+			false,
+		},
+		{
+			"AppliedChanges",
+			func(c *Config) {
+				c.LogConfig.Level = "debug"
+				c.LogConfig.Levels = map[string]string{"mqtt": "warn"}
+				c.AllowedOrigins = []string{"https://dashboard.example.com"}
+				c.Health.DownThreshold = 10 * time.Minute
+				c.BlackoutWindows = []pkg.BlackoutWindow{{StartTime: "00:00", EndTime: "23:59"}}
+				c.LeakDetection.MoistureRise = 10
+			},
+			&ReloadResponse{Applied: []string{"log.level", "log.levels", "web_server.allowed_origins", "health.down_threshold", "blackout_windows", "leak_detection"}},
+			"",
+			true,
+		},
+		{
+			"RestartRequired",
+			func(c *Config) {
+				c.MQTTConfig.Broker = "mqtt.example.com"
+				c.LogConfig.File.Path = "/var/log/garden-app.log"
+				c.LeakDetection.Enabled = false
+				c.Port = 8080
+			},
+			&ReloadResponse{Applied: []string{}, RestartRequired: []string{"web_server.port", "log.file", "leak_detection.enabled", "mqtt"}},
+			"",
+			false,
+		},
+		{
+			"InvalidLogLevels",
+			func(c *Config) {
+				c.LogConfig.Level = "debug"
+				c.LogConfig.Levels = map[string]string{"weather": "debug"}
+			},
+			nil,
+			`invalid log.levels subsystem "weather": must be one of [server worker mqtt storage]`,
+			false,
+		},
+		{
+			"InvalidBlackoutWindow",
+			func(c *Config) {
+				c.LogConfig.Level = "debug"
+				c.BlackoutWindows = []pkg.BlackoutWindow{{StartTime: "25:00", EndTime: "06:00"}}
+			},
+			nil,
+			`invalid blackout_windows[0]: invalid time "25:00": must use HH:MM format`,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current.LogConfig.setLevels()
+
+			cfg := current
+			tt.update(&cfg)
+
+			wkr := worker.NewWorker(nil, nil, nil, slog.Default())
+			api := &API{}
+			rl := &configReloader{
+				load:    func() (Config, error) { return cfg, nil },
+				current: current,
+				api:     api,
+				worker:  wkr,
+				logger:  slog.Default(),
+			}
+
+			resp, err := rl.reload()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Equal(t, slog.LevelInfo, logLevel.Level())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp)
+
+			assert.Equal(t, cfg.LogConfig.GetLogLevel(), logLevel.Level())
+			assert.Equal(t, cfg.LogConfig.GetSubsystemLogLevel(LogSubsystemMQTT), subsystemLogLevels[LogSubsystemMQTT].Level())
+			assert.Equal(t, cfg.AllowedOrigins, api.getAllowedOrigins())
+
+			_, blocked := wkr.BlackoutEnd(&pkg.Garden{}, time.Date(2023, time.August, 1, 12, 0, 0, 0, time.Local))
+			assert.Equal(t, tt.expectedBlocked, blocked)
+
+			// Reloading again only reports the settings that still require a restart
+			resp, err = rl.reload()
+			require.NoError(t, err)
+			assert.Empty(t, resp.Applied)
+			assert.Equal(t, tt.expected.RestartRequired, resp.RestartRequired)
+		})
+	}
+}
+
+func TestReloadHandler(t *testing.T) {
+	t.Run("NotConfigured", func(t *testing.T) {
+		api := &API{}
+
+		w := httptest.NewRecorder()
+		babyapi.Handler(api.reloadHandler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, reloadPath, http.NoBody))
+
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+		assert.Equal(t, `{"status":"Not Implemented","error":"config reload is not configured"}`, strings.TrimSpace(w.Body.String()))
+	})
+
+	t.Run("Successful", func(t *testing.T) {
+		api := &API{}
+		api.reloader = &configReloader{
+			load:   func() (Config, error) { return Config{WebConfig: WebConfig{AllowedOrigins: []string{"*"}}}, nil },
+			api:    api,
+			worker: worker.NewWorker(nil, nil, nil, slog.Default()),
+			logger: slog.Default(),
+		}
+
+		w := httptest.NewRecorder()
+		babyapi.Handler(api.reloadHandler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, reloadPath, http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"applied":["web_server.allowed_origins"]}`, strings.TrimSpace(w.Body.String()))
+		assert.Equal(t, []string{"*"}, api.getAllowedOrigins())
+	})
+
+	t.Run("ErrorLoading", func(t *testing.T) {
+		api := &API{}
+		api.reloader = &configReloader{
+			load:   func() (Config, error) { return Config{}, errors.New("file not found") },
+			api:    api,
+			logger: slog.Default(),
+		}
+
+		w := httptest.NewRecorder()
+		babyapi.Handler(api.reloadHandler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, reloadPath, http.NoBody))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"status":"Invalid request.","error":"unable to load config: file not found"}`, strings.TrimSpace(w.Body.String()))
+	})
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const (
+	reminderBasePath = "/reminders"
+
+	defaultUpcomingRemindersWithin = 7 * 24 * time.Hour
+	maxUpcomingRemindersWithin     = 366 * 24 * time.Hour
+)
+
+// RemindersAPI provides an API for managing Reminders. Reminders are sent by the Worker using all of the configured
+// NotificationClients
+type RemindersAPI struct {
+	*babyapi.API[*pkg.Reminder]
+
+	storageClient *storage.Client
+	worker        *worker.Worker
+}
+
+func NewRemindersAPI() *RemindersAPI {
+	api := &RemindersAPI{}
+
+	api.API = babyapi.NewAPI("Reminders", reminderBasePath, func() *pkg.Reminder { return &pkg.Reminder{} })
+
+	api.SetResponseWrapper(func(r *pkg.Reminder) render.Renderer {
+		return api.NewReminderResponse(r)
+	})
+
+	api.SetOnCreateOrUpdate(api.onCreateOrUpdate)
+
+	api.SetAfterDelete(func(r *http.Request) *babyapi.ErrResponse {
+		logger := babyapi.GetLoggerFromContext(r.Context())
+		id := api.GetIDParam(r)
+
+		logger.Info("removing scheduled Job for Reminder")
+		err := api.worker.RemoveJobsByID(id)
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("unable to remove scheduled Reminder: %w", err))
+		}
+
+		return nil
+	})
+
+	api.SetGetAllFilter(api.reminderFilter)
+
+	api.AddIDMiddleware(userGardenMiddleware(api.API, func(r *http.Request, rem *pkg.Reminder, scope pkg.APITokenScope) bool {
+		return userGardenAllows(r.Context(), api.storageClient, rem.GardenID.String(), scope)
+	}))
+
+	api.AddCustomRoute(http.MethodGet, "/upcoming", babyapi.Handler(api.upcoming))
+
+	api.ApplyExtension(conditionalRequests[*pkg.Reminder]{})
+
+	return api
+}
+
+func (api *RemindersAPI) setup(storageClient *storage.Client, worker *worker.Worker) error {
+	api.storageClient = storageClient
+	api.worker = worker
+
+	api.SetStorage(api.storageClient.Reminders)
+
+	reminders, err := api.storageClient.Reminders.GetAll(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("unable to get Reminders: %w", err)
+	}
+	for _, r := range reminders {
+		err = api.worker.ScheduleReminder(r)
+		if err != nil {
+			return fmt.Errorf("unable to schedule Reminder %v: %w", r.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// onCreateOrUpdate makes sure the Reminder's Garden, Zone, and Plant exist and belong together before scheduling it.
+// If only a Plant is set, the Reminder also uses the Plant's Zone
+func (api *RemindersAPI) onCreateOrUpdate(r *http.Request, rem *pkg.Reminder) *babyapi.ErrResponse {
+	g, err := api.storageClient.Gardens.Get(r.Context(), rem.GardenID.String())
+	if err != nil {
+		if errors.Is(err, babyapi.ErrNotFound) {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("error getting Garden with ID %q: %w", rem.GardenID, err))
+		}
+		return babyapi.InternalServerError(fmt.Errorf("error getting Garden with ID %q: %w", rem.GardenID, err))
+	}
+	if !gardenAllows(r.Context(), g, pkg.APITokenScopeWrite) {
+		return babyapi.ErrForbidden
+	}
+	if g.EndDated() {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("unable to add Reminder to end-dated Garden %q", g.ID))
+	}
+
+	if rem.PlantID != nil {
+		p, err := api.storageClient.Plants.Get(r.Context(), rem.PlantID.String())
+		if err != nil {
+			if errors.Is(err, babyapi.ErrNotFound) {
+				return babyapi.ErrInvalidRequest(fmt.Errorf("error getting Plant with ID %q: %w", rem.PlantID, err))
+			}
+			return babyapi.InternalServerError(fmt.Errorf("error getting Plant with ID %q: %w", rem.PlantID, err))
+		}
+		if p.GardenID != rem.GardenID {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("Plant %q does not belong to Garden %q", p.ID, rem.GardenID))
+		}
+		if rem.ZoneID != nil && *rem.ZoneID != p.ZoneID {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("Plant %q does not belong to Zone %q", p.ID, rem.ZoneID))
+		}
+		rem.ZoneID = &p.ZoneID
+	}
+
+	if rem.ZoneID != nil {
+		z, err := api.storageClient.Zones.Get(r.Context(), rem.ZoneID.String())
+		if err != nil {
+			if errors.Is(err, babyapi.ErrNotFound) {
+				return babyapi.ErrInvalidRequest(fmt.Errorf("error getting Zone with ID %q: %w", rem.ZoneID, err))
+			}
+			return babyapi.InternalServerError(fmt.Errorf("error getting Zone with ID %q: %w", rem.ZoneID, err))
+		}
+		if z.GardenID != rem.GardenID {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("Zone %q does not belong to Garden %q", z.ID, rem.GardenID))
+		}
+	}
+
+	err = api.worker.ResetReminder(rem)
+	if err != nil {
+		return babyapi.InternalServerError(fmt.Errorf("unable to schedule Reminder: %w", err))
+	}
+
+	return nil
+}
+
+// upcoming responds with the active Reminders that are due within the duration from the "within" query parameter,
+// sorted by the next time they are due. The "garden_id", "zone_id", and "plant_id" query parameters filter them
+func (api *RemindersAPI) upcoming(_ http.ResponseWriter, r *http.Request) render.Renderer {
+	within := defaultUpcomingRemindersWithin
+	if withinParam := r.URL.Query().Get("within"); withinParam != "" {
+		var err error
+		within, err = time.ParseDuration(withinParam)
+		if err != nil || within <= 0 || within > maxUpcomingRemindersWithin {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("within must be a duration up to %s", maxUpcomingRemindersWithin))
+		}
+	}
+
+	reminders, err := api.storageClient.Reminders.GetAll(r.Context(), babyapi.EndDatedQueryParam(false))
+	if err != nil {
+		return babyapi.InternalServerError(fmt.Errorf("unable to get Reminders: %w", err))
+	}
+	reminders = api.reminderFilter(r).Filter(reminders)
+
+	now := api.worker.Now()
+	resp := &UpcomingRemindersResponse{ResourceList: babyapi.ResourceList[*ReminderResponse]{Items: []*ReminderResponse{}}}
+	for _, rem := range reminders {
+		next := rem.NextTime(now)
+		if next == nil || next.After(now.Add(within)) {
+			continue
+		}
+		resp.Items = append(resp.Items, api.NewReminderResponse(rem))
+	}
+
+	sort.SliceStable(resp.Items, func(i, j int) bool {
+		return resp.Items[i].NextTime.Before(*resp.Items[j].NextTime)
+	})
+
+	return resp
+}
+
+// reminderFilter filters Reminders using the "garden_id", "zone_id", and "plant_id" query parameters. Requests made
+// as a User only include Reminders for Gardens that the User can read
+func (api *RemindersAPI) reminderFilter(r *http.Request) babyapi.FilterFunc[*pkg.Reminder] {
+	gardenID := r.URL.Query().Get("garden_id")
+	zoneID := r.URL.Query().Get("zone_id")
+	plantID := r.URL.Query().Get("plant_id")
+
+	return func(rem *pkg.Reminder) bool {
+		if gardenID != "" && rem.GardenID.String() != gardenID {
+			return false
+		}
+		if zoneID != "" && (rem.ZoneID == nil || rem.ZoneID.String() != zoneID) {
+			return false
+		}
+		if plantID != "" && (rem.PlantID == nil || rem.PlantID.String() != plantID) {
+			return false
+		}
+		return userCanReadGarden(r.Context(), api.storageClient, rem.GardenID.String())
+	}
+}
+
+// ReminderResponse is used to represent a Reminder in the response body with the next time it is due and
+// hypermedia Links
+type ReminderResponse struct {
+	*pkg.Reminder
+
+	NextTime *time.Time `json:"next_time,omitempty"`
+
+	Links []Link `json:"links,omitempty"`
+}
+
+// NewReminderResponse creates a ReminderResponse using the Worker's current time
+func (api *RemindersAPI) NewReminderResponse(r *pkg.Reminder) *ReminderResponse {
+	resp := &ReminderResponse{Reminder: r}
+	if !r.EndDated() {
+		resp.NextTime = r.NextTime(api.worker.Now())
+	}
+	return resp
+}
+
+// Render ...
+func (resp *ReminderResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	if resp == nil {
+		return nil
+	}
+
+	gardenPath := fmt.Sprintf("%s/%s", gardenBasePath, resp.GardenID)
+	resp.Links = append(resp.Links,
+		Link{
+			"self",
+			fmt.Sprintf("%s/%s", reminderBasePath, resp.ID),
+		},
+		Link{
+			"garden",
+			gardenPath,
+		},
+	)
+	if resp.ZoneID != nil {
+		zonePath := fmt.Sprintf("%s%s/%s", gardenPath, zoneBasePath, resp.ZoneID)
+		resp.Links = append(resp.Links, Link{"zone", zonePath})
+
+		if resp.PlantID != nil {
+			resp.Links = append(resp.Links, Link{
+				"plant",
+				fmt.Sprintf("%s%s/%s", zonePath, plantBasePath, resp.PlantID),
+			})
+		}
+	}
+
+	return nil
+}
+
+// UpcomingRemindersResponse is the list of Reminders that are due soon
+type UpcomingRemindersResponse struct {
+	babyapi.ResourceList[*ReminderResponse]
+}
+
+func (resp *UpcomingRemindersResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return resp.ResourceList.Render(w, r)
+}
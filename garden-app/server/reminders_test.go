@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateReminder(t *testing.T) {
+	otherGardenZone := createExampleZone()
+	otherGardenZone.ID = babyapi.ID{ID: id2}
+	otherGardenZone.GardenID = id2
+
+	plant := createExamplePlant()
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedRegexp string
+		code           int
+	}{
+		{
+			"SuccessfulZone",
+			`{"type":"prune","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"c5cvhpcbcv45e8bp16dg","start_date":"2099-05-01T09:00:00Z","interval":"336h"}`,
+			`{"id":"[0-9a-v]{20}","type":"prune","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"c5cvhpcbcv45e8bp16dg","start_date":"2099-05-01T09:00:00Z","interval":"336h0m0s","created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","next_time":"2099-05-01T09:00:00Z","links":\[{"rel":"self","href":"/reminders/[0-9a-v]{20}"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"zone","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg"}\]}`,
+			http.StatusCreated,
+		},
+		{
+			"SuccessfulPlantSetsZone",
+			`{"type":"harvest","garden_id":"c5cvhpcbcv45e8bp16dg","plant_id":"c5cvhpcbcv45e8bp16dg","message":"check ripeness","start_date":"2099-07-01T09:00:00Z"}`,
+			`{"id":"[0-9a-v]{20}","type":"harvest","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"c5cvhpcbcv45e8bp16dg","plant_id":"c5cvhpcbcv45e8bp16dg","message":"check ripeness","start_date":"2099-07-01T09:00:00Z","created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","next_time":"2099-07-01T09:00:00Z","links":\[{"rel":"self","href":"/reminders/[0-9a-v]{20}"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"zone","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg"},{"rel":"plant","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/plants/c5cvhpcbcv45e8bp16dg"}\]}`,
+			http.StatusCreated,
+		},
+		{
+			"ErrorInvalidType",
+			`{"type":"water","garden_id":"c5cvhpcbcv45e8bp16dg","start_date":"2099-07-01T09:00:00Z"}`,
+			`{"status":"Invalid request.","error":"invalid type \\"water\\": must be one of prune, fertilize, harvest, or repot"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorGardenNotFound",
+			`{"type":"repot","garden_id":"chkodpg3lcj13q82mq40","start_date":"2099-07-01T09:00:00Z"}`,
+			`{"status":"Invalid request.","error":"error getting Garden with ID \\"chkodpg3lcj13q82mq40\\": resource not found"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorZoneInOtherGarden",
+			`{"type":"repot","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"chkodpg3lcj13q82mq40","start_date":"2099-07-01T09:00:00Z"}`,
+			`{"status":"Invalid request.","error":"Zone \\"chkodpg3lcj13q82mq40\\" does not belong to Garden \\"c5cvhpcbcv45e8bp16dg\\""}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorPlantInOtherZone",
+			`{"type":"repot","garden_id":"c5cvhpcbcv45e8bp16dg","zone_id":"chkodpg3lcj13q82mq40","plant_id":"c5cvhpcbcv45e8bp16dg","start_date":"2099-07-01T09:00:00Z"}`,
+			`{"status":"Invalid request.","error":"Plant \\"c5cvhpcbcv45e8bp16dg\\" does not belong to Zone \\"chkodpg3lcj13q82mq40\\""}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+
+			err := storageClient.Zones.Set(context.Background(), otherGardenZone)
+			require.NoError(t, err)
+			err = storageClient.Plants.Set(context.Background(), plant)
+			require.NoError(t, err)
+
+			api := NewRemindersAPI()
+			err = api.setup(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodPost, "/reminders", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			w := babytest.TestRequest[*pkg.Reminder](t, api.API, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestUpcomingReminders(t *testing.T) {
+	now := time.Now()
+	tomorrow := now.Add(24 * time.Hour).Truncate(time.Second).UTC()
+	nextWeek := now.Add(6 * 24 * time.Hour).Truncate(time.Second).UTC()
+	nextMonth := now.Add(30 * 24 * time.Hour).Truncate(time.Second).UTC()
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+
+	reminderIDs := []xid.ID{xid.New(), xid.New(), xid.New(), xid.New()}
+	reminders := []*pkg.Reminder{
+		{ID: babyapi.ID{ID: reminderIDs[0]}, Type: pkg.ReminderTypePrune, GardenID: id, StartDate: &nextWeek},
+		{ID: babyapi.ID{ID: reminderIDs[1]}, Type: pkg.ReminderTypeFertilize, GardenID: id, StartDate: &tomorrow},
+		{ID: babyapi.ID{ID: reminderIDs[2]}, Type: pkg.ReminderTypeRepot, GardenID: id, StartDate: &nextMonth},
+		// One-time Reminders that have already been sent are not upcoming
+		{ID: babyapi.ID{ID: reminderIDs[3]}, Type: pkg.ReminderTypeHarvest, GardenID: id, StartDate: &lastWeek},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []xid.ID
+		code     int
+	}{
+		{"Default", "", []xid.ID{reminderIDs[1], reminderIDs[0]}, http.StatusOK},
+		{"Within", "?within=48h", []xid.ID{reminderIDs[1]}, http.StatusOK},
+		{"WithinMonth", "?within=744h", []xid.ID{reminderIDs[1], reminderIDs[0], reminderIDs[2]}, http.StatusOK},
+		{"FilterGarden", "?garden_id=" + id2.String(), []xid.ID{}, http.StatusOK},
+		{"ErrorInvalidWithin", "?within=abc", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+			for _, rem := range reminders {
+				err := storageClient.Reminders.Set(context.Background(), rem)
+				require.NoError(t, err)
+			}
+
+			api := NewRemindersAPI()
+			err := api.setup(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/reminders/upcoming%s", tt.query), http.NoBody)
+			w := babytest.TestRequest[*pkg.Reminder](t, api.API, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.expected == nil {
+				return
+			}
+
+			ids := []xid.ID{}
+			for _, item := range readUpcomingReminders(t, w.Body.String()) {
+				ids = append(ids, item.ID.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func readUpcomingReminders(t *testing.T, body string) []*ReminderResponse {
+	t.Helper()
+
+	var resp UpcomingRemindersResponse
+	err := json.Unmarshal([]byte(body), &resp)
+	require.NoError(t, err)
+
+	return resp.Items
+}
+
+func TestRemindersUserGardens(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+
+	user := &pkg.User{ID: babyapi.NewID(), Name: "Sam", Role: pkg.UserRoleOwner}
+	viewer := &pkg.User{ID: babyapi.NewID(), Name: "Alex", Role: pkg.UserRoleOwner}
+
+	garden, err := storageClient.Gardens.Get(context.Background(), id.String())
+	require.NoError(t, err)
+	garden.Users = map[string]pkg.UserRole{viewer.GetID(): pkg.UserRoleViewer}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+
+	otherGarden := createExampleGarden()
+	otherGarden.ID = babyapi.ID{ID: id2}
+	otherGarden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleOwner}
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), otherGarden))
+
+	tomorrow := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	reminder := &pkg.Reminder{ID: babyapi.NewID(), Type: pkg.ReminderTypePrune, GardenID: id, StartDate: &tomorrow}
+	require.NoError(t, storageClient.Reminders.Set(context.Background(), reminder))
+
+	api := NewRemindersAPI()
+	err = api.setup(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+	require.NoError(t, err)
+
+	reminderPath := "/reminders/" + reminder.GetID()
+	tests := []struct {
+		name         string
+		user         *pkg.User
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{"GetAll", user, http.MethodGet, "/reminders", "", http.StatusOK},
+		{"Upcoming", user, http.MethodGet, "/reminders/upcoming", "", http.StatusOK},
+		{"GetNotFound", user, http.MethodGet, reminderPath, "", http.StatusNotFound},
+		{"DeleteNotFound", user, http.MethodDelete, reminderPath, "", http.StatusNotFound},
+		{"PatchNotFound", user, http.MethodPatch, reminderPath, `{"garden_id":"` + id2.String() + `"}`, http.StatusNotFound},
+		{"CreateForbidden", user, http.MethodPost, "/reminders", `{"type":"repot","garden_id":"` + id.String() + `","start_date":"2099-07-01T09:00:00Z"}`, http.StatusForbidden},
+		{"ViewerCanGet", viewer, http.MethodGet, reminderPath, "", http.StatusOK},
+		{"ViewerCannotDelete", viewer, http.MethodDelete, reminderPath, "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			r = r.WithContext(withUser(r.Context(), tt.user))
+			w := babytest.TestRequest[*pkg.Reminder](t, api.API, r)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			// The Reminder is never included for a User without a role for its Garden
+			if tt.user == user {
+				assert.NotContains(t, w.Body.String(), reminder.GetID())
+			}
+		})
+	}
+
+	stored, err := storageClient.Reminders.Get(context.Background(), reminder.GetID())
+	require.NoError(t, err)
+	assert.False(t, stored.EndDated())
+	assert.Equal(t, id, stored.GardenID)
+}
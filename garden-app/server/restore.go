@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const (
+	restorePath          = "/restore"
+	includeEndDatedParam = "include_end_dated"
+)
+
+// restorable is a resource that can be end-dated and then restored
+type restorable interface {
+	babyapi.Resource
+	EndDated() bool
+	Restore()
+}
+
+// restoreResource clears the resource's EndDate and then uses the same validation and scheduling as an update
+// before saving it
+func restoreResource[T restorable](
+	r *http.Request,
+	resource T,
+	resourceName string,
+	storage babyapi.Storage[T],
+	onCreateOrUpdate func(*http.Request, T) *babyapi.ErrResponse,
+	respond func(T) render.Renderer,
+) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to restore " + resourceName)
+
+	if !resource.EndDated() {
+		return nil, babyapi.ErrInvalidRequest(fmt.Errorf("%s is not end-dated", resourceName))
+	}
+
+	resource.Restore()
+
+	httpErr := onCreateOrUpdate(r, resource)
+	if httpErr != nil {
+		logger.Error("unable to restore "+resourceName, "error", httpErr)
+		return nil, httpErr
+	}
+
+	err := storage.Set(r.Context(), resource)
+	if err != nil {
+		logger.Error("unable to save restored "+resourceName, "error", err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return respond(resource), nil
+}
+
+// includeEndDatedMiddleware allows using "include_end_dated=true" to list end-dated resources. It is the same as
+// babyapi's "end_dated" query parameter, but the name makes it clear that active resources are still included
+func includeEndDatedMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		include := query.Get(includeEndDatedParam)
+		if include == "" || query.Has("end_dated") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		query.Set("end_dated", include)
+		r = r.Clone(r.Context())
+		r.URL.RawQuery = query.Encode()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreGarden(t *testing.T) {
+	endDate := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		endDate        *time.Time
+		expectedRegexp string
+		status         int
+	}{
+		{
+			"Successful",
+			&endDate,
+			`{"name":"test-garden","topic_prefix":"test-garden","id":"c5cvhpcbcv45e8bp16dg",.*`,
+			http.StatusOK,
+		},
+		{
+			"ErrorNotEndDated",
+			nil,
+			`{"status":"Invalid request.","error":"Garden is not end-dated"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			garden := createExampleGarden()
+			garden.EndDate = tt.endDate
+			require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+
+			wkr := worker.NewWorker(storageClient, nil, nil, slog.Default())
+			gr := NewGardenAPI()
+			require.NoError(t, gr.setup(Config{}, storageClient, nil, wkr))
+			wkr.StartAsync()
+			defer wkr.Stop()
+
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/gardens/%s/restore", garden.ID), http.NoBody)
+			w := babytest.TestRequest[*pkg.Garden](t, gr.API, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+
+			stored, err := storageClient.Gardens.Get(context.Background(), garden.GetID())
+			require.NoError(t, err)
+			assert.False(t, stored.EndDated())
+			assert.NotNil(t, wkr.GetNextLightTime(stored, pkg.LightStateOn))
+		})
+	}
+}
+
+func TestRestoreZone(t *testing.T) {
+	endDate := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		gardenEndDate  *time.Time
+		expectedRegexp string
+		status         int
+	}{
+		{
+			"Successful",
+			nil,
+			`{"name":"test-zone","id":"c5cvhpcbcv45e8bp16dg",.*`,
+			http.StatusOK,
+		},
+		{
+			"ErrorGardenEndDated",
+			&endDate,
+			`{"status":"Invalid request.","error":"unable to restore Zone in end-dated Garden"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+			require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), createExampleWaterSchedule()))
+
+			zone := createExampleZone()
+			zone.EndDate = &endDate
+			require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+
+			zr := NewZonesAPI()
+			zr.setup(storageClient, nil, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+
+			garden := createExampleGarden()
+			garden.EndDate = tt.gardenEndDate
+
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/gardens/%s/zones/%s/restore", garden.ID, zone.ID), http.NoBody)
+			w := babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, zr.API, garden, "Gardens", "/gardens", r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+
+			stored, err := storageClient.Zones.Get(context.Background(), zone.GetID())
+			require.NoError(t, err)
+			assert.Equal(t, tt.status == http.StatusOK, !stored.EndDated())
+		})
+	}
+}
+
+func TestRestoreWaterSchedule(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	endDate := time.Now().Add(-time.Hour)
+	ws := createExampleWaterSchedule()
+	ws.EndDate = &endDate
+	require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+
+	wkr := worker.NewWorker(storageClient, nil, nil, slog.Default())
+	wsr := NewWaterSchedulesAPI()
+	require.NoError(t, wsr.setup(storageClient, wkr))
+	wkr.StartAsync()
+	defer wkr.Stop()
+	assert.Nil(t, wkr.GetNextWaterTime(ws))
+
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/water_schedules/%s/restore", ws.ID), http.NoBody)
+	w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	stored, err := storageClient.WaterSchedules.Get(context.Background(), ws.GetID())
+	require.NoError(t, err)
+	assert.Nil(t, stored.EndDate)
+	assert.NotNil(t, wkr.GetNextWaterTime(stored))
+}
+
+func TestIncludeEndDatedMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"NotSet", "", ""},
+		{"IncludeTrue", "include_end_dated=true", "true"},
+		{"IncludeFalse", "include_end_dated=false", "false"},
+		{"EndDatedTakesPriority", "include_end_dated=true&end_dated=false", "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var endDated string
+			handler := includeEndDatedMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				endDated = r.URL.Query().Get("end_dated")
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/gardens?"+tt.query, http.NoBody))
+			assert.Equal(t, tt.expected, endDated)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+// revisionHistory is a babyapi Extension that saves the previous version of a resource each time it is changed
+// with PUT or PATCH. It adds routes to list the Revisions and to roll back to one of them. Rolling back replaces
+// the resource using the API's PUT handler, so it is validated and scheduled like any other update. The current
+// version is saved first, so a rollback can also be undone
+type revisionHistory[T babyapi.Resource] struct {
+	resourceType string
+	// storage is a function because the storage client is not available until the API is setup
+	storage func() storage.RevisionStorage
+}
+
+func (rh revisionHistory[T]) Apply(api *babyapi.API[T]) error {
+	api.AddIDMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := api.GetIDParam(r)
+
+			// ID middlewares also run for custom routes and nested APIs, which are different resources
+			isResourcePath := strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), api.Base()+"/"+id)
+			if !isResourcePath || (r.Method != http.MethodPut && r.Method != http.MethodPatch) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// PUT can create a new resource, which does not have a previous version
+			previous, err := api.GetResourceFromContext(r.Context())
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			rh.save(r, api, id, previous, recorder.status)
+		})
+	})
+
+	api.AddCustomIDRoute(http.MethodGet, "/revisions", babyapi.Handler(func(_ http.ResponseWriter, r *http.Request) render.Renderer {
+		revisions, err := rh.storage().GetRevisions(r.Context(), rh.resourceType, api.GetIDParam(r))
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("error getting revisions: %w", err))
+		}
+
+		return &RevisionsResponse{Items: revisions}
+	}))
+
+	api.AddCustomIDRoute(http.MethodPost, "/revisions/{revisionID}/rollback", babyapi.Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
+		return rh.rollback(w, r, api)
+	}))
+
+	return nil
+}
+
+// rollback replaces the resource with the requested Revision
+func (rh revisionHistory[T]) rollback(w http.ResponseWriter, r *http.Request, api *babyapi.API[T]) render.Renderer {
+	id := api.GetIDParam(r)
+
+	number, err := strconv.ParseUint(babyapi.GetIDParam(r, "revision"), 10, 0)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid revision number: %w", err))
+	}
+
+	current, httpErr := api.GetRequestedResource(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	revision, err := rh.storage().GetRevision(r.Context(), rh.resourceType, id, uint(number))
+	if err != nil {
+		if errors.Is(err, babyapi.ErrNotFound) {
+			return babyapi.ErrNotFoundResponse
+		}
+		return babyapi.InternalServerError(fmt.Errorf("error getting revision: %w", err))
+	}
+
+	putRequest := r.Clone(r.Context())
+	putRequest.Method = http.MethodPut
+	putRequest.Body = io.NopCloser(bytes.NewReader(revision.Data))
+	putRequest.ContentLength = int64(len(revision.Data))
+	putRequest.Header.Set("Content-Type", "application/json")
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	api.Put.ServeHTTP(recorder, putRequest)
+
+	rh.save(r, api, id, current, recorder.status)
+
+	return nil
+}
+
+// save adds the previous version of the resource as a Revision if the request was successful and changed it
+func (rh revisionHistory[T]) save(r *http.Request, api *babyapi.API[T], id string, previous T, status int) {
+	if status < 200 || status >= 300 {
+		return
+	}
+
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	previousData, err := json.Marshal(previous)
+	if err != nil {
+		logger.Error("unable to marshal previous version", "error", err)
+		return
+	}
+
+	updated, err := api.Storage.Get(r.Context(), id)
+	if err != nil {
+		logger.Error("unable to get updated resource to save revision", "error", err)
+		return
+	}
+
+	updatedData, err := json.Marshal(updated)
+	if err != nil {
+		logger.Error("unable to marshal updated resource", "error", err)
+		return
+	}
+	if bytes.Equal(previousData, updatedData) {
+		return
+	}
+
+	err = rh.storage().AddRevision(r.Context(), rh.resourceType, id, time.Now(), previousData)
+	if err != nil {
+		logger.Error("unable to save revision", "error", err)
+	}
+}
+
+// RevisionsResponse is a list of a resource's previous versions, starting with the most recent
+type RevisionsResponse struct {
+	Items []pkg.Revision `json:"items"`
+}
+
+// Render ...
+func (resp *RevisionsResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneRevisions(t *testing.T) {
+	storageClient := setupZoneAndGardenStorage(t)
+	// The example Zone uses this WaterSchedule and PATCH checks that it exists
+	require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), createExampleWaterSchedule()))
+
+	api := NewZonesAPI()
+	api.setup(storageClient, nil, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/gardens/"+id.String()+"/zones/"+id.String()+path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		return babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, api.API, createExampleGarden(), "Gardens", "/gardens", r)
+	}
+
+	getRevisions := func(t *testing.T) []pkg.Revision {
+		t.Helper()
+		w := request(http.MethodGet, "/revisions", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp RevisionsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Items
+	}
+
+	assert.Empty(t, getRevisions(t))
+
+	w := request(http.MethodPatch, "", `{"name":"renamed"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	t.Run("PatchSavesPreviousVersion", func(t *testing.T) {
+		revisions := getRevisions(t)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, uint(1), revisions[0].Number)
+		assert.Contains(t, string(revisions[0].Data), `"name":"test-zone"`)
+	})
+
+	t.Run("UnchangedDoesNotSave", func(t *testing.T) {
+		w := request(http.MethodPatch, "", `{"name":"renamed"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Len(t, getRevisions(t), 1)
+	})
+
+	t.Run("ErrorInvalidNumber", func(t *testing.T) {
+		w := request(http.MethodPost, "/revisions/abc/rollback", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ErrorRevisionNotFound", func(t *testing.T) {
+		w := request(http.MethodPost, "/revisions/5/rollback", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		w := request(http.MethodPost, "/revisions/1/rollback", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), `"name":"test-zone"`)
+
+		w = request(http.MethodGet, "", "")
+		assert.Contains(t, w.Body.String(), `"name":"test-zone"`)
+
+		// The version before the rollback is saved so it can be undone
+		revisions := getRevisions(t)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, uint(2), revisions[0].Number)
+		assert.Contains(t, string(revisions[0].Data), `"name":"renamed"`)
+	})
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/jsonschema"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const schemaPath = "/schema"
+
+// ResourceSchema describes a resource type that can be managed with the API. Path is the collection path, which
+// uses parameters like "{gardenID}" for nested resources
+type ResourceSchema struct {
+	Path   string             `json:"path"`
+	Schema *jsonschema.Schema `json:"schema"`
+}
+
+// SchemaResponse has a JSON Schema for each resource type, so API clients like Terraform or Pulumi providers can be
+// generated from it. The schemas are created from the same types that are used to read requests
+type SchemaResponse struct {
+	Schema    string                    `json:"$schema"`
+	Resources map[string]ResourceSchema `json:"resources"`
+}
+
+func (resp *SchemaResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func newSchemaResponse() *SchemaResponse {
+	gardenPath := gardenBasePath + "/{gardenID}"
+	zonePath := gardenPath + zoneBasePath + "/{zoneID}"
+
+	return &SchemaResponse{
+		Schema: jsonschema.Draft,
+		Resources: map[string]ResourceSchema{
+			"garden":              {gardenBasePath, jsonschema.For(pkg.Garden{})},
+			"zone":                {gardenPath + zoneBasePath, jsonschema.For(pkg.Zone{})},
+			"zone_group":          {gardenPath + zoneGroupBasePath, jsonschema.For(pkg.ZoneGroup{})},
+			"plant":               {zonePath + plantBasePath, jsonschema.For(pkg.Plant{})},
+			"reminder":            {reminderBasePath, jsonschema.For(pkg.Reminder{})},
+			"water_schedule":      {waterScheduleBasePath, jsonschema.For(pkg.WaterSchedule{})},
+			"weather_client":      {weatherClientsBasePath, jsonschema.For(weather.Config{})},
+			"notification_client": {notificationClientsBasePath, jsonschema.For(notifications.Client{})},
+		},
+	}
+}
+
+// getSchema responds with the SchemaResponse. The schemas do not change, so they are only created once
+var getSchema = func() func(http.ResponseWriter, *http.Request) render.Renderer {
+	var once sync.Once
+	var resp *SchemaResponse
+	return func(_ http.ResponseWriter, _ *http.Request) render.Renderer {
+		once.Do(func() {
+			resp = newSchemaResponse()
+		})
+		return resp
+	}
+}()
+
+// ValidationErrorResponse is used instead of babyapi's error response when a request fails because of a
+// validation.Error. Field and Reason are stable, so clients can show the error on the right field without parsing
+// the message
+type ValidationErrorResponse struct {
+	*babyapi.ErrResponse
+
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+type validationErrorsContextKey struct{}
+
+var setupValidationErrors sync.Once
+
+// validationErrorsMiddleware adds the field and reason to responses for validation errors. babyapi renders errors
+// itself, so this wraps render.Respond. It is done when the first request is received because babyapi replaces
+// render.Respond when the routes are created. Since render.Respond is shared, the context is used so only requests
+// handled by this middleware are changed
+func validationErrorsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		setupValidationErrors.Do(func() {
+			render.Respond = withValidationErrors(render.Respond)
+		})
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), validationErrorsContextKey{}, true)))
+	})
+}
+
+// withValidationErrors wraps a responder so it uses ValidationErrorResponse for errors caused by a validation.Error
+func withValidationErrors(respond func(http.ResponseWriter, *http.Request, interface{})) func(http.ResponseWriter, *http.Request, interface{}) {
+	return func(w http.ResponseWriter, r *http.Request, v interface{}) {
+		enabled, _ := r.Context().Value(validationErrorsContextKey{}).(bool)
+		errResp, ok := v.(*babyapi.ErrResponse)
+		if !enabled || !ok {
+			respond(w, r, v)
+			return
+		}
+
+		var validationErr *validation.Error
+		if !errors.As(errResp.Err, &validationErr) {
+			respond(w, r, v)
+			return
+		}
+
+		respond(w, r, &ValidationErrorResponse{
+			ErrResponse: errResp,
+			Field:       validationErr.Field,
+			Reason:      validationErr.Code,
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/validation"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSchema(t *testing.T) {
+	resp, ok := getSchema(nil, nil).(*SchemaResponse)
+	require.True(t, ok)
+
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", resp.Schema)
+	assert.Len(t, resp.Resources, 8)
+
+	zone := resp.Resources["zone"]
+	assert.Equal(t, "/gardens/{gardenID}/zones", zone.Path)
+	assert.Equal(t, "integer", zone.Schema.Properties["position"].Type)
+	assert.Equal(t, "string", zone.Schema.Properties["id"].Type)
+
+	waterSchedule := resp.Resources["water_schedule"]
+	assert.Equal(t, "/water_schedules", waterSchedule.Path)
+	assert.Equal(t, "string", waterSchedule.Schema.Properties["duration"].Type)
+	assert.Equal(t, "time", waterSchedule.Schema.Properties["start_time"].Format)
+
+	garden := resp.Resources["garden"]
+	assert.Equal(t, "date-time", garden.Schema.Properties["light_schedule"].Properties["adhoc_on_time"].Format)
+	assert.NotContains(t, garden.Schema.Properties["light_schedule"].Properties, "until")
+}
+
+func TestWithValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		value    any
+		expected string
+	}{
+		{
+			"ValidationError",
+			true,
+			babyapi.ErrInvalidRequest(fmt.Errorf("invalid request: %w", validation.Missing("name"))),
+			`{"status":"Invalid request.","error":"invalid request: missing required name field","field":"name","reason":"required"}`,
+		},
+		{
+			"NotEnabled",
+			false,
+			babyapi.ErrInvalidRequest(validation.Missing("name")),
+			`{"status":"Invalid request.","error":"missing required name field"}`,
+		},
+		{
+			"OtherError",
+			true,
+			babyapi.ErrInvalidRequest(errors.New("bad")),
+			`{"status":"Invalid request.","error":"bad"}`,
+		},
+		{
+			"NotError",
+			true,
+			map[string]string{"key": "value"},
+			`{"key":"value"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result any
+			respond := withValidationErrors(func(_ http.ResponseWriter, _ *http.Request, v interface{}) {
+				result = v
+			})
+			r := httptest.NewRequest(http.MethodPost, "/gardens", http.NoBody)
+			if tt.enabled {
+				r = r.WithContext(context.WithValue(r.Context(), validationErrorsContextKey{}, true))
+			}
+			respond(nil, r, tt.value)
+
+			data, err := json.Marshal(result)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestValidationErrorResponse(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	// NewAPI is not used here because it can only be called once per test due to metrics registration
+	gardens := NewGardenAPI()
+	err = gardens.setup(Config{}, storageClient, nil, nil)
+	require.NoError(t, err)
+
+	api := babyapi.NewRootAPI("garden-app", "/")
+	api.AddMiddleware(validationErrorsMiddleware).AddNestedAPI(gardens)
+
+	r := httptest.NewRequest(http.MethodPost, "/gardens", strings.NewReader(`{"topic_prefix":"test-garden","max_zones":1}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := babytest.TestRequest(t, api, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"status":"Invalid request.","error":"missing required name field","field":"name","reason":"required"}`, w.Body.String())
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/go-chi/render"
+)
+
+const simulateAdvancePath = "/admin/simulate/advance"
+
+// SimulationConfig is used to run the server in simulation mode. This replaces the MQTT connection with an
+// in-memory client and embedded mock controller and uses a virtual clock that only moves forward when
+// requested with the API
+type SimulationConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// StartTime is an RFC3339 timestamp used as the initial time of the virtual clock. It defaults to the current time
+	StartTime string `mapstructure:"start_time"`
+}
+
+// startTime parses the configured StartTime or returns the current time if it is not set
+func (c SimulationConfig) startTime() (time.Time, error) {
+	if c.StartTime == "" {
+		return time.Now(), nil
+	}
+	return time.Parse(time.RFC3339, c.StartTime)
+}
+
+// newSimulatedMQTTClient creates an in-memory MQTT client with an embedded mock controller subscribed to
+// water commands for all Gardens. The controller immediately acknowledges the command and responds with a water
+// event like a real controller
+func newSimulatedMQTTClient(cfg mqtt.Config, logger *slog.Logger, handlers ...mqtt.TopicHandler) (*mqtt.InMemoryClient, error) {
+	client := mqtt.NewInMemoryClient(cfg, mqtt.DefaultHandler(logger), handlers...)
+
+	waterTopic, err := cfg.WaterTopic("+")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create water topic for simulated controller: %w", err)
+	}
+
+	// The topic prefix is the part of the incoming topic that matched the wildcard
+	topicStart, topicEnd, _ := strings.Cut(waterTopic, "+")
+
+	controllerLogger := logger.With("source", "simulated_controller")
+	err = client.Subscribe(waterTopic, paho.MessageHandler(func(_ paho.Client, msg paho.Message) {
+		var waterMsg action.WaterMessage
+		err := json.Unmarshal(msg.Payload(), &waterMsg)
+		if err != nil {
+			controllerLogger.Error("unable to unmarshal WaterMessage JSON", "error", err)
+			return
+		}
+
+		// Incoming topic is from the configured template, like "{{.TopicPrefix}}/command/water", but the controller
+		// always publishes on "{{.TopicPrefix}}/data/water"
+		topicPrefix := strings.TrimSuffix(strings.TrimPrefix(msg.Topic(), topicStart), topicEnd)
+		if waterMsg.CommandID != "" {
+			err = client.Publish(topicPrefix+"/ack/water", []byte(waterMsg.CommandID))
+			if err != nil {
+				controllerLogger.Error("unable to publish water command acknowledgment", "error", err)
+			}
+		}
+
+		dataTopic := topicPrefix + "/data/water"
+		controllerLogger.Info("publishing watering event for Zone", "topic", dataTopic, "zone_position", waterMsg.Position, "duration", waterMsg.Duration)
+
+		err = client.Publish(dataTopic, []byte(fmt.Sprintf("water,zone=%d millis=%d", waterMsg.Position, waterMsg.Duration)))
+		if err != nil {
+			controllerLogger.Error("unable to publish watering event", "error", err)
+		}
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to subscribe simulated controller: %w", err)
+	}
+
+	return client, nil
+}
+
+// setupSimulation configures the Worker and WeatherClients to use a virtual clock and adds the admin routes for
+// controlling it
+func (api *API) setupSimulation(cfg SimulationConfig, storageClient *storage.Client, w *worker.Worker) error {
+	start, err := cfg.startTime()
+	if err != nil {
+		return fmt.Errorf("invalid simulation start_time: %w", err)
+	}
+	w.SetClock(clock.NewVirtual(start))
+	storageClient.SetNow(w.Now)
+
+	api.API.AddCustomRoute(http.MethodPost, simulateAdvancePath, babyapi.Handler(func(_ http.ResponseWriter, r *http.Request) render.Renderer {
+		return advanceSimulation(r, w)
+	}))
+
+	return nil
+}
+
+// SimulationResponse is the response to advancing the virtual clock
+type SimulationResponse struct {
+	Now         time.Time `json:"now"`
+	Advanced    string    `json:"advanced"`
+	TimersFired int       `json:"timers_fired"`
+}
+
+func (resp *SimulationResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func advanceSimulation(r *http.Request, w *worker.Worker) render.Renderer {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	durationParam := r.URL.Query().Get("duration")
+	if durationParam == "" {
+		return babyapi.ErrInvalidRequest(errors.New("missing required duration query parameter"))
+	}
+
+	duration, err := time.ParseDuration(durationParam)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid duration: %w", err))
+	}
+
+	logger.Info("received request to advance simulation", "duration", duration)
+	fired, err := w.AdvanceClock(duration)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	return &SimulationResponse{
+		Now:         w.Now().UTC(),
+		Advanced:    duration.String(),
+		TimersFired: fired,
+	}
+}
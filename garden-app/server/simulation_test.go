@@ -0,0 +1,34 @@
+package server
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatedControllerPublishesWaterData(t *testing.T) {
+	var dataTopic, dataPayload string
+	handler := mqtt.TopicHandler{
+		Topic: "+/data/water",
+		Handler: paho.MessageHandler(func(_ paho.Client, msg paho.Message) {
+			dataTopic = msg.Topic()
+			dataPayload = string(msg.Payload())
+		}),
+	}
+
+	client, err := newSimulatedMQTTClient(mqtt.Config{
+		WaterTopicTemplate: "{{.Garden}}/command/water",
+	}, slog.Default(), handler)
+	require.NoError(t, err)
+
+	// The topic prefix contains "command" to make sure only the command part of the topic is replaced
+	err = client.Publish("command-center/command/water", []byte(`{"duration":1000,"id":"zone","position":1}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "command-center/data/water", dataTopic)
+	assert.Equal(t, "water,zone=1 millis=1000", dataPayload)
+}
@@ -34,6 +34,7 @@ const (
 	weatherClientsPageTemplate       html.Template = "WeatherClientsPage"
 	weatherClientsTemplate           html.Template = "WeatherClients"
 	weatherClientModalTemplate       html.Template = "WeatherClientModal"
+	photoTimelineTemplate            html.Template = "PhotoTimeline"
 )
 
 func templateFuncs(r *http.Request) map[string]any {
@@ -83,8 +84,11 @@ func templateFuncs(r *http.Request) map[string]any {
 			return strings.Contains(r.URL.Path, input)
 		},
 		"FormatDuration": formatDuration,
-		"ShortMonth": func(month string) string {
-			return month[0:3]
+		"FormatLiters": func(liters *float64) string {
+			if liters == nil {
+				return "-"
+			}
+			return fmt.Sprintf("%.1fL", *liters)
 		},
 		"RFC3339Nano": func(t *time.Time) string {
 			if t == nil {
@@ -195,7 +199,7 @@ func templateFuncs(r *http.Request) map[string]any {
 			result := map[int]string{}
 			for i := 0; i < 24; i++ {
 				selected := ""
-				if ls != nil && ls.Duration.Hours() == float64(i) {
+				if ls != nil && ls.Duration != nil && ls.Duration.Hours() == float64(i) {
 					selected = "selected"
 				}
 				result[i] = selected
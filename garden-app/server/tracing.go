@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// tracingMiddleware starts a span for each request, or continues the trace from the request's headers. Metrics and
+// probes are not traced since they are requested frequently
+func tracingMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			switch r.URL.Path {
+			case "/metrics", healthzPath, readyzPath:
+				return false
+			default:
+				return true
+			}
+		}),
+	)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const usersBasePath = "/users"
+
+// UsersAPI encapsulates the structs and dependencies necessary for the Users API to function, including storage and
+// configuring
+type UsersAPI struct {
+	*babyapi.API[*pkg.User]
+
+	storageClient *storage.Client
+}
+
+// NewUsersAPI creates a new UsersAPI
+func NewUsersAPI() *UsersAPI {
+	api := &UsersAPI{}
+
+	api.API = babyapi.NewAPI[*pkg.User]("Users", usersBasePath, func() *pkg.User { return &pkg.User{} })
+
+	api.SetResponseWrapper(func(u *pkg.User) render.Renderer {
+		return &UserResponse{User: u}
+	})
+
+	// Deleted Users are removed from Gardens so their IDs don't keep a role if they are reused
+	api.SetAfterDelete(func(r *http.Request) *babyapi.ErrResponse {
+		err := api.storageClient.RemoveUserFromGardens(r.Context(), api.GetIDParam(r))
+		if err != nil {
+			return babyapi.InternalServerError(err)
+		}
+		return nil
+	})
+
+	api.ApplyExtension(conditionalRequests[*pkg.User]{})
+
+	return api
+}
+
+func (api *UsersAPI) setup(storageClient *storage.Client) {
+	api.storageClient = storageClient
+
+	api.SetStorage(api.storageClient.Users)
+}
+
+// UserResponse is used to add links to a User
+type UserResponse struct {
+	*pkg.User
+
+	Links []Link `json:"links,omitempty"`
+}
+
+// Render ...
+func (resp *UserResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	if resp != nil {
+		resp.Links = append(resp.Links,
+			Link{
+				"self",
+				fmt.Sprintf("%s/%s", usersBasePath, resp.ID),
+			},
+		)
+	}
+	return nil
+}
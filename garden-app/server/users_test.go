@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsersAPI(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	api := NewUsersAPI()
+	api.setup(storageClient)
+
+	babytest.RunTableTest(t, api.API, []babytest.TestCase[*babyapi.AnyResource]{
+		{
+			Name: "CreateUser",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPost,
+				Body:   `{"name": "Sam", "email": "sam@example.com", "role": "viewer"}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status:     http.StatusCreated,
+				BodyRegexp: `{"id":"[0-9a-v]{20}","name":"Sam","email":"sam@example.com","role":"viewer","created_at":"[^"]+","links":\[{"rel":"self","href":"/users/[0-9a-v]{20}"}\]}`,
+			},
+		},
+		{
+			Name: "PatchRole",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPatch,
+				IDFunc: func(getResponse babytest.PreviousResponseGetter) string {
+					return getResponse("CreateUser").Data.GetID()
+				},
+				Body: `{"role": "owner"}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status:     http.StatusOK,
+				BodyRegexp: `{"id":"[0-9a-v]{20}","name":"Sam","email":"sam@example.com","role":"owner","created_at":"[^"]+","links":\[{"rel":"self","href":"/users/[0-9a-v]{20}"}\]}`,
+			},
+		},
+		{
+			Name: "ErrorCreateNoRole",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPost,
+				Body:   `{"name": "Sam"}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status: http.StatusBadRequest,
+				Error:  `error posting resource: unexpected response with text: Invalid request.`,
+				Body:   `{"status":"Invalid request.","error":"missing required role field"}`,
+			},
+		},
+		{
+			Name: "ErrorPatchInvalidRole",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPatch,
+				IDFunc: func(getResponse babytest.PreviousResponseGetter) string {
+					return getResponse("CreateUser").Data.GetID()
+				},
+				Body: `{"role": "admin"}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status: http.StatusBadRequest,
+				Error:  `error patching resource: unexpected response with text: Invalid request.`,
+				Body:   `{"status":"Invalid request.","error":"invalid role \"admin\": must be one of \"owner\", \"operator\", or \"viewer\""}`,
+			},
+		},
+	})
+
+	t.Run("DeleteUserRemovesGardenRole", func(t *testing.T) {
+		user := &pkg.User{ID: babyapi.NewID(), Name: "Alex", Role: pkg.UserRoleViewer}
+		require.NoError(t, storageClient.Users.Set(context.Background(), user))
+
+		garden := createExampleGarden()
+		garden.Users = map[string]pkg.UserRole{user.GetID(): pkg.UserRoleOperator}
+		require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+
+		r, err := http.NewRequest(http.MethodDelete, "/users/"+user.GetID(), http.NoBody)
+		require.NoError(t, err)
+		w := babytest.TestRequest(t, api.API, r)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		updated, err := storageClient.Gardens.Get(context.Background(), garden.GetID())
+		require.NoError(t, err)
+		assert.Empty(t, updated.Users)
+	})
+}
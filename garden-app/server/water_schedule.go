@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
@@ -18,6 +20,9 @@ import (
 const (
 	waterScheduleBasePath   = "/water_schedules"
 	waterScheduleIDLogField = "water_schedule_id"
+	pausePath               = "/pause"
+	resumePath              = "/resume"
+	skipPath                = "/skip"
 )
 
 // WaterSchedulesAPI provides and API for interacting with WaterSchedules
@@ -99,11 +104,182 @@ func NewWaterSchedulesAPI() *WaterSchedulesAPI {
 		}
 	}))
 
+	api.AddCustomIDRoute(http.MethodGet, "/next", api.GetRequestedResourceAndDo(api.nextWaterTimes))
+	api.AddCustomIDRoute(http.MethodGet, "/simulate", api.GetRequestedResourceAndDo(api.simulate))
+
+	api.AddCustomIDRoute(http.MethodPost, restorePath, api.GetRequestedResourceAndDo(api.restore))
+
+	api.AddCustomIDRoute(http.MethodPost, pausePath, api.GetRequestedResourceAndDo(api.setPaused(true)))
+	api.AddCustomIDRoute(http.MethodPost, resumePath, api.GetRequestedResourceAndDo(api.setPaused(false)))
+
+	api.AddCustomIDRoute(http.MethodPost, skipPath, api.GetRequestedResourceAndDo(api.skip))
+
 	api.ApplyExtension(extensions.HTMX[*pkg.WaterSchedule]{})
+	api.ApplyExtension(conditionalRequests[*pkg.WaterSchedule]{})
+	api.ApplyExtension(revisionHistory[*pkg.WaterSchedule]{
+		resourceType: "water_schedule",
+		storage:      func() storage.RevisionStorage { return api.storageClient.Revisions },
+	})
 
 	return api
 }
 
+const (
+	defaultNextWaterTimesCount = 5
+	maxNextWaterTimesCount     = 100
+	maxSkipCount               = 100
+	defaultSimulationDays      = 14
+	maxSimulationDays          = 90
+)
+
+// nextWaterTimes responds with the upcoming times that the WaterSchedule will water. The number of times is set
+// with the "count" query parameter
+func (api *WaterSchedulesAPI) nextWaterTimes(r *http.Request, ws *pkg.WaterSchedule) (render.Renderer, *babyapi.ErrResponse) {
+	count := defaultNextWaterTimesCount
+	if countParam := r.URL.Query().Get("count"); countParam != "" {
+		var err error
+		count, err = strconv.Atoi(countParam)
+		if err != nil || count < 1 || count > maxNextWaterTimesCount {
+			return nil, babyapi.ErrInvalidRequest(fmt.Errorf("count must be an integer from 1 to %d", maxNextWaterTimesCount))
+		}
+	}
+
+	times, err := api.worker.GetNextWaterTimes(ws, count)
+	if err != nil {
+		return nil, babyapi.InternalServerError(fmt.Errorf("error getting next water times: %w", err))
+	}
+
+	loc, httpErr := responseLocation(r, ws)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	resp := &NextWaterTimesResponse{Times: []time.Time{}}
+	for _, t := range times {
+		resp.Times = append(resp.Times, t.In(loc))
+	}
+
+	return resp, nil
+}
+
+// simulate responds with the projected waterings and durations of the WaterSchedule using the current weather data.
+// The number of days to simulate is set with the "days" query parameter
+func (api *WaterSchedulesAPI) simulate(r *http.Request, ws *pkg.WaterSchedule) (render.Renderer, *babyapi.ErrResponse) {
+	days := defaultSimulationDays
+	if daysParam := r.URL.Query().Get("days"); daysParam != "" {
+		var err error
+		days, err = strconv.Atoi(daysParam)
+		if err != nil || days < 1 || days > maxSimulationDays {
+			return nil, babyapi.ErrInvalidRequest(fmt.Errorf("days must be an integer from 1 to %d", maxSimulationDays))
+		}
+	}
+
+	loc, httpErr := responseLocation(r, ws)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	simulation, err := api.worker.SimulateWaterSchedule(ws, api.worker.Now().AddDate(0, 0, days))
+	if err != nil {
+		return nil, babyapi.InternalServerError(fmt.Errorf("error simulating WaterSchedule: %w", err))
+	}
+
+	return NewWaterScheduleSimulationResponse(simulation, days, loc), nil
+}
+
+// responseLocation returns the time zone used for times in a response. The "X-TZ-Offset" header is used if it is
+// set, otherwise the WaterSchedule's StartTime is used
+func responseLocation(r *http.Request, ws *pkg.WaterSchedule) (*time.Location, *babyapi.ErrResponse) {
+	if tzHeader := r.Header.Get("X-TZ-Offset"); tzHeader != "" {
+		loc, err := pkg.TimeLocationFromOffset(tzHeader)
+		if err != nil {
+			return nil, babyapi.ErrInvalidRequest(fmt.Errorf("error parsing timezone from header: %w", err))
+		}
+		return loc, nil
+	}
+	if ws.StartTime != nil {
+		return ws.StartTime.Time.Location(), nil
+	}
+	return time.UTC, nil
+}
+
+// skip sets the number of upcoming scheduled waterings that will be skipped using the "count" query parameter,
+// which defaults to 1. A count of 0 stops skipping
+func (api *WaterSchedulesAPI) skip(r *http.Request, ws *pkg.WaterSchedule) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	count := 1
+	if countParam := r.URL.Query().Get("count"); countParam != "" {
+		var err error
+		count, err = strconv.Atoi(countParam)
+		if err != nil || count < 0 || count > maxSkipCount {
+			return nil, babyapi.ErrInvalidRequest(fmt.Errorf("count must be an integer from 0 to %d", maxSkipCount))
+		}
+	}
+	logger.Info("received request to skip WaterSchedule", "count", count)
+
+	if ws.EndDated() {
+		return nil, babyapi.ErrInvalidRequest(errors.New("unable to skip end-dated WaterSchedule"))
+	}
+
+	ws.SkipCount = uint(count)
+
+	err := api.storageClient.WaterSchedules.Set(r.Context(), ws)
+	if err != nil {
+		logger.Error("unable to save WaterSchedule", "error", err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return api.NewWaterScheduleResponse(ws), nil
+}
+
+// restore clears the WaterSchedule's EndDate and schedules it again
+func (api *WaterSchedulesAPI) restore(r *http.Request, ws *pkg.WaterSchedule) (render.Renderer, *babyapi.ErrResponse) {
+	return restoreResource(r, ws, "WaterSchedule", api.storageClient.WaterSchedules, api.onCreateOrUpdate, func(ws *pkg.WaterSchedule) render.Renderer {
+		return api.NewWaterScheduleResponse(ws)
+	})
+}
+
+// setPaused returns a handler that pauses or resumes the WaterSchedule. Paused WaterSchedules keep their Zones,
+// but their scheduled Job is removed until they are resumed
+func (api *WaterSchedulesAPI) setPaused(paused bool) func(*http.Request, *pkg.WaterSchedule) (render.Renderer, *babyapi.ErrResponse) {
+	action := "resume"
+	if paused {
+		action = "pause"
+	}
+
+	return func(r *http.Request, ws *pkg.WaterSchedule) (render.Renderer, *babyapi.ErrResponse) {
+		logger := babyapi.GetLoggerFromContext(r.Context())
+		logger.Info("received request to " + action + " WaterSchedule")
+
+		if ws.EndDated() {
+			return nil, babyapi.ErrInvalidRequest(fmt.Errorf("unable to %s end-dated WaterSchedule", action))
+		}
+		switch {
+		case paused && ws.Paused:
+			return nil, babyapi.ErrInvalidRequest(errors.New("WaterSchedule is already paused"))
+		case !paused && !ws.Paused:
+			return nil, babyapi.ErrInvalidRequest(errors.New("WaterSchedule is not paused"))
+		}
+
+		ws.Paused = paused
+
+		httpErr := api.onCreateOrUpdate(r, ws)
+		if httpErr != nil {
+			logger.Error("unable to "+action+" WaterSchedule", "error", httpErr)
+			return nil, httpErr
+		}
+
+		err := api.storageClient.WaterSchedules.Set(r.Context(), ws)
+		if err != nil {
+			logger.Error("unable to save WaterSchedule", "error", err)
+			return nil, babyapi.InternalServerError(err)
+		}
+
+		return api.NewWaterScheduleResponse(ws), nil
+	}
+}
+
 func (api *WaterSchedulesAPI) setup(storageClient *storage.Client, worker *worker.Worker) error {
 	api.storageClient = storageClient
 	api.worker = worker
@@ -116,7 +292,7 @@ func (api *WaterSchedulesAPI) setup(storageClient *storage.Client, worker *worke
 		return fmt.Errorf("unable to get WaterSchedules: %v", err)
 	}
 	for _, ws := range allWaterSchedules {
-		if ws.EndDated() {
+		if !ws.Scheduled() {
 			continue
 		}
 		err = api.worker.ScheduleWaterAction(ws)
@@ -133,7 +309,8 @@ func (api *WaterSchedulesAPI) onCreateOrUpdate(r *http.Request, ws *pkg.WaterSch
 	if ws.WeatherControl != nil {
 		err := api.weatherClientsExist(r.Context(), ws)
 		if err != nil {
-			if errors.Is(err, babyapi.ErrNotFound) {
+			if errors.Is(err, babyapi.ErrNotFound) || errors.Is(err, errRainForecastUnsupported) ||
+				errors.Is(err, errTemperatureForecastUnsupported) {
 				return babyapi.ErrInvalidRequest(fmt.Errorf("unable to get WeatherClients for WaterSchedule: %w", err))
 			}
 			return babyapi.InternalServerError(err)
@@ -145,9 +322,35 @@ func (api *WaterSchedulesAPI) onCreateOrUpdate(r *http.Request, ws *pkg.WaterSch
 		}
 	}
 
+	// Validate the new WaterSchedule.ActivePeriod since patching may have combined months and dates
+	if ws.ActivePeriod != nil {
+		err := ws.ActivePeriod.Validate()
+		if err != nil {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("invalid WaterSchedule.ActivePeriod after patching: %w", err))
+		}
+	}
+
+	// Validate the new limits since patching may have changed only one of them
+	err := ws.ValidateDurationLimits()
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid WaterSchedule duration limits after patching: %w", err))
+	}
+
+	if ws.Scheduled() {
+		err := api.validateNoZoneConflicts(r.Context(), ws)
+		if err != nil {
+			return err
+		}
+	}
+
 	if !ws.EndDated() {
 		// logger.Info("updating/resetting WaterSchedule for WaterSchedule")
-		err := api.worker.ResetWaterSchedule(ws)
+		var err error
+		if ws.Paused {
+			err = api.worker.PauseWaterSchedule(ws)
+		} else {
+			err = api.worker.ResetWaterSchedule(ws)
+		}
 		if err != nil {
 			return babyapi.InternalServerError(fmt.Errorf("unable to update/reset WaterSchedule: %w", err))
 		}
@@ -156,6 +359,50 @@ func (api *WaterSchedulesAPI) onCreateOrUpdate(r *http.Request, ws *pkg.WaterSch
 	return nil
 }
 
+// validateNoZoneConflicts makes sure that changes to a WaterSchedule do not cause it to overlap with other
+// WaterSchedules used by the same Zones
+func (api *WaterSchedulesAPI) validateNoZoneConflicts(ctx context.Context, ws *pkg.WaterSchedule) *babyapi.ErrResponse {
+	zonesAndGardens, err := api.storageClient.GetZonesUsingWaterSchedule(ws.GetID())
+	if err != nil {
+		return babyapi.InternalServerError(fmt.Errorf("unable to get Zones using WaterSchedule: %w", err))
+	}
+
+	for _, zg := range zonesAndGardens {
+		waterScheduleIDs, err := api.storageClient.GetWaterScheduleIDsForZone(ctx, zg.Zone)
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("unable to get WaterSchedules for Zone: %w", err))
+		}
+
+		waterSchedules := []*pkg.WaterSchedule{}
+		for _, id := range waterScheduleIDs {
+			if id == ws.ID.ID {
+				continue
+			}
+
+			other, err := api.storageClient.WaterSchedules.Get(ctx, id.String())
+			if err != nil {
+				if errors.Is(err, babyapi.ErrNotFound) {
+					continue
+				}
+				return babyapi.InternalServerError(fmt.Errorf("error getting WaterSchedule with ID %q: %w", id, err))
+			}
+			waterSchedules = append(waterSchedules, other)
+		}
+
+		err = ws.ValidateNoConflictsWith(waterSchedules)
+		if err != nil {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("invalid WaterSchedule for Zone %q: %w", zg.Zone.GetID(), err))
+		}
+	}
+
+	return nil
+}
+
+var (
+	errRainForecastUnsupported        = errors.New("does not support rain forecasts")
+	errTemperatureForecastUnsupported = errors.New("does not support temperature forecasts")
+)
+
 func (api *WaterSchedulesAPI) weatherClientsExist(ctx context.Context, ws *pkg.WaterSchedule) error {
 	if ws.HasTemperatureControl() {
 		err := api.weatherClientExists(ctx, ws.WeatherControl.Temperature.ClientID)
@@ -171,6 +418,26 @@ func (api *WaterSchedulesAPI) weatherClientsExist(ctx context.Context, ws *pkg.W
 		}
 	}
 
+	if ws.HasRainForecastControl() {
+		wc, err := api.storageClient.WeatherClientConfigs.Get(ctx, ws.WeatherControl.RainForecast.ClientID.String())
+		if err != nil {
+			return fmt.Errorf("error getting client for RainForecastControl: error getting WeatherClient with ID %q: %w", ws.WeatherControl.RainForecast.ClientID, err)
+		}
+		if !wc.SupportsForecast() {
+			return fmt.Errorf("invalid client for RainForecastControl: WeatherClient type %q %w", wc.Type, errRainForecastUnsupported)
+		}
+	}
+
+	if ws.HasFrostControl() {
+		wc, err := api.storageClient.WeatherClientConfigs.Get(ctx, ws.WeatherControl.Frost.ClientID.String())
+		if err != nil {
+			return fmt.Errorf("error getting client for FrostControl: error getting WeatherClient with ID %q: %w", ws.WeatherControl.Frost.ClientID, err)
+		}
+		if !wc.SupportsForecast() {
+			return fmt.Errorf("invalid client for FrostControl: WeatherClient type %q %w", wc.Type, errTemperatureForecastUnsupported)
+		}
+	}
+
 	return nil
 }
 
@@ -20,23 +20,35 @@ type NextWaterDetails struct {
 	Duration        *pkg.Duration `json:"duration,omitempty"`
 	WaterScheduleID *xid.ID       `json:"water_schedule_id,omitempty"`
 	Message         string        `json:"message,omitempty"`
+	// DeferredUntil is set when the Time is in a BlackoutWindow, so watering will start when the window ends
+	DeferredUntil *time.Time `json:"deferred_until,omitempty"`
 }
 
-// GetNextWaterDetails returns the NextWaterDetails for the WaterSchedule
-func GetNextWaterDetails(r *http.Request, ws *pkg.WaterSchedule, worker *worker.Worker, excludeWeatherData bool) NextWaterDetails {
+// GetNextWaterDetails returns the NextWaterDetails for the WaterSchedule. If zone is not nil, the duration is
+// scaled for the Zone's soil type and crop coefficient, and rain controls are skipped if the Zone skips them. If
+// garden is not nil, it is also scaled for the Garden's seasonal adjustment in the month of the next watering
+func GetNextWaterDetails(r *http.Request, ws *pkg.WaterSchedule, garden *pkg.Garden, zone *pkg.Zone, worker *worker.Worker, excludeWeatherData bool) NextWaterDetails {
 	result := NextWaterDetails{
-		Time:     worker.GetNextWaterTime(ws),
-		Duration: ws.Duration,
+		Time: worker.GetNextWaterTime(ws),
 	}
+	ws = ws.ForZone(zone)
 
+	duration := ws.Duration.Duration
 	if ws.HasWeatherControl() && !excludeWeatherData {
-		wd, hadErr := worker.ScaleWateringDuration(ws)
+		wd, hadErr := worker.ScaleWateringDuration(r.Context(), ws)
 		if hadErr {
 			result.Message = "error impacted duration scaling"
 		}
 
-		result.Duration = &pkg.Duration{Duration: time.Duration(wd)}
+		duration = wd
 	}
+	if zone != nil {
+		duration = zone.ScaleWaterDuration(duration)
+	}
+	if garden != nil && result.Time != nil {
+		duration = pkg.ScaleDuration(duration, garden.SeasonalScale(*result.Time))
+	}
+	result.Duration = &pkg.Duration{Duration: ws.ClampDuration(duration)}
 
 	var loc *time.Location
 	tzHeader := r.Header.Get("X-TZ-Offset")
@@ -47,16 +59,92 @@ func GetNextWaterDetails(r *http.Request, ws *pkg.WaterSchedule, worker *worker.
 			result.Message = fmt.Sprintf("error parsing timezone from header: %v", err)
 		}
 	}
-	if loc == nil {
+	if loc == nil && ws.StartTime != nil {
 		loc = ws.StartTime.Time.Location()
 	}
+	if loc == nil {
+		// cron Intervals might not have a StartTime, so use the scheduler's time zone
+		loc = time.UTC
+	}
 
-	offsetTime := result.Time.In(loc)
-	result.Time = &offsetTime
+	if result.Time != nil {
+		offsetTime := result.Time.In(loc)
+		result.Time = &offsetTime
+	}
 
 	return result
 }
 
+// NextWaterTimesResponse lists the upcoming times that a WaterSchedule will water
+type NextWaterTimesResponse struct {
+	Times []time.Time `json:"times"`
+}
+
+// Render ...
+func (*NextWaterTimesResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// WaterScheduleSimulationResponse lists the projected waterings of a WaterSchedule using the current weather data
+type WaterScheduleSimulationResponse struct {
+	Days        int                         `json:"days"`
+	ScaleFactor float32                     `json:"scale_factor"`
+	Waterings   []SimulatedWateringResponse `json:"waterings"`
+}
+
+// SimulatedWateringResponse is a projected watering. The Duration is after weather scaling and the Zones have the
+// final duration after each Zone's scaling and the WaterSchedule's limits
+type SimulatedWateringResponse struct {
+	Time            time.Time                       `json:"time"`
+	Duration        *pkg.Duration                   `json:"duration"`
+	FrostProtection bool                            `json:"frost_protection,omitempty"`
+	SkipReason      string                          `json:"skip_reason,omitempty"`
+	Zones           []SimulatedZoneWateringResponse `json:"zones"`
+}
+
+// SimulatedZoneWateringResponse is the projected watering for one Zone
+type SimulatedZoneWateringResponse struct {
+	ZoneID     xid.ID        `json:"zone_id"`
+	Name       string        `json:"name"`
+	Duration   *pkg.Duration `json:"duration"`
+	SkipReason string        `json:"skip_reason,omitempty"`
+}
+
+// NewWaterScheduleSimulationResponse creates a response from the Worker's simulation with times in the location
+func NewWaterScheduleSimulationResponse(simulation *worker.WaterScheduleSimulation, days int, loc *time.Location) *WaterScheduleSimulationResponse {
+	resp := &WaterScheduleSimulationResponse{
+		Days:        days,
+		ScaleFactor: simulation.ScaleFactor,
+		Waterings:   []SimulatedWateringResponse{},
+	}
+
+	for _, watering := range simulation.Waterings {
+		wateringResp := SimulatedWateringResponse{
+			Time:            watering.Time.In(loc),
+			Duration:        &pkg.Duration{Duration: watering.Duration},
+			FrostProtection: watering.FrostProtection,
+			SkipReason:      watering.SkipReason,
+			Zones:           []SimulatedZoneWateringResponse{},
+		}
+		for _, zw := range watering.Zones {
+			wateringResp.Zones = append(wateringResp.Zones, SimulatedZoneWateringResponse{
+				ZoneID:     zw.Zone.ID.ID,
+				Name:       zw.Zone.Name,
+				Duration:   &pkg.Duration{Duration: zw.Duration},
+				SkipReason: zw.SkipReason,
+			})
+		}
+		resp.Waterings = append(resp.Waterings, wateringResp)
+	}
+
+	return resp
+}
+
+// Render ...
+func (*WaterScheduleSimulationResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
 // WaterScheduleResponse is used to represent a WaterSchedule in the response body with the additional Moisture data
 // and hypermedia Links fields
 type WaterScheduleResponse struct {
@@ -89,11 +177,16 @@ func (ws *WaterScheduleResponse) Render(w http.ResponseWriter, r *http.Request)
 	)
 
 	if ws.HasWeatherControl() && !ws.EndDated() && !excludeWeatherData(r) {
-		ws.WeatherData = getWeatherData(r.Context(), ws.WaterSchedule, ws.api.storageClient)
+		ws.WeatherData = getWeatherData(r.Context(), ws.WaterSchedule, nil, nil, ws.api.storageClient)
 	}
 
-	if !ws.EndDated() {
-		ws.NextWater = GetNextWaterDetails(r, ws.WaterSchedule, ws.api.worker, excludeWeatherData(r))
+	if ws.Paused {
+		ws.NextWater = NextWaterDetails{Message: "WaterSchedule is paused"}
+	} else if !ws.EndDated() {
+		ws.NextWater = GetNextWaterDetails(r, ws.WaterSchedule, nil, nil, ws.api.worker, excludeWeatherData(r))
+		if ws.SkipCount > 0 && ws.NextWater.Message == "" {
+			ws.NextWater.Message = fmt.Sprintf("skip_count %d affected the time", ws.SkipCount)
+		}
 	}
 
 	if render.GetAcceptedContentType(r) == render.ContentTypeHTML && r.Method == http.MethodPut {
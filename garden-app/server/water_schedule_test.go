@@ -75,7 +75,7 @@ func TestGetWaterSchedule(t *testing.T) {
 					},
 				},
 			},
-			`{"id":"c5cvhpcbcv45e8bp16dg","duration":"1h0m0s","interval":"24h0m0s","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":"11:24:52-07:00","weather_control":{"rain_control":{"baseline_value":0,"factor":0,"range":25.4,"client_id":"c5cvhpcbcv45e8bp16dg"},"temperature_control":{"baseline_value":30,"factor":0.5,"range":10,"client_id":"c5cvhpcbcv45e8bp16dg"}},"weather_data":{"rain":{"mm":25.4,"scale_factor":0},"average_temperature":{"celsius":80,"scale_factor":1.5}},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"0s"},"links":\[{"rel":"self","href":"/water_schedules/c5cvhpcbcv45e8bp16dg"}\]}`,
+			`{"id":"c5cvhpcbcv45e8bp16dg","duration":"1h0m0s","interval":"24h0m0s","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":"11:24:52-07:00","weather_control":{"rain_control":{"baseline_value":0,"factor":0,"range":25.4,"client_id":"c5cvhpcbcv45e8bp16dg"},"temperature_control":{"baseline_value":30,"factor":0.5,"range":10,"client_id":"c5cvhpcbcv45e8bp16dg"}},"weather_data":{"rain":{"mm":25.4,"scale_factor":0},"average_temperature":{"celsius":80,"scale_factor":1.5},"scale_factor":0,"effective_duration":"0s"},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"0s"},"links":\[{"rel":"self","href":"/water_schedules/c5cvhpcbcv45e8bp16dg"}\]}`,
 		},
 		{
 			"SuccessfulWithRainAndTemperatureDataButWeatherDataExcluded",
@@ -121,7 +121,7 @@ func TestGetWaterSchedule(t *testing.T) {
 					},
 				},
 			},
-			`{"id":"c5cvhpcbcv45e8bp16dg","duration":"1h0m0s","interval":"24h0m0s","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":"11:24:52-07:00","weather_control":{"rain_control":{"baseline_value":0,"factor":0,"range":25.4,"client_id":"chkodpg3lcj13q82mq40"}},"weather_data":{},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"59m59.99995904s","message":"error impacted duration scaling"},"links":\[{"rel":"self","href":"/water_schedules/c5cvhpcbcv45e8bp16dg"}\]}`,
+			`{"id":"c5cvhpcbcv45e8bp16dg","duration":"1h0m0s","interval":"24h0m0s","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":"11:24:52-07:00","weather_control":{"rain_control":{"baseline_value":0,"factor":0,"range":25.4,"client_id":"chkodpg3lcj13q82mq40"}},"weather_data":{},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"1h0m0s","message":"error impacted duration scaling"},"links":\[{"rel":"self","href":"/water_schedules/c5cvhpcbcv45e8bp16dg"}\]}`,
 		},
 		{
 			"ErrorTemperatureWeatherClientDNE",
@@ -141,7 +141,7 @@ func TestGetWaterSchedule(t *testing.T) {
 					},
 				},
 			},
-			`{"id":"c5cvhpcbcv45e8bp16dg","duration":"1h0m0s","interval":"24h0m0s","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":"11:24:52-07:00","weather_control":{"temperature_control":{"baseline_value":30,"factor":0.5,"range":10,"client_id":"chkodpg3lcj13q82mq40"}},"weather_data":{},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"59m59.99995904s","message":"error impacted duration scaling"},"links":\[{"rel":"self","href":"/water_schedules/c5cvhpcbcv45e8bp16dg"}\]}`,
+			`{"id":"c5cvhpcbcv45e8bp16dg","duration":"1h0m0s","interval":"24h0m0s","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":"11:24:52-07:00","weather_control":{"temperature_control":{"baseline_value":30,"factor":0.5,"range":10,"client_id":"chkodpg3lcj13q82mq40"}},"weather_data":{},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"1h0m0s","message":"error impacted duration scaling"},"links":\[{"rel":"self","href":"/water_schedules/c5cvhpcbcv45e8bp16dg"}\]}`,
 		},
 	}
 
@@ -214,6 +214,105 @@ func TestGetWaterSchedule(t *testing.T) {
 	}
 }
 
+func TestNextWaterTimes(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedRegexp string
+		status         int
+	}{
+		{
+			"Default",
+			"",
+			`{"times":\[("\d{4}-\d{2}-\d\dT11:24:52-07:00",?){5}\]}`,
+			http.StatusOK,
+		},
+		{
+			"Count",
+			"?count=2",
+			`{"times":\["\d{4}-\d{2}-\d\dT11:24:52-07:00","\d{4}-\d{2}-\d\dT11:24:52-07:00"\]}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorInvalidCount",
+			"?count=0",
+			`{"status":"Invalid request.","error":"count must be an integer from 1 to 100"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			assert.NoError(t, err)
+
+			err = storageClient.WaterSchedules.Set(context.Background(), createExampleWaterSchedule())
+			assert.NoError(t, err)
+
+			wsr := NewWaterSchedulesAPI()
+			err = wsr.setup(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+			require.NoError(t, err)
+
+			wsr.worker.StartAsync()
+			defer wsr.worker.Stop()
+
+			r := httptest.NewRequest(http.MethodGet, "/water_schedules/"+id.String()+"/next"+tt.query, http.NoBody)
+			r.Header.Set("X-TZ-Offset", "420")
+			w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestSimulateWaterSchedule(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedRegexp string
+		status         int
+	}{
+		{
+			"Days",
+			"?days=2",
+			`{"days":2,"scale_factor":1,"waterings":\[{"time":"\d{4}-\d{2}-\d\dT11:24:52-07:00","duration":"1s","zones":\[{"zone_id":"c5cvhpcbcv45e8bp16dg","name":"test-zone","duration":"1s"}\]},{"time":"\d{4}-\d{2}-\d\dT11:24:52-07:00","duration":"1s","zones":\[{"zone_id":"c5cvhpcbcv45e8bp16dg","name":"test-zone","duration":"1s"}\]}\]}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorInvalidDays",
+			"?days=91",
+			`{"status":"Invalid request.","error":"days must be an integer from 1 to 90"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneAndGardenStorage(t)
+
+			err := storageClient.WaterSchedules.Set(context.Background(), createExampleWaterSchedule())
+			assert.NoError(t, err)
+
+			wsr := NewWaterSchedulesAPI()
+			err = wsr.setup(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+			require.NoError(t, err)
+
+			wsr.worker.StartAsync()
+			defer wsr.worker.Stop()
+
+			r := httptest.NewRequest(http.MethodGet, "/water_schedules/"+id.String()+"/simulate"+tt.query, http.NoBody)
+			r.Header.Set("X-TZ-Offset", "420")
+			w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
 func TestUpdateWaterSchedule(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -284,6 +383,70 @@ func TestUpdateWaterSchedule(t *testing.T) {
 	}
 }
 
+func TestUpdateWaterScheduleConflictWithZone(t *testing.T) {
+	otherWS := createExampleWaterSchedule()
+	otherWS.ID = babyapi.ID{ID: id2}
+	otherWS.StartTime = pkg.NewStartTime(createdAt.Add(time.Hour))
+
+	// This WaterSchedule overlaps with otherWS, but that should not prevent updating an unrelated WaterSchedule
+	overlappingOtherWS := createExampleWaterSchedule()
+	overlappingOtherWS.ID = babyapi.ID{ID: xid.ID{1}}
+	overlappingOtherWS.StartTime = pkg.NewStartTime(createdAt.Add(time.Hour))
+
+	zone := createExampleZone()
+	zone.WaterScheduleIDs = append(zone.WaterScheduleIDs, otherWS.ID.ID, overlappingOtherWS.ID.ID)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedRegexp string
+		status         int
+	}{
+		{
+			"SuccessfulNoOverlap",
+			`{"duration":"30m"}`,
+			`"duration":"30m0s"`,
+			http.StatusOK,
+		},
+		{
+			"ErrorOverlap",
+			`{"duration":"2h"}`,
+			`{"status":"Invalid request.","error":"invalid WaterSchedule for Zone \\"c5cvhpcbcv45e8bp16dg\\": WaterSchedules \\"c5cvhpcbcv45e8bp16dg\\" and \\"chkodpg3lcj13q82mq40\\" overlap at \d{4}-\d{2}-\d\dT12:24:52-07:00"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupStorage(t, createExampleGarden())
+
+			err := storageClient.WaterSchedules.Set(context.Background(), createExampleWaterSchedule())
+			assert.NoError(t, err)
+			err = storageClient.WaterSchedules.Set(context.Background(), otherWS)
+			assert.NoError(t, err)
+			err = storageClient.WaterSchedules.Set(context.Background(), overlappingOtherWS)
+			assert.NoError(t, err)
+			err = storageClient.Zones.Set(context.Background(), zone)
+			assert.NoError(t, err)
+
+			wsr := NewWaterSchedulesAPI()
+			err = wsr.setup(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+			require.NoError(t, err)
+
+			wsr.worker.StartAsync()
+			defer wsr.worker.Stop()
+
+			r := httptest.NewRequest(http.MethodPatch, "/water_schedules/"+createExampleWaterSchedule().GetID(), strings.NewReader(tt.body))
+			r.Header.Set("X-TZ-Offset", "420")
+			r.Header.Set("Content-Type", "application/json")
+			w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
 func TestEndDateWaterSchedule(t *testing.T) {
 	now := time.Now()
 	endDatedWaterSchedule := createExampleWaterSchedule()
@@ -357,6 +520,143 @@ func TestEndDateWaterSchedule(t *testing.T) {
 	}
 }
 
+func TestPauseAndResumeWaterSchedule(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	ws := createExampleWaterSchedule()
+	require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+
+	wkr := worker.NewWorker(storageClient, nil, nil, slog.Default())
+	wsr := NewWaterSchedulesAPI()
+	require.NoError(t, wsr.setup(storageClient, wkr))
+	wkr.StartAsync()
+	defer wkr.Stop()
+	require.NotNil(t, wkr.GetNextWaterTime(ws))
+
+	t.Run("Pause", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/water_schedules/%s/pause", ws.ID), http.NoBody)
+		w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Regexp(t, `"paused":true,.*"next_water":{"message":"WaterSchedule is paused"}`, w.Body.String())
+
+		stored, err := storageClient.WaterSchedules.Get(context.Background(), ws.GetID())
+		require.NoError(t, err)
+		assert.True(t, stored.Paused)
+		assert.Nil(t, wkr.GetNextWaterTime(stored))
+	})
+
+	t.Run("ErrorAlreadyPaused", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/water_schedules/%s/pause", ws.ID), http.NoBody)
+		w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"status":"Invalid request.","error":"WaterSchedule is already paused"}`, strings.TrimSpace(w.Body.String()))
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/water_schedules/%s/resume", ws.ID), http.NoBody)
+		w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		stored, err := storageClient.WaterSchedules.Get(context.Background(), ws.GetID())
+		require.NoError(t, err)
+		assert.False(t, stored.Paused)
+		assert.NotNil(t, wkr.GetNextWaterTime(stored))
+	})
+
+	t.Run("ErrorNotPaused", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/water_schedules/%s/resume", ws.ID), http.NoBody)
+		w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"status":"Invalid request.","error":"WaterSchedule is not paused"}`, strings.TrimSpace(w.Body.String()))
+	})
+}
+
+func TestSkipWaterSchedule(t *testing.T) {
+	endDate := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name              string
+		query             string
+		endDate           *time.Time
+		expectedRegexp    string
+		expectedSkipCount uint
+		status            int
+	}{
+		{
+			"SuccessfulDefaultCount",
+			"",
+			nil,
+			`"skip_count":1,"next_water":{"time":".*","duration":"1s","message":"skip_count 1 affected the time"}`,
+			1,
+			http.StatusOK,
+		},
+		{
+			"SuccessfulWithCount",
+			"?count=3",
+			nil,
+			`"skip_count":3,"next_water":{"time":".*","duration":"1s","message":"skip_count 3 affected the time"}`,
+			3,
+			http.StatusOK,
+		},
+		{
+			"SuccessfulClear",
+			"?count=0",
+			nil,
+			`"start_time":"11:24:52-07:00","next_water":{"time":".*","duration":"1s"}`,
+			0,
+			http.StatusOK,
+		},
+		{
+			"ErrorInvalidCount",
+			"?count=-1",
+			nil,
+			`{"status":"Invalid request.","error":"count must be an integer from 0 to 100"}`,
+			0,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorEndDated",
+			"",
+			&endDate,
+			`{"status":"Invalid request.","error":"unable to skip end-dated WaterSchedule"}`,
+			0,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			ws := createExampleWaterSchedule()
+			ws.EndDate = tt.endDate
+			require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+
+			wkr := worker.NewWorker(storageClient, nil, nil, slog.Default())
+			wsr := NewWaterSchedulesAPI()
+			require.NoError(t, wsr.setup(storageClient, wkr))
+			wkr.StartAsync()
+			defer wkr.Stop()
+
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/water_schedules/%s/skip%s", ws.ID, tt.query), http.NoBody)
+			w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+
+			stored, err := storageClient.WaterSchedules.Get(context.Background(), ws.GetID())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSkipCount, stored.SkipCount)
+		})
+	}
+}
+
 func TestGetAllWaterSchedules(t *testing.T) {
 	waterSchedule := createExampleWaterSchedule()
 	endDatedWaterSchedule := createExampleWaterSchedule()
@@ -432,6 +732,30 @@ func TestCreateWaterSchedule(t *testing.T) {
 			`{"id":"[0-9a-v]{20}","duration":"1s","interval":"24h0m0s","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":"11:24:52-07:00","next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"1s"},"links":\[{"rel":"self","href":"/water_schedules/[0-9a-v]{20}"}\]}`,
 			http.StatusCreated,
 		},
+		{
+			"SuccessfulCronWithoutStartTime",
+			`{"duration":"1s","interval":"cron:0 6 * * 1,3,5"}`,
+			`{"id":"[0-9a-v]{20}","duration":"1s","interval":"cron:0 6 \* \* 1,3,5","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":null,"next_water":{"time":"\d\d\d\d-\d\d-\d\dT23:00:00-07:00","duration":"1s"},"links":\[{"rel":"self","href":"/water_schedules/[0-9a-v]{20}"}\]}`,
+			http.StatusCreated,
+		},
+		{
+			"SuccessfulWithCycles",
+			`{"interval":"24h0m0s","start_time":"11:24:52-07:00","cycles":{"count":3,"water":"2m","soak":"10m"}}`,
+			`{"id":"[0-9a-v]{20}","duration":"6m0s","interval":"24h0m0s","start_date":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","start_time":"11:24:52-07:00","cycles":{"count":3,"water":"2m0s","soak":"10m0s"},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"6m0s"},"links":\[{"rel":"self","href":"/water_schedules/[0-9a-v]{20}"}\]}`,
+			http.StatusCreated,
+		},
+		{
+			"ErrorInvalidCycles",
+			`{"interval":"24h0m0s","start_time":"11:24:52-07:00","cycles":{"count":3,"soak":"10m"}}`,
+			`{"status":"Invalid request.","error":"error validating cycles: missing required water field"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorMissingStartTime",
+			`{"duration":"1s","interval":"24h0m0s"}`,
+			`{"status":"Invalid request.","error":"missing required start_time field"}`,
+			http.StatusBadRequest,
+		},
 		{
 			"ErrorRainWeatherClientDNE",
 			`{"duration":"1s","interval":"24h0m0s","start_time":"11:24:52-07:00", "weather_control":{"rain_control":{"baseline_value":0,"factor":0,"range":25.4,"client_id":"c5cvhpcbcv45e8bp16dg"}}}`,
@@ -489,6 +813,55 @@ func TestCreateWaterSchedule(t *testing.T) {
 	}
 }
 
+func TestCreateWaterScheduleForecastUnsupportedClient(t *testing.T) {
+	tests := []struct {
+		name           string
+		weatherControl string
+		expectedError  string
+	}{
+		{
+			"RainForecast",
+			`{"rain_forecast_control":{"threshold":5,"hours_ahead":12,"client_id":"c5cvhpcbcv45e8bp16dg"}}`,
+			`invalid client for RainForecastControl: WeatherClient type \"netatmo\" does not support rain forecasts`,
+		},
+		{
+			"Frost",
+			`{"frost_control":{"mode":"inhibit","threshold":0,"hours_ahead":12,"client_id":"c5cvhpcbcv45e8bp16dg"}}`,
+			`invalid client for FrostControl: WeatherClient type \"netatmo\" does not support temperature forecasts`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			assert.NoError(t, err)
+
+			err = storageClient.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+				ID:   babyapi.ID{ID: id},
+				Type: "netatmo",
+			})
+			assert.NoError(t, err)
+
+			wsr := NewWaterSchedulesAPI()
+			err = wsr.setup(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+			require.NoError(t, err)
+
+			wsr.worker.StartAsync()
+			defer wsr.worker.Stop()
+
+			body := `{"duration":"1s","interval":"24h0m0s","start_time":"11:24:52-07:00","weather_control":` + tt.weatherControl + `}`
+			r := httptest.NewRequest(http.MethodPost, "/water_schedules", strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			w := babytest.TestRequest[*pkg.WaterSchedule](t, wsr.API, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, `{"status":"Invalid request.","error":"unable to get WeatherClients for WaterSchedule: `+tt.expectedError+`"}`, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
 func TestUpdateWaterSchedulePUT(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -734,6 +1107,51 @@ func TestWaterScheduleRequest(t *testing.T) {
 			},
 			"error validating weather_control: error validating moisture_control: missing required field: minimum_moisture",
 		},
+		{
+			"WeatherControlFrostInvalidMode",
+			&pkg.WaterSchedule{
+				Interval:  &pkg.Duration{Duration: time.Hour * 24},
+				Duration:  &pkg.Duration{Duration: time.Second},
+				StartTime: pkg.NewStartTime(now),
+				WeatherControl: &weather.Control{
+					Frost: &weather.FrostControl{Mode: "heat"},
+				},
+			},
+			"error validating weather_control: error validating frost_control: invalid mode \"heat\": must be one of \"protect\" or \"inhibit\"",
+		},
+		{
+			"WeatherControlFrostProtectMissingMinutes",
+			&pkg.WaterSchedule{
+				Interval:  &pkg.Duration{Duration: time.Hour * 24},
+				Duration:  &pkg.Duration{Duration: time.Second},
+				StartTime: pkg.NewStartTime(now),
+				WeatherControl: &weather.Control{
+					Frost: &weather.FrostControl{
+						Mode:      weather.FrostModeProtect,
+						Threshold: float32Pointer(0),
+					},
+				},
+			},
+			"error validating weather_control: error validating frost_control: missing required field: protect_minutes",
+		},
+		{
+			"WeatherControlInvalidOnError",
+			&pkg.WaterSchedule{
+				Interval:  &pkg.Duration{Duration: time.Hour * 24},
+				Duration:  &pkg.Duration{Duration: time.Second},
+				StartTime: pkg.NewStartTime(now),
+				WeatherControl: &weather.Control{
+					Rain: &weather.ScaleControl{
+						BaselineValue: float32Pointer(0),
+						Factor:        float32Pointer(0),
+						Range:         float32Pointer(25.4),
+						ClientID:      xid.New(),
+						OnError:       "retry",
+					},
+				},
+			},
+			"error validating weather_control: error validating rain_control: invalid on_error \"retry\": must be one of \"water_unscaled\", \"skip\", or \"fail\"",
+		},
 		{
 			"ActivePeriodInvalid",
 			&pkg.WaterSchedule{
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/netatmo"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const (
+	oauthStartPath       = "/oauth/start"
+	oauthCallbackPath    = "/oauth/callback"
+	oauthStateCookieName = "garden_app_weather_oauth_state"
+)
+
+// oauthStart redirects to the provider so the user can authorize access for the WeatherClient. Only Netatmo
+// clients use OAuth
+func (api *WeatherClientsAPI) oauthStart(w http.ResponseWriter, r *http.Request) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to start OAuth for WeatherClient")
+
+	wc, httpErr := api.getOAuthWeatherClient(r)
+	if httpErr != nil {
+		render.Render(w, r, httpErr)
+		return
+	}
+
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	if err != nil {
+		render.Render(w, r, babyapi.InternalServerError(fmt.Errorf("error generating state: %w", err)))
+		return
+	}
+	state := hex.EncodeToString(data)
+
+	authURL, err := netatmo.AuthCodeURL(wc.Options, oauthRedirectURL(r, wc), state)
+	if err != nil {
+		render.Render(w, r, babyapi.ErrInvalidRequest(err))
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookieName,
+		Value:    state,
+		Path:     oauthCookiePath(wc),
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   requestScheme(r) == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// oauthCallback exchanges the code from the provider for tokens and stores them in the WeatherClient's options.
+// After this, the client refreshes its tokens automatically
+func (api *WeatherClientsAPI) oauthCallback(w http.ResponseWriter, r *http.Request) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received OAuth callback for WeatherClient")
+
+	wc, httpErr := api.getOAuthWeatherClient(r)
+	if httpErr != nil {
+		render.Render(w, r, httpErr)
+		return
+	}
+
+	if errParam := r.URL.Query().Get("error"); errParam != "" {
+		render.Render(w, r, babyapi.ErrInvalidRequest(fmt.Errorf("authorization failed: %s", errParam)))
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	stateCookie, err := r.Cookie(oauthStateCookieName)
+	if err != nil || state == "" || state != stateCookie.Value {
+		render.Render(w, r, babyapi.ErrInvalidRequest(errors.New("invalid state")))
+		return
+	}
+
+	options, err := netatmo.ExchangeCode(r.Context(), wc.Options, oauthRedirectURL(r, wc), r.URL.Query().Get("code"))
+	if err != nil {
+		logger.Error("error exchanging OAuth code", "error", err)
+		render.Render(w, r, babyapi.ErrInvalidRequest(err))
+		return
+	}
+
+	wc.Options = options
+	err = api.storageClient.WeatherClientConfigs.Set(r.Context(), wc)
+	if err != nil {
+		logger.Error("unable to store WeatherClient tokens", "error", err)
+		render.Render(w, r, babyapi.InternalServerError(err))
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookieName,
+		Path:     oauthCookiePath(wc),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   requestScheme(r) == "https",
+	})
+	http.Redirect(w, r, weatherClientsBasePath, http.StatusFound)
+}
+
+func (api *WeatherClientsAPI) getOAuthWeatherClient(r *http.Request) (*weather.Config, *babyapi.ErrResponse) {
+	wc, httpErr := api.GetRequestedResource(r)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	if wc.Type != "netatmo" {
+		return nil, babyapi.ErrInvalidRequest(fmt.Errorf("OAuth is not supported for %q WeatherClients", wc.Type))
+	}
+
+	return wc, nil
+}
+
+// oauthRedirectURL is the callback URL for the WeatherClient on this server. It must be allowed in the
+// provider's app settings
+func oauthRedirectURL(r *http.Request, wc *weather.Config) string {
+	return fmt.Sprintf("%s://%s%s/%s%s", requestScheme(r), r.Host, weatherClientsBasePath, wc.GetID(), oauthCallbackPath)
+}
+
+func oauthCookiePath(wc *weather.Config) string {
+	return fmt.Sprintf("%s/%s/oauth", weatherClientsBasePath, wc.GetID())
+}
+
+// requestScheme uses the X-Forwarded-Proto header from a reverse proxy if it is set
+func requestScheme(r *http.Request) string {
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		return proto
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupOAuthWeatherClientsAPI(t *testing.T) (*WeatherClientsAPI, *weather.Config) {
+	t.Helper()
+
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	wc := &weather.Config{
+		ID:   babyapi.NewID(),
+		Type: "netatmo",
+		Options: map[string]interface{}{
+			"station_name":        "Station",
+			"rain_module_name":    "Rain",
+			"outdoor_module_name": "Outdoor",
+			"client_id":           "client-id",
+			"client_secret":       "client-secret",
+		},
+	}
+	require.NoError(t, storageClient.WeatherClientConfigs.Set(context.Background(), wc))
+	require.NoError(t, storageClient.WeatherClientConfigs.Set(context.Background(), createExampleWeatherClientConfig()))
+
+	wcr := NewWeatherClientsAPI()
+	wcr.setup(storageClient)
+
+	return wcr, wc
+}
+
+func TestWeatherClientOAuthStart(t *testing.T) {
+	wcr, wc := setupOAuthWeatherClientsAPI(t)
+
+	t.Run("Successful", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/weather_clients/"+wc.GetID()+"/oauth/start", http.NoBody)
+		w := babytest.TestRequest[*weather.Config](t, wcr.API, r)
+		require.Equal(t, http.StatusFound, w.Code)
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "/oauth2/authorize", location.Path)
+		assert.Equal(t, "client-id", location.Query().Get("client_id"))
+		assert.Equal(t, "http://example.com/weather_clients/"+wc.GetID()+"/oauth/callback", location.Query().Get("redirect_uri"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, oauthStateCookieName, cookies[0].Name)
+		assert.Equal(t, location.Query().Get("state"), cookies[0].Value)
+		assert.Equal(t, "/weather_clients/"+wc.GetID()+"/oauth", cookies[0].Path)
+	})
+
+	t.Run("ForwardedProto", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/weather_clients/"+wc.GetID()+"/oauth/start", http.NoBody)
+		r.Header.Set("X-Forwarded-Proto", "https")
+		w := babytest.TestRequest[*weather.Config](t, wcr.API, r)
+		require.Equal(t, http.StatusFound, w.Code)
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/weather_clients/"+wc.GetID()+"/oauth/callback", location.Query().Get("redirect_uri"))
+	})
+
+	t.Run("ErrorNotNetatmo", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/weather_clients/c5cvhpcbcv45e8bp16dg/oauth/start", http.NoBody)
+		w := babytest.TestRequest[*weather.Config](t, wcr.API, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"status":"Invalid request.","error":"OAuth is not supported for \"fake\" WeatherClients"}`, strings.TrimSpace(w.Body.String()))
+	})
+}
+
+func TestWeatherClientOAuthCallback(t *testing.T) {
+	wcr, wc := setupOAuthWeatherClientsAPI(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		cookie   string
+		expected string
+	}{
+		{
+			"ErrorFromProvider",
+			"?error=access_denied",
+			"",
+			`{"status":"Invalid request.","error":"authorization failed: access_denied"}`,
+		},
+		{
+			"ErrorMissingCookie",
+			"?state=abc&code=code",
+			"",
+			`{"status":"Invalid request.","error":"invalid state"}`,
+		},
+		{
+			"ErrorWrongState",
+			"?state=abc&code=code",
+			"def",
+			`{"status":"Invalid request.","error":"invalid state"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/weather_clients/"+wc.GetID()+"/oauth/callback"+tt.query, http.NoBody)
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: oauthStateCookieName, Value: tt.cookie})
+			}
+			w := babytest.TestRequest[*weather.Config](t, wcr.API, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.expected, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
@@ -11,8 +11,10 @@ import (
 	"github.com/go-chi/render"
 )
 
+// WeatherClientTestResponse shows the weather data for the tested Range
 type WeatherClientTestResponse struct {
 	WeatherData
+	Range string `json:"range"`
 }
 
 func (resp *WeatherClientTestResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
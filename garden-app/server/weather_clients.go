@@ -1,9 +1,10 @@
 package server
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
@@ -32,7 +33,13 @@ func NewWeatherClientsAPI() *WeatherClientsAPI {
 
 	api.SetOnCreateOrUpdate(func(_ *http.Request, wc *weather.Config) *babyapi.ErrResponse {
 		// make sure a valid WeatherClient can still be created
-		_, err := weather.NewClient(wc, func(map[string]interface{}) error { return nil })
+		var err error
+		switch wc.Type {
+		case "composite", "mqtt_sensor":
+			_, err = api.storageClient.NewWeatherClient(wc)
+		default:
+			_, err = weather.NewClient(wc, func(map[string]interface{}) error { return nil })
+		}
 		if err != nil {
 			return babyapi.ErrInvalidRequest(fmt.Errorf("invalid request to update WeatherClient: %w", err))
 		}
@@ -54,6 +61,8 @@ func NewWeatherClientsAPI() *WeatherClientsAPI {
 	})
 
 	api.AddCustomIDRoute(http.MethodGet, "/test", babyapi.Handler(api.testWeatherClient))
+	api.AddCustomIDRoute(http.MethodGet, oauthStartPath, http.HandlerFunc(api.oauthStart))
+	api.AddCustomIDRoute(http.MethodGet, oauthCallbackPath, http.HandlerFunc(api.oauthCallback))
 
 	api.AddCustomRoute(http.MethodGet, "/components", babyapi.Handler(func(_ http.ResponseWriter, r *http.Request) render.Renderer {
 		switch r.URL.Query().Get("type") {
@@ -87,9 +96,20 @@ func NewWeatherClientsAPI() *WeatherClientsAPI {
 			return babyapi.ErrInvalidRequest(fmt.Errorf("unable to delete WeatherClient used by %d WaterSchedules", len(waterSchedules)))
 		}
 
+		weatherClients, err := api.storageClient.GetWeatherClientsUsingWeatherClient(id)
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("unable to get WeatherClients using WeatherClient %q: %w", id, err))
+		}
+
+		if len(weatherClients) > 0 {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("unable to delete WeatherClient used by %d composite WeatherClients", len(weatherClients)))
+		}
+
 		return nil
 	})
 
+	api.ApplyExtension(conditionalRequests[*weather.Config]{})
+
 	return api
 }
 
@@ -99,6 +119,9 @@ func (api *WeatherClientsAPI) setup(storageClient *storage.Client) {
 	api.SetStorage(api.storageClient.WeatherClientConfigs)
 }
 
+// testWeatherClient responds with the rain and average high temperature for the duration from the "range" query
+// parameter, which defaults to 72h. The "baseline" and "factor" query parameters can be used with "range_mm" or
+// "range_celsius" to calculate the scale factor for a hypothetical rain or temperature ScaleControl
 func (api *WeatherClientsAPI) testWeatherClient(_ http.ResponseWriter, r *http.Request) render.Renderer {
 	logger := babyapi.GetLoggerFromContext(r.Context())
 	logger.Info("received request to test WeatherClient")
@@ -109,32 +132,96 @@ func (api *WeatherClientsAPI) testWeatherClient(_ http.ResponseWriter, r *http.R
 		return httpErr
 	}
 
-	weatherData, err := api.getWeatherData(r.Context(), weatherClient)
+	timeRange, err := rangeQueryParam(r)
+	if err != nil {
+		logger.Error("unable to parse time range", "error", err)
+		return babyapi.ErrInvalidRequest(err)
+	}
+	if timeRange <= 0 {
+		return babyapi.ErrInvalidRequest(errors.New("range must be a positive duration"))
+	}
+
+	rainControl, temperatureControl, err := scaleControlQueryParams(r)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	weatherData, err := api.getWeatherData(weatherClient, timeRange)
 	if err != nil {
 		logger.Error("unable to get weather data", "error", err)
 		return InternalServerError(err)
 	}
 
-	return &WeatherClientTestResponse{WeatherData: weatherData}
+	if rainControl != nil {
+		weatherData.Rain.ScaleFactor = rainControl.InvertedScaleDownOnly(weatherData.Rain.MM)
+	}
+	if temperatureControl != nil {
+		weatherData.Temperature.ScaleFactor = temperatureControl.Scale(weatherData.Temperature.Celsius)
+	}
+
+	return &WeatherClientTestResponse{WeatherData: weatherData, Range: timeRange.String()}
 }
 
-func (api *WeatherClientsAPI) getWeatherData(ctx context.Context, weatherClient *weather.Config) (WeatherData, error) {
-	wc, err := weather.NewClient(weatherClient, func(weatherClientOptions map[string]interface{}) error {
-		weatherClient.Options = weatherClientOptions
-		return api.storageClient.WeatherClientConfigs.Set(ctx, weatherClient)
-	})
+// scaleControlQueryParams creates a hypothetical ScaleControl from the "baseline", "factor", and "range_mm" or
+// "range_celsius" query parameters. "range_mm" creates a rain control and "range_celsius" creates a temperature control.
+// Both are nil if none of the parameters are used
+func scaleControlQueryParams(r *http.Request) (*weather.ScaleControl, *weather.ScaleControl, error) {
+	query := r.URL.Query()
+	if !query.Has("baseline") && !query.Has("factor") && !query.Has("range_mm") && !query.Has("range_celsius") {
+		return nil, nil, nil
+	}
+
+	if query.Has("range_mm") == query.Has("range_celsius") {
+		return nil, nil, errors.New("exactly one of range_mm or range_celsius is required for a scale control")
+	}
+
+	rangeParam := "range_mm"
+	if query.Has("range_celsius") {
+		rangeParam = "range_celsius"
+	}
+
+	values := map[string]*float32{}
+	for _, param := range []string{"baseline", "factor", rangeParam} {
+		value, err := strconv.ParseFloat(query.Get(param), 32)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: must be a number", param)
+		}
+		v := float32(value)
+		values[param] = &v
+	}
+
+	if *values[rangeParam] <= 0 {
+		return nil, nil, fmt.Errorf("%s must be greater than 0", rangeParam)
+	}
+	if *values["factor"] < 0 || *values["factor"] > 1 {
+		return nil, nil, errors.New("factor must be between 0 and 1")
+	}
+
+	sc := &weather.ScaleControl{
+		BaselineValue: values["baseline"],
+		Factor:        values["factor"],
+		Range:         values[rangeParam],
+	}
+	if rangeParam == "range_mm" {
+		return sc, nil, nil
+	}
+	return nil, sc, nil
+}
+
+func (api *WeatherClientsAPI) getWeatherData(weatherClient *weather.Config, timeRange time.Duration) (WeatherData, error) {
+	wc, err := api.storageClient.GetWeatherClient(weatherClient.ID.ID)
 	if err != nil {
 		return WeatherData{}, fmt.Errorf("error getting weather client: %w", err)
 	}
 
-	rd, err := wc.GetTotalRain(72 * time.Hour)
+	rd, err := wc.GetTotalRain(timeRange)
 	if err != nil {
-		return WeatherData{}, fmt.Errorf("unable to get total rain in the last 72 hours: %w", err)
+		return WeatherData{}, fmt.Errorf("unable to get total rain in the last %s: %w", timeRange, err)
 	}
 
-	td, err := wc.GetAverageHighTemperature(72 * time.Hour)
+	td, err := wc.GetAverageHighTemperature(timeRange)
 	if err != nil {
-		return WeatherData{}, fmt.Errorf("unable to get average high temperature in the last 72 hours: %w", err)
+		return WeatherData{}, fmt.Errorf("unable to get average high temperature in the last %s: %w", timeRange, err)
 	}
 
 	return WeatherData{
@@ -175,6 +175,17 @@ func TestDeleteWeatherClient(t *testing.T) {
 	err = storageClient.WeatherClientConfigs.Set(context.Background(), weatherClientWithWS)
 	assert.NoError(t, err)
 
+	weatherClientInComposite := createExampleWeatherClientConfig()
+	weatherClientInComposite.ID = babyapi.NewID()
+	err = storageClient.WeatherClientConfigs.Set(context.Background(), weatherClientInComposite)
+	assert.NoError(t, err)
+	err = storageClient.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+		ID:      babyapi.NewID(),
+		Type:    "composite",
+		Options: map[string]interface{}{"client_ids": []interface{}{weatherClientInComposite.GetID()}},
+	})
+	assert.NoError(t, err)
+
 	tests := []struct {
 		name          string
 		id            string
@@ -196,6 +207,13 @@ func TestDeleteWeatherClient(t *testing.T) {
 			`{"status":"Invalid request.","error":"unable to delete WeatherClient used by 2 WaterSchedules"}`,
 			http.StatusBadRequest,
 		},
+		{
+			"UnableToDeleteUsedByCompositeWeatherClients",
+			weatherClientInComposite.GetID(),
+			createExampleWeatherClientConfig(),
+			`{"status":"Invalid request.","error":"unable to delete WeatherClient used by 1 composite WeatherClients"}`,
+			http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -213,6 +231,63 @@ func TestDeleteWeatherClient(t *testing.T) {
 	}
 }
 
+func TestCreateCompositeWeatherClient(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedRegexp string
+		code           int
+	}{
+		{
+			"Successful",
+			`{"type":"composite","options":{"client_ids":["c5cvhpcbcv45e8bp16dg"],"strategy":"max"}}`,
+			`{"id":"[0-9a-v]{20}","type":"composite","options":{"client_ids":\["c5cvhpcbcv45e8bp16dg"\],"strategy":"max"},"links":.*}`,
+			http.StatusCreated,
+		},
+		{
+			"ErrorClientNotFound",
+			`{"type":"composite","options":{"client_ids":["chkodpg3lcj13q82mq40"]}}`,
+			`{"status":"Invalid request.","error":"invalid request to update WeatherClient: error getting client \\"chkodpg3lcj13q82mq40\\": error getting weather client config: resource not found"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorInvalidStrategy",
+			`{"type":"composite","options":{"client_ids":["c5cvhpcbcv45e8bp16dg"],"strategy":"median"}}`,
+			`{"status":"Invalid request.","error":"invalid request to update WeatherClient: invalid strategy \\"median\\""}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorMissingClientIDs",
+			`{"type":"composite","options":{}}`,
+			`{"status":"Invalid request.","error":"invalid request to update WeatherClient: missing required client_ids"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			assert.NoError(t, err)
+
+			err = storageClient.WeatherClientConfigs.Set(context.Background(), createExampleWeatherClientConfig())
+			assert.NoError(t, err)
+
+			wcr := NewWeatherClientsAPI()
+			wcr.setup(storageClient)
+
+			r := httptest.NewRequest(http.MethodPost, "/weather_clients", strings.NewReader(tt.body))
+			r.Header.Add("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*weather.Config](t, wcr.API, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
 func TestGetAllWeatherClients(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -364,14 +439,64 @@ func TestUpdateWeatherClientPUT(t *testing.T) {
 func TestTestWeatherClient(t *testing.T) {
 	tests := []struct {
 		name           string
+		query          string
 		expected       string
 		expectedStatus int
 	}{
 		{
 			"Successful",
-			`{"rain":{"mm":76.2,"scale_factor":0},"average_temperature":{"celsius":80,"scale_factor":0}}`,
+			"",
+			`{"rain":{"mm":76.2,"scale_factor":0},"average_temperature":{"celsius":80,"scale_factor":0},"range":"72h0m0s"}`,
+			http.StatusOK,
+		},
+		{
+			"SuccessfulRange",
+			"?range=24h",
+			`{"rain":{"mm":25.4,"scale_factor":0},"average_temperature":{"celsius":80,"scale_factor":0},"range":"24h0m0s"}`,
+			http.StatusOK,
+		},
+		{
+			"SuccessfulRainScaleControl",
+			"?baseline=25&factor=0.5&range_mm=50",
+			`{"rain":{"mm":76.2,"scale_factor":0.5},"average_temperature":{"celsius":80,"scale_factor":0},"range":"72h0m0s"}`,
 			http.StatusOK,
 		},
+		{
+			"SuccessfulTemperatureScaleControl",
+			"?baseline=70&factor=0.5&range_celsius=20",
+			`{"rain":{"mm":76.2,"scale_factor":0},"average_temperature":{"celsius":80,"scale_factor":1.25},"range":"72h0m0s"}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorInvalidRange",
+			"?range=abc",
+			`{"status":"Invalid request.","error":"time: invalid duration \"abc\""}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorNegativeRange",
+			"?range=-24h",
+			`{"status":"Invalid request.","error":"range must be a positive duration"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorMissingScaleControlRange",
+			"?baseline=25&factor=0.5",
+			`{"status":"Invalid request.","error":"exactly one of range_mm or range_celsius is required for a scale control"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorMissingBaseline",
+			"?factor=0.5&range_mm=50",
+			`{"status":"Invalid request.","error":"invalid baseline: must be a number"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorInvalidFactor",
+			"?baseline=25&factor=2&range_mm=50",
+			`{"status":"Invalid request.","error":"factor must be between 0 and 1"}`,
+			http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -387,7 +512,7 @@ func TestTestWeatherClient(t *testing.T) {
 			err = wcr.storageClient.WeatherClientConfigs.Set(context.Background(), createExampleWeatherClientConfig())
 			assert.NoError(t, err)
 
-			r := httptest.NewRequest("GET", "/weather_clients/c5cvhpcbcv45e8bp16dg/test", http.NoBody)
+			r := httptest.NewRequest("GET", "/weather_clients/c5cvhpcbcv45e8bp16dg/test"+tt.query, http.NoBody)
 			w := babytest.TestRequest[*weather.Config](t, wcr.API, r)
 
 			// check HTTP response status code
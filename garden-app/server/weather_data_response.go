@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
@@ -14,6 +15,10 @@ type WeatherData struct {
 	Rain                *RainData        `json:"rain,omitempty"`
 	Temperature         *TemperatureData `json:"average_temperature,omitempty"`
 	SoilMoisturePercent *float64         `json:"soil_moisture_percent,omitempty"`
+	// ScaleFactor is the compounded scale factor from the rain and temperature data
+	ScaleFactor *float32 `json:"scale_factor,omitempty"`
+	// EffectiveDuration is the duration that would be used if watering now, after scaling and limiting
+	EffectiveDuration *pkg.Duration `json:"effective_duration,omitempty"`
 }
 
 // RainData shows the total rain in the last watering interval and the scaling factor it would result in
@@ -28,7 +33,9 @@ type TemperatureData struct {
 	ScaleFactor float32 `json:"scale_factor"`
 }
 
-func getWeatherData(ctx context.Context, ws *pkg.WaterSchedule, storageClient *storage.Client) *WeatherData {
+// getWeatherData gets the data used by the WaterSchedule's WeatherControl and shows how it scales the duration. If
+// garden and zone are not nil, the effective duration is also scaled for them
+func getWeatherData(ctx context.Context, ws *pkg.WaterSchedule, garden *pkg.Garden, zone *pkg.Zone, storageClient *storage.Client) *WeatherData {
 	logger := babyapi.GetLoggerFromContext(ctx).With(waterScheduleIDLogField, ws.ID.String())
 	weatherData := &WeatherData{}
 
@@ -57,6 +64,29 @@ func getWeatherData(ctx context.Context, ws *pkg.WaterSchedule, storageClient *s
 			}
 		}
 	}
+
+	if weatherData.Rain == nil && weatherData.Temperature == nil {
+		return weatherData
+	}
+
+	scaleFactor := float32(1)
+	if weatherData.Rain != nil {
+		scaleFactor *= weatherData.Rain.ScaleFactor
+	}
+	if weatherData.Temperature != nil {
+		scaleFactor *= weatherData.Temperature.ScaleFactor
+	}
+	weatherData.ScaleFactor = &scaleFactor
+
+	duration := pkg.ScaleDuration(ws.Duration.Duration, scaleFactor)
+	if zone != nil {
+		duration = zone.ScaleWaterDuration(duration)
+	}
+	if garden != nil {
+		duration = pkg.ScaleDuration(duration, garden.SeasonalScale(time.Now()))
+	}
+	weatherData.EffectiveDuration = &pkg.Duration{Duration: ws.ClampDuration(duration)}
+
 	return weatherData
 }
 
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather/mqttsensor"
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// weatherSensors records the readings published by local sensors for "mqtt_sensor" WeatherClients. MQTT
+// subscriptions are kept in sync with the topics used by stored WeatherClients
+type weatherSensors struct {
+	storageClient *storage.Client
+	subscriber    mqtt.Subscriber
+	logger        *slog.Logger
+	now           func() time.Time
+
+	topics map[string]bool
+	mu     sync.Mutex
+}
+
+func newWeatherSensors(storageClient *storage.Client, subscriber mqtt.Subscriber, logger *slog.Logger, now func() time.Time) *weatherSensors {
+	return &weatherSensors{
+		storageClient: storageClient,
+		subscriber:    subscriber,
+		logger:        logger,
+		now:           now,
+		topics:        map[string]bool{},
+	}
+}
+
+// sync subscribes to new topics used by WeatherClients and unsubscribes from topics that are no longer used
+func (s *weatherSensors) sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	weatherClients, err := s.storageClient.WeatherClientConfigs.GetAll(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("unable to get all WeatherClients: %w", err)
+	}
+
+	topics := map[string]bool{}
+	for _, wc := range weatherClients {
+		if topic := wc.MQTTSensorTopic(); topic != "" {
+			topics[topic] = true
+		}
+	}
+
+	for topic := range topics {
+		if s.topics[topic] {
+			continue
+		}
+		err = s.subscriber.Subscribe(topic, paho.MessageHandler(s.handle))
+		if err != nil {
+			return err
+		}
+		s.topics[topic] = true
+		s.logger.Info("subscribed to weather sensor topic", "topic", topic)
+	}
+
+	for topic := range s.topics {
+		if topics[topic] {
+			continue
+		}
+		err = s.subscriber.Unsubscribe(topic)
+		if err != nil {
+			return err
+		}
+		delete(s.topics, topic)
+		s.logger.Info("unsubscribed from weather sensor topic", "topic", topic)
+	}
+
+	return nil
+}
+
+// watch syncs subscriptions each time a WeatherClient is changed until done is closed
+func (s *weatherSensors) watch(bus *events.Bus, done <-chan struct{}) {
+	subscriber, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-done:
+			return
+		case e := <-subscriber:
+			if !strings.HasPrefix(e.Type, "weather_client.") {
+				continue
+			}
+
+			err := s.sync()
+			if err != nil {
+				s.logger.Error("unable to sync weather sensor subscriptions", "error", err)
+			}
+		}
+	}
+}
+
+// handle records the reading for each WeatherClient that uses the message's topic
+func (s *weatherSensors) handle(_ paho.Client, msg paho.Message) {
+	logger := s.logger.With("topic", msg.Topic())
+	logger.Debug("received weather sensor data", "message", string(msg.Payload()))
+
+	reading, err := mqttsensor.ParseReading(msg.Payload(), s.now())
+	if err != nil {
+		logger.Error("error parsing weather sensor data", "error", err)
+		return
+	}
+
+	weatherClients, err := s.storageClient.WeatherClientConfigs.GetAll(context.Background(), nil)
+	if err != nil {
+		logger.Error("unable to get all WeatherClients", "error", err)
+		return
+	}
+
+	for _, wc := range weatherClients {
+		if wc.MQTTSensorTopic() != msg.Topic() {
+			continue
+		}
+
+		err = s.storageClient.WeatherReadings.AddWeatherReading(context.Background(), wc.GetID(), reading)
+		if err != nil {
+			logger.Error("unable to store weather reading", weatherClientIDLogField, wc.GetID(), "error", err)
+		}
+	}
+}
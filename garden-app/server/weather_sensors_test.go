@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createExampleMQTTSensorWeatherClientConfig(topic string) *weather.Config {
+	return &weather.Config{
+		ID:      babyapi.NewID(),
+		Type:    "mqtt_sensor",
+		Options: map[string]interface{}{"topic": topic},
+	}
+}
+
+func TestWeatherSensors(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	now := time.Date(2023, time.August, 23, 12, 0, 0, 0, time.UTC)
+	wc := createExampleMQTTSensorWeatherClientConfig("garden/data/rain")
+	require.NoError(t, storageClient.WeatherClientConfigs.Set(context.Background(), wc))
+
+	var defaultMessages []string
+	mqttClient := mqtt.NewInMemoryClient(mqtt.Config{}, func(_ paho.Client, msg paho.Message) {
+		defaultMessages = append(defaultMessages, msg.Topic())
+	})
+
+	sensors := newWeatherSensors(storageClient, mqttClient, slog.Default(), func() time.Time { return now })
+	require.NoError(t, sensors.sync())
+
+	t.Run("RecordReading", func(t *testing.T) {
+		require.NoError(t, mqttClient.Publish("garden/data/rain", []byte("weather rain_mm=1.5,temperature=30")))
+		// invalid messages are ignored
+		require.NoError(t, mqttClient.Publish("garden/data/rain", []byte("weather rain_mm=abc")))
+
+		readings, err := storageClient.WeatherReadings.GetWeatherReadings(context.Background(), wc.GetID(), time.Time{})
+		require.NoError(t, err)
+		require.Len(t, readings, 1)
+		assert.Equal(t, now, readings[0].Time)
+		assert.Equal(t, float32(1.5), *readings[0].RainMM)
+		assert.Equal(t, float32(30), *readings[0].Temperature)
+
+		client, err := storageClient.GetWeatherClient(wc.ID.ID)
+		require.NoError(t, err)
+		weather.SetNow(client, func() time.Time { return now })
+
+		rain, err := client.GetTotalRain(24 * time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, float32(1.5), rain)
+	})
+
+	t.Run("SyncAfterChange", func(t *testing.T) {
+		bus := events.NewBus()
+		done := make(chan struct{})
+		defer close(done)
+		go sensors.watch(bus, done)
+
+		wc.Options["topic"] = "garden/data/weather"
+		require.NoError(t, storageClient.WeatherClientConfigs.Set(context.Background(), wc))
+
+		// Wait for the watcher to subscribe before publishing the event so it is not missed
+		require.Eventually(t, func() bool {
+			bus.Publish(events.Event{Type: "weather_client.updated", ID: wc.GetID()})
+			sensors.mu.Lock()
+			defer sensors.mu.Unlock()
+			return sensors.topics["garden/data/weather"]
+		}, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, mqttClient.Publish("garden/data/rain", []byte("weather rain_mm=2")))
+		assert.Equal(t, []string{"garden/data/rain"}, defaultMessages)
+
+		require.NoError(t, mqttClient.Publish("garden/data/weather", []byte("weather rain_mm=2")))
+		readings, err := storageClient.WeatherReadings.GetWeatherReadings(context.Background(), wc.GetID(), time.Time{})
+		require.NoError(t, err)
+		assert.Len(t, readings, 2)
+	})
+}
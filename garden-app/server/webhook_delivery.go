@@ -0,0 +1,188 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+)
+
+const (
+	webhookSignatureHeader = "X-Garden-Signature"
+	webhookEventHeader     = "X-Garden-Event"
+	webhookDeliveryHeader  = "X-Garden-Delivery"
+
+	webhookTimeout     = 10 * time.Second
+	webhookMaxAttempts = 3
+	// webhookRetryDelay is the delay before the first retry. It doubles after each failed attempt
+	webhookRetryDelay = 5 * time.Second
+
+	webhookIDLogField = "webhook_id"
+)
+
+// webhookDispatcher sends each Event to the Webhooks that match it. Requests are sent in the background and retried
+// so a slow or failing URL does not delay other Webhooks or miss Events from the Bus
+type webhookDispatcher struct {
+	storageClient *storage.Client
+	httpClient    *http.Client
+	logger        *slog.Logger
+	now           func() time.Time
+	retryDelay    time.Duration
+
+	wg sync.WaitGroup
+}
+
+func newWebhookDispatcher(storageClient *storage.Client, logger *slog.Logger, now func() time.Time) *webhookDispatcher {
+	return &webhookDispatcher{
+		storageClient: storageClient,
+		httpClient:    &http.Client{Timeout: webhookTimeout},
+		logger:        logger,
+		now:           now,
+		retryDelay:    webhookRetryDelay,
+	}
+}
+
+// watch sends Events to Webhooks until done is closed. Retries that are waiting when done is closed are not sent
+func (d *webhookDispatcher) watch(bus *events.Bus, done <-chan struct{}) {
+	subscriber, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-done:
+			return
+		case e := <-subscriber:
+			d.dispatch(e, done)
+		}
+	}
+}
+
+// dispatch starts delivering the Event to each matching Webhook
+func (d *webhookDispatcher) dispatch(e events.Event, done <-chan struct{}) {
+	webhooks, err := d.storageClient.Webhooks.GetAll(context.Background(), nil)
+	if err != nil {
+		d.logger.Error("unable to get all Webhooks", "error", err)
+		return
+	}
+
+	var body []byte
+	for _, webhook := range webhooks {
+		if !webhook.Matches(e.Type) {
+			continue
+		}
+
+		if body == nil {
+			body, err = json.Marshal(e)
+			if err != nil {
+				d.logger.Error("unable to marshal event", "error", err, "type", e.Type)
+				return
+			}
+		}
+
+		now := d.now()
+		delivery := pkg.WebhookDelivery{
+			ID:        babyapi.NewID().String(),
+			WebhookID: webhook.GetID(),
+			EventType: e.Type,
+			EventID:   e.ID,
+			Status:    pkg.WebhookDeliveryStatusPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		d.wg.Add(1)
+		go func(webhook *pkg.Webhook) {
+			defer d.wg.Done()
+			d.deliver(webhook, delivery, body, done)
+		}(webhook)
+	}
+}
+
+// deliver sends the request until it succeeds or webhookMaxAttempts is reached. The WebhookDelivery is stored after
+// each attempt so its status can be followed with the API
+func (d *webhookDispatcher) deliver(webhook *pkg.Webhook, delivery pkg.WebhookDelivery, body []byte, done <-chan struct{}) {
+	logger := d.logger.With(webhookIDLogField, webhook.GetID(), "delivery_id", delivery.ID, "event_type", delivery.EventType)
+
+	delay := d.retryDelay
+	for {
+		status, err := d.send(webhook, delivery, body)
+
+		delivery.Attempts++
+		delivery.ResponseStatus = status
+		delivery.UpdatedAt = d.now()
+		delivery.Error = ""
+		switch {
+		case err == nil:
+			delivery.Status = pkg.WebhookDeliveryStatusSucceeded
+		case delivery.Attempts >= webhookMaxAttempts:
+			delivery.Status = pkg.WebhookDeliveryStatusFailed
+			delivery.Error = err.Error()
+		default:
+			delivery.Error = err.Error()
+		}
+
+		storageErr := d.storageClient.WebhookDeliveries.SetWebhookDelivery(context.Background(), delivery)
+		if storageErr != nil {
+			logger.Error("unable to store webhook delivery", "error", storageErr)
+		}
+
+		if delivery.Status != pkg.WebhookDeliveryStatusPending {
+			if err != nil {
+				logger.Error("webhook delivery failed", "attempts", delivery.Attempts, "error", err)
+			}
+			return
+		}
+
+		logger.Warn("webhook delivery failed, retrying", "attempts", delivery.Attempts, "delay", delay, "error", err)
+		select {
+		case <-done:
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// send makes one signed request to the Webhook's URL and returns the response status. Responses that are not 2xx
+// are errors
+func (d *webhookDispatcher) send(webhook *pkg.Webhook, delivery pkg.WebhookDelivery, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, delivery.EventType)
+	req.Header.Set(webhookDeliveryHeader, delivery.ID)
+	req.Header.Set(webhookSignatureHeader, webhookSignature(webhook.Secret, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// webhookSignature is the hex-encoded HMAC-SHA256 of the body using the Webhook's secret, prefixed with "sha256="
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+)
+
+const (
+	webhooksBasePath = "/webhooks"
+	deliveriesPath   = "/deliveries"
+)
+
+// WebhooksAPI encapsulates the structs and dependencies necessary for the Webhooks API to function, including
+// storage and configuring
+type WebhooksAPI struct {
+	*babyapi.API[*pkg.Webhook]
+
+	storageClient *storage.Client
+}
+
+// NewWebhooksAPI creates a new WebhooksAPI
+func NewWebhooksAPI() *WebhooksAPI {
+	api := &WebhooksAPI{}
+
+	api.API = babyapi.NewAPI[*pkg.Webhook]("Webhooks", webhooksBasePath, func() *pkg.Webhook { return &pkg.Webhook{} })
+
+	// The secret is not included in responses since it is only used to sign requests
+	api.SetResponseWrapper(func(w *pkg.Webhook) render.Renderer {
+		webhook := *w
+		webhook.Secret = ""
+		return &WebhookResponse{Webhook: &webhook}
+	})
+
+	api.AddCustomIDRoute(http.MethodGet, deliveriesPath, api.GetRequestedResourceAndDo(api.deliveries))
+
+	api.ApplyExtension(conditionalRequests[*pkg.Webhook]{})
+
+	return api
+}
+
+func (api *WebhooksAPI) setup(storageClient *storage.Client) {
+	api.storageClient = storageClient
+
+	api.SetStorage(api.storageClient.Webhooks)
+}
+
+// WebhookResponse is used to add links to a Webhook
+type WebhookResponse struct {
+	*pkg.Webhook
+
+	Links []Link `json:"links,omitempty"`
+}
+
+// Render ...
+func (resp *WebhookResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	if resp != nil {
+		resp.Links = append(resp.Links,
+			Link{
+				"self",
+				fmt.Sprintf("%s/%s", webhooksBasePath, resp.ID),
+			},
+			Link{
+				"deliveries",
+				fmt.Sprintf("%s/%s%s", webhooksBasePath, resp.ID, deliveriesPath),
+			},
+		)
+	}
+	return nil
+}
+
+// WebhookDeliveriesResponse is the response for listing a Webhook's recent deliveries
+type WebhookDeliveriesResponse struct {
+	Items []pkg.WebhookDelivery `json:"items"`
+}
+
+func (*WebhookDeliveriesResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// deliveries responds with the Webhook's recent deliveries, starting with the most recent. The "limit" query
+// parameter sets the maximum number of deliveries
+func (api *WebhooksAPI) deliveries(r *http.Request, webhook *pkg.Webhook) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to get Webhook deliveries")
+
+	limit, err := limitQueryParam(r)
+	if err != nil {
+		logger.Error("unable to parse limit", "error", err)
+		return nil, babyapi.ErrInvalidRequest(err)
+	}
+
+	deliveries, err := api.storageClient.WebhookDeliveries.GetWebhookDeliveries(r.Context(), webhook.GetID(), limit)
+	if err != nil {
+		logger.Error("unable to get Webhook deliveries", "error", err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return &WebhookDeliveriesResponse{Items: deliveries}, nil
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooksAPI(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	api := NewWebhooksAPI()
+	api.setup(storageClient)
+
+	babytest.RunTableTest(t, api.API, []babytest.TestCase[*babyapi.AnyResource]{
+		{
+			Name: "CreateWebhook",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPost,
+				Body:   `{"name": "Alerts", "url": "https://example.com/hook", "secret": "secret", "events": ["garden_health.changed"]}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status:     http.StatusCreated,
+				BodyRegexp: `{"id":"[0-9a-v]{20}","name":"Alerts","url":"https://example.com/hook","events":\["garden_health.changed"\],"created_at":"[^"]+","links":\[{"rel":"self","href":"/webhooks/[0-9a-v]{20}"},{"rel":"deliveries","href":"/webhooks/[0-9a-v]{20}/deliveries"}\]}`,
+			},
+		},
+		{
+			Name: "PatchEventsKeepsSecret",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPatch,
+				IDFunc: func(getResponse babytest.PreviousResponseGetter) string {
+					return getResponse("CreateWebhook").Data.GetID()
+				},
+				Body: `{"events": ["garden.*"]}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status:     http.StatusOK,
+				BodyRegexp: `{"id":"[0-9a-v]{20}","name":"Alerts","url":"https://example.com/hook","events":\["garden.\*"\],"created_at":"[^"]+","links":\[{"rel":"self","href":"/webhooks/[0-9a-v]{20}"},{"rel":"deliveries","href":"/webhooks/[0-9a-v]{20}/deliveries"}\]}`,
+			},
+			Assert: func(r *babytest.Response[*babyapi.AnyResource]) {
+				webhook, err := storageClient.Webhooks.Get(context.Background(), r.Data.GetID())
+				require.NoError(t, err)
+				assert.Equal(t, "secret", webhook.Secret)
+			},
+		},
+		{
+			Name: "ErrorCreateInvalidURL",
+			Test: babytest.RequestTest[*babyapi.AnyResource]{
+				Method: http.MethodPost,
+				Body:   `{"name": "Alerts", "url": "example.com/hook", "secret": "secret"}`,
+			},
+			ExpectedResponse: babytest.ExpectedResponse{
+				Status: http.StatusBadRequest,
+				Error:  `error posting resource: unexpected response with text: Invalid request.`,
+				Body:   `{"status":"Invalid request.","error":"invalid url \"example.com/hook\": scheme must be http or https"}`,
+			},
+		},
+	})
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	var requests atomic.Int32
+	var mu sync.Mutex
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt fails so the delivery is retried
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := &pkg.Webhook{ID: babyapi.NewID(), Name: "Health", URL: server.URL, Secret: "secret", Events: []string{"garden_health.*"}}
+	require.NoError(t, storageClient.Webhooks.Set(context.Background(), webhook))
+	failing := &pkg.Webhook{ID: babyapi.NewID(), Name: "Failing", URL: "http://127.0.0.1:0", Secret: "secret"}
+	require.NoError(t, storageClient.Webhooks.Set(context.Background(), failing))
+
+	now := time.Date(2023, time.August, 23, 12, 0, 0, 0, time.UTC)
+	dispatcher := newWebhookDispatcher(storageClient, slog.Default(), func() time.Time { return now })
+	dispatcher.retryDelay = time.Millisecond
+
+	done := make(chan struct{})
+	defer close(done)
+
+	dispatcher.dispatch(events.Event{Type: "garden_health.changed", ID: "garden", Timestamp: now}, done)
+	dispatcher.dispatch(events.Event{Type: "garden.updated", ID: "garden", Timestamp: now}, done)
+	dispatcher.wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	t.Run("SignedRequest", func(t *testing.T) {
+		require.NotNil(t, received)
+		assert.Equal(t, "garden_health.changed", received.Header.Get(webhookEventHeader))
+		assert.Equal(t, webhookSignature("secret", receivedBody), received.Header.Get(webhookSignatureHeader))
+
+		var e events.Event
+		require.NoError(t, json.Unmarshal(receivedBody, &e))
+		assert.Equal(t, "garden_health.changed", e.Type)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("DeliveryRetried", func(t *testing.T) {
+		deliveries, err := storageClient.WebhookDeliveries.GetWebhookDeliveries(context.Background(), webhook.GetID(), 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, pkg.WebhookDeliveryStatusSucceeded, deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+		assert.Equal(t, received.Header.Get(webhookDeliveryHeader), deliveries[0].ID)
+	})
+
+	t.Run("DeliveryFailed", func(t *testing.T) {
+		deliveries, err := storageClient.WebhookDeliveries.GetWebhookDeliveries(context.Background(), failing.GetID(), 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		for _, d := range deliveries {
+			assert.Equal(t, pkg.WebhookDeliveryStatusFailed, d.Status)
+			assert.Equal(t, webhookMaxAttempts, d.Attempts)
+			assert.Contains(t, d.Error, "error sending request")
+		}
+	})
+
+	t.Run("GetDeliveries", func(t *testing.T) {
+		api := NewWebhooksAPI()
+		api.setup(storageClient)
+
+		r := httptest.NewRequest(http.MethodGet, "/webhooks/"+failing.GetID()+"/deliveries?limit=1", http.NoBody)
+		w := babytest.TestRequest(t, api.API, r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp WebhookDeliveriesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, failing.GetID(), resp.Items[0].WebhookID)
+	})
+}
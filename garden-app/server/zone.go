@@ -13,7 +13,7 @@ import (
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
-	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
 	"github.com/calvinmclean/automated-garden/garden-app/worker"
 	"github.com/calvinmclean/babyapi"
@@ -32,8 +32,13 @@ type ZonesAPI struct {
 	*babyapi.API[*pkg.Zone]
 
 	storageClient  *storage.Client
-	influxdbClient influxdb.Client
+	influxdbClient metrics.Client
 	worker         *worker.Worker
+	audit          *auditLog
+	photos         *PhotosAPI
+
+	// waterHistoryFromStorage enables reading water history from storage instead of InfluxDB
+	waterHistoryFromStorage bool
 }
 
 func NewZonesAPI() *ZonesAPI {
@@ -85,19 +90,51 @@ func NewZonesAPI() *ZonesAPI {
 
 	api.AddCustomIDRoute(http.MethodPost, "/action", api.GetRequestedResourceAndDo(api.zoneAction))
 
+	api.AddCustomIDRoute(http.MethodGet, actionsPath, api.GetRequestedResourceAndDo(api.actionRecords))
+
 	api.AddCustomIDRoute(http.MethodGet, "/history", api.GetRequestedResourceAndDo(api.waterHistory))
 
+	api.AddCustomIDRoute(http.MethodGet, "/moisture", api.GetRequestedResourceAndDo(api.moistureHistory))
+
+	api.AddCustomIDRoute(http.MethodPost, restorePath, api.GetRequestedResourceAndDo(api.restore))
+
+	api.AddCustomIDRoute(http.MethodPost, photoBasePath, babyapi.Handler(api.uploadPhoto))
+
+	// The controller's number of Zones changes when a Zone is end-dated
+	api.SetAfterDelete(func(r *http.Request) *babyapi.ErrResponse {
+		logger := babyapi.GetLoggerFromContext(r.Context())
+
+		garden, httpErr := api.getGardenFromRequest(r)
+		if httpErr != nil {
+			return httpErr
+		}
+
+		zones, err := api.storageClient.Zones.GetAll(r.Context(), babyapi.EndDatedQueryParam(false))
+		if err != nil {
+			return babyapi.InternalServerError(fmt.Errorf("error getting all zones for Garden %q: %w", garden.GetID(), err))
+		}
+		zones = babyapi.FilterFunc[*pkg.Zone](filterZoneByGardenID(garden.GetID())).Filter(zones)
+
+		api.publishControllerConfig(logger, garden, zones)
+		return nil
+	})
+
 	api.SetGetAllFilter(func(r *http.Request) babyapi.FilterFunc[*pkg.Zone] {
 		gardenID := api.GetParentIDParam(r)
 		return filterZoneByGardenID(gardenID)
 	})
 
 	api.ApplyExtension(extensions.HTMX[*pkg.Zone]{})
+	api.ApplyExtension(conditionalRequests[*pkg.Zone]{})
+	api.ApplyExtension(revisionHistory[*pkg.Zone]{
+		resourceType: "zone",
+		storage:      func() storage.RevisionStorage { return api.storageClient.Revisions },
+	})
 
 	return api
 }
 
-func (api *ZonesAPI) setup(storageClient *storage.Client, influxdbClient influxdb.Client, worker *worker.Worker) {
+func (api *ZonesAPI) setup(storageClient *storage.Client, influxdbClient metrics.Client, worker *worker.Worker) {
 	api.storageClient = storageClient
 	api.influxdbClient = influxdbClient
 	api.worker = worker
@@ -165,24 +202,79 @@ func (api *ZonesAPI) zoneAction(r *http.Request, zone *pkg.Zone) (render.Rendere
 	}
 	logger.Info("zone action", "action", zoneAction)
 
-	if err := api.worker.ExecuteZoneAction(garden, zone, zoneAction); err != nil {
+	if zoneAction.Water != nil && zoneAction.Water.DryRun {
+		decision, err := api.worker.DecideWaterAction(r.Context(), garden, zone, zoneAction.Water)
+		if err != nil {
+			logger.Error("unable to calculate WaterAction", "error", err)
+			if errors.Is(err, worker.ErrMissingWaterDuration) {
+				return nil, babyapi.ErrInvalidRequest(err)
+			}
+			return nil, babyapi.InternalServerError(err)
+		}
+
+		return &ZoneActionResponse{Water: decision}, nil
+	}
+
+	resp := &ZoneActionResponse{}
+	if zoneAction.Water != nil {
+		zoneAction.Water.ID = xid.New().String()
+		resp.ActionID = zoneAction.Water.ID
+	}
+
+	if err := api.worker.ExecuteZoneAction(r.Context(), garden, zone, zoneAction); err != nil {
 		logger.Error("unable to execute ZoneAction", "error", err)
+		if errors.Is(err, worker.ErrMissingWaterDuration) {
+			return nil, babyapi.ErrInvalidRequest(err)
+		}
 		return nil, babyapi.InternalServerError(err)
 	}
 
+	actionName, details := zoneActionAuditDetails(zoneAction)
+	api.audit.record(r, "zone", zone.GetID(), actionName, details)
+
 	render.Status(r, http.StatusAccepted)
-	return &ZoneActionResponse{}, nil
+	return resp, nil
+}
+
+// uploadPhoto saves a Photo for the Zone
+func (api *ZonesAPI) uploadPhoto(w http.ResponseWriter, r *http.Request) render.Renderer {
+	zone, httpErr := api.GetRequestedResource(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	if zone.EndDated() {
+		return babyapi.ErrInvalidRequest(errors.New("unable to add Photo to end-dated Zone"))
+	}
+
+	return api.photos.upload(w, r, zone.GardenID, zone.ID.ID, nil)
 }
 
-func (api *ZonesAPI) waterSchedulesExist(ctx context.Context, ids []xid.ID) error {
+// restore clears the Zone's EndDate and schedules its WaterSchedules again. The Garden must be restored first
+func (api *ZonesAPI) restore(r *http.Request, zone *pkg.Zone) (render.Renderer, *babyapi.ErrResponse) {
+	garden, httpErr := api.getGardenFromRequest(r)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	if garden.EndDated() {
+		return nil, babyapi.ErrInvalidRequest(errors.New("unable to restore Zone in end-dated Garden"))
+	}
+
+	return restoreResource(r, zone, "Zone", api.storageClient.Zones, api.onCreateOrUpdate, func(z *pkg.Zone) render.Renderer {
+		return api.NewZoneResponse(z)
+	})
+}
+
+func (api *ZonesAPI) getWaterSchedules(ctx context.Context, ids []xid.ID) ([]*pkg.WaterSchedule, error) {
+	waterSchedules := []*pkg.WaterSchedule{}
 	for _, id := range ids {
-		_, err := api.storageClient.WaterSchedules.Get(ctx, id.String())
+		ws, err := api.storageClient.WaterSchedules.Get(ctx, id.String())
 		if err != nil {
-			return fmt.Errorf("error getting WaterSchedule with ID %q: %w", id, err)
+			return nil, fmt.Errorf("error getting WaterSchedule with ID %q: %w", id, err)
 		}
+		waterSchedules = append(waterSchedules, ws)
 	}
 
-	return nil
+	return waterSchedules, nil
 }
 
 func (api *ZonesAPI) getGardenFromRequest(r *http.Request) (*pkg.Garden, *babyapi.ErrResponse) {
@@ -239,8 +331,17 @@ func (api *ZonesAPI) onCreateOrUpdate(r *http.Request, zone *pkg.Zone) *babyapi.
 		logger.Error("invalid request to create Zone", "error", err)
 		return babyapi.ErrInvalidRequest(err)
 	}
+	// Validate that the Zone's pins are not used by the Garden or its other Zones
+	otherZones := babyapi.FilterFunc[*pkg.Zone](func(z *pkg.Zone) bool {
+		return z.GetID() != zone.GetID()
+	}).Filter(zonesForGarden)
+	err = garden.ValidatePinConflicts(append(otherZones, zone))
+	if err != nil {
+		logger.Error("invalid request to create Zone", "error", err)
+		return babyapi.ErrInvalidRequest(err)
+	}
 	// Validate water schedules exists
-	err = api.waterSchedulesExist(r.Context(), zone.WaterScheduleIDs)
+	waterSchedules, err := api.getWaterSchedules(r.Context(), zone.WaterScheduleIDs)
 	if err != nil {
 		if errors.Is(err, babyapi.ErrNotFound) {
 			logger.Error("invalid request to create Zone", "error", err)
@@ -249,10 +350,54 @@ func (api *ZonesAPI) onCreateOrUpdate(r *http.Request, zone *pkg.Zone) *babyapi.
 		logger.Error("unable to get WaterSchedules for new Zone", "water_schedule_ids", zone.WaterScheduleIDs, "error", err)
 		return babyapi.InternalServerError(err)
 	}
+	// Validate that WaterSchedules will not water this Zone at the same time
+	err = pkg.ValidateNoConflicts(waterSchedules)
+	if err != nil {
+		logger.Error("invalid request to create Zone", "error", err)
+		return babyapi.ErrInvalidRequest(err)
+	}
+
+	// WaterSchedules use the time zone of the Gardens with Zones using them, so they are reset when the Zone changes
+	err = api.worker.ResetWaterSchedulesForZone(garden, zone)
+	if err != nil {
+		logger.Error("unable to reset WaterSchedules for Zone", "error", err)
+		return babyapi.InternalServerError(err)
+	}
+
+	existing, err := api.storageClient.Zones.Get(r.Context(), zone.GetID())
+	if err != nil && !errors.Is(err, babyapi.ErrNotFound) {
+		return babyapi.InternalServerError(err)
+	}
+	if existing == nil || controllerConfigChanged(existing, zone) {
+		api.publishControllerConfig(logger, garden, append(otherZones, zone))
+	}
 
 	return nil
 }
 
+// controllerConfigChanged returns true if the Zone's changes affect the garden-controller's config
+func controllerConfigChanged(existing, zone *pkg.Zone) bool {
+	if existing.EndDated() != zone.EndDated() {
+		return true
+	}
+	if (existing.Position == nil) != (zone.Position == nil) || (zone.Position != nil && *existing.Position != *zone.Position) {
+		return true
+	}
+	if (existing.HardwareConfig == nil) != (zone.HardwareConfig == nil) {
+		return true
+	}
+	return zone.HardwareConfig != nil && *existing.HardwareConfig != *zone.HardwareConfig
+}
+
+// publishControllerConfig sends the Garden's new config to its controller. Errors are only logged since the Zone is
+// still valid and the controller's config can be regenerated with the controller_config endpoint
+func (api *ZonesAPI) publishControllerConfig(logger *slog.Logger, garden *pkg.Garden, zones []*pkg.Zone) {
+	err := api.worker.PublishControllerConfig(garden, zones)
+	if err != nil {
+		logger.Error("unable to publish controller config", "error", err)
+	}
+}
+
 func rangeQueryParam(r *http.Request) (time.Duration, error) {
 	timeRangeString := r.URL.Query().Get("range")
 	if len(timeRangeString) == 0 {
@@ -280,7 +425,26 @@ func limitQueryParam(r *http.Request) (uint64, error) {
 	return limit, nil
 }
 
-// WaterHistory responds with the Zone's recent water events read from InfluxDB
+func resolutionQueryParam(r *http.Request, timeRange time.Duration) (time.Duration, error) {
+	resolutionString := r.URL.Query().Get("resolution")
+	if len(resolutionString) == 0 {
+		resolutionString = "1h"
+	}
+
+	resolution, err := time.ParseDuration(resolutionString)
+	if err != nil {
+		return 0, err
+	}
+	if resolution <= 0 {
+		return 0, errors.New("resolution must be a positive duration")
+	}
+	if resolution > timeRange {
+		return 0, errors.New("resolution must not be longer than the range")
+	}
+	return resolution, nil
+}
+
+// WaterHistory responds with the Zone's recent water events read from InfluxDB or storage
 func (api *ZonesAPI) waterHistory(r *http.Request, zone *pkg.Zone) (render.Renderer, *babyapi.ErrResponse) {
 	logger := babyapi.GetLoggerFromContext(r.Context())
 	logger.Info("received request to get Zone water history")
@@ -314,6 +478,18 @@ func (api *ZonesAPI) getWaterHistoryFromRequest(r *http.Request, zone *pkg.Zone,
 	}
 	logger.Debug("using limit", "limit", limit)
 
+	if api.waterHistoryFromStorage {
+		logger.Debug("getting water history from storage")
+		history, err := api.storageClient.WaterHistory.GetWaterHistory(r.Context(), zone.GetID(), api.worker.Now().Add(-timeRange), limit)
+		if err != nil {
+			logger.Error("unable to get water history from storage", "error", err)
+			return nil, babyapi.InternalServerError(err)
+		}
+		logger.Debug("water history", "history", history)
+
+		return addExpectedLiters(history, zone, garden), nil
+	}
+
 	logger.Debug("getting water history from InfluxDB")
 	history, err := api.getWaterHistory(r.Context(), zone, garden, timeRange, limit)
 	if err != nil {
@@ -322,7 +498,63 @@ func (api *ZonesAPI) getWaterHistoryFromRequest(r *http.Request, zone *pkg.Zone,
 	}
 	logger.Debug("water history", "history", history)
 
-	return history, nil
+	return addExpectedLiters(history, zone, garden), nil
+}
+
+// moistureHistory responds with the Zone's average soil moisture over time read from InfluxDB so trends can be charted
+func (api *ZonesAPI) moistureHistory(r *http.Request, zone *pkg.Zone) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to get Zone moisture history")
+
+	garden, httpErr := api.getGardenFromRequest(r)
+	if httpErr != nil {
+		logger.Error("unable to get garden for zone", "error", httpErr)
+		return nil, httpErr
+	}
+
+	timeRange, err := rangeQueryParam(r)
+	if err != nil {
+		logger.Error("unable to parse time range", "error", err)
+		return nil, babyapi.ErrInvalidRequest(err)
+	}
+	if timeRange <= 0 {
+		return nil, babyapi.ErrInvalidRequest(errors.New("range must be a positive duration"))
+	}
+
+	resolution, err := resolutionQueryParam(r, timeRange)
+	if err != nil {
+		logger.Error("unable to parse resolution", "error", err)
+		return nil, babyapi.ErrInvalidRequest(err)
+	}
+	logger.Debug("using time range and resolution", "time_range", timeRange, "resolution", resolution)
+
+	history, err := api.getMoistureHistory(r.Context(), zone, garden, timeRange, resolution)
+	if err != nil {
+		logger.Error("unable to get moisture history from InfluxDB", "error", err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return NewZoneMoistureHistoryResponse(history, timeRange, resolution), nil
+}
+
+// addExpectedLiters uses the Zone's flow rate to estimate the liters expected for each water event so they can be
+// compared to the liters measured by a flow meter. Events that already have an estimate from when they were recorded
+// are not changed
+func addExpectedLiters(history []pkg.WaterHistory, zone *pkg.Zone, garden *pkg.Garden) []pkg.WaterHistory {
+	for i, h := range history {
+		if h.ExpectedLiters != nil {
+			continue
+		}
+		if h.Duration == nil {
+			continue
+		}
+		expected, ok := zone.EstimateLiters(garden, h.Duration.Duration)
+		if !ok {
+			return history
+		}
+		history[i].ExpectedLiters = &expected
+	}
+	return history
 }
 
 func (api *ZonesAPI) getMoisture(ctx context.Context, g *pkg.Garden, z *pkg.Zone) (float64, error) {
@@ -344,10 +576,39 @@ func (api *ZonesAPI) getWaterHistory(ctx context.Context, zone *pkg.Zone, garden
 		return
 	}
 
+	return waterHistoryFromMetrics(history), nil
+}
+
+// waterHistoryFromMetrics converts the water events from the metrics backend to WaterHistory
+func waterHistoryFromMetrics(history []map[string]interface{}) []pkg.WaterHistory {
+	var result []pkg.WaterHistory
+	for _, h := range history {
+		wh := pkg.WaterHistory{
+			Duration:   &pkg.Duration{Duration: time.Duration(h["Duration"].(int)) * time.Millisecond},
+			RecordTime: h["RecordTime"].(time.Time),
+		}
+		if ml, ok := h["Milliliters"].(float64); ok {
+			liters := ml / 1000
+			wh.MeasuredLiters = &liters
+		}
+		result = append(result, wh)
+	}
+	return result
+}
+
+// getMoistureHistory gets the Zone's average soil moisture for each window of the resolution from InfluxDB
+func (api *ZonesAPI) getMoistureHistory(ctx context.Context, zone *pkg.Zone, garden *pkg.Garden, timeRange, resolution time.Duration) (result []pkg.MoistureHistory, err error) {
+	defer api.influxdbClient.Close()
+
+	history, err := api.influxdbClient.GetMoistureHistory(ctx, *zone.Position, garden.TopicPrefix, timeRange, resolution)
+	if err != nil {
+		return
+	}
+
 	for _, h := range history {
-		result = append(result, pkg.WaterHistory{
-			Duration:   (time.Duration(h["Duration"].(int)) * time.Millisecond).String(),
+		result = append(result, pkg.MoistureHistory{
 			RecordTime: h["RecordTime"].(time.Time),
+			Percent:    h["Value"].(float64),
 		})
 	}
 	return
@@ -0,0 +1,306 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	"github.com/go-chi/render"
+	"github.com/rs/xid"
+)
+
+const (
+	zoneGroupBasePath = "/zone_groups"
+)
+
+// ZoneGroupsAPI encapsulates the structs and dependencies necessary for the "/zone_groups" API to function
+type ZoneGroupsAPI struct {
+	*babyapi.API[*pkg.ZoneGroup]
+
+	storageClient *storage.Client
+	worker        *worker.Worker
+	audit         *auditLog
+}
+
+// NewZoneGroupsAPI creates a new ZoneGroupsAPI. It is nested under the Gardens API
+func NewZoneGroupsAPI() *ZoneGroupsAPI {
+	api := &ZoneGroupsAPI{}
+
+	api.API = babyapi.NewAPI("ZoneGroups", zoneGroupBasePath, func() *pkg.ZoneGroup { return &pkg.ZoneGroup{} })
+
+	api.SetResponseWrapper(func(zg *pkg.ZoneGroup) render.Renderer {
+		return &ZoneGroupResponse{ZoneGroup: zg}
+	})
+
+	api.SetOnCreateOrUpdate(api.onCreateOrUpdate)
+
+	api.AddCustomIDRoute(http.MethodPost, "/action", api.GetRequestedResourceAndDo(api.zoneGroupAction))
+
+	api.SetGetAllFilter(func(r *http.Request) babyapi.FilterFunc[*pkg.ZoneGroup] {
+		gardenID := api.GetParentIDParam(r)
+		return func(zg *pkg.ZoneGroup) bool {
+			return zg.GardenID.String() == gardenID
+		}
+	})
+
+	api.ApplyExtension(conditionalRequests[*pkg.ZoneGroup]{})
+
+	return api
+}
+
+func (api *ZoneGroupsAPI) setup(storageClient *storage.Client, worker *worker.Worker) {
+	api.storageClient = storageClient
+	api.worker = worker
+
+	api.SetStorage(api.storageClient.ZoneGroups)
+}
+
+func (api *ZoneGroupsAPI) getGardenFromRequest(r *http.Request) (*pkg.Garden, *babyapi.ErrResponse) {
+	garden, err := babyapi.GetResourceFromContext[*pkg.Garden](r.Context(), api.ParentContextKey())
+	if err != nil {
+		if errors.Is(err, babyapi.ErrNotFound) {
+			return nil, babyapi.ErrNotFoundResponse
+		}
+		err = fmt.Errorf("error getting Garden %q for ZoneGroup: %w", api.GetParentIDParam(r), err)
+		return nil, babyapi.InternalServerError(err)
+	}
+
+	return garden, nil
+}
+
+// onCreateOrUpdate makes sure the ZoneGroup's Zones belong to the Garden and that its WaterSchedules do not overlap
+// with the other WaterSchedules used by each of the Zones
+func (api *ZoneGroupsAPI) onCreateOrUpdate(r *http.Request, zg *pkg.ZoneGroup) *babyapi.ErrResponse {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+
+	gardenID := api.GetParentIDParam(r)
+	if !zg.GardenID.IsNil() && gardenID != zg.GardenID.String() {
+		return babyapi.ErrInvalidRequest(errors.New("garden_id for ZoneGroup must match URL path"))
+	}
+
+	var err error
+	zg.GardenID, err = xid.FromString(gardenID)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid GardenID: %w", err))
+	}
+
+	waterSchedules := []*pkg.WaterSchedule{}
+	for _, id := range zg.WaterScheduleIDs {
+		ws, err := api.storageClient.WaterSchedules.Get(r.Context(), id.String())
+		if err != nil {
+			if errors.Is(err, babyapi.ErrNotFound) {
+				return babyapi.ErrInvalidRequest(fmt.Errorf("error getting WaterSchedule with ID %q: %w", id, err))
+			}
+			return babyapi.InternalServerError(fmt.Errorf("error getting WaterSchedule with ID %q: %w", id, err))
+		}
+		waterSchedules = append(waterSchedules, ws)
+	}
+
+	for _, id := range zg.ZoneIDs {
+		z, err := api.storageClient.Zones.Get(r.Context(), id.String())
+		if err != nil {
+			if errors.Is(err, babyapi.ErrNotFound) {
+				return babyapi.ErrInvalidRequest(fmt.Errorf("error getting Zone with ID %q: %w", id, err))
+			}
+			return babyapi.InternalServerError(fmt.Errorf("error getting Zone with ID %q: %w", id, err))
+		}
+		if z.GardenID != zg.GardenID {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("Zone %q does not belong to Garden %q", id, gardenID))
+		}
+		if z.EndDated() {
+			return babyapi.ErrInvalidRequest(fmt.Errorf("unable to add end-dated Zone %q", id))
+		}
+
+		apiErr := api.validateNoZoneConflicts(r, zg, z, waterSchedules)
+		if apiErr != nil {
+			logger.Error("invalid request to create or update ZoneGroup", "zone_id", id.String(), "error", apiErr.Err)
+			return apiErr
+		}
+	}
+
+	return nil
+}
+
+// validateNoZoneConflicts makes sure that the ZoneGroup's WaterSchedules will not water the Zone at the same time as
+// the ones it uses directly or through other ZoneGroups
+func (api *ZoneGroupsAPI) validateNoZoneConflicts(r *http.Request, zg *pkg.ZoneGroup, z *pkg.Zone, waterSchedules []*pkg.WaterSchedule) *babyapi.ErrResponse {
+	otherGroups, err := api.storageClient.GetZoneGroupsForZone(r.Context(), z)
+	if err != nil {
+		return babyapi.InternalServerError(err)
+	}
+
+	ids := slices.Clone(z.WaterScheduleIDs)
+	for _, other := range otherGroups {
+		if other.ID == zg.ID {
+			continue
+		}
+		ids = append(ids, other.WaterScheduleIDs...)
+	}
+
+	zoneWaterSchedules := slices.Clone(waterSchedules)
+	for _, id := range ids {
+		// The Zone can already use one of the ZoneGroup's WaterSchedules and will only be watered once
+		if zg.HasWaterSchedule(id) || slices.ContainsFunc(zoneWaterSchedules, func(ws *pkg.WaterSchedule) bool {
+			return ws.ID.ID == id
+		}) {
+			continue
+		}
+
+		ws, err := api.storageClient.WaterSchedules.Get(r.Context(), id.String())
+		if err != nil {
+			if errors.Is(err, babyapi.ErrNotFound) {
+				continue
+			}
+			return babyapi.InternalServerError(fmt.Errorf("error getting WaterSchedule with ID %q: %w", id, err))
+		}
+		zoneWaterSchedules = append(zoneWaterSchedules, ws)
+	}
+
+	err = pkg.ValidateNoConflicts(zoneWaterSchedules)
+	if err != nil {
+		return babyapi.ErrInvalidRequest(fmt.Errorf("invalid WaterSchedules for Zone %q: %w", z.GetID(), err))
+	}
+	return nil
+}
+
+// zoneGroupAction executes the ZoneAction for each of the ZoneGroup's Zones in order. Waterings are queued by the
+// Worker when the Garden already has MaxConcurrentZones watering, so large groups are watered in sequence
+func (api *ZoneGroupsAPI) zoneGroupAction(r *http.Request, zg *pkg.ZoneGroup) (render.Renderer, *babyapi.ErrResponse) {
+	logger := babyapi.GetLoggerFromContext(r.Context())
+	logger.Info("received request to execute ZoneGroup action")
+
+	if zg.EndDated() {
+		return nil, babyapi.ErrInvalidRequest(errors.New("unable to execute action on end-dated ZoneGroup"))
+	}
+	garden, httpErr := api.getGardenFromRequest(r)
+	if httpErr != nil {
+		logger.Error("unable to get garden for ZoneGroup", "error", httpErr)
+		return nil, httpErr
+	}
+
+	zoneAction := &action.ZoneAction{}
+	if err := render.Bind(r, zoneAction); err != nil {
+		logger.Error("invalid request for ZoneAction", "error", err)
+		return nil, babyapi.ErrInvalidRequest(err)
+	}
+	logger.Info("zone group action", "action", zoneAction)
+
+	zones := []*pkg.Zone{}
+	for _, id := range zg.ZoneIDs {
+		z, err := api.storageClient.Zones.Get(r.Context(), id.String())
+		if err != nil {
+			if errors.Is(err, babyapi.ErrNotFound) {
+				continue
+			}
+			return nil, babyapi.InternalServerError(fmt.Errorf("error getting Zone with ID %q: %w", id, err))
+		}
+		if z.EndDated() {
+			continue
+		}
+		zones = append(zones, z)
+	}
+
+	if zoneAction.Water != nil && zoneAction.Water.DryRun {
+		resp := &ZoneGroupActionResponse{Zones: []ZoneGroupActionResult{}}
+		for _, z := range zones {
+			decision, err := api.worker.DecideWaterAction(r.Context(), garden, z, zoneAction.Water)
+			if err != nil {
+				logger.Error("unable to calculate WaterAction", "zone_id", z.GetID(), "error", err)
+				if errors.Is(err, worker.ErrMissingWaterDuration) {
+					return nil, babyapi.ErrInvalidRequest(fmt.Errorf("error calculating WaterAction for Zone %q: %w", z.GetID(), err))
+				}
+				return nil, babyapi.InternalServerError(err)
+			}
+			resp.Zones = append(resp.Zones, ZoneGroupActionResult{ZoneID: z.GetID(), Water: decision})
+		}
+
+		return resp, nil
+	}
+
+	resp := &ZoneGroupActionResponse{}
+	for _, z := range zones {
+		// Each Zone gets its own copy of the WaterAction so its status is tracked separately
+		zAction := *zoneAction
+		result := ZoneGroupActionResult{ZoneID: z.GetID()}
+		if zoneAction.Water != nil {
+			water := *zoneAction.Water
+			water.ID = xid.New().String()
+			zAction.Water = &water
+			result.ActionID = water.ID
+		}
+
+		if err := api.worker.ExecuteZoneAction(r.Context(), garden, z, &zAction); err != nil {
+			logger.Error("unable to execute ZoneAction", "zone_id", z.GetID(), "error", err)
+			if errors.Is(err, worker.ErrMissingWaterDuration) {
+				return nil, babyapi.ErrInvalidRequest(fmt.Errorf("error executing ZoneAction for Zone %q: %w", z.GetID(), err))
+			}
+			return nil, babyapi.InternalServerError(err)
+		}
+		if result.ActionID != "" {
+			resp.Zones = append(resp.Zones, result)
+		}
+	}
+
+	actionName, details := zoneActionAuditDetails(zoneAction)
+	api.audit.record(r, "zone_group", zg.GetID(), actionName, details)
+
+	render.Status(r, http.StatusAccepted)
+	return resp, nil
+}
+
+// ZoneGroupResponse is used to represent a ZoneGroup in the response body with hypermedia Links
+type ZoneGroupResponse struct {
+	*pkg.ZoneGroup
+
+	Links []Link `json:"links,omitempty"`
+}
+
+// Render ...
+func (resp *ZoneGroupResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	if resp == nil {
+		return nil
+	}
+
+	gardenPath := fmt.Sprintf("%s/%s", gardenBasePath, resp.GardenID)
+	resp.Links = append(resp.Links,
+		Link{
+			"self",
+			fmt.Sprintf("%s%s/%s", gardenPath, zoneGroupBasePath, resp.ID),
+		},
+		Link{
+			"garden",
+			gardenPath,
+		},
+	)
+
+	if !resp.EndDated() {
+		resp.Links = append(resp.Links, Link{
+			"action",
+			fmt.Sprintf("%s%s/%s/action", gardenPath, zoneGroupBasePath, resp.ID),
+		})
+	}
+	return nil
+}
+
+// ZoneGroupActionResponse has the ID of each Zone's WaterAction. If the WaterAction is a dry-run, it shows how each
+// Zone would be watered instead
+type ZoneGroupActionResponse struct {
+	Zones []ZoneGroupActionResult `json:"zones,omitempty"`
+}
+
+// ZoneGroupActionResult is the WaterAction's ID or dry-run WaterDecision for one of the ZoneGroup's Zones
+type ZoneGroupActionResult struct {
+	ZoneID   string                `json:"zone_id"`
+	ActionID string                `json:"action_id,omitempty"`
+	Water    *worker.WaterDecision `json:"water,omitempty"`
+}
+
+func (*ZoneGroupActionResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
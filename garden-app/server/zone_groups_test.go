@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createExampleZoneGroup() *pkg.ZoneGroup {
+	return &pkg.ZoneGroup{
+		ID:        babyapi.ID{ID: id},
+		Name:      "test-zone-group",
+		GardenID:  id,
+		ZoneIDs:   []xid.ID{id, id2},
+		CreatedAt: &createdAt,
+	}
+}
+
+// setupZoneGroupStorage creates the example Garden with two Zones. The second Zone does not use any WaterSchedules
+func setupZoneGroupStorage(t *testing.T) *storage.Client {
+	t.Helper()
+
+	storageClient := setupZoneAndGardenStorage(t)
+
+	zone := createExampleZone()
+	zone.ID = babyapi.ID{ID: id2}
+	zone.Name = "test-zone-2"
+	one := uint(1)
+	zone.Position = &one
+	zone.WaterScheduleIDs = nil
+
+	err := storageClient.Zones.Set(context.Background(), zone)
+	assert.NoError(t, err)
+
+	return storageClient
+}
+
+func TestCreateZoneGroup(t *testing.T) {
+	conflictingWS := &pkg.WaterSchedule{
+		ID:        babyapi.ID{ID: id2},
+		Duration:  &pkg.Duration{Duration: time.Second * 10},
+		Interval:  &pkg.Duration{Duration: time.Hour * 24},
+		StartTime: pkg.NewStartTime(createdAt.Add(-1 * time.Second)),
+	}
+	otherGardenZone := createExampleZone()
+	otherGardenZone.ID = babyapi.NewID()
+	otherGardenZone.GardenID = id2
+	missingZoneID := xid.New()
+
+	tests := []struct {
+		name           string
+		waterSchedules []*pkg.WaterSchedule
+		body           string
+		expectedRegexp string
+		code           int
+	}{
+		{
+			"Successful",
+			[]*pkg.WaterSchedule{createExampleWaterSchedule()},
+			`{"name":"beds","zone_ids":["c5cvhpcbcv45e8bp16dg","chkodpg3lcj13q82mq40"],"water_schedule_ids":["c5cvhpcbcv45e8bp16dg"]}`,
+			`{"id":"[0-9a-v]{20}","name":"beds","garden_id":"c5cvhpcbcv45e8bp16dg","zone_ids":\["c5cvhpcbcv45e8bp16dg","chkodpg3lcj13q82mq40"\],"water_schedule_ids":\["c5cvhpcbcv45e8bp16dg"\],"created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","links":\[{"rel":"self","href":"/gardens/c5cvhpcbcv45e8bp16dg/zone_groups/[0-9a-v]{20}"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"action","href":"/gardens/c5cvhpcbcv45e8bp16dg/zone_groups/[0-9a-v]{20}/action"}\]}`,
+			http.StatusCreated,
+		},
+		{
+			"ErrorMissingZoneIDs",
+			nil,
+			`{"name":"beds"}`,
+			`{"status":"Invalid request.","error":"missing required zone_ids field"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorZoneNotFound",
+			nil,
+			fmt.Sprintf(`{"name":"beds","zone_ids":["%s"]}`, missingZoneID),
+			fmt.Sprintf(`{"status":"Invalid request.","error":"error getting Zone with ID \\"%s\\": resource not found"}`, missingZoneID),
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorZoneInOtherGarden",
+			nil,
+			fmt.Sprintf(`{"name":"beds","zone_ids":["%s"]}`, otherGardenZone.ID),
+			fmt.Sprintf(`{"status":"Invalid request.","error":"Zone \\"%s\\" does not belong to Garden \\"c5cvhpcbcv45e8bp16dg\\""}`, otherGardenZone.ID),
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorWaterScheduleNotFound",
+			nil,
+			`{"name":"beds","zone_ids":["chkodpg3lcj13q82mq40"],"water_schedule_ids":["chkodpg3lcj13q82mq40"]}`,
+			`{"status":"Invalid request.","error":"error getting WaterSchedule with ID \\"chkodpg3lcj13q82mq40\\": resource not found"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorConflictingWaterSchedules",
+			[]*pkg.WaterSchedule{createExampleWaterSchedule(), conflictingWS},
+			`{"name":"beds","zone_ids":["c5cvhpcbcv45e8bp16dg"],"water_schedule_ids":["chkodpg3lcj13q82mq40"]}`,
+			`{"status":"Invalid request.","error":"invalid WaterSchedules for Zone \\"c5cvhpcbcv45e8bp16dg\\": WaterSchedules \\"[0-9a-v]{20}\\" and \\"[0-9a-v]{20}\\" overlap at \d{4}-\d{2}-\d\dT11:24:52-07:00"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient := setupZoneGroupStorage(t)
+
+			err := storageClient.Zones.Set(context.Background(), otherGardenZone)
+			assert.NoError(t, err)
+
+			for _, ws := range tt.waterSchedules {
+				err := storageClient.WaterSchedules.Set(context.Background(), ws)
+				assert.NoError(t, err)
+			}
+
+			api := NewZoneGroupsAPI()
+			api.setup(storageClient, worker.NewWorker(storageClient, nil, nil, slog.Default()))
+
+			garden := createExampleGarden()
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/gardens/%s/zone_groups", garden.ID), strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			w := babytest.TestWithParentRoute[*pkg.ZoneGroup, *pkg.Garden](t, api.API, garden, "Gardens", "/gardens", r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Regexp(t, tt.expectedRegexp, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestZoneGroupAction(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(*mqtt.MockClient)
+		body      string
+		expected  string
+		status    int
+	}{
+		{
+			"SuccessfulWaterAction",
+			func(mqttClient *mqtt.MockClient) {
+				mqttClient.On("WaterTopic", "test-garden").Return("garden/action/water", nil)
+				mqttClient.On("Publish", "garden/action/water", mock.Anything).Return(nil).Twice()
+			},
+			`{"water":{"duration":1000}}`,
+			`{"zones":[{"zone_id":"c5cvhpcbcv45e8bp16dg","action_id":"ACTION_ID"},{"zone_id":"chkodpg3lcj13q82mq40","action_id":"ACTION_ID"}]}`,
+			http.StatusAccepted,
+		},
+		{
+			"DryRunWaterAction",
+			func(_ *mqtt.MockClient) {},
+			`{"water":{"duration":"30s","dry_run":true}}`,
+			`{"zones":[{"zone_id":"c5cvhpcbcv45e8bp16dg","water":{"duration":"30s","requested_duration":"30s","scale_factor":1,"skip":false}},{"zone_id":"chkodpg3lcj13q82mq40","water":{"duration":"30s","requested_duration":"30s","scale_factor":1,"skip":false}}]}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorMissingDuration",
+			func(_ *mqtt.MockClient) {},
+			`{"water":{"dry_run":true}}`,
+			`{"status":"Invalid request.","error":"error calculating WaterAction for Zone \"c5cvhpcbcv45e8bp16dg\": missing duration and Zone does not have an active WaterSchedule"}`,
+			http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mqttClient := new(mqtt.MockClient)
+			tt.setupMock(mqttClient)
+			mqttClient.On("Disconnect", uint(100)).Return()
+
+			storageClient := setupZoneGroupStorage(t)
+
+			zoneGroup := createExampleZoneGroup()
+			err := storageClient.ZoneGroups.Set(context.Background(), zoneGroup)
+			assert.NoError(t, err)
+
+			api := NewZoneGroupsAPI()
+			api.setup(storageClient, worker.NewWorker(storageClient, nil, mqttClient, slog.Default()))
+
+			api.worker.StartAsync()
+
+			garden := createExampleGarden()
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/gardens/%s/zone_groups/%s/action", garden.ID, zoneGroup.ID), strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			w := babytest.TestWithParentRoute[*pkg.ZoneGroup, *pkg.Garden](t, api.API, garden, "Gardens", "/gardens", r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.expected, replaceActionIDs(w.Body.String()))
+
+			api.worker.Stop()
+			mqttClient.AssertExpectations(t)
+		})
+	}
+}
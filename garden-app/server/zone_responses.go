@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/worker"
 	"github.com/calvinmclean/babyapi"
 	"github.com/go-chi/render"
 )
@@ -19,6 +20,8 @@ type ZoneResponse struct {
 	WeatherData *WeatherData     `json:"weather_data,omitempty"`
 	NextWater   NextWaterDetails `json:"next_water,omitempty"`
 	Links       []Link           `json:"links,omitempty"`
+	// DeferredWaterings are scheduled waterings waiting for a BlackoutWindow to end
+	DeferredWaterings []worker.DeferredWatering `json:"deferred_waterings,omitempty"`
 
 	// History is only used in HTML responses and is excluded from JSON
 	History      ZoneWaterHistoryResponse `json:"-"`
@@ -57,8 +60,14 @@ func (zr *ZoneResponse) Render(w http.ResponseWriter, r *http.Request) error {
 
 	logger := babyapi.GetLoggerFromContext(r.Context())
 
+	// The next watering can come from the Zone's WaterSchedules or the ones used by its ZoneGroups
+	waterScheduleIDs, err := zr.api.storageClient.GetWaterScheduleIDsForZone(ctx, zr.Zone)
+	if err != nil {
+		return fmt.Errorf("unable to get WaterSchedules for ZoneResponse: %w", err)
+	}
+
 	ws := []*pkg.WaterSchedule{}
-	for _, id := range zr.Zone.WaterScheduleIDs {
+	for _, id := range waterScheduleIDs {
 		result, err := zr.api.storageClient.WaterSchedules.Get(ctx, id.String())
 		if err != nil {
 			return fmt.Errorf("unable to get WaterSchedule for ZoneResponse: %w", err)
@@ -116,6 +125,10 @@ func (zr *ZoneResponse) Render(w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
+	if deferred := zr.api.worker.GetDeferredWaterings(zr.Zone); len(deferred) > 0 {
+		zr.DeferredWaterings = deferred
+	}
+
 	nextWaterSchedule := zr.api.worker.GetNextActiveWaterSchedule(ws)
 
 	if nextWaterSchedule == nil {
@@ -125,7 +138,7 @@ func (zr *ZoneResponse) Render(w http.ResponseWriter, r *http.Request) error {
 		return nil
 	}
 
-	zr.NextWater = GetNextWaterDetails(r, nextWaterSchedule, zr.api.worker, excludeWeatherData)
+	zr.NextWater = GetNextWaterDetails(r, nextWaterSchedule, garden, zr.Zone, zr.api.worker, excludeWeatherData)
 	zr.NextWater.WaterScheduleID = &nextWaterSchedule.ID.ID
 
 	if zr.Zone.SkipCount != nil && *zr.Zone.SkipCount > 0 {
@@ -134,8 +147,16 @@ func (zr *ZoneResponse) Render(w http.ResponseWriter, r *http.Request) error {
 		zr.NextWater.Time = &newNextTime
 	}
 
+	if zr.NextWater.Time != nil {
+		if until, ok := zr.api.worker.BlackoutEnd(garden, *zr.NextWater.Time); ok {
+			until = until.In(zr.NextWater.Time.Location())
+			zr.NextWater.DeferredUntil = &until
+			zr.NextWater.Message = "deferred until the end of a blackout window"
+		}
+	}
+
 	if nextWaterSchedule.HasWeatherControl() && !excludeWeatherData {
-		zr.WeatherData = getWeatherData(ctx, nextWaterSchedule, zr.api.storageClient)
+		zr.WeatherData = getWeatherData(ctx, nextWaterSchedule.ForZone(zr.Zone), garden, zr.Zone, zr.api.storageClient)
 
 		if nextWaterSchedule.HasSoilMoistureControl() && garden != nil {
 			logger.Debug("getting moisture data for Zone")
@@ -184,20 +205,34 @@ func (azr AllZonesResponse) HTML(r *http.Request) string {
 	return zonesPageTemplate.Render(r, data)
 }
 
-// ZoneWaterHistoryResponse wraps a slice of WaterHistory structs plus some aggregate stats for an HTTP response
+// ZoneWaterHistoryResponse wraps a slice of WaterHistory structs plus some aggregate stats for an HTTP response.
+// MeasuredLiters and ExpectedLiters only include events that have both values so they can be compared to detect
+// leaks or clogged lines
 type ZoneWaterHistoryResponse struct {
-	History []pkg.WaterHistory `json:"history"`
-	Count   int                `json:"count"`
-	Average string             `json:"average"`
-	Total   string             `json:"total"`
+	History        []pkg.WaterHistory `json:"history"`
+	Count          int                `json:"count"`
+	Average        string             `json:"average"`
+	Total          string             `json:"total"`
+	MeasuredLiters *float64           `json:"measured_liters,omitempty"`
+	ExpectedLiters *float64           `json:"expected_liters,omitempty"`
 }
 
 // NewZoneWaterHistoryResponse creates a response by creating some basic statistics about a list of history events
 func NewZoneWaterHistoryResponse(history []pkg.WaterHistory) ZoneWaterHistoryResponse {
 	total := time.Duration(0)
+	var measured, expected *float64
 	for _, h := range history {
-		amountDuration, _ := time.ParseDuration(h.Duration)
-		total += amountDuration
+		if h.Duration != nil {
+			total += h.Duration.Duration
+		}
+
+		if h.MeasuredLiters != nil && h.ExpectedLiters != nil {
+			if measured == nil {
+				measured, expected = new(float64), new(float64)
+			}
+			*measured += *h.MeasuredLiters
+			*expected += *h.ExpectedLiters
+		}
 	}
 	count := len(history)
 	average := time.Duration(0)
@@ -205,10 +240,12 @@ func NewZoneWaterHistoryResponse(history []pkg.WaterHistory) ZoneWaterHistoryRes
 		average = time.Duration(int(total) / len(history))
 	}
 	return ZoneWaterHistoryResponse{
-		History: history,
-		Count:   count,
-		Average: average.String(),
-		Total:   time.Duration(total).String(),
+		History:        history,
+		Count:          count,
+		Average:        average.String(),
+		Total:          time.Duration(total).String(),
+		MeasuredLiters: measured,
+		ExpectedLiters: expected,
 	}
 }
 
@@ -218,13 +255,62 @@ func (resp ZoneWaterHistoryResponse) Render(_ http.ResponseWriter, _ *http.Reque
 	return nil
 }
 
+// ZoneMoistureHistoryResponse wraps the Zone's soil moisture series plus the minimum, maximum, and average of it
+type ZoneMoistureHistoryResponse struct {
+	History    []pkg.MoistureHistory `json:"history"`
+	Count      int                   `json:"count"`
+	Range      string                `json:"range"`
+	Resolution string                `json:"resolution"`
+	Average    *float64              `json:"average,omitempty"`
+	Min        *float64              `json:"min,omitempty"`
+	Max        *float64              `json:"max,omitempty"`
+}
+
+// NewZoneMoistureHistoryResponse creates a response with basic statistics about the moisture series. The statistics
+// are left out when there is no data
+func NewZoneMoistureHistoryResponse(history []pkg.MoistureHistory, timeRange, resolution time.Duration) ZoneMoistureHistoryResponse {
+	resp := ZoneMoistureHistoryResponse{
+		History:    history,
+		Count:      len(history),
+		Range:      timeRange.String(),
+		Resolution: resolution.String(),
+	}
+	if len(history) == 0 {
+		return resp
+	}
+
+	total := 0.0
+	minimum, maximum := history[0].Percent, history[0].Percent
+	for _, h := range history {
+		total += h.Percent
+		minimum = min(minimum, h.Percent)
+		maximum = max(maximum, h.Percent)
+	}
+	average := total / float64(len(history))
+	resp.Average = &average
+	resp.Min = &minimum
+	resp.Max = &maximum
+	return resp
+}
+
+// Render is used to make this struct compatible with the go-chi webserver for writing
+// the JSON response
+func (resp ZoneMoistureHistoryResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
 func filterZoneByGardenID(gardenID string) babyapi.FilterFunc[*pkg.Zone] {
 	return func(z *pkg.Zone) bool {
 		return z.GardenID.String() == gardenID
 	}
 }
 
-type ZoneActionResponse struct{}
+// ZoneActionResponse has the ID used to get the status of the WaterAction. If the WaterAction is a dry-run, it shows
+// how the watering was calculated instead
+type ZoneActionResponse struct {
+	ActionID string                `json:"action_id,omitempty"`
+	Water    *worker.WaterDecision `json:"water,omitempty"`
+}
 
 func (*ZoneActionResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
 	return nil
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
@@ -172,7 +173,7 @@ func TestGetZone(t *testing.T) {
 				influxdbClient.On("GetMoisture", mock.Anything, mock.Anything, mock.Anything).Return(float64(2), nil)
 				influxdbClient.On("Close")
 			},
-			`{"name":"test-zone","id":"c5cvhpcbcv45e8bp16dg","garden_id":"c5cvhpcbcv45e8bp16dg","position":0,"created_at":"2021-10-03T11:24:52.891386-07:00","water_schedule_ids":\["c5cvhpcbcv45e8bp16dg"\],"skip_count":null,"weather_data":{"rain":{"mm":25.4,"scale_factor":0},"average_temperature":{"celsius":80,"scale_factor":1.5},"soil_moisture_percent":2},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"0s","water_schedule_id":"c5cvhpcbcv45e8bp16dg"},"links":\[{"rel":"self","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"action","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/action"},{"rel":"history","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/history"}\]}`,
+			`{"name":"test-zone","id":"c5cvhpcbcv45e8bp16dg","garden_id":"c5cvhpcbcv45e8bp16dg","position":0,"created_at":"2021-10-03T11:24:52.891386-07:00","water_schedule_ids":\["c5cvhpcbcv45e8bp16dg"\],"skip_count":null,"weather_data":{"rain":{"mm":25.4,"scale_factor":0},"average_temperature":{"celsius":80,"scale_factor":1.5},"soil_moisture_percent":2,"scale_factor":0,"effective_duration":"0s"},"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:52-07:00","duration":"0s","water_schedule_id":"c5cvhpcbcv45e8bp16dg"},"links":\[{"rel":"self","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg"},{"rel":"garden","href":"/gardens/c5cvhpcbcv45e8bp16dg"},{"rel":"action","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/action"},{"rel":"history","href":"/gardens/c5cvhpcbcv45e8bp16dg/zones/c5cvhpcbcv45e8bp16dg/history"}\]}`,
 		},
 		{
 			"SuccessfulWithMoistureRainAndTemperatureDataButWeatherDataExcluded",
@@ -296,7 +297,7 @@ func TestZoneAction(t *testing.T) {
 				mqttClient.On("Publish", "garden/action/water", mock.Anything).Return(nil)
 			},
 			`{"water":{"duration":1000}}`,
-			"{}",
+			`{"action_id":"ACTION_ID"}`,
 			http.StatusAccepted,
 		},
 		{
@@ -308,6 +309,41 @@ func TestZoneAction(t *testing.T) {
 			`{"status":"Server Error.","error":"unable to execute WaterAction: unable to fill MQTT topic template: template error"}`,
 			http.StatusInternalServerError,
 		},
+		{
+			"DryRunWaterAction",
+			func(_ *mqtt.MockClient) {},
+			`{"water":{"duration":"30s","dry_run":true}}`,
+			`{"water":{"duration":"30s","requested_duration":"30s","scale_factor":1,"skip":false}}`,
+			http.StatusOK,
+		},
+		{
+			"ErrorMissingDuration",
+			func(_ *mqtt.MockClient) {},
+			`{"water":{"dry_run":true}}`,
+			`{"status":"Invalid request.","error":"missing duration and Zone does not have an active WaterSchedule"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorNegativeDuration",
+			func(_ *mqtt.MockClient) {},
+			`{"water":{"duration":"-1s"}}`,
+			`{"status":"Invalid request.","error":"duration must not be negative"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"SuccessfulStopActionNotWatering",
+			func(_ *mqtt.MockClient) {},
+			`{"stop":{}}`,
+			"{}",
+			http.StatusAccepted,
+		},
+		{
+			"ErrorWaterAndStop",
+			func(_ *mqtt.MockClient) {},
+			`{"water":{"duration":1000},"stop":{}}`,
+			`{"status":"Invalid request.","error":"only one of water and stop can be used"}`,
+			http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -340,7 +376,7 @@ func TestZoneAction(t *testing.T) {
 			w := babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, zr.API, garden, "Gardens", "/gardens", r)
 
 			assert.Equal(t, tt.status, w.Code)
-			assert.Equal(t, tt.expected, strings.TrimSpace(w.Body.String()))
+			assert.Equal(t, tt.expected, replaceActionIDs(w.Body.String()))
 
 			zr.worker.Stop()
 			mqttClient.AssertExpectations(t)
@@ -370,7 +406,7 @@ func TestZoneActionForm(t *testing.T) {
 				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":1000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil)
 			},
 			`water.duration=1000`,
-			"{}",
+			`{"action_id":"ACTION_ID"}`,
 			http.StatusAccepted,
 		},
 		{
@@ -380,7 +416,7 @@ func TestZoneActionForm(t *testing.T) {
 				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":2000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil)
 			},
 			`water.duration=2s`,
-			"{}",
+			`{"action_id":"ACTION_ID"}`,
 			http.StatusAccepted,
 		},
 	}
@@ -415,7 +451,7 @@ func TestZoneActionForm(t *testing.T) {
 			w := babytest.TestWithParentRoute(t, zr.API, garden, "Gardens", "/gardens", r)
 
 			assert.Equal(t, tt.status, w.Code)
-			assert.Equal(t, tt.expected, strings.TrimSpace(w.Body.String()))
+			assert.Equal(t, tt.expected, replaceActionIDs(w.Body.String()))
 
 			zr.worker.Stop()
 			mqttClient.AssertExpectations(t)
@@ -584,13 +620,22 @@ func TestGetAllZones(t *testing.T) {
 }
 
 func TestCreateZone(t *testing.T) {
-	otherCreatedAt := createdAt.Add(-1 * time.Second)
+	// otherWS finishes watering right before the example WaterSchedule starts so they do not conflict
+	otherCreatedAt := createdAt.Add(-10 * time.Second)
 	otherWS := &pkg.WaterSchedule{
 		ID:        babyapi.ID{ID: id2},
 		Duration:  &pkg.Duration{Duration: time.Second * 10},
 		Interval:  &pkg.Duration{Duration: time.Hour * 24},
 		StartTime: pkg.NewStartTime(otherCreatedAt),
 	}
+	conflictingWS := &pkg.WaterSchedule{
+		ID:        babyapi.ID{ID: id2},
+		Duration:  &pkg.Duration{Duration: time.Second * 10},
+		Interval:  &pkg.Duration{Duration: time.Hour * 24},
+		StartTime: pkg.NewStartTime(createdAt.Add(-1 * time.Second)),
+	}
+	gardenWithHardware := createExampleGarden()
+	gardenWithHardware.ControllerHardware = &pkg.ControllerHardware{LightPin: "GPIO_NUM_16"}
 	gardenWithZone := createExampleGarden()
 	gardenWithZone.ID = babyapi.ID{ID: id2}
 	one := uint(1)
@@ -633,9 +678,25 @@ func TestCreateZone(t *testing.T) {
 			[]*pkg.WaterSchedule{createExampleWaterSchedule(), otherWS},
 			createExampleGarden(),
 			`{"name":"test-zone","position":0,"water_schedule_ids":["c5cvhpcbcv45e8bp16dg","chkodpg3lcj13q82mq40"]}`,
-			`{"name":"test-zone","id":"[0-9a-v]{20}","garden_id":"c5cvhpcbcv45e8bp16dg","position":0,"created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","water_schedule_ids":\["c5cvhpcbcv45e8bp16dg","chkodpg3lcj13q82mq40"\],"skip_count":null,"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:51-07:00","duration":"10s","water_schedule_id":"chkodpg3lcj13q82mq40"},"links":\[{"rel":"self","href":"/gardens/[0-9a-v]{20}/zones/[0-9a-v]{20}"},{"rel":"garden","href":"/gardens/[0-9a-v]{20}"},{"rel":"action","href":"/gardens/[0-9a-v]{20}/zones/[0-9a-v]{20}/action"},{"rel":"history","href":"/gardens/[0-9a-v]{20}/zones/[0-9a-v]{20}/history"}\]}`,
+			`{"name":"test-zone","id":"[0-9a-v]{20}","garden_id":"c5cvhpcbcv45e8bp16dg","position":0,"created_at":"\d{4}-\d{2}-\d\dT\d\d:\d\d:\d\d\.\d+(-07:00|Z)","water_schedule_ids":\["c5cvhpcbcv45e8bp16dg","chkodpg3lcj13q82mq40"\],"skip_count":null,"next_water":{"time":"\d\d\d\d-\d\d-\d\dT11:24:42-07:00","duration":"10s","water_schedule_id":"chkodpg3lcj13q82mq40"},"links":\[{"rel":"self","href":"/gardens/[0-9a-v]{20}/zones/[0-9a-v]{20}"},{"rel":"garden","href":"/gardens/[0-9a-v]{20}"},{"rel":"action","href":"/gardens/[0-9a-v]{20}/zones/[0-9a-v]{20}/action"},{"rel":"history","href":"/gardens/[0-9a-v]{20}/zones/[0-9a-v]{20}/history"}\]}`,
 			http.StatusCreated,
 		},
+		{
+			"ErrorConflictingWaterSchedules",
+			[]*pkg.WaterSchedule{createExampleWaterSchedule(), conflictingWS},
+			createExampleGarden(),
+			`{"name":"test-zone","position":0,"water_schedule_ids":["c5cvhpcbcv45e8bp16dg","chkodpg3lcj13q82mq40"]}`,
+			`{"status":"Invalid request.","error":"WaterSchedules \\"c5cvhpcbcv45e8bp16dg\\" and \\"chkodpg3lcj13q82mq40\\" overlap at \d{4}-\d{2}-\d\dT11:24:52-07:00"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"ErrorPinConflict",
+			[]*pkg.WaterSchedule{createExampleWaterSchedule()},
+			gardenWithHardware,
+			`{"name":"test-zone","position":0,"water_schedule_ids":["c5cvhpcbcv45e8bp16dg"],"hardware_config":{"pump_pin":"GPIO_NUM_18","valve_pin":"GPIO_NUM_16"}}`,
+			`{"status":"Invalid request.","error":"pin \\"GPIO_NUM_16\\" is used by light_pin and zone \\"test-zone\\" valve_pin"}`,
+			http.StatusBadRequest,
+		},
 		{
 			"SuccessfulWithGardenIDSet",
 			[]*pkg.WaterSchedule{createExampleWaterSchedule()},
@@ -733,13 +794,20 @@ func TestCreateZone(t *testing.T) {
 }
 
 func TestUpdateZonePUT(t *testing.T) {
-	otherCreatedAt := createdAt.Add(-1 * time.Second)
+	// otherWS finishes watering right before the example WaterSchedule starts so they do not conflict
+	otherCreatedAt := createdAt.Add(-10 * time.Second)
 	otherWS := &pkg.WaterSchedule{
 		ID:        babyapi.ID{ID: id2},
 		Duration:  &pkg.Duration{Duration: time.Second * 10},
 		Interval:  &pkg.Duration{Duration: time.Hour * 24},
 		StartTime: pkg.NewStartTime(otherCreatedAt),
 	}
+	conflictingWS := &pkg.WaterSchedule{
+		ID:        babyapi.ID{ID: id2},
+		Duration:  &pkg.Duration{Duration: time.Second * 10},
+		Interval:  &pkg.Duration{Duration: time.Hour * 24},
+		StartTime: pkg.NewStartTime(createdAt.Add(-1 * time.Second)),
+	}
 	gardenWithZone := createExampleGarden()
 	gardenWithZone.ID = babyapi.ID{ID: id2}
 	one := uint(1)
@@ -809,6 +877,14 @@ func TestUpdateZonePUT(t *testing.T) {
 			``,
 			http.StatusOK,
 		},
+		{
+			"ErrorConflictingWaterSchedules",
+			[]*pkg.WaterSchedule{createExampleWaterSchedule(), conflictingWS},
+			createExampleGarden(),
+			`{"id":"c5cvhpcbcv45e8bp16dg","name":"test-zone","position":0,"water_schedule_ids":["c5cvhpcbcv45e8bp16dg","chkodpg3lcj13q82mq40"]}`,
+			`overlap at`,
+			http.StatusBadRequest,
+		},
 		{
 			"ErrorNegativeZonePosition",
 			nil,
@@ -930,6 +1006,17 @@ func TestWaterHistory(t *testing.T) {
 			`{"history":[{"duration":"3s","record_time":"2021-10-03T11:24:52.891386-07:00"}],"count":1,"average":"3s","total":"3s"}`,
 			http.StatusOK,
 		},
+		{
+			"SuccessfulWaterHistoryWithFlowMeter",
+			func(influxdbClient *influxdb.MockClient) {
+				influxdbClient.On("GetWaterHistory", mock.Anything, uint(0), "test-garden", time.Hour*72, uint64(0)).
+					Return([]map[string]interface{}{{"Duration": 3000, "RecordTime": recordTime, "Milliliters": 250.0}}, nil)
+				influxdbClient.On("Close")
+			},
+			"",
+			`{"history":[{"duration":"3s","record_time":"2021-10-03T11:24:52.891386-07:00","measured_liters":0.25}],"count":1,"average":"3s","total":"3s"}`,
+			http.StatusOK,
+		},
 		{
 			"SuccessfulWaterHistoryWithLimit",
 			func(influxdbClient *influxdb.MockClient) {
@@ -989,6 +1076,211 @@ func TestWaterHistory(t *testing.T) {
 	}
 }
 
+func TestWaterHistoryFromStorage(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		queryParams string
+		expected    string
+	}{
+		{
+			"DefaultRange",
+			"",
+			`{"history":[{"duration":"3s","record_time":"2023-06-01T07:00:00Z"},{"duration":"2s","record_time":"2023-06-01T06:00:00Z"}],"count":2,"average":"2.5s","total":"5s"}`,
+		},
+		{
+			"RangeAndLimit",
+			"?range=720h&limit=3",
+			`{"history":[{"duration":"3s","record_time":"2023-06-01T07:00:00Z"},{"duration":"2s","record_time":"2023-06-01T06:00:00Z"},{"duration":"1s","record_time":"2023-05-28T04:00:00Z"}],"count":3,"average":"2s","total":"6s"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			assert.NoError(t, err)
+
+			w := worker.NewWorker(storageClient, nil, nil, slog.Default())
+			w.SetClock(clock.NewVirtual(now))
+
+			// InfluxDB is not used, so the mock will fail if it is called
+			zr := NewZonesAPI()
+			zr.setup(storageClient, new(influxdb.MockClient), w)
+			zr.waterHistoryFromStorage = true
+
+			garden := createExampleGarden()
+			zone := createExampleZone()
+
+			err = storageClient.Gardens.Set(context.Background(), garden)
+			assert.NoError(t, err)
+			err = storageClient.Zones.Set(context.Background(), zone)
+			assert.NoError(t, err)
+
+			for _, h := range []pkg.WaterHistory{
+				{Duration: &pkg.Duration{Duration: time.Second}, RecordTime: now.Add(-100 * time.Hour)},
+				{Duration: &pkg.Duration{Duration: 2 * time.Second}, RecordTime: now.Add(-2 * time.Hour)},
+				{Duration: &pkg.Duration{Duration: 3 * time.Second}, RecordTime: now.Add(-1 * time.Hour)},
+			} {
+				err = storageClient.WaterHistory.AddWaterHistory(context.Background(), zone.GetID(), h)
+				assert.NoError(t, err)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/gardens/%s/zones/%s/history%s", garden.ID, zone.ID, tt.queryParams), http.NoBody)
+			r.Header.Set("X-TZ-Offset", "420")
+			rr := babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, zr.API, garden, "Gardens", "/gardens", r)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.expected, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestWaterHistoryMeasuredAndExpectedLiters(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 8, 0, 0, 0, time.UTC)
+
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	assert.NoError(t, err)
+
+	w := worker.NewWorker(storageClient, nil, nil, slog.Default())
+	w.SetClock(clock.NewVirtual(now))
+
+	zr := NewZonesAPI()
+	zr.setup(storageClient, new(influxdb.MockClient), w)
+	zr.waterHistoryFromStorage = true
+
+	garden := createExampleGarden()
+	garden.Pricing = &pkg.WaterPricing{FlowRate: 2}
+	zone := createExampleZone()
+
+	err = storageClient.Gardens.Set(context.Background(), garden)
+	assert.NoError(t, err)
+	err = storageClient.Zones.Set(context.Background(), zone)
+	assert.NoError(t, err)
+
+	for _, h := range []pkg.WaterHistory{
+		{Duration: &pkg.Duration{Duration: time.Minute}, RecordTime: now.Add(-2 * time.Hour)},
+		{Duration: &pkg.Duration{Duration: 30 * time.Second}, RecordTime: now.Add(-1 * time.Hour)},
+	} {
+		err = storageClient.WaterHistory.AddWaterHistory(context.Background(), zone.GetID(), h)
+		assert.NoError(t, err)
+	}
+	err = storageClient.WaterHistory.SetMeasuredLiters(context.Background(), zone.GetID(), 0.5)
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/gardens/%s/zones/%s/history", garden.ID, zone.ID), http.NoBody)
+	rr := babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, zr.API, garden, "Gardens", "/gardens", r)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t,
+		`{"history":[{"duration":"30s","record_time":"2023-06-01T07:00:00Z","measured_liters":0.5,"expected_liters":1},{"duration":"1m0s","record_time":"2023-06-01T06:00:00Z","expected_liters":2}],"count":2,"average":"45s","total":"1m30s","measured_liters":0.5,"expected_liters":1}`,
+		strings.TrimSpace(rr.Body.String()),
+	)
+}
+
+func TestMoistureHistory(t *testing.T) {
+	recordTime, _ := time.Parse(time.RFC3339Nano, "2021-10-03T11:00:00-07:00")
+	tests := []struct {
+		name        string
+		setupMock   func(*influxdb.MockClient)
+		queryParams string
+		expected    string
+		status      int
+	}{
+		{
+			"BadRequestInvalidResolution",
+			func(*influxdb.MockClient) {},
+			"?resolution=notTime",
+			`{"status":"Invalid request.","error":"time: invalid duration \"notTime\""}`,
+			http.StatusBadRequest,
+		},
+		{
+			"BadRequestNegativeResolution",
+			func(*influxdb.MockClient) {},
+			"?resolution=-1h",
+			`{"status":"Invalid request.","error":"resolution must be a positive duration"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"BadRequestResolutionLongerThanRange",
+			func(*influxdb.MockClient) {},
+			"?range=1h&resolution=2h",
+			`{"status":"Invalid request.","error":"resolution must not be longer than the range"}`,
+			http.StatusBadRequest,
+		},
+		{
+			"SuccessfulEmpty",
+			func(influxdbClient *influxdb.MockClient) {
+				influxdbClient.On("GetMoistureHistory", mock.Anything, uint(0), "test-garden", 72*time.Hour, time.Hour).Return([]map[string]interface{}{}, nil)
+				influxdbClient.On("Close")
+			},
+			"",
+			`{"history":null,"count":0,"range":"72h0m0s","resolution":"1h0m0s"}`,
+			http.StatusOK,
+		},
+		{
+			"Successful",
+			func(influxdbClient *influxdb.MockClient) {
+				influxdbClient.On("GetMoistureHistory", mock.Anything, uint(0), "test-garden", 24*time.Hour, 6*time.Hour).
+					Return([]map[string]interface{}{
+						{"RecordTime": recordTime, "Value": 40.0},
+						{"RecordTime": recordTime.Add(6 * time.Hour), "Value": 30.0},
+					}, nil)
+				influxdbClient.On("Close")
+			},
+			"?range=24h&resolution=6h",
+			`{"history":[{"record_time":"2021-10-03T11:00:00-07:00","percent":40},{"record_time":"2021-10-03T17:00:00-07:00","percent":30}],"count":2,"range":"24h0m0s","resolution":"6h0m0s","average":35,"min":30,"max":40}`,
+			http.StatusOK,
+		},
+		{
+			"InfluxDBClientError",
+			func(influxdbClient *influxdb.MockClient) {
+				influxdbClient.On("GetMoistureHistory", mock.Anything, uint(0), "test-garden", 72*time.Hour, time.Hour).
+					Return([]map[string]interface{}{}, errors.New("influxdb error"))
+				influxdbClient.On("Close")
+			},
+			"",
+			`{"status":"Server Error.","error":"influxdb error"}`,
+			http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			influxdbClient := new(influxdb.MockClient)
+			tt.setupMock(influxdbClient)
+
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			assert.NoError(t, err)
+
+			zr := NewZonesAPI()
+			zr.setup(storageClient, influxdbClient, worker.NewWorker(storageClient, influxdbClient, nil, slog.Default()))
+
+			garden := createExampleGarden()
+			zone := createExampleZone()
+
+			err = storageClient.Gardens.Set(context.Background(), garden)
+			assert.NoError(t, err)
+			err = storageClient.Zones.Set(context.Background(), zone)
+			assert.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/gardens/%s/zones/%s/moisture%s", garden.ID, zone.ID, tt.queryParams), http.NoBody)
+			w := babytest.TestWithParentRoute[*pkg.Zone, *pkg.Garden](t, zr.API, garden, "Gardens", "/gardens", r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.expected, strings.TrimSpace(w.Body.String()))
+
+			influxdbClient.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetNextWaterTime(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/babyapi"
+)
+
+// recordWaterAction sets the status of the WaterAction's ActionRecord, creating it if this is the first status.
+// Errors are only logged since the ActionRecord is not needed to execute the action
+func (w *Worker) recordWaterAction(g *pkg.Garden, z *pkg.Zone, input *action.WaterAction, status pkg.ActionStatus, message string) {
+	if w.storageClient == nil || w.storageClient.ActionRecords == nil || input.ID == "" {
+		return
+	}
+
+	w.actionRecordsMtx.Lock()
+	defer w.actionRecordsMtx.Unlock()
+
+	now := w.now()
+	record, err := w.storageClient.ActionRecords.GetActionRecord(context.Background(), input.ID)
+	switch {
+	case errors.Is(err, babyapi.ErrNotFound):
+		record = &pkg.ActionRecord{
+			ID:        input.ID,
+			Type:      "water",
+			GardenID:  g.GetID(),
+			ZoneID:    z.GetID(),
+			Duration:  input.Duration,
+			CreatedAt: now,
+		}
+	case err != nil:
+		w.logger.Error("unable to get action record", "action_id", input.ID, "error", err)
+		return
+	}
+
+	w.saveActionStatus(record, status, message, now)
+}
+
+// updateActionRecord sets the status of an existing ActionRecord. Nothing is changed if it doesn't exist. An
+// acknowledgment is only recorded for published actions since it can arrive after the controller already
+// published that it completed
+func (w *Worker) updateActionRecord(id string, status pkg.ActionStatus, message string) {
+	if w.storageClient == nil || w.storageClient.ActionRecords == nil || id == "" {
+		return
+	}
+
+	w.actionRecordsMtx.Lock()
+	defer w.actionRecordsMtx.Unlock()
+
+	record, err := w.storageClient.ActionRecords.GetActionRecord(context.Background(), id)
+	if err != nil {
+		if !errors.Is(err, babyapi.ErrNotFound) {
+			w.logger.Error("unable to get action record", "action_id", id, "error", err)
+		}
+		return
+	}
+	if status == pkg.ActionStatusAcknowledged && record.Status != pkg.ActionStatusPublished {
+		return
+	}
+
+	w.saveActionStatus(record, status, message, w.now())
+}
+
+// saveActionStatus sets the status and stores the ActionRecord. This must be called while holding actionRecordsMtx
+func (w *Worker) saveActionStatus(record *pkg.ActionRecord, status pkg.ActionStatus, message string, now time.Time) {
+	record.SetStatus(status, message, now)
+
+	err := w.storageClient.ActionRecords.SetActionRecord(context.Background(), *record)
+	if err != nil {
+		w.logger.Error("unable to store action record", "action_id", record.ID, "status", status, "error", err)
+	}
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// actionStatuses returns the statuses from the ActionRecord's History
+func actionStatuses(t *testing.T, storageClient *storage.Client, id string) []pkg.ActionStatus {
+	t.Helper()
+
+	record, err := storageClient.ActionRecords.GetActionRecord(context.Background(), id)
+	require.NoError(t, err)
+
+	result := []pkg.ActionStatus{}
+	for _, h := range record.History {
+		result = append(result, h.Status)
+	}
+	return result
+}
+
+func TestActionRecords(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	one := uint(1)
+	garden := createExampleGarden()
+	garden.MaxConcurrentZones = &one
+
+	zone1 := createExampleZone()
+	zone2 := createExampleZone()
+	zone2.ID = babyapi.ID{ID: xid.New()}
+	position := uint(1)
+	zone2.Position = &position
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+	mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil)
+
+	w := NewWorker(storageClient, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)))
+
+	t.Run("QueuedPublishedCompleted", func(t *testing.T) {
+		first := &action.WaterAction{ID: "first", Duration: &pkg.Duration{Duration: time.Minute}}
+		second := &action.WaterAction{ID: "second", Duration: &pkg.Duration{Duration: time.Minute}}
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone1, first))
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone2, second))
+		assert.Equal(t, []pkg.ActionStatus{pkg.ActionStatusPublished}, actionStatuses(t, storageClient, "first"))
+		assert.Equal(t, []pkg.ActionStatus{pkg.ActionStatusQueued}, actionStatuses(t, storageClient, "second"))
+
+		w.CompleteWaterAction(garden, zone1)
+		assert.Equal(t, []pkg.ActionStatus{pkg.ActionStatusPublished, pkg.ActionStatusCompleted}, actionStatuses(t, storageClient, "first"))
+		assert.Equal(t, []pkg.ActionStatus{pkg.ActionStatusQueued, pkg.ActionStatusPublished}, actionStatuses(t, storageClient, "second"))
+
+		// an acknowledgment after completing does not change the status
+		w.updateActionRecord("first", pkg.ActionStatusAcknowledged, "")
+		assert.Equal(t, []pkg.ActionStatus{pkg.ActionStatusPublished, pkg.ActionStatusCompleted}, actionStatuses(t, storageClient, "first"))
+
+		w.CompleteWaterAction(garden, zone2)
+		records, err := storageClient.ActionRecords.GetZoneActionRecords(context.Background(), zone2.GetID(), 0)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, pkg.ActionStatusCompleted, records[0].Status)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		first := &action.WaterAction{ID: "third", Duration: &pkg.Duration{Duration: time.Minute}}
+		second := &action.WaterAction{ID: "fourth", Duration: &pkg.Duration{Duration: time.Minute}}
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone1, first))
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone2, second))
+		require.NoError(t, w.ExecuteZoneStopAction(garden, zone2))
+
+		record, err := storageClient.ActionRecords.GetActionRecord(context.Background(), "fourth")
+		require.NoError(t, err)
+		assert.Equal(t, pkg.ActionStatusCancelled, record.Status)
+		assert.Equal(t, "cancelled by StopAction", record.Message)
+	})
+
+	t.Run("Skipped", func(t *testing.T) {
+		skipped := &action.WaterAction{ID: "fifth", Duration: &pkg.Duration{}}
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone1, skipped))
+
+		record, err := storageClient.ActionRecords.GetActionRecord(context.Background(), "fifth")
+		require.NoError(t, err)
+		assert.Equal(t, pkg.ActionStatusSkipped, record.Status)
+		assert.Equal(t, "duration is zero", record.Message)
+	})
+}
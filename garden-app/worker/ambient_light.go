@@ -0,0 +1,149 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
+)
+
+const (
+	// ambientLightTag is used for the one-time Jobs that check the light level again after turning the light on was
+	// delayed. These Jobs are also tagged with the Garden's ID so they are removed when the LightSchedule changes
+	ambientLightTag = "AMBIENT_LIGHT"
+
+	// lightLevelMaxAge is how long a light level published by the controller is used before the metrics backend is
+	// queried instead
+	lightLevelMaxAge = 15 * time.Minute
+)
+
+// lightLevel is an ambient light level, in lux, published by a controller
+type lightLevel struct {
+	lux       float64
+	updatedAt time.Time
+}
+
+// RecordLightLevel records the ambient light level published by the controller with the topic prefix
+func (w *Worker) RecordLightLevel(topicPrefix string, lux float64) {
+	w.lightLevelsMtx.Lock()
+	defer w.lightLevelsMtx.Unlock()
+
+	w.lightLevels[topicPrefix] = lightLevel{lux: lux, updatedAt: w.now()}
+}
+
+// ambientLightLevel returns the Garden's light level published by the controller in the last lightLevelMaxAge. If
+// there is no recent reading, the average from the metrics backend is used
+func (w *Worker) ambientLightLevel(g *pkg.Garden) (float64, error) {
+	w.lightLevelsMtx.Lock()
+	level, ok := w.lightLevels[g.TopicPrefix]
+	w.lightLevelsMtx.Unlock()
+
+	if ok && w.now().Sub(level.updatedAt) <= lightLevelMaxAge {
+		return level.lux, nil
+	}
+
+	if w.influxdbClient == nil {
+		return 0, errors.New("no recent light level")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), influxdb.QueryTimeout)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "metrics.GetLightLevel")
+	lux, err := w.influxdbClient.GetLightLevel(ctx, g.TopicPrefix)
+	tracing.End(span, err)
+	if err != nil {
+		return 0, fmt.Errorf("error getting light level: %w", err)
+	}
+	return lux, nil
+}
+
+// delayLightOn returns true if the LightSchedule's AmbientLightControl measures enough light to keep the light off.
+// A one-time Job is scheduled to check again after the interval unless the light is scheduled to turn off first.
+// The light is turned on when the light level is not available
+func (w *Worker) delayLightOn(g *pkg.Garden, logger *slog.Logger) bool {
+	if !g.LightSchedule.HasAmbientLightControl() {
+		return false
+	}
+	ambientLight := g.LightSchedule.AmbientLight
+
+	lux, err := w.ambientLightLevel(g)
+	if err != nil {
+		logger.Warn("unable to get ambient light level, turning light on", "error", err)
+		return false
+	}
+
+	logger = logger.With("lux", lux, "lux_threshold", ambientLight.LuxThreshold)
+	if lux < ambientLight.LuxThreshold {
+		logger.Debug("ambient light level is below threshold")
+		return false
+	}
+
+	details := map[string]string{
+		"state":         pkg.LightStateOn.String(),
+		"lux":           fmt.Sprintf("%.1f", lux),
+		"lux_threshold": fmt.Sprintf("%.1f", ambientLight.LuxThreshold),
+	}
+
+	checkAt := w.now().Add(ambientLight.Interval())
+	nextOffTime, err := w.nextLightOffTime(g)
+	if err != nil {
+		logger.Error("unable to get next light-off time", "error", err)
+		schedulerErrors.WithLabelValues(gardenLabels(g)...).Inc()
+	}
+	if err != nil || !checkAt.Before(nextOffTime) {
+		logger.Info("ambient light level is above threshold, not turning light on before it is scheduled to turn off")
+		w.addScheduledAuditEntry("garden", g.GetID(), "light_action_skipped", details)
+		return true
+	}
+
+	logger.Info("ambient light level is above threshold, delaying turning light on", "check_at", checkAt)
+	err = w.scheduleAmbientLightCheck(g, checkAt)
+	if err != nil {
+		logger.Error("error scheduling ambient light check, turning light on", "error", err)
+		schedulerErrors.WithLabelValues(gardenLabels(g)...).Inc()
+		return false
+	}
+
+	details["check_at"] = checkAt.String()
+	w.addScheduledAuditEntry("garden", g.GetID(), "light_action_delayed", details)
+	return true
+}
+
+// nextLightOffTime calculates the next time the LightSchedule turns the light off
+func (w *Worker) nextLightOffTime(g *pkg.Garden) (time.Time, error) {
+	tz, err := g.TimeLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return g.LightSchedule.NextTime(pkg.LightStateOff, g.Location, tz, w.now())
+}
+
+// scheduleAmbientLightCheck schedules a one-time Job that runs the scheduled ON LightAction again, which checks the
+// light level before turning the light on
+func (w *Worker) scheduleAmbientLightCheck(g *pkg.Garden, checkAt time.Time) error {
+	logger := w.contextLogger(g, nil, nil)
+
+	scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Inc()
+	_, err := w.scheduleOnce(checkAt).
+		Tag("garden").
+		Tag(g.ID.String()).
+		Tag(ambientLightTag).
+		Do(w.executeAmbientLightCheck, g, logger.With("source", "ambient_light_job"))
+	if err != nil {
+		scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Dec()
+		return err
+	}
+	return nil
+}
+
+func (w *Worker) executeAmbientLightCheck(g *pkg.Garden, logger *slog.Logger) {
+	scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Dec()
+	w.executeLightActionInScheduledJob(g, &action.LightAction{State: pkg.LightStateOn}, logger)
+}
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmbientLightControl(t *testing.T) {
+	// The example Garden's light turns on at 05:00:01 UTC and off at 20:00:01 UTC
+	now := time.Date(2023, time.January, 1, 4, 0, 0, 0, time.UTC)
+	onMessage := []byte(`{"state":"ON","for_duration":null}`)
+
+	setup := func(t *testing.T, influxdbClient metrics.Client) (*Worker, *mqtt.MockClient, *pkg.Garden) {
+		storageClient, err := storage.NewClient(storage.Config{
+			Driver: "hashmap",
+		})
+		require.NoError(t, err)
+
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("LightTopic", "test-garden").Return("test-garden/action/light", nil)
+		mqttClient.On("Publish", "test-garden/action/light", onMessage).Return(nil)
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := NewWorker(storageClient, influxdbClient, mqttClient, slog.Default())
+		w.SetClock(clock.NewVirtual(now))
+		w.StartAsync()
+		t.Cleanup(w.Stop)
+
+		g := createExampleGarden()
+		g.LightSchedule.AmbientLight = &pkg.AmbientLightControl{
+			LuxThreshold:  1000,
+			CheckInterval: &pkg.Duration{Duration: 10 * time.Minute},
+		}
+		require.NoError(t, w.ScheduleLightActions(g))
+
+		return w, mqttClient, g
+	}
+
+	t.Run("DelayedUntilBelowThreshold", func(t *testing.T) {
+		w, mqttClient, g := setup(t, nil)
+
+		_, err := w.AdvanceClock(59 * time.Minute)
+		require.NoError(t, err)
+		w.RecordLightLevel(g.TopicPrefix, 5000)
+
+		_, err = w.AdvanceClock(2 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNotCalled(t, "Publish", "test-garden/action/light", onMessage)
+
+		// Still bright at the first check
+		_, err = w.AdvanceClock(10 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNotCalled(t, "Publish", "test-garden/action/light", onMessage)
+
+		w.RecordLightLevel(g.TopicPrefix, 200)
+		_, err = w.AdvanceClock(10 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+
+		// The check Job does not repeat after turning the light on
+		_, err = w.AdvanceClock(time.Hour)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+	})
+
+	t.Run("LightLevelFromMetrics", func(t *testing.T) {
+		influxdbClient := new(influxdb.MockClient)
+		influxdbClient.On("GetLightLevel", mock.Anything, "test-garden").Return(5000.0, nil).Once()
+		influxdbClient.On("GetLightLevel", mock.Anything, "test-garden").Return(0.0, nil)
+		influxdbClient.On("Close").Return()
+
+		w, mqttClient, _ := setup(t, influxdbClient)
+
+		_, err := w.AdvanceClock(61 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNotCalled(t, "Publish", "test-garden/action/light", onMessage)
+
+		_, err = w.AdvanceClock(10 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+		influxdbClient.AssertNumberOfCalls(t, "GetLightLevel", 2)
+	})
+
+	t.Run("TurnedOnWhenLightLevelIsUnavailable", func(t *testing.T) {
+		influxdbClient := new(influxdb.MockClient)
+		influxdbClient.On("GetLightLevel", mock.Anything, "test-garden").Return(0.0, errors.New("influxdb error"))
+		influxdbClient.On("Close").Return()
+
+		w, mqttClient, _ := setup(t, influxdbClient)
+
+		_, err := w.AdvanceClock(61 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+	})
+
+	t.Run("NotTurnedOnBeforeOffTime", func(t *testing.T) {
+		w, mqttClient, g := setup(t, nil)
+		g.LightSchedule.AmbientLight.CheckInterval = &pkg.Duration{Duration: 16 * time.Hour}
+
+		_, err := w.AdvanceClock(59 * time.Minute)
+		require.NoError(t, err)
+		w.RecordLightLevel(g.TopicPrefix, 5000)
+
+		_, err = w.AdvanceClock(2 * time.Minute)
+		require.NoError(t, err)
+
+		_, err = w.scheduler.FindJobsByTag(g.GetID(), ambientLightTag)
+		assert.Error(t, err)
+		mqttClient.AssertNotCalled(t, "Publish", "test-garden/action/light", onMessage)
+	})
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+)
+
+// addScheduledAuditEntry records an action executed by a scheduled job in the audit log. Errors are only logged
+// since the action was already executed
+func (w *Worker) addScheduledAuditEntry(resourceType, resourceID, actionName string, details map[string]string) {
+	if w.storageClient == nil || w.storageClient.AuditLog == nil {
+		return
+	}
+
+	err := w.storageClient.AuditLog.AddAuditEntry(context.Background(), pkg.AuditEntry{
+		Timestamp:    w.now(),
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Action:       actionName,
+		Source:       pkg.AuditSourceScheduler,
+		Details:      details,
+	})
+	if err != nil {
+		w.logger.Error("unable to store audit entry", "resource_id", resourceID, "action", actionName, "error", err)
+	}
+}
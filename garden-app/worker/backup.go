@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/backup"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+)
+
+const backupTag = "backup"
+
+// ErrBackupsNotConfigured is returned when using backups without a backup Store
+var ErrBackupsNotConfigured = errors.New("backups are not configured")
+
+// SetBackups configures the Store that backups are saved to and how many are kept. This must be used before
+// scheduling backups
+func (w *Worker) SetBackups(cfg backup.Config, store backup.Store) {
+	w.settingsMtx.Lock()
+	defer w.settingsMtx.Unlock()
+	w.backupConfig = cfg
+	w.backupStore = store
+}
+
+func (w *Worker) getBackups() (backup.Config, backup.Store) {
+	w.settingsMtx.RLock()
+	defer w.settingsMtx.RUnlock()
+	return w.backupConfig, w.backupStore
+}
+
+// ScheduleBackups schedules a Job that backs up all storage at the configured interval, starting now. Like health
+// checks, this is skipped when using a virtual clock
+func (w *Worker) ScheduleBackups() error {
+	cfg, store := w.getBackups()
+	if store == nil {
+		return nil
+	}
+
+	logger := w.logger.With("source", "scheduled_job")
+	if w.clock.IsVirtual() {
+		logger.Info("skipping backups in simulation mode")
+		return nil
+	}
+	logger.Info("creating scheduled Job for backups", "interval", cfg.IntervalOrDefault().String())
+
+	_, err := w.scheduler.
+		Every(cfg.IntervalOrDefault()).
+		Tag(backupTag).
+		Do(w.executeScheduledBackup, logger)
+	return err
+}
+
+func (w *Worker) executeScheduledBackup(logger *slog.Logger) {
+	info, err := w.CreateBackup(context.Background())
+	if err != nil {
+		logger.Error("error creating backup", "error", err)
+		schedulerErrors.WithLabelValues(backupTag, "").Inc()
+		return
+	}
+	logger.Info("created backup", "key", info.Key)
+}
+
+// CreateBackup saves a Backup of all storage and then removes the backups that are past the retention. Errors removing
+// old backups are only logged since the new backup was saved
+func (w *Worker) CreateBackup(ctx context.Context) (backup.Info, error) {
+	cfg, store := w.getBackups()
+	if store == nil {
+		return backup.Info{}, ErrBackupsNotConfigured
+	}
+
+	b, err := w.storageClient.Backup(ctx)
+	if err != nil {
+		return backup.Info{}, fmt.Errorf("error reading storage: %w", err)
+	}
+	b.CreatedAt = w.now()
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return backup.Info{}, fmt.Errorf("error encoding backup: %w", err)
+	}
+
+	info := backup.Info{Key: backup.Key(b.CreatedAt), CreatedAt: b.CreatedAt}
+	err = store.Put(ctx, info.Key, bytes.NewReader(data), int64(len(data)), "application/json")
+	if err != nil {
+		return backup.Info{}, fmt.Errorf("error saving backup: %w", err)
+	}
+
+	w.removeExpiredBackups(ctx, cfg, store)
+	return info, nil
+}
+
+// removeExpiredBackups deletes the backups that are past the KeepLast most recent or older than MaxAge
+func (w *Worker) removeExpiredBackups(ctx context.Context, cfg backup.Config, store backup.Store) {
+	backups, err := backup.List(ctx, store)
+	if err != nil {
+		w.logger.Error("unable to list backups to remove expired ones", "error", err)
+		return
+	}
+
+	for _, b := range cfg.Expired(backups, w.now()) {
+		w.logger.Info("removing expired backup", "key", b.Key)
+		err = store.Delete(ctx, b.Key)
+		if err != nil {
+			w.logger.Error("unable to remove expired backup", "key", b.Key, "error", err)
+		}
+	}
+}
+
+// ListBackups returns the saved backups, starting with the most recent
+func (w *Worker) ListBackups(ctx context.Context) ([]backup.Info, error) {
+	_, store := w.getBackups()
+	if store == nil {
+		return nil, ErrBackupsNotConfigured
+	}
+	return backup.List(ctx, store)
+}
+
+// GetBackup reads a saved backup
+func (w *Worker) GetBackup(ctx context.Context, key string) (*storage.Backup, error) {
+	_, store := w.getBackups()
+	if store == nil {
+		return nil, ErrBackupsNotConfigured
+	}
+	if _, ok := backup.ParseKey(key); !ok {
+		return nil, fmt.Errorf("invalid backup key %q", key)
+	}
+
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var b storage.Backup
+	err = json.NewDecoder(body).Decode(&b)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding backup: %w", err)
+	}
+	return &b, nil
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/backup"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBackup(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	g := createExampleGarden()
+	require.NoError(t, storageClient.Gardens.Set(ctx, g))
+
+	w := NewWorker(storageClient, nil, nil, slog.Default())
+	w.SetClock(clock.NewVirtual(now))
+
+	t.Run("ErrorNotConfigured", func(t *testing.T) {
+		_, err := w.CreateBackup(ctx)
+		assert.ErrorIs(t, err, ErrBackupsNotConfigured)
+	})
+
+	cfg := backup.Config{
+		Driver:   "disk",
+		Options:  map[string]interface{}{"directory": t.TempDir()},
+		KeepLast: 2,
+	}
+	store, err := backup.NewStore(cfg)
+	require.NoError(t, err)
+	w.SetBackups(cfg, store)
+
+	keys := []string{}
+	for i := 0; i < 3; i++ {
+		info, err := w.CreateBackup(ctx)
+		require.NoError(t, err)
+		assert.True(t, w.now().Equal(info.CreatedAt))
+		keys = append(keys, info.Key)
+
+		_, err = w.AdvanceClock(time.Hour)
+		require.NoError(t, err)
+	}
+
+	t.Run("OldestBackupRemoved", func(t *testing.T) {
+		backups, err := w.ListBackups(ctx)
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		assert.Equal(t, keys[2], backups[0].Key)
+		assert.Equal(t, keys[1], backups[1].Key)
+	})
+
+	t.Run("GetBackup", func(t *testing.T) {
+		b, err := w.GetBackup(ctx, keys[2])
+		require.NoError(t, err)
+		require.Len(t, b.Gardens, 1)
+		assert.Equal(t, g.GetID(), b.Gardens[0].GetID())
+	})
+
+	t.Run("GetExpiredBackup", func(t *testing.T) {
+		_, err := w.GetBackup(ctx, keys[0])
+		assert.ErrorIs(t, err, backup.ErrNotFound)
+	})
+
+	t.Run("ErrorInvalidKey", func(t *testing.T) {
+		_, err := w.GetBackup(ctx, "../config.yaml")
+		assert.EqualError(t, err, `invalid backup key "../config.yaml"`)
+	})
+}
@@ -0,0 +1,227 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/go-co-op/gocron"
+)
+
+const deferredTag = "DEFERRED"
+
+// DeferredWatering is a scheduled watering that was deferred because it was during a BlackoutWindow
+type DeferredWatering struct {
+	WaterScheduleID string    `json:"water_schedule_id"`
+	ScheduledTime   time.Time `json:"scheduled_time"`
+	DeferredUntil   time.Time `json:"deferred_until"`
+
+	gardenID string
+	zoneID   string
+}
+
+// SetBlackoutWindows configures BlackoutWindows that are used for every Garden in addition to their own
+func (w *Worker) SetBlackoutWindows(windows []pkg.BlackoutWindow) {
+	w.settingsMtx.Lock()
+	defer w.settingsMtx.Unlock()
+	w.blackoutWindows = windows
+}
+
+// BlackoutEnd returns the time that watering can resume if t is in one of the global or Garden's BlackoutWindows
+func (w *Worker) BlackoutEnd(g *pkg.Garden, t time.Time) (time.Time, bool) {
+	w.settingsMtx.RLock()
+	windows := w.blackoutWindows
+	w.settingsMtx.RUnlock()
+
+	return g.BlackoutEnd(windows, t)
+}
+
+// GetDeferredWaterings returns the Zone's scheduled waterings that are waiting for a BlackoutWindow to end
+func (w *Worker) GetDeferredWaterings(z *pkg.Zone) []DeferredWatering {
+	w.deferredWateringsMtx.Lock()
+	defer w.deferredWateringsMtx.Unlock()
+
+	now := w.now()
+	result := []DeferredWatering{}
+	for _, dw := range w.deferredWaterings[z.GetID()] {
+		// the Job might have been removed before running if the Zone was deleted
+		if dw.DeferredUntil.Before(now) {
+			continue
+		}
+		result = append(result, dw)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DeferredUntil.Before(result[j].DeferredUntil)
+	})
+	return result
+}
+
+// deferWaterAction schedules a one-time Job to execute the scheduled WaterAction after the BlackoutWindow ends.
+// Skipping and weather scaling are decided when the Job runs so the latest data is used
+func (w *Worker) deferWaterAction(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule, until time.Time) error {
+	logger := w.contextLogger(g, z, ws)
+	logger.Info("deferring scheduled watering until the end of a blackout window", "deferred_until", until)
+
+	err := w.scheduleDeferredWatering(g, z, ws, w.now(), until)
+	if err != nil {
+		return err
+	}
+
+	w.addScheduledAuditEntry("zone", z.GetID(), "water_action_deferred", map[string]string{
+		"deferred_until":    until.String(),
+		"water_schedule_id": ws.GetID(),
+	})
+	return nil
+}
+
+// scheduleDeferredWatering schedules the deferred Job and saves it as a PendingJob so it is restored after a restart
+func (w *Worker) scheduleDeferredWatering(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule, scheduledTime, until time.Time) error {
+	logger := w.contextLogger(g, z, ws)
+
+	w.deferredWateringsMtx.Lock()
+	if w.deferredWaterings[z.GetID()] == nil {
+		w.deferredWaterings[z.GetID()] = map[string]DeferredWatering{}
+	}
+	w.deferredWaterings[z.GetID()][ws.GetID()] = DeferredWatering{
+		WaterScheduleID: ws.GetID(),
+		ScheduledTime:   scheduledTime,
+		DeferredUntil:   until,
+		gardenID:        g.GetID(),
+		zoneID:          z.GetID(),
+	}
+	w.deferredWateringsMtx.Unlock()
+
+	// The Job is not tagged with the WaterSchedule's ID so it is not removed when the WaterSchedule is rescheduled.
+	// An existing deferred Job is removed so a WaterSchedule is only deferred once for each Zone
+	wsTag := deferredJobTag(ws.GetID())
+	err := w.scheduler.RemoveByTags(z.GetID(), wsTag)
+	if err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+		return err
+	}
+
+	scheduleJobsGauge.WithLabelValues(zoneLabels(z)...).Inc()
+	_, err = w.scheduleOnce(until).
+		Tag("zone").
+		Tag(z.GetID()).
+		Tag(deferredTag).
+		Tag(wsTag).
+		Do(func(jobLogger *slog.Logger) {
+			scheduleJobsGauge.WithLabelValues(zoneLabels(z)...).Dec()
+			w.removeDeferredWatering(z, ws)
+
+			err := w.executeDeferredWaterAction(g, z, ws)
+			if err != nil {
+				jobLogger.Error("error executing deferred water action", "error", err)
+				schedulerErrors.WithLabelValues(zoneLabels(z)...).Inc()
+				w.sendNotification(fmt.Sprintf("%s: Water Action Error", ws.Name), err.Error(), jobLogger)
+			}
+		}, logger.With("source", "deferred_job"))
+	if err != nil {
+		w.removeDeferredWatering(z, ws)
+		return fmt.Errorf("error scheduling deferred water action: %w", err)
+	}
+
+	w.savePendingJob(pkg.PendingJob{
+		ID:              deferredPendingJobID(z.GetID(), ws.GetID()),
+		Type:            pkg.PendingJobDeferredWatering,
+		GardenID:        g.GetID(),
+		ZoneID:          z.GetID(),
+		WaterScheduleID: ws.GetID(),
+		ScheduledTime:   scheduledTime,
+		RunAt:           until,
+	})
+	return nil
+}
+
+// executeDeferredWaterAction gets the latest Garden, Zone, and WaterSchedule from storage before executing the
+// scheduled WaterAction since they might have changed during the BlackoutWindow
+func (w *Worker) executeDeferredWaterAction(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) error {
+	ctx := context.Background()
+
+	garden, err := w.storageClient.Gardens.Get(ctx, g.GetID())
+	if err != nil {
+		return fmt.Errorf("error getting Garden for deferred Job: %w", err)
+	}
+	zone, err := w.storageClient.Zones.Get(ctx, z.GetID())
+	if err != nil {
+		return fmt.Errorf("error getting Zone for deferred Job: %w", err)
+	}
+	waterSchedule, err := w.storageClient.WaterSchedules.Get(ctx, ws.GetID())
+	if err != nil {
+		return fmt.Errorf("error getting WaterSchedule for deferred Job: %w", err)
+	}
+	if garden == nil || zone == nil || waterSchedule == nil {
+		return errors.New("resource not found for deferred Job")
+	}
+
+	if garden.EndDated() || zone.EndDated() || waterSchedule.EndDatedAt(w.now()) {
+		w.logger.Info("skipping deferred watering because a resource is end-dated", "zone_id", zone.GetID())
+		return nil
+	}
+	if waterSchedule.Paused {
+		w.logger.Info("skipping deferred watering because the WaterSchedule is paused", "zone_id", zone.GetID())
+		return nil
+	}
+
+	return w.ExecuteScheduledWaterAction(garden, zone, waterSchedule)
+}
+
+// cancelDeferredWaterings removes the deferred Jobs that match and returns the number that were cancelled
+func (w *Worker) cancelDeferredWaterings(match func(DeferredWatering) bool) int {
+	w.deferredWateringsMtx.Lock()
+	defer w.deferredWateringsMtx.Unlock()
+
+	cancelled := 0
+	for zoneID, waterings := range w.deferredWaterings {
+		for wsID, dw := range waterings {
+			if !match(dw) {
+				continue
+			}
+
+			err := w.scheduler.RemoveByTags(zoneID, deferredJobTag(wsID))
+			switch {
+			case err == nil:
+				scheduleJobsGauge.WithLabelValues("zone", zoneID).Dec()
+			case !errors.Is(err, gocron.ErrJobNotFoundWithTag):
+				w.logger.Error("unable to remove deferred Job", "zone_id", zoneID, "water_schedule_id", wsID, "error", err)
+				continue
+			}
+
+			delete(waterings, wsID)
+			w.deletePendingJob(deferredPendingJobID(zoneID, wsID))
+			cancelled++
+		}
+		if len(waterings) == 0 {
+			delete(w.deferredWaterings, zoneID)
+		}
+	}
+	return cancelled
+}
+
+// deferredJobTag is used to find a WaterSchedule's deferred Job without tagging it with the WaterSchedule's ID
+func deferredJobTag(waterScheduleID string) string {
+	return fmt.Sprintf("%s_%s", deferredTag, waterScheduleID)
+}
+
+// deferredPendingJobID is unique for each Zone and WaterSchedule since a WaterSchedule is only deferred once for each
+// Zone
+func deferredPendingJobID(zoneID, waterScheduleID string) string {
+	return fmt.Sprintf("%s_%s_%s", deferredTag, zoneID, waterScheduleID)
+}
+
+// removeDeferredWatering is used when the deferred Job runs or can't be scheduled, so its PendingJob is also removed
+func (w *Worker) removeDeferredWatering(z *pkg.Zone, ws *pkg.WaterSchedule) {
+	w.deletePendingJob(deferredPendingJobID(z.GetID(), ws.GetID()))
+
+	w.deferredWateringsMtx.Lock()
+	defer w.deferredWateringsMtx.Unlock()
+
+	delete(w.deferredWaterings[z.GetID()], ws.GetID())
+	if len(w.deferredWaterings[z.GetID()]) == 0 {
+		delete(w.deferredWaterings, z.GetID())
+	}
+}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteScheduledWaterActionDeferredDuringBlackout(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	garden := createExampleGarden()
+	garden.TimeZone = "UTC"
+	garden.BlackoutWindows = []pkg.BlackoutWindow{{StartTime: "10:00", EndTime: "16:00"}}
+	zone := createExampleZone()
+	ws := createExampleWaterSchedule()
+
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+	require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+	require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", mock.Anything).Return("test-garden/action/water", nil)
+	mqttClient.On("Publish", "test-garden/action/water", []byte(`{"duration":1000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+	mqttClient.On("Disconnect", uint(100)).Return()
+
+	now := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+	w := startTestWorker(t, storageClient, mqttClient, now)
+
+	err = w.ExecuteScheduledWaterAction(garden, zone, ws)
+	require.NoError(t, err)
+	mqttClient.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+
+	deferred := w.GetDeferredWaterings(zone)
+	require.Len(t, deferred, 1)
+	assert.Equal(t, ws.GetID(), deferred[0].WaterScheduleID)
+	assert.True(t, now.Equal(deferred[0].ScheduledTime))
+	assert.True(t, time.Date(2023, time.June, 1, 16, 0, 0, 0, time.UTC).Equal(deferred[0].DeferredUntil))
+
+	t.Run("WatersAfterBlackout", func(t *testing.T) {
+		_, err := w.AdvanceClock(5 * time.Hour)
+		require.NoError(t, err)
+
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+		assert.Empty(t, w.GetDeferredWaterings(zone))
+	})
+}
+
+func TestStopAllCancelsDeferredWaterings(t *testing.T) {
+	garden := createExampleGarden()
+	garden.TimeZone = "UTC"
+	garden.BlackoutWindows = []pkg.BlackoutWindow{{StartTime: "10:00", EndTime: "16:00"}}
+	zone := createExampleZone()
+	ws := createExampleWaterSchedule()
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("StopAllTopic", "test-garden").Return("test-garden/command/stop_all", nil)
+	mqttClient.On("Publish", "test-garden/command/stop_all", mock.Anything).Return(nil)
+
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(clock.NewVirtual(time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)))
+
+	require.NoError(t, w.ExecuteScheduledWaterAction(garden, zone, ws))
+	require.Len(t, w.GetDeferredWaterings(zone), 1)
+
+	require.NoError(t, w.ExecuteGardenAction(garden, &action.GardenAction{StopAll: &action.StopAllAction{}}))
+	assert.Empty(t, w.GetDeferredWaterings(zone))
+
+	jobs, err := w.scheduler.FindJobsByTag(zone.GetID(), deferredTag)
+	assert.Error(t, err)
+	assert.Empty(t, jobs)
+
+	mqttClient.AssertExpectations(t)
+}
+
+func TestBlackoutEndUsesGlobalWindows(t *testing.T) {
+	w := NewWorker(nil, nil, nil, slog.Default())
+	w.SetBlackoutWindows([]pkg.BlackoutWindow{{StartTime: "10:00", EndTime: "16:00", Days: []string{"thursday"}}})
+
+	garden := createExampleGarden()
+	garden.TimeZone = "UTC"
+
+	end, ok := w.BlackoutEnd(garden, time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.True(t, time.Date(2023, time.June, 1, 16, 0, 0, 0, time.UTC).Equal(end))
+
+	_, ok = w.BlackoutEnd(garden, time.Date(2023, time.June, 2, 12, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
@@ -0,0 +1,254 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/opensprinkler"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
+	"github.com/go-co-op/gocron"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const relayOffTag = "RELAY_OFF"
+
+// controller sends actions to a Garden's controller. garden-controllers use MQTT, and other controller types have
+// an adapter for their own API
+type controller interface {
+	water(ctx context.Context, msg action.WaterMessage) error
+	stop(all bool) error
+	light(input *action.LightAction) error
+	recirculation(input *action.RecirculationAction) error
+}
+
+// controller returns the adapter for the Garden's ControllerType
+func (w *Worker) controller(g *pkg.Garden) controller {
+	if g.UsesOpenSprinkler() && g.OpenSprinkler != nil {
+		return &openSprinklerController{opensprinkler.NewClient(*g.OpenSprinkler)}
+	}
+	if g.UsesTasmota() {
+		return &tasmotaController{w, g}
+	}
+	return &mqttController{w, g}
+}
+
+// publish sends the message with MQTT. Messages that are queued until the broker is available are still sent, so
+// the error is only logged
+func (w *Worker) publish(topic string, message []byte) error {
+	err := w.mqttClient.Publish(topic, message)
+	if errors.Is(err, mqtt.ErrPublishQueued) {
+		w.logger.Warn("MQTT message is queued until the broker is available", "topic", topic, "error", err)
+		return nil
+	}
+	return err
+}
+
+// mqttController publishes actions to a garden-controller using the Garden's topics
+type mqttController struct {
+	worker *Worker
+	garden *pkg.Garden
+}
+
+// water includes the trace context in the WaterMessage so the controller can continue the trace. The error from
+// publishing is returned as-is so callers can check if it was queued with mqtt.ErrPublishQueued
+func (c *mqttController) water(ctx context.Context, msg action.WaterMessage) (err error) {
+	topic, err := c.garden.Topic(func(tt *pkg.TopicTemplates) string { return tt.Water }, c.worker.mqttClient.WaterTopic)
+	if err != nil {
+		return fmt.Errorf("unable to fill MQTT topic template: %w", err)
+	}
+
+	ctx, span := tracing.Start(ctx, "mqtt.Publish", trace.WithSpanKind(trace.SpanKindProducer), trace.WithAttributes(
+		attribute.String("messaging.system", "mqtt"),
+		attribute.String("messaging.destination.name", topic),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	msg.TraceContext = tracing.Inject(ctx)
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("unable to marshal WaterMessage to JSON: %w", err)
+	}
+
+	return c.worker.mqttClient.Publish(topic, data)
+}
+
+func (c *mqttController) stop(all bool) error {
+	override := func(tt *pkg.TopicTemplates) string { return tt.Stop }
+	topicFunc := c.worker.mqttClient.StopTopic
+	if all {
+		override = func(tt *pkg.TopicTemplates) string { return tt.StopAll }
+		topicFunc = c.worker.mqttClient.StopAllTopic
+	}
+	topic, err := c.garden.Topic(override, topicFunc)
+	if err != nil {
+		return fmt.Errorf("unable to fill MQTT topic template: %v", err)
+	}
+
+	return c.worker.publish(topic, []byte("no message"))
+}
+
+func (c *mqttController) light(input *action.LightAction) error {
+	msg, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("unable to marshal LightAction to JSON: %v", err)
+	}
+
+	topic, err := c.garden.Topic(func(tt *pkg.TopicTemplates) string { return tt.Light }, c.worker.mqttClient.LightTopic)
+	if err != nil {
+		return fmt.Errorf("unable to fill MQTT topic template: %v", err)
+	}
+
+	err = c.worker.publish(topic, msg)
+	if err != nil {
+		return fmt.Errorf("unable to publish LightAction: %v", err)
+	}
+	return nil
+}
+
+func (c *mqttController) recirculation(input *action.RecirculationAction) error {
+	msg, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("unable to marshal RecirculationAction to JSON: %v", err)
+	}
+
+	topic, err := c.garden.Topic(func(tt *pkg.TopicTemplates) string { return tt.Recirculation }, c.worker.mqttClient.RecirculationTopic)
+	if err != nil {
+		return fmt.Errorf("unable to fill MQTT topic template: %v", err)
+	}
+
+	err = c.worker.publish(topic, msg)
+	if err != nil {
+		return fmt.Errorf("unable to publish RecirculationAction: %v", err)
+	}
+	return nil
+}
+
+// openSprinklerController runs stations on an OpenSprinkler controller. Each Zone's position is its station, and
+// fertilizer, lights, and recirculation are not supported
+type openSprinklerController struct {
+	client *opensprinkler.Client
+}
+
+func (c *openSprinklerController) water(ctx context.Context, msg action.WaterMessage) error {
+	return c.client.RunStation(ctx, msg.Position, time.Duration(msg.Duration)*time.Millisecond)
+}
+
+// stop stops all stations since OpenSprinkler does not have a way to only stop the current one
+func (c *openSprinklerController) stop(bool) error {
+	return c.client.StopAll(context.Background())
+}
+
+func (c *openSprinklerController) light(*action.LightAction) error {
+	return errors.New("LightActions are not supported by opensprinkler controllers")
+}
+
+func (c *openSprinklerController) recirculation(*action.RecirculationAction) error {
+	return errors.New("RecirculationActions are not supported by opensprinkler controllers")
+}
+
+// tasmotaController turns smart relays on and off with MQTT commands. Each Zone's position is a relay, and the relay
+// is turned off by a one-time Job after the duration since the devices do not have a timer. Fertilizer, lights, and
+// recirculation are not supported
+type tasmotaController struct {
+	worker *Worker
+	garden *pkg.Garden
+}
+
+func (c *tasmotaController) water(_ context.Context, msg action.WaterMessage) error {
+	err := c.power(msg.Position, pkg.TasmotaPowerOn)
+	if err != nil {
+		return err
+	}
+
+	// A Job from an earlier WaterAction would turn the relay off too soon
+	c.worker.removeRelayOffJobs(msg.ZoneID)
+
+	logger := c.worker.logger.With("garden_id", c.garden.GetID(), "zone_id", msg.ZoneID, "position", msg.Position)
+	_, err = c.worker.scheduler.
+		Every(1).Day(). // Every is required even though it's not needed for this Job
+		StartAt(c.worker.now().Add(time.Duration(msg.Duration)*time.Millisecond)).
+		LimitRunsTo(1).
+		Tag(relayOffTag).
+		Tag(msg.ZoneID).
+		Tag(relayOffGardenTag(c.garden.GetID())).
+		Do(func(jobLogger *slog.Logger) {
+			err := c.power(msg.Position, pkg.TasmotaPowerOff)
+			if err != nil {
+				jobLogger.Error("error turning off relay", "error", err)
+				schedulerErrors.WithLabelValues("zone", msg.ZoneID).Inc()
+			}
+		}, logger.With("source", "relay_off_job"))
+	if err != nil {
+		// Don't leave the water running if it can't be turned off later
+		offErr := c.power(msg.Position, pkg.TasmotaPowerOff)
+		return errors.Join(fmt.Errorf("error scheduling relay off Job: %w", err), offErr)
+	}
+	return nil
+}
+
+// stop turns off all of the Garden's relays since they do not have a queue that could be used to only stop the
+// current one
+func (c *tasmotaController) stop(bool) error {
+	c.worker.removeRelayOffJobs(relayOffGardenTag(c.garden.GetID()))
+
+	var maxZones uint
+	if c.garden.MaxZones != nil {
+		maxZones = *c.garden.MaxZones
+	}
+
+	var errs []error
+	for position := uint(0); position < maxZones; position++ {
+		errs = append(errs, c.power(position, pkg.TasmotaPowerOff))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *tasmotaController) light(*action.LightAction) error {
+	return errors.New("LightActions are not supported by tasmota controllers")
+}
+
+func (c *tasmotaController) recirculation(*action.RecirculationAction) error {
+	return errors.New("RecirculationActions are not supported by tasmota controllers")
+}
+
+func (c *tasmotaController) power(position uint, state string) error {
+	topic, err := c.garden.Tasmota.Topic(c.garden.TopicPrefix, position)
+	if err != nil {
+		return fmt.Errorf("unable to fill MQTT topic template: %w", err)
+	}
+
+	err = c.worker.publish(topic, []byte(state))
+	if err != nil {
+		return fmt.Errorf("unable to publish %s to relay %d: %w", state, position+1, err)
+	}
+	return nil
+}
+
+// removeRelayOffJobs removes the Jobs that turn off relays. The tag is either a Zone's ID or one from
+// relayOffGardenTag
+func (w *Worker) removeRelayOffJobs(tag string) {
+	jobs, err := w.scheduler.FindJobsByTag(tag, relayOffTag)
+	if err != nil {
+		if !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+			w.logger.Error("unable to find relay off Jobs", "tag", tag, "error", err)
+		}
+		return
+	}
+
+	for _, job := range jobs {
+		w.scheduler.RemoveByReference(job)
+	}
+}
+
+// relayOffGardenTag is used to find all of a Garden's relay off Jobs when stopping watering
+func relayOffGardenTag(gardenID string) string {
+	return fmt.Sprintf("%s_%s", relayOffTag, gardenID)
+}
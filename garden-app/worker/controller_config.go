@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+)
+
+// PublishControllerConfig sends the Garden's number of Zones and default water time to its garden-controller so
+// minor changes don't require reflashing the firmware. OpenSprinkler and Tasmota controllers are not configured this
+// way, so nothing is published for them
+func (w *Worker) PublishControllerConfig(g *pkg.Garden, zones []*pkg.Zone) error {
+	if w.mqttClient == nil || g.UsesOpenSprinkler() || g.UsesTasmota() {
+		return nil
+	}
+
+	msg := action.ConfigMessage{NumZones: numControllerZones(zones)}
+	if g.ControllerHardware != nil && g.ControllerHardware.DefaultWaterTime != nil {
+		msg.DefaultWaterTime = g.ControllerHardware.DefaultWaterTime.Milliseconds()
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("unable to marshal ConfigMessage to JSON: %w", err)
+	}
+
+	topic, err := w.mqttClient.ConfigTopic(g.TopicPrefix)
+	if err != nil {
+		return fmt.Errorf("unable to fill MQTT topic template: %w", err)
+	}
+
+	w.logger.Info("publishing controller config", "garden_id", g.GetID(), "num_zones", msg.NumZones, "topic", topic)
+	err = w.publish(topic, data)
+	if err != nil {
+		return fmt.Errorf("unable to publish ConfigMessage: %w", err)
+	}
+	return nil
+}
+
+// numControllerZones is the size of the controller's ZONES array, which is the highest Position of the active Zones
+// plus one
+func numControllerZones(zones []*pkg.Zone) uint {
+	var result uint
+	for _, z := range zones {
+		if z.Position == nil || z.EndDated() {
+			continue
+		}
+		result = max(result, *z.Position+1)
+	}
+	return result
+}
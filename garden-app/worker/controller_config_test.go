@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishControllerConfig(t *testing.T) {
+	position := func(p uint) *uint { return &p }
+	endDate := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		garden   func() *pkg.Garden
+		zones    []*pkg.Zone
+		expected string
+	}{
+		{
+			"NumZonesFromHighestPosition",
+			createExampleGarden,
+			[]*pkg.Zone{{Position: position(0)}, {Position: position(2)}, {Position: position(5), EndDate: &endDate}},
+			`{"num_zones":3}`,
+		},
+		{
+			"DefaultWaterTime",
+			func() *pkg.Garden {
+				g := createExampleGarden()
+				g.ControllerHardware = &pkg.ControllerHardware{DefaultWaterTime: &pkg.Duration{Duration: 15 * time.Second}}
+				return g
+			},
+			[]*pkg.Zone{{Position: position(0)}},
+			`{"num_zones":1,"default_water_time":15000}`,
+		},
+		{
+			"NoZones",
+			createExampleGarden,
+			nil,
+			`{"num_zones":0}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mqttClient := new(mqtt.MockClient)
+			mqttClient.On("ConfigTopic", "test-garden").Return("test-garden/command/config", nil)
+			mqttClient.On("Publish", "test-garden/command/config", []byte(tt.expected)).Return(nil)
+
+			w := NewWorker(nil, nil, mqttClient, slog.Default())
+			err := w.PublishControllerConfig(tt.garden(), tt.zones)
+			require.NoError(t, err)
+			mqttClient.AssertExpectations(t)
+		})
+	}
+
+	t.Run("SkipTasmota", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		g := createExampleGarden()
+		g.ControllerType = pkg.ControllerTypeTasmota
+
+		w := NewWorker(nil, nil, mqttClient, slog.Default())
+		err := w.PublishControllerConfig(g, []*pkg.Zone{{Position: position(0)}})
+		require.NoError(t, err)
+		mqttClient.AssertNotCalled(t, "Publish")
+	})
+
+	t.Run("NoMQTTClient", func(t *testing.T) {
+		w := NewWorker(nil, nil, nil, slog.Default())
+		assert.NoError(t, w.PublishControllerConfig(createExampleGarden(), nil))
+	})
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/opensprinkler"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSprinklerController(t *testing.T) {
+	requests := []*url.URL{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL)
+		_, _ = w.Write([]byte(`{"result":1}`))
+	}))
+	defer server.Close()
+
+	garden := &pkg.Garden{
+		ID:             babyapi.NewID(),
+		Name:           "garden",
+		TopicPrefix:    "garden",
+		ControllerType: pkg.ControllerTypeOpenSprinkler,
+		OpenSprinkler:  &opensprinkler.Config{Address: server.URL},
+	}
+	zone := &pkg.Zone{ID: babyapi.NewID(), Position: uintPointer(3)}
+
+	// MQTT is not used, so the mock has no expectations
+	mqttClient := new(mqtt.MockClient)
+	w := NewWorker(nil, nil, mqttClient, slog.Default())
+
+	t.Run("Water", func(t *testing.T) {
+		requests = []*url.URL{}
+		err := w.ExecuteWaterAction(context.Background(), garden, zone, &action.WaterAction{Duration: &pkg.Duration{Duration: 2 * time.Minute}})
+		require.NoError(t, err)
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/cm", requests[0].Path)
+		assert.Equal(t, "3", requests[0].Query().Get("sid"))
+		assert.Equal(t, "1", requests[0].Query().Get("en"))
+		assert.Equal(t, "120", requests[0].Query().Get("t"))
+	})
+
+	t.Run("StopAll", func(t *testing.T) {
+		requests = []*url.URL{}
+		err := w.ExecuteStopAction(garden, &action.StopAction{All: true})
+		require.NoError(t, err)
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/cv", requests[0].Path)
+		assert.Equal(t, "1", requests[0].Query().Get("rsn"))
+	})
+
+	t.Run("ErrorLightNotSupported", func(t *testing.T) {
+		err := w.ExecuteLightAction(garden, &action.LightAction{State: pkg.LightStateOn})
+		assert.EqualError(t, err, "LightActions are not supported by opensprinkler controllers")
+	})
+
+	mqttClient.AssertExpectations(t)
+}
+
+func TestTasmotaController(t *testing.T) {
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	newGarden := func() *pkg.Garden {
+		garden := createExampleGarden()
+		garden.ControllerType = pkg.ControllerTypeTasmota
+		return garden
+	}
+
+	t.Run("WaterTurnsRelayOffAfterDuration", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("Publish", "cmnd/test-garden/POWER1", []byte("ON")).Return(nil).Once()
+		mqttClient.On("Publish", "cmnd/test-garden/POWER1", []byte("OFF")).Return(nil).Once()
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		err := w.ExecuteWaterAction(context.Background(), newGarden(), createExampleZone(), &action.WaterAction{Duration: &pkg.Duration{Duration: time.Minute}})
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+
+		_, err = w.AdvanceClock(30 * time.Second)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+
+		_, err = w.AdvanceClock(31 * time.Second)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+	})
+
+	t.Run("CustomPowerTopic", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("Publish", "test-garden/switch/zone_1/command", []byte("ON")).Return(nil).Once()
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		garden := newGarden()
+		garden.Tasmota = &pkg.TasmotaConfig{PowerTopic: "{{.Garden}}/switch/zone_{{.Relay}}/command"}
+
+		err := w.ExecuteWaterAction(context.Background(), garden, createExampleZone(), &action.WaterAction{Duration: &pkg.Duration{Duration: time.Minute}})
+		require.NoError(t, err)
+	})
+
+	t.Run("StopTurnsOffAllRelays", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("Publish", "cmnd/test-garden/POWER1", []byte("ON")).Return(nil).Once()
+		mqttClient.On("Publish", "cmnd/test-garden/POWER1", []byte("OFF")).Return(nil).Once()
+		mqttClient.On("Publish", "cmnd/test-garden/POWER2", []byte("OFF")).Return(nil).Once()
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		garden := newGarden()
+		garden.MaxZones = uintPointer(2)
+
+		err := w.ExecuteWaterAction(context.Background(), garden, createExampleZone(), &action.WaterAction{Duration: &pkg.Duration{Duration: time.Minute}})
+		require.NoError(t, err)
+
+		err = w.ExecuteStopAction(garden, &action.StopAction{})
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 3)
+
+		// the relay off Job was removed, so nothing else is published
+		_, err = w.AdvanceClock(time.Hour)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 3)
+	})
+
+	t.Run("ErrorLightNotSupported", func(t *testing.T) {
+		w := NewWorker(nil, nil, new(mqtt.MockClient), slog.Default())
+		err := w.ExecuteLightAction(newGarden(), &action.LightAction{State: pkg.LightStateOn})
+		assert.EqualError(t, err, "LightActions are not supported by tasmota controllers")
+	})
+}
@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+)
+
+const (
+	waterActionExecutedEvent = "water_action.executed"
+	waterActionManualEvent   = "water_action.manual"
+	lightActionExecutedEvent = "light_action.executed"
+	healthChangedEvent       = "garden_health.changed"
+	leakDetectedEvent        = "leak.detected"
+)
+
+// WaterActionEvent is the Data for an Event that is published after a WaterAction is sent to a controller or a
+// controller reports a manual watering
+type WaterActionEvent struct {
+	GardenID string `json:"garden_id"`
+	ZoneID   string `json:"zone_id"`
+	Duration string `json:"duration"`
+}
+
+// LightActionEvent is the Data for an Event that is published after a LightAction is sent to a controller
+type LightActionEvent struct {
+	GardenID string              `json:"garden_id"`
+	Action   *action.LightAction `json:"action"`
+}
+
+// GardenHealthEvent is the Data for an Event that is published when a Garden's controller goes up or down
+type GardenHealthEvent struct {
+	GardenID    string     `json:"garden_id"`
+	Status      string     `json:"status"`
+	LastContact *time.Time `json:"last_contact,omitempty"`
+}
+
+// LeakEvent is the Data for an Event that is published when a possible leak is detected for a Zone
+type LeakEvent struct {
+	GardenID string `json:"garden_id"`
+	ZoneID   string `json:"zone_id"`
+	Reason   string `json:"reason"`
+}
+
+func (w *Worker) publishWaterActionEvent(g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) {
+	w.events.Publish(events.Event{
+		Type:      waterActionExecutedEvent,
+		ID:        z.GetID(),
+		Timestamp: w.now(),
+		Data: WaterActionEvent{
+			GardenID: g.GetID(),
+			ZoneID:   z.GetID(),
+			Duration: input.Duration.Duration.String(),
+		},
+	})
+}
+
+func (w *Worker) publishManualWaterEvent(g *pkg.Garden, z *pkg.Zone, duration time.Duration) {
+	w.events.Publish(events.Event{
+		Type:      waterActionManualEvent,
+		ID:        z.GetID(),
+		Timestamp: w.now(),
+		Data: WaterActionEvent{
+			GardenID: g.GetID(),
+			ZoneID:   z.GetID(),
+			Duration: duration.String(),
+		},
+	})
+}
+
+func (w *Worker) publishLightActionEvent(g *pkg.Garden, input *action.LightAction) {
+	w.events.Publish(events.Event{
+		Type:      lightActionExecutedEvent,
+		ID:        g.GetID(),
+		Timestamp: w.now(),
+		Data: LightActionEvent{
+			GardenID: g.GetID(),
+			Action:   input,
+		},
+	})
+}
+
+func (w *Worker) publishHealthChangedEvent(g *pkg.Garden, health *pkg.GardenHealth) {
+	w.events.Publish(events.Event{
+		Type:      healthChangedEvent,
+		ID:        g.GetID(),
+		Timestamp: w.now(),
+		Data: GardenHealthEvent{
+			GardenID:    g.GetID(),
+			Status:      health.Status,
+			LastContact: health.LastContact,
+		},
+	})
+}
+
+func (w *Worker) publishLeakDetectedEvent(g *pkg.Garden, z *pkg.Zone, reason string) {
+	w.events.Publish(events.Event{
+		Type:      leakDetectedEvent,
+		ID:        z.GetID(),
+		Timestamp: w.now(),
+		Data: LeakEvent{
+			GardenID: g.GetID(),
+			ZoneID:   z.GetID(),
+			Reason:   reason,
+		},
+	})
+}
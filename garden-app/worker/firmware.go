@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+)
+
+// StartFirmwareUpdate sends the FirmwareUpdateMessage to each Garden's garden-controller and records the pending
+// updates on the Firmware. Updates that can't be published are recorded as failed instead of returning an error so
+// one unreachable controller doesn't stop the others from updating
+func (w *Worker) StartFirmwareUpdate(ctx context.Context, gardens []*pkg.Garden, msg action.FirmwareUpdateMessage) (*pkg.Firmware, error) {
+	// The updates are stored before publishing since controllers could respond before Publish returns
+	err := w.modifyFirmware(ctx, msg.FirmwareID, func(f *pkg.Firmware) error {
+		now := w.now()
+		for _, g := range gardens {
+			f.StartUpdate(g.GetID(), now)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range gardens {
+		publishErr := w.publishFirmwareUpdate(g, msg)
+		if publishErr == nil {
+			continue
+		}
+
+		w.logger.Error("unable to publish firmware update", "garden_id", g.GetID(), "firmware_id", msg.FirmwareID, "error", publishErr)
+		err = w.CompleteFirmwareUpdate(ctx, msg.FirmwareID, g.GetID(), pkg.FirmwareUpdateStatusFailed, publishErr.Error())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return w.storageClient.Firmware.Get(ctx, msg.FirmwareID)
+}
+
+// CompleteFirmwareUpdate records the result of updating a Garden's controller, which is reported by the controller
+// or set when the update could not be sent
+func (w *Worker) CompleteFirmwareUpdate(ctx context.Context, firmwareID, gardenID string, status pkg.FirmwareUpdateStatus, errMsg string) error {
+	return w.modifyFirmware(ctx, firmwareID, func(f *pkg.Firmware) error {
+		return f.CompleteUpdate(gardenID, status, errMsg, w.now())
+	})
+}
+
+// modifyFirmware gets the latest Firmware from storage, changes it, and stores it while holding firmwareUpdatesMtx
+func (w *Worker) modifyFirmware(ctx context.Context, firmwareID string, modify func(*pkg.Firmware) error) error {
+	w.firmwareUpdatesMtx.Lock()
+	defer w.firmwareUpdatesMtx.Unlock()
+
+	f, err := w.storageClient.Firmware.Get(ctx, firmwareID)
+	if err != nil {
+		return fmt.Errorf("error getting Firmware %q: %w", firmwareID, err)
+	}
+
+	err = modify(f)
+	if err != nil {
+		return err
+	}
+
+	err = w.storageClient.Firmware.Set(ctx, f)
+	if err != nil {
+		return fmt.Errorf("error storing Firmware %q: %w", firmwareID, err)
+	}
+	return nil
+}
+
+// publishFirmwareUpdate tells the Garden's garden-controller to download and install new firmware. OpenSprinkler and
+// Tasmota controllers use their own firmware, so they can't be updated this way
+func (w *Worker) publishFirmwareUpdate(g *pkg.Garden, msg action.FirmwareUpdateMessage) error {
+	if g.UsesOpenSprinkler() || g.UsesTasmota() {
+		return fmt.Errorf("firmware updates are not supported by %s controllers", g.ControllerType)
+	}
+	if w.mqttClient == nil {
+		return errors.New("unable to update firmware without an MQTT client")
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("unable to marshal FirmwareUpdateMessage to JSON: %w", err)
+	}
+
+	topic, err := w.mqttClient.FirmwareUpdateTopic(g.TopicPrefix)
+	if err != nil {
+		return fmt.Errorf("unable to fill MQTT topic template: %w", err)
+	}
+
+	w.logger.Info("publishing firmware update", "garden_id", g.GetID(), "firmware_id", msg.FirmwareID, "version", msg.Version, "topic", topic)
+	err = w.publish(topic, data)
+	if err != nil {
+		return fmt.Errorf("unable to publish FirmwareUpdateMessage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartFirmwareUpdate(t *testing.T) {
+	setup := func(t *testing.T) (*storage.Client, *pkg.Firmware, action.FirmwareUpdateMessage) {
+		t.Helper()
+
+		storageClient, err := storage.NewClient(storage.Config{
+			Driver: "hashmap",
+		})
+		require.NoError(t, err)
+
+		firmware := &pkg.Firmware{ID: babyapi.NewID(), Version: "v1.2.0", Checksum: "abc"}
+		require.NoError(t, storageClient.Firmware.Set(context.Background(), firmware))
+
+		return storageClient, firmware, action.FirmwareUpdateMessage{
+			FirmwareID: firmware.GetID(),
+			Version:    "v1.2.0",
+			URL:        "http://localhost/firmware/" + firmware.GetID() + "/content",
+			Checksum:   "abc",
+		}
+	}
+
+	t.Run("Successful", func(t *testing.T) {
+		storageClient, firmware, msg := setup(t)
+
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("FirmwareUpdateTopic", "test-garden").Return("test-garden/command/firmware_update", nil)
+		mqttClient.On("Publish", "test-garden/command/firmware_update", []byte(`{"firmware_id":"`+firmware.GetID()+`","version":"v1.2.0","url":"http://localhost/firmware/`+firmware.GetID()+`/content","checksum":"abc"}`)).Return(nil)
+
+		w := NewWorker(storageClient, nil, mqttClient, slog.Default())
+		g := createExampleGarden()
+		result, err := w.StartFirmwareUpdate(context.Background(), []*pkg.Garden{g}, msg)
+		require.NoError(t, err)
+		mqttClient.AssertExpectations(t)
+
+		require.Contains(t, result.Updates, g.GetID())
+		assert.Equal(t, pkg.FirmwareUpdateStatusPending, result.Updates[g.GetID()].Status)
+
+		err = w.CompleteFirmwareUpdate(context.Background(), firmware.GetID(), g.GetID(), pkg.FirmwareUpdateStatusUpdated, "")
+		require.NoError(t, err)
+
+		stored, err := storageClient.Firmware.Get(context.Background(), firmware.GetID())
+		require.NoError(t, err)
+		assert.Equal(t, pkg.FirmwareUpdateStatusUpdated, stored.Updates[g.GetID()].Status)
+		assert.NotNil(t, stored.Updates[g.GetID()].CompletedAt)
+	})
+
+	t.Run("PublishErrorIsFailed", func(t *testing.T) {
+		storageClient, _, msg := setup(t)
+
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("FirmwareUpdateTopic", "test-garden").Return("test-garden/command/firmware_update", nil)
+		mqttClient.On("Publish", "test-garden/command/firmware_update", mock.Anything).Return(errors.New("publish error"))
+
+		tasmota := createExampleGarden()
+		tasmota.ID = babyapi.ID{ID: xid.New()}
+		tasmota.ControllerType = pkg.ControllerTypeTasmota
+
+		w := NewWorker(storageClient, nil, mqttClient, slog.Default())
+		g := createExampleGarden()
+		result, err := w.StartFirmwareUpdate(context.Background(), []*pkg.Garden{g, tasmota}, msg)
+		require.NoError(t, err)
+
+		assert.Equal(t, pkg.FirmwareUpdateStatusFailed, result.Updates[g.GetID()].Status)
+		assert.Equal(t, "unable to publish FirmwareUpdateMessage: publish error", result.Updates[g.GetID()].Error)
+		assert.Equal(t, pkg.FirmwareUpdateStatusFailed, result.Updates[tasmota.GetID()].Status)
+		assert.Equal(t, "firmware updates are not supported by tasmota controllers", result.Updates[tasmota.GetID()].Error)
+	})
+
+	t.Run("ErrorFirmwareNotFound", func(t *testing.T) {
+		storageClient, _, msg := setup(t)
+		msg.FirmwareID = "missing"
+
+		w := NewWorker(storageClient, nil, nil, slog.Default())
+		_, err := w.StartFirmwareUpdate(context.Background(), []*pkg.Garden{createExampleGarden()}, msg)
+		assert.EqualError(t, err, `error getting Firmware "missing": resource not found`)
+	})
+
+	t.Run("ErrorCompleteUnknownGarden", func(t *testing.T) {
+		storageClient, firmware, _ := setup(t)
+
+		w := NewWorker(storageClient, nil, nil, slog.Default())
+		err := w.CompleteFirmwareUpdate(context.Background(), firmware.GetID(), "garden", pkg.FirmwareUpdateStatusUpdated, "")
+		assert.EqualError(t, err, `firmware "`+firmware.GetID()+`" was not sent to garden "garden"`)
+	})
+}
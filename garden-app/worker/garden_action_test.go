@@ -273,3 +273,24 @@ func TestStopActionExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestStopActionExecuteWithTopicTemplates(t *testing.T) {
+	garden := &pkg.Garden{
+		Name:           "garden",
+		TopicPrefix:    "garden",
+		TopicTemplates: &pkg.TopicTemplates{StopAll: "cmnd/{{.Garden}}/STOP_ALL"},
+	}
+
+	mqttClient := new(mqtt.MockClient)
+	// the configured topic is used when the Garden does not override it
+	mqttClient.On("StopTopic", "garden").Return("garden/command/stop", nil)
+	mqttClient.On("Publish", "garden/command/stop", mock.Anything).Return(nil)
+	mqttClient.On("Publish", "cmnd/garden/STOP_ALL", mock.Anything).Return(nil)
+
+	w := NewWorker(nil, nil, mqttClient, slog.Default())
+	assert.NoError(t, w.ExecuteStopAction(garden, &action.StopAction{}))
+	assert.NoError(t, w.ExecuteStopAction(garden, &action.StopAction{All: true}))
+
+	mqttClient.AssertExpectations(t)
+	mqttClient.AssertNotCalled(t, "StopAllTopic", mock.Anything)
+}
@@ -1,7 +1,6 @@
 package worker
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
@@ -11,6 +10,8 @@ import (
 // ExecuteGardenAction will execute a GardenAction
 func (w *Worker) ExecuteGardenAction(g *pkg.Garden, input *action.GardenAction) error {
 	if input.Light != nil {
+		// A new LightAction replaces the temporary state from a previous one with ForDuration
+		w.cancelLightRevert(g)
 		err := w.ExecuteLightAction(g, input.Light)
 		if err != nil {
 			return fmt.Errorf("unable to execute LightAction: %v", err)
@@ -22,45 +23,60 @@ func (w *Worker) ExecuteGardenAction(g *pkg.Garden, input *action.GardenAction)
 			return fmt.Errorf("unable to execute StopAction: %v", err)
 		}
 	}
+	if input.StopAll != nil {
+		err := w.ExecuteStopAction(g, &action.StopAction{All: true})
+		if err != nil {
+			return fmt.Errorf("unable to execute StopAllAction: %v", err)
+		}
+	}
 	return nil
 }
 
-// ExecuteStopAction sends the message over MQTT to the embedded garden controller
-func (w *Worker) ExecuteStopAction(g *pkg.Garden, input *action.StopAction) error {
-	topicFunc := w.mqttClient.StopTopic
-	if input.All {
-		topicFunc = w.mqttClient.StopAllTopic
-	}
-	topic, err := topicFunc(g.TopicPrefix)
-	if err != nil {
-		return fmt.Errorf("unable to fill MQTT topic template: %v", err)
-	}
-
-	return w.mqttClient.Publish(topic, []byte("no message"))
-}
+// ExecuteStopAction sends the StopAction to the Garden's controller
+func (w *Worker) ExecuteStopAction(g *pkg.Garden, input *action.StopAction) (err error) {
+	defer func() { _ = recordAction("stop", g.GetID(), err) }()
 
-// ExecuteLightAction sends an MQTT message to the garden controller to change the state of the light
-func (w *Worker) ExecuteLightAction(g *pkg.Garden, input *action.LightAction) error {
-	msg, err := json.Marshal(input)
+	err = w.controller(g).stop(input.All)
 	if err != nil {
-		return fmt.Errorf("unable to marshal LightAction to JSON: %v", err)
+		return err
 	}
 
-	topic, err := w.mqttClient.LightTopic(g.TopicPrefix)
-	if err != nil {
-		return fmt.Errorf("unable to fill MQTT topic template: %v", err)
+	// Queued and deferred WaterActions and remaining cycles are also cancelled so they do not start after the controller's queue is cleared
+	if input.All {
+		cancelled := w.clearWaterQueue(g)
+		cancelled += w.cancelDeferredWaterings(func(dw DeferredWatering) bool { return dw.gardenID == g.GetID() })
+		cancelled += w.cancelWaterCycles(cycleGardenTag(g.GetID()))
+		w.logger.Info("cancelled queued and deferred WaterActions", "garden_id", g.GetID(), "count", cancelled)
 	}
+	return nil
+}
+
+// ExecuteLightAction sends the LightAction to the Garden's controller to change the state of the light. If it has
+// ForDuration, turning the light OFF delays the LightSchedule's next ON time and otherwise the light is changed back
+// after the duration
+func (w *Worker) ExecuteLightAction(g *pkg.Garden, input *action.LightAction) (err error) {
+	defer func() { _ = recordAction("light", g.GetID(), err) }()
 
-	err = w.mqttClient.Publish(topic, msg)
+	previous := w.LightState(g)
+	err = w.controller(g).light(input)
 	if err != nil {
-		return fmt.Errorf("unable to publish LightAction: %v", err)
+		return err
 	}
+	w.publishLightActionEvent(g, input)
 
 	// If this is a LightAction with specified duration, additional steps are necessary
 	if input != nil && input.ForDuration != nil {
-		err := w.ScheduleLightDelay(g, input)
+		if input.State == pkg.LightStateOff && g.LightSchedule != nil {
+			err := w.ScheduleLightDelay(g, input)
+			if err != nil {
+				return fmt.Errorf("unable to handle light delay: %v", err)
+			}
+			return nil
+		}
+
+		err := w.scheduleLightRevert(g, input, previous)
 		if err != nil {
-			return fmt.Errorf("unable to handle light delay: %v", err)
+			return fmt.Errorf("unable to schedule light revert: %v", err)
 		}
 	}
 	return nil
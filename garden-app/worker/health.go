@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
+	"github.com/calvinmclean/babyapi"
+)
+
+const (
+	healthCheckTag      = "health_check"
+	healthCheckInterval = time.Minute
+
+	healthStatusUp   = "UP"
+	healthStatusDown = "DOWN"
+)
+
+// ScheduleHealthChecks schedules a Job that periodically checks the health of each Garden's controller and sends
+// a notification when a controller stops responding or comes back up. Health checks are skipped when using a
+// virtual clock since advancing it would run a query for every minute of simulated time
+func (w *Worker) ScheduleHealthChecks() error {
+	logger := w.logger.With("source", "scheduled_job")
+	if w.clock.IsVirtual() {
+		logger.Info("skipping controller health checks in simulation mode")
+		return nil
+	}
+	logger.Info("creating scheduled Job for controller health checks", "interval", healthCheckInterval.String())
+
+	_, err := w.scheduler.
+		Every(healthCheckInterval).
+		Tag(healthCheckTag).
+		Do(w.checkGardenHealth, logger)
+	return err
+}
+
+// RecordControllerContact records that the controller with the topic prefix published health data
+func (w *Worker) RecordControllerContact(topicPrefix string) {
+	w.controllerContactsMtx.Lock()
+	defer w.controllerContactsMtx.Unlock()
+
+	w.controllerContacts[topicPrefix] = w.now()
+}
+
+// GardenHealth returns the health of the Garden's controller using the last time it published health data. If
+// it has not published since the server started, the last contact time is queried from InfluxDB, if available
+func (w *Worker) GardenHealth(ctx context.Context, g *pkg.Garden, influxdbClient metrics.Client) *pkg.GardenHealth {
+	w.controllerContactsMtx.Lock()
+	lastContact := w.controllerContacts[g.TopicPrefix]
+	w.controllerContactsMtx.Unlock()
+
+	if lastContact.IsZero() && influxdbClient != nil {
+		var err error
+		lastContact, err = influxdbClient.GetLastContact(ctx, g.TopicPrefix)
+		if err != nil {
+			return &pkg.GardenHealth{
+				Status:  "N/A",
+				Details: err.Error(),
+			}
+		}
+	}
+
+	return pkg.NewGardenHealth(lastContact, w.now(), w.getHealthThreshold())
+}
+
+// checkGardenHealth gets the current health of each Garden and sends notifications when it changes. Notifications
+// are not sent the first time a Garden is checked since there is no previous status to compare with, or before
+// the controller has ever been in contact
+func (w *Worker) checkGardenHealth(logger *slog.Logger) {
+	// The MQTT client otherwise only connects when publishing, so this makes sure it is subscribed to health data
+	if w.mqttClient != nil {
+		err := w.mqttClient.Connect()
+		if err != nil {
+			logger.Warn("unable to connect to MQTT broker for health data", "error", err)
+		}
+	}
+
+	gardens, err := w.storageClient.Gardens.GetAll(context.Background(), babyapi.EndDatedQueryParam(false))
+	if err != nil {
+		logger.Error("error getting Gardens for health check", "error", err)
+		schedulerErrors.WithLabelValues(healthCheckTag, "").Inc()
+		return
+	}
+
+	for _, g := range gardens {
+		gardenLogger := logger.With("garden_id", g.GetID())
+
+		ctx, cancel := context.WithTimeout(context.Background(), influxdb.QueryTimeout)
+		health := w.GardenHealth(ctx, g, w.influxdbClient)
+		cancel()
+
+		if health.Status != healthStatusUp && health.Status != healthStatusDown {
+			gardenLogger.Error("unable to get Garden health", "details", health.Details)
+			schedulerErrors.WithLabelValues(gardenLabels(g)...).Inc()
+			continue
+		}
+		if health.LastContact == nil {
+			continue
+		}
+
+		previous := w.setGardenHealthStatus(g, health.Status)
+		if previous == "" || previous == health.Status {
+			continue
+		}
+
+		gardenLogger.Info("Garden health changed", "previous", previous, "status", health.Status)
+		w.publishHealthChangedEvent(g, health)
+		w.sendHealthNotification(g, health, gardenLogger)
+	}
+}
+
+// setGardenHealthStatus stores the latest health status for the Garden and returns the previous one
+func (w *Worker) setGardenHealthStatus(g *pkg.Garden, status string) string {
+	w.gardenHealthMtx.Lock()
+	defer w.gardenHealthMtx.Unlock()
+
+	previous := w.gardenHealth[g.GetID()]
+	w.gardenHealth[g.GetID()] = status
+	return previous
+}
+
+func (w *Worker) sendHealthNotification(g *pkg.Garden, health *pkg.GardenHealth, logger *slog.Logger) {
+	title := fmt.Sprintf("%s: Controller Up", g.Name)
+	if health.Status == healthStatusDown {
+		title = fmt.Sprintf("%s: Controller Down", g.Name)
+	}
+	w.sendNotification(title, health.Details, logger)
+}
@@ -0,0 +1,184 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications/fake"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckGardenHealth(t *testing.T) {
+	fake.ResetLastMessage()
+
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	err = storageClient.NotificationClientConfigs.Set(context.Background(), &notifications.Client{
+		ID:      babyapi.NewID(),
+		Name:    "TestClient",
+		Type:    "fake",
+		Options: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	garden := createExampleGarden()
+	err = storageClient.Gardens.Set(context.Background(), garden)
+	require.NoError(t, err)
+
+	influxdbClient := new(influxdb.MockClient)
+	worker := NewWorker(storageClient, influxdbClient, nil, slog.Default())
+
+	checkWithLastContact := func(lastContact time.Time, err error) {
+		influxdbClient.On("GetLastContact", mock.Anything, "test-garden").Return(lastContact, err).Once()
+		worker.checkGardenHealth(worker.logger)
+	}
+
+	t.Run("FirstCheckDoesNotNotify", func(t *testing.T) {
+		checkWithLastContact(time.Now(), nil)
+		assert.Equal(t, fake.Message{}, fake.LastMessage())
+	})
+
+	t.Run("UnchangedDoesNotNotify", func(t *testing.T) {
+		checkWithLastContact(time.Now(), nil)
+		assert.Equal(t, fake.Message{}, fake.LastMessage())
+	})
+
+	t.Run("ErrorDoesNotNotify", func(t *testing.T) {
+		checkWithLastContact(time.Time{}, errors.New("influxdb error"))
+		assert.Equal(t, fake.Message{}, fake.LastMessage())
+	})
+
+	t.Run("ControllerDown", func(t *testing.T) {
+		checkWithLastContact(time.Now().Add(-10*time.Minute), nil)
+		assert.Equal(t, "test-garden: Controller Down", fake.LastMessage().Title)
+		assert.Contains(t, fake.LastMessage().Message, "last contact from Garden was 10m0")
+	})
+
+	t.Run("ControllerUp", func(t *testing.T) {
+		checkWithLastContact(time.Now(), nil)
+		assert.Equal(t, "test-garden: Controller Up", fake.LastMessage().Title)
+	})
+
+	influxdbClient.AssertExpectations(t)
+}
+
+func TestGardenHealthFromControllerContact(t *testing.T) {
+	start := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+	w := NewWorker(nil, nil, nil, slog.Default())
+	w.SetClock(clock.NewVirtual(start))
+	w.SetHealthThreshold(2 * time.Minute)
+
+	g := createExampleGarden()
+
+	t.Run("NoContact", func(t *testing.T) {
+		health := w.GardenHealth(context.Background(), g, nil)
+		assert.Equal(t, &pkg.GardenHealth{Status: "DOWN", Details: "no last contact time available"}, health)
+	})
+
+	t.Run("Up", func(t *testing.T) {
+		w.RecordControllerContact("test-garden")
+		_, err := w.AdvanceClock(time.Minute)
+		require.NoError(t, err)
+
+		health := w.GardenHealth(context.Background(), g, nil)
+		assert.Equal(t, "UP", health.Status)
+		assert.Equal(t, start, health.LastContact.UTC())
+		assert.Equal(t, "last contact from Garden was 1m0s ago", health.Details)
+	})
+
+	t.Run("DownAfterThreshold", func(t *testing.T) {
+		_, err := w.AdvanceClock(time.Minute)
+		require.NoError(t, err)
+
+		health := w.GardenHealth(context.Background(), g, nil)
+		assert.Equal(t, "DOWN", health.Status)
+		assert.Equal(t, start, health.LastContact.UTC())
+	})
+
+	t.Run("InfluxDBNotUsedAfterContact", func(t *testing.T) {
+		// The mock panics if it is used
+		influxdbClient := new(influxdb.MockClient)
+
+		health := w.GardenHealth(context.Background(), g, influxdbClient)
+		assert.Equal(t, "DOWN", health.Status)
+	})
+}
+
+func TestCheckGardenHealthPublishesEvent(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	garden := createExampleGarden()
+	err = storageClient.Gardens.Set(context.Background(), garden)
+	require.NoError(t, err)
+
+	start := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	bus := events.NewBus()
+	subscriber, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	w := NewWorker(storageClient, nil, nil, slog.Default())
+	w.SetClock(clock.NewVirtual(start))
+	w.SetEventBus(bus)
+
+	// Controllers that have never been in contact are not tracked, so they do not notify when first seen
+	w.checkGardenHealth(w.logger)
+	w.RecordControllerContact("test-garden")
+	w.checkGardenHealth(w.logger)
+
+	_, err = w.AdvanceClock(10 * time.Minute)
+	require.NoError(t, err)
+	w.checkGardenHealth(w.logger)
+
+	select {
+	case e := <-subscriber:
+		assert.Equal(t, "garden_health.changed", e.Type)
+		data, ok := e.Data.(GardenHealthEvent)
+		require.True(t, ok)
+		assert.Equal(t, garden.GetID(), data.GardenID)
+		assert.Equal(t, "DOWN", data.Status)
+		assert.Equal(t, start, data.LastContact.UTC())
+	case <-time.After(time.Second):
+		t.Fatal("expected garden_health.changed Event")
+	}
+
+	select {
+	case e := <-subscriber:
+		t.Fatalf("unexpected Event: %v", e)
+	default:
+	}
+}
+
+func TestScheduleHealthChecksSkippedWithVirtualClock(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	w := NewWorker(storageClient, new(influxdb.MockClient), nil, slog.Default())
+	w.SetClock(clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)))
+
+	err = w.ScheduleHealthChecks()
+	require.NoError(t, err)
+
+	_, err = w.scheduler.FindJobsByTag(healthCheckTag)
+	assert.Error(t, err)
+}
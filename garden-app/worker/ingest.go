@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ingestTopic matches all of the data that controllers publish, like "{{.TopicPrefix}}/data/moisture"
+const ingestTopic = "+/data/#"
+
+var ingestedMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "garden_app",
+	Name:      "ingested_messages",
+	Help:      "count of messages from controllers written to the metrics backend by result (success or error)",
+}, []string{"result"})
+
+// StartIngestion subscribes to all data published by controllers and writes it to the metrics backend. This
+// replaces Telegraf, so the garden-app can be deployed without it
+func (w *Worker) StartIngestion() error {
+	subscriber, ok := w.mqttClient.(mqtt.Subscriber)
+	if !ok {
+		return errors.New("MQTT client does not support subscribing to topics")
+	}
+
+	logger := w.logger.With("source", "ingest", "topic", ingestTopic)
+	logger.Info("subscribing to controller data")
+
+	err := subscriber.Subscribe(ingestTopic, paho.MessageHandler(func(_ paho.Client, msg paho.Message) {
+		err := w.influxdbClient.Write(context.Background(), msg.Topic(), msg.Payload())
+		if err != nil {
+			logger.Error("unable to write controller data", "message_topic", msg.Topic(), "error", err)
+			ingestedMessages.WithLabelValues("error").Inc()
+			return
+		}
+		ingestedMessages.WithLabelValues("success").Inc()
+	}))
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to %q: %w", ingestTopic, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartIngestion(t *testing.T) {
+	influxdbClient := new(influxdb.MockClient)
+	mqttClient := mqtt.NewInMemoryClient(mqtt.Config{}, nil)
+	w := NewWorker(nil, influxdbClient, mqttClient, slog.Default())
+
+	require.NoError(t, w.StartIngestion())
+
+	messages := []struct {
+		topic   string
+		payload string
+		err     error
+	}{
+		{"test-garden/data/moisture", "moisture,zone=0 value=40", nil},
+		{"test-garden/data/water", "water,zone=0 millis=1000", nil},
+		{"test-garden/data/light", `light,garden="test-garden" state=1`, nil},
+		// errors are only logged so the next message is still written
+		{"test-garden/data/health", `health garden="test-garden"`, errors.New("influxdb error")},
+		{"test-garden/data/logs", `logs message="setup complete"`, nil},
+	}
+	for _, msg := range messages {
+		influxdbClient.On("Write", mock.Anything, msg.topic, []byte(msg.payload)).Return(msg.err).Once()
+		require.NoError(t, mqttClient.Publish(msg.topic, []byte(msg.payload)))
+	}
+
+	// commands are not data, so they are not written
+	require.NoError(t, mqttClient.Publish("test-garden/command/water", []byte(`{"duration":1000}`)))
+
+	influxdbClient.AssertExpectations(t)
+}
+
+func TestStartIngestionErrorNotSubscriber(t *testing.T) {
+	w := NewWorker(nil, new(influxdb.MockClient), new(mqtt.MockClient), slog.Default())
+
+	err := w.StartIngestion()
+	assert.EqualError(t, err, "MQTT client does not support subscribing to topics")
+}
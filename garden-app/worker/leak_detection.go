@@ -0,0 +1,238 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/babyapi"
+)
+
+const (
+	leakDetectionTag        = "leak_detection"
+	leakDetectionInterval   = time.Hour
+	moistureHistoryInterval = 15 * time.Minute
+
+	// DefaultLeakFlowGracePeriod is used when the LeakDetectionConfig does not set a FlowGracePeriod
+	DefaultLeakFlowGracePeriod = time.Hour
+	// DefaultLeakMoistureRise is used when the LeakDetectionConfig does not set a MoistureRise
+	DefaultLeakMoistureRise = 10
+	// DefaultLeakMoistureWindow is used when the LeakDetectionConfig does not set a MoistureWindow
+	DefaultLeakMoistureWindow = 6 * time.Hour
+)
+
+// LeakDetectionConfig configures alerts for signs of a stuck valve or leak: a flow meter measuring water when no
+// watering was commanded, or soil moisture rising when the Zone was not watered
+type LeakDetectionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// FlowGracePeriod is how long after a commanded watering should have finished that flow data is still expected,
+	// since controllers can queue waterings
+	FlowGracePeriod time.Duration `mapstructure:"flow_grace_period"`
+	// MoistureRise is the increase in soil moisture percent that is considered unexpected
+	MoistureRise float64 `mapstructure:"moisture_rise"`
+	// MoistureWindow is how far back soil moisture is checked for a rise
+	MoistureWindow time.Duration `mapstructure:"moisture_window"`
+}
+
+// flowGracePeriod returns the FlowGracePeriod or its default
+func (c LeakDetectionConfig) flowGracePeriod() time.Duration {
+	if c.FlowGracePeriod > 0 {
+		return c.FlowGracePeriod
+	}
+	return DefaultLeakFlowGracePeriod
+}
+
+// moistureRise returns the MoistureRise or its default
+func (c LeakDetectionConfig) moistureRise() float64 {
+	if c.MoistureRise > 0 {
+		return c.MoistureRise
+	}
+	return DefaultLeakMoistureRise
+}
+
+// moistureWindow returns the MoistureWindow or its default
+func (c LeakDetectionConfig) moistureWindow() time.Duration {
+	if c.MoistureWindow > 0 {
+		return c.MoistureWindow
+	}
+	return DefaultLeakMoistureWindow
+}
+
+// SetLeakDetection configures the Worker's leak detection. This must be used before scheduling leak detection, but
+// the thresholds can be changed afterwards
+func (w *Worker) SetLeakDetection(cfg LeakDetectionConfig) {
+	w.settingsMtx.Lock()
+	defer w.settingsMtx.Unlock()
+	w.leakDetection = cfg
+}
+
+func (w *Worker) getLeakDetection() LeakDetectionConfig {
+	w.settingsMtx.RLock()
+	defer w.settingsMtx.RUnlock()
+	return w.leakDetection
+}
+
+// ScheduleLeakDetection schedules a Job that periodically checks each Zone's soil moisture for a rise without any
+// watering. Like health checks, this is skipped when using a virtual clock
+func (w *Worker) ScheduleLeakDetection() error {
+	if !w.getLeakDetection().Enabled {
+		return nil
+	}
+
+	logger := w.logger.With("source", "scheduled_job")
+	if w.clock.IsVirtual() {
+		logger.Info("skipping leak detection in simulation mode")
+		return nil
+	}
+	logger.Info("creating scheduled Job for leak detection", "interval", leakDetectionInterval.String())
+
+	_, err := w.scheduler.
+		Every(leakDetectionInterval).
+		Tag(leakDetectionTag).
+		Do(w.checkMoistureLeaks, logger)
+	return err
+}
+
+// CheckUnexpectedFlow alerts when a flow meter measures water for a Zone that has not been commanded to water
+// recently. This is called when a controller publishes water data with the liters that it measured
+func (w *Worker) CheckUnexpectedFlow(g *pkg.Garden, z *pkg.Zone, liters float64) {
+	leakDetection := w.getLeakDetection()
+	if !leakDetection.Enabled || liters <= 0 {
+		return
+	}
+	logger := w.logger.With("garden_id", g.GetID(), "zone_id", z.GetID())
+
+	if w.storageClient == nil || w.storageClient.WaterHistory == nil {
+		logger.Debug("unable to check for unexpected flow without water history")
+		return
+	}
+
+	history, err := w.storageClient.WaterHistory.GetWaterHistory(context.Background(), z.GetID(), time.Time{}, 1)
+	if err != nil {
+		logger.Error("unable to get water history to check for unexpected flow", "error", err)
+		return
+	}
+
+	now := w.now()
+	if len(history) > 0 {
+		d := history[0].Duration
+		if d != nil && !history[0].RecordTime.Add(d.Duration+leakDetection.flowGracePeriod()).Before(now) {
+			return
+		}
+	}
+
+	w.alertLeak(g, z, fmt.Sprintf("flow meter measured %.1fL, but no watering was commanded", liters), logger)
+}
+
+// checkMoistureLeaks alerts when a Zone's soil moisture rises by more than the configured amount and the Zone was
+// not watered since the moisture was at its lowest. Zones without moisture data are ignored
+func (w *Worker) checkMoistureLeaks(logger *slog.Logger) {
+	if w.influxdbClient == nil {
+		return
+	}
+
+	gardens, err := w.storageClient.Gardens.GetAll(context.Background(), babyapi.EndDatedQueryParam(false))
+	if err != nil {
+		logger.Error("error getting Gardens for leak detection", "error", err)
+		schedulerErrors.WithLabelValues(leakDetectionTag, "").Inc()
+		return
+	}
+
+	zones, err := w.storageClient.Zones.GetAll(context.Background(), babyapi.EndDatedQueryParam(false))
+	if err != nil {
+		logger.Error("error getting Zones for leak detection", "error", err)
+		schedulerErrors.WithLabelValues(leakDetectionTag, "").Inc()
+		return
+	}
+
+	for _, g := range gardens {
+		for _, z := range zones {
+			if z.GardenID != g.ID.ID || z.Position == nil {
+				continue
+			}
+
+			zoneLogger := logger.With("garden_id", g.GetID(), "zone_id", z.GetID())
+			err = w.checkMoistureLeak(g, z, zoneLogger)
+			if err != nil {
+				zoneLogger.Error("unable to check soil moisture for leaks", "error", err)
+				schedulerErrors.WithLabelValues(zoneLabels(z)...).Inc()
+			}
+		}
+	}
+}
+
+// checkMoistureLeak compares the Zone's latest soil moisture with the lowest earlier value in the window
+func (w *Worker) checkMoistureLeak(g *pkg.Garden, z *pkg.Zone, logger *slog.Logger) error {
+	leakDetection := w.getLeakDetection()
+	window := leakDetection.moistureWindow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), influxdb.QueryTimeout)
+	defer cancel()
+
+	history, err := w.influxdbClient.GetMoistureHistory(ctx, *z.Position, g.TopicPrefix, window, moistureHistoryInterval)
+	if err != nil {
+		return fmt.Errorf("error getting moisture history: %w", err)
+	}
+	if len(history) < 2 {
+		return nil
+	}
+
+	latest, _ := history[len(history)-1]["Value"].(float64)
+	lowest, _ := history[0]["Value"].(float64)
+	lowestTime, _ := history[0]["RecordTime"].(time.Time)
+	for _, h := range history[1 : len(history)-1] {
+		value, _ := h["Value"].(float64)
+		if value < lowest {
+			lowest = value
+			lowestTime, _ = h["RecordTime"].(time.Time)
+		}
+	}
+
+	rise := latest - lowest
+	if rise < leakDetection.moistureRise() {
+		return nil
+	}
+
+	if w.storageClient.WaterHistory != nil {
+		watered, err := w.storageClient.WaterHistory.GetWaterHistory(ctx, z.GetID(), lowestTime, 1)
+		if err != nil {
+			return fmt.Errorf("error getting water history: %w", err)
+		}
+		if len(watered) > 0 {
+			return nil
+		}
+	}
+
+	// The same rise is seen by each check until it leaves the window, so it is only alerted once
+	if !w.shouldAlertMoistureLeak(z, window) {
+		return nil
+	}
+
+	w.alertLeak(g, z, fmt.Sprintf("soil moisture rose from %.1f%% to %.1f%% without watering", lowest, latest), logger)
+	return nil
+}
+
+// shouldAlertMoistureLeak returns true if the Zone has not had a moisture leak alert in the window, and records the
+// alert if it has not
+func (w *Worker) shouldAlertMoistureLeak(z *pkg.Zone, window time.Duration) bool {
+	w.moistureLeakAlertsMtx.Lock()
+	defer w.moistureLeakAlertsMtx.Unlock()
+
+	now := w.now()
+	last, ok := w.moistureLeakAlerts[z.GetID()]
+	if ok && now.Sub(last) < window {
+		return false
+	}
+	w.moistureLeakAlerts[z.GetID()] = now
+	return true
+}
+
+// alertLeak publishes an Event and sends a notification for a possible leak
+func (w *Worker) alertLeak(g *pkg.Garden, z *pkg.Zone, reason string, logger *slog.Logger) {
+	logger.Warn("possible leak detected", "reason", reason)
+	w.publishLeakDetectedEvent(g, z, reason)
+	w.sendNotification(fmt.Sprintf("%s: Possible Leak", g.Name), fmt.Sprintf("%s: %s", z.Name, reason), logger)
+}
@@ -0,0 +1,234 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUnexpectedFlow(t *testing.T) {
+	start := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		history  []pkg.WaterHistory
+		expected string
+	}{
+		{
+			"NoWaterHistory",
+			nil,
+			"flow meter measured 1.5L, but no watering was commanded",
+		},
+		{
+			"DuringWatering",
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: 30 * time.Minute}, RecordTime: start.Add(-10 * time.Minute)}},
+			"",
+		},
+		{
+			"WithinGracePeriod",
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: 30 * time.Minute}, RecordTime: start.Add(-80 * time.Minute)}},
+			"",
+		},
+		{
+			"AfterGracePeriod",
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: 30 * time.Minute}, RecordTime: start.Add(-2 * time.Hour)}},
+			"flow meter measured 1.5L, but no watering was commanded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			garden := createExampleGarden()
+			zone := createExampleZone()
+			for _, h := range tt.history {
+				err = storageClient.WaterHistory.AddWaterHistory(context.Background(), zone.GetID(), h)
+				require.NoError(t, err)
+			}
+
+			bus := events.NewBus()
+			subscriber, unsubscribe := bus.Subscribe()
+			defer unsubscribe()
+
+			w := NewWorker(storageClient, nil, nil, slog.Default())
+			w.SetClock(clock.NewVirtual(start))
+			w.SetEventBus(bus)
+			w.SetLeakDetection(LeakDetectionConfig{Enabled: true})
+
+			w.CheckUnexpectedFlow(garden, zone, 1.5)
+
+			select {
+			case e := <-subscriber:
+				require.NotEmpty(t, tt.expected, "unexpected Event: %v", e)
+				assert.Equal(t, "leak.detected", e.Type)
+				assert.Equal(t, LeakEvent{GardenID: garden.GetID(), ZoneID: zone.GetID(), Reason: tt.expected}, e.Data)
+			default:
+				assert.Empty(t, tt.expected, "expected leak.detected Event")
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		bus := events.NewBus()
+		subscriber, unsubscribe := bus.Subscribe()
+		defer unsubscribe()
+
+		// Storage is not used when disabled
+		w := NewWorker(nil, nil, nil, slog.Default())
+		w.SetEventBus(bus)
+
+		w.CheckUnexpectedFlow(createExampleGarden(), createExampleZone(), 1.5)
+
+		select {
+		case e := <-subscriber:
+			t.Fatalf("unexpected Event: %v", e)
+		default:
+		}
+	})
+}
+
+func TestCheckMoistureLeaks(t *testing.T) {
+	start := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	moistureHistory := func(values ...float64) []map[string]interface{} {
+		result := []map[string]interface{}{}
+		for i, v := range values {
+			result = append(result, map[string]interface{}{
+				"Value":      v,
+				"RecordTime": start.Add(time.Duration(i-len(values)+1) * time.Hour),
+			})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		history  []map[string]interface{}
+		watered  []pkg.WaterHistory
+		expected string
+	}{
+		{
+			"NoMoistureData",
+			moistureHistory(),
+			nil,
+			"",
+		},
+		{
+			"SmallRise",
+			moistureHistory(40, 38, 45),
+			nil,
+			"",
+		},
+		{
+			"RiseWithoutWatering",
+			moistureHistory(40, 35, 37, 50),
+			nil,
+			"soil moisture rose from 35.0% to 50.0% without watering",
+		},
+		{
+			"RiseAfterWatering",
+			moistureHistory(40, 35, 37, 50),
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: 15 * time.Minute}, RecordTime: start.Add(-90 * time.Minute)}},
+			"",
+		},
+		{
+			"WateringBeforeLowest",
+			moistureHistory(40, 35, 37, 50),
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: 15 * time.Minute}, RecordTime: start.Add(-5 * time.Hour)}},
+			"soil moisture rose from 35.0% to 50.0% without watering",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			garden := createExampleGarden()
+			err = storageClient.Gardens.Set(context.Background(), garden)
+			require.NoError(t, err)
+
+			zone := createExampleZone()
+			err = storageClient.Zones.Set(context.Background(), zone)
+			require.NoError(t, err)
+
+			for _, h := range tt.watered {
+				err = storageClient.WaterHistory.AddWaterHistory(context.Background(), zone.GetID(), h)
+				require.NoError(t, err)
+			}
+
+			influxdbClient := new(influxdb.MockClient)
+			influxdbClient.On("GetMoistureHistory", mock.Anything, uint(0), "test-garden", 6*time.Hour, 15*time.Minute).Return(tt.history, nil)
+
+			bus := events.NewBus()
+			subscriber, unsubscribe := bus.Subscribe()
+			defer unsubscribe()
+
+			w := NewWorker(storageClient, influxdbClient, nil, slog.Default())
+			w.SetClock(clock.NewVirtual(start))
+			w.SetEventBus(bus)
+			w.SetLeakDetection(LeakDetectionConfig{Enabled: true})
+
+			// The second check does not alert again for the same rise
+			w.checkMoistureLeaks(w.logger)
+			w.checkMoistureLeaks(w.logger)
+
+			if tt.expected != "" {
+				select {
+				case e := <-subscriber:
+					assert.Equal(t, "leak.detected", e.Type)
+					assert.Equal(t, LeakEvent{GardenID: garden.GetID(), ZoneID: zone.GetID(), Reason: tt.expected}, e.Data)
+				default:
+					t.Fatal("expected leak.detected Event")
+				}
+			}
+
+			select {
+			case e := <-subscriber:
+				t.Fatalf("unexpected Event: %v", e)
+			default:
+			}
+			influxdbClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScheduleLeakDetection(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		w := NewWorker(nil, nil, nil, slog.Default())
+
+		err := w.ScheduleLeakDetection()
+		require.NoError(t, err)
+
+		_, err = w.scheduler.FindJobsByTag(leakDetectionTag)
+		assert.Error(t, err)
+	})
+
+	t.Run("SkippedWithVirtualClock", func(t *testing.T) {
+		w := NewWorker(nil, nil, nil, slog.Default())
+		w.SetClock(clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)))
+		w.SetLeakDetection(LeakDetectionConfig{Enabled: true})
+
+		err := w.ScheduleLeakDetection()
+		require.NoError(t, err)
+
+		_, err = w.scheduler.FindJobsByTag(leakDetectionTag)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/go-co-op/gocron"
+	"github.com/rs/xid"
+)
+
+// lightRevertTag is used instead of the Garden's ID so light reverts are not removed when the LightSchedule changes
+const lightRevertTag = "LIGHT_REVERT"
+
+// lightRevert is a one-time Job that changes a Garden's light back after a LightAction with ForDuration
+type lightRevert struct {
+	id    string
+	job   *gocron.Job
+	runAt time.Time
+	state pkg.LightState
+}
+
+// RecordLightState records the light state published by the controller with the topic prefix
+func (w *Worker) RecordLightState(topicPrefix string, state pkg.LightState) {
+	w.lightStatesMtx.Lock()
+	defer w.lightStatesMtx.Unlock()
+
+	w.lightStates[topicPrefix] = pkg.GardenLightState{
+		State:     state,
+		UpdatedAt: w.now(),
+	}
+}
+
+// LightState returns the last light state published by the Garden's controller. It is nil if the controller has not
+// published its light state since the server started
+func (w *Worker) LightState(g *pkg.Garden) *pkg.GardenLightState {
+	w.lightStatesMtx.Lock()
+	defer w.lightStatesMtx.Unlock()
+
+	state, ok := w.lightStates[g.TopicPrefix]
+	if !ok {
+		return nil
+	}
+	return &state
+}
+
+// LightRevert returns the time and state of the Garden's pending light revert. It returns false if there is none
+func (w *Worker) LightRevert(g *pkg.Garden) (time.Time, pkg.LightState, bool) {
+	w.lightRevertsMtx.Lock()
+	defer w.lightRevertsMtx.Unlock()
+
+	revert, ok := w.lightReverts[g.GetID()]
+	return revert.runAt, revert.state, ok
+}
+
+// scheduleLightRevert schedules changing the light back after the LightAction's ForDuration. previous is the light
+// state before the LightAction was sent
+func (w *Worker) scheduleLightRevert(g *pkg.Garden, input *action.LightAction, previous *pkg.GardenLightState) error {
+	state := w.revertLightState(g, input.State, previous)
+	runAt := w.now().Add(input.ForDuration.Duration)
+
+	return w.scheduleLightRevertJob(g, xid.New().String(), state, runAt)
+}
+
+// revertLightState decides what state the light changes back to. Gardens with a LightSchedule go back to the
+// scheduled state. Otherwise, the light goes back to its previous state or the opposite of the requested state if
+// it is not known
+func (w *Worker) revertLightState(g *pkg.Garden, requested pkg.LightState, previous *pkg.GardenLightState) pkg.LightState {
+	if g.LightSchedule != nil {
+		nextOnTime := w.GetNextLightTime(g, pkg.LightStateOn)
+		nextOffTime := w.GetNextLightTime(g, pkg.LightStateOff)
+		if nextOnTime != nil && nextOffTime != nil {
+			// If the next OFF time is before the next ON time, the light is scheduled to be ON now
+			if nextOffTime.Before(*nextOnTime) {
+				return pkg.LightStateOn
+			}
+			return pkg.LightStateOff
+		}
+	}
+
+	if previous != nil {
+		return previous.State
+	}
+
+	switch requested {
+	case pkg.LightStateOn:
+		return pkg.LightStateOff
+	case pkg.LightStateOff:
+		return pkg.LightStateOn
+	default:
+		return pkg.LightStateToggle
+	}
+}
+
+// scheduleLightRevertJob replaces the Garden's pending light revert with a one-time Job and saves it as a
+// PendingJob with the ID so it is restored after a restart
+func (w *Worker) scheduleLightRevertJob(g *pkg.Garden, id string, state pkg.LightState, runAt time.Time) error {
+	w.cancelLightRevert(g)
+
+	logger := w.contextLogger(g, nil, nil).With("state", state.String(), "run_at", runAt)
+	logger.Info("scheduling light revert")
+
+	scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Inc()
+	job, err := w.scheduleOnce(runAt).
+		Tag("garden").
+		Tag(lightRevertTag).
+		Do(w.executeLightRevert, g, id, state, logger.With("source", "light_revert_job"))
+	if err != nil {
+		scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Dec()
+		return fmt.Errorf("error scheduling light revert: %w", err)
+	}
+
+	w.lightRevertsMtx.Lock()
+	w.lightReverts[g.GetID()] = lightRevert{id: id, job: job, runAt: runAt, state: state}
+	w.lightRevertsMtx.Unlock()
+
+	w.savePendingJob(pkg.PendingJob{
+		ID:            id,
+		Type:          pkg.PendingJobLightRevert,
+		GardenID:      g.GetID(),
+		ScheduledTime: w.now(),
+		RunAt:         runAt,
+		LightState:    &state,
+	})
+	return nil
+}
+
+// executeLightRevert changes the light back unless the Garden was end-dated or deleted since it was scheduled
+func (w *Worker) executeLightRevert(g *pkg.Garden, id string, state pkg.LightState, logger *slog.Logger) {
+	scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Dec()
+	w.deletePendingJob(id)
+
+	w.lightRevertsMtx.Lock()
+	if w.lightReverts[g.GetID()].id == id {
+		delete(w.lightReverts, g.GetID())
+	}
+	w.lightRevertsMtx.Unlock()
+
+	if w.storageClient != nil {
+		garden, err := w.storageClient.Gardens.Get(context.Background(), g.GetID())
+		if err != nil || garden.EndDated() {
+			logger.Info("not reverting light for Garden that was removed", "error", err)
+			return
+		}
+		g = garden
+	}
+
+	logger.Info("reverting light")
+	err := w.ExecuteLightAction(g, &action.LightAction{State: state})
+	if err != nil {
+		logger.Error("error reverting light", "error", err)
+		schedulerErrors.WithLabelValues(gardenLabels(g)...).Inc()
+		return
+	}
+
+	w.addScheduledAuditEntry("garden", g.GetID(), "light_action", map[string]string{"state": state.String(), "revert": "true"})
+	w.sendLightActionNotification(g, state, logger)
+}
+
+// cancelLightRevert removes the Garden's pending light revert, if it has one. It is used when another LightAction
+// replaces the temporary state
+func (w *Worker) cancelLightRevert(g *pkg.Garden) {
+	w.lightRevertsMtx.Lock()
+	revert, ok := w.lightReverts[g.GetID()]
+	delete(w.lightReverts, g.GetID())
+	w.lightRevertsMtx.Unlock()
+
+	if !ok {
+		return
+	}
+
+	w.logger.Info("cancelling light revert", "garden_id", g.GetID())
+	w.scheduler.RemoveByReference(revert.job)
+	scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Dec()
+	w.deletePendingJob(revert.id)
+}
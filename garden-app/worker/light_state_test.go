@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordLightState(t *testing.T) {
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	w := NewWorker(nil, nil, nil, slog.Default())
+	w.SetClock(clock.NewVirtual(now))
+
+	g := createExampleGarden()
+	assert.Nil(t, w.LightState(g))
+
+	w.RecordLightState("test-garden", pkg.LightStateOn)
+
+	state := w.LightState(g)
+	require.NotNil(t, state)
+	assert.Equal(t, pkg.LightStateOn, state.State)
+	assert.True(t, now.Equal(state.UpdatedAt))
+}
+
+func TestLightActionForDuration(t *testing.T) {
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T, offMessage string) (*Worker, *mqtt.MockClient, *storage.Client, *pkg.Garden) {
+		storageClient, err := storage.NewClient(storage.Config{
+			Driver: "hashmap",
+		})
+		require.NoError(t, err)
+
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("LightTopic", "test-garden").Return("test-garden/action/light", nil)
+		if offMessage != "" {
+			mqttClient.On("Publish", "test-garden/action/light", []byte(offMessage)).Return(nil).Once()
+		}
+		mqttClient.On("Publish", "test-garden/action/light", mock.Anything).Return(nil)
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, storageClient, mqttClient, now)
+
+		g := createExampleGarden()
+		g.LightSchedule = nil
+		require.NoError(t, storageClient.Gardens.Set(context.Background(), g))
+
+		return w, mqttClient, storageClient, g
+	}
+
+	t.Run("RevertAfterDuration", func(t *testing.T) {
+		w, mqttClient, storageClient, g := setup(t, `{"state":"OFF","for_duration":null}`)
+
+		err := w.ExecuteGardenAction(g, &action.GardenAction{Light: &action.LightAction{
+			State:       pkg.LightStateOn,
+			ForDuration: &pkg.Duration{Duration: 30 * time.Minute},
+		}})
+		require.NoError(t, err)
+
+		revertTime, revertState, ok := w.LightRevert(g)
+		require.True(t, ok)
+		assert.True(t, now.Add(30*time.Minute).Equal(revertTime))
+		assert.Equal(t, pkg.LightStateOff, revertState)
+
+		pendingJobs, err := storageClient.PendingJobs.GetPendingJobs(context.Background())
+		require.NoError(t, err)
+		require.Len(t, pendingJobs, 1)
+		assert.Equal(t, pkg.PendingJobLightRevert, pendingJobs[0].Type)
+		require.NotNil(t, pendingJobs[0].LightState)
+		assert.Equal(t, pkg.LightStateOff, *pendingJobs[0].LightState)
+
+		_, err = w.AdvanceClock(31 * time.Minute)
+		require.NoError(t, err)
+
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+
+		_, _, ok = w.LightRevert(g)
+		assert.False(t, ok)
+
+		pendingJobs, err = storageClient.PendingJobs.GetPendingJobs(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, pendingJobs)
+	})
+
+	t.Run("RevertToPreviousState", func(t *testing.T) {
+		w, _, _, g := setup(t, "")
+		w.RecordLightState(g.TopicPrefix, pkg.LightStateOff)
+
+		err := w.ExecuteLightAction(g, &action.LightAction{
+			State:       pkg.LightStateToggle,
+			ForDuration: &pkg.Duration{Duration: 30 * time.Minute},
+		})
+		require.NoError(t, err)
+
+		_, revertState, ok := w.LightRevert(g)
+		require.True(t, ok)
+		assert.Equal(t, pkg.LightStateOff, revertState)
+	})
+
+	t.Run("NewLightActionCancelsRevert", func(t *testing.T) {
+		w, mqttClient, storageClient, g := setup(t, "")
+
+		err := w.ExecuteGardenAction(g, &action.GardenAction{Light: &action.LightAction{
+			State:       pkg.LightStateOn,
+			ForDuration: &pkg.Duration{Duration: 30 * time.Minute},
+		}})
+		require.NoError(t, err)
+
+		err = w.ExecuteGardenAction(g, &action.GardenAction{Light: &action.LightAction{State: pkg.LightStateOn}})
+		require.NoError(t, err)
+
+		_, _, ok := w.LightRevert(g)
+		assert.False(t, ok)
+
+		pendingJobs, err := storageClient.PendingJobs.GetPendingJobs(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, pendingJobs)
+
+		_, err = w.AdvanceClock(time.Hour)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+	})
+
+	t.Run("RestorePendingJob", func(t *testing.T) {
+		w, mqttClient, storageClient, g := setup(t, `{"state":"OFF","for_duration":null}`)
+
+		state := pkg.LightStateOff
+		require.NoError(t, storageClient.PendingJobs.SetPendingJob(context.Background(), pkg.PendingJob{
+			ID:         "revert",
+			Type:       pkg.PendingJobLightRevert,
+			GardenID:   g.GetID(),
+			RunAt:      now.Add(-time.Hour),
+			LightState: &state,
+		}))
+
+		// Missed light reverts run even though the default catch-up policy skips other jobs
+		require.NoError(t, w.RestorePendingJobs())
+
+		// The audit entry is added after the light is reverted
+		assert.Eventually(t, func() bool {
+			entries, err := storageClient.AuditLog.GetAuditEntries(context.Background(), "garden", g.GetID(), time.Time{}, 0)
+			return err == nil && len(entries) == 1
+		}, time.Second, 10*time.Millisecond)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+	})
+}
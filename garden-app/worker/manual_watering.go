@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+)
+
+// ManualWateringConfig configures how waterings started at a garden-controller, like with a button, are handled.
+// They are always recorded in the Zone's water history so they are included in water budgets
+type ManualWateringConfig struct {
+	// PublishEvents enables publishing a "water_action.manual" Event for each manual watering
+	PublishEvents bool `mapstructure:"publish_events"`
+}
+
+// SetManualWatering configures how manual waterings reported by garden-controllers are handled
+func (w *Worker) SetManualWatering(cfg ManualWateringConfig) {
+	w.settingsMtx.Lock()
+	defer w.settingsMtx.Unlock()
+	w.manualWatering = cfg
+}
+
+func (w *Worker) getManualWatering() ManualWateringConfig {
+	w.settingsMtx.RLock()
+	defer w.settingsMtx.RUnlock()
+	return w.manualWatering
+}
+
+// RecordManualWatering adds a watering that was started at the Zone's controller to its water history. The
+// controller reports it after watering finishes, so the history entry starts when it was received minus the
+// duration. measuredLiters is nil if the Zone does not have a flow meter
+func (w *Worker) RecordManualWatering(g *pkg.Garden, z *pkg.Zone, duration time.Duration, measuredLiters *float64) {
+	w.logger.Info("recording manual watering", "garden_id", g.GetID(), "zone_id", z.GetID(), "duration", duration)
+
+	history := pkg.WaterHistory{
+		Duration:       &pkg.Duration{Duration: duration},
+		RecordTime:     w.now().Add(-duration),
+		MeasuredLiters: measuredLiters,
+		Manual:         true,
+	}
+	if measuredLiters != nil {
+		w.RecordMeasuredLiters(z, *measuredLiters)
+	}
+	w.storeWaterHistory(g, z, history)
+
+	if w.getManualWatering().PublishEvents {
+		w.publishManualWaterEvent(g, z, duration)
+	}
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordManualWatering(t *testing.T) {
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+	liters := 0.5
+
+	tests := []struct {
+		name           string
+		cfg            ManualWateringConfig
+		measuredLiters *float64
+		expectEvent    bool
+	}{
+		{"NoEvent", ManualWateringConfig{}, nil, false},
+		{"PublishEvent", ManualWateringConfig{PublishEvents: true}, &liters, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			bus := events.NewBus()
+			subscriber, unsubscribe := bus.Subscribe()
+			defer unsubscribe()
+
+			w := NewWorker(storageClient, nil, nil, slog.Default())
+			w.SetClock(clock.NewVirtual(now))
+			w.SetEventBus(bus)
+			w.SetManualWatering(tt.cfg)
+
+			garden := createExampleGarden()
+			zone := createExampleZone()
+			w.RecordManualWatering(garden, zone, 15*time.Minute, tt.measuredLiters)
+
+			history, err := storageClient.WaterHistory.GetWaterHistory(context.Background(), zone.GetID(), time.Time{}, 0)
+			require.NoError(t, err)
+			require.Len(t, history, 1)
+			assert.True(t, history[0].Manual)
+			assert.Equal(t, 15*time.Minute, history[0].Duration.Duration)
+			assert.True(t, now.Add(-15*time.Minute).Equal(history[0].RecordTime))
+			assert.Equal(t, tt.measuredLiters, history[0].MeasuredLiters)
+
+			select {
+			case e := <-subscriber:
+				require.True(t, tt.expectEvent, "unexpected Event: %v", e)
+				assert.Equal(t, "water_action.manual", e.Type)
+				assert.Equal(t, WaterActionEvent{GardenID: garden.GetID(), ZoneID: zone.GetID(), Duration: "15m0s"}, e.Data)
+			default:
+				assert.False(t, tt.expectEvent, "expected water_action.manual Event")
+			}
+		})
+	}
+}
@@ -14,6 +14,12 @@ func (w *Worker) sendLightActionNotification(g *pkg.Garden, state pkg.LightState
 }
 
 func (w *Worker) sendNotification(title, msg string, logger *slog.Logger) {
+	// Notifications are not sent in simulation mode since they would go to real recipients
+	if w.clock.IsVirtual() {
+		logger.Debug("not sending notification with virtual clock", "title", title)
+		return
+	}
+
 	// TODO: this might end up getting client from garden or zone config instead of using all
 	notificationClients, err := w.storageClient.NotificationClientConfigs.GetAll(context.Background(), nil)
 	if err != nil {
@@ -0,0 +1,202 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/go-co-op/gocron"
+)
+
+// CatchUpPolicy decides what happens to PendingJobs that should have run while the server was not running
+type CatchUpPolicy string
+
+const (
+	// CatchUpSkip removes missed PendingJobs without running them. This is the default
+	CatchUpSkip CatchUpPolicy = "skip"
+	// CatchUpRunImmediately runs all missed PendingJobs when the server starts
+	CatchUpRunImmediately CatchUpPolicy = "run_immediately"
+	// CatchUpRunIfWithin runs missed PendingJobs when the server starts if they were missed by less than Within
+	CatchUpRunIfWithin CatchUpPolicy = "run_if_within"
+)
+
+// CatchUpConfig configures how PendingJobs that were missed while the server was down are handled when it starts
+type CatchUpConfig struct {
+	Policy CatchUpPolicy `mapstructure:"policy"`
+	// Within is how late a PendingJob can be and still run when using the "run_if_within" policy
+	Within time.Duration `mapstructure:"within"`
+}
+
+// Validate makes sure the Policy is valid and Within is set if it is needed
+func (c CatchUpConfig) Validate() error {
+	switch c.Policy {
+	case "", CatchUpSkip, CatchUpRunImmediately:
+	case CatchUpRunIfWithin:
+		if c.Within <= 0 {
+			return fmt.Errorf("catch_up.within must be positive when using the %q policy", CatchUpRunIfWithin)
+		}
+	default:
+		return fmt.Errorf("invalid catch_up.policy %q: must be one of [%s %s %s]", c.Policy, CatchUpSkip, CatchUpRunImmediately, CatchUpRunIfWithin)
+	}
+	return nil
+}
+
+// shouldRun decides if a PendingJob that was supposed to run at runAt should still run at now
+func (c CatchUpConfig) shouldRun(runAt, now time.Time) bool {
+	switch c.Policy {
+	case CatchUpRunImmediately:
+		return true
+	case CatchUpRunIfWithin:
+		return now.Sub(runAt) <= c.Within
+	default:
+		return false
+	}
+}
+
+// SetCatchUp configures how RestorePendingJobs handles PendingJobs that were missed while the server was down
+func (w *Worker) SetCatchUp(cfg CatchUpConfig) {
+	w.settingsMtx.Lock()
+	defer w.settingsMtx.Unlock()
+	w.catchUp = cfg
+}
+
+// RestorePendingJobs schedules the PendingJobs saved in storage before the server was restarted. Jobs that should
+// have already run are handled using the CatchUpConfig. It should be used once when starting, after the
+// WaterSchedules are scheduled
+func (w *Worker) RestorePendingJobs() error {
+	jobs, err := w.storageClient.PendingJobs.GetPendingJobs(context.Background())
+	if err != nil {
+		return fmt.Errorf("error getting pending jobs: %w", err)
+	}
+
+	w.settingsMtx.RLock()
+	catchUp := w.catchUp
+	w.settingsMtx.RUnlock()
+
+	now := w.now()
+	for _, job := range jobs {
+		logger := w.logger.With("pending_job_id", job.ID, "type", job.Type, "zone_id", job.ZoneID, "run_at", job.RunAt)
+
+		if job.RunAt.Before(now) {
+			// Light reverts always run so a temporary light state is not kept until the LightSchedule changes it
+			if !catchUp.shouldRun(job.RunAt, now) && job.Type != pkg.PendingJobLightRevert {
+				logger.Info("skipping pending job that was missed while the server was not running")
+				w.deletePendingJob(job.ID)
+				w.addScheduledAuditEntry("zone", job.ZoneID, "pending_job_skipped", map[string]string{
+					"type":   string(job.Type),
+					"run_at": job.RunAt.String(),
+				})
+				continue
+			}
+			logger.Info("running pending job that was missed while the server was not running")
+			job.RunAt = now
+		}
+
+		err = w.restorePendingJob(job)
+		if err != nil {
+			logger.Error("unable to restore pending job", "error", err)
+			w.deletePendingJob(job.ID)
+			continue
+		}
+		logger.Debug("restored pending job")
+	}
+
+	return nil
+}
+
+// restorePendingJob gets the PendingJob's resources from storage and schedules it again
+func (w *Worker) restorePendingJob(job pkg.PendingJob) error {
+	ctx := context.Background()
+
+	garden, err := w.storageClient.Gardens.Get(ctx, job.GardenID)
+	if err != nil {
+		return fmt.Errorf("error getting Garden: %w", err)
+	}
+
+	// Light reverts only need the Garden
+	if job.Type == pkg.PendingJobLightRevert {
+		if job.LightState == nil {
+			return errors.New("missing light state for light revert")
+		}
+		return w.scheduleLightRevertJob(garden, job.ID, *job.LightState, job.RunAt)
+	}
+
+	zone, err := w.storageClient.Zones.Get(ctx, job.ZoneID)
+	if err != nil {
+		return fmt.Errorf("error getting Zone: %w", err)
+	}
+
+	switch job.Type {
+	case pkg.PendingJobDeferredWatering:
+		ws, err := w.storageClient.WaterSchedules.Get(ctx, job.WaterScheduleID)
+		if err != nil {
+			return fmt.Errorf("error getting WaterSchedule: %w", err)
+		}
+		return w.scheduleDeferredWatering(garden, zone, ws, job.ScheduledTime, job.RunAt)
+	case pkg.PendingJobWaterCycle:
+		if job.Duration == nil {
+			return errors.New("missing duration for water cycle")
+		}
+		pulse := &action.WaterAction{Duration: job.Duration, Fertilizer: job.Fertilizer}
+		return w.scheduleWaterCycle(garden, zone, pulse, job.ID, job.RunAt, w.logger.With("garden_id", garden.GetID(), "zone_id", zone.GetID()))
+	default:
+		return fmt.Errorf("unknown pending job type %q", job.Type)
+	}
+}
+
+// scheduleOnce starts a one-time Job that runs at startAt. If startAt is not in the future, it runs as soon as the
+// scheduler is running
+func (w *Worker) scheduleOnce(startAt time.Time) *gocron.Scheduler {
+	scheduler := w.scheduler.Every(1).Day() // Every is required even though it's not needed for these Jobs
+	if startAt.After(w.now()) {
+		scheduler = scheduler.StartAt(startAt)
+	}
+	return scheduler.LimitRunsTo(1)
+}
+
+// savePendingJob saves the PendingJob so it is restored if the server restarts before it runs. Errors are only
+// logged since the Job is already scheduled and will still run unless the server restarts
+func (w *Worker) savePendingJob(job pkg.PendingJob) {
+	if w.storageClient == nil {
+		return
+	}
+
+	err := w.storageClient.PendingJobs.SetPendingJob(context.Background(), job)
+	if err != nil {
+		w.logger.Error("unable to save pending job", "pending_job_id", job.ID, "error", err)
+	}
+}
+
+// deletePendingJob removes the PendingJob after it runs or is cancelled
+func (w *Worker) deletePendingJob(id string) {
+	if w.storageClient == nil {
+		return
+	}
+
+	err := w.storageClient.PendingJobs.DeletePendingJob(context.Background(), id)
+	if err != nil {
+		w.logger.Error("unable to delete pending job", "pending_job_id", id, "error", err)
+	}
+}
+
+// deletePendingJobs removes the PendingJobs that match, like when they are cancelled
+func (w *Worker) deletePendingJobs(match func(pkg.PendingJob) bool) {
+	if w.storageClient == nil {
+		return
+	}
+
+	jobs, err := w.storageClient.PendingJobs.GetPendingJobs(context.Background())
+	if err != nil {
+		w.logger.Error("unable to get pending jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if match(job) {
+			w.deletePendingJob(job.ID)
+		}
+	}
+}
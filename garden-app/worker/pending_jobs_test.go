@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatchUpConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      CatchUpConfig
+		expectedErr string
+	}{
+		{"Empty", CatchUpConfig{}, ""},
+		{"Skip", CatchUpConfig{Policy: CatchUpSkip}, ""},
+		{"RunImmediately", CatchUpConfig{Policy: CatchUpRunImmediately}, ""},
+		{"RunIfWithin", CatchUpConfig{Policy: CatchUpRunIfWithin, Within: time.Hour}, ""},
+		{
+			"RunIfWithinMissingWithin",
+			CatchUpConfig{Policy: CatchUpRunIfWithin},
+			`catch_up.within must be positive when using the "run_if_within" policy`,
+		},
+		{
+			"InvalidPolicy",
+			CatchUpConfig{Policy: "later"},
+			`invalid catch_up.policy "later": must be one of [skip run_immediately run_if_within]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestDeferredWateringPendingJob(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	garden := createExampleGarden()
+	garden.TimeZone = "UTC"
+	garden.BlackoutWindows = []pkg.BlackoutWindow{{StartTime: "10:00", EndTime: "16:00"}}
+	zone := createExampleZone()
+	ws := createExampleWaterSchedule()
+
+	require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+	require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+	require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", mock.Anything).Return("test-garden/action/water", nil)
+	mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil)
+	mqttClient.On("Disconnect", uint(100)).Return()
+
+	now := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+	w := startTestWorker(t, storageClient, mqttClient, now)
+
+	require.NoError(t, w.ExecuteScheduledWaterAction(garden, zone, ws))
+
+	jobs, err := storageClient.PendingJobs.GetPendingJobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, pkg.PendingJobDeferredWatering, jobs[0].Type)
+	assert.Equal(t, garden.GetID(), jobs[0].GardenID)
+	assert.Equal(t, zone.GetID(), jobs[0].ZoneID)
+	assert.Equal(t, ws.GetID(), jobs[0].WaterScheduleID)
+	assert.True(t, now.Equal(jobs[0].ScheduledTime))
+	assert.True(t, time.Date(2023, time.June, 1, 16, 0, 0, 0, time.UTC).Equal(jobs[0].RunAt))
+
+	t.Run("DeletedAfterRunning", func(t *testing.T) {
+		_, err := w.AdvanceClock(5 * time.Hour)
+		require.NoError(t, err)
+
+		jobs, err := storageClient.PendingJobs.GetPendingJobs(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, jobs)
+		mqttClient.AssertCalled(t, "Publish", "test-garden/action/water", mock.Anything)
+	})
+}
+
+func TestRestorePendingJobs(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		catchUp       CatchUpConfig
+		runAt         time.Time
+		expectRestore bool
+		expectWater   bool
+	}{
+		{"Future", CatchUpConfig{}, now.Add(time.Hour), true, false},
+		{"MissedDefaultSkips", CatchUpConfig{}, now.Add(-time.Hour), false, false},
+		{"MissedRunImmediately", CatchUpConfig{Policy: CatchUpRunImmediately}, now.Add(-24 * time.Hour), true, true},
+		{"MissedWithin", CatchUpConfig{Policy: CatchUpRunIfWithin, Within: 2 * time.Hour}, now.Add(-time.Hour), true, true},
+		{"MissedNotWithin", CatchUpConfig{Policy: CatchUpRunIfWithin, Within: 30 * time.Minute}, now.Add(-time.Hour), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			garden := createExampleGarden()
+			zone := createExampleZone()
+			require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+			require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+			require.NoError(t, storageClient.PendingJobs.SetPendingJob(context.Background(), pkg.PendingJob{
+				ID:       "CYCLE_1",
+				Type:     pkg.PendingJobWaterCycle,
+				GardenID: garden.GetID(),
+				ZoneID:   zone.GetID(),
+				RunAt:    tt.runAt,
+				Duration: &pkg.Duration{Duration: time.Second},
+			}))
+
+			mqttClient := new(mqtt.MockClient)
+			mqttClient.On("WaterTopic", mock.Anything).Return("test-garden/action/water", nil)
+			mqttClient.On("Publish", "test-garden/action/water", []byte(`{"duration":1000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil)
+			mqttClient.On("Disconnect", uint(100)).Return()
+
+			w := NewWorker(storageClient, nil, mqttClient, slog.Default())
+			w.SetClock(clock.NewVirtual(now))
+			w.SetCatchUp(tt.catchUp)
+
+			require.NoError(t, w.RestorePendingJobs())
+
+			cycleJobs, _ := w.scheduler.FindJobsByTag(zone.GetID(), cycleTag)
+			if !tt.expectRestore {
+				assert.Empty(t, cycleJobs)
+
+				jobs, err := storageClient.PendingJobs.GetPendingJobs(context.Background())
+				require.NoError(t, err)
+				assert.Empty(t, jobs)
+				return
+			}
+			require.Len(t, cycleJobs, 1)
+
+			w.StartAsync()
+
+			if !tt.expectWater {
+				assert.True(t, tt.runAt.Equal(cycleJobs[0].NextRun()))
+
+				w.Stop()
+				mqttClient.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+
+				jobs, err := storageClient.PendingJobs.GetPendingJobs(context.Background())
+				require.NoError(t, err)
+				assert.Len(t, jobs, 1)
+				return
+			}
+
+			assert.Eventually(t, func() bool {
+				history, err := storageClient.WaterHistory.GetWaterHistory(context.Background(), zone.GetID(), time.Time{}, 0)
+				return err == nil && len(history) == 1
+			}, time.Second, 10*time.Millisecond)
+
+			w.Stop()
+			mqttClient.AssertExpectations(t)
+
+			jobs, err := storageClient.PendingJobs.GetPendingJobs(context.Background())
+			require.NoError(t, err)
+			assert.Empty(t, jobs)
+		})
+	}
+
+	t.Run("MissingZoneIsRemoved", func(t *testing.T) {
+		storageClient, err := storage.NewClient(storage.Config{
+			Driver: "hashmap",
+		})
+		require.NoError(t, err)
+
+		garden := createExampleGarden()
+		require.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+		require.NoError(t, storageClient.PendingJobs.SetPendingJob(context.Background(), pkg.PendingJob{
+			ID:       "CYCLE_1",
+			Type:     pkg.PendingJobWaterCycle,
+			GardenID: garden.GetID(),
+			ZoneID:   "missing",
+			RunAt:    now.Add(time.Hour),
+			Duration: &pkg.Duration{Duration: time.Second},
+		}))
+
+		w := NewWorker(storageClient, nil, nil, slog.Default())
+		w.SetClock(clock.NewVirtual(now))
+		require.NoError(t, w.RestorePendingJobs())
+
+		jobs, err := storageClient.PendingJobs.GetPendingJobs(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, jobs)
+	})
+}
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/go-co-op/gocron"
+)
+
+const recirculationTag = "RECIRCULATION"
+
+// ScheduleRecirculation turns on a hydroponic Garden's recirculation pump now and schedules Jobs to repeat its
+// duty cycle. The ON Job runs every OnDuration+OffDuration and the OFF Job runs OnDuration after it. The Jobs are
+// tagged with the Garden's ID like the LightSchedule Jobs, so they are removed with RemoveJobsByID
+func (w *Worker) ScheduleRecirculation(g *pkg.Garden) error {
+	logger := w.contextLogger(g, nil, nil)
+	logger.Info("creating scheduled Jobs for recirculation", "recirculation_schedule", *g.RecirculationSchedule)
+
+	onDuration := g.RecirculationSchedule.OnDuration.Duration
+	period := onDuration + g.RecirculationSchedule.OffDuration.Duration
+
+	err := w.ExecuteRecirculationAction(g, &action.RecirculationAction{State: pkg.LightStateOn})
+	if err != nil {
+		return err
+	}
+
+	now := w.now()
+	startTimes := []struct {
+		state   pkg.LightState
+		startAt time.Time
+	}{
+		{pkg.LightStateOn, now.Add(period)},
+		{pkg.LightStateOff, now.Add(onDuration)},
+	}
+	for _, st := range startTimes {
+		scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Inc()
+		_, err = w.scheduler.
+			Every(period).
+			StartAt(st.startAt).
+			Tag("garden").
+			Tag(g.ID.String()).
+			Tag(recirculationTag).
+			// the state is prefixed so these Jobs are not found as LightSchedule Jobs
+			Tag(fmt.Sprintf("%s_%s", recirculationTag, st.state)).
+			Do(w.executeRecirculationActionInScheduledJob, g, &action.RecirculationAction{State: st.state}, logger.With("source", "recirculation_job"))
+		if err != nil {
+			scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Dec()
+			return fmt.Errorf("error scheduling recirculation %s Job: %w", st.state, err)
+		}
+	}
+	return nil
+}
+
+// ResetRecirculationSchedule removes the Garden's recirculation Jobs and schedules them again if the Garden still
+// uses recirculation. ResetLightSchedule removes all of the Garden's Jobs, so this is called after it
+func (w *Worker) ResetRecirculationSchedule(g *pkg.Garden) error {
+	logger := w.contextLogger(g, nil, nil)
+	logger.Debug("resetting RecirculationSchedule")
+
+	err := w.removeRecirculationJobs(g)
+	if err != nil {
+		return err
+	}
+
+	if !g.HasRecirculation() || g.EndDated() {
+		return nil
+	}
+	return w.ScheduleRecirculation(g)
+}
+
+// ExecuteRecirculationAction sends the RecirculationAction to the Garden's controller to turn the recirculation pump
+// on or off
+func (w *Worker) ExecuteRecirculationAction(g *pkg.Garden, input *action.RecirculationAction) (err error) {
+	defer func() { _ = recordAction("recirculation", g.GetID(), err) }()
+
+	return w.controller(g).recirculation(input)
+}
+
+// executeRecirculationActionInScheduledJob executes the RecirculationAction and only logs errors since the Job repeats
+func (w *Worker) executeRecirculationActionInScheduledJob(g *pkg.Garden, input *action.RecirculationAction, actionLogger *slog.Logger) {
+	actionLogger = actionLogger.With("state", input.State.String())
+	actionLogger.Debug("executing RecirculationAction")
+	err := w.ExecuteRecirculationAction(g, input)
+	if err != nil {
+		actionLogger.Error("error executing scheduled RecirculationAction", "error", err)
+		schedulerErrors.WithLabelValues(gardenLabels(g)...).Inc()
+	}
+}
+
+// removeRecirculationJobs removes only the Garden's recirculation Jobs so its LightSchedule Jobs are not affected
+func (w *Worker) removeRecirculationJobs(g *pkg.Garden) error {
+	jobs, err := w.scheduler.FindJobsByTag(g.ID.String(), recirculationTag)
+	if err != nil {
+		if errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+			return nil
+		}
+		return err
+	}
+	for _, job := range jobs {
+		w.scheduler.RemoveByReference(job)
+		scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Dec()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetRecirculationSchedule(t *testing.T) {
+	onMessage := []byte(`{"state":"ON"}`)
+	offMessage := []byte(`{"state":"OFF"}`)
+
+	createHydroponicGarden := func() *pkg.Garden {
+		g := createExampleGarden()
+		g.Type = pkg.GardenTypeHydroponic
+		g.RecirculationSchedule = &pkg.RecirculationSchedule{
+			OnDuration:  &pkg.Duration{Duration: 15 * time.Minute},
+			OffDuration: &pkg.Duration{Duration: 45 * time.Minute},
+		}
+		return g
+	}
+
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	t.Run("RepeatsDutyCycle", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("RecirculationTopic", "test-garden").Return("test-garden/command/recirculation", nil)
+		mqttClient.On("Publish", "test-garden/command/recirculation", onMessage).Return(nil)
+		mqttClient.On("Publish", "test-garden/command/recirculation", offMessage).Return(nil)
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		require.NoError(t, w.ResetRecirculationSchedule(createHydroponicGarden()))
+		// the pump is turned on right away
+		mqttClient.AssertCalled(t, "Publish", "test-garden/command/recirculation", onMessage)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+
+		_, err := w.AdvanceClock(15 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertCalled(t, "Publish", "test-garden/command/recirculation", offMessage)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+
+		_, err = w.AdvanceClock(45 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 3)
+
+		_, err = w.AdvanceClock(time.Hour)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 5)
+	})
+
+	t.Run("DoesNotRemoveLightSchedule", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("RecirculationTopic", "test-garden").Return("test-garden/command/recirculation", nil)
+		mqttClient.On("Publish", "test-garden/command/recirculation", onMessage).Return(nil)
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		g := createHydroponicGarden()
+		require.NoError(t, w.ScheduleLightActions(g))
+		nextOnTime := w.GetNextLightTime(g, pkg.LightStateOn)
+		require.NotNil(t, nextOnTime)
+
+		require.NoError(t, w.ResetRecirculationSchedule(g))
+		require.NoError(t, w.ResetRecirculationSchedule(g))
+
+		assert.Equal(t, nextOnTime, w.GetNextLightTime(g, pkg.LightStateOn))
+		jobs, err := w.scheduler.FindJobsByTag(g.GetID(), recirculationTag)
+		require.NoError(t, err)
+		assert.Len(t, jobs, 2)
+	})
+
+	t.Run("RemovedWhenGardenIsNotHydroponic", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("RecirculationTopic", "test-garden").Return("test-garden/command/recirculation", nil)
+		mqttClient.On("Publish", "test-garden/command/recirculation", onMessage).Return(nil).Once()
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		g := createHydroponicGarden()
+		require.NoError(t, w.ResetRecirculationSchedule(g))
+
+		g.Type = pkg.GardenTypeSoil
+		require.NoError(t, w.ResetRecirculationSchedule(g))
+
+		_, err := w.scheduler.FindJobsByTag(g.GetID(), recirculationTag)
+		assert.Error(t, err)
+
+		_, err = w.AdvanceClock(2 * time.Hour)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+	})
+}
@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+)
+
+const reminderTag = "reminder"
+
+// ScheduleReminder schedules a Job that sends a notification when the Reminder is due. A Reminder that does not
+// repeat and has already been sent is not scheduled
+func (w *Worker) ScheduleReminder(r *pkg.Reminder) error {
+	logger := w.logger.With("reminder_id", r.GetID())
+
+	next := r.NextTime(w.now())
+	if next == nil {
+		logger.Debug("not scheduling Reminder since it has no upcoming time")
+		return nil
+	}
+	logger.Info("creating scheduled Job for Reminder", "next_time", next.String())
+
+	// Every is required even though it's not needed for a Reminder that does not repeat
+	scheduler := w.scheduler.Every(1).Day().LimitRunsTo(1)
+	if r.Repeats() {
+		scheduler = w.scheduler.Every(r.Interval.Duration)
+	}
+
+	scheduleJobsGauge.WithLabelValues(reminderTag, r.GetID()).Inc()
+	_, err := scheduler.
+		StartAt(*next).
+		Tag(reminderTag).
+		Tag(r.GetID()).
+		Do(func(jobLogger *slog.Logger) {
+			if !r.Repeats() {
+				scheduleJobsGauge.WithLabelValues(reminderTag, r.GetID()).Dec()
+			}
+			w.sendReminder(r.GetID(), jobLogger)
+		}, logger.With("source", "scheduled_job"))
+	return err
+}
+
+// ResetReminder removes the Reminder's existing Job and schedules it again unless it is end-dated
+func (w *Worker) ResetReminder(r *pkg.Reminder) error {
+	err := w.RemoveJobsByID(r.GetID())
+	if err != nil {
+		return fmt.Errorf("error removing Reminder Job: %w", err)
+	}
+	if r.EndDated() {
+		return nil
+	}
+	return w.ScheduleReminder(r)
+}
+
+// sendReminder gets the latest Reminder from storage and sends it as a notification
+func (w *Worker) sendReminder(id string, logger *slog.Logger) {
+	err := func() error {
+		r, err := w.storageClient.Reminders.Get(context.Background(), id)
+		if err != nil {
+			return fmt.Errorf("error getting Reminder when executing scheduled Job: %w", err)
+		}
+		if r.EndDated() {
+			logger.Info("skipping Reminder because it is end-dated")
+			return nil
+		}
+
+		title, msg, err := w.reminderMessage(r)
+		if err != nil {
+			return err
+		}
+
+		w.sendNotification(title, msg, logger)
+		return nil
+	}()
+	if err != nil {
+		logger.Error("error sending Reminder", "error", err)
+		schedulerErrors.WithLabelValues(reminderTag, id).Inc()
+	}
+}
+
+// reminderMessage creates the notification title and message for a Reminder using the names of its Garden, Zone,
+// and Plant
+func (w *Worker) reminderMessage(r *pkg.Reminder) (string, string, error) {
+	ctx := context.Background()
+
+	g, err := w.storageClient.Gardens.Get(ctx, r.GardenID.String())
+	if err != nil {
+		return "", "", fmt.Errorf("error getting Garden for Reminder: %w", err)
+	}
+
+	subject := g.Name
+	if r.ZoneID != nil {
+		z, err := w.storageClient.Zones.Get(ctx, r.ZoneID.String())
+		switch {
+		case errors.Is(err, babyapi.ErrNotFound):
+		case err != nil:
+			return "", "", fmt.Errorf("error getting Zone for Reminder: %w", err)
+		default:
+			subject = z.Name
+		}
+	}
+	if r.PlantID != nil {
+		p, err := w.storageClient.Plants.Get(ctx, r.PlantID.String())
+		switch {
+		case errors.Is(err, babyapi.ErrNotFound):
+		case err != nil:
+			return "", "", fmt.Errorf("error getting Plant for Reminder: %w", err)
+		default:
+			subject = p.Name
+		}
+	}
+
+	title := fmt.Sprintf("%s: %s Reminder", g.Name, strings.ToUpper(string(r.Type[:1]))+string(r.Type[1:]))
+	msg := fmt.Sprintf("Time to %s %s", r.Type, subject)
+	if r.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, r.Message)
+	}
+	return title, msg, nil
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications/fake"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendReminder(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	err = storageClient.NotificationClientConfigs.Set(context.Background(), &notifications.Client{
+		ID:      babyapi.NewID(),
+		Name:    "TestClient",
+		Type:    "fake",
+		Options: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	garden := createExampleGarden()
+	err = storageClient.Gardens.Set(context.Background(), garden)
+	require.NoError(t, err)
+
+	zone := createExampleZone()
+	err = storageClient.Zones.Set(context.Background(), zone)
+	require.NoError(t, err)
+
+	plant := &pkg.Plant{ID: babyapi.NewID(), Name: "tomato", GardenID: garden.ID.ID, ZoneID: zone.ID.ID}
+	err = storageClient.Plants.Set(context.Background(), plant)
+	require.NoError(t, err)
+
+	start := time.Now()
+	endDate := start.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		reminder *pkg.Reminder
+		expected fake.Message
+	}{
+		{
+			"Garden",
+			&pkg.Reminder{Type: pkg.ReminderTypeFertilize},
+			fake.Message{Title: "test-garden: Fertilize Reminder", Message: "Time to fertilize test-garden"},
+		},
+		{
+			"Zone",
+			&pkg.Reminder{Type: pkg.ReminderTypePrune, ZoneID: &zone.ID.ID, Message: "remove dead branches"},
+			fake.Message{Title: "test-garden: Prune Reminder", Message: "Time to prune test zone: remove dead branches"},
+		},
+		{
+			"Plant",
+			&pkg.Reminder{Type: pkg.ReminderTypeHarvest, ZoneID: &zone.ID.ID, PlantID: &plant.ID.ID},
+			fake.Message{Title: "test-garden: Harvest Reminder", Message: "Time to harvest tomato"},
+		},
+		{
+			"EndDated",
+			&pkg.Reminder{Type: pkg.ReminderTypeRepot, EndDate: &endDate},
+			fake.Message{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.ResetLastMessage()
+
+			tt.reminder.ID = babyapi.NewID()
+			tt.reminder.GardenID = garden.ID.ID
+			tt.reminder.StartDate = &start
+			err := storageClient.Reminders.Set(context.Background(), tt.reminder)
+			require.NoError(t, err)
+
+			w := NewWorker(storageClient, nil, nil, slog.Default())
+			w.sendReminder(tt.reminder.GetID(), w.logger)
+
+			assert.Equal(t, tt.expected, fake.LastMessage())
+		})
+	}
+}
+
+func TestScheduleReminder(t *testing.T) {
+	now := time.Now()
+	tomorrow := now.Add(24 * time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		reminder *pkg.Reminder
+		nextRun  *time.Time
+	}{
+		{
+			"OneTime",
+			&pkg.Reminder{StartDate: &tomorrow},
+			&tomorrow,
+		},
+		{
+			"OneTimeAlreadySent",
+			&pkg.Reminder{StartDate: &yesterday},
+			nil,
+		},
+		{
+			"Repeating",
+			&pkg.Reminder{StartDate: &yesterday, Interval: &pkg.Duration{Duration: 48 * time.Hour}},
+			&tomorrow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.reminder.ID = babyapi.NewID()
+
+			w := NewWorker(nil, nil, nil, slog.Default())
+			w.StartAsync()
+			defer w.Stop()
+
+			err := w.ScheduleReminder(tt.reminder)
+			require.NoError(t, err)
+
+			jobs, err := w.scheduler.FindJobsByTag(tt.reminder.GetID())
+			if tt.nextRun == nil {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			assert.WithinDuration(t, *tt.nextRun, jobs[0].NextRun(), time.Second)
+
+			err = w.ResetReminder(&pkg.Reminder{ID: tt.reminder.ID, EndDate: &yesterday})
+			require.NoError(t, err)
+
+			_, err = w.scheduler.FindJobsByTag(tt.reminder.GetID())
+			assert.Error(t, err)
+		})
+	}
+}
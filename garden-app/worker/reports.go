@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/reports"
+	"github.com/calvinmclean/babyapi"
+)
+
+const (
+	reportDigestTag  = "report_digest"
+	reportDigestTime = "08:00"
+)
+
+// ScheduleReportDigest schedules a Job on the first day of each month that sends the previous month's water
+// usage and cost report for each Garden with pricing configured
+func (w *Worker) ScheduleReportDigest() error {
+	logger := w.logger.With("source", "scheduled_job")
+	logger.Info("creating scheduled Job for monthly report digest")
+
+	_, err := w.scheduler.
+		Every(1).
+		Month(1).
+		At(reportDigestTime).
+		Tag(reportDigestTag).
+		Do(w.sendReportDigest, logger)
+	return err
+}
+
+// sendReportDigest generates reports for the previous month and sends them as notifications
+func (w *Worker) sendReportDigest(logger *slog.Logger) {
+	logger.Info("sending monthly report digest")
+
+	gardens, err := w.storageClient.Gardens.GetAll(context.Background(), babyapi.EndDatedQueryParam(false))
+	if err != nil {
+		logger.Error("error getting Gardens for report digest", "error", err)
+		schedulerErrors.WithLabelValues(reportDigestTag, "").Inc()
+		return
+	}
+
+	zones, err := w.storageClient.Zones.GetAll(context.Background(), babyapi.EndDatedQueryParam(true))
+	if err != nil {
+		logger.Error("error getting Zones for report digest", "error", err)
+		schedulerErrors.WithLabelValues(reportDigestTag, "").Inc()
+		return
+	}
+
+	for _, g := range gardens {
+		if g.Pricing == nil {
+			continue
+		}
+
+		gardenLogger := logger.With("garden_id", g.GetID())
+		gardenZones := babyapi.FilterFunc[*pkg.Zone](func(z *pkg.Zone) bool {
+			return z.GardenID == g.ID.ID
+		}).Filter(zones)
+
+		// Generate two months of reports since the first one is the previous month that just ended
+		ctx, cancel := context.WithTimeout(context.Background(), influxdb.QueryTimeout)
+		report, err := reports.Generate(ctx, w.influxdbClient, g, gardenZones, w.now(), 2)
+		cancel()
+		if err != nil {
+			gardenLogger.Error("error generating report for digest", "error", err)
+			schedulerErrors.WithLabelValues(gardenLabels(g)...).Inc()
+			continue
+		}
+
+		w.sendNotification(fmt.Sprintf("%s: Monthly Water Report", g.Name), report.Months[0].Summary(report.Currency), gardenLogger)
+	}
+}
@@ -5,17 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/babyapi"
 	"github.com/go-co-op/gocron"
+	"github.com/robfig/cron/v3"
 )
 
 const (
 	lightInterval = 24 * time.Hour
 	adhocTag      = "ADHOC"
+
+	// nextWaterTimesHorizon limits how far ahead GetNextWaterTimes looks so WaterSchedules with short
+	// ActivePeriods do not require too many iterations
+	nextWaterTimesHorizon = 366 * 24 * time.Hour
 )
 
 // sortableJobs is a type that makes a slice of gocron Jobs sortable
@@ -41,12 +48,30 @@ func (w *Worker) ScheduleWaterAction(waterSchedule *pkg.WaterSchedule) error {
 	logger := w.contextLogger(nil, nil, waterSchedule)
 	logger.Info("creating scheduled Job for WaterSchedule")
 
-	startTime := waterSchedule.StartTime.Time.UTC()
+	return w.scheduleWaterAction(waterSchedule, w.waterScheduleLocation(waterSchedule, nil))
+}
+
+// scheduleWaterAction schedules the WaterSchedule's Job. If loc is set, StartTime is used as the local time in that
+// time zone instead of using its offset
+func (w *Worker) scheduleWaterAction(waterSchedule *pkg.WaterSchedule, loc *time.Location) error {
+	logger := w.contextLogger(nil, nil, waterSchedule)
+	if loc != nil {
+		logger.Debug("scheduling WaterSchedule in Garden's time zone", "time_zone", loc.String())
+	}
+
+	// A cron expression controls the time, so StartAt is not used since it would add a run at the StartTime
+	scheduler := waterSchedule.Interval.SchedulerFunc(w.scheduler, loc)
+	if !waterSchedule.HasCronInterval() {
+		startTime := waterSchedule.StartTime.Time.UTC()
+		if loc != nil {
+			startTime = inLocation(waterSchedule.StartTime.Time, loc)
+		}
+		scheduler = scheduler.StartAt(w.timeAtDate(waterSchedule.StartDate, startTime))
+	}
 
 	// Schedule the WaterAction execution
 	scheduleJobsGauge.WithLabelValues(waterScheduleLabels(waterSchedule)...).Inc()
-	_, err := waterSchedule.Interval.SchedulerFunc(w.scheduler).
-		StartAt(timeAtDate(waterSchedule.StartDate, startTime)).
+	_, err := scheduler.
 		Tag("water_schedule").
 		Tag(waterSchedule.ID.String()).
 		Do(func(jobLogger *slog.Logger) {
@@ -60,8 +85,34 @@ func (w *Worker) ScheduleWaterAction(waterSchedule *pkg.WaterSchedule) error {
 					return errors.New("WaterSchedule not found")
 				}
 
-				if !ws.IsActive(time.Now()) {
-					jobLogger.Info("skipping WaterSchedule because current time is outside of ActivePeriod", "active_period", *ws.ActivePeriod)
+				if ws.EndDatedAt(w.now()) {
+					jobLogger.Info("skipping WaterSchedule because it is end-dated")
+					return nil
+				}
+
+				if ws.Paused {
+					jobLogger.Info("skipping WaterSchedule because it is paused")
+					return nil
+				}
+
+				if ws.NotStartedAt(w.now()) {
+					jobLogger.Info("skipping WaterSchedule because it is before the start_date")
+					return nil
+				}
+
+				if !ws.IsActive(w.now()) {
+					jobLogger.Info("skipping WaterSchedule because current time is outside of ActivePeriod", "active_period", ws.ActivePeriod.String())
+					return nil
+				}
+
+				if ws.SkipCount > 0 {
+					ws.SkipCount--
+					err = w.storageClient.WaterSchedules.Set(context.Background(), ws)
+					if err != nil {
+						return fmt.Errorf("unable to save WaterSchedule after decrementing SkipCount: %w", err)
+					}
+
+					jobLogger.Info("skipping WaterSchedule because of SkipCount", "remaining_skip_count", ws.SkipCount)
 					return nil
 				}
 
@@ -100,6 +151,139 @@ func (w *Worker) ResetWaterSchedule(ws *pkg.WaterSchedule) error {
 	return w.ScheduleWaterAction(ws)
 }
 
+// PauseWaterSchedule removes the WaterSchedule's Job and cancels its deferred waterings. The WaterSchedule is
+// scheduled again by ResetWaterSchedule when it is resumed
+func (w *Worker) PauseWaterSchedule(ws *pkg.WaterSchedule) error {
+	logger := w.contextLogger(nil, nil, ws)
+	logger.Info("pausing WaterSchedule")
+
+	if err := w.RemoveJobsByID(ws.ID.String()); err != nil {
+		return err
+	}
+
+	cancelled := w.cancelDeferredWaterings(func(dw DeferredWatering) bool {
+		return dw.WaterScheduleID == ws.ID.String()
+	})
+	if cancelled > 0 {
+		logger.Info("cancelled deferred waterings for paused WaterSchedule", "count", cancelled)
+	}
+	return nil
+}
+
+// ResetWaterSchedulesForGarden reschedules the WaterSchedules used by the Garden's Zones so they use its TimeZone.
+// It is used before an updated Garden is saved, so the provided Garden is used instead of the stored one
+func (w *Worker) ResetWaterSchedulesForGarden(g *pkg.Garden) error {
+	zones, err := w.storageClient.Zones.GetAll(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("error getting Zones: %w", err)
+	}
+
+	waterScheduleIDs := []string{}
+	for _, z := range zones {
+		if z.GardenID != g.ID.ID || z.EndDated() {
+			continue
+		}
+		for _, id := range z.WaterScheduleIDs {
+			waterScheduleIDs = append(waterScheduleIDs, id.String())
+		}
+	}
+
+	return w.resetWaterSchedules(waterScheduleIDs, &pkg.ZoneAndGarden{Garden: g})
+}
+
+// ResetWaterSchedulesForZone reschedules the WaterSchedules that the Zone uses, or used before this update, since
+// their time zone depends on the Gardens of the Zones using them. It is used before an updated Zone is saved, so the
+// provided Zone is used instead of the stored one
+func (w *Worker) ResetWaterSchedulesForZone(g *pkg.Garden, z *pkg.Zone) error {
+	waterScheduleIDs := []string{}
+	for _, id := range z.WaterScheduleIDs {
+		waterScheduleIDs = append(waterScheduleIDs, id.String())
+	}
+
+	existing, err := w.storageClient.Zones.Get(context.Background(), z.GetID())
+	if err != nil && !errors.Is(err, babyapi.ErrNotFound) {
+		return fmt.Errorf("error getting existing Zone: %w", err)
+	}
+	if existing != nil {
+		for _, id := range existing.WaterScheduleIDs {
+			waterScheduleIDs = append(waterScheduleIDs, id.String())
+		}
+	}
+
+	return w.resetWaterSchedules(waterScheduleIDs, &pkg.ZoneAndGarden{Garden: g, Zone: z})
+}
+
+func (w *Worker) resetWaterSchedules(ids []string, pending *pkg.ZoneAndGarden) error {
+	slices.Sort(ids)
+	for _, id := range slices.Compact(ids) {
+		ws, err := w.storageClient.WaterSchedules.Get(context.Background(), id)
+		if errors.Is(err, babyapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error getting WaterSchedule %q: %w", id, err)
+		}
+		if !ws.Scheduled() {
+			continue
+		}
+
+		// Only reschedule if the time zone changes
+		loc := w.waterScheduleLocation(ws, pending)
+		if locationName(loc) == locationName(w.waterScheduleLocation(ws, nil)) {
+			continue
+		}
+
+		err = w.RemoveJobsByID(id)
+		if err != nil {
+			return err
+		}
+		err = w.scheduleWaterAction(ws, loc)
+		if err != nil {
+			return fmt.Errorf("error scheduling WaterSchedule %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// waterScheduleLocation returns the time zone of the Gardens with Zones using the WaterSchedule. It returns nil if
+// none of them have a TimeZone or they do not all have the same one. If pending is set, its Zone and Garden are
+// used instead of the stored versions since they are about to be saved
+func (w *Worker) waterScheduleLocation(ws *pkg.WaterSchedule, pending *pkg.ZoneAndGarden) *time.Location {
+	if w.storageClient == nil {
+		return nil
+	}
+	logger := w.contextLogger(nil, nil, ws)
+
+	zonesAndGardens, err := w.storageClient.GetZonesUsingWaterSchedule(ws.ID.String())
+	if err != nil {
+		logger.Warn("unable to get Zones to determine time zone", "error", err)
+		return nil
+	}
+
+	if pending != nil {
+		zonesAndGardens = replacePending(zonesAndGardens, pending, ws.ID.String())
+	}
+
+	var result *time.Location
+	for _, zg := range zonesAndGardens {
+		loc, err := zg.Garden.TimeLocation()
+		if err != nil {
+			logger.Warn("invalid Garden time zone", "garden_id", zg.Garden.ID.String(), "error", err)
+			return nil
+		}
+		if loc == nil {
+			return nil
+		}
+		if result != nil && result.String() != loc.String() {
+			logger.Warn("Gardens using WaterSchedule have different time zones, so the StartTime offset is used")
+			return nil
+		}
+		result = loc
+	}
+
+	return result
+}
+
 // GetNextActiveWaterSchedule determines the WaterSchedule that is going to be used for the next watering time
 func (w *Worker) GetNextActiveWaterSchedule(waterSchedules []*pkg.WaterSchedule) *pkg.WaterSchedule {
 	w.logger.Debug("getting next water schedule for water_schedules", "water_schedules", waterSchedules)
@@ -140,12 +324,30 @@ func (w *Worker) GetNextActiveWaterSchedule(waterSchedules []*pkg.WaterSchedule)
 	return nextRun.ws
 }
 
-// GetNextWaterTime determines the next scheduled watering time for a given Zone using tags
+// GetNextWaterTime determines the next scheduled watering time for a given Zone using tags. If the WaterSchedule
+// has a SkipCount or a cron Interval that has not started, the skipped times are not used
 func (w *Worker) GetNextWaterTime(ws *pkg.WaterSchedule) *time.Time {
 	if ws == nil {
 		return nil
 	}
 
+	if ws.SkipCount > 0 || ws.NotStartedAt(w.now()) {
+		times, err := w.GetNextWaterTimes(ws, 1)
+		if err != nil {
+			w.contextLogger(nil, nil, ws).Warn("unable to get next water time after skipped times", "error", err)
+			return nil
+		}
+		if len(times) == 0 {
+			return nil
+		}
+		return &times[0]
+	}
+
+	return w.nextJobRun(ws)
+}
+
+// nextJobRun returns the next run of the WaterSchedule's scheduled Job
+func (w *Worker) nextJobRun(ws *pkg.WaterSchedule) *time.Time {
 	logger := w.contextLogger(nil, nil, ws)
 	logger.Debug("getting next water time for water_schedule")
 
@@ -160,6 +362,56 @@ func (w *Worker) GetNextWaterTime(ws *pkg.WaterSchedule) *time.Time {
 	return nil
 }
 
+// GetNextWaterTimes returns up to count upcoming times that the WaterSchedule will water, starting with the
+// scheduled Job's next run. Times when the WaterSchedule is not active, is before the StartDate of a cron Interval,
+// or will be skipped because of its SkipCount are excluded
+func (w *Worker) GetNextWaterTimes(ws *pkg.WaterSchedule, count int) ([]time.Time, error) {
+	next := w.nextJobRun(ws)
+	if next == nil {
+		return nil, nil
+	}
+
+	times, _, err := w.waterTimes(ws, *next, next.Add(nextWaterTimesHorizon), count)
+	return times, err
+}
+
+// waterTimes returns up to count times that the WaterSchedule will water, starting at next and ending before end.
+// The times that will be skipped because of the SkipCount are returned separately
+func (w *Worker) waterTimes(ws *pkg.WaterSchedule, next, end time.Time, count int) ([]time.Time, []time.Time, error) {
+	nextFunc := func(t time.Time) time.Time {
+		return t.Add(ws.Interval.Duration)
+	}
+	if ws.Interval.Cron != "" {
+		// Use the same time zone as the scheduler if the WaterSchedule does not use a Garden's time zone
+		loc := w.waterScheduleLocation(ws, nil)
+		if loc == nil {
+			loc = time.UTC
+		}
+		schedule, err := cron.ParseStandard(ws.Interval.CronInLocation(loc))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cron expression: %w", err)
+		}
+		nextFunc = schedule.Next
+	} else if ws.Interval.Duration <= 0 {
+		return nil, nil, errors.New("invalid interval")
+	}
+
+	result := []time.Time{}
+	skipped := []time.Time{}
+	for t := next; len(result) < count && t.Before(end); t = nextFunc(t) {
+		if !ws.IsActive(t) || ws.NotStartedAt(t) {
+			continue
+		}
+		if uint(len(skipped)) < ws.SkipCount {
+			skipped = append(skipped, t)
+			continue
+		}
+		result = append(result, t)
+	}
+
+	return result, skipped, nil
+}
+
 // ScheduleLightActions will schedule LightActions to turn the light on and off based off the CreatedAt date,
 // LightSchedule time, and Interval. The scheduled Jobs are tagged with the Garden's ID so they can
 // easily be removed
@@ -167,34 +419,12 @@ func (w *Worker) ScheduleLightActions(g *pkg.Garden) error {
 	logger := w.contextLogger(g, nil, nil)
 	logger.Info("creating scheduled Jobs for lighting Garden", "light_schedule", *g.LightSchedule)
 
-	lightTime := g.LightSchedule.StartTime.Time.UTC()
-
-	now := time.Now()
-	onStartDate := timeAtDate(&now, lightTime)
-	offStartDate := onStartDate.Add(g.LightSchedule.Duration.Duration)
-
-	// Schedule the LightAction execution for ON and OFF
-	scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Add(2)
-	onAction := &action.LightAction{State: pkg.LightStateOn}
-	offAction := &action.LightAction{State: pkg.LightStateOff}
-	_, err := w.scheduler.
-		Every(lightInterval).
-		StartAt(onStartDate).
-		Tag("garden").
-		Tag(g.ID.String()).
-		Tag(pkg.LightStateOn.String()).
-		Do(w.executeLightActionInScheduledJob, g, onAction, logger.With("source", "scheduled_job"))
-	if err != nil {
-		return err
+	var err error
+	if g.LightSchedule.UsesSunTimes() {
+		err = w.scheduleSunLightActions(g)
+	} else {
+		err = w.scheduleFixedLightActions(g)
 	}
-
-	_, err = w.scheduler.
-		Every(lightInterval).
-		StartAt(offStartDate).
-		Tag("garden").
-		Tag(g.ID.String()).
-		Tag(pkg.LightStateOff.String()).
-		Do(w.executeLightActionInScheduledJob, g, offAction, logger.With("source", "scheduled_job"))
 	if err != nil {
 		return err
 	}
@@ -203,7 +433,7 @@ func (w *Worker) ScheduleLightActions(g *pkg.Garden) error {
 	if g.LightSchedule.AdhocOnTime != nil {
 		logger.Debug("garden has adhoc ON time", "adhoc_on_time", g.LightSchedule.AdhocOnTime)
 		// If AdhocOnTime is in the past, reset it and return
-		if g.LightSchedule.AdhocOnTime.Before(time.Now().UTC()) {
+		if g.LightSchedule.AdhocOnTime.Before(w.now().UTC()) {
 			logger.Debug("adhoc ON time is in the past and is being removed")
 			g.LightSchedule.AdhocOnTime = nil
 			return w.storageClient.Gardens.Set(context.Background(), g)
@@ -234,6 +464,128 @@ func (w *Worker) ScheduleLightActions(g *pkg.Garden) error {
 	return nil
 }
 
+// scheduleFixedLightActions schedules ON and OFF Jobs that repeat every day at the same time
+func (w *Worker) scheduleFixedLightActions(g *pkg.Garden) error {
+	logger := w.contextLogger(g, nil, nil)
+
+	loc, err := g.TimeLocation()
+	if err != nil {
+		return err
+	}
+
+	// startJob is called for each state since gocron only allows creating one Job at a time
+	var startJob func(state pkg.LightState) *gocron.Scheduler
+	if loc != nil {
+		// Cron is used so the light keeps the same local time when daylight saving time changes
+		onTime := inLocation(g.LightSchedule.StartTime.Time, loc)
+		offTime := onTime.Add(g.LightSchedule.Duration.Duration)
+		startJob = func(state pkg.LightState) *gocron.Scheduler {
+			if state == pkg.LightStateOn {
+				return w.scheduler.CronWithSeconds(dailyCronExpression(onTime))
+			}
+			return w.scheduler.CronWithSeconds(dailyCronExpression(offTime))
+		}
+	} else {
+		lightTime := g.LightSchedule.StartTime.Time.UTC()
+
+		now := w.now()
+		onStartDate := w.timeAtDate(&now, lightTime)
+		offStartDate := onStartDate.Add(g.LightSchedule.Duration.Duration)
+		startJob = func(state pkg.LightState) *gocron.Scheduler {
+			if state == pkg.LightStateOn {
+				return w.scheduler.Every(lightInterval).StartAt(onStartDate)
+			}
+			return w.scheduler.Every(lightInterval).StartAt(offStartDate)
+		}
+	}
+
+	// Schedule the LightAction execution for ON and OFF
+	scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Add(2)
+	onAction := &action.LightAction{State: pkg.LightStateOn}
+	offAction := &action.LightAction{State: pkg.LightStateOff}
+	_, err = startJob(pkg.LightStateOn).
+		Tag("garden").
+		Tag(g.ID.String()).
+		Tag(pkg.LightStateOn.String()).
+		Do(w.executeLightActionInScheduledJob, g, onAction, logger.With("source", "scheduled_job"))
+	if err != nil {
+		return err
+	}
+
+	_, err = startJob(pkg.LightStateOff).
+		Tag("garden").
+		Tag(g.ID.String()).
+		Tag(pkg.LightStateOff.String()).
+		Do(w.executeLightActionInScheduledJob, g, offAction, logger.With("source", "scheduled_job"))
+	return err
+}
+
+// scheduleSunLightActions schedules ON and OFF Jobs for a LightSchedule that is relative to sunrise or sunset.
+// Since these times change every day, each Job replaces the other state's Job after it executes so it uses the
+// current day's sunrise or sunset. gocron does not allow moving a Job's next run earlier once it has run, and a
+// Job cannot replace itself while it is executing
+func (w *Worker) scheduleSunLightActions(g *pkg.Garden) error {
+	scheduleJobsGauge.WithLabelValues(gardenLabels(g)...).Add(2)
+
+	err := w.scheduleSunLightAction(g, pkg.LightStateOn)
+	if err != nil {
+		return err
+	}
+	return w.scheduleSunLightAction(g, pkg.LightStateOff)
+}
+
+// scheduleSunLightAction schedules a Job for the next time that the light changes to the state
+func (w *Worker) scheduleSunLightAction(g *pkg.Garden, state pkg.LightState) error {
+	logger := w.contextLogger(g, nil, nil)
+
+	tz, err := g.TimeLocation()
+	if err != nil {
+		return err
+	}
+
+	nextTime, err := g.LightSchedule.NextTime(state, g.Location, tz, w.now())
+	if err != nil {
+		return fmt.Errorf("error calculating next %s time: %w", state, err)
+	}
+	logger.Debug("scheduling light Job relative to sunrise/sunset", "state", state.String(), "next_time", nextTime)
+
+	_, err = w.scheduler.
+		Every(lightInterval).
+		StartAt(nextTime).
+		Tag("garden").
+		Tag(g.ID.String()).
+		Tag(state.String()).
+		Do(w.executeSunLightActionInScheduledJob, g, &action.LightAction{State: state}, logger.With("source", "scheduled_job"))
+	return err
+}
+
+// executeSunLightActionInScheduledJob executes the LightAction and then replaces the opposite state's Job
+func (w *Worker) executeSunLightActionInScheduledJob(g *pkg.Garden, input *action.LightAction, actionLogger *slog.Logger) {
+	w.executeLightActionInScheduledJob(g, input, actionLogger)
+
+	nextState := pkg.LightStateOn
+	if input.State == pkg.LightStateOn {
+		nextState = pkg.LightStateOff
+	}
+
+	err := w.rescheduleSunLightAction(g, nextState)
+	if err != nil {
+		actionLogger.Error("error rescheduling LightAction", "state", nextState.String(), "error", err)
+		schedulerErrors.WithLabelValues(gardenLabels(g)...).Inc()
+	}
+}
+
+// rescheduleSunLightAction removes the Garden's scheduled Job for the state and creates a new one
+func (w *Worker) rescheduleSunLightAction(g *pkg.Garden, state pkg.LightState) error {
+	job, err := w.getNextLightJob(g, state, false)
+	if err != nil {
+		return err
+	}
+	w.scheduler.RemoveByReference(job)
+
+	return w.scheduleSunLightAction(g, state)
+}
+
 // ResetLightSchedule will simply remove the existing Job and create a new one
 func (w *Worker) ResetLightSchedule(g *pkg.Garden) error {
 	logger := w.contextLogger(g, nil, nil)
@@ -269,7 +621,7 @@ func (w *Worker) ScheduleLightDelay(g *pkg.Garden, input *action.LightAction) er
 	}
 
 	// Don't allow delaying longer than LightSchedule.Duration
-	if input.ForDuration.Duration > g.LightSchedule.Duration.Duration {
+	if g.LightSchedule.Duration != nil && input.ForDuration.Duration > g.LightSchedule.Duration.Duration {
 		return errors.New("unable to execute delay that lasts longer than light_schedule")
 	}
 
@@ -291,7 +643,7 @@ func (w *Worker) ScheduleLightDelay(g *pkg.Garden, input *action.LightAction) er
 	// No need to change any schedules
 	if nextOffTime.Before(*nextOnTime) {
 		logger.Debug("next OFF time is before next ON time; setting schedule to turn light back on", "duration", input.ForDuration.Duration)
-		now := time.Now().UTC()
+		now := w.now().UTC()
 
 		// Don't allow a delayDuration that will occur after nextOffTime
 		if nextOffTime.Before(now.Add(input.ForDuration.Duration)) {
@@ -437,6 +789,49 @@ func (w *Worker) scheduleAdhocLightAction(g *pkg.Garden) error {
 	return err
 }
 
+// replacePending replaces the stored Zones and Gardens with the pending versions that are about to be saved. If
+// pending has a Zone, it is only included if it uses the WaterSchedule
+func replacePending(zonesAndGardens []*pkg.ZoneAndGarden, pending *pkg.ZoneAndGarden, waterScheduleID string) []*pkg.ZoneAndGarden {
+	result := []*pkg.ZoneAndGarden{}
+	for _, zg := range zonesAndGardens {
+		if pending.Zone != nil && zg.Zone.ID == pending.Zone.ID {
+			continue
+		}
+		if zg.Garden.ID == pending.Garden.ID {
+			zg = &pkg.ZoneAndGarden{Zone: zg.Zone, Garden: pending.Garden}
+		}
+		result = append(result, zg)
+	}
+
+	if pending.Zone == nil || pending.Zone.EndDated() {
+		return result
+	}
+	for _, id := range pending.Zone.WaterScheduleIDs {
+		if id.String() == waterScheduleID {
+			return append(result, pending)
+		}
+	}
+	return result
+}
+
+// locationName returns the name of the location or an empty string if it is nil
+func locationName(loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+	return loc.String()
+}
+
+// inLocation returns a time with the same hour, minute, and second as t in the location
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
+
+// dailyCronExpression creates a cron expression, with seconds, that runs every day at the time in its location
+func dailyCronExpression(t time.Time) string {
+	return fmt.Sprintf("CRON_TZ=%s %d %d %d * * *", t.Location().String(), t.Second(), t.Minute(), t.Hour())
+}
+
 func (w *Worker) contextLogger(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) *slog.Logger {
 	logger := w.logger.With()
 	if g != nil {
@@ -466,6 +861,13 @@ func waterScheduleLabels(ws *pkg.WaterSchedule) []string {
 func (w *Worker) executeLightActionInScheduledJob(g *pkg.Garden, input *action.LightAction, actionLogger *slog.Logger) {
 	actionLogger = actionLogger.With("state", input.State.String())
 	actionLogger.Info("executing LightAction")
+
+	if input.State == pkg.LightStateOn && w.delayLightOn(g, actionLogger) {
+		return
+	}
+
+	// The LightSchedule takes over from a temporary state that has not been reverted yet
+	w.cancelLightRevert(g)
 	err := w.ExecuteLightAction(g, input)
 	if err != nil {
 		actionLogger.Error("error executing scheduled LightAction", "error", err)
@@ -475,11 +877,12 @@ func (w *Worker) executeLightActionInScheduledJob(g *pkg.Garden, input *action.L
 		return
 	}
 
+	w.addScheduledAuditEntry("garden", g.GetID(), "light_action", map[string]string{"state": input.State.String()})
 	w.sendLightActionNotification(g, input.State, actionLogger)
 }
 
-func timeAtDate(date *time.Time, startTime time.Time) time.Time {
-	actualDate := time.Now()
+func (w *Worker) timeAtDate(date *time.Time, startTime time.Time) time.Time {
+	actualDate := w.now()
 	if date != nil {
 		actualDate = *date
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/notifications"
@@ -67,6 +68,23 @@ func createExampleWaterSchedule() *pkg.WaterSchedule {
 	}
 }
 
+// startTestWorker starts a Worker using a virtual clock at now. When the test finishes, the Worker is stopped before
+// the MQTT client's expectations are asserted so no scheduled Jobs are still running
+func startTestWorker(t *testing.T, storageClient *storage.Client, mqttClient *mqtt.MockClient, now time.Time) *Worker {
+	t.Helper()
+
+	w := NewWorker(storageClient, nil, mqttClient, slog.Default())
+	w.SetClock(clock.NewVirtual(now))
+	w.StartAsync()
+
+	t.Cleanup(func() {
+		w.Stop()
+		mqttClient.AssertExpectations(t)
+	})
+
+	return w
+}
+
 func TestScheduleWaterActionStorageError(t *testing.T) {
 	storageClient, err := storage.NewClient(storage.Config{
 		Driver: "hashmap",
@@ -175,6 +193,48 @@ func TestScheduleWaterAction(t *testing.T) {
 	mqttClient.AssertExpectations(t)
 }
 
+func TestScheduleWaterActionSkipCount(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	assert.NoError(t, err)
+
+	start := time.Date(2023, time.June, 1, 7, 0, 0, 0, time.UTC)
+	ws := createExampleWaterSchedule()
+	ws.StartDate = &start
+	ws.StartTime = pkg.NewStartTime(start.Add(time.Hour))
+	ws.SkipCount = 1
+
+	assert.NoError(t, storageClient.Gardens.Set(context.Background(), createExampleGarden()))
+	assert.NoError(t, storageClient.Zones.Set(context.Background(), createExampleZone()))
+	assert.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", mock.Anything).Return("test-garden/action/water", nil)
+	mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil).Once()
+	mqttClient.On("Disconnect", uint(100)).Return()
+
+	worker := startTestWorker(t, storageClient, mqttClient, start)
+
+	assert.NoError(t, worker.ScheduleWaterAction(ws))
+
+	t.Run("FirstRunIsSkipped", func(t *testing.T) {
+		_, err := worker.AdvanceClock(2 * time.Hour)
+		assert.NoError(t, err)
+		mqttClient.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+
+		stored, err := storageClient.WaterSchedules.Get(context.Background(), ws.GetID())
+		assert.NoError(t, err)
+		assert.Equal(t, uint(0), stored.SkipCount)
+	})
+
+	t.Run("NextRunWaters", func(t *testing.T) {
+		_, err := worker.AdvanceClock(24 * time.Hour)
+		assert.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+	})
+}
+
 func TestScheduleWaterActionWithErrorNotification(t *testing.T) {
 	fake.ResetLastMessage()
 
@@ -405,6 +465,142 @@ func TestGetNextWaterTimeWithInterval(t *testing.T) {
 	}
 }
 
+func TestGetNextWaterTimes(t *testing.T) {
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2023, month, day, 9, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name         string
+		interval     *pkg.Duration
+		activePeriod *pkg.ActivePeriod
+		count        int
+		skipCount    uint
+		expected     []time.Time
+	}{
+		{
+			"Interval",
+			&pkg.Duration{Duration: 48 * time.Hour},
+			nil,
+			3,
+			0,
+			[]time.Time{date(time.January, 1), date(time.January, 3), date(time.January, 5)},
+		},
+		{
+			"Cron",
+			&pkg.Duration{Cron: "0 9 * * 1"},
+			nil,
+			3,
+			0,
+			// StartTime is not used with cron, so every time is a Monday
+			[]time.Time{date(time.January, 2), date(time.January, 9), date(time.January, 16)},
+		},
+		{
+			"ActivePeriodExcludesTimes",
+			&pkg.Duration{Duration: 24 * time.Hour},
+			&pkg.ActivePeriod{StartDate: "01-01", EndDate: "01-02"},
+			5,
+			0,
+			// times are limited to one year ahead, so 2024-01-02 is not included
+			[]time.Time{date(time.January, 1), date(time.January, 2), date(time.January, 1).AddDate(1, 0, 0)},
+		},
+		{
+			"SkipCountExcludesTimes",
+			&pkg.Duration{Duration: 48 * time.Hour},
+			nil,
+			2,
+			2,
+			[]time.Time{date(time.January, 5), date(time.January, 7)},
+		},
+		{
+			"SkipCountOnlyCountsActiveTimes",
+			&pkg.Duration{Duration: 24 * time.Hour},
+			&pkg.ActivePeriod{StartDate: "01-02", EndDate: "01-31"},
+			1,
+			1,
+			[]time.Time{date(time.January, 3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mqttClient := new(mqtt.MockClient)
+			mqttClient.On("Disconnect", uint(100)).Return()
+
+			worker := NewWorker(nil, nil, mqttClient, slog.Default())
+			worker.SetClock(clock.NewVirtual(start))
+			worker.StartAsync()
+			defer worker.Stop()
+
+			ws := createExampleWaterSchedule()
+			ws.Interval = tt.interval
+			ws.ActivePeriod = tt.activePeriod
+			ws.SkipCount = tt.skipCount
+			ws.StartDate = &start
+			ws.StartTime = pkg.NewStartTime(start.Add(9 * time.Hour))
+			assert.NoError(t, worker.ScheduleWaterAction(ws))
+
+			times, err := worker.GetNextWaterTimes(ws, tt.count)
+			assert.NoError(t, err)
+			for i := range times {
+				times[i] = times[i].UTC()
+			}
+			assert.Equal(t, tt.expected, times)
+		})
+	}
+}
+
+func TestGetNextWaterTimesInGardenTimeZone(t *testing.T) {
+	start := time.Date(2023, time.January, 1, 12, 0, 0, 0, time.UTC)
+	// 06:00 in Phoenix is 13:00 UTC
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2023, month, day, 13, 0, 0, 0, time.UTC)
+	}
+
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	assert.NoError(t, err)
+
+	garden := createExampleGarden()
+	garden.TimeZone = "America/Phoenix"
+	assert.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+	assert.NoError(t, storageClient.Zones.Set(context.Background(), createExampleZone()))
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("Disconnect", uint(100)).Return()
+
+	worker := NewWorker(storageClient, nil, mqttClient, slog.Default())
+	worker.SetClock(clock.NewVirtual(start))
+	worker.StartAsync()
+	defer worker.Stop()
+
+	// The cron expression is evaluated in the Garden's TimeZone
+	ws := createExampleWaterSchedule()
+	ws.Interval = &pkg.Duration{Cron: "0 6 * * 1"}
+	ws.StartDate = &start
+	ws.StartTime = nil
+	assert.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+	assert.NoError(t, worker.ScheduleWaterAction(ws))
+
+	times, err := worker.GetNextWaterTimes(ws, 3)
+	assert.NoError(t, err)
+	for i := range times {
+		times[i] = times[i].UTC()
+	}
+	assert.Equal(t, []time.Time{date(time.January, 2), date(time.January, 9), date(time.January, 16)}, times)
+
+	t.Run("ResetWhenGardenTimeZoneChanges", func(t *testing.T) {
+		updated := createExampleGarden()
+		updated.TimeZone = "America/New_York"
+		assert.NoError(t, worker.ResetWaterSchedulesForGarden(updated))
+
+		// 06:00 in New York is 11:00 UTC, which already passed today so the next time is Monday
+		assert.Equal(t, time.Date(2023, time.January, 2, 11, 0, 0, 0, time.UTC), worker.GetNextWaterTime(ws).UTC())
+	})
+}
+
 func TestScheduleLightActions(t *testing.T) {
 	// TODO: this test was consistently failing when running in GitHub Workflow, but worked fine locally until this commit which
 	// changed line 199 of `scheduler.go` (ScheduleLightActions) to delete and re-create Job instead of updating. It's interesting
@@ -466,6 +662,86 @@ func TestScheduleLightActions(t *testing.T) {
 		assert.Equal(t, expected, *nextOnTime)
 	})
 
+	t.Run("SunTimesRescheduledDaily", func(t *testing.T) {
+		storageClient, err := storage.NewClient(storage.Config{
+			Driver: "hashmap",
+		})
+		assert.NoError(t, err)
+
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("LightTopic", mock.Anything).Return("test-garden/action/light", nil)
+		mqttClient.On("Publish", "test-garden/action/light", mock.Anything).Return(nil)
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		start := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+		worker := NewWorker(storageClient, nil, mqttClient, slog.Default())
+		worker.SetClock(clock.NewVirtual(start))
+		worker.StartAsync()
+		defer worker.Stop()
+
+		sunrise, err := pkg.SunTimeFromString("sunrise+30m")
+		assert.NoError(t, err)
+		sunset, err := pkg.SunTimeFromString("sunset-1h")
+		assert.NoError(t, err)
+
+		g := createExampleGarden()
+		g.Location = &pkg.Location{Latitude: 33.45, Longitude: -112.07}
+		g.LightSchedule = &pkg.LightSchedule{Start: sunrise, Until: sunset}
+		assert.NoError(t, worker.ScheduleLightActions(g))
+
+		// The light is already on, so it turns off before sunset on the first day
+		expectedOff, err := sunset.On(g.Location, start.AddDate(0, 0, -1))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOff, worker.GetNextLightTime(g, pkg.LightStateOff).UTC())
+
+		fired, err := worker.AdvanceClock(72 * time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, fired)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 6)
+
+		// The last ON Job recalculated the OFF time using the current day's sunset
+		expectedOff, err = sunset.On(g.Location, start.AddDate(0, 0, 2))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOff, worker.GetNextLightTime(g, pkg.LightStateOff).UTC())
+	})
+
+	t.Run("TimeZoneKeepsLocalTimeAcrossDST", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("LightTopic", mock.Anything).Return("test-garden/action/light", nil)
+		mqttClient.On("Publish", "test-garden/action/light", mock.Anything).Return(nil)
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		// Daylight saving time starts in New York on 2023-03-12
+		start := time.Date(2023, time.March, 11, 0, 0, 0, 0, time.UTC)
+		worker := NewWorker(nil, nil, mqttClient, slog.Default())
+		worker.SetClock(clock.NewVirtual(start))
+		worker.StartAsync()
+		defer worker.Stop()
+
+		// The offset is ignored since the Garden's TimeZone is used
+		startTime, err := pkg.StartTimeFromString("08:00:00Z")
+		assert.NoError(t, err)
+
+		g := createExampleGarden()
+		g.TimeZone = "America/New_York"
+		g.LightSchedule = &pkg.LightSchedule{
+			Duration:  &pkg.Duration{Duration: 2 * time.Hour},
+			StartTime: startTime,
+		}
+		assert.NoError(t, worker.ScheduleLightActions(g))
+
+		assert.Equal(t, time.Date(2023, time.March, 11, 13, 0, 0, 0, time.UTC), worker.GetNextLightTime(g, pkg.LightStateOn).UTC())
+		assert.Equal(t, time.Date(2023, time.March, 11, 15, 0, 0, 0, time.UTC), worker.GetNextLightTime(g, pkg.LightStateOff).UTC())
+
+		fired, err := worker.AdvanceClock(24 * time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, fired)
+
+		// After daylight saving time starts, 08:00 in New York is an hour earlier in UTC
+		assert.Equal(t, time.Date(2023, time.March, 12, 12, 0, 0, 0, time.UTC), worker.GetNextLightTime(g, pkg.LightStateOn).UTC())
+		assert.Equal(t, time.Date(2023, time.March, 12, 14, 0, 0, 0, time.UTC), worker.GetNextLightTime(g, pkg.LightStateOff).UTC())
+	})
+
 	t.Run("ScheduledLightActionCreatesNotification", func(t *testing.T) {
 		tests := []struct {
 			name               string
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-co-op/gocron"
+)
+
+const (
+	// jobWaitTimeout is the maximum time to wait for a Job to start and finish after a virtual timer fires
+	jobWaitTimeout = 5 * time.Second
+	jobWaitPoll    = time.Millisecond
+)
+
+// ErrNotSimulated is returned when trying to advance time on a Worker that uses the real Clock
+var ErrNotSimulated = errors.New("worker is not using a virtual clock")
+
+// Now returns the current time according to the Worker's Clock
+func (w *Worker) Now() time.Time {
+	return w.now()
+}
+
+// AdvanceClock fast-forwards the Worker's virtual Clock by the duration. All scheduled Jobs that would run in
+// this period are executed in order and each one is completed before moving on to the next
+func (w *Worker) AdvanceClock(d time.Duration) (int, error) {
+	if !w.clock.IsVirtual() {
+		return 0, ErrNotSimulated
+	}
+	if d < 0 {
+		return 0, errors.New("unable to advance clock by a negative duration")
+	}
+
+	w.logger.Info("advancing virtual clock", "duration", d, "from", w.now())
+
+	fired := w.clock.Advance(d, func(fire func()) {
+		started, _ := w.jobRunCounts()
+		fire()
+		w.waitForJobs(started)
+	})
+
+	w.logger.Info("finished advancing virtual clock", "now", w.now(), "timers", fired)
+	return fired, nil
+}
+
+// waitForJobs blocks until a new Job run has started and all running Jobs are finished. It uses the Jobs'
+// run counts since gocron executes Jobs asynchronously. If no new Job starts before the timeout, it returns
+func (w *Worker) waitForJobs(previouslyStarted int) {
+	deadline := time.Now().Add(jobWaitTimeout)
+	for {
+		started, finished := w.jobRunCounts()
+		if (started > previouslyStarted && started == finished) || time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(jobWaitPoll)
+	}
+}
+
+// jobRunCount is the number of started and finished runs of a Job
+type jobRunCount struct {
+	started  int
+	finished int
+}
+
+// jobRunCounts returns the total number of started and finished runs for all scheduled Jobs. Some Jobs are
+// replaced when they are rescheduled, so the last counts of removed Jobs are still included
+func (w *Worker) jobRunCounts() (int, int) {
+	w.jobRunsMtx.Lock()
+	defer w.jobRunsMtx.Unlock()
+
+	current := map[*gocron.Job]bool{}
+	for _, job := range w.scheduler.Jobs() {
+		current[job] = true
+		w.jobRuns[job] = jobRunCount{job.RunCount(), job.FinishedRunCount()}
+	}
+
+	started, finished := w.removedJobRuns.started, w.removedJobRuns.finished
+	for job, count := range w.jobRuns {
+		if !current[job] {
+			w.removedJobRuns.started += count.started
+			w.removedJobRuns.finished += count.finished
+			delete(w.jobRuns, job)
+		}
+		started += count.started
+		finished += count.finished
+	}
+	return started, finished
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdvanceClock(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	assert.NoError(t, err)
+	defer weather.ResetCache()
+
+	garden := createExampleGarden()
+	zone := createExampleZone()
+	ws := createExampleWaterSchedule()
+
+	start := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	ws.StartDate = &start
+	ws.StartTime = pkg.NewStartTime(start.Add(6 * time.Hour))
+
+	assert.NoError(t, storageClient.Gardens.Set(context.Background(), garden))
+	assert.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+	assert.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+
+	influxdbClient := new(influxdb.MockClient)
+	mqttClient := new(mqtt.MockClient)
+
+	mqttClient.On("WaterTopic", mock.Anything).Return("test-garden/action/water", nil)
+	mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil)
+	mqttClient.On("Disconnect", uint(100)).Return()
+	influxdbClient.On("Close").Return()
+
+	worker := NewWorker(storageClient, influxdbClient, mqttClient, slog.Default())
+	worker.SetClock(clock.NewVirtual(start))
+	worker.StartAsync()
+	defer worker.Stop()
+
+	assert.NoError(t, worker.ScheduleWaterAction(ws))
+
+	t.Run("NothingBeforeStartTime", func(t *testing.T) {
+		_, err := worker.AdvanceClock(5 * time.Hour)
+		assert.NoError(t, err)
+		mqttClient.AssertNotCalled(t, "Publish", "test-garden/action/water", mock.Anything)
+	})
+
+	t.Run("ThreeDays", func(t *testing.T) {
+		fired, err := worker.AdvanceClock(72 * time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, fired)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 3)
+		assert.Equal(t, start.Add(77*time.Hour), worker.Now().UTC())
+	})
+
+	t.Run("NextWaterTimeUsesVirtualClock", func(t *testing.T) {
+		nextWaterTime := worker.GetNextWaterTime(ws)
+		assert.NotNil(t, nextWaterTime)
+		assert.Equal(t, start.Add(78*time.Hour), nextWaterTime.UTC())
+	})
+
+	t.Run("ErrorNegativeDuration", func(t *testing.T) {
+		_, err := worker.AdvanceClock(-1 * time.Hour)
+		assert.Error(t, err)
+	})
+}
+
+func TestAdvanceClockNotSimulated(t *testing.T) {
+	worker := NewWorker(nil, nil, nil, slog.Default())
+	_, err := worker.AdvanceClock(time.Hour)
+	assert.ErrorIs(t, err, ErrNotSimulated)
+}
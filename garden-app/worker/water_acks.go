@@ -0,0 +1,215 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/rs/xid"
+)
+
+const (
+	// DefaultWaterAckTimeout is used when the WaterAckConfig does not set a Timeout
+	DefaultWaterAckTimeout = 30 * time.Second
+	// DefaultWaterAckRetries is used when the WaterAckConfig does not set Retries
+	DefaultWaterAckRetries = 3
+)
+
+// WaterAckConfig enables retrying water commands until the garden-controller acknowledges them by publishing the
+// WaterMessage's CommandID on "{prefix}/ack/water". MQTT QoS only confirms that the broker received a message, so
+// this makes sure the controller did too. It should only be enabled if all controllers publish acknowledgments
+type WaterAckConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Timeout is how long to wait for an acknowledgment before publishing the command again
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Retries is how many times an unacknowledged command is published again before the WaterAction is considered
+	// failed. A negative value disables retrying so the failure is reported after the first Timeout
+	Retries int `mapstructure:"retries"`
+}
+
+// timeout returns the Timeout or its default
+func (c WaterAckConfig) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return DefaultWaterAckTimeout
+}
+
+// retries returns the Retries or its default
+func (c WaterAckConfig) retries() int {
+	switch {
+	case c.Retries < 0:
+		return 0
+	case c.Retries == 0:
+		return DefaultWaterAckRetries
+	default:
+		return c.Retries
+	}
+}
+
+// pendingWaterAck is a water command that was published and has not been acknowledged yet
+type pendingWaterAck struct {
+	garden   *pkg.Garden
+	zone     *pkg.Zone
+	msg      action.WaterMessage
+	attempts int
+}
+
+// SetWaterAck configures acknowledgments for water commands sent to garden-controllers
+func (w *Worker) SetWaterAck(cfg WaterAckConfig) {
+	w.settingsMtx.Lock()
+	defer w.settingsMtx.Unlock()
+	w.waterAck = cfg
+}
+
+func (w *Worker) getWaterAck() WaterAckConfig {
+	w.settingsMtx.RLock()
+	defer w.settingsMtx.RUnlock()
+	return w.waterAck
+}
+
+// sendWaterMessage sends the WaterMessage to the Garden's controller. When acknowledgments are enabled, commands to
+// garden-controllers use the WaterAction's ID as the CommandID and are published again until they are acknowledged
+func (w *Worker) sendWaterMessage(ctx context.Context, g *pkg.Garden, z *pkg.Zone, msg action.WaterMessage, actionID string) error {
+	c := w.controller(g)
+	if _, ok := c.(*mqttController); !ok || !w.getWaterAck().Enabled {
+		return c.water(ctx, msg)
+	}
+
+	// The command is tracked before publishing since the acknowledgment could arrive before Publish returns
+	msg.CommandID = actionID
+	if msg.CommandID == "" {
+		msg.CommandID = xid.New().String()
+	}
+	w.expectWaterAck(g, z, msg)
+
+	// A queued command is still published later, so it keeps waiting for the acknowledgment
+	err := c.water(ctx, msg)
+	if err != nil && !errors.Is(err, mqtt.ErrPublishQueued) {
+		w.removePendingWaterAck(msg.CommandID)
+	}
+	return err
+}
+
+// AcknowledgeWaterCommand is used when a garden-controller publishes that it received a water command. The topic
+// prefix must match the Garden that the command was sent to
+func (w *Worker) AcknowledgeWaterCommand(topicPrefix, commandID string) {
+	w.pendingWaterAcksMtx.Lock()
+	defer w.pendingWaterAcksMtx.Unlock()
+
+	pending, ok := w.pendingWaterAcks[commandID]
+	if !ok {
+		w.logger.Debug("received acknowledgment for unknown water command", "topic_prefix", topicPrefix, "command_id", commandID)
+		return
+	}
+	if pending.garden.TopicPrefix != topicPrefix {
+		w.logger.Warn("received acknowledgment for water command from a different Garden", "topic_prefix", topicPrefix, "command_id", commandID)
+		return
+	}
+
+	w.logger.Debug("water command was acknowledged", "zone_id", pending.zone.GetID(), "command_id", commandID, "attempts", pending.attempts)
+	delete(w.pendingWaterAcks, commandID)
+	w.updateActionRecord(commandID, pkg.ActionStatusAcknowledged, "")
+}
+
+// PendingWaterAcks returns the number of water commands that have not been acknowledged yet
+func (w *Worker) PendingWaterAcks() int {
+	w.pendingWaterAcksMtx.Lock()
+	defer w.pendingWaterAcksMtx.Unlock()
+	return len(w.pendingWaterAcks)
+}
+
+// expectWaterAck tracks the command and starts a timer to check that it was acknowledged
+func (w *Worker) expectWaterAck(g *pkg.Garden, z *pkg.Zone, msg action.WaterMessage) {
+	w.pendingWaterAcksMtx.Lock()
+	w.pendingWaterAcks[msg.CommandID] = &pendingWaterAck{garden: g, zone: z, msg: msg, attempts: 1}
+	w.pendingWaterAcksMtx.Unlock()
+
+	w.startWaterAckTimer(msg.CommandID, 1)
+}
+
+// startWaterAckTimer checks the command after the timeout. The attempt is used to ignore timers from earlier
+// attempts
+func (w *Worker) startWaterAckTimer(commandID string, attempt int) {
+	w.clock.AfterFuncDirect(w.getWaterAck().timeout(), func() {
+		w.checkWaterAck(commandID, attempt)
+	})
+}
+
+// checkWaterAck publishes the command again if it has not been acknowledged and has retries left. Otherwise, the
+// WaterAction is considered failed
+func (w *Worker) checkWaterAck(commandID string, attempt int) {
+	w.pendingWaterAcksMtx.Lock()
+	pending, ok := w.pendingWaterAcks[commandID]
+	if !ok || pending.attempts != attempt {
+		w.pendingWaterAcksMtx.Unlock()
+		return
+	}
+
+	retry := pending.attempts <= w.getWaterAck().retries()
+	if retry {
+		pending.attempts++
+	} else {
+		delete(w.pendingWaterAcks, commandID)
+	}
+	w.pendingWaterAcksMtx.Unlock()
+
+	logger := w.contextLogger(pending.garden, pending.zone, nil).With("command_id", commandID, "attempts", attempt)
+	if !retry {
+		w.failWaterCommand(pending, logger)
+		return
+	}
+
+	logger.Warn("water command was not acknowledged, publishing it again")
+	w.startWaterAckTimer(commandID, attempt+1)
+
+	err := w.controller(pending.garden).water(context.Background(), pending.msg)
+	if errors.Is(err, mqtt.ErrPublishQueued) {
+		logger.Warn("water command is queued until the MQTT broker is available", "error", err)
+		return
+	}
+	if err != nil {
+		logger.Error("error publishing water command again", "error", err)
+		schedulerErrors.WithLabelValues(zoneLabels(pending.zone)...).Inc()
+	}
+}
+
+// failWaterCommand records that the controller never acknowledged the command and sends a notification. The Zone's
+// watering is released so queued WaterActions can start
+func (w *Worker) failWaterCommand(pending *pendingWaterAck, logger *slog.Logger) {
+	zoneID := pending.zone.GetID()
+	logger.Error("water command was not acknowledged by the controller")
+	actionExecutions.WithLabelValues("water", zoneID, "unacknowledged").Inc()
+
+	w.updateActionRecord(pending.msg.CommandID, pkg.ActionStatusFailed, "controller did not acknowledge the water command")
+	w.addScheduledAuditEntry("zone", zoneID, "water_action_failed", map[string]string{
+		"command_id": pending.msg.CommandID,
+		"attempts":   strconv.Itoa(pending.attempts),
+	})
+
+	if _, ok := w.releaseWatering(pending.garden.GetID(), func(wz wateringZone) bool { return wz.zoneID == zoneID }); ok {
+		w.startQueuedWaterActions(pending.garden)
+	}
+
+	if w.storageClient == nil {
+		return
+	}
+	w.sendNotification(
+		fmt.Sprintf("%s: Water Action Failed", pending.zone.Name),
+		fmt.Sprintf("controller did not acknowledge the water command after %d attempts", pending.attempts),
+		logger,
+	)
+}
+
+// removePendingWaterAck stops tracking a command that could not be published
+func (w *Worker) removePendingWaterAck(commandID string) {
+	w.pendingWaterAcksMtx.Lock()
+	defer w.pendingWaterAcksMtx.Unlock()
+	delete(w.pendingWaterAcks, commandID)
+}
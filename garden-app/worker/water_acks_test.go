@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaterAckConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          WaterAckConfig
+		expectedTimeout time.Duration
+		expectedRetries int
+	}{
+		{"Empty", WaterAckConfig{}, DefaultWaterAckTimeout, DefaultWaterAckRetries},
+		{"Set", WaterAckConfig{Timeout: time.Minute, Retries: 1}, time.Minute, 1},
+		{"NoRetries", WaterAckConfig{Retries: -1}, DefaultWaterAckTimeout, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedTimeout, tt.config.timeout())
+			assert.Equal(t, tt.expectedRetries, tt.config.retries())
+		})
+	}
+}
+
+// publishedWaterMessages returns the WaterMessages published to the MockClient
+func publishedWaterMessages(t *testing.T, mqttClient *mqtt.MockClient) []action.WaterMessage {
+	t.Helper()
+
+	result := []action.WaterMessage{}
+	for _, call := range mqttClient.Calls {
+		if call.Method != "Publish" {
+			continue
+		}
+		var msg action.WaterMessage
+		require.NoError(t, json.Unmarshal(call.Arguments.Get(1).([]byte), &msg))
+		result = append(result, msg)
+	}
+	return result
+}
+
+func TestWaterAcks(t *testing.T) {
+	waterAction := &action.WaterAction{Duration: &pkg.Duration{Duration: time.Hour}}
+
+	setup := func(t *testing.T, cfg WaterAckConfig) (*Worker, *clock.Clock, *mqtt.MockClient) {
+		t.Helper()
+
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+		mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil)
+
+		c := clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC))
+		w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+		w.SetClock(c)
+		w.SetWaterAck(cfg)
+		return w, c, mqttClient
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		w, c, mqttClient := setup(t, WaterAckConfig{})
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), createExampleGarden(), createExampleZone(), waterAction))
+		assert.Equal(t, 0, w.PendingWaterAcks())
+
+		c.Advance(time.Minute, nil)
+		msgs := publishedWaterMessages(t, mqttClient)
+		require.Len(t, msgs, 1)
+		assert.Empty(t, msgs[0].CommandID)
+	})
+
+	t.Run("Acknowledged", func(t *testing.T) {
+		w, c, mqttClient := setup(t, WaterAckConfig{Enabled: true, Timeout: time.Minute})
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), createExampleGarden(), createExampleZone(), waterAction))
+		assert.Equal(t, 1, w.PendingWaterAcks())
+
+		msgs := publishedWaterMessages(t, mqttClient)
+		require.Len(t, msgs, 1)
+		require.NotEmpty(t, msgs[0].CommandID)
+
+		// acknowledgments from other Gardens are ignored
+		w.AcknowledgeWaterCommand("other-garden", msgs[0].CommandID)
+		assert.Equal(t, 1, w.PendingWaterAcks())
+
+		w.AcknowledgeWaterCommand("test-garden", msgs[0].CommandID)
+		assert.Equal(t, 0, w.PendingWaterAcks())
+
+		c.Advance(5*time.Minute, nil)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+	})
+
+	t.Run("AcknowledgedAfterRetry", func(t *testing.T) {
+		w, c, mqttClient := setup(t, WaterAckConfig{Enabled: true, Timeout: time.Minute})
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), createExampleGarden(), createExampleZone(), waterAction))
+
+		c.Advance(time.Minute, nil)
+		msgs := publishedWaterMessages(t, mqttClient)
+		require.Len(t, msgs, 2)
+		assert.Equal(t, msgs[0], msgs[1])
+
+		w.AcknowledgeWaterCommand("test-garden", msgs[1].CommandID)
+		assert.Equal(t, 0, w.PendingWaterAcks())
+
+		c.Advance(5*time.Minute, nil)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+	})
+
+	t.Run("FailedStartsQueuedWaterAction", func(t *testing.T) {
+		w, c, mqttClient := setup(t, WaterAckConfig{Enabled: true, Timeout: time.Minute, Retries: 2})
+
+		one := uint(1)
+		garden := createExampleGarden()
+		garden.MaxConcurrentZones = &one
+
+		zone1 := createExampleZone()
+		zone2 := createExampleZone()
+		zone2.ID = babyapi.ID{ID: xid.New()}
+		position := uint(1)
+		zone2.Position = &position
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone1, waterAction))
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone2, waterAction))
+		assert.Equal(t, 1, w.QueuedWaterActions(garden))
+
+		// the first command and two retries
+		c.Advance(2*time.Minute, nil)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 3)
+		assert.Equal(t, 1, w.QueuedWaterActions(garden))
+
+		// after the last retry is not acknowledged, the queued WaterAction starts
+		c.Advance(time.Minute, nil)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 4)
+		assert.Equal(t, 0, w.QueuedWaterActions(garden))
+		assert.Equal(t, 1, w.PendingWaterAcks())
+
+		msgs := publishedWaterMessages(t, mqttClient)
+		assert.Equal(t, zone2.GetID(), msgs[3].ZoneID)
+		assert.NotEqual(t, msgs[0].CommandID, msgs[3].CommandID)
+	})
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/babyapi"
+)
+
+// applyWaterBudget returns the duration that can be watered without exceeding the Garden's WaterBudget. If the
+// duration is changed, the reason is also returned. A zero duration means that watering should be skipped
+func (w *Worker) applyWaterBudget(g *pkg.Garden, z *pkg.Zone, d time.Duration) (time.Duration, string, error) {
+	budget := g.WaterBudget
+	if budget == nil || d == 0 {
+		return d, "", nil
+	}
+
+	used, err := w.waterBudgetUsed(g)
+	if err != nil {
+		return d, "", fmt.Errorf("error calculating water budget usage: %w", err)
+	}
+
+	remaining := budget.MaxLiters - used
+	if remaining <= 0 {
+		return 0, fmt.Sprintf("water budget of %.1fL per %s is used up", budget.MaxLiters, budget.Period.Duration), nil
+	}
+
+	// Without a flow rate, this watering can't be counted until the budget is used up by other Zones
+	liters, ok := z.EstimateLiters(g, d)
+	if !ok || liters <= remaining {
+		return d, "", nil
+	}
+
+	if budget.ScalesDown() {
+		scaled := time.Duration(float64(d) * remaining / liters).Truncate(time.Second)
+		if scaled > 0 {
+			return scaled, fmt.Sprintf("duration was reduced to use the remaining %.1fL of the water budget", remaining), nil
+		}
+	}
+	return 0, fmt.Sprintf("watering would use %.1fL, but only %.1fL of the water budget remains", liters, remaining), nil
+}
+
+// waterBudgetUsed adds up the liters used by all of the Garden's Zones during the WaterBudget's Period. Each event
+// uses the liters measured by a flow meter or estimated when it was recorded, and otherwise they are estimated with
+// the Zone's flow rate
+func (w *Worker) waterBudgetUsed(g *pkg.Garden) (float64, error) {
+	if w.storageClient == nil || w.storageClient.WaterHistory == nil {
+		return 0, nil
+	}
+
+	zones, err := w.storageClient.Zones.GetAll(context.Background(), babyapi.EndDatedQueryParam(true))
+	if err != nil {
+		return 0, fmt.Errorf("error getting Zones: %w", err)
+	}
+
+	since := w.now().Add(-g.WaterBudget.Period.Duration)
+	used := 0.0
+	for _, z := range zones {
+		if z.GardenID != g.ID.ID {
+			continue
+		}
+
+		history, err := w.storageClient.WaterHistory.GetWaterHistory(context.Background(), z.GetID(), since, 0)
+		if err != nil {
+			return 0, fmt.Errorf("error getting water history for Zone %q: %w", z.GetID(), err)
+		}
+
+		for _, h := range history {
+			switch {
+			case h.MeasuredLiters != nil:
+				used += *h.MeasuredLiters
+			case h.ExpectedLiters != nil:
+				used += *h.ExpectedLiters
+			default:
+				if h.Duration == nil {
+					continue
+				}
+				liters, _ := z.EstimateLiters(g, h.Duration.Duration)
+				used += liters
+			}
+		}
+	}
+	return used, nil
+}
+
+// notifyWaterBudget sends a notification when a watering is skipped or reduced by the Garden's WaterBudget
+func (w *Worker) notifyWaterBudget(g *pkg.Garden, z *pkg.Zone, reason string) {
+	logger := w.logger.With("garden_id", g.GetID(), "zone_id", z.GetID())
+	logger.Info("watering was limited by water budget", "reason", reason)
+
+	w.sendNotification(fmt.Sprintf("%s: Water Budget", g.Name), fmt.Sprintf("%s: %s", z.Name, reason), logger)
+}
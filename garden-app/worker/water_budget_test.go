@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyWaterBudget(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 8, 0, 0, 0, time.UTC)
+	flowRate := 2.0
+	measured := 12.0
+	scaleDown := true
+
+	tests := []struct {
+		name      string
+		history   []pkg.WaterHistory
+		scaleDown *bool
+		duration  time.Duration
+		expected  time.Duration
+		reason    string
+	}{
+		{
+			"WithinBudget",
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: 3 * time.Minute}, RecordTime: now.AddDate(0, 0, -2)}},
+			nil,
+			time.Minute,
+			time.Minute,
+			"",
+		},
+		{
+			"SkipOverBudget",
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: 3 * time.Minute}, RecordTime: now.AddDate(0, 0, -2)}},
+			nil,
+			5 * time.Minute,
+			0,
+			"watering would use 10.0L, but only 4.0L of the water budget remains",
+		},
+		{
+			"ScaleDownOverBudget",
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: 3 * time.Minute}, RecordTime: now.AddDate(0, 0, -2)}},
+			&scaleDown,
+			5 * time.Minute,
+			2 * time.Minute,
+			"duration was reduced to use the remaining 4.0L of the water budget",
+		},
+		{
+			"UsedUpByMeasuredLiters",
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: time.Minute}, RecordTime: now.AddDate(0, 0, -1), MeasuredLiters: &measured}},
+			&scaleDown,
+			time.Minute,
+			0,
+			"water budget of 10.0L per 168h0m0s is used up",
+		},
+		{
+			"HistoryBeforePeriodIsIgnored",
+			[]pkg.WaterHistory{{Duration: &pkg.Duration{Duration: time.Hour}, RecordTime: now.AddDate(0, 0, -10)}},
+			nil,
+			5 * time.Minute,
+			5 * time.Minute,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			garden := createExampleGarden()
+			garden.WaterBudget = &pkg.WaterBudget{
+				MaxLiters: 10,
+				Period:    &pkg.Duration{Duration: 7 * 24 * time.Hour},
+				ScaleDown: tt.scaleDown,
+			}
+			zone := createExampleZone()
+			zone.FlowRate = &flowRate
+			require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+			for _, h := range tt.history {
+				require.NoError(t, storageClient.WaterHistory.AddWaterHistory(context.Background(), zone.GetID(), h))
+			}
+
+			w := NewWorker(storageClient, nil, nil, slog.Default())
+			w.SetClock(clock.NewVirtual(now))
+
+			duration, reason, err := w.applyWaterBudget(garden, zone, tt.duration)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, duration)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestDecideWaterActionWaterBudget(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 8, 0, 0, 0, time.UTC)
+	flowRate := 2.0
+
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	garden := createExampleGarden()
+	garden.WaterBudget = &pkg.WaterBudget{MaxLiters: 5, Period: &pkg.Duration{Duration: 24 * time.Hour}}
+	zone := createExampleZone()
+	zone.FlowRate = &flowRate
+	require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+
+	w := NewWorker(storageClient, nil, nil, slog.Default())
+	w.SetClock(clock.NewVirtual(now))
+
+	t.Run("SkippedOverBudget", func(t *testing.T) {
+		decision, err := w.DecideWaterAction(context.Background(), garden, zone, &action.WaterAction{Duration: &pkg.Duration{Duration: 5 * time.Minute}})
+		require.NoError(t, err)
+		assert.True(t, decision.Skip)
+		assert.Equal(t, time.Duration(0), decision.Duration.Duration)
+		assert.Equal(t, "watering would use 10.0L, but only 5.0L of the water budget remains", decision.OverBudget)
+	})
+
+	t.Run("IgnoreBudget", func(t *testing.T) {
+		decision, err := w.DecideWaterAction(context.Background(), garden, zone, &action.WaterAction{
+			Duration:     &pkg.Duration{Duration: 5 * time.Minute},
+			IgnoreBudget: true,
+		})
+		require.NoError(t, err)
+		assert.False(t, decision.Skip)
+		assert.Equal(t, 5*time.Minute, decision.Duration.Duration)
+		assert.Empty(t, decision.OverBudget)
+	})
+}
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/go-co-op/gocron"
+	"github.com/rs/xid"
+)
+
+const cycleTag = "CYCLE"
+
+// executeWaterCycles splits the WaterAction's duration into a pulse for each cycle. The first pulse is executed now
+// and the rest are scheduled as one-time Jobs after each soak. Each pulse is a separate WaterAction, so it is
+// queued by MaxConcurrentZones and recorded in the Zone's history like any other. Fertilizer is split the same way
+func (w *Worker) executeWaterCycles(ctx context.Context, g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) error {
+	cycles := input.Cycles
+	pulse := &action.WaterAction{
+		Duration: &pkg.Duration{Duration: cycles.Pulse(input.Duration.Duration)},
+	}
+	if input.Fertilizer != nil {
+		pulse.Fertilizer = &pkg.Duration{Duration: cycles.Pulse(input.Fertilizer.Duration)}
+	}
+
+	logger := w.logger.With("garden_id", g.GetID(), "zone_id", z.GetID())
+	logger.Info("starting WaterAction cycles", "count", cycles.Count, "pulse", pulse.Duration.Duration, "soak", cycles.Soak.Duration)
+
+	// The first pulse uses the WaterAction's ID and the rest get their own
+	first := *pulse
+	first.ID = input.ID
+	err := w.ExecuteWaterAction(ctx, g, z, &first)
+	if err != nil {
+		return err
+	}
+
+	start := w.now()
+	for i := uint(1); i < cycles.Count; i++ {
+		startAt := start.Add(time.Duration(i) * (pulse.Duration.Duration + cycles.Soak.Duration))
+
+		err = w.scheduleWaterCycle(g, z, pulse, fmt.Sprintf("%s_%s", cycleTag, xid.New()), startAt, logger.With("cycle", i+1))
+		if err != nil {
+			w.cancelWaterCycles(z.GetID())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scheduleWaterCycle schedules a one-time Job for one of the remaining pulses and saves it as a PendingJob with the
+// ID so it is restored after a restart
+func (w *Worker) scheduleWaterCycle(g *pkg.Garden, z *pkg.Zone, pulse *action.WaterAction, id string, startAt time.Time, logger *slog.Logger) error {
+	scheduleJobsGauge.WithLabelValues(zoneLabels(z)...).Inc()
+	_, err := w.scheduleOnce(startAt).
+		Tag("zone").
+		Tag(z.GetID()).
+		Tag(cycleTag).
+		Tag(cycleGardenTag(g.GetID())).
+		Do(func(jobLogger *slog.Logger) {
+			scheduleJobsGauge.WithLabelValues(zoneLabels(z)...).Dec()
+			w.deletePendingJob(id)
+
+			err := w.ExecuteWaterAction(context.Background(), g, z, pulse)
+			if err != nil {
+				jobLogger.Error("error executing WaterAction cycle", "error", err)
+				schedulerErrors.WithLabelValues(zoneLabels(z)...).Inc()
+			}
+		}, logger.With("source", "cycle_job"))
+	if err != nil {
+		scheduleJobsGauge.WithLabelValues(zoneLabels(z)...).Dec()
+		return fmt.Errorf("error scheduling WaterAction cycle: %w", err)
+	}
+
+	w.savePendingJob(pkg.PendingJob{
+		ID:            id,
+		Type:          pkg.PendingJobWaterCycle,
+		GardenID:      g.GetID(),
+		ZoneID:        z.GetID(),
+		ScheduledTime: w.now(),
+		RunAt:         startAt,
+		Duration:      pulse.Duration,
+		Fertilizer:    pulse.Fertilizer,
+	})
+	return nil
+}
+
+// cancelWaterCycles removes the Jobs for the remaining pulses of WaterActions with Cycles. The tag is either a
+// Zone's ID or one from cycleGardenTag. It returns the number of pulses that were cancelled
+func (w *Worker) cancelWaterCycles(tag string) int {
+	w.deletePendingJobs(func(job pkg.PendingJob) bool {
+		return job.Type == pkg.PendingJobWaterCycle && (job.ZoneID == tag || cycleGardenTag(job.GardenID) == tag)
+	})
+
+	jobs, err := w.scheduler.FindJobsByTag(tag, cycleTag)
+	if err != nil {
+		if !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+			w.logger.Error("unable to find WaterAction cycle Jobs", "tag", tag, "error", err)
+		}
+		return 0
+	}
+
+	cancelled := 0
+	for _, job := range jobs {
+		w.scheduler.RemoveByReference(job)
+		// the Zone's ID is the only tag without the cycleTag prefix other than "zone"
+		for _, t := range job.Tags() {
+			if t != "zone" && !strings.HasPrefix(t, cycleTag) {
+				scheduleJobsGauge.WithLabelValues("zone", t).Dec()
+				break
+			}
+		}
+		cancelled++
+	}
+	return cancelled
+}
+
+// cycleGardenTag is used to find all of a Garden's cycle Jobs when stopping all watering
+func cycleGardenTag(gardenID string) string {
+	return fmt.Sprintf("%s_%s", cycleTag, gardenID)
+}
@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteWaterActionCycles(t *testing.T) {
+	cycles := &pkg.WaterCycles{
+		Count: 3,
+		Water: &pkg.Duration{Duration: time.Minute},
+		Soak:  &pkg.Duration{Duration: 10 * time.Minute},
+	}
+	pulseMessage := []byte(`{"duration":60000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)
+
+	now := time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	t.Run("SendsEachPulseAfterSoaking", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+		mqttClient.On("Publish", "test-garden/action/water", pulseMessage).Return(nil)
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		garden := createExampleGarden()
+		zone := createExampleZone()
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone, &action.WaterAction{
+			Duration: &pkg.Duration{Duration: cycles.TotalWater()},
+			Cycles:   cycles,
+		}))
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+
+		_, err := w.AdvanceClock(10 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+
+		_, err = w.AdvanceClock(time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+
+		_, err = w.AdvanceClock(11 * time.Minute)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 3)
+
+		_, err = w.AdvanceClock(time.Hour)
+		require.NoError(t, err)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 3)
+	})
+
+	t.Run("ScaledDurationIsSplitEvenly", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+		mqttClient.On("Publish", "test-garden/action/water", []byte(`{"duration":30000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), createExampleGarden(), createExampleZone(), &action.WaterAction{
+			Duration: &pkg.Duration{Duration: 90 * time.Second},
+			Cycles:   cycles,
+		}))
+	})
+
+	t.Run("StopCancelsRemainingPulses", func(t *testing.T) {
+		mqttClient := new(mqtt.MockClient)
+		mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+		mqttClient.On("Publish", "test-garden/action/water", pulseMessage).Return(nil)
+		mqttClient.On("StopTopic", "test-garden").Return("test-garden/command/stop", nil)
+		mqttClient.On("Publish", "test-garden/command/stop", mock.Anything).Return(nil).Once()
+		mqttClient.On("Disconnect", uint(100)).Return()
+
+		w := startTestWorker(t, nil, mqttClient, now)
+
+		garden := createExampleGarden()
+		zone := createExampleZone()
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone, &action.WaterAction{
+			Duration: &pkg.Duration{Duration: cycles.TotalWater()},
+			Cycles:   cycles,
+		}))
+		require.NoError(t, w.ExecuteZoneAction(context.Background(), garden, zone, &action.ZoneAction{Stop: &action.ZoneStopAction{}}))
+
+		jobs, err := w.scheduler.FindJobsByTag(zone.GetID(), cycleTag)
+		assert.Error(t, err)
+		assert.Empty(t, jobs)
+
+		_, err = w.AdvanceClock(time.Hour)
+		require.NoError(t, err)
+		// the first pulse is stopped and the others are never sent
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+	})
+}
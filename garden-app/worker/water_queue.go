@@ -0,0 +1,220 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+)
+
+// waterCompleteTimeout is how long to wait after a watering should have finished for the controller to publish
+// that it is complete. After this, the watering is considered complete so queued WaterActions are not stuck
+// if the message is lost
+const waterCompleteTimeout = time.Minute
+
+// gardenWaterQueue keeps track of the Zones that are currently watering in a Garden and the WaterActions waiting
+// for one of them to finish. Zones are tracked even if the Garden does not have MaxConcurrentZones so they can be
+// stopped
+type gardenWaterQueue struct {
+	watering []wateringZone
+	pending  []queuedWaterAction
+}
+
+// wateringZone is a Zone that is currently watering. The id is used to ignore timeouts for waterings that already
+// completed, the actionID is used to record when it completes, and the timeout is stopped when it is released
+type wateringZone struct {
+	id       uint64
+	zoneID   string
+	actionID string
+	timeout  *time.Timer
+}
+
+type queuedWaterAction struct {
+	garden *pkg.Garden
+	zone   *pkg.Zone
+	input  *action.WaterAction
+}
+
+// queueWaterAction reserves a watering slot for the Zone if the Garden has fewer than MaxConcurrentZones watering.
+// Otherwise, the WaterAction is queued and true is returned so it is started later by CompleteWaterAction
+func (w *Worker) queueWaterAction(g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) (uint64, bool) {
+	w.waterQueuesMtx.Lock()
+	defer w.waterQueuesMtx.Unlock()
+
+	q, ok := w.waterQueues[g.GetID()]
+	if !ok {
+		q = &gardenWaterQueue{}
+		w.waterQueues[g.GetID()] = q
+	}
+
+	if g.MaxConcurrentZones != nil && len(q.watering) >= int(*g.MaxConcurrentZones) {
+		q.pending = append(q.pending, queuedWaterAction{g, z, input})
+		return 0, true
+	}
+
+	return w.reserveWatering(q, g, z, input), false
+}
+
+// reserveWatering adds the Zone to the Garden's watering Zones and starts a timer to complete it in case the
+// controller does not publish a message when it is done. This must be called while holding waterQueuesMtx.
+// The timer does all of its work directly, so a virtual Clock does not wait for Jobs after it fires
+func (w *Worker) reserveWatering(q *gardenWaterQueue, g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) uint64 {
+	w.nextWateringID++
+	id := w.nextWateringID
+
+	timeout := w.clock.AfterFuncDirect(input.Duration.Duration+waterCompleteTimeout, func() {
+		if _, ok := w.releaseWatering(g.GetID(), func(wz wateringZone) bool { return wz.id == id }); ok {
+			w.logger.Debug("did not receive message that watering completed, so it is considered complete", "zone_id", z.GetID())
+			w.startQueuedWaterActions(g)
+		}
+	})
+	q.watering = append(q.watering, wateringZone{id, z.GetID(), input.ID, timeout})
+	return id
+}
+
+// CompleteWaterAction is used when a Garden's controller publishes that the Zone finished watering. The WaterAction's
+// ActionRecord is completed and, if the Garden has queued WaterActions, the next ones are started
+func (w *Worker) CompleteWaterAction(g *pkg.Garden, z *pkg.Zone) {
+	wz, ok := w.releaseWatering(g.GetID(), func(wz wateringZone) bool { return wz.zoneID == z.GetID() })
+	if !ok {
+		return
+	}
+	w.updateActionRecord(wz.actionID, pkg.ActionStatusCompleted, "")
+	w.startQueuedWaterActions(g)
+}
+
+// QueuedWaterActions returns the number of WaterActions waiting for one of the Garden's Zones to finish watering
+func (w *Worker) QueuedWaterActions(g *pkg.Garden) int {
+	w.waterQueuesMtx.Lock()
+	defer w.waterQueuesMtx.Unlock()
+
+	q, ok := w.waterQueues[g.GetID()]
+	if !ok {
+		return 0
+	}
+	return len(q.pending)
+}
+
+// clearWaterQueue removes the Garden's queued WaterActions and watering Zones. It is used after stopping all
+// watering and returns the number of queued WaterActions that were cancelled
+func (w *Worker) clearWaterQueue(g *pkg.Garden) int {
+	w.waterQueuesMtx.Lock()
+	q, ok := w.waterQueues[g.GetID()]
+	if !ok {
+		w.waterQueuesMtx.Unlock()
+		return 0
+	}
+	delete(w.waterQueues, g.GetID())
+	w.waterQueuesMtx.Unlock()
+
+	for _, wz := range q.watering {
+		wz.timeout.Stop()
+	}
+
+	w.cancelActionRecords(q.pending)
+	return len(q.pending)
+}
+
+// cancelQueuedWaterActions removes the Zone's queued WaterActions and returns the number that were cancelled
+func (w *Worker) cancelQueuedWaterActions(g *pkg.Garden, z *pkg.Zone) int {
+	w.waterQueuesMtx.Lock()
+	q, ok := w.waterQueues[g.GetID()]
+	if !ok {
+		w.waterQueuesMtx.Unlock()
+		return 0
+	}
+
+	pending := []queuedWaterAction{}
+	cancelled := []queuedWaterAction{}
+	for _, qa := range q.pending {
+		if qa.zone.GetID() != z.GetID() {
+			pending = append(pending, qa)
+		} else {
+			cancelled = append(cancelled, qa)
+		}
+	}
+	q.pending = pending
+	w.waterQueuesMtx.Unlock()
+
+	w.cancelActionRecords(cancelled)
+	return len(cancelled)
+}
+
+// cancelActionRecords records that the queued WaterActions were cancelled
+func (w *Worker) cancelActionRecords(cancelled []queuedWaterAction) {
+	for _, qa := range cancelled {
+		w.updateActionRecord(qa.input.ID, pkg.ActionStatusCancelled, "cancelled by StopAction")
+	}
+}
+
+// isWatering returns true if the Zone's WaterAction was sent to the controller and it has not finished yet
+func (w *Worker) isWatering(g *pkg.Garden, z *pkg.Zone) bool {
+	w.waterQueuesMtx.Lock()
+	defer w.waterQueuesMtx.Unlock()
+
+	q, ok := w.waterQueues[g.GetID()]
+	if !ok {
+		return false
+	}
+	for _, wz := range q.watering {
+		if wz.zoneID == z.GetID() {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseWatering removes the first watering Zone that matches, stops its timeout, and returns it with true if one was
+// removed
+func (w *Worker) releaseWatering(gardenID string, match func(wateringZone) bool) (wateringZone, bool) {
+	w.waterQueuesMtx.Lock()
+	defer w.waterQueuesMtx.Unlock()
+
+	q, ok := w.waterQueues[gardenID]
+	if !ok {
+		return wateringZone{}, false
+	}
+
+	for i, wz := range q.watering {
+		if match(wz) {
+			q.watering = append(q.watering[:i], q.watering[i+1:]...)
+			wz.timeout.Stop()
+			return wz, true
+		}
+	}
+	return wateringZone{}, false
+}
+
+// startQueuedWaterActions starts queued WaterActions until the Garden reaches MaxConcurrentZones again. The
+// Garden's latest MaxConcurrentZones is used in case it changed while WaterActions were queued
+func (w *Worker) startQueuedWaterActions(g *pkg.Garden) {
+	w.waterQueuesMtx.Lock()
+	q, ok := w.waterQueues[g.GetID()]
+	if !ok {
+		w.waterQueuesMtx.Unlock()
+		return
+	}
+
+	type startingWaterAction struct {
+		queuedWaterAction
+		id uint64
+	}
+	starting := []startingWaterAction{}
+	for len(q.pending) > 0 && (g.MaxConcurrentZones == nil || len(q.watering) < int(*g.MaxConcurrentZones)) {
+		next := q.pending[0]
+		q.pending = q.pending[1:]
+		id := w.reserveWatering(q, next.garden, next.zone, next.input)
+		starting = append(starting, startingWaterAction{next, id})
+	}
+	w.waterQueuesMtx.Unlock()
+
+	for _, s := range starting {
+		w.logger.Info("starting queued WaterAction", "garden_id", s.garden.GetID(), "zone_id", s.zone.GetID())
+		err := w.startWaterAction(context.Background(), s.garden, s.zone, s.input, s.id)
+		if err != nil {
+			w.logger.Error("error executing queued WaterAction", "zone_id", s.zone.GetID(), "error", err)
+			schedulerErrors.WithLabelValues(zoneLabels(s.zone)...).Inc()
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteWaterActionMaxConcurrentZones(t *testing.T) {
+	one := uint(1)
+	garden := createExampleGarden()
+	garden.MaxConcurrentZones = &one
+
+	zone1 := createExampleZone()
+	zone2 := createExampleZone()
+	zone2.ID = babyapi.ID{ID: xid.New()}
+	position := uint(1)
+	zone2.Position = &position
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+	mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil)
+
+	c := clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC))
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(c)
+
+	waterAction := &action.WaterAction{Duration: &pkg.Duration{Duration: time.Minute}}
+
+	require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone1, waterAction))
+	require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone2, waterAction))
+	mqttClient.AssertNumberOfCalls(t, "Publish", 1)
+	assert.Equal(t, 1, w.QueuedWaterActions(garden))
+
+	t.Run("StartQueuedAfterComplete", func(t *testing.T) {
+		w.CompleteWaterAction(garden, zone1)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+		assert.Equal(t, 0, w.QueuedWaterActions(garden))
+
+		// completing a Zone that is not watering does not start anything
+		w.CompleteWaterAction(garden, zone1)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+	})
+
+	t.Run("StartQueuedAfterTimeout", func(t *testing.T) {
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone1, waterAction))
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+
+		c.Advance(time.Minute, nil)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+
+		c.Advance(waterCompleteTimeout, nil)
+		mqttClient.AssertNumberOfCalls(t, "Publish", 3)
+		assert.Equal(t, 0, w.QueuedWaterActions(garden))
+	})
+
+	t.Run("StopAllClearsQueue", func(t *testing.T) {
+		mqttClient.On("StopAllTopic", "test-garden").Return("test-garden/command/stop_all", nil)
+		mqttClient.On("Publish", "test-garden/command/stop_all", mock.Anything).Return(nil)
+
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone2, waterAction))
+		assert.Equal(t, 1, w.QueuedWaterActions(garden))
+
+		require.NoError(t, w.ExecuteStopAction(garden, &action.StopAction{All: true}))
+		assert.Equal(t, 0, w.QueuedWaterActions(garden))
+	})
+}
+
+func TestExecuteWaterActionWithoutMaxConcurrentZones(t *testing.T) {
+	garden := createExampleGarden()
+	zone := createExampleZone()
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+	mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil)
+
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+
+	waterAction := &action.WaterAction{Duration: &pkg.Duration{Duration: time.Minute}}
+	require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone, waterAction))
+	require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone, waterAction))
+
+	mqttClient.AssertNumberOfCalls(t, "Publish", 2)
+	assert.Equal(t, 0, w.QueuedWaterActions(garden))
+}
+
+func TestExecuteZoneStopAction(t *testing.T) {
+	one := uint(1)
+	garden := createExampleGarden()
+	garden.MaxConcurrentZones = &one
+
+	zone1 := createExampleZone()
+	zone2 := createExampleZone()
+	zone2.ID = babyapi.ID{ID: xid.New()}
+	position := uint(1)
+	zone2.Position = &position
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+	mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil)
+	mqttClient.On("StopTopic", "test-garden").Return("test-garden/command/stop", nil)
+	mqttClient.On("Publish", "test-garden/command/stop", mock.Anything).Return(nil).Once()
+
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)))
+
+	waterAction := &action.WaterAction{Duration: &pkg.Duration{Duration: time.Minute}}
+	require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone1, waterAction))
+	require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone2, waterAction))
+	assert.Equal(t, 1, w.QueuedWaterActions(garden))
+
+	t.Run("QueuedZoneIsCancelledWithoutStopping", func(t *testing.T) {
+		require.NoError(t, w.ExecuteZoneAction(context.Background(), garden, zone2, &action.ZoneAction{Stop: &action.ZoneStopAction{}}))
+		assert.Equal(t, 0, w.QueuedWaterActions(garden))
+		mqttClient.AssertNotCalled(t, "Publish", "test-garden/command/stop", mock.Anything)
+	})
+
+	t.Run("WateringZoneIsStopped", func(t *testing.T) {
+		require.NoError(t, w.ExecuteZoneAction(context.Background(), garden, zone1, &action.ZoneAction{Stop: &action.ZoneStopAction{}}))
+		mqttClient.AssertCalled(t, "Publish", "test-garden/command/stop", mock.Anything)
+
+		// the Zone is no longer watering, so the next WaterAction starts immediately
+		require.NoError(t, w.ExecuteWaterAction(context.Background(), garden, zone2, waterAction))
+		assert.Equal(t, 0, w.QueuedWaterActions(garden))
+	})
+
+	mqttClient.AssertExpectations(t)
+}
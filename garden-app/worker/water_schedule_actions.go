@@ -2,19 +2,36 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/rs/xid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// ExecuteScheduledWaterAction will run ExecuteWaterAction after checking SkipCount and scaling based on weather data
-func (w *Worker) ExecuteScheduledWaterAction(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) error {
+// ExecuteScheduledWaterAction will run ExecuteWaterAction after checking SkipCount and scaling based on weather data.
+// During a BlackoutWindow, it is deferred until the window ends. Each scheduled watering starts a new trace
+func (w *Worker) ExecuteScheduledWaterAction(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) (err error) {
+	ctx, span := tracing.Start(context.Background(), "ExecuteScheduledWaterAction", trace.WithAttributes(
+		attribute.String("garden_id", g.GetID()),
+		attribute.String("zone_id", z.GetID()),
+		attribute.String("water_schedule_id", ws.GetID()),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if until, ok := w.BlackoutEnd(g, w.now()); ok {
+		return w.deferWaterAction(g, z, ws, until)
+	}
 	if z.SkipCount != nil && *z.SkipCount > 0 {
 		*z.SkipCount--
-		err := w.storageClient.Zones.Set(context.Background(), z)
+		err = w.storageClient.Zones.Set(ctx, z)
 		if err != nil {
 			return fmt.Errorf("unable to save Zone after decrementing SkipCount: %w", err)
 		}
@@ -22,27 +39,141 @@ func (w *Worker) ExecuteScheduledWaterAction(g *pkg.Garden, z *pkg.Zone, ws *pkg
 		w.logger.Info("skipping watering Zone because of SkipCount", "zone_id", z.GetID())
 		return nil
 	}
-	duration, err := w.exerciseWeatherControl(g, z, ws)
+	// Frost protection replaces the usual duration, so it is not scaled or limited
+	duration, skip, err := w.frostProtectionDuration(ctx, ws)
 	if err != nil {
-		w.logger.Error("error executing weather controls, continuing to water", "error", err)
-		duration = ws.Duration.Duration
+		return err
+	}
+	if duration == 0 && !skip {
+		duration, err = w.scheduledWaterDuration(ctx, g, z, ws)
+		if err != nil {
+			return err
+		}
 	}
 	if duration == 0 {
 		w.logger.Info("weather control determined that watering should be skipped")
 		return nil
 	}
 
-	return w.ExecuteWaterAction(g, z, &action.WaterAction{
+	budgeted, reason, err := w.applyWaterBudget(g, z, duration)
+	if err != nil {
+		w.logger.Error("error checking water budget, continuing to water", "error", err)
+	}
+	if reason != "" {
+		w.notifyWaterBudget(g, z, reason)
+		duration = budgeted
+	}
+	if duration == 0 {
+		return nil
+	}
+
+	// Pulses are not merged since they are not one continuous watering
+	rollback := func() {}
+	if ws.Cycles == nil {
+		duration, rollback = w.mergeZoneWatering(z, duration)
+		if duration == 0 {
+			w.logger.Info("skipping watering Zone because it is already being watered by another WaterSchedule", "zone_id", z.GetID())
+			return nil
+		}
+	}
+
+	waterAction := &action.WaterAction{
 		Duration: &pkg.Duration{Duration: duration},
+		Cycles:   ws.Cycles,
+	}
+	if ws.Fertilizer != nil {
+		waterAction.Fertilizer = &pkg.Duration{Duration: ws.Fertilizer.DoseDuration(duration)}
+	}
+
+	err = w.ExecuteWaterAction(ctx, g, z, waterAction)
+	if err != nil {
+		rollback()
+		return err
+	}
+
+	w.addScheduledAuditEntry("zone", z.GetID(), "water_action", map[string]string{
+		"duration":          duration.String(),
+		"water_schedule_id": ws.GetID(),
 	})
+	return nil
 }
 
-func (w *Worker) exerciseWeatherControl(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) (time.Duration, error) {
+// scheduledWaterDuration calculates the duration for a scheduled watering using the WaterSchedule's WeatherControl,
+// the Zone's scaling, and the WaterSchedule's limits. An error is only returned if a control's OnError policy fails
+// the watering
+func (w *Worker) scheduledWaterDuration(ctx context.Context, g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) (time.Duration, error) {
+	duration, err := w.exerciseWeatherControl(ctx, g, z, ws.ForZone(z))
+	switch {
+	case errors.Is(err, ErrWeatherControlFailed):
+		return 0, err
+	case err != nil:
+		w.logger.Error("error executing weather controls, continuing to water", "error", err)
+		duration = ws.Duration.Duration
+	}
+	if scale := z.WaterDurationScale(); scale != 1 {
+		duration = z.ScaleWaterDuration(duration)
+		w.logger.Info("scaled watering duration for Zone's soil type and crop coefficient", "zone_id", z.GetID(), "scale_factor", scale, "duration", duration)
+	}
+	if scale := g.SeasonalScale(w.now()); scale != 1 {
+		duration = pkg.ScaleDuration(duration, scale)
+		w.logger.Info("scaled watering duration for Garden's seasonal adjustment", "garden_id", g.GetID(), "scale_factor", scale, "duration", duration)
+	}
+	if clamped := ws.ClampDuration(duration); clamped != duration {
+		w.logger.Info("limited watering duration to WaterSchedule's min_duration or max_duration", "scaled_duration", duration, "duration", clamped)
+		duration = clamped
+	}
+	return duration, nil
+}
+
+// mergeZoneWatering handles overlapping waterings for a Zone with multiple WaterSchedules. Since the controller
+// runs WaterActions one after another, the returned duration only covers the time remaining after the Zone's
+// current watering finishes. This way, overlapping waterings are merged into one continuous watering that ends
+// when the latest one would have ended. The returned function restores the previous end time and must be called
+// if the watering is not actually started
+func (w *Worker) mergeZoneWatering(z *pkg.Zone, duration time.Duration) (time.Duration, func()) {
+	w.zoneWateringUntilMtx.Lock()
+	defer w.zoneWateringUntilMtx.Unlock()
+
+	now := w.now()
+	end := now.Add(duration)
+
+	wateringUntil, ok := w.zoneWateringUntil[z.GetID()]
+	rollback := func() {
+		w.zoneWateringUntilMtx.Lock()
+		defer w.zoneWateringUntilMtx.Unlock()
+
+		// Only roll back if another watering has not extended the end time since this one
+		if !w.zoneWateringUntil[z.GetID()].Equal(end) {
+			return
+		}
+		if ok {
+			w.zoneWateringUntil[z.GetID()] = wateringUntil
+		} else {
+			delete(w.zoneWateringUntil, z.GetID())
+		}
+	}
+
+	if !ok || !wateringUntil.After(now) {
+		w.zoneWateringUntil[z.GetID()] = end
+		return duration, rollback
+	}
+
+	if !end.After(wateringUntil) {
+		return 0, func() {}
+	}
+
+	w.zoneWateringUntil[z.GetID()] = end
+	return end.Sub(wateringUntil), rollback
+}
+
+// exerciseWeatherControl returns the WaterSchedule's duration after skipping or scaling it with the WeatherControl.
+// When a WeatherClient returns an error, the control's OnError policy is used. Other errors are returned
+func (w *Worker) exerciseWeatherControl(ctx context.Context, g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) (time.Duration, error) {
 	if !ws.HasWeatherControl() {
 		return ws.Duration.Duration, nil
 	}
 
-	skipMoisture, err := w.shouldMoistureSkip(g, z, ws)
+	skipMoisture, err := w.shouldMoistureSkip(ctx, g, z, ws)
 	if err != nil {
 		return 0, err
 	}
@@ -50,20 +181,55 @@ func (w *Worker) exerciseWeatherControl(g *pkg.Garden, z *pkg.Zone, ws *pkg.Wate
 		return 0, nil
 	}
 
-	duration, _ := w.ScaleWateringDuration(ws)
-	return duration, nil
+	// A forecast error should not prevent scaling since that is based on different data, unless the control's
+	// OnError policy skips or fails the watering
+	skipFrost, err := w.shouldFrostSkip(ctx, ws)
+	if err != nil {
+		w.logger.Warn("error checking frost forecast", "error", err)
+		skipFrost, err = w.onWeatherControlError("frost_control", ws.WeatherControl.Frost.OnError, err)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if skipFrost {
+		return 0, nil
+	}
+
+	skipForecast, err := w.shouldForecastSkip(ctx, ws, true)
+	if err != nil {
+		w.logger.Warn("error checking rain forecast", "error", err)
+		skipForecast, err = w.onWeatherControlError("rain_forecast_control", ws.WeatherControl.RainForecast.OnError, err)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if skipForecast {
+		return 0, nil
+	}
+
+	scaleFactor, errs := w.weatherScaleFactor(ctx, ws, true)
+	skipControl, err := w.applyWeatherControlErrors(errs)
+	if err != nil {
+		return 0, err
+	}
+	if skipControl != "" {
+		return 0, nil
+	}
+	return pkg.ScaleDuration(ws.Duration.Duration, scaleFactor), nil
 }
 
-func (w *Worker) shouldMoistureSkip(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) (bool, error) {
+func (w *Worker) shouldMoistureSkip(ctx context.Context, g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSchedule) (bool, error) {
 	if !ws.HasSoilMoistureControl() {
 		return false, nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), influxdb.QueryTimeout)
+	ctx, cancel := context.WithTimeout(ctx, influxdb.QueryTimeout)
 	defer cancel()
 
+	ctx, span := tracing.Start(ctx, "metrics.GetMoisture")
 	defer w.influxdbClient.Close()
 	moisture, err := w.influxdbClient.GetMoisture(ctx, *z.Position, g.TopicPrefix)
+	tracing.End(span, err)
 	if err != nil {
 		return false, fmt.Errorf("error getting Zone's moisture data: %w", err)
 	}
@@ -73,21 +239,110 @@ func (w *Worker) shouldMoistureSkip(g *pkg.Garden, z *pkg.Zone, ws *pkg.WaterSch
 	return moisture > float64(*ws.WeatherControl.SoilMoisture.MinimumMoisture), nil
 }
 
+// shouldForecastSkip returns true if the WaterSchedule's RainForecastControl expects enough rain to skip watering.
+// When retry is true, WeatherClient errors are retried
+func (w *Worker) shouldForecastSkip(ctx context.Context, ws *pkg.WaterSchedule, retry bool) (bool, error) {
+	if !ws.HasRainForecastControl() {
+		return false, nil
+	}
+
+	weatherClient, err := w.storageClient.GetWeatherClient(ws.WeatherControl.RainForecast.ClientID)
+	if err != nil {
+		return false, fmt.Errorf("error getting WeatherClient for RainForecastControl: %w", err)
+	}
+
+	forecastedRain, err := w.callWeatherClient(ctx, "GetForecastedRain", ws.WeatherControl.RainForecast.ClientID, retry, func() (float32, error) {
+		return weatherClient.GetForecastedRain(ws.WeatherControl.RainForecast.Ahead())
+	})
+	if err != nil {
+		return false, fmt.Errorf("error getting forecasted rain: %w", err)
+	}
+	w.logger.Info("got forecasted rain", "forecasted_rain", forecastedRain, "hours_ahead", *ws.WeatherControl.RainForecast.HoursAhead)
+
+	// if rain is expected to meet the threshold, skip watering
+	return ws.WeatherControl.RainForecast.ShouldSkip(forecastedRain), nil
+}
+
+// forecastFreeze returns true if the forecasted low temperature reaches the WaterSchedule's FrostControl threshold.
+// When retry is true, WeatherClient errors are retried
+func (w *Worker) forecastFreeze(ctx context.Context, ws *pkg.WaterSchedule, retry bool) (bool, error) {
+	if !ws.HasFrostControl() {
+		return false, nil
+	}
+
+	weatherClient, err := w.storageClient.GetWeatherClient(ws.WeatherControl.Frost.ClientID)
+	if err != nil {
+		return false, fmt.Errorf("error getting WeatherClient for FrostControl: %w", err)
+	}
+
+	forecastedLow, err := w.callWeatherClient(ctx, "GetForecastedLowTemperature", ws.WeatherControl.Frost.ClientID, retry, func() (float32, error) {
+		return weatherClient.GetForecastedLowTemperature(ws.WeatherControl.Frost.Ahead())
+	})
+	if err != nil {
+		return false, fmt.Errorf("error getting forecasted low temperature: %w", err)
+	}
+	w.logger.Info("got forecasted low temperature", "forecasted_low", forecastedLow, "hours_ahead", *ws.WeatherControl.Frost.HoursAhead)
+
+	return ws.WeatherControl.Frost.IsFreezing(forecastedLow), nil
+}
+
+// shouldFrostSkip returns true if the WaterSchedule's FrostControl inhibits watering and a freeze is forecasted
+func (w *Worker) shouldFrostSkip(ctx context.Context, ws *pkg.WaterSchedule) (bool, error) {
+	if !ws.HasFrostControl() || ws.WeatherControl.Frost.Mode != weather.FrostModeInhibit {
+		return false, nil
+	}
+	return w.forecastFreeze(ctx, ws, true)
+}
+
+// frostProtectionDuration returns the duration of frost protection watering if the WaterSchedule's FrostControl
+// protects from frost and a freeze is forecasted. Otherwise, it returns 0 and the usual duration is used. If the
+// forecast is not available, the FrostControl's OnError policy can skip watering or fail with an error
+func (w *Worker) frostProtectionDuration(ctx context.Context, ws *pkg.WaterSchedule) (time.Duration, bool, error) {
+	if !ws.HasFrostControl() || ws.WeatherControl.Frost.Mode != weather.FrostModeProtect {
+		return 0, false, nil
+	}
+
+	freeze, err := w.forecastFreeze(ctx, ws, true)
+	if err != nil {
+		w.logger.Warn("error checking frost forecast", "error", err)
+		skip, err := w.onWeatherControlError("frost_control", ws.WeatherControl.Frost.OnError, err)
+		return 0, skip, err
+	}
+	if !freeze {
+		return 0, false, nil
+	}
+
+	duration := ws.WeatherControl.Frost.ProtectDuration()
+	w.logger.Info("watering for frost protection since a freeze is forecasted", "duration", duration)
+	return duration, false, nil
+}
+
 // ScaleWateringDuration returns a new watering duration based on weather scaling. It will not return
-// any errors if they are encountered because there are multiple factors impacting watering
-func (w *Worker) ScaleWateringDuration(ws *pkg.WaterSchedule) (time.Duration, bool) {
+// any errors if they are encountered because there are multiple factors impacting watering. This is used to show
+// the expected duration, so WeatherClient errors are not retried. The duration is scaled with float64 precision, so it
+// is exactly the WaterSchedule's duration when nothing changes the scale factor
+func (w *Worker) ScaleWateringDuration(ctx context.Context, ws *pkg.WaterSchedule) (time.Duration, bool) {
+	scaleFactor, errs := w.weatherScaleFactor(ctx, ws, false)
+	return pkg.ScaleDuration(ws.Duration.Duration, scaleFactor), len(errs) > 0
+}
+
+// weatherScaleFactor returns the compounded scale factor from the WaterSchedule's TemperatureControl and RainControl.
+// A control that could not get weather data does not scale and its error is returned. When retry is true,
+// WeatherClient errors are retried
+func (w *Worker) weatherScaleFactor(ctx context.Context, ws *pkg.WaterSchedule, retry bool) (float32, []weatherControlError) {
 	scaleFactor := float32(1)
-	hadError := false
+	errs := []weatherControlError{}
 
 	if ws.HasTemperatureControl() {
 		weatherClient, err := w.storageClient.GetWeatherClient(ws.WeatherControl.Temperature.ClientID)
 		if err != nil {
-			hadError = true
 			w.logger.Warn("error getting WeatherClient for TemperatureControl", "error", err)
 		} else {
-			avgHighTemp, err := weatherClient.GetAverageHighTemperature(ws.Interval.Duration)
+			var avgHighTemp float32
+			avgHighTemp, err = w.callWeatherClient(ctx, "GetAverageHighTemperature", ws.WeatherControl.Temperature.ClientID, retry, func() (float32, error) {
+				return weatherClient.GetAverageHighTemperature(ws.Interval.Duration)
+			})
 			if err != nil {
-				hadError = true
 				w.logger.Warn("error getting average high temperatures", "error", err)
 			} else {
 				scaleFactor = ws.WeatherControl.Temperature.Scale(avgHighTemp)
@@ -98,17 +353,21 @@ func (w *Worker) ScaleWateringDuration(ws *pkg.WaterSchedule) (time.Duration, bo
 				).Info("weather client calculated the average daily high temperature and resulting scale factor")
 			}
 		}
+		if err != nil {
+			errs = append(errs, weatherControlError{"temperature_control", ws.WeatherControl.Temperature.OnError, err})
+		}
 	}
 
 	if ws.HasRainControl() {
 		weatherClient, err := w.storageClient.GetWeatherClient(ws.WeatherControl.Rain.ClientID)
 		if err != nil {
-			hadError = true
 			w.logger.Warn("error getting WeatherClient for RainControl", "error", err)
 		} else {
-			totalRain, err := weatherClient.GetTotalRain(ws.Interval.Duration)
+			var totalRain float32
+			totalRain, err = w.callWeatherClient(ctx, "GetTotalRain", ws.WeatherControl.Rain.ClientID, retry, func() (float32, error) {
+				return weatherClient.GetTotalRain(ws.Interval.Duration)
+			})
 			if err != nil {
-				hadError = true
 				w.logger.Warn("error getting rain data", "error", err)
 			} else {
 				rainScaleFactor := ws.WeatherControl.Rain.InvertedScaleDownOnly(totalRain)
@@ -120,9 +379,34 @@ func (w *Worker) ScaleWateringDuration(ws *pkg.WaterSchedule) (time.Duration, bo
 				scaleFactor *= rainScaleFactor
 			}
 		}
+		if err != nil {
+			errs = append(errs, weatherControlError{"rain_control", ws.WeatherControl.Rain.OnError, err})
+		}
 	}
 
 	w.logger.Info("compounded scale factor", "compound_scale_factor", scaleFactor)
 
-	return time.Duration(float32(ws.Duration.Duration) * scaleFactor), hadError
+	return scaleFactor, errs
+}
+
+// applyWeatherControlErrors uses the OnError policy of each control that could not get weather data for scaling. It
+// returns the name of the control that skips watering or an error if the WaterAction should fail
+func (w *Worker) applyWeatherControlErrors(errs []weatherControlError) (string, error) {
+	for _, e := range errs {
+		skip, err := w.onWeatherControlError(e.control, e.onError, e.err)
+		if err != nil {
+			return "", err
+		}
+		if skip {
+			return e.control, nil
+		}
+	}
+	return "", nil
+}
+
+// startWeatherClientSpan starts a span for a request to the WeatherClient
+func startWeatherClientSpan(ctx context.Context, method string, clientID xid.ID) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "weather."+method, trace.WithAttributes(
+		attribute.String("weather_client_id", clientID.String()),
+	))
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
@@ -25,6 +26,7 @@ func TestExecuteScheduledWaterAction(t *testing.T) {
 		TopicPrefix: "garden",
 	}
 	weatherClientID, _ := xid.FromString("c5cvhpcbcv45e8bp16dg")
+	forecastErrorClientID, _ := xid.FromString("c5cvhpcbcv45e8bp16e0")
 	temperatureControl := &weather.ScaleControl{
 		BaselineValue: float32Pointer(70),
 		Factor:        float32Pointer(0.5),
@@ -38,7 +40,37 @@ func TestExecuteScheduledWaterAction(t *testing.T) {
 		ClientID:      weatherClientID,
 	}
 
+	twelve := 12
+	rainForecastControl := &weather.RainForecastControl{
+		Threshold:  float32Pointer(5),
+		HoursAhead: &twelve,
+		ClientID:   weatherClientID,
+	}
+
+	one := 1
+	frostControl := func(mode string) *weather.FrostControl {
+		return &weather.FrostControl{
+			Mode:           mode,
+			Threshold:      float32Pointer(0),
+			HoursAhead:     &twelve,
+			ProtectMinutes: &one,
+			ClientID:       weatherClientID,
+		}
+	}
+	setFrostForecast := func(t *testing.T, sc *storage.Client, low float32) {
+		err := sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+			ID:   babyapi.ID{ID: weatherClientID},
+			Type: "fake",
+			Options: map[string]interface{}{
+				"rain_interval":            "24h",
+				"forecast_low_temperature": low,
+			},
+		})
+		assert.NoError(t, err)
+	}
+
 	fifty := 50
+	skipRainControl := true
 
 	tests := []struct {
 		name          string
@@ -165,6 +197,34 @@ func TestExecuteScheduledWaterAction(t *testing.T) {
 			},
 			"",
 		},
+		{
+			"SkipRainControlIgnoresRainScaling",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Second},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					Rain: rainControl,
+				},
+			},
+			&pkg.Zone{
+				Position:        uintPointer(0),
+				SkipRainControl: &skipRainControl,
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				err := sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+					ID:   babyapi.ID{ID: weatherClientID},
+					Type: "fake",
+					Options: map[string]interface{}{
+						"rain_mm":       50,
+						"rain_interval": "24h",
+					},
+				})
+				assert.NoError(t, err)
+				mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":1000,"id":"00000000000000000000","position":0}`)).Return(nil)
+			},
+			"",
+		},
 		{
 			"SuccessfulNoRainScaling",
 			&pkg.WaterSchedule{
@@ -525,6 +585,229 @@ func TestExecuteScheduledWaterAction(t *testing.T) {
 			},
 			"",
 		},
+		{
+			"SuccessfulRainForecastSkip",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Second},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					RainForecast: rainForecastControl,
+				},
+			},
+			&pkg.Zone{
+				Position: uintPointer(0),
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				err := sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+					ID:   babyapi.ID{ID: weatherClientID},
+					Type: "fake",
+					Options: map[string]interface{}{
+						"rain_interval":    "24h",
+						"forecast_rain_mm": 10,
+					},
+				})
+				assert.NoError(t, err)
+				// No MQTT calls made
+			},
+			"",
+		},
+		{
+			"SkipRainControlIgnoresRainForecast",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Second},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					RainForecast: rainForecastControl,
+				},
+			},
+			&pkg.Zone{
+				Position:        uintPointer(0),
+				SkipRainControl: &skipRainControl,
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				err := sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+					ID:   babyapi.ID{ID: weatherClientID},
+					Type: "fake",
+					Options: map[string]interface{}{
+						"rain_interval":    "24h",
+						"forecast_rain_mm": 10,
+					},
+				})
+				assert.NoError(t, err)
+				mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":1000,"id":"00000000000000000000","position":0}`)).Return(nil)
+			},
+			"",
+		},
+		{
+			"SuccessfulRainForecastBelowThreshold",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Second},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					RainForecast: rainForecastControl,
+				},
+			},
+			&pkg.Zone{
+				Position: uintPointer(0),
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				err := sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+					ID:   babyapi.ID{ID: weatherClientID},
+					Type: "fake",
+					Options: map[string]interface{}{
+						"rain_interval":    "24h",
+						"forecast_rain_mm": 2,
+					},
+				})
+				assert.NoError(t, err)
+				mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":1000,"id":"00000000000000000000","position":0}`)).Return(nil)
+			},
+			"",
+		},
+		{
+			"RainForecastErrorStillWaters",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Second},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					RainForecast: rainForecastControl,
+				},
+			},
+			&pkg.Zone{
+				Position: uintPointer(0),
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				err := sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+					ID:   babyapi.ID{ID: weatherClientID},
+					Type: "fake",
+					Options: map[string]interface{}{
+						"rain_interval": "24h",
+						"error":         "weather client error",
+					},
+				})
+				assert.NoError(t, err)
+				mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":1000,"id":"00000000000000000000","position":0}`)).Return(nil)
+			},
+			"",
+		},
+		{
+			"RainForecastErrorStillScales",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Second},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					Rain: rainControl,
+					RainForecast: &weather.RainForecastControl{
+						Threshold:  float32Pointer(5),
+						HoursAhead: &twelve,
+						ClientID:   forecastErrorClientID,
+					},
+				},
+			},
+			&pkg.Zone{
+				Position: uintPointer(0),
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				err := sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+					ID:   babyapi.ID{ID: weatherClientID},
+					Type: "fake",
+					Options: map[string]interface{}{
+						"rain_mm":       50,
+						"rain_interval": "24h",
+					},
+				})
+				assert.NoError(t, err)
+				err = sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+					ID:   babyapi.ID{ID: forecastErrorClientID},
+					Type: "fake",
+					Options: map[string]interface{}{
+						"rain_interval": "24h",
+						"error":         "forecast error",
+					},
+				})
+				assert.NoError(t, err)
+				// Rain scaling still skips watering, so no MQTT calls are made
+			},
+			"",
+		},
+		{
+			"FrostProtectionReplacesDuration",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Hour},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					Frost: frostControl(weather.FrostModeProtect),
+				},
+			},
+			&pkg.Zone{
+				Position: uintPointer(0),
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				setFrostForecast(t, sc, -2)
+				mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":60000,"id":"00000000000000000000","position":0}`)).Return(nil)
+			},
+			"",
+		},
+		{
+			"FrostProtectionNotNeeded",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Hour},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					Frost: frostControl(weather.FrostModeProtect),
+				},
+			},
+			&pkg.Zone{
+				Position: uintPointer(0),
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				setFrostForecast(t, sc, 5)
+				mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":3600000,"id":"00000000000000000000","position":0}`)).Return(nil)
+			},
+			"",
+		},
+		{
+			"FrostInhibitSkip",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Hour},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					Frost: frostControl(weather.FrostModeInhibit),
+				},
+			},
+			&pkg.Zone{
+				Position: uintPointer(0),
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				setFrostForecast(t, sc, 0)
+				// No MQTT calls made
+			},
+			"",
+		},
+		{
+			"FrostInhibitAboveThreshold",
+			&pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Hour},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					Frost: frostControl(weather.FrostModeInhibit),
+				},
+			},
+			&pkg.Zone{
+				Position: uintPointer(0),
+			},
+			func(mqttClient *mqtt.MockClient, influxdbClient *influxdb.MockClient, sc *storage.Client) {
+				setFrostForecast(t, sc, 5)
+				mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+				mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":3600000,"id":"00000000000000000000","position":0}`)).Return(nil)
+			},
+			"",
+		},
 		{
 			"SkipCount>1WillSkip",
 			&pkg.WaterSchedule{
@@ -562,7 +845,11 @@ func TestExecuteScheduledWaterAction(t *testing.T) {
 			influxdbClient := new(influxdb.MockClient)
 			tt.setupMock(mqttClient, influxdbClient, sc)
 
-			err = NewWorker(sc, influxdbClient, mqttClient, slog.Default()).ExecuteScheduledWaterAction(garden, tt.zone, tt.waterSchedule)
+			w := NewWorker(sc, influxdbClient, mqttClient, slog.Default())
+			// weather client errors are not retried so the tests do not wait for backoff
+			w.SetWeatherRetry(WeatherRetryConfig{Retries: -1})
+
+			err = w.ExecuteScheduledWaterAction(garden, tt.zone, tt.waterSchedule)
 			if tt.expectedError != "" {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError, err.Error())
@@ -574,3 +861,250 @@ func TestExecuteScheduledWaterAction(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteScheduledWaterActionMergesOverlapping(t *testing.T) {
+	garden := &pkg.Garden{
+		ID:          babyapi.ID{ID: id},
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	zone := &pkg.Zone{
+		ID:       babyapi.ID{ID: id},
+		Position: uintPointer(0),
+	}
+	newWaterSchedule := func(d time.Duration) *pkg.WaterSchedule {
+		return &pkg.WaterSchedule{Duration: &pkg.Duration{Duration: d}}
+	}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":10000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":5000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":3000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+
+	c := clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC))
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(c)
+
+	// First watering runs for the full duration
+	err := w.ExecuteScheduledWaterAction(garden, zone, newWaterSchedule(10*time.Second))
+	assert.NoError(t, err)
+
+	// Overlapping watering only extends the current watering
+	err = w.ExecuteScheduledWaterAction(garden, zone, newWaterSchedule(15*time.Second))
+	assert.NoError(t, err)
+
+	// Watering that ends before the current watering is skipped
+	err = w.ExecuteScheduledWaterAction(garden, zone, newWaterSchedule(5*time.Second))
+	assert.NoError(t, err)
+
+	// After the current watering ends, the next watering runs for the full duration
+	c.Advance(time.Minute, nil)
+	err = w.ExecuteScheduledWaterAction(garden, zone, newWaterSchedule(3*time.Second))
+	assert.NoError(t, err)
+
+	mqttClient.AssertExpectations(t)
+}
+
+func TestExecuteScheduledWaterActionScalesForZone(t *testing.T) {
+	garden := &pkg.Garden{
+		ID:          babyapi.ID{ID: id},
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	cropCoefficient := float32(0.8)
+	zone := &pkg.Zone{
+		ID:              babyapi.ID{ID: id},
+		Position:        uintPointer(0),
+		SoilType:        pkg.SoilTypeClay,
+		CropCoefficient: &cropCoefficient,
+	}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	// 10s * 1.25 for clay * 0.8 crop coefficient
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":10000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+	// 10s * 0.75 for sand
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":7500,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+
+	c := clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC))
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(c)
+
+	ws := &pkg.WaterSchedule{Duration: &pkg.Duration{Duration: 10 * time.Second}}
+	err := w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	c.Advance(time.Minute, nil)
+	zone.SoilType = pkg.SoilTypeSand
+	zone.CropCoefficient = nil
+	err = w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	mqttClient.AssertExpectations(t)
+}
+
+func TestExecuteScheduledWaterActionSeasonalAdjustment(t *testing.T) {
+	garden := &pkg.Garden{
+		ID:                 babyapi.ID{ID: id},
+		Name:               "garden",
+		TopicPrefix:        "garden",
+		TimeZone:           "UTC",
+		SeasonalAdjustment: pkg.SeasonalAdjustment{"january": 50, "february": 0},
+	}
+	zone := &pkg.Zone{
+		ID:       babyapi.ID{ID: id},
+		Position: uintPointer(0),
+		SoilType: pkg.SoilTypeClay,
+	}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	// 10s * 1.25 for clay * 0.5 for january
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":6250,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+
+	c := clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC))
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(c)
+
+	ws := &pkg.WaterSchedule{Duration: &pkg.Duration{Duration: 10 * time.Second}}
+	err := w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	// watering is skipped in february
+	c.Advance(31*24*time.Hour, nil)
+	err = w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	mqttClient.AssertExpectations(t)
+}
+
+func TestExecuteScheduledWaterActionLimitsDuration(t *testing.T) {
+	garden := &pkg.Garden{
+		ID:          babyapi.ID{ID: id},
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	zone := &pkg.Zone{
+		ID:       babyapi.ID{ID: id},
+		Position: uintPointer(0),
+		SoilType: pkg.SoilTypeClay,
+	}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	// 10s * 1.25 for clay is limited to the 11s max_duration
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":11000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)))
+
+	ws := &pkg.WaterSchedule{
+		Duration:    &pkg.Duration{Duration: 10 * time.Second},
+		MaxDuration: &pkg.Duration{Duration: 11 * time.Second},
+	}
+	err := w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	mqttClient.AssertExpectations(t)
+}
+
+func TestExecuteScheduledWaterActionWithFertilizer(t *testing.T) {
+	garden := &pkg.Garden{
+		ID:          babyapi.ID{ID: id},
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	zone := &pkg.Zone{
+		ID:       babyapi.ID{ID: id},
+		Position: uintPointer(0),
+		SoilType: pkg.SoilTypeClay,
+	}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	// 10s * 1.25 for clay, then 20% of the scaled duration for fertilizer
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":12500,"id":"c5cvhpcbcv45e8bp16dg","position":0,"fertilizer":2500}`)).Return(nil).Once()
+	// a fixed fertilizer duration is limited to the watering duration
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":12500,"id":"c5cvhpcbcv45e8bp16dg","position":0,"fertilizer":12500}`)).Return(nil).Once()
+
+	c := clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC))
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(c)
+
+	ratio := float32(0.2)
+	ws := &pkg.WaterSchedule{
+		Duration:   &pkg.Duration{Duration: 10 * time.Second},
+		Fertilizer: &pkg.FertilizerSchedule{Ratio: &ratio},
+	}
+	err := w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	c.Advance(time.Minute, nil)
+	ws.Fertilizer = &pkg.FertilizerSchedule{Duration: &pkg.Duration{Duration: time.Minute}}
+	err = w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	mqttClient.AssertExpectations(t)
+}
+
+func TestExecuteScheduledWaterActionPublishErrorDoesNotMerge(t *testing.T) {
+	garden := &pkg.Garden{
+		ID:          babyapi.ID{ID: id},
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	zone := &pkg.Zone{
+		ID:       babyapi.ID{ID: id},
+		Position: uintPointer(0),
+	}
+	ws := &pkg.WaterSchedule{Duration: &pkg.Duration{Duration: 10 * time.Second}}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":10000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(errors.New("publish error")).Once()
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":10000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(nil).Once()
+
+	c := clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC))
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(c)
+
+	err := w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.Error(t, err)
+
+	// The failed watering is not counted, so a retry still runs for the full duration
+	err = w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	mqttClient.AssertExpectations(t)
+}
+
+func TestExecuteScheduledWaterActionQueuedPublishMerges(t *testing.T) {
+	garden := &pkg.Garden{
+		ID:          babyapi.ID{ID: id},
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	zone := &pkg.Zone{
+		ID:       babyapi.ID{ID: id},
+		Position: uintPointer(0),
+	}
+	ws := &pkg.WaterSchedule{Duration: &pkg.Duration{Duration: 10 * time.Second}}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	mqttClient.On("Publish", "garden/action/water", []byte(`{"duration":10000,"id":"c5cvhpcbcv45e8bp16dg","position":0}`)).Return(mqtt.ErrPublishQueued).Once()
+
+	c := clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC))
+	w := NewWorker(nil, new(influxdb.MockClient), mqttClient, slog.Default())
+	w.SetClock(c)
+
+	err := w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	// The queued watering is still sent later, so the overlapping watering is skipped
+	err = w.ExecuteScheduledWaterAction(garden, zone, ws)
+	assert.NoError(t, err)
+
+	mqttClient.AssertExpectations(t)
+}
@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+)
+
+// Reasons that a SimulatedWatering is expected to be skipped
+const (
+	SkipReasonSkipCount     = "skip_count"
+	SkipReasonZoneSkipCount = "zone_skip_count"
+	SkipReasonRainForecast  = "rain_forecast"
+	SkipReasonFrost         = "frost"
+)
+
+// WaterScheduleSimulation is the projected result of a WaterSchedule using the current weather data
+type WaterScheduleSimulation struct {
+	// ScaleFactor is the compounded scale factor from the WaterSchedule's TemperatureControl and RainControl
+	ScaleFactor float32
+	Waterings   []SimulatedWatering
+}
+
+// SimulatedWatering is a projected scheduled watering. The Duration is after weather scaling, but before each
+// Zone's and Garden's scaling and the WaterSchedule's limits. SkipReason is set if the watering is expected to be skipped
+type SimulatedWatering struct {
+	Time            time.Time
+	Duration        time.Duration
+	FrostProtection bool
+	SkipReason      string
+	Zones           []SimulatedZoneWatering
+}
+
+// SimulatedZoneWatering is the projected watering for a Zone using the WaterSchedule
+type SimulatedZoneWatering struct {
+	Zone       *pkg.Zone
+	Duration   time.Duration
+	SkipReason string
+}
+
+// SimulateWaterSchedule projects the WaterSchedule's waterings from now until the end time using the current weather
+// data. Temperature and rain scaling use the current data for every watering since there is no way to know the
+// future data. Rain forecast and frost controls only apply to waterings within their HoursAhead. Soil moisture and
+// BlackoutWindows are not included
+func (w *Worker) SimulateWaterSchedule(ws *pkg.WaterSchedule, end time.Time) (*WaterScheduleSimulation, error) {
+	result := &WaterScheduleSimulation{
+		ScaleFactor: 1,
+		Waterings:   []SimulatedWatering{},
+	}
+
+	next := w.nextJobRun(ws)
+	if next == nil {
+		return result, nil
+	}
+
+	times, skipped, err := w.waterTimes(ws, *next, end, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := w.storageClient.GetZonesUsingWaterSchedule(ws.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("error getting Zones for WaterSchedule: %w", err)
+	}
+
+	now := w.now()
+	duration := ws.Duration.Duration
+	if ws.HasTemperatureControl() || ws.HasRainControl() {
+		result.ScaleFactor, _ = w.weatherScaleFactor(context.Background(), ws, false)
+		duration = pkg.ScaleDuration(duration, result.ScaleFactor)
+	}
+
+	// Zones that skip rain control are only scaled by temperature and still water when rain is forecasted
+	coveredDuration := duration
+	if ws.HasRainControl() && slices.ContainsFunc(zones, func(zg *pkg.ZoneAndGarden) bool { return zg.Zone.SkipsRainControl() }) {
+		scaleFactor, _ := w.weatherScaleFactor(context.Background(), ws.WithoutRainControl(), false)
+		coveredDuration = pkg.ScaleDuration(ws.Duration.Duration, scaleFactor)
+	}
+
+	var rainForecastUntil time.Time
+	skipForecast, err := w.shouldForecastSkip(context.Background(), ws, false)
+	if err != nil {
+		w.logger.Warn("error checking rain forecast for simulation", "error", err)
+	}
+	if skipForecast {
+		rainForecastUntil = now.Add(ws.WeatherControl.RainForecast.Ahead())
+	}
+
+	var freezeUntil time.Time
+	freeze, err := w.forecastFreeze(context.Background(), ws, false)
+	if err != nil {
+		w.logger.Warn("error checking frost forecast for simulation", "error", err)
+	}
+	if freeze {
+		freezeUntil = now.Add(ws.WeatherControl.Frost.Ahead())
+	}
+
+	// Zone SkipCounts are decremented for each watering, so they are tracked across the simulation
+	zoneSkipCounts := map[string]uint{}
+	for _, zg := range zones {
+		if zg.Zone.SkipCount != nil {
+			zoneSkipCounts[zg.Zone.GetID()] = *zg.Zone.SkipCount
+		}
+	}
+
+	// Skipped times are always before the other times
+	for _, t := range skipped {
+		result.Waterings = append(result.Waterings, SimulatedWatering{
+			Time:       t,
+			SkipReason: SkipReasonSkipCount,
+			Zones:      []SimulatedZoneWatering{},
+		})
+	}
+
+	for _, t := range times {
+		watering := SimulatedWatering{
+			Time:     t,
+			Duration: duration,
+			Zones:    []SimulatedZoneWatering{},
+		}
+
+		frostProtection := false
+		if t.Before(freezeUntil) {
+			switch ws.WeatherControl.Frost.Mode {
+			case weather.FrostModeInhibit:
+				watering.SkipReason = SkipReasonFrost
+			case weather.FrostModeProtect:
+				frostProtection = true
+				watering.FrostProtection = true
+				watering.Duration = ws.WeatherControl.Frost.ProtectDuration()
+			}
+		}
+		if watering.SkipReason == "" && !frostProtection && t.Before(rainForecastUntil) {
+			watering.SkipReason = SkipReasonRainForecast
+		}
+		if watering.SkipReason != "" {
+			watering.Duration = 0
+		}
+
+		for _, zg := range zones {
+			zoneWatering := SimulatedZoneWatering{Zone: zg.Zone}
+
+			skipReason, zoneDuration := watering.SkipReason, watering.Duration
+			if zg.Zone.SkipsRainControl() && !frostProtection {
+				if skipReason == SkipReasonRainForecast {
+					skipReason = ""
+				}
+				if skipReason == "" {
+					zoneDuration = coveredDuration
+				}
+			}
+
+			switch {
+			case zoneSkipCounts[zg.Zone.GetID()] > 0:
+				zoneSkipCounts[zg.Zone.GetID()]--
+				zoneWatering.SkipReason = SkipReasonZoneSkipCount
+			case skipReason != "":
+				zoneWatering.SkipReason = skipReason
+			case frostProtection:
+				// Frost protection replaces the usual duration, so it is not scaled or limited
+				zoneWatering.Duration = watering.Duration
+			case zoneDuration > 0:
+				scaled := pkg.ScaleDuration(zg.Zone.ScaleWaterDuration(zoneDuration), zg.Garden.SeasonalScale(t))
+				zoneWatering.Duration = ws.ClampDuration(scaled)
+			}
+
+			watering.Zones = append(watering.Zones, zoneWatering)
+		}
+
+		result.Waterings = append(result.Waterings, watering)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,196 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateWaterSchedule(t *testing.T) {
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	twelve := 12
+	one := 1
+
+	type expectedWatering struct {
+		duration     time.Duration
+		skipReason   string
+		zoneDuration time.Duration
+		zoneSkip     string
+	}
+
+	tests := []struct {
+		name           string
+		weatherOptions map[string]interface{}
+		weatherControl *weather.Control
+		skipCount      uint
+		zoneSkipCount  uint
+		expected       []expectedWatering
+	}{
+		{
+			"NoWeatherControl",
+			nil,
+			nil,
+			0,
+			0,
+			[]expectedWatering{
+				{time.Hour, "", time.Hour, ""},
+				{time.Hour, "", time.Hour, ""},
+				{time.Hour, "", time.Hour, ""},
+			},
+		},
+		{
+			"RainScaling",
+			map[string]interface{}{"rain_mm": 25},
+			&weather.Control{
+				Rain: &weather.ScaleControl{
+					BaselineValue: float32Pointer(0),
+					Factor:        float32Pointer(0),
+					Range:         float32Pointer(50),
+					ClientID:      id,
+				},
+			},
+			0,
+			0,
+			[]expectedWatering{
+				{30 * time.Minute, "", 30 * time.Minute, ""},
+				{30 * time.Minute, "", 30 * time.Minute, ""},
+				{30 * time.Minute, "", 30 * time.Minute, ""},
+			},
+		},
+		{
+			"RainForecastOnlySkipsWithinHoursAhead",
+			map[string]interface{}{"forecast_rain_mm": 10},
+			&weather.Control{
+				RainForecast: &weather.RainForecastControl{
+					Threshold:  float32Pointer(5),
+					HoursAhead: &twelve,
+					ClientID:   id,
+				},
+			},
+			0,
+			0,
+			[]expectedWatering{
+				{0, SkipReasonRainForecast, 0, SkipReasonRainForecast},
+				{time.Hour, "", time.Hour, ""},
+				{time.Hour, "", time.Hour, ""},
+			},
+		},
+		{
+			"FrostProtection",
+			map[string]interface{}{"forecast_low_temperature": -2},
+			&weather.Control{
+				Frost: &weather.FrostControl{
+					Mode:           weather.FrostModeProtect,
+					Threshold:      float32Pointer(0),
+					HoursAhead:     &twelve,
+					ProtectMinutes: &one,
+					ClientID:       id,
+				},
+			},
+			0,
+			0,
+			[]expectedWatering{
+				{time.Minute, "", time.Minute, ""},
+				{time.Hour, "", time.Hour, ""},
+				{time.Hour, "", time.Hour, ""},
+			},
+		},
+		{
+			"SkipCount",
+			nil,
+			nil,
+			1,
+			0,
+			[]expectedWatering{
+				{0, SkipReasonSkipCount, 0, ""},
+				{time.Hour, "", time.Hour, ""},
+				{time.Hour, "", time.Hour, ""},
+			},
+		},
+		{
+			"ZoneSkipCount",
+			nil,
+			nil,
+			0,
+			2,
+			[]expectedWatering{
+				{time.Hour, "", 0, SkipReasonZoneSkipCount},
+				{time.Hour, "", 0, SkipReasonZoneSkipCount},
+				{time.Hour, "", time.Hour, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			options := map[string]interface{}{"rain_interval": "24h"}
+			for k, v := range tt.weatherOptions {
+				options[k] = v
+			}
+			err = storageClient.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+				ID:      babyapi.ID{ID: id},
+				Type:    "fake",
+				Options: options,
+			})
+			require.NoError(t, err)
+
+			zone := createExampleZone()
+			if tt.zoneSkipCount > 0 {
+				zone.SkipCount = &tt.zoneSkipCount
+			}
+			require.NoError(t, storageClient.Gardens.Set(context.Background(), createExampleGarden()))
+			require.NoError(t, storageClient.Zones.Set(context.Background(), zone))
+
+			mqttClient := new(mqtt.MockClient)
+			mqttClient.On("Disconnect", uint(100)).Return()
+
+			worker := NewWorker(storageClient, nil, mqttClient, slog.Default())
+			worker.SetClock(clock.NewVirtual(start))
+			worker.StartAsync()
+			defer worker.Stop()
+
+			ws := createExampleWaterSchedule()
+			ws.Duration = &pkg.Duration{Duration: time.Hour}
+			ws.WeatherControl = tt.weatherControl
+			ws.SkipCount = tt.skipCount
+			ws.StartDate = &start
+			ws.StartTime = pkg.NewStartTime(start.Add(9 * time.Hour))
+			require.NoError(t, storageClient.WaterSchedules.Set(context.Background(), ws))
+			require.NoError(t, worker.ScheduleWaterAction(ws))
+
+			simulation, err := worker.SimulateWaterSchedule(ws, start.AddDate(0, 0, 3))
+			require.NoError(t, err)
+			require.Len(t, simulation.Waterings, len(tt.expected))
+
+			for i, expected := range tt.expected {
+				watering := simulation.Waterings[i]
+				assert.Equal(t, start.AddDate(0, 0, i).Add(9*time.Hour), watering.Time.UTC())
+				assert.Equal(t, expected.duration, watering.Duration)
+				assert.Equal(t, expected.skipReason, watering.SkipReason)
+
+				if expected.skipReason == SkipReasonSkipCount {
+					assert.Empty(t, watering.Zones)
+					continue
+				}
+				require.Len(t, watering.Zones, 1)
+				assert.Equal(t, expected.zoneDuration, watering.Zones[0].Duration)
+				assert.Equal(t, expected.zoneSkip, watering.Zones[0].SkipReason)
+			}
+		})
+	}
+}
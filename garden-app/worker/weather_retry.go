@@ -0,0 +1,148 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/xid"
+)
+
+const (
+	// DefaultWeatherRetries is used when the WeatherRetryConfig does not set Retries
+	DefaultWeatherRetries = 2
+	// DefaultWeatherBackoff is used when the WeatherRetryConfig does not set Backoff
+	DefaultWeatherBackoff = time.Second
+	// DefaultWeatherMaxBackoff is used when the WeatherRetryConfig does not set MaxBackoff
+	DefaultWeatherMaxBackoff = 10 * time.Second
+)
+
+// ErrWeatherControlFailed is returned when a WeatherClient error fails a WaterAction because the control's OnError
+// is weather.OnErrorFail
+var ErrWeatherControlFailed = errors.New("weather control failed")
+
+var (
+	weatherClientRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "garden_app",
+		Name:      "weather_client_retries",
+		Help:      "count of weather client calls that were retried after an error when deciding how to water",
+	}, []string{"function"})
+	weatherControlErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "garden_app",
+		Name:      "weather_control_errors",
+		Help:      "count of weather controls that could not get weather data after retrying, by the on_error policy that was used",
+	}, []string{"control", "on_error"})
+)
+
+// WeatherRetryConfig retries WeatherClient calls that fail when deciding how to water, so a transient error from a
+// weather API does not change the watering. After the retries are used, each control's OnError policy is used
+type WeatherRetryConfig struct {
+	// Retries is how many times a failed call is retried. A negative value disables retrying
+	Retries int `mapstructure:"retries"`
+	// Backoff is the time to wait before the first retry. It doubles for each retry after that
+	Backoff time.Duration `mapstructure:"backoff"`
+	// MaxBackoff limits the time to wait between retries
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+}
+
+// retries returns the Retries or its default
+func (c WeatherRetryConfig) retries() int {
+	switch {
+	case c.Retries < 0:
+		return 0
+	case c.Retries == 0:
+		return DefaultWeatherRetries
+	default:
+		return c.Retries
+	}
+}
+
+// backoff returns the Backoff or its default
+func (c WeatherRetryConfig) backoff() time.Duration {
+	if c.Backoff > 0 {
+		return c.Backoff
+	}
+	return DefaultWeatherBackoff
+}
+
+// maxBackoff returns the MaxBackoff or its default
+func (c WeatherRetryConfig) maxBackoff() time.Duration {
+	if c.MaxBackoff > 0 {
+		return c.MaxBackoff
+	}
+	return DefaultWeatherMaxBackoff
+}
+
+// SetWeatherRetry configures retries for WeatherClient calls used to decide how to water
+func (w *Worker) SetWeatherRetry(cfg WeatherRetryConfig) {
+	w.settingsMtx.Lock()
+	defer w.settingsMtx.Unlock()
+	w.weatherRetry = cfg
+}
+
+func (w *Worker) getWeatherRetry() WeatherRetryConfig {
+	w.settingsMtx.RLock()
+	defer w.settingsMtx.RUnlock()
+	return w.weatherRetry
+}
+
+// callWeatherClient calls the WeatherClient method in a span. When retry is true, a failed call is retried with
+// backoff. It is false when the weather data is only displayed so responses are not delayed
+func (w *Worker) callWeatherClient(ctx context.Context, method string, clientID xid.ID, retry bool, call func() (float32, error)) (float32, error) {
+	_, span := startWeatherClientSpan(ctx, method, clientID)
+
+	cfg := w.getWeatherRetry()
+	retries := 0
+	if retry {
+		retries = cfg.retries()
+	}
+	backoff := cfg.backoff()
+
+	result, err := call()
+	for attempt := 1; err != nil && attempt <= retries && ctx.Err() == nil; attempt++ {
+		w.logger.Warn(
+			"error from WeatherClient, retrying",
+			"function", method, "weather_client_id", clientID.String(), "attempt", attempt, "backoff", backoff, "error", err,
+		)
+		weatherClientRetries.WithLabelValues(method).Inc()
+
+		w.clock.Sleep(backoff)
+		backoff = min(2*backoff, cfg.maxBackoff())
+
+		result, err = call()
+	}
+
+	tracing.End(span, err)
+	return result, err
+}
+
+// onWeatherControlError uses the control's OnError policy after its weather data could not be retrieved. It returns
+// true if watering should be skipped or an error wrapping ErrWeatherControlFailed if the WaterAction should fail
+func (w *Worker) onWeatherControlError(control, onError string, err error) (bool, error) {
+	if onError == "" {
+		onError = weather.OnErrorWaterUnscaled
+	}
+	weatherControlErrors.WithLabelValues(control, onError).Inc()
+
+	switch onError {
+	case weather.OnErrorSkip:
+		w.logger.Warn("skipping watering since weather data is not available", "control", control, "error", err)
+		return true, nil
+	case weather.OnErrorFail:
+		return false, fmt.Errorf("%w: error getting weather data for %s: %w", ErrWeatherControlFailed, control, err)
+	default:
+		w.logger.Warn("continuing to water without control since weather data is not available", "control", control, "error", err)
+		return false, nil
+	}
+}
+
+// weatherControlError is an error getting weather data for one of a WaterSchedule's controls
+type weatherControlError struct {
+	control string
+	onError string
+	err     error
+}
@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeatherRetryConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name               string
+		cfg                WeatherRetryConfig
+		expectedRetries    int
+		expectedBackoff    time.Duration
+		expectedMaxBackoff time.Duration
+	}{
+		{
+			"Defaults",
+			WeatherRetryConfig{},
+			DefaultWeatherRetries,
+			DefaultWeatherBackoff,
+			DefaultWeatherMaxBackoff,
+		},
+		{
+			"Configured",
+			WeatherRetryConfig{Retries: 5, Backoff: time.Minute, MaxBackoff: time.Hour},
+			5,
+			time.Minute,
+			time.Hour,
+		},
+		{
+			"Disabled",
+			WeatherRetryConfig{Retries: -1},
+			0,
+			DefaultWeatherBackoff,
+			DefaultWeatherMaxBackoff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedRetries, tt.cfg.retries())
+			assert.Equal(t, tt.expectedBackoff, tt.cfg.backoff())
+			assert.Equal(t, tt.expectedMaxBackoff, tt.cfg.maxBackoff())
+		})
+	}
+}
+
+func TestCallWeatherClient(t *testing.T) {
+	w := NewWorker(nil, nil, nil, slog.Default())
+	w.SetClock(clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)))
+	w.SetWeatherRetry(WeatherRetryConfig{Retries: 3})
+
+	// failingCall fails the first n calls
+	failingCall := func(n int) (func() (float32, error), *int) {
+		calls := 0
+		return func() (float32, error) {
+			calls++
+			if calls <= n {
+				return 0, errors.New("weather client error")
+			}
+			return 10, nil
+		}, &calls
+	}
+
+	t.Run("SuccessAfterRetry", func(t *testing.T) {
+		call, calls := failingCall(2)
+		result, err := w.callWeatherClient(context.Background(), "GetTotalRain", xid.New(), true, call)
+		require.NoError(t, err)
+		assert.Equal(t, float32(10), result)
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("ErrorAfterRetries", func(t *testing.T) {
+		call, calls := failingCall(10)
+		_, err := w.callWeatherClient(context.Background(), "GetTotalRain", xid.New(), true, call)
+		require.Error(t, err)
+		assert.Equal(t, 4, *calls)
+	})
+
+	t.Run("NoRetry", func(t *testing.T) {
+		call, calls := failingCall(1)
+		_, err := w.callWeatherClient(context.Background(), "GetTotalRain", xid.New(), false, call)
+		require.Error(t, err)
+		assert.Equal(t, 1, *calls)
+	})
+}
+
+func TestWeatherControlOnError(t *testing.T) {
+	weatherClientID := xid.New()
+
+	tests := []struct {
+		name          string
+		onError       string
+		expectWater   bool
+		expectedError error
+	}{
+		{"WaterUnscaledByDefault", "", true, nil},
+		{"WaterUnscaled", weather.OnErrorWaterUnscaled, true, nil},
+		{"Skip", weather.OnErrorSkip, false, nil},
+		{"Fail", weather.OnErrorFail, false, ErrWeatherControlFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+			defer weather.ResetCache()
+
+			err = sc.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+				ID:   babyapi.ID{ID: weatherClientID},
+				Type: "fake",
+				Options: map[string]interface{}{
+					"rain_interval": "24h",
+					"error":         "weather client error",
+				},
+			})
+			require.NoError(t, err)
+
+			mqttClient := new(mqtt.MockClient)
+			if tt.expectWater {
+				mqttClient.On("WaterTopic", "test-garden").Return("test-garden/action/water", nil)
+				mqttClient.On("Publish", "test-garden/action/water", mock.Anything).Return(nil)
+			}
+
+			w := NewWorker(sc, new(influxdb.MockClient), mqttClient, slog.Default())
+			w.SetClock(clock.NewVirtual(time.Date(2023, time.January, 1, 8, 0, 0, 0, time.UTC)))
+
+			ws := &pkg.WaterSchedule{
+				Duration: &pkg.Duration{Duration: time.Second},
+				Interval: &pkg.Duration{Duration: time.Hour * 24},
+				WeatherControl: &weather.Control{
+					Rain: &weather.ScaleControl{
+						BaselineValue: float32Pointer(0),
+						Factor:        float32Pointer(0),
+						Range:         float32Pointer(50),
+						ClientID:      weatherClientID,
+						OnError:       tt.onError,
+					},
+				},
+			}
+
+			err = w.ExecuteScheduledWaterAction(createExampleGarden(), createExampleZone(), ws)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			mqttClient.AssertExpectations(t)
+		})
+	}
+}
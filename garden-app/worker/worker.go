@@ -2,9 +2,14 @@ package worker
 
 import (
 	"log/slog"
+	"sync"
 	"time"
 
-	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/backup"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/clock"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/metrics"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
 	"github.com/go-co-op/gocron"
@@ -22,21 +27,136 @@ var (
 		Name:      "scheduler_errors",
 		Help:      "count of errors that occur in the background and do not have any visibility except logs",
 	}, []string{"type", "id"})
+	actionExecutions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "garden_app",
+		Name:      "action_executions",
+		Help:      "count of water, light, and stop actions sent to controllers by result (success, error, or skipped)",
+	}, []string{"action", "id", "result"})
+	waterDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "garden_app",
+		Name:      "water_duration_seconds",
+		Help:      "histogram of the durations of water actions sent to controllers",
+		Buckets:   []float64{30, 60, 120, 300, 600, 900, 1800, 3600},
+	}, []string{"zone_id"})
+	waterLiters = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "garden_app",
+		Name:      "water_liters",
+		Help:      "count of liters used by each Zone, either estimated from its flow rate or measured by a flow meter",
+	}, []string{"zone_id", "source"})
 )
 
+// recordAction counts an executed action. The error is returned so this can directly wrap the result of an action
+func recordAction(actionType, id string, err error) error {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	actionExecutions.WithLabelValues(actionType, id, result).Inc()
+	return err
+}
+
 // Worker contains the necessary clients to schedule and execute actions
 type Worker struct {
 	storageClient  *storage.Client
-	influxdbClient influxdb.Client
+	influxdbClient metrics.Client
 	mqttClient     mqtt.Client
 	scheduler      *gocron.Scheduler
+	clock          *clock.Clock
+	events         *events.Bus
 	logger         *slog.Logger
+
+	// zoneWateringUntil keeps track of when each Zone will finish its current scheduled watering so overlapping
+	// waterings from multiple WaterSchedules can be merged
+	zoneWateringUntil    map[string]time.Time
+	zoneWateringUntilMtx sync.Mutex
+
+	// gardenHealth keeps track of the last known health status of each Garden's controller so notifications are
+	// only sent when it changes
+	gardenHealth    map[string]string
+	gardenHealthMtx sync.Mutex
+
+	// controllerContacts keeps track of the last time each controller published health data, by topic prefix
+	controllerContacts    map[string]time.Time
+	controllerContactsMtx sync.Mutex
+	healthThreshold       time.Duration
+
+	// lightStates keeps track of the last light state published by each controller, by topic prefix
+	lightStates    map[string]pkg.GardenLightState
+	lightStatesMtx sync.Mutex
+
+	// lightLevels keeps track of the last ambient light level published by each controller, by topic prefix
+	lightLevels    map[string]lightLevel
+	lightLevelsMtx sync.Mutex
+
+	// lightReverts keeps track of the pending Job that changes each Garden's light back after a LightAction with
+	// ForDuration, by Garden ID
+	lightReverts    map[string]lightRevert
+	lightRevertsMtx sync.Mutex
+
+	// jobRuns keeps the last known run counts of each Job so runs are still counted after a Job is removed while
+	// advancing a virtual Clock
+	jobRuns        map[*gocron.Job]jobRunCount
+	removedJobRuns jobRunCount
+	jobRunsMtx     sync.Mutex
+
+	// blackoutWindows are used for every Garden in addition to their own
+	blackoutWindows []pkg.BlackoutWindow
+
+	// deferredWaterings keeps track of scheduled waterings that are waiting for a BlackoutWindow to end, by Zone ID
+	// and then WaterSchedule ID
+	deferredWaterings    map[string]map[string]DeferredWatering
+	deferredWateringsMtx sync.Mutex
+
+	// waterQueues keep track of watering Zones and queued WaterActions for Gardens with MaxConcurrentZones, by
+	// Garden ID
+	waterQueues    map[string]*gardenWaterQueue
+	nextWateringID uint64
+	waterQueuesMtx sync.Mutex
+
+	// leakDetection configures alerts for possible leaks and moistureLeakAlerts keeps track of the last soil
+	// moisture alert for each Zone so the same rise is not alerted every time it is checked
+	leakDetection         LeakDetectionConfig
+	moistureLeakAlerts    map[string]time.Time
+	moistureLeakAlertsMtx sync.Mutex
+
+	// catchUp decides what happens to PendingJobs that were missed while the server was not running
+	catchUp CatchUpConfig
+
+	// waterAck configures acknowledgments for water commands and pendingWaterAcks keeps track of the commands that
+	// were not acknowledged yet, by CommandID
+	waterAck            WaterAckConfig
+	pendingWaterAcks    map[string]*pendingWaterAck
+	pendingWaterAcksMtx sync.Mutex
+
+	// weatherRetry configures retries for WeatherClient calls used to decide how to water
+	weatherRetry WeatherRetryConfig
+
+	// manualWatering configures how waterings started at a controller are handled
+	manualWatering ManualWateringConfig
+
+	// backupConfig configures retention for the scheduled backups that are saved to backupStore
+	backupConfig backup.Config
+	backupStore  backup.Store
+
+	// actionRecordsMtx makes sure concurrent updates to the same ActionRecord, like an acknowledgment and
+	// completion, are not lost
+	actionRecordsMtx sync.Mutex
+
+	// firmwareUpdatesMtx makes sure a controller's status is not lost when it responds while other Gardens'
+	// updates are being recorded
+	firmwareUpdatesMtx sync.Mutex
+
+	// settingsMtx guards the healthThreshold, blackoutWindows, leakDetection, catchUp, waterAck, weatherRetry,
+	// manualWatering, and backup settings since they can be changed while the Worker is running
+	settingsMtx sync.RWMutex
+
+	scheduledJobsTotal prometheus.GaugeFunc
 }
 
 // NewWorker creates a Worker with specified clients
 func NewWorker(
 	storageClient *storage.Client,
-	influxdbClient influxdb.Client,
+	influxdbClient metrics.Client,
 	mqttClient mqtt.Client,
 	logger *slog.Logger,
 ) *Worker {
@@ -45,19 +165,84 @@ func NewWorker(
 		influxdbClient: influxdbClient,
 		mqttClient:     mqttClient,
 		scheduler:      gocron.NewScheduler(time.UTC),
+		clock:          clock.New(),
 		logger:         logger.With("source", "worker"),
+
+		zoneWateringUntil:  map[string]time.Time{},
+		gardenHealth:       map[string]string{},
+		controllerContacts: map[string]time.Time{},
+		lightStates:        map[string]pkg.GardenLightState{},
+		lightLevels:        map[string]lightLevel{},
+		lightReverts:       map[string]lightRevert{},
+		healthThreshold:    pkg.DefaultHealthThreshold,
+		jobRuns:            map[*gocron.Job]jobRunCount{},
+		deferredWaterings:  map[string]map[string]DeferredWatering{},
+		waterQueues:        map[string]*gardenWaterQueue{},
+		moistureLeakAlerts: map[string]time.Time{},
+		pendingWaterAcks:   map[string]*pendingWaterAck{},
 	}
 }
 
+// SetClock configures the Worker and its scheduler to use the provided Clock. This must be used before
+// scheduling any Jobs
+func (w *Worker) SetClock(c *clock.Clock) {
+	w.clock = c
+	w.scheduler.CustomTime(c)
+	w.scheduler.CustomTimer(c.AfterFunc)
+}
+
+// SetHealthThreshold configures how long a controller can go without publishing health data before it is
+// considered "DOWN"
+func (w *Worker) SetHealthThreshold(threshold time.Duration) {
+	w.settingsMtx.Lock()
+	defer w.settingsMtx.Unlock()
+	w.healthThreshold = threshold
+}
+
+func (w *Worker) getHealthThreshold() time.Duration {
+	w.settingsMtx.RLock()
+	defer w.settingsMtx.RUnlock()
+	return w.healthThreshold
+}
+
+// SetEventBus configures the Worker to publish Events when executing actions
+func (w *Worker) SetEventBus(bus *events.Bus) {
+	w.events = bus
+}
+
+// now returns the current time from the Worker's Clock
+func (w *Worker) now() time.Time {
+	return w.clock.Now(time.Local)
+}
+
 // StartAsync starts the Worker's background jobs
 func (w *Worker) StartAsync() {
 	w.scheduler.StartAsync()
+	w.scheduledJobsTotal = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "garden_app",
+		Name:      "scheduled_jobs_total",
+		Help:      "total number of jobs in the scheduler",
+	}, func() float64 {
+		return float64(len(w.scheduler.Jobs()))
+	})
 	prometheus.MustRegister(
 		scheduleJobsGauge,
 		schedulerErrors,
+		actionExecutions,
+		waterDurationHistogram,
+		waterLiters,
+		ingestedMessages,
+		weatherClientRetries,
+		weatherControlErrors,
+		w.scheduledJobsTotal,
 	)
 }
 
+// IsRunning returns true if the Worker's background jobs are started and it is not stopped
+func (w *Worker) IsRunning() bool {
+	return w.scheduler.IsRunning()
+}
+
 // Stop stops the Worker's background jobs
 func (w *Worker) Stop() {
 	w.scheduler.Stop()
@@ -70,4 +255,13 @@ func (w *Worker) Stop() {
 
 	prometheus.Unregister(scheduleJobsGauge)
 	prometheus.Unregister(schedulerErrors)
+	prometheus.Unregister(actionExecutions)
+	prometheus.Unregister(waterDurationHistogram)
+	prometheus.Unregister(waterLiters)
+	prometheus.Unregister(ingestedMessages)
+	prometheus.Unregister(weatherClientRetries)
+	prometheus.Unregister(weatherControlErrors)
+	if w.scheduledJobsTotal != nil {
+		prometheus.Unregister(w.scheduledJobsTotal)
+	}
 }
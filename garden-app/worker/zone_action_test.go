@@ -1,6 +1,8 @@
 package worker
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"testing"
@@ -8,12 +10,23 @@ import (
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/events"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/influxdb"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/storage"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rs/xid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestZoneAction(t *testing.T) {
@@ -70,7 +83,7 @@ func TestZoneAction(t *testing.T) {
 			influxdbClient := new(influxdb.MockClient)
 			tt.setupMock(mqttClient, influxdbClient)
 
-			err := NewWorker(nil, influxdbClient, mqttClient, slog.Default()).ExecuteZoneAction(garden, zone, tt.action)
+			err := NewWorker(nil, influxdbClient, mqttClient, slog.Default()).ExecuteZoneAction(context.Background(), garden, zone, tt.action)
 			if tt.expectedError != "" {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError, err.Error())
@@ -133,13 +146,23 @@ func TestWaterActionExecute(t *testing.T) {
 			wc := new(weather.MockClient)
 			tt.setupMock(mqttClient, influxdbClient, wc)
 
-			err = NewWorker(storageClient, influxdbClient, mqttClient, slog.Default()).ExecuteWaterAction(garden, tt.zone, action)
+			err = NewWorker(storageClient, influxdbClient, mqttClient, slog.Default()).ExecuteWaterAction(context.Background(), garden, tt.zone, action)
 			if tt.expectedError != "" {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError, err.Error())
 			} else {
 				assert.NoError(t, err)
 			}
+
+			// WaterHistory is only stored when the action was sent
+			history, err := storageClient.WaterHistory.GetWaterHistory(context.Background(), tt.zone.GetID(), time.Time{}, 0)
+			assert.NoError(t, err)
+			if tt.expectedError != "" {
+				assert.Empty(t, history)
+			} else {
+				assert.Len(t, history, 1)
+				assert.Equal(t, "1s", history[0].Duration.String())
+			}
 			mqttClient.AssertExpectations(t)
 			influxdbClient.AssertExpectations(t)
 			wc.AssertExpectations(t)
@@ -147,6 +170,123 @@ func TestWaterActionExecute(t *testing.T) {
 	}
 }
 
+func TestWaterActionExecutePublishesEvent(t *testing.T) {
+	garden := &pkg.Garden{
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	zone := &pkg.Zone{
+		Position: uintPointer(0),
+	}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	mqttClient.On("Publish", "garden/action/water", mock.Anything).Return(nil)
+
+	bus := events.NewBus()
+	subscriber, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	w := NewWorker(nil, nil, mqttClient, slog.Default())
+	w.SetEventBus(bus)
+
+	err := w.ExecuteWaterAction(context.Background(), garden, zone, &action.WaterAction{
+		Duration: &pkg.Duration{Duration: time.Second},
+	})
+	assert.NoError(t, err)
+
+	select {
+	case e := <-subscriber:
+		assert.Equal(t, "water_action.executed", e.Type)
+		assert.Equal(t, WaterActionEvent{
+			GardenID: garden.GetID(),
+			ZoneID:   zone.GetID(),
+			Duration: "1s",
+		}, e.Data)
+	case <-time.After(time.Second):
+		t.Fatal("expected water_action.executed Event")
+	}
+	mqttClient.AssertExpectations(t)
+}
+
+func TestWaterActionExecuteTraceContext(t *testing.T) {
+	_, err := tracing.Setup(tracing.Config{}, "")
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	original := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(original) })
+
+	garden := &pkg.Garden{
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	zone := &pkg.Zone{
+		Position: uintPointer(0),
+	}
+
+	var msg action.WaterMessage
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil)
+	mqttClient.On("Publish", "garden/action/water", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &msg))
+	})
+
+	ctx, span := tracing.Start(context.Background(), "test")
+	err = NewWorker(nil, nil, mqttClient, slog.Default()).ExecuteWaterAction(ctx, garden, zone, &action.WaterAction{
+		Duration: &pkg.Duration{Duration: time.Second},
+	})
+	span.End()
+	require.NoError(t, err)
+	mqttClient.AssertExpectations(t)
+
+	require.Contains(t, msg.TraceContext, "traceparent")
+	assert.Contains(t, msg.TraceContext["traceparent"], span.SpanContext().TraceID().String())
+
+	spanNames := []string{}
+	for _, s := range recorder.Ended() {
+		assert.Equal(t, span.SpanContext().TraceID(), s.SpanContext().TraceID())
+		spanNames = append(spanNames, s.Name())
+	}
+	assert.Contains(t, spanNames, "mqtt.Publish")
+}
+
+func TestWaterActionExecuteMetrics(t *testing.T) {
+	garden := &pkg.Garden{
+		Name:        "garden",
+		TopicPrefix: "garden",
+	}
+	zone := &pkg.Zone{
+		ID:       babyapi.NewID(),
+		Position: uintPointer(0),
+	}
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("WaterTopic", "garden").Return("garden/action/water", nil).Once()
+	mqttClient.On("Publish", "garden/action/water", mock.Anything).Return(nil).Once()
+	mqttClient.On("WaterTopic", "garden").Return("", errors.New("template error")).Once()
+
+	w := NewWorker(nil, nil, mqttClient, slog.Default())
+
+	err := w.ExecuteWaterAction(context.Background(), garden, zone, &action.WaterAction{Duration: &pkg.Duration{Duration: time.Minute}})
+	require.NoError(t, err)
+	err = w.ExecuteWaterAction(context.Background(), garden, zone, &action.WaterAction{Duration: &pkg.Duration{Duration: time.Minute}})
+	require.Error(t, err)
+	err = w.ExecuteWaterAction(context.Background(), garden, zone, &action.WaterAction{Duration: &pkg.Duration{}})
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(actionExecutions.WithLabelValues("water", zone.GetID(), "success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(actionExecutions.WithLabelValues("water", zone.GetID(), "error")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(actionExecutions.WithLabelValues("water", zone.GetID(), "skipped")))
+
+	histogram := &dto.Metric{}
+	require.NoError(t, waterDurationHistogram.WithLabelValues(zone.GetID()).(prometheus.Histogram).Write(histogram))
+	assert.Equal(t, uint64(1), histogram.GetHistogram().GetSampleCount())
+	assert.Equal(t, float64(60), histogram.GetHistogram().GetSampleSum())
+	mqttClient.AssertExpectations(t)
+}
+
 func uintPointer(n int) *uint {
 	uintn := uint(n)
 	return &uintn
@@ -156,3 +296,264 @@ func float32Pointer(n float64) *float32 {
 	f := float32(n)
 	return &f
 }
+
+func TestDecideWaterAction(t *testing.T) {
+	weatherClientID, _ := xid.FromString("c5cvhpcbcv45e8bp16dg")
+	twelve := 12
+	one := 1
+
+	tests := []struct {
+		name             string
+		weatherOptions   map[string]interface{}
+		weatherControl   *weather.Control
+		action           *action.WaterAction
+		expected         *WaterDecision
+		expectedError    string
+		noWaterSchedules bool
+	}{
+		{
+			"NoWaterScheduleUsesRequestedDuration",
+			nil,
+			nil,
+			&action.WaterAction{Duration: &pkg.Duration{Duration: 30 * time.Second}},
+			&WaterDecision{
+				Duration:          &pkg.Duration{Duration: 30 * time.Second},
+				RequestedDuration: &pkg.Duration{Duration: 30 * time.Second},
+				ScaleFactor:       1,
+			},
+			"",
+			true,
+		},
+		{
+			"NoWaterScheduleMissingDuration",
+			nil,
+			nil,
+			&action.WaterAction{},
+			nil,
+			"missing duration and Zone does not have an active WaterSchedule",
+			true,
+		},
+		{
+			"WaterScheduleDurationWithoutWeatherControl",
+			nil,
+			nil,
+			&action.WaterAction{},
+			&WaterDecision{
+				Duration:          &pkg.Duration{Duration: time.Second},
+				RequestedDuration: &pkg.Duration{Duration: time.Second},
+				ScaleFactor:       1,
+			},
+			"",
+			false,
+		},
+		{
+			"TemperatureScaling",
+			map[string]interface{}{"rain_interval": "24h", "avg_high_temperature": 85},
+			&weather.Control{
+				Temperature: &weather.ScaleControl{
+					BaselineValue: float32Pointer(70),
+					Factor:        float32Pointer(0.5),
+					Range:         float32Pointer(30),
+					ClientID:      weatherClientID,
+				},
+			},
+			&action.WaterAction{Duration: &pkg.Duration{Duration: 8 * time.Second}},
+			&WaterDecision{
+				Duration:          &pkg.Duration{Duration: 10 * time.Second},
+				RequestedDuration: &pkg.Duration{Duration: 8 * time.Second},
+				WaterScheduleID:   "c5cvhpcbcv45e8bp16dg",
+				ScaleFactor:       1.25,
+			},
+			"",
+			false,
+		},
+		{
+			"IgnoreWeather",
+			map[string]interface{}{"rain_interval": "24h", "avg_high_temperature": 85},
+			&weather.Control{
+				Temperature: &weather.ScaleControl{
+					BaselineValue: float32Pointer(70),
+					Factor:        float32Pointer(0.5),
+					Range:         float32Pointer(30),
+					ClientID:      weatherClientID,
+				},
+			},
+			&action.WaterAction{Duration: &pkg.Duration{Duration: 10 * time.Second}, IgnoreWeather: true},
+			&WaterDecision{
+				Duration:          &pkg.Duration{Duration: 10 * time.Second},
+				RequestedDuration: &pkg.Duration{Duration: 10 * time.Second},
+				ScaleFactor:       1,
+			},
+			"",
+			false,
+		},
+		{
+			"RainForecastSkip",
+			map[string]interface{}{"rain_interval": "24h", "forecast_rain_mm": 10},
+			&weather.Control{
+				RainForecast: &weather.RainForecastControl{
+					Threshold:  float32Pointer(5),
+					HoursAhead: &twelve,
+					ClientID:   weatherClientID,
+				},
+			},
+			&action.WaterAction{Duration: &pkg.Duration{Duration: 10 * time.Second}},
+			&WaterDecision{
+				Duration:          &pkg.Duration{},
+				RequestedDuration: &pkg.Duration{Duration: 10 * time.Second},
+				WaterScheduleID:   "c5cvhpcbcv45e8bp16dg",
+				Skip:              true,
+				Reasons:           []string{"forecasted rain is above the threshold"},
+			},
+			"",
+			false,
+		},
+		{
+			"FrostProtection",
+			map[string]interface{}{"rain_interval": "24h", "forecast_low_temperature": -2},
+			&weather.Control{
+				Frost: &weather.FrostControl{
+					Mode:           weather.FrostModeProtect,
+					Threshold:      float32Pointer(0),
+					HoursAhead:     &twelve,
+					ProtectMinutes: &one,
+					ClientID:       weatherClientID,
+				},
+			},
+			&action.WaterAction{Duration: &pkg.Duration{Duration: 10 * time.Second}},
+			&WaterDecision{
+				Duration:          &pkg.Duration{Duration: time.Minute},
+				RequestedDuration: &pkg.Duration{Duration: 10 * time.Second},
+				WaterScheduleID:   "c5cvhpcbcv45e8bp16dg",
+				ScaleFactor:       6,
+				Reasons:           []string{"watering for frost protection since the forecasted low temperature is at or below the frost threshold"},
+			},
+			"",
+			false,
+		},
+		{
+			"FrostInhibitSkip",
+			map[string]interface{}{"rain_interval": "24h", "forecast_low_temperature": -2},
+			&weather.Control{
+				Frost: &weather.FrostControl{
+					Mode:       weather.FrostModeInhibit,
+					Threshold:  float32Pointer(0),
+					HoursAhead: &twelve,
+					ClientID:   weatherClientID,
+				},
+			},
+			&action.WaterAction{Duration: &pkg.Duration{Duration: 10 * time.Second}},
+			&WaterDecision{
+				Duration:          &pkg.Duration{},
+				RequestedDuration: &pkg.Duration{Duration: 10 * time.Second},
+				WaterScheduleID:   "c5cvhpcbcv45e8bp16dg",
+				Skip:              true,
+				Reasons:           []string{"forecasted low temperature is at or below the frost threshold"},
+			},
+			"",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer weather.ResetCache()
+
+			storageClient, err := storage.NewClient(storage.Config{
+				Driver: "hashmap",
+			})
+			require.NoError(t, err)
+
+			if tt.weatherOptions != nil {
+				err = storageClient.WeatherClientConfigs.Set(context.Background(), &weather.Config{
+					ID:      babyapi.ID{ID: weatherClientID},
+					Type:    "fake",
+					Options: tt.weatherOptions,
+				})
+				require.NoError(t, err)
+			}
+
+			mqttClient := new(mqtt.MockClient)
+			mqttClient.On("Disconnect", uint(100)).Return()
+
+			w := NewWorker(storageClient, nil, mqttClient, slog.Default())
+			w.StartAsync()
+			defer w.Stop()
+
+			if !tt.noWaterSchedules {
+				ws := createExampleWaterSchedule()
+				ws.WeatherControl = tt.weatherControl
+				err = storageClient.WaterSchedules.Set(context.Background(), ws)
+				require.NoError(t, err)
+				err = w.ScheduleWaterAction(ws)
+				require.NoError(t, err)
+			}
+
+			decision, err := w.DecideWaterAction(context.Background(), createExampleGarden(), createExampleZone(), tt.action)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decision)
+		})
+	}
+}
+
+func TestDecideWaterActionScalesForZone(t *testing.T) {
+	storageClient, err := storage.NewClient(storage.Config{
+		Driver: "hashmap",
+	})
+	require.NoError(t, err)
+
+	mqttClient := new(mqtt.MockClient)
+	mqttClient.On("Disconnect", uint(100)).Return()
+
+	w := NewWorker(storageClient, nil, mqttClient, slog.Default())
+	w.StartAsync()
+	defer w.Stop()
+
+	ws := createExampleWaterSchedule()
+	err = storageClient.WaterSchedules.Set(context.Background(), ws)
+	require.NoError(t, err)
+	err = w.ScheduleWaterAction(ws)
+	require.NoError(t, err)
+
+	zone := createExampleZone()
+	zone.SoilType = pkg.SoilTypeClay
+
+	t.Run("WaterScheduleDurationIsScaled", func(t *testing.T) {
+		decision, err := w.DecideWaterAction(context.Background(), createExampleGarden(), zone, &action.WaterAction{})
+		require.NoError(t, err)
+		assert.Equal(t, &WaterDecision{
+			Duration:          &pkg.Duration{Duration: 1250 * time.Millisecond},
+			RequestedDuration: &pkg.Duration{Duration: time.Second},
+			ScaleFactor:       1.25,
+		}, decision)
+	})
+
+	t.Run("RequestedDurationIsNotScaled", func(t *testing.T) {
+		decision, err := w.DecideWaterAction(context.Background(), createExampleGarden(), zone, &action.WaterAction{Duration: &pkg.Duration{Duration: time.Second}})
+		require.NoError(t, err)
+		assert.Equal(t, &WaterDecision{
+			Duration:          &pkg.Duration{Duration: time.Second},
+			RequestedDuration: &pkg.Duration{Duration: time.Second},
+			ScaleFactor:       1,
+		}, decision)
+	})
+
+	t.Run("ScaledDurationIsLimited", func(t *testing.T) {
+		ws.MaxDuration = &pkg.Duration{Duration: 1100 * time.Millisecond}
+		err := storageClient.WaterSchedules.Set(context.Background(), ws)
+		require.NoError(t, err)
+
+		decision, err := w.DecideWaterAction(context.Background(), createExampleGarden(), zone, &action.WaterAction{})
+		require.NoError(t, err)
+		assert.Equal(t, &WaterDecision{
+			Duration:          &pkg.Duration{Duration: 1100 * time.Millisecond},
+			RequestedDuration: &pkg.Duration{Duration: time.Second},
+			ScaleFactor:       1.1,
+			Reasons:           []string{"duration was limited by the WaterSchedule's min_duration or max_duration"},
+		}, decision)
+	})
+}
@@ -1,17 +1,69 @@
 package worker
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/calvinmclean/automated-garden/garden-app/pkg"
 	"github.com/calvinmclean/automated-garden/garden-app/pkg/action"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/mqtt"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/tracing"
+	"github.com/calvinmclean/automated-garden/garden-app/pkg/weather"
+	"github.com/calvinmclean/babyapi"
+	"github.com/rs/xid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// ExecuteZoneAction will execute a ZoneAction
-func (w *Worker) ExecuteZoneAction(g *pkg.Garden, z *pkg.Zone, input *action.ZoneAction) error {
+// ExecuteZoneAction will execute a ZoneAction. The WaterAction's duration is first adjusted by the Zone's
+// WeatherControl unless it is ignored. The WaterAction's ID is used for its ActionRecord, so the caller can set it
+// to look up the result later
+func (w *Worker) ExecuteZoneAction(ctx context.Context, g *pkg.Garden, z *pkg.Zone, input *action.ZoneAction) (err error) {
+	ctx, span := tracing.Start(ctx, "ExecuteZoneAction", trace.WithAttributes(
+		attribute.String("garden_id", g.GetID()),
+		attribute.String("zone_id", z.GetID()),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if input.Stop != nil {
+		err = w.ExecuteZoneStopAction(g, z)
+		if err != nil {
+			return fmt.Errorf("unable to execute StopAction: %w", err)
+		}
+	}
 	if input.Water != nil {
-		err := w.ExecuteWaterAction(g, z, input.Water)
+		id := input.Water.ID
+		if id == "" {
+			id = xid.New().String()
+		}
+
+		decision, err := w.DecideWaterAction(ctx, g, z, input.Water)
+		if err != nil {
+			if errors.Is(err, ErrWeatherControlFailed) {
+				failed := &action.WaterAction{ID: id, Duration: input.Water.Duration}
+				w.recordWaterAction(g, z, failed, pkg.ActionStatusFailed, err.Error())
+			}
+			return fmt.Errorf("unable to execute WaterAction: %w", err)
+		}
+		if decision.OverBudget != "" {
+			w.notifyWaterBudget(g, z, decision.OverBudget)
+		}
+		if decision.Skip {
+			w.logger.Info("skipping WaterAction", "zone_id", z.GetID(), "reasons", decision.Reasons)
+			skipped := &action.WaterAction{ID: id, Duration: decision.Duration}
+			w.recordWaterAction(g, z, skipped, pkg.ActionStatusSkipped, strings.Join(decision.Reasons, "; "))
+			return nil
+		}
+
+		err = w.ExecuteWaterAction(ctx, g, z, &action.WaterAction{
+			ID:         id,
+			Duration:   decision.Duration,
+			Cycles:     decision.Cycles,
+			Fertilizer: input.Water.Fertilizer,
+		})
 		if err != nil {
 			return fmt.Errorf("unable to execute WaterAction: %w", err)
 		}
@@ -19,27 +71,358 @@ func (w *Worker) ExecuteZoneAction(g *pkg.Garden, z *pkg.Zone, input *action.Zon
 	return nil
 }
 
+// ExecuteZoneStopAction cancels the Zone's queued and deferred WaterActions. If the Zone is currently watering, the
+// controller is told to stop
+func (w *Worker) ExecuteZoneStopAction(g *pkg.Garden, z *pkg.Zone) error {
+	cancelled := w.cancelQueuedWaterActions(g, z)
+	cancelled += w.cancelDeferredWaterings(func(dw DeferredWatering) bool { return dw.zoneID == z.GetID() })
+	cancelled += w.cancelWaterCycles(z.GetID())
+	w.logger.Info("cancelled queued and deferred WaterActions", "zone_id", z.GetID(), "count", cancelled)
+
+	if !w.isWatering(g, z) {
+		return nil
+	}
+
+	err := w.ExecuteStopAction(g, &action.StopAction{})
+	if err != nil {
+		return err
+	}
+
+	// The controller might not publish a message after stopping, so the next queued WaterAction is started now
+	if _, ok := w.releaseWatering(g.GetID(), func(wz wateringZone) bool { return wz.zoneID == z.GetID() }); ok {
+		w.startQueuedWaterActions(g)
+	}
+	return nil
+}
+
+// ErrMissingWaterDuration is returned when a WaterAction does not have a duration and the Zone does not have
+// an active WaterSchedule to get it from
+var ErrMissingWaterDuration = errors.New("missing duration and Zone does not have an active WaterSchedule")
+
+// WaterDecision shows how the duration for a WaterAction was calculated
+type WaterDecision struct {
+	Duration          *pkg.Duration    `json:"duration"`
+	RequestedDuration *pkg.Duration    `json:"requested_duration"`
+	WaterScheduleID   string           `json:"water_schedule_id,omitempty"`
+	ScaleFactor       float32          `json:"scale_factor"`
+	Skip              bool             `json:"skip"`
+	Reasons           []string         `json:"reasons,omitempty"`
+	Cycles            *pkg.WaterCycles `json:"cycles,omitempty"`
+	// OverBudget is the reason that the duration was reduced or skipped by the Garden's WaterBudget
+	OverBudget string `json:"over_budget,omitempty"`
+}
+
+// DecideWaterAction calculates the duration for a WaterAction without executing it. If the WaterAction does not
+// have a duration or Cycles, the Zone's next active WaterSchedule's duration and Cycles are used. That
+// WaterSchedule's WeatherControl is used to skip or scale watering unless the WaterAction ignores it. With Cycles,
+// the duration is the total for all pulses. Then, the duration is limited by the Garden's WaterBudget unless the
+// WaterAction ignores it
+func (w *Worker) DecideWaterAction(ctx context.Context, g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) (*WaterDecision, error) {
+	decision, err := w.decideWaterDuration(ctx, g, z, input)
+	if err != nil || decision.Skip || input.IgnoreBudget {
+		return decision, err
+	}
+
+	duration, reason, err := w.applyWaterBudget(g, z, decision.Duration.Duration)
+	if err != nil {
+		decision.Reasons = append(decision.Reasons, err.Error())
+	}
+	if reason == "" {
+		return decision, nil
+	}
+
+	decision.OverBudget = reason
+	decision.Reasons = append(decision.Reasons, reason)
+	decision.Duration = &pkg.Duration{Duration: duration}
+	if decision.RequestedDuration.Duration > 0 {
+		decision.ScaleFactor = float32(duration) / float32(decision.RequestedDuration.Duration)
+	}
+	if duration == 0 {
+		decision.Skip = true
+	}
+	return decision, nil
+}
+
+// decideWaterDuration calculates the duration from the WaterAction or WaterSchedule and its WeatherControl
+func (w *Worker) decideWaterDuration(ctx context.Context, g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) (*WaterDecision, error) {
+	ws, err := w.getNextActiveWaterSchedule(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+	ws = ws.ForZone(z)
+
+	var requested time.Duration
+	var cycles *pkg.WaterCycles
+	// the Zone's soil type and crop coefficient and the Garden's seasonal adjustment only scale durations from its
+	// WaterSchedules
+	zoneScale := float32(1)
+	fromWaterSchedule := false
+	switch {
+	case input.Cycles != nil:
+		requested = input.Cycles.TotalWater()
+		cycles = input.Cycles
+	case input.Duration != nil:
+		requested = input.Duration.Duration
+	case ws != nil:
+		requested = ws.Duration.Duration
+		cycles = ws.Cycles
+		zoneScale = z.WaterDurationScale() * g.SeasonalScale(w.now())
+		fromWaterSchedule = true
+	default:
+		return nil, ErrMissingWaterDuration
+	}
+
+	decision := &WaterDecision{
+		Duration:          &pkg.Duration{Duration: pkg.ScaleDuration(requested, zoneScale)},
+		RequestedDuration: &pkg.Duration{Duration: requested},
+		ScaleFactor:       zoneScale,
+		Cycles:            cycles,
+	}
+	if ws == nil || !ws.HasWeatherControl() || input.IgnoreWeather {
+		// limits only apply to scaled durations, so a requested duration is not changed
+		if fromWaterSchedule {
+			w.clampDecision(decision, ws)
+		}
+		return decision, nil
+	}
+	decision.WaterScheduleID = ws.GetID()
+
+	skip := func(reason string) (*WaterDecision, error) {
+		decision.Skip = true
+		decision.Duration = &pkg.Duration{}
+		decision.ScaleFactor = 0
+		decision.Reasons = append(decision.Reasons, reason)
+		return decision, nil
+	}
+
+	// onError records the WeatherClient error and uses the control's OnError policy
+	onError := func(control, policy string, err error) (bool, error) {
+		decision.Reasons = append(decision.Reasons, err.Error())
+		return w.onWeatherControlError(control, policy, err)
+	}
+	weatherUnavailable := func(control string) string {
+		return fmt.Sprintf("weather data for %s is not available and its on_error is %q", control, weather.OnErrorSkip)
+	}
+
+	freeze, err := w.forecastFreeze(ctx, ws, true)
+	if err != nil {
+		skipFrost, err := onError("frost_control", ws.WeatherControl.Frost.OnError, err)
+		if err != nil {
+			return nil, err
+		}
+		if skipFrost {
+			return skip(weatherUnavailable("frost_control"))
+		}
+	}
+	if freeze {
+		if ws.WeatherControl.Frost.Mode == weather.FrostModeInhibit {
+			return skip("forecasted low temperature is at or below the frost threshold")
+		}
+
+		// frost protection replaces the usual duration, so it is not scaled or limited
+		protect := ws.WeatherControl.Frost.ProtectDuration()
+		decision.Duration = &pkg.Duration{Duration: protect}
+		if requested > 0 {
+			decision.ScaleFactor = float32(protect) / float32(requested)
+		}
+		decision.Reasons = append(decision.Reasons, "watering for frost protection since the forecasted low temperature is at or below the frost threshold")
+		return decision, nil
+	}
+
+	if !input.IgnoreMoisture {
+		skipMoisture, err := w.shouldMoistureSkip(ctx, g, z, ws)
+		if err != nil {
+			decision.Reasons = append(decision.Reasons, err.Error())
+		}
+		if skipMoisture {
+			return skip("soil moisture is above the minimum")
+		}
+	}
+
+	skipForecast, err := w.shouldForecastSkip(ctx, ws, true)
+	if err != nil {
+		skipUnavailable, err := onError("rain_forecast_control", ws.WeatherControl.RainForecast.OnError, err)
+		if err != nil {
+			return nil, err
+		}
+		if skipUnavailable {
+			return skip(weatherUnavailable("rain_forecast_control"))
+		}
+	}
+	if skipForecast {
+		return skip("forecasted rain is above the threshold")
+	}
+
+	scaleFactor, errs := w.weatherScaleFactor(ctx, ws, true)
+	if len(errs) > 0 {
+		decision.Reasons = append(decision.Reasons, "error getting weather data for scaling, check logs for details")
+	}
+	skipControl, err := w.applyWeatherControlErrors(errs)
+	if err != nil {
+		return nil, err
+	}
+	if skipControl != "" {
+		return skip(weatherUnavailable(skipControl))
+	}
+	duration := pkg.ScaleDuration(pkg.ScaleDuration(requested, scaleFactor), zoneScale)
+	if requested > 0 {
+		decision.ScaleFactor = float32(duration) / float32(requested)
+	}
+	if duration == 0 {
+		return skip("weather scaling reduced the duration to 0")
+	}
+	decision.Duration = &pkg.Duration{Duration: duration}
+	w.clampDecision(decision, ws)
+
+	return decision, nil
+}
+
+// clampDecision limits the WaterDecision's duration to the WaterSchedule's MinDuration and MaxDuration and
+// updates the ScaleFactor to match
+func (w *Worker) clampDecision(decision *WaterDecision, ws *pkg.WaterSchedule) {
+	if ws == nil {
+		return
+	}
+
+	clamped := ws.ClampDuration(decision.Duration.Duration)
+	if clamped == decision.Duration.Duration {
+		return
+	}
+
+	decision.Duration = &pkg.Duration{Duration: clamped}
+	decision.Reasons = append(decision.Reasons, "duration was limited by the WaterSchedule's min_duration or max_duration")
+	if decision.RequestedDuration.Duration > 0 {
+		decision.ScaleFactor = float32(clamped) / float32(decision.RequestedDuration.Duration)
+	}
+}
+
+// getNextActiveWaterSchedule gets the Zone's WaterSchedules, including the ones used by its ZoneGroups, from storage
+// and returns the next one to run
+func (w *Worker) getNextActiveWaterSchedule(ctx context.Context, z *pkg.Zone) (*pkg.WaterSchedule, error) {
+	waterScheduleIDs := z.WaterScheduleIDs
+	if w.storageClient != nil {
+		var err error
+		waterScheduleIDs, err = w.storageClient.GetWaterScheduleIDsForZone(ctx, z)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	waterSchedules := []*pkg.WaterSchedule{}
+	for _, id := range waterScheduleIDs {
+		ws, err := w.storageClient.WaterSchedules.Get(ctx, id.String())
+		if err != nil {
+			if errors.Is(err, babyapi.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("error getting WaterSchedule %q: %w", id, err)
+		}
+		waterSchedules = append(waterSchedules, ws)
+	}
+
+	return w.GetNextActiveWaterSchedule(waterSchedules), nil
+}
+
 // ExecuteWaterAction sends the message over MQTT to the embedded garden controller. This is used for a directly-requested
-// WaterAction and does not perform any of the watering checks that are usuall done for a scheduled watering
-func (w *Worker) ExecuteWaterAction(g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) error {
+// WaterAction and does not perform any of the watering checks that are usuall done for a scheduled watering. If the
+// Garden already has MaxConcurrentZones watering, it is queued until one of them finishes. With Cycles, the Duration
+// is split into pulses that are each sent separately. If the WaterAction does not have an ID, a new one is used for
+// its ActionRecord
+func (w *Worker) ExecuteWaterAction(ctx context.Context, g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) error {
+	if input.ID == "" {
+		withID := *input
+		withID.ID = xid.New().String()
+		input = &withID
+	}
+
 	if input.Duration.Duration == 0 {
 		w.logger.Info("weather control determined that watering should be skipped")
+		actionExecutions.WithLabelValues("water", z.GetID(), "skipped").Inc()
+		w.recordWaterAction(g, z, input, pkg.ActionStatusSkipped, "duration is zero")
+		return nil
+	}
+	if input.Cycles != nil {
+		return w.executeWaterCycles(ctx, g, z, input)
+	}
+
+	id, queued := w.queueWaterAction(g, z, input)
+	if queued {
+		w.logger.Info("queued WaterAction until another Zone finishes watering", "zone_id", z.GetID(), "max_concurrent_zones", *g.MaxConcurrentZones)
+		w.recordWaterAction(g, z, input, pkg.ActionStatusQueued, "")
 		return nil
 	}
 
-	msg, err := json.Marshal(action.WaterMessage{
+	return w.startWaterAction(ctx, g, z, input, id)
+}
+
+// startWaterAction publishes the WaterAction and records it. If publishing fails, the Zone's reserved watering is
+// released so queued WaterActions are not blocked. A WaterAction that is queued until the MQTT broker is available
+// is still sent later, so it keeps the watering. The ActionRecord is published before sending since the controller
+// can acknowledge the command before publishing returns
+func (w *Worker) startWaterAction(ctx context.Context, g *pkg.Garden, z *pkg.Zone, input *action.WaterAction, wateringID uint64) error {
+	w.recordWaterAction(g, z, input, pkg.ActionStatusPublished, "")
+	err := w.sendWaterAction(ctx, g, z, input)
+	if errors.Is(err, mqtt.ErrPublishQueued) {
+		w.logger.Warn("WaterAction is queued until the MQTT broker is available", "zone_id", z.GetID(), "error", err)
+		err = nil
+	}
+	err = recordAction("water", z.GetID(), err)
+	if err != nil {
+		w.recordWaterAction(g, z, input, pkg.ActionStatusFailed, err.Error())
+		if _, ok := w.releaseWatering(g.GetID(), func(wz wateringZone) bool { return wz.id == wateringID }); ok {
+			w.startQueuedWaterActions(g)
+		}
+		return err
+	}
+
+	waterDurationHistogram.WithLabelValues(z.GetID()).Observe(input.Duration.Duration.Seconds())
+	w.publishWaterActionEvent(g, z, input)
+	w.addWaterHistory(g, z, input)
+	return nil
+}
+
+// sendWaterAction sends the WaterMessage for the Zone to the Garden's controller
+func (w *Worker) sendWaterAction(ctx context.Context, g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) error {
+	msg := action.WaterMessage{
 		Duration: input.Duration.Duration.Milliseconds(),
 		ZoneID:   z.GetID(),
 		Position: *z.Position,
+	}
+	if input.Fertilizer != nil {
+		msg.Fertilizer = min(input.Fertilizer.Duration, input.Duration.Duration).Milliseconds()
+	}
+
+	return w.sendWaterMessage(ctx, g, z, msg, input.ID)
+}
+
+// addWaterHistory records the WaterAction in storage. The liters are estimated now so the history is not changed
+// if the flow rate is changed later. Errors are only logged since the water action was already sent to the controller
+func (w *Worker) addWaterHistory(g *pkg.Garden, z *pkg.Zone, input *action.WaterAction) {
+	w.storeWaterHistory(g, z, pkg.WaterHistory{
+		Duration:   &pkg.Duration{Duration: input.Duration.Duration},
+		RecordTime: w.now(),
 	})
-	if err != nil {
-		return fmt.Errorf("unable to marshal WaterMessage to JSON: %w", err)
+}
+
+// storeWaterHistory adds the estimated liters to the WaterHistory and stores it
+func (w *Worker) storeWaterHistory(g *pkg.Garden, z *pkg.Zone, history pkg.WaterHistory) {
+	liters, ok := z.EstimateLiters(g, history.Duration.Duration)
+	if ok {
+		history.ExpectedLiters = &liters
+		waterLiters.WithLabelValues(z.GetID(), "estimated").Add(liters)
 	}
 
-	topic, err := w.mqttClient.WaterTopic(g.TopicPrefix)
+	if w.storageClient == nil || w.storageClient.WaterHistory == nil {
+		return
+	}
+
+	err := w.storageClient.WaterHistory.AddWaterHistory(context.Background(), z.GetID(), history)
 	if err != nil {
-		return fmt.Errorf("unable to fill MQTT topic template: %w", err)
+		w.logger.Error("unable to store water history", "zone_id", z.GetID(), "error", err)
 	}
+}
 
-	return w.mqttClient.Publish(topic, msg)
+// RecordMeasuredLiters counts the liters measured by the Zone's flow meter
+func (w *Worker) RecordMeasuredLiters(z *pkg.Zone, liters float64) {
+	waterLiters.WithLabelValues(z.GetID(), "measured").Add(liters)
 }
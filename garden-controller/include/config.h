@@ -28,6 +28,8 @@
  *   Topic to publish LightEvents on
  * MQTT_WATER_DATA_TOPIC
  *   Topic to publish watering metrics on
+ * MQTT_WATER_ACK_TOPIC
+ *   Topic to publish the command_id of received water commands on so the garden-app knows they were received
  */
 #define MQTT_ADDRESS "192.168.0.107"
 #define MQTT_PORT 30002
@@ -38,6 +40,7 @@
 #define MQTT_LIGHT_TOPIC TOPIC_PREFIX"/command/light"
 #define MQTT_LIGHT_DATA_TOPIC TOPIC_PREFIX"/data/light"
 #define MQTT_WATER_DATA_TOPIC TOPIC_PREFIX"/data/water"
+#define MQTT_WATER_ACK_TOPIC TOPIC_PREFIX"/ack/water"
 
 #define ENABLE_MQTT_HEALTH
 #ifdef ENABLE_MQTT_HEALTH
@@ -50,8 +53,8 @@
 #define MQTT_LOGGING_TOPIC TOPIC_PREFIX"/data/logs"
 #endif
 
- // Size of JSON object calculated using Arduino JSON Assistant
-#define JSON_CAPACITY 48
+ // Size of JSON object calculated using Arduino JSON Assistant. 64 is enough without ENABLE_DOSING
+#define JSON_CAPACITY 80
 
 /**
  * Garden Configurations
@@ -97,6 +100,21 @@
 #define MOISTURE_SENSOR_INTERVAL 5000
 #endif
 
+// Flow meters measure the volume delivered to each zone. FLOW_METER_PINS has one pin per zone, using GPIO_NUM_MAX for
+// zones without a flow meter. The measured volume is included in the water data published to MQTT
+// #define ENABLE_FLOW_METERS
+#ifdef ENABLE_FLOW_METERS
+#define FLOW_METER_PINS { GPIO_NUM_25, GPIO_NUM_26, GPIO_NUM_MAX }
+#define FLOW_METER_PULSES_PER_LITER 450
+#endif
+
+// Dosing pumps inject fertilizer while a zone is watering. DOSING_PINS has one pin per zone, using GPIO_NUM_MAX for
+// zones without a dosing pump. The pump runs for the "fertilizer" milliseconds from the water command
+// #define ENABLE_DOSING
+#ifdef ENABLE_DOSING
+#define DOSING_PINS { GPIO_NUM_MAX, GPIO_NUM_27, GPIO_NUM_MAX }
+#endif
+
 // DHT22 Temperature and Humidity sensor
 #define ENABLE_DHT22
 #ifdef ENABLE_DHT22
@@ -0,0 +1,8 @@
+#ifndef dosing_h
+#define dosing_h
+
+void setupDosingPumps();
+bool dosingPumpOn(int position);
+void dosingPumpOff(int position);
+
+#endif
@@ -0,0 +1,8 @@
+#ifndef flow_meter_h
+#define flow_meter_h
+
+void setupFlowMeters();
+void resetFlowMeter(int position);
+unsigned long readFlowMeterMilliliters(int position);
+
+#endif
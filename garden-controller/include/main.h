@@ -5,6 +5,10 @@ struct WaterEvent {
     int position;
     unsigned long duration;
     const char* id;
+    unsigned long milliliters;
+    unsigned long fertilizer;
+    // manual is true when watering was started at the controller, like with a button
+    bool manual;
 };
 
 struct LightEvent {
@@ -18,6 +18,7 @@ void lightPublisherTask(void* parameters);
 void healthPublisherTask(void* parameters);
 void mqttConnectTask(void* parameters);
 void mqttLoopTask(void* parameters);
+bool isDuplicateCommand(const char* commandID);
 void processIncomingMessage(char* topic, byte* message, unsigned int length);
 void wifiDisconnectHandler(WiFiEvent_t event, WiFiEventInfo_t info);
 
@@ -66,7 +66,7 @@ void readButton(int valveID) {
             // If our button state is HIGH, water the zone
             if (reading == HIGH && buttonStates[valveID] == HIGH) {
                 printf("button pressed: %d\n", valveID);
-                WaterEvent we = { valveID, DEFAULT_WATER_TIME, "N/A" };
+                WaterEvent we = { valveID, DEFAULT_WATER_TIME, "N/A", 0, 0, true };
                 waterZone(we);
             }
         }
@@ -0,0 +1,48 @@
+#include "config.h"
+#ifdef ENABLE_DOSING
+
+#include <Arduino.h>
+#include "driver/gpio.h"
+#include "dosing.h"
+
+gpio_num_t dosingPins[NUM_ZONES] = DOSING_PINS;
+
+/*
+  setupDosingPumps configures each zone's dosing pin as an output. Zones using
+  GPIO_NUM_MAX do not have a dosing pump
+*/
+void setupDosingPumps() {
+    for (int i = 0; i < NUM_ZONES; i++) {
+        if (dosingPins[i] == GPIO_NUM_MAX) {
+            continue;
+        }
+        gpio_reset_pin(dosingPins[i]);
+        gpio_set_direction(dosingPins[i], GPIO_MODE_OUTPUT);
+    }
+}
+
+/*
+  dosingPumpOn turns on the zone's dosing pump. It returns false if the zone
+  does not have one
+*/
+bool dosingPumpOn(int position) {
+    if (dosingPins[position] == GPIO_NUM_MAX) {
+        return false;
+    }
+    printf("turning on dosing pump for zone %d\n", position);
+    gpio_set_level(dosingPins[position], 1);
+    return true;
+}
+
+/*
+  dosingPumpOff turns off the zone's dosing pump
+*/
+void dosingPumpOff(int position) {
+    if (dosingPins[position] == GPIO_NUM_MAX) {
+        return;
+    }
+    printf("turning off dosing pump for zone %d\n", position);
+    gpio_set_level(dosingPins[position], 0);
+}
+
+#endif
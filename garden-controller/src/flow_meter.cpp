@@ -0,0 +1,50 @@
+#include "config.h"
+#ifdef ENABLE_FLOW_METERS
+
+#include <Arduino.h>
+#include "flow_meter.h"
+
+gpio_num_t flowMeterPins[NUM_ZONES] = FLOW_METER_PINS;
+
+/* pulse counts are updated by interrupts, so they must be volatile */
+volatile unsigned long flowMeterPulses[NUM_ZONES];
+
+void IRAM_ATTR flowMeterISR(void* arg) {
+    int position = (int)arg;
+    flowMeterPulses[position]++;
+}
+
+/*
+  setupFlowMeters configures each zone's flow meter pin to count pulses with
+  an interrupt. Zones using GPIO_NUM_MAX do not have a flow meter
+*/
+void setupFlowMeters() {
+    for (int i = 0; i < NUM_ZONES; i++) {
+        flowMeterPulses[i] = 0;
+        if (flowMeterPins[i] == GPIO_NUM_MAX) {
+            continue;
+        }
+        pinMode(flowMeterPins[i], INPUT_PULLUP);
+        attachInterruptArg(digitalPinToInterrupt(flowMeterPins[i]), flowMeterISR, (void*)i, FALLING);
+    }
+}
+
+/*
+  resetFlowMeter clears the pulse count before a zone starts watering
+*/
+void resetFlowMeter(int position) {
+    flowMeterPulses[position] = 0;
+}
+
+/*
+  readFlowMeterMilliliters converts the pulses counted since the last reset
+  to milliliters
+*/
+unsigned long readFlowMeterMilliliters(int position) {
+    if (flowMeterPins[position] == GPIO_NUM_MAX) {
+        return 0;
+    }
+    return (unsigned long)(flowMeterPulses[position] * 1000.0 / FLOW_METER_PULSES_PER_LITER);
+}
+
+#endif
@@ -15,6 +15,12 @@
 #ifdef ENABLE_DHT22
 #include "dht22.h"
 #endif
+#ifdef ENABLE_FLOW_METERS
+#include "flow_meter.h"
+#endif
+#ifdef ENABLE_DOSING
+#include "dosing.h"
+#endif
 
 
 /* zone/valve variables */
@@ -61,6 +67,14 @@ void setup() {
   setupButtons();
 #endif
 
+#ifdef ENABLE_FLOW_METERS
+  setupFlowMeters();
+#endif
+
+#ifdef ENABLE_DOSING
+  setupDosingPumps();
+#endif
+
   // Initialize Queues
   waterQueue = xQueueCreate(QUEUE_SIZE, sizeof(WaterEvent));
   if (waterQueue == NULL) {
@@ -88,7 +102,10 @@ void loop() {}
   valve for an amount of time. The delay before closing the valve is done with
   xTaskNotifyWait, allowing it to be interrupted with xTaskNotify. After the
   valve is closed, the WaterEvent is pushed to the queue fro publisherTask
-  which will record the WaterEvent in InfluxDB via MQTT and Telegraf
+  which will record the WaterEvent in InfluxDB via MQTT and Telegraf. If the
+  zone has a flow meter, the measured volume is included in the WaterEvent.
+  If the zone has a dosing pump, it runs for the WaterEvent's fertilizer time
+  at the start of watering
 */
 void waterZoneTask(void* parameters) {
   WaterEvent we;
@@ -102,13 +119,32 @@ void waterZoneTask(void* parameters) {
         we.duration = DEFAULT_WATER_TIME;
       }
 
+#ifdef ENABLE_FLOW_METERS
+      resetFlowMeter(we.position);
+#endif
       unsigned long start = millis();
       zoneOn(we.position);
+      unsigned long remaining = we.duration;
+#ifdef ENABLE_DOSING
+      // Dose fertilizer at the start of watering. If watering is interrupted
+      // while dosing, the rest of the watering is also skipped
+      if (we.fertilizer > 0 && dosingPumpOn(we.position)) {
+        unsigned long dose = min(we.fertilizer, we.duration);
+        BaseType_t interrupted = xTaskNotifyWait(0x00, ULONG_MAX, NULL, dose / portTICK_PERIOD_MS);
+        dosingPumpOff(we.position);
+        remaining = interrupted == pdTRUE ? 0 : we.duration - dose;
+      }
+#endif
       // Delay for specified watering time with option to interrupt
-      xTaskNotifyWait(0x00, ULONG_MAX, NULL, we.duration / portTICK_PERIOD_MS);
+      if (remaining > 0) {
+        xTaskNotifyWait(0x00, ULONG_MAX, NULL, remaining / portTICK_PERIOD_MS);
+      }
       unsigned long stop = millis();
       zoneOff(we.position);
       we.duration = stop - start;
+#ifdef ENABLE_FLOW_METERS
+      we.milliliters = readFlowMeterMilliliters(we.position);
+#endif
       xQueueSend(waterPublisherQueue, &we, portMAX_DELAY);
     }
     vTaskDelay(5 / portTICK_PERIOD_MS);
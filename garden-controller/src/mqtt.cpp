@@ -19,11 +19,13 @@ const char* waterCommandTopic = "";
 const char* stopCommandTopic = "";
 const char* stopAllCommandTopic = "";
 const char* waterDataTopic = "";
+const char* waterAckTopic = "";
 #else
 const char* waterCommandTopic = MQTT_WATER_TOPIC;
 const char* stopCommandTopic = MQTT_STOP_TOPIC;
 const char* stopAllCommandTopic = MQTT_STOP_ALL_TOPIC;
 const char* waterDataTopic = MQTT_WATER_DATA_TOPIC;
+const char* waterAckTopic = MQTT_WATER_ACK_TOPIC;
 #endif
 
 #ifdef LIGHT_PIN
@@ -42,6 +44,12 @@ const char* healthDataTopic = "";
 
 #define ZERO (unsigned long int) 0
 
+// Recently received command IDs are kept so a retried water command is acknowledged again without watering twice
+#define RECENT_COMMAND_IDS 5
+#define COMMAND_ID_SIZE 24
+char recentCommandIDs[RECENT_COMMAND_IDS][COMMAND_ID_SIZE];
+int nextCommandIDIndex = 0;
+
 void setupMQTT() {
     // Connect to MQTT
     client.setServer(MQTT_ADDRESS, MQTT_PORT);
@@ -90,14 +98,20 @@ void setupWifi() {
 
 /*
   waterPublisherTask reads from a queue to publish WaterEvents as an InfluxDB
-  line protocol message to MQTT
+  line protocol message to MQTT. Manual waterings, like from a button, are
+  tagged with source=button so the garden-app can record them
 */
 void waterPublisherTask(void* parameters) {
     WaterEvent we;
     while (true) {
         if (xQueueReceive(waterPublisherQueue, &we, portMAX_DELAY)) {
-            char message[50];
-            sprintf(message, "water,zone=%d millis=%lu", we.position, we.duration);
+            char message[90];
+            const char* source = we.manual ? ",source=button" : "";
+            if (we.milliliters > 0) {
+                sprintf(message, "water,zone=%d%s millis=%lu,ml=%lu", we.position, source, we.duration, we.milliliters);
+            } else {
+                sprintf(message, "water,zone=%d%s millis=%lu", we.position, source, we.duration);
+            }
             if (client.connected()) {
                 printf("publishing to MQTT:\n\ttopic=%s\n\tmessage=%s\n", waterDataTopic, message);
                 client.publish(waterDataTopic, message);
@@ -197,11 +211,28 @@ void mqttLoopTask(void* parameters) {
     vTaskDelete(NULL);
 }
 
+/*
+  isDuplicateCommand returns true if the command ID was already received. Otherwise,
+  it is remembered in place of the oldest command ID
+*/
+bool isDuplicateCommand(const char* commandID) {
+    for (int i = 0; i < RECENT_COMMAND_IDS; i++) {
+        if (strcmp(recentCommandIDs[i], commandID) == 0) {
+            return true;
+        }
+    }
+    strncpy(recentCommandIDs[nextCommandIDIndex], commandID, COMMAND_ID_SIZE - 1);
+    recentCommandIDs[nextCommandIDIndex][COMMAND_ID_SIZE - 1] = '\0';
+    nextCommandIDIndex = (nextCommandIDIndex + 1) % RECENT_COMMAND_IDS;
+    return false;
+}
+
 /*
   processIncomingMessage is a callback function for the MQTT client that will
   react to incoming messages. Currently, the topics are:
     - waterCommandTopic: accepts a WaterEvent JSON to water a zone for
-                         specified time
+                         specified time. If it has a command_id, it is
+                         published on waterAckTopic
     - stopCommandTopic: ignores message and stops the currently-watering zone
     - stopAllCommandTopic: ignores message, stops the currently-watering zone,
                            and clears the waterQueue
@@ -217,13 +248,31 @@ void processIncomingMessage(char* topic, byte* message, unsigned int length) {
     }
 
     if (strcmp(topic, waterCommandTopic) == 0) {
-        WaterEvent we = {
-            doc["position"] | -1,
-            doc["duration"] | ZERO,
-            doc["id"] | "N/A"
-        };
-        printf("received command to water zone %d (%s) for %lu\n", we.position, we.id, we.duration);
-        waterZone(we);
+        const char* commandID = doc["command_id"] | "";
+        bool hasCommandID = strlen(commandID) > 0;
+        if (hasCommandID && isDuplicateCommand(commandID)) {
+            printf("ignoring water command %s since it was already received\n", commandID);
+        } else {
+            WaterEvent we = {
+                doc["position"] | -1,
+                doc["duration"] | ZERO,
+                doc["id"] | "N/A",
+                0,
+                doc["fertilizer"] | ZERO,
+                false
+            };
+            printf("received command to water zone %d (%s) for %lu\n", we.position, we.id, we.duration);
+            waterZone(we);
+        }
+
+        // The acknowledgment is published last since publishing reuses the buffer that the message was parsed from
+        if (hasCommandID) {
+            char ack[COMMAND_ID_SIZE];
+            strncpy(ack, commandID, COMMAND_ID_SIZE - 1);
+            ack[COMMAND_ID_SIZE - 1] = '\0';
+            printf("acknowledging water command %s\n", ack);
+            client.publish(waterAckTopic, ack);
+        }
     } else if (strcmp(topic, stopCommandTopic) == 0) {
         printf("received command to stop watering\n");
         stopWatering();